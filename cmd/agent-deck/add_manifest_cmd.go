@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/progress"
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleAddFromManifest implements `agent-deck add --from-manifest [path]`:
+// it materializes the groups, sessions, worktrees and loadout declared in a
+// project's .agentdeck.toml. path is the manifest or a directory inside the
+// project (default: the current directory); the nearest manifest at or above
+// it is used. Sessions are created, not started, exactly like `add`.
+func handleAddFromManifest(profile, pathArg string, jsonOutput, quiet bool) {
+	out := NewCLIOutput(jsonOutput, quiet)
+
+	manifestPath := pathArg
+	if info, err := os.Stat(pathArg); pathArg == "" || (err == nil && info.IsDir()) {
+		dir := pathArg
+		if dir == "" {
+			dir = "."
+		}
+		found, ok := session.FindProjectManifest(dir)
+		if !ok {
+			out.Error(fmt.Sprintf("no %s found in %s or its parents", session.ProjectManifestFile, dir), ErrCodeNotFound)
+			os.Exit(2)
+		}
+		manifestPath = found
+	}
+	manifest, err := session.LoadProjectManifest(manifestPath)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	storage, err := session.NewStorageWithProfile(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to initialize storage: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	instances, groups, err := storage.LoadWithGroups()
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load sessions: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	var report progress.Func
+	if !jsonOutput && !quiet {
+		report = progress.NewCLI(os.Stderr)
+	}
+	res := manifest.Materialize(instances, report)
+
+	instances = append(instances, res.Created...)
+	groupTree := session.NewGroupTreeWithGroups(instances, groups)
+	if cfg, cfgErr := session.LoadUserConfig(); cfgErr == nil && cfg != nil {
+		groupTree.DefaultMaxConcurrent = cfg.GroupDefaults.MaxConcurrent
+	}
+	manifest.ApplyGroups(groupTree)
+	if err := storage.SaveWithGroups(instances, groupTree); err != nil {
+		out.Error(fmt.Sprintf("failed to save sessions: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	for _, w := range res.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+	out.Print(formatManifestResult(manifest, res), manifestResultJSON(manifest, res))
+	if len(res.Failed) > 0 {
+		os.Exit(1)
+	}
+}
+
+func formatManifestResult(m *session.ProjectManifest, res *session.ManifestResult) string {
+	return formatMaterializeResult(fmt.Sprintf("Applied %s (group %s)", m.Path, m.RootGroup()), m, res)
+}
+
+// formatMaterializeResult lists what Materialize created, found and failed
+// to create under a header line.
+func formatMaterializeResult(header string, m *session.ProjectManifest, res *session.ManifestResult) string {
+	var b strings.Builder
+	b.WriteString(header + "\n")
+	for _, inst := range res.Created {
+		fmt.Fprintf(&b, "  %s created  %s  (%s)\n", successSymbol, inst.Title, inst.GroupPath)
+	}
+	for _, inst := range res.Existing {
+		fmt.Fprintf(&b, "  %s exists   %s  (%s)\n", bulletSymbol, inst.Title, inst.GroupPath)
+	}
+	for _, title := range sortedKeys(res.Failed) {
+		fmt.Fprintf(&b, "  %s failed   %s: %v\n", errorSymbol, title, res.Failed[title])
+	}
+	if len(res.Created) > 0 {
+		fmt.Fprintf(&b, "\nStart them with: agent-deck session start --group %s\n", m.RootGroup())
+	}
+	return b.String()
+}
+
+func manifestResultJSON(m *session.ProjectManifest, res *session.ManifestResult) map[string]interface{} {
+	return map[string]interface{}{
+		"success":  len(res.Failed) == 0,
+		"manifest": m.Path,
+		"group":    m.RootGroup(),
+		"created":  instanceListJSON(res.Created),
+		"existing": instanceListJSON(res.Existing),
+		"failed":   failedMapJSON(res.Failed),
+		"warnings": res.Warnings,
+	}
+}
+
+func instanceListJSON(insts []*session.Instance) []map[string]string {
+	items := make([]map[string]string, 0, len(insts))
+	for _, inst := range insts {
+		items = append(items, map[string]string{
+			"id":    inst.ID,
+			"title": inst.Title,
+			"group": inst.GroupPath,
+			"path":  inst.ProjectPath,
+		})
+	}
+	return items
+}
+
+func failedMapJSON(failed map[string]error) map[string]string {
+	out := make(map[string]string, len(failed))
+	for title, err := range failed {
+		out[title] = err.Error()
+	}
+	return out
+}
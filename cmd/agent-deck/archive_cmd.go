@@ -0,0 +1,304 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleArchive dispatches `agent-deck archive <subcommand>`: browsing and
+// restoring archived sessions, and applying [archive] auto_archive_after_days
+// outside the TUI.
+func handleArchive(profile string, args []string) {
+	if len(args) == 0 {
+		handleArchiveList(profile, nil)
+		return
+	}
+	if isHelpArg(args[0]) {
+		printArchiveHelp()
+		return
+	}
+	switch args[0] {
+	case "list", "ls":
+		handleArchiveList(profile, args[1:])
+	case "restore":
+		handleArchiveRestore(profile, args[1:])
+	case "sweep":
+		handleArchiveSweep(profile, args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown archive command: %s\n\n", args[0])
+		printArchiveHelp()
+		os.Exit(1)
+	}
+}
+
+func printArchiveHelp() {
+	fmt.Println("Usage: agent-deck archive <command>")
+	fmt.Println()
+	fmt.Println("Archived sessions are stopped and hidden from active lists; their")
+	fmt.Println("metadata is kept so they can be restored.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  list                 List archived sessions (default)")
+	fmt.Println("  restore <id|title>   Restore an archived session (does not restart it)")
+	fmt.Println("  sweep                Archive sessions idle for [archive] auto_archive_after_days")
+	fmt.Println()
+	fmt.Println("Enable automatic archiving in config.toml (the TUI applies it hourly):")
+	fmt.Println()
+	fmt.Println("  [archive]")
+	fmt.Println("  auto_archive_after_days = 14")
+}
+
+// archivedSessionsByRecency returns the archived sessions, most recently
+// archived first.
+func archivedSessionsByRecency(instances []*session.Instance) []*session.Instance {
+	archived := session.FilterInstancesByArchive(instances, true)
+	sort.SliceStable(archived, func(a, b int) bool {
+		return archived[a].ArchivedAt.After(archived[b].ArchivedAt)
+	})
+	return archived
+}
+
+func handleArchiveList(profile string, args []string) {
+	fs := flag.NewFlagSet("archive list", flag.ExitOnError)
+	group := fs.String("group", "", "Only list sessions in this group (and its subgroups)")
+	search := fs.String("search", "", "Only list sessions whose title or path contains this text")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck archive list [options]")
+		fmt.Println()
+		fmt.Println("List archived sessions, most recently archived first.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	_, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+
+	needle := strings.ToLower(*search)
+	var archived []*session.Instance
+	for _, inst := range archivedSessionsByRecency(instances) {
+		if *group != "" && inst.GroupPath != *group && !strings.HasPrefix(inst.GroupPath, *group+"/") {
+			continue
+		}
+		if needle != "" && !strings.Contains(strings.ToLower(inst.Title), needle) &&
+			!strings.Contains(strings.ToLower(inst.ProjectPath), needle) {
+			continue
+		}
+		archived = append(archived, inst)
+	}
+
+	now := time.Now()
+	type archivedJSON struct {
+		ID         string    `json:"id"`
+		Title      string    `json:"title"`
+		Group      string    `json:"group"`
+		Path       string    `json:"path"`
+		Tool       string    `json:"tool"`
+		ArchivedAt time.Time `json:"archived_at"`
+		LastActive time.Time `json:"last_active"`
+	}
+	rows := make([]archivedJSON, 0, len(archived))
+	var b strings.Builder
+	if len(archived) == 0 {
+		b.WriteString("No archived sessions.\n")
+	} else {
+		fmt.Fprintf(&b, "%-8s  %-28s  %-18s  %-8s  %s\n", "ID", "TITLE", "GROUP", "ARCHIVED", "LAST ACTIVE")
+	}
+	for _, inst := range archived {
+		lastActive := session.LastActiveAt(inst)
+		rows = append(rows, archivedJSON{
+			ID:         inst.ID,
+			Title:      inst.Title,
+			Group:      inst.GroupPath,
+			Path:       inst.ProjectPath,
+			Tool:       inst.Tool,
+			ArchivedAt: inst.ArchivedAt,
+			LastActive: lastActive,
+		})
+		fmt.Fprintf(&b, "%-8s  %-28s  %-18s  %-8s  %s\n",
+			shortID(inst.ID), truncate(inst.Title, 28), truncate(inst.GroupPath, 18),
+			humanizeAge(now.Sub(inst.ArchivedAt))+" ago", humanizeAge(now.Sub(lastActive))+" ago")
+	}
+	if len(archived) > 0 {
+		b.WriteString("\nRestore with: agent-deck archive restore <id|title>\n")
+	}
+
+	out.Print(b.String(), map[string]interface{}{
+		"success":  true,
+		"count":    len(rows),
+		"sessions": rows,
+	})
+}
+
+// handleArchiveRestore clears the archive flag of an archived session. The
+// identifier is resolved among archived sessions only, so a title reused by
+// an active session still finds the archived one.
+func handleArchiveRestore(profile string, args []string) {
+	fs := flag.NewFlagSet("archive restore", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck archive restore <id|title> [options]")
+		fmt.Println()
+		fmt.Println("Restore an archived session to the active list (does not restart it;")
+		fmt.Println("use `agent-deck session start` afterwards).")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+
+	identifier := fs.Arg(0)
+	if identifier == "" {
+		out.Error("session <id|title> required", ErrCodeInvalidOperation)
+		if !*jsonOutput {
+			fs.Usage()
+		}
+		os.Exit(1)
+	}
+
+	storage, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+
+	inst, errMsg, errCode := ResolveSession(identifier, session.FilterInstancesByArchive(instances, true))
+	if inst == nil {
+		if errCode == ErrCodeNotFound {
+			errMsg = fmt.Sprintf("no archived session matches '%s' (see: agent-deck archive list)", identifier)
+		}
+		out.Error(errMsg, errCode)
+		if errCode == ErrCodeNotFound {
+			os.Exit(2)
+		}
+		os.Exit(1)
+		return // unreachable, satisfies staticcheck SA5011
+	}
+
+	inst.ArchivedAt = time.Time{}
+	if err := persistArchivedCLI(storage, inst, false); err != nil {
+		out.Error(fmt.Sprintf("failed to persist restore: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	out.Success(fmt.Sprintf("Restored session: %s", inst.Title), map[string]interface{}{
+		"success":  true,
+		"id":       inst.ID,
+		"title":    inst.Title,
+		"archived": false,
+	})
+}
+
+// handleArchiveSweep archives every session idle for the configured (or
+// --days) threshold, the same way `session archive` does one. Useful from
+// cron when no TUI is running.
+func handleArchiveSweep(profile string, args []string) {
+	fs := flag.NewFlagSet("archive sweep", flag.ExitOnError)
+	days := fs.Int("days", 0, "Idle threshold in days (default: [archive] auto_archive_after_days)")
+	dryRun := fs.Bool("dry-run", false, "Only list the sessions that would be archived")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck archive sweep [options]")
+		fmt.Println()
+		fmt.Println("Archive sessions with no activity for the idle threshold: stop their tmux")
+		fmt.Println("session and move them to the archive. Pinned, conductor and running")
+		fmt.Println("sessions are skipped.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+
+	if *days < 0 {
+		out.Error("--days must be zero or positive", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	threshold := session.GetArchiveSettings().AutoArchiveAfter()
+	if *days > 0 {
+		threshold = time.Duration(*days) * 24 * time.Hour
+	}
+	if threshold <= 0 {
+		out.Error("no idle threshold: pass --days N or set [archive] auto_archive_after_days", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	storage, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+
+	candidates := session.AutoArchiveCandidates(instances, time.Now(), threshold)
+	var archived []map[string]interface{}
+	var lines []string
+	for _, inst := range candidates {
+		entry := map[string]interface{}{
+			"id":          inst.ID,
+			"title":       inst.Title,
+			"last_active": session.LastActiveAt(inst),
+		}
+		line := fmt.Sprintf("  %s  %s (idle %s)", shortID(inst.ID), inst.Title, humanizeAge(time.Since(session.LastActiveAt(inst))))
+		if !*dryRun {
+			// Same steps as `session archive`: kill only a live pane, then
+			// persist the archive stamp and post-kill status with targeted
+			// UPDATEs that survive a concurrent TUI writer.
+			killed := false
+			if inst.Exists() {
+				if err := inst.Kill(); err != nil {
+					fmt.Fprintf(os.Stderr, "skipping %s: failed to stop: %v\n", inst.Title, err)
+					continue
+				}
+				killed = true
+			}
+			inst.ArchivedAt = time.Now().UTC()
+			if err := persistArchivedCLI(storage, inst, killed); err != nil {
+				fmt.Fprintf(os.Stderr, "skipping %s: failed to persist archive: %v\n", inst.Title, err)
+				continue
+			}
+		}
+		archived = append(archived, entry)
+		lines = append(lines, line)
+	}
+
+	verb := "Archived"
+	if *dryRun {
+		verb = "Would archive"
+	}
+	msg := fmt.Sprintf("%s %d session(s) idle for %s+.", verb, len(archived), humanizeAge(threshold))
+	if len(lines) > 0 {
+		msg += "\n" + strings.Join(lines, "\n")
+	}
+	out.Success(msg, map[string]interface{}{
+		"success":  true,
+		"dry_run":  *dryRun,
+		"count":    len(archived),
+		"sessions": archived,
+	})
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleAutoRespond dispatches auto-respond subcommands.
+func handleAutoRespond(profile string, args []string) {
+	if len(args) == 0 {
+		printAutoRespondHelp()
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "rules", "list", "ls":
+		handleAutoRespondRules(args[1:])
+	case "test":
+		handleAutoRespondTest(args[1:])
+	case "log":
+		handleAutoRespondLog(args[1:])
+	case "help", "-h", "--help":
+		printAutoRespondHelp()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown auto-respond command '%s'\n", args[0])
+		printAutoRespondHelp()
+		os.Exit(1)
+	}
+}
+
+func printAutoRespondHelp() {
+	fmt.Println("Usage: agent-deck auto-respond <command> [options]")
+	fmt.Println()
+	fmt.Println("Answer Claude permission dialogs by rule. Rules live in config.toml:")
+	fmt.Println()
+	fmt.Println("  [auto_respond]")
+	fmt.Println("  enabled = true")
+	fmt.Println()
+	fmt.Println("  [[auto_respond.rule]]")
+	fmt.Println("  tool = \"bash\"          # bash, edit, write, read, fetch, mcp, other or *")
+	fmt.Println("  match = \"npm test*\"    # glob over the request, or re:<regexp>")
+	fmt.Println("  action = \"allow\"       # allow, allow_always or deny")
+	fmt.Println()
+	fmt.Println("A matching deny rule wins over allow rules; unmatched dialogs are left")
+	fmt.Println("for you. Allow rules never answer chained, piped or redirected commands.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  rules                      List the configured rules")
+	fmt.Println("  test <tool> <request>      Show which rule would answer a request")
+	fmt.Println("  log [-n N]                 Show recent auto-responses")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck auto-respond test bash \"rm -rf build\"")
+	fmt.Println("  agent-deck auto-respond log -n 50")
+}
+
+func handleAutoRespondRules(args []string) {
+	fs := flag.NewFlagSet("auto-respond rules", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	settings := session.GetAutoRespondSettings()
+
+	var b strings.Builder
+	state := "disabled"
+	if settings.Enabled {
+		state = "enabled"
+	}
+	fmt.Fprintf(&b, "Auto-respond is %s (%d rules).\n", state, len(settings.Rules))
+	for i, r := range settings.Rules {
+		fmt.Fprintf(&b, "  %d. %s\n", i, describeAutoRespondRule(r))
+	}
+	data := map[string]interface{}{
+		"success": true,
+		"enabled": settings.Enabled,
+		"rules":   settings.Rules,
+	}
+	if err := settings.Validate(); err != nil {
+		fmt.Fprintf(&b, "\nInvalid: %v (this rule is skipped)\n", err)
+		data["error"] = err.Error()
+	}
+	out.Print(b.String(), data)
+}
+
+func handleAutoRespondTest(args []string) {
+	fs := flag.NewFlagSet("auto-respond test", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	group := fs.String("group", "", "Group path of the session asking")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck auto-respond test <tool> <request> [--group path]")
+		fmt.Println()
+		fmt.Println("Show which rule would answer a permission request, without sending")
+		fmt.Println("anything. <tool> is bash, edit, write, read, fetch, mcp or other.")
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	if fs.NArg() < 2 {
+		fs.Usage()
+		out.Error("tool and request are required", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	tool, request := fs.Arg(0), strings.Join(fs.Args()[1:], " ")
+
+	settings := session.GetAutoRespondSettings()
+	idx := settings.Decide(tool, request, request, *group)
+	data := map[string]interface{}{
+		"success": true,
+		"tool":    tool,
+		"request": request,
+		"enabled": settings.Enabled,
+		"rule":    idx,
+	}
+	msg := "No rule matches; the dialog is left for you."
+	if idx >= 0 {
+		r := settings.Rules[idx]
+		data["action"] = r.Action
+		msg = fmt.Sprintf("Rule %d answers %s: %s", idx, r.Action, describeAutoRespondRule(r))
+	}
+	if !settings.Enabled {
+		msg += "\n(auto-respond is disabled; set [auto_respond] enabled = true)"
+	}
+	out.Print(msg+"\n", data)
+}
+
+func handleAutoRespondLog(args []string) {
+	fs := flag.NewFlagSet("auto-respond log", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	limit := fs.Int("n", 20, "Number of entries to show (0 for all)")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	entries, err := session.ReadAutoResponses(*limit)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to read %s: %v", session.AutoRespondLogPath(), err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if entries == nil {
+		entries = []session.AutoResponse{}
+	}
+	out.Print(renderAutoResponses(entries), map[string]interface{}{
+		"success": true,
+		"entries": entries,
+	})
+}
+
+// describeAutoRespondRule renders a rule as "action tool match [in group]".
+func describeAutoRespondRule(r session.AutoRespondRule) string {
+	tool := r.Tool
+	if tool == "" {
+		tool = "*"
+	}
+	match := r.Match
+	if match == "" {
+		match = "*"
+	}
+	s := fmt.Sprintf("%-12s %-6s %s", r.Action, tool, match)
+	if r.Group != "" {
+		s += "  (group " + r.Group + ")"
+	}
+	return s
+}
+
+// renderAutoResponses prints one line per audit entry, plus the error of
+// failed attempts.
+func renderAutoResponses(entries []session.AutoResponse) string {
+	if len(entries) == 0 {
+		return "No auto-responses yet.\n"
+	}
+	var b strings.Builder
+	for _, e := range entries {
+		fmt.Fprintf(&b, "%s  %-12s %-14s %-5s %s\n",
+			e.Time.Local().Format("2006-01-02 15:04:05"), e.Action, truncate(e.SessionTitle, 14), e.Kind, truncate(e.Request, 60))
+		if e.Error != "" {
+			fmt.Fprintf(&b, "    failed: %s\n", e.Error)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+func TestRenderAutoResponses(t *testing.T) {
+	if got := renderAutoResponses(nil); !strings.Contains(got, "No auto-responses") {
+		t.Errorf("empty log = %q", got)
+	}
+	at := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+	got := renderAutoResponses([]session.AutoResponse{
+		{Time: at, SessionTitle: "api", Kind: "bash", Request: "npm test", Action: "allow"},
+		{Time: at, SessionTitle: "web", Kind: "bash", Request: "rm -rf .", Action: "deny", Error: "dialog changed"},
+	})
+	for _, want := range []string{
+		"2026-10-16 12:00:00  allow        api            bash  npm test",
+		"deny         web",
+		"    failed: dialog changed",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("log missing %q:\n%s", want, got)
+		}
+	}
+	if d := describeAutoRespondRule(session.AutoRespondRule{Action: "deny", Group: "ops"}); d != "deny         *      *  (group ops)" {
+		t.Errorf("describe = %q", d)
+	}
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleBackup dispatches backup subcommands.
+func handleBackup(profile string, args []string) {
+	if len(args) == 0 {
+		printBackupHelp()
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "create":
+		handleBackupCreate(profile, args[1:])
+	case "list", "ls":
+		handleBackupList(profile, args[1:])
+	case "restore":
+		handleBackupRestore(profile, args[1:])
+	case "help", "-h", "--help":
+		printBackupHelp()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown backup command '%s'\n", args[0])
+		printBackupHelp()
+		os.Exit(1)
+	}
+}
+
+func printBackupHelp() {
+	fmt.Println("Usage: agent-deck backup <command> [options]")
+	fmt.Println()
+	fmt.Println("Back up the profile's state.db (sessions, groups and every Claude/Codex/")
+	fmt.Println("Gemini resume mapping) and config.toml to a directory or an S3-compatible")
+	fmt.Println("bucket (s3://bucket/prefix).")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  create [--to <dest>] [--encrypt]          Take a backup now")
+	fmt.Println("  list [--from <dest>] [--all]              List backups, newest first")
+	fmt.Println("  restore [name|file|latest] [--from <dest>] [--config]")
+	fmt.Println("                                            Replace state.db (and config) with a backup")
+	fmt.Println()
+	fmt.Println("Scheduled backups (run by the TUI's maintenance worker), in config.toml:")
+	fmt.Println("  [backup]")
+	fmt.Println("  destination = \"~/Dropbox/agent-deck\"   # or \"s3://bucket/prefix\"")
+	fmt.Println("  interval = \"24h\"")
+	fmt.Println("  keep = 7")
+	fmt.Println("  encrypt = true                         # passphrase from $" + session.DefaultBackupPassphraseEnv)
+}
+
+// backupPassphrase returns the passphrase when encrypting, failing when
+// the configured variable is empty.
+func backupPassphrase(settings session.BackupSettings, encrypt bool, out *CLIOutput) string {
+	if !encrypt {
+		return ""
+	}
+	p := settings.Passphrase()
+	if p == "" {
+		out.Error(fmt.Sprintf("encryption needs a passphrase: set $%s", settings.GetPassphraseEnv()), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	return p
+}
+
+func openBackupTargetOrExit(dest string, settings session.BackupSettings, out *CLIOutput) session.BackupTarget {
+	target, err := session.OpenBackupTarget(dest, settings)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	return target
+}
+
+func handleBackupCreate(profile string, args []string) {
+	fs := flag.NewFlagSet("backup create", flag.ExitOnError)
+	to := fs.String("to", "", "Directory or s3://bucket/prefix (default: [backup] destination)")
+	encrypt := fs.Bool("encrypt", false, "Encrypt the backup (always on for s3:// targets and with [backup] encrypt = true)")
+	keep := fs.Int("keep", -1, "Backups of this profile to keep afterwards (default: [backup] keep, 7)")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck backup create [options]")
+		fmt.Println()
+		fmt.Println("Snapshot the profile's state.db and config.toml into one archive.")
+		fmt.Println("The snapshot is consistent even while agent-deck is running.")
+		fmt.Println("config.toml can hold secrets, so backups to s3:// are always encrypted and")
+		fmt.Println("need $" + session.DefaultBackupPassphraseEnv + " (or [backup] passphrase_env).")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck backup create --to ~/backups/agent-deck")
+		fmt.Println("  AGENTDECK_BACKUP_PASSPHRASE=... agent-deck backup create --to s3://my-bucket/deck")
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+
+	settings := session.GetBackupSettings()
+	target := openBackupTargetOrExit(*to, settings, out)
+	passphrase := backupPassphrase(settings, *encrypt || settings.Encrypt || session.BackupTargetNeedsEncryption(target), out)
+	n := settings.GetKeep()
+	if *keep >= 0 {
+		n = *keep
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	info, pruned, err := session.CreateBackup(ctx, target, profile, passphrase, n)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	msg := fmt.Sprintf("Backed up profile %s to %s (%s)", info.Profile, joinBackupPath(target, info.Name), formatSize(info.Size))
+	if info.Encrypted {
+		msg += ", encrypted"
+	}
+	if pruned > 0 {
+		msg += fmt.Sprintf("; removed %d older backup(s)", pruned)
+	}
+	out.Success(msg, map[string]interface{}{
+		"success":   true,
+		"backup":    info,
+		"target":    target.String(),
+		"pruned":    pruned,
+		"encrypted": info.Encrypted,
+	})
+}
+
+func joinBackupPath(target session.BackupTarget, name string) string {
+	t := target.String()
+	if strings.HasSuffix(t, "/") {
+		return t + name
+	}
+	return t + string(os.PathSeparator) + name
+}
+
+func handleBackupList(profile string, args []string) {
+	fs := flag.NewFlagSet("backup list", flag.ExitOnError)
+	from := fs.String("from", "", "Directory or s3://bucket/prefix (default: [backup] destination)")
+	all := fs.Bool("all", false, "Show backups of every profile")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck backup list [options]")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	target := openBackupTargetOrExit(*from, session.GetBackupSettings(), out)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	backups, err := target.List(ctx)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if !*all {
+		backups = session.ProfileBackups(backups, profile)
+	}
+	if *jsonOutput {
+		if backups == nil {
+			backups = []session.BackupInfo{}
+		}
+		out.Print("", map[string]interface{}{"target": target.String(), "backups": backups})
+		return
+	}
+	if len(backups) == 0 {
+		fmt.Printf("No backups in %s\n", target)
+		return
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tPROFILE\tCREATED\tSIZE\tENCRYPTED")
+	for _, b := range backups {
+		enc := ""
+		if b.Encrypted {
+			enc = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", b.Name, b.Profile, b.CreatedAt.Local().Format("2006-01-02 15:04"), formatSize(b.Size), enc)
+	}
+	_ = tw.Flush()
+}
+
+func handleBackupRestore(profile string, args []string) {
+	fs := flag.NewFlagSet("backup restore", flag.ExitOnError)
+	from := fs.String("from", "", "Directory or s3://bucket/prefix (default: [backup] destination)")
+	withConfig := fs.Bool("config", false, "Also restore config.toml")
+	force := fs.Bool("force", false, "Restore even while agent-deck is running on this profile")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck backup restore [name|file|latest] [options]")
+		fmt.Println()
+		fmt.Println("Replace the profile's state.db with a backup's (default: the newest backup")
+		fmt.Println("of this profile). The current state.db is kept beside it as")
+		fmt.Println("state.db.pre-restore-<time>. Quit agent-deck first.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck backup restore")
+		fmt.Println("  agent-deck backup restore ./agent-deck-default-20261016T060000Z.tar.gz --config")
+		fmt.Println("  agent-deck -p work backup restore latest --from s3://my-bucket/deck")
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+	ref := fs.Arg(0)
+	settings := session.GetBackupSettings()
+
+	if !*force {
+		if n, err := session.ProfileLiveInstances(profile); err == nil && n > 0 {
+			out.Error(fmt.Sprintf("agent-deck is running on this profile (%d instance(s)); quit it first or pass --force", n), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	var data []byte
+	source := ref
+	if info, err := os.Stat(ref); ref != "" && ref != "latest" && err == nil && !info.IsDir() {
+		if data, err = os.ReadFile(ref); err != nil {
+			out.Error(fmt.Sprintf("failed to read backup: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	} else {
+		target := openBackupTargetOrExit(*from, settings, out)
+		backup, err := session.FindBackup(ctx, target, profile, ref)
+		if err != nil {
+			out.Error(err.Error(), ErrCodeNotFound)
+			os.Exit(2)
+		}
+		if data, err = target.Get(ctx, backup.Name); err != nil {
+			out.Error(fmt.Sprintf("failed to fetch backup: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		source = joinBackupPath(target, backup.Name)
+	}
+
+	res, err := session.RestoreBackup(data, profile, settings.Passphrase(), *withConfig)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Restored profile %s from %s (%d session(s), taken %s)", res.Profile, source, res.Sessions, res.CreatedAt.Local().Format("2006-01-02 15:04"))
+	if res.BackupProfile != "" && res.BackupProfile != res.Profile {
+		fmt.Fprintf(&sb, "\nNote: the backup was taken from profile %s", res.BackupProfile)
+	}
+	if res.PreviousDB != "" {
+		fmt.Fprintf(&sb, "\nPrevious state.db kept at %s", res.PreviousDB)
+	}
+	if res.PreviousConfig != "" {
+		fmt.Fprintf(&sb, "\nPrevious config.toml kept at %s", res.PreviousConfig)
+	}
+	out.Success(sb.String(), map[string]interface{}{
+		"success": true,
+		"source":  source,
+		"restore": res,
+	})
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/asheshgoplani/agent-deck/internal/bridge"
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleBridge runs the conductor bridge for Telegram and Slack in the
+// foreground. The bridge daemon installed by `conductor setup` runs this; its
+// log lines go to stdout, which the daemon appends to <conductor>/bridge.log.
+func handleBridge(args []string) {
+	fs := flag.NewFlagSet("bridge", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck bridge")
+		fmt.Println()
+		fmt.Println("Connect conductors to Telegram and Slack ([conductor.telegram] / [conductor.slack]")
+		fmt.Println("in config.toml). Normally run by the bridge daemon that `conductor setup` installs.")
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	settings := session.GetConductorSettings()
+	if settings.Discord.BotToken != "" {
+		log.Warn("discord_not_served", "hint", "Discord still runs on bridge.py; `conductor setup` installs it instead of this command")
+	}
+
+	exe, _ := os.Executable()
+	b, err := bridge.New(bridge.LoadConfig(settings), exe, log)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bridge: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	if err := b.Run(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "bridge: %v\n", err)
+		os.Exit(1)
+	}
+}
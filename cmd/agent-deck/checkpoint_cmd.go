@@ -0,0 +1,225 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/git"
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleCheckpoint dispatches checkpoint subcommands.
+func handleCheckpoint(profile string, args []string) {
+	if len(args) == 0 {
+		printCheckpointHelp()
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "list", "ls":
+		handleCheckpointList(profile, args[1:])
+	case "create":
+		handleCheckpointCreate(profile, args[1:])
+	case "rollback":
+		handleCheckpointRollback(profile, args[1:])
+	case "help", "-h", "--help":
+		printCheckpointHelp()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown checkpoint command '%s'\n", args[0])
+		printCheckpointHelp()
+		os.Exit(1)
+	}
+}
+
+func printCheckpointHelp() {
+	fmt.Println("Usage: agent-deck checkpoint <command> [options]")
+	fmt.Println()
+	fmt.Println("Snapshots of a session's working tree (tracked and untracked files) to")
+	fmt.Println("recover from agent mistakes. They are stored as git refs under")
+	fmt.Println("refs/agent-deck/checkpoints/<id>/; the branch and index are never touched.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  list <id|title>               List a session's checkpoints, newest first")
+	fmt.Println("  create <id|title> [-m msg]    Take a checkpoint now")
+	fmt.Println("  rollback <id|title> [n|sha]   Restore a checkpoint (default: the newest)")
+	fmt.Println()
+	fmt.Println("Automatic checkpoints (taken while the TUI is running):")
+	fmt.Println("  agent-deck session set <id> checkpoint waiting        # each finished turn")
+	fmt.Println("  agent-deck session set <id> checkpoint 15m            # every 15 minutes")
+	fmt.Println("  agent-deck session set <id> checkpoint waiting,15m    # both")
+	fmt.Println("  agent-deck launch . -c claude --checkpoint waiting -m \"...\"")
+}
+
+// resolveCheckpointSession loads the profile and resolves the session
+// argument (or the current session).
+func resolveCheckpointSession(profile, identifier string, out *CLIOutput) *session.Instance {
+	_, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load sessions: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	inst, errMsg, errCode := ResolveSessionOrCurrent(identifier, instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		if errCode == ErrCodeNotFound {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+	return inst
+}
+
+func checkpointJSON(cp git.Checkpoint) map[string]interface{} {
+	return map[string]interface{}{
+		"commit":  cp.Commit,
+		"head":    cp.Head,
+		"message": cp.Message,
+		"at":      cp.At.UTC().Format(time.RFC3339),
+	}
+}
+
+// handleCheckpointList prints a session's checkpoints, numbered newest
+// first as `checkpoint rollback` accepts them.
+func handleCheckpointList(profile string, args []string) {
+	fs := flag.NewFlagSet("checkpoint list", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck checkpoint list <id|title> [options]")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	inst := resolveCheckpointSession(profile, fs.Arg(0), out)
+
+	cps, err := session.ListInstanceCheckpoints(inst)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	rows := make([]map[string]interface{}, 0, len(cps))
+	var sb strings.Builder
+	mode := inst.Checkpoint
+	if mode == "" {
+		mode = "off"
+	}
+	fmt.Fprintf(&sb, "Checkpoints for %s (automatic: %s)\n", inst.Title, mode)
+	if len(cps) == 0 {
+		sb.WriteString("  none yet; take one with 'agent-deck checkpoint create'\n")
+	}
+	for i, cp := range cps {
+		row := checkpointJSON(cp)
+		row["n"] = i + 1
+		rows = append(rows, row)
+		fmt.Fprintf(&sb, "  %3d  %s  %-10s  on %s  %s\n",
+			i+1, cp.ShortCommit(), humanizeAge(time.Since(cp.At))+" ago", shortHead(cp.Head), cp.Message)
+	}
+	out.Print(sb.String(), map[string]interface{}{
+		"session_id":  inst.ID,
+		"title":       inst.Title,
+		"mode":        inst.Checkpoint,
+		"checkpoints": rows,
+	})
+}
+
+func shortHead(head string) string {
+	if len(head) > 8 {
+		return head[:8]
+	}
+	return head
+}
+
+// handleCheckpointCreate takes a checkpoint on demand.
+func handleCheckpointCreate(profile string, args []string) {
+	fs := flag.NewFlagSet("checkpoint create", flag.ExitOnError)
+	message := fs.String("m", "", "Describe the checkpoint")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck checkpoint create <id|title> [-m message]")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+	inst := resolveCheckpointSession(profile, fs.Arg(0), out)
+
+	msg := *message
+	if msg == "" {
+		msg = "manual checkpoint"
+	}
+	cp, created, err := session.CreateInstanceCheckpoint(inst, msg)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to create checkpoint: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	data := checkpointJSON(cp)
+	data["success"] = true
+	data["created"] = created
+	data["session_id"] = inst.ID
+	if !created {
+		out.Success(fmt.Sprintf("No changes since checkpoint %s", cp.ShortCommit()), data)
+		return
+	}
+	out.Success(fmt.Sprintf("Created checkpoint %s for '%s'", cp.ShortCommit(), inst.Title), data)
+}
+
+// handleCheckpointRollback restores a checkpoint after snapshotting the
+// current state, so the rollback can itself be rolled back.
+func handleCheckpointRollback(profile string, args []string) {
+	fs := flag.NewFlagSet("checkpoint rollback", flag.ExitOnError)
+	force := fs.Bool("force", false, "Roll back even while the agent is running")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck checkpoint rollback <id|title> [n|commit] [options]")
+		fmt.Println()
+		fmt.Println("Restore the session's working tree to a checkpoint: n as numbered by")
+		fmt.Println("'checkpoint list' (1 = newest, the default) or a commit prefix. The branch")
+		fmt.Println("is reset to where it was, and files that were uncommitted then are restored")
+		fmt.Println("as uncommitted. The current state is checkpointed first.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+	if fs.NArg() > 2 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	inst := resolveCheckpointSession(profile, fs.Arg(0), out)
+
+	if st := inst.GetStatusThreadSafe(); st == session.StatusRunning && !*force {
+		out.Error(fmt.Sprintf("session '%s' is running; stop it or wait for it to finish (or use --force)", inst.Title), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	restored, safety, err := session.RollbackInstanceCheckpoint(inst, fs.Arg(1))
+	if err != nil {
+		data := map[string]interface{}{"session_id": inst.ID}
+		if safety.Commit != "" {
+			data["safety_checkpoint"] = safety.Commit
+		}
+		out.ErrorWithData(fmt.Sprintf("rollback failed: %v", err), ErrCodeInvalidOperation, data)
+		os.Exit(1)
+	}
+	data := checkpointJSON(restored)
+	data["success"] = true
+	data["session_id"] = inst.ID
+	data["safety_checkpoint"] = safety.Commit
+	out.Success(fmt.Sprintf("Rolled '%s' back to checkpoint %s (%s); previous state saved as %s",
+		inst.Title, restored.ShortCommit(), restored.Message, safety.ShortCommit()), data)
+}
@@ -282,6 +282,11 @@ const (
 	// ErrCodeDeliveryFailed: `session send` typed the message but could not
 	// confirm submission (delivery=typed_not_submitted, issue #1413).
 	ErrCodeDeliveryFailed = "DELIVERY_FAILED"
+	// ErrCodePromptLint: `session send` refused a prompt that failed the
+	// [send_lint] checks (override with --force).
+	ErrCodePromptLint = "PROMPT_LINT"
+	// ErrCodeWaitTimeout: `launch --wait-for` gave up before the agent was done.
+	ErrCodeWaitTimeout = "WAIT_TIMEOUT"
 )
 
 // ResolveSession finds a session by flexible matching (title, ID prefix, or path)
@@ -405,6 +410,10 @@ func StatusSymbol(status session.Status) string {
 		return "✕"
 	case session.StatusStopped:
 		return "■"
+	case session.StatusExited:
+		return "✓"
+	case session.StatusCrashed:
+		return "✗"
 	default:
 		return "?"
 	}
@@ -423,6 +432,10 @@ func StatusString(status session.Status) string {
 		return "error"
 	case session.StatusStopped:
 		return "stopped"
+	case session.StatusExited:
+		return "exited"
+	case session.StatusCrashed:
+		return "crashed"
 	case session.StatusQueued:
 		return "queued"
 	default:
@@ -440,6 +453,8 @@ func SubstateLabel(sub session.Substate) string {
 		return "model unavailable"
 	case session.SubstateAuth401:
 		return "auth (login)"
+	case session.SubstateUsageLimit:
+		return "usage limit"
 	case session.SubstateIdleAtEmptyPrompt:
 		return "idle at prompt"
 	case session.SubstateRunning:
@@ -222,6 +222,15 @@ func printCodexHooksUsage(w io.Writer) {
 }
 
 func handleCodexHooksInstall() {
+	if err := installCodexHooks(os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// installCodexHooks installs or upgrades the agent-deck notify hook in the
+// Codex config, reporting what it did to w.
+func installCodexHooks(w io.Writer) error {
 	configPath := getCodexConfigPath()
 	content, _ := readFileOrEmpty(configPath)
 
@@ -229,68 +238,56 @@ func handleCodexHooksInstall() {
 		codexNotifyLine + "\n" +
 		codexNotifyMarkerEnd + "\n"
 
+	write := func(updated string) error {
+		if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+			return fmt.Errorf("creating codex config dir: %w", err)
+		}
+		if err := os.WriteFile(configPath, []byte(updated), 0644); err != nil {
+			return fmt.Errorf("writing codex config: %w", err)
+		}
+		return nil
+	}
+
 	if strings.Contains(content, codexNotifyMarkerBegin) {
 		begin := strings.Index(content, codexNotifyMarkerBegin)
 		endRel := strings.Index(content[begin:], codexNotifyMarkerEnd)
 		if endRel != -1 {
 			end := begin + endRel + len(codexNotifyMarkerEnd)
 			updated := strings.TrimSpace(content[:begin] + content[end:])
-			updated = prependCodexNotifyBlock(block, updated)
-			if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
-				fmt.Fprintf(os.Stderr, "Error creating codex config dir: %v\n", err)
-				os.Exit(1)
+			if err := write(prependCodexNotifyBlock(block, updated)); err != nil {
+				return err
 			}
-			if err := os.WriteFile(configPath, []byte(updated), 0644); err != nil {
-				fmt.Fprintf(os.Stderr, "Error writing codex config: %v\n", err)
-				os.Exit(1)
-			}
-			fmt.Println("Codex notify hook upgraded successfully.")
-			fmt.Printf("Config: %s\n", configPath)
-			return
+			fmt.Fprintln(w, "Codex notify hook upgraded successfully.")
+			fmt.Fprintf(w, "Config: %s\n", configPath)
+			return nil
 		}
 	}
 
 	if updated, removed := removeLegacyCodexNotifyTable(content); removed {
-		updated = prependCodexNotifyBlock(block, strings.TrimSpace(updated))
-		if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating codex config dir: %v\n", err)
-			os.Exit(1)
-		}
-		if err := os.WriteFile(configPath, []byte(updated), 0644); err != nil {
-			fmt.Fprintf(os.Stderr, "Error writing codex config: %v\n", err)
-			os.Exit(1)
+		if err := write(prependCodexNotifyBlock(block, strings.TrimSpace(updated))); err != nil {
+			return err
 		}
-		fmt.Println("Codex notify hook upgraded successfully.")
-		fmt.Printf("Config: %s\n", configPath)
-		return
+		fmt.Fprintln(w, "Codex notify hook upgraded successfully.")
+		fmt.Fprintf(w, "Config: %s\n", configPath)
+		return nil
 	}
 
 	if codexNotifyExactRe.MatchString(content) {
-		fmt.Println("Codex notify hook is already installed.")
-		fmt.Printf("Config: %s\n", configPath)
-		return
+		fmt.Fprintln(w, "Codex notify hook is already installed.")
+		fmt.Fprintf(w, "Config: %s\n", configPath)
+		return nil
 	}
 
 	if codexNotifyKeyRe.MatchString(content) || codexNotifyTableRe.MatchString(content) {
-		fmt.Fprintf(os.Stderr, "Error: existing notify setting found in %s\n", configPath)
-		fmt.Fprintln(os.Stderr, "Please merge manually by setting:")
-		fmt.Fprintln(os.Stderr, `  notify = ["agent-deck", "codex-notify"]`)
-		os.Exit(1)
+		return fmt.Errorf("existing notify setting found in %s; merge manually by setting:\n  notify = [\"agent-deck\", \"codex-notify\"]", configPath)
 	}
 
-	newContent := prependCodexNotifyBlock(block, content)
-
-	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating codex config dir: %v\n", err)
-		os.Exit(1)
+	if err := write(prependCodexNotifyBlock(block, content)); err != nil {
+		return err
 	}
-	if err := os.WriteFile(configPath, []byte(newContent), 0644); err != nil {
-		fmt.Fprintf(os.Stderr, "Error writing codex config: %v\n", err)
-		os.Exit(1)
-	}
-
-	fmt.Println("Codex notify hook installed successfully.")
-	fmt.Printf("Config: %s\n", configPath)
+	fmt.Fprintln(w, "Codex notify hook installed successfully.")
+	fmt.Fprintf(w, "Config: %s\n", configPath)
+	return nil
 }
 
 func handleCodexHooksUninstall() {
@@ -348,16 +345,13 @@ func handleCodexHooksStatus() {
 	configPath := getCodexConfigPath()
 	content, _ := readFileOrEmpty(configPath)
 
-	switch {
-	case strings.Contains(content, codexNotifyMarkerBegin), codexNotifyExactRe.MatchString(content):
+	switch codexNotifyState(content) {
+	case codexNotifyInstalled:
 		fmt.Println("Status: INSTALLED")
-	case hasLegacyCodexNotifyTable(content):
-		fmt.Println("Status: LEGACY_NOTIFY_TABLE")
-		fmt.Println("Run 'agent-deck codex-hooks install' to migrate to current Codex format.")
-	case codexNotifyTableRe.MatchString(content):
+	case codexNotifyLegacyTable:
 		fmt.Println("Status: LEGACY_NOTIFY_TABLE")
 		fmt.Println("Run 'agent-deck codex-hooks install' to migrate to current Codex format.")
-	case codexNotifyKeyRe.MatchString(content):
+	case codexNotifyCustom:
 		fmt.Println("Status: CUSTOM_NOTIFY")
 	default:
 		fmt.Println("Status: NOT INSTALLED")
@@ -366,6 +360,29 @@ func handleCodexHooksStatus() {
 	fmt.Printf("Config: %s\n", configPath)
 }
 
+// Codex notify hook states, as reported by `codex-hooks status` and
+// `agent-deck doctor`.
+const (
+	codexNotifyInstalled    = "installed"
+	codexNotifyLegacyTable  = "legacy_notify_table"
+	codexNotifyCustom       = "custom_notify"
+	codexNotifyNotInstalled = "not_installed"
+)
+
+// codexNotifyState classifies the notify hook in a Codex config.toml.
+func codexNotifyState(content string) string {
+	switch {
+	case strings.Contains(content, codexNotifyMarkerBegin), codexNotifyExactRe.MatchString(content):
+		return codexNotifyInstalled
+	case hasLegacyCodexNotifyTable(content), codexNotifyTableRe.MatchString(content):
+		return codexNotifyLegacyTable
+	case codexNotifyKeyRe.MatchString(content):
+		return codexNotifyCustom
+	default:
+		return codexNotifyNotInstalled
+	}
+}
+
 func getCodexConfigPath() string {
 	if codexHome := strings.TrimSpace(os.Getenv("CODEX_HOME")); codexHome != "" {
 		return filepath.Join(codexHome, "config.toml")
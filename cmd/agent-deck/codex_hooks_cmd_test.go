@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -257,6 +258,29 @@ func TestCodexHooksInstall_UpgradesLegacyMarkerBlock(t *testing.T) {
 	}
 }
 
+func TestCodexHooksInstall_ForeignNotifyReturnsError(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+	t.Setenv("CODEX_HOME", "")
+
+	configPath := getCodexConfigPath()
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	foreign := "notify = [\"my-notifier\"]\n"
+	if err := os.WriteFile(configPath, []byte(foreign), 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	err := installCodexHooks(io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "existing notify setting") {
+		t.Fatalf("installCodexHooks() error = %v, want existing notify setting", err)
+	}
+	if content, _ := os.ReadFile(configPath); string(content) != foreign {
+		t.Fatalf("config changed despite the error: %q", content)
+	}
+}
+
 func TestGetCodexConfigPath_UsesCodexHome(t *testing.T) {
 	t.Setenv("CODEX_HOME", filepath.Join(t.TempDir(), "codex-home"))
 
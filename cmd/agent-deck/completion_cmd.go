@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// completionCommands is the top-level subcommand set offered by shell
+// completion. Keep in sync with the dispatch switch in main().
+var completionCommands = []string{
+	"add", "launch", "fanout", "try", "list", "remove", "rename", "status", "search", "session",
+	"mcp", "skill", "plugin", "extension", "prompt", "theme", "checkpoint", "backup", "import", "task", "schedule", "auto-respond", "group", "worktree", "port", "web", "remote", "relay", "bridge", "conductor", "governor",
+	"profile", "config", "update", "setup", "completion", "hooks", "codex-hooks",
+	"gemini-hooks", "hermes-hooks", "cursor-hooks", "costs", "inbox", "watcher",
+	"feedback", "debug", "doctor", "archive", "control", "uninstall", "version", "help",
+}
+
+// completionSessionCommands is the `session <cmd>` subcommand set.
+var completionSessionCommands = []string{
+	"start", "stop", "restart", "continue", "remove", "archive", "unarchive", "fork", "snapshot", "restore",
+	"clone", "attach", "show", "send", "send-to", "mailbox", "output", "outputs", "transcript", "history", "watch", "move", "relocate", "set", "pin", "unpin", "children", "depends", "search", "presets",
+}
+
+// handleCompletion prints a shell completion script to stdout.
+func handleCompletion(args []string) {
+	if len(args) != 1 || isHelpArg(args[0]) {
+		fmt.Println("Usage: agent-deck completion <bash|zsh|fish>")
+		fmt.Println()
+		fmt.Println("Print a shell completion script. Examples:")
+		fmt.Println("  agent-deck completion bash > ~/.local/share/bash-completion/completions/agent-deck")
+		fmt.Println("  agent-deck completion fish > ~/.config/fish/completions/agent-deck.fish")
+		if len(args) != 1 {
+			os.Exit(1)
+		}
+		return
+	}
+	if err := writeCompletionScript(os.Stdout, args[0]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// writeCompletionScript renders the completion script for shell.
+func writeCompletionScript(w io.Writer, shell string) error {
+	cmds := strings.Join(completionCommands, " ")
+	sessionCmds := strings.Join(completionSessionCommands, " ")
+	switch shell {
+	case "bash":
+		_, err := fmt.Fprintf(w, `# agent-deck bash completion
+_agent_deck() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    if [[ $COMP_CWORD -eq 1 ]]; then
+        COMPREPLY=($(compgen -W "%s" -- "$cur"))
+    elif [[ $COMP_CWORD -eq 2 && "${COMP_WORDS[1]}" == "session" ]]; then
+        COMPREPLY=($(compgen -W "%s" -- "$cur"))
+    else
+        COMPREPLY=($(compgen -f -- "$cur"))
+    fi
+}
+complete -F _agent_deck agent-deck
+`, cmds, sessionCmds)
+		return err
+	case "zsh":
+		_, err := fmt.Fprintf(w, `#compdef agent-deck
+# agent-deck zsh completion
+_agent_deck() {
+    if (( CURRENT == 2 )); then
+        compadd -- %s
+    elif (( CURRENT == 3 )) && [[ "${words[2]}" == "session" ]]; then
+        compadd -- %s
+    else
+        _files
+    fi
+}
+compdef _agent_deck agent-deck
+`, cmds, sessionCmds)
+		return err
+	case "fish":
+		if _, err := fmt.Fprintf(w, "# agent-deck fish completion\ncomplete -c agent-deck -f -n '__fish_use_subcommand' -a '%s'\n", cmds); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintf(w, "complete -c agent-deck -f -n '__fish_seen_subcommand_from session' -a '%s'\n", sessionCmds)
+		return err
+	default:
+		return fmt.Errorf("unsupported shell %q (supported: bash, zsh, fish)", shell)
+	}
+}
+
+// completionInstallPath returns where `setup` installs the completion script
+// for shell. bash-completion and fish auto-load from these locations; zsh
+// needs ~/.zsh/completions on $fpath (setup prints the hint).
+func completionInstallPath(home, shell string) (string, error) {
+	switch shell {
+	case "bash":
+		return filepath.Join(home, ".local", "share", "bash-completion", "completions", "agent-deck"), nil
+	case "zsh":
+		return filepath.Join(home, ".zsh", "completions", "_agent-deck"), nil
+	case "fish":
+		return filepath.Join(home, ".config", "fish", "completions", "agent-deck.fish"), nil
+	default:
+		return "", fmt.Errorf("unsupported shell %q", shell)
+	}
+}
+
+// detectShell returns the basename of $SHELL ("bash", "zsh", "fish", ...).
+func detectShell() string {
+	return filepath.Base(os.Getenv("SHELL"))
+}
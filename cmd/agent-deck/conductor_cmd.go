@@ -12,8 +12,10 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
 )
 
 // envVarFlags implements flag.Value for repeatable -env KEY=VALUE flags
@@ -48,6 +50,14 @@ func handleConductor(profile string, args []string) {
 		handleConductorStatus(profile, args[1:])
 	case "list":
 		handleConductorList(profile, args[1:])
+	case "dispatch":
+		handleConductorDispatch(profile, args[1:])
+	case "delegate":
+		handleConductorDelegate(profile, args[1:])
+	case "delegation", "delegations":
+		handleConductorDelegation(profile, args[1:])
+	case "receipts":
+		handleConductorReceipts(profile, args[1:])
 	case "move":
 		handleConductorMove(profile, args[1:])
 	case "migrate-dir":
@@ -131,6 +141,7 @@ func handleConductorSetup(profile string, args []string) {
 	heartbeat := fs.Bool("heartbeat", false, "Enable heartbeat for this conductor (default)")
 	noHeartbeat := fs.Bool("no-heartbeat", false, "Disable heartbeat for this conductor")
 	heartbeatIdleMinutes := fs.Int("heartbeat-idle-minutes", 0, "Minutes of idle time before pausing heartbeats (default 0=disabled, negative also disabled)")
+	scope := fs.String("scope", "", "Comma-separated group paths the heartbeat checks (default: the group named after the conductor)")
 	instructionsMD := fs.String("instructions-md", "", "Custom instructions file for this conductor (agent-specific, e.g., ~/docs/conductor-ops.md)")
 	sharedInstructionsMD := fs.String("shared-instructions-md", "", "Custom shared instructions file for all conductors of this agent")
 	claudeMD := fs.String("claude-md", "", "Custom CLAUDE.md for this conductor (e.g., ~/docs/conductor-ryan.md)")
@@ -163,6 +174,9 @@ func handleConductorSetup(profile string, args []string) {
 		fmt.Println("        Disable heartbeat for this conductor")
 		fmt.Println("  -heartbeat-idle-minutes int")
 		fmt.Println("        Minutes of idle time before pausing heartbeats (default 0=disabled, negative also disabled)")
+		fmt.Println("  -scope string")
+		fmt.Println("        Comma-separated group paths the heartbeat checks, subgroups included")
+		fmt.Println("        (default: the group named after the conductor; \"\" resets it)")
 		fmt.Println("  -no-clear-on-compact")
 		fmt.Println("        Claude-only: allow normal compaction instead of /clear when context fills up")
 		fmt.Println()
@@ -219,6 +233,17 @@ func handleConductorSetup(profile string, args []string) {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	scopeSet := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "scope" {
+			scopeSet = true
+		}
+	})
+	scopeGroups, err := session.ParseConductorScope(*scope)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -scope: %v\n", err)
+		os.Exit(1)
+	}
 	if *instructionsMD != "" && *claudeMD != "" {
 		fmt.Fprintln(os.Stderr, "Error: use only one of -instructions-md or -claude-md")
 		os.Exit(1)
@@ -518,8 +543,22 @@ func handleConductorSetup(profile string, args []string) {
 		fmt.Fprintf(os.Stderr, "Error setting up conductor %s: %v\n", name, err)
 		os.Exit(1)
 	}
+	if scopeSet {
+		meta, err := session.LoadConductorMeta(name)
+		if err == nil {
+			meta.Scope = strings.Join(scopeGroups, ",")
+			err = session.SaveConductorMeta(meta)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving scope for conductor %s: %v\n", name, err)
+			os.Exit(1)
+		}
+	}
 	if !*jsonOutput {
 		fmt.Printf("  [ok] Directory, %s, and meta.json created\n", spec.InstructionsFileName)
+		if len(scopeGroups) > 0 {
+			fmt.Printf("  [ok] Heartbeat scope: %s\n", strings.Join(scopeGroups, ", "))
+		}
 	}
 
 	// Step 5: Register session in the profile's storage
@@ -611,14 +650,19 @@ func handleConductorSetup(profile string, args []string) {
 			fmt.Println("Installing bridge...")
 		}
 
-		depsInstalled := installPythonDeps()
+		// Telegram and Slack run on the native `agent-deck bridge`; only
+		// Discord still needs the Python bridge and its dependencies.
+		depsInstalled := true
+		if discordConfigured {
+			depsInstalled = installPythonDeps()
 
-		if err := session.InstallBridgeScript(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error installing bridge.py: %v\n", err)
-			os.Exit(1)
-		}
-		if !*jsonOutput {
-			fmt.Println("[ok] bridge.py installed")
+			if err := session.InstallBridgeScript(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error installing bridge.py: %v\n", err)
+				os.Exit(1)
+			}
+			if !*jsonOutput {
+				fmt.Println("[ok] bridge.py installed (Discord)")
+			}
 		}
 
 		if !depsInstalled {
@@ -635,8 +679,7 @@ func handleConductorSetup(profile string, args []string) {
 			daemonPath, err := session.InstallBridgeDaemon()
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to install bridge daemon: %v\n", err)
-				condDir, _ := session.ConductorDir()
-				fmt.Fprintf(os.Stderr, "Run manually: python3 %s/bridge.py\n", condDir)
+				fmt.Fprintf(os.Stderr, "Run manually: %s\n", session.BridgeManualCommand())
 			} else {
 				plistPath = daemonPath
 				if !*jsonOutput {
@@ -1008,9 +1051,37 @@ func handleConductorStatus(_ string, args []string) {
 		Description          string `json:"description,omitempty"`
 		LastActivityAt       string `json:"last_activity_at,omitempty"`
 		HeartbeatIdleMinutes int    `json:"heartbeat_idle_minutes"`
+		// Conductor dispatch ([conductor.dispatch]): active children and
+		// the deferred queue in drain order.
+		DispatchActive int                  `json:"dispatch_active"`
+		DispatchQueue  []dispatchQueueEntry `json:"dispatch_queue,omitempty"`
+		// Open conductor-to-conductor delegations, with their chains.
+		Delegations []delegationStatusEntry `json:"delegations,omitempty"`
+		// Prompts sent to children that were not acted on
+		// ([conductor.receipts]).
+		Unacknowledged []receiptEntry `json:"unacknowledged,omitempty"`
+		// Latest conductor watchdog view ([conductor.watchdog]).
+		Watchdog *session.WatchdogTarget `json:"watchdog,omitempty"`
 	}
 	var statuses []conductorStatus
 
+	// Per-profile capacity decisions for each configured tool: what would
+	// happen to a dispatch launched right now.
+	dispatchSettings := session.GetConductorSettings().Dispatch
+	dispatchDecisions := map[string][]session.DispatchDecision{}
+	receiptSettings := session.GetConductorSettings().Receipts
+	receipts := map[string][]*statedb.ReceiptRow{}
+
+	// Open delegations across every conductor profile: a delegation lives in
+	// its target's profile, so the delegator's view needs all of them.
+	watchdog, _ := session.LoadWatchdogReport()
+
+	var openDelegations []*statedb.DelegationRow
+	if stores, err := openDelegationStores(); err == nil {
+		openDelegations, _, _ = stores.load(session.OpenDelegationStatuses...)
+		stores.Close()
+	}
+
 	for _, meta := range conductors {
 		cs := conductorStatus{
 			Name:                 meta.Name,
@@ -1046,9 +1117,23 @@ func handleConductorStatus(_ string, args []string) {
 						break
 					}
 				}
+				if dispatchSettings.Enabled() {
+					cs.DispatchActive, cs.DispatchQueue = conductorDispatchState(cs.SessionID, instances)
+					if _, done := dispatchDecisions[meta.Profile]; !done {
+						dispatchDecisions[meta.Profile] = conductorDispatchDecisions(storage, dispatchSettings, instances)
+					}
+				}
+				if _, done := receipts[meta.Profile]; !done {
+					receipts[meta.Profile], _ = session.RefreshDispatchReceipts(storage.GetDB(), instances, receiptSettings, time.Now())
+				}
+				cs.Unacknowledged = conductorReceiptState(cs.SessionID, receipts[meta.Profile], instances)
 			}
 		}
 
+		cs.Delegations = conductorDelegationState(meta.Name, openDelegations)
+		if watchdog != nil {
+			cs.Watchdog = watchdog.Conductors[meta.Name]
+		}
 		statuses = append(statuses, cs)
 	}
 
@@ -1057,12 +1142,22 @@ func handleConductorStatus(_ string, args []string) {
 	notifierRunning := session.IsTransitionNotifierDaemonRunning()
 
 	if *jsonOutput {
-		output, _ := json.MarshalIndent(map[string]any{
+		payload := map[string]any{
 			"enabled":                 true,
 			"conductors":              statuses,
 			"daemon_running":          daemonRunning,
 			"notifier_daemon_running": notifierRunning,
-		}, "", "  ")
+		}
+		if watchdog != nil {
+			payload["watchdog"] = watchdog
+		}
+		if dispatchSettings.Enabled() {
+			payload["dispatch"] = map[string]any{
+				"limits":    dispatchSettings,
+				"decisions": dispatchDecisions,
+			}
+		}
+		output, _ := json.MarshalIndent(payload, "", "  ")
 		fmt.Println(string(output))
 		return
 	}
@@ -1077,6 +1172,9 @@ func handleConductorStatus(_ string, args []string) {
 	} else {
 		fmt.Println("Bridge daemon: STOPPED")
 	}
+	if watchdog != nil && watchdog.Bridge != nil {
+		printWatchdogTarget("  ", watchdog.Bridge)
+	}
 	if notifierRunning {
 		fmt.Println("Notifier daemon: RUNNING")
 	} else {
@@ -1118,9 +1216,24 @@ func handleConductorStatus(_ string, args []string) {
 		}
 
 		fmt.Printf("  %s %s [%s] agent:%s heartbeat:%s  (%s)%s\n", statusIcon, cs.Name, cs.Profile, cs.Agent, hb, statusText, desc)
+		if dispatchSettings.Enabled() && (cs.DispatchActive > 0 || len(cs.DispatchQueue) > 0) {
+			fmt.Printf("      dispatch: %d active, %d queued\n", cs.DispatchActive, len(cs.DispatchQueue))
+			for _, q := range cs.DispatchQueue {
+				fmt.Printf("        [p%d] %s (%s) — %s\n", q.Priority, q.Title, q.Tool, q.Reason)
+			}
+		}
+		printConductorDelegations(cs.Delegations)
+		printConductorReceipts(cs.Unacknowledged)
+		if cs.Watchdog != nil {
+			printWatchdogTarget("      ", cs.Watchdog)
+		}
 	}
 	fmt.Println()
 
+	if dispatchSettings.Enabled() {
+		printConductorDispatchDecisions(dispatchSettings, dispatchDecisions)
+	}
+
 	// Hints
 	if !daemonRunning {
 		fmt.Printf("Tip: %s\n", session.BridgeDaemonHint())
@@ -1130,6 +1243,29 @@ func handleConductorStatus(_ string, args []string) {
 	}
 }
 
+// printWatchdogTarget prints the watchdog line for a conductor or the bridge:
+// its problem and restarts in the last hour. Nothing is printed for a healthy
+// target the watchdog never had to restart.
+func printWatchdogTarget(indent string, t *session.WatchdogTarget) {
+	if t.Healthy && len(t.Restarts) == 0 {
+		return
+	}
+	var parts []string
+	if !t.Healthy && t.Problem != "" {
+		parts = append(parts, t.Problem)
+	}
+	if n := len(t.Restarts); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d restart(s) in the last hour, last %s ago", n, time.Since(t.LastRestartAt).Round(time.Second)))
+	}
+	if t.RestartError != "" {
+		parts = append(parts, "restart failed: "+t.RestartError)
+	}
+	if t.GaveUp {
+		parts = append(parts, "restart limit reached")
+	}
+	fmt.Printf("%swatchdog: %s\n", indent, strings.Join(parts, "; "))
+}
+
 // handleConductorList lists all conductors
 func handleConductorList(profile string, args []string) {
 	fs := flag.NewFlagSet("conductor list", flag.ExitOnError)
@@ -1221,8 +1357,11 @@ func handleConductorList(profile string, args []string) {
 		}
 
 		desc := ""
+		if meta.Scope != "" {
+			desc = "  scope:" + meta.Scope
+		}
 		if meta.Description != "" {
-			desc = fmt.Sprintf("  %q", meta.Description)
+			desc += fmt.Sprintf("  %q", meta.Description)
 		}
 
 		fmt.Printf("  %-12s [%s]  agent:%-6s heartbeat:%-3s  %-10s%s\n", meta.Name, meta.Profile, meta.GetAgent(), hb, statusText, desc)
@@ -1427,6 +1566,10 @@ func printConductorHelp() {
 	fmt.Println("  teardown <name>  Stop and optionally remove a conductor (or --all)")
 	fmt.Println("  status [name]    Show conductor health (all or specific)")
 	fmt.Println("  list             List all configured conductors")
+	fmt.Println("  dispatch         Start deferred dispatches as [conductor.dispatch] capacity allows")
+	fmt.Println("  delegate <from> <to> <task>  Hand a task to another conductor (tracked, loop-checked)")
+	fmt.Println("  delegation list|done  Show open delegations or close one")
+	fmt.Println("  receipts         Show prompts children did not act on (--retry to re-deliver)")
 	fmt.Println("  move <name>      Move a conductor to another profile (--to-profile)")
 	fmt.Println("  migrate-dir <path>  Relocate the conductor base dir (move homes + reconcile daemons)")
 	fmt.Println("  help             Show this help")
@@ -1437,6 +1580,7 @@ func printConductorHelp() {
 	fmt.Println("  agent-deck -p work conductor setup infra --no-heartbeat")
 	fmt.Println("  agent-deck conductor list")
 	fmt.Println("  agent-deck conductor status")
+	fmt.Println("  agent-deck conductor delegate ryan review \"Review the auth PR\"")
 	fmt.Println("  agent-deck conductor teardown infra --remove")
 	fmt.Println("  agent-deck conductor teardown --all --remove")
 	fmt.Println("  agent-deck conductor move ryan --to-profile march")
@@ -0,0 +1,486 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/send"
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// delegationStores holds the statedb of every profile that has a conductor.
+// A delegation is stored in its target conductor's profile, so lookups by ID
+// and chain checks have to look across all of them.
+type delegationStores struct {
+	byProfile map[string]*session.Storage
+}
+
+// openDelegationStores opens the storage of each conductor profile.
+func openDelegationStores() (*delegationStores, error) {
+	conductors, err := session.ListConductors()
+	if err != nil {
+		return nil, err
+	}
+	s := &delegationStores{byProfile: map[string]*session.Storage{}}
+	for _, meta := range conductors {
+		if _, err := s.storage(meta.Profile); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// storage returns (opening on first use) the storage of profile.
+func (s *delegationStores) storage(profile string) (*session.Storage, error) {
+	if st, ok := s.byProfile[profile]; ok {
+		return st, nil
+	}
+	st, err := session.NewStorageWithProfile(profile)
+	if err != nil {
+		return nil, fmt.Errorf("open profile %s: %w", profile, err)
+	}
+	s.byProfile[profile] = st
+	return st, nil
+}
+
+// Close closes every opened storage.
+func (s *delegationStores) Close() {
+	for _, st := range s.byProfile {
+		_ = st.Close()
+	}
+}
+
+// profiles returns the opened profiles in name order.
+func (s *delegationStores) profiles() []string {
+	names := make([]string, 0, len(s.byProfile))
+	for p := range s.byProfile {
+		names = append(names, p)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// load returns delegations from every profile, oldest first, restricted to
+// statuses when given, along with the profile each one is stored in.
+func (s *delegationStores) load(statuses ...string) ([]*statedb.DelegationRow, map[string]string, error) {
+	var all []*statedb.DelegationRow
+	where := map[string]string{}
+	for _, p := range s.profiles() {
+		db := s.byProfile[p].GetDB()
+		if db == nil {
+			continue
+		}
+		rows, err := db.LoadDelegations(statuses...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("load delegations (%s): %w", p, err)
+		}
+		for _, r := range rows {
+			where[r.ID] = p
+		}
+		all = append(all, rows...)
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].CreatedAt.Before(all[j].CreatedAt) })
+	return all, where, nil
+}
+
+// find returns the delegation with id and the profile storing it.
+func (s *delegationStores) find(id string) (*statedb.DelegationRow, string, error) {
+	for _, p := range s.profiles() {
+		db := s.byProfile[p].GetDB()
+		if db == nil {
+			continue
+		}
+		d, err := db.LoadDelegation(id)
+		if err != nil {
+			return nil, "", err
+		}
+		if d != nil {
+			return d, p, nil
+		}
+	}
+	return nil, "", fmt.Errorf("delegation %s not found", id)
+}
+
+// save writes d to profile's statedb.
+func (s *delegationStores) save(profile string, d *statedb.DelegationRow) error {
+	st, err := s.storage(profile)
+	if err != nil {
+		return err
+	}
+	db := st.GetDB()
+	if db == nil {
+		return fmt.Errorf("profile %s has no state database", profile)
+	}
+	d.UpdatedAt = time.Now()
+	return db.SaveDelegation(d)
+}
+
+// conductorInstance returns the session of the named conductor.
+func (s *delegationStores) conductorInstance(meta *session.ConductorMeta) (*session.Instance, error) {
+	st, err := s.storage(meta.Profile)
+	if err != nil {
+		return nil, err
+	}
+	instances, _, err := st.LoadWithGroups()
+	if err != nil {
+		return nil, err
+	}
+	title := session.ConductorSessionTitle(meta.Name)
+	for _, inst := range instances {
+		if inst.Title == title {
+			return inst, nil
+		}
+	}
+	return nil, fmt.Errorf("conductor %s has no session in profile %s", meta.Name, meta.Profile)
+}
+
+// deliverToConductor sends message to a conductor session the way `session
+// send` does: wait for the agent to be ready, then type and submit it. db is
+// the conductor's profile statedb, where the send is stamped.
+func deliverToConductor(inst *session.Instance, db *statedb.StateDB, message string, timeout time.Duration) error {
+	if !inst.Exists() {
+		return fmt.Errorf("session '%s' is not running", inst.Title)
+	}
+	tmuxSess := inst.GetTmuxSession()
+	if tmuxSess == nil {
+		return fmt.Errorf("could not determine tmux session for '%s'", inst.Title)
+	}
+	if err := send.WaitForAgentReady(tmuxSess, inst.Tool, timeout, send.PromptGates{
+		ClaudeComposer: session.IsClaudeCompatible(inst.Tool),
+		CodexPrompt:    session.IsCodexCompatible(inst.Tool),
+	}); err != nil {
+		return fmt.Errorf("timeout waiting for '%s': %w", inst.Title, err)
+	}
+	sentAt := time.Now()
+	if _, err := executeSend(tmuxSess, inst.Tool, message, false, defaultSendTuning()); err != nil {
+		return err
+	}
+	if db != nil {
+		_ = db.WriteLastSentAt(inst.ID, sentAt.Unix())
+	}
+	return nil
+}
+
+// handleConductorDelegate implements `conductor delegate <from> <to> <task>`.
+func handleConductorDelegate(_ string, args []string) {
+	fs := flag.NewFlagSet("conductor delegate", flag.ExitOnError)
+	parentID := fs.String("parent", "", "Delegation this task is part of (default: the newest one <from> is working on)")
+	timeout := fs.Duration("timeout", 5*time.Minute, "Max time to wait for the target conductor to be ready")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck conductor delegate <from> <to> <task> [options]")
+		fmt.Println()
+		fmt.Println("Hand a task from one conductor to another. The delegation is tracked and")
+		fmt.Println("delivered to the target conductor's session as a [DELEGATION ...] message;")
+		fmt.Println("the target reports back with 'conductor delegation done'.")
+		fmt.Println()
+		fmt.Println("Delegating to a conductor already in the chain is refused, as is a chain")
+		fmt.Println("deeper than [conductor.delegation] max_depth (default 3).")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck conductor delegate ops review \"Review PR #42 for API breakage\"")
+		fmt.Println("  agent-deck conductor delegate review docs \"Update the API docs\" --parent dlg-1a2b3c4d")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+	if fs.NArg() < 3 {
+		fs.Usage()
+		out.Error("from, to and task are required", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	fromName, toName := fs.Arg(0), fs.Arg(1)
+	task := strings.Join(fs.Args()[2:], " ")
+
+	fromMeta, err := session.LoadConductorMeta(fromName)
+	if err != nil {
+		out.Error(fmt.Sprintf("conductor %q not found: %v", fromName, err), ErrCodeNotFound)
+		os.Exit(2)
+	}
+	toMeta, err := session.LoadConductorMeta(toName)
+	if err != nil {
+		out.Error(fmt.Sprintf("conductor %q not found: %v", toName, err), ErrCodeNotFound)
+		os.Exit(2)
+	}
+
+	stores, err := openDelegationStores()
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	defer stores.Close()
+
+	open, _, err := stores.load(session.OpenDelegationStatuses...)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	var inbound []*statedb.DelegationRow
+	for _, d := range open {
+		if d.To == fromMeta.Name {
+			inbound = append(inbound, d)
+		}
+	}
+	var parent *statedb.DelegationRow
+	if *parentID != "" {
+		if parent, _, err = stores.find(*parentID); err != nil {
+			out.Error(err.Error(), ErrCodeNotFound)
+			os.Exit(2)
+		}
+	}
+
+	settings := session.GetConductorSettings().Delegation
+	d, err := session.PlanDelegation(fromMeta.Name, toMeta.Name, task, parent, inbound, settings.GetMaxDepth())
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	target, err := stores.conductorInstance(toMeta)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(2)
+	}
+	if err := stores.save(toMeta.Profile, d); err != nil {
+		out.Error(fmt.Sprintf("failed to record delegation: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	if err := deliverToConductor(target, stores.byProfile[toMeta.Profile].GetDB(), session.FormatDelegationMessage(d), *timeout); err != nil {
+		d.Status = session.DelegationFailed
+		d.Result = "delivery failed: " + err.Error()
+		_ = stores.save(toMeta.Profile, d)
+		out.Error(fmt.Sprintf("failed to deliver delegation %s: %v", d.ID, err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	d.Status = session.DelegationDelivered
+	if err := stores.save(toMeta.Profile, d); err != nil {
+		out.Error(fmt.Sprintf("delegation delivered but not recorded: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	out.Success(fmt.Sprintf("Delegated %s to %s (%s)", d.ID, d.To, session.FormatDelegationChain(d.Chain)), delegationJSON(d, toMeta.Profile))
+}
+
+// handleConductorDelegation implements `conductor delegation list|done`.
+func handleConductorDelegation(_ string, args []string) {
+	usage := func() {
+		fmt.Println("Usage: agent-deck conductor delegation <command>")
+		fmt.Println()
+		fmt.Println("Commands:")
+		fmt.Println("  list [--all]                         Show open delegations (--all: include closed)")
+		fmt.Println("  done <id> [--result TEXT] [--failed]  Close a delegation and report back to its delegator")
+	}
+	if len(args) == 0 {
+		usage()
+		os.Exit(1)
+	}
+	switch args[0] {
+	case "list", "ls":
+		handleConductorDelegationList(args[1:])
+	case "done":
+		handleConductorDelegationDone(args[1:])
+	case "help", "--help", "-h":
+		usage()
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown delegation command: %s\n\n", args[0])
+		usage()
+		os.Exit(1)
+	}
+}
+
+func handleConductorDelegationList(args []string) {
+	fs := flag.NewFlagSet("conductor delegation list", flag.ExitOnError)
+	all := fs.Bool("all", false, "Include done and failed delegations")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	stores, err := openDelegationStores()
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	defer stores.Close()
+	var statuses []string
+	if !*all {
+		statuses = session.OpenDelegationStatuses
+	}
+	rows, where, err := stores.load(statuses...)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	items := make([]map[string]any, 0, len(rows))
+	for _, d := range rows {
+		items = append(items, delegationJSON(d, where[d.ID]))
+	}
+	out.Print(formatDelegationList(rows), map[string]any{"success": true, "delegations": items})
+}
+
+func handleConductorDelegationDone(args []string) {
+	fs := flag.NewFlagSet("conductor delegation done", flag.ExitOnError)
+	result := fs.String("result", "", "Summary reported back to the delegating conductor")
+	failed := fs.Bool("failed", false, "Mark the delegation failed instead of done")
+	noNotify := fs.Bool("no-notify", false, "Don't send the result to the delegating conductor")
+	timeout := fs.Duration("timeout", 2*time.Minute, "Max time to wait for the delegating conductor to be ready")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+	if fs.NArg() < 1 {
+		out.Error("delegation id is required", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	stores, err := openDelegationStores()
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	defer stores.Close()
+	d, profile, err := stores.find(fs.Arg(0))
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(2)
+	}
+	if !slices.Contains(session.OpenDelegationStatuses, d.Status) {
+		out.Error(fmt.Sprintf("delegation %s is already %s", d.ID, d.Status), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	d.Status = session.DelegationDone
+	if *failed {
+		d.Status = session.DelegationFailed
+	}
+	d.Result = strings.TrimSpace(*result)
+	if err := stores.save(profile, d); err != nil {
+		out.Error(fmt.Sprintf("failed to record delegation: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	notified := false
+	if !*noNotify {
+		err := errors.New("delegating conductor not found")
+		if meta, metaErr := session.LoadConductorMeta(d.From); metaErr == nil {
+			var inst *session.Instance
+			if inst, err = stores.conductorInstance(meta); err == nil {
+				err = deliverToConductor(inst, stores.byProfile[meta.Profile].GetDB(), session.FormatDelegationResultMessage(d), *timeout)
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not notify %s: %v\n", d.From, err)
+		}
+		notified = err == nil
+	}
+
+	data := delegationJSON(d, profile)
+	data["notified"] = notified
+	out.Success(fmt.Sprintf("Delegation %s marked %s", d.ID, d.Status), data)
+}
+
+func delegationJSON(d *statedb.DelegationRow, profile string) map[string]any {
+	return map[string]any{
+		"id":         d.ID,
+		"parent_id":  d.ParentID,
+		"from":       d.From,
+		"to":         d.To,
+		"chain":      d.Chain,
+		"task":       d.Task,
+		"status":     d.Status,
+		"result":     d.Result,
+		"profile":    profile,
+		"created_at": d.CreatedAt.UTC().Format(time.RFC3339),
+		"updated_at": d.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// formatDelegationList renders one block per delegation: id, status, age and
+// chain, then the first line of the task.
+func formatDelegationList(rows []*statedb.DelegationRow) string {
+	if len(rows) == 0 {
+		return "No delegations.\n"
+	}
+	var b strings.Builder
+	for _, d := range rows {
+		fmt.Fprintf(&b, "%s  %-9s  %-8s  %s\n", d.ID, d.Status, formatDuration(time.Since(d.CreatedAt)), session.FormatDelegationChain(d.Chain))
+		first, _, _ := strings.Cut(d.Task, "\n")
+		fmt.Fprintf(&b, "    %s\n", truncate(first, 90))
+		if d.Result != "" {
+			res, _, _ := strings.Cut(d.Result, "\n")
+			fmt.Fprintf(&b, "    → %s\n", truncate(res, 90))
+		}
+	}
+	return b.String()
+}
+
+// delegationStatusEntry is one open delegation as shown by conductor status.
+type delegationStatusEntry struct {
+	ID        string   `json:"id"`
+	Direction string   `json:"direction"` // "in" (handed to this conductor) or "out"
+	Peer      string   `json:"peer"`
+	Chain     []string `json:"chain"`
+	Status    string   `json:"status"`
+	Task      string   `json:"task"`
+	CreatedAt string   `json:"created_at"`
+}
+
+// conductorDelegationState returns the open delegations a conductor received
+// or handed out, oldest first.
+func conductorDelegationState(name string, open []*statedb.DelegationRow) []delegationStatusEntry {
+	var entries []delegationStatusEntry
+	for _, d := range open {
+		var direction, peer string
+		switch name {
+		case d.To:
+			direction, peer = "in", d.From
+		case d.From:
+			direction, peer = "out", d.To
+		default:
+			continue
+		}
+		first, _, _ := strings.Cut(d.Task, "\n")
+		entries = append(entries, delegationStatusEntry{
+			ID:        d.ID,
+			Direction: direction,
+			Peer:      peer,
+			Chain:     d.Chain,
+			Status:    d.Status,
+			Task:      first,
+			CreatedAt: d.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return entries
+}
+
+// printConductorDelegations renders a conductor's open delegations under its
+// status line.
+func printConductorDelegations(entries []delegationStatusEntry) {
+	for _, e := range entries {
+		arrow := "←"
+		if e.Direction == "out" {
+			arrow = "→"
+		}
+		fmt.Printf("      delegation %s %s %s  [%s]  %s\n", e.ID, arrow, e.Peer, session.FormatDelegationChain(e.Chain), e.Status)
+		fmt.Printf("        %s\n", truncate(e.Task, 80))
+	}
+}
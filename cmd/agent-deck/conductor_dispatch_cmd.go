@@ -0,0 +1,240 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/costs"
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// dispatchTokensToday sums the tokens recorded since 00:00 UTC for the
+// conductor children running tool. Only queried when the tool has a daily
+// budget; a missing database or query error counts as zero so cost
+// tracking problems never wedge dispatch.
+func dispatchTokensToday(storage *session.Storage, settings session.DispatchSettings, instances []*session.Instance, tool string) int64 {
+	if settings.ToolLimits(tool).DailyTokenBudget <= 0 || storage == nil {
+		return 0
+	}
+	db := storage.GetDB()
+	if db == nil {
+		return 0
+	}
+	since := time.Now().UTC().Truncate(24 * time.Hour)
+	n, err := costs.NewStore(db.DB()).TokensForSessions(session.DispatchSessionIDs(instances, tool), since)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// checkConductorDispatch evaluates the dispatch limits for a conductor child
+// running tool.
+func checkConductorDispatch(storage *session.Storage, settings session.DispatchSettings, instances []*session.Instance, tool string) session.DispatchDecision {
+	tokens := dispatchTokensToday(storage, settings, instances, tool)
+	return session.CheckDispatchCapacity(settings, instances, tool, tokens)
+}
+
+// drainConductorDispatch starts deferred conductor dispatches, highest
+// priority first, while capacity allows, delivering each one's pending
+// message. Entries that still cannot start get their reason refreshed; a
+// blocked tool does not hold back lower-priority work for another tool.
+// The caller is responsible for persisting state afterward.
+func drainConductorDispatch(storage *session.Storage, settings session.DispatchSettings, instances []*session.Instance) []*session.Instance {
+	if !settings.Enabled() {
+		return nil
+	}
+	var drained []*session.Instance
+	for _, next := range session.QueuedDispatches(instances) {
+		decision := checkConductorDispatch(storage, settings, instances, next.Tool)
+		if !decision.Allowed {
+			next.Dispatch.Reason = decision.Reason
+			continue
+		}
+		message := next.Dispatch.Message
+		var err error
+		if message != "" {
+			err = next.StartWithMessage(message)
+		} else {
+			err = next.Start()
+		}
+		if err != nil {
+			// Drain is best-effort, like drainGroupQueue.
+			next.Status = session.StatusError
+			fmt.Fprintf(os.Stderr, "dispatch drain failed to start %s: %v\n", next.Title, err)
+			continue
+		}
+		next.ClearDispatch()
+		if message != "" {
+			session.RecordDispatchReceipt(storage.GetDB(), next, instances, message, time.Now())
+		}
+		drained = append(drained, next)
+	}
+	return drained
+}
+
+// handleConductorDispatch drains the deferred conductor dispatch queue.
+func handleConductorDispatch(profile string, args []string) {
+	fs := flag.NewFlagSet("conductor dispatch", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck [-p profile] conductor dispatch [options]")
+		fmt.Println()
+		fmt.Println("Start deferred conductor dispatches (highest priority first) while the")
+		fmt.Println("[conductor.dispatch] limits allow. Stopping a conductor child drains the")
+		fmt.Println("queue automatically; run this after raising a limit or at budget reset.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, *quiet)
+	settings := session.GetConductorSettings().Dispatch
+
+	storage, instances, groups, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+
+	drained := drainConductorDispatch(storage, settings, instances)
+	if err := saveSessionData(storage, instances, groups); err != nil {
+		out.Error(fmt.Sprintf("failed to save session state: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	started := make([]map[string]any, 0, len(drained))
+	for _, inst := range drained {
+		started = append(started, map[string]any{"id": inst.ID, "title": inst.Title, "tool": inst.Tool})
+	}
+	remaining := session.QueuedDispatches(instances)
+	deferred := make([]map[string]any, 0, len(remaining))
+	for _, inst := range remaining {
+		deferred = append(deferred, map[string]any{
+			"id":       inst.ID,
+			"title":    inst.Title,
+			"tool":     inst.Tool,
+			"priority": inst.Dispatch.Priority,
+			"reason":   inst.Dispatch.Reason,
+		})
+	}
+
+	msg := fmt.Sprintf("Dispatched %d, %d still queued", len(drained), len(remaining))
+	if !settings.Enabled() {
+		msg = "No [conductor.dispatch] limits configured; nothing to drain"
+	}
+	out.Success(msg, map[string]any{
+		"success":  true,
+		"started":  started,
+		"deferred": deferred,
+	})
+}
+
+// dispatchQueueEntry is one deferred dispatch as shown by conductor status.
+type dispatchQueueEntry struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	Tool       string `json:"tool"`
+	Priority   int    `json:"priority"`
+	Reason     string `json:"reason,omitempty"`
+	DeferredAt string `json:"deferred_at,omitempty"`
+}
+
+// conductorDispatchState returns the active child count and the deferred
+// queue (drain order) for the conductor session conductorID.
+func conductorDispatchState(conductorID string, instances []*session.Instance) (int, []dispatchQueueEntry) {
+	if conductorID == "" {
+		return 0, nil
+	}
+	active := 0
+	for _, inst := range session.ConductorDispatches(instances) {
+		if inst.ParentSessionID == conductorID && (inst.Status == session.StatusRunning || inst.Status == session.StatusStarting) {
+			active++
+		}
+	}
+	var queue []dispatchQueueEntry
+	for _, inst := range session.QueuedDispatches(instances) {
+		if inst.ParentSessionID != conductorID {
+			continue
+		}
+		entry := dispatchQueueEntry{
+			ID:       inst.ID,
+			Title:    inst.Title,
+			Tool:     inst.Tool,
+			Priority: inst.Dispatch.Priority,
+			Reason:   inst.Dispatch.Reason,
+		}
+		if !inst.Dispatch.DeferredAt.IsZero() {
+			entry.DeferredAt = inst.Dispatch.DeferredAt.UTC().Format(time.RFC3339)
+		}
+		queue = append(queue, entry)
+	}
+	return active, queue
+}
+
+// conductorDispatchDecisions evaluates the limits for every configured tool
+// in one profile, sorted by tool name.
+func conductorDispatchDecisions(storage *session.Storage, settings session.DispatchSettings, instances []*session.Instance) []session.DispatchDecision {
+	if len(settings.Tools) == 0 {
+		// Only the global cap is configured: one decision covering all tools.
+		d := session.CheckDispatchCapacity(settings, instances, "all", 0)
+		d.ToolActive = d.Active
+		d.ToolMaxActive = settings.MaxActive
+		return []session.DispatchDecision{d}
+	}
+	tools := make([]string, 0, len(settings.Tools))
+	for tool := range settings.Tools {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+	decisions := make([]session.DispatchDecision, 0, len(tools))
+	for _, tool := range tools {
+		decisions = append(decisions, checkConductorDispatch(storage, settings, instances, tool))
+	}
+	return decisions
+}
+
+// printConductorDispatchDecisions renders the per-profile dispatch capacity
+// section of conductor status.
+func printConductorDispatchDecisions(settings session.DispatchSettings, decisions map[string][]session.DispatchDecision) {
+	fmt.Println("Dispatch capacity")
+	profiles := make([]string, 0, len(decisions))
+	for p := range decisions {
+		profiles = append(profiles, p)
+	}
+	sort.Strings(profiles)
+	for _, p := range profiles {
+		for _, d := range decisions[p] {
+			verdict := "accepting"
+			if !d.Allowed {
+				verdict = "deferring: " + d.Reason
+			}
+			line := fmt.Sprintf("  [%s] %s: %d%s active", p, d.Tool, d.ToolActive, dispatchLimitSuffix(d.ToolMaxActive))
+			if d.TokenBudget > 0 {
+				line += fmt.Sprintf(", %d/%d tokens today", d.TokensToday, d.TokenBudget)
+			}
+			fmt.Printf("%s  (%s)\n", line, verdict)
+		}
+	}
+	if settings.MaxActive > 0 {
+		fmt.Printf("  total cap: %d active conductor children per profile\n", settings.MaxActive)
+	}
+	fmt.Println()
+}
+
+// dispatchLimitSuffix renders "/N" for a positive cap, "" when unlimited.
+func dispatchLimitSuffix(max int) string {
+	if max <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("/%d", max)
+}
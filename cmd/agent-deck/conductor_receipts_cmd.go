@@ -0,0 +1,194 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// receiptEntry is an unacknowledged dispatch in `conductor status` and
+// `conductor receipts`.
+type receiptEntry struct {
+	ID        int64  `json:"id"`
+	SessionID string `json:"session_id"`
+	Title     string `json:"title"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason,omitempty"`
+	Attempts  int    `json:"attempts"`
+	SentAt    string `json:"sent_at"`
+	Message   string `json:"message"`
+	Retried   bool   `json:"retried,omitempty"`
+}
+
+func newReceiptEntry(r *statedb.ReceiptRow, byID map[string]*session.Instance) receiptEntry {
+	e := receiptEntry{
+		ID:        r.ID,
+		SessionID: r.SessionID,
+		Title:     r.SessionID,
+		Status:    r.Status,
+		Reason:    r.Reason,
+		Attempts:  r.Attempts,
+		SentAt:    r.SentAt.UTC().Format(time.RFC3339),
+		Message:   r.Message,
+	}
+	if inst := byID[r.SessionID]; inst != nil {
+		e.Title = inst.Title
+	}
+	return e
+}
+
+// conductorReceiptState returns the unacknowledged dispatches of one
+// conductor's children. Pending receipts still inside their timeout are
+// not listed.
+func conductorReceiptState(conductorID string, rows []*statedb.ReceiptRow, instances []*session.Instance) []receiptEntry {
+	if conductorID == "" {
+		return nil
+	}
+	byID := instancesByID(instances)
+	var out []receiptEntry
+	for _, r := range rows {
+		if r.ParentID == conductorID && r.Status != session.ReceiptPending {
+			out = append(out, newReceiptEntry(r, byID))
+		}
+	}
+	return out
+}
+
+func instancesByID(instances []*session.Instance) map[string]*session.Instance {
+	byID := make(map[string]*session.Instance, len(instances))
+	for _, inst := range instances {
+		if inst != nil {
+			byID[inst.ID] = inst
+		}
+	}
+	return byID
+}
+
+func printConductorReceipts(entries []receiptEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	fmt.Printf("      unacknowledged: %d\n", len(entries))
+	for _, e := range entries {
+		fmt.Printf("        %s [%s] %s (attempt %d)\n", e.Title, e.Status, e.Reason, e.Attempts)
+		fmt.Printf("          %s\n", truncate(firstLine(e.Message), 80))
+	}
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// handleConductorReceipts lists, and with --retry re-delivers, prompts that
+// conductor children have not acted on.
+func handleConductorReceipts(profile string, args []string) {
+	fs := flag.NewFlagSet("conductor receipts", flag.ExitOnError)
+	retry := fs.Bool("retry", false, "Re-deliver unacknowledged prompts (up to [conductor.receipts] max_retries)")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck [-p profile] conductor receipts [options]")
+		fmt.Println()
+		fmt.Println("Show prompts delivered to conductor children that the child has not acted")
+		fmt.Println("on: still pending, unacknowledged after [conductor.receipts]")
+		fmt.Println("ack_timeout_seconds (default 120), or failed after max_retries (default 1).")
+		fmt.Println("A prompt is acknowledged when the child starts working after delivery.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck conductor receipts")
+		fmt.Println("  agent-deck -p work conductor receipts --retry --json")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+	settings := session.GetConductorSettings().Receipts
+
+	storage, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	db := storage.GetDB()
+	rows, err := session.RefreshDispatchReceipts(db, instances, settings, time.Now())
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load receipts: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	byID := instancesByID(instances)
+	entries := make([]receiptEntry, 0, len(rows))
+	var skipped []string
+	for _, r := range rows {
+		retried := false
+		if *retry && session.ReceiptRetryable(r, settings) {
+			if err := redeliverReceipt(r, byID[r.SessionID]); err != nil {
+				skipped = append(skipped, fmt.Sprintf("%s: %v", newReceiptEntry(r, byID).Title, err))
+			} else {
+				session.MarkReceiptRetried(r, time.Now())
+				if err := db.UpdateReceipt(r); err != nil {
+					out.Error(fmt.Sprintf("failed to save receipt: %v", err), ErrCodeInvalidOperation)
+					os.Exit(1)
+				}
+				retried = true
+			}
+		}
+		e := newReceiptEntry(r, byID)
+		e.Retried = retried
+		entries = append(entries, e)
+	}
+
+	for _, s := range skipped {
+		fmt.Fprintf(os.Stderr, "Warning: not retried: %s\n", s)
+	}
+	var b strings.Builder
+	if len(entries) == 0 {
+		b.WriteString("All conductor dispatches acknowledged.\n")
+	}
+	for _, e := range entries {
+		mark := ""
+		if e.Retried {
+			mark = "  → re-delivered"
+		}
+		fmt.Fprintf(&b, "%s [%s] %s (attempt %d)%s\n", e.Title, e.Status, e.Reason, e.Attempts, mark)
+		fmt.Fprintf(&b, "  %s\n", truncate(firstLine(e.Message), 80))
+	}
+	out.Print(b.String(), map[string]any{
+		"receipts": entries,
+		"settings": map[string]any{
+			"ack_timeout_seconds": int(settings.GetAckTimeout().Seconds()),
+			"max_retries":         settings.GetMaxRetries(),
+		},
+	})
+}
+
+// redeliverReceipt sends a receipt's prompt again. Only live sessions are
+// retried: a stopped session or a dead pane needs a restart first.
+func redeliverReceipt(r *statedb.ReceiptRow, inst *session.Instance) error {
+	if inst == nil {
+		return fmt.Errorf("session no longer exists")
+	}
+	tmuxSess := inst.GetTmuxSession()
+	if tmuxSess == nil || !inst.Exists() {
+		return fmt.Errorf("session is not running (restart it first)")
+	}
+	if tmuxSess.IsPaneDead() {
+		return fmt.Errorf("pane is dead (restart it first)")
+	}
+	_, err := executeSend(tmuxSess, inst.Tool, r.Message, true, noWaitSendTuning())
+	return err
+}
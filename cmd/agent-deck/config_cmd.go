@@ -0,0 +1,106 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleConfig dispatches the config subcommands.
+func handleConfig(args []string) {
+	if len(args) == 0 {
+		printConfigHelp()
+		return
+	}
+	switch args[0] {
+	case "validate":
+		handleConfigValidate(args[1:])
+	case "help", "-h", "--help":
+		printConfigHelp()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown config command '%s'\n", args[0])
+		printConfigHelp()
+		os.Exit(1)
+	}
+}
+
+func printConfigHelp() {
+	fmt.Println("Usage: agent-deck config <command> [options]")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  validate [path]    Check config.toml for syntax errors, unknown keys,")
+	fmt.Println("                     MCPs without a command or url and invalid regexps")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck config validate")
+	fmt.Println("  agent-deck config validate --json ~/.agent-deck/config.toml")
+}
+
+// handleConfigValidate prints every problem in config.toml with its line and
+// column, and exits 1 when there is one.
+func handleConfigValidate(args []string) {
+	fs := flag.NewFlagSet("config validate", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode (only the exit code)")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck config validate [options] [path]")
+		fmt.Println()
+		fmt.Println("Validates config.toml (default: the active config file).")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+
+	path := fs.Arg(0)
+	if path == "" {
+		var err error
+		if path, err = session.GetUserConfigPath(); err != nil {
+			out.Error(err.Error(), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			out.Success(fmt.Sprintf("No config file at %s; defaults are used", path), map[string]interface{}{
+				"success": true,
+				"path":    path,
+				"issues":  []session.ConfigIssue{},
+			})
+			return
+		}
+	}
+	issues, err := session.ValidateUserConfigFile(path)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to read %s: %v", path, err), ErrCodeNotFound)
+		os.Exit(2)
+	}
+
+	if len(issues) == 0 {
+		out.Success(fmt.Sprintf("%s is valid", path), map[string]interface{}{
+			"success": true,
+			"path":    path,
+			"issues":  []session.ConfigIssue{},
+		})
+		return
+	}
+	var b strings.Builder
+	for _, issue := range issues {
+		if issue.Line > 0 {
+			fmt.Fprintf(&b, "%s:%s\n", path, issue)
+		} else {
+			fmt.Fprintf(&b, "%s: %s\n", path, issue)
+		}
+	}
+	fmt.Fprintf(&b, "%s %d issue(s)\n", errorSymbol, len(issues))
+	out.Print(b.String(), map[string]interface{}{
+		"success": false,
+		"path":    path,
+		"issues":  issues,
+	})
+	os.Exit(1)
+}
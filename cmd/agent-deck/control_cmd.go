@@ -0,0 +1,453 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/control"
+	"github.com/asheshgoplani/agent-deck/internal/logging"
+	"github.com/asheshgoplani/agent-deck/internal/send"
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// controlMutateMu serializes the control methods that load, edit and save
+// the whole profile, so two concurrent calls cannot lose each other's write.
+var controlMutateMu sync.Mutex
+
+// controlSendReadyTimeout bounds how long `send` waits for the agent's
+// prompt, like `session send --timeout`.
+const controlSendReadyTimeout = 2 * time.Minute
+
+// startControlServer serves the JSON-RPC control socket for profile and
+// records it on this process's heartbeat row. Returns nil when the socket is
+// disabled or another live process already serves it.
+func startControlServer(profile string) *control.Server {
+	if !session.GetControlSettings().GetEnabled() {
+		return nil
+	}
+	log := logging.ForComponent(logging.CompControl)
+	path, err := session.ControlSocketPath(profile)
+	if err != nil {
+		log.Warn("control_path_failed", slog.String("error", err.Error()))
+		return nil
+	}
+	srv := control.NewServer(path, controlMethods(profile))
+	if err := srv.Start(); err != nil {
+		if errors.Is(err, control.ErrSocketInUse) {
+			log.Info("control_socket_in_use", slog.String("path", path))
+		} else {
+			log.Warn("control_start_failed", slog.String("error", err.Error()))
+		}
+		return nil
+	}
+	if db := statedb.GetGlobal(); db != nil {
+		_ = db.SetControlSocket(path)
+	}
+	return srv
+}
+
+// controlSession is one session in control results, a subset of
+// `list --json`.
+type controlSession struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Path      string    `json:"path"`
+	Group     string    `json:"group"`
+	Tool      string    `json:"tool"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	Archived  bool      `json:"archived"`
+}
+
+func controlSessionOf(inst *session.Instance) controlSession {
+	return controlSession{
+		ID:        inst.ID,
+		Title:     inst.Title,
+		Path:      inst.ProjectPath,
+		Group:     inst.GroupPath,
+		Tool:      inst.Tool,
+		Status:    StatusString(inst.GetStatusThreadSafe()),
+		CreatedAt: inst.CreatedAt,
+		Archived:  inst.IsArchived(),
+	}
+}
+
+// controlMethods returns the control socket's methods. Statuses come from
+// state.db, which the serving TUI keeps current, so reads never poll tmux.
+func controlMethods(profile string) map[string]control.HandlerFunc {
+	return map[string]control.HandlerFunc{
+		"list": func(params json.RawMessage) (any, error) {
+			return controlList(profile, params)
+		},
+		"status": func(json.RawMessage) (any, error) {
+			return controlStatus(profile)
+		},
+		"add": func(params json.RawMessage) (any, error) {
+			return controlAdd(profile, params)
+		},
+		"start": func(params json.RawMessage) (any, error) {
+			return controlStart(profile, params)
+		},
+		"send": func(params json.RawMessage) (any, error) {
+			return controlSend(profile, params)
+		},
+	}
+}
+
+// controlResolve maps ResolveSession's error codes onto JSON-RPC errors.
+func controlResolve(identifier string, instances []*session.Instance) (*session.Instance, error) {
+	inst, errMsg, errCode := ResolveSession(identifier, instances)
+	if inst != nil {
+		return inst, nil
+	}
+	switch errCode {
+	case ErrCodeNotFound:
+		return nil, control.Errorf(control.CodeNotFound, "%s", errMsg)
+	case ErrCodeAmbiguous:
+		return nil, control.Errorf(control.CodeAmbiguous, "%s", errMsg)
+	}
+	return nil, control.Errorf(control.CodeFailed, "%s", errMsg)
+}
+
+func controlList(profile string, params json.RawMessage) (any, error) {
+	var p struct {
+		Group    string `json:"group"`
+		Archived bool   `json:"archived"`
+	}
+	if err := control.DecodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	_, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]controlSession, 0, len(instances))
+	for _, inst := range session.FilterInstancesByArchive(instances, p.Archived) {
+		if p.Group != "" && inst.GroupPath != p.Group && !strings.HasPrefix(inst.GroupPath, p.Group+"/") {
+			continue
+		}
+		sessions = append(sessions, controlSessionOf(inst))
+	}
+	return map[string]any{"count": len(sessions), "sessions": sessions}, nil
+}
+
+func controlStatus(profile string) (any, error) {
+	_, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		return nil, err
+	}
+	c := session.CountStatuses(instances)
+	return map[string]int{
+		"waiting": c.Waiting,
+		"running": c.Running,
+		"idle":    c.Idle,
+		"error":   c.Error,
+		"stopped": c.Stopped,
+		"exited":  c.Exited,
+		"crashed": c.Crashed,
+		"total":   c.Total,
+	}, nil
+}
+
+// controlAdd mirrors `agent-deck add <path> [-t title] [-g group] [-c cmd]`,
+// plus an optional start like `launch`.
+func controlAdd(profile string, params json.RawMessage) (any, error) {
+	var p struct {
+		Path  string `json:"path"`
+		Title string `json:"title"`
+		Group string `json:"group"`
+		Cmd   string `json:"cmd"`
+		Start bool   `json:"start"`
+	}
+	if err := control.DecodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	// The server's working directory means nothing to the caller, so
+	// relative paths are refused rather than resolved against it.
+	raw := session.ExpandPath(strings.TrimSpace(p.Path))
+	if !filepath.IsAbs(raw) {
+		return nil, control.Errorf(control.CodeInvalidParams, "path must be absolute (or start with ~): %q", p.Path)
+	}
+	path := filepath.Clean(raw)
+	if info, err := os.Stat(path); err != nil || !info.IsDir() {
+		return nil, control.Errorf(control.CodeInvalidParams, "path is not a directory: %s", path)
+	}
+
+	controlMutateMu.Lock()
+	defer controlMutateMu.Unlock()
+
+	storage, instances, groups, err := loadSessionData(profile)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.Close()
+
+	title := strings.TrimSpace(p.Title)
+	if title == "" {
+		title = generateUniqueTitle(instances, filepath.Base(path), path)
+	} else if isDupe, existing := isDuplicateSession(instances, title, path); isDupe {
+		return map[string]any{"created": false, "session": controlSessionOf(existing)}, nil
+	}
+
+	var inst *session.Instance
+	if p.Group != "" {
+		inst = session.NewInstanceWithGroup(title, path, p.Group)
+	} else {
+		inst = session.NewInstance(title, path)
+	}
+	if cmd := strings.TrimSpace(p.Cmd); cmd != "" {
+		tool, resolved, wrapper, _ := resolveSessionCommand(cmd, "")
+		inst.Tool = firstNonEmpty(tool, detectTool(cmd))
+		inst.Command = resolved
+		if wrapper != "" {
+			inst.Wrapper = wrapper
+		}
+	}
+	for _, w := range session.ApplyConfiguredLoadout(inst) {
+		logging.ForComponent(logging.CompControl).Warn("control_add_loadout", slog.String("warning", w))
+	}
+
+	instances = append(instances, inst)
+	if err := saveSessionData(storage, instances, groups); err != nil {
+		return nil, fmt.Errorf("failed to save session: %w", err)
+	}
+	if p.Start {
+		if err := inst.Start(); err != nil {
+			return nil, fmt.Errorf("session created but failed to start: %w", err)
+		}
+		inst.PostStartSync(3 * time.Second)
+		if err := saveSessionData(storage, instances, groups); err != nil {
+			return nil, fmt.Errorf("failed to save session state: %w", err)
+		}
+	}
+	return map[string]any{"created": true, "session": controlSessionOf(inst)}, nil
+}
+
+// controlStart mirrors `agent-deck session start <id> [-m message]`,
+// including dependency start-up and the group concurrency queue.
+func controlStart(profile string, params json.RawMessage) (any, error) {
+	var p struct {
+		Session string `json:"session"`
+		Message string `json:"message"`
+	}
+	if err := control.DecodeParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	controlMutateMu.Lock()
+	defer controlMutateMu.Unlock()
+
+	storage, instances, groups, err := loadSessionData(profile)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.Close()
+	inst, err := controlResolve(p.Session, instances)
+	if err != nil {
+		return nil, err
+	}
+	if inst.Exists() {
+		return nil, control.Errorf(control.CodeFailed, "session '%s' is already running", inst.Title)
+	}
+
+	tree := session.NewGroupTreeWithGroups(instances, groups)
+	if max := session.GroupMaxConcurrent(tree, inst.GroupPath); session.ShouldQueue(instances, inst.GroupPath, max) {
+		inst.Status = session.StatusQueued
+		if err := saveSessionData(storage, instances, groups); err != nil {
+			return nil, fmt.Errorf("failed to save queued state: %w", err)
+		}
+		return map[string]any{"queued": true, "session": controlSessionOf(inst)}, nil
+	}
+
+	if _, err := startDependencies(inst, instances); err != nil {
+		_ = saveSessionData(storage, instances, groups)
+		return nil, err
+	}
+	if p.Message != "" {
+		err = inst.StartWithMessage(p.Message)
+	} else {
+		err = inst.Start()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+	inst.PostStartSync(3 * time.Second)
+	if err := saveSessionData(storage, instances, groups); err != nil {
+		return nil, fmt.Errorf("failed to save session state: %w", err)
+	}
+	return map[string]any{"queued": false, "session": controlSessionOf(inst)}, nil
+}
+
+// controlSend mirrors `agent-deck session send <id> <message> [--no-wait]`:
+// it waits for the agent's prompt, then types and verifies the message.
+func controlSend(profile string, params json.RawMessage) (any, error) {
+	var p struct {
+		Session string `json:"session"`
+		Message string `json:"message"`
+		NoWait  bool   `json:"no_wait"`
+	}
+	if err := control.DecodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	if p.Message == "" {
+		return nil, control.Errorf(control.CodeInvalidParams, "message is required")
+	}
+
+	storage, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		return nil, err
+	}
+	storage.Close()
+	inst, err := controlResolve(p.Session, instances)
+	if err != nil {
+		return nil, err
+	}
+	if !inst.Exists() {
+		return nil, control.Errorf(control.CodeFailed, "session '%s' is not running", inst.Title)
+	}
+	tmuxSess := inst.GetTmuxSession()
+	if tmuxSess == nil {
+		return nil, control.Errorf(control.CodeFailed, "could not determine tmux session")
+	}
+
+	if !p.NoWait {
+		if err := send.WaitForAgentReady(tmuxSess, inst.Tool, controlSendReadyTimeout, send.PromptGates{
+			ClaudeComposer: session.IsClaudeCompatible(inst.Tool),
+			CodexPrompt:    session.IsCodexCompatible(inst.Tool),
+		}); err != nil {
+			return nil, fmt.Errorf("timeout waiting for agent: %w", err)
+		}
+	}
+	sentAt := time.Now()
+	tun := defaultSendTuning()
+	if p.NoWait {
+		tun = noWaitSendTuning()
+	}
+	res, err := executeSend(tmuxSess, inst.Tool, p.Message, p.NoWait, tun)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send message: %w", err)
+	}
+	if db := statedb.GetGlobal(); db != nil {
+		_ = db.WriteLastSentAt(inst.ID, sentAt.Unix())
+	}
+	result := res.jsonFields()
+	result["session_id"] = inst.ID
+	result["session_title"] = inst.Title
+	return result, nil
+}
+
+// handleControl implements `agent-deck control`: locating the running
+// instance's control socket and making one-off calls against it.
+func handleControl(profile string, args []string) {
+	if len(args) == 0 || isHelpArg(args[0]) {
+		printControlHelp()
+		return
+	}
+	switch args[0] {
+	case "path":
+		handleControlPath(profile, args[1:])
+	case "call":
+		handleControlCall(profile, args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown control command: %s\n\n", args[0])
+		printControlHelp()
+		os.Exit(1)
+	}
+}
+
+func printControlHelp() {
+	fmt.Println("Usage: agent-deck control <command>")
+	fmt.Println()
+	fmt.Println("A running TUI serves a JSON-RPC 2.0 control socket (newline-delimited)")
+	fmt.Println("with the methods list, status, add, start and send.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  path                         Print the control socket of the running instance")
+	fmt.Println("  call <method> [params-json]  Call a method and print its result")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck control call status")
+	fmt.Println(`  agent-deck control call send '{"session":"api","message":"run the tests"}'`)
+	fmt.Println(`  echo '{"jsonrpc":"2.0","id":1,"method":"list"}' | nc -U "$(agent-deck control path)"`)
+}
+
+// controlSocketOrExit finds the live socket for profile, exiting with a
+// hint when no instance serves one.
+func controlSocketOrExit(out *CLIOutput, profile string) string {
+	path, err := session.FindControlSocket(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to read state.db: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if path == "" {
+		out.Error(fmt.Sprintf("no running agent-deck serves a control socket for profile '%s' (start the TUI)",
+			session.GetEffectiveProfile(profile)), ErrCodeNotFound)
+		os.Exit(2)
+	}
+	return path
+}
+
+func handleControlPath(profile string, args []string) {
+	fs := flag.NewFlagSet("control path", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	path := controlSocketOrExit(out, profile)
+	out.Print(path+"\n", map[string]any{"success": true, "path": path})
+}
+
+func handleControlCall(profile string, args []string) {
+	fs := flag.NewFlagSet("control call", flag.ExitOnError)
+	socket := fs.String("socket", "", "Socket path (default: the running instance's)")
+	timeout := fs.Duration("timeout", 5*time.Minute, "Max time to wait for the result")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck control call <method> [params-json] [options]")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(true, false)
+	method := fs.Arg(0)
+	if method == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+	var params json.RawMessage
+	if raw := fs.Arg(1); raw != "" {
+		if !json.Valid([]byte(raw)) {
+			out.Error("params must be valid JSON", ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		params = json.RawMessage(raw)
+	}
+
+	path := *socket
+	if path == "" {
+		path = controlSocketOrExit(out, profile)
+	}
+	var result json.RawMessage
+	if err := control.Call(path, method, params, &result, *timeout); err != nil {
+		var rpcErr *control.Error
+		if errors.As(err, &rpcErr) && rpcErr.Code == control.CodeNotFound {
+			out.Error(rpcErr.Message, ErrCodeNotFound)
+			os.Exit(2)
+		}
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	fmt.Println(string(result))
+}
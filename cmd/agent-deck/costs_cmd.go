@@ -6,12 +6,15 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/asheshgoplani/agent-deck/internal/costs"
 	"github.com/asheshgoplani/agent-deck/internal/session"
 )
 
-const costsUsage = "Usage: agent-deck costs <sync|summary|recompute>"
+const costsUsage = "Usage: agent-deck costs <sync|summary|report|recompute>"
 
 func handleCosts(profile string, args []string) {
 	if len(args) == 0 {
@@ -24,6 +27,8 @@ func handleCosts(profile string, args []string) {
 		handleCostsSync(profile)
 	case "summary":
 		handleCostsSummary(profile, args[1:])
+	case "report":
+		handleCostsReport(profile, args[1:])
 	case "recompute":
 		handleCostsRecompute(profile, args[1:])
 	default:
@@ -77,19 +82,7 @@ func handleCostsSync(profile string) {
 		os.Exit(1)
 	}
 
-	var syncSessions []costs.SyncSession
-	for _, inst := range instances {
-		if inst.Tool != "claude" || inst.ClaudeSessionID == "" {
-			continue
-		}
-		syncSessions = append(syncSessions, costs.SyncSession{
-			InstanceID:      inst.ID,
-			ClaudeSessionID: inst.ClaudeSessionID,
-			ProjectPath:     inst.ProjectPath,
-			Tool:            inst.Tool,
-		})
-	}
-
+	syncSessions := costSyncSessions(instances)
 	if len(syncSessions) == 0 {
 		fmt.Println("No Claude sessions found to sync.")
 		return
@@ -110,6 +103,24 @@ func handleCostsSync(profile string) {
 	}
 }
 
+// costSyncSessions returns the transcript locations of the Claude sessions
+// among instances.
+func costSyncSessions(instances []*session.Instance) []costs.SyncSession {
+	var syncSessions []costs.SyncSession
+	for _, inst := range instances {
+		if inst.Tool != "claude" || inst.ClaudeSessionID == "" {
+			continue
+		}
+		syncSessions = append(syncSessions, costs.SyncSession{
+			InstanceID:      inst.ID,
+			ClaudeSessionID: inst.ClaudeSessionID,
+			ProjectPath:     inst.ProjectPath,
+			Tool:            inst.Tool,
+		})
+	}
+	return syncSessions
+}
+
 func handleCostsSummary(profile string, args []string) {
 	// #1101: --json output so a remote agent-deck can be queried over SSH and
 	// its cost totals merged into the local TUI's status-line cost segment.
@@ -222,3 +233,175 @@ func handleCostsRecompute(profile string, args []string) {
 		fmt.Println("\nRe-run without --dry-run to apply changes.")
 	}
 }
+
+// handleCostsReport prints per-session token usage and estimated cost over a
+// window. Transcripts are synced first so the report includes turns the Stop
+// hook hasn't recorded (or that ran while agent-deck wasn't watching).
+func handleCostsReport(profile string, args []string) {
+	fs := flag.NewFlagSet("costs report", flag.ExitOnError)
+	sinceFlag := fs.String("since", "7d", "Report window: days (7d), a duration (12h), or \"all\"")
+	limit := fs.Int("limit", 0, "Show only the N most expensive sessions (0 = all)")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck costs report [--since 7d] [--limit N] [--json]")
+		fmt.Println()
+		fmt.Println("Token usage and estimated cost per session, most expensive first.")
+		fmt.Println()
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	since, err := parseCostSince(*sinceFlag, time.Now())
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	costStore, storage := openCostStore(profile)
+	defer storage.Close()
+	if instances, err := storage.Load(); err == nil {
+		costs.SyncFromTranscripts(costStore, newPricerFromConfig(), costSyncSessions(instances))
+	}
+
+	rows, err := costStore.SessionCostsSince(since)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to read costs: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	var total costs.SessionCost
+	for _, r := range rows {
+		total.CostMicrodollars += r.CostMicrodollars
+		total.EventCount += r.EventCount
+		total.InputTokens += r.InputTokens
+		total.OutputTokens += r.OutputTokens
+		total.CacheReadTokens += r.CacheReadTokens
+		total.CacheWriteTokens += r.CacheWriteTokens
+	}
+	if *limit > 0 && len(rows) > *limit {
+		rows = rows[:*limit]
+	}
+
+	items := make([]map[string]interface{}, 0, len(rows))
+	for _, r := range rows {
+		m := sessionCostJSON(r)
+		m["session_id"] = r.SessionID
+		m["session"] = r.SessionTitle
+		m["group"] = r.Group
+		items = append(items, m)
+	}
+	jsonData := map[string]interface{}{
+		"success":  true,
+		"since":    *sinceFlag,
+		"sessions": items,
+		"total":    sessionCostJSON(total),
+	}
+	if !since.IsZero() {
+		jsonData["since_time"] = since
+	}
+	out.Print(renderCostReport(rows, total, *sinceFlag), jsonData)
+}
+
+// parseCostSince turns a --since value into the window start: "7d" counts
+// days, anything else is a Go duration; "all" (or "") means no limit.
+func parseCostSince(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "all" {
+		return time.Time{}, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return time.Time{}, fmt.Errorf("invalid --since %q: want e.g. 7d, 12h or all", s)
+		}
+		return now.AddDate(0, 0, -n), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return time.Time{}, fmt.Errorf("invalid --since %q: want e.g. 7d, 12h or all", s)
+	}
+	return now.Add(-d), nil
+}
+
+// renderCostReport formats the report as a table with a total row.
+func renderCostReport(rows []costs.SessionCost, total costs.SessionCost, since string) string {
+	window := "in the last " + since
+	if since == "" || since == "all" {
+		window = "overall"
+	}
+	if len(rows) == 0 {
+		return fmt.Sprintf("No usage recorded %s.\n", window)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Usage %s:\n\n", window)
+	fmt.Fprintf(&b, "  %-30s %9s %8s %8s %10s %10s\n", "SESSION", "COST", "INPUT", "OUTPUT", "CACHE RD", "CACHE WR")
+	line := func(name string, c costs.SessionCost) {
+		fmt.Fprintf(&b, "  %-30s %9s %8s %8s %10s %10s\n", truncate(name, 30), costs.FormatUSD(c.CostMicrodollars),
+			costs.FormatTokens(c.InputTokens), costs.FormatTokens(c.OutputTokens),
+			costs.FormatTokens(c.CacheReadTokens), costs.FormatTokens(c.CacheWriteTokens))
+	}
+	for _, r := range rows {
+		title := r.SessionTitle
+		if title == "" {
+			title = r.SessionID
+		}
+		line(title, r)
+	}
+	b.WriteString("\n")
+	line("Total", total)
+	return b.String()
+}
+
+// sessionCost syncs inst's Claude transcript and returns its recorded totals.
+// ok is false when the session has no usage on record.
+func sessionCost(profile string, inst *session.Instance) (costs.SessionCost, bool) {
+	storage, err := session.NewStorageWithProfile(profile)
+	if err != nil {
+		return costs.SessionCost{}, false
+	}
+	defer storage.Close()
+	db := storage.GetDB()
+	if db == nil {
+		return costs.SessionCost{}, false
+	}
+	costStore := costs.NewStore(db.DB())
+	if sync := costSyncSessions([]*session.Instance{inst}); len(sync) > 0 {
+		costs.SyncFromTranscripts(costStore, newPricerFromConfig(), sync)
+	}
+	sum, err := costStore.TotalBySession(inst.ID)
+	if err != nil || sum.EventCount == 0 {
+		return costs.SessionCost{}, false
+	}
+	return costs.SessionCost{
+		SessionID:        inst.ID,
+		CostMicrodollars: sum.TotalCostMicrodollars,
+		EventCount:       sum.EventCount,
+		InputTokens:      sum.TotalInputTokens,
+		OutputTokens:     sum.TotalOutputTokens,
+		CacheReadTokens:  sum.TotalCacheReadTokens,
+		CacheWriteTokens: sum.TotalCacheWriteTokens,
+	}, true
+}
+
+// sessionCostJSON is the JSON shape of a session's usage, shared by
+// `session show` and `costs report`.
+func sessionCostJSON(c costs.SessionCost) map[string]interface{} {
+	return map[string]interface{}{
+		"cost_usd":           float64(c.CostMicrodollars) / 1_000_000,
+		"cost_microdollars":  c.CostMicrodollars,
+		"input_tokens":       c.InputTokens,
+		"output_tokens":      c.OutputTokens,
+		"cache_read_tokens":  c.CacheReadTokens,
+		"cache_write_tokens": c.CacheWriteTokens,
+		"events":             c.EventCount,
+	}
+}
+
+// formatSessionCost is the one-line summary used by `session show`.
+func formatSessionCost(c costs.SessionCost) string {
+	return fmt.Sprintf("%s (in %s, out %s, cache read %s, cache write %s)",
+		costs.FormatUSD(c.CostMicrodollars),
+		costs.FormatTokens(c.InputTokens), costs.FormatTokens(c.OutputTokens),
+		costs.FormatTokens(c.CacheReadTokens), costs.FormatTokens(c.CacheWriteTokens))
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/costs"
+)
+
+func TestParseCostSince(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		in   string
+		want time.Time
+	}{
+		{"7d", now.AddDate(0, 0, -7)},
+		{"12h", now.Add(-12 * time.Hour)},
+		{"all", time.Time{}},
+		{"", time.Time{}},
+	}
+	for _, c := range cases {
+		got, err := parseCostSince(c.in, now)
+		if err != nil {
+			t.Fatalf("parseCostSince(%q): %v", c.in, err)
+		}
+		if !got.Equal(c.want) {
+			t.Errorf("parseCostSince(%q) = %v, want %v", c.in, got, c.want)
+		}
+	}
+	for _, bad := range []string{"0d", "xd", "-1h", "week"} {
+		if _, err := parseCostSince(bad, now); err == nil {
+			t.Errorf("parseCostSince(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestRenderCostReport(t *testing.T) {
+	if got := renderCostReport(nil, costs.SessionCost{}, "7d"); !strings.Contains(got, "No usage") {
+		t.Errorf("empty report = %q", got)
+	}
+	rows := []costs.SessionCost{
+		{SessionID: "a", SessionTitle: "api", CostMicrodollars: 1_250_000, InputTokens: 12_300, OutputTokens: 800},
+		{SessionID: "b", CostMicrodollars: 50_000, CacheReadTokens: 2_000_000},
+	}
+	got := renderCostReport(rows, costs.SessionCost{CostMicrodollars: 1_300_000}, "7d")
+	for _, want := range []string{"api", "$1.25", "12.3K", "b ", "2.0M", "Total", "$1.30"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("report missing %q:\n%s", want, got)
+		}
+	}
+}
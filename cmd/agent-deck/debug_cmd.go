@@ -0,0 +1,253 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// handleDebug dispatches `agent-deck debug <subcommand>`.
+func handleDebug(profile string, args []string) {
+	if len(args) == 0 || isHelpArg(args[0]) {
+		printDebugHelp()
+		if len(args) == 0 {
+			os.Exit(1)
+		}
+		return
+	}
+	switch args[0] {
+	case "status-history":
+		handleDebugStatusHistory(profile, args[1:])
+	case "dump":
+		handleDebugDump()
+	case "classify":
+		handleDebugClassify(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown debug command: %s\n\n", args[0])
+		printDebugHelp()
+		os.Exit(1)
+	}
+}
+
+func printDebugHelp() {
+	fmt.Println("Usage: agent-deck debug <command>")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  status-history <id>   Dump the recorded status decisions of a session")
+	fmt.Println("  dump                  Dump the debug ring buffer to a file (same as debug-dump)")
+	fmt.Println("  classify <file|->     Show the status a recorded pane capture is detected as")
+}
+
+// handleDebugClassify replays a pane capture through the status engine, the
+// same path the testdata/status fixtures take, so a misdetected screen can be
+// reproduced and contributed as a fixture.
+func handleDebugClassify(args []string) {
+	fs := flag.NewFlagSet("debug classify", flag.ExitOnError)
+	tool := fs.String("tool", "", "Tool that drew the pane (claude, codex, gemini, ...; default: detect from content)")
+	expect := fs.String("expect", "", "Status the pane should have (active, waiting, idle, error, starting); exit 1 if it differs")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck debug classify <file|-> [options]")
+		fmt.Println()
+		fmt.Println("Run a saved pane capture through status detection, as the first poll of a")
+		fmt.Println("session would, and print the verdict. No tmux server is needed, so the")
+		fmt.Println("answer is the same on every machine. When detection misfires, capture the")
+		fmt.Println("pane, check that it reproduces with --expect, and contribute it as")
+		fmt.Println("internal/tmux/testdata/status/<tool>/<version>/<name>.<status>.txt.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  tmux capture-pane -p -t <tmux-session> > pane.txt")
+		fmt.Println("  agent-deck debug classify pane.txt --tool claude --expect waiting")
+		fmt.Println("  tmux capture-pane -p -t <tmux-session> | agent-deck debug classify - --json")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	path := fs.Arg(0)
+	if path == "" {
+		out.Error("a capture file (or - for stdin) is required", ErrCodeInvalidOperation)
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *expect != "" && !tmux.IsSnapshotStatus(*expect) {
+		out.Error(fmt.Sprintf("invalid --expect %q: use active, waiting, idle, error or starting", *expect), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to read capture: %v", err), ErrCodeNotFound)
+		os.Exit(2)
+	}
+
+	v := tmux.ReplaySnapshot(strings.ToLower(strings.TrimSpace(*tool)), string(data))
+	var b strings.Builder
+	fmt.Fprintf(&b, "Status:   %s\n", v.Status)
+	fmt.Fprintf(&b, "Tool:     %s\n", v.Tool)
+	if v.Substate != "" {
+		fmt.Fprintf(&b, "Substate: %s\n", v.Substate)
+	}
+	if !v.Matched {
+		b.WriteString("No busy, prompt or error signal found; this is the first-poll default.\n")
+	}
+	mismatch := *expect != "" && *expect != v.Status
+	if mismatch {
+		fmt.Fprintf(&b, "Expected %s: the capture reproduces the misdetection.\n", *expect)
+	}
+	out.Print(b.String(), map[string]interface{}{
+		"success":  !mismatch,
+		"status":   v.Status,
+		"tool":     v.Tool,
+		"substate": v.Substate,
+		"matched":  v.Matched,
+		"expected": *expect,
+	})
+	if mismatch {
+		os.Exit(1)
+	}
+}
+
+// handleDebugStatusHistory prints the status history ring of a session: each
+// status change with the hook state, tmux verdict and pane tail behind it.
+// Recording is opt-in ([status_history] enabled = true).
+func handleDebugStatusHistory(profile string, args []string) {
+	fs := flag.NewFlagSet("debug status-history", flag.ExitOnError)
+	limit := fs.Int("limit", 0, "Only show the newest N samples (0 = all)")
+	brief := fs.Bool("brief", false, "Omit pane captures")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck debug status-history <id|title> [options]")
+		fmt.Println()
+		fmt.Println("Dump the recorded status decisions of a session, oldest first: the status")
+		fmt.Println("agent-deck showed, the hook and tmux signals behind it, and the redacted")
+		fmt.Println("tail of the pane each time it changed. Attach the output to reports of a")
+		fmt.Println("wrong status. Recording is off by default; enable it in config.toml:")
+		fmt.Println()
+		fmt.Println("  [status_history]")
+		fmt.Println("  enabled = true")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck debug status-history my-project --limit 20")
+		fmt.Println("  agent-deck debug status-history my-project --json > status.json")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	identifier := fs.Arg(0)
+	if identifier == "" {
+		out.Error("session ID or title is required", ErrCodeInvalidOperation)
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	_, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load sessions: %v", err), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	id, title := identifier, identifier
+	inst, errMsg, errCode := ResolveSession(identifier, instances)
+	if inst != nil {
+		id, title = inst.ID, inst.Title
+	} else if errCode == ErrCodeAmbiguous {
+		out.Error(errMsg, errCode)
+		os.Exit(1)
+	}
+
+	samples, err := session.ReadStatusHistory(id)
+	if errors.Is(err, os.ErrNotExist) {
+		hint := ""
+		if !session.GetStatusHistorySettings().Enabled {
+			hint = " (recording is off: set [status_history] enabled = true in config.toml and reproduce)"
+		}
+		out.Error(fmt.Sprintf("no status history for '%s'%s", title, hint), ErrCodeNotFound)
+		os.Exit(2)
+	}
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to read status history: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if *limit > 0 && len(samples) > *limit {
+		samples = samples[len(samples)-*limit:]
+	}
+	if *brief {
+		for i := range samples {
+			samples[i].Capture = nil
+		}
+	}
+
+	out.Print(renderStatusHistory(title, samples), map[string]interface{}{
+		"success": true,
+		"id":      id,
+		"title":   title,
+		"samples": samples,
+	})
+}
+
+// renderStatusHistory prints one block per sample: a decision line, a signal
+// line, and the pane tail when the sample carries one.
+func renderStatusHistory(title string, samples []session.StatusSample) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Status history of %s (%d samples, oldest first)\n", title, len(samples))
+	for _, s := range samples {
+		b.WriteString("\n")
+		change := s.Status
+		if s.Previous != "" && s.Previous != s.Status {
+			change = s.Previous + " → " + s.Status
+		}
+		fmt.Fprintf(&b, "%s  %s\n", s.Timestamp.Local().Format(time.DateTime), change)
+
+		var signals []string
+		if s.Hook != "" {
+			hook := "hook=" + s.Hook
+			if s.HookAge != "" {
+				hook += " " + s.HookAge + " ago"
+			}
+			signals = append(signals, hook)
+		}
+		if s.Tmux != "" {
+			signals = append(signals, "tmux="+s.Tmux)
+		}
+		if s.Substate != "" {
+			signals = append(signals, "substate="+s.Substate)
+		}
+		if s.TitleState != "" {
+			signals = append(signals, "title="+s.TitleState)
+		}
+		signals = append(signals, fmt.Sprintf("prompt=%t", s.Prompt), fmt.Sprintf("ack=%t", s.Acknowledged))
+		if s.Hash != "" {
+			signals = append(signals, "pane="+s.Hash)
+		}
+		fmt.Fprintf(&b, "    %s\n", strings.Join(signals, "  "))
+		for _, line := range s.Capture {
+			fmt.Fprintf(&b, "    │ %s\n", line)
+		}
+	}
+	return b.String()
+}
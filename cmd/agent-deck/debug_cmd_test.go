@@ -0,0 +1,30 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+func TestRenderStatusHistory(t *testing.T) {
+	out := renderStatusHistory("api", []session.StatusSample{
+		{Timestamp: time.Now(), Status: "idle", Previous: "running", Hook: "waiting (Stop)", HookAge: "2s", Tmux: "active", TitleState: "working", Hash: "abc123", Capture: []string{"✻ Working… (esc to interrupt)"}},
+		{Timestamp: time.Now(), Status: "idle", Previous: "idle", Tmux: "idle", Acknowledged: true},
+	})
+	for _, want := range []string{
+		"Status history of api (2 samples, oldest first)",
+		"running → idle",
+		"hook=waiting (Stop) 2s ago  tmux=active  title=working  prompt=false  ack=false  pane=abc123",
+		"    │ ✻ Working… (esc to interrupt)",
+		"tmux=idle  prompt=false  ack=true",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "idle → idle") {
+		t.Errorf("an unchanged status should not render as a transition:\n%s", out)
+	}
+}
@@ -0,0 +1,475 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// Doctor check results, worst last.
+const (
+	doctorOK   = "ok"
+	doctorSkip = "skip"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+)
+
+// doctorProbeTimeout bounds each tmux probe so a wedged server cannot hang
+// the doctor.
+const doctorProbeTimeout = 3 * time.Second
+
+// doctorCheck is one finding of `agent-deck doctor`. Fix is the command or
+// config change that resolves a warn or fail.
+type doctorCheck struct {
+	Section string `json:"section"`
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Detail  string `json:"detail"`
+	Fix     string `json:"fix,omitempty"`
+}
+
+// handleDoctor checks the environment agent-deck depends on and prints a
+// fix for every problem. It exits 1 when any check fails.
+func handleDoctor(profile string, args []string) {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output the report as JSON")
+	quiet := fs.Bool("q", false, "Only print warnings and failures")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck doctor [options]")
+		fmt.Println()
+		fmt.Println("Check tmux (version, allow-passthrough, control mode), the terminal,")
+		fmt.Println("config.toml, the profile's state.db, the status hooks of each tool and the")
+		fmt.Println("conductor daemons, and print a fix for every problem found.")
+		fmt.Println("Exits 1 when a check fails.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	var checks []doctorCheck
+	checks = append(checks, doctorTmuxChecks()...)
+	checks = append(checks, doctorTerminalChecks(tmux.GetTerminalInfo(), os.Getenv)...)
+	checks = append(checks, doctorConfigChecks()...)
+	checks = append(checks, doctorStateDBChecks(profile)...)
+	checks = append(checks, doctorHookChecks(profile)...)
+	checks = append(checks, doctorDaemonChecks()...)
+
+	counts := doctorCounts(checks)
+	if *jsonOutput {
+		out := NewCLIOutput(true, false)
+		out.Print("", map[string]interface{}{
+			"success": counts[doctorFail] == 0,
+			"profile": session.GetEffectiveProfile(profile),
+			"checks":  checks,
+			"summary": counts,
+		})
+	} else {
+		fmt.Print(renderDoctorReport(checks, *quiet))
+	}
+	if counts[doctorFail] > 0 {
+		os.Exit(1)
+	}
+}
+
+// doctorCounts tallies checks by status.
+func doctorCounts(checks []doctorCheck) map[string]int {
+	counts := map[string]int{doctorOK: 0, doctorSkip: 0, doctorWarn: 0, doctorFail: 0}
+	for _, c := range checks {
+		counts[c.Status]++
+	}
+	return counts
+}
+
+// renderDoctorReport prints the checks grouped by section, each problem
+// followed by its fix. quiet leaves out passing and skipped checks.
+func renderDoctorReport(checks []doctorCheck, quiet bool) string {
+	var b strings.Builder
+	section := ""
+	for _, c := range checks {
+		if quiet && (c.Status == doctorOK || c.Status == doctorSkip) {
+			continue
+		}
+		if c.Section != section {
+			if section != "" {
+				b.WriteString("\n")
+			}
+			section = c.Section
+			b.WriteString(section + "\n")
+		}
+		fmt.Fprintf(&b, "  %s %-18s %s\n", doctorSymbol(c.Status), c.Name, c.Detail)
+		if c.Fix != "" && (c.Status == doctorWarn || c.Status == doctorFail) {
+			fmt.Fprintf(&b, "    → %s\n", c.Fix)
+		}
+	}
+	counts := doctorCounts(checks)
+	if section != "" {
+		b.WriteString("\n")
+	}
+	switch {
+	case counts[doctorFail] > 0:
+		fmt.Fprintf(&b, "%d problem(s), %d warning(s).\n", counts[doctorFail], counts[doctorWarn])
+	case counts[doctorWarn] > 0:
+		fmt.Fprintf(&b, "No problems, %d warning(s).\n", counts[doctorWarn])
+	default:
+		b.WriteString("Everything looks good.\n")
+	}
+	return b.String()
+}
+
+func doctorSymbol(status string) string {
+	switch status {
+	case doctorOK:
+		return successSymbol
+	case doctorWarn:
+		return "!"
+	case doctorFail:
+		return errorSymbol
+	default:
+		return "-"
+	}
+}
+
+// doctorTmuxChecks checks the tmux binary, its version, the passthrough
+// option agent-deck sets on every session, and control mode.
+func doctorTmuxChecks() []doctorCheck {
+	const section = "tmux"
+	ver, err := tmux.Version()
+	if err != nil {
+		return []doctorCheck{{section, "tmux", doctorFail, err.Error(),
+			"install tmux 3.2 or newer (brew install tmux, apt install tmux)"}}
+	}
+	checks := []doctorCheck{doctorTmuxVersionCheck(ver, runtime.GOOS)}
+
+	var override string
+	if cfg, _ := session.LoadUserConfig(); cfg != nil {
+		override = cfg.Tmux.Options["allow-passthrough"]
+	}
+	checks = append(checks, doctorPassthroughCheck(ver, override))
+
+	socket := tmux.DefaultSocketName()
+	ctx, cancel := context.WithTimeout(context.Background(), doctorProbeTimeout)
+	defer cancel()
+	serverOut, serverErr := tmux.ExecContext(ctx, socket, "list-sessions", "-F", "#{session_name}").CombinedOutput()
+	server := "default socket"
+	if socket != "" {
+		server = "socket " + socket
+	}
+	switch {
+	case serverErr == nil:
+		n := len(strings.Fields(string(serverOut)))
+		checks = append(checks, doctorCheck{section, "server", doctorOK, fmt.Sprintf("running on the %s, %d session(s)", server, n), ""})
+	case tmuxNoServer(string(serverOut)):
+		checks = append(checks, doctorCheck{section, "server", doctorOK, fmt.Sprintf("not running on the %s (starts with the first session)", server), ""})
+		checks = append(checks, doctorCheck{section, "control mode", doctorSkip, "no tmux server to probe", ""})
+		return checks
+	default:
+		checks = append(checks, doctorCheck{section, "server", doctorFail,
+			fmt.Sprintf("the %s does not answer: %s", server, firstNonEmpty(strings.TrimSpace(string(serverOut)), serverErr.Error())),
+			"restart the tmux server (tmux kill-server ends every session on it)"})
+		return checks
+	}
+
+	// Control mode: the TUI streams pane output and sends keys through
+	// `tmux -C` pipes. Stdin is empty, so the client runs one command and exits.
+	ctx2, cancel2 := context.WithTimeout(context.Background(), doctorProbeTimeout)
+	defer cancel2()
+	cmd := tmux.ExecContext(ctx2, socket, "-C", "list-sessions")
+	cmd.Stdin = strings.NewReader("")
+	ctlOut, ctlErr := cmd.CombinedOutput()
+	if ctlErr == nil && strings.Contains(string(ctlOut), "%begin") {
+		checks = append(checks, doctorCheck{section, "control mode", doctorOK, "tmux -C answers", ""})
+	} else {
+		detail := "tmux -C gave no %begin reply"
+		if ctlErr != nil {
+			detail = "tmux -C failed: " + ctlErr.Error()
+		}
+		checks = append(checks, doctorCheck{section, "control mode", doctorWarn, detail + "; the TUI falls back to slower tmux subprocesses",
+			"upgrade tmux, and keep [tmux] control_mode_only = false until control mode works"})
+	}
+	return checks
+}
+
+// doctorTmuxVersionCheck grades the tmux version: 3.2 added the
+// allow-passthrough and extended-keys options agent-deck sets on sessions.
+func doctorTmuxVersionCheck(ver, goos string) doctorCheck {
+	switch {
+	case !tmux.VersionAtLeast(ver, 3, 2):
+		return doctorCheck{"tmux", "version", doctorWarn,
+			fmt.Sprintf("tmux %s is older than 3.2: OSC 8 links, OSC 52 clipboard and Shift+Enter will not reach agents", ver),
+			"upgrade tmux to 3.2 or newer"}
+	case goos == "darwin" && tmux.IsVulnerableVersion(ver):
+		return doctorCheck{"tmux", "version", doctorWarn,
+			fmt.Sprintf("tmux %s has an unfixed control-mode crash (tmux #4980)", ver),
+			"brew upgrade tmux once a patched release is out, or install tmux from master"}
+	default:
+		return doctorCheck{"tmux", "version", doctorOK, "tmux " + ver, ""}
+	}
+}
+
+// doctorPassthroughCheck reports whether sessions get allow-passthrough,
+// which OSC 8 links, clipboard copies and desktop notifications need.
+// override is the [tmux] options value for it, if any.
+func doctorPassthroughCheck(ver, override string) doctorCheck {
+	switch {
+	case override == "off":
+		return doctorCheck{"tmux", "allow-passthrough", doctorWarn,
+			"[tmux] options turns it off: links, clipboard and notifications from agents are dropped",
+			`remove "allow-passthrough" from [tmux] options in config.toml`}
+	case !tmux.VersionAtLeast(ver, 3, 2):
+		return doctorCheck{"tmux", "allow-passthrough", doctorWarn, "not supported by tmux " + ver, "upgrade tmux to 3.2 or newer"}
+	case override != "":
+		return doctorCheck{"tmux", "allow-passthrough", doctorOK, override + " ([tmux] options)", ""}
+	default:
+		return doctorCheck{"tmux", "allow-passthrough", doctorOK, "on for every session", ""}
+	}
+}
+
+func tmuxNoServer(output string) bool {
+	return strings.Contains(output, "no server running") || strings.Contains(output, "error connecting to")
+}
+
+// doctorTerminalChecks checks the terminal the CLI runs in.
+func doctorTerminalChecks(info tmux.TerminalInfo, getenv func(string) string) []doctorCheck {
+	const section = "terminal"
+	term := getenv("TERM")
+	var checks []doctorCheck
+	switch {
+	case getenv("ATUIN_PTY_PROXY_ACTIVE") != "":
+		checks = append(checks, doctorCheck{section, "terminal", doctorFail,
+			"running under atuin pty-proxy, which breaks the TUI's alternate screen and mouse",
+			"use `atuin init zsh` (or bash/fish) instead of `atuin pty-proxy init`"})
+	case term == "" || term == "dumb":
+		checks = append(checks, doctorCheck{section, "terminal", doctorWarn,
+			fmt.Sprintf("TERM=%q cannot draw the TUI", term), "run agent-deck from a terminal emulator, or export TERM=xterm-256color"})
+	default:
+		checks = append(checks, doctorCheck{section, "terminal", doctorOK, fmt.Sprintf("%s (TERM=%s)", info.Name, term), ""})
+	}
+	if info.SupportsTrueColor {
+		checks = append(checks, doctorCheck{section, "true color", doctorOK, "24-bit color", ""})
+	} else {
+		checks = append(checks, doctorCheck{section, "true color", doctorWarn, "not detected: themes fall back to 256 colors",
+			"export COLORTERM=truecolor if your terminal supports it"})
+	}
+	capability := func(name string, ok bool, feature string) doctorCheck {
+		if ok {
+			return doctorCheck{section, name, doctorOK, "supported", ""}
+		}
+		return doctorCheck{section, name, doctorSkip, "not known to work in " + info.Name + ": " + feature, ""}
+	}
+	checks = append(checks,
+		capability("OSC 8 links", info.SupportsOSC8, "file paths are not clickable"),
+		capability("OSC 52 clipboard", info.SupportsOSC52, "copying over SSH needs a local clipboard tool"))
+	return checks
+}
+
+// doctorConfigChecks checks that config.toml parses and validates.
+func doctorConfigChecks() []doctorCheck {
+	const section = "config"
+	path, err := session.GetUserConfigPath()
+	if err != nil {
+		return []doctorCheck{{section, "config.toml", doctorFail, err.Error(), "check that $HOME is set"}}
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return []doctorCheck{{section, "config.toml", doctorOK, "not created yet, using defaults", ""}}
+	}
+	if _, err := session.LoadUserConfig(); err != nil {
+		return []doctorCheck{{section, "config.toml", doctorFail, err.Error() + " (the whole file is ignored)",
+			"fix the syntax error in " + path}}
+	}
+	if issues := session.UserConfigIssues(); len(issues) > 0 {
+		return []doctorCheck{{section, "config.toml", doctorWarn,
+			fmt.Sprintf("%d issue(s), first: %s", len(issues), issues[0]), "agent-deck config validate"}}
+	}
+	return []doctorCheck{{section, "config.toml", doctorOK, path, ""}}
+}
+
+// doctorStateDBChecks checks the profile's state.db without migrating it.
+func doctorStateDBChecks(profile string) []doctorCheck {
+	const section = "state"
+	path, err := session.GetDBPathForProfile(session.GetEffectiveProfile(profile))
+	if err != nil {
+		return []doctorCheck{{section, "state.db", doctorFail, err.Error(), ""}}
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return []doctorCheck{{section, "state.db", doctorOK, "not created yet", ""}}
+	}
+	db, err := statedb.Open(path)
+	if err != nil {
+		return []doctorCheck{{section, "state.db", doctorFail, err.Error(), "check the permissions of " + filepath.Dir(path)}}
+	}
+	defer db.Close()
+
+	problems, err := db.IntegrityCheck()
+	switch {
+	case err != nil:
+		return []doctorCheck{{section, "state.db", doctorFail, "integrity check failed: " + err.Error(),
+			"stop agent-deck, move " + path + " aside and restore from `agent-deck backup`"}}
+	case len(problems) > 0:
+		return []doctorCheck{{section, "state.db", doctorFail, fmt.Sprintf("corrupt: %s", strings.Join(problems, "; ")),
+			"stop agent-deck, move " + path + " aside and restore from `agent-deck backup`"}}
+	}
+
+	check := doctorCheck{section, "state.db", doctorOK, path, ""}
+	raw, _ := db.GetMeta("schema_version")
+	if v, err := strconv.Atoi(raw); err == nil && v > statedb.SchemaVersion {
+		check.Status = doctorWarn
+		check.Detail = fmt.Sprintf("schema v%d was written by a newer agent-deck (this one knows v%d)", v, statedb.SchemaVersion)
+		check.Fix = "agent-deck update"
+	}
+	return []doctorCheck{check}
+}
+
+// doctorHookChecks reports the status hooks of each tool. A tool whose
+// config dir does not exist is not used on this machine and is skipped.
+func doctorHookChecks(profile string) []doctorCheck {
+	const section = "hooks"
+	var checks []doctorCheck
+	exists := func(dir string) bool {
+		_, err := os.Stat(dir)
+		return dir != "" && err == nil
+	}
+	cfg, _ := session.LoadUserConfig()
+
+	claudeDir := session.GetClaudeConfigDirForProfile(session.GetEffectiveProfile(profile))
+	switch {
+	case cfg != nil && !cfg.Claude.GetHooksEnabled():
+		checks = append(checks, doctorCheck{section, "claude", doctorSkip, "disabled ([claude] hooks_enabled = false)", ""})
+	case !exists(claudeDir):
+		checks = append(checks, doctorCheck{section, "claude", doctorSkip, "no config dir at " + claudeDir, ""})
+	default:
+		st := session.InspectClaudeHooks(claudeDir)
+		switch {
+		case st.State == session.ClaudeHooksNotInstalled:
+			checks = append(checks, doctorCheck{section, "claude", doctorWarn, "not installed in " + claudeDir + ": status falls back to pane parsing", "agent-deck hooks install"})
+		case st.NeedsSync():
+			checks = append(checks, doctorCheck{section, "claude", doctorWarn, claudeHooksStateLabel(st.State) + ": " + strings.Join(st.Reasons, "; "), "agent-deck hooks sync"})
+		default:
+			checks = append(checks, doctorCheck{section, "claude", doctorOK, "installed in " + claudeDir, ""})
+		}
+	}
+
+	codexPath := getCodexConfigPath()
+	if !exists(filepath.Dir(codexPath)) {
+		checks = append(checks, doctorCheck{section, "codex", doctorSkip, "no config dir at " + filepath.Dir(codexPath), ""})
+	} else {
+		content, _ := readFileOrEmpty(codexPath)
+		switch codexNotifyState(content) {
+		case codexNotifyInstalled:
+			checks = append(checks, doctorCheck{section, "codex", doctorOK, "installed in " + codexPath, ""})
+		case codexNotifyLegacyTable:
+			checks = append(checks, doctorCheck{section, "codex", doctorWarn, "legacy notify table in " + codexPath, "agent-deck codex-hooks install"})
+		case codexNotifyCustom:
+			checks = append(checks, doctorCheck{section, "codex", doctorWarn, "a custom notify command replaces agent-deck's", "agent-deck codex-hooks install"})
+		default:
+			checks = append(checks, doctorCheck{section, "codex", doctorWarn, "not installed in " + codexPath, "agent-deck codex-hooks install"})
+		}
+	}
+
+	geminiDir := session.GetGeminiConfigDir()
+	if !exists(geminiDir) {
+		checks = append(checks, doctorCheck{section, "gemini", doctorSkip, "no config dir at " + geminiDir, ""})
+	} else if missing := session.MissingGeminiHookEvents(geminiDir); len(missing) == len(session.GeminiHookEvents()) {
+		checks = append(checks, doctorCheck{section, "gemini", doctorWarn, "not installed in " + geminiDir, "agent-deck gemini-hooks install"})
+	} else if len(missing) > 0 {
+		checks = append(checks, doctorCheck{section, "gemini", doctorWarn, "outdated, missing " + strings.Join(missing, ", "), "agent-deck gemini-hooks install"})
+	} else {
+		checks = append(checks, doctorCheck{section, "gemini", doctorOK, "installed in " + geminiDir, ""})
+	}
+
+	cursorDir := session.GetCursorConfigDir()
+	switch {
+	case cfg != nil && !cfg.Cursor.GetHooksEnabled():
+		checks = append(checks, doctorCheck{section, "cursor", doctorSkip, "disabled ([cursor] hooks_enabled = false)", ""})
+	case !exists(cursorDir):
+		checks = append(checks, doctorCheck{section, "cursor", doctorSkip, "no config dir at " + cursorDir, ""})
+	case session.CheckCursorHooksInstalled(cursorDir):
+		checks = append(checks, doctorCheck{section, "cursor", doctorOK, "installed in " + cursorDir, ""})
+	default:
+		checks = append(checks, doctorCheck{section, "cursor", doctorWarn, "not installed in " + cursorDir, "agent-deck cursor-hooks install"})
+	}
+
+	hermesDir := session.GetHermesConfigDir()
+	switch {
+	case !exists(hermesDir):
+		checks = append(checks, doctorCheck{section, "hermes", doctorSkip, "no config dir at " + hermesDir, ""})
+	case session.CheckHermesHooksInstalled(hermesDir):
+		checks = append(checks, doctorCheck{section, "hermes", doctorOK, "installed in " + hermesDir, ""})
+	default:
+		checks = append(checks, doctorCheck{section, "hermes", doctorWarn, "not installed in " + hermesDir, "agent-deck hermes-hooks install"})
+	}
+	return checks
+}
+
+// doctorDaemonChecks checks the conductor bridge and notifier daemons and
+// the watchdog's view of each conductor. Without conductors there is
+// nothing to check.
+func doctorDaemonChecks() []doctorCheck {
+	const section = "conductor"
+	if !session.ConductorSystemActive() {
+		return []doctorCheck{{section, "conductor", doctorSkip, "no conductors set up", ""}}
+	}
+	var checks []doctorCheck
+	switch {
+	case session.IsBridgeDaemonRunning():
+		checks = append(checks, doctorCheck{section, "bridge daemon", doctorOK, "running", ""})
+	case bridgeDaemonInstalled():
+		checks = append(checks, doctorCheck{section, "bridge daemon", doctorFail, "installed but not running", session.BridgeDaemonHint()})
+	default:
+		checks = append(checks, doctorCheck{section, "bridge daemon", doctorSkip, "not installed", ""})
+	}
+	if session.IsTransitionNotifierDaemonRunning() {
+		checks = append(checks, doctorCheck{section, "notifier daemon", doctorOK, "running", ""})
+	} else {
+		checks = append(checks, doctorCheck{section, "notifier daemon", doctorWarn, "stopped: conductors miss child status changes", session.TransitionNotifierDaemonHint()})
+	}
+
+	conductors, err := session.ListConductors()
+	if err != nil {
+		return append(checks, doctorCheck{section, "conductors", doctorFail, err.Error(), ""})
+	}
+	watchdog, _ := session.LoadWatchdogReport()
+	for _, meta := range conductors {
+		name := "conductor " + meta.Name
+		if !session.IsConductorSetup(meta.Name) {
+			checks = append(checks, doctorCheck{section, name, doctorFail, "directory missing", "agent-deck conductor setup " + meta.Name})
+			continue
+		}
+		var target *session.WatchdogTarget
+		if watchdog != nil {
+			target = watchdog.Conductors[meta.Name]
+		}
+		checks = append(checks, doctorConductorCheck(name, meta.Name, target))
+	}
+	return checks
+}
+
+// doctorConductorCheck grades one conductor from the watchdog's last pass.
+func doctorConductorCheck(label, name string, t *session.WatchdogTarget) doctorCheck {
+	switch {
+	case t == nil:
+		return doctorCheck{"conductor", label, doctorSkip, "no watchdog report yet", ""}
+	case t.GaveUp:
+		return doctorCheck{"conductor", label, doctorFail,
+			fmt.Sprintf("%s; the watchdog gave up after %d restarts this hour", firstNonEmpty(t.Problem, "unhealthy"), len(t.Restarts)),
+			"agent-deck session restart " + session.ConductorSessionTitle(name)}
+	case !t.Healthy:
+		return doctorCheck{"conductor", label, doctorWarn, firstNonEmpty(t.Problem, "unhealthy"), "agent-deck conductor status " + name}
+	case len(t.Restarts) > 0:
+		return doctorCheck{"conductor", label, doctorOK, fmt.Sprintf("healthy, %d restart(s) this hour", len(t.Restarts)), ""}
+	default:
+		return doctorCheck{"conductor", label, doctorOK, "healthy", ""}
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+func TestDoctorTmuxChecks_VersionAndPassthrough(t *testing.T) {
+	if c := doctorTmuxVersionCheck("3.1c", "linux"); c.Status != doctorWarn || c.Fix == "" {
+		t.Errorf("tmux 3.1c = %+v, want a warning with a fix", c)
+	}
+	if c := doctorTmuxVersionCheck("3.6a", "darwin"); c.Status != doctorWarn || !strings.Contains(c.Detail, "#4980") {
+		t.Errorf("tmux 3.6a on macOS = %+v, want the control-mode crash warning", c)
+	}
+	if c := doctorTmuxVersionCheck("3.6a", "linux"); c.Status != doctorOK {
+		t.Errorf("tmux 3.6a on Linux = %+v, want ok", c)
+	}
+
+	if c := doctorPassthroughCheck("3.4", "off"); c.Status != doctorWarn || !strings.Contains(c.Fix, "[tmux] options") {
+		t.Errorf("allow-passthrough off = %+v, want a warning pointing at [tmux] options", c)
+	}
+	if c := doctorPassthroughCheck("3.0a", ""); c.Status != doctorWarn {
+		t.Errorf("allow-passthrough on tmux 3.0a = %+v, want unsupported", c)
+	}
+	if c := doctorPassthroughCheck("3.4", "all"); c.Status != doctorOK || !strings.Contains(c.Detail, "all") {
+		t.Errorf("allow-passthrough all = %+v, want ok", c)
+	}
+}
+
+func TestDoctorTerminalChecks(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(k string) string { return vars[k] }
+	}
+	info := tmux.TerminalInfo{Name: "kitty", SupportsOSC8: true, SupportsOSC52: true, SupportsTrueColor: true}
+
+	checks := doctorTerminalChecks(info, env(map[string]string{"TERM": "xterm-kitty"}))
+	if n := doctorCounts(checks); n[doctorWarn]+n[doctorFail] != 0 {
+		t.Errorf("kitty checks = %+v, want no problems", checks)
+	}
+
+	checks = doctorTerminalChecks(info, env(map[string]string{"TERM": "xterm", "ATUIN_PTY_PROXY_ACTIVE": "1"}))
+	if checks[0].Status != doctorFail || !strings.Contains(checks[0].Fix, "atuin init") {
+		t.Errorf("atuin pty-proxy = %+v, want a failure with the atuin fix", checks[0])
+	}
+
+	checks = doctorTerminalChecks(tmux.TerminalInfo{Name: "unknown"}, env(map[string]string{"TERM": "dumb"}))
+	if checks[0].Status != doctorWarn || checks[1].Status != doctorWarn {
+		t.Errorf("dumb terminal = %+v, want TERM and true color warnings", checks)
+	}
+}
+
+func TestDoctorConductorCheck(t *testing.T) {
+	cases := []struct {
+		target *session.WatchdogTarget
+		want   string
+	}{
+		{nil, doctorSkip},
+		{&session.WatchdogTarget{Healthy: true}, doctorOK},
+		{&session.WatchdogTarget{Healthy: false, Problem: "heartbeat stale"}, doctorWarn},
+		{&session.WatchdogTarget{Problem: "session stopped", GaveUp: true, Restarts: []time.Time{time.Now()}}, doctorFail},
+	}
+	for _, c := range cases {
+		if got := doctorConductorCheck("conductor ops", "ops", c.target); got.Status != c.want {
+			t.Errorf("doctorConductorCheck(%+v) = %+v, want %s", c.target, got, c.want)
+		}
+	}
+}
+
+func TestRenderDoctorReport(t *testing.T) {
+	checks := []doctorCheck{
+		{"tmux", "version", doctorOK, "tmux 3.4", ""},
+		{"config", "config.toml", doctorFail, "parse error", "fix the syntax error"},
+		{"hooks", "codex", doctorSkip, "no config dir", ""},
+		{"hooks", "claude", doctorWarn, "not installed", "agent-deck hooks install"},
+	}
+
+	full := renderDoctorReport(checks, false)
+	for _, want := range []string{"tmux\n", "✓ version", "✕ config.toml", "→ fix the syntax error", "→ agent-deck hooks install", "1 problem(s), 1 warning(s)."} {
+		if !strings.Contains(full, want) {
+			t.Errorf("report missing %q:\n%s", want, full)
+		}
+	}
+
+	quiet := renderDoctorReport(checks, true)
+	if strings.Contains(quiet, "version") || strings.Contains(quiet, "codex") || !strings.Contains(quiet, "claude") {
+		t.Errorf("quiet report should list only problems:\n%s", quiet)
+	}
+
+	if got := renderDoctorReport(checks[:1], false); !strings.HasSuffix(got, "Everything looks good.\n") {
+		t.Errorf("healthy report = %q", got)
+	}
+}
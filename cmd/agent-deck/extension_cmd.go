@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleExtension dispatches extension subcommands. Extensions themselves run
+// as `agent-deck <name>` (see the default case of main's dispatch switch).
+func handleExtension(profile string, args []string) {
+	if len(args) == 0 {
+		printExtensionHelp()
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "list", "ls":
+		handleExtensionList(args[1:])
+	case "run":
+		handleExtensionRun(profile, args[1:])
+	case "help", "-h", "--help":
+		printExtensionHelp()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown extension command '%s'\n", args[0])
+		printExtensionHelp()
+		os.Exit(1)
+	}
+}
+
+func printExtensionHelp() {
+	fmt.Println("Usage: agent-deck extension <command> [options]")
+	fmt.Println()
+	fmt.Println("Extensions add commands to agent-deck. An executable named agent-deck-<name>")
+	fmt.Println("on PATH, or declared in [extensions.<name>] in config.toml, runs as")
+	fmt.Println("`agent-deck <name> [args]`. It gets AGENTDECK_PROFILE, AGENTDECK_BIN and, when")
+	fmt.Println("run for a session, AGENTDECK_SESSION_{ID,TITLE,PATH,GROUP,TOOL,STATUS} and")
+	fmt.Println("AGENTDECK_TMUX_SESSION. Built-in commands take precedence over extensions.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  list                             List discovered extensions")
+	fmt.Println("  run <name> [--session <id>] ...  Run an extension for a session")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck extension list")
+	fmt.Println("  agent-deck review --staged")
+	fmt.Println("  agent-deck extension run review --session my-project -- --staged")
+	fmt.Println()
+	fmt.Println("In the TUI, press Alt+x to run one for the selected session; it then also")
+	fmt.Println("gets the session as JSON on stdin.")
+}
+
+// handleExtensionList prints the discovered extensions.
+func handleExtensionList(args []string) {
+	fs := flag.NewFlagSet("extension list", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode (names only)")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck extension list [options]")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+
+	exts := session.GetExtensions()
+	rows := make([]map[string]interface{}, 0, len(exts))
+	var sb strings.Builder
+	if len(exts) == 0 {
+		sb.WriteString("No extensions found. Put an agent-deck-<name> executable on PATH or add [extensions.<name>] to config.toml.\n")
+	}
+	for _, ext := range exts {
+		shadowed := globalFlagSubcommands[ext.Name]
+		rows = append(rows, map[string]interface{}{
+			"name":        ext.Name,
+			"path":        ext.Path,
+			"args":        ext.Args,
+			"description": ext.Description,
+			"source":      ext.Source,
+			"shadowed":    shadowed,
+		})
+		if *quiet {
+			sb.WriteString(ext.Name + "\n")
+			continue
+		}
+		summary := ext.Description
+		if summary == "" {
+			summary = ext.Path
+		}
+		if shadowed {
+			summary += " (shadowed by built-in command; use 'extension run')"
+		}
+		fmt.Fprintf(&sb, "  %-16s %-7s %s\n", ext.Name, ext.Source, summary)
+	}
+	out.Print(strings.TrimRight(sb.String(), "\n"), rows)
+}
+
+// handleExtensionRun runs an extension by name, optionally for a given
+// session. Unlike `agent-deck <name>` it also reaches extensions whose name a
+// built-in command shadows.
+func handleExtensionRun(profile string, args []string) {
+	fs := flag.NewFlagSet("extension run", flag.ExitOnError)
+	sessionID := fs.String("session", "", "Session to run the extension for (default: the current session)")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck extension run <name> [--session <id|title>] [-- args...]")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fs.Usage()
+		os.Exit(1)
+	}
+	name := args[0]
+	if err := fs.Parse(args[1:]); err != nil {
+		os.Exit(1)
+	}
+
+	ext, ok := session.FindExtension(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: extension %q not found (see 'agent-deck extension list')\n", name)
+		os.Exit(2)
+	}
+	var inst *session.Instance
+	if *sessionID != "" {
+		_, instances, _, err := loadSessionData(profile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		var errMsg string
+		inst, errMsg, _ = ResolveSession(*sessionID, instances)
+		if inst == nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", errMsg)
+			os.Exit(2)
+		}
+	} else {
+		inst = currentSessionForExtension(profile)
+	}
+	os.Exit(execExtension(profile, ext, inst, fs.Args()))
+}
+
+// runExtension runs ext as `agent-deck <name> args...` and returns its exit
+// code. Run from inside an agent-deck session, that session is the context.
+func runExtension(profile string, ext session.Extension, args []string) int {
+	return execExtension(profile, ext, currentSessionForExtension(profile), args)
+}
+
+// currentSessionForExtension returns the agent-deck session the CLI was run
+// from, or nil. Storage is only opened when the environment says we are
+// inside one.
+func currentSessionForExtension(profile string) *session.Instance {
+	if os.Getenv("AGENTDECK_INSTANCE_ID") == "" && os.Getenv("AGENT_DECK_SESSION_ID") == "" && GetCurrentSessionID() == "" {
+		return nil
+	}
+	_, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		return nil
+	}
+	return resolveAutoParentInstance(instances)
+}
+
+// execExtension runs ext in the foreground with the terminal's stdio and
+// returns its exit code.
+func execExtension(profile string, ext session.Extension, inst *session.Instance, args []string) int {
+	cmd := ext.Command(context.Background(), args, session.NewExtensionContext(session.GetEffectiveProfile(profile), inst))
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode()
+		}
+		fmt.Fprintf(os.Stderr, "Error: extension %s: %v\n", ext.Name, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,392 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/git"
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// maxFanoutAttempts caps -n: each attempt is a worktree plus a running agent.
+const maxFanoutAttempts = 10
+
+// handleFanout dispatches `agent-deck fanout`: without a subcommand it
+// creates a fan-out from the prompt.
+func handleFanout(profile string, args []string) {
+	if len(args) > 0 {
+		switch args[0] {
+		case "list", "ls":
+			handleFanoutList(args[1:])
+			return
+		case "compare", "status":
+			handleFanoutCompare(profile, args[1:])
+			return
+		case "help", "-h", "--help":
+			printFanoutHelp()
+			return
+		}
+	}
+	handleFanoutCreate(profile, args)
+}
+
+func printFanoutHelp() {
+	fmt.Println("Usage: agent-deck fanout [options] <prompt>")
+	fmt.Println("       agent-deck fanout compare [fanout-id]")
+	fmt.Println("       agent-deck fanout list")
+	fmt.Println()
+	fmt.Println("Send one task to N agents at once, each in its own worktree of the same")
+	fmt.Println("repository, then compare their attempts and merge the best one.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  (none)               Create the worktree sessions and send the prompt to each")
+	fmt.Println("  compare [id]         Status and diffstat of each attempt (default: the newest fan-out)")
+	fmt.Println("  list                 List recorded fan-outs")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck fanout -n 3 -w feature/try-{n} -c claude \"implement X\"")
+	fmt.Println("  agent-deck fanout -n 2 -c codex --message-file task.md")
+	fmt.Println("  agent-deck fanout compare")
+	fmt.Println("  agent-deck worktree diff \"implement-x #2\"     # review the winner")
+	fmt.Println("  agent-deck worktree merge \"implement-x #2\" --squash")
+}
+
+// fanoutLaunchArgs builds the `launch` invocation for attempt n. The prompt
+// goes over stdin so long prompts need no quoting.
+func fanoutLaunchArgs(path, branch, title, group, tool, model string) []string {
+	argv := []string{"launch", path, "-w", branch, "-b", "-t", title, "--message-file", "-", "--json"}
+	if group != "" {
+		argv = append(argv, "-g", group)
+	}
+	if tool != "" {
+		argv = append(argv, "-c", tool)
+	}
+	if model != "" {
+		argv = append(argv, "--model", model)
+	}
+	return argv
+}
+
+// parseLaunchJSON reads the JSON object `launch --json` printed last; a
+// worktree setup script may have written to stdout before it.
+func parseLaunchJSON(out []byte) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	if err := json.Unmarshal(out, &result); err == nil {
+		return result, nil
+	}
+	if i := bytes.LastIndex(out, []byte("\n{")); i >= 0 {
+		if err := json.Unmarshal(out[i+1:], &result); err == nil {
+			return result, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected launch output: %s", strings.TrimSpace(string(out)))
+}
+
+// runFanoutAttempts launches every attempt concurrently (launch's own
+// throttle bounds the spawns) and fills in each attempt's session or error.
+func runFanoutAttempts(run mcpCommandRunner, attempts []session.FanoutAttempt, argvFor func(session.FanoutAttempt) []string, prompt string) {
+	var wg sync.WaitGroup
+	for i := range attempts {
+		wg.Add(1)
+		go func(a *session.FanoutAttempt) {
+			defer wg.Done()
+			out, runErr := run(argvFor(*a), prompt)
+			result, err := parseLaunchJSON(out)
+			if err != nil {
+				if runErr != nil {
+					err = runErr
+				}
+				a.Error = err.Error()
+				return
+			}
+			if ok, _ := result["success"].(bool); !ok {
+				a.Error, _ = result["error"].(string)
+				if a.Error == "" {
+					a.Error = "launch failed"
+				}
+				return
+			}
+			a.SessionID, _ = result["session_id"].(string)
+			a.Title, _ = result["title"].(string)
+		}(&attempts[i])
+	}
+	wg.Wait()
+}
+
+// handleFanoutCreate creates N worktree sessions from one repository and
+// sends each the same prompt.
+func handleFanoutCreate(profile string, args []string) {
+	fs := flag.NewFlagSet("fanout", flag.ExitOnError)
+	count := fs.Int("n", 3, fmt.Sprintf("Number of attempts (1-%d)", maxFanoutAttempts))
+	branchTemplate := fs.String("w", "", "Branch template; {n} becomes the attempt number (default: fanout/<prompt-slug>-{n})")
+	branchTemplateLong := fs.String("worktree", "", "Branch template (long)")
+	command := fs.String("cmd", "", "Tool/command each attempt runs (e.g. 'claude')")
+	commandShort := fs.String("c", "", "Tool/command (short)")
+	title := fs.String("title", "", "Title prefix; attempts are named '<prefix> #n' (default: from the prompt)")
+	titleShort := fs.String("t", "", "Title prefix (short)")
+	group := fs.String("group", "", "Group for the attempts (default: the repository's group)")
+	groupShort := fs.String("g", "", "Group (short)")
+	path := fs.String("path", ".", "Repository to fan out from")
+	messageFile := fs.String("message-file", "", "Read the prompt from a file ('-' for stdin)")
+	model := fs.String("model", "", "Model ID/version for every attempt")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+	fs.Usage = func() {
+		printFanoutHelp()
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+
+	prompt, err := resolveMessageInput(strings.Join(fs.Args(), " "), *messageFile, os.Stdin)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if strings.TrimSpace(prompt) == "" {
+		out.Error("a prompt is required (as an argument or --message-file)", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if *count < 1 || *count > maxFanoutAttempts {
+		out.Error(fmt.Sprintf("-n must be between 1 and %d", maxFanoutAttempts), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	repoPath, err := filepath.Abs(*path)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to resolve path: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if _, err := detectAndCreateBackend(repoPath); err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	slug := git.BranchSlugFromPrompt(prompt)
+	template := mergeFlags(*branchTemplate, *branchTemplateLong)
+	if template == "" {
+		template = "fanout/" + slug + "-" + session.FanoutBranchPlaceholder
+	}
+	branches, err := session.FanoutBranches(template, *count)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	for _, b := range branches {
+		if err := git.ValidateBranchName(b); err != nil {
+			out.Error(fmt.Sprintf("invalid branch name %q: %v", b, err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	}
+
+	titlePrefix := mergeFlags(*title, *titleShort)
+	if titlePrefix == "" {
+		titlePrefix = slug
+	}
+	// Keep the attempts side by side instead of in per-worktree groups.
+	groupPath := mergeFlags(*group, *groupShort)
+	if groupPath == "" {
+		groupPath = session.GroupPathForProject(repoPath)
+	}
+	tool := mergeFlags(*command, *commandShort)
+
+	rec := session.FanoutRecord{
+		ID:        git.GeneratePathID(),
+		Prompt:    strings.TrimSpace(prompt),
+		Tool:      tool,
+		Path:      repoPath,
+		CreatedAt: time.Now(),
+	}
+	for i, b := range branches {
+		rec.Attempts = append(rec.Attempts, session.FanoutAttempt{N: i + 1, Branch: b})
+	}
+
+	if !*jsonOutput && !*quiet && !*quietShort {
+		fmt.Fprintf(os.Stderr, "Launching %d attempt(s) of %q...\n", *count, truncateString(rec.Prompt, 60))
+	}
+	runFanoutAttempts(selfCommandRunner(profile), rec.Attempts, func(a session.FanoutAttempt) []string {
+		return fanoutLaunchArgs(repoPath, a.Branch, fmt.Sprintf("%s #%d", titlePrefix, a.N), groupPath, tool, *model)
+	}, prompt)
+
+	launched := 0
+	for _, a := range rec.Attempts {
+		if a.Error == "" {
+			launched++
+		}
+	}
+	if launched > 0 {
+		if err := session.RecordFanout(rec); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record fan-out: %v\n", err)
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Fan-out %s: %d of %d attempt(s) launched\n", rec.ID, launched, len(rec.Attempts))
+	for _, a := range rec.Attempts {
+		if a.Error != "" {
+			fmt.Fprintf(&sb, "  #%d %s  failed: %s\n", a.N, a.Branch, a.Error)
+		} else {
+			fmt.Fprintf(&sb, "  #%d %s  %s\n", a.N, a.Branch, a.Title)
+		}
+	}
+	if launched > 0 {
+		fmt.Fprintf(&sb, "\nCompare the attempts with: agent-deck fanout compare %s\n", rec.ID)
+	}
+	jsonData := map[string]interface{}{
+		"success":  launched == len(rec.Attempts),
+		"id":       rec.ID,
+		"launched": launched,
+		"attempts": rec.Attempts,
+	}
+	if launched == 0 {
+		out.ErrorWithData("no attempt could be launched", ErrCodeInvalidOperation, jsonData)
+		if !*jsonOutput {
+			fmt.Fprint(os.Stderr, sb.String())
+		}
+		os.Exit(1)
+	}
+	out.Print(sb.String(), jsonData)
+	if launched < len(rec.Attempts) {
+		os.Exit(1)
+	}
+}
+
+// handleFanoutList prints the recorded fan-outs, newest first.
+func handleFanoutList(args []string) {
+	fs := flag.NewFlagSet("fanout list", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	records, err := session.ReadFanouts()
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to read fan-outs: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+		records[i], records[j] = records[j], records[i]
+	}
+	if records == nil {
+		records = []session.FanoutRecord{}
+	}
+
+	var sb strings.Builder
+	if len(records) == 0 {
+		sb.WriteString("No fan-outs recorded.\n")
+	} else {
+		w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tCREATED\tATTEMPTS\tPROMPT")
+		for _, r := range records {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", r.ID, r.CreatedAt.Local().Format("2006-01-02 15:04"),
+				len(r.Attempts), truncateString(strings.ReplaceAll(r.Prompt, "\n", " "), 50))
+		}
+		_ = w.Flush()
+	}
+	out.Print(sb.String(), map[string]interface{}{"fanouts": records})
+}
+
+// fanoutComparison is one attempt's row in `fanout compare`.
+type fanoutComparison struct {
+	session.FanoutAttempt
+	Status string        `json:"status"`
+	Base   string        `json:"base,omitempty"`
+	Work   *git.WorkStat `json:"work,omitempty"`
+}
+
+// handleFanoutCompare shows each attempt's status and how much it changed,
+// committed or not, so the best one can be picked for review and merge.
+func handleFanoutCompare(profile string, args []string) {
+	fs := flag.NewFlagSet("fanout compare", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck fanout compare [fanout-id] [options]")
+		fmt.Println()
+		fmt.Println("Show each attempt's status and its changes since the base branch,")
+		fmt.Println("including uncommitted work. Defaults to the newest fan-out.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	rec, err := session.FindFanout(fs.Arg(0))
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(2)
+	}
+	_, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load sessions: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	byID := make(map[string]*session.Instance, len(instances))
+	for _, inst := range instances {
+		byID[inst.ID] = inst
+	}
+	session.RefreshInstancesForCLIStatus(instances)
+
+	rows := make([]fanoutComparison, 0, len(rec.Attempts))
+	for _, a := range rec.Attempts {
+		row := fanoutComparison{FanoutAttempt: a, Status: "failed"}
+		inst := byID[a.SessionID]
+		switch {
+		case a.Error != "":
+		case inst == nil:
+			row.Status = "removed"
+		default:
+			_ = inst.UpdateStatus()
+			row.Status = StatusString(inst.Status)
+			row.Title = inst.Title
+			if base, err := session.WorktreeBase(inst, ""); err == nil {
+				row.Base = base
+				if ws, err := git.WorktreeWorkStat(inst.WorktreePath, base); err == nil {
+					row.Work = &ws
+				}
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Fan-out %s (%s): %s\n\n", rec.ID, rec.CreatedAt.Local().Format("2006-01-02 15:04"),
+		truncateString(strings.ReplaceAll(rec.Prompt, "\n", " "), 70))
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tSESSION\tSTATUS\tCOMMITS\tFILES\tLINES\tUNTRACKED\tBRANCH")
+	for _, r := range rows {
+		commits, files, lines, untracked := "-", "-", "-", "-"
+		if r.Work != nil {
+			commits = fmt.Sprint(r.Work.Commits)
+			files = fmt.Sprint(r.Work.Files)
+			lines = fmt.Sprintf("+%d -%d", r.Work.Added, r.Work.Deleted)
+			untracked = fmt.Sprint(r.Work.Untracked)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", r.N, r.Title, r.Status, commits, files, lines, untracked, r.Branch)
+	}
+	_ = w.Flush()
+	sb.WriteString("\nReview one with 'agent-deck worktree diff <session>', then 'agent-deck worktree merge <session>'.\n")
+
+	out.Print(sb.String(), map[string]interface{}{
+		"id":         rec.ID,
+		"prompt":     rec.Prompt,
+		"created_at": rec.CreatedAt,
+		"attempts":   rows,
+	})
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+func TestParseLaunchJSON_SkipsSetupOutput(t *testing.T) {
+	out := []byte("running worktree setup...\ndone\n{\n  \"success\": true,\n  \"session_id\": \"abc\"\n}\n")
+	got, err := parseLaunchJSON(out)
+	if err != nil || got["session_id"] != "abc" {
+		t.Fatalf("parseLaunchJSON = %v, %v", got, err)
+	}
+	if _, err := parseLaunchJSON([]byte("not json")); err == nil {
+		t.Error("plain text: want error")
+	}
+}
+
+func TestRunFanoutAttempts(t *testing.T) {
+	attempts := []session.FanoutAttempt{
+		{N: 1, Branch: "try-1"},
+		{N: 2, Branch: "try-2"},
+		{N: 3, Branch: "try-3"},
+	}
+	var mu sync.Mutex
+	prompts := map[string]string{}
+	run := func(argv []string, stdin string) ([]byte, error) {
+		branch := argv[3]
+		mu.Lock()
+		prompts[branch] = stdin
+		mu.Unlock()
+		switch branch {
+		case "try-2":
+			return []byte(`{"success": false, "error": "branch 'try-2' already exists", "code": "INVALID_OPERATION"}`), errors.New("exit status 1")
+		case "try-3":
+			return nil, errors.New("exec: not found")
+		}
+		return []byte(`{"success": true, "session_id": "s-` + branch + `", "title": "x #1"}`), nil
+	}
+	runFanoutAttempts(run, attempts, func(a session.FanoutAttempt) []string {
+		return fanoutLaunchArgs("/repo", a.Branch, "x", "grp", "claude", "")
+	}, "implement X")
+
+	if a := attempts[0]; a.SessionID != "s-try-1" || a.Title != "x #1" || a.Error != "" {
+		t.Errorf("attempt 1 = %+v", a)
+	}
+	if a := attempts[1]; a.SessionID != "" || !strings.Contains(a.Error, "already exists") {
+		t.Errorf("attempt 2 = %+v, want launch error", a)
+	}
+	if a := attempts[2]; !strings.Contains(a.Error, "not found") {
+		t.Errorf("attempt 3 = %+v, want runner error", a)
+	}
+	for _, b := range []string{"try-1", "try-2", "try-3"} {
+		if prompts[b] != "implement X" {
+			t.Errorf("prompt for %s = %q", b, prompts[b])
+		}
+	}
+}
+
+func TestFanoutLaunchArgs(t *testing.T) {
+	got := strings.Join(fanoutLaunchArgs("/repo", "try-1", "x #1", "work", "claude", "opus"), " ")
+	for _, want := range []string{"launch /repo -w try-1 -b", "-t x #1", "--message-file -", "--json", "-g work", "-c claude", "--model opus"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("args %q missing %q", got, want)
+		}
+	}
+	if got := strings.Join(fanoutLaunchArgs("/repo", "try-1", "x", "", "", ""), " "); strings.Contains(got, "-g") || strings.Contains(got, "-c") {
+		t.Errorf("empty group/tool should be omitted: %q", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/asheshgoplani/agent-deck/internal/session"
 )
@@ -73,13 +74,19 @@ func handleGeminiHooksUninstall() {
 
 func handleGeminiHooksStatus() {
 	configDir := getGeminiConfigDirForHooks()
-	installed := session.CheckGeminiHooksInstalled(configDir)
+	missing := session.MissingGeminiHookEvents(configDir)
 	configPath := filepath.Join(configDir, "settings.json")
 
-	if installed {
+	switch {
+	case len(missing) == 0:
 		fmt.Println("Status: INSTALLED")
 		fmt.Printf("Config: %s\n", configPath)
-	} else {
+	case len(missing) < len(session.GeminiHookEvents()):
+		fmt.Println("Status: OUTDATED")
+		fmt.Printf("Config: %s\n", configPath)
+		fmt.Printf("Missing hooks: %s\n", strings.Join(missing, ", "))
+		fmt.Println("Run 'agent-deck gemini-hooks install' to upgrade.")
+	default:
 		fmt.Println("Status: NOT INSTALLED")
 		fmt.Println("Run 'agent-deck gemini-hooks install' to install.")
 	}
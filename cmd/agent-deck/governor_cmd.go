@@ -0,0 +1,273 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// governorPollInterval is how often a blocking send (--wait, --stream)
+// re-checks the governor while it holds the message.
+const governorPollInterval = 5 * time.Second
+
+// refreshGovernorStatuses refreshes the status (and substate) of the Claude
+// sessions, the only ones the governor counts.
+func refreshGovernorStatuses(instances []*session.Instance) {
+	var claude []*session.Instance
+	for _, inst := range instances {
+		if session.IsClaudeCompatible(inst.Tool) {
+			claude = append(claude, inst)
+		}
+	}
+	refreshStatusesForCLI(claude)
+}
+
+// governSend applies the [governor] to a `session send`. It returns true
+// when the message was queued instead of sent (the caller is done). A
+// blocking send cannot be queued, so it waits here until the governor lets
+// it through, up to timeout.
+func governSend(profile string, out *CLIOutput, storage *session.Storage, inst *session.Instance, instances []*session.Instance, message string, blocking bool, timeout time.Duration) bool {
+	settings := session.GetGovernorSettings()
+	if !settings.Enabled() || !session.IsClaudeCompatible(inst.Tool) {
+		return false
+	}
+	refreshGovernorStatuses(instances)
+	d := session.CheckGovernor(settings, instances, inst)
+	if d.Allowed {
+		return false
+	}
+
+	if blocking {
+		deadline := time.Now().Add(timeout)
+		for !d.Allowed {
+			if time.Now().After(deadline) {
+				out.Error(fmt.Sprintf("governor held the message for %s: %s", timeout, d.Reason), ErrCodeInvalidOperation)
+				os.Exit(1)
+			}
+			time.Sleep(governorPollInterval)
+			freshStorage, fresh, _, err := loadSessionData(profile)
+			if err != nil {
+				continue
+			}
+			_ = freshStorage.Close()
+			target, _, _ := ResolveSession(inst.ID, fresh)
+			if target == nil {
+				out.Error(fmt.Sprintf("session '%s' was removed while the governor held the message", inst.Title), ErrCodeNotFound)
+				os.Exit(2)
+			}
+			refreshGovernorStatuses(fresh)
+			d = session.CheckGovernor(settings, fresh, target)
+		}
+		return false
+	}
+
+	db := storage.GetDB()
+	if db == nil {
+		out.Error("no state database for this profile (needed to queue the message)", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	q, err := session.QueueSend(db, inst, message, d.Reason, time.Now())
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to queue message: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Queued message for '%s' (%s); it is sent when capacity frees up [%s]", inst.Title, d.Reason, q.ID),
+		map[string]interface{}{
+			"success":       true,
+			"queued":        true,
+			"queue_id":      q.ID,
+			"reason":        d.Reason,
+			"session_id":    inst.ID,
+			"session_title": inst.Title,
+		})
+	return true
+}
+
+// handleGovernor dispatches the governor subcommands.
+func handleGovernor(profile string, args []string) {
+	if len(args) == 0 {
+		handleGovernorStatus(profile, args)
+		return
+	}
+	switch args[0] {
+	case "status":
+		handleGovernorStatus(profile, args[1:])
+	case "drop":
+		handleGovernorDrop(profile, args[1:])
+	case "help", "-h", "--help":
+		printGovernorHelp()
+	default:
+		if strings.HasPrefix(args[0], "-") {
+			handleGovernorStatus(profile, args)
+			return
+		}
+		fmt.Fprintf(os.Stderr, "Error: unknown governor command '%s'\n", args[0])
+		printGovernorHelp()
+		os.Exit(1)
+	}
+}
+
+func printGovernorHelp() {
+	fmt.Println("Usage: agent-deck governor [command] [options]")
+	fmt.Println()
+	fmt.Println("The send governor caps how many Claude sessions work at once. With")
+	fmt.Println("[governor] max_active set, `session send` to an idle Claude session over")
+	fmt.Println("the cap is queued; with queue_on_usage_limit, sends are queued while any")
+	fmt.Println("Claude pane shows the usage-limit notice. Queued messages go out oldest")
+	fmt.Println("first as sessions go idle (from the TUI, or the notify daemon without one).")
+	fmt.Println("`session send --no-queue` bypasses the governor.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  status                    Show active Claude sessions and queued messages (default)")
+	fmt.Println("  drop <queue-id|all>       Drop queued messages without sending them")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck governor")
+	fmt.Println("  agent-deck governor --json")
+	fmt.Println("  agent-deck governor drop send-1a2b3c4d")
+}
+
+// handleGovernorStatus shows the governor's current view and the queue.
+func handleGovernorStatus(profile string, args []string) {
+	fs := flag.NewFlagSet("governor status", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	storage, instances, db := openTaskDB(profile, out)
+	defer storage.Close()
+	settings := session.GetGovernorSettings()
+	refreshGovernorStatuses(instances)
+
+	var active, limited []string
+	for _, inst := range instances {
+		if !session.IsClaudeCompatible(inst.Tool) {
+			continue
+		}
+		if s := inst.GetStatusThreadSafe(); s == session.StatusRunning || s == session.StatusStarting {
+			active = append(active, inst.Title)
+		}
+		if inst.CachedSubstate() == session.SubstateUsageLimit {
+			limited = append(limited, inst.Title)
+		}
+	}
+	queued, err := db.LoadQueuedSends("")
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to read the send queue: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	titles := sessionTitles(instances)
+
+	var b strings.Builder
+	if settings.Enabled() {
+		limit := "no cap"
+		if settings.MaxActive > 0 {
+			limit = fmt.Sprintf("max %d", settings.MaxActive)
+		}
+		fmt.Fprintf(&b, "Governor: on (%s, queue on usage limit: %v)\n", limit, settings.QueueOnUsageLimit)
+	} else {
+		b.WriteString("Governor: off (set [governor] max_active or queue_on_usage_limit)\n")
+	}
+	fmt.Fprintf(&b, "Active Claude sessions: %d", len(active))
+	if len(active) > 0 {
+		fmt.Fprintf(&b, " (%s)", strings.Join(active, ", "))
+	}
+	b.WriteString("\n")
+	if len(limited) > 0 {
+		fmt.Fprintf(&b, "Usage limit shown by: %s\n", strings.Join(limited, ", "))
+	}
+	if len(queued) == 0 {
+		b.WriteString("No queued messages.\n")
+	} else {
+		fmt.Fprintf(&b, "\n%-14s  %-20s  %-8s  %s\n", "ID", "SESSION", "AGE", "REASON")
+		for _, q := range queued {
+			title := titles[q.InstanceID]
+			if title == "" {
+				title = q.InstanceID + " (removed)"
+			}
+			reason := q.Reason
+			if q.LastError != "" {
+				reason += fmt.Sprintf(" [attempt %d failed: %s]", q.Attempts, q.LastError)
+			}
+			fmt.Fprintf(&b, "%-14s  %-20s  %-8s  %s\n", q.ID, truncate(title, 20), formatDuration(time.Since(q.QueuedAt)), reason)
+		}
+	}
+
+	out.Print(b.String(), map[string]interface{}{
+		"success":              true,
+		"enabled":              settings.Enabled(),
+		"max_active":           settings.MaxActive,
+		"queue_on_usage_limit": settings.QueueOnUsageLimit,
+		"active":               active,
+		"usage_limited":        limited,
+		"queued":               queuedSendsJSON(queued, titles),
+	})
+}
+
+// handleGovernorDrop removes queued messages without sending them.
+func handleGovernorDrop(profile string, args []string) {
+	fs := flag.NewFlagSet("governor drop", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+	if fs.NArg() != 1 {
+		out.Error("governor drop requires <queue-id|all>", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	storage, _, db := openTaskDB(profile, out)
+	defer storage.Close()
+	queued, err := db.LoadQueuedSends("")
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to read the send queue: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	ref := fs.Arg(0)
+	var dropped []string
+	for _, q := range queued {
+		if ref != "all" && q.ID != ref {
+			continue
+		}
+		if ok, err := db.TakeQueuedSend(q.ID); err == nil && ok {
+			dropped = append(dropped, q.ID)
+		}
+	}
+	if len(dropped) == 0 && ref != "all" {
+		out.Error(fmt.Sprintf("no queued message %s", ref), ErrCodeNotFound)
+		os.Exit(2)
+	}
+	out.Success(fmt.Sprintf("Dropped %d queued message(s)", len(dropped)), map[string]interface{}{
+		"success": true,
+		"dropped": dropped,
+	})
+}
+
+func queuedSendsJSON(queued []*statedb.QueuedSend, titles map[string]string) []map[string]interface{} {
+	items := make([]map[string]interface{}, 0, len(queued))
+	for _, q := range queued {
+		item := map[string]interface{}{
+			"id":         q.ID,
+			"session_id": q.InstanceID,
+			"session":    titles[q.InstanceID],
+			"message":    q.Message,
+			"reason":     q.Reason,
+			"queued_at":  q.QueuedAt,
+		}
+		if q.Attempts > 0 {
+			item["attempts"] = q.Attempts
+			item["last_error"] = q.LastError
+		}
+		items = append(items, item)
+	}
+	return items
+}
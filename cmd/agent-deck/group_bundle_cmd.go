@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/progress"
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleGroupExport implements `agent-deck group export <group> [file]`:
+// it writes the group's session definitions as a shareable bundle.
+func handleGroupExport(profile string, args []string) {
+	fs := flag.NewFlagSet("group export", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output the result as JSON (requires a file)")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck group export <group> [file.json]")
+		fmt.Println()
+		fmt.Println("Write a group, its subgroups and their session definitions (titles, tools,")
+		fmt.Println("worktree branches, MCPs, skills) as a bundle others can 'group import'.")
+		fmt.Println("Paths are stored relative to {root}, the deepest directory holding them all.")
+		fmt.Println("Without a file, the bundle is printed to stdout.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck group export payments payments-stack.json")
+		fmt.Println("  agent-deck group export work/payments > payments-stack.json")
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	name, file := fs.Arg(0), fs.Arg(1)
+	if name == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *jsonOutput && (file == "" || file == "-") {
+		out.Error("--json needs a file to write the bundle to", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	_, instances, groups, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load sessions: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	tree := session.NewGroupTreeWithGroups(instances, groups)
+	groupPath := resolveGroupPathForAdd(tree, normalizeGroupPath(name))
+	if _, ok := tree.Groups[groupPath]; !ok {
+		out.Error(fmt.Sprintf("group '%s' not found", name), ErrCodeNotFound)
+		os.Exit(2)
+	}
+	bundle, err := session.NewGroupBundle(tree, groupPath)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	for _, s := range bundle.Skipped {
+		fmt.Fprintf(os.Stderr, "Warning: skipped %s\n", s)
+	}
+	if file == "" || file == "-" {
+		if err := bundle.Write(os.Stdout); err != nil {
+			out.Error(err.Error(), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		return
+	}
+	var buf bytes.Buffer
+	if err := bundle.Write(&buf); err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(file, buf.Bytes(), 0o644); err != nil {
+		out.Error(fmt.Sprintf("failed to write bundle: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Exported %s (%d session(s), root %s) to %s", groupPath, len(bundle.Sessions), bundle.Root, file), map[string]interface{}{
+		"success":  true,
+		"group":    groupPath,
+		"file":     file,
+		"root":     bundle.Root,
+		"sessions": len(bundle.Sessions),
+		"skipped":  bundle.Skipped,
+	})
+}
+
+// handleGroupImport implements `agent-deck group import <file>`: it
+// recreates a bundle's groups and sessions under a local {root}. Sessions
+// are created, not started; ones that already exist are kept.
+func handleGroupImport(profile string, args []string) {
+	fs := flag.NewFlagSet("group import", flag.ExitOnError)
+	root := fs.String("root", "", "Directory {root} maps to on this machine (default: the bundle's root)")
+	group := fs.String("group", "", "Group to import into (default: the bundle's group name)")
+	dryRun := fs.Bool("dry-run", false, "Show what would be created without changing anything")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck group import <file.json> [options]")
+		fmt.Println()
+		fmt.Println("Recreate the groups and sessions of a 'group export' bundle. Worktree")
+		fmt.Println("sessions get a fresh worktree for their branch. Sessions whose title")
+		fmt.Println("already exists in their group are kept. Use '-' to read stdin.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck group import payments-stack.json")
+		fmt.Println("  agent-deck group import payments-stack.json --root ~/src/payments --group team/payments")
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	quietMode := *quiet || *quietShort
+	out := NewCLIOutput(*jsonOutput, quietMode)
+	file := fs.Arg(0)
+	if file == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	var r io.Reader = os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			out.Error(fmt.Sprintf("failed to open bundle: %v", err), ErrCodeNotFound)
+			os.Exit(2)
+		}
+		defer f.Close()
+		r = f
+	}
+	bundle, err := session.ReadGroupBundle(r)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	rootDir := *root
+	if rootDir == "" {
+		rootDir = bundle.Root
+	}
+	manifest, err := bundle.Manifest(rootDir, normalizeGroupPath(*group))
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	if *dryRun {
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "Would import %d session(s) into %s (root %s):\n", len(manifest.Sessions), manifest.RootGroup(), manifest.Dir)
+		planned := make([]map[string]string, 0, len(manifest.Sessions))
+		for _, s := range manifest.Sessions {
+			line := fmt.Sprintf("  %s  (%s)  %s", s.Title, manifest.GroupPath(s.Group), s.Path)
+			if s.Worktree != "" {
+				line += "  worktree " + s.Worktree
+			}
+			sb.WriteString(line + "\n")
+			planned = append(planned, map[string]string{
+				"title":    s.Title,
+				"group":    manifest.GroupPath(s.Group),
+				"path":     s.Path,
+				"worktree": s.Worktree,
+			})
+		}
+		out.Print(sb.String(), map[string]interface{}{
+			"dry_run":  true,
+			"group":    manifest.RootGroup(),
+			"root":     manifest.Dir,
+			"sessions": planned,
+		})
+		return
+	}
+
+	storage, err := session.NewStorageWithProfile(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to initialize storage: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	instances, groups, err := storage.LoadWithGroups()
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load sessions: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	var report progress.Func
+	if !*jsonOutput && !quietMode {
+		report = progress.NewCLI(os.Stderr)
+	}
+	res := manifest.Materialize(instances, report)
+
+	instances = append(instances, res.Created...)
+	groupTree := session.NewGroupTreeWithGroups(instances, groups)
+	if cfg, cfgErr := session.LoadUserConfig(); cfgErr == nil && cfg != nil {
+		groupTree.DefaultMaxConcurrent = cfg.GroupDefaults.MaxConcurrent
+	}
+	manifest.ApplyGroups(groupTree)
+	bundle.ApplyGroupSettings(groupTree, manifest)
+	if err := storage.SaveWithGroups(instances, groupTree); err != nil {
+		out.Error(fmt.Sprintf("failed to save sessions: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	for _, w := range res.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+	result := manifestResultJSON(manifest, res)
+	delete(result, "manifest")
+	result["root"] = manifest.Dir
+	header := fmt.Sprintf("Imported %s (group %s, root %s)", file, manifest.RootGroup(), manifest.Dir)
+	out.Print(formatMaterializeResult(header, manifest, res), result)
+	if len(res.Failed) > 0 {
+		os.Exit(1)
+	}
+}
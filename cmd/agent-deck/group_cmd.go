@@ -36,6 +36,12 @@ func groupVerbCanonical(verb string) (canonical string, ok bool) {
 		return "change", true
 	case "reorder", "sort":
 		return "reorder", true
+	case "set-mcp", "set-mcps":
+		return "set-mcp", true
+	case "export":
+		return "export", true
+	case "import":
+		return "import", true
 	case "help", "--help", "-h":
 		return "help", true
 	}
@@ -75,6 +81,12 @@ func handleGroup(profile string, args []string) {
 		handleGroupChange(profile, args[1:])
 	case "reorder":
 		handleGroupReorder(profile, args[1:])
+	case "set-mcp":
+		handleGroupSetMCP(profile, args[1:])
+	case "export":
+		handleGroupExport(profile, args[1:])
+	case "import":
+		handleGroupImport(profile, args[1:])
 	case "help":
 		printGroupHelp()
 	}
@@ -86,13 +98,16 @@ func printGroupHelp() {
 	fmt.Println()
 	fmt.Println("Commands:")
 	fmt.Println("  list              List all groups with session counts")
-	fmt.Println("  show <name>       Show one group; --resolved adds the effective claude config (alias: info)")
+	fmt.Println("  show <name>       Show one group and its README; --resolved adds the effective claude config (alias: info)")
 	fmt.Println("  create <name>     Create a new group")
-	fmt.Println("  update <name>     Update group settings")
+	fmt.Println("  update <name>     Update group settings or README (--goal, --readme-file)")
 	fmt.Println("  delete <name>     Delete a group (aliases: rm, remove)")
 	fmt.Println("  move <id> <group> Move session to a different group")
 	fmt.Println("  change <group> [<dest>] Reparent a group (empty dest = move to root)")
 	fmt.Println("  reorder <name>    Reorder a group (--up, --down, --position N)")
+	fmt.Println("  set-mcp <name> <mcp>...  Set MCPs attached to new sessions in the group (--clear to remove)")
+	fmt.Println("  export <name> [file]     Write the group's session definitions as a shareable bundle")
+	fmt.Println("  import <file>     Recreate a bundle's groups and sessions (--root maps its paths)")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  agent-deck group list")
@@ -100,6 +115,7 @@ func printGroupHelp() {
 	fmt.Println("  agent-deck group create mobile")
 	fmt.Println("  agent-deck group create ios --parent mobile")
 	fmt.Println("  agent-deck group update mobile --default-path /path/to/repo")
+	fmt.Println("  agent-deck group update mobile --goal \"Ship offline sync by Friday\"")
 	fmt.Println("  agent-deck group delete experiments")
 	fmt.Println("  agent-deck group delete work --force")
 	fmt.Println("  agent-deck group move my-project work/frontend")
@@ -109,6 +125,9 @@ func printGroupHelp() {
 	fmt.Println("  agent-deck group reorder mobile --up")
 	fmt.Println("  agent-deck group reorder mobile --down")
 	fmt.Println("  agent-deck group reorder mobile --position 0")
+	fmt.Println("  agent-deck group set-mcp mobile memory exa")
+	fmt.Println("  agent-deck group export payments payments-stack.json")
+	fmt.Println("  agent-deck group import payments-stack.json --root ~/src/payments")
 }
 
 // handleGroupList lists all groups with session counts and status
@@ -163,6 +182,8 @@ func handleGroupList(profile string, args []string) {
 			Idle    int `json:"idle"`
 			Error   int `json:"error"`
 			Stopped int `json:"stopped"`
+			Exited  int `json:"exited"`
+			Crashed int `json:"crashed"`
 		}
 
 		type groupJSON struct {
@@ -192,6 +213,10 @@ func handleGroupList(profile string, args []string) {
 							status.Error++
 						case session.StatusStopped:
 							status.Stopped++
+						case session.StatusExited:
+							status.Exited++
+						case session.StatusCrashed:
+							status.Crashed++
 						}
 					}
 				}
@@ -366,7 +391,7 @@ func handleGroupShow(profile string, args []string) {
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck group show <name> [--resolved] [--json]")
 		fmt.Println()
-		fmt.Println("Show a group's settings.")
+		fmt.Println("Show a group's settings and README (goal statement).")
 		fmt.Println()
 		fmt.Println("Options:")
 		fs.PrintDefaults()
@@ -438,6 +463,7 @@ func handleGroupShow(profile string, args []string) {
 		"path":           groupPath,
 		"default_path":   groupTree.DefaultPathForGroup(groupPath),
 		"max_concurrent": g.MaxConcurrent,
+		"default_mcps":   nonNilStrings(session.GroupDefaultMCPs(groupTree, groupPath)),
 		"sessions":       sessionCount,
 	}
 
@@ -446,8 +472,22 @@ func handleGroupShow(profile string, args []string) {
 	fmt.Fprintf(&b, "  Name:           %s\n", g.Name)
 	fmt.Fprintf(&b, "  Default path:   %s\n", orNone(groupTree.DefaultPathForGroup(groupPath)))
 	fmt.Fprintf(&b, "  Max concurrent: %d\n", g.MaxConcurrent)
+	fmt.Fprintf(&b, "  Default MCPs:   %s\n", orNone(describeGroupMCPs(groupTree, groupPath)))
 	fmt.Fprintf(&b, "  Sessions:       %d\n", sessionCount)
 
+	readme, err := session.ReadGroupReadme(profile, groupPath)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to read group README: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	jsonData["readme"] = readme
+	if strings.TrimSpace(readme) != "" {
+		b.WriteString("\nREADME:\n")
+		for _, line := range strings.Split(strings.TrimRight(readme, "\n"), "\n") {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+
 	if *resolved {
 		// Force a fresh config.toml parse — `group show --resolved` is a
 		// diagnostic command and must always show current on-disk state,
@@ -499,6 +539,12 @@ func handleGroupShow(profile string, args []string) {
 	out.Print(b.String(), jsonData)
 }
 
+// groupHasReadme reports whether groupPath has a non-empty README.
+func groupHasReadme(profile, groupPath string) bool {
+	readme, _ := session.ReadGroupReadme(profile, groupPath)
+	return strings.TrimSpace(readme) != ""
+}
+
 // orNone renders an empty string as "(none)" for human-readable output.
 func orNone(s string) string {
 	if s == "" {
@@ -643,6 +689,9 @@ func handleGroupUpdate(profile string, args []string) {
 	// v1.9.1: -1 sentinel means "flag not set; leave existing value alone".
 	// 0 = unlimited, 1 = serial, N>=2 = bounded cap.
 	maxConcurrent := fs.Int("max-concurrent", -1, "Cap simultaneous running sessions in this group (0=unlimited, 1=serial, N=cap)")
+	goal := fs.String("goal", "", "Set the group README to this goal statement (markdown)")
+	readmeFile := fs.String("readme-file", "", "Read the group README (markdown) from a file ('-' for stdin)")
+	clearReadme := fs.Bool("clear-readme", false, "Remove the group README")
 	jsonOutput := fs.Bool("json", false, "Output as JSON")
 	quiet := fs.Bool("quiet", false, "Minimal output")
 	quietShort := fs.Bool("q", false, "Minimal output (short)")
@@ -659,6 +708,8 @@ func handleGroupUpdate(profile string, args []string) {
 		fmt.Println("  agent-deck group update mobile --default-path /path/to/repo")
 		fmt.Println("  agent-deck group update mobile --clear-default-path")
 		fmt.Println("  agent-deck group update mobile --max-concurrent 2")
+		fmt.Println("  agent-deck group update mobile --goal \"Ship offline sync by Friday\"")
+		fmt.Println("  agent-deck group update mobile --readme-file docs/mobile-goal.md")
 	}
 
 	args = reorderGroupArgs(args)
@@ -679,10 +730,23 @@ func handleGroupUpdate(profile string, args []string) {
 	// At least one mutation must be requested.
 	pathFlagSet := *defaultPath != "" || *clearDefaultPath
 	maxFlagSet := *maxConcurrent >= 0
-	if !pathFlagSet && !maxFlagSet {
-		out.Error("specify at least one of --default-path, --clear-default-path, or --max-concurrent", ErrCodeInvalidOperation)
+	readmeFlagSet := *goal != "" || *readmeFile != "" || *clearReadme
+	if !pathFlagSet && !maxFlagSet && !readmeFlagSet {
+		out.Error("specify at least one of --default-path, --clear-default-path, --max-concurrent, --goal, --readme-file, or --clear-readme", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if *clearReadme && (*goal != "" || *readmeFile != "") {
+		out.Error("--clear-readme cannot be combined with --goal or --readme-file", ErrCodeInvalidOperation)
 		os.Exit(1)
 	}
+	var readme string
+	if *goal != "" || *readmeFile != "" {
+		var err error
+		if readme, err = resolveMessageInput(*goal, *readmeFile, os.Stdin); err != nil {
+			out.Error(err.Error(), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	}
 	if *defaultPath != "" && *clearDefaultPath {
 		out.Error("--default-path and --clear-default-path are mutually exclusive", ErrCodeInvalidOperation)
 		os.Exit(1)
@@ -730,9 +794,18 @@ func handleGroupUpdate(profile string, args []string) {
 		}
 	}
 
-	if err := storage.SaveWithGroups(instances, groupTree); err != nil {
-		out.Error(fmt.Sprintf("failed to save: %v", err), ErrCodeNotFound)
-		os.Exit(1)
+	if pathFlagSet || maxFlagSet {
+		if err := storage.SaveWithGroups(instances, groupTree); err != nil {
+			out.Error(fmt.Sprintf("failed to save: %v", err), ErrCodeNotFound)
+			os.Exit(1)
+		}
+	}
+
+	if readmeFlagSet {
+		if err := session.WriteGroupReadme(profile, groupPath, readme); err != nil {
+			out.Error(fmt.Sprintf("failed to save group README: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
 	}
 
 	currentDefaultPath := groupTree.DefaultPathForGroup(groupPath)
@@ -740,7 +813,7 @@ func handleGroupUpdate(profile string, args []string) {
 	if g := groupTree.Groups[groupPath]; g != nil {
 		currentMax = g.MaxConcurrent
 	}
-	if *clearDefaultPath && !maxFlagSet {
+	if *clearDefaultPath && !maxFlagSet && !readmeFlagSet {
 		out.Success(fmt.Sprintf("Cleared default path for group: %s", groupPath), map[string]interface{}{
 			"success":        true,
 			"path":           groupPath,
@@ -756,6 +829,7 @@ func handleGroupUpdate(profile string, args []string) {
 		"path":           groupPath,
 		"default_path":   currentDefaultPath,
 		"max_concurrent": currentMax,
+		"has_readme":     strings.TrimSpace(readme) != "" || (!readmeFlagSet && groupHasReadme(profile, groupPath)),
 	})
 }
 
@@ -903,6 +977,10 @@ func handleGroupDelete(profile string, args []string) {
 		os.Exit(1)
 	}
 
+	if err := session.MoveGroupReadmes(profile, groupPath, ""); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to remove group README: %v\n", err)
+	}
+
 	out.Success(fmt.Sprintf("Deleted group: %s", name), map[string]interface{}{
 		"success":        true,
 		"name":           name,
@@ -1296,6 +1374,8 @@ func reorderGroupArgs(args []string) []string {
 		"--parent":       true,
 		"--default-path": true,
 		"--position":     true,
+		"--goal":         true,
+		"--readme-file":  true,
 		"-p":             true,
 	}
 
@@ -1457,6 +1537,10 @@ func handleGroupChange(profile string, args []string) {
 		os.Exit(1)
 	}
 
+	if err := session.MoveGroupReadmes(profile, sourcePath, newPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to move group README: %v\n", err)
+	}
+
 	out.Success(fmt.Sprintf("Moved group %q to %q", sourcePath, newPath), map[string]interface{}{
 		"from": sourcePath,
 		"to":   newPath,
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleGroupSetMCP sets the MCPs attached to every new session in a group.
+func handleGroupSetMCP(profile string, args []string) {
+	fs := flag.NewFlagSet("group set-mcp", flag.ExitOnError)
+	clearMCPs := fs.Bool("clear", false, "Remove the group's default MCPs (subgroups then inherit from the parent again)")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck group set-mcp <group> <mcp>... [options]")
+		fmt.Println("       agent-deck group set-mcp <group> --clear")
+		fmt.Println()
+		fmt.Println("Set the MCPs attached to every session created in a group. Subgroups")
+		fmt.Println("without their own set inherit the parent group's. Existing sessions")
+		fmt.Println("are not changed; use `mcp attach` for those.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck group set-mcp work memory exa sequential-thinking")
+		fmt.Println("  agent-deck group set-mcp work/scratch --clear")
+	}
+
+	args = reorderGroupArgs(args)
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+
+	name := fs.Arg(0)
+	mcps := fs.Args()
+	if len(mcps) > 0 {
+		mcps = mcps[1:]
+	}
+	if name == "" || (len(mcps) == 0 && !*clearMCPs) {
+		fs.Usage()
+		out.Error("group and at least one MCP (or --clear) are required", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if *clearMCPs && len(mcps) > 0 {
+		out.Error("--clear cannot be combined with MCP names", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if err := session.ValidateMCPNames(mcps); err != nil {
+		out.Error(err.Error(), ErrCodeMCPNotAvailable)
+		os.Exit(2)
+	}
+
+	storage, err := session.NewStorageWithProfile(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to initialize storage: %v", err), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	instances, groups, err := storage.LoadWithGroups()
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load sessions: %v", err), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	groupTree := session.NewGroupTreeWithGroups(instances, groups)
+
+	groupPath := normalizeGroupPath(name)
+	_, exists := groupTree.Groups[groupPath]
+	if !exists {
+		for path, g := range groupTree.Groups {
+			if strings.EqualFold(g.Name, name) {
+				groupPath = path
+				exists = true
+				break
+			}
+		}
+	}
+	if !exists {
+		out.Error(fmt.Sprintf("group '%s' not found", name), ErrCodeNotFound)
+		os.Exit(2)
+	}
+
+	groupTree.SetDefaultMCPsForGroup(groupPath, mcps)
+	if err := storage.SaveWithGroups(instances, groupTree); err != nil {
+		out.Error(fmt.Sprintf("failed to save: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	current := groupTree.Groups[groupPath].DefaultMCPs
+	data := map[string]interface{}{
+		"success":      true,
+		"path":         groupPath,
+		"default_mcps": nonNilStrings(current),
+	}
+	if *clearMCPs {
+		msg := fmt.Sprintf("Cleared default MCPs for group: %s", groupPath)
+		if inherited := session.GroupDefaultMCPs(groupTree, groupPath); len(inherited) > 0 {
+			origin := session.GroupMCPOrigin(groupTree, groupPath)
+			msg += fmt.Sprintf(" (now inherits %s from %s)", strings.Join(inherited, ", "), origin)
+			data["inherited_mcps"] = inherited
+			data["inherited_from"] = origin
+		}
+		out.Success(msg, data)
+		return
+	}
+	out.Success(fmt.Sprintf("Default MCPs for %s: %s", groupPath, strings.Join(current, ", ")), data)
+}
+
+// nonNilStrings keeps JSON output an array rather than null.
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// describeGroupMCPs renders a group's effective default MCPs for `group
+// show`, noting where an inherited set comes from.
+func describeGroupMCPs(tree *session.GroupTree, groupPath string) string {
+	mcps := session.GroupDefaultMCPs(tree, groupPath)
+	if len(mcps) == 0 {
+		return ""
+	}
+	s := strings.Join(mcps, ", ")
+	if origin := session.GroupMCPOrigin(tree, groupPath); origin != groupPath {
+		s += " (from " + origin + ")"
+	}
+	return s
+}
@@ -152,3 +152,39 @@ env_file = "broken
 		t.Errorf("broken config.toml must be loudly surfaced:\n%s", stdout)
 	}
 }
+
+func TestGroupShow_Readme(t *testing.T) {
+	home := t.TempDir()
+
+	if stdout, _, code := runAgentDeck(t, home, "group", "create", "work"); code != 0 {
+		t.Fatalf("group create failed: %s", stdout)
+	}
+	if stdout, _, code := runAgentDeck(t, home, "group", "update", "work", "--goal", "# Goal\nShip offline sync"); code != 0 {
+		t.Fatalf("group update --goal failed (exit %d): %s", code, stdout)
+	}
+
+	stdout, _, code := runAgentDeck(t, home, "group", "show", "work")
+	if code != 0 {
+		t.Fatalf("group show failed (exit %d): %s", code, stdout)
+	}
+	if !strings.Contains(stdout, "README:") || !strings.Contains(stdout, "  Ship offline sync") {
+		t.Errorf("expected README section, got:\n%s", stdout)
+	}
+
+	stdout, _, _ = runAgentDeck(t, home, "group", "show", "work", "--json")
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(stdout), &data); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+	}
+	if data["readme"] != "# Goal\nShip offline sync\n" {
+		t.Errorf("readme = %q", data["readme"])
+	}
+
+	if stdout, _, code := runAgentDeck(t, home, "group", "update", "work", "--clear-readme"); code != 0 {
+		t.Fatalf("group update --clear-readme failed (exit %d): %s", code, stdout)
+	}
+	stdout, _, _ = runAgentDeck(t, home, "group", "show", "work")
+	if strings.Contains(stdout, "README:") {
+		t.Errorf("README should be cleared, got:\n%s", stdout)
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"log/slog"
@@ -50,6 +51,9 @@ type hookPayload struct {
 	// false. A missing field must NOT be read as "fresh user turn" (which would
 	// reset the loop guard every Stop); resolveStopHookActive fails safe to true.
 	StopHookActive *bool `json:"stop_hook_active"`
+	// NotificationType is Gemini CLI's Notification payload kind;
+	// "ToolPermission" means a tool call is waiting for the user's approval.
+	NotificationType string `json:"notification_type,omitempty"`
 }
 
 // resolveStopHookActive fails safe (audit B8): an absent stop_hook_active is
@@ -132,6 +136,25 @@ func mapEventToStatus(event string) string {
 	}
 }
 
+// notificationStatus maps a Notification event: "waiting" when it announces
+// a prompt the user must answer — Claude's permission_prompt or
+// elicitation_dialog matcher, Gemini's ToolPermission notification — and no
+// status change for informational notifications.
+func notificationStatus(payload hookPayload) string {
+	if payload.NotificationType == "ToolPermission" {
+		return "waiting"
+	}
+	if payload.Matcher != nil {
+		var matcher string
+		if err := json.Unmarshal(payload.Matcher, &matcher); err == nil {
+			if matcher == "permission_prompt" || matcher == "elicitation_dialog" {
+				return "waiting"
+			}
+		}
+	}
+	return ""
+}
+
 // handleHookHandler processes a Claude Code hook event.
 // Reads JSON from stdin, maps the event to a status, and writes a status file.
 // Always exits 0 to avoid blocking Claude Code.
@@ -173,15 +196,8 @@ func handleHookHandler() {
 	// Map event to status
 	status := mapEventToStatus(payload.HookEventName)
 
-	// Special handling for Notification events: only map to "waiting" if
-	// the matcher indicates a permission prompt or elicitation dialog
-	if normalizeHookEventKey(payload.HookEventName) == "notification" && payload.Matcher != nil {
-		var matcher string
-		if err := json.Unmarshal(payload.Matcher, &matcher); err == nil {
-			if matcher == "permission_prompt" || matcher == "elicitation_dialog" {
-				status = "waiting"
-			}
-		}
+	if normalizeHookEventKey(payload.HookEventName) == "notification" {
+		status = notificationStatus(payload)
 	}
 
 	if status == "" {
@@ -368,6 +384,20 @@ func writeHookStatusWithScan(instanceID, status, sessionID, event string, scan d
 		return
 	}
 
+	// Keep a short per-session history for `session show`; it lives outside
+	// the hooks dir so the status watcher never sees it.
+	if err := session.AppendHookEvent(instanceID, session.HookEvent{
+		Timestamp:  time.Unix(statusFile.Timestamp, 0).UTC(),
+		Event:      event,
+		Status:     status,
+		DoneStatus: statusFile.DoneStatus,
+	}); err != nil {
+		hookHandlerLog.Debug("hook_timeline_append_failed",
+			slog.String("instance", instanceID),
+			slog.String("error", err.Error()),
+		)
+	}
+
 	// Clear sticky session mapping when the upstream session is explicitly ended.
 	if isTerminalHookEvent(event) {
 		session.ClearHookSessionAnchor(instanceID)
@@ -463,8 +493,9 @@ func cleanStaleHookFiles() {
 
 // handleHooks handles the "hooks" CLI subcommand for manual hook management.
 func handleHooks(args []string) {
+	const usage = "Usage: agent-deck hooks <install|uninstall|status|sync>"
 	if len(args) == 0 {
-		fmt.Fprintln(os.Stderr, "Usage: agent-deck hooks <install|uninstall|status>")
+		fmt.Fprintln(os.Stderr, usage)
 		os.Exit(1)
 	}
 
@@ -474,10 +505,12 @@ func handleHooks(args []string) {
 	case "uninstall":
 		handleHooksUninstall()
 	case "status":
-		handleHooksStatus()
+		handleHooksStatus(args[1:])
+	case "sync":
+		handleHooksSync(args[1:])
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown hooks subcommand: %s\n", args[0])
-		fmt.Fprintln(os.Stderr, "Usage: agent-deck hooks <install|uninstall|status>")
+		fmt.Fprintln(os.Stderr, usage)
 		os.Exit(1)
 	}
 }
@@ -511,18 +544,38 @@ func handleHooksUninstall() {
 	}
 }
 
-func handleHooksStatus() {
+func handleHooksStatus(args []string) {
+	fs := flag.NewFlagSet("hooks status", flag.ExitOnError)
+	allProfiles := fs.Bool("all-profiles", false, "Show the Claude config dir of every profile")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	targets := collectClaudeHooksTargets(*allProfiles)
+	if *jsonOutput {
+		out, _ := json.MarshalIndent(map[string]any{
+			"hooks_version": session.ClaudeHooksVersion,
+			"targets":       targets,
+		}, "", "  ")
+		fmt.Println(string(out))
+		return
+	}
+
 	// Clean up stale hook files while checking status
 	cleanStaleHookFiles()
 
-	configDir := getClaudeConfigDirForHooks()
-	installed := session.CheckClaudeHooksInstalled(configDir)
-
-	if installed {
-		fmt.Println("Status: INSTALLED")
-		fmt.Printf("Config: %s/settings.json\n", configDir)
-	} else {
-		fmt.Println("Status: NOT INSTALLED")
+	needsSync := false
+	for i, t := range targets {
+		if i > 0 {
+			fmt.Println()
+		}
+		printClaudeHooksTarget(t, *allProfiles)
+		needsSync = needsSync || t.NeedsSync()
+	}
+	if needsSync {
+		fmt.Println("Run 'agent-deck hooks sync' to re-install outdated hooks.")
+	} else if len(targets) == 1 && targets[0].State == session.ClaudeHooksNotInstalled {
 		fmt.Println("Run 'agent-deck hooks install' to install.")
 	}
 
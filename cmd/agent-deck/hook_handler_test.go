@@ -51,6 +51,31 @@ func TestMapEventToStatus(t *testing.T) {
 	}
 }
 
+func TestNotificationStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		expect  string
+	}{
+		{"claude permission prompt", `{"hook_event_name":"Notification","matcher":"permission_prompt"}`, "waiting"},
+		{"claude elicitation", `{"hook_event_name":"Notification","matcher":"elicitation_dialog"}`, "waiting"},
+		{"claude idle reminder", `{"hook_event_name":"Notification","matcher":"idle_prompt"}`, ""},
+		{"gemini tool permission", `{"hook_event_name":"Notification","notification_type":"ToolPermission","message":"Allow run_shell_command?"}`, "waiting"},
+		{"informational", `{"hook_event_name":"Notification","message":"hello"}`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p hookPayload
+			if err := json.Unmarshal([]byte(tt.payload), &p); err != nil {
+				t.Fatal(err)
+			}
+			if got := notificationStatus(p); got != tt.expect {
+				t.Errorf("notificationStatus = %q, want %q", got, tt.expect)
+			}
+		})
+	}
+}
+
 func TestHookStatusFile_JSON(t *testing.T) {
 	sf := hookStatusFile{
 		Status:    "running",
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// claudeHooksTarget is one Claude config dir and the agent-deck profiles
+// that resolve to it. Profiles commonly share ~/.claude, so dirs are
+// inspected once each.
+type claudeHooksTarget struct {
+	session.ClaudeHooksStatus
+	Profiles []string `json:"profiles"`
+}
+
+// collectClaudeHooksTargets returns the config dirs to inspect: the active
+// one, or with allProfiles every agent-deck profile's plus every
+// [profiles.<name>.claude] config_dir.
+func collectClaudeHooksTargets(allProfiles bool) []claudeHooksTarget {
+	if !allProfiles {
+		configDir := getClaudeConfigDirForHooks()
+		return []claudeHooksTarget{{
+			ClaudeHooksStatus: session.InspectClaudeHooks(configDir),
+			Profiles:          []string{session.GetEffectiveProfile("")},
+		}}
+	}
+
+	names := map[string]bool{session.DefaultProfile: true}
+	if profiles, err := session.ListProfiles(); err == nil {
+		for _, p := range profiles {
+			names[p] = true
+		}
+	}
+	if cfg, _ := session.LoadUserConfig(); cfg != nil {
+		for p := range cfg.Profiles {
+			names[p] = true
+		}
+	}
+	sorted := make([]string, 0, len(names))
+	for p := range names {
+		sorted = append(sorted, p)
+	}
+	sort.Strings(sorted)
+
+	var targets []claudeHooksTarget
+	index := map[string]int{}
+	for _, p := range sorted {
+		dir := session.GetClaudeConfigDirForProfile(p)
+		if i, ok := index[dir]; ok {
+			targets[i].Profiles = append(targets[i].Profiles, p)
+			continue
+		}
+		index[dir] = len(targets)
+		targets = append(targets, claudeHooksTarget{
+			ClaudeHooksStatus: session.InspectClaudeHooks(dir),
+			Profiles:          []string{p},
+		})
+	}
+	return targets
+}
+
+// claudeHooksStateLabel renders a state for human output.
+func claudeHooksStateLabel(state session.ClaudeHooksState) string {
+	switch state {
+	case session.ClaudeHooksCurrent:
+		return "INSTALLED"
+	case session.ClaudeHooksOutdated:
+		return "OUTDATED"
+	case session.ClaudeHooksNewer:
+		return "NEWER"
+	}
+	return "NOT INSTALLED"
+}
+
+// printClaudeHooksTarget prints one target's status block.
+func printClaudeHooksTarget(t claudeHooksTarget, showProfiles bool) {
+	label := claudeHooksStateLabel(t.State)
+	if t.Stamp != nil {
+		label += fmt.Sprintf(" (hooks v%d)", t.Stamp.Version)
+	}
+	fmt.Printf("Status: %s\n", label)
+	fmt.Printf("Config: %s/settings.json\n", t.ConfigDir)
+	if showProfiles {
+		fmt.Printf("Profiles: %s\n", strings.Join(t.Profiles, ", "))
+	}
+	for _, r := range t.Reasons {
+		if t.State == session.ClaudeHooksNotInstalled {
+			break
+		}
+		fmt.Printf("  - %s\n", r)
+	}
+}
+
+func handleHooksSync(args []string) {
+	fs := flag.NewFlagSet("hooks sync", flag.ExitOnError)
+	allProfiles := fs.Bool("all-profiles", false, "Check the Claude config dir of every profile")
+	yes := fs.Bool("yes", false, "Re-install without prompting")
+	fs.BoolVar(yes, "y", false, "Short for --yes")
+	jsonOutput := fs.Bool("json", false, "Output as JSON (implies no prompt; combine with --yes to apply)")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck hooks sync [options]")
+		fmt.Println()
+		fmt.Println("Re-install agent-deck's Claude Code hooks wherever they were written by an")
+		fmt.Println("older or newer agent-deck (stale binary path, flags, events or version).")
+		fmt.Println("Config dirs without agent-deck hooks are never touched.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	targets := collectClaudeHooksTargets(*allProfiles)
+	var stale []claudeHooksTarget
+	for _, t := range targets {
+		if t.NeedsSync() {
+			stale = append(stale, t)
+		}
+	}
+
+	apply := *yes
+	if !*jsonOutput && len(stale) > 0 {
+		fmt.Printf("%d Claude hook install(s) need a re-sync to hooks v%d:\n\n", len(stale), session.ClaudeHooksVersion)
+		for _, t := range stale {
+			printClaudeHooksTarget(t, *allProfiles)
+			fmt.Println()
+		}
+		if !apply {
+			if stdinStdoutIsTerminal() {
+				drainStdin()
+				fmt.Print("Re-install these hooks now? [Y/n] ")
+				response, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+				response = strings.TrimSpace(response)
+				apply = response == "" || response == "y" || response == "Y"
+			} else {
+				fmt.Println("Run 'agent-deck hooks sync --yes' to re-install.")
+			}
+		}
+	}
+
+	type syncResult struct {
+		ConfigDir string `json:"config_dir"`
+		Synced    bool   `json:"synced"`
+		Error     string `json:"error,omitempty"`
+	}
+	var results []syncResult
+	failed := false
+	if apply {
+		for _, t := range stale {
+			r := syncResult{ConfigDir: t.ConfigDir}
+			if _, err := session.InjectClaudeHooks(t.ConfigDir); err != nil {
+				r.Error = err.Error()
+				failed = true
+			} else {
+				r.Synced = true
+			}
+			results = append(results, r)
+		}
+	}
+
+	if *jsonOutput {
+		out, _ := json.MarshalIndent(map[string]any{
+			"hooks_version": session.ClaudeHooksVersion,
+			"targets":       targets,
+			"applied":       apply,
+			"results":       results,
+		}, "", "  ")
+		fmt.Println(string(out))
+	} else if len(stale) == 0 {
+		fmt.Printf("Claude hooks are up to date (hooks v%d).\n", session.ClaudeHooksVersion)
+	} else if apply {
+		for _, r := range results {
+			if r.Error != "" {
+				fmt.Printf("✗ %s: %s\n", r.ConfigDir, r.Error)
+			} else {
+				fmt.Printf("✓ Re-synced %s/settings.json\n", r.ConfigDir)
+			}
+		}
+	} else if stdinStdoutIsTerminal() {
+		fmt.Println("Skipped.")
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// syncHooksAfterUpdate runs the freshly installed binary's `hooks sync` so
+// hook installs across all profiles follow the new version. It must be the
+// new binary: this process still carries the old hook set.
+func syncHooksAfterUpdate() {
+	bin, err := exec.LookPath("agent-deck")
+	if err != nil {
+		if bin, err = os.Executable(); err != nil {
+			return
+		}
+	}
+	fmt.Println()
+	cmd := exec.Command(bin, "hooks", "sync", "--all-profiles")
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		fmt.Printf("Warning: hook re-sync did not complete: %v\n", err)
+		fmt.Println("  Run 'agent-deck hooks sync --all-profiles' after restarting.")
+	}
+}
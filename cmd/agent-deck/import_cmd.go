@@ -0,0 +1,145 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleImport implements `agent-deck import tmuxinator|tmuxp <config>`:
+// it turns a tmux workflow-manager project into a group with one session per
+// window, so people migrating keep their layout.
+func handleImport(profile string, args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	group := fs.String("group", "", "Group to create the sessions in (default: the project name)")
+	groupShort := fs.String("g", "", "Group to create the sessions in (short)")
+	dryRun := fs.Bool("dry-run", false, "Show what would be created without saving")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck import <tmuxinator|tmuxp> <file|project> [options]")
+		fmt.Println()
+		fmt.Println("Create a group and one session per window from a tmuxinator or tmuxp")
+		fmt.Println("config. A window runs its first pane's command (Claude, Codex and other")
+		fmt.Println("tools are detected from it); extra panes, layouts and project hooks are")
+		fmt.Println("reported but not imported. A project name is looked up in the tool's")
+		fmt.Println("config directory. Sessions are created, not started; windows whose title")
+		fmt.Println("already exists in the group are kept.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck import tmuxinator ~/.config/tmuxinator/app.yml")
+		fmt.Println("  agent-deck import tmuxinator app --dry-run")
+		fmt.Println("  agent-deck import tmuxp ~/.tmuxp/dev.yaml -g work/dev")
+	}
+
+	if len(args) == 0 || args[0] == "help" || args[0] == "--help" || args[0] == "-h" {
+		fs.Usage()
+		return
+	}
+	format := args[0]
+	if err := fs.Parse(normalizeArgs(fs, args[1:])); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+	if format != session.TmuxImportTmuxinator && format != session.TmuxImportTmuxp {
+		out.Error(fmt.Sprintf("unknown import format %q (want tmuxinator or tmuxp)", format), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	path, err := session.FindTmuxImportConfig(format, fs.Arg(0))
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(2)
+	}
+	imp, err := session.LoadTmuxImport(format, path)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	groupPath := imp.GroupPath(mergeFlags(*group, *groupShort))
+
+	storage, instances, groups, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	res := imp.Materialize(instances, groupPath)
+
+	if !*dryRun && len(res.Created) > 0 {
+		instances = append(instances, res.Created...)
+		groupTree := session.NewGroupTreeWithGroups(instances, groups)
+		if cfg, cfgErr := session.LoadUserConfig(); cfgErr == nil && cfg != nil {
+			groupTree.DefaultMaxConcurrent = cfg.GroupDefaults.MaxConcurrent
+		}
+		groupTree.CreateGroupPath(groupPath)
+		if err := storage.SaveWithGroups(instances, groupTree); err != nil {
+			out.Error(fmt.Sprintf("failed to save sessions: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	}
+
+	for _, w := range res.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+	out.Print(formatImportResult(imp, res, groupPath, *dryRun), importResultJSON(imp, res, groupPath, *dryRun))
+	if len(res.Failed) > 0 {
+		os.Exit(1)
+	}
+}
+
+func formatImportResult(imp *session.TmuxImport, res *session.ManifestResult, groupPath string, dryRun bool) string {
+	var b strings.Builder
+	verb := "Imported"
+	created := "created"
+	if dryRun {
+		verb, created = "Would import", "would create"
+	}
+	fmt.Fprintf(&b, "%s %s project %q from %s into group %s\n", verb, imp.Format, imp.Name, imp.Path, groupPath)
+	for _, inst := range res.Created {
+		fmt.Fprintf(&b, "  %s %s  %s  (%s)  %s\n", successSymbol, created, inst.Title, inst.GroupPath, commandLabel(inst))
+	}
+	for _, inst := range res.Existing {
+		fmt.Fprintf(&b, "  %s exists   %s  (%s)\n", bulletSymbol, inst.Title, inst.GroupPath)
+	}
+	for _, title := range sortedKeys(res.Failed) {
+		fmt.Fprintf(&b, "  %s failed   %s: %v\n", errorSymbol, title, res.Failed[title])
+	}
+	if len(res.Created) > 0 && !dryRun {
+		fmt.Fprintf(&b, "\nStart them with: agent-deck session start --group %s\n", groupPath)
+	}
+	return b.String()
+}
+
+func commandLabel(inst *session.Instance) string {
+	if inst.Command == "" {
+		return inst.Tool
+	}
+	return inst.Tool + ": " + inst.Command
+}
+
+func importResultJSON(imp *session.TmuxImport, res *session.ManifestResult, groupPath string, dryRun bool) map[string]interface{} {
+	return map[string]interface{}{
+		"success":  len(res.Failed) == 0,
+		"format":   imp.Format,
+		"config":   imp.Path,
+		"project":  imp.Name,
+		"group":    groupPath,
+		"dry_run":  dryRun,
+		"created":  instanceListJSON(res.Created),
+		"existing": instanceListJSON(res.Existing),
+		"failed":   failedMapJSON(res.Failed),
+		"warnings": res.Warnings,
+	}
+}
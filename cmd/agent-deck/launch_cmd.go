@@ -46,6 +46,10 @@ func handleLaunch(profile string, args []string) {
 	messageShort := fs.String("m", "", "Initial message to send (short)")
 	messageFile := fs.String("message-file", "", "Read the initial message from a file ('-' for stdin); avoids shell quoting of long prompts")
 	noWait := fs.Bool("no-wait", false, "Don't wait for agent to be ready before sending message")
+	waitFor := fs.String("wait-for", "", "Block until the agent is done: 'waiting' (turn finished) or 'idle' (finished and settled)")
+	waitTimeout := fs.Duration("timeout", defaultLaunchWaitTimeout, "Maximum time --wait-for blocks before failing")
+	captureOutput := fs.String("capture-output", "", "With --wait-for, write the agent's final response to this file ('-' for stdout)")
+	groupContext := fs.Bool("group-context", false, "Prepend the target group's README (see 'group update --goal') to the initial message")
 	assertDone := fs.Bool("assert-done", false, "Append a completion-sentinel instruction to the message (default on for -c claude)")
 	noAssertDone := fs.Bool("no-assert-done", false, "Disable the completion-sentinel instruction")
 	parent := fs.String("parent", "", "Parent session (creates sub-session; group is cwd-derived by default — auto-inherits the parent's group for git worktree children or with --inherit-group)")
@@ -72,8 +76,8 @@ func handleLaunch(profile string, args []string) {
 	quietShort := fs.Bool("q", false, "Minimal output (short)")
 
 	// Worktree flags
-	worktreeBranch := fs.String("w", "", "Create session in git worktree for branch")
-	worktreeBranchLong := fs.String("worktree", "", "Create session in git worktree for branch")
+	worktreeBranch := fs.String("w", "", "Create session in git worktree for branch ('auto' names a new branch from the -m prompt)")
+	worktreeBranchLong := fs.String("worktree", "", "Create session in git worktree for branch ('auto' names a new branch from the -m prompt)")
 	newBranch := fs.Bool("b", false, "Create new branch (use with --worktree)")
 	newBranchLong := fs.Bool("new-branch", false, "Create new branch")
 	worktreeLocation := fs.String("location", "", "Worktree location: sibling, subdirectory, or custom path")
@@ -120,6 +124,7 @@ func handleLaunch(profile string, args []string) {
 	// Resume session flag
 	resumeSession := fs.String("resume-session", "", "Claude session ID to resume")
 	modelID := fs.String("model", "", "Model ID/version to use for this session (claude, codex, gemini, opencode)")
+	preset := fs.String("preset", "", "Start preset: light, standard, heavy, or a [presets.<name>] (see 'session presets')")
 
 	// Socket isolation (v1.7.50+, issue #687). Same semantics as
 	// `agent-deck add --tmux-socket`: overrides `[tmux].socket_name` for
@@ -127,8 +132,13 @@ func handleLaunch(profile string, args []string) {
 	tmuxSocket := fs.String("tmux-socket", "", "tmux -L socket name for this session (overrides [tmux].socket_name)")
 
 	// Issue #1143: auto-stop dormant child sessions.
+	checkpoint := fs.String("checkpoint", "", "Automatic working-tree checkpoints: 'waiting' (each finished turn), an interval like 15m, or both ('waiting,15m')")
 	idleTimeout := fs.String("idle-timeout", "", "Auto-stop session after this duration of no tmux output (Go duration: 30m, 1h, 24h). 0 or unset = disabled")
 
+	// Conductor dispatch capacity: when a conductor child is deferred by
+	// [conductor.dispatch] limits, higher priorities drain first.
+	priority := fs.Int("priority", 0, "Dispatch priority when a conductor launch is deferred by [conductor.dispatch] limits (higher starts first)")
+
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck launch [path] [options]")
 		fmt.Println()
@@ -150,11 +160,14 @@ func handleLaunch(profile string, args []string) {
 		fmt.Println("  agent-deck launch . -c claude --mcp memory -m \"Research topic X\"")
 		fmt.Println("  agent-deck launch . -c claude --channel plugin:telegram@user/repo -m \"Listen for messages\"")
 		fmt.Println("  agent-deck launch . -c claude -m \"Fix bug\" --no-wait")
+		fmt.Println("  agent-deck launch . -c claude -m \"Run the tests\" --wait-for waiting --timeout 10m --capture-output result.txt")
 		fmt.Println("  agent-deck launch . -c claude --message-file task.md   # long prompt from file, no shell quoting")
+		fmt.Println("  agent-deck launch . -g mobile -c claude --group-context -m \"Take the sync ticket\"")
 		fmt.Println("  agent-deck launch . -c claude -m \"Refactor X\"   # auto-appends completion sentinel (see session children)")
 		fmt.Println("  agent-deck launch . -c \"codex --dangerously-bypass-approvals-and-sandbox\"")
 		fmt.Println("  agent-deck launch . -g ard --no-parent -c claude -m \"Run review\"")
 		fmt.Println("  agent-deck launch . -c claude -w feature/new -b -m \"Start work\"")
+		fmt.Println("  agent-deck launch . -c claude -w auto -m \"add dark mode toggle\"   # new branch named from the prompt")
 	}
 
 	// Reorder args: move path to end so flags are parsed correctly
@@ -167,6 +180,19 @@ func handleLaunch(profile string, args []string) {
 	quietMode := *quiet || *quietShort
 	out := NewCLIOutput(*jsonOutput, quietMode)
 
+	if err := validateLaunchWaitFor(*waitFor); err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if *captureOutput != "" && *waitFor == "" {
+		out.Error("--capture-output requires --wait-for", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if *waitFor != "" && *waitTimeout <= 0 {
+		out.Error("--timeout must be positive", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
 	// Resolve path
 	path, err := resolveLaunchPath(strings.Trim(fs.Arg(0), "'\""), mergeFlags(*group, *groupShort), profile)
 	if err != nil {
@@ -201,6 +227,9 @@ func handleLaunch(profile string, args []string) {
 		out.Error(err.Error(), ErrCodeInvalidOperation)
 		os.Exit(1)
 	}
+	// -w auto names the branch from the prompt as the user wrote it, before
+	// the sentinel and group context are appended.
+	branchPrompt := initialMessage
 
 	// --assert-done: append the completion-sentinel instruction so the child
 	// reliably reports back via the ledger / parent inbox. Default-on for
@@ -234,6 +263,11 @@ func handleLaunch(profile string, args []string) {
 
 	// Handle worktree creation
 	var worktreePath, worktreeRepoRoot, worktreeType string
+	autoBranch := wtBranch == "auto"
+	if autoBranch && strings.TrimSpace(branchPrompt) == "" {
+		out.Error("-w auto needs a prompt (-m or --message-file) to name the branch", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
 	if wtBranch != "" {
 		backend, err := detectAndCreateBackend(path)
 		if err != nil {
@@ -245,13 +279,30 @@ func handleLaunch(profile string, args []string) {
 
 		// Apply configured branch prefix before validation/existence checks
 		wtSettings := session.GetWorktreeSettings()
-		wtBranch = wtSettings.ApplyBranchPrefix(wtBranch)
+		if autoBranch {
+			wtBranch = wtSettings.AutoPrefix() + git.BranchSlugFromPrompt(branchPrompt)
+		} else {
+			wtBranch = wtSettings.ApplyBranchPrefix(wtBranch)
+		}
 
 		if err := git.ValidateBranchName(wtBranch); err != nil {
 			out.Error(fmt.Sprintf("invalid branch name: %v", err), ErrCodeInvalidOperation)
 			os.Exit(1)
 		}
 
+		// A prompt-named branch is always new: step past names that are
+		// already a branch or checked out in a worktree.
+		if autoBranch {
+			wtBranch = git.UniqueBranchName(wtBranch, func(name string) bool {
+				if backend.BranchExists(name) {
+					return true
+				}
+				existing, err := backend.GetWorktreeForBranch(name)
+				return err == nil && existing != ""
+			})
+			createNewBranch = true
+		}
+
 		branchExists := backend.BranchExists(wtBranch)
 		if createNewBranch && branchExists {
 			out.Error(fmt.Sprintf("branch '%s' already exists (remove -b flag to use existing branch)", wtBranch), ErrCodeInvalidOperation)
@@ -444,6 +495,14 @@ func handleLaunch(profile string, args []string) {
 		newInstance.Wrapper = sessionWrapperResolved
 	}
 
+	// A start preset sets model, MCPs and tool flags; an explicit --model
+	// below still wins over the preset's model.
+	mcpFlags, presetSetsMCPs, err := applyCLIPreset(newInstance, *preset, mcpFlags)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
 	selectedModelID := strings.TrimSpace(*modelID)
 	if selectedModelID != "" {
 		if err := applyCLIModelOverride(newInstance, selectedModelID); err != nil {
@@ -467,6 +526,10 @@ func handleLaunch(profile string, args []string) {
 	} else {
 		newInstance.IdleTimeoutSecs = idleSecs
 	}
+	if err := newInstance.SetCheckpoint(*checkpoint); err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
 
 	if *resumeSession != "" {
 		newInstance.ClaudeSessionID = *resumeSession
@@ -511,6 +574,17 @@ func handleLaunch(profile string, args []string) {
 		os.Exit(1)
 	}
 
+	if autoBranch {
+		if err := session.RecordAutoBranch(session.AutoBranchRecord{
+			Branch:    wtBranch,
+			RepoRoot:  worktreeRepoRoot,
+			Prompt:    strings.TrimSpace(branchPrompt),
+			SessionID: newInstance.ID,
+		}); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record branch name for prompt: %v\n", err)
+		}
+	}
+
 	// Attach MCPs if specified
 	if len(mcpFlags) > 0 {
 		availableMCPs := session.GetAvailableMCPs()
@@ -526,6 +600,25 @@ func handleLaunch(profile string, args []string) {
 		}
 	}
 
+	// Attach the group's default MCPs on top of any --mcp ones, unless a
+	// preset chose the MCP set.
+	if !presetSetsMCPs {
+		groupMCPs, err := session.ApplyGroupDefaultMCPs(newInstance, groupTree)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to attach group default MCPs: %v\n", err)
+		}
+		mcpFlags = append(mcpFlags, groupMCPs...)
+	}
+
+	// --group-context: hand the new session the group's shared goal up front.
+	if *groupContext && newInstance.GroupPath != "" {
+		readme, err := session.ReadGroupReadme(profile, newInstance.GroupPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to read group README: %v\n", err)
+		}
+		initialMessage = session.WithGroupContext(newInstance.GroupPath, readme, initialMessage)
+	}
+
 	// v1.9.1 group concurrency cap: if the target group is at its
 	// max_concurrent cap, mark this session queued instead of starting.
 	// Groups with max_concurrent<=0 (legacy default) skip this check.
@@ -550,10 +643,45 @@ func handleLaunch(profile string, args []string) {
 			"max_concurrent": maxC,
 		}
 		addModelInfoJSON(queuedJSON, newInstance.LaunchModelInfo())
+		if *waitFor != "" {
+			out.ErrorWithData(fmt.Sprintf("session %s was queued (group at cap %d); --wait-for needs a started session", newInstance.Title, maxC), ErrCodeInvalidOperation, queuedJSON)
+			os.Exit(1)
+		}
 		out.Success(fmt.Sprintf("Queued session: %s (group at cap %d)", newInstance.Title, maxC), queuedJSON)
 		return
 	}
 
+	// Conductor dispatch capacity: a conductor fanning out work is held to
+	// the [conductor.dispatch] per-tool active and token limits. Over a
+	// limit, the child is queued with its priority and initial message and
+	// started later by drainConductorDispatch.
+	if dispatchSettings := launchCfg.Conductor.Dispatch; dispatchSettings.Enabled() && session.IsConductorDispatch(newInstance, instances) {
+		decision := checkConductorDispatch(storage, dispatchSettings, instances, newInstance.Tool)
+		if !decision.Allowed {
+			newInstance.DeferDispatch(*priority, initialMessage, decision.Reason)
+			if err := storage.InsertSessionAndVerify(newInstance, tree); err != nil {
+				out.Error(fmt.Sprintf("failed to save queued state: %v", err), ErrCodeInvalidOperation)
+				os.Exit(1)
+			}
+			queuedJSON := map[string]interface{}{
+				"success":  true,
+				"id":       newInstance.ID,
+				"title":    newInstance.Title,
+				"status":   "queued",
+				"group":    newInstance.GroupPath,
+				"priority": *priority,
+				"dispatch": decision,
+			}
+			addModelInfoJSON(queuedJSON, newInstance.LaunchModelInfo())
+			if *waitFor != "" {
+				out.ErrorWithData(fmt.Sprintf("session %s was queued (%s); --wait-for needs a started session", newInstance.Title, decision.Reason), ErrCodeInvalidOperation, queuedJSON)
+				os.Exit(1)
+			}
+			out.Success(fmt.Sprintf("Queued session: %s (%s)", newInstance.Title, decision.Reason), queuedJSON)
+			return
+		}
+	}
+
 	// Issue #955: strip TELEGRAM_STATE_DIR from the agent-deck CLI
 	// process env before the tmux server inherits it on the first
 	// `new-session`. No-op for conductors and explicit telegram
@@ -575,6 +703,7 @@ func handleLaunch(profile string, args []string) {
 	throttle.Acquire()
 	defer throttle.Release()
 
+	sentAt := time.Now()
 	if initialMessage != "" && !*noWait {
 		if err := newInstance.StartWithMessage(initialMessage); err != nil {
 			out.Error(fmt.Sprintf("failed to start session: %v", err), ErrCodeInvalidOperation)
@@ -635,6 +764,10 @@ func handleLaunch(profile string, args []string) {
 		}
 	}
 
+	if initialMessage != "" {
+		session.RecordDispatchReceipt(storage.GetDB(), newInstance, instances, initialMessage, time.Now())
+	}
+
 	// Build output. v1.9.x issue #1031: surface the new session ID
 	// under an explicit `session_id` key so callers (conductor fleet
 	// spawn loops, shell scripts) don't have to fall back to diffing
@@ -668,16 +801,25 @@ func handleLaunch(profile string, args []string) {
 	if len(mcpFlags) > 0 {
 		jsonData["mcps"] = mcpFlags
 	}
+	if newInstance.Preset != "" {
+		jsonData["preset"] = newInstance.Preset
+	}
 	if parentInstance != nil {
 		jsonData["parent_id"] = parentInstance.ID
 	}
 	if worktreePath != "" {
 		jsonData["worktree_path"] = worktreePath
 		jsonData["worktree_branch"] = wtBranch
+		if autoBranch {
+			jsonData["worktree_branch_auto"] = true
+		}
 	}
 	addModelInfoJSON(jsonData, newInstance.LaunchModelInfo())
 
 	msg := fmt.Sprintf("Launched session: %s", newInstance.Title)
+	if autoBranch {
+		msg += fmt.Sprintf(" on new branch %s", wtBranch)
+	}
 	if initialMessage != "" {
 		if *noWait {
 			msg += " (message sent with --no-wait)"
@@ -685,7 +827,63 @@ func handleLaunch(profile string, args []string) {
 			msg += " (message sent)"
 		}
 	}
-	out.Success(msg, jsonData)
+	if *waitFor == "" {
+		out.Success(msg, jsonData)
+		return
+	}
+
+	// --wait-for: block until the agent is done, then report once so a CI
+	// step gets a single result (and a single JSON object).
+	if !*jsonOutput && !quietMode {
+		fmt.Fprintf(os.Stderr, "%s; waiting for %s (timeout %s)...\n", msg, *waitFor, *waitTimeout)
+	}
+	tmuxSess := newInstance.GetTmuxSession()
+	if tmuxSess == nil {
+		out.ErrorWithData("session has no tmux session to wait on", ErrCodeInvalidOperation, jsonData)
+		os.Exit(1)
+	}
+	waitStart := time.Now()
+	finalStatus, err := waitForLaunchState(tmuxSess, *waitFor, initialMessage != "", *waitTimeout)
+	jsonData["wait_for"] = *waitFor
+	jsonData["waited_seconds"] = int(time.Since(waitStart).Seconds())
+	if err != nil {
+		out.ErrorWithData(fmt.Sprintf("timed out waiting for '%s': %v", newInstance.Title, err), ErrCodeWaitTimeout, jsonData)
+		os.Exit(1)
+	}
+	jsonData["final_status"] = finalStatus
+
+	if *captureOutput != "" {
+		// Same refresh as `session send --wait`: the Claude session ID may
+		// only have been detected while the agent was working.
+		if session.IsClaudeCompatible(newInstance.Tool) {
+			if freshID := newInstance.GetSessionIDFromTmux(); freshID != "" {
+				newInstance.ClaudeSessionID = freshID
+				newInstance.ClaudeDetectedAt = time.Now()
+			}
+		}
+		var response *session.ResponseOutput
+		if initialMessage != "" {
+			response, err = waitForFreshOutput(newInstance, sentAt, instances)
+		} else {
+			response, err = newInstance.GetLastResponseBestEffort()
+		}
+		if err == nil {
+			err = writeLaunchCapture(*captureOutput, response.Content)
+		}
+		if err != nil {
+			out.ErrorWithData(fmt.Sprintf("failed to capture output: %v", err), ErrCodeInvalidOperation, jsonData)
+			os.Exit(1)
+		}
+		if *captureOutput != "-" {
+			jsonData["output_file"] = *captureOutput
+		}
+	}
+
+	if finalStatus == "inactive" || finalStatus == "error" {
+		out.ErrorWithData(fmt.Sprintf("session '%s' exited before finishing (status %s)", newInstance.Title, finalStatus), ErrCodeInvalidOperation, jsonData)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("%s; agent %s after %s", msg, finalStatus, time.Since(waitStart).Round(time.Second)), jsonData)
 }
 
 // resolveLaunchPath resolves the project path for `agent-deck launch`.
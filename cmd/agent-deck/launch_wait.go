@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Targets for `launch --wait-for`.
+//   - waiting: the agent finished its turn and is waiting for input.
+//   - idle: the same, and it stayed that way for a settle window, so an
+//     agent that pauses between steps isn't mistaken for one that's done.
+const (
+	launchWaitWaiting = "waiting"
+	launchWaitIdle    = "idle"
+)
+
+// defaultLaunchWaitTimeout bounds `launch --wait-for` when --timeout is unset.
+const defaultLaunchWaitTimeout = 10 * time.Minute
+
+// launchWaitConfig holds tunable parameters for waitForLaunchState.
+type launchWaitConfig struct {
+	pollInterval time.Duration
+	// startGrace is how long a stopped status is ignored after the initial
+	// message was sent while the agent has not been seen working yet: the
+	// prompt may still be on its way in.
+	startGrace time.Duration
+	// settlePolls is how many consecutive stopped polls --wait-for idle needs.
+	settlePolls int
+}
+
+// launchWaitTestConfig, when non-nil, overrides the default timing. Only set
+// from tests.
+var launchWaitTestConfig *launchWaitConfig
+
+func validateLaunchWaitFor(target string) error {
+	switch target {
+	case "", launchWaitWaiting, launchWaitIdle:
+		return nil
+	}
+	return fmt.Errorf("--wait-for must be %q or %q, got %q", launchWaitWaiting, launchWaitIdle, target)
+}
+
+// waitForLaunchState polls a freshly launched session until it reaches
+// target. It returns the last status seen: "waiting" or "idle" when the
+// agent is done, "inactive" or "error" when the session died on the way
+// (not an error here; the caller decides the exit code), and an error only
+// on timeout.
+func waitForLaunchState(checker statusChecker, target string, sentMessage bool, timeout time.Duration) (string, error) {
+	cfg := launchWaitConfig{pollInterval: 2 * time.Second, startGrace: 30 * time.Second, settlePolls: 5}
+	if launchWaitTestConfig != nil {
+		cfg = *launchWaitTestConfig
+	}
+	need := 1
+	if target == launchWaitIdle {
+		need = cfg.settlePolls
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	start := time.Now()
+
+	seenActive := false
+	stopped := 0
+	consecutiveErrors := 0
+	const maxConsecutiveErrors = 5
+	for {
+		status, err := checker.GetStatus()
+		switch {
+		case err != nil:
+			consecutiveErrors++
+			if consecutiveErrors >= maxConsecutiveErrors {
+				return "error", nil // Session likely died
+			}
+		case status == "inactive":
+			return status, nil
+		case status == "active":
+			consecutiveErrors = 0
+			seenActive = true
+			stopped = 0
+		default:
+			consecutiveErrors = 0
+			if sentMessage && !seenActive && time.Since(start) < cfg.startGrace {
+				break
+			}
+			stopped++
+			if stopped >= need {
+				return status, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("agent not %s after %s", target, timeout)
+		case <-time.After(cfg.pollInterval):
+		}
+	}
+}
+
+// writeLaunchCapture writes the agent's final output for --capture-output.
+// "-" writes to stdout.
+func writeLaunchCapture(path, content string) error {
+	if content != "" && content[len(content)-1] != '\n' {
+		content += "\n"
+	}
+	if path == "-" {
+		_, err := os.Stdout.WriteString(content)
+		return err
+	}
+	return os.WriteFile(path, []byte(content), 0o644)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func fastLaunchWait(t *testing.T, startGrace time.Duration) {
+	t.Helper()
+	launchWaitTestConfig = &launchWaitConfig{pollInterval: time.Millisecond, startGrace: startGrace, settlePolls: 3}
+	t.Cleanup(func() { launchWaitTestConfig = nil })
+}
+
+func TestWaitForLaunchState_WaitingReturnsAfterAgentWorked(t *testing.T) {
+	fastLaunchWait(t, time.Minute)
+	// The first "waiting" is the prompt not yet picked up; it must not end the wait.
+	mock := &mockStatusChecker{statuses: []string{"waiting", "active", "active", "waiting"}}
+	status, err := waitForLaunchState(mock, launchWaitWaiting, true, 5*time.Second)
+	if err != nil || status != "waiting" {
+		t.Fatalf("waitForLaunchState = %q, %v", status, err)
+	}
+	if got := mock.idx.Load(); got != 4 {
+		t.Fatalf("polled %d times, want 4", got)
+	}
+}
+
+func TestWaitForLaunchState_StartGraceExpires(t *testing.T) {
+	fastLaunchWait(t, 0)
+	// An agent that finished between polls is never seen active.
+	mock := &mockStatusChecker{statuses: []string{"waiting"}}
+	if status, err := waitForLaunchState(mock, launchWaitWaiting, true, 5*time.Second); err != nil || status != "waiting" {
+		t.Fatalf("waitForLaunchState = %q, %v", status, err)
+	}
+}
+
+func TestWaitForLaunchState_IdleNeedsSettledStop(t *testing.T) {
+	fastLaunchWait(t, time.Minute)
+	mock := &mockStatusChecker{statuses: []string{"active", "waiting", "waiting", "active", "waiting", "idle", "idle"}}
+	status, err := waitForLaunchState(mock, launchWaitIdle, true, 5*time.Second)
+	if err != nil || status != "idle" {
+		t.Fatalf("waitForLaunchState = %q, %v", status, err)
+	}
+	if got := mock.idx.Load(); got != 7 {
+		t.Fatalf("polled %d times, want 7 (the pause between steps must reset the settle count)", got)
+	}
+}
+
+func TestWaitForLaunchState_SessionDiesOrTimesOut(t *testing.T) {
+	fastLaunchWait(t, time.Minute)
+
+	dead := &mockStatusChecker{statuses: []string{"active", "inactive"}}
+	if status, err := waitForLaunchState(dead, launchWaitWaiting, true, 5*time.Second); err != nil || status != "inactive" {
+		t.Fatalf("dead session = %q, %v", status, err)
+	}
+
+	boom := errors.New("no pane")
+	broken := &mockStatusChecker{statuses: []string{""}, errors: []error{boom, boom, boom, boom, boom}}
+	if status, err := waitForLaunchState(broken, launchWaitWaiting, true, 5*time.Second); err != nil || status != "error" {
+		t.Fatalf("unreadable session = %q, %v", status, err)
+	}
+
+	busy := &mockStatusChecker{statuses: []string{"active"}}
+	if _, err := waitForLaunchState(busy, launchWaitWaiting, true, 50*time.Millisecond); err == nil {
+		t.Fatal("expected a timeout while the agent stays active")
+	}
+}
+
+func TestValidateLaunchWaitFor(t *testing.T) {
+	for _, ok := range []string{"", "waiting", "idle"} {
+		if err := validateLaunchWaitFor(ok); err != nil {
+			t.Errorf("validateLaunchWaitFor(%q) = %v", ok, err)
+		}
+	}
+	if err := validateLaunchWaitFor("done"); err == nil {
+		t.Error("validateLaunchWaitFor(\"done\") accepted")
+	}
+}
+
+func TestWriteLaunchCapture_AddsTrailingNewline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.txt")
+	if err := writeLaunchCapture(path, "All 42 tests pass."); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "All 42 tests pass.\n" {
+		t.Fatalf("capture = %q, %v", data, err)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// applyCLILayout gives a session being created the panes of the named
+// [layouts] entry followed by one pane per --pane command (split to the
+// right of the agent). Both are optional; neither leaves the session
+// single-pane.
+func applyCLILayout(inst *session.Instance, name string, paneCommands []string) error {
+	name = strings.TrimSpace(name)
+	if inst == nil || (name == "" && len(paneCommands) == 0) {
+		return nil
+	}
+	var panes []tmux.PaneSpec
+	if name != "" {
+		l, err := session.LookupLayout(name)
+		if err != nil {
+			return err
+		}
+		panes = append(panes, l.Panes...)
+	}
+	for _, cmd := range paneCommands {
+		p := tmux.PaneSpec{Command: strings.TrimSpace(cmd)}
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("--pane: %w", err)
+		}
+		panes = append(panes, p)
+	}
+	inst.Layout = name
+	inst.Panes = panes
+	return nil
+}
@@ -29,9 +29,11 @@ import (
 	"github.com/asheshgoplani/agent-deck/internal/feedback"
 	"github.com/asheshgoplani/agent-deck/internal/git"
 	"github.com/asheshgoplani/agent-deck/internal/logging"
+	"github.com/asheshgoplani/agent-deck/internal/progress"
 	"github.com/asheshgoplani/agent-deck/internal/session"
 	"github.com/asheshgoplani/agent-deck/internal/statedb"
 	"github.com/asheshgoplani/agent-deck/internal/tmux"
+	"github.com/asheshgoplani/agent-deck/internal/tmux/faketmux"
 	"github.com/asheshgoplani/agent-deck/internal/ui"
 	"github.com/asheshgoplani/agent-deck/internal/update"
 	"github.com/asheshgoplani/agent-deck/internal/vcs"
@@ -209,6 +211,17 @@ func initColorProfile() {
 }
 
 func main() {
+	// AGENTDECK_FAKE_TMUX: this binary doubles as the simulated tmux server.
+	// Invoked through the <dir>/bin/tmux shim it answers the tmux command and
+	// exits; otherwise it installs the shim ahead of any real tmux on PATH.
+	if faketmux.IsShim(os.Args[0]) {
+		os.Exit(faketmux.Main(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+	}
+	if err := faketmux.Install(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: fake tmux backend: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Make bare `tmux` invocations resolve even when launched from a minimal
 	// environment (notably a `terminal-notifier -execute` notification click,
 	// whose launchd PATH omits Homebrew's /opt/homebrew/bin). Must run before any
@@ -231,6 +244,7 @@ func main() {
 	// calls use Instance.TmuxSocketName directly — this default is only
 	// the installation-wide fallback for callers without a session handle.
 	tmux.SetDefaultSocketName(session.GetTmuxSettings().GetSocketName())
+	tmux.SetControlModeOnly(session.GetTmuxSettings().ControlModeOnly)
 
 	// Nudge macOS users whose tmux predates the upstream fix for the
 	// control-mode NULL-deref (tmux #4980, issue #737). Once per process,
@@ -267,9 +281,18 @@ func main() {
 		case "status":
 			handleStatus(profile, args[1:])
 			return
+		case "search":
+			handleSearch(args[1:])
+			return
 		case "profile":
 			handleProfile(args[1:])
 			return
+		case "setup":
+			handleSetup(profile, args[1:])
+			return
+		case "completion":
+			handleCompletion(args[1:])
+			return
 		case "update":
 			handleUpdate(args[1:])
 			return
@@ -285,6 +308,36 @@ func main() {
 		case "skill":
 			handleSkill(profile, args[1:])
 			return
+		case "prompt":
+			handlePrompt(profile, args[1:])
+			return
+		case "theme":
+			handleTheme(args[1:])
+			return
+		case "checkpoint":
+			handleCheckpoint(profile, args[1:])
+			return
+		case "backup":
+			handleBackup(profile, args[1:])
+			return
+		case "extension", "ext":
+			handleExtension(profile, args[1:])
+			return
+		case "schedule":
+			handleSchedule(profile, args[1:])
+			return
+		case "task":
+			handleTask(profile, args[1:])
+			return
+		case "port":
+			handlePort(profile, args[1:])
+			return
+		case "governor":
+			handleGovernor(profile, args[1:])
+			return
+		case "auto-respond":
+			handleAutoRespond(profile, args[1:])
+			return
 		case "mcp-proxy":
 			if len(args) < 2 {
 				fmt.Fprintln(os.Stderr, "Usage: agent-deck mcp-proxy <socket-path>")
@@ -292,6 +345,13 @@ func main() {
 			}
 			runMCPProxy(args[1])
 			return
+		case "mcp-hub":
+			if len(args) < 2 {
+				fmt.Fprintln(os.Stderr, "Usage: agent-deck mcp-hub <manifest-path>")
+				os.Exit(1)
+			}
+			runMCPHub(args[1])
+			return
 		case "group":
 			handleGroup(profile, args[1:])
 			return
@@ -301,12 +361,24 @@ func main() {
 		case "launch":
 			handleLaunch(profile, args[1:])
 			return
+		case "fanout":
+			handleFanout(profile, args[1:])
+			return
+		case "import":
+			handleImport(profile, args[1:])
+			return
 		case "conductor":
 			handleConductor(profile, args[1:])
 			return
 		case "telegram-doctor":
 			handleTelegramDoctor(profile, args[1:])
 			return
+		case "doctor":
+			handleDoctor(profile, args[1:])
+			return
+		case "config":
+			handleConfig(args[1:])
+			return
 		case "watcher":
 			handleWatcher(profile, args[1:])
 			return
@@ -316,13 +388,20 @@ func main() {
 		case "remote":
 			handleRemote(profile, args[1:])
 			return
+		case "relay":
+			handleRelay(args[1:])
+			return
 		case "worktree", "wt":
 			handleWorktree(profile, args[1:])
 			return
-		case "costs":
+		case "costs", "cost":
 			handleCosts(profile, args[1:])
 			return
 		case "web":
+			if len(args) > 1 && args[1] == "share" {
+				handleWebShare(profile, args[2:])
+				return
+			}
 			webEnabled = true
 			// Extract --no-tui out of webArgs before buildWebServer's flag set
 			// sees it. The TUI-vs-headless decision is made at bootstrap (it
@@ -360,6 +439,9 @@ func main() {
 		case "notify-daemon":
 			handleNotifyDaemon(args[1:])
 			return
+		case "bridge":
+			handleBridge(args[1:])
+			return
 		case "run-task":
 			handleRunTask(args[1:])
 			return
@@ -375,6 +457,22 @@ func main() {
 		case "debug-dump":
 			handleDebugDump()
 			return
+		case "debug":
+			handleDebug(profile, args[1:])
+			return
+		case "archive":
+			handleArchive(profile, args[1:])
+			return
+		case "control":
+			handleControl(profile, args[1:])
+			return
+		default:
+			// Not a built-in: run the agent-deck-<name> extension, if any.
+			if !strings.HasPrefix(args[0], "-") {
+				if ext, ok := session.FindExtension(args[0]); ok {
+					os.Exit(runExtension(profile, ext, args[1:]))
+				}
+			}
 		}
 	}
 
@@ -426,9 +524,9 @@ func main() {
 	// Set version for UI update checking
 	ui.SetVersion(Version)
 
-	// Initialize theme from config (resolves "system" to actual dark/light)
-	theme := session.ResolveTheme()
-	ui.InitTheme(theme)
+	// Initialize theme from config (resolves "system" to actual dark/light and
+	// applies the [themes] colors)
+	ui.InitThemeDef(session.ResolveThemeDef())
 
 	// Check for updates and prompt user before launching TUI. Headless web
 	// mode (--no-tui) skips this — it's an interactive prompt that would
@@ -561,6 +659,7 @@ func main() {
 
 		logging.Init(logCfg)
 		defer logging.Shutdown()
+		defer initTracing()()
 
 		// OBS-01: emit the cgroup-isolation decision exactly once on TUI
 		// startup. The line lands in the XDG cache debug.log via the
@@ -762,6 +861,13 @@ func main() {
 		}
 	}
 
+	// Serve the JSON-RPC control socket for editors and scripts. Both the
+	// TUI and headless web mode host it; a second instance finds the socket
+	// live and leaves it to the first.
+	if controlServer := startControlServer(profile); controlServer != nil {
+		defer func() { _ = controlServer.Close() }()
+	}
+
 	// Start web server alongside TUI if "web" subcommand was used.
 	// When --no-tui is also set, run the HTTP server in the foreground and
 	// skip bubbletea entirely — the perf win that motivated this flag.
@@ -889,9 +995,14 @@ func main() {
 	session.StartMaintenanceWorker(maintenanceCtx, func(result session.MaintenanceResult) {
 		p.Send(ui.MaintenanceCompleteMsg{Result: result})
 	})
-
-	if _, err := p.Run(); err != nil {
-		fmt.Printf("Error: %v\n", err)
+	// Scheduled messages (agent-deck schedule) fire while the TUI runs.
+	session.StartScheduleWorker(maintenanceCtx, profile)
+
+	_, runErr := p.Run()
+	// Click-only mouse mode uses a tracking mode Bubble Tea doesn't reset.
+	ui.ResetMouseReporting(os.Stdout)
+	if runErr != nil {
+		fmt.Printf("Error: %v\n", runErr)
 		os.Exit(1)
 	}
 }
@@ -904,15 +1015,16 @@ func main() {
 var globalFlagSubcommands = map[string]bool{
 	"add": true, "list": true, "ls": true, "remove": true, "rm": true,
 	"rename": true, "mv": true, "status": true, "profile": true, "update": true,
-	"session": true, "mcp": true, "plugin": true, "skill": true, "mcp-proxy": true,
-	"group": true, "try": true, "launch": true, "conductor": true,
-	"telegram-doctor": true, "watcher": true, "openclaw": true, "oc": true,
-	"remote": true, "worktree": true, "wt": true, "costs": true, "web": true,
+	"session": true, "prompt": true, "theme": true, "checkpoint": true, "backup": true, "extension": true, "ext": true, "schedule": true, "task": true, "port": true, "governor": true, "auto-respond": true, "mcp": true, "plugin": true, "skill": true, "mcp-proxy": true, "mcp-hub": true,
+	"group": true, "try": true, "launch": true, "fanout": true, "conductor": true,
+	"telegram-doctor": true, "doctor": true, "config": true, "watcher": true, "openclaw": true, "oc": true,
+	"remote": true, "relay": true, "worktree": true, "wt": true, "costs": true, "cost": true, "web": true,
 	"uninstall": true, "migrate-paths": true, "hook-handler": true,
 	"codex-notify": true, "hooks": true, "codex-hooks": true, "gemini-hooks": true,
-	"hermes-hooks": true, "cursor-hooks": true, "notify-daemon": true,
+	"hermes-hooks": true, "cursor-hooks": true, "notify-daemon": true, "bridge": true,
 	"run-task": true, "inbox": true, "feedback": true, "creds-refresh": true,
-	"debug-dump": true, "version": true, "help": true,
+	"debug-dump": true, "debug": true, "archive": true, "control": true, "version": true, "help": true, "setup": true,
+	"completion": true,
 }
 
 // extractProfileFlag extracts the global -p or --profile flag from args,
@@ -934,7 +1046,7 @@ func extractProfileFlag(args []string) (string, []string) {
 
 		// Reached the subcommand: global flag parsing is over. Everything from
 		// here belongs to the subcommand, which may define its own -p.
-		if globalFlagSubcommands[arg] {
+		if globalFlagSubcommands[arg] || isExtensionToken(args, i) {
 			remaining = append(remaining, args[i:]...)
 			return profile, remaining
 		}
@@ -964,6 +1076,18 @@ func extractProfileFlag(args []string) (string, []string) {
 	return profile, remaining
 }
 
+// isExtensionToken reports whether args[i] names an extension command
+// (agent-deck-<name> on PATH or [extensions.<name>]), which like a built-in
+// subcommand owns everything after it. Only a bare token that isn't the value
+// of a preceding flag is looked up, so the PATH scan stays off the common path.
+func isExtensionToken(args []string, i int) bool {
+	if strings.HasPrefix(args[i], "-") || (i > 0 && strings.HasPrefix(args[i-1], "-") && !strings.Contains(args[i-1], "=")) {
+		return false
+	}
+	_, ok := session.FindExtension(args[i])
+	return ok
+}
+
 // extractGroupFlag extracts -g or --group from args, returning the group path and remaining args.
 // This only applies to the TUI launch path; subcommands like add/launch have their own -g flag.
 func extractGroupFlag(args []string) (string, []string) {
@@ -1058,6 +1182,9 @@ func reorderArgsForFlagParsing(args []string) []string {
 		"extra-arg":      true,
 		"wrapper":        true,
 		"model":          true,
+		"preset":         true,
+		"layout":         true,
+		"pane":           true,
 		"w":              true,
 		"worktree":       true,
 		"location":       true,
@@ -1066,6 +1193,10 @@ func reorderArgsForFlagParsing(args []string) []string {
 		"ssh":            true,
 		"remote-path":    true,
 		"tmux-socket":    true,
+		"wait-for":       true,
+		"timeout":        true,
+		"capture-output": true,
+		"checkpoint":     true,
 	}
 
 	var flags []string
@@ -1306,6 +1437,15 @@ func handleAdd(profile string, args []string) {
 	// Resume session flag
 	resumeSession := fs.String("resume-session", "", "Claude session ID to resume (skips new session creation)")
 	modelID := fs.String("model", "", "Model ID/version to use for this session (claude, codex, gemini, opencode)")
+	preset := fs.String("preset", "", "Start preset: light, standard, heavy, or a [presets.<name>] (see 'session presets')")
+	// Multi-pane sessions: extra tmux panes next to the agent. Status
+	// detection and input stay bound to the agent pane.
+	layout := fs.String("layout", "", "Multi-pane layout from [layouts.<name>] in config.toml (e.g. agent + dev server + logs)")
+	var paneFlags []string
+	fs.Func("pane", "Command for an extra tmux pane beside the agent (can specify multiple times)", func(s string) error {
+		paneFlags = append(paneFlags, s)
+		return nil
+	})
 	yoloMode := fs.Bool("yolo", false, "Enable YOLO mode for Gemini or Codex sessions")
 	geminiYoloMode := fs.Bool("gemini-yolo", false, "Enable YOLO mode (alias for --yolo)")
 
@@ -1321,6 +1461,10 @@ func handleAdd(profile string, args []string) {
 	// Empty = fall through to conductor/group/env/profile/global/default.
 	account := fs.String("account", "", "Named account slot (resolves via [profiles.<account>.claude].config_dir; #924)")
 
+	// Project manifest: materialize everything declared in .agentdeck.toml
+	// instead of adding a single session.
+	fromManifest := fs.Bool("from-manifest", false, "Create the groups, sessions and loadout declared in the project's .agentdeck.toml ([path] = manifest or project dir)")
+
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck add [path] [options]")
 		fmt.Println()
@@ -1339,6 +1483,9 @@ func handleAdd(profile string, args []string) {
 		fmt.Println("  agent-deck add -c claude .")
 		fmt.Println("  agent-deck add -c codex --model gpt-5.5 .")
 		fmt.Println("  agent-deck add -c gemini --model gemini-3.1-pro-preview .")
+		fmt.Println("  agent-deck add -c claude --preset heavy .")
+		fmt.Println("  agent-deck add -c claude --layout fullstack .  # panes from [layouts.fullstack]")
+		fmt.Println("  agent-deck add -c claude --pane \"npm run dev\" --pane \"tail -f log/dev.log\" .")
 		fmt.Println("  agent-deck -p work add               # Add to 'work' profile")
 		fmt.Println("  agent-deck add -t \"Sub-task\" --parent \"Main Project\"  # Create sub-session")
 		fmt.Println("  agent-deck add -t \"Research\" -c claude --mcp memory --mcp sequential-thinking /tmp/x")
@@ -1359,6 +1506,10 @@ func handleAdd(profile string, args []string) {
 		fmt.Println("SSH Examples:")
 		fmt.Println("  agent-deck add --ssh user@host --remote-path ~/project -c claude")
 		fmt.Println("  agent-deck add --ssh user@host -c claude -t \"remote-dev\"")
+		fmt.Println()
+		fmt.Println("Project Manifest Examples:")
+		fmt.Println("  agent-deck add --from-manifest        # Apply ./.agentdeck.toml (or the nearest one above)")
+		fmt.Println("  agent-deck add --from-manifest ~/src/acme")
 	}
 
 	// Reorder args: move path to end so flags are parsed correctly
@@ -1374,6 +1525,11 @@ func handleAdd(profile string, args []string) {
 	// Fix: sanitize input to remove surrounding quotes that cause issues.
 	rawPathArg := strings.Trim(fs.Arg(0), "'\"")
 	explicitPathProvided := rawPathArg != ""
+
+	if *fromManifest {
+		handleAddFromManifest(profile, rawPathArg, *jsonOutput, *quiet || *quietShort)
+		return
+	}
 	path := ""
 
 	// Resolve worktree flags
@@ -1715,6 +1871,14 @@ func handleAdd(profile string, args []string) {
 		newInstance.Account = trimmed
 	}
 
+	// A start preset sets model, MCPs and tool flags; an explicit --model
+	// below still wins over the preset's model.
+	mcpFlags, presetSetsMCPs, err := applyCLIPreset(newInstance, *preset, mcpFlags)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Apply per-session model override after command/tool resolution so the
 	// tool-specific option field is populated correctly.
 	selectedModelID := strings.TrimSpace(*modelID)
@@ -1748,6 +1912,19 @@ func handleAdd(profile string, args []string) {
 		newInstance.SSHRemotePath = *sshRemotePath
 	}
 
+	// Multi-pane layout. The extra panes run on the local tmux server, so
+	// they do not combine with a remote or sandboxed agent.
+	if *layout != "" || len(paneFlags) > 0 {
+		if *sshHost != "" || *sandbox {
+			fmt.Println("Error: --layout/--pane cannot be used with --ssh or --sandbox")
+			os.Exit(1)
+		}
+		if err := applyCLILayout(newInstance, *layout, paneFlags); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Handle --resume-session: set Claude session ID and resume mode
 	if *resumeSession != "" {
 		newInstance.ClaudeSessionID = *resumeSession
@@ -1816,6 +1993,16 @@ func handleAdd(profile string, args []string) {
 		}
 	}
 
+	// Attach the group's default MCPs on top of any --mcp ones, unless a
+	// preset chose the MCP set.
+	if !presetSetsMCPs {
+		groupMCPs, err := session.ApplyGroupDefaultMCPs(newInstance, groupTree)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to attach group default MCPs: %v\n", err)
+		}
+		mcpFlags = append(mcpFlags, groupMCPs...)
+	}
+
 	quietMode := *quiet || *quietShort
 	out := NewCLIOutput(*jsonOutput, quietMode)
 
@@ -1872,6 +2059,20 @@ func handleAdd(profile string, args []string) {
 	if len(mcpFlags) > 0 {
 		humanLines = append(humanLines, fmt.Sprintf("  MCPs:    %s", strings.Join(mcpFlags, ", ")))
 	}
+	if newInstance.Preset != "" {
+		humanLines = append(humanLines, fmt.Sprintf("  Preset:  %s", newInstance.Preset))
+	}
+	if len(newInstance.Panes) > 0 {
+		panes := make([]string, len(newInstance.Panes))
+		for i, p := range newInstance.Panes {
+			panes[i] = p.Command
+		}
+		label := strings.Join(panes, ", ")
+		if newInstance.Layout != "" {
+			label = newInstance.Layout + ": " + label
+		}
+		humanLines = append(humanLines, fmt.Sprintf("  Panes:   %s", label))
+	}
 	if parentInstance != nil {
 		humanLines = append(humanLines, fmt.Sprintf("  Parent:  %s (%s)", parentInstance.Title, parentInstance.ID[:8]))
 	}
@@ -1921,6 +2122,15 @@ func handleAdd(profile string, args []string) {
 	if len(mcpFlags) > 0 {
 		jsonData["mcps"] = mcpFlags
 	}
+	if newInstance.Preset != "" {
+		jsonData["preset"] = newInstance.Preset
+	}
+	if newInstance.Layout != "" {
+		jsonData["layout"] = newInstance.Layout
+	}
+	if len(newInstance.Panes) > 0 {
+		jsonData["panes"] = newInstance.Panes
+	}
 	if parentInstance != nil {
 		jsonData["parent_id"] = parentInstance.ID
 		jsonData["parent_title"] = parentInstance.Title
@@ -2023,6 +2233,7 @@ func handleList(profile string, args []string) {
 			ID            string    `json:"id"`
 			Title         string    `json:"title"`
 			Path          string    `json:"path"`
+			PathMissing   bool      `json:"path_missing,omitempty"`
 			Group         string    `json:"group"`
 			Tool          string    `json:"tool"`
 			Command       string    `json:"command,omitempty"`
@@ -2030,7 +2241,8 @@ func handleList(profile string, args []string) {
 			Model         string    `json:"model,omitempty"`
 			ModelVersion  string    `json:"model_version,omitempty"`
 			Status        string    `json:"status"`
-			Substate      string    `json:"substate,omitempty"` // Honest Status v2: additive refinement
+			Substate      string    `json:"substate,omitempty"`  // Honest Status v2: additive refinement
+			ExitCode      *int      `json:"exit_code,omitempty"` // set for exited/crashed sessions
 			TmuxSession   string    `json:"tmux_session,omitempty"`
 			Profile       string    `json:"profile"`
 			CreatedAt     time.Time `json:"created_at"`
@@ -2052,6 +2264,7 @@ func handleList(profile string, args []string) {
 				ID:            inst.ID,
 				Title:         inst.Title,
 				Path:          inst.ProjectPath,
+				PathMissing:   inst.ProjectPathMissing(),
 				Group:         inst.GroupPath,
 				Tool:          inst.Tool,
 				Command:       inst.Command,
@@ -2070,6 +2283,11 @@ func handleList(profile string, args []string) {
 			if tmuxSess := inst.GetTmuxSession(); tmuxSess != nil {
 				sj.TmuxSession = tmuxSess.Name
 			}
+			if inst.Status == session.StatusExited || inst.Status == session.StatusCrashed {
+				if rec := inst.ExitRecord(); rec != nil {
+					sj.ExitCode = &rec.Code
+				}
+			}
 			if modelInfo := inst.LaunchModelInfo(); modelInfo.ModelID != "" {
 				sj.ModelID = modelInfo.ModelID
 				sj.Model = modelInfo.Model
@@ -2102,6 +2320,16 @@ func handleList(profile string, args []string) {
 		fmt.Printf("%-*s %-*s %-*s %s\n", tableColTitle, title, tableColGroup, group, tableColPath, path, idDisplay)
 	}
 	fmt.Printf("\nTotal: %d sessions\n", len(instances))
+	var missing []string
+	for _, inst := range instances {
+		if inst.ProjectPathMissing() {
+			missing = append(missing, inst.Title)
+		}
+	}
+	if len(missing) > 0 {
+		fmt.Printf("\n%s Project path missing (moved?): %s\n", errorSymbol, strings.Join(missing, ", "))
+		fmt.Println("  Fix with: agent-deck session relocate <id> [new-path]")
+	}
 
 	// Show update notice if available
 	printUpdateNotice()
@@ -2330,6 +2558,9 @@ func handleRemove(profile string, args []string) {
 	if _, err := session.RemoveNotifyStateRecord(removedID); err != nil && !*jsonOutput {
 		fmt.Fprintf(os.Stderr, "warn: notify-state sweep for %s failed: %v\n", removedID, err)
 	}
+	if err := session.UnpinSession(removedID); err != nil && !*jsonOutput {
+		fmt.Fprintf(os.Stderr, "warn: removing login service for %s failed: %v\n", removedID, err)
+	}
 
 	out.Success(
 		fmt.Sprintf("Removed session: %s (from profile '%s')", removedTitle, storage.Profile()),
@@ -2431,39 +2662,14 @@ func handleRename(profile string, args []string) {
 	)
 }
 
-// statusCounts holds session counts by status
-type statusCounts struct {
-	running int
-	waiting int
-	idle    int
-	err     int
-	stopped int
-	total   int
-}
-
-// countByStatus counts sessions by their status
-func countByStatus(instances []*session.Instance) statusCounts {
+// refreshStatusesForCLI polls every instance's status from tmux.
+func refreshStatusesForCLI(instances []*session.Instance) {
 	// Warm tmux pane-title cache + load hook statuses so `status`/`status --json`
 	// reports the same counts the TUI and /api/menu do (issue #610).
 	session.RefreshInstancesForCLIStatus(instances)
-	var counts statusCounts
 	for _, inst := range instances {
 		_ = inst.UpdateStatus() // Refresh status from tmux
-		switch inst.Status {
-		case session.StatusRunning:
-			counts.running++
-		case session.StatusWaiting:
-			counts.waiting++
-		case session.StatusIdle:
-			counts.idle++
-		case session.StatusError:
-			counts.err++
-		case session.StatusStopped:
-			counts.stopped++
-		}
-		counts.total++
-	}
-	return counts
+	}
 }
 
 // handleStatus shows session status summary
@@ -2474,12 +2680,16 @@ func handleStatus(profile string, args []string) {
 	quiet := fs.Bool("quiet", false, "Only output waiting count (for scripts)")
 	quietShort := fs.Bool("q", false, "Only output waiting count (short)")
 	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	fresh := fs.Bool("fresh", false, "Always poll tmux instead of using the running TUI's cached counts")
 
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck status [options]")
 		fmt.Println()
 		fmt.Println("Show a summary of session statuses.")
 		fmt.Println()
+		fmt.Println("While a TUI or the notify daemon is running, the summary (without -v) is")
+		fmt.Println("read from the counts it keeps in state.db and returns in milliseconds.")
+		fmt.Println()
 		fmt.Println("Options:")
 		fs.PrintDefaults()
 		fmt.Println()
@@ -2487,6 +2697,7 @@ func handleStatus(profile string, args []string) {
 		fmt.Println("  agent-deck status              # Quick summary")
 		fmt.Println("  agent-deck status -v           # Detailed list")
 		fmt.Println("  agent-deck status -q           # Just waiting count")
+		fmt.Println("  agent-deck status -q --fresh   # Waiting count, polling tmux directly")
 		fmt.Println("  agent-deck -p work status      # Status for 'work' profile")
 	}
 
@@ -2494,32 +2705,50 @@ func handleStatus(profile string, args []string) {
 		os.Exit(1)
 	}
 
-	// Load sessions
-	storage, err := session.NewStorageWithProfile(profile)
-	if err != nil {
-		fmt.Printf("Error: failed to initialize storage: %v\n", err)
-		os.Exit(1)
+	// Fast path: a running TUI publishes its counts to state.db, which spares
+	// prompt/statusline scripts a full profile load and tmux poll per call.
+	// -v needs per-session detail, so it always takes the slow path.
+	var counts statedb.StatusCounters
+	var storage *session.Storage
+	var instances []*session.Instance
+	cached := false
+	if !*fresh && !*verbose && !*verboseShort {
+		if c, ok := session.ReadCachedStatusCounters(profile); ok && c.Total > 0 {
+			counts = c
+			cached = true
+		}
 	}
 
-	instances, _, err := storage.LoadWithGroups()
-	if err != nil {
-		fmt.Printf("Error: failed to load sessions: %v\n", err)
-		os.Exit(1)
-	}
+	if !cached {
+		// Load sessions
+		var err error
+		storage, err = session.NewStorageWithProfile(profile)
+		if err != nil {
+			fmt.Printf("Error: failed to initialize storage: %v\n", err)
+			os.Exit(1)
+		}
 
-	if len(instances) == 0 {
-		if *jsonOutput {
-			fmt.Println(`{"waiting": 0, "running": 0, "idle": 0, "error": 0, "stopped": 0, "total": 0}`)
-		} else if *quiet || *quietShort {
-			fmt.Println("0")
-		} else {
-			fmt.Printf("No sessions in profile '%s'.\n", storage.Profile())
+		instances, _, err = storage.LoadWithGroups()
+		if err != nil {
+			fmt.Printf("Error: failed to load sessions: %v\n", err)
+			os.Exit(1)
 		}
-		return
-	}
 
-	// Count by status
-	counts := countByStatus(instances)
+		if len(instances) == 0 {
+			if *jsonOutput {
+				fmt.Println(`{"waiting": 0, "running": 0, "idle": 0, "error": 0, "stopped": 0, "exited": 0, "crashed": 0, "total": 0}`)
+			} else if *quiet || *quietShort {
+				fmt.Println("0")
+			} else {
+				fmt.Printf("No sessions in profile '%s'.\n", storage.Profile())
+			}
+			return
+		}
+
+		// Count by status
+		refreshStatusesForCLI(instances)
+		counts = session.CountStatuses(instances)
+	}
 
 	// Output based on flags
 	if *jsonOutput {
@@ -2544,16 +2773,20 @@ func handleStatus(profile string, args []string) {
 			Idle     int                 `json:"idle"`
 			Error    int                 `json:"error"`
 			Stopped  int                 `json:"stopped"`
+			Exited   int                 `json:"exited"`
+			Crashed  int                 `json:"crashed"`
 			Total    int                 `json:"total"`
 			Sessions []statusSessionJSON `json:"sessions,omitempty"`
 		}
 		resp := statusJSON{
-			Waiting: counts.waiting,
-			Running: counts.running,
-			Idle:    counts.idle,
-			Error:   counts.err,
-			Stopped: counts.stopped,
-			Total:   counts.total,
+			Waiting: counts.Waiting,
+			Running: counts.Running,
+			Idle:    counts.Idle,
+			Error:   counts.Error,
+			Stopped: counts.Stopped,
+			Exited:  counts.Exited,
+			Crashed: counts.Crashed,
+			Total:   counts.Total,
 		}
 		if *verbose || *verboseShort {
 			session.RefreshInstancesForCLIStatus(instances)
@@ -2579,7 +2812,7 @@ func handleStatus(profile string, args []string) {
 		output, _ := json.Marshal(resp)
 		fmt.Println(string(output))
 	} else if *quiet || *quietShort {
-		fmt.Println(counts.waiting)
+		fmt.Println(counts.Waiting)
 	} else if *verbose || *verboseShort {
 		// Detailed output grouped by status
 		printStatusGroup := func(label, symbol string, status session.Status) {
@@ -2612,13 +2845,15 @@ func handleStatus(profile string, args []string) {
 		printStatusGroup("RUNNING", "●", session.StatusRunning)
 		printStatusGroup("IDLE", "○", session.StatusIdle)
 		printStatusGroup("STOPPED", "■", session.StatusStopped)
+		printStatusGroup("EXITED", "✓", session.StatusExited)
+		printStatusGroup("CRASHED", "✗", session.StatusCrashed)
 		printStatusGroup("ERROR", "✕", session.StatusError)
 
-		fmt.Printf("Total: %d sessions in profile '%s'\n", counts.total, storage.Profile())
+		fmt.Printf("Total: %d sessions in profile '%s'\n", counts.Total, storage.Profile())
 	} else {
 		// Compact output
 		fmt.Printf("%d waiting • %d running • %d idle\n",
-			counts.waiting, counts.running, counts.idle)
+			counts.Waiting, counts.Running, counts.Idle)
 	}
 
 	// Show update notice if available (skip for JSON/quiet output)
@@ -2843,6 +3078,7 @@ func handleUpdate(args []string) {
 	fs := flag.NewFlagSet("update", flag.ExitOnError)
 	checkOnly := fs.Bool("check", false, "Only check for updates, don't install")
 	targetVersion := fs.String("version", "", "Install a specific released version (e.g. 1.7.3); may be a downgrade")
+	showProgress := fs.Bool("progress", false, "Print download and verification progress to stderr")
 
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck update [options]")
@@ -2856,14 +3092,20 @@ func handleUpdate(args []string) {
 		fmt.Println("  agent-deck update              # Check and install latest if available")
 		fmt.Println("  agent-deck update --check      # Only check, don't install")
 		fmt.Println("  agent-deck update --version 1.7.3  # Install a specific version (may downgrade)")
+		fmt.Println("  agent-deck update --progress   # Show download progress")
 	}
 
 	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
 		os.Exit(1)
 	}
 
+	var report progress.Func
+	if *showProgress {
+		report = progress.NewCLI(os.Stderr)
+	}
+
 	if strings.TrimSpace(*targetVersion) != "" {
-		handleUpdateToSpecificVersion(*targetVersion, *checkOnly)
+		handleUpdateToSpecificVersion(*targetVersion, *checkOnly, report)
 		return
 	}
 
@@ -2942,7 +3184,7 @@ func handleUpdate(args []string) {
 			fmt.Printf("Error installing update: failed to fetch release info: %v\n", err)
 			os.Exit(1)
 		}
-		if err := update.PerformVerifiedUpdate(release, runtime.GOOS, runtime.GOARCH); err != nil {
+		if err := update.PerformVerifiedUpdateProgress(context.Background(), release, runtime.GOOS, runtime.GOARCH, report); err != nil {
 			fmt.Printf("Error installing update: %v\n", err)
 			os.Exit(1)
 		}
@@ -2957,6 +3199,9 @@ func handleUpdate(args []string) {
 	fmt.Printf("\n✓ Updated to v%s\n", info.LatestVersion)
 	fmt.Println("  Restart agent-deck to use the new version.")
 
+	// Re-sync hook installs written by the previous version
+	syncHooksAfterUpdate()
+
 	// Offer to update remotes
 	updateRemotesAfterLocalUpdate(info.LatestVersion)
 }
@@ -2964,7 +3209,7 @@ func handleUpdate(args []string) {
 // handleUpdateToSpecificVersion installs a user-specified release version.
 // Unlike the default update flow, this bypasses the "is this newer?" check so
 // callers can reinstall or downgrade to a prior release on purpose.
-func handleUpdateToSpecificVersion(requested string, checkOnly bool) {
+func handleUpdateToSpecificVersion(requested string, checkOnly bool, report progress.Func) {
 	fmt.Printf("Agent Deck v%s\n", Version)
 
 	normalized := update.NormalizeReleaseTag(requested)
@@ -3032,7 +3277,7 @@ func handleUpdateToSpecificVersion(requested string, checkOnly bool) {
 	}
 
 	fmt.Println()
-	if err := update.PerformVerifiedUpdate(release, runtime.GOOS, runtime.GOARCH); err != nil {
+	if err := update.PerformVerifiedUpdateProgress(context.Background(), release, runtime.GOOS, runtime.GOARCH, report); err != nil {
 		fmt.Printf("Error installing v%s: %v\n", targetVersion, err)
 		os.Exit(1)
 	}
@@ -3044,6 +3289,9 @@ func handleUpdateToSpecificVersion(requested string, checkOnly bool) {
 
 	fmt.Printf("\n✓ Installed v%s\n", targetVersion)
 	fmt.Println("  Restart agent-deck to use this version.")
+
+	// Re-sync hook installs to the installed version's hook set
+	syncHooksAfterUpdate()
 }
 
 // brewRunner abstracts `brew <args...>` so tests can inject canned output
@@ -3180,14 +3428,29 @@ func printHelp() {
 	fmt.Println("  (none)           Start the TUI")
 	fmt.Println("  add <path>       Add a new session")
 	fmt.Println("  launch [path]    Add, start, and optionally send a message in one step")
+	fmt.Println("  fanout <prompt>  Send one task to N worktree sessions and compare their attempts")
 	fmt.Println("  try <name>       Quick experiment (create/find dated folder + session)")
+	fmt.Println("  import           Import sessions from a tmuxinator or tmuxp config")
 	fmt.Println("  list, ls         List all sessions")
 	fmt.Println("  remove, rm       Remove a session")
 	fmt.Println("  rename, mv       Rename a session")
 	fmt.Println("  status           Show session status summary")
+	fmt.Println("  search <query>   Search sessions across all profiles (titles, paths, transcripts)")
 	fmt.Println("  session          Manage session lifecycle")
+	fmt.Println("  archive          Browse, restore and auto-archive archived sessions")
+	fmt.Println("  control          Call the running instance's JSON-RPC control socket")
 	fmt.Println("  mcp              Manage MCP servers")
 	fmt.Println("  skill            Manage project skills")
+	fmt.Println("  prompt           Save reusable prompts and send them to sessions")
+	fmt.Println("  theme            List, switch, export and import color themes")
+	fmt.Println("  checkpoint       List, take and roll back working-tree checkpoints")
+	fmt.Println("  backup           Back up and restore the profile's state.db and config")
+	fmt.Println("  extension        List and run extensions (agent-deck-<name> commands)")
+	fmt.Println("  schedule         Send messages to sessions on a cron schedule")
+	fmt.Println("  task             Queue work items for sessions and track acknowledgments")
+	fmt.Println("  port             Claim ports for sessions so worktree copies never collide")
+	fmt.Println("  governor         Show the Claude send governor and its queued messages")
+	fmt.Println("  auto-respond     Answer Claude permission dialogs by config rules")
 	fmt.Println("  codex-hooks      Manage Codex notify hook integration")
 	fmt.Println("  gemini-hooks     Manage Gemini hook integration")
 	fmt.Println("  hermes-hooks     Manage Hermes Agent hook integration")
@@ -3195,12 +3458,19 @@ func printHelp() {
 	fmt.Println("  group            Manage groups")
 	fmt.Println("  worktree, wt     Manage git worktrees")
 	fmt.Println("  web              Start TUI with web UI server running alongside")
+	fmt.Println("  web share <id>   Create a time-limited read-only link to one session")
 	fmt.Println("  remote           Manage remote agent-deck instances")
+	fmt.Println("  relay            End-to-end encrypted remote access via a self-hosted relay")
 	fmt.Println("  conductor        Manage conductor meta-agent orchestration")
+	fmt.Println("  doctor           Check tmux, terminal, config, state.db, hooks and daemons")
+	fmt.Println("  config validate  Check config.toml for unknown keys and invalid definitions")
 	fmt.Println("  telegram-doctor  Audit channel-owning sessions for telegram drops (#1138)")
 	fmt.Println("  profile          Manage profiles")
+	fmt.Println("  setup            Guided first-run bootstrap (tmux, completion, hooks, daemons)")
+	fmt.Println("  completion       Print shell completion script (bash, zsh, fish)")
 	fmt.Println("  update           Check for and install updates")
 	fmt.Println("  debug-dump       Dump debug ring buffer to file for sharing")
+	fmt.Println("  debug            Diagnostics (status-history, classify: replay a pane capture)")
 	fmt.Println("  migrate-paths    Copy legacy ~/.agent-deck files into XDG paths")
 	fmt.Println("  uninstall        Uninstall Agent Deck")
 	fmt.Println("  version          Show version")
@@ -3220,6 +3490,56 @@ func printHelp() {
 	fmt.Println("  mcp attach <id> <mcp>     Attach MCP to session")
 	fmt.Println("  mcp detach <id> <mcp>     Detach MCP from session")
 	fmt.Println()
+	fmt.Println("Prompt Commands:")
+	fmt.Println("  prompt list               List saved prompts")
+	fmt.Println("  prompt save <name> <text> Save a reusable prompt")
+	fmt.Println("  prompt send <name> <id>   Send a saved prompt to a session")
+	fmt.Println()
+	fmt.Println("Theme Commands:")
+	fmt.Println("  theme list                List themes (* marks the active one)")
+	fmt.Println("  theme use <name>          Switch the active theme")
+	fmt.Println("  theme export <name>       Print a theme as a shareable theme file")
+	fmt.Println("  theme import <file>       Add a theme file to config.toml")
+	fmt.Println()
+	fmt.Println("Checkpoint Commands:")
+	fmt.Println("  checkpoint list <id>      List a session's checkpoints, newest first")
+	fmt.Println("  checkpoint create <id>    Snapshot the session's working tree now")
+	fmt.Println("  checkpoint rollback <id>  Restore a checkpoint (the current state is saved first)")
+	fmt.Println()
+	fmt.Println("Backup Commands:")
+	fmt.Println("  backup create [--to dest] Back up state.db and config.toml (dir or s3://bucket)")
+	fmt.Println("  backup list               List backups, newest first")
+	fmt.Println("  backup restore [name]     Restore the newest (or a named) backup")
+	fmt.Println()
+	fmt.Println("Extensions:")
+	fmt.Println("  <name> [args]             Run the agent-deck-<name> extension")
+	fmt.Println("  extension list            List discovered extensions")
+	fmt.Println()
+	fmt.Println("Schedule Commands:")
+	fmt.Println("  schedule add <cron> <id> <msg>  Send a message on a cron schedule")
+	fmt.Println("  schedule list                   List schedules with last run status")
+	fmt.Println("  schedule remove <sched-id>      Delete a schedule")
+	fmt.Println()
+	fmt.Println("Task Queue Commands:")
+	fmt.Println("  task add [--session id] <text>  Queue a work item")
+	fmt.Println("  task list [--all]               List open (or all) tasks")
+	fmt.Println("  task next [--dispatch]          Take the oldest queued task")
+	fmt.Println("  task done|fail <task-id>        Acknowledge a dispatched task")
+	fmt.Println()
+	fmt.Println("Port Commands:")
+	fmt.Println("  port claim <id> [count]         Claim ports, exported as PORT, PORT_2, ...")
+	fmt.Println("  port list [id]                  List claimed ports")
+	fmt.Println("  port release <id> [port...]     Release a session's ports")
+	fmt.Println()
+	fmt.Println("Governor Commands:")
+	fmt.Println("  governor [status]               Show active Claude sessions and queued sends")
+	fmt.Println("  governor drop <queue-id|all>    Drop queued sends without sending them")
+	fmt.Println()
+	fmt.Println("Auto-respond Commands:")
+	fmt.Println("  auto-respond rules              List the [auto_respond] rules")
+	fmt.Println("  auto-respond test <tool> <req>  Show which rule would answer a request")
+	fmt.Println("  auto-respond log [-n N]         Show the audit log of auto-responses")
+	fmt.Println()
 	fmt.Println("Skill Commands:")
 	fmt.Println("  skill list                List discoverable skills")
 	fmt.Println("  skill attached [id]       Show skills attached to a session")
@@ -3227,6 +3547,12 @@ func printHelp() {
 	fmt.Println("  skill detach <id> <name>  Detach skill from session project")
 	fmt.Println("  skill source list         List global skill sources")
 	fmt.Println()
+	fmt.Println("Claude Hook Commands:")
+	fmt.Println("  hooks install             Install Claude Code hooks")
+	fmt.Println("  hooks uninstall           Remove Claude Code hooks")
+	fmt.Println("  hooks status              Show hooks install status and version")
+	fmt.Println("  hooks sync                Re-install hooks written by another version")
+	fmt.Println()
 	fmt.Println("Codex Hook Commands:")
 	fmt.Println("  codex-hooks install       Install or upgrade Codex notify hook")
 	fmt.Println("  codex-hooks uninstall     Remove Codex notify hook")
@@ -3290,6 +3616,7 @@ func printHelp() {
 	fmt.Println("  agent-deck web --listen 127.0.0.1:9000  # TUI + web on a custom loopback port")
 	fmt.Println("  agent-deck web --read-only            # TUI + web in read-only mode")
 	fmt.Println("  agent-deck web --token secret         # auth token (REQUIRED to bind a non-loopback address)")
+	fmt.Println("  agent-deck web share my-proj --ttl 4h  # read-only link to one session for a teammate")
 	fmt.Println("  agent-deck web --help                 # Show web command flags")
 	fmt.Println()
 	fmt.Println("Environment Variables:")
@@ -3351,6 +3678,26 @@ func detectTool(cmd string) string {
 	return session.MatchTool(cmd)
 }
 
+// initTracing starts OpenTelemetry span export when [logs] tracing_enabled
+// or AGENTDECK_TRACING=1 asks for it. The returned func flushes pending
+// spans; call it after logging.Init so failures are logged.
+func initTracing() func() {
+	var ls session.LogSettings
+	if userCfg, err := session.LoadUserConfig(); err == nil {
+		ls = userCfg.Logs
+	}
+	shutdown, err := logging.InitTracing(context.Background(), ls.TracingConfig(Version))
+	if err != nil {
+		logging.Logger().Warn("tracing_init_failed", slog.String("error", err.Error()))
+		return func() {}
+	}
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		_ = shutdown(ctx)
+	}
+}
+
 // handleUninstall removes agent-deck from the system
 func handleDebugDump() {
 	cacheDir, err := ensureEffectiveCacheDir()
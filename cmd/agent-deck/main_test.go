@@ -159,6 +159,21 @@ func TestExtractProfileFlag_SubcommandBoundary(t *testing.T) {
 		}
 	})
 
+	t.Run("extension_owns_its_short_p", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "agent-deck-review"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("PATH", dir)
+		profile, args := extractProfileFlag([]string{"-p", "work", "review", "-p", "1"})
+		if profile != "work" {
+			t.Errorf("got profile=%q", profile)
+		}
+		if !slices.Equal(args, []string{"review", "-p", "1"}) {
+			t.Errorf("extension -p must survive, got %v", args)
+		}
+	})
+
 	t.Run("long_parent_form_unaffected", func(t *testing.T) {
 		profile, args := extractProfileFlag([]string{"launch", ".", "--parent", "PARENT123"})
 		if profile != "" {
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// errPromptEOF ends an interactive browse when stdin runs out.
+var errPromptEOF = errors.New("input closed")
+
+// handleMCPBrowse lists the curated MCP registry and installs picked entries
+// into [mcps] in config.toml.
+func handleMCPBrowse(args []string) {
+	fs := flag.NewFlagSet("mcp browse", flag.ExitOnError)
+	registry := fs.String("registry", session.DefaultMCPRegistryURL, "Registry index (https URL or local JSON file)")
+	jsonOutput := fs.Bool("json", false, "Print the matching registry entries as JSON and exit")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck mcp browse [query] [options]")
+		fmt.Println()
+		fmt.Println("Browse a curated registry of MCP servers and add them to config.toml.")
+		fmt.Println("You are prompted for any arguments and environment variables (API keys)")
+		fmt.Println("an entry needs. Installed MCPs can then be attached with 'mcp attach' or m.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck mcp browse")
+		fmt.Println("  agent-deck mcp browse github")
+		fmt.Println("  agent-deck mcp browse --registry ~/my-registry.json")
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	query := strings.Join(fs.Args(), " ")
+
+	reg, err := session.FetchMCPRegistry(context.Background(), nil, *registry)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	var entries []session.MCPRegistryEntry
+	for _, e := range reg.Servers {
+		if e.Matches(query) {
+			entries = append(entries, e)
+		}
+	}
+
+	if *jsonOutput {
+		data, _ := json.MarshalIndent(entries, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+	if len(entries) == 0 {
+		fmt.Printf("No registry entries match %q.\n", query)
+		return
+	}
+
+	config, err := session.LoadUserConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+	if !stdinStdoutIsTerminal() {
+		printMCPRegistryEntries(os.Stdout, entries, config.MCPs)
+		fmt.Println("\nRun 'agent-deck mcp browse' in a terminal to install entries.")
+		return
+	}
+
+	p := &mcpPrompter{
+		in:  bufio.NewReader(os.Stdin),
+		out: os.Stdout,
+		readSecret: func() (string, error) {
+			b, err := term.ReadPassword(int(os.Stdin.Fd()))
+			fmt.Println()
+			return string(b), err
+		},
+	}
+	added := browseMCPRegistry(p, entries, config.MCPs)
+	if len(added) == 0 {
+		fmt.Println("Nothing installed.")
+		return
+	}
+
+	if config.MCPs == nil {
+		config.MCPs = make(map[string]session.MCPDef)
+	}
+	for name, def := range added {
+		config.MCPs[name] = def
+	}
+	if err := session.SaveUserConfig(config); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to save config: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println()
+	for _, name := range slices.Sorted(maps.Keys(added)) {
+		fmt.Printf("✓ Added [mcps.%s] to config.toml\n", name)
+	}
+	fmt.Println("Attach with: agent-deck mcp attach <session> <name>  (or press m in the TUI)")
+}
+
+// mcpPrompter reads answers for mcp browse. readSecret, when set, reads a
+// value without echoing it.
+type mcpPrompter struct {
+	in         *bufio.Reader
+	out        io.Writer
+	readSecret func() (string, error)
+}
+
+// ask prints label (with def in brackets when set) and returns the trimmed
+// answer, or def for an empty one.
+func (p *mcpPrompter) ask(label, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", label, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", label)
+	}
+	line, err := p.in.ReadString('\n')
+	line = strings.TrimSpace(line)
+	if err != nil && line == "" {
+		return "", errPromptEOF
+	}
+	if line == "" {
+		return def, nil
+	}
+	return line, nil
+}
+
+// askSecret is ask without echo when the terminal allows it.
+func (p *mcpPrompter) askSecret(label string) (string, error) {
+	if p.readSecret == nil {
+		return p.ask(label, "")
+	}
+	fmt.Fprintf(p.out, "%s: ", label)
+	v, err := p.readSecret()
+	return strings.TrimSpace(v), err
+}
+
+// confirm asks a yes/no question; an empty answer means defYes.
+func (p *mcpPrompter) confirm(label string, defYes bool) (bool, error) {
+	hint := "y/N"
+	if defYes {
+		hint = "Y/n"
+	}
+	answer, err := p.ask(label+" ["+hint+"]", "")
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(answer) {
+	case "":
+		return defYes, nil
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// printMCPRegistryEntries prints the numbered entry list, marking names that
+// already exist in config.toml.
+func printMCPRegistryEntries(out io.Writer, entries []session.MCPRegistryEntry, installed map[string]session.MCPDef) {
+	for i, e := range entries {
+		mark := ""
+		if _, ok := installed[e.Name]; ok {
+			mark = " (installed)"
+		}
+		fmt.Fprintf(out, "%3d. %s%s\n", i+1, e.Name, mark)
+		if e.Description != "" {
+			fmt.Fprintf(out, "     %s\n", e.Description)
+		}
+	}
+}
+
+// browseMCPRegistry lists entries, lets the user pick some and prompts for
+// each one's inputs and environment variables. It returns the [mcps] entries
+// to add, keyed by name.
+func browseMCPRegistry(p *mcpPrompter, entries []session.MCPRegistryEntry, installed map[string]session.MCPDef) map[string]session.MCPDef {
+	printMCPRegistryEntries(p.out, entries, installed)
+	fmt.Fprintln(p.out)
+
+	var picks []int
+	for {
+		answer, err := p.ask("Install which? (numbers, comma-separated; Enter to quit)", "")
+		if err != nil || answer == "" {
+			return nil
+		}
+		picks, err = parseMCPPicks(answer, len(entries))
+		if err == nil {
+			break
+		}
+		fmt.Fprintf(p.out, "  %v\n", err)
+	}
+
+	added := make(map[string]session.MCPDef)
+	for _, i := range picks {
+		name, def, err := promptMCPRegistryEntry(p, entries[i], installed, added)
+		if errors.Is(err, errPromptEOF) {
+			break
+		}
+		if err != nil {
+			fmt.Fprintf(p.out, "  Skipped %s: %v\n", entries[i].Name, err)
+			continue
+		}
+		if name != "" {
+			added[name] = def
+		}
+	}
+	return added
+}
+
+// parseMCPPicks turns "1, 3 4" into zero-based indexes below n, without
+// duplicates.
+func parseMCPPicks(answer string, n int) ([]int, error) {
+	var picks []int
+	seen := make(map[int]bool)
+	for _, f := range strings.FieldsFunc(answer, func(r rune) bool { return r == ',' || r == ' ' }) {
+		i, err := strconv.Atoi(f)
+		if err != nil || i < 1 || i > n {
+			return nil, fmt.Errorf("%q is not a number between 1 and %d", f, n)
+		}
+		if !seen[i-1] {
+			seen[i-1] = true
+			picks = append(picks, i-1)
+		}
+	}
+	return picks, nil
+}
+
+// promptMCPRegistryEntry walks the user through installing e. An empty name
+// means the user declined.
+func promptMCPRegistryEntry(p *mcpPrompter, e session.MCPRegistryEntry, installed, added map[string]session.MCPDef) (string, session.MCPDef, error) {
+	fmt.Fprintf(p.out, "\n── %s ──\n", e.Name)
+	if e.Homepage != "" {
+		fmt.Fprintf(p.out, "  %s\n", e.Homepage)
+	}
+
+	name, err := p.ask("  Name in config.toml", e.Name)
+	if err != nil {
+		return "", session.MCPDef{}, err
+	}
+	if strings.ContainsAny(name, " .\"'") {
+		return "", session.MCPDef{}, fmt.Errorf("invalid name %q", name)
+	}
+	_, exists := installed[name]
+	if _, ok := added[name]; ok {
+		exists = true
+	}
+	if exists {
+		ok, err := p.confirm(fmt.Sprintf("  [mcps.%s] already exists. Overwrite?", name), false)
+		if err != nil || !ok {
+			return "", session.MCPDef{}, err
+		}
+	}
+
+	inputs := make(map[string]string)
+	for _, in := range e.Inputs {
+		label := "  " + in.Name
+		if in.Description != "" {
+			label += " — " + in.Description
+		}
+		v, err := p.ask(label, in.Default)
+		if err != nil {
+			return "", session.MCPDef{}, err
+		}
+		inputs[in.Name] = v
+	}
+
+	env := make(map[string]string)
+	for _, v := range e.Env {
+		label := "  " + v.Name
+		if v.Description != "" {
+			label += " — " + v.Description
+		}
+		if !v.Required {
+			label += " (optional)"
+		}
+		var val string
+		if v.Secret {
+			val, err = p.askSecret(label)
+		} else {
+			val, err = p.ask(label, v.Default)
+		}
+		if err != nil {
+			return "", session.MCPDef{}, err
+		}
+		if val == "" && v.Required {
+			return "", session.MCPDef{}, fmt.Errorf("%s is required", v.Name)
+		}
+		env[v.Name] = val
+	}
+
+	def := e.ToMCPDef(inputs, env)
+	if def.URL != "" {
+		fmt.Fprintf(p.out, "  → %s\n", def.URL)
+	} else {
+		fmt.Fprintf(p.out, "  → %s\n", strings.Join(append([]string{def.Command}, def.Args...), " "))
+	}
+	if len(def.Env) > 0 {
+		fmt.Fprintln(p.out, "  Environment values are stored in plain text in config.toml.")
+	}
+	ok, err := p.confirm("  Add to config.toml?", true)
+	if err != nil || !ok {
+		return "", session.MCPDef{}, err
+	}
+	return name, def, nil
+}
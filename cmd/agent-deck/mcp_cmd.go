@@ -1,8 +1,10 @@
 package main
 
 import (
+	"bufio"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -29,6 +31,10 @@ func handleMCP(profile string, args []string) {
 		handleMCPDetach(profile, args[1:])
 	case "server":
 		handleMCPServer(args[1:])
+	case "serve":
+		handleMCPServe(profile, args[1:])
+	case "browse":
+		handleMCPBrowse(args[1:])
 	case "help", "-h", "--help":
 		printMCPHelp()
 	default:
@@ -50,6 +56,8 @@ func printMCPHelp() {
 	fmt.Println("  attach <id> <mcp>   Attach an MCP to a session")
 	fmt.Println("  detach <id> <mcp>   Detach an MCP from a session")
 	fmt.Println("  server <cmd>        Manage HTTP MCP servers (start/stop/status)")
+	fmt.Println("  serve               Run agent-deck itself as a stdio MCP server")
+	fmt.Println("  browse [query]      Browse the MCP registry and add servers to config.toml")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  agent-deck mcp list                        # List available MCPs")
@@ -60,6 +68,8 @@ func printMCPHelp() {
 	fmt.Println("  agent-deck mcp detach my-project exa       # Detach exa from my-project")
 	fmt.Println("  agent-deck mcp server status               # Show HTTP server status")
 	fmt.Println("  agent-deck mcp server start slack          # Start HTTP server for slack MCP")
+	fmt.Println("  agent-deck mcp browse github               # Find and install a registry MCP")
+	fmt.Println("  claude mcp add agent-deck -- agent-deck mcp serve  # Let Claude manage the deck")
 }
 
 // handleMCPList lists all available MCPs from config.toml
@@ -349,6 +359,8 @@ func handleMCPAttach(profile string, args []string) {
 	quietShort := fs.Bool("q", false, "Minimal output (short)")
 	global := fs.Bool("global", false, "Attach to global config instead of local config (Codex always uses global)")
 	restart := fs.Bool("restart", false, "Restart session to load MCP immediately")
+	yes := fs.Bool("yes", false, "Write .mcp.json without showing the diff and asking for confirmation")
+	yesShort := fs.Bool("y", false, "Write .mcp.json without confirmation (short)")
 
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck mcp attach <session-id> <mcp-name> [options]")
@@ -363,6 +375,7 @@ func handleMCPAttach(profile string, args []string) {
 		fmt.Println("  agent-deck mcp attach my-project exa           # Attach locally (Codex uses global)")
 		fmt.Println("  agent-deck mcp attach my-project exa --global  # Attach globally")
 		fmt.Println("  agent-deck mcp attach my-project exa --restart # Attach and restart")
+		fmt.Println("  agent-deck mcp attach my-project exa --yes     # Skip the .mcp.json diff prompt")
 	}
 
 	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
@@ -428,6 +441,7 @@ func handleMCPAttach(profile string, args []string) {
 	}
 
 	// Attach the MCP
+	hotSwapped := false
 	if useGlobalConfig {
 		mcpInfo := inst.GetMCPInfo()
 		if mcpInfo == nil {
@@ -457,6 +471,11 @@ func handleMCPAttach(profile string, args []string) {
 			}
 		}
 		newLocal := append(mcpInfo.Local(), mcpName)
+		if !confirmLocalMCPWrite(inst, newLocal, *yes || *yesShort || *jsonOutput || quietMode) {
+			out.Error("cancelled; .mcp.json left unchanged", ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		hotSwapped = inst.MCPHotSwapActive()
 		if err := inst.WriteLocalMCPConfig(newLocal); err != nil {
 			out.Error(fmt.Sprintf("failed to write local MCP config: %v", err), ErrCodeInvalidOperation)
 			os.Exit(1)
@@ -465,9 +484,9 @@ func handleMCPAttach(profile string, args []string) {
 
 	inst.InvalidateProjectMCPIntegrationsCache()
 
-	// Restart if requested
+	// Restart if requested; the hot-swap hub already picked up the change.
 	restarted := false
-	if *restart && inst.SupportsMCPAgentRestart() {
+	if *restart && !hotSwapped && inst.SupportsMCPAgentRestart() {
 		if err := inst.Restart(); err != nil {
 			// Don't fail the whole operation, just warn
 			if !*jsonOutput && !quietMode {
@@ -487,15 +506,18 @@ func handleMCPAttach(profile string, args []string) {
 	// Output result
 	if *jsonOutput {
 		out.Print("", map[string]interface{}{
-			"success":   true,
-			"session":   inst.Title,
-			"mcp":       mcpName,
-			"scope":     scope,
-			"restarted": restarted,
+			"success":     true,
+			"session":     inst.Title,
+			"mcp":         mcpName,
+			"scope":       scope,
+			"restarted":   restarted,
+			"hot_swapped": hotSwapped,
 		})
 	} else {
 		message := fmt.Sprintf("Attached %s to %s (%s)", mcpName, inst.Title, scope)
-		if restarted {
+		if hotSwapped {
+			message += " - hot-swapped into running session"
+		} else if restarted {
 			message += " - session restarted"
 		}
 		out.Success(message, nil)
@@ -510,6 +532,8 @@ func handleMCPDetach(profile string, args []string) {
 	quietShort := fs.Bool("q", false, "Minimal output (short)")
 	global := fs.Bool("global", false, "Remove from global config instead of local config (Codex always uses global)")
 	restart := fs.Bool("restart", false, "Restart session to unload MCP immediately")
+	yes := fs.Bool("yes", false, "Write .mcp.json without showing the diff and asking for confirmation")
+	yesShort := fs.Bool("y", false, "Write .mcp.json without confirmation (short)")
 
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck mcp detach <session-id> <mcp-name> [options]")
@@ -524,6 +548,7 @@ func handleMCPDetach(profile string, args []string) {
 		fmt.Println("  agent-deck mcp detach my-project exa           # Detach from local (Codex uses global)")
 		fmt.Println("  agent-deck mcp detach my-project exa --global  # Detach from global")
 		fmt.Println("  agent-deck mcp detach my-project exa --restart # Detach and restart")
+		fmt.Println("  agent-deck mcp detach my-project exa --yes     # Skip the .mcp.json diff prompt")
 	}
 
 	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
@@ -576,6 +601,7 @@ func handleMCPDetach(profile string, args []string) {
 	}
 
 	// Detach the MCP
+	hotSwapped := false
 	if useGlobalConfig {
 		mcpInfo := inst.GetMCPInfo()
 		if mcpInfo == nil {
@@ -618,6 +644,11 @@ func handleMCPDetach(profile string, args []string) {
 			out.Error(fmt.Sprintf("MCP '%s' is not attached locally", mcpName), ErrCodeNotFound)
 			os.Exit(2)
 		}
+		if !confirmLocalMCPWrite(inst, newLocal, *yes || *yesShort || *jsonOutput || quietMode) {
+			out.Error("cancelled; .mcp.json left unchanged", ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		hotSwapped = inst.MCPHotSwapActive()
 		if err := inst.WriteLocalMCPConfig(newLocal); err != nil {
 			out.Error(fmt.Sprintf("failed to write local MCP config: %v", err), ErrCodeInvalidOperation)
 			os.Exit(1)
@@ -626,9 +657,9 @@ func handleMCPDetach(profile string, args []string) {
 
 	inst.InvalidateProjectMCPIntegrationsCache()
 
-	// Restart if requested
+	// Restart if requested; the hot-swap hub already picked up the change.
 	restarted := false
-	if *restart && inst.SupportsMCPAgentRestart() {
+	if *restart && !hotSwapped && inst.SupportsMCPAgentRestart() {
 		if err := inst.Restart(); err != nil {
 			// Don't fail the whole operation, just warn
 			if !*jsonOutput && !quietMode {
@@ -648,21 +679,56 @@ func handleMCPDetach(profile string, args []string) {
 	// Output result
 	if *jsonOutput {
 		out.Print("", map[string]interface{}{
-			"success":   true,
-			"session":   inst.Title,
-			"mcp":       mcpName,
-			"scope":     scope,
-			"restarted": restarted,
+			"success":     true,
+			"session":     inst.Title,
+			"mcp":         mcpName,
+			"scope":       scope,
+			"restarted":   restarted,
+			"hot_swapped": hotSwapped,
 		})
 	} else {
 		message := fmt.Sprintf("Detached %s from %s (%s)", mcpName, inst.Title, scope)
-		if restarted {
+		if hotSwapped {
+			message += " - hot-swapped into running session"
+		} else if restarted {
 			message += " - session restarted"
 		}
 		out.Success(message, nil)
 	}
 }
 
+// confirmLocalMCPWrite shows the pending .mcp.json diff and asks the user to
+// confirm it. Unmanaged servers are always preserved and the previous file is
+// kept as .mcp.json.bak, so non-interactive callers (or skip=true) proceed
+// without a prompt.
+func confirmLocalMCPWrite(inst *session.Instance, names []string, skip bool) bool {
+	if skip || !stdinStdoutIsTerminal() {
+		return true
+	}
+	change, ok, err := inst.PreviewLocalMCPConfig(names)
+	if err != nil || !ok {
+		return true
+	}
+	return confirmMCPJsonChange(change, os.Stdin, os.Stdout)
+}
+
+// confirmMCPJsonChange prints change as a diff and reads a y/N answer from in.
+// An unchanged file needs no confirmation.
+func confirmMCPJsonChange(change session.MCPJsonChange, in io.Reader, out io.Writer) bool {
+	if !change.Changed() {
+		return true
+	}
+	fmt.Fprintf(out, "Pending changes to %s:\n\n", change.Path)
+	fmt.Fprint(out, change.Diff())
+	if change.Before != nil {
+		fmt.Fprintf(out, "\nThe current file will be saved as %s.bak.\n", change.Path)
+	}
+	fmt.Fprint(out, "Write these changes? [y/N]: ")
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
 // handleMCPServer handles mcp server subcommands (start/stop/status)
 func handleMCPServer(args []string) {
 	if len(args) == 0 {
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+func TestConfirmMCPJsonChange(t *testing.T) {
+	change := session.MCPJsonChange{
+		Path:   "/proj/.mcp.json",
+		Before: []byte("{}\n"),
+		After:  []byte("{\"mcpServers\": {}}\n"),
+	}
+
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"y\n", true},
+		{"yes\n", true},
+		{"\n", false},
+		{"n\n", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if got := confirmMCPJsonChange(change, strings.NewReader(tt.input), &out); got != tt.want {
+			t.Errorf("input %q: got %v, want %v", tt.input, got, tt.want)
+		}
+		s := out.String()
+		if !strings.Contains(s, "- {}") || !strings.Contains(s, ".mcp.json.bak") {
+			t.Errorf("prompt missing diff or backup note:\n%s", s)
+		}
+	}
+}
+
+func TestConfirmMCPJsonChange_UnchangedSkipsPrompt(t *testing.T) {
+	change := session.MCPJsonChange{Path: "/proj/.mcp.json", Before: []byte("{}\n"), After: []byte("{}\n")}
+	var out bytes.Buffer
+	if !confirmMCPJsonChange(change, strings.NewReader(""), &out) {
+		t.Fatal("unchanged file should not need confirmation")
+	}
+	if out.Len() != 0 {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+}
+
+func TestBrowseMCPRegistry_PromptsAndBuildsDefs(t *testing.T) {
+	entries := []session.MCPRegistryEntry{
+		{Name: "exa", Command: "npx", Args: []string{"-y", "exa-mcp-server"},
+			Env: []session.MCPRegistryEnvVar{{Name: "EXA_API_KEY", Required: true, Secret: true}}},
+		{Name: "filesystem", Command: "npx", Args: []string{"{{dir}}"},
+			Inputs: []session.MCPRegistryInput{{Name: "dir", Default: "~"}}},
+		{Name: "memory", Command: "npx"},
+	}
+	installed := map[string]session.MCPDef{"memory": {Command: "old"}}
+
+	// Pick all three: exa gets a key, filesystem keeps its default dir under a
+	// new name, memory is already installed and the overwrite is declined.
+	input := strings.Join([]string{
+		"9", // out of range, re-asked
+		"1, 2 3",
+		"", "secret-key", "",
+		"fs", "", "y",
+		"", "n",
+	}, "\n") + "\n"
+	var out bytes.Buffer
+	p := &mcpPrompter{in: bufio.NewReader(strings.NewReader(input)), out: &out}
+	added := browseMCPRegistry(p, entries, installed)
+
+	if len(added) != 2 {
+		t.Fatalf("added = %+v\n%s", added, out.String())
+	}
+	if got := added["exa"].Env["EXA_API_KEY"]; got != "secret-key" {
+		t.Errorf("exa env = %q", got)
+	}
+	if got := added["fs"].Args; len(got) != 1 || got[0] != "~" {
+		t.Errorf("fs args = %v", got)
+	}
+	if !strings.Contains(out.String(), "(installed)") || !strings.Contains(out.String(), "between 1 and 3") {
+		t.Errorf("missing installed marker or range error:\n%s", out.String())
+	}
+}
+
+func TestBrowseMCPRegistry_RequiredEnvSkipsEntry(t *testing.T) {
+	entries := []session.MCPRegistryEntry{
+		{Name: "exa", Command: "npx", Env: []session.MCPRegistryEnvVar{{Name: "EXA_API_KEY", Required: true}}},
+	}
+	var out bytes.Buffer
+	p := &mcpPrompter{in: bufio.NewReader(strings.NewReader("1\n\n\n")), out: &out}
+	if added := browseMCPRegistry(p, entries, nil); len(added) != 0 {
+		t.Fatalf("entry without its required key should be skipped: %+v", added)
+	}
+	if !strings.Contains(out.String(), "EXA_API_KEY is required") {
+		t.Errorf("missing skip reason:\n%s", out.String())
+	}
+}
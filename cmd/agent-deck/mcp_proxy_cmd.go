@@ -1,10 +1,16 @@
 package main
 
 import (
+	"context"
 	"io"
+	"log/slog"
 	"net"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/mcphub"
 )
 
 // runMCPProxy is a bidirectional proxy between stdin/stdout and a Unix socket.
@@ -65,3 +71,16 @@ func runMCPProxy(socketPath string) {
 		time.Sleep(reconnectPause)
 	}
 }
+
+// runMCPHub serves the MCP hot-swap hub on stdin/stdout for the MCPs listed in
+// manifestPath. Claude launches it from .mcp.json when mcp_hot_swap is on;
+// logs go to stderr, which Claude keeps in its MCP logs.
+func runMCPHub(manifestPath string) {
+	log := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	if err := mcphub.New(manifestPath, log).Serve(ctx, os.Stdin, os.Stdout); err != nil {
+		log.Error("mcp_hub_failed", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,373 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// mcpServeProtocolVersion is advertised when the client does not request one.
+const mcpServeProtocolVersion = "2025-06-18"
+
+// JSON-RPC error codes used by `mcp serve`.
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+)
+
+// mcpCommandRunner runs one agent-deck CLI invocation (argv excludes the
+// binary) with stdin and returns its combined stdout. Tests substitute a fake.
+type mcpCommandRunner func(argv []string, stdin string) ([]byte, error)
+
+// mcpServeTool is one tool exposed by `agent-deck mcp serve`. Each tool maps
+// its arguments onto an existing `--json` CLI command so results are exactly
+// what scripts already get, just delivered as structured MCP content.
+type mcpServeTool struct {
+	name        string
+	description string
+	schema      map[string]any
+	argv        func(args map[string]any) (argv []string, stdin string, err error)
+}
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// handleMCPServe runs agent-deck as a stdio MCP server so a conductor (or any
+// MCP client) can manage the deck through typed tool calls.
+func handleMCPServe(profile string, args []string) {
+	if len(args) > 0 && isHelpArg(args[0]) {
+		fmt.Println("Usage: agent-deck mcp serve")
+		fmt.Println()
+		fmt.Println("Run agent-deck as an MCP server on stdin/stdout. Tools:")
+		for _, t := range mcpServeTools() {
+			fmt.Printf("  %-15s %s\n", t.name, t.description)
+		}
+		fmt.Println()
+		fmt.Println("Register it with Claude Code:")
+		fmt.Println("  claude mcp add agent-deck -- agent-deck mcp serve")
+		fmt.Println("Or in config.toml:")
+		fmt.Println("  [mcps.agent-deck]")
+		fmt.Println("  command = \"agent-deck\"")
+		fmt.Println("  args = [\"mcp\", \"serve\"]")
+		return
+	}
+
+	if err := serveMCP(os.Stdin, os.Stdout, selfCommandRunner(profile)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// selfCommandRunner runs this agent-deck binary against profile. When a
+// command fails without printing anything, its stderr becomes the error.
+func selfCommandRunner(profile string) mcpCommandRunner {
+	self, err := os.Executable()
+	if err != nil {
+		self = "agent-deck"
+	}
+	return func(argv []string, stdin string) ([]byte, error) {
+		if profile != "" {
+			argv = append([]string{"-p", profile}, argv...)
+		}
+		cmd := exec.Command(self, argv...)
+		cmd.Stdin = strings.NewReader(stdin)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil && len(bytes.TrimSpace(out)) == 0 {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return nil, errors.New(msg)
+			}
+		}
+		return out, err
+	}
+}
+
+// serveMCP reads newline-delimited JSON-RPC requests from in and writes
+// responses to out until in is exhausted.
+func serveMCP(in io.Reader, out io.Writer, run mcpCommandRunner) error {
+	tools := mcpServeTools()
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	enc := json.NewEncoder(out)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var req rpcRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			if err := enc.Encode(rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: rpcParseError, Message: err.Error()}}); err != nil {
+				return err
+			}
+			continue
+		}
+		// Notifications (no id) never get a response.
+		if len(req.ID) == 0 {
+			continue
+		}
+		resp := rpcResponse{JSONRPC: "2.0", ID: req.ID}
+		resp.Result, resp.Error = dispatchMCPRequest(req, tools, run)
+		if err := enc.Encode(resp); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func dispatchMCPRequest(req rpcRequest, tools []mcpServeTool, run mcpCommandRunner) (any, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(req.Params, &params)
+		version := params.ProtocolVersion
+		if version == "" {
+			version = mcpServeProtocolVersion
+		}
+		return map[string]any{
+			"protocolVersion": version,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]any{"name": "agent-deck", "version": Version},
+		}, nil
+	case "ping":
+		return map[string]any{}, nil
+	case "tools/list":
+		list := make([]map[string]any, 0, len(tools))
+		for _, t := range tools {
+			list = append(list, map[string]any{
+				"name":        t.name,
+				"description": t.description,
+				"inputSchema": t.schema,
+			})
+		}
+		return map[string]any{"tools": list}, nil
+	case "tools/call":
+		var params struct {
+			Name      string         `json:"name"`
+			Arguments map[string]any `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+		}
+		for _, t := range tools {
+			if t.name == params.Name {
+				return callMCPTool(t, params.Arguments, run), nil
+			}
+		}
+		return nil, &rpcError{Code: rpcInvalidParams, Message: fmt.Sprintf("unknown tool %q", params.Name)}
+	case "":
+		return nil, &rpcError{Code: rpcInvalidRequest, Message: "missing method"}
+	default:
+		return nil, &rpcError{Code: rpcMethodNotFound, Message: fmt.Sprintf("method %q not found", req.Method)}
+	}
+}
+
+// callMCPTool runs t and packages the CLI's JSON output as an MCP tool result.
+// Tool failures are reported in-band (isError) per the MCP spec, not as
+// JSON-RPC errors.
+func callMCPTool(t mcpServeTool, args map[string]any, run mcpCommandRunner) map[string]any {
+	argv, stdin, err := t.argv(args)
+	if err != nil {
+		return mcpToolError(err.Error())
+	}
+	out, runErr := run(argv, stdin)
+	text := strings.TrimSpace(string(out))
+	if runErr != nil {
+		if text == "" {
+			text = runErr.Error()
+		}
+		return mcpToolError(text)
+	}
+
+	result := map[string]any{
+		"content": []map[string]any{{"type": "text", "text": text}},
+	}
+	var parsed any
+	if err := json.Unmarshal(out, &parsed); err == nil {
+		if obj, ok := parsed.(map[string]any); ok {
+			result["structuredContent"] = obj
+		} else {
+			result["structuredContent"] = map[string]any{"result": parsed}
+		}
+	}
+	return result
+}
+
+func mcpToolError(msg string) map[string]any {
+	return map[string]any{
+		"content": []map[string]any{{"type": "text", "text": msg}},
+		"isError": true,
+	}
+}
+
+// mcpServeTools is the tool catalog for `mcp serve`.
+func mcpServeTools() []mcpServeTool {
+	sessionProp := map[string]any{"type": "string", "description": "Session ID or title"}
+	return []mcpServeTool{
+		{
+			name:        "list_sessions",
+			description: "List sessions in the current profile with status, tool, path and group",
+			schema:      map[string]any{"type": "object", "properties": map[string]any{}},
+			argv: func(map[string]any) ([]string, string, error) {
+				return []string{"list", "--json"}, "", nil
+			},
+		},
+		{
+			name:        "get_status",
+			description: "Get status counts for the profile, or full details for one session",
+			schema: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"session": sessionProp},
+			},
+			argv: func(args map[string]any) ([]string, string, error) {
+				if id := mcpStringArg(args, "session"); id != "" {
+					return []string{"session", "show", id, "--json"}, "", nil
+				}
+				return []string{"status", "--json"}, "", nil
+			},
+		},
+		{
+			name:        "send_message",
+			description: "Send a message to a running session; with wait=true, block until it finishes and return its output",
+			schema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"session": sessionProp,
+					"message": map[string]any{"type": "string", "description": "Message text"},
+					"wait":    map[string]any{"type": "boolean", "description": "Wait for the agent to finish and return its output"},
+				},
+				"required": []string{"session", "message"},
+			},
+			argv: func(args map[string]any) ([]string, string, error) {
+				id, msg := mcpStringArg(args, "session"), mcpStringArg(args, "message")
+				if id == "" || msg == "" {
+					return nil, "", errors.New("session and message are required")
+				}
+				argv := []string{"session", "send", id, "--message-file", "-", "--json"}
+				if mcpBoolArg(args, "wait") {
+					argv = append(argv, "--wait")
+				}
+				return argv, msg, nil
+			},
+		},
+		{
+			name:        "create_session",
+			description: "Create and start a session in a project directory, optionally sending an initial message",
+			schema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"path":    map[string]any{"type": "string", "description": "Project directory"},
+					"title":   map[string]any{"type": "string", "description": "Session title"},
+					"group":   map[string]any{"type": "string", "description": "Group path"},
+					"tool":    map[string]any{"type": "string", "description": "Tool/command, e.g. claude or codex"},
+					"message": map[string]any{"type": "string", "description": "Initial message"},
+				},
+				"required": []string{"path"},
+			},
+			argv: func(args map[string]any) ([]string, string, error) {
+				path := mcpStringArg(args, "path")
+				if path == "" {
+					return nil, "", errors.New("path is required")
+				}
+				argv := []string{"launch", path, "--json"}
+				for _, f := range []struct{ key, flag string }{{"title", "-t"}, {"group", "-g"}, {"tool", "-c"}} {
+					if v := mcpStringArg(args, f.key); v != "" {
+						argv = append(argv, f.flag, v)
+					}
+				}
+				msg := mcpStringArg(args, "message")
+				if msg != "" {
+					argv = append(argv, "--message-file", "-")
+				}
+				return argv, msg, nil
+			},
+		},
+		{
+			name:        "send_to_session",
+			description: "Send a message from this session (or from) to another session's mailbox; it is also typed into the recipient's pane when it is ready",
+			schema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"to":      sessionProp,
+					"message": map[string]any{"type": "string", "description": "Message text"},
+					"from":    map[string]any{"type": "string", "description": "Sender session ID or title (default: this session)"},
+				},
+				"required": []string{"to", "message"},
+			},
+			argv: func(args map[string]any) ([]string, string, error) {
+				to, msg := mcpStringArg(args, "to"), mcpStringArg(args, "message")
+				if to == "" || msg == "" {
+					return nil, "", errors.New("to and message are required")
+				}
+				from := mcpStringArg(args, "from")
+				if from == "" {
+					from = "self"
+				}
+				return []string{"session", "send-to", from, to, "--message-file", "-", "--json"}, msg, nil
+			},
+		},
+		{
+			name:        "read_mailbox",
+			description: "Read this session's (or session's) unread messages from other sessions and mark them read",
+			schema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"session": map[string]any{"type": "string", "description": "Session ID or title (default: this session)"},
+					"all":     map[string]any{"type": "boolean", "description": "Include messages already read"},
+					"peek":    map[string]any{"type": "boolean", "description": "Don't mark the messages read"},
+				},
+			},
+			argv: func(args map[string]any) ([]string, string, error) {
+				id := mcpStringArg(args, "session")
+				if id == "" {
+					id = "self"
+				}
+				argv := []string{"session", "mailbox", id, "--json"}
+				if mcpBoolArg(args, "all") {
+					argv = append(argv, "--all")
+				}
+				if mcpBoolArg(args, "peek") {
+					argv = append(argv, "--peek")
+				}
+				return argv, "", nil
+			},
+		},
+	}
+}
+
+func mcpStringArg(args map[string]any, key string) string {
+	s, _ := args[key].(string)
+	return strings.TrimSpace(s)
+}
+
+func mcpBoolArg(args map[string]any, key string) bool {
+	b, _ := args[key].(bool)
+	return b
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type fakeMCPRun struct {
+	argv  []string
+	stdin string
+	out   string
+	err   error
+}
+
+func (f *fakeMCPRun) run(argv []string, stdin string) ([]byte, error) {
+	f.argv, f.stdin = argv, stdin
+	return []byte(f.out), f.err
+}
+
+func serveMCPLines(t *testing.T, run mcpCommandRunner, lines ...string) []map[string]any {
+	t.Helper()
+	var out bytes.Buffer
+	if err := serveMCP(strings.NewReader(strings.Join(lines, "\n")+"\n"), &out, run); err != nil {
+		t.Fatalf("serveMCP: %v", err)
+	}
+	var resps []map[string]any
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var m map[string]any
+		if err := dec.Decode(&m); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		resps = append(resps, m)
+	}
+	return resps
+}
+
+func TestServeMCP_HandshakeAndToolsList(t *testing.T) {
+	f := &fakeMCPRun{}
+	resps := serveMCPLines(t, f.run,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"bogus"}`,
+	)
+	if len(resps) != 3 {
+		t.Fatalf("expected 3 responses (notification gets none), got %d", len(resps))
+	}
+
+	init := resps[0]["result"].(map[string]any)
+	if init["protocolVersion"] != "2025-03-26" {
+		t.Errorf("protocolVersion = %v", init["protocolVersion"])
+	}
+
+	var names []string
+	for _, tool := range resps[1]["result"].(map[string]any)["tools"].([]any) {
+		names = append(names, tool.(map[string]any)["name"].(string))
+	}
+	want := []string{"list_sessions", "get_status", "send_message", "create_session", "send_to_session", "read_mailbox"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("tools = %v, want %v", names, want)
+	}
+
+	if code := resps[2]["error"].(map[string]any)["code"].(float64); code != rpcMethodNotFound {
+		t.Errorf("error code = %v, want %d", code, rpcMethodNotFound)
+	}
+}
+
+func TestServeMCP_ToolCallStructuredResult(t *testing.T) {
+	f := &fakeMCPRun{out: `[{"id":"abc","title":"api","status":"waiting"}]`}
+	resps := serveMCPLines(t, f.run,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"list_sessions","arguments":{}}}`,
+	)
+	if !reflect.DeepEqual(f.argv, []string{"list", "--json"}) {
+		t.Fatalf("argv = %v", f.argv)
+	}
+	result := resps[0]["result"].(map[string]any)
+	sc := result["structuredContent"].(map[string]any)
+	sessions := sc["result"].([]any)
+	if sessions[0].(map[string]any)["title"] != "api" {
+		t.Fatalf("structuredContent = %v", sc)
+	}
+}
+
+func TestServeMCP_SendMessageUsesStdin(t *testing.T) {
+	f := &fakeMCPRun{out: `{"success":true}`}
+	serveMCPLines(t, f.run,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"send_message","arguments":{"session":"api","message":"run \"tests\"","wait":true}}}`,
+	)
+	want := []string{"session", "send", "api", "--message-file", "-", "--json", "--wait"}
+	if !reflect.DeepEqual(f.argv, want) {
+		t.Fatalf("argv = %v, want %v", f.argv, want)
+	}
+	if f.stdin != `run "tests"` {
+		t.Fatalf("stdin = %q", f.stdin)
+	}
+}
+
+func TestServeMCP_MailboxToolsDefaultToSelf(t *testing.T) {
+	f := &fakeMCPRun{out: `{"success":true}`}
+	serveMCPLines(t, f.run,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"send_to_session","arguments":{"to":"parent","message":"done: PR #412"}}}`,
+	)
+	want := []string{"session", "send-to", "self", "parent", "--message-file", "-", "--json"}
+	if !reflect.DeepEqual(f.argv, want) || f.stdin != "done: PR #412" {
+		t.Fatalf("send_to_session argv = %v, stdin = %q", f.argv, f.stdin)
+	}
+
+	serveMCPLines(t, f.run,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"read_mailbox","arguments":{"peek":true}}}`,
+	)
+	want = []string{"session", "mailbox", "self", "--json", "--peek"}
+	if !reflect.DeepEqual(f.argv, want) {
+		t.Fatalf("read_mailbox argv = %v, want %v", f.argv, want)
+	}
+}
+
+func TestServeMCP_ToolErrorsAreInBand(t *testing.T) {
+	f := &fakeMCPRun{out: `{"success":false,"error":"session not found"}`, err: errors.New("exit status 2")}
+	resps := serveMCPLines(t, f.run,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_status","arguments":{"session":"nope"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"send_message","arguments":{"session":"x"}}}`,
+	)
+	for i, r := range resps {
+		if r["error"] != nil {
+			t.Fatalf("response %d: tool failure surfaced as JSON-RPC error: %v", i, r["error"])
+		}
+		if r["result"].(map[string]any)["isError"] != true {
+			t.Fatalf("response %d: expected isError, got %v", i, r["result"])
+		}
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"io"
 	"os"
 	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/send"
+	"github.com/asheshgoplani/agent-deck/internal/session"
 )
 
 // resolveMessageInput merges the inline -m/--message value with --message-file.
@@ -38,3 +41,45 @@ func resolveMessageInput(inline, file string, stdin io.Reader) (string, error) {
 	}
 	return msg, nil
 }
+
+// lintSendMessage runs the [send_lint] checks on message and reports the
+// result with a summary of what would be typed into the session. It returns
+// false when the send should be refused. With checkOnly (--check) a clean
+// prompt is reported as a success instead of passing silently.
+func lintSendMessage(out *CLIOutput, message string, checkOnly bool) bool {
+	cfg := session.GetSendLintSettings()
+	issues := send.LintPrompt(message, send.LintOptions{
+		MinChars:     cfg.MinChars,
+		MaxBytes:     cfg.MaxBytes,
+		Placeholders: cfg.Placeholders,
+	})
+	summary := send.SummarizePrompt(message)
+
+	if len(issues) == 0 {
+		if checkOnly {
+			out.Success("Prompt OK: "+summary.String(), map[string]interface{}{
+				"success": true,
+				"summary": summary,
+			})
+		}
+		return true
+	}
+
+	msgs := make([]string, len(issues))
+	for i, issue := range issues {
+		msgs[i] = issue.Message
+	}
+	hint := " (use --force to send anyway)"
+	if checkOnly {
+		hint = ""
+	}
+	out.ErrorWithData(fmt.Sprintf("prompt check failed: %s%s", strings.Join(msgs, "; "), hint),
+		ErrCodePromptLint, map[string]interface{}{
+			"issues":  issues,
+			"summary": summary,
+		})
+	if !out.jsonMode {
+		fmt.Fprintf(os.Stderr, "Would send: %s\n", summary)
+	}
+	return false
+}
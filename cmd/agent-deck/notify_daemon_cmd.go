@@ -41,6 +41,7 @@ func handleNotifyDaemon(args []string) {
 	// That blind spot is exactly why the 2026-06-18 wake regression could not be
 	// diagnosed from the logs. See initDaemonLogging.
 	defer initDaemonLogging()()
+	defer initTracing()()
 
 	// One unconditional startup line so an operator can confirm the daemon is
 	// alive AND that its logging pipeline works (the absence of which hid the
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// handlePort dispatches the port registry subcommands.
+func handlePort(profile string, args []string) {
+	if len(args) == 0 {
+		printPortHelp()
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "claim":
+		handlePortClaim(profile, args[1:])
+	case "list", "ls":
+		handlePortList(profile, args[1:])
+	case "release":
+		handlePortRelease(profile, args[1:])
+	case "help", "-h", "--help":
+		printPortHelp()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown port command '%s'\n", args[0])
+		printPortHelp()
+		os.Exit(1)
+	}
+}
+
+func printPortHelp() {
+	fmt.Println("Usage: agent-deck port <command> [options]")
+	fmt.Println()
+	fmt.Println("A registry of TCP ports held by sessions, so two worktree copies of the")
+	fmt.Println("same app never fight over one port. A session's ports are exported as")
+	fmt.Println("PORT, PORT_2, ... and AGENTDECK_PORTS (comma-separated) when it starts,")
+	fmt.Println("and set on its tmux session right away for new panes. Ports come from")
+	fmt.Println("[ports] range_start..range_end (default 4000-4999), skipping ones in use.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  claim <id> [count]        Claim count more ports (default 1)")
+	fmt.Println("  list [id]                 List claimed ports (all sessions, or one)")
+	fmt.Println("  release <id> [port...]    Release the given ports, or all of the session's")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck port claim api-try-1 2")
+	fmt.Println("  agent-deck port list")
+	fmt.Println("  agent-deck port release api-try-1 4001")
+}
+
+// handlePortClaim claims ports for a session.
+func handlePortClaim(profile string, args []string) {
+	fs := flag.NewFlagSet("port claim", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode (print only the ports)")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck port claim <id> [count]")
+		fmt.Println()
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		out.Error("port claim requires <id> [count]", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	count := 1
+	if fs.NArg() == 2 {
+		n, err := strconv.Atoi(fs.Arg(1))
+		if err != nil || n < 1 {
+			out.Error(fmt.Sprintf("invalid count %q", fs.Arg(1)), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		count = n
+	}
+
+	storage, instances, db := openTaskDB(profile, out)
+	defer storage.Close()
+	inst := resolveTaskSession(fs.Arg(0), instances, out)
+	claimed, err := session.ClaimPorts(db, inst.ID, count, time.Now())
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	claims, _ := db.LoadPortClaims(inst.ID)
+	inst.SyncPortEnv(claims, nil)
+
+	if *quiet && !*jsonOutput {
+		for _, c := range claimed {
+			fmt.Println(c.Port)
+		}
+		return
+	}
+	out.Success(fmt.Sprintf("Claimed %s for %s (restart the session for the agent to see them)",
+		formatPortClaims(claimed), inst.Title), map[string]interface{}{
+		"success":    true,
+		"session_id": inst.ID,
+		"session":    inst.Title,
+		"claimed":    portClaimsJSON(claimed, nil),
+		"ports":      portClaimsJSON(claims, nil),
+	})
+}
+
+// handlePortList prints the claimed ports, of one session or all.
+func handlePortList(profile string, args []string) {
+	fs := flag.NewFlagSet("port list", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	storage, instances, db := openTaskDB(profile, out)
+	defer storage.Close()
+	instanceID := ""
+	if fs.NArg() > 0 {
+		instanceID = resolveTaskSession(fs.Arg(0), instances, out).ID
+	}
+	claims, err := db.LoadPortClaims(instanceID)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to read port claims: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	titles := sessionTitles(instances)
+
+	var b strings.Builder
+	if len(claims) == 0 {
+		b.WriteString("No ports claimed.\n")
+	} else {
+		fmt.Fprintf(&b, "%-6s  %-8s  %s\n", "PORT", "ENV", "SESSION")
+		for _, c := range claims {
+			title := titles[c.InstanceID]
+			if title == "" {
+				title = c.InstanceID + " (removed)"
+			}
+			fmt.Fprintf(&b, "%-6d  %-8s  %s\n", c.Port, c.Name, title)
+		}
+	}
+	out.Print(b.String(), map[string]interface{}{
+		"success": true,
+		"ports":   portClaimsJSON(claims, titles),
+	})
+}
+
+// handlePortRelease releases some or all of a session's ports.
+func handlePortRelease(profile string, args []string) {
+	fs := flag.NewFlagSet("port release", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+	if fs.NArg() < 1 {
+		out.Error("port release requires <id> [port...]", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	var ports []int
+	for _, arg := range fs.Args()[1:] {
+		p, err := strconv.Atoi(strings.TrimPrefix(arg, ":"))
+		if err != nil || p < 1 || p > 65535 {
+			out.Error(fmt.Sprintf("invalid port %q", arg), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		ports = append(ports, p)
+	}
+
+	storage, instances, db := openTaskDB(profile, out)
+	defer storage.Close()
+	inst := resolveTaskSession(fs.Arg(0), instances, out)
+	before, err := db.LoadPortClaims(inst.ID)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to read port claims: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	n, err := db.ReleasePorts(inst.ID, ports...)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to release ports: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if n == 0 {
+		out.Error(fmt.Sprintf("%s holds none of those ports", inst.Title), ErrCodeNotFound)
+		os.Exit(2)
+	}
+	claims, _ := db.LoadPortClaims(inst.ID)
+	inst.SyncPortEnv(claims, before)
+
+	out.Success(fmt.Sprintf("Released %d port(s) of %s", n, inst.Title), map[string]interface{}{
+		"success":    true,
+		"session_id": inst.ID,
+		"released":   n,
+		"ports":      portClaimsJSON(claims, nil),
+	})
+}
+
+func formatPortClaims(claims []*statedb.PortClaim) string {
+	parts := make([]string, len(claims))
+	for i, c := range claims {
+		parts[i] = fmt.Sprintf("%d (%s)", c.Port, c.Name)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func portClaimsJSON(claims []*statedb.PortClaim, titles map[string]string) []map[string]interface{} {
+	items := make([]map[string]interface{}, 0, len(claims))
+	for _, c := range claims {
+		item := map[string]interface{}{
+			"port":       c.Port,
+			"env":        c.Name,
+			"session_id": c.InstanceID,
+			"claimed_at": c.ClaimedAt,
+		}
+		if titles != nil {
+			item["session"] = titles[c.InstanceID]
+		}
+		items = append(items, item)
+	}
+	return items
+}
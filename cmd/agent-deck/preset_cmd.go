@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// applyCLIPreset switches a session being created to a start preset and
+// folds the preset's MCP set into the --mcp list, so the later MCP write
+// keeps both. setsMCPs reports whether the preset owns the MCP set, in which
+// case group default MCPs are not layered on top.
+func applyCLIPreset(inst *session.Instance, name string, mcpFlags []string) (mcps []string, setsMCPs bool, err error) {
+	name = strings.TrimSpace(name)
+	if inst == nil || name == "" {
+		return mcpFlags, false, nil
+	}
+	p, err := session.LookupStartPreset(name)
+	if err != nil {
+		return mcpFlags, false, err
+	}
+	if err := inst.ApplyStartPreset(name); err != nil {
+		return mcpFlags, false, err
+	}
+	presetMCPs, setsMCPs := p.MCPNames()
+	if !setsMCPs || len(mcpFlags) == 0 {
+		return mcpFlags, setsMCPs, nil
+	}
+	seen := make(map[string]bool, len(presetMCPs)+len(mcpFlags))
+	for _, name := range append(presetMCPs, mcpFlags...) {
+		if !seen[name] {
+			seen[name] = true
+			mcps = append(mcps, name)
+		}
+	}
+	return mcps, true, nil
+}
+
+// handleSessionPresets lists the start presets usable with --preset.
+func handleSessionPresets(args []string) {
+	fs := flag.NewFlagSet("session presets", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck session presets [options]")
+		fmt.Println()
+		fmt.Println("List the start presets for `add/launch/session start --preset` and")
+		fmt.Println("`session set <id> preset <name>`. Define or override one with")
+		fmt.Println("[presets.<name>] in config.toml.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+
+	presets := session.GetStartPresets()
+	var sb strings.Builder
+	var rows []map[string]interface{}
+	for _, name := range session.StartPresetNames(presets) {
+		p := presets[name]
+		mcps := "unchanged"
+		switch {
+		case len(p.MCPs) == 1 && p.MCPs[0] == session.AllMCPsPreset:
+			mcps = "all"
+		case p.MCPs != nil && len(p.MCPs) == 0:
+			mcps = "none"
+		case p.MCPs != nil:
+			mcps = strings.Join(p.MCPs, ", ")
+		}
+		model := p.ModelFor("claude")
+		if model == "" {
+			model = "default"
+		}
+		fmt.Fprintf(&sb, "%-10s model %-8s MCPs %-10s %s\n", name, model, mcps, p.Description)
+		rows = append(rows, map[string]interface{}{
+			"name":        name,
+			"description": p.Description,
+			"model":       p.Model,
+			"mcps":        p.MCPs,
+			"tools":       p.Tools,
+		})
+	}
+	out.Print(sb.String(), map[string]interface{}{"presets": rows})
+}
@@ -288,3 +288,98 @@ func TestConductorMoveToProfile_RequiresToProfile(t *testing.T) {
 		t.Errorf("stderr should mention --to-profile; got %s", stderr)
 	}
 }
+
+// TestSessionMoveToProfile_ProfileAliasGroupAndTitle — `--profile` is an alias
+// for --to-profile, and --to-group / --title are applied in the destination.
+func TestSessionMoveToProfile_ProfileAliasGroupAndTitle(t *testing.T) {
+	if testing.Short() {
+		t.Skip("subprocess CLI test skipped in short mode")
+	}
+	home := t.TempDir()
+	bootstrapProfile(t, home, "src")
+	bootstrapProfile(t, home, "dst")
+	id := addInProfile(t, home, "src", "alias-migrate", filepath.Join(home, "proj"))
+
+	stdout, stderr, code := runAgentDeck(t, home,
+		"-p", "src", "session", "move", id,
+		"--profile", "dst",
+		"--to-group", "review",
+		"--title", "alias-migrated",
+		"--json",
+	)
+	if code != 0 {
+		t.Fatalf("migrate failed: code=%d\nstdout: %s\nstderr: %s", code, stdout, stderr)
+	}
+	var resp struct {
+		Title string `json:"title"`
+		Group string `json:"group"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &resp); err != nil {
+		t.Fatalf("parse response: %v\nstdout: %s", err, stdout)
+	}
+	if resp.Title != "alias-migrated" || resp.Group != "review" {
+		t.Errorf("got title=%q group=%q, want alias-migrated/review", resp.Title, resp.Group)
+	}
+
+	dstList := listJSONForProfile(t, home, "dst")
+	if !strings.Contains(dstList, "alias-migrated") || !strings.Contains(dstList, "review") {
+		t.Errorf("dst list missing renamed/regrouped session: %s", dstList)
+	}
+}
+
+// TestSessionMove_ProfileOnlyFlagsRequireToProfile — --live/--to-group/--title
+// are meaningless for a path move and must be rejected.
+func TestSessionMove_ProfileOnlyFlagsRequireToProfile(t *testing.T) {
+	if testing.Short() {
+		t.Skip("subprocess CLI test skipped in short mode")
+	}
+	home := t.TempDir()
+	bootstrapProfile(t, home, "src")
+	id := addInProfile(t, home, "src", "path-move", filepath.Join(home, "p"))
+
+	stdout, stderr, code := runAgentDeck(t, home,
+		"-p", "src", "session", "move", id, filepath.Join(home, "q"), "--live", "--json")
+	if code == 0 {
+		t.Fatalf("expected failure for --live without --to-profile; stdout=%s", stdout)
+	}
+	if !strings.Contains(stdout+stderr, "require --to-profile") {
+		t.Errorf("unexpected error output: stdout=%s stderr=%s", stdout, stderr)
+	}
+}
+
+// TestSessionMoveToProfile_RenameTmuxStoppedSession — --rename-tmux on a
+// stopped session migrates it and reports that there was nothing to rename.
+func TestSessionMoveToProfile_RenameTmuxStoppedSession(t *testing.T) {
+	if testing.Short() {
+		t.Skip("subprocess CLI test skipped in short mode")
+	}
+	home := t.TempDir()
+	bootstrapProfile(t, home, "src")
+	bootstrapProfile(t, home, "dst")
+	id := addInProfile(t, home, "src", "rename-stopped", filepath.Join(home, "proj"))
+
+	stdout, stderr, code := runAgentDeck(t, home,
+		"-p", "src", "session", "move", id,
+		"--to-profile", "dst",
+		"--rename-tmux",
+		"--json",
+	)
+	if code != 0 {
+		t.Fatalf("migrate failed: code=%d\nstdout: %s\nstderr: %s", code, stdout, stderr)
+	}
+	var resp struct {
+		TmuxSession string `json:"tmux_session"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &resp); err != nil {
+		t.Fatalf("parse response: %v\nstdout: %s", err, stdout)
+	}
+	if resp.TmuxSession != "" {
+		t.Errorf("tmux_session = %q, want empty for a stopped session", resp.TmuxSession)
+	}
+	if !strings.Contains(stderr, "no tmux session to rename") {
+		t.Errorf("expected a note about the stopped session, stderr: %s", stderr)
+	}
+	if !strings.Contains(listJSONForProfile(t, home, "dst"), id) {
+		t.Errorf("session %s not in dst after migration", id)
+	}
+}
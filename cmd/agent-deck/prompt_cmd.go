@@ -0,0 +1,321 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// promptVarFlags implements flag.Value for repeatable --var NAME=VALUE flags.
+type promptVarFlags map[string]string
+
+var promptVarNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func (v promptVarFlags) String() string { return "" }
+func (v promptVarFlags) Set(val string) error {
+	name, value, ok := strings.Cut(val, "=")
+	if !ok || !promptVarNameRe.MatchString(name) {
+		return fmt.Errorf("invalid variable %q, expected NAME=VALUE", val)
+	}
+	v[name] = value
+	return nil
+}
+
+// handlePrompt dispatches prompt library subcommands.
+func handlePrompt(profile string, args []string) {
+	if len(args) == 0 {
+		printPromptHelp()
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "list", "ls":
+		handlePromptList(args[1:])
+	case "save":
+		handlePromptSave(args[1:])
+	case "remove", "rm":
+		handlePromptRemove(args[1:])
+	case "send":
+		handlePromptSend(profile, args[1:])
+	case "help", "-h", "--help":
+		printPromptHelp()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown prompt command '%s'\n", args[0])
+		printPromptHelp()
+		os.Exit(1)
+	}
+}
+
+func printPromptHelp() {
+	fmt.Println("Usage: agent-deck prompt <command> [options]")
+	fmt.Println()
+	fmt.Println("Keep a library of reusable prompts and send them to sessions.")
+	fmt.Println("Prompts live in [prompts.<name>] in config.toml. {path}, {branch}, {title},")
+	fmt.Println("{group}, {tool} and {id} are filled in from the target session; any other")
+	fmt.Println("{name} needs --var name=value.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  list                   List saved prompts")
+	fmt.Println("  save <name> <text>     Save (or replace) a prompt")
+	fmt.Println("  remove <name>          Delete a prompt")
+	fmt.Println("  send <name> <session>  Send a prompt to a session")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck prompt save tests \"Run the tests and fix any failures\"")
+	fmt.Println("  agent-deck prompt save rebase \"Rebase {branch} onto {base} and resolve conflicts\"")
+	fmt.Println("  agent-deck prompt send tests my-project")
+	fmt.Println("  agent-deck prompt send rebase my-project --var base=main --wait")
+	fmt.Println()
+	fmt.Println("In the TUI, press Alt+l to pick a prompt for the selected session.")
+}
+
+// handlePromptList prints the prompt library.
+func handlePromptList(args []string) {
+	fs := flag.NewFlagSet("prompt list", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode (names only)")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck prompt list [options]")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+
+	prompts := session.GetSavedPrompts()
+	names := session.SavedPromptNames(prompts)
+	rows := make([]map[string]interface{}, 0, len(names))
+	var sb strings.Builder
+	if len(names) == 0 {
+		sb.WriteString("No prompts saved. Add one with: agent-deck prompt save <name> <text>\n")
+	}
+	for _, name := range names {
+		p := prompts[name]
+		vars := session.PromptVariables(p.Text)
+		rows = append(rows, map[string]interface{}{
+			"name":        name,
+			"text":        p.Text,
+			"description": p.Description,
+			"variables":   vars,
+		})
+		if *quiet {
+			continue
+		}
+		summary := p.Description
+		if summary == "" {
+			summary = truncate(firstLine(strings.TrimSpace(p.Text)), 80)
+		}
+		fmt.Fprintf(&sb, "  %-16s %s\n", name, summary)
+	}
+	if *quiet {
+		sb.Reset()
+		for _, name := range names {
+			sb.WriteString(name + "\n")
+		}
+	}
+	out.Print(strings.TrimRight(sb.String(), "\n"), rows)
+}
+
+// handlePromptSave adds or replaces a prompt in config.toml.
+func handlePromptSave(args []string) {
+	fs := flag.NewFlagSet("prompt save", flag.ExitOnError)
+	description := fs.String("description", "", "Short description shown in lists and the TUI picker")
+	messageFile := fs.String("message-file", "", "Read the prompt from a file ('-' for stdin) instead of a positional argument")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck prompt save <name> <text> [options]")
+		fmt.Println()
+		fmt.Println("Save a prompt to [prompts.<name>] in config.toml, replacing any prompt")
+		fmt.Println("of the same name.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+
+	remaining := fs.Args()
+	if len(remaining) < 1 || (*messageFile == "" && len(remaining) < 2) {
+		fs.Usage()
+		out.Error("name and prompt text (or --message-file) are required", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	name := remaining[0]
+	if err := session.ValidatePromptName(name); err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	text, err := resolveMessageInput(strings.Join(remaining[1:], " "), *messageFile, os.Stdin)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if strings.TrimSpace(text) == "" {
+		out.Error("prompt text is empty", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	config, err := session.LoadUserConfig()
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load config: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if config.Prompts == nil {
+		config.Prompts = make(map[string]session.SavedPrompt)
+	}
+	_, replaced := config.Prompts[name]
+	config.Prompts[name] = session.SavedPrompt{Text: text, Description: strings.TrimSpace(*description)}
+	if err := session.SaveUserConfig(config); err != nil {
+		out.Error(fmt.Sprintf("failed to save config: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	verb := "Saved"
+	if replaced {
+		verb = "Replaced"
+	}
+	out.Success(fmt.Sprintf("%s prompt '%s'", verb, name), map[string]interface{}{
+		"success":   true,
+		"name":      name,
+		"replaced":  replaced,
+		"variables": session.PromptVariables(text),
+	})
+}
+
+// handlePromptRemove deletes a prompt from config.toml.
+func handlePromptRemove(args []string) {
+	fs := flag.NewFlagSet("prompt remove", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck prompt remove <name> [options]")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		out.Error("prompt name is required", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	name := fs.Arg(0)
+
+	config, err := session.LoadUserConfig()
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load config: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if _, ok := config.Prompts[name]; !ok {
+		out.Error(fmt.Sprintf("prompt '%s' not found", name), ErrCodeNotFound)
+		os.Exit(2)
+	}
+	delete(config.Prompts, name)
+	if err := session.SaveUserConfig(config); err != nil {
+		out.Error(fmt.Sprintf("failed to save config: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Removed prompt '%s'", name), map[string]interface{}{
+		"success": true,
+		"name":    name,
+	})
+}
+
+// handlePromptSend fills in a saved prompt for a session and hands it to
+// `session send`, so delivery, readiness waits and --wait behave the same.
+func handlePromptSend(profile string, args []string) {
+	fs := flag.NewFlagSet("prompt send", flag.ExitOnError)
+	vars := promptVarFlags{}
+	fs.Var(vars, "var", "Value for a {name} placeholder, as NAME=VALUE (repeatable)")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode")
+	noWait := fs.Bool("no-wait", false, "Don't wait for agent to be ready (send immediately)")
+	wait := fs.Bool("wait", false, "Block until agent finishes processing, then print output")
+	draft := fs.Bool("draft", false, "Pre-fill the prompt without submitting")
+	show := fs.Bool("show", false, "Print the filled-in prompt without sending it")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck prompt send <name> <id|title> [options]")
+		fmt.Println()
+		fmt.Println("Send a saved prompt to a running session.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		out.Error("prompt name and session are required", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	name, sessionRef := fs.Arg(0), fs.Arg(1)
+
+	p, err := session.LookupSavedPrompt(name)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(2)
+	}
+
+	_, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	inst, errMsg, errCode := ResolveSession(sessionRef, instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		if errCode == ErrCodeNotFound {
+			os.Exit(2)
+		}
+		os.Exit(1)
+		return // unreachable, satisfies staticcheck SA5011
+	}
+
+	values := session.PromptVarsFor(inst, p.Text)
+	for k, v := range vars {
+		values[k] = v
+	}
+	text, err := session.RenderPrompt(p.Text, values)
+	if err != nil {
+		out.Error(fmt.Sprintf("prompt '%s': %v (pass --var name=value)", name, err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	if *show {
+		out.Print(text, map[string]interface{}{
+			"name":          name,
+			"session_id":    inst.ID,
+			"session_title": inst.Title,
+			"message":       text,
+		})
+		return
+	}
+
+	sendArgs := []string{inst.ID, text}
+	for _, f := range []struct {
+		name string
+		set  bool
+	}{{"--json", *jsonOutput}, {"-q", *quiet}, {"--no-wait", *noWait}, {"--wait", *wait}, {"--draft", *draft}} {
+		if f.set {
+			sendArgs = append(sendArgs, f.name)
+		}
+	}
+	handleSessionSend(profile, sendArgs)
+}
@@ -0,0 +1,375 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/relay"
+)
+
+// handleRelay dispatches `agent-deck relay` subcommands.
+func handleRelay(args []string) {
+	if len(args) == 0 {
+		printRelayUsage()
+		return
+	}
+
+	switch args[0] {
+	case "serve":
+		handleRelayServe(args[1:])
+	case "pair":
+		handleRelayPair(args[1:])
+	case "devices":
+		handleRelayDevices(args[1:])
+	case "revoke":
+		handleRelayRevoke(args[1:])
+	case "join":
+		handleRelayJoin(args[1:])
+	case "connect":
+		handleRelayConnect(args[1:])
+	case "decks":
+		handleRelayDecks(args[1:])
+	case "help", "-h", "--help":
+		printRelayUsage()
+	default:
+		fmt.Printf("Unknown relay command: %s\n", args[0])
+		printRelayUsage()
+		os.Exit(1)
+	}
+}
+
+func printRelayUsage() {
+	fmt.Println("Usage: agent-deck relay <command> [options]")
+	fmt.Println()
+	fmt.Println("End-to-end encrypted remote access through a self-hosted relay. The deck")
+	fmt.Println("(your workstation) and your devices both dial out to the relay, so no port")
+	fmt.Println("is exposed; the relay only forwards ciphertext.")
+	fmt.Println()
+	fmt.Println("Relay server:")
+	fmt.Println("  serve                     Run the relay broker")
+	fmt.Println()
+	fmt.Println("On the deck:")
+	fmt.Println("  pair --relay <url>        Create a single-use pairing offer for a device")
+	fmt.Println("  devices                   List paired devices")
+	fmt.Println("  revoke <name>             Unpair a device")
+	fmt.Println("  (then run: agent-deck web --relay <url>)")
+	fmt.Println()
+	fmt.Println("On a device:")
+	fmt.Println("  join <offer>              Pair with a deck using its offer")
+	fmt.Println("  connect [deck]            Expose the deck's web UI on a local port")
+	fmt.Println("  decks                     List paired decks")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck relay serve --listen :8443 --tls-cert cert.pem --tls-key key.pem")
+	fmt.Println("  agent-deck relay pair --relay https://relay.example.com --name phone")
+	fmt.Println("  agent-deck web --no-tui --relay https://relay.example.com")
+	fmt.Println("  agent-deck relay join adpair1:...        # on the device")
+	fmt.Println("  agent-deck relay connect                 # then open http://127.0.0.1:8421")
+}
+
+// relayDirOrExit resolves the relay state directory.
+func relayDirOrExit() string {
+	dir, err := relay.DefaultDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	return dir
+}
+
+// relaySignalContext is cancelled on SIGINT/SIGTERM.
+func relaySignalContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+func handleRelayServe(args []string) {
+	fs := flag.NewFlagSet("relay serve", flag.ExitOnError)
+	listen := fs.String("listen", ":8443", "Listen address")
+	tlsCert := fs.String("tls-cert", "", "TLS certificate file (serve HTTPS/WSS directly)")
+	tlsKey := fs.String("tls-key", "", "TLS private key file")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck relay serve [options]")
+		fmt.Println()
+		fmt.Println("Run the relay broker. It pairs deck and device WebSockets and copies")
+		fmt.Println("encrypted frames between them; it stores nothing and holds no keys.")
+		fmt.Println("Serve it over TLS (directly or behind a reverse proxy) so the relay URL")
+		fmt.Println("and stream metadata are not visible on the network.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	if (*tlsCert == "") != (*tlsKey == "") {
+		fmt.Fprintln(os.Stderr, "Error: --tls-cert and --tls-key must be given together")
+		os.Exit(1)
+	}
+
+	srv := &http.Server{
+		Addr:              *listen,
+		Handler:           relay.NewServer().Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	ctx, stop := relaySignalContext()
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("Relay listening on %s\n", *listen)
+	var err error
+	if *tlsCert != "" {
+		err = srv.ListenAndServeTLS(*tlsCert, *tlsKey)
+	} else {
+		err = srv.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func handleRelayPair(args []string) {
+	fs := flag.NewFlagSet("relay pair", flag.ExitOnError)
+	relayURL := fs.String("relay", "", "Relay URL the deck and device use (required)")
+	name := fs.String("name", "device", "Name for the device being paired")
+	deckName := fs.String("deck-name", "", "Name the device will show for this deck (default: hostname)")
+	ttl := fs.Duration("ttl", relay.DefaultPairingTTL, "How long the offer stays valid")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck relay pair --relay <url> [options]")
+		fmt.Println()
+		fmt.Println("Create a single-use pairing offer. Run 'agent-deck relay join <offer>' on")
+		fmt.Println("the device while this deck runs 'agent-deck web --relay <url>'. Treat the")
+		fmt.Println("offer like a password until it is used or expires.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	if *relayURL == "" {
+		out.Error("--relay is required", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if *deckName == "" {
+		*deckName, _ = os.Hostname()
+	}
+
+	id, store, err := relay.OpenDeck(relayDirOrExit())
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	offer, err := store.NewOffer(id, *relayURL, *deckName, *name, *ttl)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	encoded := offer.Encode()
+	if *jsonOutput {
+		out.Success("", map[string]any{
+			"success":    true,
+			"offer":      encoded,
+			"deck_id":    offer.DeckID,
+			"expires_in": ttl.String(),
+		})
+		return
+	}
+	fmt.Printf("Pairing offer for %q (valid %s, single use):\n\n", *name, *ttl)
+	fmt.Println(encoded)
+	fmt.Println()
+	fmt.Println("On the device run:")
+	fmt.Printf("  agent-deck relay join %s\n", encoded)
+	fmt.Println()
+	fmt.Printf("Deck ID: %s\n", offer.DeckID)
+}
+
+func handleRelayDevices(args []string) {
+	fs := flag.NewFlagSet("relay devices", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	_, store, err := relay.OpenDeck(relayDirOrExit())
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	devices := store.Devices()
+	if *jsonOutput {
+		out.Print("", map[string]any{"devices": devices})
+		return
+	}
+	if len(devices) == 0 {
+		fmt.Println("No paired devices. Create an offer with 'agent-deck relay pair --relay <url>'.")
+		return
+	}
+	fmt.Printf("%-20s %-20s %s\n", "NAME", "PAIRED", "LAST SEEN")
+	for _, d := range devices {
+		seen := "never"
+		if !d.LastSeen.IsZero() {
+			seen = d.LastSeen.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Printf("%-20s %-20s %s\n", d.Name, d.PairedAt.Local().Format("2006-01-02 15:04"), seen)
+	}
+}
+
+func handleRelayRevoke(args []string) {
+	fs := flag.NewFlagSet("relay revoke", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	if fs.NArg() != 1 {
+		out.Error("usage: agent-deck relay revoke <device-name>", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	_, store, err := relay.OpenDeck(relayDirOrExit())
+	if err == nil {
+		err = store.Revoke(fs.Arg(0))
+	}
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Revoked %s; its open streams end when they next reconnect", fs.Arg(0)), map[string]any{
+		"success": true,
+		"device":  fs.Arg(0),
+	})
+}
+
+func handleRelayJoin(args []string) {
+	fs := flag.NewFlagSet("relay join", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	if fs.NArg() != 1 {
+		out.Error("usage: agent-deck relay join <offer>", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	offer, err := relay.ParseOffer(fs.Arg(0))
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	id, store, err := relay.OpenDevice(relayDirOrExit())
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	deck, err := relay.Join(ctx, offer, id, nil)
+	if err != nil {
+		out.Error(fmt.Sprintf("pairing failed: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if err := store.Add(deck); err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Paired with %s. Run 'agent-deck relay connect' to open it.", deck.Name), map[string]any{
+		"success": true,
+		"deck":    deck.Name,
+		"deck_id": deck.DeckID,
+	})
+}
+
+func handleRelayConnect(args []string) {
+	fs := flag.NewFlagSet("relay connect", flag.ExitOnError)
+	listen := fs.String("listen", "127.0.0.1:8421", "Local address serving the deck's web UI")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck relay connect [deck] [options]")
+		fmt.Println()
+		fmt.Println("Tunnel a paired deck's web UI (API and terminal streams included) to a")
+		fmt.Println("local port. Each connection is end-to-end encrypted to the deck.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	id, store, err := relay.OpenDevice(relayDirOrExit())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	deck, err := store.Find(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	client := &relay.Client{Deck: deck, Identity: id}
+	ctx, stop := relaySignalContext()
+	defer stop()
+	// Fail fast on pairing or relay problems instead of on the first browser
+	// request.
+	probeCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	probe, err := client.Dial(probeCtx)
+	cancel()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: cannot reach %s: %v\n", deck.Name, err)
+		os.Exit(1)
+	}
+	_ = probe.Close()
+
+	ln, err := net.Listen("tcp", *listen)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Connected to %s via %s\n", deck.Name, deck.RelayURL)
+	fmt.Printf("Open http://%s (Ctrl+C to stop)\n", ln.Addr())
+	if err := client.Serve(ctx, ln); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func handleRelayDecks(args []string) {
+	fs := flag.NewFlagSet("relay decks", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	_, store, err := relay.OpenDevice(relayDirOrExit())
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	decks := store.Decks()
+	if *jsonOutput {
+		out.Print("", map[string]any{"decks": decks})
+		return
+	}
+	if len(decks) == 0 {
+		fmt.Println("No paired decks. Pair with 'agent-deck relay join <offer>'.")
+		return
+	}
+	fmt.Printf("%-20s %-30s %s\n", "NAME", "RELAY", "DECK ID")
+	for _, d := range decks {
+		fmt.Printf("%-20s %-30s %s\n", d.Name, d.RelayURL, d.DeckID)
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// handleSchedule dispatches schedule subcommands.
+func handleSchedule(profile string, args []string) {
+	if len(args) == 0 {
+		printScheduleHelp()
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "add":
+		handleScheduleAdd(profile, args[1:])
+	case "list", "ls":
+		handleScheduleList(profile, args[1:])
+	case "remove", "rm":
+		handleScheduleRemove(profile, args[1:])
+	case "help", "-h", "--help":
+		printScheduleHelp()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown schedule command '%s'\n", args[0])
+		printScheduleHelp()
+		os.Exit(1)
+	}
+}
+
+func printScheduleHelp() {
+	fmt.Println("Usage: agent-deck schedule <command> [options]")
+	fmt.Println()
+	fmt.Println("Send a message to a session on a cron schedule.")
+	fmt.Println("Schedules fire while the TUI or the notify daemon is running; a run missed")
+	fmt.Println("while neither was up fires once when one starts.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  add <cron> <session> <message>  Add a schedule")
+	fmt.Println("  list                            List schedules with next and last run")
+	fmt.Println("  remove <id>                     Delete a schedule")
+	fmt.Println()
+	fmt.Println("Cron format: minute hour day-of-month month day-of-week, or @hourly,")
+	fmt.Println("@daily, @weekly, @monthly, @yearly. Times are local.")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck schedule add \"0 9 * * 1-5\" my-project \"summarize overnight CI failures\"")
+	fmt.Println("  agent-deck schedule add @hourly reviewer \"check for new PRs\"")
+	fmt.Println("  agent-deck schedule list")
+	fmt.Println("  agent-deck schedule remove sch-1a2b3c4d")
+}
+
+// handleScheduleAdd stores a new schedule for a session.
+func handleScheduleAdd(profile string, args []string) {
+	fs := flag.NewFlagSet("schedule add", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck schedule add <cron> <session> <message> [options]")
+		fmt.Println()
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+	if fs.NArg() != 3 {
+		out.Error("schedule add requires <cron> <session> <message>", ErrCodeInvalidOperation)
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	storage, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	defer storage.Close()
+	inst, errMsg, errCode := ResolveSession(fs.Arg(1), instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		if errCode == ErrCodeNotFound {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+	db := storage.GetDB()
+	if db == nil {
+		out.Error("no state database for this profile", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	r, err := session.AddSchedule(db, fs.Arg(0), inst, fs.Arg(2), time.Now())
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Scheduled %s for %s (next run %s)", r.ID, inst.Title, formatScheduleTime(r.NextRun)),
+		scheduleJSON(r, inst.Title))
+}
+
+// handleScheduleList prints every schedule of the profile.
+func handleScheduleList(profile string, args []string) {
+	fs := flag.NewFlagSet("schedule list", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode (IDs only)")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	storage, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		NewCLIOutput(*jsonOutput, false).Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	defer storage.Close()
+	var rows []*statedb.ScheduleRow
+	if db := storage.GetDB(); db != nil {
+		if rows, err = db.LoadSchedules(); err != nil {
+			NewCLIOutput(*jsonOutput, false).Error(fmt.Sprintf("failed to read schedules: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	}
+
+	titles := make(map[string]string, len(instances))
+	for _, inst := range instances {
+		titles[inst.ID] = inst.Title
+	}
+
+	if *quiet {
+		for _, r := range rows {
+			fmt.Println(r.ID)
+		}
+		return
+	}
+	items := make([]map[string]interface{}, 0, len(rows))
+	for _, r := range rows {
+		items = append(items, scheduleJSON(r, titles[r.InstanceID]))
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	out.Print(renderScheduleList(rows, titles), map[string]interface{}{
+		"success":   true,
+		"schedules": items,
+	})
+}
+
+// handleScheduleRemove deletes a schedule by ID.
+func handleScheduleRemove(profile string, args []string) {
+	fs := flag.NewFlagSet("schedule remove", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+	if fs.NArg() != 1 {
+		out.Error("schedule remove requires <id>", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	id := fs.Arg(0)
+
+	storage, err := session.NewStorageWithProfile(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	defer storage.Close()
+	db := storage.GetDB()
+	if db == nil {
+		out.Error("no state database for this profile", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	removed, err := db.DeleteSchedule(id)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to remove schedule: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if !removed {
+		out.Error(fmt.Sprintf("schedule %s not found", id), ErrCodeNotFound)
+		os.Exit(2)
+	}
+	out.Success(fmt.Sprintf("Removed schedule %s", id), map[string]interface{}{
+		"success": true,
+		"id":      id,
+	})
+}
+
+// scheduleJSON is the JSON shape of a schedule for add and list.
+func scheduleJSON(r *statedb.ScheduleRow, title string) map[string]interface{} {
+	m := map[string]interface{}{
+		"id":          r.ID,
+		"cron":        r.Spec,
+		"session_id":  r.InstanceID,
+		"session":     title,
+		"message":     r.Message,
+		"next_run":    r.NextRun,
+		"last_status": r.LastStatus,
+	}
+	if !r.LastRun.IsZero() {
+		m["last_run"] = r.LastRun
+	}
+	if r.LastError != "" {
+		m["last_error"] = r.LastError
+	}
+	return m
+}
+
+// renderScheduleList prints one block per schedule: the cron spec and
+// target, the next and last run, and the message.
+func renderScheduleList(rows []*statedb.ScheduleRow, titles map[string]string) string {
+	if len(rows) == 0 {
+		return "No schedules. Add one with: agent-deck schedule add \"<cron>\" <session> \"<message>\"\n"
+	}
+	var b strings.Builder
+	for i, r := range rows {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		title, ok := titles[r.InstanceID]
+		if !ok {
+			title = r.InstanceID + " (session removed)"
+		}
+		fmt.Fprintf(&b, "%s  %-14s → %s\n", r.ID, r.Spec, title)
+		last := "never"
+		if !r.LastRun.IsZero() {
+			last = formatScheduleTime(r.LastRun) + " " + r.LastStatus
+			if r.LastError != "" {
+				last += ": " + truncate(firstLine(r.LastError), 80)
+			}
+		}
+		fmt.Fprintf(&b, "  next: %s   last: %s\n", formatScheduleTime(r.NextRun), last)
+		fmt.Fprintf(&b, "  %q\n", truncate(firstLine(r.Message), 80))
+	}
+	return b.String()
+}
+
+// formatScheduleTime formats a run time in local time, with the weekday so
+// "next Monday" is obvious.
+func formatScheduleTime(t time.Time) string {
+	return t.Local().Format("Mon Jan 02 15:04")
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleSearch implements `agent-deck search <query>`: sessions of every
+// profile matched by title, group, path and recorded transcript. Each hit
+// carries its profile and ID so a script can act on it with
+// `agent-deck -p <profile> session ... <id>`.
+func handleSearch(args []string) {
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	limit := fs.Int("limit", 20, "Maximum number of results (0 = all)")
+	noTranscripts := fs.Bool("no-transcripts", false, "Match titles, groups and paths only")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck search <query> [options]")
+		fmt.Println()
+		fmt.Println("Search sessions across all profiles. Titles and groups match fuzzily,")
+		fmt.Println("project paths and recorded transcripts as substrings. Results are best")
+		fmt.Println("first: title, then group, path and transcript matches.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck search api")
+		fmt.Println("  agent-deck search \"migration failed\" --json")
+		fmt.Println("  agent-deck search billing --no-transcripts --limit 5")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+	query := strings.TrimSpace(strings.Join(fs.Args(), " "))
+	if query == "" {
+		out.Error("query is required", ErrCodeInvalidOperation)
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	sessions, err := session.LoadProfileSessions()
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to list profiles: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	hits := session.SearchProfileSessions(query, sessions, !*noTranscripts)
+	total := len(hits)
+	if *limit > 0 && len(hits) > *limit {
+		hits = hits[:*limit]
+	}
+
+	type hitJSON struct {
+		Profile string `json:"profile"`
+		ID      string `json:"id"`
+		Title   string `json:"title"`
+		Path    string `json:"path"`
+		Group   string `json:"group"`
+		Tool    string `json:"tool"`
+		Status  string `json:"status"`
+		Field   string `json:"matched"`
+		Snippet string `json:"snippet,omitempty"`
+	}
+	results := make([]hitJSON, 0, len(hits))
+	var b strings.Builder
+	if len(hits) == 0 {
+		fmt.Fprintf(&b, "No sessions matched %q\n", query)
+	} else {
+		fmt.Fprintf(&b, "Found %d session(s) matching %q:\n", total, query)
+	}
+	for i, h := range hits {
+		results = append(results, hitJSON{
+			Profile: h.Profile,
+			ID:      h.ID,
+			Title:   h.Title,
+			Path:    h.ProjectPath,
+			Group:   h.GroupPath,
+			Tool:    h.Tool,
+			Status:  string(h.Status),
+			Field:   h.Field,
+			Snippet: h.Snippet,
+		})
+		fmt.Fprintf(&b, "%d. [%s] %s (%s) %s %s\n", i+1, h.Profile, h.Title, h.ID, bulletSymbol, h.Field)
+		fmt.Fprintf(&b, "   %s · %s\n", h.GroupPath, h.ProjectPath)
+		if h.Snippet != "" {
+			fmt.Fprintf(&b, "   %s\n", h.Snippet)
+		}
+	}
+	if total > len(hits) {
+		fmt.Fprintf(&b, "... %d more (use --limit 0 for all)\n", total-len(hits))
+	}
+	if len(hits) > 0 {
+		fmt.Fprintf(&b, "Open in the TUI with: agent-deck -p <profile> --select <id>\n")
+	}
+	out.Print(b.String(), map[string]interface{}{
+		"success": true,
+		"query":   query,
+		"results": results,
+		"count":   len(results),
+		"total":   total,
+	})
+}
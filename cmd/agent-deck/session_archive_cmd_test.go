@@ -151,3 +151,67 @@ func TestSessionUnarchive_MissingArg_Exit1(t *testing.T) {
 		t.Fatalf("expected exit 1 for unarchive with no id, got %d", code)
 	}
 }
+
+// TestArchiveListAndRestore covers the archive browser: archived sessions
+// are listed, active ones are not, and restore resolves among archived
+// sessions only.
+func TestArchiveListAndRestore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("subprocess CLI test skipped in short mode")
+	}
+	home := t.TempDir()
+	archivedID := addTestSession(t, home, filepath.Join(home, "old"), "archive-browse-old")
+	addTestSession(t, home, filepath.Join(home, "new"), "archive-browse-new")
+
+	if _, stderr, code := runAgentDeck(t, home, "session", "archive", archivedID, "--json"); code != 0 {
+		t.Fatalf("archive setup failed (exit %d): %s", code, stderr)
+	}
+
+	stdout, stderr, code := runAgentDeck(t, home, "archive", "list", "--json")
+	if code != 0 {
+		t.Fatalf("archive list failed (exit %d): %s", code, stderr)
+	}
+	var listed struct {
+		Count    int `json:"count"`
+		Sessions []struct {
+			ID string `json:"id"`
+		} `json:"sessions"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &listed); err != nil {
+		t.Fatalf("parse archive list: %v\n%s", err, stdout)
+	}
+	if listed.Count != 1 || listed.Sessions[0].ID != archivedID {
+		t.Fatalf("archive list = %s, want only %s", stdout, archivedID)
+	}
+
+	if _, _, code := runAgentDeck(t, home, "archive", "restore", "archive-browse-new", "--json"); code != 2 {
+		t.Errorf("restoring an active session should exit 2 (not archived), got %d", code)
+	}
+	if _, stderr, code := runAgentDeck(t, home, "archive", "restore", "archive-browse-old", "--json"); code != 0 {
+		t.Fatalf("archive restore failed (exit %d): %s", code, stderr)
+	}
+	if archivedFlag(t, home, archivedID) {
+		t.Errorf("session %s still archived after restore", archivedID)
+	}
+}
+
+// TestArchiveSweep_RequiresThreshold: with auto-archive unconfigured and no
+// --days, sweep refuses rather than archiving everything.
+func TestArchiveSweep_RequiresThreshold(t *testing.T) {
+	if testing.Short() {
+		t.Skip("subprocess CLI test skipped in short mode")
+	}
+	home := t.TempDir()
+	id := addTestSession(t, home, filepath.Join(home, "proj"), "archive-sweep")
+
+	if _, _, code := runAgentDeck(t, home, "archive", "sweep", "--json"); code != 1 {
+		t.Fatalf("expected exit 1 without a threshold, got %d", code)
+	}
+	stdout, stderr, code := runAgentDeck(t, home, "archive", "sweep", "--days", "1", "--json")
+	if code != 0 {
+		t.Fatalf("archive sweep failed (exit %d): %s", code, stderr)
+	}
+	if archivedFlag(t, home, id) {
+		t.Errorf("fresh session archived by a 1-day sweep: %s", stdout)
+	}
+}
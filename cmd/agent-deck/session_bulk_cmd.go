@@ -0,0 +1,304 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/progress"
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// Bulk start/stop/restart (`session <verb> --group <g>` / `--all`). Every
+// bulk operation loads the profile once, acts on each selected session, and
+// saves once at the end, so a scripted loop no longer races the TUI's
+// force-save between per-session CLI invocations.
+
+// bulkResult is one session's outcome in a bulk operation.
+type bulkResult struct {
+	inst    *session.Instance
+	skipped string // why the session was left alone; empty when acted on
+	warning string
+	err     error
+}
+
+// bulkSelection resolves the sessions a bulk operation may touch: every
+// non-archived session for --all, or the sessions in the named group and its
+// subgroups. The group is matched by path first, then case-insensitively by
+// name, like `group show`. Returns the resolved group path ("" for --all).
+func bulkSelection(groupSel string, instances []*session.Instance, groups []*session.GroupData) ([]*session.Instance, string, error) {
+	groupPath := ""
+	if groupSel != "" {
+		tree := session.NewGroupTreeWithGroups(instances, groups)
+		groupPath = normalizeGroupPath(groupSel)
+		if _, ok := tree.Groups[groupPath]; !ok {
+			found := false
+			for path, g := range tree.Groups {
+				if strings.EqualFold(g.Name, groupSel) {
+					groupPath, found = path, true
+					break
+				}
+			}
+			if !found {
+				return nil, "", fmt.Errorf("group '%s' not found", groupSel)
+			}
+		}
+	}
+
+	var selected []*session.Instance
+	for _, inst := range instances {
+		if inst.IsArchived() {
+			continue
+		}
+		if groupPath != "" && inst.GroupPath != groupPath && !strings.HasPrefix(inst.GroupPath, groupPath+"/") {
+			continue
+		}
+		selected = append(selected, inst)
+	}
+	return selected, groupPath, nil
+}
+
+// resolveBulkTargets validates the --group/--all flags against a positional
+// identifier and returns the selected sessions, exiting on misuse.
+func resolveBulkTargets(out *CLIOutput, identifier, groupSel string, all bool, instances []*session.Instance, groups []*session.GroupData) ([]*session.Instance, string) {
+	if identifier != "" {
+		out.Error("cannot combine a session identifier with --group or --all", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if all && groupSel != "" {
+		out.Error("--group and --all are mutually exclusive", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	selected, groupPath, err := bulkSelection(groupSel, instances, groups)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(2)
+	}
+	return selected, groupPath
+}
+
+// bulkProgress returns the reporter for --progress: "[3/10] Restarting api"
+// lines on stderr, so --json output on stdout stays parseable. Nil (silent)
+// when the flag is off.
+func bulkProgress(enabled bool) progress.Func {
+	if !enabled {
+		return nil
+	}
+	return progress.NewCLI(os.Stderr)
+}
+
+// bulkScope describes the selection for messages: "in group 'x'" or "".
+func bulkScope(groupPath string) string {
+	if groupPath == "" {
+		return ""
+	}
+	return fmt.Sprintf(" in group '%s'", groupPath)
+}
+
+// bulkStartSessions starts every stopped session in targets. Prerequisites
+// are started first unless noDeps is set, groups at their max_concurrent cap
+// queue the rest, and cosmetic tmux options are batched across all starts.
+func bulkStartSessions(out *CLIOutput, storage *session.Storage, instances []*session.Instance, groups []*session.GroupData, targets []*session.Instance, groupPath string, noDeps bool, report progress.Func) {
+	var stopped []*session.Instance
+	for _, inst := range targets {
+		if !inst.Exists() {
+			stopped = append(stopped, inst)
+		}
+	}
+	if len(stopped) == 0 {
+		out.Error("no stopped sessions to start"+bulkScope(groupPath), ErrCodeNotFound)
+		os.Exit(1)
+	}
+
+	tree := session.NewGroupTreeWithGroups(instances, groups)
+	results := make(map[string]*bulkResult, len(stopped))
+	var started []*session.Instance
+	startedAsDep := make(map[string]bool)
+	step := 0
+	errs := session.StartInstances(stopped, func(inst *session.Instance) error {
+		res := &bulkResult{inst: inst}
+		results[inst.ID] = res
+		step++
+		report.Step(step, len(stopped), "Starting %s", inst.Title)
+		// An earlier session in this run may have started it as a
+		// prerequisite; that counts as started, not skipped.
+		if inst.Exists() {
+			if !startedAsDep[inst.ID] {
+				res.skipped = "already running"
+			}
+			return nil
+		}
+		if max := session.GroupMaxConcurrent(tree, inst.GroupPath); session.ShouldQueue(instances, inst.GroupPath, max) {
+			inst.Status = session.StatusQueued
+			res.skipped = fmt.Sprintf("queued (group at cap %d)", max)
+			return nil
+		}
+		if !noDeps {
+			deps, err := startDependencies(inst, instances)
+			for _, dep := range deps {
+				startedAsDep[dep.ID] = true
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if err := inst.Start(); err != nil {
+			return err
+		}
+		started = append(started, inst)
+		return nil
+	})
+	for id, err := range errs {
+		results[id].err = err
+	}
+	// Session IDs are captured after every start has been issued, so the
+	// sessions come up in parallel instead of waiting 3s each.
+	if len(started) > 0 {
+		report.Logf("Waiting for %d session(s) to report their IDs", len(started))
+	}
+	for _, inst := range started {
+		inst.PostStartSync(3 * time.Second)
+	}
+
+	ordered := make([]bulkResult, 0, len(stopped))
+	for _, inst := range stopped {
+		ordered = append(ordered, *results[inst.ID])
+	}
+	reportBulkResults(out, storage, instances, groups, "Started", "started", ordered)
+}
+
+// bulkStopSessions stops every running session in targets. Unlike a single
+// stop it does not drain group queues: the queued sessions would belong to
+// the very groups being stopped.
+func bulkStopSessions(out *CLIOutput, storage *session.Storage, instances []*session.Instance, groups []*session.GroupData, targets []*session.Instance, groupPath string, report progress.Func) {
+	var running []*session.Instance
+	for _, inst := range targets {
+		if inst.Exists() {
+			running = append(running, inst)
+		}
+	}
+	var results []bulkResult
+	for i, inst := range running {
+		report.Step(i+1, len(running), "Stopping %s", inst.Title)
+		res := bulkResult{inst: inst}
+		// Capture tool conversation IDs before the tmux environment goes
+		// away, as in handleSessionStop.
+		inst.SyncSessionIDsFromTmux()
+		if err := inst.Kill(); err != nil {
+			res.err = err
+		}
+		results = append(results, res)
+	}
+	if len(results) == 0 {
+		out.Error("no running sessions to stop"+bulkScope(groupPath), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	reportBulkResults(out, storage, instances, groups, "Stopped", "stopped", results)
+}
+
+// bulkRestartSessions restarts every running session in targets. The issue
+// #30 freshness guard applies per session unless force is set.
+func bulkRestartSessions(out *CLIOutput, storage *session.Storage, instances []*session.Instance, groups []*session.GroupData, targets []*session.Instance, groupPath string, env map[string]string, force bool, report progress.Func) {
+	var active []*session.Instance
+	for _, inst := range targets {
+		if inst.Exists() {
+			active = append(active, inst)
+		}
+	}
+	if len(active) == 0 {
+		out.Error("no active sessions to restart"+bulkScope(groupPath), ErrCodeNotFound)
+		os.Exit(1)
+	}
+
+	results := make([]bulkResult, 0, len(active))
+	now := time.Now()
+	for i, inst := range active {
+		report.Step(i+1, len(active), "Restarting %s", inst.Title)
+		res := bulkResult{inst: inst}
+		if skip, reason := session.ShouldSkipRestart(inst, now, force || len(env) > 0); skip {
+			res.skipped = reason
+			results = append(results, res)
+			continue
+		}
+		if err := inst.RestartWithEnv(env); err != nil {
+			res.err = err
+			results = append(results, res)
+			continue
+		}
+		inst.LastStartedAt = time.Now()
+		res.warning = inst.ConsumeCodexRestartWarning()
+		// If restart created a fresh session (no prior ID), capture the new ID
+		if session.IsClaudeCompatible(inst.Tool) && inst.ClaudeSessionID == "" {
+			inst.PostStartSync(3 * time.Second)
+		}
+		results = append(results, res)
+	}
+	reportBulkResults(out, storage, instances, groups, "Restarted", "restarted", results)
+}
+
+// reportBulkResults saves state once, prints per-session outcomes and a
+// summary, and exits 1 when any session failed. doneKey names the JSON count
+// of sessions acted on ("started", "stopped", "restarted").
+func reportBulkResults(out *CLIOutput, storage *session.Storage, instances []*session.Instance, groups []*session.GroupData, pastVerb, doneKey string, results []bulkResult) {
+	if err := saveSessionData(storage, instances, groups); err != nil {
+		out.Error(fmt.Sprintf("failed to save session state: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	var done, skipped, failed int
+	sessions := make([]map[string]interface{}, 0, len(results))
+	var sb strings.Builder
+	for _, res := range results {
+		entry := map[string]interface{}{
+			"id":    res.inst.ID,
+			"title": res.inst.Title,
+		}
+		switch {
+		case res.err != nil:
+			failed++
+			entry["success"] = false
+			entry["error"] = res.err.Error()
+			fmt.Fprintf(&sb, "  %s %s: %v\n", errorSymbol, res.inst.Title, res.err)
+		case res.skipped != "":
+			skipped++
+			entry["success"] = true
+			entry["skipped"] = true
+			entry["reason"] = res.skipped
+			fmt.Fprintf(&sb, "  - %s (skipped: %s)\n", res.inst.Title, res.skipped)
+		default:
+			done++
+			entry["success"] = true
+			fmt.Fprintf(&sb, "  %s %s\n", successSymbol, res.inst.Title)
+		}
+		if res.warning != "" {
+			entry["warning"] = res.warning
+			fmt.Fprintf(&sb, "    Warning: %s\n", res.warning)
+		}
+		sessions = append(sessions, entry)
+	}
+
+	fmt.Fprintf(&sb, "%s %d/%d sessions", pastVerb, done, len(results))
+	var notes []string
+	if skipped > 0 {
+		notes = append(notes, fmt.Sprintf("%d skipped", skipped))
+	}
+	if failed > 0 {
+		notes = append(notes, fmt.Sprintf("%d failed", failed))
+	}
+	if len(notes) > 0 {
+		fmt.Fprintf(&sb, " (%s)", strings.Join(notes, ", "))
+	}
+
+	out.Print(sb.String()+"\n", map[string]interface{}{
+		"success":  failed == 0,
+		"total":    len(results),
+		doneKey:    done,
+		"skipped":  skipped,
+		"failed":   failed,
+		"sessions": sessions,
+	})
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+func TestBulkSelection_GroupIncludesSubgroupsAndSkipsArchived(t *testing.T) {
+	mk := func(id, group string) *session.Instance {
+		return &session.Instance{ID: id, Title: id, GroupPath: group}
+	}
+	archived := mk("old", "backend")
+	archived.ArchivedAt = time.Now()
+	instances := []*session.Instance{
+		mk("api", "backend"),
+		mk("worker", "backend/jobs"),
+		mk("web", "frontend"),
+		mk("lookalike", "backend-legacy"),
+		archived,
+	}
+
+	ids := func(list []*session.Instance) []string {
+		out := make([]string, len(list))
+		for i, inst := range list {
+			out[i] = inst.ID
+		}
+		return out
+	}
+
+	got, path, err := bulkSelection("backend", instances, nil)
+	if err != nil {
+		t.Fatalf("bulkSelection: %v", err)
+	}
+	if path != "backend" || !reflect.DeepEqual(ids(got), []string{"api", "worker"}) {
+		t.Fatalf("group selection = %v (path %q), want [api worker] in backend", ids(got), path)
+	}
+
+	all, path, err := bulkSelection("", instances, nil)
+	if err != nil || path != "" {
+		t.Fatalf("--all selection: path %q err %v", path, err)
+	}
+	if want := []string{"api", "worker", "web", "lookalike"}; !reflect.DeepEqual(ids(all), want) {
+		t.Fatalf("--all selection = %v, want %v", ids(all), want)
+	}
+
+	if _, _, err := bulkSelection("missing", instances, nil); err == nil {
+		t.Fatal("unknown group must be an error")
+	}
+}
+
+// The bulk flags are validated before any session is touched: a group with
+// nothing running is reported, and an identifier cannot be mixed with --group.
+func TestSessionBulk_CLIValidation(t *testing.T) {
+	home := t.TempDir()
+	proj := filepath.Join(home, "proj")
+	if err := os.MkdirAll(proj, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, stderr, code := runAgentDeck(t, home, "add", "-t", "api", "-c", "claude", "-g", "backend", "--no-parent", proj); code != 0 {
+		t.Fatalf("add failed (exit %d): %s", code, stderr)
+	}
+
+	stdout, _, code := runAgentDeck(t, home, "session", "stop", "--group", "backend", "--json")
+	if code != 1 || !strings.Contains(stdout, "no running sessions to stop in group 'backend'") {
+		t.Fatalf("stop --group with nothing running: exit %d, stdout %s", code, stdout)
+	}
+
+	stdout, _, code = runAgentDeck(t, home, "session", "restart", "api", "--group", "backend", "--json")
+	if code != 1 || !strings.Contains(stdout, "cannot combine a session identifier") {
+		t.Fatalf("identifier + --group: exit %d, stdout %s", code, stdout)
+	}
+
+	_, _, code = runAgentDeck(t, home, "session", "start", "--group", "nope", "--json")
+	if code != 2 {
+		t.Fatalf("unknown group: exit %d, want 2", code)
+	}
+}
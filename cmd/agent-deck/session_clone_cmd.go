@@ -0,0 +1,261 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/git"
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/vcs"
+)
+
+// handleSessionClone creates a new session with another session's
+// configuration, optionally in a fresh worktree branched from the same base.
+func handleSessionClone(profile string, args []string) {
+	fs := flag.NewFlagSet("session clone", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+	title := fs.String("title", "", "Title for the clone (default: <title>-clone)")
+	titleShort := fs.String("t", "", "Title for the clone (short)")
+	group := fs.String("group", "", "Group for the clone (default: the source's group)")
+	groupShort := fs.String("g", "", "Group for the clone (short)")
+	branch := fs.String("branch", "", "Run the clone in a new worktree on this new branch")
+	branchShort := fs.String("b", "", "New worktree branch (short)")
+	base := fs.String("base", "", "Start the new branch here (default: the source worktree's base branch, else the source's current branch)")
+	message := fs.String("message", "", "Initial message to send once the clone is ready")
+	messageShort := fs.String("m", "", "Initial message (short)")
+	noStart := fs.Bool("no-start", false, "Create the clone without starting it")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck session clone <id|title> [options]")
+		fmt.Println()
+		fmt.Println("Create a new session with the same tool, command, options, MCPs and")
+		fmt.Println("settings as an existing one, but a fresh conversation. With --branch the")
+		fmt.Println("clone gets its own worktree, branched from the source's base, so it can")
+		fmt.Println("take another attempt at the same task. Without it, the clone runs in the")
+		fmt.Println("source's directory.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck session clone my-task")
+		fmt.Println("  agent-deck session clone my-task --branch my-task-take2")
+		fmt.Println("  agent-deck session clone my-task -b retry --base main -m \"Try a streaming parser instead\"")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	identifier := fs.Arg(0)
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+	if identifier == "" {
+		out.Error("session <id|title> required", ErrCodeInvalidOperation)
+		if !*jsonOutput {
+			fs.Usage()
+		}
+		os.Exit(1)
+	}
+
+	cloneTitle := mergeFlags(*title, *titleShort)
+	cloneGroup := mergeFlags(*group, *groupShort)
+	cloneBranch := mergeFlags(*branch, *branchShort)
+	initialMessage := mergeFlags(*message, *messageShort)
+	if *base != "" && cloneBranch == "" {
+		out.Error("--base requires --branch", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if initialMessage != "" && *noStart {
+		out.Error("--message cannot be used with --no-start", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	storage, instances, groupsData, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+
+	inst, errMsg, errCode := ResolveSession(identifier, instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		if errCode == ErrCodeNotFound {
+			os.Exit(2)
+		}
+		os.Exit(1)
+		return // unreachable, satisfies staticcheck SA5011
+	}
+
+	explicitTitle := cloneTitle != ""
+	if !explicitTitle {
+		cloneTitle = inst.Title + "-clone"
+	}
+
+	var wt *cloneWorktree
+	if cloneBranch != "" {
+		if inst.SSHHost != "" {
+			out.Error("--branch is not supported for remote sessions", ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		if inst.MultiRepoEnabled {
+			out.Error("--branch is not supported for multi-repo sessions", ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		wt, err = createCloneWorktree(inst, cloneBranch, *base)
+		if err != nil {
+			out.Error(err.Error(), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	}
+
+	projectPath := ""
+	if wt != nil {
+		projectPath = wt.path
+	}
+	clone := inst.CloneInstance(cloneTitle, cloneGroup, projectPath)
+	if explicitTitle {
+		clone.TitleLocked = true
+	}
+
+	var mcpWritten string
+	if wt != nil {
+		clone.WorktreePath = wt.path
+		clone.WorktreeRepoRoot = wt.repoRoot
+		clone.WorktreeBranch = wt.branch
+		clone.WorktreeType = wt.vcsType
+		if mcpWritten, err = session.CopyLocalMCPConfig(inst.Tool, inst.ProjectPath, wt.path); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: MCP config not copied: %v\n", err)
+		}
+	}
+
+	if !*noStart {
+		var startErr error
+		if initialMessage != "" {
+			startErr = clone.StartWithMessage(initialMessage)
+		} else {
+			startErr = clone.Start()
+		}
+		if startErr != nil {
+			out.Error(fmt.Sprintf("failed to start clone: %v", startErr), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		clone.PostStartSync(3 * time.Second)
+	}
+
+	instances = append(instances, clone)
+	if err := saveSessionData(storage, instances, groupsData); err != nil {
+		out.Error(fmt.Sprintf("failed to save: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	msg := fmt.Sprintf("Cloned session: %s -> %s (%s)", inst.Title, clone.Title, TruncateID(clone.ID))
+	if wt != nil {
+		msg += fmt.Sprintf("\n  worktree: %s (%s from %s)", wt.path, wt.branch, wt.base)
+	}
+	if mcpWritten != "" {
+		msg += fmt.Sprintf("\n  copied MCP config: %s", mcpWritten)
+	}
+	if *noStart {
+		msg += fmt.Sprintf("\nStart it with: agent-deck session start %s", TruncateID(clone.ID))
+	}
+	data := map[string]interface{}{
+		"success":   true,
+		"source_id": inst.ID,
+		"new_id":    clone.ID,
+		"new_title": clone.Title,
+		"group":     clone.GroupPath,
+		"path":      clone.ProjectPath,
+		"started":   !*noStart,
+	}
+	if wt != nil {
+		data["branch"] = wt.branch
+		data["base"] = wt.base
+		data["worktree_path"] = wt.path
+	}
+	out.Success(msg, data)
+}
+
+// cloneWorktree is the worktree created for a clone.
+type cloneWorktree struct {
+	path     string
+	repoRoot string
+	branch   string
+	base     string
+	vcsType  string
+}
+
+// createCloneWorktree creates a new branch and worktree for a clone of inst.
+// For git the branch starts at base, defaulting to the branch inst's own
+// worktree was based on, or inst's current branch (or detached HEAD) when it
+// is not in a worktree. Other
+// backends branch from their current revision and take no base.
+func createCloneWorktree(inst *session.Instance, branch, base string) (*cloneWorktree, error) {
+	backend, err := detectAndCreateBackend(inst.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+	repoRoot := backend.RepoDir()
+	wtSettings := session.GetWorktreeSettings()
+	branch = wtSettings.ApplyBranchPrefix(branch)
+
+	if backend.Type() == vcs.TypeGit {
+		if err := git.ValidateBranchName(branch); err != nil {
+			return nil, fmt.Errorf("invalid branch name %q: %w", branch, err)
+		}
+		if base == "" {
+			if inst.IsWorktree() && inst.WorktreeBranch != "" {
+				if base, err = session.WorktreeBase(inst, ""); err != nil {
+					return nil, err
+				}
+			} else if current, err := git.GetCurrentBranch(inst.ProjectPath); err == nil && current != "" && current != "HEAD" {
+				base = current
+			} else if base, err = git.HeadCommit(inst.ProjectPath); err != nil {
+				return nil, fmt.Errorf("failed to resolve %s HEAD: %w", inst.ProjectPath, err)
+			}
+		}
+	} else if base != "" {
+		return nil, fmt.Errorf("--base is only supported in git repositories")
+	}
+	if backend.BranchExists(branch) {
+		return nil, fmt.Errorf("branch '%s' already exists; choose a new branch for the clone", branch)
+	}
+
+	path := backend.WorktreePath(vcs.WorktreePathOptions{
+		Branch:    branch,
+		Location:  wtSettings.DefaultLocation,
+		SessionID: git.GeneratePathID(),
+		Template:  wtSettings.Template(),
+	})
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("worktree path already exists: %s", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	var setupErr error
+	if backend.Type() == vcs.TypeGit {
+		if _, err := git.CreateWorktreeAtStartPoint(repoRoot, path, branch, base); err != nil {
+			return nil, fmt.Errorf("worktree creation failed: %w", err)
+		}
+		if err := git.ProcessWorktreeInclude(repoRoot, path, os.Stderr); err != nil {
+			fmt.Fprintf(os.Stderr, "worktreeinclude: %v\n", err)
+		}
+		setupErr = git.RunWorktreeSetupAfterCreate(repoRoot, path, os.Stdout, os.Stderr, wtSettings.SetupTimeout())
+	} else if setupErr, err = createWorktreeWithSetup(backend, path, branch, os.Stdout, os.Stderr, wtSettings.SetupTimeout()); err != nil {
+		return nil, fmt.Errorf("worktree creation failed: %w", err)
+	}
+	if setupErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: worktree setup script failed: %v\n", setupErr)
+	}
+
+	if base == "" {
+		base = "current revision"
+	}
+	return &cloneWorktree{path: path, repoRoot: repoRoot, branch: branch, base: base, vcsType: string(backend.Type())}, nil
+}
@@ -53,6 +53,8 @@ func handleSession(profile string, args []string) {
 		handleSessionRevive(profile, args[1:])
 	case "fork":
 		handleSessionFork(profile, args[1:])
+	case "clone":
+		handleSessionClone(profile, args[1:])
 	case "handoff":
 		handleSessionHandoff(profile, args[1:])
 	case "attach":
@@ -78,22 +80,50 @@ func handleSession(profile string, args []string) {
 		handleSessionSetTitleLock(profile, args[1:])
 	case "set":
 		handleSessionSet(profile, args[1:])
+	case "presets":
+		handleSessionPresets(args[1:])
 	case "switch-account":
 		handleSessionSwitchAccount(profile, args[1:])
 	case "move", "mv":
 		handleSessionMove(profile, args[1:])
+	case "snapshot":
+		handleSessionSnapshot(profile, args[1:])
+	case "restore":
+		handleSessionRestore(profile, args[1:])
+	case "history":
+		handleSessionHistory(profile, args[1:])
+	case "relocate":
+		handleSessionRelocate(profile, args[1:])
 	case "send":
 		handleSessionSend(profile, args[1:])
+	case "send-to":
+		handleSessionSendTo(profile, args[1:])
+	case "mailbox":
+		handleSessionMailbox(profile, args[1:])
+	case "continue":
+		handleSessionContinue(profile, args[1:])
 	case "approve":
 		handleSessionApprove(profile, args[1:])
 	case "send-keys":
 		handleSessionSendKeys(profile, args[1:])
 	case "output":
 		handleSessionOutput(profile, args[1:])
+	case "watch":
+		handleSessionWatch(profile, args[1:])
+	case "transcript":
+		handleSessionTranscript(profile, args[1:])
+	case "outputs":
+		handleSessionOutputs(profile, args[1:])
 	case "children":
 		handleSessionChildren(profile, args[1:])
 	case "search":
 		handleSessionSearch(profile, args[1:])
+	case "depends":
+		handleSessionDepends(profile, args[1:])
+	case "pin":
+		handleSessionPin(profile, args[1:], false)
+	case "unpin":
+		handleSessionPin(profile, args[1:], true)
 	case "help", "--help", "-h":
 		printSessionHelp()
 	default:
@@ -110,28 +140,42 @@ func printSessionHelp() {
 	fmt.Println("Manage individual sessions.")
 	fmt.Println()
 	fmt.Println("Commands:")
-	fmt.Println("  start <id>              Start a session's tmux process")
-	fmt.Println("  stop <id>               Stop/kill session process")
+	fmt.Println("  start <id> [--group <g>|--all]  Start session(s)' tmux process")
+	fmt.Println("  stop <id> [--group <g>|--all]   Stop/kill session process(es)")
 	fmt.Println("  remove <id>             Remove session from registry (stopped/error only; --force to bypass)")
 	fmt.Println("  cleanup [--days N]      Purge dead sessions idle N+ days (dry-run unless --yes)")
 	fmt.Println("  archive <id|title>      Stop session and hide it from active lists (retained in storage)")
 	fmt.Println("  unarchive <id|title>    Restore an archived session (does not restart it)")
-	fmt.Println("  restart [id] [--all] [--env KEY=VALUE]  Restart session (Claude: reload MCPs)")
+	fmt.Println("  restart [id] [--group <g>|--all] [--env KEY=VALUE]  Restart session(s) (Claude: reload MCPs)")
 	fmt.Println("  revive [--all|--name]   Rebuild dead control pipes for errored sessions")
 	fmt.Println("  fork <id>               Fork Claude, OpenCode, Pi, or Codex session with context")
+	fmt.Println("  clone <id> [--branch <b>]  New session with the same config (optionally in a fresh worktree)")
 	fmt.Println("  handoff <id>            Build a cross-tool handoff prompt from the session's conversation (read-only)")
 	fmt.Println("  attach <id>             Attach to session interactively")
 	fmt.Println("  focus <id> [--attach]   Signal the running TUI to select (or --attach) a session")
 	fmt.Println("  show [id]               Show session details (auto-detect current if no id)")
 	fmt.Println("  current                 Show current session and profile (auto-detect)")
 	fmt.Println("  set <id> <field> <value>  Update session property")
+	fmt.Println("  presets                 List start presets (resource tiers) for --preset")
 	fmt.Println("  switch-account <id> <account>  Switch Claude account and migrate the conversation")
 	fmt.Println("  move <id> <path>        Move session to a new path (migrates Claude history)")
+	fmt.Println("  relocate <id> [path]    Repoint sessions at a project that was moved or renamed")
+	fmt.Println("  snapshot <id> [-o file]  Export a session (metadata, MCPs, transcript, git ref) to a tarball")
+	fmt.Println("  restore <file>          Recreate a session from a snapshot")
+	fmt.Println("  history <id> [--since 24h]  Timeline of running/waiting/idle with totals")
 	fmt.Println("  send <id> <message>     Send a message to a running session")
+	fmt.Println("  send-to <from> <to> <message>  Send a message to another session's mailbox")
+	fmt.Println("  mailbox [id]            Read a session's unread messages (auto-detect current)")
+	fmt.Println("  continue [id] [--queue]  Send the continuation prompt to an idle session")
 	fmt.Println("  approve <id> [choice]   Resolve a visible Codex approval prompt")
 	fmt.Println("  output <id>             Get the last response from a session")
+	fmt.Println("  watch [id] [--tail]     Follow a session's pane output without attaching")
+	fmt.Println("  transcript <id> [--since 1h]  Print recorded pane output (kept after the session is killed)")
+	fmt.Println("  outputs <id> [n]        List recent agent responses, or print/copy one (--copy)")
 	fmt.Println("  children [id]           List sub-sessions with status + last completion")
 	fmt.Println("  search <query>          Search message content across Claude sessions")
+	fmt.Println("  depends <id> [--on <id>]  Show or edit prerequisites started before this session")
+	fmt.Println("  pin <id> / unpin <id>   Start the session at login (launchd/systemd user service)")
 	fmt.Println("  set-parent <id> <parent>  Link session as sub-session of parent")
 	fmt.Println("  unset-parent <id>       Remove sub-session link")
 	fmt.Println("  update <id> --no-parent          Alias for unset-parent <id>")
@@ -149,7 +193,9 @@ func printSessionHelp() {
 	fmt.Println("  agent-deck session stop abc123")
 	fmt.Println("  agent-deck session restart my-project")
 	fmt.Println("  agent-deck session restart --all                # Restart all active sessions")
+	fmt.Println("  agent-deck session stop --group backend         # Stop every session in a group")
 	fmt.Println("  agent-deck session fork my-project -t \"my-project-fork\"")
+	fmt.Println("  agent-deck session clone my-project --branch my-project-take2")
 	fmt.Println("  agent-deck session attach my-project")
 	fmt.Println("  agent-deck session show                  # Auto-detect current session")
 	fmt.Println("  agent-deck session show my-project --json")
@@ -191,11 +237,19 @@ func handleSessionStart(profile string, args []string) {
 	messageFile := fs.String("message-file", "", "Read the initial message from a file ('-' for stdin); avoids shell quoting of long prompts")
 	yoloMode := fs.Bool("yolo", false, "Enable YOLO mode when starting Gemini or Codex sessions")
 	attach := fs.Bool("attach", false, "Attach to the session after starting (requires an interactive terminal)")
+	noDeps := fs.Bool("no-deps", false, "Do not start stopped prerequisites (see 'session depends')")
+	group := fs.String("group", "", "Start every stopped session in this group (and its subgroups)")
+	all := fs.Bool("all", false, "Start every stopped session")
+	showProgress := fs.Bool("progress", false, "With --group/--all, print per-session progress to stderr")
+	preset := fs.String("preset", "", "Switch to a start preset (light, standard, heavy, or [presets.<name>]) before starting")
+	ifStopped := fs.Bool("if-stopped", false, "Succeed without doing anything when the session is already running (used by pinned sessions)")
 
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck session start <id|title> [options]")
+		fmt.Println("       agent-deck session start --group <group> | --all [options]")
 		fmt.Println()
-		fmt.Println("Start a session's tmux process.")
+		fmt.Println("Start a session's tmux process. Stopped prerequisites declared with")
+		fmt.Println("'session depends' are started first, in dependency order.")
 		fmt.Println()
 		fmt.Println("Options:")
 		fs.PrintDefaults()
@@ -206,6 +260,8 @@ func handleSessionStart(profile string, args []string) {
 		fmt.Println("  agent-deck session start my-project -m \"Explain this codebase\"")
 		fmt.Println("  agent-deck session start my-project --message-file task.md   # long prompt from file, no shell quoting")
 		fmt.Println("  git diff | agent-deck session start my-project --message-file -   # initial message from stdin")
+		fmt.Println("  agent-deck session start --group backend")
+		fmt.Println("  agent-deck session start my-project --preset heavy")
 	}
 
 	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
@@ -230,6 +286,16 @@ func handleSessionStart(profile string, args []string) {
 		os.Exit(1)
 	}
 
+	if *all || *group != "" {
+		if initialMessage != "" || *attach || *yoloMode || *preset != "" {
+			out.Error("--message, --attach, --yolo and --preset apply to a single session; not supported with --group/--all", ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		targets, groupPath := resolveBulkTargets(out, identifier, *group, *all, instances, groups)
+		bulkStartSessions(out, storage, instances, groups, targets, groupPath, *noDeps, bulkProgress(*showProgress))
+		return
+	}
+
 	// Resolve session
 	inst, errMsg, errCode := ResolveSession(identifier, instances)
 	if inst == nil {
@@ -243,6 +309,15 @@ func handleSessionStart(profile string, args []string) {
 
 	// Check if already running
 	if inst.Exists() {
+		if *ifStopped {
+			out.Success(fmt.Sprintf("Session already running: %s", inst.Title), map[string]interface{}{
+				"success":         true,
+				"id":              inst.ID,
+				"title":           inst.Title,
+				"already_running": true,
+			})
+			return
+		}
 		out.Error(fmt.Sprintf("session '%s' is already running", inst.Title), ErrCodeInvalidOperation)
 		os.Exit(1)
 	}
@@ -252,6 +327,13 @@ func handleSessionStart(profile string, args []string) {
 		os.Exit(1)
 	}
 
+	if *preset != "" {
+		if err := inst.ApplyStartPreset(*preset); err != nil {
+			out.Error(err.Error(), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	}
+
 	// v1.9.1 group concurrency cap: if the target group is at its
 	// max_concurrent cap, mark this session queued instead of starting.
 	// The queue drains in handleSessionStop. Groups with max_concurrent<=0
@@ -278,6 +360,19 @@ func handleSessionStart(profile string, args []string) {
 		return
 	}
 
+	// Start stopped prerequisites first so the session comes up with its
+	// dependencies already running.
+	var startedDeps []*session.Instance
+	if !*noDeps {
+		startedDeps, err = startDependencies(inst, instances)
+		if err != nil {
+			// Persist whatever did start so the TUI sees it.
+			_ = saveSessionData(storage, instances, groups)
+			out.Error(err.Error(), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	}
+
 	// Start the session (with or without initial message)
 	if initialMessage != "" {
 		if err := inst.StartWithMessage(initialMessage); err != nil {
@@ -334,12 +429,21 @@ func handleSessionStart(profile string, args []string) {
 	if inst.ClaudeSessionID != "" {
 		jsonData["claude_session_id"] = inst.ClaudeSessionID
 	}
+	depsNote := ""
+	if len(startedDeps) > 0 {
+		ids := make([]string, len(startedDeps))
+		for i, dep := range startedDeps {
+			ids[i] = dep.ID
+		}
+		jsonData["started_dependencies"] = ids
+		depsNote = fmt.Sprintf(" (after prerequisites: %s)", formatStartOrder(startedDeps))
+	}
 	if initialMessage != "" {
 		jsonData["message"] = initialMessage
 		jsonData["message_pending"] = false
-		out.Success(fmt.Sprintf("Started session: %s (message sent)", inst.Title), jsonData)
+		out.Success(fmt.Sprintf("Started session: %s (message sent)%s", inst.Title, depsNote), jsonData)
 	} else {
-		out.Success(fmt.Sprintf("Started session: %s", inst.Title), jsonData)
+		out.Success(fmt.Sprintf("Started session: %s%s", inst.Title, depsNote), jsonData)
 	}
 }
 
@@ -349,9 +453,13 @@ func handleSessionStop(profile string, args []string) {
 	jsonOutput := fs.Bool("json", false, "Output as JSON")
 	quiet := fs.Bool("quiet", false, "Minimal output")
 	quietShort := fs.Bool("q", false, "Minimal output (short)")
+	group := fs.String("group", "", "Stop every running session in this group (and its subgroups)")
+	all := fs.Bool("all", false, "Stop every running session")
+	showProgress := fs.Bool("progress", false, "With --group/--all, print per-session progress to stderr")
 
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck session stop <id|title> [options]")
+		fmt.Println("       agent-deck session stop --group <group> | --all [options]")
 		fmt.Println()
 		fmt.Println("Stop/kill a session's process (tmux session remains).")
 		fmt.Println()
@@ -374,6 +482,12 @@ func handleSessionStop(profile string, args []string) {
 		os.Exit(1)
 	}
 
+	if *all || *group != "" {
+		targets, groupPath := resolveBulkTargets(out, identifier, *group, *all, instances, groups)
+		bulkStopSessions(out, storage, instances, groups, targets, groupPath, bulkProgress(*showProgress))
+		return
+	}
+
 	// Resolve session
 	inst, errMsg, errCode := ResolveSession(identifier, instances)
 	if inst == nil {
@@ -409,6 +523,13 @@ func handleSessionStop(profile string, args []string) {
 	// stop drains the next entry.
 	drained := drainGroupQueue(inst.GroupPath, instances, groups)
 
+	// A conductor child freed a dispatch slot: start deferred dispatches
+	// that now fit under [conductor.dispatch].
+	var dispatched []*session.Instance
+	if session.IsConductorDispatch(inst, instances) {
+		dispatched = drainConductorDispatch(storage, session.GetConductorSettings().Dispatch, instances)
+	}
+
 	// Save updated state
 	if err := saveSessionData(storage, instances, groups); err != nil {
 		out.Error(fmt.Sprintf("failed to save session state: %v", err), ErrCodeInvalidOperation)
@@ -425,6 +546,13 @@ func handleSessionStop(profile string, args []string) {
 		result["drained"] = drained.ID
 		result["drained_title"] = drained.Title
 	}
+	if len(dispatched) > 0 {
+		ids := make([]string, 0, len(dispatched))
+		for _, d := range dispatched {
+			ids = append(ids, d.ID)
+		}
+		result["dispatched"] = ids
+	}
 	out.Success(fmt.Sprintf("Stopped session: %s", inst.Title), result)
 }
 
@@ -638,7 +766,7 @@ func drainGroupQueue(groupPath string, instances []*session.Instance, groups []*
 	return next
 }
 
-// handleSessionRestart restarts a session (or all active sessions with --all)
+// handleSessionRestart restarts a session (or a group / all active sessions)
 func handleSessionRestart(profile string, args []string) {
 	fs := flag.NewFlagSet("session restart", flag.ExitOnError)
 	jsonOutput := fs.Bool("json", false, "Output as JSON")
@@ -646,11 +774,14 @@ func handleSessionRestart(profile string, args []string) {
 	quietShort := fs.Bool("q", false, "Minimal output (short)")
 	force := fs.Bool("force", false, "Restart even if the session is already healthy and fresh (bypasses issue #30 guard)")
 	all := fs.Bool("all", false, "Restart all active sessions")
+	showProgress := fs.Bool("progress", false, "With --group/--all, print per-session progress to stderr")
+	group := fs.String("group", "", "Restart every active session in this group (and its subgroups)")
 	envFlags := make(envVarFlags)
 	fs.Var(&envFlags, "env", "Environment variable in KEY=VALUE format for the restarted process (can be repeated)")
 
 	fs.Usage = func() {
-		fmt.Println("Usage: agent-deck session restart [id|title] [options]")
+		fmt.Println("Usage: agent-deck session restart <id|title> [options]")
+		fmt.Println("       agent-deck session restart --group <group> | --all [options]")
 		fmt.Println()
 		fmt.Println("Restart a session. For Claude sessions, this reloads MCPs.")
 		fmt.Println()
@@ -666,6 +797,8 @@ func handleSessionRestart(profile string, args []string) {
 		fmt.Println("  agent-deck session restart my-project")
 		fmt.Println("  agent-deck session restart my-project --env API_URL=https://api.example.com")
 		fmt.Println("  agent-deck session restart my-project --env FOO=one --env BAR=two")
+		fmt.Println("  agent-deck session restart --group backend")
+		fmt.Println("  agent-deck session restart --group backend --progress")
 		fmt.Println("  agent-deck session restart --all")
 	}
 
@@ -683,14 +816,15 @@ func handleSessionRestart(profile string, args []string) {
 		os.Exit(1)
 	}
 
-	if *all {
-		restartAllSessions(out, storage, instances, groups, envFlags)
+	identifier := fs.Arg(0)
+	if *all || *group != "" {
+		targets, groupPath := resolveBulkTargets(out, identifier, *group, *all, instances, groups)
+		bulkRestartSessions(out, storage, instances, groups, targets, groupPath, envFlags, *force, bulkProgress(*showProgress))
 		return
 	}
 
-	identifier := fs.Arg(0)
 	if identifier == "" {
-		out.Error("session identifier required (or use --all)", ErrCodeInvalidOperation)
+		out.Error("session identifier required (or use --group/--all)", ErrCodeInvalidOperation)
 		fs.Usage()
 		os.Exit(1)
 	}
@@ -758,94 +892,6 @@ func handleSessionRestart(profile string, args []string) {
 	out.Success(fmt.Sprintf("Restarted session: %s", inst.Title), data)
 }
 
-// restartAllSessions restarts every active session one by one.
-func restartAllSessions(out *CLIOutput, storage *session.Storage, instances []*session.Instance, groups []*session.GroupData, env map[string]string) {
-	var active []*session.Instance
-	for _, inst := range instances {
-		if inst.Exists() {
-			active = append(active, inst)
-		}
-	}
-
-	if len(active) == 0 {
-		out.Error("no active sessions to restart", ErrCodeNotFound)
-		os.Exit(1)
-	}
-
-	var results []map[string]interface{}
-	var failed int
-
-	for _, inst := range active {
-		result := map[string]interface{}{
-			"id":    inst.ID,
-			"title": inst.Title,
-		}
-
-		if !out.jsonMode {
-			fmt.Printf("Restarting %s...\n", inst.Title)
-		}
-
-		if err := inst.RestartWithEnv(env); err != nil {
-			errMsg := fmt.Sprintf("failed to restart session '%s': %v", inst.Title, err)
-			if !out.jsonMode {
-				fmt.Fprintf(os.Stderr, "  Error: %s\n", errMsg)
-			}
-			result["success"] = false
-			result["error"] = errMsg
-			failed++
-			results = append(results, result)
-			continue
-		}
-		inst.LastStartedAt = time.Now()
-
-		warning := inst.ConsumeCodexRestartWarning()
-		if warning != "" && !out.jsonMode {
-			fmt.Fprintf(os.Stderr, "  Warning: %s\n", warning)
-		}
-
-		// If restart created a fresh session (no prior ID), capture the new ID
-		if session.IsClaudeCompatible(inst.Tool) && inst.ClaudeSessionID == "" {
-			inst.PostStartSync(3 * time.Second)
-		}
-
-		result["success"] = true
-		if warning != "" {
-			result["warning"] = warning
-		}
-		results = append(results, result)
-
-		if !out.jsonMode {
-			fmt.Printf("  Done: %s\n", inst.Title)
-		}
-	}
-
-	// Save updated state after all restarts
-	if err := saveSessionData(storage, instances, groups); err != nil {
-		out.Error(fmt.Sprintf("failed to save session state: %v", err), ErrCodeInvalidOperation)
-		os.Exit(1)
-	}
-
-	if out.jsonMode {
-		out.Success("", map[string]interface{}{
-			"success":   failed == 0,
-			"total":     len(active),
-			"restarted": len(active) - failed,
-			"failed":    failed,
-			"sessions":  results,
-		})
-	} else if !out.quietMode {
-		fmt.Printf("Restarted %d/%d sessions", len(active)-failed, len(active))
-		if failed > 0 {
-			fmt.Printf(" (%d failed)", failed)
-		}
-		fmt.Println()
-	}
-
-	if failed > 0 {
-		os.Exit(1)
-	}
-}
-
 // sessionForkBeforeStartHook is nil in production. Tests assign it to inspect
 // the fully-prepared fork before tmux Start() mutates the environment. When
 // the hook is set, handleSessionFork invokes it and returns immediately —
@@ -881,11 +927,14 @@ func handleSessionFork(profile string, args []string) {
 	withStateGitignored := fs.Bool("with-state-and-gitignored", false, "Like --with-state, plus gitignored files (e.g. .env). Implies --with-state. Requires -w.")
 	sandbox := fs.Bool("sandbox", false, "Run forked session in Docker sandbox")
 	sandboxImage := fs.String("sandbox-image", "", "Docker image for sandbox (overrides config default)")
+	contextLines := fs.Int("context-lines", 0, fmt.Sprintf("Codex/OpenCode: start a fresh session seeded with the parent's last N terminal lines instead of a native fork (used automatically, with %d lines, when no native fork is possible)", session.DefaultForkContextLines))
 
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck session fork <id|title> [options]")
 		fmt.Println()
 		fmt.Println("Fork a Claude, OpenCode, Pi, or Codex session with conversation context.")
+		fmt.Println("Codex and OpenCode sessions without a resumable conversation are forked")
+		fmt.Println("by seeding a fresh session with the parent's recent terminal output.")
 		fmt.Println()
 		fmt.Println("Options:")
 		fs.PrintDefaults()
@@ -898,6 +947,7 @@ func handleSessionFork(profile string, args []string) {
 		fmt.Println("  agent-deck session fork my-project -w fork/new-idea -b")
 		fmt.Println("  agent-deck session fork my-project -w fork/wip -b --with-state")
 		fmt.Println("  agent-deck session fork my-project -w fork/wip -b --with-state-and-gitignored")
+		fmt.Println("  agent-deck session fork codex-worker --context-lines 400")
 	}
 
 	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
@@ -948,8 +998,29 @@ func handleSessionFork(profile string, args []string) {
 		inst.PostStartSync(2 * time.Second)
 	}
 
+	// Codex and OpenCode fall back to a context fork: a fresh session whose
+	// first prompt carries the parent's recent terminal output.
+	if *contextLines < 0 {
+		out.Error("--context-lines must be positive", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if *contextLines > 0 && !session.SupportsContextFork(inst.Tool) {
+		out.Error(fmt.Sprintf("--context-lines only applies to Codex and OpenCode sessions; '%s' uses %s", inst.Title, inst.Tool), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	contextFork := *contextLines > 0 || (session.SupportsContextFork(inst.Tool) && !inst.CanFork())
+	var contextPrompt string
+	if contextFork {
+		lines, err := inst.ForkContext(*contextLines)
+		if err != nil {
+			out.Error(fmt.Sprintf("session '%s' cannot be forked: %v", inst.Title, err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		contextPrompt = session.BuildForkContextPrompt(inst, lines)
+	}
+
 	// Verify it can be forked.
-	if !inst.CanFork() {
+	if !contextFork && !inst.CanFork() {
 		out.Error(
 			fmt.Sprintf("session '%s' cannot be forked: no resumable session for tool %s", inst.Title, inst.Tool),
 			ErrCodeInvalidOperation,
@@ -1216,10 +1287,14 @@ func handleSessionFork(profile string, args []string) {
 
 	// Create the forked instance
 	var forkedInst *session.Instance
-	forkedInst, _, err = inst.CreateForkedInstanceForTool(forkTitle, forkGroup, opts)
-	if err != nil {
-		out.Error(fmt.Sprintf("failed to create fork: %v", err), ErrCodeInvalidOperation)
-		os.Exit(1)
+	if contextFork {
+		forkedInst = inst.CreateContextForkedInstance(forkTitle, forkGroup, opts)
+	} else {
+		forkedInst, _, err = inst.CreateForkedInstanceForTool(forkTitle, forkGroup, opts)
+		if err != nil {
+			out.Error(fmt.Sprintf("failed to create fork: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
 	}
 	if explicitTitle {
 		forkedInst.TitleLocked = true
@@ -1242,9 +1317,16 @@ func handleSessionFork(profile string, args []string) {
 		return
 	}
 
-	// Start the forked session
-	if err := forkedInst.Start(); err != nil {
-		out.Error(fmt.Sprintf("failed to start forked session: %v", err), ErrCodeInvalidOperation)
+	// Start the forked session; a context fork gets the parent's output as
+	// its first prompt.
+	var startErr error
+	if contextFork {
+		startErr = forkedInst.StartWithMessage(contextPrompt)
+	} else {
+		startErr = forkedInst.Start()
+	}
+	if startErr != nil {
+		out.Error(fmt.Sprintf("failed to start forked session: %v", startErr), ErrCodeInvalidOperation)
 		os.Exit(1)
 	}
 
@@ -1276,6 +1358,7 @@ func handleSessionFork(profile string, args []string) {
 			"parent_id": inst.ID,
 			"new_id":    forkedInst.ID,
 			"new_title": forkedInst.Title,
+			"context":   contextFork,
 		},
 	)
 }
@@ -1536,11 +1619,15 @@ func handleSessionShow(profile string, args []string) {
 	jsonOutput := fs.Bool("json", false, "Output as JSON")
 	quiet := fs.Bool("quiet", false, "Minimal output")
 	quietShort := fs.Bool("q", false, "Minimal output (short)")
+	outputLines := fs.Int("lines", 20, "Recent output lines to include (0 to omit)")
+	hookEvents := fs.Int("events", 10, "Recent hook events to include (0 to omit)")
 
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck session show [id|title] [options]")
 		fmt.Println()
 		fmt.Println("Show session details. If no ID is provided, auto-detects current session.")
+		fmt.Println("Besides the session's settings this includes its recent output, latest")
+		fmt.Println("hook events, attached MCPs and skills, and the worktree's diffstat.")
 		fmt.Println()
 		fmt.Println("Options:")
 		fs.PrintDefaults()
@@ -1598,11 +1685,8 @@ func handleSessionShow(profile string, args []string) {
 	// Update status
 	_ = inst.UpdateStatus()
 
-	// Get MCP info if Claude session
-	var mcpInfo *session.MCPInfo
-	if session.IsClaudeCompatible(inst.Tool) {
-		mcpInfo = inst.GetMCPInfo()
-	}
+	// MCPs for the tools agent-deck manages them for (nil otherwise)
+	mcpInfo := inst.GetMCPInfo()
 
 	// Prepare JSON output
 	jsonData := map[string]interface{}{
@@ -1611,6 +1695,7 @@ func handleSessionShow(profile string, args []string) {
 		"profile":              profile,
 		"status":               StatusString(inst.Status),
 		"path":                 inst.ProjectPath,
+		"path_missing":         inst.ProjectPathMissing(),
 		"group":                inst.GroupPath,
 		"parent_session_id":    inst.ParentSessionID,
 		"parent_project_path":  inst.ParentProjectPath,
@@ -1627,20 +1712,29 @@ func handleSessionShow(profile string, args []string) {
 	modelInfo := inst.LaunchModelInfo()
 	addModelInfoJSON(jsonData, modelInfo)
 	addAutoNameJSON(jsonData, inst)
+	if inst.Preset != "" {
+		jsonData["preset"] = inst.Preset
+	}
 
 	if inst.Command != "" {
 		jsonData["command"] = inst.Command
 	}
+	if len(inst.Labels) > 0 {
+		jsonData["labels"] = inst.Labels
+	}
+	idleTimeout, idleSource := inst.ResolveIdleTimeout()
+	if idleTimeout > 0 {
+		jsonData["idle_timeout"] = map[string]interface{}{
+			"seconds": int64(idleTimeout / time.Second),
+			"source":  idleSource,
+		}
+	}
 
 	if session.IsClaudeCompatible(inst.Tool) {
 		jsonData["claude_session_id"] = inst.ClaudeSessionID
 		jsonData["can_fork"] = inst.CanFork()
 		jsonData["can_restart"] = inst.CanRestart()
 
-		if mcps := mcpInfoForJSON(mcpInfo); mcps != nil {
-			jsonData["mcps"] = mcps
-		}
-
 		// Always include channels for claude sessions — omitting when empty
 		// would make absence-of-field ambiguous with absence-of-value. Match
 		// the `list --json` emitter which surfaces this field unconditionally.
@@ -1667,10 +1761,30 @@ func handleSessionShow(profile string, args []string) {
 		}
 	}
 
+	if mcps := mcpInfoForJSON(mcpInfo); mcps != nil {
+		jsonData["mcps"] = mcps
+	}
+	skills := attachedSkillRefs(inst)
+	if len(skills) > 0 {
+		jsonData["skills"] = skills
+	}
+
 	if tmuxSession := inst.GetTmuxSession(); tmuxSession != nil {
 		jsonData["tmux_session"] = tmuxSession.Name
 	}
 
+	// Session dependencies: prerequisites and the resolved start order.
+	// A session found via cross-profile tmux detection is not in instances;
+	// graph it on its own so its keys are still present.
+	graphInstances := instances
+	if !containsInstance(instances, inst) {
+		graphInstances = []*session.Instance{inst}
+	}
+	depGraph := session.NewDependencyGraph(graphInstances)
+	for k, v := range dependencyJSON(depGraph, inst) {
+		jsonData[k] = v
+	}
+
 	// #1580: surface a spawn-failure diagnostic when the session errored at
 	// startup (bare "error" with no pane). Include the structured record in
 	// --json so tooling can read it too.
@@ -1685,6 +1799,25 @@ func handleSessionShow(profile string, args []string) {
 		}
 	}
 
+	// The agent's recorded exit (exited/crashed statuses): code and where it
+	// came from, so tooling can tell a clean finish from a crash.
+	exitRecord := inst.ExitRecord()
+	if exitRecord != nil {
+		exitJSON := map[string]interface{}{
+			"code":   exitRecord.Code,
+			"source": exitRecord.Source,
+		}
+		if !exitRecord.At.IsZero() {
+			exitJSON["at"] = exitRecord.At.Format(time.RFC3339)
+		}
+		jsonData["exit"] = exitJSON
+	}
+	pinnedService := session.PinnedServicePath(inst.ID)
+	if pinnedService != "" {
+		jsonData["pinned"] = true
+		jsonData["pinned_service"] = pinnedService
+	}
+
 	// Build human-readable output
 	var sb strings.Builder
 
@@ -1692,11 +1825,20 @@ func handleSessionShow(profile string, args []string) {
 	sb.WriteString(fmt.Sprintf("Profile: %s\n", profile))
 	sb.WriteString(fmt.Sprintf("ID:      %s\n", inst.ID))
 	sb.WriteString(fmt.Sprintf("Status:  %s %s\n", StatusSymbol(inst.Status), StatusString(inst.Status)))
+	if exitRecord != nil {
+		sb.WriteString(fmt.Sprintf("Exit:    %s\n", exitRecord.Describe()))
+	}
 	sb.WriteString(fmt.Sprintf("Path:    %s\n", FormatPath(inst.ProjectPath)))
+	if inst.ProjectPathMissing() {
+		sb.WriteString(fmt.Sprintf("         %s missing (moved?) — agent-deck session relocate %s [new-path]\n", errorSymbol, inst.ID))
+	}
 
 	if inst.GroupPath != "" {
 		sb.WriteString(fmt.Sprintf("Group:   %s\n", inst.GroupPath))
 	}
+	if pinnedService != "" {
+		sb.WriteString(fmt.Sprintf("Pinned:  starts at login (%s)\n", FormatPath(pinnedService)))
+	}
 
 	sb.WriteString(fmt.Sprintf("Tool:    %s\n", inst.Tool))
 	if modelInfo.ModelID != "" {
@@ -1710,6 +1852,9 @@ func handleSessionShow(profile string, args []string) {
 	} else if session.SupportsLaunchModel(inst.Tool) {
 		sb.WriteString("Model:   tool default\n")
 	}
+	if inst.Preset != "" {
+		sb.WriteString(fmt.Sprintf("Preset:  %s\n", inst.Preset))
+	}
 
 	if inst.Command != "" {
 		sb.WriteString(fmt.Sprintf("Command: %s\n", inst.Command))
@@ -1730,20 +1875,6 @@ func handleSessionShow(profile string, args []string) {
 			sb.WriteString("Claude:  no session ID detected\n")
 		}
 
-		if mcpInfo != nil && mcpInfo.HasAny() {
-			var mcpParts []string
-			for _, name := range mcpInfo.Local() {
-				mcpParts = append(mcpParts, name+" (local)")
-			}
-			for _, name := range mcpInfo.Global {
-				mcpParts = append(mcpParts, name+" (global)")
-			}
-			for _, name := range mcpInfo.Project {
-				mcpParts = append(mcpParts, name+" (project)")
-			}
-			sb.WriteString(fmt.Sprintf("MCPs:    %s\n", strings.Join(mcpParts, ", ")))
-		}
-
 		// Channels and Plugins (RFC docs/rfc/PLUGIN_ATTACH.md). Surfaced
 		// for claude sessions so users can verify per-session topology
 		// without parsing state.db or the scratch settings.json.
@@ -1758,9 +1889,45 @@ func handleSessionShow(profile string, args []string) {
 		}
 	}
 
+	if mcpInfo != nil && mcpInfo.HasAny() {
+		var mcpParts []string
+		for _, name := range mcpInfo.Local() {
+			mcpParts = append(mcpParts, name+" (local)")
+		}
+		for _, name := range mcpInfo.Global {
+			mcpParts = append(mcpParts, name+" (global)")
+		}
+		for _, name := range mcpInfo.Project {
+			mcpParts = append(mcpParts, name+" (project)")
+		}
+		sb.WriteString(fmt.Sprintf("MCPs:    %s\n", strings.Join(mcpParts, ", ")))
+	}
+	if len(skills) > 0 {
+		sb.WriteString(fmt.Sprintf("Skills:  %s\n", strings.Join(skills, ", ")))
+	}
+
+	if cost, ok := sessionCost(profile, inst); ok {
+		jsonData["cost"] = sessionCostJSON(cost)
+		sb.WriteString(fmt.Sprintf("Cost:    %s\n", formatSessionCost(cost)))
+	}
+
+	if deps := depGraph.Dependencies(inst.ID); len(deps) > 0 {
+		if order, err := depGraph.StartOrder(inst.ID); err == nil {
+			sb.WriteString(fmt.Sprintf("Depends: %s\n", formatStartOrder(order)))
+		} else {
+			sb.WriteString(fmt.Sprintf("Depends: %v\n", err))
+		}
+	}
+
 	if inst.NoTransitionNotify {
 		sb.WriteString("Notify:  transition events suppressed\n")
 	}
+	if len(inst.Labels) > 0 {
+		sb.WriteString(fmt.Sprintf("Labels:  %s\n", strings.Join(inst.Labels, ", ")))
+	}
+	if idleTimeout > 0 {
+		sb.WriteString(fmt.Sprintf("Idle stop: after %s idle (%s)\n", formatDuration(idleTimeout), idleSource))
+	}
 	sb.WriteString(fmt.Sprintf("Created: %s\n", inst.CreatedAt.Format("2006-01-02 15:04:05")))
 
 	if !inst.LastAccessedAt.IsZero() {
@@ -1782,9 +1949,88 @@ func handleSessionShow(profile string, args []string) {
 		sb.WriteString(spawnFailure.FormatForDisplay())
 	}
 
+	// What the agent has been doing: worktree changes, hook events and the
+	// tail of its terminal.
+	if inst.IsWorktree() && inst.WorktreeBranch != "" && inst.SSHHost == "" {
+		if d, err := session.DiffInstanceWorktree(inst, ""); err == nil {
+			dirty, _ := git.HasUncommittedChanges(inst.WorktreePath)
+			added, deleted := d.Totals()
+			files := make([]map[string]interface{}, 0, len(d.Files))
+			for _, f := range d.Files {
+				files = append(files, map[string]interface{}{
+					"path":    f.Path,
+					"status":  f.Status,
+					"added":   f.Added,
+					"deleted": f.Deleted,
+				})
+			}
+			jsonData["worktree_diff"] = map[string]interface{}{
+				"branch":      d.Branch,
+				"base":        d.Base,
+				"commits":     d.Commits,
+				"added":       added,
+				"deleted":     deleted,
+				"uncommitted": dirty,
+				"files":       files,
+			}
+			sb.WriteString("\nWorktree: " + describeBranchDiff(d) + "\n")
+			for _, f := range d.Files {
+				sb.WriteString(fmt.Sprintf("  %s  +%d -%d  %s\n", f.Status, f.Added, f.Deleted, f.Path))
+			}
+			if dirty {
+				sb.WriteString("  (uncommitted changes not included)\n")
+			}
+		}
+	}
+
+	if *hookEvents > 0 {
+		if events, err := session.ReadHookTimeline(inst.ID, *hookEvents); err == nil && len(events) > 0 {
+			jsonData["hook_events"] = events
+			sb.WriteString("\nHook events:\n")
+			for _, ev := range events {
+				line := fmt.Sprintf("  %s  %-18s %s", ev.Timestamp.Local().Format("2006-01-02 15:04:05"), ev.Event, ev.Status)
+				if ev.DoneStatus != "" {
+					line += " (" + ev.DoneStatus + ")"
+				}
+				sb.WriteString(line + "\n")
+			}
+		}
+	}
+
+	if *outputLines > 0 {
+		if lines, err := inst.ForkContext(*outputLines); err == nil {
+			jsonData["recent_output"] = lines
+			sb.WriteString(fmt.Sprintf("\nRecent output (last %d lines):\n", len(lines)))
+			for _, l := range lines {
+				sb.WriteString("  " + l + "\n")
+			}
+		}
+	}
+
 	out.Print(sb.String(), jsonData)
 }
 
+// attachedSkillRefs lists the project skills attached to inst's project as
+// "source/name", or nil when its tool has no project skills.
+func attachedSkillRefs(inst *session.Instance) []string {
+	if !session.SupportsProjectSkills(inst.Tool) {
+		return nil
+	}
+	attached, err := session.GetAttachedProjectSkills(inst.ProjectPath)
+	if err != nil {
+		return nil
+	}
+	refs := make([]string, 0, len(attached))
+	for _, s := range attached {
+		ref := s.Name
+		if s.Source != "" {
+			ref = s.Source + "/" + s.Name
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
 func mcpInfoForJSON(mcpInfo *session.MCPInfo) map[string]interface{} {
 	if mcpInfo == nil || !mcpInfo.HasAny() {
 		return nil
@@ -1818,11 +2064,15 @@ func handleSessionSet(profile string, args []string) {
 		fmt.Printf("  plugins            Comma-separated plugin catalog names (claude only) — see [plugins.<name>] in %s\n", effectiveUserConfigPathForHelp())
 		fmt.Println("  extra-args         Extra claude CLI tokens (claude only; use `-- --flag value` for tokens starting with -; persisted plaintext — no secrets)")
 		fmt.Println("  model              Per-session model override (e.g. opus/sonnet/haiku or a gemini model); persists across restart (#1436). Empty clears it.")
+		fmt.Println("  preset             Start preset: light, standard, heavy or a [presets.<name>] — sets model, MCPs and tool flags; restart required")
 		fmt.Println("  color              Optional TUI row tint: '#RRGGBB' or ANSI '0'..'255' or '' (issue #391)")
 		fmt.Println("  claude-session-id  Claude conversation ID")
 		fmt.Println("  gemini-session-id  Gemini conversation ID")
 		fmt.Println("  account            Named account slot (#924) — resolves via [profiles.<account>.claude].config_dir; restart required")
 		fmt.Println("  idle-timeout       Auto-stop after no tmux output for this duration (#1143; Go duration: 30m, 1h, 24h; 0 disables)")
+		fmt.Println("  working-hours      Override the group's working_hours window (e.g. 09:00-17:00, mon-fri 18:00-23:00); 'always' exempts, '' inherits")
+		fmt.Println("  labels             Comma-separated labels; the [idle_stop] exempt label (default keep-alive) opts out of idle auto-stop. '' clears")
+		fmt.Println("  checkpoint         Automatic checkpoints: 'waiting' (each finished turn), an interval like 15m, or 'waiting,15m'; 'off' disables (see 'agent-deck checkpoint')")
 		fmt.Println()
 		fmt.Println("Options:")
 		fs.PrintDefaults()
@@ -2591,6 +2841,9 @@ func handleSessionSend(profile string, args []string) {
 	streamIdle := fs.Duration("stream-idle", 10*time.Second, "Max idle time before --stream aborts with error")
 	streamCharBudget := fs.Int("stream-char-budget", 4000, "Char budget for text flush in --stream mode")
 	streamToolBudget := fs.Int("stream-tool-budget", 3, "Tool-event budget for text flush in --stream mode")
+	force := fs.Bool("force", false, "Send even if the [send_lint] prompt checks fail")
+	check := fs.Bool("check", false, "Run the prompt checks and show what would be sent, without sending")
+	noQueue := fs.Bool("no-queue", false, "Send now even if the [governor] would queue the message")
 
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck session send <id|title> <message> [options]")
@@ -2609,6 +2862,8 @@ func handleSessionSend(profile string, args []string) {
 		fmt.Println("  agent-deck session send my-project --message-file answer.md   # long reply from file")
 		fmt.Println("  git diff | agent-deck session send my-project --message-file -   # message from stdin")
 		fmt.Println("  agent-deck session send parent \"child done\" --defer-if-busy --defer-timeout 30m")
+		fmt.Println("  agent-deck session send my-project --message-file plan.md --check   # lint only")
+		fmt.Println("  agent-deck session send my-project \"next task\" --no-queue   # bypass the [governor]")
 	}
 
 	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
@@ -2649,8 +2904,18 @@ func handleSessionSend(profile string, args []string) {
 		os.Exit(1)
 	}
 
+	// Pre-send prompt checks: opt-in via [send_lint], always on for --check.
+	if *check || (!*force && session.GetSendLintSettings().Enabled) {
+		if !lintSendMessage(out, message, *check) {
+			os.Exit(1)
+		}
+		if *check {
+			return
+		}
+	}
+
 	// Load sessions
-	_, instances, _, err := loadSessionData(profile)
+	storage, instances, _, err := loadSessionData(profile)
 	if err != nil {
 		out.Error(err.Error(), ErrCodeNotFound)
 		os.Exit(1)
@@ -2700,6 +2965,14 @@ func handleSessionSend(profile string, args []string) {
 		os.Exit(1)
 	}
 
+	// [governor]: over the Claude concurrency cap, or while the account is
+	// usage-limited, queue the message for release when capacity frees up.
+	// --wait/--stream need the reply, so they block here instead. A draft
+	// is never submitted, so it is not governed.
+	if !*noQueue && !*draft && governSend(profile, out, storage, inst, instances, message, *wait || *stream, *timeout) {
+		return
+	}
+
 	// #1578: --defer-if-busy holds delivery until the target is turn-finished.
 	// Runs BEFORE WaitForAgentReady + the composer-draft Ctrl+C guard, so a
 	// mid-generation target is never interrupted. Keys off the hook-driven
@@ -2796,6 +3069,10 @@ func handleSessionSend(profile string, args []string) {
 		_ = db.WriteLastSentAt(inst.ID, sentAt.Unix())
 	}
 
+	// Read receipt: a prompt to a conductor child is tracked until the child
+	// shows it started working (conductor status / conductor receipts).
+	session.RecordDispatchReceipt(statedb.GetGlobal(), inst, instances, message, sentAt)
+
 	// Delivery succeeded, but if an operator draft was cleared and could not
 	// be typed back, it's no longer on screen — surface it on stderr (it's
 	// also in saved_draft in --json) so the operator can recover it rather
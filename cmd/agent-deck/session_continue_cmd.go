@@ -0,0 +1,132 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/send"
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// handleSessionContinue nudges an idle or waiting session with its
+// continuation prompt. A busy session is refused, or with --queue held
+// until its turn finishes.
+func handleSessionContinue(profile string, args []string) {
+	fs := flag.NewFlagSet("session continue", flag.ExitOnError)
+	fs.SetOutput(os.Stdout)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode")
+	message := fs.String("message", "", "Continuation text (default: [continue] config, else the tool's default)")
+	messageShort := fs.String("m", "", "Continuation text (short)")
+	queue := fs.Bool("queue", false, "Wait for a busy session to finish its turn instead of refusing")
+	queueTimeout := fs.Duration("queue-timeout", 30*time.Minute, "Max time --queue waits before giving up without sending")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck session continue [id|title] [options]")
+		fmt.Println()
+		fmt.Println("Send the continuation prompt (default \"continue\") to an idle or waiting")
+		fmt.Println("session. Busy sessions are refused unless --queue is given.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck session continue my-project")
+		fmt.Println("  agent-deck session continue my-project -m \"keep going with the tests\"")
+		fmt.Println("  agent-deck session continue my-project --queue --queue-timeout 1h")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+
+	_, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	inst, errMsg, errCode := ResolveSessionOrCurrent(fs.Arg(0), instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		if errCode == ErrCodeNotFound {
+			os.Exit(2)
+		}
+		os.Exit(1)
+		return // unreachable, satisfies staticcheck SA5011
+	}
+	sessionRef := inst.ID
+
+	text := mergeFlags(*message, *messageShort)
+	if text == "" {
+		text = session.GetContinueSettings().PromptFor(inst.Tool)
+	}
+
+	if !inst.Exists() {
+		out.Error(fmt.Sprintf("session '%s' is not running", inst.Title), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	tmuxSess := inst.GetTmuxSession()
+	if tmuxSess == nil {
+		out.Error("could not determine tmux session", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	fetchStatus := func() (string, error) { return fetchHookDrivenStatus(profile, sessionRef) }
+	status, err := fetchStatus()
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	queued := false
+	if *queue && send.StatusIsBusy(status) {
+		if !*jsonOutput && !*quiet {
+			fmt.Printf("'%s' is %s; waiting for it to finish...\n", inst.Title, status)
+		}
+		if err := send.WaitUntilNotBusy(fetchStatus, *queueTimeout, send.DeferPollInterval, time.Sleep); err != nil {
+			out.Error(err.Error(), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		queued = true
+		if status, err = fetchStatus(); err != nil {
+			out.Error(err.Error(), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	}
+	if err := session.CheckContinuable(inst.Title, session.Status(status)); err != nil {
+		out.ErrorWithData(err.Error(), ErrCodeInvalidOperation, map[string]interface{}{
+			"session_id":    inst.ID,
+			"session_title": inst.Title,
+			"status":        status,
+		})
+		os.Exit(1)
+	}
+
+	sentAt := time.Now()
+	sendRes, sendErr := executeSend(tmuxSess, inst.Tool, text, false, defaultSendTuning())
+	if sendErr != nil {
+		extra := sendRes.jsonFields()
+		extra["session_id"] = inst.ID
+		extra["session_title"] = inst.Title
+		out.ErrorWithData(fmt.Sprintf("failed to send continue: %v", sendErr), ErrCodeDeliveryFailed, extra)
+		os.Exit(1)
+	}
+	if db := statedb.GetGlobal(); db != nil {
+		_ = db.WriteLastSentAt(inst.ID, sentAt.Unix())
+	}
+
+	data := map[string]interface{}{
+		"success":       true,
+		"session_id":    inst.ID,
+		"session_title": inst.Title,
+		"message":       text,
+		"queued":        queued,
+	}
+	for k, v := range sendRes.jsonFields() {
+		data[k] = v
+	}
+	out.Success(fmt.Sprintf("Sent %q to '%s'", text, inst.Title), data)
+}
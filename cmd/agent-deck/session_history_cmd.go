@@ -0,0 +1,118 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleSessionHistory implements `agent-deck session history <id>`: the
+// session's recorded status changes as a timeline, with the time spent
+// running and waiting, to see how long a task took and where it stalled.
+func handleSessionHistory(profile string, args []string) {
+	fs := flag.NewFlagSet("session history", flag.ExitOnError)
+	since := fs.Duration("since", 0, "Only show the timeline within this duration (e.g. 2h, 24h)")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck session history <id|title> [options]")
+		fmt.Println()
+		fmt.Println("Show when a session was running, waiting or idle, with totals per status.")
+		fmt.Println("Status changes are recorded as agent-deck observes them (TUI or daemon).")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck session history my-project")
+		fmt.Println("  agent-deck session history my-project --since 24h --json")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+	if fs.NArg() != 1 {
+		out.Error("session history requires <id|title>", ErrCodeInvalidOperation)
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	storage, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	defer storage.Close()
+	inst, errMsg, errCode := ResolveSession(fs.Arg(0), instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		if errCode == ErrCodeNotFound {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+
+	var cutoff time.Time
+	if *since > 0 {
+		cutoff = time.Now().Add(-*since)
+	}
+	tl, err := session.LoadStatusTimeline(storage.GetDB(), inst.ID, cutoff)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to read status history: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	type spanJSON struct {
+		Status     string    `json:"status"`
+		Start      time.Time `json:"start"`
+		End        time.Time `json:"end"`
+		DurationMs int64     `json:"duration_ms"`
+	}
+	spans := make([]spanJSON, 0, len(tl.Spans))
+	for _, s := range tl.Spans {
+		spans = append(spans, spanJSON{string(s.Status), s.Start, s.End, s.Duration().Milliseconds()})
+	}
+	totals := make(map[string]int64)
+	for status, d := range tl.Totals() {
+		totals[string(status)] = d.Milliseconds()
+	}
+	out.Print(renderStatusTimelineText(inst.Title, tl), map[string]interface{}{
+		"success":   true,
+		"id":        inst.ID,
+		"title":     inst.Title,
+		"spans":     spans,
+		"totals_ms": totals,
+	})
+}
+
+// renderStatusTimelineText prints one line per span, then the totals.
+func renderStatusTimelineText(title string, tl session.StatusTimeline) string {
+	if len(tl.Spans) == 0 {
+		return fmt.Sprintf("No status changes recorded for %s\n", title)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Status history of %s\n\n", title)
+	for _, s := range tl.Spans {
+		fmt.Fprintf(&b, "  %s  %-8s %s\n", s.Start.Local().Format("Jan 02 15:04:05"), s.Status, formatDuration(s.Duration()))
+	}
+	totals := tl.Totals()
+	statuses := make([]session.Status, 0, len(totals))
+	for status := range totals {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return totals[statuses[i]] > totals[statuses[j]] })
+	parts := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		parts = append(parts, fmt.Sprintf("%s %s", status, formatDuration(totals[status])))
+	}
+	fmt.Fprintf(&b, "\nOver %s: %s\n", formatDuration(tl.Span()), strings.Join(parts, " · "))
+	return b.String()
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// mailNotifyTimeout bounds how long send-to waits for the recipient to be
+// ready before leaving the message in its mailbox only.
+const mailNotifyTimeout = 15 * time.Second
+
+// resolveSessionOrSelf resolves ref, where "self" (or "") means the session
+// the command runs in, exiting when nothing matches.
+func resolveSessionOrSelf(ref string, instances []*session.Instance, out *CLIOutput) *session.Instance {
+	if ref == "" || strings.EqualFold(ref, "self") {
+		id, err := resolveSelfSessionID()
+		if err != nil {
+			out.Error(err.Error(), ErrCodeNotFound)
+			os.Exit(2)
+		}
+		ref = id
+	}
+	inst, errMsg, errCode := ResolveSession(ref, instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		if errCode == ErrCodeNotFound {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+	return inst
+}
+
+// handleSessionSendTo implements `session send-to <from> <to> <message>`:
+// the message goes into <to>'s mailbox and, when <to> is ready for input,
+// straight into its pane.
+func handleSessionSendTo(profile string, args []string) {
+	fs := flag.NewFlagSet("session send-to", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode")
+	noNotify := fs.Bool("no-notify", false, "Only store the message; don't type it into the recipient's pane")
+	messageFile := fs.String("message-file", "", "Read the message from a file ('-' for stdin)")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck session send-to <from|self> <to> <message> [options]")
+		fmt.Println()
+		fmt.Println("Send a message from one session to another. It is stored in the")
+		fmt.Println("recipient's mailbox (read with `session mailbox`) and, when the recipient")
+		fmt.Println("is ready for input, typed into its pane with reply instructions.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck session send-to self parent \"tests pass, PR is #412\"")
+		fmt.Println("  agent-deck session send-to api-child api \"need the staging DB URL\" --no-notify")
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+	rest := fs.Args()
+	if len(rest) < 2 || (*messageFile == "" && len(rest) < 3) {
+		fs.Usage()
+		out.Error("from, to and message (or --message-file) are required", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	text, err := resolveMessageInput(strings.Join(rest[2:], " "), *messageFile, os.Stdin)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	storage, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	defer storage.Close()
+	from := resolveSessionOrSelf(rest[0], instances, out)
+	to := resolveSessionOrSelf(rest[1], instances, out)
+
+	m, err := session.PostMail(from, to, text, time.Now())
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	delivered := false
+	var notifyErr error
+	if !*noNotify && to.Exists() {
+		notifyErr = deliverToConductor(to, storage.GetDB(), session.FormatMailNotice(profile, *m), mailNotifyTimeout)
+		if notifyErr == nil {
+			delivered = true
+			_ = session.MarkMailRead(to.ID, []string{m.ID}, time.Now())
+		}
+	}
+
+	msg := fmt.Sprintf("Sent %s from %s to %s", m.ID, from.Title, to.Title)
+	switch {
+	case delivered:
+		msg += " (delivered to its pane)"
+	case notifyErr != nil:
+		msg += " (in its mailbox; not ready for input)"
+	default:
+		msg += " (in its mailbox)"
+	}
+	result := map[string]interface{}{
+		"success":   true,
+		"message":   m,
+		"delivered": delivered,
+	}
+	if notifyErr != nil {
+		result["notify_error"] = notifyErr.Error()
+	}
+	out.Success(msg, result)
+}
+
+// handleSessionMailbox implements `session mailbox [id|self]`: print the
+// unread messages and mark them read.
+func handleSessionMailbox(profile string, args []string) {
+	fs := flag.NewFlagSet("session mailbox", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode (print only the unread count)")
+	all := fs.Bool("all", false, "Show read messages too")
+	peek := fs.Bool("peek", false, "Don't mark the messages read")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck session mailbox [id|self] [options]")
+		fmt.Println()
+		fmt.Println("Print a session's unread messages (default: the current session) and")
+		fmt.Println("mark them read.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	if fs.NArg() > 1 {
+		fs.Usage()
+		out.Error("expected at most one session", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	storage, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	defer storage.Close()
+	inst := resolveSessionOrSelf(fs.Arg(0), instances, out)
+
+	if *quiet && !*jsonOutput {
+		fmt.Println(session.UnreadMailCount(inst.ID))
+		return
+	}
+	msgs, err := session.ReadMailbox(inst.ID, *all, !*peek, time.Now())
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to read mailbox: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if msgs == nil {
+		msgs = []session.MailMessage{}
+	}
+	out.Print(renderMailbox(inst.Title, msgs), map[string]interface{}{
+		"success":  true,
+		"session":  inst.Title,
+		"id":       inst.ID,
+		"messages": msgs,
+	})
+}
+
+// renderMailbox prints one block per message: sender, time and text.
+func renderMailbox(title string, msgs []session.MailMessage) string {
+	if len(msgs) == 0 {
+		return fmt.Sprintf("No new messages for %s.\n", title)
+	}
+	var b strings.Builder
+	for i, m := range msgs {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		state := ""
+		if !m.Unread() {
+			state = "  (read)"
+		}
+		fmt.Fprintf(&b, "%s  from %s  %s%s\n", m.ID, m.FromTitle, m.SentAt.Local().Format("2006-01-02 15:04"), state)
+		for _, line := range strings.Split(m.Text, "\n") {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+	return b.String()
+}
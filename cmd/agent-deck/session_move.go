@@ -25,16 +25,25 @@ func handleSessionMove(profile string, args []string) {
 	copyHistory := fs.Bool("copy", false, "Copy Claude session history instead of moving (preserves old path data)")
 	toProfile := fs.String("to-profile", "", "Migrate the session to another profile's DB (issue #928); incompatible with <new-path>")
 	force := fs.Bool("force", false, "With --to-profile: migrate running sessions (tmux process keeps running)")
+	profileAlias := fs.String("profile", "", "Alias for --to-profile")
+	live := fs.Bool("live", false, "With --to-profile: transfer a running session without restarting it (implies --force)")
+	toGroup := fs.String("to-group", "", "With --to-profile: place the session in this group of the target profile")
+	newTitle := fs.String("title", "", "With --to-profile: rename the session in the target profile")
+	renameTmux := fs.Bool("rename-tmux", false, "With --to-profile: give a running session's tmux session a fresh name from its (new) title")
 
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck session move <id|title> <new-path> [options]")
-		fmt.Println("       agent-deck session move <id|title> --to-profile <name> [--force]")
+		fmt.Println("       agent-deck session move <id|title> --to-profile <name> [--live] [--to-group <g>] [--title <t>] [--rename-tmux]")
 		fmt.Println()
 		fmt.Println("Move a session to a new project path (default form), migrating its Claude")
 		fmt.Println("conversation history from ~/.claude/projects/<old>/ to <new>/.")
 		fmt.Println()
 		fmt.Println("With --to-profile, instead migrate the session row to another profile's DB,")
 		fmt.Println("preserving all metadata and associated rows (cost_events, watcher_events).")
+		fmt.Println("With --live, a running session keeps its tmux process and Claude session ID;")
+		fmt.Println("its tmux AGENTDECK_PROFILE is re-pointed at the target, so hooks and CLI calls")
+		fmt.Println("made from the session follow the move. MCP servers it already started keep")
+		fmt.Println("the old profile until the session restarts.")
 		fmt.Println()
 		fmt.Println("Options:")
 		fs.PrintDefaults()
@@ -45,6 +54,8 @@ func handleSessionMove(profile string, args []string) {
 		fmt.Println("  agent-deck session move my-project /new/path --no-restart")
 		fmt.Println("  agent-deck session move my-project /new/path --copy")
 		fmt.Println("  agent-deck session move my-project --to-profile march")
+		fmt.Println("  agent-deck session move my-project --profile work --live --to-group review")
+		fmt.Println("  agent-deck session move my-project --to-profile work --live --title api --rename-tmux")
 	}
 
 	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
@@ -54,6 +65,10 @@ func handleSessionMove(profile string, args []string) {
 	quietMode := *quiet || *quietShort
 	out := NewCLIOutput(*jsonOutput, quietMode)
 
+	if *toProfile == "" {
+		*toProfile = *profileAlias
+	}
+
 	// Cross-profile migration mode (issue #928): different argument shape and
 	// completely different code path. Branch as early as possible so the
 	// existing path-move flow doesn't run.
@@ -83,10 +98,29 @@ func handleSessionMove(profile string, args []string) {
 			fs.Usage()
 			os.Exit(1)
 		}
-		handleSessionMoveToProfile(profile, *toProfile, fs.Arg(0), *force, out)
+		handleSessionMoveToProfile(profile, *toProfile, fs.Arg(0), moveToProfileOptions{
+			force:      *force || *live,
+			live:       *live,
+			group:      *toGroup,
+			title:      *newTitle,
+			renameTmux: *renameTmux,
+		}, out)
 		return
 	}
 
+	var profileOnly []string
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "live", "to-group", "title", "rename-tmux":
+			profileOnly = append(profileOnly, "--"+f.Name)
+		}
+	})
+	if len(profileOnly) > 0 {
+		out.Error(fmt.Sprintf("%s require --to-profile", profileOnly), ErrCodeInvalidOperation)
+		fs.Usage()
+		os.Exit(1)
+	}
+
 	if fs.NArg() < 2 {
 		out.Error("session move requires <id|title> and <new-path>", ErrCodeInvalidOperation)
 		fs.Usage()
@@ -176,12 +210,21 @@ func handleSessionMove(profile string, args []string) {
 	})
 }
 
+// moveToProfileOptions carries the --to-profile flags of `session move`.
+type moveToProfileOptions struct {
+	force      bool   // migrate running sessions
+	live       bool   // re-point a running session's tmux env at the target profile
+	group      string // target group in the destination profile ("" keeps the source group)
+	title      string // new title in the destination profile ("" keeps the title)
+	renameTmux bool   // rename the running tmux session after the (new) title
+}
+
 // handleSessionMoveToProfile implements `session move <id> --to-profile <name>`
 // (issue #928). The identifier is resolved against the source profile (and,
 // if missing there, the target — preserving idempotency on re-runs), then
 // the session row + cost_events + watcher_events are transferred to the
 // target profile's state.db via session.MigrateSessionsToProfile.
-func handleSessionMoveToProfile(sourceProfile, targetProfile, identifier string, force bool, out *CLIOutput) {
+func handleSessionMoveToProfile(sourceProfile, targetProfile, identifier string, opts moveToProfileOptions, out *CLIOutput) {
 	// Resolve identifier → ID using the source profile's instance list. We do
 	// this in the CLI layer (not in MigrateSessionsToProfile) because
 	// ResolveSession lives in cmd/agent-deck and supports title/path lookup
@@ -220,7 +263,7 @@ func handleSessionMoveToProfile(sourceProfile, targetProfile, identifier string,
 
 	result, err := session.MigrateSessionsToProfile(
 		sourceProfile, targetProfile, []string{inst.ID},
-		session.ProfileMigrateOptions{Force: force},
+		session.ProfileMigrateOptions{Force: opts.force},
 	)
 	if err != nil {
 		exitCode := ErrCodeInvalidOperation
@@ -237,10 +280,54 @@ func handleSessionMoveToProfile(sourceProfile, targetProfile, identifier string,
 		os.Exit(1)
 	}
 
-	out.Success(fmt.Sprintf("Migrated %q: profile %s → %s", inst.Title, sourceProfile, targetProfile), map[string]interface{}{
+	// Post-migration edits on the destination row: group placement and title.
+	title := inst.Title
+	group := inst.GroupPath
+	if opts.group != "" || opts.title != "" {
+		dstInst, err := applyMovedSessionEdits(targetProfile, inst.ID, opts.group, opts.title)
+		if err != nil {
+			out.Error(fmt.Sprintf("session migrated, but updating it in %s failed: %v", targetProfile, err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		title, group = dstInst.Title, dstInst.GroupPath
+	}
+
+	// Live transfer: the tmux process keeps running untouched. Re-point its
+	// AGENTDECK_PROFILE so hook-handler and in-session CLI calls resolve the
+	// new profile, and refresh the tmux-rendered title.
+	retargeted := false
+	if opts.live && inst.Exists() {
+		inst.Title = title
+		if err := inst.RetargetLiveProfile(targetProfile); err != nil {
+			out.Error(fmt.Sprintf("session migrated, but re-pointing the live tmux session failed: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		retargeted = true
+	}
+
+	// Tmux rename: done last so the live retarget above still addresses the
+	// session by its old name. The new name is stored on the target row.
+	tmuxName := ""
+	if opts.renameTmux {
+		name, err := renameMovedTmuxSession(targetProfile, inst.ID)
+		if err != nil {
+			out.Error(fmt.Sprintf("session migrated, but renaming its tmux session failed: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		if name == "" {
+			fmt.Fprintln(os.Stderr, "Note: session is not running; no tmux session to rename")
+		}
+		tmuxName = name
+	}
+
+	out.Success(fmt.Sprintf("Migrated %q: profile %s → %s", title, sourceProfile, targetProfile), map[string]interface{}{
 		"success":         true,
 		"id":              inst.ID,
-		"title":           inst.Title,
+		"title":           title,
+		"group":           group,
+		"live":            retargeted,
+		"tmux_session":    tmuxName,
+		"claude_session":  inst.ClaudeSessionID,
 		"from_profile":    sourceProfile,
 		"to_profile":      targetProfile,
 		"cost_events":     result.MovedCostEvents,
@@ -249,3 +336,68 @@ func handleSessionMoveToProfile(sourceProfile, targetProfile, identifier string,
 		"already_at_dest": len(result.SkippedIdempotent) > 0,
 	})
 }
+
+// applyMovedSessionEdits moves a freshly migrated session into group and/or
+// renames it inside the target profile, creating the group when missing.
+func applyMovedSessionEdits(targetProfile, id, group, title string) (*session.Instance, error) {
+	storage, instances, groups, err := loadSessionData(targetProfile)
+	if err != nil {
+		return nil, err
+	}
+	var inst *session.Instance
+	for _, candidate := range instances {
+		if candidate.ID == id {
+			inst = candidate
+			break
+		}
+	}
+	if inst == nil {
+		return nil, fmt.Errorf("session %s not found after migration", id)
+	}
+	if title != "" {
+		inst.Title = title
+	}
+
+	groupTree := session.NewGroupTreeWithGroups(instances, groups)
+	if group != "" {
+		if group == "root" {
+			group = session.DefaultGroupPath
+		}
+		if _, ok := groupTree.Groups[group]; !ok && group != session.DefaultGroupPath {
+			group = groupTree.CreateGroup(group).Path
+		}
+		groupTree.MoveSessionToGroup(inst, group)
+	}
+	if err := storage.SaveWithGroups(groupTree.GetAllInstances(), groupTree); err != nil {
+		return nil, err
+	}
+	return inst, nil
+}
+
+// renameMovedTmuxSession renames a migrated session's running tmux session
+// after its title in the target profile and saves the new name there.
+// Returns "" when the session has no running tmux session.
+func renameMovedTmuxSession(targetProfile, id string) (string, error) {
+	storage, instances, groups, err := loadSessionData(targetProfile)
+	if err != nil {
+		return "", err
+	}
+	var inst *session.Instance
+	for _, candidate := range instances {
+		if candidate.ID == id {
+			inst = candidate
+			break
+		}
+	}
+	if inst == nil {
+		return "", fmt.Errorf("session %s not found after migration", id)
+	}
+	name, err := inst.RenameTmuxSession()
+	if err != nil || name == "" {
+		return "", err
+	}
+	if err := saveSessionData(storage, instances, groups); err != nil {
+		return "", err
+	}
+	return name, nil
+}
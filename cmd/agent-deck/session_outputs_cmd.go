@@ -0,0 +1,180 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/clipboard"
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// handleSessionOutputs implements `agent-deck session outputs <id> [n]`: the
+// session's recent distinct agent responses, newest first. With n it prints
+// (or copies) that one response in full.
+func handleSessionOutputs(profile string, args []string) {
+	fs := flag.NewFlagSet("session outputs", flag.ExitOnError)
+	copyFlag := fs.Bool("copy", false, "Copy the chosen response (default: newest) to the clipboard")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Print only the chosen response (default: newest)")
+	quietShort := fs.Bool("q", false, "Print only the chosen response (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck session outputs <id|title> [n] [options]")
+		fmt.Println()
+		fmt.Printf("List the last %d distinct responses of a session, newest first.\n", session.OutputHistoryLimit)
+		fmt.Println("Pass n to print that response in full (1 = newest). The history is")
+		fmt.Println("recorded whenever the agent finishes a turn and outlives the session")
+		fmt.Println("(pass the session ID for removed sessions).")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck session outputs my-project")
+		fmt.Println("  agent-deck session outputs my-project 3")
+		fmt.Println("  agent-deck session outputs my-project 2 --copy")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	quietMode := *quiet || *quietShort
+	out := NewCLIOutput(*jsonOutput, quietMode)
+
+	identifier := fs.Arg(0)
+	if identifier == "" {
+		out.Error("session ID or title is required", ErrCodeInvalidOperation)
+		fs.Usage()
+		os.Exit(1)
+	}
+	index := 0
+	if raw := fs.Arg(1); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			out.Error(fmt.Sprintf("invalid response number %q (1 = newest)", raw), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		index = n
+	}
+
+	_, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load sessions: %v", err), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	id, title := identifier, ""
+	inst, errMsg, errCode := ResolveSession(identifier, instances)
+	switch {
+	case inst != nil:
+		id, title = inst.ID, inst.Title
+		// Pick up a turn that ended while no TUI was watching. Best effort:
+		// a stopped session or unreadable transcript still lists its history.
+		_, _ = inst.RecordOutputHistory()
+	case errCode == ErrCodeAmbiguous:
+		out.Error(errMsg, errCode)
+		os.Exit(1)
+	}
+
+	stored, err := session.ReadOutputHistory(id)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to read output history: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if len(stored) == 0 {
+		if inst == nil {
+			out.Error(errMsg, errCode)
+			os.Exit(2)
+		}
+		out.Error(fmt.Sprintf("no recorded outputs for session '%s'", inst.Title), ErrCodeNotFound)
+		os.Exit(2)
+	}
+	entries := make([]session.OutputEntry, len(stored))
+	for i, e := range stored {
+		entries[len(stored)-1-i] = e
+	}
+
+	if index > len(entries) {
+		out.Error(fmt.Sprintf("only %d response(s) recorded", len(entries)), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if index == 0 && (*copyFlag || quietMode) {
+		index = 1
+	}
+	if index == 0 {
+		items := make([]map[string]interface{}, 0, len(entries))
+		for i, e := range entries {
+			items = append(items, outputEntryJSON(i+1, e))
+		}
+		out.Print(renderOutputsList(title, id, entries), map[string]interface{}{
+			"success": true,
+			"id":      id,
+			"title":   title,
+			"outputs": items,
+		})
+		return
+	}
+
+	entry := entries[index-1]
+	if *copyFlag {
+		termInfo := tmux.GetTerminalInfo()
+		result, err := clipboard.Copy(entry.Content, termInfo.SupportsOSC52)
+		if err != nil {
+			out.Error(fmt.Sprintf("clipboard: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		out.Print(
+			fmt.Sprintf("Copied response %d (%d lines) to clipboard via %s\n", index, result.LineCount, result.Method),
+			map[string]interface{}{
+				"success":      true,
+				"id":           id,
+				"index":        index,
+				"lines_copied": result.LineCount,
+				"method":       result.Method,
+			},
+		)
+		return
+	}
+	if quietMode {
+		fmt.Println(entry.Content)
+		return
+	}
+	data := outputEntryJSON(index, entry)
+	data["success"] = true
+	data["id"] = id
+	out.Print(entry.Content+"\n", data)
+}
+
+func outputEntryJSON(index int, e session.OutputEntry) map[string]interface{} {
+	return map[string]interface{}{
+		"index":     index,
+		"timestamp": e.Timestamp,
+		"tool":      e.Tool,
+		"content":   e.Content,
+	}
+}
+
+// renderOutputsList prints one numbered row per response: age, size and the
+// first line.
+func renderOutputsList(title, id string, entries []session.OutputEntry) string {
+	var b strings.Builder
+	name := title
+	if name == "" {
+		name = id
+	}
+	fmt.Fprintf(&b, "Outputs of %s (newest first)\n\n", name)
+	for i, e := range entries {
+		first, _, _ := strings.Cut(e.Content, "\n")
+		first = strings.TrimSpace(first)
+		if r := []rune(first); len(r) > 72 {
+			first = string(r[:69]) + "..."
+		}
+		fmt.Fprintf(&b, "%3d  %s  %4d lines  %s\n", i+1, e.Timestamp.Local().Format(time.DateTime), strings.Count(e.Content, "\n")+1, first)
+	}
+	fmt.Fprintf(&b, "\nPrint one with: agent-deck session outputs %s <n>\n", id)
+	return b.String()
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+func TestRenderOutputsList(t *testing.T) {
+	ts := time.Date(2026, 10, 1, 12, 0, 0, 0, time.Local)
+	got := renderOutputsList("api", "abc123", []session.OutputEntry{
+		{Timestamp: ts, Content: "Done.\nAll tests pass."},
+		{Timestamp: ts.Add(-time.Hour), Content: strings.Repeat("é", 80)},
+	})
+	for _, want := range []string{
+		"Outputs of api (newest first)",
+		"  1  2026-10-01 12:00:00     2 lines  Done.",
+		"  2  2026-10-01 11:00:00     1 lines  " + strings.Repeat("é", 69) + "...",
+		"agent-deck session outputs abc123 <n>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleSessionPin installs (or, with unpin, removes) the login service that
+// starts a session after a reboot.
+func handleSessionPin(profile string, args []string, unpin bool) {
+	name := "pin"
+	if unpin {
+		name = "unpin"
+	}
+	fs := flag.NewFlagSet("session "+name, flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+
+	fs.Usage = func() {
+		fmt.Printf("Usage: agent-deck session %s <id|title> [options]\n", name)
+		fmt.Println()
+		if unpin {
+			fmt.Println("Remove a pinned session's login service. The session keeps running.")
+		} else {
+			fmt.Println("Start the session at login: installs a launchd agent (macOS) or systemd")
+			fmt.Println("user service (Linux) that runs 'agent-deck session start' for it, so it")
+			fmt.Println("survives reboots. The session is not started now.")
+		}
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	identifier := fs.Arg(0)
+	quietMode := *quiet || *quietShort
+	out := NewCLIOutput(*jsonOutput, quietMode)
+
+	if identifier == "" {
+		out.Error("session <id|title> required", ErrCodeInvalidOperation)
+		if !*jsonOutput {
+			fs.Usage()
+		}
+		os.Exit(1)
+	}
+
+	_, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+
+	inst, errMsg, errCode := ResolveSession(identifier, instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		if errCode == ErrCodeNotFound {
+			os.Exit(2)
+		}
+		os.Exit(1)
+		return // unreachable, satisfies staticcheck SA5011
+	}
+
+	if unpin {
+		if session.PinnedServicePath(inst.ID) == "" {
+			out.Error(fmt.Sprintf("session '%s' is not pinned", inst.Title), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		if err := session.UnpinSession(inst.ID); err != nil {
+			out.Error(fmt.Sprintf("failed to unpin session: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		out.Success(fmt.Sprintf("Unpinned session: %s", inst.Title), map[string]interface{}{
+			"success": true,
+			"id":      inst.ID,
+			"title":   inst.Title,
+			"pinned":  false,
+		})
+		return
+	}
+
+	if inst.IsArchived() {
+		out.Error(fmt.Sprintf("session '%s' is archived; unarchive it before pinning", inst.Title), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	path, err := session.PinSession(inst, session.GetEffectiveProfile(profile))
+	if err != nil {
+		msg := fmt.Sprintf("failed to pin session: %v", err)
+		if path != "" {
+			msg = fmt.Sprintf("%v (unit written to %s)", err, path)
+		}
+		out.Error(msg, ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Pinned session: %s (starts at login via %s)", inst.Title, path), map[string]interface{}{
+		"success": true,
+		"id":      inst.ID,
+		"title":   inst.Title,
+		"pinned":  true,
+		"service": path,
+	})
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleSessionRelocate implements `agent-deck session relocate <id> [new-path]`:
+// it repoints sessions at a project directory that was moved or renamed.
+// Unlike `session move`, the files already moved; only agent-deck's
+// references (sessions, worktrees, group defaults, Claude history) follow.
+func handleSessionRelocate(profile string, args []string) {
+	fs := flag.NewFlagSet("session relocate", flag.ExitOnError)
+	var searchRoots stringSliceFlag
+	fs.Var(&searchRoots, "search-root", "Directory to search for the moved project (repeatable; default: near the old path and $HOME)")
+	yes := fs.Bool("yes", false, "Accept the only candidate without prompting")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck session relocate <id|title> [new-path] [options]")
+		fmt.Println()
+		fmt.Println("Point a session whose project directory was moved or renamed at its new")
+		fmt.Println("location. Every session under the moved directory follows (siblings too")
+		fmt.Println("when their parent folder moved), worktrees are repaired with `git worktree")
+		fmt.Println("repair`, group default paths are updated and Claude history is migrated.")
+		fmt.Println()
+		fmt.Println("Without new-path, folders with the same name (or the same git remote) are")
+		fmt.Println("searched for and offered for confirmation.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck session relocate my-project ~/code/renamed-project")
+		fmt.Println("  agent-deck session relocate my-project")
+		fmt.Println("  agent-deck session relocate my-project --search-root ~/archive --yes")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		out.Error("session relocate requires <id|title> and an optional <new-path>", ErrCodeInvalidOperation)
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	storage, instances, groups, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	inst, errMsg, errCode := ResolveSession(fs.Arg(0), instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		if errCode == ErrCodeNotFound {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+
+	newPath := fs.Arg(1)
+	if newPath == "" {
+		if !inst.ProjectPathMissing() {
+			out.Error(fmt.Sprintf("%s still exists; pass the new path to relocate anyway", inst.ProjectPath), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		candidates := session.FindRelocationCandidates(inst, searchRoots)
+		newPath = pickRelocationCandidate(inst, candidates, *yes, *jsonOutput, out)
+		if newPath == "" {
+			os.Exit(1)
+		}
+	}
+
+	rel, err := session.RelocateSessions(instances, inst, newPath)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	groupTree := session.NewGroupTreeWithGroups(instances, groups)
+	if cfg, cfgErr := session.LoadUserConfig(); cfgErr == nil && cfg != nil {
+		groupTree.DefaultMaxConcurrent = cfg.GroupDefaults.MaxConcurrent
+	}
+	groupsUpdated := groupTree.RelocateDefaultPaths(rel.OldPath, rel.NewPath)
+	if err := storage.SaveWithGroups(instances, groupTree); err != nil {
+		out.Error(fmt.Sprintf("failed to save: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	for _, w := range rel.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Relocated %s → %s\n", rel.OldPath, rel.NewPath)
+	for _, s := range rel.Sessions {
+		fmt.Fprintf(&b, "  %s %s  %s\n", successSymbol, s.Title, s.ProjectPath)
+	}
+	if groupsUpdated > 0 {
+		fmt.Fprintf(&b, "  %s %d group default path(s) updated\n", bulletSymbol, groupsUpdated)
+	}
+	out.Print(b.String(), map[string]interface{}{
+		"success":        true,
+		"old_path":       rel.OldPath,
+		"new_path":       rel.NewPath,
+		"sessions":       instanceListJSON(rel.Sessions),
+		"groups_updated": groupsUpdated,
+		"warnings":       nonNilStrings(rel.Warnings),
+	})
+}
+
+// pickRelocationCandidate chooses the new path for a missing project: the
+// only candidate with --yes, the user's pick on a terminal, or nothing (after
+// listing the candidates) otherwise.
+func pickRelocationCandidate(inst *session.Instance, candidates []session.RelocationCandidate, yes, jsonOutput bool, out *CLIOutput) string {
+	if len(candidates) == 0 {
+		out.Error(fmt.Sprintf("%s is missing and no folder named %q was found; pass the new path", inst.ProjectPath, filepath.Base(inst.ProjectPath)), ErrCodeNotFound)
+		return ""
+	}
+	if yes && len(candidates) == 1 {
+		return candidates[0].Path
+	}
+	if jsonOutput || !stdinStdoutIsTerminal() {
+		if jsonOutput {
+			out.Print("", map[string]interface{}{
+				"success":      false,
+				"missing_path": inst.ProjectPath,
+				"candidates":   candidates,
+			})
+		} else {
+			fmt.Printf("%s is missing. Possible new locations:\n", inst.ProjectPath)
+			for _, c := range candidates {
+				fmt.Printf("  %s  (%s)\n", c.Path, c.Reason)
+			}
+			fmt.Println("Re-run with the path: agent-deck session relocate <id> <new-path>")
+		}
+		return ""
+	}
+
+	drainStdin()
+	fmt.Printf("%s is missing. Did %q move?\n", inst.ProjectPath, inst.Title)
+	for i, c := range candidates {
+		fmt.Printf("  %d) %s  (%s)\n", i+1, c.Path, c.Reason)
+	}
+	if len(candidates) == 1 {
+		fmt.Print("Relocate there? [Y/n] ")
+	} else {
+		fmt.Printf("Pick one [1-%d, n to cancel] ", len(candidates))
+	}
+	response, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	response = strings.TrimSpace(response)
+	if len(candidates) == 1 && (response == "" || strings.EqualFold(response, "y")) {
+		return candidates[0].Path
+	}
+	if n, err := strconv.Atoi(response); err == nil && n >= 1 && n <= len(candidates) {
+		return candidates[n-1].Path
+	}
+	fmt.Println("Cancelled.")
+	return ""
+}
@@ -116,6 +116,8 @@ func handleSessionRemove(profile string, args []string) {
 	// matching block in handleRemove for rationale.
 	_, _ = session.SweepInboxesForChildSession(inst.ID)
 	_, _ = session.RemoveNotifyStateRecord(inst.ID)
+	// A pinned session's login service would only fail to find it.
+	_ = session.UnpinSession(inst.ID)
 
 	out.Success(fmt.Sprintf("Removed session: %s", inst.Title), map[string]interface{}{
 		"success": true,
@@ -127,7 +129,11 @@ func handleSessionRemove(profile string, args []string) {
 // isRemovableStatus returns true for states where a session can be removed
 // from the registry without --force.
 func isRemovableStatus(s session.Status) bool {
-	return s == session.StatusStopped || s == session.StatusError
+	switch s {
+	case session.StatusStopped, session.StatusError, session.StatusExited, session.StatusCrashed:
+		return true
+	}
+	return false
 }
 
 // removedSessionRow is the {id,title} payload emitted for each removed session.
@@ -239,6 +245,7 @@ func bulkRemoveSessions(
 		// Best-effort transition-notifier cleanup (issue #910).
 		_, _ = session.SweepInboxesForChildSession(id)
 		_, _ = session.RemoveNotifyStateRecord(id)
+		_ = session.UnpinSession(id)
 	}
 	return removed
 }
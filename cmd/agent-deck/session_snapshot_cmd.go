@@ -0,0 +1,242 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleSessionSnapshot implements `agent-deck session snapshot <id>`: it
+// writes a session's metadata, tool options, MCP config, Claude transcript
+// and git ref to a tarball that `session restore` recreates elsewhere.
+func handleSessionSnapshot(profile string, args []string) {
+	fs := flag.NewFlagSet("session snapshot", flag.ExitOnError)
+	output := fs.String("output", "", "Output file (default: <title>-<date>.agentdeck.tar.gz; - for stdout)")
+	outputShort := fs.String("o", "", "Output file (short)")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck session snapshot <id|title> [options]")
+		fmt.Println()
+		fmt.Println("Export a session to a tarball: its metadata and tool options, the")
+		fmt.Println("project's .mcp.json, the Claude transcript and the git branch and commit.")
+		fmt.Println("Restore it on another machine with `agent-deck session restore <file>`.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck session snapshot my-project")
+		fmt.Println("  agent-deck session snapshot my-project -o ~/Dropbox/my-project.tar.gz")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+	if fs.NArg() != 1 {
+		out.Error("session snapshot requires <id|title>", ErrCodeInvalidOperation)
+		fs.Usage()
+		os.Exit(1)
+	}
+	path := *output
+	if path == "" {
+		path = *outputShort
+	}
+
+	storage, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	inst, errMsg, errCode := ResolveSession(fs.Arg(0), instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		if errCode == ErrCodeNotFound {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+
+	group, _ := storage.GetDB().LoadGroup(inst.GroupPath)
+	snap, err := session.NewSnapshot(inst, group)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to snapshot: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	if path == "-" {
+		if err := snap.Write(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if path == "" {
+		path = fmt.Sprintf("%s-%s.agentdeck.tar.gz", snapshotFileStem(inst.Title), time.Now().Format("20060102-150405"))
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if err := snap.Write(f); err != nil {
+		f.Close()
+		out.Error(fmt.Sprintf("failed to write snapshot: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if err := f.Close(); err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s Snapshot of %s written to %s\n", successSymbol, inst.Title, path)
+	if snap.Transcript != nil {
+		fmt.Fprintf(&b, "  %s Claude transcript (%d bytes)\n", bulletSymbol, len(snap.Transcript))
+	}
+	if snap.MCPConfig != nil {
+		fmt.Fprintf(&b, "  %s .mcp.json\n", bulletSymbol)
+	}
+	if g := snap.Manifest.Git; g != nil && g.Commit != "" {
+		fmt.Fprintf(&b, "  %s git %s @ %s\n", bulletSymbol, g.Branch, truncate(g.Commit, 12))
+	}
+	out.Print(b.String(), map[string]interface{}{
+		"success":    true,
+		"id":         inst.ID,
+		"title":      inst.Title,
+		"file":       path,
+		"transcript": snap.Transcript != nil,
+		"mcp_config": snap.MCPConfig != nil,
+		"git":        snap.Manifest.Git,
+	})
+}
+
+// snapshotFileStem turns a session title into a file-name-safe stem.
+func snapshotFileStem(title string) string {
+	stem := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '-'
+	}, title)
+	stem = strings.Trim(stem, "-.")
+	if stem == "" {
+		return "session"
+	}
+	return stem
+}
+
+// handleSessionRestore implements `agent-deck session restore <file>`.
+func handleSessionRestore(profile string, args []string) {
+	fs := flag.NewFlagSet("session restore", flag.ExitOnError)
+	path := fs.String("path", "", "Project path on this machine (default: the snapshot's path, with its $HOME mapped to yours)")
+	group := fs.String("group", "", "Restore into this group (default: the snapshot's group)")
+	groupShort := fs.String("g", "", "Restore into this group (short)")
+	title := fs.String("title", "", "Session title (default: the snapshot's title)")
+	titleShort := fs.String("t", "", "Session title (short)")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck session restore <file> [options]")
+		fmt.Println()
+		fmt.Println("Recreate a session from `agent-deck session snapshot`. Paths under the")
+		fmt.Println("snapshot machine's home directory are mapped to yours; a missing worktree")
+		fmt.Println("is recreated from its branch. The Claude transcript and .mcp.json are")
+		fmt.Println("restored unless a local copy exists. The session is restored stopped;")
+		fmt.Println("start it to resume the conversation.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck session restore my-project-20260101-120000.agentdeck.tar.gz")
+		fmt.Println("  agent-deck session restore snap.tar.gz --path ~/src/my-project -g work")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+	if fs.NArg() != 1 {
+		out.Error("session restore requires <file>", ErrCodeInvalidOperation)
+		fs.Usage()
+		os.Exit(1)
+	}
+	opts := session.SnapshotRestoreOptions{ProjectPath: *path, Group: *group, Title: *title}
+	if opts.Group == "" {
+		opts.Group = *groupShort
+	}
+	if opts.Title == "" {
+		opts.Title = *titleShort
+	}
+	if opts.ProjectPath != "" {
+		if abs, err := filepath.Abs(session.ExpandPath(opts.ProjectPath)); err == nil {
+			opts.ProjectPath = abs
+		}
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	snap, err := session.ReadSnapshot(f)
+	f.Close()
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	storage, err := session.NewStorageWithProfile(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	defer storage.Close()
+	res, err := session.RestoreSnapshot(storage.GetDB(), snap, opts)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	for _, w := range res.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+	row := res.Row
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s Restored %s (%s) in %s\n", successSymbol, row.Title, row.ID, row.GroupPath)
+	fmt.Fprintf(&b, "  %s path: %s\n", bulletSymbol, row.ProjectPath)
+	if res.WorktreeCreated {
+		fmt.Fprintf(&b, "  %s worktree recreated on %s\n", bulletSymbol, row.WorktreeBranch)
+	}
+	if res.TranscriptWritten != "" {
+		fmt.Fprintf(&b, "  %s transcript: %s\n", bulletSymbol, res.TranscriptWritten)
+	}
+	if res.MCPConfigWritten != "" {
+		fmt.Fprintf(&b, "  %s wrote %s\n", bulletSymbol, res.MCPConfigWritten)
+	}
+	fmt.Fprintf(&b, "Start it with: agent-deck session start %s\n", row.ID)
+	out.Print(b.String(), map[string]interface{}{
+		"success":          true,
+		"id":               row.ID,
+		"new_id":           res.NewID,
+		"title":            row.Title,
+		"group":            row.GroupPath,
+		"path":             row.ProjectPath,
+		"worktree_created": res.WorktreeCreated,
+		"transcript":       res.TranscriptWritten,
+		"mcp_config":       res.MCPConfigWritten,
+		"warnings":         nonNilStrings(res.Warnings),
+	})
+}
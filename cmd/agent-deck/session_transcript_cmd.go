@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleSessionTranscript prints the recorded transcript of a session: the
+// pane output the TUI's transcript recorder appended while it ran, plus the
+// final capture taken when it was killed. Works for removed sessions too when
+// given the raw session ID, since transcripts outlive the registry entry.
+func handleSessionTranscript(profile string, args []string) {
+	fs := flag.NewFlagSet("session transcript", flag.ExitOnError)
+	since := fs.Duration("since", 0, "Only show output recorded within this duration (e.g. 30m, 1h)")
+	format := fs.String("format", "text", "Output format: text or json")
+	jsonOutput := fs.Bool("json", false, "Output as JSON (same as --format json)")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck session transcript <id|title> [options]")
+		fmt.Println()
+		fmt.Println("Print the recorded pane output of a session, including sessions that")
+		fmt.Println("have since been stopped or removed (pass the session ID for those).")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck session transcript my-project")
+		fmt.Println("  agent-deck session transcript my-project --since 1h")
+		fmt.Println("  agent-deck session transcript 3f2a9c1e --format json | jq '.entries[-1]'")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	switch *format {
+	case "text":
+	case "json":
+		*jsonOutput = true
+	default:
+		fmt.Fprintf(os.Stderr, "Error: --format must be text or json, got %q\n", *format)
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+
+	identifier := fs.Arg(0)
+	if identifier == "" {
+		out.Error("session ID or title is required", ErrCodeInvalidOperation)
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	_, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load sessions: %v", err), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	id, title := identifier, ""
+	inst, errMsg, errCode := ResolveSession(identifier, instances)
+	if inst != nil {
+		id, title = inst.ID, inst.Title
+	} else if errCode == ErrCodeAmbiguous {
+		out.Error(errMsg, errCode)
+		os.Exit(1)
+	}
+
+	var cutoff time.Time
+	if *since > 0 {
+		cutoff = time.Now().Add(-*since)
+	}
+	entries, err := session.ReadTranscript(id, cutoff)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			if inst == nil {
+				out.Error(errMsg, errCode)
+				os.Exit(2)
+			}
+			out.Error(fmt.Sprintf("no transcript recorded for session '%s'", inst.Title), ErrCodeNotFound)
+			os.Exit(2)
+		}
+		out.Error(fmt.Sprintf("failed to read transcript: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	if entries == nil {
+		entries = []session.TranscriptEntry{}
+	}
+	out.Print(renderTranscriptText(entries), map[string]interface{}{
+		"success": true,
+		"id":      id,
+		"title":   title,
+		"entries": entries,
+	})
+}
+
+// renderTranscriptText joins the recorded lines of entries, marking where the
+// session was killed.
+func renderTranscriptText(entries []session.TranscriptEntry) string {
+	var b strings.Builder
+	for _, e := range entries {
+		for _, line := range e.Lines {
+			b.WriteString(line)
+			b.WriteByte('\n')
+		}
+		if e.Event == session.TranscriptEventKill {
+			fmt.Fprintf(&b, "--- session killed at %s ---\n", e.Timestamp.Local().Format(time.DateTime))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+func TestRenderTranscriptText_MarksKill(t *testing.T) {
+	ts := time.Date(2026, 10, 1, 12, 0, 0, 0, time.Local)
+	got := renderTranscriptText([]session.TranscriptEntry{
+		{Timestamp: ts, Lines: []string{"$ make", "ok"}},
+		{Timestamp: ts, Event: session.TranscriptEventKill},
+	})
+	want := "$ make\nok\n--- session killed at 2026-10-01 12:00:00 ---\n"
+	if got != want {
+		t.Fatalf("renderTranscriptText = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// `session watch` follows a pane without attaching a tmux client, the same
+// way the web pane stream (/ws/pane/<id>) does: one control-mode pipe
+// signals %output, the pane is re-captured through that pipe, and only the
+// lines that changed are written. Over mosh or a slow SSH link this costs a
+// few bytes per update and never resizes or steals the session's window.
+
+const (
+	// watchDebounce coalesces bursts of %output events into one capture.
+	watchDebounce = 100 * time.Millisecond
+	// watchPollInterval re-captures even without output events.
+	watchPollInterval = 2 * time.Second
+)
+
+func handleSessionWatch(profile string, args []string) {
+	fs := flag.NewFlagSet("session watch", flag.ExitOnError)
+	tail := fs.Bool("tail", false, "Append new lines instead of redrawing the pane (default when stdout is not a terminal)")
+	plain := fs.Bool("plain", false, "Strip colors (default when stdout is not a terminal)")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck session watch [id|title] [options]")
+		fmt.Println()
+		fmt.Println("Follow a session's pane in this terminal without attaching to tmux.")
+		fmt.Println("Only changed lines are redrawn, so it stays cheap over mosh or slow")
+		fmt.Println("SSH. Input is never sent; press Ctrl+C to stop.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck session watch my-project")
+		fmt.Println("  agent-deck session watch my-project --tail | tee pane.log")
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(false, false)
+
+	_, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load sessions: %v", err), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	inst, errMsg, errCode := ResolveSessionOrCurrent(fs.Arg(0), instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		if errCode == ErrCodeNotFound {
+			os.Exit(2)
+		}
+		os.Exit(1)
+		return // unreachable, satisfies staticcheck SA5011
+	}
+	tmuxSess := inst.GetTmuxSession()
+	if tmuxSess == nil || !inst.Exists() {
+		out.Error(fmt.Sprintf("session '%s' is not running", inst.Title), ErrCodeInvalidOperation)
+		os.Exit(1)
+		return
+	}
+
+	pipe, err := tmux.NewControlPipe(tmuxSess.Name, inst.TmuxSocketName)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to connect to tmux session: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	defer pipe.Close()
+
+	isTTY := term.IsTerminal(int(os.Stdout.Fd()))
+	live := isTTY && !*tail
+	stripColors := *plain || !isTTY
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	header := fmt.Sprintf("watching %s (%s) — Ctrl+C to stop", inst.Title, inst.Tool)
+	if live {
+		// Hide the cursor and turn off autowrap so a long line can't push
+		// the rows below it out of place.
+		fmt.Print("\x1b[?25l\x1b[?7l\x1b[H\x1b[2J")
+		defer fmt.Print("\x1b[0m\x1b[?7h\x1b[?25h\n")
+	} else if isTTY {
+		fmt.Println(header)
+	}
+
+	var last []string
+	capture := func() {
+		content, err := pipe.CapturePaneVia()
+		if err != nil {
+			return // transient; the next event or poll retries
+		}
+		if stripColors {
+			content = tmux.StripANSI(content)
+		}
+		lines := splitWatchLines(content)
+		if live {
+			if _, h, err := term.GetSize(int(os.Stdout.Fd())); err == nil && h > 1 && len(lines) > h-1 {
+				lines = lines[len(lines)-(h-1):]
+			}
+			if last == nil {
+				fmt.Print("\x1b[1;1H\x1b[7m" + header + "\x1b[0m\x1b[K")
+			}
+			fmt.Print(renderWatchFrame(last, lines, 2))
+		} else {
+			for _, line := range appendedWatchLines(last, lines) {
+				fmt.Println(line)
+			}
+		}
+		last = lines
+	}
+
+	capture()
+	poll := time.NewTicker(watchPollInterval)
+	defer poll.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-pipe.Done():
+			if live {
+				fmt.Print("\x1b[0m\x1b[?7h\x1b[?25h\n")
+			}
+			fmt.Fprintf(os.Stderr, "session '%s' ended\n", inst.Title)
+			os.Exit(0)
+		case <-pipe.OutputEvents():
+			select {
+			case <-time.After(watchDebounce):
+			case <-ctx.Done():
+				return
+			}
+			// Events during the debounce are covered by this capture.
+			select {
+			case <-pipe.OutputEvents():
+			default:
+			}
+		case <-poll.C:
+		}
+		capture()
+	}
+}
+
+// splitWatchLines splits a capture into lines without the trailing blank
+// rows of an unfilled pane.
+func splitWatchLines(content string) []string {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	for len(lines) > 0 && strings.TrimSpace(tmux.StripANSI(lines[len(lines)-1])) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// renderWatchFrame returns the escape sequences that turn a terminal showing
+// prev (from row top down) into next: changed rows are rewritten and rows
+// past the end of next are cleared. A nil prev redraws every row.
+func renderWatchFrame(prev, next []string, top int) string {
+	var b strings.Builder
+	for i, line := range next {
+		if prev != nil && i < len(prev) && prev[i] == line {
+			continue
+		}
+		fmt.Fprintf(&b, "\x1b[%d;1H%s\x1b[0m\x1b[K", top+i, line)
+	}
+	for i := len(next); i < len(prev); i++ {
+		fmt.Fprintf(&b, "\x1b[%d;1H\x1b[K", top+i)
+	}
+	return b.String()
+}
+
+// appendedWatchLines returns the lines of next that tail mode has not
+// printed yet. A pane that scrolled keeps a suffix of prev as its prefix; a
+// pane that changed in place keeps a common prefix. Whichever explains more
+// of next wins, and only the rest is new.
+func appendedWatchLines(prev, next []string) []string {
+	if prev == nil {
+		return next
+	}
+	kept := 0
+	for k := min(len(prev), len(next)); k > 0; k-- {
+		if slices.Equal(prev[len(prev)-k:], next[:k]) {
+			kept = k
+			break
+		}
+	}
+	prefix := 0
+	for prefix < len(prev) && prefix < len(next) && prev[prefix] == next[prefix] {
+		prefix++
+	}
+	return next[max(kept, prefix):]
+}
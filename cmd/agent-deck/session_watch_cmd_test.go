@@ -0,0 +1,42 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAppendedWatchLines(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		prev, next []string
+		want       []string
+	}{
+		{"first capture", nil, []string{"a", "b"}, []string{"a", "b"}},
+		{"grew", []string{"a", "b"}, []string{"a", "b", "c"}, []string{"c"}},
+		{"scrolled", []string{"a", "b", "c"}, []string{"b", "c", "d", "e"}, []string{"d", "e"}},
+		{"last line redrawn", []string{"a", "b", "50%"}, []string{"a", "b", "75%"}, []string{"75%"}},
+		{"unchanged", []string{"a", "b"}, []string{"a", "b"}, []string{}},
+		{"cleared", []string{"a", "b"}, []string{"x"}, []string{"x"}},
+	} {
+		if got := appendedWatchLines(tc.prev, tc.next); !slices.Equal(got, tc.want) {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestRenderWatchFrame(t *testing.T) {
+	if got := renderWatchFrame([]string{"a", "b"}, []string{"a", "b"}, 2); got != "" {
+		t.Errorf("unchanged frame = %q, want nothing", got)
+	}
+	got := renderWatchFrame([]string{"a", "b", "c"}, []string{"a", "B"}, 2)
+	want := "\x1b[3;1HB\x1b[0m\x1b[K" + "\x1b[4;1H\x1b[K"
+	if got != want {
+		t.Errorf("frame = %q, want %q", got, want)
+	}
+	if got := renderWatchFrame(nil, []string{"a"}, 2); got != "\x1b[2;1Ha\x1b[0m\x1b[K" {
+		t.Errorf("first frame = %q", got)
+	}
+	if got := splitWatchLines("a\n\x1b[0m  \n\n"); !slices.Equal(got, []string{"a"}) {
+		t.Errorf("splitWatchLines = %q", got)
+	}
+}
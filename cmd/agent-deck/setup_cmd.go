@@ -0,0 +1,379 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// setupStep is one item of the `agent-deck setup` checklist. check reports
+// whether the step is already satisfied (with a short detail for the
+// checklist); apply performs it. Steps are idempotent: re-running setup on a
+// configured machine is a no-op.
+type setupStep struct {
+	key   string
+	title string
+	check func() (done bool, detail string)
+	apply func(w io.Writer) error
+}
+
+// setupOptions controls runSetup.
+type setupOptions struct {
+	yes       bool            // accept every pending step without prompting
+	checkOnly bool            // print the checklist and stop
+	skip      map[string]bool // step keys to leave untouched
+}
+
+// handleSetup condenses first-run onboarding (tmux, shell completion, hooks,
+// notifier daemon, starter groups) into one guided checklist.
+func handleSetup(profile string, args []string) {
+	fs := flag.NewFlagSet("setup", flag.ExitOnError)
+	yes := fs.Bool("yes", false, "Apply every pending step without prompting")
+	yesShort := fs.Bool("y", false, "Apply every pending step without prompting (short)")
+	check := fs.Bool("check", false, "Only print the checklist; change nothing")
+	skip := fs.String("skip", "", "Comma-separated step keys to skip (tmux,completion,claude-hooks,codex-hooks,gemini-hooks,notifier,groups)")
+	groups := fs.String("groups", "work,personal", "Starter groups created in an empty profile")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck setup [options]")
+		fmt.Println()
+		fmt.Println("Guided bootstrap for a new machine. Checks each item below and offers")
+		fmt.Println("to fix what is missing:")
+		fmt.Println("  tmux          Install tmux (brew/apt/dnf/pacman)")
+		fmt.Println("  completion    Install shell completion for $SHELL")
+		fmt.Println("  claude-hooks  Install Claude Code status hooks")
+		fmt.Println("  codex-hooks   Install Codex notify hook (when codex is on PATH)")
+		fmt.Println("  gemini-hooks  Install Gemini CLI status hooks (when gemini is on PATH)")
+		fmt.Println("  notifier      Register the transition notifier daemon")
+		fmt.Println("  groups        Create the profile and starter groups")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck setup")
+		fmt.Println("  agent-deck setup --check")
+		fmt.Println("  agent-deck -p work setup --yes --skip notifier --groups backend,frontend")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	opts := setupOptions{
+		yes:       *yes || *yesShort,
+		checkOnly: *check,
+		skip:      make(map[string]bool),
+	}
+	for _, k := range strings.Split(*skip, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			opts.skip[k] = true
+		}
+	}
+
+	steps := defaultSetupSteps(profile, splitNonEmpty(*groups))
+	if failed := runSetup(steps, os.Stdin, os.Stdout, opts); failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// runSetup prints the checklist and walks the pending steps. Returns the
+// number of steps that failed to apply.
+func runSetup(steps []setupStep, in io.Reader, out io.Writer, opts setupOptions) int {
+	fmt.Fprintln(out, "Agent Deck setup")
+	fmt.Fprintln(out)
+
+	pending := make([]setupStep, 0, len(steps))
+	for _, st := range steps {
+		done, detail := st.check()
+		mark := "○"
+		switch {
+		case done:
+			mark = "✓"
+		case opts.skip[st.key]:
+			mark = "-"
+		default:
+			pending = append(pending, st)
+		}
+		line := fmt.Sprintf("  %s %-28s", mark, st.title)
+		if detail != "" {
+			line += " " + detail
+		}
+		fmt.Fprintln(out, strings.TrimRight(line, " "))
+	}
+	fmt.Fprintln(out)
+
+	if len(pending) == 0 {
+		fmt.Fprintln(out, "Everything is set up.")
+		return 0
+	}
+	if opts.checkOnly {
+		fmt.Fprintf(out, "%d step(s) pending. Run 'agent-deck setup' to apply.\n", len(pending))
+		return 0
+	}
+
+	reader := bufio.NewReader(in)
+	failed := 0
+	for _, st := range pending {
+		if !opts.yes {
+			fmt.Fprintf(out, "%s? [Y/n]: ", st.title)
+			answer, _ := reader.ReadString('\n')
+			answer = strings.ToLower(strings.TrimSpace(answer))
+			if answer != "" && answer != "y" && answer != "yes" {
+				fmt.Fprintln(out, "  skipped")
+				continue
+			}
+		}
+		if err := st.apply(out); err != nil {
+			fmt.Fprintf(out, "  ✗ %s: %v\n", st.title, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(out, "  ✓ %s\n", st.title)
+	}
+
+	fmt.Fprintln(out)
+	if failed > 0 {
+		fmt.Fprintf(out, "%d step(s) failed. Re-run 'agent-deck setup' after fixing the errors above.\n", failed)
+	} else {
+		fmt.Fprintln(out, "Setup complete. Run 'agent-deck' to open the deck.")
+	}
+	return failed
+}
+
+// defaultSetupSteps builds the production checklist for profile.
+func defaultSetupSteps(profile string, groups []string) []setupStep {
+	steps := []setupStep{
+		{
+			key:   "tmux",
+			title: "Install tmux",
+			check: func() (bool, string) {
+				if path, err := exec.LookPath("tmux"); err == nil {
+					return true, path
+				}
+				return false, "not found on PATH"
+			},
+			apply: func(w io.Writer) error {
+				argv := tmuxInstallCommand(runtime.GOOS, exec.LookPath)
+				if argv == nil {
+					return errors.New("no supported package manager found; see https://github.com/tmux/tmux/wiki/Installing")
+				}
+				fmt.Fprintf(w, "  running: %s\n", strings.Join(argv, " "))
+				cmd := exec.Command(argv[0], argv[1:]...)
+				cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, w, w
+				return cmd.Run()
+			},
+		},
+		completionSetupStep(),
+		{
+			key:   "claude-hooks",
+			title: "Install Claude Code hooks",
+			check: func() (bool, string) {
+				configDir := getClaudeConfigDirForHooks()
+				if session.CheckClaudeHooksInstalled(configDir) {
+					return true, configDir
+				}
+				return false, configDir
+			},
+			apply: func(io.Writer) error {
+				_, err := session.InjectClaudeHooks(getClaudeConfigDirForHooks())
+				return err
+			},
+		},
+	}
+
+	if _, err := exec.LookPath("codex"); err == nil {
+		steps = append(steps, setupStep{
+			key:   "codex-hooks",
+			title: "Install Codex notify hook",
+			check: func() (bool, string) {
+				content, _ := readFileOrEmpty(getCodexConfigPath())
+				installed := strings.Contains(content, codexNotifyMarkerBegin) || codexNotifyExactRe.MatchString(content)
+				return installed, getCodexConfigPath()
+			},
+			apply: installCodexHooks,
+		})
+	}
+
+	if _, err := exec.LookPath("gemini"); err == nil {
+		steps = append(steps, setupStep{
+			key:   "gemini-hooks",
+			title: "Install Gemini CLI hooks",
+			check: func() (bool, string) {
+				configDir := getGeminiConfigDirForHooks()
+				return session.CheckGeminiHooksInstalled(configDir), configDir
+			},
+			apply: func(io.Writer) error {
+				_, err := session.InjectGeminiHooks(getGeminiConfigDirForHooks())
+				return err
+			},
+		})
+	}
+
+	steps = append(steps,
+		setupStep{
+			key:   "notifier",
+			title: "Register notifier daemon",
+			check: func() (bool, string) {
+				if session.IsTransitionNotifierDaemonRunning() {
+					return true, "running"
+				}
+				return false, "not running"
+			},
+			apply: func(w io.Writer) error {
+				path, err := session.InstallTransitionNotifierDaemon()
+				if err == nil {
+					fmt.Fprintf(w, "  unit: %s\n", path)
+				}
+				return err
+			},
+		},
+		starterGroupsSetupStep(profile, groups),
+	)
+	return steps
+}
+
+// completionSetupStep installs the completion script for the user's $SHELL.
+func completionSetupStep() setupStep {
+	shell := detectShell()
+	home, _ := os.UserHomeDir()
+	path, pathErr := completionInstallPath(home, shell)
+	return setupStep{
+		key:   "completion",
+		title: "Install shell completion",
+		check: func() (bool, string) {
+			if pathErr != nil {
+				return false, fmt.Sprintf("unsupported shell %q", shell)
+			}
+			if _, err := os.Stat(path); err == nil {
+				return true, path
+			}
+			return false, path
+		},
+		apply: func(w io.Writer) error {
+			if pathErr != nil {
+				return pathErr
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return err
+			}
+			f, err := os.Create(path)
+			if err != nil {
+				return err
+			}
+			if err := writeCompletionScript(f, shell); err != nil {
+				_ = f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+			if shell == "zsh" {
+				fmt.Fprintln(w, "  add to ~/.zshrc: fpath=(~/.zsh/completions $fpath); autoload -U compinit && compinit")
+			}
+			return nil
+		},
+	}
+}
+
+// starterGroupsSetupStep ensures the profile exists and seeds starter groups
+// when it has no groups yet. A profile that already has groups is left alone.
+func starterGroupsSetupStep(profile string, groups []string) setupStep {
+	name := profile
+	if name == "" {
+		name = session.DefaultProfile
+	}
+	return setupStep{
+		key:   "groups",
+		title: "Create starter groups",
+		check: func() (bool, string) {
+			if len(groups) == 0 {
+				return true, "none requested"
+			}
+			exists, err := session.ProfileExists(name)
+			if err != nil || !exists {
+				return false, fmt.Sprintf("profile %q: %s", name, strings.Join(groups, ", "))
+			}
+			storage, err := session.NewStorageWithProfile(profile)
+			if err != nil {
+				return false, err.Error()
+			}
+			defer storage.Close()
+			_, existing, err := storage.LoadWithGroups()
+			if err != nil {
+				return false, err.Error()
+			}
+			if len(existing) > 0 {
+				return true, fmt.Sprintf("profile %q has %d group(s)", name, len(existing))
+			}
+			return false, fmt.Sprintf("profile %q: %s", name, strings.Join(groups, ", "))
+		},
+		apply: func(io.Writer) error {
+			if exists, _ := session.ProfileExists(name); !exists {
+				if err := session.CreateProfile(name); err != nil {
+					return err
+				}
+			}
+			storage, err := session.NewStorageWithProfile(profile)
+			if err != nil {
+				return err
+			}
+			defer storage.Close()
+			instances, existing, err := storage.LoadWithGroups()
+			if err != nil {
+				return err
+			}
+			tree := session.NewGroupTreeWithGroups(instances, existing)
+			for _, g := range groups {
+				tree.CreateGroup(g)
+			}
+			return storage.SaveWithGroups(instances, tree)
+		},
+	}
+}
+
+// tmuxInstallCommand returns the argv that installs tmux with the first
+// available package manager, or nil when none is found.
+func tmuxInstallCommand(goos string, lookPath func(string) (string, error)) []string {
+	has := func(bin string) bool {
+		_, err := lookPath(bin)
+		return err == nil
+	}
+	if goos == "darwin" {
+		if has("brew") {
+			return []string{"brew", "install", "tmux"}
+		}
+		return nil
+	}
+	switch {
+	case has("apt-get"):
+		return []string{"sudo", "apt-get", "install", "-y", "tmux"}
+	case has("dnf"):
+		return []string{"sudo", "dnf", "install", "-y", "tmux"}
+	case has("pacman"):
+		return []string{"sudo", "pacman", "-S", "--noconfirm", "tmux"}
+	case has("brew"):
+		return []string{"brew", "install", "tmux"}
+	}
+	return nil
+}
+
+// splitNonEmpty splits a comma-separated list, dropping blanks.
+func splitNonEmpty(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func fakeSetupStep(key string, done bool, applied *[]string, applyErr error) setupStep {
+	return setupStep{
+		key:   key,
+		title: "Step " + key,
+		check: func() (bool, string) { return done, "" },
+		apply: func(io.Writer) error {
+			*applied = append(*applied, key)
+			return applyErr
+		},
+	}
+}
+
+func TestRunSetup_AppliesOnlyPendingSteps(t *testing.T) {
+	var applied []string
+	steps := []setupStep{
+		fakeSetupStep("a", true, &applied, nil),
+		fakeSetupStep("b", false, &applied, nil),
+		fakeSetupStep("c", false, &applied, nil),
+	}
+	var out bytes.Buffer
+	failed := runSetup(steps, strings.NewReader(""), &out, setupOptions{yes: true, skip: map[string]bool{"c": true}})
+	if failed != 0 {
+		t.Fatalf("failed = %d, want 0", failed)
+	}
+	if !reflect.DeepEqual(applied, []string{"b"}) {
+		t.Fatalf("applied = %v, want [b] (a done, c skipped)", applied)
+	}
+}
+
+func TestRunSetup_PromptDeclineSkips(t *testing.T) {
+	var applied []string
+	steps := []setupStep{
+		fakeSetupStep("a", false, &applied, nil),
+		fakeSetupStep("b", false, &applied, nil),
+	}
+	var out bytes.Buffer
+	// Decline the first step, accept the second with a bare Enter.
+	runSetup(steps, strings.NewReader("n\n\n"), &out, setupOptions{skip: map[string]bool{}})
+	if !reflect.DeepEqual(applied, []string{"b"}) {
+		t.Fatalf("applied = %v, want [b]", applied)
+	}
+}
+
+func TestRunSetup_CheckOnlyChangesNothing(t *testing.T) {
+	var applied []string
+	steps := []setupStep{fakeSetupStep("a", false, &applied, nil)}
+	var out bytes.Buffer
+	runSetup(steps, strings.NewReader(""), &out, setupOptions{checkOnly: true, skip: map[string]bool{}})
+	if len(applied) != 0 {
+		t.Fatalf("check-only applied %v", applied)
+	}
+	if !strings.Contains(out.String(), "1 step(s) pending") {
+		t.Fatalf("missing pending summary:\n%s", out.String())
+	}
+}
+
+func TestRunSetup_CountsFailures(t *testing.T) {
+	var applied []string
+	steps := []setupStep{fakeSetupStep("a", false, &applied, errors.New("boom"))}
+	var out bytes.Buffer
+	if failed := runSetup(steps, strings.NewReader(""), &out, setupOptions{yes: true, skip: map[string]bool{}}); failed != 1 {
+		t.Fatalf("failed = %d, want 1", failed)
+	}
+}
+
+func TestTmuxInstallCommand(t *testing.T) {
+	only := func(bins ...string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			for _, b := range bins {
+				if b == name {
+					return "/usr/bin/" + name, nil
+				}
+			}
+			return "", errors.New("not found")
+		}
+	}
+	cases := []struct {
+		goos string
+		bins []string
+		want []string
+	}{
+		{"darwin", []string{"brew"}, []string{"brew", "install", "tmux"}},
+		{"darwin", nil, nil},
+		{"linux", []string{"apt-get", "brew"}, []string{"sudo", "apt-get", "install", "-y", "tmux"}},
+		{"linux", []string{"pacman"}, []string{"sudo", "pacman", "-S", "--noconfirm", "tmux"}},
+		{"linux", nil, nil},
+	}
+	for _, tc := range cases {
+		got := tmuxInstallCommand(tc.goos, only(tc.bins...))
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("tmuxInstallCommand(%s, %v) = %v, want %v", tc.goos, tc.bins, got, tc.want)
+		}
+	}
+}
+
+func TestWriteCompletionScript(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		var buf bytes.Buffer
+		if err := writeCompletionScript(&buf, shell); err != nil {
+			t.Fatalf("%s: %v", shell, err)
+		}
+		if !strings.Contains(buf.String(), "setup") || !strings.Contains(buf.String(), "agent-deck") {
+			t.Errorf("%s completion missing commands:\n%s", shell, buf.String())
+		}
+	}
+	if err := writeCompletionScript(io.Discard, "tcsh"); err == nil {
+		t.Fatal("expected error for unsupported shell")
+	}
+}
@@ -0,0 +1,530 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// taskDispatchTimeout bounds how long `task dispatch` waits for the target
+// agent to be ready for input.
+const taskDispatchTimeout = 2 * time.Minute
+
+// handleTask dispatches task subcommands.
+func handleTask(profile string, args []string) {
+	if len(args) == 0 {
+		printTaskHelp()
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "add":
+		handleTaskAdd(profile, args[1:])
+	case "list", "ls":
+		handleTaskList(profile, args[1:])
+	case "show":
+		handleTaskShow(profile, args[1:])
+	case "next":
+		handleTaskNext(profile, args[1:])
+	case "dispatch":
+		handleTaskDispatch(profile, args[1:])
+	case "done":
+		handleTaskFinish(profile, session.TaskDone, args[1:])
+	case "fail":
+		handleTaskFinish(profile, session.TaskFailed, args[1:])
+	case "requeue":
+		handleTaskRequeue(profile, args[1:])
+	case "remove", "rm":
+		handleTaskRemove(profile, args[1:])
+	case "help", "-h", "--help":
+		printTaskHelp()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown task command '%s'\n", args[0])
+		printTaskHelp()
+		os.Exit(1)
+	}
+}
+
+func printTaskHelp() {
+	fmt.Println("Usage: agent-deck task <command> [options]")
+	fmt.Println()
+	fmt.Println("A persistent queue of work items for sessions. Users and conductors push")
+	fmt.Println("tasks; conductors (or you) dispatch them, and the agent that receives one")
+	fmt.Println("acknowledges it with `task done` or `task fail`.")
+	fmt.Println()
+	fmt.Println("States: queued -> dispatched -> done | failed")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  add [--session <s>] [--dispatch] <text>   Queue a task")
+	fmt.Println("  list [--status <s>] [--session <s>] [--all]")
+	fmt.Println("                                            List open tasks (or all)")
+	fmt.Println("  show <id>                                 Show one task")
+	fmt.Println("  next [--session <s>] [--dispatch]         Oldest queued task for a session")
+	fmt.Println("  dispatch <id> [--session <s>]             Send a queued task to its session")
+	fmt.Println("  done <id> [--result <text>]               Acknowledge a task as done")
+	fmt.Println("  fail <id> [--reason <text>]               Acknowledge a task as failed")
+	fmt.Println("  requeue <id>                              Put a dispatched or failed task back")
+	fmt.Println("  remove <id>                               Delete a task")
+	fmt.Println()
+	fmt.Println("Task IDs can be shortened to any unique prefix.")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck task add --session api \"add rate limiting to /login\"")
+	fmt.Println("  agent-deck task add \"triage the flaky e2e test\"")
+	fmt.Println("  agent-deck task next --session api --dispatch")
+	fmt.Println("  agent-deck task done task-1a2b3c4d --result \"merged in #412\"")
+}
+
+// openTaskDB loads the profile's sessions and state database, exiting on
+// failure. The caller closes storage.
+func openTaskDB(profile string, out *CLIOutput) (*session.Storage, []*session.Instance, *statedb.StateDB) {
+	storage, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	db := storage.GetDB()
+	if db == nil {
+		storage.Close()
+		out.Error("no state database for this profile", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	return storage, instances, db
+}
+
+// resolveTaskSession resolves ref to a session, exiting when it does not
+// match one.
+func resolveTaskSession(ref string, instances []*session.Instance, out *CLIOutput) *session.Instance {
+	inst, errMsg, errCode := ResolveSession(ref, instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		if errCode == ErrCodeNotFound {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+	return inst
+}
+
+// findTaskOrExit resolves a task ID or prefix.
+func findTaskOrExit(db *statedb.StateDB, ref string, out *CLIOutput) *statedb.TaskRow {
+	t, err := session.FindTask(db, ref)
+	if err != nil {
+		if errors.Is(err, session.ErrTaskNotFound) {
+			out.Error(err.Error(), ErrCodeNotFound)
+			os.Exit(2)
+		}
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	return t
+}
+
+// taskSource names whoever is queueing a task: the agent-deck session the
+// command runs in, or "user".
+func taskSource(instances []*session.Instance) string {
+	if id := GetCurrentSessionID(); id != "" {
+		if inst, _, _ := ResolveSession(id, instances); inst != nil {
+			return inst.Title
+		}
+	}
+	return "user"
+}
+
+func sessionTitles(instances []*session.Instance) map[string]string {
+	titles := make(map[string]string, len(instances))
+	for _, inst := range instances {
+		titles[inst.ID] = inst.Title
+	}
+	return titles
+}
+
+// dispatchTaskOrExit sends t to inst and reports the result.
+func dispatchTaskOrExit(profile string, db *statedb.StateDB, t *statedb.TaskRow, inst *session.Instance, out *CLIOutput) {
+	err := session.DispatchTask(db, profile, t, inst, time.Now(), func(_ string, message string) error {
+		return deliverToConductor(inst, db, message, taskDispatchTimeout)
+	})
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Dispatched %s to %s", t.ID, inst.Title), taskJSON(t, inst.Title))
+}
+
+// handleTaskAdd queues a task, optionally dispatching it right away.
+func handleTaskAdd(profile string, args []string) {
+	fs := flag.NewFlagSet("task add", flag.ExitOnError)
+	sessionRef := fs.String("session", "", "Session the task is for (default: left to the conductor)")
+	sessionShort := fs.String("s", "", "Session the task is for (short)")
+	dispatch := fs.Bool("dispatch", false, "Send it to the session now (requires --session)")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode (print only the task ID)")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck task add [options] <text>")
+		fmt.Println()
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+	text := strings.TrimSpace(strings.Join(fs.Args(), " "))
+	if text == "" {
+		out.Error("task add requires <text>", ErrCodeInvalidOperation)
+		fs.Usage()
+		os.Exit(1)
+	}
+	ref := mergeFlags(*sessionRef, *sessionShort)
+	if *dispatch && ref == "" {
+		out.Error("--dispatch requires --session", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	storage, instances, db := openTaskDB(profile, out)
+	defer storage.Close()
+	var inst *session.Instance
+	if ref != "" {
+		inst = resolveTaskSession(ref, instances, out)
+	}
+	t, err := session.AddTask(db, inst, text, taskSource(instances), time.Now())
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if *dispatch {
+		dispatchTaskOrExit(profile, db, t, inst, out)
+		return
+	}
+	if *quiet && !*jsonOutput {
+		fmt.Println(t.ID)
+		return
+	}
+	title := ""
+	msg := fmt.Sprintf("Queued %s", t.ID)
+	if inst != nil {
+		title = inst.Title
+		msg += " for " + inst.Title
+	}
+	out.Success(msg, taskJSON(t, title))
+}
+
+// handleTaskList prints open tasks, or every task with --all.
+func handleTaskList(profile string, args []string) {
+	fs := flag.NewFlagSet("task list", flag.ExitOnError)
+	status := fs.String("status", "", "Only tasks in this status (queued, dispatched, done, failed)")
+	sessionRef := fs.String("session", "", "Only tasks for this session")
+	all := fs.Bool("all", false, "Include done and failed tasks")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode (IDs only)")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	statuses := session.OpenTaskStatuses
+	switch {
+	case *status != "":
+		if !validTaskStatus(*status) {
+			out.Error(fmt.Sprintf("invalid status %q (want %s)", *status, strings.Join(session.TaskStatuses, ", ")), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		statuses = []string{*status}
+	case *all:
+		statuses = nil
+	}
+
+	storage, instances, db := openTaskDB(profile, out)
+	defer storage.Close()
+	rows, err := db.LoadTasks(statuses...)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to read tasks: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if *sessionRef != "" {
+		inst := resolveTaskSession(*sessionRef, instances, out)
+		kept := rows[:0]
+		for _, t := range rows {
+			if t.SessionID == inst.ID {
+				kept = append(kept, t)
+			}
+		}
+		rows = kept
+	}
+
+	if *quiet {
+		for _, t := range rows {
+			fmt.Println(t.ID)
+		}
+		return
+	}
+	titles := sessionTitles(instances)
+	items := make([]map[string]interface{}, 0, len(rows))
+	for _, t := range rows {
+		items = append(items, taskJSON(t, titles[t.SessionID]))
+	}
+	out.Print(renderTaskList(rows, titles, time.Now()), map[string]interface{}{
+		"success": true,
+		"tasks":   items,
+	})
+}
+
+func validTaskStatus(s string) bool {
+	for _, st := range session.TaskStatuses {
+		if s == st {
+			return true
+		}
+	}
+	return false
+}
+
+// handleTaskShow prints one task in full.
+func handleTaskShow(profile string, args []string) {
+	fs := flag.NewFlagSet("task show", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+	if fs.NArg() != 1 {
+		out.Error("task show requires <id>", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	storage, instances, db := openTaskDB(profile, out)
+	defer storage.Close()
+	t := findTaskOrExit(db, fs.Arg(0), out)
+	title := sessionTitles(instances)[t.SessionID]
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s  [%s]\n", t.ID, t.Status)
+	fmt.Fprintf(&b, "  session:  %s\n", taskSessionLabel(t, title))
+	fmt.Fprintf(&b, "  source:   %s\n", t.Source)
+	fmt.Fprintf(&b, "  created:  %s\n", t.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+	if !t.DispatchedAt.IsZero() {
+		fmt.Fprintf(&b, "  dispatched: %s (attempt %d)\n", t.DispatchedAt.Local().Format("2006-01-02 15:04:05"), t.Attempts)
+	}
+	if t.Result != "" {
+		fmt.Fprintf(&b, "  result:   %s\n", t.Result)
+	}
+	b.WriteString("\n" + t.Text + "\n")
+	out.Print(b.String(), taskJSON(t, title))
+}
+
+// handleTaskNext prints (and optionally dispatches) the oldest queued task
+// a session can take. Exit code 2 when the queue is empty.
+func handleTaskNext(profile string, args []string) {
+	fs := flag.NewFlagSet("task next", flag.ExitOnError)
+	sessionRef := fs.String("session", "", "Session to take a task for (default: current session)")
+	sessionShort := fs.String("s", "", "Session to take a task for (short)")
+	dispatch := fs.Bool("dispatch", false, "Send the task to the session")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode (print only the task ID)")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+	storage, instances, db := openTaskDB(profile, out)
+	defer storage.Close()
+
+	ref := mergeFlags(*sessionRef, *sessionShort)
+	var inst *session.Instance
+	if ref != "" || *dispatch || GetCurrentSessionID() != "" {
+		var errMsg, errCode string
+		if inst, errMsg, errCode = ResolveSessionOrCurrent(ref, instances); inst == nil {
+			out.Error(errMsg, errCode)
+			os.Exit(1)
+		}
+	}
+	sessionID := ""
+	if inst != nil {
+		sessionID = inst.ID
+	}
+	t, err := session.NextTask(db, sessionID)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to read tasks: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if t == nil {
+		out.Error("no queued tasks", ErrCodeNotFound)
+		os.Exit(2)
+	}
+	if *dispatch {
+		dispatchTaskOrExit(profile, db, t, inst, out)
+		return
+	}
+	if *quiet && !*jsonOutput {
+		fmt.Println(t.ID)
+		return
+	}
+	title := sessionTitles(instances)[t.SessionID]
+	out.Print(fmt.Sprintf("%s  (%s)\n%s\n", t.ID, taskSessionLabel(t, title), t.Text), taskJSON(t, title))
+}
+
+// handleTaskDispatch sends a queued task to its session, or to --session
+// when it has none.
+func handleTaskDispatch(profile string, args []string) {
+	fs := flag.NewFlagSet("task dispatch", flag.ExitOnError)
+	sessionRef := fs.String("session", "", "Session to send it to (required when the task has none)")
+	sessionShort := fs.String("s", "", "Session to send it to (short)")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+	if fs.NArg() != 1 {
+		out.Error("task dispatch requires <id>", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	storage, instances, db := openTaskDB(profile, out)
+	defer storage.Close()
+	t := findTaskOrExit(db, fs.Arg(0), out)
+
+	ref := mergeFlags(*sessionRef, *sessionShort)
+	if ref == "" {
+		ref = t.SessionID
+	}
+	if ref == "" {
+		out.Error(fmt.Sprintf("task %s has no session; pass --session", t.ID), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	dispatchTaskOrExit(profile, db, t, resolveTaskSession(ref, instances, out), out)
+}
+
+// handleTaskFinish implements `task done` and `task fail`.
+func handleTaskFinish(profile, status string, args []string) {
+	verb, flagName, help := "done", "result", "Summary of what was done"
+	if status == session.TaskFailed {
+		verb, flagName, help = "fail", "reason", "Why the task could not be completed"
+	}
+	fs := flag.NewFlagSet("task "+verb, flag.ExitOnError)
+	result := fs.String(flagName, "", help)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+	if fs.NArg() != 1 {
+		out.Error(fmt.Sprintf("task %s requires <id>", verb), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	storage, instances, db := openTaskDB(profile, out)
+	defer storage.Close()
+	t := findTaskOrExit(db, fs.Arg(0), out)
+	if err := session.FinishTask(db, t, status, *result, time.Now()); err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Marked %s %s", t.ID, status), taskJSON(t, sessionTitles(instances)[t.SessionID]))
+}
+
+// handleTaskRequeue puts a dispatched or failed task back in the queue.
+func handleTaskRequeue(profile string, args []string) {
+	fs := flag.NewFlagSet("task requeue", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+	if fs.NArg() != 1 {
+		out.Error("task requeue requires <id>", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	storage, instances, db := openTaskDB(profile, out)
+	defer storage.Close()
+	t := findTaskOrExit(db, fs.Arg(0), out)
+	if err := session.RequeueTask(db, t, time.Now()); err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Requeued %s", t.ID), taskJSON(t, sessionTitles(instances)[t.SessionID]))
+}
+
+// handleTaskRemove deletes a task.
+func handleTaskRemove(profile string, args []string) {
+	fs := flag.NewFlagSet("task remove", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode")
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+	if fs.NArg() != 1 {
+		out.Error("task remove requires <id>", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	storage, _, db := openTaskDB(profile, out)
+	defer storage.Close()
+	t := findTaskOrExit(db, fs.Arg(0), out)
+	if _, err := db.DeleteTask(t.ID); err != nil {
+		out.Error(fmt.Sprintf("failed to remove task: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Removed task %s", t.ID), map[string]interface{}{
+		"success": true,
+		"id":      t.ID,
+	})
+}
+
+// taskJSON is the JSON shape of a task.
+func taskJSON(t *statedb.TaskRow, title string) map[string]interface{} {
+	m := map[string]interface{}{
+		"success":    true,
+		"id":         t.ID,
+		"text":       t.Text,
+		"status":     t.Status,
+		"session_id": t.SessionID,
+		"session":    title,
+		"source":     t.Source,
+		"attempts":   t.Attempts,
+		"created_at": t.CreatedAt,
+		"updated_at": t.UpdatedAt,
+	}
+	if !t.DispatchedAt.IsZero() {
+		m["dispatched_at"] = t.DispatchedAt
+	}
+	if t.Result != "" {
+		m["result"] = t.Result
+	}
+	return m
+}
+
+// taskSessionLabel is the session column of a task: its title, "(any)"
+// when unassigned, or the ID when the session was removed.
+func taskSessionLabel(t *statedb.TaskRow, title string) string {
+	switch {
+	case t.SessionID == "":
+		return "(any)"
+	case title == "":
+		return t.SessionID + " (session removed)"
+	}
+	return title
+}
+
+// renderTaskList prints one block per task: ID, status, session and age,
+// then the text and any result.
+func renderTaskList(rows []*statedb.TaskRow, titles map[string]string, now time.Time) string {
+	if len(rows) == 0 {
+		return "No tasks. Add one with: agent-deck task add [--session <s>] \"<text>\"\n"
+	}
+	var b strings.Builder
+	for i, t := range rows {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		age := now.Sub(t.UpdatedAt).Truncate(time.Minute)
+		fmt.Fprintf(&b, "%s  %-10s → %s  (%s, %s ago)\n", t.ID, t.Status, taskSessionLabel(t, titles[t.SessionID]), t.Source, formatDuration(age))
+		fmt.Fprintf(&b, "  %q\n", truncate(firstLine(t.Text), 80))
+		if t.Result != "" {
+			fmt.Fprintf(&b, "  result: %s\n", truncate(firstLine(t.Result), 80))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+func TestRenderTaskList(t *testing.T) {
+	if got := renderTaskList(nil, nil, time.Now()); !strings.Contains(got, "No tasks") {
+		t.Errorf("empty list = %q", got)
+	}
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	rows := []*statedb.TaskRow{
+		{ID: "task-a", Text: "fix navbar\nsee issue", Status: "dispatched", SessionID: "s1", Source: "user", UpdatedAt: now.Add(-90 * time.Minute)},
+		{ID: "task-b", Text: "bump deps", Status: "failed", SessionID: "gone", Source: "conductor", Result: "no network", UpdatedAt: now},
+		{ID: "task-c", Text: "triage", Status: "queued", Source: "user", UpdatedAt: now},
+	}
+	got := renderTaskList(rows, map[string]string{"s1": "web"}, now)
+	for _, want := range []string{
+		"task-a  dispatched → web  (user, 1h 30m ago)",
+		`"fix navbar"`,
+		"→ gone (session removed)",
+		"result: no network",
+		"task-c  queued     → (any)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("list missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/ui"
+)
+
+// handleTheme dispatches theme subcommands.
+func handleTheme(args []string) {
+	if len(args) == 0 {
+		printThemeHelp()
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "list", "ls":
+		handleThemeList(args[1:])
+	case "use":
+		handleThemeUse(args[1:])
+	case "export":
+		handleThemeExport(args[1:])
+	case "import":
+		handleThemeImport(args[1:])
+	case "help", "-h", "--help":
+		printThemeHelp()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown theme command '%s'\n", args[0])
+		printThemeHelp()
+		os.Exit(1)
+	}
+}
+
+func printThemeHelp() {
+	fmt.Println("Usage: agent-deck theme <command> [options]")
+	fmt.Println()
+	fmt.Println("Manage TUI color schemes. Custom themes live in [themes.<name>] in")
+	fmt.Println("config.toml: a base palette (dark or light), any colors to change, and")
+	fmt.Println("per-status colors in [themes.<name>.status]. [themes.dark] and")
+	fmt.Println("[themes.light] adjust the built-in palettes.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  list                   List themes and check custom ones for errors")
+	fmt.Println("  use <name>             Make a theme the active one")
+	fmt.Println("  export <name>          Write a theme, fully resolved, as a theme file")
+	fmt.Println("  import <file>          Add a theme file to config.toml")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck theme export dark -o mytheme.toml   # start from the built-in dark")
+	fmt.Println("  agent-deck theme import mytheme.toml --name gruvbox --use")
+	fmt.Println("  agent-deck theme use system")
+	fmt.Println()
+	fmt.Println("In the TUI, pick a theme under Settings (S); it applies immediately.")
+}
+
+// themeDefFor returns the definition behind a theme name: built-in names
+// resolve to their [themes.dark]/[themes.light] overrides (or nothing).
+func themeDefFor(name string) (session.ThemeDef, bool) {
+	def, ok := session.GetThemes()[name]
+	if name == "dark" || name == "light" {
+		return def, true
+	}
+	return def, ok
+}
+
+// handleThemeList prints the built-in and custom themes.
+func handleThemeList(args []string) {
+	fs := flag.NewFlagSet("theme list", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode (names only)")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck theme list [options]")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+
+	active := session.GetTheme()
+	themes := session.GetThemes()
+	names := append([]string{"dark", "light", "system"}, session.CustomThemeNames()...)
+	rows := make([]map[string]interface{}, 0, len(names))
+	var sb strings.Builder
+	for _, name := range names {
+		def, custom := themes[name]
+		row := map[string]interface{}{
+			"name":    name,
+			"active":  name == active,
+			"builtin": name == "dark" || name == "light" || name == "system",
+		}
+		var notes []string
+		if name != "system" {
+			base := def.ThemeBase(name)
+			row["base"] = base
+			if custom {
+				notes = append(notes, fmt.Sprintf("%d colors, %d statuses", len(def.Colors()), len(def.Status)))
+				if name == "dark" || name == "light" {
+					notes = append(notes, "customized")
+				} else {
+					notes = append(notes, "base "+base)
+				}
+				if err := session.ValidateThemeDef(name, def); err != nil {
+					row["error"] = err.Error()
+					notes = append(notes, "error: "+err.Error())
+				}
+			} else {
+				notes = append(notes, "built-in")
+			}
+		} else {
+			notes = append(notes, "follows the OS dark mode")
+		}
+		rows = append(rows, row)
+
+		marker := " "
+		if name == active {
+			marker = "*"
+		}
+		fmt.Fprintf(&sb, "%s %-16s %s\n", marker, name, strings.Join(notes, ", "))
+	}
+	if *quiet {
+		sb.Reset()
+		for _, name := range names {
+			sb.WriteString(name + "\n")
+		}
+	}
+	out.Print(sb.String(), rows)
+}
+
+// handleThemeUse sets theme = <name> in config.toml.
+func handleThemeUse(args []string) {
+	fs := flag.NewFlagSet("theme use", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck theme use <name> [options]")
+		fmt.Println()
+		fmt.Println("Set the active theme: dark, light, system or a [themes.<name>] theme.")
+		fmt.Println("A running TUI picks it up the next time Settings is saved or on restart.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		out.Error("theme name is required", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	name := fs.Arg(0)
+
+	config, err := session.LoadUserConfig()
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load config: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if name != "system" {
+		def, ok := themeDefFor(name)
+		if !ok {
+			out.Error(fmt.Sprintf("theme '%s' not found (see 'agent-deck theme list')", name), ErrCodeNotFound)
+			os.Exit(2)
+		}
+		if err := session.ValidateThemeDef(name, def); err != nil {
+			out.Error(err.Error(), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	}
+	config.Theme = name
+	if err := session.SaveUserConfig(config); err != nil {
+		out.Error(fmt.Sprintf("failed to save config: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Theme set to '%s'", name), map[string]interface{}{
+		"success": true,
+		"theme":   name,
+	})
+}
+
+// handleThemeExport writes a theme file: the theme's base palette with its
+// overrides applied and every color spelled out, so the file needs nothing
+// else to reproduce the theme.
+func handleThemeExport(args []string) {
+	fs := flag.NewFlagSet("theme export", flag.ExitOnError)
+	output := fs.String("o", "", "Write to this file instead of stdout")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck theme export <name> [-o file]")
+		fmt.Println()
+		fmt.Println("Print a theme as a theme file. Exporting dark or light gives a complete")
+		fmt.Println("palette to start a custom theme from.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		fmt.Fprintln(os.Stderr, "Error: theme name is required")
+		os.Exit(1)
+	}
+	name := fs.Arg(0)
+	if name == "system" {
+		fmt.Fprintln(os.Stderr, "Error: 'system' is not a palette; export dark or light")
+		os.Exit(1)
+	}
+	def, ok := themeDefFor(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: theme '%s' not found (see 'agent-deck theme list')\n", name)
+		os.Exit(2)
+	}
+	if err := session.ValidateThemeDef(name, def); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	data, err := session.EncodeThemeFile(name, ui.ResolvedThemeDef(def.ThemeBase(name), def))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to encode theme: %v\n", err)
+		os.Exit(1)
+	}
+	if *output == "" {
+		_, _ = os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(*output, data, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write %s: %v\n", *output, err)
+		os.Exit(1)
+	}
+	fmt.Printf("%s Exported theme '%s' to %s\n", successSymbol, name, *output)
+}
+
+// handleThemeImport adds a theme file to [themes.<name>] in config.toml.
+func handleThemeImport(args []string) {
+	fs := flag.NewFlagSet("theme import", flag.ExitOnError)
+	nameFlag := fs.String("name", "", "Import under this name instead of the file's")
+	use := fs.Bool("use", false, "Make the imported theme the active one")
+	force := fs.Bool("force", false, "Replace an existing theme of the same name")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode")
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck theme import <file> [options]")
+		fmt.Println()
+		fmt.Println("Add a theme file (from 'agent-deck theme export') to config.toml. The")
+		fmt.Println("name comes from the file's name = key, --name, or the file name.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		out.Error("theme file is required", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	path := fs.Arg(0)
+	fallback := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	name, def, err := session.ReadThemeFile(path, fallback)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if *nameFlag != "" {
+		name = *nameFlag
+		if err := session.ValidateThemeDef(name, def); err != nil {
+			out.Error(err.Error(), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	}
+
+	config, err := session.LoadUserConfig()
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load config: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if _, exists := config.Themes[name]; exists && !*force {
+		out.Error(fmt.Sprintf("theme '%s' already exists; use --force to replace it or --name to import under another name", name), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if config.Themes == nil {
+		config.Themes = make(map[string]session.ThemeDef)
+	}
+	config.Themes[name] = def
+	if *use {
+		config.Theme = name
+	}
+	if err := session.SaveUserConfig(config); err != nil {
+		out.Error(fmt.Sprintf("failed to save config: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	msg := fmt.Sprintf("Imported theme '%s'", name)
+	if *use {
+		msg += " and made it active"
+	}
+	out.Success(msg, map[string]interface{}{
+		"success": true,
+		"name":    name,
+		"active":  *use,
+	})
+}
@@ -23,6 +23,8 @@ func handleTry(profile string, args []string) {
 	toolShort := fs.String("c", "", "AI tool to use (short)")
 	noSession := fs.Bool("no-session", false, "Create folder only, don't start session")
 	sandbox := fs.Bool("sandbox", false, "Run session in Docker sandbox")
+	group := fs.String("group", "", "Session group (applies [experiments.groups.<group>] settings)")
+	groupShort := fs.String("g", "", "Session group (short)")
 
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck try <name> [options]")
@@ -42,12 +44,19 @@ func handleTry(profile string, args []string) {
 		fmt.Println("  agent-deck try --list redis         # Fuzzy search experiments")
 		fmt.Println("  agent-deck try myproject -c gemini  # Use Gemini instead of Claude")
 		fmt.Println("  agent-deck try myproject --no-session  # Just create folder")
+		fmt.Println("  agent-deck try tokenizer -g work/ml    # Use the work/ml group's settings")
 		fmt.Println()
 		fmt.Printf("Config (%s):\n", effectiveUserConfigPathForHelp())
 		fmt.Println("  [experiments]")
 		fmt.Println("  directory = \"~/src/tries\"    # Base directory for experiments")
 		fmt.Println("  date_prefix = true           # Add YYYY-MM-DD- prefix")
 		fmt.Println("  default_tool = \"claude\"     # Default AI tool")
+		fmt.Println("  template = \"~/experiments/{yyyy}/{mm}/{name}\"  # Folder layout (replaces directory)")
+		fmt.Println("  group = \"experiments\"      # Session group")
+		fmt.Println("  cleanup_empty_days = 14      # Maintenance removes empty folders (0 = never)")
+		fmt.Println()
+		fmt.Println("  [experiments.groups.\"work/ml\"]  # Per-group overrides for --group")
+		fmt.Println("  template = \"~/work/ml/{date}-{name}\"")
 	}
 
 	// Reorder args: move name to end so flags are parsed correctly
@@ -60,7 +69,7 @@ func handleTry(profile string, args []string) {
 	}
 
 	// Get settings
-	settings := session.GetExperimentsSettings()
+	settings := session.GetExperimentsSettings().ForGroup(mergeFlags(*group, *groupShort))
 
 	// Merge flags
 	listMode := *listOnly || *listShort
@@ -75,7 +84,7 @@ func handleTry(profile string, args []string) {
 
 	// Handle list mode
 	if listMode {
-		handleTryList(settings.Directory, fs.Arg(0), *jsonOutput)
+		handleTryList(settings, fs.Arg(0), *jsonOutput)
 		return
 	}
 
@@ -87,7 +96,7 @@ func handleTry(profile string, args []string) {
 	}
 
 	// Find or create experiment
-	exp, created, err := experiments.FindOrCreate(settings.Directory, name, settings.GetDatePrefix())
+	exp, created, err := settings.FindOrCreateExperiment(name)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -144,7 +153,7 @@ func handleTry(profile string, args []string) {
 	}
 
 	// Create new session
-	newInst := session.NewInstanceWithGroup(exp.Name, exp.Path, "experiments")
+	newInst := session.NewInstanceWithGroup(exp.Name, exp.Path, settings.Group)
 	newInst.Command = selectedTool
 	newInst.Tool = detectTool(selectedTool)
 
@@ -155,7 +164,7 @@ func handleTry(profile string, args []string) {
 
 	instances = append(instances, newInst)
 
-	// Save using helper (rebuilds group tree including "experiments" group from instance)
+	// Save using helper (rebuilds group tree including the try group from instance)
 	if err := saveSessionData(storage, instances, groups); err != nil {
 		out.Error(err.Error(), ErrCodeInvalidOperation)
 		os.Exit(1)
@@ -190,8 +199,9 @@ func handleTry(profile string, args []string) {
 }
 
 // handleTryList lists experiments with optional fuzzy search
-func handleTryList(dir, query string, jsonOutput bool) {
-	exps, err := experiments.ListExperiments(dir)
+func handleTryList(settings session.ExperimentsSettings, query string, jsonOutput bool) {
+	dir := settings.Root()
+	exps, err := settings.ListExperiments()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -260,6 +270,7 @@ func reorderArgsForTryCommand(args []string) []string {
 	// Known flags that take a value (need to skip their values)
 	valueFlags := map[string]bool{
 		"-c": true, "--cmd": true, "-cmd": true,
+		"-g": true, "--group": true, "-group": true,
 	}
 
 	var flags []string
@@ -7,6 +7,7 @@ import (
 
 	"github.com/asheshgoplani/agent-deck/internal/git"
 	"github.com/asheshgoplani/agent-deck/internal/jujutsu"
+	"github.com/asheshgoplani/agent-deck/internal/sapling"
 	"github.com/asheshgoplani/agent-deck/internal/vcs"
 )
 
@@ -92,24 +93,31 @@ func (g *gitBackend) PruneWorktrees() error {
 	return git.PruneWorktrees(g.repoDir)
 }
 
+func (g *gitBackend) HasUncommittedChanges(worktreePath string) (bool, error) {
+	return git.HasUncommittedChanges(worktreePath)
+}
+
 // detectAndCreateBackend detects the VCS type for the given directory and
 // returns the appropriate Backend. Jujutsu is preferred when both jj and git
 // are present (matching jennings's original ordering in #754) so that mixed
-// repos opt into jj semantics.
+// repos opt into jj semantics. Sapling is checked next, before git.
 func detectAndCreateBackend(dir string) (vcs.Backend, error) {
 	if b, err := jujutsu.NewJJBackend(dir); err == nil {
 		return b, nil
 	}
+	if b, err := sapling.NewSLBackend(dir); err == nil {
+		return b, nil
+	}
 	if b, err := newGitBackend(dir); err == nil {
 		return b, nil
 	}
-	return nil, fmt.Errorf("not a git or jujutsu repository: %s", dir)
+	return nil, fmt.Errorf("not a git, jujutsu or sapling repository: %s", dir)
 }
 
 // createWorktreeWithSetup creates a worktree via the backend. For git
 // backends it also runs the per-repo worktree-setup script (git path
 // retains the existing CreateWorktreeWithSetup contract). For non-git
-// backends (jujutsu) the worktree is created without running a setup
+// backends (jujutsu, sapling) the worktree is created without running a setup
 // script — this is the Option B minimal-port limitation noted in the
 // PR body.
 func createWorktreeWithSetup(backend vcs.Backend, worktreePath, branchName string, stdout, stderr io.Writer, setupTimeout time.Duration) (setupErr error, err error) {
@@ -27,6 +27,13 @@ func buildWebServer(profile string, args []string, menuData web.MenuDataLoader,
 	pushEnabled := fs.Bool("push", false, "Enable web push notifications (auto-generates VAPID keys per profile)")
 	pushVAPIDSubject := fs.String("push-vapid-subject", "mailto:agentdeck@localhost", "VAPID subject used for web push notifications")
 	pushTestEvery := fs.Duration("push-test-every", 0, "Send periodic push test notifications at this interval (e.g. 10s, 1m); 0 disables")
+	relayURL := fs.String("relay", "", "Register on a self-hosted relay for end-to-end encrypted remote access (see 'agent-deck relay')")
+	tlsCert := fs.String("tls-cert", "", "Serve HTTPS with this certificate (PEM); requires --tls-key")
+	tlsKey := fs.String("tls-key", "", "Private key (PEM) for --tls-cert")
+	tlsClientCA := fs.String("tls-client-ca", "", "Require client certificates signed by this CA (mutual TLS); a valid certificate authenticates")
+	var trustedProxies stringSliceFlag
+	fs.Var(&trustedProxies, "trusted-proxy", "Reverse proxy IP or CIDR whose user header is trusted as authentication (repeatable or comma-separated)")
+	trustedProxyHeader := fs.String("trusted-proxy-header", web.DefaultTrustedProxyHeader, "Header a trusted proxy sets to the authenticated user")
 
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck web [options]")
@@ -49,11 +56,19 @@ func buildWebServer(profile string, args []string, menuData web.MenuDataLoader,
 		fmt.Println("  agent-deck web --no-tui                 # headless, perf win")
 		fmt.Println("  agent-deck web --no-tui --listen 127.0.0.1:9000")
 		fmt.Println("  agent-deck web --listen 0.0.0.0:8420 --token secret  # expose to LAN (token REQUIRED)")
+		fmt.Println("  agent-deck web --no-tui --relay https://relay.example.com  # remote access, no open port")
+		fmt.Println("  agent-deck web --listen 0.0.0.0:8443 --tls-cert deck.crt --tls-key deck.key --tls-client-ca ca.crt")
+		fmt.Println("  agent-deck web --listen 0.0.0.0:8420 --trusted-proxy 10.0.0.2  # behind Caddy/Traefik forward auth")
+		fmt.Println("  agent-deck web share my-project --ttl 4h  # read-only link to one session (see 'web share --help')")
 		fmt.Println()
 		fmt.Println("Security: the server binds loopback (127.0.0.1) by default. Binding a")
-		fmt.Println("non-loopback address without --token is refused — it would expose an")
-		fmt.Println("unauthenticated remote-code-execution surface. Override with --insecure-bind")
-		fmt.Println("(unsafe) only when you understand the risk.")
+		fmt.Println("non-loopback address without --token, --tls-client-ca or --trusted-proxy is")
+		fmt.Println("refused — it would expose an unauthenticated remote-code-execution surface.")
+		fmt.Println("Override with --insecure-bind (unsafe) only when you understand the risk.")
+		fmt.Println()
+		fmt.Println("With --trusted-proxy, a request from that address carrying the user header")
+		fmt.Println("(default X-Forwarded-User) is authorized. The proxy must authenticate users")
+		fmt.Println("itself and strip the header from incoming requests.")
 	}
 
 	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
@@ -72,10 +87,29 @@ func buildWebServer(profile string, args []string, menuData web.MenuDataLoader,
 		return nil, fmt.Errorf("--push-test-every requires --push")
 	}
 
+	if (*tlsCert == "") != (*tlsKey == "") {
+		return nil, fmt.Errorf("--tls-cert and --tls-key must be given together")
+	}
+	if *tlsClientCA != "" && *tlsCert == "" {
+		return nil, fmt.Errorf("--tls-client-ca requires --tls-cert and --tls-key")
+	}
+	var proxies []string
+	for _, v := range trustedProxies {
+		for _, p := range strings.Split(v, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				proxies = append(proxies, p)
+			}
+		}
+	}
+	if _, err := web.ParseTrustedProxies(proxies); err != nil {
+		return nil, fmt.Errorf("--trusted-proxy: %w", err)
+	}
+
 	// Report #1: refuse an unauthenticated non-loopback bind before the TUI
 	// boots. Fails fast with an actionable error rather than silently exposing
 	// an unauthenticated RCE surface (terminal bridge + session-create API).
-	if err := web.CheckBindSecurity(*listenAddr, *token, *insecureBind); err != nil {
+	authenticated := *token != "" || *tlsClientCA != "" || len(proxies) > 0
+	if err := web.CheckBindSecurity(*listenAddr, authenticated, *insecureBind); err != nil {
 		return nil, err
 	}
 
@@ -110,6 +144,12 @@ func buildWebServer(profile string, args []string, menuData web.MenuDataLoader,
 		PushVAPIDPrivateKey: resolvedPushPrivate,
 		PushVAPIDSubject:    resolvedPushSubject,
 		PushTestInterval:    *pushTestEvery,
+		RelayURL:            *relayURL,
+		TLSCertFile:         *tlsCert,
+		TLSKeyFile:          *tlsKey,
+		TLSClientCAFile:     *tlsClientCA,
+		TrustedProxies:      proxies,
+		TrustedProxyHeader:  *trustedProxyHeader,
 	})
 
 	if mutator != nil {
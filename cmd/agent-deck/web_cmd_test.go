@@ -21,6 +21,7 @@ func (noopMutator) StartSession(string) error          { return nil }
 func (noopMutator) StopSession(string) error           { return nil }
 func (noopMutator) RestartSession(string) error        { return nil }
 func (noopMutator) DeleteSession(string) error         { return nil }
+func (noopMutator) SendMessage(string, string) error   { return nil }
 func (noopMutator) CloseSession(string) error          { return nil }
 func (noopMutator) ArchiveSession(string) error        { return nil }
 func (noopMutator) UnarchiveSession(string) error      { return nil }
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/web"
+)
+
+// handleWebShare mints a read-only share link for one session, or with
+// --revoke-all rotates the signing secret so every issued link stops working.
+// Links are verified by the web server with the same per-profile secret, so
+// the server does not need to be running to mint one.
+func handleWebShare(profile string, args []string) {
+	fs := flag.NewFlagSet("web share", flag.ExitOnError)
+	ttl := fs.Duration("ttl", web.DefaultShareTTL, "How long the link stays valid (max 168h)")
+	baseURL := fs.String("url", "http://127.0.0.1:8420", "Base URL the web server is reachable at")
+	revokeAll := fs.Bool("revoke-all", false, "Invalidate every share link issued for this profile")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output (print only the URL)")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck web share <id|title> [options]")
+		fmt.Println("       agent-deck web share --revoke-all")
+		fmt.Println()
+		fmt.Println("Create a time-limited link that shows one session's live pane and")
+		fmt.Println("transcript, read-only, without the web token. Anyone with the link can")
+		fmt.Println("watch that session until it expires; nothing else on the deck is exposed.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck web share my-project")
+		fmt.Println("  agent-deck web share my-project --ttl 4h --url https://deck.example.com")
+		fmt.Println("  agent-deck web share --revoke-all")
+	}
+
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	quietMode := *quiet || *quietShort
+	out := NewCLIOutput(*jsonOutput, quietMode)
+	effectiveProfile := session.GetEffectiveProfile(profile)
+
+	if *revokeAll {
+		if fs.NArg() > 0 {
+			out.Error("--revoke-all takes no session", ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		if err := web.RotateShareSecret(effectiveProfile); err != nil {
+			out.Error(fmt.Sprintf("failed to revoke share links: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		out.Success("Revoked all share links for profile "+effectiveProfile, map[string]interface{}{
+			"success": true,
+			"revoked": true,
+			"profile": effectiveProfile,
+		})
+		return
+	}
+
+	identifier := fs.Arg(0)
+	if identifier == "" {
+		out.Error("session <id|title> required", ErrCodeInvalidOperation)
+		if !*jsonOutput {
+			fs.Usage()
+		}
+		os.Exit(1)
+	}
+	if err := web.ValidateShareTTL(*ttl); err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	_, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	inst, errMsg, errCode := ResolveSession(identifier, instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		if errCode == ErrCodeNotFound {
+			os.Exit(2)
+		}
+		os.Exit(1)
+		return // unreachable, satisfies staticcheck SA5011
+	}
+
+	secret, err := web.EnsureShareSecret(effectiveProfile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to prepare share secret: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	expires := time.Now().Add(*ttl).Truncate(time.Second)
+	link := strings.TrimRight(*baseURL, "/") + web.ShareLinkPath(web.NewShareToken(secret, inst.ID, expires))
+
+	if quietMode {
+		fmt.Println(link)
+		return
+	}
+	out.Print(fmt.Sprintf("Read-only link for '%s' (expires %s):\n%s\n", inst.Title, expires.Format("2006-01-02 15:04 MST"), link), map[string]interface{}{
+		"success":    true,
+		"id":         inst.ID,
+		"title":      inst.Title,
+		"url":        link,
+		"expires_at": expires.UTC().Format(time.RFC3339),
+	})
+}
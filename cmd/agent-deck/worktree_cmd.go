@@ -29,6 +29,14 @@ func handleWorktree(profile string, args []string) {
 		handleWorktreeCleanup(profile, args[1:])
 	case "finish":
 		handleWorktreeFinish(profile, args[1:])
+	case "diff":
+		handleWorktreeDiff(profile, args[1:])
+	case "merge":
+		handleWorktreeMerge(profile, args[1:])
+	case "pr":
+		handleWorktreePR(profile, args[1:])
+	case "sync":
+		handleWorktreeSync(profile, args[1:])
 	case "help", "-h", "--help":
 		printWorktreeUsage()
 	default:
@@ -47,7 +55,12 @@ func printWorktreeUsage() {
 	fmt.Println("Commands:")
 	fmt.Println("  list              List all worktrees in current repository")
 	fmt.Println("  info <session>    Show worktree info for a session")
+	fmt.Println("  diff <session>    Show the branch's changes against its base")
+	fmt.Println("  merge <session>   Merge (or --squash) the branch into its base; --remove cleans up")
 	fmt.Println("  finish <session>  Merge branch, remove worktree, and delete session")
+	fmt.Println("  pr create <session>  Push branch and open a pull request (gh/glab)")
+	fmt.Println("  pr status <session>  Show pull request state and CI status")
+	fmt.Println("  sync <session>    Fetch and rebase (or merge) the branch onto its base")
 	fmt.Println("  cleanup [--force] Find and remove orphaned worktrees/sessions")
 	fmt.Println()
 	fmt.Println("Global Options:")
@@ -57,10 +70,16 @@ func printWorktreeUsage() {
 	fmt.Println("Examples:")
 	fmt.Println("  agent-deck worktree list")
 	fmt.Println("  agent-deck worktree list --json")
+	fmt.Println("  agent-deck worktree list --pr")
 	fmt.Println("  agent-deck worktree info \"My Session\"")
+	fmt.Println("  agent-deck worktree diff \"My Session\" --stat")
+	fmt.Println("  agent-deck worktree merge \"My Session\" --squash")
 	fmt.Println("  agent-deck worktree finish \"My Session\"")
 	fmt.Println("  agent-deck worktree finish \"My Session\" --no-merge")
 	fmt.Println("  agent-deck worktree finish \"My Session\" --into develop")
+	fmt.Println("  agent-deck worktree pr create \"My Session\" --draft")
+	fmt.Println("  agent-deck worktree sync \"My Session\" --base develop")
+	fmt.Println("  agent-deck worktree sync --all")
 	fmt.Println("  agent-deck worktree cleanup")
 	fmt.Println("  agent-deck worktree cleanup --force")
 }
@@ -69,6 +88,7 @@ func printWorktreeUsage() {
 func handleWorktreeList(profile string, args []string) {
 	fs := flag.NewFlagSet("worktree list", flag.ExitOnError)
 	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	showPR := fs.Bool("pr", false, "Look up each branch's pull request and CI status (gh/glab)")
 
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck worktree list [options]")
@@ -128,6 +148,8 @@ func handleWorktreeList(profile string, args []string) {
 		Branch  string `json:"branch"`
 		Type    string `json:"type"` // "main" or "worktree"
 		Session string `json:"session,omitempty"`
+
+		PullRequest *git.PullRequest `json:"pull_request,omitempty"`
 	}
 
 	var results []worktreeInfo
@@ -153,6 +175,21 @@ func handleWorktreeList(profile string, args []string) {
 		results = append(results, info)
 	}
 
+	if *showPR {
+		var branches []string
+		for _, wt := range results {
+			if wt.Type == "worktree" && wt.Branch != "" {
+				branches = append(branches, wt.Branch)
+			}
+		}
+		prs := fetchWorktreePRs(repoRoot, branches)
+		for i := range results {
+			if results[i].Type == "worktree" {
+				results[i].PullRequest = prs[results[i].Branch]
+			}
+		}
+	}
+
 	if *jsonOutput {
 		out.Print("", map[string]interface{}{
 			"repo_root": repoRoot,
@@ -169,14 +206,32 @@ func handleWorktreeList(profile string, args []string) {
 	}
 
 	fmt.Printf("Repository: %s\n\n", FormatPath(repoRoot))
-	fmt.Printf("%-40s  %-20s  %-10s  %s\n", "PATH", "BRANCH", "TYPE", "SESSION")
-	fmt.Printf("%-40s  %-20s  %-10s  %s\n", strings.Repeat("-", 40), strings.Repeat("-", 20), strings.Repeat("-", 10), strings.Repeat("-", 20))
+	if *showPR {
+		fmt.Printf("%-40s  %-20s  %-10s  %-20s  %s\n", "PATH", "BRANCH", "TYPE", "SESSION", "PR")
+		fmt.Printf("%-40s  %-20s  %-10s  %-20s  %s\n", strings.Repeat("-", 40), strings.Repeat("-", 20), strings.Repeat("-", 10), strings.Repeat("-", 20), strings.Repeat("-", 20))
+	} else {
+		fmt.Printf("%-40s  %-20s  %-10s  %s\n", "PATH", "BRANCH", "TYPE", "SESSION")
+		fmt.Printf("%-40s  %-20s  %-10s  %s\n", strings.Repeat("-", 40), strings.Repeat("-", 20), strings.Repeat("-", 10), strings.Repeat("-", 20))
+	}
 
 	for _, wt := range results {
 		sessionStr := wt.Session
 		if sessionStr == "" {
 			sessionStr = "-"
 		}
+		if *showPR {
+			prStr := "-"
+			if wt.PullRequest != nil {
+				prStr = wt.PullRequest.Summary()
+			}
+			fmt.Printf("%-40s  %-20s  %-10s  %-20s  %s\n",
+				truncateString(FormatPath(wt.Path), 40),
+				truncateString(wt.Branch, 20),
+				wt.Type,
+				truncateString(sessionStr, 20),
+				prStr)
+			continue
+		}
 		fmt.Printf("%-40s  %-20s  %-10s  %s\n",
 			truncateString(FormatPath(wt.Path), 40),
 			truncateString(wt.Branch, 20),
@@ -245,15 +300,32 @@ func handleWorktreeInfo(profile string, args []string) {
 		worktreeExists = true
 	}
 
+	// Branches named by `launch -w auto` remember their prompt.
+	autoRec, _ := session.FindAutoBranch(inst.WorktreeRepoRoot, inst.WorktreeBranch)
+
+	var syncState *session.WorktreeSyncState
+	if states, err := session.ReadWorktreeSyncStates(); err == nil {
+		if st, ok := states[inst.ID]; ok {
+			syncState = &st
+		}
+	}
+
 	if *jsonOutput {
-		out.Print("", map[string]interface{}{
+		data := map[string]interface{}{
 			"session":         inst.Title,
 			"session_id":      inst.ID,
 			"branch":          inst.WorktreeBranch,
 			"worktree_path":   inst.WorktreePath,
 			"main_repo":       inst.WorktreeRepoRoot,
 			"worktree_exists": worktreeExists,
-		})
+		}
+		if autoRec != nil {
+			data["branch_prompt"] = autoRec.Prompt
+		}
+		if syncState != nil {
+			data["sync"] = syncState
+		}
+		out.Print("", data)
 		return
 	}
 
@@ -262,12 +334,19 @@ func handleWorktreeInfo(profile string, args []string) {
 	fmt.Printf("Branch:         %s\n", inst.WorktreeBranch)
 	fmt.Printf("Worktree Path:  %s\n", FormatPath(inst.WorktreePath))
 	fmt.Printf("Main Repo:      %s\n", FormatPath(inst.WorktreeRepoRoot))
+	if autoRec != nil {
+		first, _, _ := strings.Cut(autoRec.Prompt, "\n")
+		fmt.Printf("Named From:     %q\n", truncate(first, 60))
+	}
 
 	if worktreeExists {
 		fmt.Printf("Status:         exists\n")
 	} else {
 		fmt.Printf("Status:         MISSING (worktree directory not found)\n")
 	}
+	if syncState != nil {
+		fmt.Printf("Last Sync:      %s\n", describeWorktreeSync(*syncState))
+	}
 }
 
 // handleWorktreeCleanup finds and removes orphaned worktrees and sessions
@@ -550,9 +629,9 @@ func handleWorktreeFinish(profile string, args []string) {
 		os.Exit(1)
 	}
 
-	// Check for uncommitted changes (uses worktree path, not repoDir — stays standalone)
+	// Check for uncommitted changes in the worktree itself (not repoDir)
 	if !*force {
-		dirty, err := git.HasUncommittedChanges(worktreePath)
+		dirty, err := finishBackend.HasUncommittedChanges(worktreePath)
 		if err != nil {
 			// Worktree dir might be gone already
 			if _, statErr := os.Stat(worktreePath); os.IsNotExist(statErr) {
@@ -638,24 +717,60 @@ func handleWorktreeFinish(profile string, args []string) {
 		fmt.Printf("  %s Merged successfully\n", successSymbol)
 	}
 
+	// Steps 2-5: remove the worktree, branch and session.
+	removeFinishedWorktreeSession(out, storage, instances, groups, inst, finishBackend, *force, *keepBranch, *force, *jsonOutput)
+
+	if *jsonOutput {
+		out.Print("", map[string]interface{}{
+			"success":        true,
+			"session":        inst.Title,
+			"session_id":     inst.ID,
+			"branch":         worktreeBranch,
+			"merged_into":    targetBranch,
+			"merged":         !*noMerge,
+			"branch_deleted": !*keepBranch,
+		})
+	} else {
+		fmt.Printf("\n%s Finished: session '%s' removed, worktree cleaned up", successSymbol, inst.Title)
+		if !*noMerge {
+			fmt.Printf(", branch merged into %s", targetBranch)
+		}
+		fmt.Println()
+	}
+}
+
+// removeFinishedWorktreeSession removes a finished worktree session: the
+// worktree directory, its branch (unless keepBranch), the tmux session and
+// the session record, then sweeps the session's notifier and sync state.
+// forceDeleteBranch deletes the branch even when git does not consider it
+// merged, as after a squash merge.
+func removeFinishedWorktreeSession(out *CLIOutput, storage *session.Storage, instances []*session.Instance, groups []*session.GroupData, inst *session.Instance, backend vcs.Backend, force, keepBranch, forceDeleteBranch, jsonOutput bool) {
+	worktreePath := inst.WorktreePath
+	worktreeBranch := inst.WorktreeBranch
+	progress := func(format string, args ...interface{}) {
+		if !jsonOutput {
+			fmt.Printf(format, args...)
+		}
+	}
+
 	// Step 2: Remove worktree
 	if _, statErr := os.Stat(worktreePath); !os.IsNotExist(statErr) {
-		fmt.Printf("Removing worktree at %s...\n", FormatPath(worktreePath))
-		if err := finishBackend.RemoveWorktree(worktreePath, *force); err != nil {
+		progress("Removing worktree at %s...\n", FormatPath(worktreePath))
+		if err := backend.RemoveWorktree(worktreePath, force); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to remove worktree: %v\n", err)
 		} else {
-			fmt.Printf("  %s Worktree removed\n", successSymbol)
+			progress("  %s Worktree removed\n", successSymbol)
 		}
 	}
-	_ = finishBackend.PruneWorktrees()
+	_ = backend.PruneWorktrees()
 
 	// Step 3: Delete branch (if not --keep-branch)
-	if !*keepBranch {
-		fmt.Printf("Deleting branch %s...\n", worktreeBranch)
-		if err := finishBackend.DeleteBranch(worktreeBranch, *force); err != nil {
+	if !keepBranch {
+		progress("Deleting branch %s...\n", worktreeBranch)
+		if err := backend.DeleteBranch(worktreeBranch, forceDeleteBranch); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to delete branch: %v\n", err)
 		} else {
-			fmt.Printf("  %s Branch deleted\n", successSymbol)
+			progress("  %s Branch deleted\n", successSymbol)
 		}
 	}
 
@@ -688,32 +803,15 @@ func handleWorktreeFinish(profile string, args []string) {
 	// keep re-firing [EVENT] deliveries to the parent conductor. Best-effort:
 	// failures warn but never block the finish (the SQLite removal above is
 	// the user-visible contract).
-	if swept, err := session.SweepInboxesForChildSession(inst.ID); err != nil && !*jsonOutput {
+	if swept, err := session.SweepInboxesForChildSession(inst.ID); err != nil && !jsonOutput {
 		fmt.Fprintf(os.Stderr, "warn: inbox sweep for %s failed: %v\n", inst.ID, err)
-	} else if swept > 0 && !*jsonOutput {
+	} else if swept > 0 && !jsonOutput {
 		fmt.Fprintf(os.Stderr, "swept %d stale inbox event(s) for removed session\n", swept)
 	}
-	if _, err := session.RemoveNotifyStateRecord(inst.ID); err != nil && !*jsonOutput {
+	if _, err := session.RemoveNotifyStateRecord(inst.ID); err != nil && !jsonOutput {
 		fmt.Fprintf(os.Stderr, "warn: notify-state sweep for %s failed: %v\n", inst.ID, err)
 	}
-
-	if *jsonOutput {
-		out.Print("", map[string]interface{}{
-			"success":        true,
-			"session":        inst.Title,
-			"session_id":     inst.ID,
-			"branch":         worktreeBranch,
-			"merged_into":    targetBranch,
-			"merged":         !*noMerge,
-			"branch_deleted": !*keepBranch,
-		})
-	} else {
-		fmt.Printf("\n%s Finished: session '%s' removed, worktree cleaned up", successSymbol, inst.Title)
-		if !*noMerge {
-			fmt.Printf(", branch merged into %s", targetBranch)
-		}
-		fmt.Println()
-	}
+	_ = session.ClearWorktreeSyncState(inst.ID)
 }
 
 // truncateString truncates a string to maxLen, adding "..." if truncated
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/git"
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// describeBranchDiff is the one-line summary of a branch diff, e.g.
+// "feature → main: 3 commits, 5 files, +40 -3".
+func describeBranchDiff(d *git.BranchDiff) string {
+	added, deleted := d.Totals()
+	return fmt.Sprintf("%s → %s: %d commit(s), %d file(s), +%d -%d",
+		d.Branch, d.Base, d.Commits, len(d.Files), added, deleted)
+}
+
+// handleWorktreeDiff prints what merging a worktree session's branch would
+// bring into its base.
+func handleWorktreeDiff(profile string, args []string) {
+	fs := flag.NewFlagSet("worktree diff", flag.ExitOnError)
+	base := fs.String("base", "", "Base branch (default: the last one synced onto, else the repo's default branch)")
+	stat := fs.Bool("stat", false, "Only list the changed files with line counts")
+	jsonOutput := fs.Bool("json", false, "Output as JSON (includes each file's patch unless --stat)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck worktree diff <session> [options]")
+		fmt.Println()
+		fmt.Println("Show the worktree branch's changes against its base: everything from their")
+		fmt.Println("merge base to the branch tip, i.e. what 'worktree merge' would bring in.")
+		fmt.Println("Uncommitted changes in the worktree are not included.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	_, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load sessions: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	inst, errMsg, errCode := ResolveSessionOrCurrent(fs.Arg(0), instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		os.Exit(1)
+		return // unreachable, satisfies staticcheck SA5011
+	}
+
+	d, err := session.DiffInstanceWorktree(inst, *base)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	dirty, _ := git.HasUncommittedChanges(inst.WorktreePath)
+
+	if *stat {
+		for i := range d.Files {
+			d.Files[i].Patch = ""
+		}
+	}
+	var sb strings.Builder
+	sb.WriteString(describeBranchDiff(d) + "\n")
+	if dirty {
+		sb.WriteString("(worktree has uncommitted changes, not shown)\n")
+	}
+	sb.WriteString("\n")
+	for _, f := range d.Files {
+		name := f.Path
+		if f.OldPath != "" {
+			name = f.OldPath + " → " + f.Path
+		}
+		counts := fmt.Sprintf("+%d -%d", f.Added, f.Deleted)
+		if f.Binary {
+			counts = "binary"
+		}
+		fmt.Fprintf(&sb, " %s  %-12s %s\n", f.Status, counts, name)
+	}
+	if !*stat {
+		for _, f := range d.Files {
+			sb.WriteString("\n" + f.Patch + "\n")
+		}
+	}
+	out.Print(sb.String(), map[string]interface{}{
+		"session_id":  inst.ID,
+		"title":       inst.Title,
+		"branch":      d.Branch,
+		"base":        d.Base,
+		"merge_base":  d.MergeBase,
+		"commits":     d.Commits,
+		"uncommitted": dirty,
+		"files":       d.Files,
+	})
+}
+
+// handleWorktreeMerge merges a worktree session's branch into its base,
+// optionally squashed, and with --remove finishes the session like
+// `worktree finish`.
+func handleWorktreeMerge(profile string, args []string) {
+	fs := flag.NewFlagSet("worktree merge", flag.ExitOnError)
+	into := fs.String("into", "", "Branch to merge into (default: the last one synced onto, else the repo's default branch)")
+	squash := fs.Bool("squash", false, "Squash the branch into a single commit")
+	message := fs.String("m", "", "Commit message for --squash (default: \"<session title> (squashed <branch>)\")")
+	remove := fs.Bool("remove", false, "Then remove the worktree, delete the branch and delete the session")
+	keepBranch := fs.Bool("keep-branch", false, "With --remove, keep the branch")
+	force := fs.Bool("force", false, "Merge even though the worktree has uncommitted changes (they are not merged)")
+	yes := fs.Bool("yes", false, "Skip the confirmation prompt")
+	yesShort := fs.Bool("y", false, "Skip the confirmation prompt (short)")
+	jsonOutput := fs.Bool("json", false, "Output as JSON (implies --yes)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck worktree merge <session> [options]")
+		fmt.Println()
+		fmt.Println("Merge the worktree branch into its base. Review it first with")
+		fmt.Println("'agent-deck worktree diff <session>' (or Alt+w in the TUI).")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck worktree merge \"My Feature\"")
+		fmt.Println("  agent-deck worktree merge \"My Feature\" --squash -m \"Add login page\"")
+		fmt.Println("  agent-deck worktree merge \"My Feature\" --squash --remove -y")
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	storage, instances, groups, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load sessions: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	inst, errMsg, errCode := ResolveSessionOrCurrent(fs.Arg(0), instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		os.Exit(1)
+		return // unreachable, satisfies staticcheck SA5011
+	}
+
+	d, err := session.DiffInstanceWorktree(inst, *into)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if d.Commits == 0 {
+		out.Error(fmt.Sprintf("nothing to merge: %s has no commits that are not on %s", d.Branch, d.Base), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if dirty, err := git.HasUncommittedChanges(inst.WorktreePath); err == nil && dirty && !*force {
+		out.Error("worktree has uncommitted changes, which would not be merged; commit them or use --force", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	msg := *message
+	if msg == "" {
+		msg = fmt.Sprintf("%s (squashed %s)", inst.Title, d.Branch)
+	}
+	if !*yes && !*yesShort && !*jsonOutput {
+		fmt.Printf("Session:  %s\n", inst.Title)
+		fmt.Printf("Merge:    %s\n", describeBranchDiff(d))
+		if *squash {
+			fmt.Printf("Squash:   one commit, %q\n", msg)
+		}
+		if *remove {
+			fmt.Printf("Then:     remove worktree %s", FormatPath(inst.WorktreePath))
+			if !*keepBranch {
+				fmt.Printf(", delete branch %s", d.Branch)
+			}
+			fmt.Println(", delete the session")
+		}
+		fmt.Println()
+		fmt.Print("Proceed? [y/N]: ")
+		response, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		response = strings.TrimSpace(strings.ToLower(response))
+		if response != "y" && response != "yes" {
+			fmt.Println("Aborted.")
+			return
+		}
+	}
+
+	repoRoot := inst.WorktreeRepoRoot
+	if *squash {
+		err = git.SquashMergeBack(repoRoot, d.Branch, d.Base, msg)
+	} else {
+		err = git.MergeBack(repoRoot, d.Branch, d.Base)
+		if err != nil && git.IsGitRepo(repoRoot) && !git.IsBareRepo(repoRoot) {
+			// Leave the base checkout clean, as `worktree finish` does.
+			_ = exec.Command("git", "-C", repoRoot, "merge", "--abort").Run()
+		}
+	}
+	if err != nil {
+		out.Error(fmt.Sprintf("merge failed (aborted): %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if !*jsonOutput {
+		fmt.Printf("%s Merged %s into %s\n", successSymbol, d.Branch, d.Base)
+	}
+
+	if *remove {
+		backend, err := detectAndCreateBackend(repoRoot)
+		if err != nil {
+			out.Error(fmt.Sprintf("merged, but failed to initialize VCS for cleanup: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		// The branch is merged, but after a squash git cannot tell: force.
+		removeFinishedWorktreeSession(out, storage, instances, groups, inst, backend, *force, *keepBranch, true, *jsonOutput)
+	}
+
+	if *jsonOutput {
+		out.Print("", map[string]interface{}{
+			"success":        true,
+			"session":        inst.Title,
+			"session_id":     inst.ID,
+			"branch":         d.Branch,
+			"merged_into":    d.Base,
+			"squash":         *squash,
+			"commits":        d.Commits,
+			"files":          len(d.Files),
+			"removed":        *remove,
+			"branch_deleted": *remove && !*keepBranch,
+		})
+	} else if *remove {
+		fmt.Printf("%s Removed session '%s'\n", successSymbol, inst.Title)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// TestWorktreeMerge_SquashAndRemove drives `worktree merge --squash --remove`
+// against a real repo: the branch lands on main as one commit, and the
+// worktree, branch and session are gone afterwards.
+func TestWorktreeMerge_SquashAndRemove(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, ".local", "share"))
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, ".cache"))
+	t.Setenv("AGENT_DECK_HOME", "")
+	session.ClearUserConfigCache()
+	t.Cleanup(session.ClearUserConfigCache)
+
+	const profile = "wtmerge"
+
+	repo := filepath.Join(home, "repo")
+	if err := os.MkdirAll(repo, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	git := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t",
+			"GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t",
+		)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	git(repo, "init", "-b", "main")
+	if err := os.WriteFile(filepath.Join(repo, "f.txt"), []byte("x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git(repo, "add", ".")
+	git(repo, "commit", "-m", "init")
+	wtPath := filepath.Join(home, "wt")
+	git(repo, "worktree", "add", wtPath, "-b", "feature")
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := os.WriteFile(filepath.Join(wtPath, name), []byte(name+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		git(wtPath, "add", name)
+		git(wtPath, "commit", "-m", "add "+name)
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "t")
+	t.Setenv("GIT_AUTHOR_EMAIL", "t@t")
+	t.Setenv("GIT_COMMITTER_NAME", "t")
+	t.Setenv("GIT_COMMITTER_EMAIL", "t@t")
+
+	inst := session.NewInstance("wt-merge", wtPath)
+	inst.WorktreePath = wtPath
+	inst.WorktreeRepoRoot = repo
+	inst.WorktreeBranch = "feature"
+	storage, err := session.NewStorageWithProfile(profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	instances := []*session.Instance{inst}
+	if err := storage.SaveWithGroups(instances, session.NewGroupTreeWithGroups(instances, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	handleWorktreeMerge(profile, []string{"wt-merge", "--squash", "-m", "Add a and b", "--remove", "-y"})
+
+	if got := git(repo, "log", "-1", "--format=%s", "main"); got != "Add a and b" {
+		t.Errorf("main tip = %q, want the squash commit", got)
+	}
+	if got := git(repo, "rev-list", "--count", "main"); got != "2" {
+		t.Errorf("main has %s commits, want 2 (init + squash)", got)
+	}
+	if _, err := os.Stat(wtPath); !os.IsNotExist(err) {
+		t.Errorf("worktree still exists: %v", err)
+	}
+	if branches := git(repo, "branch", "--list", "feature"); branches != "" {
+		t.Errorf("branch not deleted: %q", branches)
+	}
+
+	storage, err = session.NewStorageWithProfile(profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer storage.Close()
+	remaining, err := storage.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range remaining {
+		if r.ID == inst.ID {
+			t.Errorf("session %s still stored", inst.ID)
+		}
+	}
+}
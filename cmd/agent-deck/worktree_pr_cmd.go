@@ -0,0 +1,268 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/asheshgoplani/agent-deck/internal/git"
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleWorktreePR dispatches `worktree pr` subcommands.
+func handleWorktreePR(profile string, args []string) {
+	if len(args) == 0 {
+		printWorktreePRUsage()
+		return
+	}
+	switch args[0] {
+	case "create":
+		handleWorktreePRCreate(profile, args[1:])
+	case "status":
+		handleWorktreePRStatus(profile, args[1:])
+	case "help", "-h", "--help":
+		printWorktreePRUsage()
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown worktree pr command: %s\n", args[0])
+		printWorktreePRUsage()
+		os.Exit(1)
+	}
+}
+
+func printWorktreePRUsage() {
+	fmt.Println("Usage: agent-deck worktree pr <command> <session> [options]")
+	fmt.Println()
+	fmt.Println("Open and track pull requests for a worktree session's branch via gh (GitHub)")
+	fmt.Println("or glab (GitLab). The forge is picked from the repository's remote URL.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  create <session>  Push the branch and open a pull request")
+	fmt.Println("  status <session>  Show the branch's pull request state and CI status")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck worktree pr create \"My Feature\"")
+	fmt.Println("  agent-deck worktree pr create \"My Feature\" --base develop --draft")
+	fmt.Println("  agent-deck worktree pr status \"My Feature\" --json")
+}
+
+// resolveWorktreePRSession loads the profile and resolves a worktree session,
+// exiting with an error for anything else.
+func resolveWorktreePRSession(profile, identifier string, out *CLIOutput) *session.Instance {
+	_, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load sessions: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	inst, errMsg, errCode := ResolveSessionOrCurrent(identifier, instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		os.Exit(1)
+		return nil // unreachable, satisfies staticcheck SA5011
+	}
+	if !inst.IsWorktree() || inst.WorktreeBranch == "" {
+		out.Error(fmt.Sprintf("session '%s' is not in a worktree", inst.Title), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	return inst
+}
+
+// worktreePRDir is where forge commands run: the worktree itself when it
+// still exists, so gh/glab resolve the right repository.
+func worktreePRDir(inst *session.Instance) string {
+	if _, err := os.Stat(inst.WorktreePath); err == nil {
+		return inst.WorktreePath
+	}
+	return inst.WorktreeRepoRoot
+}
+
+// resolvePRProvider honors an explicit --provider, else detects the forge.
+func resolvePRProvider(flagValue, dir string) (git.PRProvider, error) {
+	switch strings.ToLower(flagValue) {
+	case "":
+		return git.DetectPRProvider(dir)
+	case "github", "gh":
+		return git.PRProviderGitHub, nil
+	case "gitlab", "glab":
+		return git.PRProviderGitLab, nil
+	}
+	return "", fmt.Errorf("unknown provider %q (use github or gitlab)", flagValue)
+}
+
+func handleWorktreePRCreate(profile string, args []string) {
+	fs := flag.NewFlagSet("worktree pr create", flag.ExitOnError)
+	base := fs.String("base", "", "Target branch (default: the forge's default branch)")
+	title := fs.String("title", "", "PR title (default: filled from commits)")
+	body := fs.String("body", "", "PR description (used with --title)")
+	draft := fs.Bool("draft", false, "Open as a draft")
+	noPush := fs.Bool("no-push", false, "Don't push the branch first")
+	provider := fs.String("provider", "", "Forge: github or gitlab (default: from the remote URL)")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck worktree pr create <session> [options]")
+		fmt.Println()
+		fmt.Println("Push a worktree session's branch and open a pull request for it.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, false)
+	inst := resolveWorktreePRSession(profile, fs.Arg(0), out)
+	dir := worktreePRDir(inst)
+	branch := inst.WorktreeBranch
+
+	forge, err := resolvePRProvider(*provider, dir)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	// One PR per branch: report an open one instead of failing in gh/glab.
+	if existing, err := git.GetPullRequest(dir, forge, branch); err == nil && existing != nil &&
+		(existing.State == git.PRStateOpen || existing.State == git.PRStateDraft) {
+		if *jsonOutput {
+			out.Print("", map[string]interface{}{"success": true, "created": false, "pull_request": existing})
+		} else {
+			fmt.Printf("Branch %s already has a pull request: %s\n%s\n", branch, existing.Summary(), existing.URL)
+		}
+		return
+	}
+
+	// A branch named by `launch -w auto` remembers its prompt; it makes a
+	// better default title than the commit subject.
+	if *title == "" {
+		if rec, _ := session.FindAutoBranch(inst.WorktreeRepoRoot, branch); rec != nil {
+			first, _, _ := strings.Cut(strings.TrimSpace(rec.Prompt), "\n")
+			*title = truncate(first, 72)
+		}
+	}
+
+	if !*noPush {
+		if !*jsonOutput {
+			fmt.Printf("Pushing %s...\n", branch)
+		}
+		if err := git.PushBranch(dir, branch); err != nil {
+			out.Error(err.Error(), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	}
+
+	if !*jsonOutput {
+		fmt.Printf("Opening pull request with %s...\n", forge.CLI())
+	}
+	url, err := git.CreatePullRequest(dir, forge, branch, git.CreatePROptions{
+		Base: *base, Title: *title, Body: *body, Draft: *draft,
+	})
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to create pull request: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	pr, _ := git.GetPullRequest(dir, forge, branch)
+
+	if *jsonOutput {
+		data := map[string]interface{}{"success": true, "created": true, "url": url}
+		if pr != nil {
+			data["pull_request"] = pr
+		}
+		out.Print("", data)
+		return
+	}
+	fmt.Printf("%s Opened pull request for %s\n", successSymbol, branch)
+	if url != "" {
+		fmt.Println(url)
+	}
+}
+
+func handleWorktreePRStatus(profile string, args []string) {
+	fs := flag.NewFlagSet("worktree pr status", flag.ExitOnError)
+	provider := fs.String("provider", "", "Forge: github or gitlab (default: from the remote URL)")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck worktree pr status <session> [options]")
+		fmt.Println()
+		fmt.Println("Show the pull request for a worktree session's branch: state and CI status.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, false)
+	inst := resolveWorktreePRSession(profile, fs.Arg(0), out)
+	dir := worktreePRDir(inst)
+
+	forge, err := resolvePRProvider(*provider, dir)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	pr, err := git.GetPullRequest(dir, forge, inst.WorktreeBranch)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to get pull request: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	if *jsonOutput {
+		out.Print("", map[string]interface{}{
+			"session":      inst.Title,
+			"branch":       inst.WorktreeBranch,
+			"provider":     forge,
+			"pull_request": pr,
+		})
+		return
+	}
+	fmt.Printf("Session:  %s\n", inst.Title)
+	fmt.Printf("Branch:   %s\n", inst.WorktreeBranch)
+	if pr == nil {
+		fmt.Printf("PR:       none (run 'agent-deck worktree pr create %q')\n", inst.Title)
+		return
+	}
+	fmt.Printf("PR:       #%d %s\n", pr.Number, pr.Title)
+	fmt.Printf("State:    %s\n", pr.State)
+	if pr.Base != "" {
+		fmt.Printf("Base:     %s\n", pr.Base)
+	}
+	checks := pr.Checks
+	if checks == "" {
+		checks = "none"
+	}
+	fmt.Printf("Checks:   %s\n", checks)
+	fmt.Printf("URL:      %s\n", pr.URL)
+}
+
+// fetchWorktreePRs looks up the PR for each branch concurrently. Lookups
+// that fail are left out; a missing forge CLI fails them all at once.
+func fetchWorktreePRs(dir string, branches []string) map[string]*git.PullRequest {
+	result := make(map[string]*git.PullRequest)
+	forge, err := git.DetectPRProvider(dir)
+	if err != nil {
+		return result
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, 4)
+	for _, branch := range branches {
+		wg.Add(1)
+		go func(branch string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if pr, err := git.GetPullRequest(dir, forge, branch); err == nil && pr != nil {
+				mu.Lock()
+				result[branch] = pr
+				mu.Unlock()
+			}
+		}(branch)
+	}
+	wg.Wait()
+	return result
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/git"
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleWorktreeSync brings one worktree session (or all of them) up to date
+// with its base branch.
+func handleWorktreeSync(profile string, args []string) {
+	fs := flag.NewFlagSet("worktree sync", flag.ExitOnError)
+	base := fs.String("base", "", "Base branch (default: the last one synced onto, else the repo's default branch)")
+	strategy := fs.String("strategy", "", "rebase or merge (default: [worktree] sync_strategy, else rebase)")
+	all := fs.Bool("all", false, "Sync every worktree session in the profile")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck worktree sync <session> [options]")
+		fmt.Println("       agent-deck worktree sync --all [options]")
+		fmt.Println()
+		fmt.Println("Fetch the base branch and rebase (or merge) the worktree branch onto it.")
+		fmt.Println("A conflicting sync is aborted, leaving the worktree as it was, and the")
+		fmt.Println("session is marked as in conflict until a later sync succeeds.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(normalizeArgs(fs, args)); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, false)
+	syncStrategy := git.SyncStrategy("")
+	if *strategy != "" {
+		s, err := git.ParseSyncStrategy(*strategy)
+		if err != nil {
+			out.Error(err.Error(), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		syncStrategy = s
+	}
+
+	_, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load sessions: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	var targets []*session.Instance
+	if *all {
+		if fs.Arg(0) != "" {
+			out.Error("use either a session or --all, not both", ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		for _, inst := range instances {
+			if inst.IsWorktree() && inst.WorktreeBranch != "" && !inst.IsArchived() {
+				targets = append(targets, inst)
+			}
+		}
+	} else {
+		inst, errMsg, errCode := ResolveSessionOrCurrent(fs.Arg(0), instances)
+		if inst == nil {
+			out.Error(errMsg, errCode)
+			os.Exit(1)
+			return // unreachable, satisfies staticcheck SA5011
+		}
+		if !inst.IsWorktree() || inst.WorktreeBranch == "" {
+			out.Error(fmt.Sprintf("session '%s' is not in a worktree", inst.Title), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		targets = []*session.Instance{inst}
+	}
+
+	type syncOutcome struct {
+		Session string                     `json:"session"`
+		ID      string                     `json:"session_id"`
+		Sync    *session.WorktreeSyncState `json:"sync,omitempty"`
+		Error   string                     `json:"error,omitempty"`
+	}
+	var outcomes []syncOutcome
+	failed := false
+	for _, inst := range targets {
+		if !*jsonOutput {
+			fmt.Printf("Syncing %s (%s)...\n", inst.Title, inst.WorktreeBranch)
+		}
+		st, err := session.SyncInstanceWorktree(inst, *base, syncStrategy)
+		o := syncOutcome{Session: inst.Title, ID: inst.ID}
+		if st.Status != "" {
+			o.Sync = &st
+		}
+		if err != nil {
+			o.Error = err.Error()
+			if errors.Is(err, git.ErrWorktreeDirty) {
+				o.Error += " (commit or stash first)"
+			}
+		}
+		if err != nil || st.Status == session.WorktreeSyncConflict {
+			failed = true
+		}
+		outcomes = append(outcomes, o)
+
+		if *jsonOutput {
+			continue
+		}
+		switch {
+		case o.Sync != nil:
+			symbol := successSymbol
+			if st.Status != session.WorktreeSyncOK {
+				symbol = errorSymbol
+			}
+			fmt.Printf("  %s %s\n", symbol, describeWorktreeSync(st))
+			for _, f := range st.ConflictFiles {
+				fmt.Printf("      %s\n", f)
+			}
+		default:
+			fmt.Printf("  %s %s\n", errorSymbol, o.Error)
+		}
+	}
+
+	if *jsonOutput {
+		out.Print("", map[string]interface{}{
+			"success":  !failed,
+			"sessions": outcomes,
+		})
+	} else if *all && len(targets) == 0 {
+		fmt.Println("No worktree sessions.")
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// describeWorktreeSync renders a recorded sync on one line, e.g.
+// "rebased onto origin/main (3 new commits), 5m ago".
+func describeWorktreeSync(st session.WorktreeSyncState) string {
+	var s string
+	switch st.Status {
+	case session.WorktreeSyncOK:
+		if st.Behind == 0 {
+			s = "up to date with " + st.Upstream
+		} else {
+			verb := "rebased"
+			if st.Strategy == string(git.SyncMerge) {
+				verb = "merged"
+			}
+			s = fmt.Sprintf("%s onto %s (%d new commit(s))", verb, st.Upstream, st.Behind)
+		}
+	case session.WorktreeSyncConflict:
+		s = fmt.Sprintf("CONFLICT with %s in %s (%s aborted)",
+			st.Upstream, strings.Join(st.ConflictFiles, ", "), st.Strategy)
+	default:
+		s = "failed: " + st.Error
+	}
+	if !st.At.IsZero() {
+		s += ", " + humanizeAge(time.Since(st.At)) + " ago"
+	}
+	return s
+}
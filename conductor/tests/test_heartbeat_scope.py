@@ -0,0 +1,30 @@
+"""Heartbeat scope: meta.json "scope" limits which groups a conductor checks."""
+
+from __future__ import annotations
+
+import sys
+import types
+from pathlib import Path
+
+sys.path.insert(0, str(Path(__file__).parent.parent))
+try:
+    import toml  # noqa: F401
+except ModuleNotFoundError:
+    sys.modules["toml"] = types.SimpleNamespace(load=lambda *_args, **_kwargs: {})
+
+from bridge import conductor_scope_groups, in_conductor_scope  # noqa: E402
+
+
+def test_scope_defaults_to_conductor_group():
+    assert conductor_scope_groups({"name": "ops"}) == ["ops"]
+    assert conductor_scope_groups({"name": "ops", "scope": ""}) == ["ops"]
+
+
+def test_scope_lists_groups_and_subgroups():
+    groups = conductor_scope_groups({"name": "ops", "scope": " work/api/ ,infra"})
+    assert groups == ["work/api", "infra"]
+    assert in_conductor_scope(groups, "work/api")
+    assert in_conductor_scope(groups, "work/api/tests")
+    assert in_conductor_scope(groups, "infra")
+    assert not in_conductor_scope(groups, "work")
+    assert not in_conductor_scope(groups, "work/api-legacy")
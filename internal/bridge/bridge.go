@@ -0,0 +1,167 @@
+// Package bridge connects conductors to Telegram and Slack. It is the native
+// replacement for the embedded bridge.py: it long-polls the Telegram Bot API,
+// holds a Slack Socket Mode connection, routes "<name>: message" to the
+// matching conductor session, and sends heartbeats when no OS heartbeat timer
+// is installed. Conductors are driven through the agent-deck CLI.
+package bridge
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// aliveInterval is how often bridge.alive is touched; the conductor watchdog
+// restarts the bridge once it goes stale.
+const aliveInterval = 60 * time.Second
+
+// Bridge is a running conductor bridge.
+type Bridge struct {
+	cfg          Config
+	cli          runner
+	log          *slog.Logger
+	conductorDir string
+
+	listConductors func() ([]session.ConductorMeta, error)
+
+	// Timing, shortened in tests.
+	startSettle    time.Duration
+	pollInterval   time.Duration
+	pendingMaxWait time.Duration
+
+	telegram *telegramBot
+	slack    *slackApp
+	// alert delivers heartbeat alerts; notify unless a test replaces it.
+	alert func(ctx context.Context, text string)
+
+	mu       sync.Mutex
+	queues   map[string]*conductorQueue
+	draining bool
+}
+
+// New creates a bridge that drives conductors with the agent-deck binary at
+// agentDeck (found on PATH when empty).
+func New(cfg Config, agentDeck string, log *slog.Logger) (*Bridge, error) {
+	if agentDeck == "" {
+		agentDeck = session.FindAgentDeck()
+	}
+	if agentDeck == "" {
+		return nil, errors.New("agent-deck binary not found")
+	}
+	dir, err := session.ConductorDir()
+	if err != nil {
+		return nil, err
+	}
+	b := &Bridge{
+		cfg:            cfg,
+		cli:            execRunner{path: agentDeck},
+		log:            log,
+		conductorDir:   dir,
+		listConductors: session.ListConductors,
+		startSettle:    5 * time.Second,
+		pollInterval:   5 * time.Second,
+		pendingMaxWait: time.Hour,
+		queues:         map[string]*conductorQueue{},
+	}
+	b.alert = b.notify
+	return b, nil
+}
+
+// Run serves Telegram and/or Slack until ctx is cancelled. A platform whose
+// connection fails is retried with backoff rather than taking the bridge
+// down, so the service manager doesn't respawn it in a tight loop.
+func (b *Bridge) Run(ctx context.Context) error {
+	if !b.cfg.Telegram.Configured() && !b.cfg.Slack.Configured() {
+		return errors.New("no messaging platform configured: set [conductor.telegram] or [conductor.slack] in config.toml")
+	}
+	metas := b.conductors()
+	b.log.Info("bridge_starting",
+		slog.Bool("telegram", b.cfg.Telegram.Configured()),
+		slog.Bool("slack", b.cfg.Slack.Configured()),
+		slog.Duration("heartbeat", b.cfg.HeartbeatInterval),
+		slog.Any("conductors", conductorNames(metas)))
+
+	// Pre-start conductors so they're warm when messages arrive.
+	for _, m := range metas {
+		if b.ensureConductorRunning(ctx, m) {
+			b.log.Info("conductor_running", slog.String("conductor", m.Name))
+		} else {
+			b.log.Warn("conductor_prestart_failed", slog.String("conductor", m.Name))
+		}
+	}
+
+	var wg sync.WaitGroup
+	spawn := func(name string, fn func(context.Context) error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.runWithBackoff(ctx, name, fn)
+		}()
+	}
+	if b.cfg.Telegram.Configured() {
+		b.telegram = newTelegramBot(b.cfg.Telegram, b)
+		spawn("telegram", b.telegram.run)
+	}
+	if b.cfg.Slack.Configured() {
+		b.slack = newSlackApp(b.cfg.Slack, b)
+		spawn("slack", b.slack.run)
+	}
+	wg.Add(2)
+	go func() { defer wg.Done(); b.heartbeatLoop(ctx) }()
+	go func() { defer wg.Done(); b.livenessLoop(ctx) }()
+
+	wg.Wait()
+	b.log.Info("bridge_stopped")
+	return nil
+}
+
+// runWithBackoff reruns fn until ctx ends, waiting 5s after a failure and
+// doubling up to 5 minutes.
+func (b *Bridge) runWithBackoff(ctx context.Context, name string, fn func(context.Context) error) {
+	backoff := 5 * time.Second
+	for ctx.Err() == nil {
+		err := fn(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			backoff = 5 * time.Second
+			continue
+		}
+		b.log.Error("platform_failed", slog.String("platform", name),
+			slog.String("error", err.Error()), slog.Duration("retry_in", backoff))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, 5*time.Minute)
+	}
+}
+
+// livenessLoop touches bridge.alive while the bridge is alive.
+func (b *Bridge) livenessLoop(ctx context.Context) {
+	path := filepath.Join(b.conductorDir, "bridge.alive")
+	for {
+		now := time.Now()
+		if err := os.Chtimes(path, now, now); errors.Is(err, os.ErrNotExist) {
+			err = os.WriteFile(path, nil, 0o644)
+			if err != nil {
+				b.log.Warn("touch_alive_failed", slog.String("error", err.Error()))
+			}
+		} else if err != nil {
+			b.log.Warn("touch_alive_failed", slog.String("error", err.Error()))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(aliveInterval):
+		}
+	}
+}
@@ -0,0 +1,536 @@
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+func TestSplitMessage(t *testing.T) {
+	if got := splitMessage("short", 10); len(got) != 1 || got[0] != "short" {
+		t.Fatalf("splitMessage(short) = %q", got)
+	}
+	got := splitMessage("aaaa\nbbbb\ncccc", 10)
+	if strings.Join(got, "|") != "aaaa\nbbbb|cccc" {
+		t.Errorf("splitMessage at newline = %q", got)
+	}
+	for _, chunk := range splitMessage(strings.Repeat("é", 10), 5) {
+		if !strings.HasPrefix(chunk, "é") || len(chunk) > 5 {
+			t.Errorf("chunk %q splits a rune or exceeds limit", chunk)
+		}
+	}
+}
+
+func TestMarkdownToTelegramHTML(t *testing.T) {
+	got := markdownToTelegramHTML("**bold** *it* `a<b` x<y & z")
+	want := "<b>bold</b> <i>it</i> <code>a&lt;b</code> x&lt;y &amp; z"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := markdownToTelegramHTML("`**not bold**`"); got != "<code>**not bold**</code>" {
+		t.Errorf("code span rewritten: %q", got)
+	}
+}
+
+func TestMarkdownToSlack(t *testing.T) {
+	in := "# Title\n**bold** ~~gone~~ [docs](https://x.y)\n- item\n```\n**kept**\n```"
+	want := "*Title*\n*bold* ~gone~ <https://x.y|docs>\n• item\n```\n**kept**\n```"
+	if got := markdownToSlack(in); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestParseConductorPrefix(t *testing.T) {
+	names := []string{"ops", "research"}
+	if n, m := parseConductorPrefix("research: look at PR 12", names); n != "research" || m != "look at PR 12" {
+		t.Errorf("got %q %q", n, m)
+	}
+	if n, m := parseConductorPrefix("hello: there", names); n != "" || m != "hello: there" {
+		t.Errorf("unknown prefix routed: %q %q", n, m)
+	}
+}
+
+func TestFilterNeedLines(t *testing.T) {
+	resp := "all good\nNEED: approve deploy"
+	var counts map[string]int
+	var alerts, retired []string
+	for cycle := 1; cycle <= 4; cycle++ {
+		alerts, retired, counts = filterNeedLines(resp, counts, 3)
+		switch cycle {
+		case 1, 2:
+			if len(alerts) != 1 || len(retired) != 0 {
+				t.Fatalf("cycle %d: alerts=%v retired=%v", cycle, alerts, retired)
+			}
+		case 3:
+			if len(alerts) != 0 || len(retired) != 1 || !strings.HasPrefix(retired[0], "STILL BLOCKED") {
+				t.Fatalf("cycle 3: alerts=%v retired=%v", alerts, retired)
+			}
+		case 4:
+			if len(alerts)+len(retired) != 0 {
+				t.Fatalf("cycle 4 still forwards: %v %v", alerts, retired)
+			}
+		}
+	}
+	if alerts, _, _ = filterNeedLines(resp, map[string]int{}, 3); len(alerts) != 1 {
+		t.Error("a line that went away should alert again when it returns")
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("TEST_BRIDGE_TOKEN", "secret")
+	zero := 0
+	cfg := LoadConfig(session.ConductorSettings{
+		Telegram:          session.TelegramSettings{Token: "$TEST_BRIDGE_TOKEN", UserID: 7},
+		Slack:             session.SlackSettings{BotToken: "xoxb", AppToken: "${TEST_BRIDGE_TOKEN}", ChannelID: "C1"},
+		HeartbeatInterval: &zero,
+	})
+	if cfg.Telegram.Token != "secret" || !cfg.Telegram.Configured() {
+		t.Errorf("telegram = %+v", cfg.Telegram)
+	}
+	if cfg.Slack.AppToken != "secret" || cfg.Slack.ListenMode != "mentions" || !cfg.Slack.Configured() {
+		t.Errorf("slack = %+v", cfg.Slack)
+	}
+	if cfg.HeartbeatInterval != 0 {
+		t.Errorf("heartbeat = %v, want disabled", cfg.HeartbeatInterval)
+	}
+	if got := LoadConfig(session.ConductorSettings{}).HeartbeatInterval; got != 15*time.Minute {
+		t.Errorf("default heartbeat = %v, want 15m", got)
+	}
+}
+
+// fakeCLI stands in for the agent-deck binary. Each conductor session has a
+// status; a send records the message and answers with reply.
+type fakeCLI struct {
+	mu       sync.Mutex
+	status   map[string]string
+	reply    string
+	sendErr  string
+	sent     []string
+	sessions []listedSession
+}
+
+func (f *fakeCLI) Run(_ context.Context, _ string, _ time.Duration, args ...string) (string, string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fail := errors.New("exit status 1")
+	switch {
+	case len(args) >= 3 && args[0] == "session" && args[1] == "show":
+		st, ok := f.status[args[2]]
+		if !ok {
+			return "", "not found", fail
+		}
+		out, _ := json.Marshal(map[string]string{"status": st})
+		return string(out), "", nil
+	case len(args) >= 3 && args[0] == "session" && args[1] == "output":
+		out, _ := json.Marshal(map[string]string{"content": f.reply})
+		return string(out), "", nil
+	case len(args) >= 4 && args[0] == "session" && args[1] == "send":
+		if f.sendErr != "" {
+			return "", f.sendErr, fail
+		}
+		f.sent = append(f.sent, args[3])
+		return "", "", nil
+	case len(args) >= 3 && args[0] == "session" && (args[1] == "start" || args[1] == "restart"):
+		if _, ok := f.status[args[2]]; !ok {
+			return "", "not found", fail
+		}
+		return "", "", nil
+	case len(args) >= 1 && args[0] == "list":
+		out, _ := json.Marshal(map[string]any{"sessions": f.sessions})
+		return string(out), "", nil
+	case len(args) >= 1 && args[0] == "status":
+		return `{"waiting":1,"running":2,"idle":3,"error":0,"stopped":0,"total":6}`, "", nil
+	}
+	return "", "unexpected " + strings.Join(args, " "), fail
+}
+
+func (f *fakeCLI) sentMessages() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.sent...)
+}
+
+func (f *fakeCLI) setStatus(title, status string) {
+	f.mu.Lock()
+	f.status[title] = status
+	f.mu.Unlock()
+}
+
+func newTestBridge(t *testing.T, cli *fakeCLI, metas ...session.ConductorMeta) *Bridge {
+	t.Helper()
+	return &Bridge{
+		cli:            cli,
+		log:            slog.New(slog.NewTextHandler(io.Discard, nil)),
+		conductorDir:   t.TempDir(),
+		listConductors: func() ([]session.ConductorMeta, error) { return metas, nil },
+		pollInterval:   10 * time.Millisecond,
+		pendingMaxWait: time.Second,
+		queues:         map[string]*conductorQueue{},
+	}
+}
+
+// replies collects what the bridge posts back.
+type replies struct {
+	mu  sync.Mutex
+	got []string
+	ch  chan string
+}
+
+func newReplies() *replies { return &replies{ch: make(chan string, 16)} }
+
+func (r *replies) reply(text string) {
+	r.mu.Lock()
+	r.got = append(r.got, text)
+	r.mu.Unlock()
+	r.ch <- text
+}
+
+func (r *replies) next(t *testing.T) string {
+	t.Helper()
+	select {
+	case s := <-r.ch:
+		return s
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a reply")
+		return ""
+	}
+}
+
+func TestHandleMessage_RoutesByPrefix(t *testing.T) {
+	cli := &fakeCLI{
+		status: map[string]string{"conductor-ops": "idle", "conductor-research": "idle"},
+		reply:  "done",
+	}
+	b := newTestBridge(t, cli,
+		session.ConductorMeta{Name: "research", Profile: "default"},
+		session.ConductorMeta{Name: "ops", Profile: "default"})
+	r := newReplies()
+
+	b.handleMessage(context.Background(), message{text: "research: summarize", context: "[from:ana (U1)]", reply: r.reply})
+
+	if got := r.next(t); got != "[research] ⏳" {
+		t.Errorf("first reply = %q", got)
+	}
+	if got := r.next(t); got != "[research] done" {
+		t.Errorf("answer = %q", got)
+	}
+	if sent := cli.sentMessages(); len(sent) != 1 || sent[0] != "[from:ana (U1)] summarize" {
+		t.Errorf("sent = %q", sent)
+	}
+}
+
+func TestHandleMessage_DefaultsToFirstConductorUntagged(t *testing.T) {
+	cli := &fakeCLI{status: map[string]string{"conductor-ops": "waiting"}, reply: "hi"}
+	b := newTestBridge(t, cli, session.ConductorMeta{Name: "ops", Profile: "default"})
+	r := newReplies()
+
+	b.handleMessage(context.Background(), message{text: "status?", reply: r.reply})
+
+	if r.next(t) != "⏳" || r.next(t) != "hi" {
+		t.Errorf("replies = %q", r.got)
+	}
+}
+
+func TestHandleMessage_QueuesWhileBusy(t *testing.T) {
+	cli := &fakeCLI{status: map[string]string{"conductor-ops": "running"}, reply: "later"}
+	b := newTestBridge(t, cli, session.ConductorMeta{Name: "ops", Profile: "default"})
+	r := newReplies()
+
+	b.handleMessage(context.Background(), message{text: "do it", reply: r.reply})
+	if got := r.next(t); !strings.Contains(got, "message queued") {
+		t.Fatalf("busy reply = %q", got)
+	}
+	if len(cli.sentMessages()) != 0 {
+		t.Fatal("message sent to a busy conductor")
+	}
+
+	cli.setStatus("conductor-ops", "waiting")
+	got := r.next(t)
+	if !strings.HasPrefix(got, "Queued response (waited ") || !strings.HasSuffix(got, "\nlater") {
+		t.Errorf("queued answer = %q", got)
+	}
+	if sent := cli.sentMessages(); len(sent) != 1 || sent[0] != "do it" {
+		t.Errorf("sent = %q", sent)
+	}
+}
+
+func TestHandleMessage_StillRunningRepliesLaterWithoutResend(t *testing.T) {
+	cli := &fakeCLI{
+		status:  map[string]string{"conductor-ops": "idle"},
+		reply:   "finally",
+		sendErr: "Error: timeout waiting for completion",
+	}
+	b := newTestBridge(t, cli, session.ConductorMeta{Name: "ops", Profile: "default"})
+	r := newReplies()
+
+	b.handleMessage(context.Background(), message{text: "long job", reply: r.reply})
+	r.next(t) // ⏳
+	if got := r.next(t); !strings.Contains(got, "Still working") {
+		t.Fatalf("reply = %q", got)
+	}
+	if got := r.next(t); !strings.HasSuffix(got, "\nfinally") {
+		t.Errorf("late reply = %q", got)
+	}
+}
+
+func TestHandleMessage_PreMessageHookGates(t *testing.T) {
+	cli := &fakeCLI{status: map[string]string{"conductor-ops": "idle"}}
+	b := newTestBridge(t, cli, session.ConductorMeta{Name: "ops", Profile: "default"})
+	writeHook(t, b.conductorDir, "pre-message", "#!/bin/sh\nexit 1\n")
+
+	b.handleMessage(context.Background(), message{text: "blocked", reply: func(s string) { t.Errorf("unexpected reply %q", s) }})
+	if len(cli.sentMessages()) != 0 {
+		t.Error("gated message was sent")
+	}
+}
+
+func TestHandleMessage_PreMessageHookRewrites(t *testing.T) {
+	cli := &fakeCLI{status: map[string]string{"conductor-ops": "idle"}, reply: "ok"}
+	b := newTestBridge(t, cli, session.ConductorMeta{Name: "ops", Profile: "default"})
+	writeHook(t, b.conductorDir, "pre-message", "#!/bin/sh\necho rewritten\n")
+	r := newReplies()
+
+	b.handleMessage(context.Background(), message{text: "original", reply: r.reply})
+	r.next(t)
+	r.next(t)
+	if sent := cli.sentMessages(); len(sent) != 1 || sent[0] != "rewritten" {
+		t.Errorf("sent = %q", sent)
+	}
+}
+
+func writeHook(t *testing.T, dir, name, script string) {
+	t.Helper()
+	hooks := filepath.Join(dir, "hooks")
+	if err := os.MkdirAll(hooks, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(hooks, name), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHeartbeat_ForwardsNeedLinesForScopedSessions(t *testing.T) {
+	cli := &fakeCLI{
+		status: map[string]string{"conductor-ops": "idle"},
+		reply:  "checked\nNEED: approve api deploy",
+		sessions: []listedSession{
+			{Title: "api", Group: "ops/backend", Status: "waiting", Path: "/src/api"},
+			{Title: "web", Group: "frontend", Status: "error"},
+			{Title: "conductor-ops", Group: "conductor", Status: "idle"},
+		},
+	}
+	meta := session.ConductorMeta{Name: "ops", Profile: "default", HeartbeatEnabled: true}
+	b := newTestBridge(t, cli, meta)
+	var notified []string
+	b.alert = func(_ context.Context, text string) { notified = append(notified, text) }
+
+	b.heartbeat(context.Background(), meta, false, map[string]map[string]int{})
+
+	sent := cli.sentMessages()
+	if len(sent) != 1 {
+		t.Fatalf("sent = %q", sent)
+	}
+	if !strings.HasPrefix(sent[0], "[HEARTBEAT] [ops] Status: 1 waiting, 0 running, 0 idle, 0 error, 0 stopped.") ||
+		!strings.Contains(sent[0], "api (project: /src/api)") || strings.Contains(sent[0], "web") {
+		t.Errorf("heartbeat = %q", sent[0])
+	}
+	if len(notified) != 1 || notified[0] != "Conductor alert:\nNEED: approve api deploy" {
+		t.Errorf("notified = %q", notified)
+	}
+}
+
+func TestHeartbeat_QuietWhenNothingNeedsAttention(t *testing.T) {
+	cli := &fakeCLI{
+		status:   map[string]string{"conductor-ops": "idle"},
+		sessions: []listedSession{{Title: "api", Group: "ops", Status: "running"}},
+	}
+	meta := session.ConductorMeta{Name: "ops", Profile: "default", HeartbeatEnabled: true}
+	b := newTestBridge(t, cli, meta)
+
+	b.heartbeat(context.Background(), meta, false, map[string]map[string]int{})
+	if len(cli.sentMessages()) != 0 {
+		t.Error("heartbeat sent with nothing waiting or in error")
+	}
+}
+
+func TestTelegram_AnswersAuthorizedUserOnly(t *testing.T) {
+	cli := &fakeCLI{status: map[string]string{"conductor-ops": "idle"}, reply: "**pong**"}
+	b := newTestBridge(t, cli, session.ConductorMeta{Name: "ops", Profile: "default"})
+
+	var mu sync.Mutex
+	var sent []map[string]any
+	polled := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var params map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&params)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/getMe"):
+			_, _ = io.WriteString(w, `{"ok":true,"result":{"id":1,"username":"DeckBot"}}`)
+		case strings.HasSuffix(r.URL.Path, "/getUpdates"):
+			mu.Lock()
+			polled++
+			first := polled == 1
+			mu.Unlock()
+			if !first {
+				<-r.Context().Done()
+				return
+			}
+			_, _ = io.WriteString(w, `{"ok":true,"result":[
+				{"update_id":5,"message":{"from":{"id":99},"chat":{"id":99,"type":"private"},"text":"ping"}},
+				{"update_id":6,"message":{"from":{"id":42},"chat":{"id":-1,"type":"group"},"text":"no mention"}},
+				{"update_id":7,"message":{"from":{"id":42},"chat":{"id":-1,"type":"group"},"text":"@deckbot ping",
+					"entities":[{"type":"mention","offset":0,"length":8}]}}]}`)
+		case strings.HasSuffix(r.URL.Path, "/sendMessage"):
+			mu.Lock()
+			sent = append(sent, params)
+			mu.Unlock()
+			_, _ = io.WriteString(w, `{"ok":true,"result":{}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	tg := newTelegramBot(TelegramConfig{Token: "T", UserID: 42}, b)
+	tg.apiBase = srv.URL
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- tg.run(ctx) }()
+
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		mu.Lock()
+		n := len(sent)
+		mu.Unlock()
+		if n >= 2 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(sent) != 2 {
+		t.Fatalf("sent %d messages, want ⏳ and the answer: %v", len(sent), sent)
+	}
+	if sent[1]["text"] != "<b>pong</b>" || sent[1]["parse_mode"] != "HTML" || sent[1]["chat_id"] != float64(-1) {
+		t.Errorf("answer = %v", sent[1])
+	}
+	if got := cli.sentMessages(); len(got) != 1 || got[0] != "ping" {
+		t.Errorf("conductor got %q, want the mention stripped", got)
+	}
+}
+
+func TestSlack_SocketModeMentionAndSlashCommand(t *testing.T) {
+	cli := &fakeCLI{status: map[string]string{"conductor-ops": "idle"}, reply: "**ack**"}
+	b := newTestBridge(t, cli, session.ConductorMeta{Name: "ops", Profile: "default"})
+
+	var mu sync.Mutex
+	var posts []map[string]any
+	var responded []string
+	acks := make(chan string, 4)
+	upgrader := websocket.Upgrader{}
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/apps.connections.open":
+			if r.Header.Get("Authorization") != "Bearer xapp" {
+				t.Errorf("connections.open auth = %q", r.Header.Get("Authorization"))
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "url": "ws" + strings.TrimPrefix(srv.URL, "http") + "/socket"})
+		case "/socket":
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			_ = conn.WriteJSON(map[string]any{"type": "hello"})
+			_ = conn.WriteJSON(map[string]any{"type": "events_api", "envelope_id": "e1", "payload": map[string]any{
+				"event": map[string]any{"type": "app_mention", "user": "U1", "channel": "C9", "text": "<@UBOT> hello", "ts": "1.0"}}})
+			_ = conn.WriteJSON(map[string]any{"type": "slash_commands", "envelope_id": "e2", "payload": map[string]any{
+				"command": "/ad-status", "user_id": "U1", "response_url": srv.URL + "/respond"}})
+			for {
+				var ack map[string]string
+				if conn.ReadJSON(&ack) != nil {
+					return
+				}
+				acks <- ack["envelope_id"]
+			}
+		case "/api/users.info":
+			_, _ = io.WriteString(w, `{"ok":true,"user":{"profile":{"display_name":"ana"}}}`)
+		case "/api/conversations.info":
+			_, _ = io.WriteString(w, `{"ok":true,"channel":{"name":"ops"}}`)
+		case "/api/chat.postMessage":
+			var p map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&p)
+			mu.Lock()
+			posts = append(posts, p)
+			mu.Unlock()
+			_, _ = io.WriteString(w, `{"ok":true}`)
+		case "/respond":
+			var p map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&p)
+			mu.Lock()
+			responded = append(responded, p["text"])
+			mu.Unlock()
+		}
+	}))
+	defer srv.Close()
+
+	sl := newSlackApp(SlackConfig{BotToken: "xoxb", AppToken: "xapp", ChannelID: "C9", ListenMode: "mentions"}, b)
+	sl.apiBase = srv.URL + "/api"
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- sl.run(ctx) }()
+
+	for _, want := range []string{"e1", "e2"} {
+		select {
+		case got := <-acks:
+			if got != want {
+				t.Errorf("ack = %q, want %q", got, want)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("no ack for %s", want)
+		}
+	}
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		mu.Lock()
+		n, m := len(posts), len(responded)
+		mu.Unlock()
+		if (n >= 2 && m >= 1) || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(posts) != 2 || posts[1]["text"] != "*ack*" || posts[1]["thread_ts"] != "1.0" || posts[1]["channel"] != "C9" {
+		t.Errorf("posts = %v", posts)
+	}
+	if len(responded) != 1 || !strings.HasPrefix(responded[0], "Total: 6 sessions") {
+		t.Errorf("slash command response = %q", responded)
+	}
+	if got := cli.sentMessages(); len(got) != 1 || got[0] != "[from:ana (U1)] [channel:#ops (C9)] hello" {
+		t.Errorf("conductor got %q", got)
+	}
+}
@@ -0,0 +1,179 @@
+package bridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// The bridge drives conductors through the agent-deck CLI, like the
+// heartbeat script does: `session send --wait` already owns delivery,
+// readiness and reply capture, and a CLI call per message keeps the bridge
+// from holding the state database open.
+
+// responseTimeout is how long a blocking send waits for the conductor's
+// reply before falling back to watching for it.
+const responseTimeout = 300 * time.Second
+
+// runner runs one agent-deck command for profile ("" for the default).
+type runner interface {
+	Run(ctx context.Context, profile string, timeout time.Duration, args ...string) (stdout, stderr string, err error)
+}
+
+// execRunner runs the agent-deck binary at path.
+type execRunner struct{ path string }
+
+// Run runs the command in its own process group, so a timeout also kills the
+// tmux calls it spawned instead of leaving them queued against a pane.
+func (r execRunner) Run(ctx context.Context, profile string, timeout time.Duration, args ...string) (string, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if profile != "" {
+		args = append([]string{"-p", profile}, args...)
+	}
+	cmd := exec.CommandContext(ctx, r.path, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error { return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL) }
+	cmd.WaitDelay = 2 * time.Second
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return stdout.String(), "timeout", ctx.Err()
+	}
+	return stdout.String(), stderr.String(), err
+}
+
+// Conductor session statuses as reported by `session show --json`, plus
+// statusUnknown for a failed lookup (transient: retry, don't drop state).
+const (
+	statusUnknown = "unknown"
+	statusError   = "error"
+)
+
+// isBusy reports whether a conductor is in the middle of a turn.
+func isBusy(status string) bool {
+	return status == "running" || status == "active" || status == "starting"
+}
+
+// sessionStatus returns a session's status, or statusUnknown.
+func (b *Bridge) sessionStatus(ctx context.Context, session, profile string) string {
+	out, _, err := b.cli.Run(ctx, profile, 30*time.Second, "session", "show", session, "--json")
+	if err != nil {
+		return statusUnknown
+	}
+	var data struct {
+		Status string `json:"status"`
+	}
+	if json.Unmarshal([]byte(out), &data) != nil || data.Status == "" {
+		return statusUnknown
+	}
+	return data.Status
+}
+
+// sessionOutput returns the session's last reply (the structured content of
+// `session output --json`, not a pane capture).
+func (b *Bridge) sessionOutput(ctx context.Context, session, profile string) string {
+	out, stderr, err := b.cli.Run(ctx, profile, 30*time.Second, "session", "output", session, "--json")
+	if err != nil {
+		return "[Error getting output: " + strings.TrimSpace(stderr) + "]"
+	}
+	var data struct {
+		Content string `json:"content"`
+	}
+	if json.Unmarshal([]byte(out), &data) != nil {
+		return strings.TrimSpace(out)
+	}
+	return strings.TrimSpace(data.Content)
+}
+
+// errStillRunning means a blocking send delivered the message but the turn
+// outran the wait: the reply is still coming.
+var errStillRunning = errors.New("conductor still running")
+
+// sendAndWait delivers message and waits for the reply. It returns
+// errStillRunning when the wait timed out with the agent still working.
+func (b *Bridge) sendAndWait(ctx context.Context, session, profile, message string) (string, error) {
+	_, stderr, err := b.cli.Run(ctx, profile, responseTimeout+30*time.Second,
+		"session", "send", session, message, "--wait", "--timeout", responseTimeout.String(), "-q")
+	if err != nil {
+		s := strings.ToLower(stderr)
+		if strings.Contains(s, "timeout waiting for completion") || strings.Contains(s, "still running") {
+			return "", errStillRunning
+		}
+		return "", errors.New(strings.TrimSpace(stderr))
+	}
+	return b.sessionOutput(ctx, session, profile), nil
+}
+
+// isBusySendError reports whether a failed send hit a conductor that became
+// busy, so the message should be queued rather than dropped.
+func isBusySendError(err error) bool {
+	s := strings.ToLower(err.Error())
+	return strings.Contains(s, "timeout") || strings.Contains(s, "not ready")
+}
+
+// listedSession is one entry of `list --json`.
+type listedSession struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Path    string `json:"path"`
+	Group   string `json:"group"`
+	Tool    string `json:"tool"`
+	Status  string `json:"status"`
+	Profile string `json:"profile"`
+}
+
+// listSessions returns a profile's sessions. ok is false when the list could
+// not be read, so callers that must not guess can refuse to act.
+func (b *Bridge) listSessions(ctx context.Context, profile string) (sessions []listedSession, ok bool) {
+	out, _, err := b.cli.Run(ctx, profile, 30*time.Second, "list", "--json")
+	if err != nil {
+		return nil, false
+	}
+	if strings.HasPrefix(strings.TrimSpace(out), "No sessions found") {
+		return nil, true
+	}
+	var wrapped struct {
+		Sessions []listedSession `json:"sessions"`
+	}
+	if json.Unmarshal([]byte(out), &wrapped) == nil && wrapped.Sessions != nil {
+		return wrapped.Sessions, true
+	}
+	if json.Unmarshal([]byte(out), &sessions) == nil {
+		return sessions, true
+	}
+	return nil, false
+}
+
+// statusCounts is `status --json` for one profile.
+type statusCounts struct {
+	Waiting int `json:"waiting"`
+	Running int `json:"running"`
+	Idle    int `json:"idle"`
+	Error   int `json:"error"`
+	Stopped int `json:"stopped"`
+	Total   int `json:"total"`
+}
+
+func (c *statusCounts) add(o statusCounts) {
+	c.Waiting += o.Waiting
+	c.Running += o.Running
+	c.Idle += o.Idle
+	c.Error += o.Error
+	c.Stopped += o.Stopped
+	c.Total += o.Total
+}
+
+func (b *Bridge) statusSummary(ctx context.Context, profile string) statusCounts {
+	var c statusCounts
+	if out, _, err := b.cli.Run(ctx, profile, 30*time.Second, "status", "--json"); err == nil {
+		_ = json.Unmarshal([]byte(out), &c)
+	}
+	return c
+}
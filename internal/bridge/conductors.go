@@ -0,0 +1,230 @@
+package bridge
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// conductors returns every conductor sorted by name; the first one is the
+// default target for messages without a "<name>:" prefix.
+func (b *Bridge) conductors() []session.ConductorMeta {
+	metas, err := b.listConductors()
+	if err != nil {
+		b.log.Warn("list_conductors_failed", slog.String("error", err.Error()))
+		return nil
+	}
+	slices.SortFunc(metas, func(a, c session.ConductorMeta) int { return strings.Compare(a.Name, c.Name) })
+	for i := range metas {
+		if metas[i].Profile == "" {
+			metas[i].Profile = session.DefaultProfile
+		}
+	}
+	return metas
+}
+
+// conductorNames returns the names of conductors, in order.
+func conductorNames(metas []session.ConductorMeta) []string {
+	names := make([]string, len(metas))
+	for i, m := range metas {
+		names[i] = m.Name
+	}
+	return names
+}
+
+// conductorProfiles returns the distinct profiles conductors live in.
+func conductorProfiles(metas []session.ConductorMeta) []string {
+	var profiles []string
+	for _, m := range metas {
+		if !slices.Contains(profiles, m.Profile) {
+			profiles = append(profiles, m.Profile)
+		}
+	}
+	slices.Sort(profiles)
+	return profiles
+}
+
+// pickConductor returns the conductor called name, or the default one.
+func pickConductor(metas []session.ConductorMeta, name string) *session.ConductorMeta {
+	for i := range metas {
+		if metas[i].Name == name {
+			return &metas[i]
+		}
+	}
+	if len(metas) == 0 {
+		return nil
+	}
+	return &metas[0]
+}
+
+// ensureConductorRunning starts the conductor session when it is not live.
+// A conductor whose session was renamed is found by its directory and given
+// its title back; one with no session at all is recreated. When the session
+// list cannot be read it refuses to create anything rather than risk a
+// duplicate conductor.
+func (b *Bridge) ensureConductorRunning(ctx context.Context, meta session.ConductorMeta) bool {
+	title := session.ConductorSessionTitle(meta.Name)
+	switch b.sessionStatus(ctx, title, meta.Profile) {
+	case "waiting", "running", "idle", "active", "starting":
+		return true
+	}
+	if _, _, err := b.cli.Run(ctx, meta.Profile, 60*time.Second, "session", "start", title); err == nil {
+		return b.settledHealthy(ctx, title, meta.Profile)
+	}
+
+	sessions, ok := b.listSessions(ctx, meta.Profile)
+	if !ok {
+		b.log.Error("conductor_identity_unknown", slog.String("conductor", meta.Name))
+		return false
+	}
+	dir := filepath.Join(b.conductorDir, meta.Name)
+	var byPath, byTitle *listedSession
+	for i := range sessions {
+		s := &sessions[i]
+		if s.Profile != "" && s.Profile != meta.Profile {
+			continue
+		}
+		if s.Path != "" && filepath.Clean(session.ExpandPath(s.Path)) == filepath.Clean(dir) {
+			if byPath != nil {
+				b.log.Error("conductor_path_ambiguous", slog.String("conductor", meta.Name), slog.String("path", dir))
+				return false
+			}
+			byPath = s
+		}
+		if s.Title == title && byTitle == nil {
+			byTitle = s
+		}
+	}
+	if byPath != nil && byTitle != nil && byPath.ID != byTitle.ID {
+		b.log.Error("conductor_identity_conflict", slog.String("conductor", meta.Name))
+		return false
+	}
+
+	ref := title
+	switch existing := cmp.Or(byPath, byTitle); {
+	case existing != nil && existing.Title != title:
+		ref = existing.ID
+		b.log.Info("conductor_title_restored", slog.String("from", existing.Title), slog.String("to", title))
+		if _, stderr, err := b.cli.Run(ctx, meta.Profile, 60*time.Second, "session", "set", ref, "title", title); err != nil {
+			b.log.Error("conductor_rename_failed", slog.String("conductor", meta.Name), slog.String("error", strings.TrimSpace(stderr)))
+			return false
+		}
+	case existing == nil:
+		b.log.Info("conductor_session_created", slog.String("conductor", meta.Name))
+		if _, stderr, err := b.cli.Run(ctx, meta.Profile, 60*time.Second,
+			"add", dir, "-t", title, "-c", meta.GetAgent(), "-g", "conductor", "--title-lock"); err != nil {
+			b.log.Error("conductor_create_failed", slog.String("conductor", meta.Name), slog.String("error", strings.TrimSpace(stderr)))
+			return false
+		}
+	}
+	if _, stderr, err := b.cli.Run(ctx, meta.Profile, 60*time.Second, "session", "start", ref); err != nil {
+		b.log.Warn("conductor_start_failed", slog.String("conductor", meta.Name), slog.String("error", strings.TrimSpace(stderr)))
+		return false
+	}
+	return b.settledHealthy(ctx, ref, meta.Profile)
+}
+
+// settledHealthy waits for a just-started session to settle and reports
+// whether it came up.
+func (b *Bridge) settledHealthy(ctx context.Context, ref, profile string) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(b.startSettle):
+	}
+	status := b.sessionStatus(ctx, ref, profile)
+	return status != statusError && status != statusUnknown
+}
+
+// hookTimeout bounds a conductor hook unless meta.json sets hooks.timeout.
+const hookTimeout = 30 * time.Second
+
+// runHook runs the profile's (else the global) hook script hookName with
+// payload as JSON on stdin. ran is false when no such hook exists; ok is its
+// exit status and out its trimmed stdout.
+func (b *Bridge) runHook(ctx context.Context, profile, hookName string, payload map[string]any) (ran, ok bool, out string) {
+	var path string
+	for _, p := range []string{
+		filepath.Join(b.conductorDir, profile, "hooks", hookName),
+		filepath.Join(b.conductorDir, "hooks", hookName),
+	} {
+		info, err := os.Stat(p)
+		if err != nil {
+			continue
+		}
+		if info.Mode()&0o111 == 0 {
+			b.log.Warn("hook_not_executable", slog.String("hook", hookName), slog.String("path", p))
+			return false, false, ""
+		}
+		path = p
+		break
+	}
+	if path == "" {
+		return false, false, ""
+	}
+
+	timeout := hookTimeout
+	if data, err := os.ReadFile(filepath.Join(b.conductorDir, profile, "meta.json")); err == nil {
+		var meta struct {
+			Hooks struct {
+				Timeout int `json:"timeout"`
+			} `json:"hooks"`
+		}
+		if json.Unmarshal(data, &meta) == nil && meta.Hooks.Timeout > 0 {
+			timeout = time.Duration(meta.Hooks.Timeout) * time.Second
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	stdin, _ := json.Marshal(payload)
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Stdin = strings.NewReader(string(stdin))
+	cmd.Env = append(os.Environ(), "CONDUCTOR_PROFILE="+profile, "CONDUCTOR_DIR="+b.conductorDir)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	stdout, err := cmd.Output()
+	if s := strings.TrimSpace(stderr.String()); s != "" {
+		b.log.Warn("hook_stderr", slog.String("hook", hookName), slog.String("profile", profile), slog.String("stderr", s))
+	}
+	b.log.Info("hook_ran", slog.String("hook", hookName), slog.String("profile", profile), slog.Bool("ok", err == nil))
+	return true, err == nil, strings.TrimSpace(string(stdout))
+}
+
+// heartbeatDaemonInstalled reports whether any conductor has an OS heartbeat
+// timer (launchd agent or systemd timer); the bridge then leaves heartbeats
+// to it so conductors aren't pinged twice.
+func heartbeatDaemonInstalled() bool {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false
+	}
+	for _, pattern := range []string{
+		filepath.Join(home, "Library", "LaunchAgents", "com.agentdeck.conductor-heartbeat.*.plist"),
+		filepath.Join(home, ".config", "systemd", "user", "agent-deck-conductor-heartbeat-*.timer"),
+	} {
+		if m, _ := filepath.Glob(pattern); len(m) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// conductorTag prefixes replies with the conductor's name once there is more
+// than one to tell apart.
+func conductorTag(metas []session.ConductorMeta, name string) string {
+	if len(metas) > 1 {
+		return fmt.Sprintf("[%s] ", name)
+	}
+	return ""
+}
@@ -0,0 +1,90 @@
+package bridge
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// Config is what the bridge needs from [conductor] in config.toml, with
+// secrets resolved.
+type Config struct {
+	Telegram TelegramConfig
+	Slack    SlackConfig
+
+	// HeartbeatInterval is how often the bridge sends heartbeats when no OS
+	// heartbeat timer is installed. Zero disables them.
+	HeartbeatInterval time.Duration
+}
+
+// TelegramConfig is [conductor.telegram].
+type TelegramConfig struct {
+	Token  string
+	UserID int64
+}
+
+// Configured reports whether the Telegram bot can run.
+func (c TelegramConfig) Configured() bool { return c.Token != "" && c.UserID != 0 }
+
+// SlackConfig is [conductor.slack].
+type SlackConfig struct {
+	BotToken       string
+	AppToken       string
+	ChannelID      string
+	ListenMode     string // "mentions" (default) or "all"
+	AllowedUserIDs []string
+}
+
+// Configured reports whether the Slack app can run.
+func (c SlackConfig) Configured() bool {
+	return c.BotToken != "" && c.AppToken != "" && c.ChannelID != ""
+}
+
+// LoadConfig builds the bridge config from conductor settings. Tokens may be
+// "$ENV_VAR" / "${ENV_VAR}" references or "keychain:<service>" entries.
+// An absent heartbeat_interval means every 15 minutes, as it always has for
+// the bridge; 0 disables the heartbeat.
+func LoadConfig(s session.ConductorSettings) Config {
+	interval := 15
+	if s.HeartbeatInterval != nil {
+		interval = s.GetHeartbeatInterval()
+	}
+	listen := s.Slack.ListenMode
+	if listen == "" {
+		listen = "mentions"
+	}
+	return Config{
+		Telegram: TelegramConfig{
+			Token:  resolveSecret(s.Telegram.Token),
+			UserID: s.Telegram.UserID,
+		},
+		Slack: SlackConfig{
+			BotToken:       resolveSecret(s.Slack.BotToken),
+			AppToken:       resolveSecret(s.Slack.AppToken),
+			ChannelID:      s.Slack.ChannelID,
+			ListenMode:     listen,
+			AllowedUserIDs: s.Slack.AllowedUserIDs,
+		},
+		HeartbeatInterval: time.Duration(interval) * time.Minute,
+	}
+}
+
+// resolveSecret expands "$VAR"/"${VAR}" from the environment and looks up
+// "keychain:<service>" in the macOS Keychain. Other values are returned as is;
+// an unresolvable reference yields "".
+func resolveSecret(value string) string {
+	switch {
+	case strings.HasPrefix(value, "$"):
+		return os.Getenv(strings.Trim(strings.TrimPrefix(value, "$"), "{}"))
+	case strings.HasPrefix(value, "keychain:"):
+		out, err := exec.Command("/usr/bin/security", "find-generic-password", "-s", strings.TrimPrefix(value, "keychain:"), "-w").Output()
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(out))
+	}
+	return value
+}
@@ -0,0 +1,148 @@
+package bridge
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Platform message limits.
+const (
+	telegramMaxLength = 4096
+	slackMaxLength    = 40000
+)
+
+// splitMessage splits text into chunks of at most maxLen bytes, preferring
+// to break at a newline.
+func splitMessage(text string, maxLen int) []string {
+	if len(text) <= maxLen {
+		return []string{text}
+	}
+	var chunks []string
+	for text != "" {
+		if len(text) <= maxLen {
+			chunks = append(chunks, text)
+			break
+		}
+		cut := strings.LastIndexByte(text[:maxLen], '\n')
+		if cut <= 0 {
+			cut = maxLen
+			// Don't split inside a UTF-8 sequence.
+			for cut > 0 && text[cut]&0xC0 == 0x80 {
+				cut--
+			}
+		}
+		chunks = append(chunks, text[:cut])
+		text = strings.TrimLeft(text[cut:], "\n")
+	}
+	return chunks
+}
+
+var (
+	mdInlineCode = regexp.MustCompile("`([^`\n]+?)`")
+	mdBold       = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	mdItalic     = regexp.MustCompile(`(^|[^*])\*([^*\n]+?)\*`)
+)
+
+// markdownToTelegramHTML converts markdown bold, italic and inline code to
+// Telegram HTML and escapes everything else. Code spans are protected from
+// the bold/italic rewrite.
+func markdownToTelegramHTML(text string) string {
+	var codes []string
+	text = mdInlineCode.ReplaceAllStringFunc(text, func(m string) string {
+		codes = append(codes, mdInlineCode.FindStringSubmatch(m)[1])
+		return fmt.Sprintf("\x00CODE%d\x00", len(codes)-1)
+	})
+	text = escapeTelegramHTML(text)
+	text = mdBold.ReplaceAllString(text, "<b>$1</b>")
+	text = mdItalic.ReplaceAllString(text, "$1<i>$2</i>")
+	for i, code := range codes {
+		text = strings.Replace(text, fmt.Sprintf("\x00CODE%d\x00", i), "<code>"+escapeTelegramHTML(code)+"</code>", 1)
+	}
+	return text
+}
+
+// escapeTelegramHTML escapes the three characters Telegram's HTML parse mode
+// requires; quotes are left alone.
+func escapeTelegramHTML(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+var (
+	mdCodeBlock  = regexp.MustCompile("(?s)```.*?```")
+	mdHeader     = regexp.MustCompile(`(?m)^#{1,6}\s+(.+)$`)
+	mdStrike     = regexp.MustCompile(`~~(.+?)~~`)
+	mdLink       = regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
+	mdBullet     = regexp.MustCompile(`(?m)^(\s*)[-*]\s+`)
+	slackMention = regexp.MustCompile(`<@[A-Z0-9]+>\s*`)
+)
+
+// markdownToSlack converts GitHub-flavored markdown to Slack mrkdwn: headers
+// and **bold** become *bold*, ~~strike~~ becomes ~strike~, links become
+// <url|text> and list markers become bullets. Code is left untouched.
+func markdownToSlack(text string) string {
+	var protected []string
+	protect := func(m string) string {
+		protected = append(protected, m)
+		return fmt.Sprintf("\x00P%d\x00", len(protected)-1)
+	}
+	text = mdCodeBlock.ReplaceAllStringFunc(text, protect)
+	text = mdInlineCode.ReplaceAllStringFunc(text, protect)
+
+	text = mdHeader.ReplaceAllString(text, "*$1*")
+	text = mdBold.ReplaceAllString(text, "*$1*")
+	text = mdStrike.ReplaceAllString(text, "~$1~")
+	text = mdLink.ReplaceAllString(text, "<$2|$1>")
+	text = mdBullet.ReplaceAllString(text, "$1• ")
+
+	for i := len(protected) - 1; i >= 0; i-- {
+		text = strings.Replace(text, fmt.Sprintf("\x00P%d\x00", i), protected[i], 1)
+	}
+	return text
+}
+
+// parseConductorPrefix splits "<name>: message" into the conductor name and
+// the message. name is "" when text does not start with a known conductor.
+func parseConductorPrefix(text string, names []string) (name, message string) {
+	for _, n := range names {
+		if rest, ok := strings.CutPrefix(text, n+":"); ok {
+			return n, strings.TrimSpace(rest)
+		}
+	}
+	return "", text
+}
+
+// needRetireThreshold is how many consecutive heartbeats may repeat the same
+// NEED: line before it is escalated once and then dropped (issue #971).
+const needRetireThreshold = 3
+
+// filterNeedLines de-duplicates heartbeat NEED: lines across cycles. A line
+// is forwarded for the first threshold-1 cycles, escalated as "STILL
+// BLOCKED" on cycle threshold, and dropped after that. counts carries the
+// consecutive occurrences into the next cycle; lines that disappear reset.
+func filterNeedLines(response string, prev map[string]int, threshold int) (alerts, retired []string, counts map[string]int) {
+	counts = map[string]int{}
+	for _, raw := range strings.Split(response, "\n") {
+		line := strings.TrimSpace(raw)
+		if !strings.HasPrefix(line, "NEED:") {
+			continue
+		}
+		n := prev[line] + 1
+		counts[line] = n
+		switch {
+		case n < threshold:
+			alerts = append(alerts, line)
+		case n == threshold:
+			retired = append(retired, fmt.Sprintf("STILL BLOCKED (%d cycles, no reply): %s", threshold, line))
+		}
+	}
+	return alerts, retired, counts
+}
+
+// formatWaited renders how long a queued reply waited: "45s" or "3m 5s".
+func formatWaited(seconds int) string {
+	if seconds >= 60 {
+		return fmt.Sprintf("%dm %ds", seconds/60, seconds%60)
+	}
+	return fmt.Sprintf("%ds", seconds)
+}
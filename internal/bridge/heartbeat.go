@@ -0,0 +1,220 @@
+package bridge
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// heartbeatLoop pings each heartbeat-enabled conductor every interval when it
+// has sessions waiting or in error, and forwards the NEED: lines of its reply
+// to Telegram and Slack. It stays off when an OS heartbeat timer already does
+// this, so conductors aren't pinged twice.
+func (b *Bridge) heartbeatLoop(ctx context.Context) {
+	if b.cfg.HeartbeatInterval <= 0 {
+		b.log.Info("heartbeat_disabled")
+		return
+	}
+	if heartbeatDaemonInstalled() {
+		b.log.Info("heartbeat_left_to_os_timer")
+		return
+	}
+	b.log.Info("heartbeat_started", slog.Duration("interval", b.cfg.HeartbeatInterval))
+
+	needs := map[string]map[string]int{}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(b.cfg.HeartbeatInterval):
+		}
+		all := b.conductors()
+		for _, meta := range heartbeatConductors(all) {
+			b.heartbeat(ctx, meta, len(all) > 1, needs)
+		}
+	}
+}
+
+// heartbeatConductors returns the heartbeat-enabled conductors ordered by
+// profile, creation time and name.
+func heartbeatConductors(metas []session.ConductorMeta) []session.ConductorMeta {
+	var enabled []session.ConductorMeta
+	for _, m := range metas {
+		if m.HeartbeatEnabled {
+			enabled = append(enabled, m)
+		}
+	}
+	slices.SortFunc(enabled, func(a, c session.ConductorMeta) int {
+		return cmp.Or(cmp.Compare(a.Profile, c.Profile), cmp.Compare(a.CreatedAt, c.CreatedAt), cmp.Compare(a.Name, c.Name))
+	})
+	return enabled
+}
+
+// heartbeat runs one check-in for meta. needs carries each conductor's NEED:
+// line counts between cycles.
+func (b *Bridge) heartbeat(ctx context.Context, meta session.ConductorMeta, tagged bool, needs map[string]map[string]int) {
+	name, profile := meta.Name, meta.Profile
+	sessions, _ := b.listSessions(ctx, profile)
+	scope := meta.ScopeGroups()
+	var scoped []listedSession
+	var c statusCounts
+	for _, s := range sessions {
+		if strings.HasPrefix(s.Title, session.ConductorSessionTitlePrefix) || !session.ConductorScopeIncludes(scope, s.Group) {
+			continue
+		}
+		scoped = append(scoped, s)
+		switch s.Status {
+		case "waiting":
+			c.Waiting++
+		case "running":
+			c.Running++
+		case "idle":
+			c.Idle++
+		case "error":
+			c.Error++
+		case "stopped":
+			c.Stopped++
+		}
+	}
+	b.log.Info("heartbeat_status", slog.String("conductor", name), slog.String("profile", profile),
+		slog.Int("waiting", c.Waiting), slog.Int("running", c.Running), slog.Int("idle", c.Idle),
+		slog.Int("error", c.Error), slog.Int("stopped", c.Stopped))
+	if c.Waiting == 0 && c.Error == 0 {
+		return
+	}
+
+	msg := b.heartbeatMessage(meta, scope, scoped, c)
+	hookSessions := make([]map[string]string, len(scoped))
+	for i, s := range scoped {
+		hookSessions[i] = map[string]string{"title": s.Title, "status": s.Status, "path": s.Path}
+	}
+	if ran, ok, out := b.runHook(ctx, profile, "pre-heartbeat", map[string]any{
+		"profile":       profile,
+		"waiting":       c.Waiting,
+		"running":       c.Running,
+		"idle":          c.Idle,
+		"error":         c.Error,
+		"sessions":      hookSessions,
+		"draft_message": msg,
+	}); ran {
+		if !ok {
+			b.log.Info("heartbeat_gated_by_hook", slog.String("conductor", name))
+			return
+		}
+		if out != "" {
+			msg = out
+		}
+	}
+
+	if !b.ensureConductorRunning(ctx, meta) {
+		b.log.Error("heartbeat_conductor_not_running", slog.String("conductor", name))
+		return
+	}
+	title := session.ConductorSessionTitle(name)
+	if status := b.sessionStatus(ctx, title, profile); isBusy(status) {
+		// Heartbeats are periodic; no point queueing one.
+		b.log.Info("heartbeat_skipped_busy", slog.String("conductor", name), slog.String("status", status))
+		return
+	}
+	response, err := b.sendAndWait(ctx, title, profile, msg)
+	if err != nil {
+		b.log.Error("heartbeat_send_failed", slog.String("conductor", name), slog.String("error", err.Error()))
+		return
+	}
+	b.log.Info("heartbeat_response", slog.String("conductor", name), slog.String("response", truncate(response, 200)))
+
+	alerts, retired, counts := filterNeedLines(response, needs[name], needRetireThreshold)
+	needs[name] = counts
+	if len(retired) > 0 {
+		b.log.Info("heartbeat_need_retired", slog.String("conductor", name), slog.Any("lines", retired))
+	}
+	forwarded := append(alerts, retired...)
+	if len(forwarded) > 0 {
+		prefix := ""
+		if tagged {
+			prefix = "[" + name + "] "
+		}
+		b.alert(ctx, prefix+"Conductor alert:\n"+strings.Join(forwarded, "\n"))
+	}
+
+	b.runHook(ctx, profile, "post-heartbeat", map[string]any{
+		"profile":    profile,
+		"response":   response,
+		"has_alerts": len(forwarded) > 0,
+	})
+}
+
+// heartbeatMessage is the check-in text: status counts, scope, the sessions
+// needing attention and the conductor's HEARTBEAT_RULES.md.
+func (b *Bridge) heartbeatMessage(meta session.ConductorMeta, scope []string, scoped []listedSession, c statusCounts) string {
+	parts := []string{fmt.Sprintf("[HEARTBEAT] [%s] Status: %d waiting, %d running, %d idle, %d error, %d stopped.",
+		meta.Name, c.Waiting, c.Running, c.Idle, c.Error, c.Stopped)}
+	if strings.TrimSpace(meta.Scope) != "" {
+		parts = append(parts, fmt.Sprintf("Scope: %s (subgroups included); leave all other sessions alone.", strings.Join(scope, ", ")))
+	}
+	var waiting, errored []string
+	for _, s := range scoped {
+		detail := fmt.Sprintf("%s (project: %s)", s.Title, s.Path)
+		switch s.Status {
+		case "waiting":
+			waiting = append(waiting, detail)
+		case "error":
+			errored = append(errored, detail)
+		}
+	}
+	if len(waiting) > 0 {
+		parts = append(parts, "Waiting sessions: "+strings.Join(waiting, ", ")+".")
+	}
+	if len(errored) > 0 {
+		parts = append(parts, "Error sessions: "+strings.Join(errored, ", ")+".")
+	}
+	if rules := b.heartbeatRules(meta); rules != "" {
+		parts = append(parts, "\n\n"+rules)
+	} else {
+		parts = append(parts, "Check if any need auto-response or user attention.")
+	}
+	return strings.Join(parts, " ")
+}
+
+// heartbeatRules reads HEARTBEAT_RULES.md for the conductor, then its
+// profile, then the shared copy.
+func (b *Bridge) heartbeatRules(meta session.ConductorMeta) string {
+	for _, p := range []string{
+		filepath.Join(b.conductorDir, meta.Name, "HEARTBEAT_RULES.md"),
+		filepath.Join(b.conductorDir, meta.Profile, "HEARTBEAT_RULES.md"),
+		filepath.Join(b.conductorDir, "HEARTBEAT_RULES.md"),
+	} {
+		data, err := os.ReadFile(p)
+		if err == nil {
+			return strings.TrimSpace(string(data))
+		}
+		if !os.IsNotExist(err) {
+			b.log.Warn("heartbeat_rules_unreadable", slog.String("path", p), slog.String("error", err.Error()))
+			return ""
+		}
+	}
+	return ""
+}
+
+// notify sends an unprompted message to the Telegram user and the Slack
+// channel.
+func (b *Bridge) notify(ctx context.Context, text string) {
+	if b.telegram != nil {
+		if err := b.telegram.send(ctx, b.cfg.Telegram.UserID, text); err != nil {
+			b.log.Error("telegram_notify_failed", slog.String("error", err.Error()))
+		}
+	}
+	if b.slack != nil {
+		if err := b.slack.post(ctx, b.cfg.Slack.ChannelID, "", text); err != nil {
+			b.log.Error("slack_notify_failed", slog.String("error", err.Error()))
+		}
+	}
+}
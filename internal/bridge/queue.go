@@ -0,0 +1,176 @@
+package bridge
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// maxQueueDepth bounds the messages held for one busy conductor; the oldest
+// is dropped (and its sender told) when it overflows.
+const maxQueueDepth = 20
+
+// queuedMessage is a message waiting for its conductor to go idle.
+type queuedMessage struct {
+	text  string
+	reply func(string)
+}
+
+type conductorQueue struct {
+	profile string
+	items   []queuedMessage
+}
+
+// enqueue holds text for a busy conductor session and starts the drain if it
+// isn't running. reply receives the conductor's answer once delivered.
+func (b *Bridge) enqueue(ctx context.Context, title, profile, text string, reply func(string)) {
+	b.mu.Lock()
+	q := b.queues[title]
+	if q == nil {
+		q = &conductorQueue{profile: profile}
+		b.queues[title] = q
+	}
+	if len(q.items) >= maxQueueDepth {
+		b.log.Warn("queue_full", slog.String("session", title), slog.Int("depth", maxQueueDepth))
+		dropped := q.items[0]
+		q.items = q.items[1:]
+		go dropped.reply("[Message dropped — conductor queue overflow.]")
+	}
+	q.items = append(q.items, queuedMessage{text: text, reply: reply})
+	b.log.Info("message_queued", slog.String("session", title), slog.Int("depth", len(q.items)))
+	start := !b.draining
+	b.draining = true
+	b.mu.Unlock()
+
+	if start {
+		go b.drain(ctx)
+	}
+}
+
+// drain delivers queued messages, oldest first, as each conductor leaves the
+// busy state, and returns once every queue is empty.
+func (b *Bridge) drain(ctx context.Context) {
+	b.log.Info("queue_drain_started")
+	for {
+		select {
+		case <-ctx.Done():
+			b.mu.Lock()
+			b.draining = false
+			b.mu.Unlock()
+			return
+		case <-time.After(b.pollInterval):
+		}
+
+		b.mu.Lock()
+		titles := make([]string, 0, len(b.queues))
+		for title := range b.queues {
+			titles = append(titles, title)
+		}
+		b.mu.Unlock()
+
+		for _, title := range titles {
+			b.drainOne(ctx, title)
+		}
+
+		b.mu.Lock()
+		if len(b.queues) == 0 {
+			b.draining = false
+			b.mu.Unlock()
+			b.log.Info("queue_drain_finished")
+			return
+		}
+		b.mu.Unlock()
+	}
+}
+
+// drainOne tries to deliver the head of one conductor's queue.
+func (b *Bridge) drainOne(ctx context.Context, title string) {
+	b.mu.Lock()
+	q := b.queues[title]
+	if q == nil || len(q.items) == 0 {
+		delete(b.queues, title)
+		b.mu.Unlock()
+		return
+	}
+	head, profile := q.items[0], q.profile
+	b.mu.Unlock()
+
+	status := b.sessionStatus(ctx, title, profile)
+	if isBusy(status) || status == statusUnknown {
+		return
+	}
+	if status == statusError {
+		b.mu.Lock()
+		dropped := b.queues[title].items
+		delete(b.queues, title)
+		b.mu.Unlock()
+		b.log.Error("queue_dropped_conductor_error", slog.String("session", title), slog.Int("count", len(dropped)))
+		for _, m := range dropped {
+			go m.reply("[Queued message could not be delivered — conductor is in error state.]")
+		}
+		return
+	}
+
+	response, err := b.sendAndWait(ctx, title, profile, head.text)
+	switch {
+	case err == nil:
+		b.popHead(title)
+		if response == "" {
+			response = "[No output from conductor.]"
+		}
+		go head.reply(response)
+	case err == errStillRunning:
+		// Delivered; the reply is still coming.
+		b.popHead(title)
+		go b.watchPending(ctx, title, profile, head.reply)
+	case isBusySendError(err):
+		b.log.Info("queue_conductor_busy_again", slog.String("session", title))
+	default:
+		b.log.Error("queue_delivery_failed", slog.String("session", title), slog.String("error", err.Error()))
+		b.popHead(title)
+		msg := err.Error()
+		if len(msg) > 100 {
+			msg = msg[:100]
+		}
+		go head.reply("[Queued message could not be delivered — send failed: " + msg + "]")
+	}
+}
+
+func (b *Bridge) popHead(title string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	q := b.queues[title]
+	if q == nil {
+		return
+	}
+	q.items = q.items[1:]
+	if len(q.items) == 0 {
+		delete(b.queues, title)
+	}
+}
+
+// watchPending waits for an in-flight turn (one whose blocking send timed out
+// after delivery) to finish and hands its output to reply. It never re-sends:
+// that would make the conductor process the message twice.
+func (b *Bridge) watchPending(ctx context.Context, title, profile string, reply func(string)) {
+	deadline := time.Now().Add(b.pendingMaxWait)
+	for time.Now().Before(deadline) {
+		status := b.sessionStatus(ctx, title, profile)
+		if !isBusy(status) && status != statusUnknown {
+			out := b.sessionOutput(ctx, title, profile)
+			if out == "" {
+				out = "[No output from conductor.]"
+			}
+			reply(out)
+			b.log.Info("pending_reply_delivered", slog.String("session", title))
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(b.pollInterval):
+		}
+	}
+	b.log.Warn("pending_reply_gave_up", slog.String("session", title), slog.Duration("waited", b.pendingMaxWait))
+	reply("[Conductor is still working after a long time — reply not captured. Check the session directly.]")
+}
@@ -0,0 +1,202 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// message is one chat message addressed to the bridge, already authorized
+// and stripped of any bot mention.
+type message struct {
+	text   string
+	userID string // passed to the pre-message hook
+	// context is prepended to what the conductor sees, e.g.
+	// "[from:ana (U123)] [channel:#ops (C456)]".
+	context string
+	// reply posts markdown text back where the message came from.
+	reply func(string)
+}
+
+// handleMessage routes a message to its conductor and replies with the
+// answer. A busy conductor gets the message queued; a turn that outlasts
+// responseTimeout is answered later in the same place.
+func (b *Bridge) handleMessage(ctx context.Context, m message) {
+	metas := b.conductors()
+	name, text := parseConductorPrefix(m.text, conductorNames(metas))
+	target := pickConductor(metas, name)
+	if target == nil {
+		m.reply("[No conductors configured. Run: agent-deck conductor setup <name>]")
+		return
+	}
+	if text == "" {
+		text = m.text
+	}
+	profile := target.Profile
+
+	if ran, ok, out := b.runHook(ctx, profile, "pre-message", map[string]any{
+		"profile":      profile,
+		"message_text": text,
+		"user_id":      m.userID,
+	}); ran {
+		if !ok {
+			b.log.Info("message_gated_by_hook", slog.String("conductor", target.Name))
+			return
+		}
+		if out != "" {
+			text = out
+		}
+	}
+	if m.context != "" {
+		text = m.context + " " + text
+	}
+
+	if !b.ensureConductorRunning(ctx, *target) {
+		m.reply(fmt.Sprintf("[Could not start conductor %s. Check agent-deck.]", target.Name))
+		return
+	}
+
+	title := session.ConductorSessionTitle(target.Name)
+	tag := conductorTag(metas, target.Name)
+	b.log.Info("message_routed", slog.String("conductor", target.Name), slog.String("text", truncate(text, 100)))
+
+	if isBusy(b.sessionStatus(ctx, title, profile)) {
+		b.enqueue(ctx, title, profile, text, lateReply(tag, time.Now(), m.reply))
+		m.reply(tag + "⏳ Conductor busy — message queued, will reply here when done.")
+		return
+	}
+
+	m.reply(tag + "⏳")
+	started := time.Now()
+	response, err := b.sendAndWait(ctx, title, profile, text)
+	if err == errStillRunning {
+		go b.watchPending(ctx, title, profile, lateReply(tag, started, m.reply))
+		m.reply(tag + "⏳ Still working — will reply here when done.")
+		return
+	}
+	if err != nil {
+		b.log.Error("send_failed", slog.String("conductor", target.Name), slog.String("error", err.Error()))
+		m.reply(fmt.Sprintf("[Failed to send message to conductor %s.]", target.Name))
+		return
+	}
+	b.log.Info("conductor_replied", slog.String("conductor", target.Name), slog.String("response", truncate(response, 100)))
+	m.reply(tag + response)
+
+	b.runHook(ctx, profile, "post-message", map[string]any{
+		"profile":      profile,
+		"message_text": text,
+		"response":     response,
+	})
+}
+
+// lateReply wraps reply for an answer that arrives after the handler
+// returned, headed with how long it waited.
+func lateReply(tag string, since time.Time, reply func(string)) func(string) {
+	return func(text string) {
+		waited := formatWaited(int(time.Since(since).Seconds()))
+		reply(fmt.Sprintf("%sQueued response (waited %s):\n%s", tag, waited, text))
+	}
+}
+
+// statusText is /status: session counts across every conductor profile,
+// broken down per profile when there is more than one.
+func (b *Bridge) statusText(ctx context.Context) string {
+	profiles := conductorProfiles(b.conductors())
+	var total statusCounts
+	per := make([]statusCounts, len(profiles))
+	for i, p := range profiles {
+		per[i] = b.statusSummary(ctx, p)
+		total.add(per[i])
+	}
+	lines := []string{
+		fmt.Sprintf("Total: %d sessions", total.Total),
+		fmt.Sprintf("  Running: %d", total.Running),
+		fmt.Sprintf("  Waiting: %d", total.Waiting),
+		fmt.Sprintf("  Idle: %d", total.Idle),
+		fmt.Sprintf("  Error: %d", total.Error),
+	}
+	if len(profiles) > 1 {
+		lines = append(lines, "")
+		for i, p := range profiles {
+			c := per[i]
+			lines = append(lines, fmt.Sprintf("[%s] %ds (%dR %dW %dI %dE)", p, c.Total, c.Running, c.Waiting, c.Idle, c.Error))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+var sessionIcons = map[string]string{
+	"running": "🟢",
+	"waiting": "🟡",
+	"idle":    "⚪",
+	"error":   "🔴",
+}
+
+// sessionsText is /sessions: every session in every conductor profile.
+func (b *Bridge) sessionsText(ctx context.Context) string {
+	profiles := conductorProfiles(b.conductors())
+	var lines []string
+	for _, p := range profiles {
+		sessions, _ := b.listSessions(ctx, p)
+		for _, s := range sessions {
+			icon, ok := sessionIcons[s.Status]
+			if !ok {
+				icon = "❓"
+			}
+			prefix := ""
+			if len(profiles) > 1 {
+				prefix = "[" + p + "] "
+			}
+			lines = append(lines, fmt.Sprintf("%s %s%s (%s)", icon, prefix, s.Title, s.Tool))
+		}
+	}
+	if len(lines) == 0 {
+		return "No sessions found."
+	}
+	return strings.Join(lines, "\n")
+}
+
+// restart is /restart [name]: restarts the named (or default) conductor,
+// reporting progress through reply.
+func (b *Bridge) restart(ctx context.Context, name string, reply func(string)) {
+	target := pickConductor(b.conductors(), strings.TrimSpace(name))
+	if target == nil {
+		reply("No conductors found.")
+		return
+	}
+	reply(fmt.Sprintf("Restarting conductor %s...", target.Name))
+	_, stderr, err := b.cli.Run(ctx, target.Profile, 60*time.Second, "session", "restart", session.ConductorSessionTitle(target.Name))
+	if err != nil {
+		reply("Restart failed: " + strings.TrimSpace(stderr))
+		return
+	}
+	reply(fmt.Sprintf("Conductor %s restarted.", target.Name))
+}
+
+// helpText lists the platform's commands (cmdPrefix "/" or "/ad-").
+func (b *Bridge) helpText(cmdPrefix string) string {
+	names := conductorNames(b.conductors())
+	list := "none"
+	if len(names) > 0 {
+		list = strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("Conductor Commands:\n"+
+		"%[1]sstatus    - Aggregated status across all profiles\n"+
+		"%[1]ssessions  - List all sessions (all profiles)\n"+
+		"%[1]srestart   - Restart a conductor (specify name)\n"+
+		"%[1]shelp      - This message\n\n"+
+		"Conductors: %[2]s\n"+
+		"Route: <name>: <message>\n"+
+		"Default: messages go to first conductor", cmdPrefix, list)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
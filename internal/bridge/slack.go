@@ -0,0 +1,376 @@
+package bridge
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// slackAPI is the Web API base URL.
+const slackAPI = "https://slack.com/api"
+
+// Socket Mode keepalive: ping every slackPingInterval and give up on the
+// connection when nothing arrives for slackReadTimeout.
+const (
+	slackPingInterval = 30 * time.Second
+	slackReadTimeout  = 90 * time.Second
+)
+
+// slackNegativeTTL is how long a failed user/channel name lookup is cached.
+const slackNegativeTTL = 5 * time.Minute
+
+// slackApp holds a Socket Mode connection. It answers @mentions anywhere the
+// app is, every message in the configured channel when listen_mode is "all",
+// and the /ad-* slash commands.
+type slackApp struct {
+	cfg     SlackConfig
+	b       *Bridge
+	apiBase string
+	http    *http.Client
+
+	writeMu sync.Mutex
+
+	cacheMu sync.Mutex
+	names   map[string]cachedName
+}
+
+type cachedName struct {
+	value   string
+	expires time.Time // zero: never
+}
+
+func newSlackApp(cfg SlackConfig, b *Bridge) *slackApp {
+	return &slackApp{
+		cfg:     cfg,
+		b:       b,
+		apiBase: slackAPI,
+		http:    &http.Client{Timeout: 30 * time.Second},
+		names:   map[string]cachedName{},
+	}
+}
+
+// call invokes a Web API method with token and decodes the response into
+// out. GET methods take form params; others are sent as JSON.
+func (s *slackApp) call(ctx context.Context, token, method string, params map[string]any, out any) error {
+	var req *http.Request
+	var err error
+	if method == "users.info" || method == "conversations.info" {
+		q := url.Values{}
+		for k, v := range params {
+			q.Set(k, fmt.Sprint(v))
+		}
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, s.apiBase+"/"+method+"?"+q.Encode(), nil)
+	} else {
+		body, _ := json.Marshal(params)
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, s.apiBase+"/"+method, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	}
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("slack %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+	var raw json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return fmt.Errorf("slack %s: %s", method, resp.Status)
+	}
+	var env struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return fmt.Errorf("slack %s: %w", method, err)
+	}
+	if !env.OK {
+		return fmt.Errorf("slack %s: %s", method, env.Error)
+	}
+	if out != nil {
+		return json.Unmarshal(raw, out)
+	}
+	return nil
+}
+
+// post sends markdown text to a channel, in thread when threadTS is set.
+func (s *slackApp) post(ctx context.Context, channel, threadTS, text string) error {
+	for _, chunk := range splitMessage(markdownToSlack(text), slackMaxLength) {
+		params := map[string]any{"channel": channel, "text": chunk}
+		if threadTS != "" {
+			params["thread_ts"] = threadTS
+		}
+		if err := s.call(ctx, s.cfg.BotToken, "chat.postMessage", params, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// run opens a Socket Mode connection and serves it until it drops. A
+// disconnect request from Slack returns nil so the caller reconnects at once.
+func (s *slackApp) run(ctx context.Context) error {
+	var opened struct {
+		URL string `json:"url"`
+	}
+	if err := s.call(ctx, s.cfg.AppToken, "apps.connections.open", map[string]any{}, &opened); err != nil {
+		return err
+	}
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, opened.URL, nil)
+	if err != nil {
+		return fmt.Errorf("slack socket: %w", err)
+	}
+	defer conn.Close()
+	s.b.log.Info("slack_connected", slog.String("channel", s.cfg.ChannelID), slog.String("listen_mode", s.cfg.ListenMode))
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(slackPingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				_ = conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+				_ = conn.Close()
+				return
+			case <-done:
+				return
+			case <-ticker.C:
+				_ = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(10*time.Second))
+			}
+		}
+	}()
+	extend := func(string) error { return conn.SetReadDeadline(time.Now().Add(slackReadTimeout)) }
+	conn.SetPongHandler(extend)
+	_ = extend("")
+
+	for {
+		var env struct {
+			Type       string          `json:"type"`
+			EnvelopeID string          `json:"envelope_id"`
+			Reason     string          `json:"reason"`
+			Payload    json.RawMessage `json:"payload"`
+		}
+		if err := conn.ReadJSON(&env); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("slack socket: %w", err)
+		}
+		_ = extend("")
+		if env.EnvelopeID != "" {
+			s.writeMu.Lock()
+			err := conn.WriteJSON(map[string]string{"envelope_id": env.EnvelopeID})
+			s.writeMu.Unlock()
+			if err != nil {
+				return fmt.Errorf("slack ack: %w", err)
+			}
+		}
+		switch env.Type {
+		case "disconnect":
+			s.b.log.Info("slack_disconnect_requested", slog.String("reason", env.Reason))
+			return nil
+		case "events_api":
+			var p struct {
+				Event slackEvent `json:"event"`
+			}
+			if json.Unmarshal(env.Payload, &p) == nil {
+				go s.handleEvent(ctx, p.Event)
+			}
+		case "slash_commands":
+			var c slackCommand
+			if json.Unmarshal(env.Payload, &c) == nil {
+				go s.handleCommand(ctx, c)
+			}
+		}
+	}
+}
+
+type slackEvent struct {
+	Type     string `json:"type"`
+	Subtype  string `json:"subtype"`
+	BotID    string `json:"bot_id"`
+	User     string `json:"user"`
+	Channel  string `json:"channel"`
+	Text     string `json:"text"`
+	TS       string `json:"ts"`
+	ThreadTS string `json:"thread_ts"`
+}
+
+type slackCommand struct {
+	Command     string `json:"command"`
+	Text        string `json:"text"`
+	UserID      string `json:"user_id"`
+	ResponseURL string `json:"response_url"`
+}
+
+// authorized reports whether user may use the bridge; an empty
+// allowed_user_ids lets everyone in.
+func (s *slackApp) authorized(user string) bool {
+	if len(s.cfg.AllowedUserIDs) == 0 || slices.Contains(s.cfg.AllowedUserIDs, user) {
+		return true
+	}
+	s.b.log.Warn("slack_unauthorized", slog.String("user", user))
+	return false
+}
+
+func (s *slackApp) handleEvent(ctx context.Context, ev slackEvent) {
+	switch ev.Type {
+	case "message":
+		if s.cfg.ListenMode != "all" || ev.BotID != "" || ev.Subtype != "" || ev.Channel != s.cfg.ChannelID {
+			return
+		}
+	case "app_mention":
+	default:
+		return
+	}
+	if !s.authorized(ev.User) {
+		return
+	}
+	text := strings.TrimSpace(slackMention.ReplaceAllString(ev.Text, ""))
+	if text == "" {
+		return
+	}
+	thread := ev.ThreadTS
+	if thread == "" {
+		thread = ev.TS
+	}
+	s.b.handleMessage(ctx, message{
+		text:    text,
+		userID:  ev.User,
+		context: s.senderContext(ctx, ev.User, ev.Channel),
+		reply: func(text string) {
+			if err := s.post(ctx, ev.Channel, thread, text); err != nil {
+				s.b.log.Error("slack_post_failed", slog.String("error", err.Error()))
+			}
+		},
+	})
+}
+
+// senderContext tells the conductor who wrote and where:
+// "[from:name (U123)] [channel:#name (C456)]" or "... [dm]".
+func (s *slackApp) senderContext(ctx context.Context, user, channel string) string {
+	var parts []string
+	if user != "" {
+		parts = append(parts, fmt.Sprintf("[from:%s (%s)]", s.userName(ctx, user), user))
+	}
+	if channel != "" {
+		parts = append(parts, s.channelTag(ctx, channel))
+	}
+	return strings.Join(parts, " ")
+}
+
+func (s *slackApp) userName(ctx context.Context, user string) string {
+	return s.cached(ctx, "u:"+user, func() (string, error) {
+		var r struct {
+			User struct {
+				Profile struct {
+					DisplayName string `json:"display_name"`
+					RealName    string `json:"real_name"`
+				} `json:"profile"`
+			} `json:"user"`
+		}
+		if err := s.call(ctx, s.cfg.BotToken, "users.info", map[string]any{"user": user}, &r); err != nil {
+			return user, err
+		}
+		return cmp.Or(r.User.Profile.DisplayName, r.User.Profile.RealName, user), nil
+	})
+}
+
+func (s *slackApp) channelTag(ctx context.Context, channel string) string {
+	return s.cached(ctx, "c:"+channel, func() (string, error) {
+		var r struct {
+			Channel struct {
+				Name string `json:"name"`
+				IsIM bool   `json:"is_im"`
+			} `json:"channel"`
+		}
+		if err := s.call(ctx, s.cfg.BotToken, "conversations.info", map[string]any{"channel": channel}, &r); err != nil {
+			return fmt.Sprintf("[channel:%s]", channel), err
+		}
+		if r.Channel.IsIM {
+			return "[dm]", nil
+		}
+		return fmt.Sprintf("[channel:#%s (%s)]", cmp.Or(r.Channel.Name, channel), channel), nil
+	})
+}
+
+// cached memoizes a name lookup: hits forever, misses for slackNegativeTTL.
+func (s *slackApp) cached(ctx context.Context, key string, lookup func() (string, error)) string {
+	s.cacheMu.Lock()
+	if c, ok := s.names[key]; ok && (c.expires.IsZero() || time.Now().Before(c.expires)) {
+		s.cacheMu.Unlock()
+		return c.value
+	}
+	s.cacheMu.Unlock()
+
+	value, err := lookup()
+	entry := cachedName{value: value}
+	if err != nil {
+		s.b.log.Warn("slack_lookup_failed", slog.String("key", key), slog.String("error", err.Error()))
+		entry.expires = time.Now().Add(slackNegativeTTL)
+	}
+	s.cacheMu.Lock()
+	s.names[key] = entry
+	s.cacheMu.Unlock()
+	return value
+}
+
+func (s *slackApp) handleCommand(ctx context.Context, c slackCommand) {
+	respond := func(text string) {
+		if err := s.respond(ctx, c.ResponseURL, text); err != nil {
+			s.b.log.Error("slack_respond_failed", slog.String("command", c.Command), slog.String("error", err.Error()))
+		}
+	}
+	if !s.authorized(c.UserID) {
+		respond("⛔ Unauthorized. Contact your administrator.")
+		return
+	}
+	switch c.Command {
+	case "/ad-status":
+		respond(s.b.statusText(ctx))
+	case "/ad-sessions":
+		respond(s.b.sessionsText(ctx))
+	case "/ad-restart":
+		s.b.restart(ctx, c.Text, respond)
+	case "/ad-help":
+		respond(s.b.helpText("/ad-"))
+	}
+}
+
+// respond answers a slash command through its response_url.
+func (s *slackApp) respond(ctx context.Context, responseURL, text string) error {
+	if responseURL == "" {
+		return errors.New("no response_url")
+	}
+	body, _ := json.Marshal(map[string]string{"text": markdownToSlack(text)})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("response_url: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package bridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// telegramAPI is the Bot API base URL.
+const telegramAPI = "https://api.telegram.org"
+
+// telegramPollTimeout is the getUpdates long-poll timeout in seconds.
+const telegramPollTimeout = 50
+
+// telegramBot long-polls the Bot API. Only the configured user may talk to
+// it; in groups it answers only when mentioned or replied to.
+type telegramBot struct {
+	cfg     TelegramConfig
+	b       *Bridge
+	apiBase string
+	http    *http.Client
+
+	username string // lowercased, from getMe
+}
+
+func newTelegramBot(cfg TelegramConfig, b *Bridge) *telegramBot {
+	return &telegramBot{
+		cfg:     cfg,
+		b:       b,
+		apiBase: telegramAPI,
+		// Proxies come from HTTPS_PROXY/HTTP_PROXY via the default transport.
+		http: &http.Client{Timeout: (telegramPollTimeout + 15) * time.Second},
+	}
+}
+
+type tgUser struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+type tgMessage struct {
+	MessageID int64  `json:"message_id"`
+	From      tgUser `json:"from"`
+	Chat      struct {
+		ID   int64  `json:"id"`
+		Type string `json:"type"`
+	} `json:"chat"`
+	Text     string `json:"text"`
+	Entities []struct {
+		Type   string `json:"type"`
+		Offset int    `json:"offset"`
+		Length int    `json:"length"`
+	} `json:"entities"`
+	ReplyTo *struct {
+		From tgUser `json:"from"`
+	} `json:"reply_to_message"`
+}
+
+type tgUpdate struct {
+	UpdateID int64      `json:"update_id"`
+	Message  *tgMessage `json:"message"`
+}
+
+// call invokes a Bot API method and decodes its result into out.
+func (t *telegramBot) call(ctx context.Context, method string, params, out any) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		fmt.Sprintf("%s/bot%s/%s", t.apiBase, t.cfg.Token, method), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := t.http.Do(req)
+	if err != nil {
+		// The URL holds the token; keep it out of logs.
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return fmt.Errorf("telegram %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+	var env struct {
+		OK          bool            `json:"ok"`
+		Description string          `json:"description"`
+		Result      json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		return fmt.Errorf("telegram %s: %s", method, resp.Status)
+	}
+	if !env.OK {
+		return fmt.Errorf("telegram %s: %s", method, env.Description)
+	}
+	if out != nil {
+		return json.Unmarshal(env.Result, out)
+	}
+	return nil
+}
+
+// run polls for updates until ctx ends or the API fails.
+func (t *telegramBot) run(ctx context.Context) error {
+	var me tgUser
+	if err := t.call(ctx, "getMe", map[string]any{}, &me); err != nil {
+		return err
+	}
+	t.username = strings.ToLower(me.Username)
+	t.b.log.Info("telegram_connected", slog.String("bot", "@"+t.username), slog.Int64("user_id", t.cfg.UserID))
+
+	var offset int64
+	for ctx.Err() == nil {
+		var updates []tgUpdate
+		err := t.call(ctx, "getUpdates", map[string]any{
+			"offset":          offset,
+			"timeout":         telegramPollTimeout,
+			"allowed_updates": []string{"message"},
+		}, &updates)
+		if err != nil {
+			return err
+		}
+		for _, u := range updates {
+			offset = u.UpdateID + 1
+			if u.Message != nil {
+				go t.handle(ctx, *u.Message)
+			}
+		}
+	}
+	return nil
+}
+
+// send posts markdown text to a chat as Telegram HTML, split to fit.
+func (t *telegramBot) send(ctx context.Context, chatID int64, text string) error {
+	for _, chunk := range splitMessage(markdownToTelegramHTML(text), telegramMaxLength) {
+		if err := t.call(ctx, "sendMessage", map[string]any{
+			"chat_id":    chatID,
+			"text":       chunk,
+			"parse_mode": "HTML",
+		}, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handle answers one message.
+func (t *telegramBot) handle(ctx context.Context, msg tgMessage) {
+	if msg.From.ID != t.cfg.UserID {
+		t.b.log.Warn("telegram_unauthorized", slog.Int64("user_id", msg.From.ID))
+		return
+	}
+	if msg.Text == "" || !t.addressed(msg) {
+		return
+	}
+	reply := func(text string) {
+		if err := t.send(ctx, msg.Chat.ID, text); err != nil {
+			t.b.log.Error("telegram_send_failed", slog.String("error", err.Error()))
+		}
+	}
+
+	if cmd, arg, ok := t.command(msg.Text); ok {
+		switch cmd {
+		case "start":
+			names := conductorNames(t.b.conductors())
+			list, def := "none", "none"
+			if len(names) > 0 {
+				list, def = strings.Join(names, ", "), names[0]
+			}
+			reply(fmt.Sprintf("Conductor bridge active.\nConductors: %s\nCommands: /status /sessions /help /restart\n"+
+				"Route to conductor: <name>: <message>\nDefault conductor: %s", list, def))
+		case "status":
+			reply(t.b.statusText(ctx))
+		case "sessions":
+			reply(t.b.sessionsText(ctx))
+		case "help":
+			reply(t.b.helpText("/"))
+		case "restart":
+			t.b.restart(ctx, arg, reply)
+		default:
+			t.route(ctx, msg, reply)
+		}
+		return
+	}
+	t.route(ctx, msg, reply)
+}
+
+func (t *telegramBot) route(ctx context.Context, msg tgMessage, reply func(string)) {
+	text := t.stripMention(msg.Text)
+	if text == "" {
+		return
+	}
+	t.b.handleMessage(ctx, message{
+		text:   text,
+		userID: fmt.Sprint(msg.From.ID),
+		reply:  reply,
+	})
+}
+
+// addressed reports whether the bot should answer: always in a private chat,
+// otherwise only when replied to or @mentioned.
+func (t *telegramBot) addressed(msg tgMessage) bool {
+	if msg.Chat.Type == "private" {
+		return true
+	}
+	if msg.ReplyTo != nil && strings.EqualFold(msg.ReplyTo.From.Username, t.username) {
+		return true
+	}
+	units := utf16.Encode([]rune(msg.Text))
+	for _, e := range msg.Entities {
+		if e.Type != "mention" || e.Offset < 0 || e.Offset+e.Length > len(units) {
+			continue
+		}
+		mention := string(utf16.Decode(units[e.Offset : e.Offset+e.Length]))
+		if strings.EqualFold(mention, "@"+t.username) {
+			return true
+		}
+	}
+	return false
+}
+
+// command splits "/name[@bot] arg" into name and arg. Commands addressed to
+// another bot are not ours.
+func (t *telegramBot) command(text string) (name, arg string, ok bool) {
+	if !strings.HasPrefix(text, "/") {
+		return "", "", false
+	}
+	head, arg, _ := strings.Cut(text[1:], " ")
+	name, bot, hasBot := strings.Cut(head, "@")
+	if hasBot && !strings.EqualFold(bot, t.username) {
+		return "", "", false
+	}
+	return strings.ToLower(name), strings.TrimSpace(arg), true
+}
+
+// stripMention removes "@botname" from text.
+func (t *telegramBot) stripMention(text string) string {
+	if t.username == "" {
+		return strings.TrimSpace(text)
+	}
+	re := regexp.MustCompile(`(?i)@` + regexp.QuoteMeta(t.username) + `\b`)
+	return strings.TrimSpace(re.ReplaceAllString(text, ""))
+}
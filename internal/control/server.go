@@ -0,0 +1,287 @@
+// Package control serves agent-deck's JSON-RPC 2.0 control socket.
+//
+// A running TUI (or `agent-deck web --no-tui`) listens on a Unix-domain
+// socket in the profile directory so editors and scripts can list, create,
+// start and message sessions without spawning the binary for every call.
+// Messages are newline-delimited JSON-RPC 2.0 objects; one connection may
+// carry any number of requests, answered in order.
+//
+// The package is transport only: the methods are registered by the caller
+// (see cmd/agent-deck/control_cmd.go).
+package control
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/logging"
+)
+
+var controlLog = logging.ForComponent(logging.CompControl)
+
+// JSON-RPC 2.0 error codes. The -320xx range is reserved by the spec for
+// implementation-defined server errors.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+	CodeFailed         = -32000 // the operation ran and failed
+	CodeNotFound       = -32001 // the session does not exist
+	CodeAmbiguous      = -32002 // the identifier matches several sessions
+)
+
+// maxRequestBytes caps one request line.
+const maxRequestBytes = 1 << 20
+
+// Error is a JSON-RPC error object. Handlers return it to choose the code;
+// any other error is reported as CodeFailed.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// Errorf builds an *Error with the given code.
+func Errorf(code int, format string, args ...any) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// HandlerFunc handles one method call. params is the raw "params" member
+// (nil when absent); the result is marshaled into the response.
+type HandlerFunc func(params json.RawMessage) (any, error)
+
+// Request is a JSON-RPC 2.0 request. A request without an id is a
+// notification: it runs but gets no response.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is a JSON-RPC 2.0 response.
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Server accepts control connections on a Unix-domain socket.
+type Server struct {
+	path    string
+	methods map[string]HandlerFunc
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	closed   bool
+	wg       sync.WaitGroup
+}
+
+// NewServer returns a server for socketPath dispatching to methods.
+func NewServer(socketPath string, methods map[string]HandlerFunc) *Server {
+	return &Server{
+		path:    socketPath,
+		methods: methods,
+		conns:   make(map[net.Conn]struct{}),
+	}
+}
+
+// Path returns the socket path.
+func (s *Server) Path() string { return s.path }
+
+// ErrSocketInUse is returned by Start when another live process already
+// serves the socket.
+var ErrSocketInUse = errors.New("control socket already served by another process")
+
+// Start listens on the socket and serves connections in the background. A
+// socket file left behind by a dead process is replaced; a live one is not.
+func (s *Server) Start() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("control: mkdir: %w", err)
+	}
+	if _, err := os.Stat(s.path); err == nil {
+		if conn, err := net.DialTimeout("unix", s.path, 500*time.Millisecond); err == nil {
+			_ = conn.Close()
+			return ErrSocketInUse
+		}
+		// Nobody is listening: stale socket from a crashed process.
+		_ = os.Remove(s.path)
+	}
+
+	ln, err := net.Listen("unix", s.path)
+	if err != nil {
+		return fmt.Errorf("control: listen: %w", err)
+	}
+	// Owner-only: the socket can create sessions and type into panes.
+	_ = os.Chmod(s.path, 0600)
+
+	s.mu.Lock()
+	s.listener = ln
+	s.mu.Unlock()
+
+	controlLog.Info("control_listening", slog.String("path", s.path))
+	s.wg.Add(1)
+	go s.acceptLoop(ln)
+	return nil
+}
+
+// Close stops accepting, closes open connections and removes the socket.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	ln := s.listener
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+
+	var err error
+	if ln != nil {
+		err = ln.Close()
+		_ = os.Remove(s.path)
+	}
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) acceptLoop(ln net.Listener) {
+	defer s.wg.Done()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if !closed {
+				controlLog.Warn("control_accept_failed", slog.String("error", err.Error()))
+			}
+			return
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			_ = conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go s.serveConn(conn)
+	}
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		_ = conn.Close()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxRequestBytes)
+	enc := json.NewEncoder(conn)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		resp := s.handle(line)
+		if resp == nil {
+			continue
+		}
+		if err := enc.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// handle runs one request line and returns its response, or nil for a
+// notification.
+func (s *Server) handle(line []byte) *Response {
+	var req Request
+	if err := json.Unmarshal(line, &req); err != nil {
+		return &Response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: Errorf(CodeParseError, "parse error: %v", err)}
+	}
+	id := req.ID
+	if len(id) == 0 {
+		id = nil
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return &Response{JSONRPC: "2.0", ID: orNull(id), Error: Errorf(CodeInvalidRequest, "invalid request: want jsonrpc \"2.0\" and a method")}
+	}
+
+	handler, ok := s.methods[req.Method]
+	if !ok {
+		if id == nil {
+			return nil
+		}
+		return &Response{JSONRPC: "2.0", ID: id, Error: Errorf(CodeMethodNotFound, "method not found: %s", req.Method)}
+	}
+
+	result, err := s.call(handler, req.Params)
+	if id == nil {
+		return nil
+	}
+	if err != nil {
+		var rpcErr *Error
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &Error{Code: CodeFailed, Message: err.Error()}
+		}
+		return &Response{JSONRPC: "2.0", ID: id, Error: rpcErr}
+	}
+	if result == nil {
+		result = struct{}{}
+	}
+	return &Response{JSONRPC: "2.0", ID: id, Result: result}
+}
+
+// call runs a handler, turning a panic into an internal error so one bad
+// request cannot take down the TUI hosting the socket.
+func (s *Server) call(handler HandlerFunc, params json.RawMessage) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			controlLog.Error("control_handler_panic", slog.Any("panic", r))
+			result, err = nil, Errorf(CodeInternalError, "internal error: %v", r)
+		}
+	}()
+	return handler(params)
+}
+
+func orNull(id json.RawMessage) json.RawMessage {
+	if id == nil {
+		return json.RawMessage("null")
+	}
+	return id
+}
+
+// DecodeParams unmarshals params into v, reporting CodeInvalidParams on
+// failure. Absent params leave v untouched.
+func DecodeParams(params json.RawMessage, v any) error {
+	if len(params) == 0 || string(params) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return Errorf(CodeInvalidParams, "invalid params: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package control
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// socketPath returns a short socket path; t.TempDir can exceed the
+// 108-byte sun_path limit.
+func socketPath(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "ctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	return filepath.Join(dir, "c.sock")
+}
+
+func startTestServer(t *testing.T) *Server {
+	t.Helper()
+	srv := NewServer(socketPath(t), map[string]HandlerFunc{
+		"echo": func(params json.RawMessage) (any, error) {
+			var p struct {
+				Text string `json:"text"`
+			}
+			if err := DecodeParams(params, &p); err != nil {
+				return nil, err
+			}
+			return map[string]string{"text": p.Text}, nil
+		},
+		"missing": func(json.RawMessage) (any, error) {
+			return nil, Errorf(CodeNotFound, "session not found")
+		},
+		"fail": func(json.RawMessage) (any, error) {
+			return nil, errors.New("boom")
+		},
+		"panic": func(json.RawMessage) (any, error) {
+			panic("bad handler")
+		},
+	})
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { _ = srv.Close() })
+	return srv
+}
+
+func TestCallRoundTrip(t *testing.T) {
+	srv := startTestServer(t)
+
+	var got map[string]string
+	if err := Call(srv.Path(), "echo", map[string]string{"text": "hi"}, &got, time.Second); err != nil {
+		t.Fatalf("Call: %v", err)
+	}
+	if got["text"] != "hi" {
+		t.Errorf("result = %v, want text=hi", got)
+	}
+}
+
+func TestCallErrors(t *testing.T) {
+	srv := startTestServer(t)
+
+	tests := []struct {
+		method string
+		params any
+		code   int
+	}{
+		{"nope", nil, CodeMethodNotFound},
+		{"missing", nil, CodeNotFound},
+		{"fail", nil, CodeFailed},
+		{"panic", nil, CodeInternalError},
+		{"echo", []int{1}, CodeInvalidParams},
+	}
+	for _, tt := range tests {
+		err := Call(srv.Path(), tt.method, tt.params, nil, time.Second)
+		var rpcErr *Error
+		if !errors.As(err, &rpcErr) {
+			t.Errorf("%s: err = %v, want *Error", tt.method, err)
+			continue
+		}
+		if rpcErr.Code != tt.code {
+			t.Errorf("%s: code = %d, want %d", tt.method, rpcErr.Code, tt.code)
+		}
+	}
+}
+
+func TestServerConnectionProtocol(t *testing.T) {
+	srv := startTestServer(t)
+
+	conn, err := net.Dial("unix", srv.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(2 * time.Second))
+
+	// A parse error, a notification (no reply) and a request share one
+	// connection; only the first and last are answered, in order.
+	lines := []string{
+		`{not json`,
+		`{"jsonrpc":"2.0","method":"echo","params":{"text":"quiet"}}`,
+		`{"jsonrpc":"2.0","id":"a","method":"echo","params":{"text":"loud"}}`,
+	}
+	if _, err := conn.Write([]byte(strings.Join(lines, "\n") + "\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := bufio.NewReader(conn)
+	var first, second struct {
+		ID     json.RawMessage   `json:"id"`
+		Result map[string]string `json:"result"`
+		Error  *Error            `json:"error"`
+	}
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = json.Unmarshal(line, &first)
+	if first.Error == nil || first.Error.Code != CodeParseError || string(first.ID) != "null" {
+		t.Errorf("first response = %s, want parse error with null id", line)
+	}
+	line, err = reader.ReadBytes('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = json.Unmarshal(line, &second)
+	if string(second.ID) != `"a"` || second.Result["text"] != "loud" {
+		t.Errorf("second response = %s, want id \"a\" echoing loud", line)
+	}
+}
+
+func TestServerStartReplacesStaleSocket(t *testing.T) {
+	path := socketPath(t)
+	// A socket file nobody listens on, as left by a crashed process.
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln.(*net.UnixListener).SetUnlinkOnClose(false)
+	_ = ln.Close()
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("stale socket missing: %v", err)
+	}
+
+	srv := NewServer(path, map[string]HandlerFunc{})
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start over stale socket: %v", err)
+	}
+	defer srv.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("socket mode = %o, want 600", perm)
+	}
+}
+
+func TestServerStartLiveSocketInUse(t *testing.T) {
+	srv := startTestServer(t)
+
+	second := NewServer(srv.Path(), map[string]HandlerFunc{})
+	if err := second.Start(); !errors.Is(err, ErrSocketInUse) {
+		t.Fatalf("second Start = %v, want ErrSocketInUse", err)
+	}
+	// The first server must be unaffected.
+	if err := Call(srv.Path(), "echo", nil, nil, time.Second); err != nil {
+		t.Errorf("first server after rejected second: %v", err)
+	}
+
+	if err := srv.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := os.Stat(srv.Path()); !os.IsNotExist(err) {
+		t.Errorf("socket still present after Close: %v", err)
+	}
+}
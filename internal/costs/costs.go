@@ -35,6 +35,10 @@ type SessionCost struct {
 	Group            string
 	CostMicrodollars int64
 	EventCount       int
+	InputTokens      int64
+	OutputTokens     int64
+	CacheReadTokens  int64
+	CacheWriteTokens int64
 }
 
 // DailyCost represents cost for a single day.
@@ -49,6 +53,17 @@ func FormatUSD(microdollars int64) string {
 	return fmt.Sprintf("$%.2f", float64(microdollars)/1_000_000)
 }
 
+// FormatTokens formats a token count compactly: 950, 12.3K, 4.1M.
+func FormatTokens(n int64) string {
+	if n >= 1_000_000 {
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	}
+	if n >= 1_000 {
+		return fmt.Sprintf("%.1fK", float64(n)/1_000)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
 // RemoteCostSummary mirrors `agent-deck costs summary --json` output. #1101:
 // when an SSH remote is configured, the TUI fetches one of these per remote
 // and folds the totals into the local cost-line totals so the status bar
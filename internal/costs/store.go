@@ -130,6 +130,74 @@ func (s *Store) TopSessionsByCost(limit int) ([]SessionCost, error) {
 	return result, rows.Err()
 }
 
+// SessionCostsSince returns per-session totals for events at or after
+// since, most expensive first. A zero since covers all recorded events.
+func (s *Store) SessionCostsSince(since time.Time) ([]SessionCost, error) {
+	rows, err := s.db.Query(`
+		SELECT ce.session_id, COALESCE(i.title, ce.session_id), COALESCE(i.group_path, ''),
+			SUM(ce.cost_microdollars), COUNT(*),
+			SUM(ce.input_tokens), SUM(ce.output_tokens),
+			SUM(ce.cache_read_tokens), SUM(ce.cache_write_tokens)
+		FROM cost_events ce
+		LEFT JOIN instances i ON ce.session_id = i.id
+		WHERE ce.timestamp >= ?
+		GROUP BY ce.session_id
+		ORDER BY SUM(ce.cost_microdollars) DESC, ce.session_id`, since.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []SessionCost
+	for rows.Next() {
+		var sc SessionCost
+		if err := rows.Scan(&sc.SessionID, &sc.SessionTitle, &sc.Group, &sc.CostMicrodollars, &sc.EventCount,
+			&sc.InputTokens, &sc.OutputTokens, &sc.CacheReadTokens, &sc.CacheWriteTokens); err != nil {
+			return nil, err
+		}
+		result = append(result, sc)
+	}
+	return result, rows.Err()
+}
+
+// CostBySession returns the all-time cost of every session with events,
+// keyed by session ID. The TUI uses it for the session list's cost column.
+func (s *Store) CostBySession() (map[string]int64, error) {
+	rows, err := s.db.Query(`SELECT session_id, SUM(cost_microdollars) FROM cost_events GROUP BY session_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string]int64)
+	for rows.Next() {
+		var id string
+		var cost int64
+		if err := rows.Scan(&id, &cost); err != nil {
+			return nil, err
+		}
+		result[id] = cost
+	}
+	return result, rows.Err()
+}
+
+// DeleteHookEventsSince removes the Stop-hook events of a session recorded at
+// or after since. Hook events are keyed <session>_<unix-nanos>; transcript
+// sync calls this once the transcript covers the same turns.
+func (s *Store) DeleteHookEventsSince(sessionID string, since time.Time) (int64, error) {
+	res, err := s.db.Exec(`
+		DELETE FROM cost_events
+		WHERE session_id = ? AND timestamp >= ?
+			AND substr(id, 1, length(session_id) + 1) = session_id || '_'
+			AND substr(id, length(session_id) + 2) GLOB '[0-9]*'
+			AND substr(id, length(session_id) + 2) NOT GLOB '*[^0-9]*'`,
+		sessionID, since.UTC().Format(time.RFC3339))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
 // CostByModel returns total cost per model.
 func (s *Store) CostByModel() (map[string]int64, error) {
 	rows, err := s.db.Query(`
@@ -259,6 +327,26 @@ func (s *Store) GroupRunningTotal(tx *sql.Tx, sessionIDs []string, since time.Ti
 	return total, err
 }
 
+// TokensForSessions returns input plus output tokens recorded for a set of
+// sessions since the given instant. Used by the conductor dispatch budget.
+func (s *Store) TokensForSessions(sessionIDs []string, since time.Time) (int64, error) {
+	if len(sessionIDs) == 0 {
+		return 0, nil
+	}
+	placeholders := "?" + repeatArg(len(sessionIDs)-1)
+	args := make([]any, len(sessionIDs)+1)
+	for i, id := range sessionIDs {
+		args[i] = id
+	}
+	args[len(sessionIDs)] = since.UTC().Format(time.RFC3339)
+	// #nosec G201 -- placeholders is "?, ?, ?" generated by repeatArg.
+	cs, err := s.querySum(fmt.Sprintf(`WHERE session_id IN (%s) AND timestamp >= ?`, placeholders), args...)
+	if err != nil {
+		return 0, err
+	}
+	return cs.TotalInputTokens + cs.TotalOutputTokens, nil
+}
+
 func (s *Store) querySum(where string, args ...any) (CostSummary, error) {
 	var cs CostSummary
 	err := s.db.QueryRow(`
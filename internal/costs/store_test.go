@@ -88,6 +88,33 @@ func TestStore_TotalToday(t *testing.T) {
 	}
 }
 
+func TestStore_TokensForSessions(t *testing.T) {
+	s := testStore(t)
+	now := time.Now()
+	events := []costs.CostEvent{
+		{ID: "e1", SessionID: "s1", Timestamp: now, InputTokens: 1000, OutputTokens: 500, CacheReadTokens: 9000},
+		{ID: "e2", SessionID: "s2", Timestamp: now, InputTokens: 200, OutputTokens: 100},
+		{ID: "e3", SessionID: "s3", Timestamp: now, InputTokens: 7000, OutputTokens: 7000},
+		{ID: "e4", SessionID: "s1", Timestamp: now.Add(-48 * time.Hour), InputTokens: 5000},
+	}
+	for _, ev := range events {
+		if err := s.WriteCostEvent(ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := s.TokensForSessions([]string{"s1", "s2"}, now.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 1800 {
+		t.Errorf("tokens = %d, want 1800 (input+output, excluding s3 and old events)", got)
+	}
+	if got, _ := s.TokensForSessions(nil, now); got != 0 {
+		t.Errorf("no sessions: tokens = %d, want 0", got)
+	}
+}
+
 func TestStore_CostByModel(t *testing.T) {
 	s := testStore(t)
 	now := time.Now()
@@ -414,3 +441,85 @@ func TestStore_TotalLastMonth_TwoMonthsAgoExcluded(t *testing.T) {
 		t.Errorf("two months ago: last-month total = %d, want 0", summary.TotalCostMicrodollars)
 	}
 }
+
+func TestStore_SessionCostsSince(t *testing.T) {
+	s := testStore(t)
+	now := time.Now()
+	_ = s.WriteCostEvent(costs.CostEvent{ID: "a1", SessionID: "a", Timestamp: now.Add(-10 * 24 * time.Hour), Model: "m", InputTokens: 1, CostMicrodollars: 900})
+	_ = s.WriteCostEvent(costs.CostEvent{ID: "a2", SessionID: "a", Timestamp: now, Model: "m", InputTokens: 10, OutputTokens: 5, CacheReadTokens: 7, CostMicrodollars: 100})
+	_ = s.WriteCostEvent(costs.CostEvent{ID: "b1", SessionID: "b", Timestamp: now, Model: "m", InputTokens: 20, CacheWriteTokens: 3, CostMicrodollars: 300})
+
+	week, err := s.SessionCostsSince(now.Add(-7 * 24 * time.Hour))
+	if err != nil {
+		t.Fatalf("SessionCostsSince: %v", err)
+	}
+	if len(week) != 2 || week[0].SessionID != "b" || week[1].SessionID != "a" {
+		t.Fatalf("week = %+v, want b then a", week)
+	}
+	if a := week[1]; a.CostMicrodollars != 100 || a.InputTokens != 10 || a.OutputTokens != 5 || a.CacheReadTokens != 7 || a.EventCount != 1 {
+		t.Errorf("a = %+v, want only the recent event", a)
+	}
+	if week[0].CacheWriteTokens != 3 {
+		t.Errorf("b cache write = %d, want 3", week[0].CacheWriteTokens)
+	}
+
+	all, err := s.SessionCostsSince(time.Time{})
+	if err != nil {
+		t.Fatalf("SessionCostsSince(zero): %v", err)
+	}
+	if len(all) != 2 || all[0].SessionID != "a" || all[0].CostMicrodollars != 1000 {
+		t.Errorf("all = %+v, want a first at 1000", all)
+	}
+
+	bySession, err := s.CostBySession()
+	if err != nil {
+		t.Fatalf("CostBySession: %v", err)
+	}
+	if bySession["a"] != 1000 || bySession["b"] != 300 {
+		t.Errorf("CostBySession = %v", bySession)
+	}
+}
+
+func TestStore_DeleteHookEventsSince(t *testing.T) {
+	s := testStore(t)
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, ev := range []costs.CostEvent{
+		{ID: "sess_1700000000000000000", SessionID: "sess", Timestamp: base.Add(-time.Hour)}, // older conversation
+		{ID: "sess_1700000000000000001", SessionID: "sess", Timestamp: base.Add(time.Minute)},
+		{ID: "sess_5f0c-uuid", SessionID: "sess", Timestamp: base.Add(time.Minute)},             // transcript event
+		{ID: "other_1700000000000000002", SessionID: "other", Timestamp: base.Add(time.Minute)}, // other session
+	} {
+		ev.Model = "m"
+		if err := s.WriteCostEvent(ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n, err := s.DeleteHookEventsSince("sess", base)
+	if err != nil {
+		t.Fatalf("DeleteHookEventsSince: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("deleted %d, want 1", n)
+	}
+	var ids []string
+	rows, err := s.DB().Query(`SELECT id FROM cost_events ORDER BY id`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		_ = rows.Scan(&id)
+		ids = append(ids, id)
+	}
+	want := []string{"other_1700000000000000002", "sess_1700000000000000000", "sess_5f0c-uuid"}
+	if len(ids) != len(want) {
+		t.Fatalf("remaining = %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("remaining = %v, want %v", ids, want)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -65,6 +66,46 @@ type SyncSession struct {
 // SyncFromTranscripts reads historical usage from Claude transcript files
 // and backfills cost_events for managed sessions.
 func SyncFromTranscripts(store *Store, pricer *Pricer, sessions []SyncSession) SyncResult {
+	return syncTranscripts(store, pricer, sessions, nil)
+}
+
+// TranscriptSyncer runs SyncFromTranscripts repeatedly, skipping transcripts
+// whose size and mtime haven't changed since the last pass. The TUI keeps one
+// for its periodic refresh so idle sessions cost a stat, not a re-parse.
+type TranscriptSyncer struct {
+	store  *Store
+	pricer *Pricer
+
+	mu   sync.Mutex
+	seen map[string]transcriptStamp
+}
+
+type transcriptStamp struct {
+	size    int64
+	modTime time.Time
+}
+
+// NewTranscriptSyncer creates a syncer writing into store.
+func NewTranscriptSyncer(store *Store, pricer *Pricer) *TranscriptSyncer {
+	return &TranscriptSyncer{store: store, pricer: pricer, seen: make(map[string]transcriptStamp)}
+}
+
+// Sync imports new usage from the transcripts of sessions.
+func (t *TranscriptSyncer) Sync(sessions []SyncSession) SyncResult {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return syncTranscripts(t.store, t.pricer, sessions, t.seen)
+}
+
+// TranscriptPath returns where Claude keeps the transcript of sess:
+// ~/.claude/projects/<slugified-path>/<session-id>.jsonl.
+func TranscriptPath(home string, sess SyncSession) string {
+	return filepath.Join(home, ".claude", "projects", slugifyProjectPath(sess.ProjectPath), sess.ClaudeSessionID+".jsonl")
+}
+
+// syncTranscripts does the work of SyncFromTranscripts. When seen is non-nil,
+// transcripts whose stamp matches seen are skipped and seen is updated.
+func syncTranscripts(store *Store, pricer *Pricer, sessions []SyncSession, seen map[string]transcriptStamp) SyncResult {
 	var result SyncResult
 
 	home, err := os.UserHomeDir()
@@ -83,18 +124,25 @@ func SyncFromTranscripts(store *Store, pricer *Pricer, sessions []SyncSession) S
 
 		result.SessionsScanned++
 
-		// Derive transcript path: ~/.claude/projects/<slugified-path>/<session-id>.jsonl
-		sluggedPath := slugifyProjectPath(sess.ProjectPath)
-		transcriptPath := filepath.Join(home, ".claude", "projects", sluggedPath, sess.ClaudeSessionID+".jsonl")
-
-		if _, err := os.Stat(transcriptPath); os.IsNotExist(err) {
+		transcriptPath := TranscriptPath(home, sess)
+		fi, err := os.Stat(transcriptPath)
+		if err != nil {
+			continue
+		}
+		stamp := transcriptStamp{size: fi.Size(), modTime: fi.ModTime()}
+		if seen != nil && seen[transcriptPath] == stamp {
 			continue
 		}
 
 		events, errs := parseTranscriptFile(transcriptPath, sess.InstanceID, pricer)
 		result.Errors = append(result.Errors, errs...)
 
+		var first time.Time
 		for _, ev := range events {
+			if first.IsZero() || ev.timestamp.Before(first) {
+				first = ev.timestamp
+			}
+
 			// Check if we already have this event (by a deterministic ID)
 			dedupKey := fmt.Sprintf("%s_%s", sess.InstanceID, ev.dedupKey)
 			if existing[dedupKey] {
@@ -132,6 +180,19 @@ func SyncFromTranscripts(store *Store, pricer *Pricer, sessions []SyncSession) S
 			existing[dedupKey] = true
 			result.EventsImported++
 		}
+
+		// The transcript now accounts for every turn from its first message
+		// on; drop the Stop-hook events for the same turns so they aren't
+		// counted twice.
+		if !first.IsZero() {
+			if _, err := store.DeleteHookEventsSince(sess.InstanceID, first); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("drop hook events: %v", err))
+				continue
+			}
+		}
+		if seen != nil {
+			seen[transcriptPath] = stamp
+		}
 	}
 
 	return result
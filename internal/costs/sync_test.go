@@ -0,0 +1,101 @@
+package costs_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/costs"
+)
+
+// writeTranscript writes a Claude transcript for sess under home.
+func writeTranscript(t *testing.T, home string, sess costs.SyncSession, lines ...string) string {
+	t.Helper()
+	path := costs.TranscriptPath(home, sess)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const (
+	turn1 = `{"type":"assistant","uuid":"u-1","timestamp":"2026-03-01T12:00:00Z","message":{"model":"claude-sonnet-4-6","usage":{"input_tokens":100,"output_tokens":50,"cache_read_input_tokens":1000}}}`
+	turn2 = `{"type":"assistant","uuid":"u-2","timestamp":"2026-03-01T12:05:00Z","message":{"model":"claude-sonnet-4-6","usage":{"input_tokens":200,"output_tokens":80}}}`
+)
+
+func TestSyncFromTranscripts_ReplacesHookEvents(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	s := testStore(t)
+	pricer := costs.NewPricer(costs.PricerConfig{})
+	sess := costs.SyncSession{InstanceID: "inst", ClaudeSessionID: "c-1", ProjectPath: "/work/app", Tool: "claude"}
+	writeTranscript(t, home, sess, turn1, `{"type":"user","uuid":"u-x"}`, turn2)
+
+	// The Stop hook already recorded turn 2 before the sync ran.
+	hookAt := time.Date(2026, 3, 1, 12, 5, 1, 0, time.UTC)
+	if err := s.WriteCostEvent(costs.CostEvent{ID: "inst_1772366701000000000", SessionID: "inst", Timestamp: hookAt,
+		Model: "claude-sonnet-4-6", InputTokens: 200, OutputTokens: 80, CostMicrodollars: 1800}); err != nil {
+		t.Fatal(err)
+	}
+
+	res := costs.SyncFromTranscripts(s, pricer, []costs.SyncSession{sess})
+	if res.EventsImported != 2 || len(res.Errors) != 0 {
+		t.Fatalf("result = %+v, want 2 imported", res)
+	}
+	sum, err := s.TotalBySession("inst")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum.EventCount != 2 || sum.TotalInputTokens != 300 || sum.TotalOutputTokens != 130 || sum.TotalCacheReadTokens != 1000 {
+		t.Errorf("summary = %+v, want the two transcript turns only", sum)
+	}
+
+	// A second pass imports nothing new.
+	res = costs.SyncFromTranscripts(s, pricer, []costs.SyncSession{sess})
+	if res.EventsImported != 0 || res.EventsSkipped != 2 {
+		t.Errorf("resync = %+v, want 2 skipped", res)
+	}
+}
+
+func TestTranscriptSyncer_SkipsUnchangedTranscripts(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	s := testStore(t)
+	syncer := costs.NewTranscriptSyncer(s, costs.NewPricer(costs.PricerConfig{}))
+	sess := costs.SyncSession{InstanceID: "inst", ClaudeSessionID: "c-1", ProjectPath: "/work/app", Tool: "claude"}
+	path := writeTranscript(t, home, sess, turn1)
+
+	if res := syncer.Sync([]costs.SyncSession{sess}); res.EventsImported != 1 {
+		t.Fatalf("first sync = %+v, want 1 imported", res)
+	}
+	if res := syncer.Sync([]costs.SyncSession{sess}); res.EventsImported != 0 || res.EventsSkipped != 0 {
+		t.Errorf("unchanged sync = %+v, want the file skipped", res)
+	}
+
+	writeTranscript(t, home, sess, turn1, turn2)
+	later := time.Now().Add(time.Second)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if res := syncer.Sync([]costs.SyncSession{sess}); res.EventsImported != 1 || res.EventsSkipped != 1 {
+		t.Errorf("grown sync = %+v, want 1 imported and 1 skipped", res)
+	}
+}
+
+func TestSyncFromTranscripts_IgnoresOtherTools(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	s := testStore(t)
+	sess := costs.SyncSession{InstanceID: "inst", ClaudeSessionID: "c-1", ProjectPath: "/work/app", Tool: "codex"}
+	writeTranscript(t, home, sess, turn1)
+
+	res := costs.SyncFromTranscripts(s, costs.NewPricer(costs.PricerConfig{}), []costs.SyncSession{sess})
+	if res.SessionsScanned != 0 || res.EventsImported != 0 {
+		t.Errorf("result = %+v, want non-Claude sessions skipped", res)
+	}
+}
@@ -112,8 +112,7 @@ func FindExact(experiments []Experiment, name string) *Experiment {
 // If datePrefix is true, prepends YYYY-MM-DD- to the name
 // Returns the created experiment
 func CreateExperiment(baseDir, name string, datePrefix bool) (*Experiment, error) {
-	// Sanitize name (replace spaces with hyphens, lowercase)
-	name = strings.ToLower(strings.ReplaceAll(name, " ", "-"))
+	name = sanitizeName(name)
 
 	// Build folder name
 	folderName := name
@@ -181,9 +180,25 @@ func FindOrCreate(baseDir, query string, datePrefix bool) (*Experiment, bool, er
 		return nil, false, err
 	}
 
+	if exp := findMatch(experiments, query); exp != nil {
+		return exp, false, nil
+	}
+
+	// No good match - create new experiment
+	exp, err := CreateExperiment(baseDir, query, datePrefix)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return exp, true, nil
+}
+
+// findMatch returns the experiment query refers to: an exact name match, or
+// the only fuzzy match. Returns nil when there is none or it is ambiguous.
+func findMatch(experiments []Experiment, query string) *Experiment {
 	// Check for exact match first
 	if exp := FindExact(experiments, query); exp != nil {
-		return exp, false, nil
+		return exp
 	}
 
 	// Fuzzy search
@@ -194,16 +209,15 @@ func FindOrCreate(baseDir, query string, datePrefix bool) (*Experiment, bool, er
 	if len(matches) == 1 {
 		for i := range experiments {
 			if experiments[i].Path == matches[0].Path {
-				return &experiments[i], false, nil // Safe: points to slice element
+				return &experiments[i] // Safe: points to slice element
 			}
 		}
 	}
+	return nil
+}
 
-	// No good match - create new experiment
-	exp, err := CreateExperiment(baseDir, query, datePrefix)
-	if err != nil {
-		return nil, false, err
-	}
-
-	return exp, true, nil
+// sanitizeName turns an experiment name into a folder name (replace spaces
+// with hyphens, lowercase).
+func sanitizeName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, " ", "-"))
 }
@@ -0,0 +1,246 @@
+package experiments
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Template lays out experiment folders as a path pattern such as
+// "~/experiments/{yyyy}/{mm}/{name}" (home already expanded). Supported
+// tokens are {name}, {yyyy}, {mm}, {dd} and {date} (YYYY-MM-DD). {name}
+// must appear exactly once, in the last path segment, so every experiment
+// is a leaf directory.
+type Template struct {
+	pattern string
+	match   *regexp.Regexp
+}
+
+// templateToken matches one {token} in a template pattern.
+var templateToken = regexp.MustCompile(`\{[a-z]+\}`)
+
+// templateTokenRegexps maps each supported token to the regexp that matches
+// its expansion inside a single path segment.
+var templateTokenRegexps = map[string]string{
+	"{name}": `(?P<name>[^/\\]+)`,
+	"{yyyy}": `(?P<yyyy>\d{4})`,
+	"{mm}":   `(?P<mm>\d{2})`,
+	"{dd}":   `(?P<dd>\d{2})`,
+	"{date}": `(?P<date>\d{4}-\d{2}-\d{2})`,
+}
+
+// ParseTemplate validates pattern and returns the template.
+func ParseTemplate(pattern string) (Template, error) {
+	if pattern == "" {
+		return Template{}, errors.New("empty template")
+	}
+	pattern = filepath.Clean(pattern)
+	for _, tok := range templateToken.FindAllString(pattern, -1) {
+		if _, ok := templateTokenRegexps[tok]; !ok {
+			return Template{}, fmt.Errorf("template %q: unknown token %s (want {name}, {yyyy}, {mm}, {dd} or {date})", pattern, tok)
+		}
+	}
+	if n := strings.Count(pattern, "{name}"); n != 1 {
+		return Template{}, fmt.Errorf("template %q: needs {name} exactly once", pattern)
+	}
+	if !strings.Contains(filepath.Base(pattern), "{name}") {
+		return Template{}, fmt.Errorf("template %q: {name} must be in the last path segment", pattern)
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	last := 0
+	for _, loc := range templateToken.FindAllStringIndex(pattern, -1) {
+		expr.WriteString(regexp.QuoteMeta(pattern[last:loc[0]]))
+		expr.WriteString(templateTokenRegexps[pattern[loc[0]:loc[1]]])
+		last = loc[1]
+	}
+	expr.WriteString(regexp.QuoteMeta(pattern[last:]))
+	expr.WriteString("$")
+
+	match, err := regexp.Compile(expr.String())
+	if err != nil {
+		return Template{}, fmt.Errorf("template %q: %w", pattern, err)
+	}
+	return Template{pattern: pattern, match: match}, nil
+}
+
+// String returns the template pattern.
+func (t Template) String() string {
+	return t.pattern
+}
+
+// Root returns the directory above the first path segment that holds a
+// token: everything the template creates lives under it.
+func (t Template) Root() string {
+	dir := t.pattern
+	for templateToken.MatchString(dir) {
+		dir = filepath.Dir(dir)
+	}
+	return dir
+}
+
+// Expand returns the folder path for name created at now.
+func (t Template) Expand(name string, now time.Time) string {
+	return strings.NewReplacer(
+		"{name}", name,
+		"{yyyy}", now.Format("2006"),
+		"{mm}", now.Format("01"),
+		"{dd}", now.Format("02"),
+		"{date}", now.Format("2006-01-02"),
+	).Replace(t.pattern)
+}
+
+// List returns every folder matching the template, most recently modified
+// first. Name is the {name} part; the date tokens, when present, set Date.
+func (t Template) List() ([]Experiment, error) {
+	glob := globEscape(t.pattern)
+	glob = templateToken.ReplaceAllString(glob, "*")
+	paths, err := filepath.Glob(glob)
+	if err != nil {
+		return nil, err
+	}
+
+	experiments := []Experiment{}
+	for _, path := range paths {
+		exp, ok := t.parse(path)
+		if !ok {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil || !info.IsDir() {
+			continue
+		}
+		exp.ModTime = info.ModTime()
+		experiments = append(experiments, exp)
+	}
+
+	sort.Slice(experiments, func(i, j int) bool {
+		return experiments[i].ModTime.After(experiments[j].ModTime)
+	})
+	return experiments, nil
+}
+
+// parse matches path against the template and extracts name and date.
+func (t Template) parse(path string) (Experiment, bool) {
+	m := t.match.FindStringSubmatch(path)
+	if m == nil {
+		return Experiment{}, false
+	}
+	group := func(name string) string {
+		if i := t.match.SubexpIndex(name); i >= 0 {
+			return m[i]
+		}
+		return ""
+	}
+
+	exp := Experiment{Name: group("name"), Path: path}
+	if d := group("date"); d != "" {
+		if date, err := time.Parse("2006-01-02", d); err == nil {
+			exp.Date, exp.HasDate = date, true
+		}
+	} else if y := group("yyyy"); y != "" {
+		mm, dd := group("mm"), group("dd")
+		if mm == "" {
+			mm = "01"
+		}
+		if dd == "" {
+			dd = "01"
+		}
+		if date, err := time.Parse("2006-01-02", y+"-"+mm+"-"+dd); err == nil {
+			exp.Date, exp.HasDate = date, true
+		}
+	}
+	return exp, true
+}
+
+// globEscape escapes the glob metacharacters in a literal path.
+func globEscape(path string) string {
+	return strings.NewReplacer(`*`, `\*`, `?`, `\?`, `[`, `\[`).Replace(path)
+}
+
+// CreateFromTemplate creates a new experiment folder for name, adding a
+// numeric suffix to the name when the folder already exists.
+func CreateFromTemplate(t Template, name string) (*Experiment, error) {
+	name = sanitizeName(name)
+	now := time.Now()
+
+	displayName := name
+	targetPath := t.Expand(name, now)
+	for suffix := 2; ; suffix++ {
+		if _, err := os.Stat(targetPath); os.IsNotExist(err) {
+			break
+		}
+		if suffix > 100 {
+			return nil, fmt.Errorf("too many experiments with name %q", name)
+		}
+		displayName = fmt.Sprintf("%s-%d", name, suffix)
+		targetPath = t.Expand(displayName, now)
+	}
+
+	if err := os.MkdirAll(targetPath, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create experiment directory: %w", err)
+	}
+
+	exp, _ := t.parse(targetPath)
+	exp.Name = displayName
+	exp.Path = targetPath
+	exp.ModTime = now
+	return &exp, nil
+}
+
+// FindOrCreateTemplate is FindOrCreate for a directory template.
+// Returns (experiment, created, error)
+func FindOrCreateTemplate(t Template, query string) (*Experiment, bool, error) {
+	experiments, err := t.List()
+	if err != nil {
+		return nil, false, err
+	}
+	if exp := findMatch(experiments, query); exp != nil {
+		return exp, false, nil
+	}
+
+	exp, err := CreateFromTemplate(t, query)
+	if err != nil {
+		return nil, false, err
+	}
+	return exp, true, nil
+}
+
+// RemoveEmpty deletes the experiment folders that are empty and were last
+// modified more than olderThan ago, then any directory between a removed
+// folder and root that is left empty (root itself is kept). Returns the
+// number of experiment folders removed.
+func RemoveEmpty(experiments []Experiment, root string, olderThan time.Duration) int {
+	cutoff := time.Now().Add(-olderThan)
+	root = filepath.Clean(root)
+	removed := 0
+
+	for _, exp := range experiments {
+		if !exp.ModTime.Before(cutoff) || !isEmptyDir(exp.Path) {
+			continue
+		}
+		if err := os.Remove(exp.Path); err != nil {
+			continue
+		}
+		removed++
+
+		for dir := filepath.Dir(exp.Path); dir != root && strings.HasPrefix(dir, root+string(filepath.Separator)); dir = filepath.Dir(dir) {
+			if !isEmptyDir(dir) || os.Remove(dir) != nil {
+				break
+			}
+		}
+	}
+	return removed
+}
+
+// isEmptyDir reports whether path is a directory with no entries.
+func isEmptyDir(path string) bool {
+	entries, err := os.ReadDir(path)
+	return err == nil && len(entries) == 0
+}
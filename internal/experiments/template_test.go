@@ -0,0 +1,140 @@
+package experiments
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseTemplate_Errors(t *testing.T) {
+	for _, pattern := range []string{
+		"",
+		"/tmp/tries/{yyyy}",               // no {name}
+		"/tmp/tries/{name}/{name}",        // {name} twice
+		"/tmp/tries/{name}/notes",         // {name} not last
+		"/tmp/tries/{yyyy}/{week}/{name}", // unknown token
+	} {
+		if _, err := ParseTemplate(pattern); err == nil {
+			t.Errorf("ParseTemplate(%q): expected error", pattern)
+		}
+	}
+}
+
+func TestTemplate_ExpandAndRoot(t *testing.T) {
+	tpl, err := ParseTemplate("/tmp/tries/{yyyy}/{mm}/{dd}-{name}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 3, 7, 12, 0, 0, 0, time.Local)
+	if got, want := tpl.Expand("redis", now), "/tmp/tries/2026/03/07-redis"; got != want {
+		t.Errorf("Expand = %q, want %q", got, want)
+	}
+	if got, want := tpl.Root(), "/tmp/tries"; got != want {
+		t.Errorf("Root = %q, want %q", got, want)
+	}
+}
+
+func TestTemplate_FindOrCreateAndList(t *testing.T) {
+	tmpDir := t.TempDir()
+	tpl, err := ParseTemplate(filepath.Join(tmpDir, "{yyyy}", "{mm}", "{name}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An older experiment in another month, and a folder that does not fit.
+	if err := os.MkdirAll(filepath.Join(tmpDir, "2025", "11", "api-test"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(tmpDir, "notes", "11", "misc"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	exp, created, err := FindOrCreateTemplate(tpl, "Redis Cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created {
+		t.Error("expected experiment to be created")
+	}
+	want := tpl.Expand("redis-cache", time.Now())
+	if exp.Path != want || exp.Name != "redis-cache" || !exp.HasDate {
+		t.Errorf("created %+v, want path %q name redis-cache with date", exp, want)
+	}
+
+	exps, err := tpl.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exps) != 2 {
+		t.Fatalf("expected 2 experiments, got %d: %+v", len(exps), exps)
+	}
+
+	found, created, err := FindOrCreateTemplate(tpl, "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created || found.Name != "api-test" {
+		t.Errorf("expected to find api-test, got %+v (created=%v)", found, created)
+	}
+	if found.Date.Year() != 2025 || found.Date.Month() != time.November {
+		t.Errorf("expected date 2025-11, got %v", found.Date)
+	}
+
+	dup, err := CreateFromTemplate(tpl, "redis-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dup.Name != "redis-cache-2" {
+		t.Errorf("expected duplicate to be redis-cache-2, got %q", dup.Name)
+	}
+}
+
+func TestRemoveEmpty(t *testing.T) {
+	tmpDir := t.TempDir()
+	tpl, err := ParseTemplate(filepath.Join(tmpDir, "{yyyy}", "{mm}", "{name}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old := time.Now().Add(-30 * 24 * time.Hour)
+	mkdir := func(path string, mtime time.Time) {
+		t.Helper()
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	emptyOld := filepath.Join(tmpDir, "2025", "01", "abandoned")
+	usedOld := filepath.Join(tmpDir, "2025", "02", "kept")
+	emptyNew := filepath.Join(tmpDir, "2025", "02", "fresh")
+	mkdir(emptyOld, old)
+	mkdir(usedOld, old)
+	if err := os.WriteFile(filepath.Join(usedOld, "main.go"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	mkdir(usedOld, old) // writing the file bumped the mtime
+	mkdir(emptyNew, time.Now())
+
+	exps, err := tpl.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed := RemoveEmpty(exps, tpl.Root(), 7*24*time.Hour); removed != 1 {
+		t.Errorf("expected 1 folder removed, got %d", removed)
+	}
+
+	if _, err := os.Stat(emptyOld); !os.IsNotExist(err) {
+		t.Error("expected empty old experiment to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "2025", "01")); !os.IsNotExist(err) {
+		t.Error("expected its emptied month directory to be removed")
+	}
+	for _, path := range []string{usedOld, emptyNew, tmpDir} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected %s to be kept: %v", path, err)
+		}
+	}
+}
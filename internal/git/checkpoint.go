@@ -0,0 +1,225 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CheckpointRefPrefix is the ref namespace checkpoints live under, one
+// directory per owner (session ID): refs/agent-deck/checkpoints/<owner>/<n>.
+// Being outside refs/heads and refs/tags, checkpoints never show up as
+// branches, are not pushed, and keep their snapshots from being gc'd.
+const CheckpointRefPrefix = "refs/agent-deck/checkpoints/"
+
+// checkpointIdentity is the author and committer of checkpoint commits, so
+// taking one never depends on (or is attributed to) the user's git identity.
+var checkpointIdentity = []string{
+	"GIT_AUTHOR_NAME=agent-deck", "GIT_AUTHOR_EMAIL=agent-deck@localhost",
+	"GIT_COMMITTER_NAME=agent-deck", "GIT_COMMITTER_EMAIL=agent-deck@localhost",
+}
+
+// Checkpoint is a snapshot of a working tree: a commit whose tree holds every
+// tracked and untracked (not ignored) file as it was, and whose parent is the
+// commit HEAD pointed at.
+type Checkpoint struct {
+	Ref     string    `json:"ref"`
+	Commit  string    `json:"commit"`
+	Head    string    `json:"head"`
+	Message string    `json:"message"`
+	At      time.Time `json:"at"`
+
+	tree string
+}
+
+// ShortCommit returns the abbreviated checkpoint commit.
+func (c Checkpoint) ShortCommit() string {
+	if len(c.Commit) > 8 {
+		return c.Commit[:8]
+	}
+	return c.Commit
+}
+
+func checkpointOwnerPrefix(owner string) (string, error) {
+	if owner == "" || strings.ContainsAny(owner, "/ \t\n~^:?*[\\") || strings.Contains(owner, "..") {
+		return "", fmt.Errorf("invalid checkpoint owner %q", owner)
+	}
+	return CheckpointRefPrefix + owner + "/", nil
+}
+
+// checkpointGit runs git in dir and returns its trimmed stdout.
+func checkpointGit(dir string, env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %s: %w", args[0], strings.TrimSpace(stderr.String()), err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// CreateCheckpoint snapshots the working tree at dir under owner's
+// checkpoints. The snapshot is built in a scratch index, so the real index,
+// the working tree and the branch are untouched. When nothing changed since
+// owner's latest checkpoint, that checkpoint is returned with created=false.
+func CreateCheckpoint(dir, owner, message string) (cp Checkpoint, created bool, err error) {
+	prefix, err := checkpointOwnerPrefix(owner)
+	if err != nil {
+		return Checkpoint{}, false, err
+	}
+	head, err := checkpointGit(dir, nil, "rev-parse", "--verify", "--quiet", "HEAD^{commit}")
+	if err != nil || head == "" {
+		return Checkpoint{}, false, errors.New("repository has no commits to checkpoint against")
+	}
+
+	tree, err := snapshotTree(dir)
+	if err != nil {
+		return Checkpoint{}, false, err
+	}
+	if existing, err := ListCheckpoints(dir, owner); err == nil && len(existing) > 0 {
+		if latest := existing[0]; latest.tree == tree && latest.Head == head {
+			return latest, false, nil
+		}
+	}
+
+	if message == "" {
+		message = "checkpoint"
+	}
+	commit, err := checkpointGit(dir, checkpointIdentity, "commit-tree", tree, "-p", head, "-m", message)
+	if err != nil {
+		return Checkpoint{}, false, err
+	}
+	now := time.Now()
+	ref := prefix + strconv.FormatInt(now.UnixNano(), 10)
+	if _, err := checkpointGit(dir, nil, "update-ref", ref, commit); err != nil {
+		return Checkpoint{}, false, err
+	}
+	return Checkpoint{Ref: ref, Commit: commit, Head: head, Message: message, At: now.Truncate(time.Second), tree: tree}, true, nil
+}
+
+// snapshotTree writes a tree of the working tree at dir (tracked and
+// untracked files, .gitignore respected) using a copy of the index, which
+// keeps its stat cache so unchanged files are not re-hashed.
+func snapshotTree(dir string) (string, error) {
+	indexPath, err := checkpointGit(dir, nil, "rev-parse", "--git-path", "index")
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(indexPath) {
+		indexPath = filepath.Join(dir, indexPath)
+	}
+	scratch, err := os.CreateTemp("", "agent-deck-checkpoint-index-*")
+	if err != nil {
+		return "", err
+	}
+	scratchPath := scratch.Name()
+	defer os.Remove(scratchPath)
+	if src, err := os.Open(indexPath); err == nil {
+		_, err = io.Copy(scratch, src)
+		src.Close()
+		if err != nil {
+			scratch.Close()
+			return "", err
+		}
+	}
+	if err := scratch.Close(); err != nil {
+		return "", err
+	}
+	if info, err := os.Stat(scratchPath); err == nil && info.Size() == 0 {
+		// No index yet: git rejects an empty file as an index.
+		_ = os.Remove(scratchPath)
+	}
+
+	env := []string{"GIT_INDEX_FILE=" + scratchPath}
+	if _, err := checkpointGit(dir, env, "add", "--all", "--", ":/"); err != nil {
+		return "", err
+	}
+	return checkpointGit(dir, env, "write-tree")
+}
+
+// ListCheckpoints returns owner's checkpoints in the repository at dir,
+// newest first.
+func ListCheckpoints(dir, owner string) ([]Checkpoint, error) {
+	prefix, err := checkpointOwnerPrefix(owner)
+	if err != nil {
+		return nil, err
+	}
+	out, err := checkpointGit(dir, nil, "for-each-ref", "--sort=-refname",
+		"--format=%(refname)%00%(objectname)%00%(parent)%00%(tree)%00%(committerdate:unix)%00%(contents:subject)",
+		prefix)
+	if err != nil {
+		return nil, err
+	}
+	var cps []Checkpoint
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) != 6 {
+			continue
+		}
+		unix, _ := strconv.ParseInt(fields[4], 10, 64)
+		cps = append(cps, Checkpoint{
+			Ref:     fields[0],
+			Commit:  fields[1],
+			Head:    fields[2],
+			tree:    fields[3],
+			At:      time.Unix(unix, 0),
+			Message: fields[5],
+		})
+	}
+	return cps, nil
+}
+
+// PruneCheckpoints deletes all but owner's newest keep checkpoints.
+func PruneCheckpoints(dir, owner string, keep int) error {
+	cps, err := ListCheckpoints(dir, owner)
+	if err != nil {
+		return err
+	}
+	for i := keep; i < len(cps); i++ {
+		if _, err := checkpointGit(dir, nil, "update-ref", "-d", cps[i].Ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RestoreCheckpoint puts the working tree at dir back to cp: the branch is
+// reset to the commit HEAD pointed at when cp was taken, and the files,
+// including ones that were uncommitted or untracked then, are restored with
+// their changes left uncommitted. Commits made and untracked files created
+// since are dropped from the working tree (take a checkpoint first to keep
+// them recoverable).
+func RestoreCheckpoint(dir string, cp Checkpoint) error {
+	if cp.Commit == "" || cp.Head == "" {
+		return errors.New("incomplete checkpoint")
+	}
+	if _, err := checkpointGit(dir, nil, "cat-file", "-e", cp.Commit+"^{commit}"); err != nil {
+		return fmt.Errorf("checkpoint %s no longer exists", cp.ShortCommit())
+	}
+	top, err := checkpointGit(dir, nil, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return err
+	}
+	for _, args := range [][]string{
+		{"reset", "--quiet", "--hard", cp.Head},
+		{"clean", "--quiet", "-fd"},
+		{"read-tree", "--reset", "-u", cp.Commit},
+		{"reset", "--quiet"},
+	} {
+		if _, err := checkpointGit(filepath.Clean(top), nil, args...); err != nil {
+			return fmt.Errorf("restore checkpoint %s: %w", cp.ShortCommit(), err)
+		}
+	}
+	return nil
+}
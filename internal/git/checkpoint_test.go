@@ -0,0 +1,103 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readCheckpointFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "<missing>"
+	}
+	return string(data)
+}
+
+func TestCheckpoint_SnapshotAndRestore(t *testing.T) {
+	dir := t.TempDir()
+	createTestRepo(t, dir)
+	if err := os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("build/\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, dir, "main.go", "package main\n")
+
+	// Uncommitted edit, new untracked file, ignored output.
+	_ = os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main // wip\n"), 0o644)
+	_ = os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("todo\n"), 0o644)
+	_ = os.MkdirAll(filepath.Join(dir, "build"), 0o755)
+	_ = os.WriteFile(filepath.Join(dir, "build", "out"), []byte("bin"), 0o644)
+	statusBefore, _ := exec.Command("git", "-C", dir, "status", "--porcelain").Output()
+
+	cp, created, err := CreateCheckpoint(dir, "sess-1", "before refactor")
+	if err != nil || !created {
+		t.Fatalf("CreateCheckpoint = %+v, %v, %v", cp, created, err)
+	}
+	if statusAfter, _ := exec.Command("git", "-C", dir, "status", "--porcelain").Output(); string(statusAfter) != string(statusBefore) {
+		t.Fatalf("checkpoint changed the index or tree:\nbefore %s\nafter %s", statusBefore, statusAfter)
+	}
+	if again, created, err := CreateCheckpoint(dir, "sess-1", "no changes"); err != nil || created || again.Commit != cp.Commit {
+		t.Fatalf("unchanged tree made a new checkpoint: %+v, %v, %v", again, created, err)
+	}
+
+	// The agent goes wrong: commits a rewrite, deletes a file, adds junk.
+	commitFile(t, dir, "main.go", "broken\n")
+	_ = os.Remove(filepath.Join(dir, "notes.txt"))
+	_ = os.WriteFile(filepath.Join(dir, "junk.txt"), []byte("junk"), 0o644)
+
+	cps, err := ListCheckpoints(dir, "sess-1")
+	if err != nil || len(cps) != 1 || cps[0].Commit != cp.Commit || cps[0].Message != "before refactor" {
+		t.Fatalf("ListCheckpoints = %+v, %v", cps, err)
+	}
+	if other, _ := ListCheckpoints(dir, "sess-2"); len(other) != 0 {
+		t.Fatalf("another session sees %d checkpoints", len(other))
+	}
+
+	if err := RestoreCheckpoint(dir, cps[0]); err != nil {
+		t.Fatal(err)
+	}
+	if head, _ := HeadCommit(dir); head != cp.Head {
+		t.Errorf("HEAD = %s, want the checkpoint's %s", head, cp.Head)
+	}
+	for name, want := range map[string]string{
+		"main.go":   "package main // wip\n",
+		"notes.txt": "todo\n",
+		"junk.txt":  "<missing>",
+		"build/out": "bin",
+	} {
+		if got := readCheckpointFile(t, filepath.Join(dir, name)); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	status, _ := exec.Command("git", "-C", dir, "status", "--porcelain").Output()
+	if string(status) != string(statusBefore) {
+		t.Errorf("status after restore:\n%s\nwant the uncommitted state from before:\n%s", status, statusBefore)
+	}
+}
+
+func TestPruneCheckpoints_KeepsNewest(t *testing.T) {
+	dir := t.TempDir()
+	createTestRepo(t, dir)
+	var commits []string
+	for i := 0; i < 3; i++ {
+		_ = os.WriteFile(filepath.Join(dir, "f.txt"), []byte(strings.Repeat("x", i+1)), 0o644)
+		cp, _, err := CreateCheckpoint(dir, "s", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		commits = append(commits, cp.Commit)
+	}
+	if err := PruneCheckpoints(dir, "s", 2); err != nil {
+		t.Fatal(err)
+	}
+	cps, _ := ListCheckpoints(dir, "s")
+	if len(cps) != 2 || cps[0].Commit != commits[2] || cps[1].Commit != commits[1] {
+		t.Fatalf("after prune: %+v", cps)
+	}
+	if _, _, err := CreateCheckpoint(dir, "../x", ""); err == nil {
+		t.Fatal("owner with path separators accepted")
+	}
+}
@@ -0,0 +1,220 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Change kinds of a DiffFile, as git diff --name-status reports them.
+const (
+	DiffAdded    = "A"
+	DiffDeleted  = "D"
+	DiffModified = "M"
+	DiffRenamed  = "R"
+)
+
+// DiffFile is one file's part of a BranchDiff.
+type DiffFile struct {
+	Path    string `json:"path"`
+	OldPath string `json:"old_path,omitempty"` // set for renames
+	Status  string `json:"status"`
+	Added   int    `json:"added"`
+	Deleted int    `json:"deleted"`
+	Binary  bool   `json:"binary,omitempty"`
+	// Patch is the file's unified diff, from its "diff --git" header on.
+	Patch string `json:"patch,omitempty"`
+}
+
+// BranchDiff is what a branch would bring into its base: the changes from
+// their merge base to the branch tip, the same set a merge would apply.
+type BranchDiff struct {
+	Base      string     `json:"base"`
+	Branch    string     `json:"branch"`
+	MergeBase string     `json:"merge_base"`
+	Commits   int        `json:"commits"` // commits on branch not on base
+	Files     []DiffFile `json:"files"`
+}
+
+// Totals sums the added and deleted lines over all files.
+func (d *BranchDiff) Totals() (added, deleted int) {
+	for _, f := range d.Files {
+		added += f.Added
+		deleted += f.Deleted
+	}
+	return added, deleted
+}
+
+// DiffBranch computes the diff of branch against base in the repository at
+// dir (any of its worktrees). Uncommitted changes are not included: they
+// are not part of what merging the branch would bring in.
+func DiffBranch(dir, base, branch string) (*BranchDiff, error) {
+	mb, err := exec.Command("git", "-C", dir, "merge-base", base, branch).Output()
+	if err != nil {
+		return nil, fmt.Errorf("no common history between %s and %s", base, branch)
+	}
+	d := &BranchDiff{Base: base, Branch: branch, MergeBase: strings.TrimSpace(string(mb))}
+
+	count, err := exec.Command("git", "-C", dir, "rev-list", "--count", d.MergeBase+".."+branch).Output()
+	if err != nil {
+		return nil, fmt.Errorf("count commits on %s: %w", branch, err)
+	}
+	d.Commits, _ = strconv.Atoi(strings.TrimSpace(string(count)))
+
+	out, err := exec.Command("git", "-C", dir, "-c", "core.quotePath=false",
+		"diff", "--no-color", "--no-ext-diff", "-M", d.MergeBase, branch).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff %s..%s: %w", base, branch, err)
+	}
+	d.Files = ParseUnifiedDiff(string(out))
+	return d, nil
+}
+
+// WorkStat sums up everything a worktree has changed since it forked from
+// its base, committed or not: what a reviewer comparing attempts cares
+// about before any of them is committed.
+type WorkStat struct {
+	Commits   int `json:"commits"`   // commits on HEAD not on base
+	Files     int `json:"files"`     // tracked files changed since the merge base
+	Added     int `json:"added"`     // lines added in those files
+	Deleted   int `json:"deleted"`   // lines deleted in those files
+	Untracked int `json:"untracked"` // new files not yet added to git
+}
+
+// WorktreeWorkStat computes the WorkStat of the worktree at dir against base.
+func WorktreeWorkStat(dir, base string) (WorkStat, error) {
+	var ws WorkStat
+	mb, err := exec.Command("git", "-C", dir, "merge-base", base, "HEAD").Output()
+	if err != nil {
+		return ws, fmt.Errorf("no common history between %s and HEAD", base)
+	}
+	mergeBase := strings.TrimSpace(string(mb))
+
+	count, err := exec.Command("git", "-C", dir, "rev-list", "--count", mergeBase+"..HEAD").Output()
+	if err != nil {
+		return ws, fmt.Errorf("count commits: %w", err)
+	}
+	ws.Commits, _ = strconv.Atoi(strings.TrimSpace(string(count)))
+
+	// Without a second revision, git diff compares the working tree.
+	numstat, err := exec.Command("git", "-C", dir, "diff", "--numstat", "--no-ext-diff", "-M", mergeBase).Output()
+	if err != nil {
+		return ws, fmt.Errorf("git diff --numstat: %w", err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(numstat)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		ws.Files++
+		// Binary files report "-" for both counts.
+		a, _ := strconv.Atoi(fields[0])
+		d, _ := strconv.Atoi(fields[1])
+		ws.Added += a
+		ws.Deleted += d
+	}
+
+	others, err := exec.Command("git", "-C", dir, "ls-files", "--others", "--exclude-standard").Output()
+	if err != nil {
+		return ws, fmt.Errorf("list untracked files: %w", err)
+	}
+	if s := strings.TrimSpace(string(others)); s != "" {
+		ws.Untracked = len(strings.Split(s, "\n"))
+	}
+	return ws, nil
+}
+
+// ParseUnifiedDiff splits git diff output into per-file entries with their
+// change kind and line counts.
+func ParseUnifiedDiff(patch string) []DiffFile {
+	var files []DiffFile
+	var cur *DiffFile
+	var body []string
+	inHunk := false
+	flush := func() {
+		if cur == nil {
+			return
+		}
+		cur.Patch = strings.Join(body, "\n")
+		files = append(files, *cur)
+	}
+
+	for _, line := range strings.Split(strings.TrimRight(patch, "\n"), "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			flush()
+			cur = &DiffFile{Status: DiffModified}
+			body = nil
+			inHunk = false
+			// "diff --git a/<old> b/<new>": exact for paths without " b/";
+			// the ---/+++ and rename lines below override it when present.
+			if i := strings.LastIndex(line, " b/"); i >= 0 {
+				cur.Path = line[i+3:]
+			}
+		}
+		if cur == nil {
+			continue
+		}
+		body = append(body, line)
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		case inHunk && strings.HasPrefix(line, "+"):
+			cur.Added++
+		case inHunk && strings.HasPrefix(line, "-"):
+			cur.Deleted++
+		case inHunk:
+		case strings.HasPrefix(line, "new file mode"):
+			cur.Status = DiffAdded
+		case strings.HasPrefix(line, "deleted file mode"):
+			cur.Status = DiffDeleted
+		case strings.HasPrefix(line, "rename from "):
+			cur.Status = DiffRenamed
+			cur.OldPath = strings.TrimPrefix(line, "rename from ")
+		case strings.HasPrefix(line, "rename to "):
+			cur.Path = strings.TrimPrefix(line, "rename to ")
+		case strings.HasPrefix(line, "+++ b/"):
+			cur.Path = strings.TrimPrefix(line, "+++ b/")
+		case strings.HasPrefix(line, "--- a/") && cur.Status == DiffDeleted:
+			cur.Path = strings.TrimPrefix(line, "--- a/")
+		case strings.HasPrefix(line, "Binary files "):
+			cur.Binary = true
+		}
+	}
+	flush()
+	return files
+}
+
+// SquashMergeBack squash-merges sourceBranch into targetBranch as a single
+// commit with message, handling the same repository layouts as MergeBack.
+// Unlike a merge, it never fast-forwards: the target always gets exactly
+// one new commit.
+func SquashMergeBack(projectRoot, sourceBranch, targetBranch, message string) error {
+	squash := func(dir string) error {
+		out, err := exec.Command("git", "-C", dir, "merge", "--squash", sourceBranch).CombinedOutput()
+		if err != nil {
+			_, _ = exec.Command("git", "-C", dir, "reset", "--merge").CombinedOutput()
+			return fmt.Errorf("squash merge failed: %s: %w", strings.TrimSpace(string(out)), err)
+		}
+		if out, err := exec.Command("git", "-C", dir, "commit", "--no-verify", "-m", message).CombinedOutput(); err != nil {
+			_, _ = exec.Command("git", "-C", dir, "reset", "--merge").CombinedOutput()
+			return fmt.Errorf("commit squash merge: %s: %w", strings.TrimSpace(string(out)), err)
+		}
+		return nil
+	}
+
+	if IsGitRepo(projectRoot) && !IsBareRepo(projectRoot) {
+		co := exec.Command("git", "-C", projectRoot, "checkout", targetBranch)
+		if out, err := co.CombinedOutput(); err != nil {
+			return fmt.Errorf("checkout %s: %s: %w", targetBranch, strings.TrimSpace(string(out)), err)
+		}
+		return squash(projectRoot)
+	}
+	bareDir := projectRoot
+	if !IsBareRepo(projectRoot) {
+		if bareDir = findNestedBareRepo(projectRoot); bareDir == "" {
+			return fmt.Errorf("not a git repository or bare-repo project root: %s", projectRoot)
+		}
+	}
+	return inTemporaryWorktree(bareDir, targetBranch, squash)
+}
@@ -0,0 +1,135 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffBranch(t *testing.T) {
+	repo, wt := newSyncFixture(t)
+	commitFile(t, wt, "feature.txt", "one\ntwo\n")
+	commitFile(t, wt, "README.md", "# Test Repo\nmore\n")
+	runGit(t, wt, "mv", "feature.txt", "renamed.txt")
+	runGit(t, wt, "commit", "-q", "-m", "rename")
+	// Base moving on must not show up as the branch deleting it.
+	commitFile(t, repo, "base.txt", "base\n")
+	// Nor do uncommitted changes in the worktree.
+	if err := os.WriteFile(filepath.Join(wt, "scratch.txt"), []byte("x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := DiffBranch(wt, "main", "feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Commits != 3 {
+		t.Errorf("Commits = %d, want 3", d.Commits)
+	}
+	if len(d.Files) != 2 {
+		t.Fatalf("files = %+v, want README.md and renamed.txt", d.Files)
+	}
+	byPath := map[string]DiffFile{}
+	for _, f := range d.Files {
+		byPath[f.Path] = f
+	}
+	readme := byPath["README.md"]
+	if readme.Status != DiffModified || readme.Added != 2 || readme.Deleted != 1 {
+		t.Errorf("README.md = %+v", readme)
+	}
+	if !strings.Contains(readme.Patch, "+more") {
+		t.Errorf("README.md patch missing the change:\n%s", readme.Patch)
+	}
+	if added := byPath["renamed.txt"]; added.Status != DiffAdded || added.Added != 2 {
+		t.Errorf("renamed.txt = %+v, want added with 2 lines", added)
+	}
+	if a, del := d.Totals(); a != 4 || del != 1 {
+		t.Errorf("Totals = +%d -%d, want +4 -1", a, del)
+	}
+}
+
+func TestParseUnifiedDiff_RenameDeleteBinary(t *testing.T) {
+	patch := `diff --git a/old.go b/new.go
+similarity index 90%
+rename from old.go
+rename to new.go
+index 1111111..2222222 100644
+--- a/old.go
++++ b/new.go
+@@ -1,2 +1,2 @@
+-package old
++package new
+ // --- not a header
+diff --git a/gone.txt b/gone.txt
+deleted file mode 100644
+index 3333333..0000000
+--- a/gone.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+diff --git a/img.png b/img.png
+new file mode 100644
+index 0000000..4444444
+Binary files /dev/null and b/img.png differ
+`
+	files := ParseUnifiedDiff(patch)
+	if len(files) != 3 {
+		t.Fatalf("got %d files: %+v", len(files), files)
+	}
+	if f := files[0]; f.Path != "new.go" || f.OldPath != "old.go" || f.Status != DiffRenamed || f.Added != 1 || f.Deleted != 1 {
+		t.Errorf("rename = %+v", f)
+	}
+	if f := files[1]; f.Path != "gone.txt" || f.Status != DiffDeleted || f.Deleted != 1 {
+		t.Errorf("delete = %+v", f)
+	}
+	if f := files[2]; f.Path != "img.png" || f.Status != DiffAdded || !f.Binary {
+		t.Errorf("binary = %+v", f)
+	}
+}
+
+func TestSquashMergeBack(t *testing.T) {
+	repo, wt := newSyncFixture(t)
+	commitFile(t, wt, "a.txt", "a\n")
+	commitFile(t, wt, "b.txt", "b\n")
+	before := runGit(t, repo, "rev-list", "--count", "main")
+
+	if err := SquashMergeBack(repo, "feature", "main", "Add a and b"); err != nil {
+		t.Fatal(err)
+	}
+	if after := runGit(t, repo, "rev-list", "--count", "main"); after == before {
+		t.Fatal("main did not move")
+	}
+	if subject := runGit(t, repo, "log", "-1", "--format=%s", "main"); subject != "Add a and b" {
+		t.Errorf("subject = %q", subject)
+	}
+	if parents := runGit(t, repo, "log", "-1", "--format=%p", "main"); strings.Contains(parents, " ") {
+		t.Errorf("squash commit has several parents: %s", parents)
+	}
+	for _, f := range []string{"a.txt", "b.txt"} {
+		if _, err := os.Stat(filepath.Join(repo, f)); err != nil {
+			t.Errorf("%s not merged", f)
+		}
+	}
+}
+
+func TestWorktreeWorkStat_IncludesUncommitted(t *testing.T) {
+	repo, wt := newSyncFixture(t)
+	commitFile(t, wt, "feature.txt", "one\ntwo\n")
+	commitFile(t, repo, "base.txt", "base\n")
+	if err := os.WriteFile(filepath.Join(wt, "README.md"), []byte("# Test Repo\nedited\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(wt, "scratch.txt"), []byte("x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ws, err := WorktreeWorkStat(wt, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := WorkStat{Commits: 1, Files: 2, Added: 4, Deleted: 1, Untracked: 1}
+	if ws != want {
+		t.Errorf("WorktreeWorkStat = %+v, want %+v", ws, want)
+	}
+}
@@ -778,6 +778,71 @@ func SanitizeBranchName(name string) string {
 	return sanitized
 }
 
+// promptSlugMaxLen caps the length of a branch slug derived from a prompt.
+const promptSlugMaxLen = 40
+
+// promptSlugFillers are words dropped from a prompt-derived branch slug
+// unless nothing else is left.
+var promptSlugFillers = map[string]bool{
+	"a": true, "an": true, "the": true, "please": true, "can": true,
+	"you": true, "could": true, "would": true, "we": true, "i": true,
+}
+
+// BranchSlugFromPrompt derives a short branch name from a task prompt: the
+// first line, lowercased, reduced to ASCII words joined by dashes, without
+// filler words, cut at a word boundary near promptSlugMaxLen. Returns "task"
+// when the prompt has no usable words.
+func BranchSlugFromPrompt(prompt string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(prompt), "\n")
+	words := strings.FieldsFunc(strings.ToLower(line), func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	})
+	kept := make([]string, 0, len(words))
+	for _, w := range words {
+		if !promptSlugFillers[w] {
+			kept = append(kept, w)
+		}
+	}
+	if len(kept) == 0 {
+		kept = words
+	}
+
+	slug := ""
+	for _, w := range kept {
+		next := w
+		if slug != "" {
+			next = slug + "-" + w
+		}
+		if len(next) > promptSlugMaxLen {
+			if slug == "" {
+				slug = w[:promptSlugMaxLen]
+			}
+			break
+		}
+		slug = next
+	}
+	if slug == "" {
+		return "task"
+	}
+	return slug
+}
+
+// UniqueBranchName returns base, or base with the first free "-2", "-3", ...
+// suffix when taken reports it in use. After 99 attempts a random suffix is
+// used.
+func UniqueBranchName(base string, taken func(string) bool) string {
+	if !taken(base) {
+		return base
+	}
+	for n := 2; n < 100; n++ {
+		candidate := fmt.Sprintf("%s-%d", base, n)
+		if !taken(candidate) {
+			return candidate
+		}
+	}
+	return base + "-" + GeneratePathID()
+}
+
 // freshOriginDefaultBranchRef fetches the default branch from the default
 // remote and returns the remote-tracking ref (e.g. "origin/main") that callers
 // should base a new branch on. Returns ok=false when there is no remote, no
@@ -1011,6 +1076,30 @@ func DeleteBranch(repoDir, branchName string, force bool) error {
 	return nil
 }
 
+// RepairWorktrees re-links worktrees after the repository or the worktrees
+// were moved, so `git worktree list` and the worktrees' .git files agree again.
+func RepairWorktrees(repoDir string, worktreePaths ...string) error {
+	args := append([]string{"-C", repoDir, "worktree", "repair"}, worktreePaths...)
+	output, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to repair worktrees: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	return nil
+}
+
+// GetRemoteURL returns the URL of the repository's default remote.
+func GetRemoteURL(dir string) (string, error) {
+	remote, err := getDefaultRemote(dir)
+	if err != nil {
+		return "", err
+	}
+	out, err := exec.Command("git", "-C", dir, "remote", "get-url", remote).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get remote url: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
 // PruneWorktrees removes stale worktree references
 func PruneWorktrees(repoDir string) error {
 	cmd := exec.Command("git", "-C", repoDir, "worktree", "prune")
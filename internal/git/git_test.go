@@ -283,6 +283,36 @@ func TestValidateBranchName(t *testing.T) {
 	})
 }
 
+func TestBranchSlugFromPrompt(t *testing.T) {
+	cases := map[string]string{
+		"add dark mode toggle":                                                   "add-dark-mode-toggle",
+		"  Please fix the login bug!\nDetails follow.":                           "fix-login-bug",
+		"Refactor X: split parser.go (v2)":                                       "refactor-x-split-parser-go-v2",
+		"implement the extremely long-running background synchronisation worker": "implement-extremely-long-running",
+		"¿¡!!":  "task",
+		"the a": "the-a",
+	}
+	for prompt, want := range cases {
+		got := BranchSlugFromPrompt(prompt)
+		if got != want {
+			t.Errorf("BranchSlugFromPrompt(%q) = %q, want %q", prompt, got, want)
+		}
+		if err := ValidateBranchName("agent/" + got); err != nil {
+			t.Errorf("slug %q is not a valid branch name: %v", got, err)
+		}
+	}
+}
+
+func TestUniqueBranchName(t *testing.T) {
+	taken := map[string]bool{"agent/x": true, "agent/x-2": true}
+	if got := UniqueBranchName("agent/x", func(b string) bool { return taken[b] }); got != "agent/x-3" {
+		t.Errorf("UniqueBranchName = %q, want agent/x-3", got)
+	}
+	if got := UniqueBranchName("agent/y", func(b string) bool { return taken[b] }); got != "agent/y" {
+		t.Errorf("UniqueBranchName = %q, want agent/y", got)
+	}
+}
+
 func TestGenerateWorktreePath(t *testing.T) {
 	t.Run("generates sibling path with branch suffix", func(t *testing.T) {
 		repoDir := "/path/to/my-project"
@@ -1062,6 +1092,37 @@ func TestPruneWorktrees(t *testing.T) {
 	})
 }
 
+func TestRepairWorktrees(t *testing.T) {
+	t.Run("relinks worktree after the repo moved", func(t *testing.T) {
+		base := t.TempDir()
+		repo := filepath.Join(base, "repo")
+		if err := os.MkdirAll(repo, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		createTestRepo(t, repo)
+
+		worktreePath := filepath.Join(base, "wt")
+		if err := CreateWorktree(repo, worktreePath, "repair-test"); err != nil {
+			t.Fatalf("failed to create worktree: %v", err)
+		}
+
+		moved := filepath.Join(base, "moved")
+		if err := os.Rename(repo, moved); err != nil {
+			t.Fatal(err)
+		}
+		if IsGitRepo(worktreePath) {
+			t.Fatal("expected worktree to be broken after moving the repo")
+		}
+
+		if err := RepairWorktrees(moved, worktreePath); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !IsGitRepo(worktreePath) {
+			t.Error("expected worktree to resolve after repair")
+		}
+	})
+}
+
 func TestIsWorktree(t *testing.T) {
 	t.Run("returns false for main repo", func(t *testing.T) {
 		dir := t.TempDir()
@@ -69,6 +69,14 @@ func mergeBackInBareRepo(bareDir, sourceBranch, targetBranch string) error {
 		return nil
 	}
 
+	return inTemporaryWorktree(bareDir, targetBranch, func(dir string) error {
+		return MergeBranch(dir, sourceBranch)
+	})
+}
+
+// inTemporaryWorktree checks branch out in a throwaway worktree of the bare
+// repository at bareDir, runs fn in it, and removes the worktree again.
+func inTemporaryWorktree(bareDir, branch string, fn func(dir string) error) error {
 	tmpWT, err := os.MkdirTemp("", "agent-deck-mergeback-")
 	if err != nil {
 		return fmt.Errorf("create temp worktree dir: %w", err)
@@ -80,10 +88,10 @@ func mergeBackInBareRepo(bareDir, sourceBranch, targetBranch string) error {
 		_ = os.RemoveAll(filepath.Dir(tmpWT))
 	}()
 
-	if out, err := exec.Command("git", "-C", bareDir, "worktree", "add", tmpWT, targetBranch).CombinedOutput(); err != nil {
-		return fmt.Errorf("worktree add %s: %s: %w", targetBranch, strings.TrimSpace(string(out)), err)
+	if out, err := exec.Command("git", "-C", bareDir, "worktree", "add", tmpWT, branch).CombinedOutput(); err != nil {
+		return fmt.Errorf("worktree add %s: %s: %w", branch, strings.TrimSpace(string(out)), err)
 	}
-	return MergeBranch(tmpWT, sourceBranch)
+	return fn(tmpWT)
 }
 
 func revParseInDir(dir, ref string) (string, error) {
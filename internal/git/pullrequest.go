@@ -0,0 +1,359 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// PRProvider names the forge CLI used for pull requests.
+type PRProvider string
+
+const (
+	PRProviderGitHub PRProvider = "github" // gh
+	PRProviderGitLab PRProvider = "gitlab" // glab
+)
+
+// CLI returns the forge CLI binary for the provider.
+func (p PRProvider) CLI() string {
+	if p == PRProviderGitLab {
+		return "glab"
+	}
+	return "gh"
+}
+
+// PR states, normalized across providers.
+const (
+	PRStateOpen   = "open"
+	PRStateDraft  = "draft"
+	PRStateMerged = "merged"
+	PRStateClosed = "closed"
+)
+
+// CI rollups, normalized across providers. An empty Checks means the PR has
+// no checks.
+const (
+	PRChecksPassing = "passing"
+	PRChecksFailing = "failing"
+	PRChecksPending = "pending"
+)
+
+// PullRequest is a forge pull/merge request for a branch.
+type PullRequest struct {
+	Provider PRProvider `json:"provider"`
+	Number   int        `json:"number"`
+	URL      string     `json:"url"`
+	Title    string     `json:"title"`
+	State    string     `json:"state"`
+	Base     string     `json:"base,omitempty"`
+	Checks   string     `json:"checks,omitempty"`
+}
+
+// Summary renders the PR on one line, e.g. "#12 open · checks passing".
+func (pr *PullRequest) Summary() string {
+	s := fmt.Sprintf("#%d %s", pr.Number, pr.State)
+	if pr.Checks != "" {
+		s += " · checks " + pr.Checks
+	}
+	return s
+}
+
+// CreatePROptions configures CreatePullRequest.
+type CreatePROptions struct {
+	Base  string // target branch; empty lets the forge pick its default
+	Title string // empty fills title and body from the branch's commits
+	Body  string
+	Draft bool
+}
+
+// PR command timeouts: status is polled from the TUI and must not hang it;
+// create may have to wait on a slow forge.
+const (
+	prStatusTimeout = 20 * time.Second
+	prCreateTimeout = 2 * time.Minute
+)
+
+// runForgeCLI runs a forge CLI in dir. Tests replace it.
+var runForgeCLI = func(ctx context.Context, dir, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return out, fmt.Errorf("%s: %s", name, msg)
+		}
+		return out, fmt.Errorf("%s: %w", name, err)
+	}
+	return out, nil
+}
+
+// forgeCLIAvailable reports whether a forge CLI is on PATH. Tests replace it.
+var forgeCLIAvailable = func(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
+
+// DetectPRProvider picks the forge for repoDir from its default remote URL,
+// falling back to whichever forge CLI is installed.
+func DetectPRProvider(repoDir string) (PRProvider, error) {
+	if remote, err := getDefaultRemote(repoDir); err == nil {
+		out, err := exec.Command("git", "-C", repoDir, "remote", "get-url", remote).Output()
+		if err == nil {
+			if p, ok := prProviderForURL(strings.TrimSpace(string(out))); ok {
+				return p, nil
+			}
+		}
+	}
+	for _, p := range []PRProvider{PRProviderGitHub, PRProviderGitLab} {
+		if forgeCLIAvailable(p.CLI()) {
+			return p, nil
+		}
+	}
+	return "", errors.New("no forge CLI found (install gh or glab)")
+}
+
+func prProviderForURL(url string) (PRProvider, bool) {
+	u := strings.ToLower(url)
+	switch {
+	case strings.Contains(u, "github"):
+		return PRProviderGitHub, true
+	case strings.Contains(u, "gitlab"):
+		return PRProviderGitLab, true
+	}
+	return "", false
+}
+
+// errNoPullRequest marks forge output meaning "this branch has no PR".
+var errNoPullRequest = errors.New("no pull request")
+
+// GetPullRequest returns the PR whose head is branch, or nil when there is
+// none. dir is any checkout of the repository (typically the worktree).
+func GetPullRequest(dir string, provider PRProvider, branch string) (*PullRequest, error) {
+	if !forgeCLIAvailable(provider.CLI()) {
+		return nil, fmt.Errorf("%s is not installed", provider.CLI())
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), prStatusTimeout)
+	defer cancel()
+
+	var pr *PullRequest
+	var err error
+	switch provider {
+	case PRProviderGitLab:
+		var out []byte
+		out, err = runForgeCLI(ctx, dir, "glab", "mr", "view", branch, "--output", "json")
+		if err == nil {
+			pr, err = parseGitLabMR(out)
+		}
+	default:
+		var out []byte
+		out, err = runForgeCLI(ctx, dir, "gh", "pr", "view", branch,
+			"--json", "number,url,title,state,isDraft,baseRefName,statusCheckRollup")
+		if err == nil {
+			pr, err = parseGitHubPR(out)
+		}
+	}
+	if err != nil {
+		if isNoPullRequestError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return pr, nil
+}
+
+func isNoPullRequestError(err error) bool {
+	if errors.Is(err, errNoPullRequest) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "no pull requests found") ||
+		strings.Contains(msg, "no open merge request") ||
+		strings.Contains(msg, "merge request not found")
+}
+
+// CreatePullRequest opens a PR from branch with the forge CLI and returns the
+// URL it printed. The branch must already be pushed (see PushBranch).
+func CreatePullRequest(dir string, provider PRProvider, branch string, opts CreatePROptions) (string, error) {
+	if !forgeCLIAvailable(provider.CLI()) {
+		return "", fmt.Errorf("%s is not installed", provider.CLI())
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), prCreateTimeout)
+	defer cancel()
+
+	var args []string
+	switch provider {
+	case PRProviderGitLab:
+		args = []string{"mr", "create", "--source-branch", branch, "--yes"}
+		if opts.Base != "" {
+			args = append(args, "--target-branch", opts.Base)
+		}
+		if opts.Title != "" {
+			args = append(args, "--title", opts.Title, "--description", opts.Body)
+		} else {
+			args = append(args, "--fill")
+		}
+	default:
+		args = []string{"pr", "create", "--head", branch}
+		if opts.Base != "" {
+			args = append(args, "--base", opts.Base)
+		}
+		if opts.Title != "" {
+			args = append(args, "--title", opts.Title, "--body", opts.Body)
+		} else {
+			args = append(args, "--fill")
+		}
+	}
+	if opts.Draft {
+		args = append(args, "--draft")
+	}
+	out, err := runForgeCLI(ctx, dir, provider.CLI(), args...)
+	if err != nil {
+		return "", err
+	}
+	// Both CLIs end their output with the new PR's URL.
+	for _, line := range reverseLines(string(out)) {
+		if strings.HasPrefix(line, "https://") || strings.HasPrefix(line, "http://") {
+			return line, nil
+		}
+	}
+	return "", nil
+}
+
+// PushBranch pushes branch to the repository's default remote and sets it
+// as upstream, so the forge can see it.
+func PushBranch(dir, branch string) error {
+	remote, err := getDefaultRemote(dir)
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command("git", "-C", dir, "push", "--set-upstream", remote, branch)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git push %s %s: %s", remote, branch, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func reverseLines(s string) []string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	out := make([]string, 0, len(lines))
+	for i := len(lines) - 1; i >= 0; i-- {
+		out = append(out, strings.TrimSpace(lines[i]))
+	}
+	return out
+}
+
+func parseGitHubPR(data []byte) (*PullRequest, error) {
+	var raw struct {
+		Number      int    `json:"number"`
+		URL         string `json:"url"`
+		Title       string `json:"title"`
+		State       string `json:"state"`
+		IsDraft     bool   `json:"isDraft"`
+		BaseRefName string `json:"baseRefName"`
+		Checks      []struct {
+			Status     string `json:"status"`     // check runs
+			Conclusion string `json:"conclusion"` // check runs
+			State      string `json:"state"`      // commit statuses
+		} `json:"statusCheckRollup"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse gh output: %w", err)
+	}
+	if raw.Number == 0 {
+		return nil, errNoPullRequest
+	}
+	pr := &PullRequest{
+		Provider: PRProviderGitHub,
+		Number:   raw.Number,
+		URL:      raw.URL,
+		Title:    raw.Title,
+		Base:     raw.BaseRefName,
+	}
+	switch strings.ToUpper(raw.State) {
+	case "MERGED":
+		pr.State = PRStateMerged
+	case "CLOSED":
+		pr.State = PRStateClosed
+	default:
+		pr.State = PRStateOpen
+		if raw.IsDraft {
+			pr.State = PRStateDraft
+		}
+	}
+	for _, c := range raw.Checks {
+		result := strings.ToUpper(c.Conclusion)
+		if c.State != "" {
+			result = strings.ToUpper(c.State)
+		}
+		switch result {
+		case "FAILURE", "ERROR", "CANCELLED", "TIMED_OUT", "ACTION_REQUIRED", "STARTUP_FAILURE":
+			pr.Checks = PRChecksFailing
+		case "SUCCESS", "NEUTRAL", "SKIPPED":
+			if pr.Checks == "" {
+				pr.Checks = PRChecksPassing
+			}
+		default: // running check run (no conclusion yet), PENDING, EXPECTED
+			if pr.Checks != PRChecksFailing {
+				pr.Checks = PRChecksPending
+			}
+		}
+	}
+	return pr, nil
+}
+
+func parseGitLabMR(data []byte) (*PullRequest, error) {
+	var raw struct {
+		IID          int    `json:"iid"`
+		WebURL       string `json:"web_url"`
+		Title        string `json:"title"`
+		State        string `json:"state"`
+		Draft        bool   `json:"draft"`
+		TargetBranch string `json:"target_branch"`
+		HeadPipeline *struct {
+			Status string `json:"status"`
+		} `json:"head_pipeline"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse glab output: %w", err)
+	}
+	if raw.IID == 0 {
+		return nil, errNoPullRequest
+	}
+	pr := &PullRequest{
+		Provider: PRProviderGitLab,
+		Number:   raw.IID,
+		URL:      raw.WebURL,
+		Title:    raw.Title,
+		Base:     raw.TargetBranch,
+	}
+	switch strings.ToLower(raw.State) {
+	case "merged":
+		pr.State = PRStateMerged
+	case "closed", "locked":
+		pr.State = PRStateClosed
+	default:
+		pr.State = PRStateOpen
+		if raw.Draft {
+			pr.State = PRStateDraft
+		}
+	}
+	if raw.HeadPipeline != nil {
+		switch strings.ToLower(raw.HeadPipeline.Status) {
+		case "success", "skipped":
+			pr.Checks = PRChecksPassing
+		case "failed", "canceled":
+			pr.Checks = PRChecksFailing
+		case "":
+		default:
+			pr.Checks = PRChecksPending
+		}
+	}
+	return pr, nil
+}
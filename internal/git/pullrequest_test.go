@@ -0,0 +1,115 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// stubForgeCLI replaces the forge CLI runner for one test and records the
+// argv of every call.
+func stubForgeCLI(t *testing.T, out string, err error) *[][]string {
+	t.Helper()
+	var calls [][]string
+	origRun, origAvail := runForgeCLI, forgeCLIAvailable
+	runForgeCLI = func(_ context.Context, _ string, name string, args ...string) ([]byte, error) {
+		calls = append(calls, append([]string{name}, args...))
+		return []byte(out), err
+	}
+	forgeCLIAvailable = func(string) bool { return true }
+	t.Cleanup(func() { runForgeCLI, forgeCLIAvailable = origRun, origAvail })
+	return &calls
+}
+
+func TestParseGitHubPR(t *testing.T) {
+	tests := []struct {
+		name       string
+		json       string
+		wantState  string
+		wantChecks string
+	}{
+		{"open passing", `{"number":12,"state":"OPEN","statusCheckRollup":[{"status":"COMPLETED","conclusion":"SUCCESS"},{"state":"SUCCESS"}]}`, PRStateOpen, PRChecksPassing},
+		{"draft pending", `{"number":12,"state":"OPEN","isDraft":true,"statusCheckRollup":[{"status":"IN_PROGRESS","conclusion":""},{"conclusion":"SUCCESS"}]}`, PRStateDraft, PRChecksPending},
+		{"failing wins", `{"number":12,"state":"OPEN","statusCheckRollup":[{"status":"IN_PROGRESS"},{"conclusion":"FAILURE"},{"conclusion":"SUCCESS"}]}`, PRStateOpen, PRChecksFailing},
+		{"merged no checks", `{"number":12,"state":"MERGED","statusCheckRollup":[]}`, PRStateMerged, ""},
+		{"closed", `{"number":12,"state":"CLOSED"}`, PRStateClosed, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr, err := parseGitHubPR([]byte(tt.json))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pr.State != tt.wantState || pr.Checks != tt.wantChecks {
+				t.Errorf("state=%q checks=%q, want %q %q", pr.State, pr.Checks, tt.wantState, tt.wantChecks)
+			}
+		})
+	}
+}
+
+func TestParseGitLabMR(t *testing.T) {
+	pr, err := parseGitLabMR([]byte(`{"iid":7,"web_url":"https://gitlab.com/a/b/-/merge_requests/7","state":"opened","draft":true,"target_branch":"main","head_pipeline":{"status":"failed"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr.Number != 7 || pr.State != PRStateDraft || pr.Checks != PRChecksFailing || pr.Base != "main" {
+		t.Errorf("pr = %+v", pr)
+	}
+	if got := pr.Summary(); got != "#7 draft · checks failing" {
+		t.Errorf("Summary() = %q", got)
+	}
+
+	pr, err = parseGitLabMR([]byte(`{"iid":8,"state":"merged"}`))
+	if err != nil || pr.State != PRStateMerged || pr.Checks != "" {
+		t.Errorf("merged MR = %+v, %v", pr, err)
+	}
+}
+
+func TestGetPullRequest_NoPR(t *testing.T) {
+	stubForgeCLI(t, "", errors.New(`gh: no pull requests found for branch "feature"`))
+	pr, err := GetPullRequest(t.TempDir(), PRProviderGitHub, "feature")
+	if err != nil || pr != nil {
+		t.Errorf("GetPullRequest = %+v, %v; want nil, nil", pr, err)
+	}
+}
+
+func TestCreatePullRequest_Args(t *testing.T) {
+	calls := stubForgeCLI(t, "Creating pull request for feature into main\n\nhttps://github.com/a/b/pull/3\n", nil)
+	url, err := CreatePullRequest(t.TempDir(), PRProviderGitHub, "feature", CreatePROptions{Base: "main", Draft: true})
+	if err != nil || url != "https://github.com/a/b/pull/3" {
+		t.Fatalf("CreatePullRequest = %q, %v", url, err)
+	}
+	got := strings.Join((*calls)[0], " ")
+	if got != "gh pr create --head feature --base main --fill --draft" {
+		t.Errorf("argv = %q", got)
+	}
+
+	calls = stubForgeCLI(t, "https://gitlab.com/a/b/-/merge_requests/4\n", nil)
+	if _, err := CreatePullRequest(t.TempDir(), PRProviderGitLab, "feature", CreatePROptions{Title: "Add x", Body: "why"}); err != nil {
+		t.Fatal(err)
+	}
+	got = strings.Join((*calls)[0], " ")
+	if got != "glab mr create --source-branch feature --yes --title Add x --description why" {
+		t.Errorf("argv = %q", got)
+	}
+}
+
+func TestDetectPRProvider_FromRemote(t *testing.T) {
+	dir := t.TempDir()
+	createTestRepo(t, dir)
+	for url, want := range map[string]PRProvider{
+		"git@gitlab.example.com:team/app.git": PRProviderGitLab,
+		"https://github.com/team/app.git":     PRProviderGitHub,
+	} {
+		_ = exec.Command("git", "-C", dir, "remote", "remove", "origin").Run()
+		if err := exec.Command("git", "-C", dir, "remote", "add", "origin", url).Run(); err != nil {
+			t.Fatal(err)
+		}
+		got, err := DetectPRProvider(dir)
+		if err != nil || got != want {
+			t.Errorf("DetectPRProvider(%s) = %q, %v; want %q", url, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// SyncStrategy is how SyncWorktree brings a branch up to date with its base.
+type SyncStrategy string
+
+const (
+	SyncRebase SyncStrategy = "rebase"
+	SyncMerge  SyncStrategy = "merge"
+)
+
+// ParseSyncStrategy validates a strategy name; empty selects SyncRebase.
+func ParseSyncStrategy(s string) (SyncStrategy, error) {
+	switch SyncStrategy(strings.ToLower(strings.TrimSpace(s))) {
+	case "", SyncRebase:
+		return SyncRebase, nil
+	case SyncMerge:
+		return SyncMerge, nil
+	}
+	return "", fmt.Errorf("unknown sync strategy %q (use rebase or merge)", s)
+}
+
+// ErrWorktreeDirty is returned by SyncWorktree when the worktree has
+// uncommitted changes; rebasing or merging over them is never attempted.
+var ErrWorktreeDirty = errors.New("worktree has uncommitted changes")
+
+// SyncResult describes one SyncWorktree run.
+type SyncResult struct {
+	// Upstream is the ref the branch was synced onto, e.g. "origin/main",
+	// or the local base branch when the repository has no remote.
+	Upstream string `json:"upstream"`
+	// Behind is how many upstream commits the branch was missing.
+	Behind int `json:"behind"`
+	// Updated is true when the branch was rebased or merged.
+	Updated bool `json:"updated"`
+	// Conflict is true when the rebase or merge stopped on conflicts. The
+	// operation is aborted, so the worktree is left as it was.
+	Conflict      bool     `json:"conflict,omitempty"`
+	ConflictFiles []string `json:"conflict_files,omitempty"`
+}
+
+// SyncWorktree fetches base from the default remote and rebases (or merges)
+// the branch checked out in worktreePath onto it. A conflicting sync is
+// aborted and reported through SyncResult.Conflict rather than an error, so
+// an agent working in the worktree never finds it stuck mid-rebase.
+func SyncWorktree(worktreePath, base string, strategy SyncStrategy) (*SyncResult, error) {
+	if base == "" {
+		return nil, errors.New("no base branch")
+	}
+	dirty, err := HasUncommittedChanges(worktreePath)
+	if err != nil {
+		return nil, err
+	}
+	if dirty {
+		return nil, ErrWorktreeDirty
+	}
+
+	upstream := base
+	if remote, err := getDefaultRemote(worktreePath); err == nil {
+		fetch := exec.Command("git", "-C", worktreePath, "fetch", "--quiet", remote, base)
+		if out, err := fetch.CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("git fetch %s %s: %s", remote, base, strings.TrimSpace(string(out)))
+		}
+		upstream = remote + "/" + base
+	}
+
+	count, err := exec.Command("git", "-C", worktreePath, "rev-list", "--count", "HEAD.."+upstream).Output()
+	if err != nil {
+		return nil, fmt.Errorf("compare with %s: %w", upstream, err)
+	}
+	result := &SyncResult{Upstream: upstream}
+	result.Behind, _ = strconv.Atoi(strings.TrimSpace(string(count)))
+	if result.Behind == 0 {
+		return result, nil
+	}
+
+	var args []string
+	if strategy == SyncMerge {
+		args = []string{"merge", "--no-edit", upstream}
+	} else {
+		args = []string{"rebase", upstream}
+	}
+	out, err := exec.Command("git", append([]string{"-C", worktreePath}, args...)...).CombinedOutput()
+	if err == nil {
+		result.Updated = true
+		return result, nil
+	}
+
+	files, _ := conflictedFiles(worktreePath)
+	abort := exec.Command("git", "-C", worktreePath, args[0], "--abort")
+	if abortOut, abortErr := abort.CombinedOutput(); abortErr != nil {
+		return nil, fmt.Errorf("%s onto %s failed and could not be aborted: %s", args[0], upstream, strings.TrimSpace(string(abortOut)))
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%s onto %s failed: %s", args[0], upstream, strings.TrimSpace(string(out)))
+	}
+	result.Conflict = true
+	result.ConflictFiles = files
+	return result, nil
+}
+
+// conflictedFiles lists the unmerged paths in dir.
+func conflictedFiles(dir string) ([]string, error) {
+	out, err := exec.Command("git", "-C", dir, "diff", "--name-only", "--diff-filter=U").Output()
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,88 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// commitFile writes content to name in dir and commits it.
+func commitFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{{"add", name}, {"commit", "-q", "-m", "edit " + name}} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s", args, out)
+		}
+	}
+}
+
+// newSyncFixture returns a repo on main plus a worktree on "feature" that
+// branched off it.
+func newSyncFixture(t *testing.T) (repo, wt string) {
+	t.Helper()
+	repo = t.TempDir()
+	createTestRepo(t, repo)
+	wt = filepath.Join(t.TempDir(), "feature")
+	if err := CreateWorktree(repo, wt, "feature"); err != nil {
+		t.Fatal(err)
+	}
+	return repo, wt
+}
+
+func TestSyncWorktree_Rebase(t *testing.T) {
+	repo, wt := newSyncFixture(t)
+	commitFile(t, wt, "feature.txt", "feature\n")
+	commitFile(t, repo, "base.txt", "base\n")
+
+	res, err := SyncWorktree(wt, "main", SyncRebase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Updated || res.Behind != 1 || res.Upstream != "main" || res.Conflict {
+		t.Fatalf("result = %+v", res)
+	}
+	if _, err := os.Stat(filepath.Join(wt, "base.txt")); err != nil {
+		t.Error("base commit not in worktree after rebase")
+	}
+
+	res, err = SyncWorktree(wt, "main", SyncRebase)
+	if err != nil || res.Updated || res.Behind != 0 {
+		t.Errorf("second sync = %+v, %v; want up to date", res, err)
+	}
+}
+
+func TestSyncWorktree_ConflictIsAborted(t *testing.T) {
+	for _, strategy := range []SyncStrategy{SyncRebase, SyncMerge} {
+		t.Run(string(strategy), func(t *testing.T) {
+			repo, wt := newSyncFixture(t)
+			commitFile(t, wt, "README.md", "feature side\n")
+			commitFile(t, repo, "README.md", "main side\n")
+
+			res, err := SyncWorktree(wt, "main", strategy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !res.Conflict || res.Updated || len(res.ConflictFiles) != 1 || res.ConflictFiles[0] != "README.md" {
+				t.Fatalf("result = %+v", res)
+			}
+			if dirty, _ := HasUncommittedChanges(wt); dirty {
+				t.Error("worktree left dirty after aborted sync")
+			}
+		})
+	}
+}
+
+func TestSyncWorktree_DirtyRefused(t *testing.T) {
+	_, wt := newSyncFixture(t)
+	if err := os.WriteFile(filepath.Join(wt, "scratch.txt"), []byte("wip"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SyncWorktree(wt, "main", SyncRebase); !errors.Is(err, ErrWorktreeDirty) {
+		t.Errorf("err = %v, want ErrWorktreeDirty", err)
+	}
+}
@@ -244,9 +244,10 @@ func (b *JJBackend) PruneWorktrees() error {
 	return nil
 }
 
-// HasUncommittedChanges checks if the working copy has uncommitted changes.
-func (b *JJBackend) HasUncommittedChanges() (bool, error) {
-	cmd := exec.Command("jj", "diff", "--stat", "-R", b.repoDir, "--ignore-working-copy") // #nosec G204 -- jj invocations with slice args, not shell-formed
+// HasUncommittedChanges checks if the workspace at workspacePath has
+// uncommitted changes. Snapshots the working copy so fresh edits count.
+func (b *JJBackend) HasUncommittedChanges(workspacePath string) (bool, error) {
+	cmd := exec.Command("jj", "diff", "--stat", "-R", workspacePath) // #nosec G204 -- jj invocations with slice args, not shell-formed
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return false, fmt.Errorf("failed to check jj diff: %s: %w", strings.TrimSpace(string(output)), err)
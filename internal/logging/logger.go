@@ -25,6 +25,8 @@ const (
 	CompHTTP    = "http"
 	CompWeb     = "web"
 	CompWatcher = "watcher"
+	CompRelay   = "relay"
+	CompControl = "control"
 )
 
 // Config holds logging configuration.
@@ -251,7 +253,9 @@ func IsDebugEnabled() bool {
 
 // TraceOp starts a timed span for a named operation. Call the returned function
 // to finish the span. If elapsed exceeds warnThreshold, logs at Warn level;
-// otherwise logs at Debug level. Returns a zero-cost no-op when debug is off.
+// otherwise logs at Debug level. With tracing on (see InitTracing) the
+// operation is also exported as a root span carrying attrs. Returns a
+// zero-cost no-op when debug and tracing are both off.
 //
 // Usage:
 //
@@ -259,11 +263,16 @@ func IsDebugEnabled() bool {
 //	    slog.String("session", name))
 //	defer finish()
 func TraceOp(logger *slog.Logger, op string, warnThreshold time.Duration, attrs ...slog.Attr) func() {
+	_, span := StartSpan(context.Background(), op, attrs...)
 	if !debugEnabled.Load() {
-		return func() {}
+		if span.span == nil {
+			return func() {}
+		}
+		return span.End
 	}
 	start := time.Now()
 	return func() {
+		span.End()
 		elapsed := time.Since(start)
 		args := make([]any, 0, len(attrs)*2+2)
 		args = append(args, slog.Duration("elapsed", elapsed))
@@ -0,0 +1,156 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OpenTelemetry tracing for the hot paths behind TUI stutter: the status
+// tick, per-session status updates, capture-pane, the tmux session cache
+// refresh, status classification and storage saves. Spans are exported over
+// OTLP/HTTP to any collector (Jaeger, Tempo, Honeycomb, ...).
+//
+// Tracing is off unless InitTracing is called with Enabled; until then
+// StartSpan returns a zero Span that does nothing. Operations
+// that take no context.Context (most of internal/tmux) start root spans
+// tagged with the session name, so look them up next to the tick span that
+// ran them.
+
+// tracerName is the instrumentation scope of every agent-deck span.
+const tracerName = "github.com/asheshgoplani/agent-deck"
+
+// defaultTracingEndpoint is a collector on this machine (Jaeger's
+// all-in-one image listens here).
+const defaultTracingEndpoint = "http://localhost:4318"
+
+var tracingEnabled atomic.Bool
+
+// TracingConfig configures InitTracing.
+type TracingConfig struct {
+	Enabled bool
+	// Endpoint is the OTLP/HTTP collector URL, e.g. http://localhost:4318.
+	// Empty uses the standard OTEL_EXPORTER_OTLP_* environment variables,
+	// falling back to defaultTracingEndpoint.
+	Endpoint string
+	// SampleRatio is the fraction of root spans kept (0 means 1.0).
+	SampleRatio    float64
+	ServiceName    string
+	ServiceVersion string
+}
+
+// InitTracing installs the OTLP exporter as the global tracer provider. The
+// returned function flushes pending spans and must be called on exit. With
+// Enabled false it does nothing and returns a no-op shutdown.
+func InitTracing(ctx context.Context, cfg TracingConfig) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+	if !cfg.Enabled {
+		return noop, nil
+	}
+	endpoint := cfg.Endpoint
+	if endpoint == "" && os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		endpoint = defaultTracingEndpoint
+	}
+	var opts []otlptracehttp.Option
+	if endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpointURL(endpoint))
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return noop, fmt.Errorf("create OTLP exporter: %w", err)
+	}
+
+	name := cfg.ServiceName
+	if name == "" {
+		name = "agent-deck"
+	}
+	res := resource.NewSchemaless(
+		attribute.String("service.name", name),
+		attribute.String("service.version", cfg.ServiceVersion),
+	)
+	ratio := cfg.SampleRatio
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(provider)
+	tracingEnabled.Store(true)
+	Logger().Info("tracing_enabled", slog.String("endpoint", endpoint), slog.Float64("sample_ratio", ratio))
+
+	return func(ctx context.Context) error {
+		tracingEnabled.Store(false)
+		return provider.Shutdown(ctx)
+	}, nil
+}
+
+// TracingEnabled reports whether spans are being exported.
+func TracingEnabled() bool {
+	return tracingEnabled.Load()
+}
+
+// Span is an operation being traced. The zero Span (tracing off) ignores
+// every call.
+type Span struct {
+	span trace.Span
+}
+
+// SetAttrs adds attributes known only once the operation has run.
+func (s Span) SetAttrs(attrs ...slog.Attr) {
+	if s.span != nil {
+		s.span.SetAttributes(spanAttrs(attrs)...)
+	}
+}
+
+// End finishes the span.
+func (s Span) End() {
+	if s.span != nil {
+		s.span.End()
+	}
+}
+
+// StartSpan starts a span named op as a child of any span in ctx. End the
+// returned span when the operation finishes.
+//
+//	ctx, span := logging.StartSpan(ctx, "status_tick", slog.Int("sessions", n))
+//	defer span.End()
+func StartSpan(ctx context.Context, op string, attrs ...slog.Attr) (context.Context, Span) {
+	if !tracingEnabled.Load() {
+		return ctx, Span{}
+	}
+	ctx, span := otel.Tracer(tracerName).Start(ctx, op, trace.WithAttributes(spanAttrs(attrs)...))
+	return ctx, Span{span: span}
+}
+
+// spanAttrs converts slog attributes to span attributes.
+func spanAttrs(attrs []slog.Attr) []attribute.KeyValue {
+	out := make([]attribute.KeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		v := a.Value.Resolve()
+		switch v.Kind() {
+		case slog.KindString:
+			out = append(out, attribute.String(a.Key, v.String()))
+		case slog.KindInt64:
+			out = append(out, attribute.Int64(a.Key, v.Int64()))
+		case slog.KindBool:
+			out = append(out, attribute.Bool(a.Key, v.Bool()))
+		case slog.KindFloat64:
+			out = append(out, attribute.Float64(a.Key, v.Float64()))
+		default:
+			out = append(out, attribute.String(a.Key, v.String()))
+		}
+	}
+	return out
+}
@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStartSpan_DisabledIsNoop(t *testing.T) {
+	ctx, span := StartSpan(context.Background(), "op", slog.String("k", "v"))
+	if span.span != nil || ctx != context.Background() {
+		t.Fatal("tracing off: want zero Span and the same ctx")
+	}
+	span.SetAttrs(slog.Int("n", 1))
+	span.End()
+}
+
+func TestStartSpan_ExportsNestedSpansAndTraceOp(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	tracingEnabled.Store(true)
+	t.Cleanup(func() {
+		tracingEnabled.Store(false)
+		otel.SetTracerProvider(prev)
+	})
+
+	ctx, tick := StartSpan(context.Background(), "status_tick", slog.Int("sessions", 3))
+	_, child := StartSpan(ctx, "update_status", slog.String("session", "api"))
+	child.SetAttrs(slog.String("status", "waiting"))
+	child.End()
+	tick.End()
+	TraceOp(slog.Default(), "capture_pane_subprocess", time.Second, slog.String("session", "api"))()
+
+	ended := recorder.Ended()
+	if len(ended) != 3 {
+		t.Fatalf("ended %d spans, want 3", len(ended))
+	}
+	upd, parent, op := ended[0], ended[1], ended[2]
+	if upd.Name() != "update_status" || upd.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("update_status not a child of status_tick: %s parent %s", upd.Name(), upd.Parent().SpanID())
+	}
+	want := map[attribute.Key]string{"session": "api", "status": "waiting"}
+	for _, kv := range upd.Attributes() {
+		if w, ok := want[kv.Key]; ok && kv.Value.AsString() == w {
+			delete(want, kv.Key)
+		}
+	}
+	if len(want) != 0 {
+		t.Errorf("update_status missing attributes %v", want)
+	}
+	if op.Name() != "capture_pane_subprocess" || op.Parent().IsValid() {
+		t.Errorf("TraceOp span = %s (parent valid %v), want a root capture_pane_subprocess", op.Name(), op.Parent().IsValid())
+	}
+}
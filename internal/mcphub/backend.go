@@ -0,0 +1,310 @@
+package mcphub
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// errBackendClosed fails calls to a backend whose process has exited.
+var errBackendClosed = errors.New("backend closed")
+
+// tool is one backend tool as the hub advertises it.
+type tool struct {
+	hubName  string          // "<backend>__<tool>"
+	origName string          // the backend's own name
+	def      json.RawMessage // the backend's tool object with name rewritten
+}
+
+// backend is a stdio MCP server the hub runs on behalf of the client.
+type backend struct {
+	name string
+	spec Server
+	log  *slog.Logger
+
+	// ctx bounds startup; stop cancels it so a slow handshake can't delay
+	// removing the backend.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+
+	writeMu sync.Mutex
+	nextID  atomic.Int64
+
+	pendingMu sync.Mutex
+	pending   map[int64]chan *message
+
+	toolsMu sync.RWMutex
+	tools   []tool
+
+	// ready is closed once the handshake finished (or failed); done once the
+	// process exited.
+	ready chan struct{}
+	done  chan struct{}
+
+	// onToolsChanged is called when the tool list changes after startup.
+	onToolsChanged func()
+}
+
+func newBackend(ctx context.Context, name string, spec Server, log *slog.Logger, onToolsChanged func()) *backend {
+	ctx, cancel := context.WithCancel(ctx)
+	return &backend{
+		ctx:            ctx,
+		cancel:         cancel,
+		name:           name,
+		spec:           spec,
+		log:            log.With(slog.String("mcp", name)),
+		pending:        make(map[int64]chan *message),
+		ready:          make(chan struct{}),
+		done:           make(chan struct{}),
+		onToolsChanged: onToolsChanged,
+	}
+}
+
+// start launches the server and runs the MCP handshake. The backend is usable
+// once ready is closed; a failed start leaves it with no tools.
+func (b *backend) start() {
+	defer close(b.ready)
+
+	cmd := exec.Command(b.spec.Command, b.spec.Args...)
+	// Same environment Claude would give the server if it launched it itself.
+	cmd.Env = os.Environ()
+	for k, v := range b.spec.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	// Own process group, so npx/uvx grandchildren die with it.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		b.fail(err)
+		return
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		b.fail(err)
+		return
+	}
+	if err := cmd.Start(); err != nil {
+		b.fail(err)
+		return
+	}
+	b.cmd, b.stdin = cmd, stdin
+	b.log.Info("backend_started", slog.Int("pid", cmd.Process.Pid))
+	go b.readLoop(stdout)
+
+	ctx, cancel := context.WithTimeout(b.ctx, startTimeout)
+	defer cancel()
+	if _, err := b.call(ctx, "initialize", map[string]any{
+		"protocolVersion": protocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]any{"name": serverName, "version": serverVersion},
+	}); err != nil {
+		b.log.Error("backend_initialize_failed", slog.String("error", err.Error()))
+		b.kill()
+		return
+	}
+	_ = b.send(&message{Method: "notifications/initialized"})
+	if err := b.refreshTools(ctx); err != nil {
+		b.log.Error("backend_tools_failed", slog.String("error", err.Error()))
+	}
+}
+
+func (b *backend) fail(err error) {
+	b.log.Error("backend_start_failed", slog.String("error", err.Error()))
+	close(b.done)
+}
+
+// stop aborts a pending startup and terminates the server.
+func (b *backend) stop() {
+	b.cancel()
+	<-b.ready
+	b.kill()
+}
+
+// kill terminates the server's process group.
+func (b *backend) kill() {
+	if b.cmd == nil {
+		return
+	}
+	_ = b.stdin.Close()
+	_ = syscall.Kill(-b.cmd.Process.Pid, syscall.SIGTERM)
+	select {
+	case <-b.done:
+	case <-time.After(3 * time.Second):
+		_ = syscall.Kill(-b.cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
+
+// readLoop dispatches the server's responses and notifications until it
+// exits, then fails every pending call.
+func (b *backend) readLoop(stdout io.Reader) {
+	defer func() {
+		_ = b.cmd.Wait()
+		b.pendingMu.Lock()
+		for id, ch := range b.pending {
+			close(ch)
+			delete(b.pending, id)
+		}
+		b.pendingMu.Unlock()
+		close(b.done)
+		b.log.Info("backend_exited")
+	}()
+
+	r := bufio.NewReaderSize(stdout, 64*1024)
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 {
+			b.dispatch(line)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (b *backend) dispatch(line []byte) {
+	var msg message
+	if err := json.Unmarshal(line, &msg); err != nil {
+		b.log.Debug("backend_bad_json", slog.String("error", err.Error()))
+		return
+	}
+	switch {
+	case msg.Method == "notifications/tools/list_changed":
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), startTimeout)
+			defer cancel()
+			if err := b.refreshTools(ctx); err == nil && b.onToolsChanged != nil {
+				b.onToolsChanged()
+			}
+		}()
+	case msg.Method != "" && msg.ID != nil:
+		// The hub offers backends no client capabilities (roots, sampling, …).
+		_ = b.send(&message{ID: msg.ID, Error: &rpcError{Code: codeMethodNotFound, Message: "not supported by " + serverName}})
+	case msg.Method == "" && msg.ID != nil:
+		var id int64
+		if json.Unmarshal(msg.ID, &id) != nil {
+			return
+		}
+		b.pendingMu.Lock()
+		ch, ok := b.pending[id]
+		delete(b.pending, id)
+		b.pendingMu.Unlock()
+		if ok {
+			ch <- &msg
+		}
+	}
+}
+
+func (b *backend) send(msg *message) error {
+	msg.JSONRPC = "2.0"
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	b.writeMu.Lock()
+	defer b.writeMu.Unlock()
+	_, err = b.stdin.Write(append(data, '\n'))
+	return err
+}
+
+// call sends a request and waits for its result. A JSON-RPC error reply is
+// returned as *rpcError.
+func (b *backend) call(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	id := b.nextID.Add(1)
+	ch := make(chan *message, 1)
+	b.pendingMu.Lock()
+	b.pending[id] = ch
+	b.pendingMu.Unlock()
+	defer func() {
+		b.pendingMu.Lock()
+		delete(b.pending, id)
+		b.pendingMu.Unlock()
+	}()
+
+	rawID, _ := json.Marshal(id)
+	if err := b.send(&message{ID: rawID, Method: method, Params: rawParams}); err != nil {
+		return nil, err
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-b.done:
+		return nil, errBackendClosed
+	case resp, ok := <-ch:
+		if !ok {
+			return nil, errBackendClosed
+		}
+		if resp.Error != nil {
+			return nil, resp.Error
+		}
+		return resp.Result, nil
+	}
+}
+
+// refreshTools re-reads the server's tool list, following pagination.
+func (b *backend) refreshTools(ctx context.Context) error {
+	var tools []tool
+	cursor := ""
+	for {
+		params := map[string]any{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+		raw, err := b.call(ctx, "tools/list", params)
+		if err != nil {
+			return err
+		}
+		var page struct {
+			Tools      []map[string]json.RawMessage `json:"tools"`
+			NextCursor string                       `json:"nextCursor"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return fmt.Errorf("bad tools/list result: %w", err)
+		}
+		for _, t := range page.Tools {
+			var name string
+			if json.Unmarshal(t["name"], &name) != nil || name == "" {
+				continue
+			}
+			hubName := b.name + toolSeparator + name
+			t["name"], _ = json.Marshal(hubName)
+			def, err := json.Marshal(t)
+			if err != nil {
+				continue
+			}
+			tools = append(tools, tool{hubName: hubName, origName: name, def: def})
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	b.toolsMu.Lock()
+	b.tools = tools
+	b.toolsMu.Unlock()
+	b.log.Info("backend_tools", slog.Int("count", len(tools)))
+	return nil
+}
+
+func (b *backend) toolList() []tool {
+	b.toolsMu.RLock()
+	defer b.toolsMu.RUnlock()
+	return b.tools
+}
@@ -0,0 +1,396 @@
+// Package mcphub is the MCP hot-swap hub. Claude sessions with
+// mcp_hot_swap enabled get a single "agent-deck-hub" server in .mcp.json
+// instead of one entry per MCP; the hub runs the MCPs listed in a manifest
+// file, serves their tools under "<mcp>__<tool>" names, and re-reads the
+// manifest whenever it changes. Attaching or detaching an MCP only rewrites
+// the manifest, and the hub tells the client with
+// notifications/tools/list_changed — the session keeps its conversation.
+package mcphub
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	serverName    = "agent-deck-hub"
+	serverVersion = "1.0.0"
+	// protocolVersion is offered to backends and to clients that don't
+	// state one.
+	protocolVersion = "2025-06-18"
+	toolSeparator   = "__"
+
+	// startTimeout bounds a backend's handshake; npx may have to download
+	// the package first.
+	startTimeout = 60 * time.Second
+	// listWait is how long tools/list waits for backends still starting.
+	listWait = 10 * time.Second
+
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// Manifest is the set of MCPs the hub serves, keyed by name. agent-deck
+// writes it next to the project's .mcp.json.
+type Manifest struct {
+	Servers map[string]Server `json:"servers"`
+}
+
+// Server is how to launch one stdio MCP (pooled MCPs are launched through
+// `agent-deck mcp-proxy <socket>`).
+type Server struct {
+	Command string            `json:"command"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+}
+
+// ReadManifest reads the manifest at path. A missing file is an empty one.
+func ReadManifest(path string) (Manifest, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Manifest{}, nil
+	}
+	if err != nil {
+		return Manifest{}, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return Manifest{}, fmt.Errorf("invalid hub manifest %s: %w", path, err)
+	}
+	return m, nil
+}
+
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *rpcError) Error() string { return e.Message }
+
+// Hub serves one MCP client over stdio.
+type Hub struct {
+	manifestPath string
+	log          *slog.Logger
+	pollInterval time.Duration
+
+	outMu sync.Mutex
+	out   io.Writer
+
+	mu          sync.Mutex
+	backends    map[string]*backend
+	initialized bool
+}
+
+// New creates a hub serving the MCPs listed in manifestPath.
+func New(manifestPath string, log *slog.Logger) *Hub {
+	return &Hub{
+		manifestPath: manifestPath,
+		log:          log,
+		pollInterval: time.Second,
+		backends:     make(map[string]*backend),
+	}
+}
+
+// Serve speaks MCP on in/out until in closes or ctx ends, then stops every
+// backend.
+func (h *Hub) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	h.out = out
+
+	m, err := ReadManifest(h.manifestPath)
+	if err != nil {
+		h.log.Error("manifest_unreadable", slog.String("error", err.Error()))
+	}
+	h.apply(ctx, m)
+	go h.watch(ctx)
+	defer h.stopAll()
+
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		r := bufio.NewReaderSize(in, 64*1024)
+		for {
+			line, err := r.ReadBytes('\n')
+			if len(line) > 0 {
+				select {
+				case lines <- line:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				readErr <- err
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-readErr:
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		case line := <-lines:
+			var msg message
+			if err := json.Unmarshal(line, &msg); err != nil {
+				h.reply(nil, nil, &rpcError{Code: -32700, Message: "parse error"})
+				continue
+			}
+			if msg.Method == "" || msg.ID == nil {
+				// Responses (the hub sends no requests) and notifications.
+				continue
+			}
+			go h.handle(ctx, &msg)
+		}
+	}
+}
+
+// handle answers one client request.
+func (h *Hub) handle(ctx context.Context, msg *message) {
+	switch msg.Method {
+	case "initialize":
+		var p struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(msg.Params, &p)
+		h.mu.Lock()
+		h.initialized = true
+		h.mu.Unlock()
+		h.reply(msg.ID, map[string]any{
+			"protocolVersion": cmp.Or(p.ProtocolVersion, protocolVersion),
+			"capabilities":    map[string]any{"tools": map[string]any{"listChanged": true}},
+			"serverInfo":      map[string]any{"name": serverName, "version": serverVersion},
+			"instructions":    "Tools of the MCP servers attached to this agent-deck session, named <mcp>__<tool>. The list changes when MCPs are attached or detached.",
+		}, nil)
+	case "ping":
+		h.reply(msg.ID, map[string]any{}, nil)
+	case "tools/list":
+		h.reply(msg.ID, map[string]any{"tools": h.tools(ctx)}, nil)
+	case "tools/call":
+		result, err := h.callTool(ctx, msg.Params)
+		var rerr *rpcError
+		if errors.As(err, &rerr) {
+			h.reply(msg.ID, nil, rerr)
+		} else if err != nil {
+			h.reply(msg.ID, nil, &rpcError{Code: codeInternalError, Message: err.Error()})
+		} else {
+			h.reply(msg.ID, result, nil)
+		}
+	default:
+		h.reply(msg.ID, nil, &rpcError{Code: codeMethodNotFound, Message: "method not found: " + msg.Method})
+	}
+}
+
+// tools lists every backend's tools, giving backends that are still starting
+// a moment to come up so the first list after launch is complete.
+func (h *Hub) tools(ctx context.Context) []json.RawMessage {
+	deadline := time.After(listWait)
+	for _, b := range h.snapshot() {
+		select {
+		case <-b.ready:
+		case <-deadline:
+		case <-ctx.Done():
+		}
+	}
+	tools := []json.RawMessage{}
+	for _, b := range h.snapshot() {
+		for _, t := range b.toolList() {
+			tools = append(tools, t.def)
+		}
+	}
+	return tools
+}
+
+// callTool routes a tools/call to the backend owning the tool.
+func (h *Hub) callTool(ctx context.Context, params json.RawMessage) (json.RawMessage, error) {
+	var p map[string]json.RawMessage
+	var name string
+	if json.Unmarshal(params, &p) != nil || json.Unmarshal(p["name"], &name) != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "tools/call needs a tool name"}
+	}
+	b, orig := h.route(name)
+	if b == nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "unknown tool: " + name}
+	}
+	p["name"], _ = json.Marshal(orig)
+	return b.call(ctx, "tools/call", p)
+}
+
+// route finds the backend and backend-side name for a hub tool name.
+func (h *Hub) route(name string) (*backend, string) {
+	for _, b := range h.snapshot() {
+		if !strings.HasPrefix(name, b.name+toolSeparator) {
+			continue
+		}
+		for _, t := range b.toolList() {
+			if t.hubName == name {
+				return b, t.origName
+			}
+		}
+	}
+	return nil, ""
+}
+
+// snapshot returns the current backends ordered by name.
+func (h *Hub) snapshot() []*backend {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	out := make([]*backend, 0, len(h.backends))
+	for _, b := range h.backends {
+		out = append(out, b)
+	}
+	slices.SortFunc(out, func(a, c *backend) int { return strings.Compare(a.name, c.name) })
+	return out
+}
+
+func (h *Hub) reply(id json.RawMessage, result any, rerr *rpcError) {
+	msg := message{ID: id, Error: rerr}
+	if rerr == nil {
+		raw, err := json.Marshal(result)
+		if err != nil {
+			msg.Error = &rpcError{Code: codeInternalError, Message: err.Error()}
+		} else {
+			msg.Result = raw
+		}
+	}
+	if id == nil {
+		msg.ID = json.RawMessage("null")
+	}
+	h.write(&msg)
+}
+
+func (h *Hub) write(msg *message) {
+	msg.JSONRPC = "2.0"
+	data, err := json.Marshal(msg)
+	if err != nil {
+		h.log.Error("marshal_failed", slog.String("error", err.Error()))
+		return
+	}
+	h.outMu.Lock()
+	defer h.outMu.Unlock()
+	if _, err := h.out.Write(append(data, '\n')); err != nil {
+		h.log.Error("client_write_failed", slog.String("error", err.Error()))
+	}
+}
+
+// toolsChanged tells an initialized client to re-list tools.
+func (h *Hub) toolsChanged() {
+	h.mu.Lock()
+	ok := h.initialized
+	h.mu.Unlock()
+	if ok {
+		h.write(&message{Method: "notifications/tools/list_changed"})
+	}
+}
+
+// watch polls the manifest and applies changes.
+func (h *Hub) watch(ctx context.Context) {
+	var lastMod time.Time
+	var lastSize int64 = -1
+	if st, err := os.Stat(h.manifestPath); err == nil {
+		lastMod, lastSize = st.ModTime(), st.Size()
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(h.pollInterval):
+		}
+		var mod time.Time
+		var size int64 = -1
+		if st, err := os.Stat(h.manifestPath); err == nil {
+			mod, size = st.ModTime(), st.Size()
+		}
+		if mod.Equal(lastMod) && size == lastSize {
+			continue
+		}
+		lastMod, lastSize = mod, size
+		m, err := ReadManifest(h.manifestPath)
+		if err != nil {
+			// Likely caught mid-write by a non-atomic editor; keep serving
+			// the current set and retry on the next change.
+			h.log.Warn("manifest_unreadable", slog.String("error", err.Error()))
+			continue
+		}
+		h.log.Info("manifest_changed", slog.Int("servers", len(m.Servers)))
+		h.apply(ctx, m)
+	}
+}
+
+// apply starts, stops and restarts backends to match m.
+func (h *Hub) apply(ctx context.Context, m Manifest) {
+	h.mu.Lock()
+	var stopped, started []*backend
+	for name, b := range h.backends {
+		if spec, ok := m.Servers[name]; !ok || !reflect.DeepEqual(spec, b.spec) {
+			stopped = append(stopped, b)
+			delete(h.backends, name)
+		}
+	}
+	for name, spec := range m.Servers {
+		if strings.Contains(name, toolSeparator) || spec.Command == "" {
+			h.log.Warn("manifest_entry_skipped", slog.String("mcp", name))
+			continue
+		}
+		if _, ok := h.backends[name]; ok {
+			continue
+		}
+		b := newBackend(ctx, name, spec, h.log, h.toolsChanged)
+		h.backends[name] = b
+		started = append(started, b)
+	}
+	h.mu.Unlock()
+
+	for _, b := range stopped {
+		go b.stop()
+	}
+	if len(stopped) > 0 {
+		h.toolsChanged()
+	}
+	for _, b := range started {
+		go func() {
+			b.start()
+			h.toolsChanged()
+		}()
+	}
+}
+
+func (h *Hub) stopAll() {
+	var wg sync.WaitGroup
+	for _, b := range h.snapshot() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.stop()
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,217 @@
+package mcphub
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMain doubles as a fake stdio MCP server when the hub launches the test
+// binary as a backend.
+func TestMain(m *testing.M) {
+	if name := os.Getenv("MCPHUB_FAKE_SERVER"); name != "" {
+		fakeServer(name, strings.Split(os.Getenv("MCPHUB_FAKE_TOOLS"), ","))
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func fakeServer(name string, tools []string) {
+	r := bufio.NewReader(os.Stdin)
+	enc := json.NewEncoder(os.Stdout)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			return
+		}
+		var msg message
+		if json.Unmarshal(line, &msg) != nil || msg.ID == nil {
+			continue
+		}
+		var result any
+		switch msg.Method {
+		case "initialize":
+			result = map[string]any{"protocolVersion": protocolVersion, "capabilities": map[string]any{"tools": map[string]any{}}}
+		case "tools/list":
+			var list []map[string]any
+			for _, t := range tools {
+				list = append(list, map[string]any{"name": t, "inputSchema": map[string]any{"type": "object"}})
+			}
+			result = map[string]any{"tools": list}
+		case "tools/call":
+			var p struct {
+				Name      string          `json:"name"`
+				Arguments json.RawMessage `json:"arguments"`
+			}
+			_ = json.Unmarshal(msg.Params, &p)
+			result = map[string]any{"content": []map[string]any{{"type": "text", "text": fmt.Sprintf("%s:%s:%s", name, p.Name, p.Arguments)}}}
+		}
+		raw, _ := json.Marshal(result)
+		_ = enc.Encode(message{JSONRPC: "2.0", ID: msg.ID, Result: raw})
+	}
+}
+
+func fakeSpec(name, tools string) Server {
+	return Server{
+		Command: os.Args[0],
+		Args:    []string{"-test.run=^$"},
+		Env:     map[string]string{"MCPHUB_FAKE_SERVER": name, "MCPHUB_FAKE_TOOLS": tools},
+	}
+}
+
+func writeManifest(t *testing.T, path string, servers map[string]Server) {
+	t.Helper()
+	data, err := json.Marshal(Manifest{Servers: servers})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type testClient struct {
+	t      *testing.T
+	w      io.Writer
+	msgs   chan message
+	nextID int
+}
+
+func (c *testClient) request(method string, params any) message {
+	c.t.Helper()
+	c.nextID++
+	id, _ := json.Marshal(c.nextID)
+	raw, _ := json.Marshal(params)
+	data, _ := json.Marshal(message{JSONRPC: "2.0", ID: id, Method: method, Params: raw})
+	if _, err := c.w.Write(append(data, '\n')); err != nil {
+		c.t.Fatal(err)
+	}
+	return c.await(func(m message) bool { return string(m.ID) == string(id) })
+}
+
+func (c *testClient) await(match func(message) bool) message {
+	c.t.Helper()
+	timeout := time.After(20 * time.Second)
+	for {
+		select {
+		case m := <-c.msgs:
+			if match(m) {
+				return m
+			}
+		case <-timeout:
+			c.t.Fatal("timed out waiting for hub message")
+		}
+	}
+}
+
+func (c *testClient) toolNames() []string {
+	c.t.Helper()
+	resp := c.request("tools/list", map[string]any{})
+	var res struct {
+		Tools []struct {
+			Name string `json:"name"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(resp.Result, &res); err != nil {
+		c.t.Fatalf("tools/list: %v (%s)", err, resp.Result)
+	}
+	var names []string
+	for _, tl := range res.Tools {
+		names = append(names, tl.Name)
+	}
+	return names
+}
+
+func TestHub_HotSwapsBackends(t *testing.T) {
+	dir := t.TempDir()
+	manifest := filepath.Join(dir, "mcp-hub.json")
+	writeManifest(t, manifest, map[string]Server{"alpha": fakeSpec("alpha", "echo")})
+
+	h := New(manifest, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	h.pollInterval = 20 * time.Millisecond
+
+	clientOut, hubIn := io.Pipe()
+	hubOut, clientIn := io.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- h.Serve(ctx, clientOut, clientIn) }()
+	t.Cleanup(func() {
+		cancel()
+		_ = hubIn.Close()
+		<-served
+	})
+
+	c := &testClient{t: t, w: hubIn, msgs: make(chan message, 64)}
+	go func() {
+		r := bufio.NewReader(hubOut)
+		for {
+			line, err := r.ReadBytes('\n')
+			if err != nil {
+				return
+			}
+			var m message
+			if json.Unmarshal(line, &m) == nil {
+				c.msgs <- m
+			}
+		}
+	}()
+
+	init := c.request("initialize", map[string]any{"protocolVersion": "2025-03-26"})
+	if !strings.Contains(string(init.Result), `"listChanged":true`) || !strings.Contains(string(init.Result), "2025-03-26") {
+		t.Fatalf("initialize result = %s", init.Result)
+	}
+
+	if got := c.toolNames(); len(got) != 1 || got[0] != "alpha__echo" {
+		t.Fatalf("tools = %v", got)
+	}
+	call := c.request("tools/call", map[string]any{"name": "alpha__echo", "arguments": map[string]any{"x": 1}})
+	if !strings.Contains(string(call.Result), `alpha:echo:{\"x\":1}`) {
+		t.Fatalf("tools/call result = %s (error %+v)", call.Result, call.Error)
+	}
+
+	isListChanged := func(m message) bool { return m.Method == "notifications/tools/list_changed" }
+
+	// Attach: beta appears without reconnecting.
+	writeManifest(t, manifest, map[string]Server{
+		"alpha": fakeSpec("alpha", "echo"),
+		"beta":  fakeSpec("beta", "ping,pong"),
+	})
+	c.await(isListChanged)
+	if got := strings.Join(c.toolNames(), ","); got != "alpha__echo,beta__ping,beta__pong" {
+		t.Fatalf("tools after attach = %s", got)
+	}
+
+	// Detach: alpha's tools go away and calls to them fail.
+	writeManifest(t, manifest, map[string]Server{"beta": fakeSpec("beta", "ping,pong")})
+	c.await(isListChanged)
+	if got := strings.Join(c.toolNames(), ","); got != "beta__ping,beta__pong" {
+		t.Fatalf("tools after detach = %s", got)
+	}
+	gone := c.request("tools/call", map[string]any{"name": "alpha__echo"})
+	if gone.Error == nil || !strings.Contains(gone.Error.Message, "unknown tool") {
+		t.Fatalf("call to detached tool = %+v", gone)
+	}
+
+	if resp := c.request("resources/list", map[string]any{}); resp.Error == nil || resp.Error.Code != codeMethodNotFound {
+		t.Fatalf("unsupported method = %+v", resp)
+	}
+}
+
+func TestReadManifest_MissingIsEmpty(t *testing.T) {
+	m, err := ReadManifest(filepath.Join(t.TempDir(), "none.json"))
+	if err != nil || len(m.Servers) != 0 {
+		t.Fatalf("got %+v, %v", m, err)
+	}
+}
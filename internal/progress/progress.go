@@ -0,0 +1,187 @@
+// Package progress is the shared progress reporting used by long-running
+// operations (bulk session actions, worktree setup, update downloads). An
+// operation reports through a Func; the CLI renders reports with NewCLI for
+// --progress, and the TUI feeds them into its background tasks panel.
+package progress
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Update is one progress report. Done/Total count steps (or bytes when Bytes
+// is set); Total <= 0 means the amount of work is not known yet. Message
+// describes the current step.
+type Update struct {
+	Done    int64
+	Total   int64
+	Bytes   bool
+	Message string
+}
+
+// Percent returns the completion percentage, or -1 when Total is unknown.
+func (u Update) Percent() int {
+	if u.Total <= 0 {
+		return -1
+	}
+	return int(min(u.Done, u.Total) * 100 / u.Total)
+}
+
+// Func receives progress reports. A nil Func discards them, so operations
+// can report unconditionally.
+type Func func(Update)
+
+// Report sends u to f.
+func (f Func) Report(u Update) {
+	if f != nil {
+		f(u)
+	}
+}
+
+// Step reports step done of total.
+func (f Func) Step(done, total int, format string, args ...any) {
+	f.Report(Update{Done: int64(done), Total: int64(total), Message: fmt.Sprintf(format, args...)})
+}
+
+// Logf reports a message with no completion amount.
+func (f Func) Logf(format string, args ...any) {
+	f.Report(Update{Message: fmt.Sprintf(format, args...)})
+}
+
+// FormatBytes renders n as a short human-readable size ("3.1 MB").
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// String renders u as one CLI line: "[3/10] message", "[ 42%] message
+// (1.2 MB / 3.0 MB)" or just the message when there is no amount.
+func (u Update) String() string {
+	switch {
+	case u.Bytes && u.Total > 0:
+		return fmt.Sprintf("[%3d%%] %s (%s / %s)", u.Percent(), u.Message, FormatBytes(u.Done), FormatBytes(u.Total))
+	case u.Bytes:
+		return fmt.Sprintf("%s (%s)", u.Message, FormatBytes(u.Done))
+	case u.Total > 0:
+		return fmt.Sprintf("[%d/%d] %s", u.Done, u.Total, u.Message)
+	default:
+		return u.Message
+	}
+}
+
+// cliPercentStep is how far a byte transfer must advance before the CLI
+// prints another line for it.
+const cliPercentStep = 10
+
+// NewCLI returns a Func that prints reports to w, one line each. Byte
+// transfers are throttled to every cliPercentStep percent (plus the final
+// report) so a download prints a handful of lines rather than thousands.
+// Safe for concurrent use.
+func NewCLI(w io.Writer) Func {
+	var (
+		mu          sync.Mutex
+		lastMessage string
+		lastPercent = -1
+	)
+	return func(u Update) {
+		mu.Lock()
+		defer mu.Unlock()
+		if u.Bytes {
+			pct := u.Percent()
+			sameTransfer := u.Message == lastMessage
+			if sameTransfer && (pct < 0 || (pct < 100 && pct-lastPercent < cliPercentStep)) {
+				return
+			}
+			lastPercent = pct
+		} else {
+			lastPercent = -1
+		}
+		lastMessage = u.Message
+		fmt.Fprintln(w, u.String())
+	}
+}
+
+// Reader wraps r and reports the bytes read so far against total (<= 0 when
+// unknown) with the given message.
+func Reader(r io.Reader, total int64, message string, f Func) io.Reader {
+	if f == nil {
+		return r
+	}
+	f.Report(Update{Total: total, Bytes: true, Message: message})
+	return &reader{r: r, total: total, message: message, f: f}
+}
+
+type reader struct {
+	r       io.Reader
+	done    int64
+	total   int64
+	message string
+	f       Func
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.done += int64(n)
+		r.f.Report(Update{Done: r.done, Total: r.total, Bytes: true, Message: r.message})
+	}
+	return n, err
+}
+
+// LineWriter returns an io.Writer that reports every complete line written
+// to it as a message, e.g. to stream a setup script's output into a task
+// log. Call Flush on the returned writer to report a trailing partial line.
+func LineWriter(f Func) *Lines {
+	return &Lines{f: f}
+}
+
+// Lines is the io.Writer returned by LineWriter.
+type Lines struct {
+	mu  sync.Mutex
+	f   Func
+	buf bytes.Buffer
+}
+
+func (l *Lines) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.buf.Write(p)
+	for {
+		line, err := l.buf.ReadString('\n')
+		if err != nil {
+			// Incomplete line: keep it for the next write.
+			l.buf.Reset()
+			l.buf.WriteString(line)
+			return len(p), nil
+		}
+		l.report(line)
+	}
+}
+
+// Flush reports any buffered partial line.
+func (l *Lines) Flush() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.buf.Len() > 0 {
+		l.report(l.buf.String())
+		l.buf.Reset()
+	}
+}
+
+func (l *Lines) report(line string) {
+	line = strings.TrimRight(line, "\r\n")
+	if strings.TrimSpace(line) != "" {
+		l.f.Report(Update{Message: line})
+	}
+}
@@ -0,0 +1,74 @@
+package progress
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestUpdateString(t *testing.T) {
+	cases := []struct {
+		u    Update
+		want string
+	}{
+		{Update{Done: 3, Total: 10, Message: "Restarting api"}, "[3/10] Restarting api"},
+		{Update{Done: 512 * 1024, Total: 2 << 20, Bytes: true, Message: "Downloading"}, "[ 25%] Downloading (512.0 KB / 2.0 MB)"},
+		{Update{Done: 100, Bytes: true, Message: "Downloading"}, "Downloading (100 B)"},
+		{Update{Message: "Running setup script"}, "Running setup script"},
+	}
+	for _, c := range cases {
+		if got := c.u.String(); got != c.want {
+			t.Errorf("%+v.String() = %q, want %q", c.u, got, c.want)
+		}
+	}
+}
+
+func TestNilFuncDiscards(t *testing.T) {
+	var f Func
+	f.Step(1, 2, "ignored")
+	f.Logf("ignored")
+	if r := Reader(strings.NewReader("x"), 1, "m", nil); r == nil {
+		t.Fatal("Reader with a nil Func must return the source reader")
+	}
+}
+
+func TestCLIThrottlesByteTransfers(t *testing.T) {
+	var out bytes.Buffer
+	f := NewCLI(&out)
+	data := strings.Repeat("x", 1000)
+	r := Reader(strings.NewReader(data), int64(len(data)), "Downloading", f)
+	buf := make([]byte, 10)
+	for {
+		if _, err := r.Read(buf); err == io.EOF {
+			break
+		}
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	// 0%, then every 10% up to and including 100%.
+	if len(lines) != 11 {
+		t.Fatalf("got %d lines, want 11:\n%s", len(lines), out.String())
+	}
+	if !strings.HasPrefix(lines[len(lines)-1], "[100%] Downloading") {
+		t.Fatalf("last line = %q, want the 100%% report", lines[len(lines)-1])
+	}
+
+	out.Reset()
+	f.Step(1, 2, "first")
+	f.Step(2, 2, "second")
+	if got := out.String(); got != "[1/2] first\n[2/2] second\n" {
+		t.Fatalf("step output = %q", got)
+	}
+}
+
+func TestLineWriter(t *testing.T) {
+	var got []string
+	w := LineWriter(func(u Update) { got = append(got, u.Message) })
+	_, _ = w.Write([]byte("npm install\r\nadded 3 pack"))
+	_, _ = w.Write([]byte("ages\n\n  \ntail"))
+	w.Flush()
+	want := []string{"npm install", "added 3 packages", "tail"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("lines = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,108 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/asheshgoplani/agent-deck/internal/logging"
+)
+
+// Client opens encrypted streams from a device to a paired deck.
+type Client struct {
+	Deck     PairedDeck
+	Identity *Identity
+	// Dialer overrides websocket.DefaultDialer (tests).
+	Dialer *websocket.Dialer
+}
+
+// Dial opens one stream to the deck.
+func (c *Client) Dial(ctx context.Context) (*SecureConn, error) {
+	return c.dial(ctx, "")
+}
+
+func (c *Client) dial(ctx context.Context, token string) (*SecureConn, error) {
+	deckKey, err := DecodeKey(c.Deck.DeckKey)
+	if err != nil {
+		return nil, fmt.Errorf("deck %s: %w", c.Deck.Name, err)
+	}
+	u, err := relayEndpoint(c.Deck.RelayURL, "/v1/connect/"+c.Deck.DeckID)
+	if err != nil {
+		return nil, err
+	}
+	dialer := c.Dialer
+	if dialer == nil {
+		dialer = websocket.DefaultDialer
+	}
+	conn, resp, err := dialer.DialContext(ctx, u, nil)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusServiceUnavailable {
+			return nil, errDeckOffline
+		}
+		return nil, err
+	}
+	fc := newWSFrameConn(conn)
+	_ = conn.SetReadDeadline(time.Now().Add(acceptTimeout))
+	sc, err := clientHandshake(fc, c.Identity, deckKey, token)
+	if err != nil {
+		_ = fc.Close()
+		return nil, err
+	}
+	_ = conn.SetReadDeadline(time.Time{})
+	return sc, nil
+}
+
+// Serve accepts local TCP connections on ln and tunnels each one to the
+// deck, until ctx is cancelled.
+func (c *Client) Serve(ctx context.Context, ln net.Listener) error {
+	stop := context.AfterFunc(ctx, func() { _ = ln.Close() })
+	defer stop()
+	log := logging.ForComponent(logging.CompRelay)
+	for {
+		local, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go func() {
+			sc, err := c.Dial(ctx)
+			if err != nil {
+				log.Warn("relay_dial_failed", slog.String("deck", c.Deck.Name), slog.String("error", err.Error()))
+				_ = local.Close()
+				return
+			}
+			pipe(local, sc)
+		}()
+	}
+}
+
+// Join completes a pairing offer: it proves the device key to the deck with
+// the offer's single-use token and returns the deck to remember.
+func Join(ctx context.Context, offer Offer, id *Identity, dialer *websocket.Dialer) (PairedDeck, error) {
+	name := offer.DeckName
+	if name == "" {
+		name = offer.DeckID
+	}
+	deck := PairedDeck{
+		Name:     name,
+		RelayURL: offer.RelayURL,
+		DeckID:   offer.DeckID,
+		DeckKey:  offer.DeckKey,
+		PairedAt: time.Now().UTC(),
+	}
+	c := &Client{Deck: deck, Identity: id, Dialer: dialer}
+	sc, err := c.dial(ctx, offer.Token)
+	if err != nil {
+		return PairedDeck{}, err
+	}
+	_ = sc.Close()
+	return deck, nil
+}
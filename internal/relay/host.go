@@ -0,0 +1,178 @@
+package relay
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/asheshgoplani/agent-deck/internal/logging"
+)
+
+const (
+	hostMinBackoff = time.Second
+	hostMaxBackoff = 30 * time.Second
+)
+
+// Host keeps a deck registered on a relay and serves device streams by
+// forwarding them, once the handshake succeeds, to Target (the local web
+// server).
+type Host struct {
+	RelayURL string
+	Identity *Identity
+	Store    *HostStore
+	// Target is the TCP address streams are forwarded to, e.g.
+	// "127.0.0.1:8420".
+	Target string
+	// Dialer overrides websocket.DefaultDialer (tests).
+	Dialer *websocket.Dialer
+}
+
+func (h *Host) dialer() *websocket.Dialer {
+	if h.Dialer != nil {
+		return h.Dialer
+	}
+	return websocket.DefaultDialer
+}
+
+// Run keeps the deck registered until ctx is cancelled, reconnecting with
+// backoff when the relay drops.
+func (h *Host) Run(ctx context.Context) error {
+	log := logging.ForComponent(logging.CompRelay)
+	backoff := hostMinBackoff
+	for {
+		registered, err := h.runOnce(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if registered {
+			backoff = hostMinBackoff
+		}
+		log.Warn("relay_disconnected",
+			slog.String("relay", h.RelayURL),
+			slog.String("error", fmt.Sprint(err)),
+			slog.Duration("retry_in", backoff))
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, hostMaxBackoff)
+	}
+}
+
+// runOnce registers on the relay and serves stream requests until the
+// control connection ends. registered reports whether it got that far.
+func (h *Host) runOnce(ctx context.Context) (registered bool, err error) {
+	u, err := relayEndpoint(h.RelayURL, "/v1/deck/"+h.Identity.ID())
+	if err != nil {
+		return false, err
+	}
+	conn, _, err := h.dialer().DialContext(ctx, u, nil)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	var challenge controlMessage
+	if err := conn.ReadJSON(&challenge); err != nil {
+		return false, err
+	}
+	nonce, err := b64.DecodeString(challenge.Nonce)
+	if challenge.Type != "challenge" || err != nil {
+		return false, errors.New("relay sent an invalid challenge")
+	}
+	if err := conn.WriteJSON(controlMessage{
+		Type: "auth",
+		Key:  EncodeKey(h.Identity.Public),
+		Sig:  b64.EncodeToString(ed25519.Sign(h.Identity.Private, signedPayload([]byte("register"), nonce))),
+	}); err != nil {
+		return false, err
+	}
+	var ack controlMessage
+	if err := conn.ReadJSON(&ack); err != nil {
+		return false, fmt.Errorf("relay rejected deck registration: %w", err)
+	}
+	if ack.Type != "registered" {
+		return false, errors.New("relay rejected deck registration")
+	}
+	logging.ForComponent(logging.CompRelay).Info("relay_registered",
+		slog.String("relay", h.RelayURL),
+		slog.String("deck_id", h.Identity.ID()))
+
+	for {
+		var msg controlMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return true, err
+		}
+		if msg.Type == "open" && msg.Stream != "" {
+			go h.serveStream(ctx, msg.Stream)
+		}
+	}
+}
+
+// serveStream picks up one device stream, authenticates the device, and
+// splices it to Target.
+func (h *Host) serveStream(ctx context.Context, streamID string) {
+	log := logging.ForComponent(logging.CompRelay)
+	u, err := relayEndpoint(h.RelayURL, "/v1/accept/"+h.Identity.ID()+"/"+streamID)
+	if err != nil {
+		return
+	}
+	conn, _, err := h.dialer().DialContext(ctx, u, nil)
+	if err != nil {
+		log.Warn("relay_accept_failed", slog.String("error", err.Error()))
+		return
+	}
+	fc := newWSFrameConn(conn)
+
+	_ = conn.SetReadDeadline(time.Now().Add(acceptTimeout))
+	sc, deviceKey, err := hostHandshake(fc, h.Identity, h.Store)
+	if err != nil {
+		log.Warn("relay_handshake_failed", slog.String("error", err.Error()))
+		_ = fc.Close()
+		return
+	}
+	_ = conn.SetReadDeadline(time.Time{})
+
+	var d net.Dialer
+	target, err := d.DialContext(ctx, "tcp", h.Target)
+	if err != nil {
+		log.Warn("relay_target_unreachable",
+			slog.String("target", h.Target),
+			slog.String("error", err.Error()))
+		_ = sc.Close()
+		return
+	}
+	log.Debug("relay_stream_open", slog.String("device", KeyID(deviceKey)))
+	pipe(sc, target)
+}
+
+// relayEndpoint turns a relay base URL (http, https, ws or wss) into the
+// WebSocket URL of path.
+func relayEndpoint(base, path string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(base))
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid relay url %q", base)
+	}
+	switch u.Scheme {
+	case "http", "ws":
+		u.Scheme = "ws"
+	case "https", "wss":
+		u.Scheme = "wss"
+	default:
+		return "", fmt.Errorf("invalid relay url %q: scheme must be http(s) or ws(s)", base)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + path
+	u.RawQuery = ""
+	return u.String(), nil
+}
@@ -0,0 +1,168 @@
+// Package relay implements agent-deck's end-to-end encrypted remote access.
+//
+// A self-hosted relay server (`agent-deck relay serve`) brokers connections
+// between a workstation running `agent-deck web --relay <url>` and paired
+// devices running `agent-deck relay connect`. Neither side opens an inbound
+// port: both dial out to the relay, which splices their WebSockets together
+// and forwards opaque frames. Every stream is encrypted end to end with keys
+// the relay never sees:
+//
+//   - The deck (host) and every device hold a long-term Ed25519 identity.
+//     A deck is addressed on the relay by its deck ID, a hash of its public
+//     key, and proves ownership of that ID when it registers.
+//   - Each stream runs an authenticated ephemeral X25519 handshake (see
+//     secure.go); both sides sign their ephemeral keys, the device checks the
+//     deck key it pinned at pairing time, and the deck checks the device is
+//     in its paired list.
+//   - Pairing is a one-time offer (`agent-deck relay pair`) carrying the
+//     relay URL, the deck's public key and a single-use token that the device
+//     presents inside the encrypted channel.
+//
+// Once a stream is up it carries one TCP connection to the deck's local web
+// server, so the web UI, its API and the terminal WebSockets all work through
+// the tunnel unchanged.
+package relay
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/atomicfile"
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// b64 is the encoding used for keys and tokens on disk and on the wire.
+var b64 = base64.RawURLEncoding
+
+// DefaultDir returns the directory holding relay keys and pairing state
+// (~/.agent-deck/relay).
+func DefaultDir() (string, error) {
+	dir, err := session.GetAgentDeckDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "relay"), nil
+}
+
+// Identity is a long-term Ed25519 key pair identifying a deck or a device.
+type Identity struct {
+	Public  ed25519.PublicKey
+	Private ed25519.PrivateKey
+}
+
+type identityFile struct {
+	Seed string `json:"seed"`
+}
+
+// LoadOrCreateIdentity reads the identity at path, generating and saving a
+// new one (mode 0600) when the file does not exist.
+func LoadOrCreateIdentity(path string) (*Identity, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		var f identityFile
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("parse identity %s: %w", path, err)
+		}
+		seed, err := b64.DecodeString(f.Seed)
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("identity %s: invalid seed", path)
+		}
+		priv := ed25519.NewKeyFromSeed(seed)
+		return &Identity{Public: priv.Public().(ed25519.PublicKey), Private: priv}, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	data, err = json.Marshal(identityFile{Seed: b64.EncodeToString(priv.Seed())})
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	if err := atomicfile.WriteFile(path, data, 0o600); err != nil {
+		return nil, err
+	}
+	return &Identity{Public: pub, Private: priv}, nil
+}
+
+// ID returns the identity's key ID (see KeyID).
+func (id *Identity) ID() string { return KeyID(id.Public) }
+
+// KeyID derives the short, URL-safe identifier of a public key. A deck's
+// KeyID is its address on the relay.
+func KeyID(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return b64.EncodeToString(sum[:15])
+}
+
+// EncodeKey / DecodeKey convert a public key to and from its text form.
+func EncodeKey(pub ed25519.PublicKey) string { return b64.EncodeToString(pub) }
+
+func DecodeKey(s string) (ed25519.PublicKey, error) {
+	raw, err := b64.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, errors.New("invalid public key")
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// offerPrefix marks an encoded pairing offer.
+const offerPrefix = "adpair1:"
+
+// Offer is what a deck hands to a device to pair: where to reach it, which
+// key to expect, and the single-use token that authorizes the device.
+type Offer struct {
+	RelayURL string `json:"relay_url"`
+	DeckID   string `json:"deck_id"`
+	DeckKey  string `json:"deck_key"`
+	DeckName string `json:"deck_name,omitempty"`
+	Token    string `json:"token"`
+}
+
+// Encode renders the offer as a single copy-pasteable string.
+func (o Offer) Encode() string {
+	data, _ := json.Marshal(o)
+	return offerPrefix + b64.EncodeToString(data)
+}
+
+// ParseOffer decodes an offer produced by Offer.Encode and checks that the
+// deck ID matches the deck key.
+func ParseOffer(s string) (Offer, error) {
+	var o Offer
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, offerPrefix) {
+		return o, errors.New("not an agent-deck pairing offer")
+	}
+	data, err := b64.DecodeString(strings.TrimPrefix(s, offerPrefix))
+	if err != nil {
+		return o, fmt.Errorf("decode pairing offer: %w", err)
+	}
+	if err := json.Unmarshal(data, &o); err != nil {
+		return o, fmt.Errorf("decode pairing offer: %w", err)
+	}
+	key, err := DecodeKey(o.DeckKey)
+	if err != nil {
+		return o, fmt.Errorf("pairing offer: %w", err)
+	}
+	if KeyID(key) != o.DeckID {
+		return o, errors.New("pairing offer: deck id does not match deck key")
+	}
+	if o.RelayURL == "" || o.Token == "" {
+		return o, errors.New("pairing offer: missing relay url or token")
+	}
+	return o, nil
+}
@@ -0,0 +1,234 @@
+package relay
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// relayFixture runs a relay broker, a deck web server and a Host registered
+// on the relay.
+type relayFixture struct {
+	relay    *Server
+	relayURL string
+	deck     *Identity
+	store    *HostStore
+}
+
+func newRelayFixture(t *testing.T) *relayFixture {
+	t.Helper()
+	dir := t.TempDir()
+
+	relay := NewServer()
+	relaySrv := httptest.NewServer(relay.Handler())
+	t.Cleanup(relaySrv.Close)
+
+	web := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "deck says hi to %s", r.URL.Path)
+	}))
+	t.Cleanup(web.Close)
+
+	deck, err := LoadOrCreateIdentity(filepath.Join(dir, "deck.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := OpenHostStore(filepath.Join(dir, "devices.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	host := &Host{RelayURL: relaySrv.URL, Identity: deck, Store: store, Target: web.Listener.Addr().String()}
+	go func() { _ = host.Run(ctx) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !relay.Connected(deck.ID()) {
+		if time.Now().After(deadline) {
+			t.Fatal("deck never registered on the relay")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return &relayFixture{relay: relay, relayURL: relaySrv.URL, deck: deck, store: store}
+}
+
+func newDevice(t *testing.T) *Identity {
+	t.Helper()
+	id, err := LoadOrCreateIdentity(filepath.Join(t.TempDir(), "device.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+// get sends one HTTP request over a relay stream and returns the body.
+func get(t *testing.T, c *Client, path string) string {
+	t.Helper()
+	sc, err := c.Dial(context.Background())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer sc.Close()
+	fmt.Fprintf(sc, "GET %s HTTP/1.1\r\nHost: deck\r\nConnection: close\r\n\r\n", path)
+	resp, err := http.ReadResponse(bufio.NewReader(sc), nil)
+	if err != nil {
+		t.Fatalf("read response: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body)
+}
+
+func TestRelay_PairThenTunnel(t *testing.T) {
+	f := newRelayFixture(t)
+	device := newDevice(t)
+
+	offer, err := f.store.NewOffer(f.deck, f.relayURL, "workstation", "phone", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseOffer(offer.Encode())
+	if err != nil || parsed != offer {
+		t.Fatalf("offer round-trip = %+v, %v", parsed, err)
+	}
+
+	deck, err := Join(context.Background(), parsed, device, nil)
+	if err != nil {
+		t.Fatalf("join: %v", err)
+	}
+	if devs := f.store.Devices(); len(devs) != 1 || devs[0].Name != "phone" || devs[0].PublicKey != EncodeKey(device.Public) {
+		t.Fatalf("paired devices = %+v", devs)
+	}
+
+	c := &Client{Deck: deck, Identity: device}
+	if got := get(t, c, "/api/menu"); got != "deck says hi to /api/menu" {
+		t.Fatalf("tunnelled body = %q", got)
+	}
+
+	// The token is single-use: a second device cannot reuse the offer.
+	if _, err := Join(context.Background(), parsed, newDevice(t), nil); err == nil {
+		t.Fatal("a consumed pairing offer must not pair another device")
+	}
+
+	// A local listener proxies whole TCP connections (what `relay connect` does).
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = c.Serve(ctx, ln) }()
+	resp, err := http.Get("http://" + ln.Addr().String() + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "deck says hi to /healthz" {
+		t.Fatalf("proxied body = %q", body)
+	}
+}
+
+func TestRelay_RejectsUnpairedAndRevokedDevices(t *testing.T) {
+	f := newRelayFixture(t)
+	stranger := newDevice(t)
+	deck := PairedDeck{Name: "w", RelayURL: f.relayURL, DeckID: f.deck.ID(), DeckKey: EncodeKey(f.deck.Public)}
+
+	if _, err := (&Client{Deck: deck, Identity: stranger}).Dial(context.Background()); !errors.Is(err, ErrNotPaired) {
+		t.Fatalf("unpaired dial err = %v, want ErrNotPaired", err)
+	}
+
+	offer, _ := f.store.NewOffer(f.deck, f.relayURL, "w", "laptop", time.Minute)
+	device := newDevice(t)
+	if _, err := Join(context.Background(), offer, device, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.store.Revoke("laptop"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&Client{Deck: deck, Identity: device}).Dial(context.Background()); !errors.Is(err, ErrNotPaired) {
+		t.Fatalf("revoked dial err = %v, want ErrNotPaired", err)
+	}
+}
+
+func TestRelay_DeviceRejectsWrongDeckKey(t *testing.T) {
+	f := newRelayFixture(t)
+	offer, _ := f.store.NewOffer(f.deck, f.relayURL, "w", "phone", time.Minute)
+	device := newDevice(t)
+	deck, err := Join(context.Background(), offer, device, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A relay impersonating the deck cannot produce the pinned key's
+	// signature: pin a different key under the same deck ID route.
+	impostor := newDevice(t)
+	deck.DeckKey = EncodeKey(impostor.Public)
+	_, err = (&Client{Deck: deck, Identity: device}).Dial(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "signature") {
+		t.Fatalf("dial with wrong pinned key err = %v, want signature failure", err)
+	}
+}
+
+func TestRelay_OfflineDeck(t *testing.T) {
+	relaySrv := httptest.NewServer(NewServer().Handler())
+	defer relaySrv.Close()
+	deckID := newDevice(t)
+	c := &Client{
+		Deck:     PairedDeck{Name: "w", RelayURL: relaySrv.URL, DeckID: deckID.ID(), DeckKey: EncodeKey(deckID.Public)},
+		Identity: newDevice(t),
+	}
+	if _, err := c.Dial(context.Background()); !errors.Is(err, errDeckOffline) {
+		t.Fatalf("offline dial err = %v, want errDeckOffline", err)
+	}
+}
+
+func TestParseOffer_RejectsMismatchedDeckID(t *testing.T) {
+	id := newDevice(t)
+	o := Offer{RelayURL: "https://relay.example", DeckID: "not-the-hash", DeckKey: EncodeKey(id.Public), Token: "t"}
+	if _, err := ParseOffer(o.Encode()); err == nil {
+		t.Fatal("offer whose deck id does not match its key must be rejected")
+	}
+	if _, err := ParseOffer("hello"); err == nil {
+		t.Fatal("garbage must be rejected")
+	}
+}
+
+func TestLoadOrCreateIdentity_Stable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "id.key")
+	a, err := LoadOrCreateIdentity(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := LoadOrCreateIdentity(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ID() != b.ID() {
+		t.Fatal("identity must survive reload")
+	}
+}
+
+func TestRelayEndpoint(t *testing.T) {
+	for in, want := range map[string]string{
+		"https://relay.example.com":       "wss://relay.example.com/v1/connect/x",
+		"http://10.0.0.2:8443/base/":      "ws://10.0.0.2:8443/base/v1/connect/x",
+		"wss://relay.example.com?debug=1": "wss://relay.example.com/v1/connect/x",
+	} {
+		if got, err := relayEndpoint(in, "/v1/connect/x"); err != nil || got != want {
+			t.Errorf("relayEndpoint(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := relayEndpoint("ftp://x", "/"); err == nil {
+		t.Error("ftp scheme must be rejected")
+	}
+}
@@ -0,0 +1,330 @@
+package relay
+
+// Stream encryption.
+//
+// Handshake (all frames are relay-visible until keys are derived):
+//
+//	device → deck  hello  {device_key, eph, pair, sig = Sign(device, "client" ‖ deck_id ‖ eph)}
+//	deck → device  accept {eph, sig = Sign(deck, "host" ‖ device_eph ‖ deck_eph ‖ device_key)}
+//	               or     {error}
+//	device → deck  sealed auth: the pairing token, or empty for a paired device
+//	deck → device  sealed "ok"
+//
+// Both sides derive two AES-256-GCM keys (one per direction) with HKDF over
+// the X25519 shared secret, salted with both ephemeral keys. Nonces are
+// per-direction counters, so a replayed, dropped or reordered frame fails to
+// open and kills the stream.
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+const (
+	protocolLabel = "agent-deck-relay-v1"
+	// maxPlaintextFrame bounds a sealed frame's payload; the relay enforces
+	// a read limit above this.
+	maxPlaintextFrame = 32 * 1024
+)
+
+// ErrNotPaired is returned to a device whose key the deck does not know.
+var ErrNotPaired = errors.New("device is not paired with this deck")
+
+// frameConn is a message-oriented transport (a WebSocket in production).
+type frameConn interface {
+	ReadFrame() ([]byte, error)
+	WriteFrame([]byte) error
+	Close() error
+}
+
+type clientHello struct {
+	DeviceKey string `json:"device_key"`
+	Eph       string `json:"eph"`
+	Pair      bool   `json:"pair,omitempty"`
+	Sig       string `json:"sig"`
+}
+
+type hostAccept struct {
+	Eph   string `json:"eph,omitempty"`
+	Sig   string `json:"sig,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+func signedPayload(parts ...[]byte) []byte {
+	return append([]byte(protocolLabel+" "), bytes.Join(parts, nil)...)
+}
+
+// clientHandshake runs the device side over fc. deckKey is the key pinned at
+// pairing; token is the pairing token for a first connection, else "".
+func clientHandshake(fc frameConn, id *Identity, deckKey ed25519.PublicKey, token string) (*SecureConn, error) {
+	eph, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	ephPub := eph.PublicKey().Bytes()
+	hello := clientHello{
+		DeviceKey: EncodeKey(id.Public),
+		Eph:       b64.EncodeToString(ephPub),
+		Pair:      token != "",
+		Sig:       b64.EncodeToString(ed25519.Sign(id.Private, signedPayload([]byte("client"), []byte(KeyID(deckKey)), ephPub))),
+	}
+	if err := writeJSONFrame(fc, hello); err != nil {
+		return nil, err
+	}
+
+	var accept hostAccept
+	if err := readJSONFrame(fc, &accept); err != nil {
+		return nil, err
+	}
+	if accept.Error != "" {
+		if accept.Error == ErrNotPaired.Error() {
+			return nil, ErrNotPaired
+		}
+		return nil, fmt.Errorf("deck refused connection: %s", accept.Error)
+	}
+	hostEph, err := b64.DecodeString(accept.Eph)
+	if err != nil {
+		return nil, errors.New("handshake: bad deck ephemeral key")
+	}
+	sig, err := b64.DecodeString(accept.Sig)
+	if err != nil || !ed25519.Verify(deckKey, signedPayload([]byte("host"), ephPub, hostEph, id.Public), sig) {
+		return nil, errors.New("handshake: deck signature invalid (wrong deck or tampered relay)")
+	}
+
+	sc, err := deriveConn(fc, eph, hostEph, ephPub, hostEph, true)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := sc.writeFrame([]byte(token)); err != nil {
+		return nil, err
+	}
+	ok, err := sc.readFrame()
+	if err != nil {
+		return nil, fmt.Errorf("handshake: %w", err)
+	}
+	if string(ok) != "ok" {
+		return nil, fmt.Errorf("deck refused connection: %s", ok)
+	}
+	return sc, nil
+}
+
+// hostHandshake runs the deck side over fc and returns the stream plus the
+// device it is talking to.
+func hostHandshake(fc frameConn, id *Identity, store *HostStore) (*SecureConn, ed25519.PublicKey, error) {
+	var hello clientHello
+	if err := readJSONFrame(fc, &hello); err != nil {
+		return nil, nil, err
+	}
+	refuse := func(err error) (*SecureConn, ed25519.PublicKey, error) {
+		_ = writeJSONFrame(fc, hostAccept{Error: err.Error()})
+		return nil, nil, err
+	}
+
+	deviceKey, err := DecodeKey(hello.DeviceKey)
+	if err != nil {
+		return refuse(errors.New("bad device key"))
+	}
+	devEph, err := b64.DecodeString(hello.Eph)
+	if err != nil {
+		return refuse(errors.New("bad device ephemeral key"))
+	}
+	sig, err := b64.DecodeString(hello.Sig)
+	if err != nil || !ed25519.Verify(deviceKey, signedPayload([]byte("client"), []byte(id.ID()), devEph), sig) {
+		return refuse(errors.New("device signature invalid"))
+	}
+	paired := store.IsPaired(deviceKey)
+	if !paired && !(hello.Pair && store.HasPending()) {
+		return refuse(ErrNotPaired)
+	}
+
+	eph, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	ephPub := eph.PublicKey().Bytes()
+	if err := writeJSONFrame(fc, hostAccept{
+		Eph: b64.EncodeToString(ephPub),
+		Sig: b64.EncodeToString(ed25519.Sign(id.Private, signedPayload([]byte("host"), devEph, ephPub, deviceKey))),
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	sc, err := deriveConn(fc, eph, devEph, devEph, ephPub, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	auth, err := sc.readFrame()
+	if err != nil {
+		return nil, nil, fmt.Errorf("handshake: %w", err)
+	}
+	if !paired {
+		if _, err := store.CompletePairing(string(auth), deviceKey); err != nil {
+			_, _ = sc.writeFrame([]byte(err.Error()))
+			return nil, nil, err
+		}
+	}
+	if _, err := sc.writeFrame([]byte("ok")); err != nil {
+		return nil, nil, err
+	}
+	return sc, deviceKey, nil
+}
+
+// deriveConn computes the shared secret and the two direction keys.
+func deriveConn(fc frameConn, eph *ecdh.PrivateKey, peerEph, clientEph, hostEph []byte, isClient bool) (*SecureConn, error) {
+	peer, err := ecdh.X25519().NewPublicKey(peerEph)
+	if err != nil {
+		return nil, errors.New("handshake: bad peer ephemeral key")
+	}
+	shared, err := eph.ECDH(peer)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := hkdf.Key(sha256.New, shared, append(append([]byte{}, clientEph...), hostEph...), protocolLabel, 64)
+	if err != nil {
+		return nil, err
+	}
+	c2h, err := newGCM(keys[:32])
+	if err != nil {
+		return nil, err
+	}
+	h2c, err := newGCM(keys[32:])
+	if err != nil {
+		return nil, err
+	}
+	sc := &SecureConn{fc: fc, send: c2h, recv: h2c}
+	if !isClient {
+		sc.send, sc.recv = h2c, c2h
+	}
+	return sc, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// SecureConn is an encrypted, ordered byte stream over a frameConn.
+type SecureConn struct {
+	fc   frameConn
+	send cipher.AEAD
+	recv cipher.AEAD
+
+	wmu     sync.Mutex
+	sendSeq uint64
+
+	rmu     sync.Mutex
+	recvSeq uint64
+	pending []byte
+}
+
+func seqNonce(seq uint64, size int) []byte {
+	nonce := make([]byte, size)
+	binary.BigEndian.PutUint64(nonce[size-8:], seq)
+	return nonce
+}
+
+func (c *SecureConn) writeFrame(p []byte) (int, error) {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	sealed := c.send.Seal(nil, seqNonce(c.sendSeq, c.send.NonceSize()), p, nil)
+	c.sendSeq++
+	if err := c.fc.WriteFrame(sealed); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (c *SecureConn) readFrame() ([]byte, error) {
+	sealed, err := c.fc.ReadFrame()
+	if err != nil {
+		return nil, err
+	}
+	plain, err := c.recv.Open(nil, seqNonce(c.recvSeq, c.recv.NonceSize()), sealed, nil)
+	if err != nil {
+		return nil, errors.New("relay stream: frame failed authentication")
+	}
+	c.recvSeq++
+	return plain, nil
+}
+
+// Write encrypts p, splitting it into frames of at most maxPlaintextFrame.
+func (c *SecureConn) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := min(len(p), maxPlaintextFrame)
+		if _, err := c.writeFrame(p[:n]); err != nil {
+			return written, err
+		}
+		written += n
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// Read returns decrypted stream bytes.
+func (c *SecureConn) Read(p []byte) (int, error) {
+	c.rmu.Lock()
+	defer c.rmu.Unlock()
+	for len(c.pending) == 0 {
+		frame, err := c.readFrame()
+		if err != nil {
+			return 0, err
+		}
+		c.pending = frame
+	}
+	n := copy(p, c.pending)
+	c.pending = c.pending[n:]
+	return n, nil
+}
+
+// Close closes the underlying transport.
+func (c *SecureConn) Close() error { return c.fc.Close() }
+
+func writeJSONFrame(fc frameConn, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return fc.WriteFrame(data)
+}
+
+func readJSONFrame(fc frameConn, v any) error {
+	data, err := fc.ReadFrame()
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("handshake: %w", err)
+	}
+	return nil
+}
+
+// pipe copies between a and b until either side ends, then closes both.
+func pipe(a, b io.ReadWriteCloser) {
+	done := make(chan struct{}, 2)
+	cp := func(dst io.Writer, src io.Reader) {
+		_, _ = io.Copy(dst, src)
+		done <- struct{}{}
+	}
+	go cp(a, b)
+	go cp(b, a)
+	<-done
+	_ = a.Close()
+	_ = b.Close()
+	<-done
+}
@@ -0,0 +1,301 @@
+package relay
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/asheshgoplani/agent-deck/internal/logging"
+)
+
+const (
+	// relayReadLimit caps one frame on the relay; sealed frames carry at
+	// most maxPlaintextFrame plus the GCM tag.
+	relayReadLimit = maxPlaintextFrame + 1024
+	// acceptTimeout is how long a device waits for the deck to pick up a
+	// stream before the relay gives up.
+	acceptTimeout = 10 * time.Second
+	// controlPingInterval keeps idle deck registrations alive through
+	// proxies and detects dead decks.
+	controlPingInterval = 30 * time.Second
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  maxPlaintextFrame,
+	WriteBufferSize: maxPlaintextFrame,
+	// Relay clients are agent-deck processes, not browsers; there is no
+	// ambient credential for a cross-origin page to abuse.
+	CheckOrigin: func(*http.Request) bool { return true },
+}
+
+// wsFrameConn adapts a WebSocket to frameConn using binary messages.
+type wsFrameConn struct {
+	conn *websocket.Conn
+	wmu  sync.Mutex
+}
+
+func newWSFrameConn(conn *websocket.Conn) *wsFrameConn {
+	conn.SetReadLimit(relayReadLimit)
+	return &wsFrameConn{conn: conn}
+}
+
+func (w *wsFrameConn) ReadFrame() ([]byte, error) {
+	for {
+		mt, data, err := w.conn.ReadMessage()
+		if err != nil {
+			return nil, err
+		}
+		if mt == websocket.BinaryMessage {
+			return data, nil
+		}
+	}
+}
+
+func (w *wsFrameConn) WriteFrame(p []byte) error {
+	w.wmu.Lock()
+	defer w.wmu.Unlock()
+	return w.conn.WriteMessage(websocket.BinaryMessage, p)
+}
+
+func (w *wsFrameConn) writeJSON(v any) error {
+	w.wmu.Lock()
+	defer w.wmu.Unlock()
+	return w.conn.WriteJSON(v)
+}
+
+func (w *wsFrameConn) Close() error { return w.conn.Close() }
+
+// controlMessage is a text frame on a deck's control connection.
+type controlMessage struct {
+	Type   string `json:"type"` // challenge, auth, registered, open
+	Nonce  string `json:"nonce,omitempty"`
+	Key    string `json:"key,omitempty"`
+	Sig    string `json:"sig,omitempty"`
+	Stream string `json:"stream,omitempty"`
+}
+
+// Server is the relay broker. It only matches decks with devices and copies
+// frames between them; it holds no keys and cannot read stream contents.
+type Server struct {
+	mu      sync.Mutex
+	decks   map[string]*wsFrameConn  // deck ID → control connection
+	pending map[string]pendingStream // stream ID → waiting device
+}
+
+// pendingStream is a device waiting for its deck to dial back.
+type pendingStream struct {
+	deckID string
+	ch     chan *websocket.Conn // unbuffered hand-off to the device handler
+	gone   chan struct{}        // closed when the device handler stops waiting
+}
+
+// NewServer returns an empty relay broker.
+func NewServer() *Server {
+	return &Server{
+		decks:   make(map[string]*wsFrameConn),
+		pending: make(map[string]pendingStream),
+	}
+}
+
+// Handler serves the relay endpoints:
+//
+//	GET /v1/deck/{deck}           deck control connection (challenge-signed)
+//	GET /v1/accept/{deck}/{id}    deck data connection for stream id
+//	GET /v1/connect/{deck}        device stream
+//	GET /healthz
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/deck/{deck}", s.handleDeck)
+	mux.HandleFunc("GET /v1/accept/{deck}/{stream}", s.handleAccept)
+	mux.HandleFunc("GET /v1/connect/{deck}", s.handleConnect)
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("ok\n"))
+	})
+	return mux
+}
+
+// Connected reports whether the deck is registered.
+func (s *Server) Connected(deckID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.decks[deckID]
+	return ok
+}
+
+func (s *Server) handleDeck(w http.ResponseWriter, r *http.Request) {
+	deckID := r.PathValue("deck")
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	fc := newWSFrameConn(conn)
+	defer fc.Close()
+
+	// Prove the deck owns deckID: sign a fresh nonce with the key it hashes to.
+	nonce := make([]byte, 32)
+	_, _ = rand.Read(nonce)
+	if err := fc.writeJSON(controlMessage{Type: "challenge", Nonce: b64.EncodeToString(nonce)}); err != nil {
+		return
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(acceptTimeout))
+	var auth controlMessage
+	if err := conn.ReadJSON(&auth); err != nil {
+		return
+	}
+	key, err := DecodeKey(auth.Key)
+	sig, sigErr := b64.DecodeString(auth.Sig)
+	if err != nil || sigErr != nil || KeyID(key) != deckID ||
+		!ed25519.Verify(key, signedPayload([]byte("register"), nonce), sig) {
+		_ = conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "deck authentication failed"),
+			time.Now().Add(time.Second))
+		return
+	}
+	_ = conn.SetReadDeadline(time.Time{})
+
+	s.mu.Lock()
+	if old := s.decks[deckID]; old != nil {
+		// A restarted deck replaces its stale registration.
+		_ = old.Close()
+	}
+	s.decks[deckID] = fc
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		if s.decks[deckID] == fc {
+			delete(s.decks, deckID)
+		}
+		s.mu.Unlock()
+	}()
+	logging.ForComponent(logging.CompRelay).Info("relay_deck_registered", slog.String("deck_id", deckID))
+	_ = fc.writeJSON(controlMessage{Type: "registered"})
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	go func() {
+		t := time.NewTicker(controlPingInterval)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				if conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(5*time.Second)) != nil {
+					_ = fc.Close()
+					return
+				}
+			}
+		}
+	}()
+	// The deck sends nothing after auth; reading surfaces its disconnect.
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) handleConnect(w http.ResponseWriter, r *http.Request) {
+	deckID := r.PathValue("deck")
+	s.mu.Lock()
+	control := s.decks[deckID]
+	s.mu.Unlock()
+	if control == nil {
+		http.Error(w, "deck is not connected to this relay", http.StatusServiceUnavailable)
+		return
+	}
+
+	raw := make([]byte, 16)
+	_, _ = rand.Read(raw)
+	streamID := b64.EncodeToString(raw)
+	ch, gone := make(chan *websocket.Conn), make(chan struct{})
+	s.mu.Lock()
+	s.pending[streamID] = pendingStream{deckID: deckID, ch: ch, gone: gone}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, streamID)
+		s.mu.Unlock()
+		close(gone)
+	}()
+
+	if err := control.writeJSON(controlMessage{Type: "open", Stream: streamID}); err != nil {
+		http.Error(w, "deck is not reachable", http.StatusBadGateway)
+		return
+	}
+	var deckConn *websocket.Conn
+	select {
+	case deckConn = <-ch:
+	case <-time.After(acceptTimeout):
+		http.Error(w, "deck did not accept the stream", http.StatusGatewayTimeout)
+		return
+	case <-r.Context().Done():
+		return
+	}
+
+	deviceConn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		_ = deckConn.Close()
+		return
+	}
+	splice(newWSFrameConn(deviceConn), newWSFrameConn(deckConn))
+}
+
+func (s *Server) handleAccept(w http.ResponseWriter, r *http.Request) {
+	streamID := r.PathValue("stream")
+	s.mu.Lock()
+	p, ok := s.pending[streamID]
+	if ok && p.deckID == r.PathValue("deck") {
+		delete(s.pending, streamID)
+	} else {
+		ok = false
+	}
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, "unknown stream", http.StatusNotFound)
+		return
+	}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	// The device handler owns the connection from here; if it already gave
+	// up, nobody will splice it.
+	select {
+	case p.ch <- conn:
+	case <-p.gone:
+		_ = conn.Close()
+	}
+}
+
+// splice copies frames both ways until either side closes.
+func splice(a, b *wsFrameConn) {
+	done := make(chan struct{}, 2)
+	cp := func(dst, src *wsFrameConn) {
+		for {
+			frame, err := src.ReadFrame()
+			if err != nil || dst.WriteFrame(frame) != nil {
+				break
+			}
+		}
+		done <- struct{}{}
+	}
+	go cp(a, b)
+	go cp(b, a)
+	<-done
+	_ = a.Close()
+	_ = b.Close()
+	<-done
+}
+
+// errDeckOffline is reported by devices when the relay has no registration
+// for the deck.
+var errDeckOffline = errors.New("deck is not connected to the relay (is 'agent-deck web --relay' running?)")
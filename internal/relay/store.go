@@ -0,0 +1,358 @@
+package relay
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/atomicfile"
+)
+
+// DefaultPairingTTL is how long an unused pairing offer stays valid.
+const DefaultPairingTTL = 10 * time.Minute
+
+// PairedDevice is a device allowed to open streams to this deck.
+type PairedDevice struct {
+	Name      string    `json:"name"`
+	PublicKey string    `json:"public_key"`
+	PairedAt  time.Time `json:"paired_at"`
+	LastSeen  time.Time `json:"last_seen,omitzero"`
+}
+
+// PendingPairing is an outstanding offer. Only the token's hash is stored.
+type PendingPairing struct {
+	Name      string    `json:"name"`
+	TokenHash string    `json:"token_hash"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type hostState struct {
+	Devices []PairedDevice   `json:"devices"`
+	Pending []PendingPairing `json:"pending,omitempty"`
+}
+
+// HostStore is the deck's list of paired devices and outstanding offers,
+// persisted as JSON. Safe for concurrent use.
+type HostStore struct {
+	path string
+
+	mu    sync.Mutex
+	state hostState
+}
+
+// OpenHostStore loads the store at path; a missing file is an empty store.
+func OpenHostStore(path string) (*HostStore, error) {
+	s := &HostStore{path: path}
+	if err := readJSON(path, &s.state); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Devices returns the paired devices sorted by name.
+func (s *HostStore) Devices() []PairedDevice {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := append([]PairedDevice(nil), s.state.Devices...)
+	sort.Slice(out, func(a, b int) bool { return out[a].Name < out[b].Name })
+	return out
+}
+
+// NewOffer registers a single-use pairing token for a device called name and
+// returns the offer to hand to that device.
+func (s *HostStore) NewOffer(id *Identity, relayURL, deckName, name string, ttl time.Duration) (Offer, error) {
+	if ttl <= 0 {
+		ttl = DefaultPairingTTL
+	}
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return Offer{}, err
+	}
+	token := b64.EncodeToString(raw)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prunePendingLocked(time.Now())
+	s.state.Pending = append(s.state.Pending, PendingPairing{
+		Name:      name,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(ttl).UTC(),
+	})
+	if err := s.saveLocked(); err != nil {
+		return Offer{}, err
+	}
+	return Offer{
+		RelayURL: relayURL,
+		DeckID:   id.ID(),
+		DeckKey:  EncodeKey(id.Public),
+		DeckName: deckName,
+		Token:    token,
+	}, nil
+}
+
+// HasPending reports whether an unexpired pairing offer exists.
+func (s *HostStore) HasPending() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prunePendingLocked(time.Now())
+	return len(s.state.Pending) > 0
+}
+
+// lastSeenResolution limits how often IsPaired rewrites the store: a browser
+// opens a stream per HTTP connection.
+const lastSeenResolution = time.Minute
+
+// IsPaired reports whether pub belongs to a paired device, and records the
+// contact time when it does.
+func (s *HostStore) IsPaired(pub ed25519.PublicKey) bool {
+	key := EncodeKey(pub)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.state.Devices {
+		if s.state.Devices[i].PublicKey == key {
+			if now := time.Now().UTC(); now.Sub(s.state.Devices[i].LastSeen) > lastSeenResolution {
+				s.state.Devices[i].LastSeen = now
+				_ = s.saveLocked()
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// CompletePairing consumes token and adds pub as a paired device. The device
+// takes the name given when the offer was created, suffixed if it clashes.
+func (s *HostStore) CompletePairing(token string, pub ed25519.PublicKey) (PairedDevice, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prunePendingLocked(time.Now())
+
+	want := hashToken(token)
+	for i, p := range s.state.Pending {
+		if subtle.ConstantTimeCompare([]byte(p.TokenHash), []byte(want)) != 1 {
+			continue
+		}
+		s.state.Pending = append(s.state.Pending[:i:i], s.state.Pending[i+1:]...)
+		key := EncodeKey(pub)
+		for _, d := range s.state.Devices {
+			if d.PublicKey == key {
+				return d, s.saveLocked()
+			}
+		}
+		dev := PairedDevice{
+			Name:      s.uniqueNameLocked(p.Name),
+			PublicKey: key,
+			PairedAt:  time.Now().UTC(),
+		}
+		s.state.Devices = append(s.state.Devices, dev)
+		return dev, s.saveLocked()
+	}
+	return PairedDevice{}, errors.New("pairing token is invalid or expired")
+}
+
+// Revoke removes the paired device called name.
+func (s *HostStore) Revoke(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, d := range s.state.Devices {
+		if d.Name == name {
+			s.state.Devices = append(s.state.Devices[:i:i], s.state.Devices[i+1:]...)
+			return s.saveLocked()
+		}
+	}
+	return fmt.Errorf("no paired device named %q", name)
+}
+
+func (s *HostStore) uniqueNameLocked(name string) string {
+	if name == "" {
+		name = "device"
+	}
+	taken := map[string]bool{}
+	for _, d := range s.state.Devices {
+		taken[d.Name] = true
+	}
+	candidate := name
+	for n := 2; taken[candidate]; n++ {
+		candidate = fmt.Sprintf("%s-%d", name, n)
+	}
+	return candidate
+}
+
+func (s *HostStore) prunePendingLocked(now time.Time) {
+	kept := s.state.Pending[:0]
+	for _, p := range s.state.Pending {
+		if now.Before(p.ExpiresAt) {
+			kept = append(kept, p)
+		}
+	}
+	s.state.Pending = kept
+}
+
+func (s *HostStore) saveLocked() error {
+	return writeJSON(s.path, s.state)
+}
+
+// PairedDeck is a deck this device has paired with.
+type PairedDeck struct {
+	Name     string    `json:"name"`
+	RelayURL string    `json:"relay_url"`
+	DeckID   string    `json:"deck_id"`
+	DeckKey  string    `json:"deck_key"`
+	PairedAt time.Time `json:"paired_at"`
+}
+
+type deviceState struct {
+	Decks []PairedDeck `json:"decks"`
+}
+
+// DeviceStore is a device's list of paired decks. Safe for concurrent use.
+type DeviceStore struct {
+	path string
+
+	mu    sync.Mutex
+	state deviceState
+}
+
+// OpenDeviceStore loads the store at path; a missing file is an empty store.
+func OpenDeviceStore(path string) (*DeviceStore, error) {
+	s := &DeviceStore{path: path}
+	if err := readJSON(path, &s.state); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Decks returns the paired decks sorted by name.
+func (s *DeviceStore) Decks() []PairedDeck {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := append([]PairedDeck(nil), s.state.Decks...)
+	sort.Slice(out, func(a, b int) bool { return out[a].Name < out[b].Name })
+	return out
+}
+
+// Find returns the deck whose name or deck ID is ref. An empty ref selects
+// the only paired deck.
+func (s *DeviceStore) Find(ref string) (PairedDeck, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ref == "" {
+		switch len(s.state.Decks) {
+		case 0:
+			return PairedDeck{}, errors.New("no paired decks (run 'agent-deck relay join <offer>')")
+		case 1:
+			return s.state.Decks[0], nil
+		default:
+			return PairedDeck{}, errors.New("several decks are paired; name one")
+		}
+	}
+	for _, d := range s.state.Decks {
+		if d.Name == ref || d.DeckID == ref {
+			return d, nil
+		}
+	}
+	return PairedDeck{}, fmt.Errorf("no paired deck named %q", ref)
+}
+
+// Add records deck, replacing an earlier pairing with the same deck ID.
+func (s *DeviceStore) Add(deck PairedDeck) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, d := range s.state.Decks {
+		if d.DeckID == deck.DeckID {
+			s.state.Decks[i] = deck
+			return writeJSON(s.path, s.state)
+		}
+	}
+	s.state.Decks = append(s.state.Decks, deck)
+	return writeJSON(s.path, s.state)
+}
+
+// Remove forgets the deck whose name or deck ID is ref.
+func (s *DeviceStore) Remove(ref string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, d := range s.state.Decks {
+		if d.Name == ref || d.DeckID == ref {
+			s.state.Decks = append(s.state.Decks[:i:i], s.state.Decks[i+1:]...)
+			return writeJSON(s.path, s.state)
+		}
+	}
+	return fmt.Errorf("no paired deck named %q", ref)
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return b64.EncodeToString(sum[:])
+}
+
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+	return nil
+}
+
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(path, data, 0o600)
+}
+
+// File names inside the relay directory. A machine can be a deck and a
+// device at once, so the two roles keep separate keys.
+const (
+	deckKeyFile    = "deck.key"
+	devicesFile    = "devices.json"
+	deviceKeyFile  = "device.key"
+	pairedDeckFile = "decks.json"
+)
+
+// OpenDeck loads (creating on first use) the deck identity and paired
+// device list under dir.
+func OpenDeck(dir string) (*Identity, *HostStore, error) {
+	id, err := LoadOrCreateIdentity(filepath.Join(dir, deckKeyFile))
+	if err != nil {
+		return nil, nil, err
+	}
+	store, err := OpenHostStore(filepath.Join(dir, devicesFile))
+	if err != nil {
+		return nil, nil, err
+	}
+	return id, store, nil
+}
+
+// OpenDevice loads (creating on first use) the device identity and paired
+// deck list under dir.
+func OpenDevice(dir string) (*Identity, *DeviceStore, error) {
+	id, err := LoadOrCreateIdentity(filepath.Join(dir, deviceKeyFile))
+	if err != nil {
+		return nil, nil, err
+	}
+	store, err := OpenDeviceStore(filepath.Join(dir, pairedDeckFile))
+	if err != nil {
+		return nil, nil, err
+	}
+	return id, store, nil
+}
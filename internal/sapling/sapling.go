@@ -0,0 +1,352 @@
+// Package sapling provides Sapling (sl) VCS operations for agent-deck.
+// It mirrors the internal/jujutsu package: a vcs.Backend implementation
+// whose methods shell out to the sl CLI.
+//
+// Sapling has no first-class linked worktrees, so an agent-deck "worktree"
+// is a shared checkout created with `sl share`: a separate working copy
+// backed by the same store (commits and bookmarks are visible from every
+// checkout). Sapling does not list shares, so the backend records the ones
+// it creates in <dotdir>/agent-deck-worktrees, one absolute path per line.
+package sapling
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/git"
+	"github.com/asheshgoplani/agent-deck/internal/vcs"
+)
+
+// registryFile is the name of the share registry inside the repo's dot dir.
+const registryFile = "agent-deck-worktrees"
+
+type SLBackend struct {
+	repoDir string
+}
+
+// Compile-time check that *SLBackend satisfies vcs.Backend.
+var _ vcs.Backend = (*SLBackend)(nil)
+
+func NewSLBackend(dir string) (*SLBackend, error) {
+	if !IsSaplingRepo(dir) {
+		return nil, fmt.Errorf("not a sapling repository: %s", dir)
+	}
+	root, err := GetRepoRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &SLBackend{root}, nil
+}
+
+func (b *SLBackend) Type() vcs.Type { return vcs.TypeSapling }
+
+// RepoDir returns the root directory of the repository.
+func (b *SLBackend) RepoDir() string { return b.repoDir }
+
+// WorktreePath generates a checkout path using the backend's repoDir.
+// Delegates to the shared template logic in the git package (VCS-agnostic).
+func (b *SLBackend) WorktreePath(opts vcs.WorktreePathOptions) string {
+	return git.WorktreePath(git.WorktreePathOptions{
+		Branch:    opts.Branch,
+		Location:  opts.Location,
+		RepoDir:   b.repoDir,
+		SessionID: opts.SessionID,
+		Template:  opts.Template,
+	})
+}
+
+// IsSaplingRepo checks if the given directory is inside a Sapling repository
+// by running `sl root`. Returns false if sl is not installed or the directory
+// is not a Sapling repo.
+func IsSaplingRepo(dir string) bool {
+	if _, err := exec.LookPath("sl"); err != nil {
+		return false
+	}
+	cmd := exec.Command("sl", "root", "--cwd", dir) // #nosec G204 -- sl invocations with slice args, not shell-formed
+	return cmd.Run() == nil
+}
+
+// GetRepoRoot returns the root directory of the checkout containing dir.
+func GetRepoRoot(dir string) (string, error) {
+	cmd := exec.Command("sl", "root", "--cwd", dir) // #nosec G204 -- sl invocations with slice args, not shell-formed
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("not a sapling repository: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// run executes sl in dir and returns trimmed combined output, wrapping
+// failures with what.
+func run(dir, what string, args ...string) (string, error) {
+	args = append(args, "--cwd", dir)
+	cmd := exec.Command("sl", args...) // #nosec G204 -- sl invocations with slice args + internal repoDir/branch fields, not shell-formed
+	output, err := cmd.CombinedOutput()
+	out := strings.TrimSpace(string(output))
+	if err != nil {
+		return out, fmt.Errorf("%s: %s: %w", what, out, err)
+	}
+	return out, nil
+}
+
+// GetCurrentBranch returns the active bookmark of the repo's working copy,
+// or "" when no bookmark is active.
+func (b *SLBackend) GetCurrentBranch() (string, error) {
+	return activeBookmark(b.repoDir)
+}
+
+func activeBookmark(dir string) (string, error) {
+	out, err := run(dir, "failed to get active bookmark", "log", "-r", ".", "-T", "{activebookmark}")
+	if err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+// BranchExists checks if a bookmark exists in the repository.
+func (b *SLBackend) BranchExists(branchName string) bool {
+	out, err := run(b.repoDir, "failed to list bookmarks", "bookmarks", "-T", "{bookmark}\n")
+	if err != nil {
+		return false
+	}
+	for _, name := range parseBookmarks(out) {
+		if name == branchName {
+			return true
+		}
+	}
+	return false
+}
+
+func parseBookmarks(output string) []string {
+	var names []string
+	for _, line := range strings.Split(output, "\n") {
+		if name := strings.TrimSpace(line); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// GetDefaultBranch returns the default branch name (checks for main/master bookmarks).
+func (b *SLBackend) GetDefaultBranch() (string, error) {
+	if b.BranchExists("main") {
+		return "main", nil
+	}
+	if b.BranchExists("master") {
+		return "master", nil
+	}
+	return "", errors.New("could not determine default branch (no main or master bookmark)")
+}
+
+// DeleteBranch deletes a bookmark. The commits stay reachable until hidden.
+func (b *SLBackend) DeleteBranch(branchName string, force bool) error {
+	_, err := run(b.repoDir, "failed to delete bookmark", "bookmark", "--delete", branchName)
+	return err
+}
+
+// MergeBranch folds branchName into the current bookmark the Sapling way:
+// the branch's commits are rebased onto the working-copy commit and the
+// current bookmark is moved forward to the rebased tip, keeping history
+// linear.
+func (b *SLBackend) MergeBranch(branchName string) error {
+	current, err := b.GetCurrentBranch()
+	if err != nil {
+		return err
+	}
+	if _, err := run(b.repoDir, "merge failed", "rebase", "--base", branchName, "--dest", "."); err != nil {
+		return err
+	}
+	if current == "" {
+		_, err = run(b.repoDir, "merge failed", "goto", branchName)
+		return err
+	}
+	if _, err := run(b.repoDir, "merge failed", "bookmark", "--force", "--rev", branchName, current); err != nil {
+		return err
+	}
+	_, err = run(b.repoDir, "merge failed", "goto", current)
+	return err
+}
+
+// CreateWorktree creates a shared checkout at worktreePath with branchName
+// active: an existing bookmark is checked out, otherwise a new bookmark is
+// created at the repo's working-copy commit.
+func (b *SLBackend) CreateWorktree(worktreePath, branchName string) error {
+	base, err := run(b.repoDir, "failed to resolve working-copy commit", "log", "-r", ".", "-T", "{node}")
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command("sl", "share", "--noupdate", "--bookmarks", b.repoDir, worktreePath) // #nosec G204 -- sl invocations with slice args, not shell-formed
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to create shared checkout: %s: %w", strings.TrimSpace(string(output)), err)
+	}
+	if err := b.register(worktreePath); err != nil {
+		return err
+	}
+
+	if branchName != "" && b.BranchExists(branchName) {
+		_, err = run(worktreePath, "failed to check out bookmark", "goto", branchName)
+		return err
+	}
+	if _, err := run(worktreePath, "failed to check out base commit", "goto", base); err != nil {
+		return err
+	}
+	if branchName != "" {
+		if _, err := run(worktreePath, "failed to create bookmark", "bookmark", branchName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListWorktrees returns the main checkout followed by every registered
+// shared checkout that still exists on disk.
+func (b *SLBackend) ListWorktrees() ([]vcs.Worktree, error) {
+	paths, err := b.registered()
+	if err != nil {
+		return nil, err
+	}
+	var out []vcs.Worktree
+	for _, p := range append([]string{b.repoDir}, paths...) {
+		if _, err := os.Stat(p); err != nil {
+			continue
+		}
+		wt := vcs.Worktree{Path: p}
+		wt.Branch, _ = activeBookmark(p)
+		if commit, err := run(p, "failed to resolve commit", "log", "-r", ".", "-T", "{node}"); err == nil {
+			wt.Commit = commit
+		}
+		out = append(out, wt)
+	}
+	return out, nil
+}
+
+// GetWorktreeForBranch returns the checkout whose active bookmark is
+// branchName, or "" when none is.
+func (b *SLBackend) GetWorktreeForBranch(branchName string) (string, error) {
+	worktrees, err := b.ListWorktrees()
+	if err != nil {
+		return "", err
+	}
+	for _, wt := range worktrees {
+		if wt.Branch == branchName {
+			return wt.Path, nil
+		}
+	}
+	return "", nil
+}
+
+// RemoveWorktree deletes a shared checkout. Without force it refuses to
+// drop uncommitted changes. The store and bookmarks are untouched.
+func (b *SLBackend) RemoveWorktree(worktreePath string, force bool) error {
+	if filepath.Clean(worktreePath) == filepath.Clean(b.repoDir) {
+		return errors.New("refusing to remove the main checkout")
+	}
+	if !force {
+		if dirty, err := b.HasUncommittedChanges(worktreePath); err == nil && dirty {
+			return fmt.Errorf("checkout has uncommitted changes: %s", worktreePath)
+		}
+	}
+	if err := os.RemoveAll(worktreePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove checkout directory: %w", err)
+	}
+	return b.unregister(func(p string) bool { return filepath.Clean(p) == filepath.Clean(worktreePath) })
+}
+
+// PruneWorktrees drops registry entries whose directories no longer exist.
+func (b *SLBackend) PruneWorktrees() error {
+	return b.unregister(func(p string) bool {
+		_, err := os.Stat(p)
+		return os.IsNotExist(err)
+	})
+}
+
+// HasUncommittedChanges reports whether the checkout at worktreePath has
+// modified, added, removed or untracked files.
+func (b *SLBackend) HasUncommittedChanges(worktreePath string) (bool, error) {
+	out, err := run(worktreePath, "failed to check sl status", "status")
+	if err != nil {
+		return false, err
+	}
+	return out != "", nil
+}
+
+// registryPath returns <repo>/<dotdir>/agent-deck-worktrees. Sapling-native
+// clones use .sl; repos converted from Mercurial keep .hg.
+func (b *SLBackend) registryPath() string {
+	dotdir := filepath.Join(b.repoDir, ".sl")
+	if _, err := os.Stat(dotdir); err != nil {
+		if _, hgErr := os.Stat(filepath.Join(b.repoDir, ".hg")); hgErr == nil {
+			dotdir = filepath.Join(b.repoDir, ".hg")
+		}
+	}
+	return filepath.Join(dotdir, registryFile)
+}
+
+func (b *SLBackend) registered() ([]string, error) {
+	f, err := os.Open(b.registryPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseRegistry(f)
+}
+
+func parseRegistry(r io.Reader) ([]string, error) {
+	var paths []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if p := strings.TrimSpace(scanner.Text()); p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths, scanner.Err()
+}
+
+func (b *SLBackend) register(worktreePath string) error {
+	abs, err := filepath.Abs(worktreePath)
+	if err != nil {
+		return err
+	}
+	paths, err := b.registered()
+	if err != nil {
+		return err
+	}
+	for _, p := range paths {
+		if p == abs {
+			return nil
+		}
+	}
+	return writeRegistry(b.registryPath(), append(paths, abs))
+}
+
+func (b *SLBackend) unregister(drop func(string) bool) error {
+	paths, err := b.registered()
+	if err != nil || len(paths) == 0 {
+		return err
+	}
+	kept := paths[:0]
+	for _, p := range paths {
+		if !drop(p) {
+			kept = append(kept, p)
+		}
+	}
+	return writeRegistry(b.registryPath(), kept)
+}
+
+func writeRegistry(path string, paths []string) error {
+	var sb strings.Builder
+	for _, p := range paths {
+		sb.WriteString(p)
+		sb.WriteString("\n")
+	}
+	return os.WriteFile(path, []byte(sb.String()), 0o644)
+}
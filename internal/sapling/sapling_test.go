@@ -0,0 +1,91 @@
+package sapling
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestSaplingDetect_NonSaplingDir verifies that IsSaplingRepo returns false
+// for a directory that is not a Sapling repository (or when sl is missing).
+func TestSaplingDetect_NonSaplingDir(t *testing.T) {
+	tmp := t.TempDir()
+	if IsSaplingRepo(tmp) {
+		t.Fatalf("IsSaplingRepo returned true for non-sapling dir %s", tmp)
+	}
+	if _, err := NewSLBackend(tmp); err == nil {
+		t.Fatalf("NewSLBackend(%s) should error for non-sapling dir", tmp)
+	}
+}
+
+// TestSaplingDetect_SLNotInstalled covers the missing-binary path.
+func TestSaplingDetect_SLNotInstalled(t *testing.T) {
+	if _, err := exec.LookPath("sl"); err == nil {
+		t.Skip("sl binary is available; skipping not-installed path")
+	}
+	if IsSaplingRepo(t.TempDir()) {
+		t.Fatal("IsSaplingRepo should return false when sl is not installed")
+	}
+}
+
+func TestSaplingParseBookmarks(t *testing.T) {
+	got := parseBookmarks("main\n  feature/x \n\n")
+	want := []string{"main", "feature/x"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseBookmarks = %q, want %q", got, want)
+	}
+}
+
+// TestSaplingRegistry exercises the share registry without invoking sl:
+// register dedupes, prune drops vanished checkouts, and .hg repos keep the
+// registry under .hg.
+func TestSaplingRegistry(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".sl"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	b := &SLBackend{repoDir: repo}
+
+	live := t.TempDir()
+	gone := filepath.Join(t.TempDir(), "gone")
+	for _, p := range []string{live, gone, live} {
+		if err := b.register(p); err != nil {
+			t.Fatalf("register(%s): %v", p, err)
+		}
+	}
+	if got, _ := b.registered(); !reflect.DeepEqual(got, []string{live, gone}) {
+		t.Fatalf("registered = %q", got)
+	}
+
+	if err := b.PruneWorktrees(); err != nil {
+		t.Fatalf("PruneWorktrees: %v", err)
+	}
+	if got, _ := b.registered(); !reflect.DeepEqual(got, []string{live}) {
+		t.Fatalf("after prune = %q", got)
+	}
+	if !strings.HasPrefix(b.registryPath(), filepath.Join(repo, ".sl")) {
+		t.Errorf("registry should live under .sl, got %s", b.registryPath())
+	}
+
+	hgRepo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(hgRepo, ".hg"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if p := (&SLBackend{repoDir: hgRepo}).registryPath(); p != filepath.Join(hgRepo, ".hg", registryFile) {
+		t.Errorf("hg registry path = %s", p)
+	}
+}
+
+func TestSaplingRemoveWorktree_RefusesMainCheckout(t *testing.T) {
+	repo := t.TempDir()
+	b := &SLBackend{repoDir: repo}
+	if err := b.RemoveWorktree(repo+"/", true); err == nil {
+		t.Fatal("removing the main checkout must fail")
+	}
+	if _, err := os.Stat(repo); err != nil {
+		t.Fatalf("main checkout was touched: %v", err)
+	}
+}
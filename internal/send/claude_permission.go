@@ -0,0 +1,304 @@
+package send
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// ClaudePermissionTarget is the tmux surface needed to answer a Claude
+// permission dialog: a fresh capture and a single named key.
+type ClaudePermissionTarget interface {
+	CapturePaneFresh() (string, error)
+	SendNamedKey(string) error
+}
+
+// Kinds of Claude permission requests, derived from the dialog header.
+const (
+	ClaudePermissionBash  = "bash"
+	ClaudePermissionEdit  = "edit"
+	ClaudePermissionWrite = "write"
+	ClaudePermissionRead  = "read"
+	ClaudePermissionFetch = "fetch"
+	ClaudePermissionMCP   = "mcp"
+	ClaudePermissionOther = "other"
+)
+
+// Answers a Claude permission dialog can be given.
+const (
+	ClaudeAnswerAllow       = "allow"
+	ClaudeAnswerAllowAlways = "allow_always"
+	ClaudeAnswerDeny        = "deny"
+)
+
+// ClaudePermissionOption is one numbered choice of the dialog.
+type ClaudePermissionOption struct {
+	Number int
+	Label  string
+}
+
+// ClaudePermissionPrompt is a live Claude permission dialog.
+type ClaudePermissionPrompt struct {
+	// Kind is one of the ClaudePermission* constants.
+	Kind string
+	// Header is the dialog title as shown ("Bash command", "Edit file", ...).
+	Header string
+	// Request is what is being asked for: the file for edit/write/read,
+	// otherwise every line of the dialog body (the command and Claude's
+	// description of it for bash, the URL for fetch, the tool call for
+	// MCP) joined with newlines.
+	Request string
+	// Command is the request without Claude's trailing description: the
+	// lines the pending tool call above the dialog ("⏺ Bash(npm test)")
+	// shows. When that call is missing or does not match the body, it is
+	// all of Request.
+	Command string
+	// Question is the "Do you want to ...?" line.
+	Question    string
+	Options     []ClaudePermissionOption
+	Fingerprint string
+}
+
+// claudePermissionHeaders maps dialog titles to request kinds.
+var claudePermissionHeaders = []struct {
+	prefix string
+	kind   string
+}{
+	{"bash command", ClaudePermissionBash},
+	{"edit file", ClaudePermissionEdit},
+	{"create file", ClaudePermissionWrite},
+	{"write file", ClaudePermissionWrite},
+	{"overwrite file", ClaudePermissionWrite},
+	{"read file", ClaudePermissionRead},
+	{"read files", ClaudePermissionRead},
+	{"fetch", ClaudePermissionFetch},
+	{"tool use", ClaudePermissionMCP},
+}
+
+var (
+	claudePermissionOptionPattern = regexp.MustCompile(`^\s*(❯\s*)?([1-9])\.\s+(.+?)\s*$`)
+	claudePermissionFilePattern   = regexp.MustCompile(`^Do you want to (?:make this edit to|create|overwrite|read) (.+?)\?$`)
+)
+
+// trimClaudeDialogLine strips the box border Claude draws around older
+// permission dialogs and surrounding whitespace.
+func trimClaudeDialogLine(line string) string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "│")
+	line = strings.TrimSuffix(line, "│")
+	return strings.TrimSpace(line)
+}
+
+// DetectClaudePermissionPrompt returns the permission dialog at the bottom of
+// a Claude pane, or nil. The pane must also look like it is waiting to the
+// status PromptDetector, so a dialog scrolled into history or one Claude is
+// already acting on is never answered.
+func DetectClaudePermissionPrompt(content string) *ClaudePermissionPrompt {
+	content = tmux.StripANSI(content)
+	if !tmux.NewPromptDetector("claude").HasPrompt(content) {
+		return nil
+	}
+	raw := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	start := len(raw) - 40
+	if start < 0 {
+		start = 0
+	}
+	lines := make([]string, 0, len(raw)-start)
+	for _, l := range raw[start:] {
+		lines = append(lines, trimClaudeDialogLine(l))
+	}
+
+	question := -1
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.HasPrefix(lines[i], "Do you want") {
+			question = i
+			break
+		}
+	}
+	if question < 0 {
+		return nil
+	}
+
+	p := &ClaudePermissionPrompt{Question: lines[question]}
+	selected := false
+	hasYes, hasNo := false, false
+	last := question
+	for i := question + 1; i < len(lines) && i <= question+6; i++ {
+		m := claudePermissionOptionPattern.FindStringSubmatch(lines[i])
+		if m == nil {
+			if len(p.Options) > 0 && lines[i] != "" {
+				break
+			}
+			continue
+		}
+		n, _ := strconv.Atoi(m[2])
+		label := strings.Join(strings.Fields(m[3]), " ")
+		lower := strings.ToLower(label)
+		hasYes = hasYes || strings.HasPrefix(lower, "yes")
+		hasNo = hasNo || strings.HasPrefix(lower, "no")
+		selected = selected || m[1] != ""
+		p.Options = append(p.Options, ClaudePermissionOption{Number: n, Label: label})
+		last = i
+	}
+	// Anything but a hint line below the menu means the dialog is history.
+	for _, l := range lines[last+1:] {
+		if l != "" && !strings.HasPrefix(l, "╰") && !strings.HasPrefix(strings.ToLower(l), "esc to") {
+			return nil
+		}
+	}
+	if len(p.Options) < 2 || !selected || !hasYes || !hasNo {
+		return nil
+	}
+
+	header := -1
+	for i := question - 1; i >= 0 && i >= question-30; i-- {
+		lower := strings.ToLower(lines[i])
+		for _, h := range claudePermissionHeaders {
+			if lower == h.prefix || strings.HasPrefix(lower, h.prefix+" ") || strings.HasPrefix(lower, h.prefix+":") {
+				header, p.Kind, p.Header = i, h.kind, lines[i]
+				break
+			}
+		}
+		if header >= 0 {
+			break
+		}
+	}
+	if header < 0 {
+		p.Kind, header = ClaudePermissionOther, question
+	}
+
+	switch p.Kind {
+	case ClaudePermissionEdit, ClaudePermissionWrite, ClaudePermissionRead:
+		if m := claudePermissionFilePattern.FindStringSubmatch(p.Question); m != nil {
+			p.Request = m[1]
+		}
+	}
+	if p.Request == "" {
+		// Every body line belongs to the request: a command that wraps or
+		// spans lines must not be judged by its first line alone.
+		var body []string
+		for _, l := range lines[header+1 : question] {
+			if l = trimClaudeDialogLine(l); l != "" && !strings.HasPrefix(l, "╭") && !strings.HasPrefix(l, "╰") {
+				body = append(body, l)
+			}
+		}
+		p.Request = strings.Join(body, "\n")
+		p.Command = claudePermissionCommand(body, lines[:header])
+	} else {
+		p.Command = p.Request
+	}
+
+	var fp []string
+	for _, l := range lines[header : last+1] {
+		l = strings.TrimSpace(strings.TrimPrefix(l, "❯"))
+		if l = strings.Join(strings.Fields(l), " "); l != "" {
+			fp = append(fp, l)
+		}
+	}
+	p.Fingerprint = strings.Join(fp, "\n")
+	return p
+}
+
+// claudePermissionCommand splits a dialog body into the command and
+// Claude's description of it. The tool call Claude shows above the dialog
+// while it waits ("⏺ Bash(npm test)" over "⎿  Running…") carries the command
+// alone: the leading body lines that spell it out are the command, and at
+// most one line may follow them as the description. When there is no such
+// pending call, or it is truncated or does not match, every body line
+// counts as the command, so an allow rule never judges part of what runs.
+func claudePermissionCommand(body, above []string) string {
+	all := strings.Join(body, "\n")
+	call := ""
+	for i := len(above) - 1; i >= 0; i-- {
+		l := above[i]
+		if c, ok := strings.CutPrefix(l, "⏺ "); ok {
+			call = strings.TrimSpace(c)
+			break
+		}
+		// Only the pending call's status line may sit between it and the
+		// dialog; anything else means the call is an older, finished one.
+		if l != "" && !(strings.HasPrefix(l, "⎿") && (strings.Contains(l, "Running") || strings.Contains(l, "Waiting"))) {
+			return all
+		}
+	}
+	open := strings.Index(call, "(")
+	if open < 0 || !strings.HasSuffix(call, ")") {
+		return all
+	}
+	arg := call[open+1 : len(call)-1]
+	for k := 1; k <= len(body); k++ {
+		if strings.Join(body[:k], " ") == arg {
+			if len(body)-k > 1 {
+				return all
+			}
+			return strings.Join(body[:k], "\n")
+		}
+	}
+	return all
+}
+
+// Option returns the displayed option for an answer: the plain "Yes" for
+// allow, the "don't ask again" / "during this session" choice for
+// allow_always and the "No" choice for deny.
+func (p *ClaudePermissionPrompt) Option(answer string) (ClaudePermissionOption, error) {
+	for _, o := range p.Options {
+		lower := strings.ToLower(o.Label)
+		switch answer {
+		case ClaudeAnswerAllow:
+			if lower == "yes" || strings.HasPrefix(lower, "yes, allow once") || strings.HasPrefix(lower, "yes, proceed") {
+				return o, nil
+			}
+		case ClaudeAnswerAllowAlways:
+			if strings.HasPrefix(lower, "yes") &&
+				(strings.Contains(lower, "don't ask again") || strings.Contains(lower, "during this session") || strings.Contains(lower, "allow always")) {
+				return o, nil
+			}
+		case ClaudeAnswerDeny:
+			if strings.HasPrefix(lower, "no") {
+				return o, nil
+			}
+		default:
+			return ClaudePermissionOption{}, fmt.Errorf("unknown answer %q", answer)
+		}
+	}
+	return ClaudePermissionOption{}, fmt.Errorf("the dialog offers no %s option", answer)
+}
+
+// AnswerClaudePermission selects the option for answer in the dialog whose
+// fingerprint is want. The pane is re-captured first and nothing is sent when
+// the dialog has changed, so a digit never lands in Claude's composer or in a
+// different request's dialog. It returns the option it sent.
+func AnswerClaudePermission(target ClaudePermissionTarget, want, answer string, verifyTimeout time.Duration) (ClaudePermissionOption, error) {
+	raw, err := target.CapturePaneFresh()
+	if err != nil {
+		return ClaudePermissionOption{}, fmt.Errorf("capture Claude pane: %w", err)
+	}
+	p := DetectClaudePermissionPrompt(raw)
+	if p == nil || p.Fingerprint != want {
+		return ClaudePermissionOption{}, fmt.Errorf("Claude permission dialog changed before the key could be sent")
+	}
+	opt, err := p.Option(answer)
+	if err != nil {
+		return opt, err
+	}
+	if err := target.SendNamedKey(strconv.Itoa(opt.Number)); err != nil {
+		return opt, fmt.Errorf("send Claude permission key: %w", err)
+	}
+
+	deadline := time.Now().Add(verifyTimeout)
+	for {
+		if raw, err := target.CapturePaneFresh(); err == nil {
+			if cur := DetectClaudePermissionPrompt(raw); cur == nil || cur.Fingerprint != want {
+				return opt, nil
+			}
+		}
+		if !time.Now().Before(deadline) {
+			return opt, fmt.Errorf("key %d was sent, but the Claude permission dialog did not clear within %s", opt.Number, verifyTimeout)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
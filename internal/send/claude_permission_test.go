@@ -0,0 +1,117 @@
+package send
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const claudeBashPermissionPrompt = `⏺ Bash(npm test)
+  ⎿  Running…
+
+ Bash command
+
+   npm test
+   Run the unit tests
+
+ Do you want to proceed?
+ ❯ 1. Yes
+   2. Yes, and don't ask again for npm test commands in /home/u/app
+   3. No, and tell Claude what to do differently (esc)
+`
+
+const claudeEditPermissionPrompt = `╭──────────────────────────────────────────────╮
+│ Edit file                                    │
+│ ╭──────────────────────────────────────────╮ │
+│ │ internal/app/main.go                     │ │
+│ │ - old                                    │ │
+│ │ + new                                    │ │
+│ ╰──────────────────────────────────────────╯ │
+│ Do you want to make this edit to main.go?    │
+│ ❯ 1. Yes                                     │
+│   2. Yes, allow all edits during this session │
+│   3. No, and tell Claude what to do differently (esc) │
+╰──────────────────────────────────────────────╯
+`
+
+func TestDetectClaudePermissionPrompt(t *testing.T) {
+	p := DetectClaudePermissionPrompt(claudeBashPermissionPrompt)
+	if p == nil {
+		t.Fatal("bash dialog not detected")
+	}
+	if p.Kind != ClaudePermissionBash || p.Request != "npm test\nRun the unit tests" || p.Command != "npm test" || len(p.Options) != 3 {
+		t.Errorf("bash prompt = %+v", p)
+	}
+	for answer, want := range map[string]int{ClaudeAnswerAllow: 1, ClaudeAnswerAllowAlways: 2, ClaudeAnswerDeny: 3} {
+		if o, err := p.Option(answer); err != nil || o.Number != want {
+			t.Errorf("Option(%s) = %+v, %v; want %d", answer, o, err, want)
+		}
+	}
+
+	e := DetectClaudePermissionPrompt(claudeEditPermissionPrompt)
+	if e == nil || e.Kind != ClaudePermissionEdit || e.Request != "main.go" || e.Command != "main.go" {
+		t.Fatalf("edit prompt = %+v", e)
+	}
+	if o, err := e.Option(ClaudeAnswerAllowAlways); err != nil || o.Number != 2 {
+		t.Errorf("edit allow_always = %+v, %v", o, err)
+	}
+
+	// Answered dialog scrolled up behind Claude working again.
+	working := claudeBashPermissionPrompt + "\n✶ Testing… (3s · ↓ 120 tokens · esc to interrupt)\n"
+	if got := DetectClaudePermissionPrompt(working); got != nil {
+		t.Errorf("history dialog detected: %+v", got)
+	}
+	// No selection cursor: not a live menu.
+	if got := DetectClaudePermissionPrompt(strings.Replace(claudeBashPermissionPrompt, "❯ 1.", "  1.", 1)); got != nil {
+		t.Errorf("unselected menu detected: %+v", got)
+	}
+}
+
+func TestDetectClaudePermissionPrompt_MultiLineCommandKeepsEveryLine(t *testing.T) {
+	dialog := strings.Replace(claudeBashPermissionPrompt, "   npm test\n", "   ls\n   rm -rf ~\n", 1)
+	p := DetectClaudePermissionPrompt(dialog)
+	if p == nil {
+		t.Fatal("multi-line bash dialog not detected")
+	}
+	if want := "ls\nrm -rf ~\nRun the unit tests"; p.Request != want || p.Command != want {
+		t.Errorf("Request = %q, Command = %q; want both %q", p.Request, p.Command, want)
+	}
+}
+
+func TestDetectClaudePermissionPrompt_CommandWithoutDescription(t *testing.T) {
+	// The pending call spells out both lines: both are the command.
+	dialog := strings.Replace(claudeBashPermissionPrompt, "   npm test\n", "   npm test\n   npm publish\n", 1)
+	dialog = strings.Replace(dialog, "Bash(npm test)", "Bash(npm test npm publish)", 1)
+	if p := DetectClaudePermissionPrompt(dialog); p == nil || p.Command != "npm test\nnpm publish" {
+		t.Errorf("multi-line call: %+v", p)
+	}
+
+	// A finished call above the dialog says nothing about this request.
+	finished := strings.Replace(claudeBashPermissionPrompt, "⎿  Running…", "⎿  ok", 1)
+	if p := DetectClaudePermissionPrompt(finished); p == nil || p.Command != p.Request {
+		t.Errorf("finished call: %+v", p)
+	}
+	// So does a dialog with no call above it.
+	if p := DetectClaudePermissionPrompt(claudeBashPermissionPrompt[strings.Index(claudeBashPermissionPrompt, "\n Bash command"):]); p == nil || p.Command != p.Request {
+		t.Errorf("no call: %+v", p)
+	}
+}
+
+func TestAnswerClaudePermission_FailsClosedOnChangedDialog(t *testing.T) {
+	p := DetectClaudePermissionPrompt(claudeBashPermissionPrompt)
+	other := strings.Replace(claudeBashPermissionPrompt, "npm test\n", "rm -rf build\n", 1)
+
+	target := &fakeCodexApprovalTarget{captures: []string{other}}
+	if _, err := AnswerClaudePermission(target, p.Fingerprint, ClaudeAnswerAllow, 10*time.Millisecond); err == nil {
+		t.Fatal("changed dialog: want error")
+	}
+	if len(target.sent) != 0 {
+		t.Fatalf("sent %v into a changed dialog", target.sent)
+	}
+
+	target = &fakeCodexApprovalTarget{captures: []string{claudeBashPermissionPrompt, "⏺ Bash(npm test)\n  ⎿  ok\n\n> \n"}}
+	o, err := AnswerClaudePermission(target, p.Fingerprint, ClaudeAnswerDeny, time.Second)
+	if err != nil || o.Number != 3 || len(target.sent) != 1 || target.sent[0] != "3" {
+		t.Fatalf("deny = %+v, %v, sent %v", o, err, target.sent)
+	}
+}
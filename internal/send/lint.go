@@ -0,0 +1,125 @@
+package send
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// DefaultLintMinChars is the shortest prompt LintPrompt accepts by default.
+// Shorter prompts are usually a stray keystroke or a truncated argument.
+const DefaultLintMinChars = 3
+
+// DefaultLintPlaceholders are the placeholder markers LintPrompt flags by
+// default: text left over from a template or a half-written prompt.
+var DefaultLintPlaceholders = []string{"TODO", "FIXME", "TBD", "XXX", "lorem ipsum"}
+
+// LintOptions configures LintPrompt. Zero values select the defaults; a
+// negative MinChars or MaxBytes disables that check.
+type LintOptions struct {
+	MinChars     int
+	MaxBytes     int      // default: tmux.SendChunkSize (larger prompts are chunked)
+	Placeholders []string // nil: DefaultLintPlaceholders; empty: no placeholder check
+}
+
+// LintIssue is one problem found in a prompt.
+type LintIssue struct {
+	Check   string `json:"check"` // "empty", "short", "placeholder" or "size"
+	Message string `json:"message"`
+}
+
+// PromptSummary describes the text that will actually be typed into the
+// session.
+type PromptSummary struct {
+	Bytes     int    `json:"bytes"`
+	Chars     int    `json:"chars"`
+	Lines     int    `json:"lines"`
+	Chunks    int    `json:"chunks"` // tmux send-keys calls needed
+	FirstLine string `json:"first_line"`
+}
+
+// SummarizePrompt reports the size and shape of message as it will be sent.
+func SummarizePrompt(message string) PromptSummary {
+	s := PromptSummary{
+		Bytes: len(message),
+		Chars: utf8.RuneCountInString(message),
+	}
+	if message == "" {
+		return s
+	}
+	s.Lines = strings.Count(message, "\n") + 1
+	s.Chunks = tmux.SendChunkCount(message)
+	first, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	if utf8.RuneCountInString(first) > 60 {
+		first = string([]rune(first)[:57]) + "..."
+	}
+	s.FirstLine = first
+	return s
+}
+
+// String renders the summary on one line, e.g.
+// `412 chars, 9 lines, 1 chunk: "Refactor the parser..."`.
+func (s PromptSummary) String() string {
+	plural := func(n int, word string) string {
+		if n == 1 {
+			return fmt.Sprintf("%d %s", n, word)
+		}
+		return fmt.Sprintf("%d %ss", n, word)
+	}
+	return fmt.Sprintf("%s, %s, %s: %q",
+		plural(s.Chars, "char"), plural(s.Lines, "line"), plural(s.Chunks, "chunk"), s.FirstLine)
+}
+
+// LintPrompt runs the pre-send checks on message and returns the issues
+// found, or nil when the prompt looks fine.
+func LintPrompt(message string, opts LintOptions) []LintIssue {
+	trimmed := strings.TrimSpace(message)
+	if trimmed == "" {
+		return []LintIssue{{Check: "empty", Message: "prompt is empty"}}
+	}
+
+	var issues []LintIssue
+	minChars := opts.MinChars
+	if minChars == 0 {
+		minChars = DefaultLintMinChars
+	}
+	if n := utf8.RuneCountInString(trimmed); minChars > 0 && n < minChars {
+		issues = append(issues, LintIssue{
+			Check:   "short",
+			Message: fmt.Sprintf("prompt is only %d chars (minimum %d)", n, minChars),
+		})
+	}
+
+	placeholders := opts.Placeholders
+	if placeholders == nil {
+		placeholders = DefaultLintPlaceholders
+	}
+	for _, p := range placeholders {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+		re := regexp.MustCompile(`(?i)(^|\W)` + regexp.QuoteMeta(p) + `($|\W)`)
+		if re.MatchString(message) {
+			issues = append(issues, LintIssue{
+				Check:   "placeholder",
+				Message: fmt.Sprintf("prompt contains placeholder text %q", p),
+			})
+		}
+	}
+
+	maxBytes := opts.MaxBytes
+	if maxBytes == 0 {
+		maxBytes = tmux.SendChunkSize
+	}
+	if maxBytes > 0 && len(message) > maxBytes {
+		issues = append(issues, LintIssue{
+			Check: "size",
+			Message: fmt.Sprintf("prompt is %d bytes (limit %d); it will be pasted in %d chunks",
+				len(message), maxBytes, SummarizePrompt(message).Chunks),
+		})
+	}
+	return issues
+}
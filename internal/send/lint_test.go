@@ -0,0 +1,58 @@
+package send
+
+import (
+	"strings"
+	"testing"
+)
+
+func lintChecks(issues []LintIssue) string {
+	var checks []string
+	for _, i := range issues {
+		checks = append(checks, i.Check)
+	}
+	return strings.Join(checks, ",")
+}
+
+func TestLintPrompt(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		opts    LintOptions
+		want    string
+	}{
+		{"fine", "Refactor the parser for better error messages", LintOptions{}, ""},
+		{"empty", "  \n ", LintOptions{}, "empty"},
+		{"short", "ok", LintOptions{}, "short"},
+		{"short disabled", "ok", LintOptions{MinChars: -1}, ""},
+		{"placeholder", "Fix the bug in TODO.", LintOptions{}, "placeholder"},
+		{"placeholder case-insensitive", "ship it xxx", LintOptions{}, "placeholder"},
+		{"placeholder whole word only", "Update todos.go and the xxxl sizes", LintOptions{}, ""},
+		{"custom placeholders", "Deploy to <env>", LintOptions{Placeholders: []string{"<env>"}}, "placeholder"},
+		{"placeholders disabled", "TODO", LintOptions{Placeholders: []string{}}, ""},
+		{"size", strings.Repeat("word ", 1000), LintOptions{}, "size"},
+		{"size custom limit", "a longer prompt", LintOptions{MaxBytes: 10}, "size"},
+		{"size disabled", strings.Repeat("word ", 1000), LintOptions{MaxBytes: -1}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lintChecks(LintPrompt(tt.message, tt.opts)); got != tt.want {
+				t.Errorf("LintPrompt(%q) checks = %q, want %q", tt.message, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarizePrompt(t *testing.T) {
+	s := SummarizePrompt("Fix the login flow\nthen run the tests")
+	if s.Lines != 2 || s.Chunks != 1 || s.FirstLine != "Fix the login flow" {
+		t.Errorf("summary = %+v", s)
+	}
+	if got := s.String(); got != `37 chars, 2 lines, 1 chunk: "Fix the login flow"` {
+		t.Errorf("String() = %q", got)
+	}
+
+	long := strings.Repeat(strings.Repeat("x", 99)+"\n", 100)
+	if s := SummarizePrompt(long); s.Chunks < 3 {
+		t.Errorf("10KB prompt chunks = %d, want >= 3", s.Chunks)
+	}
+}
@@ -0,0 +1,31 @@
+package session
+
+import (
+	"strings"
+)
+
+// Aider adapter.
+//
+// Aider (aider.chat) is a terminal pair-programming chat. It keeps its
+// conversation in .aider.chat.history.md in the project directory, so a
+// restart resumes it with --restore-chat-history. Status comes from the pane
+// patterns in internal/tmux (the "Waiting for <model>" spinner line and the
+// "> " prompt).
+
+// buildAiderCommand builds the launch command for Aider. continuePrev adds
+// --restore-chat-history unless the command already sets it, so restart
+// picks the conversation back up.
+func (i *Instance) buildAiderCommand(baseCommand string, continuePrev bool) string {
+	if i.Tool != "aider" {
+		return baseCommand
+	}
+
+	cmd := strings.TrimSpace(baseCommand)
+	if cmd == "" {
+		cmd = "aider"
+	}
+	if continuePrev && !strings.Contains(cmd, "restore-chat-history") {
+		cmd += " --restore-chat-history"
+	}
+	return i.buildEnvSourceCommand() + cmd
+}
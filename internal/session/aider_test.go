@@ -0,0 +1,43 @@
+package session
+
+import "testing"
+
+func TestBuildAiderCommand(t *testing.T) {
+	oldCache := userConfigCache
+	defer func() { userConfigCache = oldCache }()
+	userConfigCache = &UserConfig{}
+
+	inst := &Instance{Tool: "aider"}
+	tests := []struct {
+		name         string
+		base         string
+		continuePrev bool
+		want         string
+	}{
+		{"fresh start", "aider", false, "aider"},
+		{"empty command", "", false, "aider"},
+		{"restart restores history", "aider --model sonnet", true, "aider --model sonnet --restore-chat-history"},
+		{"restart keeps explicit flag", "aider --no-restore-chat-history", true, "aider --no-restore-chat-history"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inst.buildAiderCommand(tt.base, tt.continuePrev); !endsWith(got, tt.want) {
+				t.Errorf("buildAiderCommand(%q, %v) = %q, want suffix %q", tt.base, tt.continuePrev, got, tt.want)
+			}
+		})
+	}
+
+	other := &Instance{Tool: "shell"}
+	if got := other.buildAiderCommand("aider", true); got != "aider" {
+		t.Errorf("buildAiderCommand with wrong tool = %q, want %q", got, "aider")
+	}
+}
+
+func TestAider_DetectedAndRestartable(t *testing.T) {
+	if got := Init(nil).Match("/usr/local/bin/aider --model sonnet"); got != "aider" {
+		t.Errorf("Match(aider) = %q, want aider", got)
+	}
+	if !NewInstanceWithTool("a", "/tmp", "aider").CanRestart() {
+		t.Error("aider sessions should be restartable")
+	}
+}
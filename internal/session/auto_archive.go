@@ -0,0 +1,76 @@
+package session
+
+import (
+	"sort"
+	"time"
+)
+
+// ArchiveSettings configures automatic archiving: sessions with no activity
+// for auto_archive_after_days are stopped and moved to the archive, the same
+// as archiving them by hand. Archived sessions keep their metadata and can be
+// restored with `agent-deck archive restore`.
+//
+//	[archive]
+//	auto_archive_after_days = 14
+type ArchiveSettings struct {
+	// AutoArchiveAfterDays is the idle threshold in days (default: 0, off).
+	AutoArchiveAfterDays int `toml:"auto_archive_after_days,omitempty"`
+}
+
+// AutoArchiveAfter returns the idle threshold, or 0 when auto-archive is off.
+func (s ArchiveSettings) AutoArchiveAfter() time.Duration {
+	if s.AutoArchiveAfterDays <= 0 {
+		return 0
+	}
+	return time.Duration(s.AutoArchiveAfterDays) * 24 * time.Hour
+}
+
+// GetArchiveSettings returns archive settings from config.
+func GetArchiveSettings() ArchiveSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return ArchiveSettings{}
+	}
+	return config.Archive
+}
+
+// AutoArchiveCandidates returns the sessions that have been idle for at least
+// after, longest-idle first. Already-archived, pinned and conductor sessions
+// are never candidates, nor are sessions that are running, starting or
+// queued. after <= 0 yields nothing.
+func AutoArchiveCandidates(instances []*Instance, now time.Time, after time.Duration) []*Instance {
+	if after <= 0 {
+		return nil
+	}
+	var out []*Instance
+	for _, inst := range instances {
+		if inst == nil || inst.IsArchived() || inst.Pin != PinNone || inst.IsConductor {
+			continue
+		}
+		switch inst.GetStatusThreadSafe() {
+		case StatusRunning, StatusStarting, StatusQueued:
+			continue
+		}
+		if now.Sub(LastActiveAt(inst)) >= after {
+			out = append(out, inst)
+		}
+	}
+	sort.SliceStable(out, func(a, b int) bool {
+		return LastActiveAt(out[a]).Before(LastActiveAt(out[b]))
+	})
+	return out
+}
+
+// LastActiveAt returns the latest sign of use for a session: confirmed pane
+// activity, the last attach, or its creation. Unlike DisplayLastActivityTime
+// it never lets an older observed-activity stamp hide a newer attach.
+func LastActiveAt(inst *Instance) time.Time {
+	last := inst.CreatedAt
+	if inst.LastAccessedAt.After(last) {
+		last = inst.LastAccessedAt
+	}
+	if ts, ok := inst.LastObservedActivity(); ok && ts.After(last) {
+		last = ts
+	}
+	return last
+}
@@ -0,0 +1,47 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAutoArchiveCandidates(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	old := now.Add(-20 * 24 * time.Hour)
+	mk := func(id string, status Status, lastAccess time.Time) *Instance {
+		return &Instance{ID: id, Status: status, CreatedAt: old.Add(-time.Hour), LastAccessedAt: lastAccess}
+	}
+
+	stale := mk("stale", StatusStopped, old)
+	staler := mk("staler", StatusIdle, old.Add(-24*time.Hour))
+	recent := mk("recent", StatusStopped, now.Add(-time.Hour))
+	running := mk("running", StatusRunning, old)
+	pinned := mk("pinned", StatusStopped, old)
+	pinned.Pin = PinTop
+	conductor := mk("conductor", StatusIdle, old)
+	conductor.IsConductor = true
+	archived := mk("archived", StatusStopped, old)
+	archived.ArchivedAt = now.Add(-time.Hour)
+
+	all := []*Instance{stale, staler, recent, running, pinned, conductor, archived}
+	got := AutoArchiveCandidates(all, now, 14*24*time.Hour)
+	if len(got) != 2 || got[0] != staler || got[1] != stale {
+		ids := make([]string, len(got))
+		for i, inst := range got {
+			ids[i] = inst.ID
+		}
+		t.Fatalf("candidates = %v, want [staler stale]", ids)
+	}
+	if got := AutoArchiveCandidates(all, now, 0); got != nil {
+		t.Errorf("threshold 0 should disable auto-archive, got %d candidates", len(got))
+	}
+}
+
+func TestArchiveSettings_AutoArchiveAfter(t *testing.T) {
+	if d := (ArchiveSettings{}).AutoArchiveAfter(); d != 0 {
+		t.Errorf("default = %v, want off", d)
+	}
+	if d := (ArchiveSettings{AutoArchiveAfterDays: 3}).AutoArchiveAfter(); d != 72*time.Hour {
+		t.Errorf("3 days = %v", d)
+	}
+}
@@ -0,0 +1,106 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// AutoBranchRecord maps a branch named by `launch -w auto` back to the
+// prompt it was derived from.
+type AutoBranchRecord struct {
+	Branch    string    `json:"branch"`
+	RepoRoot  string    `json:"repo_root"`
+	Prompt    string    `json:"prompt"`
+	SessionID string    `json:"session_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// autoBranchesMu serializes read-modify-write cycles on the mapping file.
+var autoBranchesMu sync.Mutex
+
+// AutoBranchesPath returns the file holding prompt-named branch records.
+func AutoBranchesPath() (string, error) {
+	return dataPath("auto-branches.json", "auto-branches.json")
+}
+
+// ReadAutoBranches returns every recorded prompt-named branch, oldest first.
+// A missing file yields an empty slice and no error.
+func ReadAutoBranches() ([]AutoBranchRecord, error) {
+	path, err := AutoBranchesPath()
+	if err != nil {
+		return nil, err
+	}
+	autoBranchesMu.Lock()
+	defer autoBranchesMu.Unlock()
+	return readAutoBranchesFile(path)
+}
+
+func readAutoBranchesFile(path string) ([]AutoBranchRecord, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var records []AutoBranchRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return records, nil
+}
+
+// RecordAutoBranch stores rec, replacing an earlier record for the same
+// repository and branch.
+func RecordAutoBranch(rec AutoBranchRecord) error {
+	if rec.Branch == "" {
+		return fmt.Errorf("auto branch record has no branch")
+	}
+	if rec.CreatedAt.IsZero() {
+		rec.CreatedAt = time.Now()
+	}
+	path, err := AutoBranchesPath()
+	if err != nil {
+		return err
+	}
+	autoBranchesMu.Lock()
+	defer autoBranchesMu.Unlock()
+	records, err := readAutoBranchesFile(path)
+	if err != nil {
+		return err
+	}
+	kept := records[:0]
+	for _, r := range records {
+		if r.Branch != rec.Branch || r.RepoRoot != rec.RepoRoot {
+			kept = append(kept, r)
+		}
+	}
+	kept = append(kept, rec)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(kept, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeJSONFileAtomic(path, data, 0o600)
+}
+
+// FindAutoBranch returns the record for branch in repoRoot, or nil.
+func FindAutoBranch(repoRoot, branch string) (*AutoBranchRecord, error) {
+	records, err := ReadAutoBranches()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].Branch == branch && records[i].RepoRoot == repoRoot {
+			return &records[i], nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,37 @@
+package session
+
+import (
+	"os"
+	"testing"
+)
+
+func TestRecordAutoBranch_ReplacesSameBranch(t *testing.T) {
+	path, err := AutoBranchesPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = os.Remove(path)
+	t.Cleanup(func() { _ = os.Remove(path) })
+
+	for _, rec := range []AutoBranchRecord{
+		{Branch: "agent/add-dark-mode", RepoRoot: "/repo", Prompt: "add dark mode", SessionID: "s1"},
+		{Branch: "agent/add-dark-mode", RepoRoot: "/other", Prompt: "add dark mode", SessionID: "s2"},
+		{Branch: "agent/add-dark-mode", RepoRoot: "/repo", Prompt: "add dark mode toggle", SessionID: "s3"},
+	} {
+		if err := RecordAutoBranch(rec); err != nil {
+			t.Fatalf("RecordAutoBranch(%+v): %v", rec, err)
+		}
+	}
+
+	records, err := ReadAutoBranches()
+	if err != nil || len(records) != 2 {
+		t.Fatalf("ReadAutoBranches = %+v, %v; want 2 records", records, err)
+	}
+	got, err := FindAutoBranch("/repo", "agent/add-dark-mode")
+	if err != nil || got == nil || got.SessionID != "s3" || got.Prompt != "add dark mode toggle" || got.CreatedAt.IsZero() {
+		t.Fatalf("FindAutoBranch = %+v, %v", got, err)
+	}
+	if got, _ := FindAutoBranch("/repo", "agent/missing"); got != nil {
+		t.Errorf("FindAutoBranch(missing) = %+v", got)
+	}
+}
@@ -0,0 +1,403 @@
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/send"
+)
+
+// Auto-responder for Claude permission dialogs. Rules in config.toml answer
+// dialogs nobody wants to click through by hand — and refuse the ones nobody
+// should ever approve:
+//
+//	[auto_respond]
+//	enabled = true
+//
+//	[[auto_respond.rule]]
+//	tool = "bash"
+//	match = "npm test*"
+//	action = "allow"
+//
+//	[[auto_respond.rule]]
+//	tool = "bash"
+//	match = "*rm -rf*"
+//	action = "deny"
+//
+// A dialog is found with the status PromptDetector plus
+// send.DetectClaudePermissionPrompt, and answered with one digit keypress
+// after re-checking the dialog has not changed. A matching deny rule always
+// wins over allow rules; a dialog no rule matches is left for the human.
+// Every answer, and every failed attempt, is appended to
+// logs/auto-respond.jsonl. Like [events] hooks, the TUI runs the check from
+// its status loop and the notify-daemon only while no TUI runs.
+
+const (
+	// autoRespondVerifyTimeout bounds how long an answer waits for the
+	// dialog to clear.
+	autoRespondVerifyTimeout = 3 * time.Second
+	// autoRespondRecheck is how often a waiting session whose dialog was
+	// already evaluated is captured again, in case a new one replaced it
+	// without the session leaving waiting.
+	autoRespondRecheck = 5 * time.Second
+)
+
+// AutoRespondSettings configures [auto_respond].
+type AutoRespondSettings struct {
+	// Enabled turns the auto-responder on. Default: false.
+	Enabled bool `toml:"enabled,omitempty"`
+
+	// Rules are the policy; see AutoRespondRule.
+	Rules []AutoRespondRule `toml:"rule,omitempty"`
+}
+
+// AutoRespondRule answers the permission dialogs it matches.
+type AutoRespondRule struct {
+	// Tool is the kind of request: "bash", "edit", "write", "read",
+	// "fetch", "mcp" or "other". Empty or "*" matches every kind.
+	Tool string `toml:"tool,omitempty"`
+
+	// Match is a glob over the request — the command for bash, the file
+	// for edit/write/read, the URL for fetch, the tool call for mcp — where
+	// "*" matches any text. A "re:" prefix makes it a Go regular expression
+	// instead. Empty matches every request.
+	Match string `toml:"match,omitempty"`
+
+	// Action is "allow", "allow_always" (the dialog's "don't ask again"
+	// choice) or "deny".
+	Action string `toml:"action"`
+
+	// Group limits the rule to sessions in this group and its subgroups.
+	Group string `toml:"group,omitempty"`
+}
+
+// GetAutoRespondSettings returns the [auto_respond] settings from config.toml.
+func GetAutoRespondSettings() AutoRespondSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return AutoRespondSettings{}
+	}
+	return config.AutoRespond
+}
+
+var autoRespondKinds = map[string]bool{
+	send.ClaudePermissionBash:  true,
+	send.ClaudePermissionEdit:  true,
+	send.ClaudePermissionWrite: true,
+	send.ClaudePermissionRead:  true,
+	send.ClaudePermissionFetch: true,
+	send.ClaudePermissionMCP:   true,
+	send.ClaudePermissionOther: true,
+}
+
+// shellSegmentSeparator splits a command line at its control operators.
+var shellSegmentSeparator = regexp.MustCompile(`&&|\|\||[;|\n]`)
+
+// shellControlChars mark a command an allow rule must not answer: chained,
+// piped, redirected or substituted commands do more than their prefix says.
+var shellControlChars = regexp.MustCompile("&&|\\|\\||[;|&<>`\\n]|\\$\\(")
+
+// compile returns the rule's request matcher.
+func (r AutoRespondRule) compile() (*regexp.Regexp, error) {
+	switch r.Action {
+	case send.ClaudeAnswerAllow, send.ClaudeAnswerAllowAlways, send.ClaudeAnswerDeny:
+	default:
+		return nil, fmt.Errorf("action %q: want allow, allow_always or deny", r.Action)
+	}
+	if r.Tool != "" && r.Tool != "*" && !autoRespondKinds[r.Tool] {
+		return nil, fmt.Errorf("tool %q: want bash, edit, write, read, fetch, mcp, other or *", r.Tool)
+	}
+	if expr, ok := strings.CutPrefix(r.Match, "re:"); ok {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("match %q: %w", r.Match, err)
+		}
+		return re, nil
+	}
+	if r.Match == "" {
+		return regexp.MustCompile(`(?s).*`), nil
+	}
+	parts := strings.Split(r.Match, "*")
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+	return regexp.MustCompile(`(?s)^` + strings.Join(parts, ".*") + `$`), nil
+}
+
+// matches reports whether the rule applies to a request of kind from a
+// session in groupPath. Allow rules are matched against command, the request
+// without Claude's description, and never match a command that spans several
+// lines or that chains, pipes or redirects. Deny rules are matched against
+// the whole request and also match any single line of it and, for bash, any
+// single command of a chain, so "rm -rf*" catches "cd build && rm -rf .".
+func (r AutoRespondRule) matches(re *regexp.Regexp, kind, command, request, groupPath string) bool {
+	if r.Tool != "" && r.Tool != "*" && r.Tool != kind {
+		return false
+	}
+	if r.Group != "" && groupPath != r.Group && !strings.HasPrefix(groupPath, r.Group+"/") {
+		return false
+	}
+	if r.Action != send.ClaudeAnswerDeny {
+		if strings.Contains(command, "\n") || (kind == send.ClaudePermissionBash && shellControlChars.MatchString(command)) {
+			return false
+		}
+		return re.MatchString(command)
+	}
+	if re.MatchString(request) {
+		return true
+	}
+	for _, line := range strings.Split(request, "\n") {
+		if line = strings.TrimSpace(line); line != "" && re.MatchString(line) {
+			return true
+		}
+	}
+	if kind == send.ClaudePermissionBash {
+		for _, seg := range shellSegmentSeparator.Split(request, -1) {
+			if seg = strings.TrimSpace(seg); seg != "" && re.MatchString(seg) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Validate reports the first invalid rule.
+func (s AutoRespondSettings) Validate() error {
+	for i, r := range s.Rules {
+		if _, err := r.compile(); err != nil {
+			return fmt.Errorf("auto_respond.rule[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Decide returns the index of the rule that answers a request of kind from
+// a session in groupPath, or -1 when the human has to answer. command is the
+// request without Claude's description of it (see
+// send.ClaudePermissionPrompt). A matching deny rule wins over every allow
+// rule; otherwise the first match wins. Invalid rules are skipped.
+func (s AutoRespondSettings) Decide(kind, command, request, groupPath string) int {
+	allow := -1
+	for i, r := range s.Rules {
+		re, err := r.compile()
+		if err != nil {
+			if _, warned := invalidEventHookWarned.LoadOrStore("auto_respond\x00"+err.Error(), true); !warned {
+				eventHookLog.Warn("auto_respond_invalid_rule", slog.Int("rule", i), slog.String("error", err.Error()))
+			}
+			continue
+		}
+		if !r.matches(re, kind, command, request, groupPath) {
+			continue
+		}
+		if r.Action == send.ClaudeAnswerDeny {
+			return i
+		}
+		if allow < 0 {
+			allow = i
+		}
+	}
+	return allow
+}
+
+// AutoResponse is one line of the auto-respond audit log.
+type AutoResponse struct {
+	Time         time.Time `json:"time"`
+	Profile      string    `json:"profile"`
+	SessionID    string    `json:"session_id"`
+	SessionTitle string    `json:"session_title"`
+	Kind         string    `json:"kind"`
+	Request      string    `json:"request"`
+	Rule         int       `json:"rule"`
+	Match        string    `json:"match,omitempty"`
+	Action       string    `json:"action"`
+	Option       string    `json:"option,omitempty"`
+	Error        string    `json:"error,omitempty"`
+}
+
+var autoRespondLogMu sync.Mutex
+
+// AutoRespondLogPath returns ~/.agent-deck/logs/auto-respond.jsonl.
+func AutoRespondLogPath() string {
+	path, err := logDataPath("auto-respond.jsonl")
+	if err != nil {
+		return tempAgentDeckPath("logs", "auto-respond.jsonl")
+	}
+	return path
+}
+
+// appendAutoResponse writes one audit line.
+func appendAutoResponse(r AutoResponse) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	path := AutoRespondLogPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	autoRespondLogMu.Lock()
+	defer autoRespondLogMu.Unlock()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644) //nolint:gosec // log file
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// ReadAutoResponses returns the newest limit audit entries, oldest first
+// (limit <= 0 returns all). A missing log is empty.
+func ReadAutoResponses(limit int) ([]AutoResponse, error) {
+	f, err := os.Open(AutoRespondLogPath())
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	var out []AutoResponse
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r AutoResponse
+		if json.Unmarshal(scanner.Bytes(), &r) == nil {
+			out = append(out, r)
+		}
+	}
+	if limit > 0 && len(out) > limit {
+		out = out[len(out)-limit:]
+	}
+	return out, scanner.Err()
+}
+
+// autoRespondTarget returns the pane an instance's dialog is read from and
+// answered in. Swapped in tests.
+var autoRespondTarget = func(inst *Instance) send.ClaudePermissionTarget {
+	if !inst.Exists() {
+		return nil
+	}
+	if tmuxSess := inst.GetTmuxSession(); tmuxSess != nil {
+		return tmuxSess
+	}
+	return nil
+}
+
+var (
+	autoRespondMu sync.Mutex
+	// autoRespondSeen holds, per profile\x00session, the last dialog
+	// evaluated, so one dialog is answered (or logged as failed) once. It is
+	// cleared when the session stops waiting.
+	autoRespondSeen = map[string]autoRespondCheck{}
+	// autoRespondBusy marks sessions with a check in flight.
+	autoRespondBusy = map[string]bool{}
+)
+
+type autoRespondCheck struct {
+	fingerprint string
+	at          time.Time
+}
+
+// CheckAutoResponses answers the permission dialogs of waiting Claude
+// sessions that an [auto_respond] rule matches. Call it once per status
+// sweep; captures and answers run in the background. It returns how many
+// sessions were checked.
+func CheckAutoResponses(profile string, instances []*Instance, now time.Time) int {
+	settings := GetAutoRespondSettings()
+	if !settings.Enabled || len(settings.Rules) == 0 {
+		return 0
+	}
+	prefix := profileOrDefault(profile) + "\x00"
+	checked := 0
+	autoRespondMu.Lock()
+	defer autoRespondMu.Unlock()
+	for _, inst := range instances {
+		key := prefix + inst.ID
+		if !IsClaudeCompatible(inst.Tool) || inst.GetStatusThreadSafe() != StatusWaiting {
+			delete(autoRespondSeen, key)
+			continue
+		}
+		if autoRespondBusy[key] {
+			continue
+		}
+		if c, ok := autoRespondSeen[key]; ok && now.Sub(c.at) < autoRespondRecheck {
+			continue
+		}
+		autoRespondBusy[key] = true
+		checked++
+		go func(inst *Instance, key string) {
+			defer func() {
+				autoRespondMu.Lock()
+				delete(autoRespondBusy, key)
+				autoRespondMu.Unlock()
+			}()
+			autoRespond(profile, inst, settings, key, now)
+		}(inst, key)
+	}
+	return checked
+}
+
+// autoRespond evaluates one waiting session's pane and answers its dialog
+// when a rule matches.
+func autoRespond(profile string, inst *Instance, settings AutoRespondSettings, key string, now time.Time) {
+	target := autoRespondTarget(inst)
+	if target == nil {
+		return
+	}
+	raw, err := target.CapturePaneFresh()
+	if err != nil {
+		return
+	}
+	prompt := send.DetectClaudePermissionPrompt(raw)
+	if prompt == nil {
+		return
+	}
+	autoRespondMu.Lock()
+	seen := autoRespondSeen[key].fingerprint == prompt.Fingerprint
+	autoRespondSeen[key] = autoRespondCheck{prompt.Fingerprint, now}
+	autoRespondMu.Unlock()
+	if seen {
+		return
+	}
+
+	idx := settings.Decide(prompt.Kind, prompt.Command, prompt.Request, inst.GroupPath)
+	if idx < 0 {
+		return
+	}
+	rule := settings.Rules[idx]
+	entry := AutoResponse{
+		Time:         now,
+		Profile:      profileOrDefault(profile),
+		SessionID:    inst.ID,
+		SessionTitle: inst.Title,
+		Kind:         prompt.Kind,
+		Request:      prompt.Request,
+		Rule:         idx,
+		Match:        rule.Match,
+		Action:       rule.Action,
+	}
+	opt, err := send.AnswerClaudePermission(target, prompt.Fingerprint, rule.Action, autoRespondVerifyTimeout)
+	if opt.Label != "" {
+		entry.Option = fmt.Sprintf("%d. %s", opt.Number, opt.Label)
+	}
+	if err != nil {
+		entry.Error = err.Error()
+		eventHookLog.Warn("auto_respond_failed", slog.String("session", inst.ID), slog.String("request", prompt.Request), slog.String("error", err.Error()))
+	} else {
+		eventHookLog.Info("auto_respond", slog.String("session", inst.ID), slog.String("kind", prompt.Kind), slog.String("request", prompt.Request), slog.String("action", rule.Action))
+	}
+	if err := appendAutoResponse(entry); err != nil {
+		eventHookLog.Warn("auto_respond_audit_failed", slog.String("error", err.Error()))
+	}
+}
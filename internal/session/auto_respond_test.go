@@ -0,0 +1,254 @@
+package session
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/send"
+)
+
+func TestAutoRespondSettings_Decide(t *testing.T) {
+	s := AutoRespondSettings{Rules: []AutoRespondRule{
+		{Tool: "bash", Match: "npm test*", Action: "allow"},
+		{Tool: "bash", Match: "rm -rf*", Action: "deny"},
+		{Tool: "edit", Match: "*.md", Action: "allow_always", Group: "docs"},
+		{Tool: "fetch", Match: "re:^https://(docs\\.)?example\\.com/", Action: "allow"},
+		{Tool: "bash", Action: "maybe"},
+	}}
+	if err := s.Validate(); err == nil {
+		t.Error("Validate: want error for action \"maybe\"")
+	}
+	for _, tc := range []struct {
+		kind, request, group string
+		want                 int
+		command              string // "" means the whole request
+	}{
+		{"bash", "npm test", "", 0, ""},
+		{"bash", "npm test -- --watch=false", "", 0, ""},
+		{"bash", "npm test && git push", "", -1, ""},
+		{"bash", "npm test 2>&1 | tail", "", -1, ""},
+		{"bash", "rm -rf build", "", 1, ""},
+		{"bash", "cd build && rm -rf .", "", 1, ""},
+		{"bash", "npm install", "", -1, ""},
+		{"edit", "README.md", "docs/site", 2, ""},
+		{"edit", "README.md", "api", -1, ""},
+		{"fetch", "https://docs.example.com/x", "", 3, ""},
+		{"fetch", "https://evil.test/?https://example.com/", "", -1, ""},
+		{"bash", "npm test\nrm -rf ~", "", 1, ""},
+		{"fetch", "https://docs.example.com/x\nhttps://evil.test/", "", -1, ""},
+		// Allow rules judge the command, not Claude's description of it.
+		{"bash", "npm test\nRun the unit tests", "", 0, "npm test"},
+		{"bash", "npm test && git push\nRun the tests and push", "", -1, "npm test && git push"},
+		{"bash", "npm test\nnpm publish\nRun the tests and publish", "", -1, "npm test\nnpm publish"},
+	} {
+		command := tc.command
+		if command == "" {
+			command = tc.request
+		}
+		if got := s.Decide(tc.kind, command, tc.request, tc.group); got != tc.want {
+			t.Errorf("Decide(%s, %q, %q, %q) = %d, want %d", tc.kind, command, tc.request, tc.group, got, tc.want)
+		}
+	}
+}
+
+type fakePermissionPane struct {
+	mu       sync.Mutex
+	captures []string
+	sent     []string
+}
+
+func (f *fakePermissionPane) CapturePaneFresh() (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c := f.captures[0]
+	if len(f.captures) > 1 {
+		f.captures = f.captures[1:]
+	}
+	return c, nil
+}
+
+func (f *fakePermissionPane) SendNamedKey(key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = append(f.sent, key)
+	return nil
+}
+
+// useFakePermissionPane points auto-respond at pane for the test and clears
+// the package-level dialog state around it, so tests reusing a session ID do
+// not skip each other's dialogs.
+func useFakePermissionPane(t *testing.T, pane *fakePermissionPane) {
+	t.Helper()
+	reset := func() {
+		autoRespondMu.Lock()
+		defer autoRespondMu.Unlock()
+		autoRespondSeen = map[string]autoRespondCheck{}
+		autoRespondBusy = map[string]bool{}
+	}
+	reset()
+	orig := autoRespondTarget
+	autoRespondTarget = func(*Instance) send.ClaudePermissionTarget { return pane }
+	t.Cleanup(func() {
+		// Let the check in flight finish before the next test starts.
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+			autoRespondMu.Lock()
+			busy := len(autoRespondBusy)
+			autoRespondMu.Unlock()
+			if busy == 0 {
+				break
+			}
+		}
+		autoRespondTarget = orig
+		reset()
+	})
+}
+
+func TestCheckAutoResponses_AnswersAndAudits(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("AGENT_DECK_HOME", "")
+	t.Setenv("XDG_DATA_HOME", home+"/xdg-data")
+	writeUserConfigForTest(t, home, `
+[auto_respond]
+enabled = true
+
+[[auto_respond.rule]]
+tool = "bash"
+match = "*rm -rf*"
+action = "deny"
+`)
+
+	dialog := `
+ Bash command
+
+   rm -rf /tmp/cache
+   Clear the cache
+
+ Do you want to proceed?
+ ❯ 1. Yes
+   2. Yes, and don't ask again for rm commands in /home/u/app
+   3. No, and tell Claude what to do differently (esc)
+`
+	pane := &fakePermissionPane{captures: []string{dialog, dialog, "> \n"}}
+	useFakePermissionPane(t, pane)
+
+	inst := &Instance{ID: "s-1", Title: "api", Tool: "claude", Status: StatusWaiting}
+	idle := &Instance{ID: "s-2", Title: "web", Tool: "claude", Status: StatusIdle}
+	if n := CheckAutoResponses("work", []*Instance{inst, idle}, time.Now()); n != 1 {
+		t.Fatalf("checked %d sessions, want 1", n)
+	}
+
+	var log []AutoResponse
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if log, _ = ReadAutoResponses(0); len(log) > 0 {
+			break
+		}
+	}
+	if len(log) != 1 {
+		t.Fatalf("audit log = %+v, want one entry", log)
+	}
+	e := log[0]
+	if e.SessionID != "s-1" || e.Action != "deny" || e.Request != "rm -rf /tmp/cache\nClear the cache" || e.Option != "3. No, and tell Claude what to do differently (esc)" || e.Error != "" {
+		t.Errorf("audit entry = %+v", e)
+	}
+	pane.mu.Lock()
+	defer pane.mu.Unlock()
+	if len(pane.sent) != 1 || pane.sent[0] != "3" {
+		t.Errorf("sent keys = %v, want [3]", pane.sent)
+	}
+}
+
+func TestCheckAutoResponses_AllowsCommandWithDescription(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("AGENT_DECK_HOME", "")
+	t.Setenv("XDG_DATA_HOME", home+"/xdg-data")
+	writeUserConfigForTest(t, home, `
+[auto_respond]
+enabled = true
+
+[[auto_respond.rule]]
+tool = "bash"
+match = "npm test*"
+action = "allow"
+`)
+
+	dialog := `⏺ Bash(npm test)
+  ⎿  Running…
+
+ Bash command
+
+   npm test
+   Run the unit tests
+
+ Do you want to proceed?
+ ❯ 1. Yes
+   2. Yes, and don't ask again for npm test commands in /home/u/app
+   3. No, and tell Claude what to do differently (esc)
+`
+	pane := &fakePermissionPane{captures: []string{dialog, dialog, "> \n"}}
+	useFakePermissionPane(t, pane)
+
+	inst := &Instance{ID: "s-1", Title: "api", Tool: "claude", Status: StatusWaiting}
+	CheckAutoResponses("work", []*Instance{inst}, time.Now())
+
+	var log []AutoResponse
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if log, _ = ReadAutoResponses(0); len(log) > 0 {
+			break
+		}
+	}
+	if len(log) != 1 || log[0].Action != "allow" || log[0].Error != "" {
+		t.Fatalf("audit log = %+v, want one allow", log)
+	}
+	pane.mu.Lock()
+	defer pane.mu.Unlock()
+	if len(pane.sent) != 1 || pane.sent[0] != "1" {
+		t.Errorf("sent keys = %v, want [1]", pane.sent)
+	}
+}
+
+func TestCheckAutoResponses_MultiLineCommandNotAllowed(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("AGENT_DECK_HOME", "")
+	t.Setenv("XDG_DATA_HOME", home+"/xdg-data")
+	writeUserConfigForTest(t, home, `
+[auto_respond]
+enabled = true
+
+[[auto_respond.rule]]
+tool = "bash"
+match = "ls*"
+action = "allow"
+`)
+
+	// Only the first line looks harmless; the dialog must be left alone.
+	dialog := `
+ Bash command
+
+   ls
+   rm -rf ~
+
+ Do you want to proceed?
+ ❯ 1. Yes
+   2. Yes, and don't ask again for ls commands in /home/u/app
+   3. No, and tell Claude what to do differently (esc)
+`
+	pane := &fakePermissionPane{captures: []string{dialog}}
+	useFakePermissionPane(t, pane)
+
+	inst := &Instance{ID: "s-1", Title: "api", Tool: "claude", Status: StatusWaiting}
+	CheckAutoResponses("work", []*Instance{inst}, time.Now())
+	time.Sleep(200 * time.Millisecond)
+
+	if log, _ := ReadAutoResponses(0); len(log) != 0 {
+		t.Errorf("audit log = %+v, want no answer", log)
+	}
+	pane.mu.Lock()
+	defer pane.mu.Unlock()
+	if len(pane.sent) != 0 {
+		t.Errorf("sent keys = %v, want none", pane.sent)
+	}
+}
@@ -0,0 +1,713 @@
+// Backups: `agent-deck backup create` snapshots a profile's state.db (the
+// session list and every Claude/Codex/Gemini resume mapping) plus
+// config.toml into one tar.gz archive, written to a local directory or an
+// S3-compatible bucket ([backup] destination). With [backup] encrypt = true,
+// and always for S3 since config.toml holds secrets, the archive is sealed
+// with AES-256-GCM under a key derived from the passphrase in
+// $AGENTDECK_BACKUP_PASSPHRASE (or [backup] passphrase_env).
+// [backup] interval makes the maintenance worker take one whenever the
+// newest backup of a profile is older than that, keeping the last `keep`.
+// `backup restore` swaps a backup's state.db in, keeping the current one
+// alongside as state.db.pre-restore-<time>.
+package session
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/atomicfile"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// DefaultBackupKeep is how many backups per profile [backup] keeps when
+// keep is unset.
+const DefaultBackupKeep = 7
+
+// DefaultBackupPassphraseEnv names the environment variable holding the
+// encryption passphrase when [backup] passphrase_env is unset.
+const DefaultBackupPassphraseEnv = "AGENTDECK_BACKUP_PASSPHRASE"
+
+// minBackupInterval keeps a typo like "10s" from backing up every tick.
+const minBackupInterval = time.Hour
+
+// backupMagic prefixes an encrypted archive: magic, 16-byte salt, 12-byte
+// nonce, then the GCM-sealed tar.gz.
+const backupMagic = "ADECKBK1"
+
+const (
+	backupSaltSize   = 16
+	backupPBKDF2Iter = 600_000
+)
+
+// BackupSettings is the [backup] section.
+type BackupSettings struct {
+	// Destination is where backups go: a directory, or s3://bucket/prefix
+	// for an S3-compatible store.
+	Destination string `toml:"destination,omitempty"`
+	// Interval schedules backups through the maintenance worker, as a
+	// duration like "24h" (minimum 1h). Empty: only `backup create`.
+	Interval string `toml:"interval,omitempty"`
+	// Keep is how many backups per profile to keep (default: 7).
+	Keep int `toml:"keep,omitempty"`
+	// Encrypt seals backups with the passphrase from PassphraseEnv.
+	Encrypt bool `toml:"encrypt,omitempty"`
+	// PassphraseEnv names the variable holding the passphrase
+	// (default: AGENTDECK_BACKUP_PASSPHRASE). The passphrase itself never
+	// goes in config.toml, which every backup contains.
+	PassphraseEnv string `toml:"passphrase_env,omitempty"`
+
+	// Endpoint is the S3-compatible endpoint URL, e.g.
+	// "https://<account>.r2.cloudflarestorage.com" or "http://localhost:9000".
+	// Default: AWS S3 in Region.
+	Endpoint string `toml:"endpoint,omitempty"`
+	// Region signs S3 requests (default: "us-east-1").
+	Region string `toml:"region,omitempty"`
+	// AccessKeyEnv and SecretKeyEnv name the variables holding S3
+	// credentials (default: AWS_ACCESS_KEY_ID / AWS_SECRET_ACCESS_KEY;
+	// AWS_SESSION_TOKEN is sent when set).
+	AccessKeyEnv string `toml:"access_key_env,omitempty"`
+	SecretKeyEnv string `toml:"secret_key_env,omitempty"`
+}
+
+// GetKeep returns Keep, defaulting to DefaultBackupKeep.
+func (s BackupSettings) GetKeep() int {
+	if s.Keep > 0 {
+		return s.Keep
+	}
+	return DefaultBackupKeep
+}
+
+// GetPassphraseEnv returns PassphraseEnv, defaulting to
+// DefaultBackupPassphraseEnv.
+func (s BackupSettings) GetPassphraseEnv() string {
+	if v := strings.TrimSpace(s.PassphraseEnv); v != "" {
+		return v
+	}
+	return DefaultBackupPassphraseEnv
+}
+
+// Passphrase returns the passphrase from the configured variable.
+func (s BackupSettings) Passphrase() string {
+	return os.Getenv(s.GetPassphraseEnv())
+}
+
+// ScheduleInterval parses Interval; zero means backups are not scheduled.
+func (s BackupSettings) ScheduleInterval() (time.Duration, error) {
+	spec := strings.TrimSpace(s.Interval)
+	if spec == "" || spec == "0" || spec == "off" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(spec)
+	if err != nil {
+		return 0, fmt.Errorf("invalid [backup] interval %q: use a duration like 24h", spec)
+	}
+	if d < minBackupInterval {
+		return 0, fmt.Errorf("[backup] interval %s is too short (minimum %s)", d, minBackupInterval)
+	}
+	return d, nil
+}
+
+// GetBackupSettings returns the [backup] section.
+func GetBackupSettings() BackupSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return BackupSettings{}
+	}
+	return config.Backup
+}
+
+// BackupInfo describes one stored backup.
+type BackupInfo struct {
+	Name      string    `json:"name"`
+	Profile   string    `json:"profile"`
+	CreatedAt time.Time `json:"created_at"`
+	Encrypted bool      `json:"encrypted"`
+	Size      int64     `json:"size,omitempty"`
+}
+
+// backupNameRE matches agent-deck-<profile>-<UTC time>.tar.gz[.enc].
+var backupNameRE = regexp.MustCompile(`^agent-deck-(.+)-(\d{8}T\d{6}Z)\.tar\.gz(\.enc)?$`)
+
+const backupTimeLayout = "20060102T150405Z"
+
+func backupName(profile string, at time.Time, encrypted bool) string {
+	name := fmt.Sprintf("agent-deck-%s-%s.tar.gz", profile, at.UTC().Format(backupTimeLayout))
+	if encrypted {
+		name += ".enc"
+	}
+	return name
+}
+
+// ParseBackupName parses a backup file name; ok is false for other files.
+func ParseBackupName(name string) (BackupInfo, bool) {
+	m := backupNameRE.FindStringSubmatch(name)
+	if m == nil {
+		return BackupInfo{}, false
+	}
+	at, err := time.Parse(backupTimeLayout, m[2])
+	if err != nil {
+		return BackupInfo{}, false
+	}
+	return BackupInfo{Name: name, Profile: m[1], CreatedAt: at, Encrypted: m[3] != ""}, true
+}
+
+// backupManifest is manifest.json inside an archive.
+type backupManifest struct {
+	Profile   string    `json:"profile"`
+	CreatedAt time.Time `json:"created_at"`
+	Host      string    `json:"host,omitempty"`
+	Files     []string  `json:"files"`
+}
+
+// BackupTarget stores backup archives by name.
+type BackupTarget interface {
+	Put(ctx context.Context, name string, data []byte) error
+	Get(ctx context.Context, name string) ([]byte, error)
+	// List returns the backups stored, newest first.
+	List(ctx context.Context) ([]BackupInfo, error)
+	Delete(ctx context.Context, name string) error
+	String() string
+}
+
+// OpenBackupTarget returns the target for dest (a directory or
+// s3://bucket/prefix); dest defaults to settings.Destination.
+func OpenBackupTarget(dest string, settings BackupSettings) (BackupTarget, error) {
+	if dest == "" {
+		dest = settings.Destination
+	}
+	dest = strings.TrimSpace(dest)
+	if dest == "" {
+		return nil, errors.New("no backup destination: pass --to/--from or set [backup] destination")
+	}
+	if strings.HasPrefix(dest, "s3://") {
+		return newS3BackupTarget(dest, settings)
+	}
+	return dirBackupTarget(ExpandPath(dest)), nil
+}
+
+// dirBackupTarget stores backups as files in a local directory.
+type dirBackupTarget string
+
+func (d dirBackupTarget) String() string { return string(d) }
+
+func (d dirBackupTarget) Put(_ context.Context, name string, data []byte) error {
+	if err := os.MkdirAll(string(d), 0o700); err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(filepath.Join(string(d), name), data, 0o600)
+}
+
+func (d dirBackupTarget) Get(_ context.Context, name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(string(d), filepath.Base(name)))
+}
+
+func (d dirBackupTarget) List(_ context.Context) ([]BackupInfo, error) {
+	entries, err := os.ReadDir(string(d))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []BackupInfo
+	for _, e := range entries {
+		info, ok := ParseBackupName(e.Name())
+		if !ok || e.IsDir() {
+			continue
+		}
+		if fi, err := e.Info(); err == nil {
+			info.Size = fi.Size()
+		}
+		out = append(out, info)
+	}
+	sortBackupsNewestFirst(out)
+	return out, nil
+}
+
+func (d dirBackupTarget) Delete(_ context.Context, name string) error {
+	return os.Remove(filepath.Join(string(d), filepath.Base(name)))
+}
+
+func sortBackupsNewestFirst(b []BackupInfo) {
+	sort.SliceStable(b, func(i, j int) bool { return b[i].CreatedAt.After(b[j].CreatedAt) })
+}
+
+// ProfileBackups filters backups to one profile, keeping the order.
+func ProfileBackups(all []BackupInfo, profile string) []BackupInfo {
+	if profile == "" {
+		profile = DefaultProfile
+	}
+	var out []BackupInfo
+	for _, b := range all {
+		if b.Profile == profile {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// FindBackup returns profile's backup named ref in target, or its newest
+// for "" and "latest".
+func FindBackup(ctx context.Context, target BackupTarget, profile, ref string) (BackupInfo, error) {
+	all, err := target.List(ctx)
+	if err != nil {
+		return BackupInfo{}, err
+	}
+	if ref == "" || ref == "latest" {
+		mine := ProfileBackups(all, profile)
+		if len(mine) == 0 {
+			return BackupInfo{}, fmt.Errorf("no backups of profile %q in %s", profileOrDefault(profile), target)
+		}
+		return mine[0], nil
+	}
+	for _, b := range all {
+		if b.Name == ref {
+			return b, nil
+		}
+	}
+	return BackupInfo{}, fmt.Errorf("backup %q not found in %s", ref, target)
+}
+
+func profileOrDefault(profile string) string {
+	if profile == "" {
+		return DefaultProfile
+	}
+	return profile
+}
+
+// CreateBackupArchive snapshots the profile's state.db and the user config
+// into an archive, sealed with passphrase when it is non-empty.
+func CreateBackupArchive(profile, passphrase string, now time.Time) (string, []byte, error) {
+	if profile == "" {
+		profile = DefaultProfile
+	}
+	dbPath, err := GetDBPathForProfile(profile)
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := os.Stat(dbPath); err != nil {
+		return "", nil, fmt.Errorf("profile %q has no state.db to back up: %w", profile, err)
+	}
+
+	tmp, err := os.MkdirTemp("", "agent-deck-backup-")
+	if err != nil {
+		return "", nil, err
+	}
+	defer os.RemoveAll(tmp)
+	snapshot := filepath.Join(tmp, "state.db")
+	db, err := statedb.Open(dbPath)
+	if err != nil {
+		return "", nil, err
+	}
+	err = db.SnapshotTo(snapshot)
+	_ = db.Close()
+	if err != nil {
+		return "", nil, err
+	}
+
+	files := map[string]string{"state.db": snapshot}
+	if cfgPath, err := GetUserConfigPath(); err == nil {
+		if _, err := os.Stat(cfgPath); err == nil {
+			files[UserConfigFileName] = cfgPath
+		}
+	}
+	host, _ := os.Hostname()
+	data, err := writeBackupTarGz(backupManifest{Profile: profile, CreatedAt: now.UTC(), Host: host}, files)
+	if err != nil {
+		return "", nil, err
+	}
+	if passphrase != "" {
+		if data, err = sealBackup(data, passphrase); err != nil {
+			return "", nil, err
+		}
+	}
+	return backupName(profile, now, passphrase != ""), data, nil
+}
+
+func writeBackupTarGz(manifest backupManifest, files map[string]string) ([]byte, error) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	manifest.Files = names
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	add := func(name string, body []byte) error {
+		hdr := &tar.Header{Name: name, Mode: 0o600, Size: int64(len(body)), ModTime: manifest.CreatedAt}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(body)
+		return err
+	}
+	mj, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := add("manifest.json", mj); err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		body, err := os.ReadFile(files[name])
+		if err != nil {
+			return nil, err
+		}
+		if err := add(name, body); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// readBackupArchive unseals (when needed) and unpacks an archive.
+func readBackupArchive(data []byte, passphrase string) (backupManifest, map[string][]byte, error) {
+	var manifest backupManifest
+	if bytes.HasPrefix(data, []byte(backupMagic)) {
+		if passphrase == "" {
+			return manifest, nil, errors.New("backup is encrypted: set the passphrase variable ([backup] passphrase_env, default " + DefaultBackupPassphraseEnv + ")")
+		}
+		var err error
+		if data, err = openBackup(data, passphrase); err != nil {
+			return manifest, nil, err
+		}
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return manifest, nil, fmt.Errorf("not an agent-deck backup: %w", err)
+	}
+	tr := tar.NewReader(gz)
+	files := map[string][]byte{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return manifest, nil, fmt.Errorf("corrupt backup: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg || strings.Contains(hdr.Name, "/") {
+			continue
+		}
+		body, err := io.ReadAll(tr)
+		if err != nil {
+			return manifest, nil, fmt.Errorf("corrupt backup: %w", err)
+		}
+		files[hdr.Name] = body
+	}
+	if err := json.Unmarshal(files["manifest.json"], &manifest); err != nil {
+		return manifest, nil, errors.New("not an agent-deck backup: missing manifest.json")
+	}
+	if len(files["state.db"]) == 0 {
+		return manifest, nil, errors.New("backup has no state.db")
+	}
+	return manifest, files, nil
+}
+
+func backupKey(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, backupPBKDF2Iter, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func sealBackup(plain []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, backupSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	aead, err := backupKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append([]byte(backupMagic), salt...)
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, plain, []byte(backupMagic)), nil
+}
+
+func openBackup(sealed []byte, passphrase string) ([]byte, error) {
+	rest := sealed[len(backupMagic):]
+	if len(rest) < backupSaltSize {
+		return nil, errors.New("corrupt encrypted backup")
+	}
+	aead, err := backupKey(passphrase, rest[:backupSaltSize])
+	if err != nil {
+		return nil, err
+	}
+	rest = rest[backupSaltSize:]
+	if len(rest) < aead.NonceSize() {
+		return nil, errors.New("corrupt encrypted backup")
+	}
+	plain, err := aead.Open(nil, rest[:aead.NonceSize()], rest[aead.NonceSize():], []byte(backupMagic))
+	if err != nil {
+		return nil, errors.New("cannot decrypt backup: wrong passphrase or corrupt file")
+	}
+	return plain, nil
+}
+
+// BackupTargetNeedsEncryption reports whether backups written to target
+// must be encrypted. Every archive holds config.toml, which may carry API
+// keys and bot tokens, so nothing leaves the machine in the clear: S3
+// targets always need a passphrase.
+func BackupTargetNeedsEncryption(target BackupTarget) bool {
+	_, remote := target.(*s3BackupTarget)
+	return remote
+}
+
+// CreateBackup writes a backup of profile to target and prunes the
+// profile's backups beyond keep (0: no pruning).
+func CreateBackup(ctx context.Context, target BackupTarget, profile, passphrase string, keep int) (BackupInfo, int, error) {
+	if profile == "" {
+		profile = DefaultProfile
+	}
+	if passphrase == "" && BackupTargetNeedsEncryption(target) {
+		return BackupInfo{}, 0, fmt.Errorf("backups to %s must be encrypted because config.toml holds secrets: set the passphrase variable ([backup] passphrase_env, default %s)", target, DefaultBackupPassphraseEnv)
+	}
+	now := time.Now()
+	name, data, err := CreateBackupArchive(profile, passphrase, now)
+	if err != nil {
+		return BackupInfo{}, 0, err
+	}
+	if err := target.Put(ctx, name, data); err != nil {
+		return BackupInfo{}, 0, fmt.Errorf("write backup to %s: %w", target, err)
+	}
+	info, _ := ParseBackupName(name)
+	info.Size = int64(len(data))
+	pruned := 0
+	if keep > 0 {
+		pruned = pruneBackups(ctx, target, profile, keep)
+	}
+	return info, pruned, nil
+}
+
+func pruneBackups(ctx context.Context, target BackupTarget, profile string, keep int) int {
+	all, err := target.List(ctx)
+	if err != nil {
+		maintLog.Warn("backup_list_failed", slog.String("target", target.String()), slog.String("error", err.Error()))
+		return 0
+	}
+	pruned := 0
+	mine := ProfileBackups(all, profile)
+	for i := keep; i < len(mine); i++ {
+		if err := target.Delete(ctx, mine[i].Name); err != nil {
+			maintLog.Warn("backup_prune_failed", slog.String("name", mine[i].Name), slog.String("error", err.Error()))
+			continue
+		}
+		pruned++
+	}
+	return pruned
+}
+
+// RestoreResult reports what RestoreBackup replaced.
+type RestoreResult struct {
+	Profile        string    `json:"profile"`
+	BackupProfile  string    `json:"backup_profile"`
+	CreatedAt      time.Time `json:"created_at"`
+	Sessions       int       `json:"sessions"`
+	PreviousDB     string    `json:"previous_db,omitempty"`
+	ConfigRestored bool      `json:"config_restored"`
+	PreviousConfig string    `json:"previous_config,omitempty"`
+}
+
+// RestoreBackup replaces profile's state.db (and, with restoreConfig, the
+// user config) with the archive's. The files it replaces are kept beside
+// them with a .pre-restore-<time> suffix. Callers must make sure no TUI is
+// using the profile.
+func RestoreBackup(data []byte, profile, passphrase string, restoreConfig bool) (RestoreResult, error) {
+	if profile == "" {
+		profile = DefaultProfile
+	}
+	manifest, files, err := readBackupArchive(data, passphrase)
+	if err != nil {
+		return RestoreResult{}, err
+	}
+	res := RestoreResult{Profile: profile, BackupProfile: manifest.Profile, CreatedAt: manifest.CreatedAt}
+	if restoreConfig && files[UserConfigFileName] == nil {
+		return res, errors.New("backup has no config.toml")
+	}
+
+	dbPath, err := GetDBPathForProfile(profile)
+	if err != nil {
+		return res, err
+	}
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0o700); err != nil {
+		return res, err
+	}
+	// Check the restored database opens before touching the live one.
+	staged := dbPath + ".restore"
+	_ = os.Remove(staged)
+	if err := os.WriteFile(staged, files["state.db"], 0o600); err != nil {
+		return res, err
+	}
+	defer os.Remove(staged)
+	check, err := statedb.Open(staged)
+	if err != nil {
+		return res, err
+	}
+	rows, err := check.LoadInstances()
+	_ = check.Close()
+	if err != nil {
+		return res, fmt.Errorf("backup state.db is unreadable: %w", err)
+	}
+	res.Sessions = len(rows)
+	for _, sfx := range []string{"-wal", "-shm"} {
+		_ = os.Remove(staged + sfx)
+	}
+
+	suffix := ".pre-restore-" + time.Now().UTC().Format(backupTimeLayout)
+	if _, err := os.Stat(dbPath); err == nil {
+		live, err := statedb.Open(dbPath)
+		if err != nil {
+			return res, err
+		}
+		err = live.SnapshotTo(dbPath + suffix)
+		_ = live.Close()
+		if err != nil {
+			return res, fmt.Errorf("keep current state.db: %w", err)
+		}
+		res.PreviousDB = dbPath + suffix
+	}
+	for _, sfx := range []string{"-wal", "-shm"} {
+		if err := os.Remove(dbPath + sfx); err != nil && !os.IsNotExist(err) {
+			return res, err
+		}
+	}
+	if err := os.Rename(staged, dbPath); err != nil {
+		return res, err
+	}
+
+	if restoreConfig {
+		cfgPath, err := GetUserConfigPath()
+		if err != nil {
+			return res, err
+		}
+		if old, err := os.ReadFile(cfgPath); err == nil {
+			if err := os.WriteFile(cfgPath+suffix, old, 0o600); err != nil {
+				return res, err
+			}
+			res.PreviousConfig = cfgPath + suffix
+		}
+		if err := os.MkdirAll(filepath.Dir(cfgPath), 0o700); err != nil {
+			return res, err
+		}
+		if err := atomicfile.WriteFile(cfgPath, files[UserConfigFileName], 0o600); err != nil {
+			return res, err
+		}
+		ClearUserConfigCache()
+		res.ConfigRestored = true
+	}
+	return res, nil
+}
+
+// ProfileLiveInstances returns how many TUIs have a fresh heartbeat in
+// profile's state.db (0 when it has none yet).
+func ProfileLiveInstances(profile string) (int, error) {
+	dbPath, err := GetDBPathForProfile(profile)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return 0, nil
+	}
+	db, err := statedb.Open(dbPath)
+	if err != nil {
+		return 0, err
+	}
+	defer db.Close()
+	n, err := db.AliveInstanceCount()
+	if err != nil && strings.Contains(err.Error(), "no such table") {
+		return 0, nil
+	}
+	return n, err
+}
+
+// RunScheduledBackups backs up every profile whose newest backup is older
+// than [backup] interval. Returns the number of backups written.
+func RunScheduledBackups(ctx context.Context) int {
+	settings := GetBackupSettings()
+	interval, err := settings.ScheduleInterval()
+	if err != nil {
+		maintLog.Warn("backup_interval_invalid", slog.String("error", err.Error()))
+		return 0
+	}
+	if interval == 0 {
+		return 0
+	}
+	target, err := OpenBackupTarget("", settings)
+	if err != nil {
+		maintLog.Warn("backup_target_invalid", slog.String("error", err.Error()))
+		return 0
+	}
+	passphrase := ""
+	if settings.Encrypt || BackupTargetNeedsEncryption(target) {
+		if passphrase = settings.Passphrase(); passphrase == "" {
+			maintLog.Warn("backup_skipped_no_passphrase", slog.String("env", settings.GetPassphraseEnv()), slog.String("target", target.String()))
+			return 0
+		}
+	}
+	profiles, err := ListProfiles()
+	if err != nil {
+		maintLog.Warn("backup_profiles_failed", slog.String("error", err.Error()))
+		return 0
+	}
+	existing, err := target.List(ctx)
+	if err != nil {
+		maintLog.Warn("backup_list_failed", slog.String("target", target.String()), slog.String("error", err.Error()))
+		return 0
+	}
+	written := 0
+	for _, profile := range profiles {
+		if !backupDue(ProfileBackups(existing, profile), interval, time.Now()) {
+			continue
+		}
+		info, _, err := CreateBackup(ctx, target, profile, passphrase, settings.GetKeep())
+		if err != nil {
+			maintLog.Warn("backup_failed", slog.String("profile", profile), slog.String("error", err.Error()))
+			continue
+		}
+		maintLog.Info("backup_written", slog.String("profile", profile), slog.String("name", info.Name), slog.String("target", target.String()))
+		written++
+	}
+	return written
+}
+
+// backupDue reports whether the newest of a profile's backups (newest
+// first) is older than interval.
+func backupDue(backups []BackupInfo, interval time.Duration, now time.Time) bool {
+	return len(backups) == 0 || now.Sub(backups[0].CreatedAt) >= interval
+}
@@ -0,0 +1,277 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// s3BackupTarget stores backups in an S3-compatible bucket (AWS, R2, MinIO,
+// B2...) using path-style requests signed with SigV4, so no SDK is needed.
+type s3BackupTarget struct {
+	endpoint  *url.URL
+	bucket    string
+	prefix    string // "" or "dir/"
+	region    string
+	accessKey string
+	secretKey string
+	token     string
+	client    *http.Client
+	now       func() time.Time
+}
+
+func newS3BackupTarget(dest string, settings BackupSettings) (*s3BackupTarget, error) {
+	rest := strings.TrimPrefix(dest, "s3://")
+	bucket, prefix, _ := strings.Cut(rest, "/")
+	if bucket == "" {
+		return nil, fmt.Errorf("invalid backup destination %q: use s3://bucket[/prefix]", dest)
+	}
+	prefix = strings.Trim(prefix, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	region := strings.TrimSpace(settings.Region)
+	if region == "" {
+		region = "us-east-1"
+	}
+	endpoint := strings.TrimSpace(settings.Endpoint)
+	if endpoint == "" {
+		endpoint = "https://s3." + region + ".amazonaws.com"
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("invalid [backup] endpoint %q", endpoint)
+	}
+	akEnv, skEnv := settings.AccessKeyEnv, settings.SecretKeyEnv
+	if akEnv == "" {
+		akEnv = "AWS_ACCESS_KEY_ID"
+	}
+	if skEnv == "" {
+		skEnv = "AWS_SECRET_ACCESS_KEY"
+	}
+	t := &s3BackupTarget{
+		endpoint:  u,
+		bucket:    bucket,
+		prefix:    prefix,
+		region:    region,
+		accessKey: os.Getenv(akEnv),
+		secretKey: os.Getenv(skEnv),
+		token:     os.Getenv("AWS_SESSION_TOKEN"),
+		client:    &http.Client{Timeout: 5 * time.Minute},
+		now:       time.Now,
+	}
+	if t.accessKey == "" || t.secretKey == "" {
+		return nil, fmt.Errorf("S3 credentials missing: set %s and %s", akEnv, skEnv)
+	}
+	return t, nil
+}
+
+func (t *s3BackupTarget) String() string {
+	return "s3://" + t.bucket + "/" + t.prefix
+}
+
+func (t *s3BackupTarget) Put(ctx context.Context, name string, data []byte) error {
+	_, err := t.do(ctx, http.MethodPut, t.prefix+name, nil, data)
+	return err
+}
+
+func (t *s3BackupTarget) Get(ctx context.Context, name string) ([]byte, error) {
+	return t.do(ctx, http.MethodGet, t.prefix+name, nil, nil)
+}
+
+func (t *s3BackupTarget) Delete(ctx context.Context, name string) error {
+	_, err := t.do(ctx, http.MethodDelete, t.prefix+name, nil, nil)
+	return err
+}
+
+// s3ListResult is the part of a ListObjectsV2 response List reads.
+type s3ListResult struct {
+	Contents []struct {
+		Key  string `xml:"Key"`
+		Size int64  `xml:"Size"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func (t *s3BackupTarget) List(ctx context.Context) ([]BackupInfo, error) {
+	var out []BackupInfo
+	token := ""
+	for {
+		q := url.Values{"list-type": {"2"}, "prefix": {t.prefix}}
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+		body, err := t.do(ctx, http.MethodGet, "", q, nil)
+		if err != nil {
+			return nil, err
+		}
+		var res s3ListResult
+		if err := xml.Unmarshal(body, &res); err != nil {
+			return nil, fmt.Errorf("s3 list: %w", err)
+		}
+		for _, obj := range res.Contents {
+			name := strings.TrimPrefix(obj.Key, t.prefix)
+			if info, ok := ParseBackupName(name); ok && !strings.Contains(name, "/") {
+				info.Size = obj.Size
+				out = append(out, info)
+			}
+		}
+		if !res.IsTruncated || res.NextContinuationToken == "" {
+			break
+		}
+		token = res.NextContinuationToken
+	}
+	sortBackupsNewestFirst(out)
+	return out, nil
+}
+
+// do sends a signed request for key ("" addresses the bucket) and returns
+// the response body; non-2xx responses become errors.
+func (t *s3BackupTarget) do(ctx context.Context, method, key string, query url.Values, body []byte) ([]byte, error) {
+	u := *t.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + t.bucket
+	if key != "" {
+		u.Path += "/" + key
+	}
+	u.RawPath = s3EscapePath(u.Path)
+	u.RawQuery = s3CanonicalQuery(query)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	t.sign(req, body)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		var e struct {
+			Code    string `xml:"Code"`
+			Message string `xml:"Message"`
+		}
+		if xml.Unmarshal(data, &e) == nil && e.Code != "" {
+			return nil, fmt.Errorf("s3 %s %s: %s: %s", method, u.Path, e.Code, e.Message)
+		}
+		return nil, fmt.Errorf("s3 %s %s: %s", method, u.Path, resp.Status)
+	}
+	return data, nil
+}
+
+// sign adds SigV4 headers for the "s3" service.
+func (t *s3BackupTarget) sign(req *http.Request, body []byte) {
+	now := t.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+	if t.token != "" {
+		req.Header.Set("x-amz-security-token", t.token)
+	}
+	headers := map[string]string{"host": req.URL.Host}
+	for k := range req.Header {
+		if lk := strings.ToLower(k); strings.HasPrefix(lk, "x-amz-") {
+			headers[lk] = strings.TrimSpace(req.Header.Get(k))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonHeaders strings.Builder
+	for _, k := range names {
+		canonHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := day + "/" + t.region + "/s3/aws4_request"
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonical))
+
+	key := hmacSHA256([]byte("AWS4"+t.secretKey), day)
+	key = hmacSHA256(key, t.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	sig := hex.EncodeToString(hmacSHA256(key, toSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		t.accessKey, scope, signedHeaders, sig))
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// s3Escape percent-encodes s as SigV4 requires: everything but the RFC 3986
+// unreserved characters, and "/" too unless keepSlash.
+func s3Escape(s string, keepSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && keepSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func s3EscapePath(p string) string { return s3Escape(p, true) }
+
+// s3CanonicalQuery encodes query sorted by key, as both the request and
+// its signature use it.
+func s3CanonicalQuery(q url.Values) string {
+	if len(q) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		for _, v := range q[k] {
+			parts = append(parts, s3Escape(k, false)+"="+s3Escape(v, false))
+		}
+	}
+	return strings.Join(parts, "&")
+}
@@ -0,0 +1,255 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+func seedBackupProfile(t *testing.T, profile string, ids ...string) string {
+	t.Helper()
+	dbPath, err := GetDBPathForProfile(profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := statedb.Open(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := db.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+	var rows []*statedb.InstanceRow
+	for i, id := range ids {
+		rows = append(rows, &statedb.InstanceRow{
+			ID: id, Title: id, ProjectPath: "/" + id, GroupPath: "grp", Order: i,
+			Tool: "claude", Status: "idle", CreatedAt: time.Now(), ToolData: json.RawMessage("{}"),
+		})
+	}
+	if len(rows) > 0 {
+		if err := db.SaveInstances(rows); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dbPath
+}
+
+func TestBackup_CreateAndRestore(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, "xdg-data"))
+	isolateConfigHomeXDG(t)
+	cfgPath, _ := GetUserConfigPath()
+	if err := os.MkdirAll(filepath.Dir(cfgPath), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cfgPath, []byte("theme = \"light\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	dbPath := seedBackupProfile(t, "work", "a", "b")
+
+	target := dirBackupTarget(filepath.Join(home, "backups"))
+	ctx := context.Background()
+	info, _, err := CreateBackup(ctx, target, "work", "s3cret", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Profile != "work" || !info.Encrypted || !strings.HasSuffix(info.Name, ".tar.gz.enc") {
+		t.Fatalf("info = %+v", info)
+	}
+
+	// Lose a session and the config, then restore.
+	seedBackupProfile(t, "work", "c")
+	if err := os.WriteFile(cfgPath, []byte("theme = \"dark\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	found, err := FindBackup(ctx, target, "work", "latest")
+	if err != nil || found.Name != info.Name {
+		t.Fatalf("FindBackup = %+v, %v", found, err)
+	}
+	data, err := target.Get(ctx, found.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RestoreBackup(data, "work", "wrong", false); err == nil || !strings.Contains(err.Error(), "passphrase") {
+		t.Fatalf("wrong passphrase: err = %v", err)
+	}
+	res, err := RestoreBackup(data, "work", "s3cret", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Sessions != 2 || !res.ConfigRestored || res.PreviousDB == "" || res.PreviousConfig == "" {
+		t.Fatalf("restore = %+v", res)
+	}
+
+	db, err := statedb.Open(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	rows, err := db.LoadInstances()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, r := range rows {
+		ids = append(ids, r.ID)
+	}
+	sort.Strings(ids)
+	if strings.Join(ids, ",") != "a,b" {
+		t.Errorf("restored sessions = %v, want a,b", ids)
+	}
+	if got, _ := os.ReadFile(cfgPath); string(got) != "theme = \"light\"\n" {
+		t.Errorf("restored config = %q", got)
+	}
+	if _, err := os.Stat(res.PreviousDB); err != nil {
+		t.Errorf("previous state.db not kept: %v", err)
+	}
+}
+
+func TestBackup_PruneAndSchedule(t *testing.T) {
+	target := dirBackupTarget(t.TempDir())
+	ctx := context.Background()
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		for _, p := range []string{"work", "work-2"} {
+			if err := target.Put(ctx, backupName(p, base.Add(time.Duration(i)*time.Hour), false), []byte("x")); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if n := pruneBackups(ctx, target, "work", 2); n != 2 {
+		t.Errorf("pruned %d, want 2", n)
+	}
+	all, _ := target.List(ctx)
+	work := ProfileBackups(all, "work")
+	if len(work) != 2 || len(ProfileBackups(all, "work-2")) != 4 {
+		t.Fatalf("after prune: work=%v all=%d", work, len(all))
+	}
+	if !work[0].CreatedAt.Equal(base.Add(3 * time.Hour)) {
+		t.Errorf("newest kept = %v", work[0].CreatedAt)
+	}
+
+	if backupDue(work, 24*time.Hour, base.Add(4*time.Hour)) {
+		t.Error("backup taken 1h ago should not be due")
+	}
+	if !backupDue(work, 24*time.Hour, base.Add(27*time.Hour)) || !backupDue(nil, time.Hour, base) {
+		t.Error("backup should be due")
+	}
+	if _, err := (BackupSettings{Interval: "5m"}).ScheduleInterval(); err == nil {
+		t.Error("interval below minimum: want error")
+	}
+}
+
+// fakeS3 is an in-memory bucket that checks requests are signed.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") || r.Header.Get("x-amz-content-sha256") == "" {
+		http.Error(w, "<Error><Code>AccessDenied</Code><Message>unsigned</Message></Error>", http.StatusForbidden)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/bucket":
+		type obj struct {
+			Key  string
+			Size int64
+		}
+		var res struct {
+			XMLName  xml.Name `xml:"ListBucketResult"`
+			Contents []obj
+		}
+		for k, v := range f.objects {
+			if strings.HasPrefix(k, r.URL.Query().Get("prefix")) {
+				res.Contents = append(res.Contents, obj{k, int64(len(v))})
+			}
+		}
+		_ = xml.NewEncoder(w).Encode(res)
+	case r.Method == http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[key] = body
+	case r.Method == http.MethodGet:
+		body, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "<Error><Code>NoSuchKey</Code><Message>missing</Message></Error>", http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(body)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3BackupTarget(t *testing.T) {
+	fake := &fakeS3{objects: map[string][]byte{"other/agent-deck-x-20260101T000000Z.tar.gz": []byte("y")}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	target, err := OpenBackupTarget("s3://bucket/deck/", BackupSettings{Endpoint: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	name := backupName("work", time.Now(), true)
+	if err := target.Put(ctx, name, []byte("sealed")); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := fake.objects["deck/"+name]; !ok {
+		t.Fatalf("object not stored under prefix: %v", fake.objects)
+	}
+	list, err := target.List(ctx)
+	if err != nil || len(list) != 1 || list[0].Name != name || !list[0].Encrypted {
+		t.Fatalf("List = %+v, %v", list, err)
+	}
+	if got, err := target.Get(ctx, name); err != nil || string(got) != "sealed" {
+		t.Fatalf("Get = %q, %v", got, err)
+	}
+	if _, err := target.Get(ctx, "missing"); err == nil || !strings.Contains(err.Error(), "NoSuchKey") {
+		t.Errorf("Get missing: err = %v", err)
+	}
+	if err := target.Delete(ctx, name); err != nil {
+		t.Fatal(err)
+	}
+	if len(fake.objects) != 1 {
+		t.Errorf("objects after delete = %v", fake.objects)
+	}
+
+	// config.toml holds secrets: an unencrypted backup never reaches S3.
+	if !BackupTargetNeedsEncryption(target) || BackupTargetNeedsEncryption(dirBackupTarget(t.TempDir())) {
+		t.Error("only S3 targets should require encryption")
+	}
+	if _, _, err := CreateBackup(ctx, target, "work", "", 0); err == nil || !strings.Contains(err.Error(), "must be encrypted") {
+		t.Errorf("unencrypted S3 backup: err = %v", err)
+	}
+	if len(fake.objects) != 1 {
+		t.Errorf("unencrypted backup uploaded: %v", fake.objects)
+	}
+
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	if _, err := OpenBackupTarget("s3://bucket", BackupSettings{}); err == nil {
+		t.Error("missing credentials: want error")
+	}
+}
@@ -0,0 +1,64 @@
+package session
+
+import (
+	"log/slog"
+
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// StartBatch defers the cosmetic tmux options — status bar, terminal title,
+// detach binding — of a run of session starts into one shared OptionBatch, so
+// a bulk start of N sessions spends one tmux call per session on the options
+// that matter plus a single call for the rest. Run each start through Do and
+// call Flush after the last one. A nil *StartBatch runs starts unbatched.
+type StartBatch struct {
+	batch *tmux.OptionBatch
+}
+
+// NewStartBatch returns an empty StartBatch.
+func NewStartBatch() *StartBatch {
+	return &StartBatch{batch: tmux.NewOptionBatch()}
+}
+
+// Do runs start (Start, StartWithMessage or Restart of inst) with inst's
+// cosmetic options deferred into the batch. Not safe to call concurrently for
+// the same instance.
+func (b *StartBatch) Do(inst *Instance, start func() error) error {
+	if b == nil {
+		return start()
+	}
+	inst.tmuxOptionBatch = b.batch
+	defer func() {
+		inst.tmuxOptionBatch = nil
+		if inst.tmuxSession != nil {
+			inst.tmuxSession.DeferCosmeticOptions(nil)
+		}
+	}()
+	return start()
+}
+
+// Flush applies the deferred options of every start so far.
+func (b *StartBatch) Flush() {
+	if b == nil || b.batch.Len() == 0 {
+		return
+	}
+	n := b.batch.Len()
+	if err := b.batch.Flush(); err != nil {
+		sessionLog.Warn("bulk_start_option_flush_failed", slog.Int("sessions", n), slog.String("error", err.Error()))
+	}
+}
+
+// StartInstances starts insts one after another with start (typically
+// (*Instance).Start or a StartWithMessage closure) through one StartBatch.
+// Returns the start error for each failed instance, keyed by instance ID.
+func StartInstances(insts []*Instance, start func(*Instance) error) map[string]error {
+	batch := NewStartBatch()
+	errs := make(map[string]error)
+	for _, inst := range insts {
+		if err := batch.Do(inst, func() error { return start(inst) }); err != nil {
+			errs[inst.ID] = err
+		}
+	}
+	batch.Flush()
+	return errs
+}
@@ -0,0 +1,42 @@
+package session
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStartBatch_DoAttachesBatchOnlyDuringStart(t *testing.T) {
+	inst := &Instance{ID: "s-1"}
+	batch := NewStartBatch()
+	var during bool
+	err := batch.Do(inst, func() error {
+		during = inst.tmuxOptionBatch == batch.batch
+		return errors.New("boom")
+	})
+	if err == nil || err.Error() != "boom" {
+		t.Fatalf("Do error = %v, want the start error", err)
+	}
+	if !during || inst.tmuxOptionBatch != nil {
+		t.Errorf("batch attached during start = %v, after = %v", during, inst.tmuxOptionBatch)
+	}
+
+	var nilBatch *StartBatch
+	ran := false
+	if err := nilBatch.Do(inst, func() error { ran = inst.tmuxOptionBatch == nil; return nil }); err != nil || !ran {
+		t.Errorf("nil batch: ran unbatched = %v, err = %v", ran, err)
+	}
+	nilBatch.Flush()
+}
+
+func TestStartInstances_ReportsFailuresByID(t *testing.T) {
+	a, b := &Instance{ID: "a"}, &Instance{ID: "b"}
+	errs := StartInstances([]*Instance{a, b}, func(inst *Instance) error {
+		if inst == b {
+			return errors.New("no tmux")
+		}
+		return nil
+	})
+	if len(errs) != 1 || errs["b"] == nil {
+		t.Errorf("errs = %v, want only b", errs)
+	}
+}
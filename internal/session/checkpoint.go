@@ -0,0 +1,317 @@
+// Checkpoints: opt-in snapshots of a session's working tree, so an agent's
+// mistakes can be rolled back. `session set <id> checkpoint <mode>` (or
+// `launch --checkpoint`) turns them on; the mode is "waiting" (snapshot each
+// time the agent finishes a turn), an interval like "15m" (snapshot that
+// often while the session is live), or both: "waiting,15m". Snapshots are
+// commits under refs/agent-deck/checkpoints/<id>/ in the session's repo (see
+// git.CreateCheckpoint), so the branch and index are never touched. Like
+// labels, the mode lives in the tool_data extras zone.
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/git"
+)
+
+const toolDataCheckpointKey = "checkpoint"
+
+// CheckpointOnWaiting is the mode token that snapshots on every
+// running -> waiting transition.
+const CheckpointOnWaiting = "waiting"
+
+// MaxCheckpointsPerSession bounds the snapshots kept per session; older
+// ones are pruned as new ones are taken.
+const MaxCheckpointsPerSession = 50
+
+// minCheckpointInterval keeps a typo like "15s" from snapshotting every tick.
+const minCheckpointInterval = time.Minute
+
+// CheckpointMode is a parsed checkpoint spec.
+type CheckpointMode struct {
+	OnWaiting bool
+	Interval  time.Duration
+}
+
+// Enabled reports whether the mode takes any checkpoints.
+func (m CheckpointMode) Enabled() bool {
+	return m.OnWaiting || m.Interval > 0
+}
+
+// ParseCheckpointMode parses a checkpoint spec: "waiting", a Go duration of
+// at least a minute, or both comma-separated. "" and "off" disable.
+func ParseCheckpointMode(spec string) (CheckpointMode, error) {
+	var m CheckpointMode
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	if spec == "" || spec == "off" {
+		return m, nil
+	}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == CheckpointOnWaiting {
+			m.OnWaiting = true
+			continue
+		}
+		d, err := time.ParseDuration(part)
+		if err != nil {
+			return CheckpointMode{}, fmt.Errorf("invalid checkpoint mode %q: use \"waiting\", an interval like 15m, or both (\"waiting,15m\")", spec)
+		}
+		if d < minCheckpointInterval {
+			return CheckpointMode{}, fmt.Errorf("checkpoint interval %s is too short (minimum %s)", d, minCheckpointInterval)
+		}
+		m.Interval = d
+	}
+	return m, nil
+}
+
+// SetCheckpoint validates and sets the session's checkpoint mode. "" or
+// "off" turns checkpoints off.
+func (i *Instance) SetCheckpoint(spec string) error {
+	m, err := ParseCheckpointMode(spec)
+	if err != nil {
+		return err
+	}
+	spec = ""
+	if m.Enabled() {
+		var parts []string
+		if m.OnWaiting {
+			parts = append(parts, CheckpointOnWaiting)
+		}
+		if m.Interval > 0 {
+			parts = append(parts, m.Interval.String())
+		}
+		spec = strings.Join(parts, ",")
+	}
+	i.Checkpoint = spec
+	i.checkpointCleared = spec == ""
+	return nil
+}
+
+// CheckpointMode returns the session's parsed checkpoint mode.
+func (i *Instance) CheckpointMode() CheckpointMode {
+	m, _ := ParseCheckpointMode(i.Checkpoint)
+	return m
+}
+
+// WriteCheckpointToToolData merges the checkpoint mode into the tool_data
+// blob. See WriteDependsOnToToolData for the cleared semantics.
+func WriteCheckpointToToolData(td json.RawMessage, spec string, cleared bool) json.RawMessage {
+	if spec == "" && !cleared {
+		return td
+	}
+	m := map[string]json.RawMessage{}
+	if len(td) > 0 {
+		_ = json.Unmarshal(td, &m)
+	}
+	raw, _ := json.Marshal(spec)
+	m[toolDataCheckpointKey] = raw
+	out, _ := json.Marshal(m)
+	return out
+}
+
+// ReadCheckpointFromToolData extracts the checkpoint mode from the blob.
+func ReadCheckpointFromToolData(td json.RawMessage) string {
+	if len(td) == 0 {
+		return ""
+	}
+	var blob struct {
+		Checkpoint string `json:"checkpoint"`
+	}
+	_ = json.Unmarshal(td, &blob)
+	return blob.Checkpoint
+}
+
+// CheckpointDir returns the git working tree a session's checkpoints are
+// taken in: its worktree, else its project path.
+func (i *Instance) CheckpointDir() (string, error) {
+	dir := i.ProjectPath
+	if i.IsWorktree() {
+		dir = i.WorktreePath
+	}
+	if dir == "" || !git.IsGitRepo(dir) {
+		return "", fmt.Errorf("session '%s' is not in a git repository", i.Title)
+	}
+	return dir, nil
+}
+
+// CreateInstanceCheckpoint snapshots the session's working tree and prunes
+// it to MaxCheckpointsPerSession. created is false when nothing changed
+// since the latest checkpoint.
+func CreateInstanceCheckpoint(inst *Instance, message string) (git.Checkpoint, bool, error) {
+	dir, err := inst.CheckpointDir()
+	if err != nil {
+		return git.Checkpoint{}, false, err
+	}
+	cp, created, err := git.CreateCheckpoint(dir, inst.ID, message)
+	if err != nil || !created {
+		return cp, created, err
+	}
+	if err := git.PruneCheckpoints(dir, inst.ID, MaxCheckpointsPerSession); err != nil {
+		sessionLog.Warn("checkpoint_prune_failed",
+			slog.String("instance_id", inst.ID),
+			slog.String("error", err.Error()))
+	}
+	return cp, true, nil
+}
+
+// ListInstanceCheckpoints returns the session's checkpoints, newest first.
+func ListInstanceCheckpoints(inst *Instance) ([]git.Checkpoint, error) {
+	dir, err := inst.CheckpointDir()
+	if err != nil {
+		return nil, err
+	}
+	return git.ListCheckpoints(dir, inst.ID)
+}
+
+// ErrNoCheckpoints is returned when a session has no checkpoint to roll back to.
+var ErrNoCheckpoints = errors.New("no checkpoints")
+
+// FindCheckpoint picks a checkpoint by its position in the newest-first list
+// ("1" is the newest, as `checkpoint list` numbers them) or by a commit
+// prefix. An empty target selects the newest.
+func FindCheckpoint(cps []git.Checkpoint, target string) (git.Checkpoint, error) {
+	if len(cps) == 0 {
+		return git.Checkpoint{}, ErrNoCheckpoints
+	}
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return cps[0], nil
+	}
+	if n, err := strconv.Atoi(target); err == nil && len(target) < 4 {
+		if n < 1 || n > len(cps) {
+			return git.Checkpoint{}, fmt.Errorf("no checkpoint #%d (there are %d)", n, len(cps))
+		}
+		return cps[n-1], nil
+	}
+	var match []git.Checkpoint
+	for _, cp := range cps {
+		if strings.HasPrefix(cp.Commit, strings.ToLower(target)) {
+			match = append(match, cp)
+		}
+	}
+	switch len(match) {
+	case 0:
+		return git.Checkpoint{}, fmt.Errorf("no checkpoint matches %q", target)
+	case 1:
+		return match[0], nil
+	}
+	return git.Checkpoint{}, fmt.Errorf("%q matches %d checkpoints; use more characters", target, len(match))
+}
+
+// RollbackInstanceCheckpoint restores the session's working tree to the
+// checkpoint target (see FindCheckpoint). The current state is snapshotted
+// first and returned as safety, so the rollback itself can be undone.
+func RollbackInstanceCheckpoint(inst *Instance, target string) (restored, safety git.Checkpoint, err error) {
+	dir, err := inst.CheckpointDir()
+	if err != nil {
+		return git.Checkpoint{}, git.Checkpoint{}, err
+	}
+	cps, err := git.ListCheckpoints(dir, inst.ID)
+	if err != nil {
+		return git.Checkpoint{}, git.Checkpoint{}, err
+	}
+	restored, err = FindCheckpoint(cps, target)
+	if err != nil {
+		return git.Checkpoint{}, git.Checkpoint{}, err
+	}
+	safety, _, err = CreateInstanceCheckpoint(inst, "before rollback to "+restored.ShortCommit())
+	if err != nil {
+		return restored, git.Checkpoint{}, fmt.Errorf("snapshot current state before rollback: %w", err)
+	}
+	if err := git.RestoreCheckpoint(dir, restored); err != nil {
+		return restored, safety, err
+	}
+	return restored, safety, nil
+}
+
+// CheckpointWatcherConfig wires the watcher to its environment. Nil fields
+// default to production behavior; tests inject them.
+type CheckpointWatcherConfig struct {
+	// Now is the clock source. Defaults to time.Now.
+	Now func() time.Time
+	// Create takes one checkpoint. Defaults to CreateInstanceCheckpoint.
+	Create func(inst *Instance, message string) (git.Checkpoint, bool, error)
+}
+
+// CheckpointWatcher takes the automatic checkpoints of sessions with a
+// checkpoint mode. Like WorktreeSyncWatcher, Tick is driven from the TUI's
+// background sweep; it must run every sweep to see status transitions.
+type CheckpointWatcher struct {
+	cfg CheckpointWatcherConfig
+
+	run sync.Mutex // held for a whole Tick; a slow snapshot skips the next one
+
+	lastStatus map[string]Status
+	lastTaken  map[string]time.Time
+}
+
+// NewCheckpointWatcher constructs a watcher with production defaults filled
+// in for any nil config callback.
+func NewCheckpointWatcher(cfg CheckpointWatcherConfig) *CheckpointWatcher {
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	if cfg.Create == nil {
+		cfg.Create = CreateInstanceCheckpoint
+	}
+	return &CheckpointWatcher{
+		cfg:        cfg,
+		lastStatus: map[string]Status{},
+		lastTaken:  map[string]time.Time{},
+	}
+}
+
+// Tick checkpoints every session whose agent just finished a turn (mode
+// "waiting") or whose interval elapsed while it was live.
+func (w *CheckpointWatcher) Tick(instances []*Instance) {
+	if !w.run.TryLock() {
+		return
+	}
+	defer w.run.Unlock()
+
+	now := w.cfg.Now()
+	seen := make(map[string]bool, len(instances))
+	for _, inst := range instances {
+		if inst == nil || inst.IsArchived() {
+			continue
+		}
+		mode := inst.CheckpointMode()
+		if !mode.Enabled() {
+			continue
+		}
+		seen[inst.ID] = true
+		status := inst.GetStatusThreadSafe()
+		prev, tracked := w.lastStatus[inst.ID]
+		w.lastStatus[inst.ID] = status
+
+		live := status == StatusRunning || status == StatusWaiting || status == StatusIdle
+		var message string
+		switch {
+		case mode.OnWaiting && tracked && prev == StatusRunning && (status == StatusWaiting || status == StatusIdle):
+			message = "agent finished a turn"
+		case mode.Interval > 0 && live && now.Sub(w.lastTaken[inst.ID]) >= mode.Interval:
+			message = "every " + mode.Interval.String()
+		default:
+			continue
+		}
+		w.lastTaken[inst.ID] = now
+		if _, _, err := w.cfg.Create(inst, message); err != nil {
+			sessionLog.Warn("checkpoint_failed",
+				slog.String("instance_id", inst.ID),
+				slog.String("error", err.Error()))
+		}
+	}
+	for id := range w.lastStatus {
+		if !seen[id] {
+			delete(w.lastStatus, id)
+			delete(w.lastTaken, id)
+		}
+	}
+}
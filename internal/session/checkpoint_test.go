@@ -0,0 +1,136 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/git"
+)
+
+func TestParseCheckpointMode(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    CheckpointMode
+		wantErr bool
+	}{
+		{"", CheckpointMode{}, false},
+		{"off", CheckpointMode{}, false},
+		{"waiting", CheckpointMode{OnWaiting: true}, false},
+		{"15m", CheckpointMode{Interval: 15 * time.Minute}, false},
+		{" Waiting, 1h ", CheckpointMode{OnWaiting: true, Interval: time.Hour}, false},
+		{"30s", CheckpointMode{}, true},
+		{"often", CheckpointMode{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseCheckpointMode(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseCheckpointMode(%q) err = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseCheckpointMode(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestSetField_Checkpoint(t *testing.T) {
+	inst := &Instance{ID: "x"}
+	if _, _, err := SetField(inst, FieldCheckpoint, "15m,WAITING", nil); err != nil {
+		t.Fatalf("valid mode rejected: %v", err)
+	}
+	if inst.Checkpoint != "waiting,15m0s" {
+		t.Errorf("Checkpoint = %q, want normalized waiting,15m0s", inst.Checkpoint)
+	}
+	if _, _, err := SetField(inst, FieldCheckpoint, "5s", nil); err == nil {
+		t.Error("too-short interval accepted")
+	}
+	if _, _, err := SetField(inst, FieldCheckpoint, "off", nil); err != nil || inst.Checkpoint != "" || !inst.checkpointCleared {
+		t.Errorf("off: %q cleared=%v err=%v", inst.Checkpoint, inst.checkpointCleared, err)
+	}
+}
+
+func TestCheckpointToolDataRoundTrip(t *testing.T) {
+	td := WriteCheckpointToToolData([]byte(`{"claude_session_id":"x"}`), "waiting", false)
+	if got := ReadCheckpointFromToolData(td); got != "waiting" {
+		t.Errorf("round trip = %q", got)
+	}
+	if got := WriteCheckpointToToolData(td, "", false); string(got) != string(td) {
+		t.Errorf("unset mode should leave the blob alone, got %s", got)
+	}
+	if got := ReadCheckpointFromToolData(WriteCheckpointToToolData(td, "", true)); got != "" {
+		t.Errorf("cleared mode = %q, want explicit empty", got)
+	}
+}
+
+func TestFindCheckpoint(t *testing.T) {
+	cps := []git.Checkpoint{
+		{Commit: "abc12345aaaa"},
+		{Commit: "abd99999bbbb"},
+		{Commit: "f00d0000cccc"},
+	}
+	if _, err := FindCheckpoint(nil, ""); err != ErrNoCheckpoints {
+		t.Errorf("empty list err = %v, want ErrNoCheckpoints", err)
+	}
+	for target, want := range map[string]string{
+		"":     "abc12345aaaa",
+		"1":    "abc12345aaaa",
+		"3":    "f00d0000cccc",
+		"abd9": "abd99999bbbb",
+		"F00D": "f00d0000cccc",
+	} {
+		got, err := FindCheckpoint(cps, target)
+		if err != nil || got.Commit != want {
+			t.Errorf("FindCheckpoint(%q) = %s, %v; want %s", target, got.Commit, err, want)
+		}
+	}
+	for _, target := range []string{"0", "4", "ab", "9999"} {
+		if got, err := FindCheckpoint(cps, target); err == nil {
+			t.Errorf("FindCheckpoint(%q) = %s, want error", target, got.Commit)
+		}
+	}
+}
+
+func TestCheckpointWatcher_TakesCheckpoints(t *testing.T) {
+	clock := newFakeClock(time.Date(2026, 10, 12, 12, 0, 0, 0, time.Local))
+	var taken []string
+	w := NewCheckpointWatcher(CheckpointWatcherConfig{
+		Now: clock.Now,
+		Create: func(inst *Instance, message string) (git.Checkpoint, bool, error) {
+			taken = append(taken, inst.ID+": "+message)
+			return git.Checkpoint{}, true, nil
+		},
+	})
+
+	onTurn := &Instance{ID: "turn", Status: StatusRunning, Checkpoint: "waiting"}
+	timed := &Instance{ID: "timed", Status: StatusStopped, Checkpoint: "30m"}
+	off := &Instance{ID: "off", Status: StatusRunning}
+	all := []*Instance{onTurn, timed, off}
+
+	// First sight of a session is not a transition; a stopped session is
+	// not checkpointed on the interval.
+	w.Tick(all)
+	if len(taken) != 0 {
+		t.Fatalf("taken = %v, want none on the first tick", taken)
+	}
+
+	onTurn.Status = StatusWaiting
+	timed.Status = StatusRunning
+	off.Status = StatusWaiting
+	w.Tick(all)
+	if len(taken) != 2 || taken[0] != "turn: agent finished a turn" || taken[1] != "timed: every 30m0s" {
+		t.Fatalf("taken = %v, want a turn and an interval checkpoint", taken)
+	}
+
+	// Staying waiting and within the interval: nothing new.
+	clock.Advance(10 * time.Minute)
+	w.Tick(all)
+	if len(taken) != 2 {
+		t.Fatalf("taken = %v, want no repeat", taken)
+	}
+
+	clock.Advance(25 * time.Minute)
+	w.Tick(all)
+	if len(taken) != 3 || taken[2] != "timed: every 30m0s" {
+		t.Fatalf("taken = %v, want a second interval checkpoint", taken)
+	}
+}
@@ -264,6 +264,13 @@ func getMCPInfoUncached(projectPath string) *MCPInfo {
 			var mcp projectMCPConfig
 			if json.Unmarshal(data, &mcp) == nil {
 				for name := range mcp.MCPServers {
+					if name == MCPHubServerName {
+						// Report the MCPs behind the hot-swap hub, not the hub.
+						for _, hubName := range mcpHubServerNames(currentPath) {
+							info.LocalMCPs = append(info.LocalMCPs, LocalMCP{Name: hubName, SourcePath: currentPath})
+						}
+						continue
+					}
 					info.LocalMCPs = append(info.LocalMCPs, LocalMCP{
 						Name:       name,
 						SourcePath: currentPath,
@@ -301,6 +308,7 @@ func getMCPInfoUncached(projectPath string) *MCPInfo {
 type resolveOpts struct {
 	inst      *Instance
 	groupPath string
+	profile   string // "" = the effective profile
 }
 
 // resolveClaudeConfigDir is the single source of truth for the Claude
@@ -376,7 +384,7 @@ func resolveClaudeConfigDir(opts resolveOpts) (path, source string) {
 	}
 
 	if userConfig != nil {
-		profile := GetEffectiveProfile("")
+		profile := GetEffectiveProfile(opts.profile)
 		if profileDir := userConfig.GetProfileClaudeConfigDir(profile); profileDir != "" {
 			return profileDir, "profile"
 		}
@@ -422,6 +430,13 @@ func GetClaudeConfigDir() string {
 	return path
 }
 
+// GetClaudeConfigDirForProfile returns the Claude config directory an
+// agent-deck profile resolves to: env > profile > global > default.
+func GetClaudeConfigDirForProfile(profile string) string {
+	path, _ := resolveClaudeConfigDir(resolveOpts{profile: profile})
+	return path
+}
+
 // IsClaudeConfigDirExplicit returns true when any priority level (env,
 // profile, global) sets the dir.
 func IsClaudeConfigDirExplicit() bool {
@@ -6,7 +6,6 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/asheshgoplani/agent-deck/internal/atomicfile"
 )
@@ -97,7 +96,10 @@ func InjectClaudeHooks(configDir string) (bool, error) {
 	}
 
 	// Check if already installed (all events present with our hook command)
-	if hooksAlreadyInstalled(existingHooks) {
+	// and stamped by this binary's hook set. A missing or foreign stamp
+	// re-runs the merge, which is idempotent, so a hook-set version bump
+	// always reaches settings.json.
+	if hooksAlreadyInstalled(existingHooks) && claudeHooksStampCurrent(configDir) {
 		return false, nil
 	}
 
@@ -128,7 +130,11 @@ func InjectClaudeHooks(configDir string) (bool, error) {
 		return false, fmt.Errorf("write settings.json: %w", err)
 	}
 
-	sessionLog.Info("claude_hooks_installed", slog.String("config_dir", configDir))
+	if err := writeClaudeHooksStamp(configDir); err != nil {
+		sessionLog.Warn("claude_hooks_stamp_failed", slog.String("config_dir", configDir), slog.String("error", err.Error()))
+	}
+
+	sessionLog.Info("claude_hooks_installed", slog.String("config_dir", configDir), slog.Int("hooks_version", ClaudeHooksVersion))
 	return true, nil
 }
 
@@ -196,6 +202,8 @@ func RemoveClaudeHooks(configDir string) (bool, error) {
 		return false, fmt.Errorf("write settings.json: %w", err)
 	}
 
+	removeClaudeHooksStamp(configDir)
+
 	sessionLog.Info("claude_hooks_removed", slog.String("config_dir", configDir))
 	return true, nil
 }
@@ -251,7 +259,7 @@ func hooksAlreadyInstalled(hooks map[string]json.RawMessage) bool {
 
 // eventHasAgentDeckHookMatchingConfig checks both presence AND config match:
 // the agent-deck hook entry must live under a matcher block whose Matcher
-// field equals the expected value, and its Async flag must match.
+// field equals the expected value, and its Async flag and command must match.
 func eventHasAgentDeckHookMatchingConfig(raw json.RawMessage, expectedMatcher string, expectedAsync bool) bool {
 	var matchers []claudeHookMatcher
 	if err := json.Unmarshal(raw, &matchers); err != nil {
@@ -262,8 +270,10 @@ func eventHasAgentDeckHookMatchingConfig(raw json.RawMessage, expectedMatcher st
 			continue
 		}
 		for _, h := range m.Hooks {
-			if strings.Contains(h.Command, agentDeckHookCommand) {
-				return h.Async == expectedAsync
+			if isAgentDeckHookCommand(h.Command) {
+				// A hook written with an old binary path or flags is drift
+				// too: re-installing rewrites it to the canonical command.
+				return h.Async == expectedAsync && h.Command == agentDeckHookCommand
 			}
 		}
 	}
@@ -290,7 +300,7 @@ func mergeHookEvent(existing json.RawMessage, matcher string, async bool) json.R
 			// (e.g., flipping Async from true to false) and the persisted
 			// settings.json must drift to follow.
 			for j, h := range m.Hooks {
-				if strings.Contains(h.Command, agentDeckHookCommand) {
+				if isAgentDeckHookCommand(h.Command) {
 					matchers[i].Hooks[j] = agentDeckHook(async)
 					result, _ := json.Marshal(matchers)
 					return result
@@ -327,7 +337,7 @@ func removeAgentDeckFromEvent(raw json.RawMessage) (json.RawMessage, bool) {
 	for _, m := range matchers {
 		var hooks []claudeHookEntry
 		for _, h := range m.Hooks {
-			if strings.Contains(h.Command, agentDeckHookCommand) {
+			if isAgentDeckHookCommand(h.Command) {
 				removed = true
 				continue
 			}
@@ -0,0 +1,198 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/atomicfile"
+)
+
+// ClaudeHooksVersion is the version of the hook set in hookEventConfigs.
+// Bump it whenever the installed entries change (events, matchers, async
+// flags, command) so installs written by an older or newer binary are
+// detected and re-synced, even when the change is not visible to
+// hooksAlreadyInstalled.
+const ClaudeHooksVersion = 1
+
+// claudeHooksStampName is the stamp file written next to settings.json.
+// Claude Code ignores it; keeping it out of settings.json avoids tripping
+// Claude's settings validation with an unknown key.
+const claudeHooksStampName = "agent-deck-hooks.json"
+
+// ClaudeHooksStamp records which hook set was last written to a config dir.
+type ClaudeHooksStamp struct {
+	Version     int       `json:"version"`
+	Command     string    `json:"command"`
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+// ClaudeHooksState classifies a config dir's agent-deck hook install.
+type ClaudeHooksState string
+
+const (
+	ClaudeHooksNotInstalled ClaudeHooksState = "not_installed"
+	ClaudeHooksCurrent      ClaudeHooksState = "current"
+	ClaudeHooksOutdated     ClaudeHooksState = "outdated" // written by an older binary
+	ClaudeHooksNewer        ClaudeHooksState = "newer"    // written by a newer binary
+)
+
+// ClaudeHooksStatus describes the agent-deck hooks in one config dir.
+type ClaudeHooksStatus struct {
+	ConfigDir string            `json:"config_dir"`
+	State     ClaudeHooksState  `json:"state"`
+	Stamp     *ClaudeHooksStamp `json:"stamp,omitempty"`
+	// Reasons explains an outdated or newer state, one finding per entry.
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// NeedsSync reports whether re-installing would change the hooks. A config
+// dir without agent-deck hooks never needs a sync: installing is opt-in.
+func (s ClaudeHooksStatus) NeedsSync() bool {
+	return s.State == ClaudeHooksOutdated || s.State == ClaudeHooksNewer
+}
+
+// ReadClaudeHooksStamp returns the stamp in configDir, or nil when absent or
+// unreadable.
+func ReadClaudeHooksStamp(configDir string) *ClaudeHooksStamp {
+	data, err := os.ReadFile(filepath.Join(configDir, claudeHooksStampName))
+	if err != nil {
+		return nil
+	}
+	var stamp ClaudeHooksStamp
+	if err := json.Unmarshal(data, &stamp); err != nil {
+		return nil
+	}
+	return &stamp
+}
+
+// writeClaudeHooksStamp records the current hook set in configDir.
+func writeClaudeHooksStamp(configDir string) error {
+	data, err := json.MarshalIndent(ClaudeHooksStamp{
+		Version:     ClaudeHooksVersion,
+		Command:     agentDeckHookCommand,
+		InstalledAt: time.Now().UTC().Truncate(time.Second),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := atomicfile.WriteFile(filepath.Join(configDir, claudeHooksStampName), data, 0644); err != nil {
+		return fmt.Errorf("write hooks stamp: %w", err)
+	}
+	return nil
+}
+
+// removeClaudeHooksStamp deletes the stamp after the hooks are uninstalled.
+func removeClaudeHooksStamp(configDir string) {
+	_ = os.Remove(filepath.Join(configDir, claudeHooksStampName))
+}
+
+// claudeHooksStampCurrent reports whether configDir is stamped with exactly
+// this binary's hook set.
+func claudeHooksStampCurrent(configDir string) bool {
+	stamp := ReadClaudeHooksStamp(configDir)
+	return stamp != nil && stamp.Version == ClaudeHooksVersion
+}
+
+// InspectClaudeHooks compares the agent-deck hooks in configDir's
+// settings.json against the set this binary installs.
+func InspectClaudeHooks(configDir string) ClaudeHooksStatus {
+	status := ClaudeHooksStatus{ConfigDir: configDir, State: ClaudeHooksNotInstalled}
+
+	hooks := readClaudeSettingsHooks(configDir)
+	present := false
+	var reasons []string
+	for _, cfg := range hookEventConfigs {
+		raw, ok := hooks[cfg.Event]
+		if !ok || !eventHasAgentDeckHook(raw) {
+			reasons = append(reasons, fmt.Sprintf("%s hook missing", cfg.Event))
+			continue
+		}
+		present = true
+		staleCommand := false
+		for _, cmd := range agentDeckHookCommands(raw) {
+			if cmd != agentDeckHookCommand {
+				staleCommand = true
+				reasons = append(reasons, fmt.Sprintf("%s hook runs %q, want %q", cfg.Event, cmd, agentDeckHookCommand))
+			}
+		}
+		if !staleCommand && !eventHasAgentDeckHookMatchingConfig(raw, cfg.Matcher, cfg.Async) {
+			reasons = append(reasons, fmt.Sprintf("%s hook has stale matcher or async flag", cfg.Event))
+		}
+	}
+	if !present {
+		return status
+	}
+
+	status.State = ClaudeHooksCurrent
+	status.Stamp = ReadClaudeHooksStamp(configDir)
+	switch {
+	case status.Stamp == nil:
+		reasons = append(reasons, "no version stamp (installed before hook versioning)")
+	case status.Stamp.Version < ClaudeHooksVersion:
+		reasons = append(reasons, fmt.Sprintf("stamped hook version %d, this binary installs %d", status.Stamp.Version, ClaudeHooksVersion))
+	case status.Stamp.Version > ClaudeHooksVersion:
+		status.State = ClaudeHooksNewer
+		reasons = append(reasons, fmt.Sprintf("stamped hook version %d by a newer agent-deck, this binary installs %d", status.Stamp.Version, ClaudeHooksVersion))
+	}
+	if len(reasons) > 0 && status.State == ClaudeHooksCurrent {
+		status.State = ClaudeHooksOutdated
+	}
+	status.Reasons = reasons
+	return status
+}
+
+// readClaudeSettingsHooks returns the "hooks" object of configDir's
+// settings.json, or nil when the file or key is missing or malformed.
+func readClaudeSettingsHooks(configDir string) map[string]json.RawMessage {
+	data, err := os.ReadFile(filepath.Join(configDir, "settings.json"))
+	if err != nil {
+		return nil
+	}
+	var rawSettings map[string]json.RawMessage
+	if err := json.Unmarshal(data, &rawSettings); err != nil {
+		return nil
+	}
+	var hooks map[string]json.RawMessage
+	if err := json.Unmarshal(rawSettings["hooks"], &hooks); err != nil {
+		return nil
+	}
+	return hooks
+}
+
+// eventHasAgentDeckHook reports whether any matcher of the event carries an
+// agent-deck hook, whatever its config.
+func eventHasAgentDeckHook(raw json.RawMessage) bool {
+	return len(agentDeckHookCommands(raw)) > 0
+}
+
+// agentDeckHookCommands returns the agent-deck hook commands registered for
+// an event, including ones written with an old binary path or flags.
+func agentDeckHookCommands(raw json.RawMessage) []string {
+	var matchers []claudeHookMatcher
+	if err := json.Unmarshal(raw, &matchers); err != nil {
+		return nil
+	}
+	var cmds []string
+	for _, m := range matchers {
+		for _, h := range m.Hooks {
+			if isAgentDeckHookCommand(h.Command) {
+				cmds = append(cmds, h.Command)
+			}
+		}
+	}
+	return cmds
+}
+
+// isAgentDeckHookCommand matches agent-deck's hook handler however it was
+// invoked: "agent-deck hook-handler", an absolute binary path, or extra flags.
+func isAgentDeckHookCommand(command string) bool {
+	if strings.Contains(command, agentDeckHookCommand) {
+		return true
+	}
+	fields := strings.Fields(command)
+	return len(fields) >= 2 && filepath.Base(fields[0]) == "agent-deck" && fields[1] == "hook-handler"
+}
@@ -0,0 +1,135 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInspectClaudeHooks_NotInstalled(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	status := InspectClaudeHooks(tmpDir)
+	if status.State != ClaudeHooksNotInstalled || status.NeedsSync() {
+		t.Errorf("empty config dir: state=%s needsSync=%v", status.State, status.NeedsSync())
+	}
+}
+
+func TestInspectClaudeHooks_CurrentAfterInstall(t *testing.T) {
+	tmpDir := t.TempDir()
+	if _, err := InjectClaudeHooks(tmpDir); err != nil {
+		t.Fatal(err)
+	}
+
+	status := InspectClaudeHooks(tmpDir)
+	if status.State != ClaudeHooksCurrent {
+		t.Fatalf("state = %s, reasons %v; want current", status.State, status.Reasons)
+	}
+	if status.Stamp == nil || status.Stamp.Version != ClaudeHooksVersion {
+		t.Errorf("stamp = %+v, want version %d", status.Stamp, ClaudeHooksVersion)
+	}
+
+	// A second install is a no-op once stamped.
+	installed, err := InjectClaudeHooks(tmpDir)
+	if err != nil || installed {
+		t.Errorf("re-install = %v, %v; want no-op", installed, err)
+	}
+}
+
+func TestInspectClaudeHooks_StaleCommandAndStamp(t *testing.T) {
+	tmpDir := t.TempDir()
+	settings := `{"model":"opus","hooks":{"Stop":[{"hooks":[` +
+		`{"type":"command","command":"/opt/old/agent-deck hook-handler --legacy"},` +
+		`{"type":"command","command":"my-hook"}]}]}}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "settings.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	status := InspectClaudeHooks(tmpDir)
+	if status.State != ClaudeHooksOutdated || !status.NeedsSync() {
+		t.Fatalf("state = %s, want outdated", status.State)
+	}
+	joined := strings.Join(status.Reasons, "\n")
+	for _, want := range []string{"/opt/old/agent-deck hook-handler --legacy", "no version stamp", "SessionStart hook missing"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("reasons %q missing %q", joined, want)
+		}
+	}
+
+	if _, err := InjectClaudeHooks(tmpDir); err != nil {
+		t.Fatal(err)
+	}
+	if status := InspectClaudeHooks(tmpDir); status.State != ClaudeHooksCurrent {
+		t.Fatalf("after sync: state = %s, reasons %v", status.State, status.Reasons)
+	}
+
+	// The stale command was rewritten in place, the user's hook kept.
+	data, _ := os.ReadFile(filepath.Join(tmpDir, "settings.json"))
+	if strings.Contains(string(data), "/opt/old/") {
+		t.Error("stale hook command survived the sync")
+	}
+	if !strings.Contains(string(data), "my-hook") || !strings.Contains(string(data), `"opus"`) {
+		t.Error("sync dropped user settings or hooks")
+	}
+}
+
+func TestInspectClaudeHooks_StampVersions(t *testing.T) {
+	tmpDir := t.TempDir()
+	if _, err := InjectClaudeHooks(tmpDir); err != nil {
+		t.Fatal(err)
+	}
+	writeStamp := func(version int) {
+		data, _ := json.Marshal(ClaudeHooksStamp{Version: version, Command: agentDeckHookCommand})
+		if err := os.WriteFile(filepath.Join(tmpDir, claudeHooksStampName), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeStamp(ClaudeHooksVersion - 1)
+	if status := InspectClaudeHooks(tmpDir); status.State != ClaudeHooksOutdated {
+		t.Errorf("older stamp: state = %s, want outdated", status.State)
+	}
+
+	writeStamp(ClaudeHooksVersion + 1)
+	if status := InspectClaudeHooks(tmpDir); status.State != ClaudeHooksNewer || !status.NeedsSync() {
+		t.Errorf("newer stamp: state = %s, want newer", status.State)
+	}
+
+	// A foreign stamp makes InjectClaudeHooks rewrite and restamp.
+	installed, err := InjectClaudeHooks(tmpDir)
+	if err != nil || !installed {
+		t.Fatalf("sync over newer stamp = %v, %v; want rewrite", installed, err)
+	}
+	if stamp := ReadClaudeHooksStamp(tmpDir); stamp == nil || stamp.Version != ClaudeHooksVersion {
+		t.Errorf("stamp after sync = %+v", stamp)
+	}
+}
+
+func TestRemoveClaudeHooks_RemovesStamp(t *testing.T) {
+	tmpDir := t.TempDir()
+	if _, err := InjectClaudeHooks(tmpDir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RemoveClaudeHooks(tmpDir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, claudeHooksStampName)); !os.IsNotExist(err) {
+		t.Errorf("stamp left after uninstall: %v", err)
+	}
+}
+
+func TestIsAgentDeckHookCommand(t *testing.T) {
+	for cmd, want := range map[string]bool{
+		"agent-deck hook-handler":                     true,
+		"/usr/local/bin/agent-deck hook-handler":      true,
+		"/home/u/go/bin/agent-deck hook-handler --v2": true,
+		"agent-deck session send":                     false,
+		"my-agent-deck-wrapper hook-handler":          false,
+	} {
+		if got := isAgentDeckHookCommand(cmd); got != want {
+			t.Errorf("isAgentDeckHookCommand(%q) = %v, want %v", cmd, got, want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// CloneInstance creates a fresh session with i's configuration: tool,
+// command, wrapper, tool options, extra args, MCPs, sandbox, layout and the
+// other per-session settings. Nothing tied to i's run is carried over: no
+// conversation or tool session IDs (a resume option becomes a new
+// conversation), no status, notes or pin. projectPath is where the clone
+// runs; empty means i's project. The clone is not in a worktree; callers
+// that create one for it set the worktree fields.
+func (i *Instance) CloneInstance(newTitle, newGroupPath, projectPath string) *Instance {
+	if projectPath == "" {
+		projectPath = i.ProjectPath
+	}
+	clone := NewInstance(newTitle, projectPath)
+	clone.GroupPath = i.GroupPath
+	if newGroupPath != "" {
+		clone.GroupPath = newGroupPath
+	}
+
+	clone.Tool = i.Tool
+	clone.Command = i.Command
+	clone.Wrapper = i.Wrapper
+	clone.ExtraArgs = slices.Clone(i.ExtraArgs)
+	clone.ToolOptionsJSON = freshToolOptions(i.ToolOptionsJSON)
+	clone.Account = i.Account
+	clone.ParentSessionID = i.ParentSessionID
+	clone.ParentProjectPath = i.ParentProjectPath
+	clone.NoTransitionNotify = i.NoTransitionNotify
+	clone.Color = i.Color
+
+	if projectPath == i.ProjectPath {
+		clone.MultiRepoEnabled = i.MultiRepoEnabled
+		clone.AdditionalPaths = slices.Clone(i.AdditionalPaths)
+	}
+
+	clone.GeminiYoloMode = cloneBoolPtr(i.GeminiYoloMode)
+	clone.GeminiModel = i.GeminiModel
+	clone.CopilotModel = i.CopilotModel
+	clone.CopilotAllowAll = i.CopilotAllowAll
+
+	if i.Sandbox != nil {
+		sandbox := *i.Sandbox
+		clone.Sandbox = &sandbox
+	}
+	clone.SSHHost = i.SSHHost
+	clone.SSHRemotePath = i.SSHRemotePath
+	clone.TmuxSocketName = i.TmuxSocketName
+	if clone.tmuxSession != nil {
+		clone.tmuxSession.SocketName = i.TmuxSocketName
+	}
+
+	clone.LoadedMCPNames = slices.Clone(i.LoadedMCPNames)
+	clone.Channels = slices.Clone(i.Channels)
+	clone.Plugins = slices.Clone(i.Plugins)
+	clone.InheritTelegramEnv = i.InheritTelegramEnv
+	clone.PluginChannelLinkDisabled = i.PluginChannelLinkDisabled
+
+	clone.IdleTimeoutSecs = i.IdleTimeoutSecs
+	clone.WorkingHours = i.WorkingHours
+	clone.Checkpoint = i.Checkpoint
+	clone.Labels = slices.Clone(i.Labels)
+	clone.Preset = i.Preset
+	clone.Layout = i.Layout
+	clone.Panes = slices.Clone(i.Panes)
+	clone.DependsOn = slices.Clone(i.DependsOn)
+	clone.ExitToShell = cloneBoolPtr(i.ExitToShell)
+	clone.LaunchShell = cloneBoolPtr(i.LaunchShell)
+
+	return clone
+}
+
+// freshToolOptions returns tool options with the session mode and resume ID
+// dropped, so a clone of a resumed session starts a new conversation.
+// Options that do not parse are returned unchanged.
+func freshToolOptions(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 {
+		return nil
+	}
+	var wrapper ToolOptionsWrapper
+	if err := json.Unmarshal(raw, &wrapper); err != nil {
+		return slices.Clone(raw)
+	}
+	var opts map[string]json.RawMessage
+	if err := json.Unmarshal(wrapper.Options, &opts); err != nil {
+		return slices.Clone(raw)
+	}
+	delete(opts, "session_mode")
+	delete(opts, "resume_session_id")
+	optBytes, err := json.Marshal(opts)
+	if err != nil {
+		return slices.Clone(raw)
+	}
+	wrapper.Options = optBytes
+	out, err := json.Marshal(wrapper)
+	if err != nil {
+		return slices.Clone(raw)
+	}
+	return out
+}
+
+func cloneBoolPtr(b *bool) *bool {
+	if b == nil {
+		return nil
+	}
+	v := *b
+	return &v
+}
+
+// CopyLocalMCPConfig copies the project-local MCP file of tool (.mcp.json,
+// opencode.json, ...) from one project to another, so a clone in a new
+// worktree gets the MCPs attached to its source. It does nothing when the
+// tool has no project-local file, the source has none or the destination
+// already has one. Returns the path written, or "".
+func CopyLocalMCPConfig(tool, fromPath, toPath string) (string, error) {
+	src := MCPLocalConfigPathForTool(tool, fromPath)
+	dst := MCPLocalConfigPathForTool(tool, toPath)
+	if src == "" || dst == "" || src == dst {
+		return "", nil
+	}
+	data, err := os.ReadFile(src)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("read %s: %w", src, err)
+	}
+	if _, err := os.Stat(dst); err == nil {
+		return "", nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(dst, data, 0o644); err != nil {
+		return "", fmt.Errorf("write %s: %w", dst, err)
+	}
+	return dst, nil
+}
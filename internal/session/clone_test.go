@@ -0,0 +1,100 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestCloneInstance_CopiesConfigNotConversation(t *testing.T) {
+	src := NewInstanceWithTool("api", "/tmp/api", "claude")
+	src.GroupPath = "work"
+	src.Command = "claude"
+	src.ExtraArgs = []string{"--verbose"}
+	src.Labels = []string{"backend"}
+	src.LoadedMCPNames = []string{"exa"}
+	src.ClaudeSessionID = "conv-1"
+	src.Notes = "half done"
+	src.Sandbox = &SandboxConfig{Enabled: true, Image: "sandbox:latest"}
+	if err := src.SetClaudeOptions(&ClaudeOptions{SessionMode: "resume", ResumeSessionID: "conv-1", Model: "opus", SkipPermissions: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	clone := src.CloneInstance("api-clone", "", "")
+
+	if clone.ID == src.ID || clone.Title != "api-clone" {
+		t.Errorf("clone has id %q title %q, want a new id and title api-clone", clone.ID, clone.Title)
+	}
+	if clone.ProjectPath != src.ProjectPath || clone.GroupPath != "work" {
+		t.Errorf("clone path/group = %q/%q, want %q/work", clone.ProjectPath, clone.GroupPath, src.ProjectPath)
+	}
+	if clone.Tool != "claude" || !slices.Equal(clone.ExtraArgs, src.ExtraArgs) || !slices.Equal(clone.LoadedMCPNames, src.LoadedMCPNames) || !slices.Equal(clone.Labels, src.Labels) {
+		t.Errorf("clone config not copied: %+v", clone)
+	}
+	if clone.ClaudeSessionID != "" || clone.Notes != "" {
+		t.Errorf("clone carried conversation state: session %q notes %q", clone.ClaudeSessionID, clone.Notes)
+	}
+	if clone.Sandbox == nil || clone.Sandbox == src.Sandbox || clone.Sandbox.Image != "sandbox:latest" {
+		t.Errorf("sandbox not copied by value: %+v", clone.Sandbox)
+	}
+
+	clone.ExtraArgs[0] = "--changed"
+	if src.ExtraArgs[0] != "--verbose" {
+		t.Error("clone's ExtraArgs aliases the source's")
+	}
+
+	opts := clone.GetClaudeOptions()
+	if opts == nil {
+		t.Fatal("clone lost its Claude options")
+	}
+	if opts.SessionMode != "" || opts.ResumeSessionID != "" {
+		t.Errorf("clone still resumes the source conversation: %+v", opts)
+	}
+	if opts.Model != "opus" || !opts.SkipPermissions {
+		t.Errorf("clone lost launch options: %+v", opts)
+	}
+}
+
+func TestCloneInstance_NewProjectPathDropsMultiRepo(t *testing.T) {
+	src := NewInstanceWithTool("multi", "/tmp/a", "claude")
+	src.MultiRepoEnabled = true
+	src.AdditionalPaths = []string{"/tmp/b"}
+
+	clone := src.CloneInstance("multi-clone", "other", "/tmp/a-wt")
+	if clone.ProjectPath != "/tmp/a-wt" || clone.GroupPath != "other" {
+		t.Errorf("clone path/group = %q/%q, want /tmp/a-wt/other", clone.ProjectPath, clone.GroupPath)
+	}
+	if clone.MultiRepoEnabled || len(clone.AdditionalPaths) != 0 {
+		t.Errorf("clone in a new path kept multi-repo paths: %v", clone.AdditionalPaths)
+	}
+}
+
+func TestCopyLocalMCPConfig(t *testing.T) {
+	from := t.TempDir()
+	to := t.TempDir()
+	mcp := []byte(`{"mcpServers":{"exa":{"command":"exa"}}}`)
+	if err := os.WriteFile(filepath.Join(from, ".mcp.json"), mcp, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	written, err := CopyLocalMCPConfig("claude", from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written != filepath.Join(to, ".mcp.json") {
+		t.Errorf("written = %q, want %s", written, filepath.Join(to, ".mcp.json"))
+	}
+	if data, err := os.ReadFile(written); err != nil || string(data) != string(mcp) {
+		t.Errorf("copied .mcp.json = %q, %v", data, err)
+	}
+
+	// An existing destination file is kept.
+	if written, err := CopyLocalMCPConfig("claude", from, to); err != nil || written != "" {
+		t.Errorf("second copy = %q, %v; want no write", written, err)
+	}
+	// Tools without a project-local MCP file are a no-op.
+	if written, err := CopyLocalMCPConfig("codex", from, t.TempDir()); err != nil || written != "" {
+		t.Errorf("codex copy = %q, %v; want no write", written, err)
+	}
+}
@@ -104,6 +104,22 @@ type ConductorSettings struct {
 	// 'conductor migrate-dir'). The bridge daemon similarly freezes
 	// AGENT_DECK_CONDUCTOR_DIR at install time.
 	Dir string `toml:"dir,omitempty"`
+
+	// Dispatch caps how many conductor children may be active per tool and
+	// how many tokens they may spend per day (see conductor_dispatch.go).
+	Dispatch DispatchSettings `toml:"dispatch,omitempty"`
+
+	// Delegation bounds conductor-to-conductor delegation chains (see
+	// conductor_delegation.go).
+	Delegation DelegationSettings `toml:"delegation,omitempty"`
+
+	// Receipts tracks whether conductor children act on delivered prompts
+	// (see conductor_receipts.go).
+	Receipts ReceiptSettings `toml:"receipts,omitempty"`
+
+	// Watchdog restarts errored conductors and a stuck bridge (see
+	// conductor_watchdog.go).
+	Watchdog WatchdogSettings `toml:"watchdog,omitempty"`
 }
 
 // TelegramSettings defines Telegram bot configuration for the conductor bridge
@@ -187,6 +203,11 @@ type ConductorMeta struct {
 	// HeartbeatIdleMinutes is the minutes of inactivity before pausing heartbeats.
 	// 0 or negative = disabled (never pause). Positive = number of minutes.
 	HeartbeatIdleMinutes int `json:"heartbeat_idle_minutes"`
+
+	// Scope is a comma-separated list of group paths the heartbeat checks
+	// (subgroups included). Empty = the group named after the conductor.
+	// See conductor_scope.go.
+	Scope string `json:"scope,omitempty"`
 }
 
 // GetAgent returns the normalized conductor agent, defaulting to Claude.
@@ -758,7 +779,7 @@ func SetupConductorWithAgent(name, profile, agent string, heartbeatEnabled bool,
 
 	// Write meta.json. On a re-run, preserve user-state that setup callers don't
 	// necessarily re-pass: CreatedAt, Description, Env, EnvFile, ClearOnCompact,
-	// HeartbeatInterval and HeartbeatIdleMinutes are kept from the existing meta
+	// HeartbeatInterval, HeartbeatIdleMinutes and Scope are kept from the existing meta
 	// when the corresponding flag is unset, rather than reset to zero.
 	meta := &ConductorMeta{
 		Name:             name,
@@ -784,6 +805,7 @@ func SetupConductorWithAgent(name, profile, agent string, heartbeatEnabled bool,
 			meta.EnvFile = existing.EnvFile
 		}
 		meta.HeartbeatInterval = existing.HeartbeatInterval
+		meta.Scope = existing.Scope
 	} else if heartbeatEnabled {
 		meta.HeartbeatInterval = 15
 	}
@@ -847,13 +869,19 @@ func InstallHeartbeatScript(name, profile string) error {
 	if err != nil {
 		return err
 	}
+	scope := ""
+	if meta, err := LoadConductorMeta(name); err == nil {
+		scope = meta.Scope
+	}
 	scriptPath := filepath.Join(dir, "heartbeat.sh")
-	return os.WriteFile(scriptPath, []byte(renderConductorHeartbeatScript(name, profile)), 0o755)
+	return os.WriteFile(scriptPath, []byte(renderConductorHeartbeatScript(name, profile, scope)), 0o755)
 }
 
-func renderConductorHeartbeatScript(name, profile string) string {
+func renderConductorHeartbeatScript(name, profile, scope string) string {
 	profile = normalizeConductorProfile(profile)
-	script := strings.ReplaceAll(conductorHeartbeatScript, "{NAME}", name)
+	prompt := ConductorHeartbeatPrompt(&ConductorMeta{Name: name, Scope: scope})
+	script := strings.ReplaceAll(conductorHeartbeatScript, "{HEARTBEAT_PROMPT}", shellDoubleQuotedValue(prompt))
+	script = strings.ReplaceAll(script, "{NAME}", name)
 	script = strings.ReplaceAll(script, "{PROFILE}", profile)
 	script = strings.ReplaceAll(script, "{HEARTBEAT_PREFIX}", ConductorBridgeHeartbeatPrefix)
 	conductorRoot := "$HOME/.agent-deck/conductor"
@@ -1133,7 +1161,7 @@ for candidate in \
     fi
 done
 
-MSG="{HEARTBEAT_PREFIX} Check sessions in your group ({NAME}). List any that are waiting, auto-respond where safe, and report what needs my attention."
+MSG="{HEARTBEAT_PREFIX} {HEARTBEAT_PROMPT}"
 if [ -n "$RULES_FILE" ]; then
     RULES=$(cat "$RULES_FILE")
     if [ -n "$RULES" ]; then
@@ -1579,7 +1607,7 @@ func MigrateConductorHeartbeatScripts() ([]string, error) {
 		}
 
 		scriptPath := filepath.Join(dir, "heartbeat.sh")
-		expected := renderConductorHeartbeatScript(meta.Name, meta.Profile)
+		expected := renderConductorHeartbeatScript(meta.Name, meta.Profile, meta.Scope)
 
 		existing, err := os.ReadFile(scriptPath)
 		if err != nil {
@@ -1680,13 +1708,17 @@ func GenerateLaunchdPlist() (string, error) {
 		return "", err
 	}
 
-	// Find python3
-	python3Path := findPython3()
-	if python3Path == "" {
-		return "", fmt.Errorf("python3 not found in PATH")
+	command, err := bridgeCommand(condDir)
+	if err != nil {
+		return "", err
+	}
+	var programArgs strings.Builder
+	for i, arg := range command {
+		if i > 0 {
+			programArgs.WriteString("\n")
+		}
+		programArgs.WriteString("        <string>" + arg + "</string>")
 	}
-
-	bridgePath := filepath.Join(condDir, "bridge.py")
 	logPath := filepath.Join(condDir, "bridge.log")
 
 	dataBase, configBase, err := bridgeXDGBaseDirs()
@@ -1694,8 +1726,7 @@ func GenerateLaunchdPlist() (string, error) {
 		return "", err
 	}
 
-	plist := strings.ReplaceAll(conductorPlistTemplate, "__PYTHON3__", python3Path)
-	plist = strings.ReplaceAll(plist, "__BRIDGE_PATH__", bridgePath)
+	plist := strings.ReplaceAll(conductorPlistTemplate, "__PROGRAM_ARGUMENTS__", programArgs.String())
 	plist = strings.ReplaceAll(plist, "__LOG_PATH__", logPath)
 	plist = strings.ReplaceAll(plist, "__HOME__", homeDir)
 	plist = strings.ReplaceAll(plist, "__XDG_DATA_HOME__", dataBase)
@@ -1716,6 +1747,40 @@ func LaunchdPlistPath() (string, error) {
 	return filepath.Join(homeDir, "Library", "LaunchAgents", LaunchdPlistName+".plist"), nil
 }
 
+// BridgeUsesPython reports whether the bridge daemon still has to run the
+// Python bridge.py. Telegram and Slack are served by the native
+// `agent-deck bridge`; only Discord needs the Python bridge.
+func BridgeUsesPython() bool {
+	return GetConductorSettings().Discord.BotToken != ""
+}
+
+// bridgeCommand returns the program and arguments the bridge daemon runs:
+// `agent-deck bridge`, or python3 bridge.py when Discord is configured.
+func bridgeCommand(condDir string) ([]string, error) {
+	if BridgeUsesPython() {
+		python3Path := findPython3()
+		if python3Path == "" {
+			return nil, fmt.Errorf("python3 not found in PATH (the Discord bridge needs it)")
+		}
+		return []string{python3Path, filepath.Join(condDir, "bridge.py")}, nil
+	}
+	agentDeck := FindAgentDeck()
+	if agentDeck == "" {
+		agentDeck = "agent-deck"
+	}
+	return []string{agentDeck, "bridge"}, nil
+}
+
+// BridgeManualCommand is the command line that runs the bridge in the
+// foreground, for hints when no daemon could be installed.
+func BridgeManualCommand() string {
+	if BridgeUsesPython() {
+		condDir, _ := ConductorDir()
+		return "python3 " + filepath.Join(condDir, "bridge.py")
+	}
+	return "agent-deck bridge"
+}
+
 // findPython3 resolves python3 for daemon configs.
 // Prefer the conductor venv (has required deps like toml), then the current
 // PATH (so pyenv/asdf-selected interpreters win), then common absolute paths.
@@ -1759,8 +1824,7 @@ const conductorPlistTemplate = `<?xml version="1.0" encoding="UTF-8"?>
 
     <key>ProgramArguments</key>
     <array>
-        <string>__PYTHON3__</string>
-        <string>__BRIDGE_PATH__</string>
+__PROGRAM_ARGUMENTS__
     </array>
 
     <key>RunAtLoad</key>
@@ -1852,7 +1916,7 @@ After=network.target
 [Service]
 Type=simple
 ExecStartPre=-/bin/mkdir -p "__LOG_DIR__"
-ExecStart="__PYTHON3__" "__BRIDGE_PATH__"
+ExecStart=__EXEC_START__
 Restart=always
 RestartSec=10
 WorkingDirectory=__HOME__
@@ -1986,11 +2050,19 @@ func GenerateSystemdBridgeService() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	python3Path := findPython3()
-	if python3Path == "" {
-		return "", fmt.Errorf("python3 not found in PATH")
+	command, err := bridgeCommand(condDir)
+	if err != nil {
+		return "", err
+	}
+	// Quote the program and paths: systemd splits ExecStart on unquoted
+	// whitespace.
+	execStart := make([]string, len(command))
+	for i, arg := range command {
+		if i == 0 || filepath.IsAbs(arg) {
+			arg = `"` + arg + `"`
+		}
+		execStart[i] = arg
 	}
-	bridgePath := filepath.Join(condDir, "bridge.py")
 	logPath := filepath.Join(condDir, "bridge.log")
 
 	dataBase, configBase, err := bridgeXDGBaseDirs()
@@ -2003,8 +2075,7 @@ func GenerateSystemdBridgeService() (string, error) {
 		"\n" + `Environment="XDG_CONFIG_HOME=` + configBase + `"` +
 		"\n" + `Environment="AGENT_DECK_CONDUCTOR_DIR=` + condDir + `"`
 
-	unit := strings.ReplaceAll(systemdBridgeServiceTemplate, "__PYTHON3__", python3Path)
-	unit = strings.ReplaceAll(unit, "__BRIDGE_PATH__", bridgePath)
+	unit := strings.ReplaceAll(systemdBridgeServiceTemplate, "__EXEC_START__", strings.Join(execStart, " "))
 	unit = strings.ReplaceAll(unit, "__LOG_PATH__", logPath)
 	unit = strings.ReplaceAll(unit, "__LOG_DIR__", filepath.Dir(logPath))
 	unit = strings.ReplaceAll(unit, "__HOME__", homeDir)
@@ -2133,8 +2204,7 @@ func InstallBridgeDaemon() (string, error) {
 	case platform.PlatformLinux, platform.PlatformWSL2:
 		return installBridgeDaemonSystemd()
 	default:
-		condDir, _ := ConductorDir()
-		return "", fmt.Errorf("unsupported platform %s for daemon management; run manually: python3 %s/bridge.py", plat, condDir)
+		return "", fmt.Errorf("unsupported platform %s for daemon management; run manually: %s", plat, BridgeManualCommand())
 	}
 }
 
@@ -2184,8 +2254,7 @@ func installBridgeDaemonSystemd() (string, error) {
 		return "", fmt.Errorf("failed to write systemd unit: %w", err)
 	}
 	if !systemdUserAvailable() {
-		condDir, _ := ConductorDir()
-		return "", fmt.Errorf("systemd user session not available (common in containers/VMs without lingering); run manually: python3 %s/bridge.py", condDir)
+		return "", fmt.Errorf("systemd user session not available (common in containers/VMs without lingering); run manually: %s", BridgeManualCommand())
 	}
 	if err := exec.Command("systemctl", "--user", "enable", "--now", systemdBridgeServiceName).Run(); err != nil {
 		return unitPath, fmt.Errorf("unit written but enable failed: %w", err)
@@ -2429,9 +2498,8 @@ func BridgeDaemonHint() string {
 		}
 		return "Run 'agent-deck conductor setup <name>' to install the daemon"
 	case platform.PlatformLinux, platform.PlatformWSL2:
-		condDir, _ := ConductorDir()
 		if !systemdUserAvailable() {
-			return fmt.Sprintf("Run manually: python3 %s/bridge.py", condDir)
+			return "Run manually: " + BridgeManualCommand()
 		}
 		unitPath, err := SystemdBridgeServicePath()
 		if err == nil {
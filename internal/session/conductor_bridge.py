@@ -124,6 +124,10 @@ CONDUCTOR_DIR = Path(os.path.expanduser(_override)) if _override else resolve_da
 CONFIG_PATH = resolve_config_path("config.toml")
 # --- end issue #1350 resolver ---
 LOG_PATH = CONDUCTOR_DIR / "bridge.log"
+# Touched every ALIVE_INTERVAL seconds while the event loop makes progress; the
+# conductor watchdog restarts the bridge when it goes stale (conductor_watchdog.go).
+ALIVE_PATH = CONDUCTOR_DIR / "bridge.alive"
+ALIVE_INTERVAL = 60
 
 # Telegram message length limit
 TG_MAX_LENGTH = 4096
@@ -335,6 +339,26 @@ def get_unique_profiles() -> list[str]:
     return sorted(profiles)
 
 
+def conductor_scope_groups(conductor: dict) -> list[str]:
+    """Group paths a conductor's heartbeat covers (subgroups included).
+
+    meta.json "scope" is a comma-separated list of group paths; when unset the
+    conductor watches the group named after itself. Mirrors
+    ConductorMeta.ScopeGroups in conductor_scope.go.
+    """
+    groups = []
+    for part in str(conductor.get("scope") or "").split(","):
+        path = part.strip().strip("/")
+        if path:
+            groups.append(path)
+    return groups or [conductor.get("name", "")]
+
+
+def in_conductor_scope(groups: list[str], group_path: str) -> bool:
+    """True when group_path is one of groups or nested under one."""
+    return any(group_path == g or group_path.startswith(f"{g}/") for g in groups)
+
+
 def select_heartbeat_conductors(conductors: list[dict]) -> list[dict]:
     """Select all heartbeat-enabled conductors in deterministic order."""
     enabled = [c for c in conductors if c.get("heartbeat_enabled", True)]
@@ -2741,6 +2765,16 @@ def _os_heartbeat_daemon_installed() -> bool:
     return False
 
 
+async def liveness_loop():
+    """Touch ALIVE_PATH while the event loop runs, so a wedged bridge shows."""
+    while True:
+        try:
+            ALIVE_PATH.touch()
+        except OSError as e:
+            log.warning("Failed to touch %s: %s", ALIVE_PATH, e)
+        await asyncio.sleep(ALIVE_INTERVAL)
+
+
 async def heartbeat_loop(
     config: dict, telegram_bot=None, slack_app=None, slack_channel_id=None,
     discord_bot=None, discord_channel_id=None,
@@ -2779,8 +2813,10 @@ async def heartbeat_loop(
 
                 session_title = conductor_session_title(name)
 
-                # Scope heartbeat monitoring to this conductor's own group
-                # (mirrors the deployed bridge: per-conductor, not profile-wide).
+                # Scope heartbeat monitoring to this conductor's groups: its
+                # own group by default, or meta.json "scope" when set
+                # (per-conductor, not profile-wide).
+                scope_groups = conductor_scope_groups(conductor)
                 sessions = get_sessions_list(profile)
                 scoped_sessions = []
                 for s in sessions:
@@ -2788,7 +2824,7 @@ async def heartbeat_loop(
                     s_group = s.get("group", "") or ""
                     if s_title.startswith("conductor-"):
                         continue
-                    if s_group != name and not s_group.startswith(f"{name}/"):
+                    if not in_conductor_scope(scope_groups, s_group):
                         continue
                     scoped_sessions.append(s)
 
@@ -2823,6 +2859,11 @@ async def heartbeat_loop(
                     f"[HEARTBEAT] [{name}] Status: {waiting} waiting, "
                     f"{running} running, {idle} idle, {error} error, {stopped} stopped."
                 ]
+                if conductor.get("scope"):
+                    parts.append(
+                        f"Scope: {', '.join(scope_groups)} (subgroups included); "
+                        "leave all other sessions alone."
+                    )
                 if waiting_details:
                     parts.append(f"Waiting sessions: {', '.join(waiting_details)}.")
                 if error_details:
@@ -3102,7 +3143,8 @@ async def main():
     )
 
     # Run all concurrently
-    tasks = [heartbeat_task]
+    liveness_task = asyncio.create_task(liveness_loop())
+    tasks = [heartbeat_task, liveness_task]
     if telegram_dp and telegram_bot:
         tasks.append(asyncio.create_task(_run_platform_task(
             "Telegram polling",
@@ -3125,6 +3167,7 @@ async def main():
         await asyncio.gather(*tasks)
     finally:
         heartbeat_task.cancel()
+        liveness_task.cancel()
         if telegram_bot:
             await telegram_bot.session.close()
         if slack_handler:
@@ -0,0 +1,158 @@
+package session
+
+// Conductor-to-conductor delegation.
+//
+// A conductor can hand a task to another conductor (for example a profile
+// conductor handing reviews to a dedicated review conductor) with
+// `agent-deck conductor delegate <from> <to> <task>`. The delegation is
+// recorded in the target conductor's profile statedb and delivered to its
+// session as a structured message (FormatDelegationMessage) that tells the
+// receiver how to report back or delegate further.
+//
+// Every delegation carries its chain: the conductor names from the root
+// delegator to the receiver. A delegation that would hand work back to a
+// conductor already in the chain — or to one that is still working on a
+// task it received from the chain — is refused, as is a chain deeper than
+// [conductor.delegation] max_depth.
+//
+//	[conductor.delegation]
+//	max_depth = 3
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// DelegationSettings bounds delegation chains.
+type DelegationSettings struct {
+	// MaxDepth is the number of hand-offs allowed from the root delegator
+	// (default: 3; a→b→c→d is depth 3).
+	MaxDepth int `toml:"max_depth,omitzero"`
+}
+
+// GetMaxDepth returns the configured chain depth limit (default: 3).
+func (d DelegationSettings) GetMaxDepth() int {
+	if d.MaxDepth <= 0 {
+		return 3
+	}
+	return d.MaxDepth
+}
+
+// Delegation statuses. Pending and delivered delegations are open: they count
+// toward loop detection and are listed by conductor status.
+const (
+	DelegationPending   = "pending"
+	DelegationDelivered = "delivered"
+	DelegationDone      = "done"
+	DelegationFailed    = "failed"
+)
+
+// OpenDelegationStatuses are the statuses of delegations still in flight.
+var OpenDelegationStatuses = []string{DelegationPending, DelegationDelivered}
+
+// ErrDelegationLoop is returned by PlanDelegation when the target conductor is
+// already part of the chain.
+var ErrDelegationLoop = errors.New("delegation loop")
+
+// PlanDelegation validates a delegation from → to and returns the row to
+// record. parent is the delegation from is working on (may be nil); when
+// nil, the newest of inbound — the open delegations addressed to from — is
+// used, so a conductor that forgets --parent still extends its chain.
+// The target is refused when it appears in the parent chain or in the chain
+// of any open inbound delegation.
+func PlanDelegation(from, to, task string, parent *statedb.DelegationRow, inbound []*statedb.DelegationRow, maxDepth int) (*statedb.DelegationRow, error) {
+	for _, name := range []string{from, to} {
+		if err := ValidateConductorName(name); err != nil {
+			return nil, err
+		}
+	}
+	task = strings.TrimSpace(task)
+	if task == "" {
+		return nil, fmt.Errorf("delegation task is empty")
+	}
+	if from == to {
+		return nil, fmt.Errorf("%w: %s cannot delegate to itself", ErrDelegationLoop, from)
+	}
+	if parent == nil && len(inbound) > 0 {
+		parent = inbound[len(inbound)-1]
+	}
+
+	chain := []string{from}
+	parentID := ""
+	if parent != nil {
+		if parent.To != from {
+			return nil, fmt.Errorf("delegation %s was handed to %s, not %s", parent.ID, parent.To, from)
+		}
+		if !slices.Contains(OpenDelegationStatuses, parent.Status) {
+			return nil, fmt.Errorf("delegation %s is already %s", parent.ID, parent.Status)
+		}
+		chain = slices.Clone(parent.Chain)
+		parentID = parent.ID
+	}
+
+	seen := slices.Clone(chain)
+	for _, d := range inbound {
+		seen = append(seen, d.Chain...)
+	}
+	if slices.Contains(seen, to) {
+		return nil, fmt.Errorf("%w: %s is already working on this chain (%s)", ErrDelegationLoop, to, FormatDelegationChain(append(chain, to)))
+	}
+	chain = append(chain, to)
+	if depth := len(chain) - 1; depth > maxDepth {
+		return nil, fmt.Errorf("delegation chain %s is %d deep, max_depth is %d", FormatDelegationChain(chain), depth, maxDepth)
+	}
+
+	now := time.Now()
+	return &statedb.DelegationRow{
+		ID:        "dlg-" + randomString(8),
+		ParentID:  parentID,
+		From:      from,
+		To:        to,
+		Chain:     chain,
+		Task:      task,
+		Status:    DelegationPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// FormatDelegationChain renders a chain as "a → b → c".
+func FormatDelegationChain(chain []string) string {
+	return strings.Join(chain, " → ")
+}
+
+// delegationHeaderPrefix starts the first line of every delegation message so
+// a conductor can tell one apart from a human message at a glance.
+const delegationHeaderPrefix = "[DELEGATION "
+
+// FormatDelegationMessage renders the message sent to the receiving
+// conductor: a one-line machine-readable header, the task, and the commands
+// for reporting back and delegating further.
+func FormatDelegationMessage(d *statedb.DelegationRow) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s%s] from=%s to=%s chain=%s\n", delegationHeaderPrefix, d.ID, d.From, d.To, strings.Join(d.Chain, ">"))
+	b.WriteString(d.Task)
+	b.WriteString("\n\n")
+	fmt.Fprintf(&b, "When finished: agent-deck conductor delegation done %s --result \"<summary>\"\n", d.ID)
+	fmt.Fprintf(&b, "If you cannot do it: agent-deck conductor delegation done %s --failed --result \"<reason>\"\n", d.ID)
+	fmt.Fprintf(&b, "To hand part of it on: agent-deck conductor delegate %s <conductor> \"<task>\" --parent %s", d.To, d.ID)
+	return b.String()
+}
+
+// FormatDelegationResultMessage renders the message sent back to the
+// delegating conductor when a delegation is closed.
+func FormatDelegationResultMessage(d *statedb.DelegationRow) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s%s %s] from=%s to=%s chain=%s\n", delegationHeaderPrefix, d.ID, strings.ToUpper(d.Status), d.To, d.From, strings.Join(d.Chain, ">"))
+	if d.Result != "" {
+		b.WriteString(d.Result)
+	} else {
+		fmt.Fprintf(&b, "%s reported no result.", d.To)
+	}
+	return b.String()
+}
@@ -0,0 +1,86 @@
+package session
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+func TestPlanDelegation_RootAndChild(t *testing.T) {
+	root, err := PlanDelegation("ops", "review", "  review PR 12  ", nil, nil, 3)
+	if err != nil {
+		t.Fatalf("root: %v", err)
+	}
+	if !strings.HasPrefix(root.ID, "dlg-") || root.Status != DelegationPending || root.Task != "review PR 12" {
+		t.Errorf("root = %+v", root)
+	}
+	if !slices.Equal(root.Chain, []string{"ops", "review"}) {
+		t.Errorf("root chain = %v", root.Chain)
+	}
+
+	// review forgot --parent: the open inbound delegation is used.
+	root.Status = DelegationDelivered
+	child, err := PlanDelegation("review", "qa", "run e2e", nil, []*statedb.DelegationRow{root}, 3)
+	if err != nil {
+		t.Fatalf("child: %v", err)
+	}
+	if child.ParentID != root.ID || !slices.Equal(child.Chain, []string{"ops", "review", "qa"}) {
+		t.Errorf("child = %+v", child)
+	}
+}
+
+func TestPlanDelegation_RefusesLoops(t *testing.T) {
+	if _, err := PlanDelegation("ops", "ops", "x", nil, nil, 3); !errors.Is(err, ErrDelegationLoop) {
+		t.Errorf("self delegation err = %v, want ErrDelegationLoop", err)
+	}
+
+	parent := &statedb.DelegationRow{ID: "dlg-1", From: "ops", To: "review", Chain: []string{"ops", "review"}, Status: DelegationDelivered}
+	if _, err := PlanDelegation("review", "ops", "x", parent, nil, 3); !errors.Is(err, ErrDelegationLoop) {
+		t.Errorf("back to root err = %v, want ErrDelegationLoop", err)
+	}
+
+	// An explicit parent does not hide another open inbound chain.
+	other := &statedb.DelegationRow{ID: "dlg-2", From: "infra", To: "review", Chain: []string{"infra", "review"}, Status: DelegationPending}
+	if _, err := PlanDelegation("review", "infra", "x", parent, []*statedb.DelegationRow{parent, other}, 3); !errors.Is(err, ErrDelegationLoop) {
+		t.Errorf("into another inbound chain err = %v, want ErrDelegationLoop", err)
+	}
+}
+
+func TestPlanDelegation_DepthAndParentChecks(t *testing.T) {
+	parent := &statedb.DelegationRow{ID: "dlg-1", From: "a", To: "b", Chain: []string{"a", "b"}, Status: DelegationDelivered}
+	if _, err := PlanDelegation("b", "c", "x", parent, nil, 1); err == nil || !strings.Contains(err.Error(), "max_depth is 1") {
+		t.Errorf("depth err = %v", err)
+	}
+	if _, err := PlanDelegation("c", "d", "x", parent, nil, 3); err == nil {
+		t.Error("expected error when parent was handed to another conductor")
+	}
+	parent.Status = DelegationDone
+	if _, err := PlanDelegation("b", "c", "x", parent, nil, 3); err == nil {
+		t.Error("expected error for a closed parent")
+	}
+	if _, err := PlanDelegation("a", "b", "   ", nil, nil, 3); err == nil {
+		t.Error("expected error for an empty task")
+	}
+}
+
+func TestFormatDelegationMessages(t *testing.T) {
+	d := &statedb.DelegationRow{ID: "dlg-1", From: "ops", To: "review", Chain: []string{"ops", "review"}, Task: "review PR 12"}
+	msg := FormatDelegationMessage(d)
+	for _, want := range []string{
+		"[DELEGATION dlg-1] from=ops to=review chain=ops>review\nreview PR 12",
+		"conductor delegation done dlg-1 --result",
+		"conductor delegate review <conductor> \"<task>\" --parent dlg-1",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message missing %q:\n%s", want, msg)
+		}
+	}
+
+	d.Status, d.Result = DelegationDone, "approved"
+	if got := FormatDelegationResultMessage(d); got != "[DELEGATION dlg-1 DONE] from=review to=ops chain=ops>review\napproved" {
+		t.Errorf("result message = %q", got)
+	}
+}
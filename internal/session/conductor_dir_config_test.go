@@ -152,7 +152,7 @@ func TestRenderConductorHeartbeatScript_UsesConfigOverrideConductorRoot(t *testi
 	override := filepath.Join(t.TempDir(), "conductor homes")
 	writeConductorDirConfig(t, xdgConfigHome, override)
 
-	script := renderConductorHeartbeatScript("alpha", "work")
+	script := renderConductorHeartbeatScript("alpha", "work", "")
 
 	if !strings.Contains(script, `CONDUCTOR_ROOT="`+override+`"`) {
 		t.Fatalf("heartbeat script should render override conductor root %q:\n%s", override, script)
@@ -217,6 +217,53 @@ func TestGenerateSystemdBridgeService_InjectsConductorDirOverride(t *testing.T)
 	if !strings.Contains(unit, want) {
 		t.Errorf("systemd bridge unit should contain %q, unit:\n%s", want, unit)
 	}
+}
+
+// TestGenerateSystemdBridgeService_NativeUnlessDiscord pins which bridge the
+// daemon runs: the native `agent-deck bridge` for Telegram/Slack, and
+// bridge.py (quoted, under the space-bearing conductor dir) only when Discord
+// is configured.
+func TestGenerateSystemdBridgeService_NativeUnlessDiscord(t *testing.T) {
+	_, xdgConfigHome, _ := setupSessionXDGPathEnv(t)
+
+	override := filepath.Join(t.TempDir(), "conductor homes", "conductor")
+	writeConductorDirConfig(t, xdgConfigHome, override)
+
+	unit, err := GenerateSystemdBridgeService()
+	if err != nil {
+		t.Fatalf("GenerateSystemdBridgeService(): %v", err)
+	}
+	execLine := ""
+	for _, line := range strings.Split(unit, "\n") {
+		if strings.HasPrefix(line, "ExecStart=") {
+			execLine = line
+		}
+	}
+	if !strings.HasSuffix(execLine, " bridge") || strings.Contains(unit, "bridge.py") {
+		t.Errorf("without Discord the unit should run `agent-deck bridge`, got %q", execLine)
+	}
+	plist, err := GenerateLaunchdPlist()
+	if err != nil {
+		t.Fatalf("GenerateLaunchdPlist(): %v", err)
+	}
+	if !strings.Contains(plist, "        <string>bridge</string>\n    </array>") {
+		t.Errorf("without Discord the plist should run `agent-deck bridge`:\n%s", plist)
+	}
+
+	cfgPath := filepath.Join(xdgConfigHome, "agent-deck", "config.toml")
+	cfg := "[conductor]\ndir = \"" + override + "\"\n\n[conductor.discord]\nbot_token = \"tok\"\n"
+	if err := os.WriteFile(cfgPath, []byte(cfg), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ClearUserConfigCache()
+
+	unit, err = GenerateSystemdBridgeService()
+	if err != nil {
+		if strings.Contains(err.Error(), "not found in PATH") {
+			t.Skipf("skipping: %v", err)
+		}
+		t.Fatalf("GenerateSystemdBridgeService(): %v", err)
+	}
 	// The bridge.py path lives under the space-bearing override, so the ExecStart
 	// argument must be quoted too — otherwise systemd would split it.
 	wantExec := `"` + filepath.Join(override, "bridge.py") + `"`
@@ -244,7 +291,7 @@ func TestSystemdUnits_QuoteEnvironmentAndExecStart(t *testing.T) {
 		`Environment="XDG_DATA_HOME=`,
 		`Environment="XDG_CONFIG_HOME=`,
 		`Environment="AGENT_DECK_CONDUCTOR_DIR=`,
-		"ExecStart=\"", // quoted python3 executable + bridge path
+		"ExecStart=\"", // quoted bridge executable
 	} {
 		if !strings.Contains(bridge, want) {
 			t.Errorf("systemd bridge unit must contain %q, unit:\n%s", want, bridge)
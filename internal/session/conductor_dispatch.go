@@ -0,0 +1,244 @@
+package session
+
+// Conductor dispatch capacity ([conductor.dispatch]).
+//
+// A conductor fans work out by launching child sessions with itself as the
+// parent. Without a limit a single heartbeat can wake every worker at once,
+// which is the same burst the group max_concurrent cap (group_concurrency.go)
+// exists to prevent — but across groups, since conductor children land in
+// their project groups (#972). Dispatch limits cap how many conductor
+// children may be active in total and per tool, and how many tokens a tool's
+// conductor children may spend per UTC day. A launch over a limit is queued
+// (StatusQueued) with a priority and its initial message, and is drained
+// highest-priority first as capacity frees up.
+//
+// Example:
+//
+//	[conductor.dispatch]
+//	max_active = 6
+//
+//	[conductor.dispatch.tools.claude]
+//	max_active = 3
+//	daily_token_budget = 5000000
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DispatchSettings defines conductor dispatch limits. Zero values mean
+// unlimited, so an absent section keeps the pre-dispatch behavior.
+type DispatchSettings struct {
+	// MaxActive caps active conductor children across all tools.
+	MaxActive int `toml:"max_active,omitzero"`
+
+	// Tools holds per-tool limits keyed by tool name (claude, codex, ...).
+	Tools map[string]ToolDispatchLimits `toml:"tools,omitempty"`
+}
+
+// ToolDispatchLimits defines the limits for one tool.
+type ToolDispatchLimits struct {
+	// MaxActive caps active conductor children running this tool.
+	MaxActive int `toml:"max_active,omitzero"`
+
+	// DailyTokenBudget caps input+output tokens recorded by the cost
+	// tracker for this tool's conductor children since 00:00 UTC.
+	DailyTokenBudget int64 `toml:"daily_token_budget,omitzero"`
+}
+
+// Enabled reports whether any dispatch limit is configured.
+func (d DispatchSettings) Enabled() bool {
+	if d.MaxActive > 0 {
+		return true
+	}
+	for _, l := range d.Tools {
+		if l.MaxActive > 0 || l.DailyTokenBudget > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ToolLimits returns the limits for tool (case-insensitive), or zero limits.
+func (d DispatchSettings) ToolLimits(tool string) ToolDispatchLimits {
+	tool = strings.ToLower(strings.TrimSpace(tool))
+	for name, l := range d.Tools {
+		if strings.ToLower(name) == tool {
+			return l
+		}
+	}
+	return ToolDispatchLimits{}
+}
+
+// DispatchRequest is the persisted state of a deferred conductor dispatch.
+// It is set while the session is queued and cleared once it starts.
+type DispatchRequest struct {
+	Priority   int       `json:"priority,omitempty"`
+	Message    string    `json:"message,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	DeferredAt time.Time `json:"deferred_at,omitempty"`
+}
+
+// DispatchDecision records why a dispatch was allowed or deferred. It is
+// returned by CheckDispatchCapacity and surfaced by launch and
+// `conductor status`.
+type DispatchDecision struct {
+	Allowed       bool   `json:"allowed"`
+	Tool          string `json:"tool"`
+	Reason        string `json:"reason,omitempty"`
+	Active        int    `json:"active"`
+	MaxActive     int    `json:"max_active,omitempty"`
+	ToolActive    int    `json:"tool_active"`
+	ToolMaxActive int    `json:"tool_max_active,omitempty"`
+	TokensToday   int64  `json:"tokens_today,omitempty"`
+	TokenBudget   int64  `json:"daily_token_budget,omitempty"`
+}
+
+// isConductorParent reports whether parentID names a conductor session.
+func isConductorParent(parentID string, byID map[string]*Instance) bool {
+	if parentID == "" {
+		return false
+	}
+	parent, ok := byID[parentID]
+	return ok && parent != nil && isConductorSessionTitle(parent.Title)
+}
+
+// ConductorDispatches returns the instances whose parent is a conductor
+// session.
+func ConductorDispatches(instances []*Instance) []*Instance {
+	byID := make(map[string]*Instance, len(instances))
+	for _, inst := range instances {
+		if inst != nil {
+			byID[inst.ID] = inst
+		}
+	}
+	var out []*Instance
+	for _, inst := range instances {
+		if inst != nil && isConductorParent(inst.ParentSessionID, byID) {
+			out = append(out, inst)
+		}
+	}
+	return out
+}
+
+// IsConductorDispatch reports whether inst is a conductor child.
+func IsConductorDispatch(inst *Instance, instances []*Instance) bool {
+	if inst == nil {
+		return false
+	}
+	for _, d := range ConductorDispatches(instances) {
+		if d == inst {
+			return true
+		}
+	}
+	return false
+}
+
+// dispatchIsActive reports whether a conductor child occupies a slot. Like
+// the group cap, only sessions doing work count: running, plus starting so
+// a burst of launches cannot all pass the check before any reaches running.
+func dispatchIsActive(inst *Instance) bool {
+	return inst.Status == StatusRunning || inst.Status == StatusStarting
+}
+
+// CountActiveDispatches returns the number of active conductor children in
+// total and per tool (lowercased).
+func CountActiveDispatches(instances []*Instance) (int, map[string]int) {
+	total := 0
+	byTool := map[string]int{}
+	for _, inst := range ConductorDispatches(instances) {
+		if !dispatchIsActive(inst) {
+			continue
+		}
+		total++
+		byTool[strings.ToLower(inst.Tool)]++
+	}
+	return total, byTool
+}
+
+// DispatchSessionIDs returns the IDs of conductor children running tool,
+// whatever their status. Callers sum their recorded tokens for the budget.
+func DispatchSessionIDs(instances []*Instance, tool string) []string {
+	tool = strings.ToLower(tool)
+	var ids []string
+	for _, inst := range ConductorDispatches(instances) {
+		if strings.ToLower(inst.Tool) == tool {
+			ids = append(ids, inst.ID)
+		}
+	}
+	return ids
+}
+
+// CheckDispatchCapacity decides whether a conductor child running tool may
+// start now. instances must not include the candidate in an active state.
+// tokensToday is the tool's token usage since 00:00 UTC; it is only
+// consulted when the tool has a daily budget.
+func CheckDispatchCapacity(settings DispatchSettings, instances []*Instance, tool string, tokensToday int64) DispatchDecision {
+	limits := settings.ToolLimits(tool)
+	total, byTool := CountActiveDispatches(instances)
+	d := DispatchDecision{
+		Allowed:       true,
+		Tool:          tool,
+		Active:        total,
+		MaxActive:     settings.MaxActive,
+		ToolActive:    byTool[strings.ToLower(tool)],
+		ToolMaxActive: limits.MaxActive,
+		TokenBudget:   limits.DailyTokenBudget,
+	}
+	if limits.DailyTokenBudget > 0 {
+		d.TokensToday = tokensToday
+	}
+	switch {
+	case limits.DailyTokenBudget > 0 && tokensToday >= limits.DailyTokenBudget:
+		d.Allowed = false
+		d.Reason = fmt.Sprintf("%s daily token budget spent (%d/%d)", tool, tokensToday, limits.DailyTokenBudget)
+	case IsAtCap(d.ToolActive, limits.MaxActive):
+		d.Allowed = false
+		d.Reason = fmt.Sprintf("%s at capacity (%d/%d active)", tool, d.ToolActive, limits.MaxActive)
+	case IsAtCap(total, settings.MaxActive):
+		d.Allowed = false
+		d.Reason = fmt.Sprintf("conductor dispatch at capacity (%d/%d active)", total, settings.MaxActive)
+	}
+	return d
+}
+
+// QueuedDispatches returns the deferred conductor dispatches in drain order:
+// highest priority first, then oldest first.
+func QueuedDispatches(instances []*Instance) []*Instance {
+	var queued []*Instance
+	for _, inst := range instances {
+		if inst != nil && inst.Status == StatusQueued && inst.Dispatch != nil {
+			queued = append(queued, inst)
+		}
+	}
+	sort.SliceStable(queued, func(a, b int) bool {
+		if queued[a].Dispatch.Priority != queued[b].Dispatch.Priority {
+			return queued[a].Dispatch.Priority > queued[b].Dispatch.Priority
+		}
+		return queued[a].CreatedAt.Before(queued[b].CreatedAt)
+	})
+	return queued
+}
+
+// DeferDispatch marks inst as a queued conductor dispatch.
+func (i *Instance) DeferDispatch(priority int, message, reason string) {
+	i.Status = StatusQueued
+	i.Dispatch = &DispatchRequest{
+		Priority:   priority,
+		Message:    message,
+		Reason:     reason,
+		DeferredAt: time.Now().UTC(),
+	}
+	i.dispatchCleared = false
+}
+
+// ClearDispatch drops the deferred dispatch once the session has started.
+func (i *Instance) ClearDispatch() {
+	if i.Dispatch == nil {
+		return
+	}
+	i.Dispatch = nil
+	i.dispatchCleared = true
+}
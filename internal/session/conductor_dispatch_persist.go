@@ -0,0 +1,52 @@
+// Conductor dispatch JSON helpers.
+//
+// The deferred dispatch (priority, pending message, reason) lives in the
+// tool_data extras zone like depends_on. Clearing writes an explicit null
+// once so MergeToolDataExtras does not carry the old request forward.
+package session
+
+import "encoding/json"
+
+const toolDataDispatchKey = "dispatch"
+
+// WriteDispatchToToolData merges the dispatch request into the given
+// tool_data blob. A nil request removes the key, unless cleared is set, in
+// which case an explicit null is written.
+func WriteDispatchToToolData(td json.RawMessage, req *DispatchRequest, cleared bool) json.RawMessage {
+	if req == nil && !cleared {
+		if len(td) == 0 {
+			return td
+		}
+		m := map[string]json.RawMessage{}
+		if err := json.Unmarshal(td, &m); err != nil {
+			return td
+		}
+		if _, ok := m[toolDataDispatchKey]; !ok {
+			return td
+		}
+		delete(m, toolDataDispatchKey)
+		out, _ := json.Marshal(m)
+		return out
+	}
+	m := map[string]json.RawMessage{}
+	if len(td) > 0 {
+		_ = json.Unmarshal(td, &m)
+	}
+	raw, _ := json.Marshal(req)
+	m[toolDataDispatchKey] = raw
+	out, _ := json.Marshal(m)
+	return out
+}
+
+// ReadDispatchFromToolData extracts the dispatch request from the blob.
+// Returns nil for missing/null/malformed/legacy rows.
+func ReadDispatchFromToolData(td json.RawMessage) *DispatchRequest {
+	if len(td) == 0 {
+		return nil
+	}
+	var blob struct {
+		Dispatch *DispatchRequest `json:"dispatch"`
+	}
+	_ = json.Unmarshal(td, &blob)
+	return blob.Dispatch
+}
@@ -0,0 +1,140 @@
+package session
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// dispatchFixture returns a conductor session plus children launched by it.
+func dispatchFixture(children ...*Instance) []*Instance {
+	conductor := &Instance{ID: "cond", Title: "conductor-ops", Tool: "claude", Status: StatusRunning}
+	out := []*Instance{conductor}
+	for _, c := range children {
+		c.ParentSessionID = "cond"
+		out = append(out, c)
+	}
+	return out
+}
+
+func dispatchChild(id, tool string, status Status) *Instance {
+	return &Instance{ID: id, Title: id, Tool: tool, Status: status}
+}
+
+func TestConductorDispatches_OnlyConductorChildren(t *testing.T) {
+	instances := dispatchFixture(dispatchChild("a", "claude", StatusRunning))
+	other := &Instance{ID: "p", Title: "worker", Status: StatusRunning}
+	grandchild := &Instance{ID: "b", Title: "b", Tool: "claude", Status: StatusRunning, ParentSessionID: "p"}
+	instances = append(instances, other, grandchild)
+
+	got := ConductorDispatches(instances)
+	if len(got) != 1 || got[0].ID != "a" {
+		t.Fatalf("ConductorDispatches = %v, want [a]", instIDs(got))
+	}
+	if IsConductorDispatch(grandchild, instances) || !IsConductorDispatch(got[0], instances) {
+		t.Fatal("IsConductorDispatch must follow the parent's conductor title")
+	}
+}
+
+func TestCheckDispatchCapacity_PerToolAndTotal(t *testing.T) {
+	instances := dispatchFixture(
+		dispatchChild("c1", "claude", StatusRunning),
+		dispatchChild("c2", "claude", StatusStarting),
+		dispatchChild("c3", "claude", StatusWaiting), // idle slots do not count
+		dispatchChild("x1", "codex", StatusRunning),
+	)
+	settings := DispatchSettings{
+		MaxActive: 4,
+		Tools:     map[string]ToolDispatchLimits{"Claude": {MaxActive: 2}},
+	}
+
+	d := CheckDispatchCapacity(settings, instances, "claude", 0)
+	if d.Allowed || d.ToolActive != 2 || d.ToolMaxActive != 2 || !strings.Contains(d.Reason, "claude at capacity") {
+		t.Fatalf("claude decision = %+v, want deferred at 2/2", d)
+	}
+	if d := CheckDispatchCapacity(settings, instances, "codex", 0); !d.Allowed || d.Active != 3 {
+		t.Fatalf("codex decision = %+v, want allowed with 3 active", d)
+	}
+
+	settings.MaxActive = 3
+	if d := CheckDispatchCapacity(settings, instances, "codex", 0); d.Allowed || !strings.Contains(d.Reason, "3/3") {
+		t.Fatalf("codex decision = %+v, want deferred by the total cap", d)
+	}
+	if d := CheckDispatchCapacity(DispatchSettings{}, instances, "claude", 0); !d.Allowed {
+		t.Fatalf("no limits must always allow, got %+v", d)
+	}
+}
+
+func TestCheckDispatchCapacity_TokenBudget(t *testing.T) {
+	settings := DispatchSettings{Tools: map[string]ToolDispatchLimits{"claude": {DailyTokenBudget: 1000}}}
+	if !settings.Enabled() {
+		t.Fatal("a token budget alone enables dispatch limits")
+	}
+	instances := dispatchFixture()
+	if d := CheckDispatchCapacity(settings, instances, "claude", 999); !d.Allowed || d.TokensToday != 999 {
+		t.Fatalf("under budget: %+v", d)
+	}
+	d := CheckDispatchCapacity(settings, instances, "claude", 1000)
+	if d.Allowed || !strings.Contains(d.Reason, "token budget") {
+		t.Fatalf("spent budget: %+v", d)
+	}
+}
+
+func TestQueuedDispatches_PriorityThenAge(t *testing.T) {
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	mk := func(id string, priority int, age time.Duration) *Instance {
+		inst := dispatchChild(id, "claude", StatusIdle)
+		inst.CreatedAt = base.Add(-age)
+		inst.DeferDispatch(priority, "task "+id, "claude at capacity")
+		return inst
+	}
+	old := mk("old", 0, 3*time.Hour)
+	newer := mk("newer", 0, time.Hour)
+	urgent := mk("urgent", 5, 0)
+	instances := dispatchFixture(newer, urgent, old, dispatchChild("live", "claude", StatusRunning))
+
+	got := instIDs(QueuedDispatches(instances))
+	if strings.Join(got, ",") != "urgent,old,newer" {
+		t.Fatalf("drain order = %v, want urgent,old,newer", got)
+	}
+	if urgent.Status != StatusQueued || urgent.Dispatch.Message != "task urgent" {
+		t.Fatalf("DeferDispatch did not queue: %+v", urgent.Dispatch)
+	}
+
+	// The group queue must leave deferred dispatches to the dispatch drain.
+	urgent.GroupPath = "proj"
+	if next := FindNextQueued(instances, "proj"); next != nil {
+		t.Fatalf("FindNextQueued picked deferred dispatch %s", next.ID)
+	}
+
+	urgent.ClearDispatch()
+	if urgent.Dispatch != nil || !urgent.dispatchCleared {
+		t.Fatal("ClearDispatch must drop the request and mark it cleared")
+	}
+}
+
+func TestDispatch_ToolDataRoundTrip(t *testing.T) {
+	req := &DispatchRequest{Priority: 2, Message: "fix the build", Reason: "claude at capacity (2/2 active)"}
+	td := WriteDispatchToToolData([]byte(`{"color":"#ff00aa"}`), req, false)
+	got := ReadDispatchFromToolData(td)
+	if got == nil || *got != *req {
+		t.Fatalf("round-trip = %+v, want %+v", got, req)
+	}
+	if !strings.Contains(string(td), `"color":"#ff00aa"`) {
+		t.Fatalf("round-trip dropped color: %s", td)
+	}
+
+	legacy := []byte(`{"color":"#ff00aa"}`)
+	if got := WriteDispatchToToolData(legacy, nil, false); string(got) != string(legacy) {
+		t.Fatalf("nil write changed legacy blob: %s", got)
+	}
+
+	// Clearing writes an explicit null so MergeToolDataExtras drops the request.
+	cleared := WriteDispatchToToolData(td, nil, true)
+	if !strings.Contains(string(cleared), `"dispatch":null`) {
+		t.Fatalf("cleared blob should carry an explicit null: %s", cleared)
+	}
+	if got := ReadDispatchFromToolData(cleared); got != nil {
+		t.Fatalf("cleared read = %+v, want nil", got)
+	}
+}
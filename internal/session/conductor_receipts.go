@@ -0,0 +1,240 @@
+package session
+
+// Read receipts for conductor dispatches.
+//
+// A conductor drives its children with `session send` (and launches with an
+// initial message). A delivered paste is not the same as a received prompt:
+// the pane can die right after, or the paste can be swallowed by a dialog,
+// and the conductor only finds out when it wonders why nothing happened.
+// Every prompt delivered to a conductor child gets a receipt that is
+// acknowledged once the child shows it started working — a lifecycle hook
+// fired after the send, or the session is running. A receipt still pending
+// after ack_timeout_seconds is unacknowledged; `conductor receipts --retry`
+// re-delivers it up to max_retries times, after which it is failed.
+// Unacknowledged and failed receipts are listed by `conductor status`.
+//
+//	[conductor.receipts]
+//	ack_timeout_seconds = 120
+//	max_retries = 1
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// ReceiptSettings configures dispatch read receipts.
+type ReceiptSettings struct {
+	// AckTimeoutSeconds is how long a child has to start working on a
+	// prompt before the receipt is unacknowledged (default: 120).
+	AckTimeoutSeconds int `toml:"ack_timeout_seconds,omitzero"`
+
+	// MaxRetries is how many times an unacknowledged prompt is re-delivered
+	// (default: 1; negative disables retries).
+	MaxRetries int `toml:"max_retries,omitzero"`
+}
+
+// GetAckTimeout returns the acknowledgment timeout (default: 2 minutes).
+func (r ReceiptSettings) GetAckTimeout() time.Duration {
+	if r.AckTimeoutSeconds <= 0 {
+		return 2 * time.Minute
+	}
+	return time.Duration(r.AckTimeoutSeconds) * time.Second
+}
+
+// GetMaxRetries returns the re-delivery limit (default: 1).
+func (r ReceiptSettings) GetMaxRetries() int {
+	switch {
+	case r.MaxRetries < 0:
+		return 0
+	case r.MaxRetries == 0:
+		return 1
+	}
+	return r.MaxRetries
+}
+
+// Receipt statuses. Pending and unacknowledged receipts are open: they are
+// re-evaluated on every check.
+const (
+	ReceiptPending = "pending"
+	ReceiptAcked   = "acked"
+	ReceiptUnacked = "unacked"
+	ReceiptFailed  = "failed"
+)
+
+// OpenReceiptStatuses are the statuses still waiting for an acknowledgment.
+var OpenReceiptStatuses = []string{ReceiptPending, ReceiptUnacked}
+
+// receiptMessageMax caps the prompt text kept on a receipt; enough to
+// recognize it and to re-deliver ordinary conductor instructions.
+const receiptMessageMax = 4000
+
+// receiptRetention is how long settled receipts are kept.
+const receiptRetention = 7 * 24 * time.Hour
+
+// NewDispatchReceipt returns a pending receipt for message delivered to inst
+// at sentAt.
+func NewDispatchReceipt(inst *Instance, message string, sentAt time.Time) *statedb.ReceiptRow {
+	if len(message) > receiptMessageMax {
+		message = message[:receiptMessageMax]
+	}
+	return &statedb.ReceiptRow{
+		SessionID: inst.ID,
+		ParentID:  inst.ParentSessionID,
+		Message:   message,
+		Status:    ReceiptPending,
+		Attempts:  1,
+		SentAt:    sentAt,
+		UpdatedAt: sentAt,
+	}
+}
+
+// RecordDispatchReceipt stores a receipt for a prompt delivered to inst when
+// inst is a conductor child. Receipt bookkeeping never fails a send, so
+// errors are only logged.
+func RecordDispatchReceipt(db *statedb.StateDB, inst *Instance, instances []*Instance, message string, sentAt time.Time) {
+	if db == nil || strings.TrimSpace(message) == "" || !IsConductorDispatch(inst, instances) {
+		return
+	}
+	if err := db.InsertReceipt(NewDispatchReceipt(inst, message, sentAt)); err != nil {
+		sessionLog.Warn("dispatch_receipt_insert_failed", slog.String("id", inst.ID), slog.String("error", err.Error()))
+	}
+}
+
+// ReceiptProbe is what is known about a child when its receipt is checked.
+type ReceiptProbe struct {
+	Exists   bool      // the tmux session exists
+	PaneDead bool      // the pane's process exited
+	Running  bool      // the session is working right now
+	HookAt   time.Time // time of the latest lifecycle hook event (zero: none)
+}
+
+// EvaluateReceipt advances r given the child's state at now and reports
+// whether it changed. A hook event after the send, or a running session,
+// acknowledges it (late acknowledgments also clear unacknowledged and
+// failed receipts). A pending receipt past the timeout — or whose pane is
+// gone — becomes unacknowledged; one that has used its retries fails.
+func EvaluateReceipt(r *statedb.ReceiptRow, probe *ReceiptProbe, settings ReceiptSettings, now time.Time) bool {
+	if r.Status == ReceiptAcked {
+		return false
+	}
+	if probe == nil {
+		if r.Status == ReceiptFailed {
+			return false
+		}
+		r.Status, r.Reason, r.UpdatedAt = ReceiptFailed, "session removed", now
+		return true
+	}
+	if probe.HookAt.After(r.SentAt) {
+		r.Status, r.Reason, r.AckedAt, r.UpdatedAt = ReceiptAcked, "", probe.HookAt, now
+		return true
+	}
+	if probe.Running {
+		r.Status, r.Reason, r.AckedAt, r.UpdatedAt = ReceiptAcked, "", now, now
+		return true
+	}
+
+	reason := ""
+	switch {
+	case !probe.Exists:
+		reason = "session not running"
+	case probe.PaneDead:
+		reason = "pane is dead"
+	case now.Sub(r.SentAt) >= settings.GetAckTimeout():
+		reason = fmt.Sprintf("no activity %s after delivery", settings.GetAckTimeout())
+	default:
+		return false
+	}
+	status := ReceiptUnacked
+	if r.Attempts > settings.GetMaxRetries() {
+		status = ReceiptFailed
+		reason += fmt.Sprintf(" (%d attempts)", r.Attempts)
+	}
+	if r.Status == status && r.Reason == reason {
+		return false
+	}
+	r.Status, r.Reason, r.UpdatedAt = status, reason, now
+	return true
+}
+
+// ReceiptRetryable reports whether an unacknowledged receipt may be
+// re-delivered.
+func ReceiptRetryable(r *statedb.ReceiptRow, settings ReceiptSettings) bool {
+	return r.Status == ReceiptUnacked && r.Attempts <= settings.GetMaxRetries()
+}
+
+// MarkReceiptRetried records a re-delivery at now: the receipt is pending
+// again with a fresh timeout.
+func MarkReceiptRetried(r *statedb.ReceiptRow, now time.Time) {
+	r.Attempts++
+	r.Status, r.Reason, r.SentAt, r.UpdatedAt = ReceiptPending, "", now, now
+}
+
+// probeReceiptTarget reads what EvaluateReceipt needs from a loaded
+// instance whose status was refreshed.
+func probeReceiptTarget(inst *Instance) *ReceiptProbe {
+	if inst == nil {
+		return nil
+	}
+	probe := &ReceiptProbe{Exists: inst.Exists()}
+	if probe.Exists {
+		if ts := inst.GetTmuxSession(); ts != nil {
+			probe.PaneDead = ts.IsPaneDead()
+		}
+	}
+	probe.Running = inst.GetStatusThreadSafe() == StatusRunning
+	if hs := readHookStatusFile(inst.ID); hs != nil {
+		probe.HookAt = hs.UpdatedAt
+	}
+	return probe
+}
+
+// RefreshDispatchReceipts re-evaluates the open receipts (plus failed ones,
+// which a late acknowledgment can still clear) against instances, persists
+// the changes and prunes settled receipts past retention. It returns every
+// receipt that is not acknowledged, oldest first.
+func RefreshDispatchReceipts(db *statedb.StateDB, instances []*Instance, settings ReceiptSettings, now time.Time) ([]*statedb.ReceiptRow, error) {
+	if db == nil {
+		return nil, nil
+	}
+	rows, err := db.LoadReceipts(ReceiptPending, ReceiptUnacked, ReceiptFailed)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*Instance, len(instances))
+	for _, inst := range instances {
+		if inst != nil {
+			byID[inst.ID] = inst
+		}
+	}
+	var targets []*Instance
+	for _, r := range rows {
+		if inst := byID[r.SessionID]; inst != nil && !slices.Contains(targets, inst) {
+			targets = append(targets, inst)
+		}
+	}
+	if len(targets) > 0 {
+		RefreshInstancesForCLIStatus(targets)
+		for _, inst := range targets {
+			_ = inst.UpdateStatus()
+		}
+	}
+
+	var out []*statedb.ReceiptRow
+	for _, r := range rows {
+		if EvaluateReceipt(r, probeReceiptTarget(byID[r.SessionID]), settings, now) {
+			if err := db.UpdateReceipt(r); err != nil {
+				return nil, err
+			}
+		}
+		if r.Status != ReceiptAcked {
+			out = append(out, r)
+		}
+	}
+	_ = db.DeleteReceiptsBefore(now.Add(-receiptRetention), OpenReceiptStatuses...)
+	return out, nil
+}
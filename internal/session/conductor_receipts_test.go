@@ -0,0 +1,89 @@
+package session
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+func TestReceiptSettings_Defaults(t *testing.T) {
+	var s ReceiptSettings
+	if s.GetAckTimeout() != 2*time.Minute || s.GetMaxRetries() != 1 {
+		t.Errorf("defaults = %v, %d; want 2m, 1", s.GetAckTimeout(), s.GetMaxRetries())
+	}
+	s = ReceiptSettings{AckTimeoutSeconds: 30, MaxRetries: -1}
+	if s.GetAckTimeout() != 30*time.Second || s.GetMaxRetries() != 0 {
+		t.Errorf("custom = %v, %d; want 30s, 0", s.GetAckTimeout(), s.GetMaxRetries())
+	}
+}
+
+func TestEvaluateReceipt_Acknowledged(t *testing.T) {
+	sent := time.UnixMilli(1_700_000_000_500)
+	now := sent.Add(10 * time.Second)
+
+	// The Stop hook of the previous turn, in the same second as the send,
+	// is not an acknowledgment.
+	r := NewDispatchReceipt(&Instance{ID: "c1", ParentSessionID: "cond"}, "go", sent)
+	if EvaluateReceipt(r, &ReceiptProbe{Exists: true, HookAt: sent.Truncate(time.Second)}, ReceiptSettings{}, now) {
+		t.Fatalf("stale hook acknowledged receipt: %+v", r)
+	}
+
+	if !EvaluateReceipt(r, &ReceiptProbe{Exists: true, HookAt: sent.Add(2 * time.Second)}, ReceiptSettings{}, now) || r.Status != ReceiptAcked {
+		t.Errorf("hook after send: status = %s, want acked", r.Status)
+	}
+
+	r = NewDispatchReceipt(&Instance{ID: "c2"}, "go", sent)
+	if !EvaluateReceipt(r, &ReceiptProbe{Exists: true, Running: true}, ReceiptSettings{}, now) || r.Status != ReceiptAcked {
+		t.Errorf("running session: status = %s, want acked", r.Status)
+	}
+}
+
+func TestEvaluateReceipt_TimeoutAndRetryPolicy(t *testing.T) {
+	sent := time.Unix(1_700_000_000, 0)
+	settings := ReceiptSettings{AckTimeoutSeconds: 60, MaxRetries: 1}
+	idle := &ReceiptProbe{Exists: true}
+
+	r := NewDispatchReceipt(&Instance{ID: "c1"}, "go", sent)
+	if EvaluateReceipt(r, idle, settings, sent.Add(30*time.Second)) {
+		t.Fatalf("inside timeout: %+v", r)
+	}
+	if !EvaluateReceipt(r, idle, settings, sent.Add(time.Minute)) || r.Status != ReceiptUnacked {
+		t.Fatalf("after timeout: status = %s, want unacked", r.Status)
+	}
+	if !ReceiptRetryable(r, settings) {
+		t.Fatal("first unacked receipt should be retryable")
+	}
+
+	retryAt := sent.Add(2 * time.Minute)
+	MarkReceiptRetried(r, retryAt)
+	if r.Status != ReceiptPending || r.Attempts != 2 || !r.SentAt.Equal(retryAt) {
+		t.Fatalf("after retry = %+v", r)
+	}
+	if !EvaluateReceipt(r, &ReceiptProbe{Exists: true, PaneDead: true}, settings, retryAt.Add(time.Second)) || r.Status != ReceiptFailed {
+		t.Fatalf("dead pane after last retry: status = %s, want failed", r.Status)
+	}
+	if !strings.Contains(r.Reason, "pane is dead") || ReceiptRetryable(r, settings) {
+		t.Errorf("failed receipt = %+v", r)
+	}
+
+	// A late acknowledgment still clears a failed receipt.
+	if !EvaluateReceipt(r, &ReceiptProbe{Exists: true, Running: true}, settings, retryAt.Add(time.Hour)) || r.Status != ReceiptAcked {
+		t.Errorf("late ack: status = %s, want acked", r.Status)
+	}
+}
+
+func TestEvaluateReceipt_SessionRemoved(t *testing.T) {
+	r := &statedb.ReceiptRow{Status: ReceiptPending, Attempts: 1, SentAt: time.Unix(1_700_000_000, 0)}
+	if !EvaluateReceipt(r, nil, ReceiptSettings{}, time.Unix(1_700_000_010, 0)) || r.Status != ReceiptFailed || r.Reason != "session removed" {
+		t.Errorf("removed session = %+v", r)
+	}
+}
+
+func TestNewDispatchReceipt_TruncatesMessage(t *testing.T) {
+	r := NewDispatchReceipt(&Instance{ID: "c1"}, strings.Repeat("x", receiptMessageMax+10), time.Now())
+	if len(r.Message) != receiptMessageMax {
+		t.Errorf("message length = %d, want %d", len(r.Message), receiptMessageMax)
+	}
+}
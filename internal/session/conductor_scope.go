@@ -0,0 +1,76 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A conductor's heartbeat scope is the set of groups whose sessions it
+// checks. By default that is the group named after the conductor; meta.json's
+// "scope" narrows or redirects it to other groups so a profile can hold
+// sessions the conductor never touches. Subgroups are always included.
+
+// ParseConductorScope normalizes a comma-separated list of group paths as
+// given to `conductor setup --scope`. Surrounding spaces and slashes are
+// dropped; an empty string clears the scope.
+func ParseConductorScope(scope string) ([]string, error) {
+	var groups []string
+	for _, part := range strings.Split(scope, ",") {
+		path := strings.Trim(strings.TrimSpace(part), "/")
+		if path == "" {
+			if strings.TrimSpace(part) != "" {
+				return nil, fmt.Errorf("invalid scope entry %q", part)
+			}
+			continue
+		}
+		if path == "conductor" || strings.HasPrefix(path, "conductor/") {
+			return nil, fmt.Errorf("scope %q: the conductor group holds conductors, not sessions to manage", path)
+		}
+		groups = append(groups, path)
+	}
+	return groups, nil
+}
+
+// ScopeGroups returns the group paths this conductor's heartbeat covers:
+// the configured scope, or the conductor's own group when none is set.
+func (m *ConductorMeta) ScopeGroups() []string {
+	if m == nil {
+		return nil
+	}
+	if groups, err := ParseConductorScope(m.Scope); err == nil && len(groups) > 0 {
+		return groups
+	}
+	return []string{m.Name}
+}
+
+// ConductorScopeIncludes reports whether a session in groupPath falls in a
+// scope of groups (subgroups included).
+func ConductorScopeIncludes(groups []string, groupPath string) bool {
+	for _, g := range groups {
+		if groupPath == g || strings.HasPrefix(groupPath, g+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// ConductorHeartbeatPrompt is the heartbeat check-in text (without the
+// prefix) for a conductor. With an explicit scope it names the groups and
+// tells the conductor to leave every other session alone.
+func ConductorHeartbeatPrompt(meta *ConductorMeta) string {
+	const ask = "List any that are waiting, auto-respond where safe, and report what needs my attention."
+	if meta == nil || strings.TrimSpace(meta.Scope) == "" {
+		name := ""
+		if meta != nil {
+			name = meta.Name
+		}
+		return fmt.Sprintf("Check sessions in your group (%s). %s", name, ask)
+	}
+	groups := meta.ScopeGroups()
+	noun := "group"
+	if len(groups) > 1 {
+		noun = "groups"
+	}
+	return fmt.Sprintf("Check only sessions in %s %s (subgroups included) and leave all other sessions alone. %s",
+		noun, strings.Join(groups, ", "), ask)
+}
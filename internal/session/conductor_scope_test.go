@@ -0,0 +1,65 @@
+package session
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseConductorScope(t *testing.T) {
+	groups, err := ParseConductorScope(" work/api/ , infra ,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"work/api", "infra"}; !reflect.DeepEqual(groups, want) {
+		t.Errorf("groups = %v, want %v", groups, want)
+	}
+	if groups, err := ParseConductorScope(""); err != nil || len(groups) != 0 {
+		t.Errorf("empty scope = %v, %v", groups, err)
+	}
+	for _, bad := range []string{"/", "conductor", "conductor/x"} {
+		if _, err := ParseConductorScope(bad); err == nil {
+			t.Errorf("scope %q should be rejected", bad)
+		}
+	}
+}
+
+func TestConductorMetaScopeGroups(t *testing.T) {
+	meta := &ConductorMeta{Name: "ops"}
+	if got := meta.ScopeGroups(); !reflect.DeepEqual(got, []string{"ops"}) {
+		t.Errorf("default scope = %v, want the conductor's own group", got)
+	}
+	meta.Scope = "work/api,infra"
+	groups := meta.ScopeGroups()
+	if !reflect.DeepEqual(groups, []string{"work/api", "infra"}) {
+		t.Errorf("scope = %v", groups)
+	}
+	for path, want := range map[string]bool{
+		"work/api":        true,
+		"work/api/tests":  true,
+		"infra":           true,
+		"work":            false,
+		"work/api-legacy": false,
+		"personal":        false,
+	} {
+		if got := ConductorScopeIncludes(groups, path); got != want {
+			t.Errorf("ConductorScopeIncludes(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestConductorHeartbeatPrompt(t *testing.T) {
+	def := ConductorHeartbeatPrompt(&ConductorMeta{Name: "ops"})
+	if !strings.HasPrefix(def, "Check sessions in your group (ops).") {
+		t.Errorf("default prompt changed: %q", def)
+	}
+	scoped := ConductorHeartbeatPrompt(&ConductorMeta{Name: "ops", Scope: "work/api"})
+	if !strings.Contains(scoped, "only sessions in group work/api") || !strings.Contains(scoped, "leave all other sessions alone") {
+		t.Errorf("scoped prompt = %q", scoped)
+	}
+
+	script := renderConductorHeartbeatScript("ops", "work", "work/api,infra")
+	if !strings.Contains(script, `MSG="[HEARTBEAT] Check only sessions in groups work/api, infra`) {
+		t.Errorf("heartbeat.sh does not carry the scoped prompt:\n%s", script)
+	}
+}
@@ -76,6 +76,10 @@ them; act on each before composing your status. Your Stop hook drains the same q
 automatically at each turn boundary, so this heartbeat drain is the idle-conductor
 fallback — together they guarantee no completion is missed whether you are busy or idle.
 
+**SECOND step — drain the task queue** (see Task Queue below): dispatch queued
+tasks to sessions that are idle or waiting, and check on tasks that have been
+dispatched for a long time without an acknowledgment.
+
 **Your heartbeat response format:**
 
 ` + "```" + `
@@ -93,6 +97,35 @@ NEED: api-fix - asking whether to run integration tests against staging or prod
 
 Your response is parsed: if it contains ` + "`" + `NEED:` + "`" + ` lines, those get forwarded to the user (via remote channels if configured, or visible in the TUI/task-log).
 
+## Task Queue
+
+Users (and you) push work items into a persistent queue. Each task has a state:
+` + "`" + `queued` + "`" + ` → ` + "`" + `dispatched` + "`" + ` → ` + "`" + `done` + "`" + ` | ` + "`" + `failed` + "`" + `. A task may already name its session, or leave
+routing to you.
+
+` + "```bash" + `
+agent-deck task list --json                        # Open tasks (queued + dispatched)
+agent-deck task add --session <s> "<text>"         # Queue work for a session
+agent-deck task dispatch <task-id>                 # Send a queued task to its session
+agent-deck task dispatch <task-id> --session <s>   # Route an unassigned task
+agent-deck task next --session <s> --dispatch      # Send <s> its oldest queued task
+agent-deck task requeue <task-id>                  # Retry a stuck or failed task
+` + "```" + `
+
+**Draining the queue:**
+1. For each queued task with a session: dispatch it when that session is idle or waiting.
+   Leave it queued while the session is running — one task per session at a time.
+2. For each queued task without a session: pick the best-suited idle session (by project
+   and current work) and dispatch it there. If none fits, leave it queued and mention it
+   in your status.
+3. A dispatched task is acknowledged by the agent itself, which runs
+   ` + "`" + `agent-deck task done <id> --result "..."` + "`" + ` or ` + "`" + `agent-deck task fail <id> --reason "..."` + "`" + `
+   (the dispatched message tells it how). If its session has gone idle with no
+   acknowledgment, read its output and mark the task yourself; requeue it if the work
+   was not done.
+4. Report failed tasks as ` + "`" + `NEED:` + "`" + ` lines unless the failure is something you can fix by
+   requeueing.
+
 ## State Management
 
 Maintain ` + "`" + `./state.json` + "`" + ` for persistent context across compactions:
@@ -595,7 +595,10 @@ func TestBridgeTemplate_HeartbeatScopesToConductorGroups(t *testing.T) {
 		"def select_heartbeat_conductors(conductors: list[dict]) -> list[dict]:",
 		"conductors = select_heartbeat_conductors(all_conductors)",
 		`s_group = s.get("group", "") or ""`,
-		`if s_group != name and not s_group.startswith(f"{name}/"):`,
+		`scope_groups = conductor_scope_groups(conductor)`,
+		`return any(group_path == g or group_path.startswith(f"{g}/") for g in groups)`,
+		`return groups or [conductor.get("name", "")]`,
+		`if not in_conductor_scope(scope_groups, s_group):`,
 		`for s in scoped_sessions:`,
 	}
 
@@ -658,7 +661,7 @@ func TestConductorHeartbeatScript_InjectsHeartbeatRules(t *testing.T) {
 	// The rendered (not raw) script should carry the bridge-style prefix so the
 	// idle-pause matcher (IsConductorHeartbeatMessage) can recognise heartbeat
 	// messages emitted by this OS-level heartbeat path.
-	rendered := renderConductorHeartbeatScript("alpha", "default")
+	rendered := renderConductorHeartbeatScript("alpha", "default", "")
 	if !strings.Contains(rendered, ConductorBridgeHeartbeatPrefix) {
 		t.Fatalf("rendered heartbeat script should emit %q prefix (matches bridge.py)", ConductorBridgeHeartbeatPrefix)
 	}
@@ -671,7 +674,7 @@ func TestRenderConductorHeartbeatScript_UsesXDGConductorRoot(t *testing.T) {
 	t.Setenv("XDG_DATA_HOME", xdgData)
 
 	wantRoot := filepath.Join(xdgData, "agent-deck", "conductor")
-	script := renderConductorHeartbeatScript("alpha", "work")
+	script := renderConductorHeartbeatScript("alpha", "work", "")
 
 	if !strings.Contains(script, `CONDUCTOR_ROOT="`+wantRoot+`"`) {
 		t.Fatalf("heartbeat script should render XDG conductor root %q:\n%s", wantRoot, script)
@@ -689,7 +692,7 @@ func TestRenderConductorHeartbeatScript_UsesXDGConductorRoot(t *testing.T) {
 // producer (shell script) and the consumer (IsConductorHeartbeatMessage)
 // cannot drift apart in future refactors.
 func TestRenderConductorHeartbeatScript_ReplacesHeartbeatPrefix(t *testing.T) {
-	script := renderConductorHeartbeatScript("test", "default")
+	script := renderConductorHeartbeatScript("test", "default", "")
 	if strings.Contains(script, "{HEARTBEAT_PREFIX}") {
 		t.Fatalf("heartbeat script must not contain unresolved prefix placeholder:\n%s", script)
 	}
@@ -2421,10 +2424,11 @@ func TestConductorHeartbeatScript_GroupScoped(t *testing.T) {
 	}
 
 	// The heartbeat message must reference the conductor's own group via {NAME}
+	// (the rendered prompt; a configured scope names other groups instead).
 	if !strings.Contains(conductorHeartbeatScript, "{NAME}") {
 		t.Fatal("heartbeat script must reference {NAME} for group scoping")
 	}
-	if !strings.Contains(conductorHeartbeatScript, "Check sessions in") {
+	if !strings.Contains(renderConductorHeartbeatScript("alpha", "default", ""), "Check sessions in your group (alpha)") {
 		t.Fatal("heartbeat script should contain group-scoped message like 'Check sessions in'")
 	}
 
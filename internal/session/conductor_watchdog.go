@@ -0,0 +1,352 @@
+package session
+
+// Conductor watchdog.
+//
+// A conductor that dies in an error state, or a bridge.py whose event loop
+// wedges, fails silently: Telegram/Slack messages stop getting answered and
+// heartbeats stop firing, and nobody notices until they wonder why. The
+// watchdog runs from the notify-daemon poll loop (not a daemon of its own)
+// about once a minute and:
+//
+//   - restarts a conductor session whose status is error (a stopped
+//     conductor was stopped on purpose and is left alone);
+//   - restarts the bridge daemon when it is installed but not running, or
+//     when bridge.alive — touched every minute by bridge.py, falling back to
+//     bridge.log — has not moved for bridge_stale_minutes.
+//
+// Restarts are capped per target per hour so a conductor that errors again
+// right after starting does not flap forever. Each pass is written to
+// <conductor-dir>/watchdog.json, which `conductor status` reports.
+//
+//	[conductor.watchdog]
+//	disabled = false
+//	bridge_stale_minutes = 10
+//	max_restarts_per_hour = 3
+//	notify = true          # push via [notifications.push] on each restart
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WatchdogSettings configures the conductor watchdog.
+type WatchdogSettings struct {
+	// Disabled turns the watchdog off.
+	Disabled bool `toml:"disabled,omitempty"`
+
+	// BridgeStaleMinutes is how long bridge.alive may go untouched before
+	// the bridge counts as stuck (default: 10).
+	BridgeStaleMinutes int `toml:"bridge_stale_minutes,omitzero"`
+
+	// MaxRestartsPerHour caps restarts per conductor and for the bridge
+	// (default: 3).
+	MaxRestartsPerHour int `toml:"max_restarts_per_hour,omitzero"`
+
+	// Notify sends a push notification through [notifications.push] when
+	// the watchdog restarts something or gives up.
+	Notify bool `toml:"notify,omitempty"`
+}
+
+// GetBridgeStale returns the bridge staleness threshold (default: 10 minutes).
+func (w WatchdogSettings) GetBridgeStale() time.Duration {
+	if w.BridgeStaleMinutes <= 0 {
+		return 10 * time.Minute
+	}
+	return time.Duration(w.BridgeStaleMinutes) * time.Minute
+}
+
+// GetMaxRestartsPerHour returns the restart cap (default: 3).
+func (w WatchdogSettings) GetMaxRestartsPerHour() int {
+	if w.MaxRestartsPerHour <= 0 {
+		return 3
+	}
+	return w.MaxRestartsPerHour
+}
+
+// watchdogInterval is how often the notify daemon runs a watchdog pass.
+const watchdogInterval = time.Minute
+
+// WatchdogTarget is the watchdog's view of one conductor or the bridge.
+type WatchdogTarget struct {
+	Healthy       bool        `json:"healthy"`
+	Problem       string      `json:"problem,omitempty"`
+	LastBeatAt    time.Time   `json:"last_beat_at,omitzero"`
+	Restarts      []time.Time `json:"restarts,omitempty"` // within the last hour
+	LastRestartAt time.Time   `json:"last_restart_at,omitzero"`
+	RestartError  string      `json:"restart_error,omitempty"`
+	GaveUp        bool        `json:"gave_up,omitempty"` // hit the hourly restart cap
+}
+
+// WatchdogReport is the result of the latest watchdog pass.
+type WatchdogReport struct {
+	CheckedAt  time.Time                  `json:"checked_at"`
+	Bridge     *WatchdogTarget            `json:"bridge,omitempty"` // nil: no bridge daemon installed
+	Conductors map[string]*WatchdogTarget `json:"conductors"`
+}
+
+// WatchdogReportPath returns <conductor-dir>/watchdog.json.
+func WatchdogReportPath() (string, error) {
+	dir, err := ConductorDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "watchdog.json"), nil
+}
+
+// LoadWatchdogReport reads the latest watchdog report. A missing report is
+// (nil, nil): the watchdog has not run yet.
+func LoadWatchdogReport() (*WatchdogReport, error) {
+	path, err := WatchdogReportPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var r WatchdogReport
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return &r, nil
+}
+
+func saveWatchdogReport(r *WatchdogReport) error {
+	path, err := WatchdogReportPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeJSONFileAtomic(path, data, 0o644)
+}
+
+// conductorWatchdog holds the watchdog's probes and actions; tests replace
+// them.
+type conductorWatchdog struct {
+	settings func() WatchdogSettings
+	list     func() ([]ConductorMeta, error)
+
+	// sessionStatus returns the conductor session's status ("" when the
+	// session does not exist).
+	sessionStatus  func(meta ConductorMeta) string
+	restartSession func(meta ConductorMeta) error
+
+	bridgeInstalled func() bool
+	bridgeRunning   func() bool
+	bridgeLastBeat  func() time.Time
+	restartBridge   func() error
+
+	notify func(title, body string)
+	now    func() time.Time
+
+	load func() (*WatchdogReport, error)
+	save func(*WatchdogReport) error
+}
+
+func newConductorWatchdog(sessionStatus func(ConductorMeta) string) *conductorWatchdog {
+	return &conductorWatchdog{
+		settings:        func() WatchdogSettings { return GetConductorSettings().Watchdog },
+		list:            ListConductors,
+		sessionStatus:   sessionStatus,
+		restartSession:  restartConductorSession,
+		bridgeInstalled: bridgeDaemonInstalledOnDisk,
+		bridgeRunning:   IsBridgeDaemonRunning,
+		bridgeLastBeat:  bridgeLastBeat,
+		restartBridge:   restartBridgeDaemon,
+		notify:          sendWatchdogPush,
+		now:             time.Now,
+		load:            LoadWatchdogReport,
+		save:            saveWatchdogReport,
+	}
+}
+
+// Run performs one pass and saves the report. It returns nil when the
+// watchdog is disabled or there are no conductors.
+func (w *conductorWatchdog) Run() *WatchdogReport {
+	settings := w.settings()
+	if settings.Disabled {
+		return nil
+	}
+	metas, err := w.list()
+	if err != nil || len(metas) == 0 {
+		return nil
+	}
+	prev, _ := w.load()
+	if prev == nil {
+		prev = &WatchdogReport{}
+	}
+	now := w.now()
+	report := &WatchdogReport{CheckedAt: now, Conductors: map[string]*WatchdogTarget{}}
+
+	for _, meta := range metas {
+		t := carryWatchdogTarget(prev.Conductors[meta.Name], now)
+		report.Conductors[meta.Name] = t
+		switch status := w.sessionStatus(meta); status {
+		case "":
+			t.Healthy, t.Problem = false, "session not found"
+			continue
+		case string(StatusError):
+			t.Healthy, t.Problem = false, "session in error state"
+		default:
+			t.Healthy, t.GaveUp = true, false
+			continue
+		}
+		w.restart(t, settings, now, "conductor "+meta.Name, func() error { return w.restartSession(meta) })
+	}
+
+	if w.bridgeInstalled() {
+		t := carryWatchdogTarget(prev.Bridge, now)
+		report.Bridge = t
+		t.LastBeatAt = w.bridgeLastBeat()
+		t.Healthy = true
+		switch {
+		case !w.bridgeRunning():
+			t.Healthy, t.Problem = false, "daemon not running"
+		case !t.LastBeatAt.IsZero() && now.Sub(t.LastBeatAt) > settings.GetBridgeStale():
+			t.Healthy, t.Problem = false, fmt.Sprintf("no activity for %s", now.Sub(t.LastBeatAt).Round(time.Minute))
+		}
+		if t.Healthy {
+			t.GaveUp = false
+		} else {
+			w.restart(t, settings, now, "bridge", w.restartBridge)
+		}
+	}
+
+	if err := w.save(report); err != nil {
+		sessionLog.Warn("conductor_watchdog_save_failed", slog.String("error", err.Error()))
+	}
+	return report
+}
+
+// restart restarts an unhealthy target unless it already hit the hourly cap.
+func (w *conductorWatchdog) restart(t *WatchdogTarget, settings WatchdogSettings, now time.Time, what string, do func() error) {
+	if len(t.Restarts) >= settings.GetMaxRestartsPerHour() {
+		if !t.GaveUp {
+			w.maybeNotify(settings, "Conductor watchdog gave up", fmt.Sprintf("%s: %s; %d restarts in the last hour", what, t.Problem, len(t.Restarts)))
+		}
+		t.GaveUp = true
+		return
+	}
+	t.Restarts = append(t.Restarts, now)
+	t.LastRestartAt = now
+	t.RestartError = ""
+	if err := do(); err != nil {
+		t.RestartError = err.Error()
+		sessionLog.Warn("conductor_watchdog_restart_failed", slog.String("target", what), slog.String("error", err.Error()))
+		w.maybeNotify(settings, "Conductor watchdog restart failed", fmt.Sprintf("%s: %s; restart failed: %v", what, t.Problem, err))
+		return
+	}
+	sessionLog.Info("conductor_watchdog_restarted", slog.String("target", what), slog.String("problem", t.Problem))
+	w.maybeNotify(settings, "Conductor watchdog restarted "+what, t.Problem)
+}
+
+func (w *conductorWatchdog) maybeNotify(settings WatchdogSettings, title, body string) {
+	if settings.Notify && w.notify != nil {
+		w.notify(title, body)
+	}
+}
+
+// carryWatchdogTarget starts this pass's target from the previous one,
+// keeping the restarts of the last hour. Health is re-evaluated each pass.
+func carryWatchdogTarget(prev *WatchdogTarget, now time.Time) *WatchdogTarget {
+	t := &WatchdogTarget{}
+	if prev == nil {
+		return t
+	}
+	for _, at := range prev.Restarts {
+		if now.Sub(at) < time.Hour {
+			t.Restarts = append(t.Restarts, at)
+		}
+	}
+	t.LastRestartAt = prev.LastRestartAt
+	t.RestartError = prev.RestartError
+	t.GaveUp = prev.GaveUp
+	return t
+}
+
+// restartConductorSession restarts conductor-<name> through the CLI, which
+// owns the restart path (tmux, tool options, MCPs) for the conductor's
+// profile.
+func restartConductorSession(meta ConductorMeta) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	args := []string{}
+	if meta.Profile != "" {
+		args = append(args, "-p", meta.Profile)
+	}
+	args = append(args, "session", "restart", ConductorSessionTitle(meta.Name), "-q")
+	out, err := exec.CommandContext(ctx, agentDeckBinaryPath(), args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// bridgeDaemonInstalledOnDisk reports whether a bridge launchd plist or
+// systemd unit exists.
+func bridgeDaemonInstalledOnDisk() bool {
+	for _, pathFn := range []func() (string, error){LaunchdPlistPath, SystemdBridgeServicePath} {
+		if p, err := pathFn(); err == nil {
+			if _, err := os.Stat(p); err == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// bridgeLastBeat returns the mtime of bridge.alive, or of bridge.log for a
+// bridge.py from before bridge.alive. Zero when neither exists.
+func bridgeLastBeat() time.Time {
+	dir, err := ConductorDir()
+	if err != nil {
+		return time.Time{}
+	}
+	for _, name := range []string{"bridge.alive", "bridge.log"} {
+		if info, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return info.ModTime()
+		}
+	}
+	return time.Time{}
+}
+
+// restartBridgeDaemon restarts the installed bridge daemon in place.
+func restartBridgeDaemon() error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		cmd = exec.Command("launchctl", "kickstart", "-k", "gui/"+strconv.Itoa(os.Getuid())+"/"+LaunchdPlistName)
+	} else {
+		cmd = exec.Command("systemctl", "--user", "restart", systemdBridgeServiceName)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func sendWatchdogPush(title, body string) {
+	p := GetPushSettings()
+	if p.Validate() != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := SendPush(ctx, p, PushMessage{Title: title, Body: body}); err != nil {
+		sessionLog.Warn("conductor_watchdog_push_failed", slog.String("error", err.Error()))
+	}
+}
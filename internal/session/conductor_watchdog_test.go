@@ -0,0 +1,126 @@
+package session
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeWatchdog returns a watchdog over conductor "ops" whose probes report a
+// healthy system; tests flip the parts they exercise.
+func fakeWatchdog(now time.Time) (*conductorWatchdog, *int, *int, *[]string) {
+	sessionRestarts, bridgeRestarts := 0, 0
+	var notes []string
+	var saved *WatchdogReport
+	w := &conductorWatchdog{
+		settings:        func() WatchdogSettings { return WatchdogSettings{Notify: true} },
+		list:            func() ([]ConductorMeta, error) { return []ConductorMeta{{Name: "ops", Profile: "default"}}, nil },
+		sessionStatus:   func(ConductorMeta) string { return string(StatusWaiting) },
+		restartSession:  func(ConductorMeta) error { sessionRestarts++; return nil },
+		bridgeInstalled: func() bool { return true },
+		bridgeRunning:   func() bool { return true },
+		bridgeLastBeat:  func() time.Time { return now.Add(-time.Minute) },
+		restartBridge:   func() error { bridgeRestarts++; return nil },
+		notify:          func(title, _ string) { notes = append(notes, title) },
+		now:             func() time.Time { return now },
+		load:            func() (*WatchdogReport, error) { return saved, nil },
+		save:            func(r *WatchdogReport) error { saved = r; return nil },
+	}
+	return w, &sessionRestarts, &bridgeRestarts, &notes
+}
+
+func TestConductorWatchdog_HealthyDoesNothing(t *testing.T) {
+	now := time.Now()
+	w, sessionRestarts, bridgeRestarts, notes := fakeWatchdog(now)
+	r := w.Run()
+	if r == nil || !r.Conductors["ops"].Healthy || r.Bridge == nil || !r.Bridge.Healthy {
+		t.Fatalf("report = %+v, want all healthy", r)
+	}
+	if *sessionRestarts+*bridgeRestarts != 0 || len(*notes) != 0 {
+		t.Errorf("restarts = %d/%d, notes = %v; want none", *sessionRestarts, *bridgeRestarts, *notes)
+	}
+}
+
+func TestConductorWatchdog_RestartsErroredConductorOnly(t *testing.T) {
+	now := time.Now()
+	w, sessionRestarts, _, notes := fakeWatchdog(now)
+	w.sessionStatus = func(ConductorMeta) string { return string(StatusStopped) }
+	w.Run()
+	if *sessionRestarts != 0 {
+		t.Fatalf("stopped conductor restarted")
+	}
+
+	w.sessionStatus = func(ConductorMeta) string { return string(StatusError) }
+	r := w.Run()
+	if *sessionRestarts != 1 {
+		t.Fatalf("sessionRestarts = %d, want 1", *sessionRestarts)
+	}
+	if got := r.Conductors["ops"]; got.Healthy || len(got.Restarts) != 1 || !got.LastRestartAt.Equal(now) {
+		t.Errorf("target = %+v", got)
+	}
+	if len(*notes) != 1 {
+		t.Errorf("notes = %v, want one restart notification", *notes)
+	}
+}
+
+func TestConductorWatchdog_StaleAndStoppedBridge(t *testing.T) {
+	now := time.Now()
+	w, _, bridgeRestarts, _ := fakeWatchdog(now)
+	w.bridgeLastBeat = func() time.Time { return now.Add(-11 * time.Minute) }
+	r := w.Run()
+	if *bridgeRestarts != 1 || r.Bridge.Healthy || r.Bridge.Problem == "" {
+		t.Fatalf("stale bridge: restarts = %d, bridge = %+v", *bridgeRestarts, r.Bridge)
+	}
+
+	w.bridgeLastBeat = func() time.Time { return now }
+	w.bridgeRunning = func() bool { return false }
+	r = w.Run()
+	if *bridgeRestarts != 2 || r.Bridge.Problem != "daemon not running" {
+		t.Fatalf("stopped bridge: restarts = %d, bridge = %+v", *bridgeRestarts, r.Bridge)
+	}
+
+	w.bridgeInstalled = func() bool { return false }
+	if r = w.Run(); r.Bridge != nil {
+		t.Errorf("uninstalled bridge reported: %+v", r.Bridge)
+	}
+}
+
+func TestConductorWatchdog_RestartCapPerHour(t *testing.T) {
+	now := time.Now()
+	w, sessionRestarts, _, notes := fakeWatchdog(now)
+	w.settings = func() WatchdogSettings { return WatchdogSettings{MaxRestartsPerHour: 2, Notify: true} }
+	w.sessionStatus = func(ConductorMeta) string { return string(StatusError) }
+	w.restartSession = func(ConductorMeta) error { *sessionRestarts++; return errors.New("boom") }
+
+	var r *WatchdogReport
+	for i := range 4 {
+		w.now = func() time.Time { return now.Add(time.Duration(i) * time.Minute) }
+		r = w.Run()
+	}
+	if *sessionRestarts != 2 {
+		t.Fatalf("sessionRestarts = %d, want 2 (capped)", *sessionRestarts)
+	}
+	got := r.Conductors["ops"]
+	if !got.GaveUp || got.RestartError != "boom" {
+		t.Errorf("target = %+v, want gave up with the restart error", got)
+	}
+	// Two failed restarts, then one "gave up" — not one per capped pass.
+	if len(*notes) != 3 {
+		t.Errorf("notes = %v, want 3", *notes)
+	}
+
+	// An hour later the cap has rolled off.
+	w.now = func() time.Time { return now.Add(time.Hour + 2*time.Minute) }
+	w.Run()
+	if *sessionRestarts != 3 {
+		t.Errorf("sessionRestarts = %d after the hour, want 3", *sessionRestarts)
+	}
+}
+
+func TestConductorWatchdog_Disabled(t *testing.T) {
+	w, _, _, _ := fakeWatchdog(time.Now())
+	w.settings = func() WatchdogSettings { return WatchdogSettings{Disabled: true} }
+	if r := w.Run(); r != nil {
+		t.Errorf("disabled watchdog ran: %+v", r)
+	}
+}
@@ -1,14 +1,18 @@
 package session
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/asheshgoplani/agent-deck/internal/agentpaths"
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
 )
 
 const (
@@ -313,7 +317,8 @@ func SetDefaultProfile(profile string) error {
 
 // GetEffectiveProfile returns the profile to use, considering:
 // 1. Explicitly provided profile (from -p flag)
-// 2. Environment variable AGENTDECK_PROFILE
+// 2. Environment variable AGENTDECK_PROFILE (inside an agent-deck session,
+// the value currently set on its tmux session; see liveSessionProfile)
 // 3. Inferred from CLAUDE_CONFIG_DIR (e.g. ~/.claude-work -> "work")
 // 4. Config default profile
 // 5. Fallback to "default"
@@ -328,6 +333,9 @@ func GetEffectiveProfile(explicit string) string {
 		return explicit
 	}
 
+	if live := liveSessionProfile(); live != "" {
+		return live
+	}
 	if envProfile := os.Getenv("AGENTDECK_PROFILE"); envProfile != "" {
 		return envProfile
 	}
@@ -348,6 +356,38 @@ func GetEffectiveProfile(explicit string) string {
 	return DefaultProfile
 }
 
+var (
+	liveProfileOnce  sync.Once
+	liveProfileValue string
+)
+
+// liveSessionProfile returns AGENTDECK_PROFILE as currently set on the tmux
+// session this process runs in, or "" outside an agent-deck session. The
+// agent and every hook or CLI call it spawns inherit the value exported by
+// the launch command, which goes stale when `session move` retargets the
+// session to another profile; the tmux session environment is what
+// RetargetLiveProfile rewrites, so it wins. Looked up once per process.
+func liveSessionProfile() string {
+	pane := os.Getenv("TMUX_PANE")
+	if os.Getenv("AGENTDECK_INSTANCE_ID") == "" || os.Getenv("TMUX") == "" || pane == "" {
+		return ""
+	}
+	liveProfileOnce.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		// No socket: inside tmux the client finds its server through $TMUX.
+		out, err := tmux.ExecContext(ctx, "", "show-environment", "-t", pane, "AGENTDECK_PROFILE").Output()
+		if err != nil {
+			return
+		}
+		liveProfileValue, _ = strings.CutPrefix(strings.TrimSpace(string(out)), "AGENTDECK_PROFILE=")
+		if strings.HasPrefix(liveProfileValue, "-") {
+			liveProfileValue = "" // removed from the session environment
+		}
+	})
+	return liveProfileValue
+}
+
 // profileFromClaudeConfigDir maps a CLAUDE_CONFIG_DIR path to a profile name.
 // The supported shapes mirror the cdw / cdp shell aliases that drive the
 // dual-profile setup:
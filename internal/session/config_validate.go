@@ -0,0 +1,458 @@
+package session
+
+// Strict validation of config.toml.
+//
+// LoadUserConfig decodes config.toml leniently: a key it does not know is
+// dropped without a word, so a typo such as [mcp.serena] for [mcps.serena]
+// silently disables the MCP. ValidateUserConfig reports those keys, with
+// their line and column and the closest known key, plus definitions that
+// parse but cannot work: an MCP without a command or url, a status pattern
+// that is not a valid regexp, an event hook with a broken template.
+//
+// LoadUserConfig records the issues (UserConfigIssues) but keeps loading:
+// an unknown key may be a setting from a newer agent-deck, and refusing to
+// start over it would be worse than the typo. `agent-deck config validate`
+// and `agent-deck doctor` print them.
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/asheshgoplani/agent-deck/internal/experiments"
+)
+
+// ConfigIssue is one problem found in config.toml. Line and Col are
+// 1-based; 0 means the position is unknown.
+type ConfigIssue struct {
+	Line    int    `json:"line,omitempty"`
+	Col     int    `json:"col,omitempty"`
+	Key     string `json:"key,omitempty"`
+	Message string `json:"message"`
+}
+
+// String formats the issue as "line:col: message", the way compilers do.
+func (i ConfigIssue) String() string {
+	if i.Line == 0 {
+		return i.Message
+	}
+	return fmt.Sprintf("%d:%d: %s", i.Line, i.Col, i.Message)
+}
+
+// ValidateUserConfigFile reads and validates the config file at path. A
+// syntax error is returned as the only issue. The error is for a file that
+// cannot be read at all.
+func ValidateUserConfigFile(path string) ([]ConfigIssue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ValidateUserConfig(string(data)), nil
+}
+
+// ValidateUserConfig validates config.toml content.
+func ValidateUserConfig(content string) []ConfigIssue {
+	var config UserConfig
+	md, err := toml.Decode(content, &config)
+	if err != nil {
+		return []ConfigIssue{configParseIssue(err)}
+	}
+	return validateDecodedConfig(content, md, &config)
+}
+
+// configParseIssue turns a decode error into an issue, keeping the
+// position of a syntax or type error.
+func configParseIssue(err error) ConfigIssue {
+	var perr toml.ParseError
+	if errors.As(err, &perr) {
+		return ConfigIssue{Line: perr.Position.Line, Col: perr.Position.Col, Message: perr.Message}
+	}
+	return ConfigIssue{Message: err.Error()}
+}
+
+// validateDecodedConfig runs every check on an already decoded config.
+func validateDecodedConfig(content string, md toml.MetaData, config *UserConfig) []ConfigIssue {
+	pos := scanConfigKeyPositions(content)
+	var issues []ConfigIssue
+	add := func(key toml.Key, msg string) {
+		line, col := pos.find(key)
+		issues = append(issues, ConfigIssue{Line: line, Col: col, Key: key.String(), Message: msg})
+	}
+
+	for _, key := range unknownConfigKeys(md) {
+		msg := fmt.Sprintf("unknown key %q", key.String())
+		if s := suggestConfigKey(key); s != "" {
+			msg += fmt.Sprintf(" (did you mean %q?)", s)
+		}
+		add(key, msg)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(config.MCPs)) {
+		for _, msg := range validateMCPDef(config.MCPs[name]) {
+			add(configKey("mcps", name), fmt.Sprintf("mcp %q: %s", name, msg))
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(config.Tools)) {
+		for _, msg := range validateToolPatterns(config.Tools[name]) {
+			add(configKey("tools", name), fmt.Sprintf("tool %q: %s", name, msg))
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(config.Plugins)) {
+		if err := validatePluginDef(name, config.Plugins[name]); err != nil {
+			add(configKey("plugins", name), err.Error())
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(config.Layouts)) {
+		if err := config.Layouts[name].Validate(); err != nil {
+			add(configKey("layouts", name), fmt.Sprintf("layout %q: %v", name, err))
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(config.Themes)) {
+		if err := ValidateThemeDef(name, config.Themes[name]); err != nil {
+			add(configKey("themes", name), err.Error())
+		}
+	}
+	for _, h := range config.Events.Hooks {
+		if err := h.Validate(); err != nil {
+			add(toml.Key{"events", "hook"}, err.Error())
+		}
+	}
+	if tpl := config.Experiments.Template; tpl != "" {
+		if _, err := experiments.ParseTemplate(tpl); err != nil {
+			add(toml.Key{"experiments", "template"}, err.Error())
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(config.Experiments.Groups)) {
+		if tpl := config.Experiments.Groups[name].Template; tpl != "" {
+			if _, err := experiments.ParseTemplate(tpl); err != nil {
+				add(configKey("experiments", "groups", name, "template"), err.Error())
+			}
+		}
+	}
+	if err := config.AutoRespond.Validate(); err != nil {
+		add(toml.Key{"auto_respond"}, err.Error())
+	}
+	if push := config.Notifications.Push; push.Provider != "" {
+		if err := push.Validate(); err != nil {
+			add(toml.Key{"notifications", "push"}, err.Error())
+		}
+	}
+	if err := config.Notifications.Digest.Validate(); err != nil {
+		add(toml.Key{"notifications", "digest", "minutes"}, err.Error())
+	}
+
+	sort.SliceStable(issues, func(a, b int) bool {
+		if issues[a].Line == 0 || issues[b].Line == 0 {
+			return issues[a].Line != 0
+		}
+		return issues[a].Line < issues[b].Line
+	})
+	return issues
+}
+
+// unknownConfigKeys returns the keys the decoder did not use, each cut at
+// its first part that is not a known key: a misspelled table is one issue,
+// not one per line under it.
+func unknownConfigKeys(md toml.MetaData) []toml.Key {
+	seen := map[string]bool{}
+	var keys []toml.Key
+	for _, key := range md.Undecoded() {
+		key = key[:unknownConfigKeyDepth(key)]
+		if !seen[key.String()] {
+			seen[key.String()] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// unknownConfigKeyDepth returns how many parts of key it takes to reach a
+// key UserConfig does not have, or len(key) when every part is known.
+func unknownConfigKeyDepth(key toml.Key) int {
+	t := reflect.TypeOf(UserConfig{})
+	for i, part := range key {
+		if t = configFieldType(t, part); t == nil {
+			return i + 1
+		}
+	}
+	return len(key)
+}
+
+// validateMCPDef returns what is wrong with one [mcps.<name>] entry.
+func validateMCPDef(def MCPDef) []string {
+	var problems []string
+	if def.Command == "" && def.URL == "" {
+		problems = append(problems, "needs command (stdio) or url (http/sse)")
+	}
+	switch def.Transport {
+	case "", "stdio", "http", "sse":
+	default:
+		problems = append(problems, fmt.Sprintf("unknown transport %q (want stdio, http or sse)", def.Transport))
+	}
+	if def.Transport != "" && def.Transport != "stdio" && def.URL == "" {
+		problems = append(problems, fmt.Sprintf("transport %q needs a url", def.Transport))
+	}
+	if def.Server != nil {
+		if def.URL == "" {
+			problems = append(problems, "server is only used with url (it starts the http server the url points at)")
+		}
+		if def.Server.Command == "" {
+			problems = append(problems, "server needs a command")
+		}
+	}
+	return problems
+}
+
+// validateToolPatterns compiles the regexps of one [tools.<name>] entry.
+// Status detection skips a bad pattern at runtime, which makes the typo
+// invisible; here it is reported.
+func validateToolPatterns(def ToolDef) []string {
+	var problems []string
+	check := func(field, pattern string) {
+		if _, err := regexp.Compile(pattern); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: invalid regexp %q: %v", field, pattern, err))
+		}
+	}
+	prefixed := func(field string, patterns []string) {
+		for _, p := range patterns {
+			if re, ok := strings.CutPrefix(p, "re:"); ok {
+				check(field, re)
+			}
+		}
+	}
+	for _, p := range def.DetectPatterns {
+		check("detect_patterns", p)
+	}
+	prefixed("busy_patterns", def.BusyPatterns)
+	prefixed("prompt_patterns", def.PromptPatterns)
+	prefixed("busy_patterns_extra", def.BusyPatternsExtra)
+	prefixed("prompt_patterns_extra", def.PromptPatternsExtra)
+	if def.Patterns != nil {
+		for _, p := range def.Patterns.BusyRegexps {
+			check("patterns.busy_regexps", p)
+		}
+		for _, p := range def.Patterns.PromptRegexps {
+			check("patterns.prompt_regexps", p)
+		}
+	}
+	return problems
+}
+
+// suggestConfigKey returns the known key closest to the last part of key,
+// or "" when nothing is close. Only struct levels have a fixed key set;
+// under a map (the name in [mcps.<name>]) any key is valid.
+func suggestConfigKey(key toml.Key) string {
+	t := reflect.TypeOf(UserConfig{})
+	for _, part := range key[:len(key)-1] {
+		t = configFieldType(t, part)
+		if t == nil {
+			return ""
+		}
+	}
+	t = derefConfigType(t)
+	if t.Kind() != reflect.Struct {
+		return ""
+	}
+	name := key[len(key)-1]
+	best, bestDist := "", 3
+	for _, candidate := range configTagNames(t) {
+		if d := editDistance(name, candidate); d < bestDist && d < len(name) {
+			best, bestDist = candidate, d
+		}
+	}
+	return best
+}
+
+// configFieldType returns the type found under key part of t, or nil.
+func configFieldType(t reflect.Type, part string) reflect.Type {
+	t = derefConfigType(t)
+	switch t.Kind() {
+	case reflect.Map:
+		return t.Elem()
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if configTagName(t.Field(i)) == part {
+				return t.Field(i).Type
+			}
+		}
+	}
+	return nil
+}
+
+// derefConfigType strips pointers and slices (arrays of tables).
+func derefConfigType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return t
+}
+
+func configTagNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		if name := configTagName(t.Field(i)); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func configTagName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("toml"), ",")
+	if name == "-" || !f.IsExported() {
+		return ""
+	}
+	return name
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// configKey builds a key path from its parts.
+func configKey(parts ...string) toml.Key {
+	return toml.Key(parts)
+}
+
+// configKeyPositions maps each key path of a TOML file to where it first
+// appears. The decoder does not expose key positions, so the file is
+// scanned for table headers and key/value lines.
+type configKeyPositions map[string][2]int
+
+// find returns the position of key, or of its closest ancestor that
+// appears in the file (keys inside an inline table have no line of their
+// own).
+func (p configKeyPositions) find(key toml.Key) (line, col int) {
+	for n := len(key); n > 0; n-- {
+		if at, ok := p[key[:n].String()]; ok {
+			return at[0], at[1]
+		}
+	}
+	return 0, 0
+}
+
+// scanConfigKeyPositions records the position of every table header and
+// key in content. Values are not parsed beyond skipping multi-line
+// strings, whose lines could otherwise look like keys.
+func scanConfigKeyPositions(content string) configKeyPositions {
+	pos := configKeyPositions{}
+	record := func(parts []string, line, col int) {
+		for n := 1; n <= len(parts); n++ {
+			k := toml.Key(parts[:n]).String()
+			if _, ok := pos[k]; !ok {
+				pos[k] = [2]int{line, col}
+			}
+		}
+	}
+	var table []string
+	inMultiline := ""
+	for i, raw := range strings.Split(content, "\n") {
+		line := i + 1
+		if inMultiline != "" {
+			if strings.Count(raw, inMultiline)%2 == 1 {
+				inMultiline = ""
+			}
+			continue
+		}
+		trimmed := strings.TrimLeft(raw, " \t")
+		col := len(raw) - len(trimmed) + 1
+		switch {
+		case trimmed == "" || trimmed[0] == '#':
+			continue
+		case strings.HasPrefix(trimmed, "[["):
+			if parts, _, ok := parseTOMLKey(trimmed[2:]); ok {
+				table = parts
+				record(parts, line, col)
+			}
+			continue
+		case trimmed[0] == '[':
+			if parts, _, ok := parseTOMLKey(trimmed[1:]); ok {
+				table = parts
+				record(parts, line, col)
+			}
+			continue
+		}
+		parts, rest, ok := parseTOMLKey(trimmed)
+		if !ok || !strings.HasPrefix(rest, "=") {
+			continue
+		}
+		record(append(append([]string(nil), table...), parts...), line, col)
+		for _, delim := range []string{`"""`, `'''`} {
+			if strings.Count(rest, delim)%2 == 1 {
+				inMultiline = delim
+				break
+			}
+		}
+	}
+	return pos
+}
+
+// parseTOMLKey parses a dotted key (bare or quoted parts) at the start of
+// s and returns its parts and the rest of s after trailing whitespace.
+func parseTOMLKey(s string) (parts []string, rest string, ok bool) {
+	for {
+		s = strings.TrimLeft(s, " \t")
+		if s == "" {
+			return nil, "", false
+		}
+		var part string
+		switch s[0] {
+		case '"', '\'':
+			end := 1
+			for end < len(s) && s[end] != s[0] {
+				if s[0] == '"' && s[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(s) {
+				return nil, "", false
+			}
+			part, s = s[1:end], s[end+1:]
+		default:
+			end := 0
+			for end < len(s) && isBareKeyChar(s[end]) {
+				end++
+			}
+			if end == 0 {
+				return nil, "", false
+			}
+			part, s = s[:end], s[end:]
+		}
+		parts = append(parts, part)
+		s = strings.TrimLeft(s, " \t")
+		if !strings.HasPrefix(s, ".") {
+			return parts, s, true
+		}
+		s = s[1:]
+	}
+}
+
+func isBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
@@ -0,0 +1,105 @@
+package session
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateUserConfig_UnknownKeys(t *testing.T) {
+	content := `default_tool = "claude"
+
+[mcp.serena]
+command = "uvx"
+args = ["serena"]
+
+[claude]
+config_dri = "~/.claude-work"
+
+[mcps.exa]
+command = "npx"
+env = { EXA_API_KEY = "k" }
+`
+	issues := ValidateUserConfig(content)
+	if len(issues) != 2 {
+		t.Fatalf("issues = %+v, want 2 (one per misspelled key, not per child)", issues)
+	}
+	if got := issues[0].String(); got != `3:1: unknown key "mcp" (did you mean "mcps"?)` {
+		t.Errorf("issue[0] = %q", got)
+	}
+	if got := issues[1].String(); got != `8:1: unknown key "claude.config_dri" (did you mean "config_dir"?)` {
+		t.Errorf("issue[1] = %q", got)
+	}
+}
+
+func TestValidateUserConfig_Definitions(t *testing.T) {
+	content := `[mcps.broken]
+description = "no command"
+
+[mcps.remote]
+transport = "websocket"
+url = "http://localhost:8000/mcp"
+
+[tools.mytool]
+command = "mytool"
+busy_patterns = ["working", "re:(unclosed"]
+
+[tools.claude.patterns]
+prompt_regexps = ["[z-a]"]
+
+[[events.hook]]
+on = "running->waiting"
+command = "notify-send {{.Titel"
+`
+	issues := ValidateUserConfig(content)
+	want := []string{
+		`1:1: mcp "broken": needs command (stdio) or url (http/sse)`,
+		`4:1: mcp "remote": unknown transport "websocket"`,
+		`8:1: tool "mytool": busy_patterns: invalid regexp "(unclosed"`,
+		`12:1: tool "claude": patterns.prompt_regexps: invalid regexp "[z-a]"`,
+		`15:1: event hook "running->waiting"`,
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("issues = %+v, want %d", issues, len(want))
+	}
+	for i, w := range want {
+		if !strings.HasPrefix(issues[i].String(), w) {
+			t.Errorf("issue[%d] = %q, want prefix %q", i, issues[i].String(), w)
+		}
+	}
+}
+
+func TestValidateUserConfig_SyntaxErrorAndClean(t *testing.T) {
+	issues := ValidateUserConfig("[claude]\nconfig_dir = \"unterminated\n")
+	if len(issues) != 1 || issues[0].Line != 2 {
+		t.Fatalf("syntax error issues = %+v, want one on line 2", issues)
+	}
+
+	clean := `theme = "dark"
+
+[mcps.exa]
+command = "npx"
+args = ["-y", "exa-mcp-server"]
+
+[tools.claude]
+prompt_patterns_extra = ["re:^Continue\\?"]
+`
+	if issues := ValidateUserConfig(clean); len(issues) != 0 {
+		t.Fatalf("clean config issues = %+v", issues)
+	}
+}
+
+func TestUserConfigIssues_RecordedOnLoad(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("AGENT_DECK_HOME", "")
+	writeUserConfigForTest(t, home, "[mcp.serena]\ncommand = \"uvx\"\n")
+
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		t.Fatalf("an unknown key must not fail the load: %v", err)
+	}
+	issues := UserConfigIssues()
+	if len(issues) != 1 || issues[0].Key != "mcp" {
+		t.Fatalf("UserConfigIssues() = %+v, want the unknown [mcp] table", issues)
+	}
+}
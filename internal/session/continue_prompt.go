@@ -0,0 +1,81 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultContinuePrompt is the text `session continue` (and the TUI continue
+// key) sends when neither [continue] nor the tool defaults set one.
+const DefaultContinuePrompt = "continue"
+
+// defaultContinuePrompts are the built-in per-tool continuation prompts,
+// keyed by the tool a session is compatible with.
+var defaultContinuePrompts = map[string]string{
+	"claude": "continue",
+	"codex":  "continue",
+	"gemini": "Please continue.",
+}
+
+// ContinueSettings configures the one-key "continue" nudge.
+type ContinueSettings struct {
+	// Prompt overrides the continuation text for every tool without an
+	// entry in Tools.
+	Prompt string `toml:"prompt,omitempty"`
+
+	// Tools maps a tool name (claude, codex, gemini, or a custom tool) to
+	// its continuation text.
+	Tools map[string]string `toml:"tools,omitempty"`
+}
+
+// GetContinueSettings returns the [continue] settings from config.toml.
+func GetContinueSettings() ContinueSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return ContinueSettings{}
+	}
+	return config.Continue
+}
+
+// PromptFor returns the continuation text for tool. Lookup order: the
+// tool's own entry in Tools, the entry of the tool it is compatible with,
+// Prompt, the built-in per-tool default, then DefaultContinuePrompt.
+func (s ContinueSettings) PromptFor(tool string) string {
+	base := continueBaseTool(tool)
+	for _, name := range []string{tool, base} {
+		if p := strings.TrimSpace(s.Tools[name]); name != "" && p != "" {
+			return p
+		}
+	}
+	if p := strings.TrimSpace(s.Prompt); p != "" {
+		return p
+	}
+	if p, ok := defaultContinuePrompts[base]; ok {
+		return p
+	}
+	return DefaultContinuePrompt
+}
+
+// continueBaseTool maps custom tools onto the built-in tool they wrap.
+func continueBaseTool(tool string) string {
+	switch {
+	case IsClaudeCompatible(tool):
+		return "claude"
+	case IsCodexCompatible(tool):
+		return "codex"
+	}
+	return tool
+}
+
+// CheckContinuable reports why a session with the given status cannot take a
+// continuation prompt right now, or nil when it is idle or waiting. Busy
+// sessions are refused rather than interrupted mid-turn.
+func CheckContinuable(title string, status Status) error {
+	switch status {
+	case StatusIdle, StatusWaiting:
+		return nil
+	case StatusRunning, StatusStarting:
+		return fmt.Errorf("session '%s' is busy (%s); not sending continue", title, status)
+	}
+	return fmt.Errorf("session '%s' is %s; start it before continuing", title, status)
+}
@@ -0,0 +1,39 @@
+package session
+
+import "testing"
+
+func TestContinueSettings_PromptFor(t *testing.T) {
+	tests := []struct {
+		name string
+		s    ContinueSettings
+		tool string
+		want string
+	}{
+		{"default", ContinueSettings{}, "claude", "continue"},
+		{"unknown tool", ContinueSettings{}, "aider", DefaultContinuePrompt},
+		{"built-in per tool", ContinueSettings{}, "gemini", "Please continue."},
+		{"global override", ContinueSettings{Prompt: "go on"}, "gemini", "go on"},
+		{"tool override wins", ContinueSettings{Prompt: "go on", Tools: map[string]string{"codex": "next step"}}, "codex", "next step"},
+		{"blank tool entry ignored", ContinueSettings{Tools: map[string]string{"claude": "  "}}, "claude", "continue"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.PromptFor(tt.tool); got != tt.want {
+				t.Errorf("PromptFor(%q) = %q, want %q", tt.tool, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckContinuable(t *testing.T) {
+	for _, st := range []Status{StatusIdle, StatusWaiting} {
+		if err := CheckContinuable("s", st); err != nil {
+			t.Errorf("CheckContinuable(%s) = %v, want nil", st, err)
+		}
+	}
+	for _, st := range []Status{StatusRunning, StatusStarting, StatusStopped, StatusError} {
+		if err := CheckContinuable("s", st); err == nil {
+			t.Errorf("CheckContinuable(%s) = nil, want refusal", st)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// controlSocketName is the JSON-RPC control socket's file name inside the
+// profile directory.
+const controlSocketName = "control.sock"
+
+// ControlSettings configures the JSON-RPC control socket served by the TUI.
+type ControlSettings struct {
+	// Enabled serves the socket. Default: true.
+	Enabled *bool `toml:"enabled,omitempty"`
+}
+
+// GetEnabled reports whether the control socket should be served.
+func (c ControlSettings) GetEnabled() bool {
+	if c.Enabled == nil {
+		return true
+	}
+	return *c.Enabled
+}
+
+// GetControlSettings returns the [control] settings from config.toml.
+func GetControlSettings() ControlSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return ControlSettings{}
+	}
+	return config.Control
+}
+
+// ControlSocketPath returns where a process serving profile listens.
+func ControlSocketPath(profile string) (string, error) {
+	profileDir, err := GetProfileDir(GetEffectiveProfile(profile))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(profileDir, controlSocketName), nil
+}
+
+// FindControlSocket returns the control socket a live agent-deck process
+// registered for profile in state.db, or "" when none is running. Only
+// state.db is opened; sessions are not loaded.
+func FindControlSocket(profile string) (string, error) {
+	dbPath, err := GetDBPathForProfile(GetEffectiveProfile(profile))
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(dbPath); err != nil {
+		return "", nil
+	}
+	db, err := statedb.Open(dbPath)
+	if err != nil {
+		return "", err
+	}
+	// Read-only lookup: skip Close's WAL checkpoint (see
+	// ReadCachedStatusCounters).
+	defer db.DB().Close()
+	path, err := db.LiveControlSocket()
+	if err != nil && !isMissingControlColumn(err) {
+		return "", err
+	}
+	return path, nil
+}
+
+// isMissingControlColumn reports a state.db not yet migrated to v15, whose
+// live processes predate the control socket.
+func isMissingControlColumn(err error) bool {
+	return err != nil && (strings.Contains(err.Error(), "no such column") || strings.Contains(err.Error(), "no such table"))
+}
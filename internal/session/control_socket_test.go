@@ -0,0 +1,56 @@
+package session
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestFindControlSocket(t *testing.T) {
+	channelsTestEnv(t)
+	t.Setenv("AGENTDECK_PROFILE", "")
+
+	if path, err := FindControlSocket(""); err != nil || path != "" {
+		t.Fatalf("profile without state.db = %q, %v; want empty", path, err)
+	}
+
+	storage, err := NewStorageWithProfile("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer storage.Close()
+	db := storage.GetDB()
+	if err := db.RegisterInstance(false); err != nil {
+		t.Fatal(err)
+	}
+	if path, _ := FindControlSocket(""); path != "" {
+		t.Fatalf("no socket registered yet, got %q", path)
+	}
+
+	want, err := ControlSocketPath("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(want) != controlSocketName {
+		t.Errorf("ControlSocketPath = %q, want %s in the profile dir", want, controlSocketName)
+	}
+	if err := db.SetControlSocket(want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := FindControlSocket("")
+	if err != nil {
+		t.Fatalf("FindControlSocket: %v", err)
+	}
+	if got != want {
+		t.Errorf("FindControlSocket = %q, want %q", got, want)
+	}
+}
+
+func TestControlSettingsDefaultEnabled(t *testing.T) {
+	if !(ControlSettings{}).GetEnabled() {
+		t.Error("control socket should default to enabled")
+	}
+	off := false
+	if (ControlSettings{Enabled: &off}).GetEnabled() {
+		t.Error("enabled = false not honored")
+	}
+}
@@ -0,0 +1,205 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Session dependencies let a session declare prerequisites ("start the
+// backend before the frontend"). Each Instance lists the IDs it depends on
+// in DependsOn; DependencyGraph resolves those edges across a profile's
+// sessions into a start order. Edges pointing at sessions that no longer
+// exist are ignored rather than treated as errors, so deleting a
+// prerequisite never wedges its dependents.
+
+// ErrDependencyCycle is returned when resolving or adding a dependency would
+// make a session (transitively) depend on itself.
+var ErrDependencyCycle = errors.New("dependency cycle")
+
+// DependencyGraph indexes sessions by ID for dependency resolution.
+type DependencyGraph struct {
+	byID  map[string]*Instance
+	order []*Instance
+}
+
+// NewDependencyGraph builds a graph over instances. Nil entries are skipped.
+func NewDependencyGraph(instances []*Instance) *DependencyGraph {
+	g := &DependencyGraph{byID: make(map[string]*Instance, len(instances))}
+	for _, inst := range instances {
+		if inst == nil {
+			continue
+		}
+		g.byID[inst.ID] = inst
+		g.order = append(g.order, inst)
+	}
+	return g
+}
+
+// Dependencies returns id's direct prerequisites that still exist, in
+// declaration order.
+func (g *DependencyGraph) Dependencies(id string) []*Instance {
+	inst := g.byID[id]
+	if inst == nil {
+		return nil
+	}
+	var deps []*Instance
+	for _, depID := range inst.DependsOn {
+		if dep := g.byID[depID]; dep != nil {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+// Dependents returns the sessions that list id as a direct prerequisite.
+func (g *DependencyGraph) Dependents(id string) []*Instance {
+	var out []*Instance
+	for _, inst := range g.order {
+		for _, depID := range inst.DependsOn {
+			if depID == id {
+				out = append(out, inst)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// StartOrder returns id's transitive prerequisites in the order they must
+// start, followed by the session itself. Each session appears once even
+// when several paths lead to it. Returns ErrDependencyCycle (wrapped with
+// the offending path) when the prerequisites loop back on themselves.
+func (g *DependencyGraph) StartOrder(id string) ([]*Instance, error) {
+	if g.byID[id] == nil {
+		return nil, fmt.Errorf("session not found: %s", id)
+	}
+	const (
+		visiting = iota + 1
+		done
+	)
+	state := make(map[string]int)
+	var order []*Instance
+	var path []string
+
+	var visit func(string) error
+	visit = func(cur string) error {
+		switch state[cur] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, g.describePath(append(path, cur)))
+		}
+		state[cur] = visiting
+		path = append(path, cur)
+		for _, dep := range g.Dependencies(cur) {
+			if err := visit(dep.ID); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[cur] = done
+		order = append(order, g.byID[cur])
+		return nil
+	}
+	if err := visit(id); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// CheckDependency reports why id cannot depend on depID: either session is
+// missing, it is a self-dependency, or depID already (transitively) depends
+// on id. Returns nil when the edge is safe to add.
+func (g *DependencyGraph) CheckDependency(id, depID string) error {
+	if g.byID[id] == nil {
+		return fmt.Errorf("session not found: %s", id)
+	}
+	if g.byID[depID] == nil {
+		return fmt.Errorf("session not found: %s", depID)
+	}
+	if id == depID {
+		return fmt.Errorf("%w: a session cannot depend on itself", ErrDependencyCycle)
+	}
+	order, err := g.StartOrder(depID)
+	if err != nil {
+		return err
+	}
+	for _, inst := range order {
+		if inst.ID == id {
+			return fmt.Errorf("%w: %s already depends on %s", ErrDependencyCycle, g.title(depID), g.title(id))
+		}
+	}
+	return nil
+}
+
+// StartPrerequisites starts every stopped prerequisite of id, in start
+// order, using start, and returns the ones it started. Prerequisites that
+// already exist are left alone; the first failure aborts with an error
+// naming the prerequisite.
+func (g *DependencyGraph) StartPrerequisites(id string, start func(*Instance) error) ([]*Instance, error) {
+	order, err := g.StartOrder(id)
+	if err != nil {
+		return nil, err
+	}
+	var started []*Instance
+	for _, dep := range order[:len(order)-1] {
+		if dep.Exists() {
+			continue
+		}
+		if err := start(dep); err != nil {
+			return started, fmt.Errorf("failed to start prerequisite '%s': %w", dep.Title, err)
+		}
+		started = append(started, dep)
+	}
+	return started, nil
+}
+
+func (g *DependencyGraph) title(id string) string {
+	if inst := g.byID[id]; inst != nil && inst.Title != "" {
+		return inst.Title
+	}
+	return id
+}
+
+func (g *DependencyGraph) describePath(ids []string) string {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = g.title(id)
+	}
+	return strings.Join(names, " -> ")
+}
+
+// AddDependency records depID as a prerequisite of i. Returns false when it
+// was already listed. Callers validate the edge with
+// DependencyGraph.CheckDependency first.
+func (i *Instance) AddDependency(depID string) bool {
+	for _, id := range i.DependsOn {
+		if id == depID {
+			return false
+		}
+	}
+	i.DependsOn = append(i.DependsOn, depID)
+	return true
+}
+
+// RemoveDependency drops depID from i's prerequisites. Returns false when it
+// was not listed.
+func (i *Instance) RemoveDependency(depID string) bool {
+	for idx, id := range i.DependsOn {
+		if id == depID {
+			i.DependsOn = append(i.DependsOn[:idx:idx], i.DependsOn[idx+1:]...)
+			if len(i.DependsOn) == 0 {
+				i.ClearDependencies()
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// ClearDependencies removes every prerequisite of i.
+func (i *Instance) ClearDependencies() {
+	i.DependsOn = nil
+	i.dependsOnCleared = true
+}
@@ -0,0 +1,156 @@
+package session
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func depInst(id string, deps ...string) *Instance {
+	return &Instance{ID: id, Title: id, DependsOn: deps}
+}
+
+func instIDs(list []*Instance) []string {
+	ids := make([]string, len(list))
+	for i, inst := range list {
+		ids[i] = inst.ID
+	}
+	return ids
+}
+
+// A diamond (app -> api, web; api, web -> db) starts db once, first.
+func TestDependencyGraph_StartOrder_Diamond(t *testing.T) {
+	g := NewDependencyGraph([]*Instance{
+		depInst("app", "api", "web"),
+		depInst("api", "db"),
+		depInst("web", "db"),
+		depInst("db"),
+	})
+	order, err := g.StartOrder("app")
+	if err != nil {
+		t.Fatalf("StartOrder: %v", err)
+	}
+	if want := []string{"db", "api", "web", "app"}; !reflect.DeepEqual(instIDs(order), want) {
+		t.Fatalf("StartOrder = %v, want %v", instIDs(order), want)
+	}
+	if got := instIDs(g.Dependents("db")); !reflect.DeepEqual(got, []string{"api", "web"}) {
+		t.Fatalf("Dependents(db) = %v", got)
+	}
+}
+
+func TestDependencyGraph_StartOrder_Cycle(t *testing.T) {
+	g := NewDependencyGraph([]*Instance{
+		depInst("a", "b"),
+		depInst("b", "c"),
+		depInst("c", "a"),
+	})
+	_, err := g.StartOrder("a")
+	if !errors.Is(err, ErrDependencyCycle) {
+		t.Fatalf("StartOrder err = %v, want ErrDependencyCycle", err)
+	}
+	if !strings.Contains(err.Error(), "a -> b -> c -> a") {
+		t.Fatalf("cycle error should name the path, got %q", err)
+	}
+}
+
+// Deleting a prerequisite must not wedge its dependents.
+func TestDependencyGraph_IgnoresMissingDependencies(t *testing.T) {
+	g := NewDependencyGraph([]*Instance{depInst("app", "deleted")})
+	order, err := g.StartOrder("app")
+	if err != nil {
+		t.Fatalf("StartOrder: %v", err)
+	}
+	if got := instIDs(order); !reflect.DeepEqual(got, []string{"app"}) {
+		t.Fatalf("StartOrder = %v, want [app]", got)
+	}
+	if deps := g.Dependencies("app"); len(deps) != 0 {
+		t.Fatalf("Dependencies should skip missing sessions, got %v", instIDs(deps))
+	}
+}
+
+func TestDependencyGraph_CheckDependency(t *testing.T) {
+	g := NewDependencyGraph([]*Instance{
+		depInst("app", "api"),
+		depInst("api", "db"),
+		depInst("db"),
+	})
+	if err := g.CheckDependency("app", "db"); err != nil {
+		t.Errorf("app -> db should be allowed: %v", err)
+	}
+	if err := g.CheckDependency("db", "db"); !errors.Is(err, ErrDependencyCycle) {
+		t.Errorf("self dependency err = %v, want ErrDependencyCycle", err)
+	}
+	if err := g.CheckDependency("db", "app"); !errors.Is(err, ErrDependencyCycle) {
+		t.Errorf("db -> app closes a cycle, err = %v", err)
+	}
+	if err := g.CheckDependency("app", "nope"); err == nil || errors.Is(err, ErrDependencyCycle) {
+		t.Errorf("unknown session should be a not-found error, got %v", err)
+	}
+}
+
+func TestDependencyGraph_StartPrerequisites_StopsOnFailure(t *testing.T) {
+	g := NewDependencyGraph([]*Instance{
+		depInst("app", "api"),
+		depInst("api", "db"),
+		depInst("db"),
+	})
+	var calls []string
+	started, err := g.StartPrerequisites("app", func(inst *Instance) error {
+		calls = append(calls, inst.ID)
+		if inst.ID == "api" {
+			return errors.New("boom")
+		}
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "prerequisite 'api'") {
+		t.Fatalf("err = %v, want failure naming api", err)
+	}
+	if !reflect.DeepEqual(calls, []string{"db", "api"}) {
+		t.Fatalf("start calls = %v, want [db api] (never the session itself)", calls)
+	}
+	if got := instIDs(started); !reflect.DeepEqual(got, []string{"db"}) {
+		t.Fatalf("started = %v, want [db]", got)
+	}
+}
+
+func TestInstance_AddRemoveDependency(t *testing.T) {
+	inst := depInst("app")
+	if !inst.AddDependency("db") || inst.AddDependency("db") {
+		t.Fatal("AddDependency should add once and report duplicates")
+	}
+	if inst.RemoveDependency("nope") {
+		t.Fatal("RemoveDependency of an unlisted id should return false")
+	}
+	if !inst.RemoveDependency("db") || len(inst.DependsOn) != 0 {
+		t.Fatalf("RemoveDependency left %v", inst.DependsOn)
+	}
+	if !inst.dependsOnCleared {
+		t.Fatal("removing the last dependency should mark the list cleared for persistence")
+	}
+}
+
+func TestDependsOn_ToolDataRoundTrip(t *testing.T) {
+	td := WriteDependsOnToToolData([]byte(`{"color":"#ff00aa"}`), []string{"db", "api"}, false)
+	if got := ReadDependsOnFromToolData(td); !reflect.DeepEqual(got, []string{"db", "api"}) {
+		t.Fatalf("round-trip = %v", got)
+	}
+	if !strings.Contains(string(td), `"color":"#ff00aa"`) {
+		t.Fatalf("round-trip dropped color: %s", td)
+	}
+
+	// An empty, uncleared list leaves legacy rows byte-identical.
+	legacy := []byte(`{"color":"#ff00aa"}`)
+	if got := WriteDependsOnToToolData(legacy, nil, false); string(got) != string(legacy) {
+		t.Fatalf("empty write changed legacy blob: %s", got)
+	}
+
+	// Clearing writes an explicit [] so MergeToolDataExtras drops the old list.
+	cleared := WriteDependsOnToToolData(td, nil, true)
+	if !strings.Contains(string(cleared), `"depends_on":[]`) {
+		t.Fatalf("cleared blob should carry an explicit empty list: %s", cleared)
+	}
+	if got := ReadDependsOnFromToolData(cleared); got != nil {
+		t.Fatalf("cleared read = %v, want nil", got)
+	}
+}
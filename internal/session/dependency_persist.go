@@ -0,0 +1,61 @@
+// Session dependency JSON helpers.
+//
+// Like idle_timeout_secs (#1143), depends_on lives in the tool_data extras
+// zone so the positional MarshalToolData signature stays unchanged. Because
+// MergeToolDataExtras carries unknown keys forward when the new blob omits
+// them, clearing the list writes an explicit empty array once; an omitted
+// key would resurrect the old prerequisites on the next save.
+package session
+
+import "encoding/json"
+
+const toolDataDependsOnKey = "depends_on"
+
+// WriteDependsOnToToolData merges depends_on into the given tool_data blob.
+// A non-empty ids list is written as-is. An empty list removes the key,
+// unless cleared is set, in which case an explicit [] is written so the
+// merge layer drops the previous value.
+func WriteDependsOnToToolData(td json.RawMessage, ids []string, cleared bool) json.RawMessage {
+	if len(ids) == 0 && !cleared {
+		if len(td) == 0 {
+			return td
+		}
+		m := map[string]json.RawMessage{}
+		if err := json.Unmarshal(td, &m); err != nil {
+			return td
+		}
+		if _, ok := m[toolDataDependsOnKey]; !ok {
+			return td
+		}
+		delete(m, toolDataDependsOnKey)
+		out, _ := json.Marshal(m)
+		return out
+	}
+	m := map[string]json.RawMessage{}
+	if len(td) > 0 {
+		_ = json.Unmarshal(td, &m)
+	}
+	if ids == nil {
+		ids = []string{}
+	}
+	raw, _ := json.Marshal(ids)
+	m[toolDataDependsOnKey] = raw
+	out, _ := json.Marshal(m)
+	return out
+}
+
+// ReadDependsOnFromToolData extracts depends_on from the blob. Returns nil for
+// missing/malformed/legacy rows.
+func ReadDependsOnFromToolData(td json.RawMessage) []string {
+	if len(td) == 0 {
+		return nil
+	}
+	var blob struct {
+		DependsOn []string `json:"depends_on"`
+	}
+	_ = json.Unmarshal(td, &blob)
+	if len(blob.DependsOn) == 0 {
+		return nil
+	}
+	return blob.DependsOn
+}
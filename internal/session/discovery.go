@@ -112,6 +112,8 @@ func FilterByQuery(instances []*Instance, query string) []*Instance {
 		"idle":    StatusIdle,
 		"error":   StatusError,
 		"stopped": StatusStopped,
+		"exited":  StatusExited,
+		"crashed": StatusCrashed,
 	}
 
 	// If query matches a status filter exactly, filter by status
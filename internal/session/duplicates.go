@@ -0,0 +1,223 @@
+package session
+
+// Duplicate-session advisor.
+//
+// Large decks accumulate near-identical sessions created from different entry
+// points (the TUI, `add`, `launch`, `try`, issue import). FindDuplicateSessions
+// clusters sessions that look like the same piece of work so the TUI can
+// suggest merging or removing them. It is advisory only: nothing here mutates
+// sessions.
+//
+// Two sessions are candidates when they work on the same repository (worktree
+// repo root, else project path) and at least one of these holds:
+//
+//   - both sit on the same worktree branch
+//   - their titles are near-identical once auto-suffixes ("api-2", "api (3)")
+//     are dropped
+//   - their latest prompts overlap substantially
+//
+// Fan-out siblings (same parent session) and conductor sessions are never
+// flagged: they are deliberately parallel.
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// duplicateTitleSimilarity is the minimum Jaccard similarity of title
+	// tokens for two titles to count as overlapping.
+	duplicateTitleSimilarity = 0.6
+	// duplicatePromptSimilarity is the minimum Jaccard similarity of prompt
+	// tokens for two prompts to count as overlapping.
+	duplicatePromptSimilarity = 0.6
+	// duplicatePromptMinTokens keeps short prompts ("continue", "yes") from
+	// matching everything.
+	duplicatePromptMinTokens = 4
+)
+
+// DuplicateGroup is a cluster of sessions that look like duplicates of each
+// other, oldest first.
+type DuplicateGroup struct {
+	Sessions []*Instance
+	// Reasons explains why the cluster was formed (deduplicated, in the
+	// order first seen), e.g. "same branch feat/login".
+	Reasons []string
+}
+
+// Key identifies the group by its member IDs, independent of order. Used to
+// remember dismissed suggestions.
+func (g DuplicateGroup) Key() string {
+	ids := make([]string, len(g.Sessions))
+	for i, inst := range g.Sessions {
+		ids[i] = inst.ID
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
+
+// FindDuplicateSessions returns the clusters of likely-duplicate sessions,
+// ordered by the creation time of each cluster's oldest member.
+func FindDuplicateSessions(instances []*Instance) []DuplicateGroup {
+	var candidates []*Instance
+	for _, inst := range instances {
+		if inst == nil || inst.IsArchived() || isConductorSessionTitle(inst.Title) {
+			continue
+		}
+		candidates = append(candidates, inst)
+	}
+
+	parent := make([]int, len(candidates))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+
+	reasons := map[int][]string{}
+	for a := 0; a < len(candidates); a++ {
+		for b := a + 1; b < len(candidates); b++ {
+			reason := duplicateReason(candidates[a], candidates[b])
+			if reason == "" {
+				continue
+			}
+			ra, rb := find(a), find(b)
+			if ra != rb {
+				parent[rb] = ra
+				reasons[ra] = append(reasons[ra], reasons[rb]...)
+				delete(reasons, rb)
+			}
+			reasons[ra] = append(reasons[ra], reason)
+		}
+	}
+
+	clusters := map[int][]*Instance{}
+	for i, inst := range candidates {
+		root := find(i)
+		clusters[root] = append(clusters[root], inst)
+	}
+
+	var groups []DuplicateGroup
+	for root, members := range clusters {
+		if len(members) < 2 {
+			continue
+		}
+		sort.SliceStable(members, func(a, b int) bool {
+			return members[a].CreatedAt.Before(members[b].CreatedAt)
+		})
+		groups = append(groups, DuplicateGroup{Sessions: members, Reasons: dedupeStrings(reasons[root])})
+	}
+	sort.SliceStable(groups, func(a, b int) bool {
+		oa, ob := groups[a].Sessions[0], groups[b].Sessions[0]
+		if !oa.CreatedAt.Equal(ob.CreatedAt) {
+			return oa.CreatedAt.Before(ob.CreatedAt)
+		}
+		return oa.ID < ob.ID
+	})
+	return groups
+}
+
+// duplicateReason returns why a and b look like duplicates, or "".
+func duplicateReason(a, b *Instance) string {
+	if a.ParentSessionID != "" && a.ParentSessionID == b.ParentSessionID {
+		return ""
+	}
+	if a.ParentSessionID == b.ID || b.ParentSessionID == a.ID {
+		return ""
+	}
+	repoA, repoB := duplicateRepoKey(a), duplicateRepoKey(b)
+	if repoA == "" || repoA != repoB {
+		return ""
+	}
+	if a.WorktreeBranch != "" && a.WorktreeBranch == b.WorktreeBranch {
+		return "same branch " + a.WorktreeBranch
+	}
+	if tokenSimilarity(titleTokens(a.Title), titleTokens(b.Title)) >= duplicateTitleSimilarity {
+		return "similar titles"
+	}
+	pa, pb := promptTokens(a.LatestPrompt), promptTokens(b.LatestPrompt)
+	if len(pa) >= duplicatePromptMinTokens && len(pb) >= duplicatePromptMinTokens &&
+		tokenSimilarity(pa, pb) >= duplicatePromptSimilarity {
+		return "similar prompts"
+	}
+	return ""
+}
+
+// duplicateRepoKey is the repository a session works on: the worktree's
+// repo root when it has one, so worktrees of the same repo compare equal.
+func duplicateRepoKey(inst *Instance) string {
+	p := inst.WorktreeRepoRoot
+	if p == "" {
+		p = inst.ProjectPath
+	}
+	if p == "" {
+		return ""
+	}
+	return filepath.Clean(p)
+}
+
+// titleTokens lowercases and splits a title on non-alphanumerics, dropping
+// purely numeric tokens so auto-suffixed copies ("api-2", "api (3)") match.
+func titleTokens(s string) map[string]bool {
+	tokens := map[string]bool{}
+	for _, tok := range splitWords(s) {
+		if strings.IndexFunc(tok, func(r rune) bool { return !unicode.IsDigit(r) }) < 0 {
+			continue
+		}
+		tokens[tok] = true
+	}
+	return tokens
+}
+
+// promptTokens splits a prompt into lowercase words of three or more
+// characters, which skips most filler words.
+func promptTokens(s string) map[string]bool {
+	tokens := map[string]bool{}
+	for _, tok := range splitWords(s) {
+		if len(tok) >= 3 {
+			tokens[tok] = true
+		}
+	}
+	return tokens
+}
+
+func splitWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// tokenSimilarity is the Jaccard index of two token sets (0 when either is
+// empty).
+func tokenSimilarity(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for tok := range a {
+		if b[tok] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+func dedupeStrings(in []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, s := range in {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,94 @@
+package session
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func dupInst(id, title, path string, created time.Duration) *Instance {
+	return &Instance{
+		ID:          id,
+		Title:       title,
+		ProjectPath: path,
+		Tool:        "claude",
+		CreatedAt:   time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC).Add(created),
+	}
+}
+
+func TestFindDuplicateSessions_TitlesAndBranches(t *testing.T) {
+	a := dupInst("a", "api refactor", "/src/api", 0)
+	b := dupInst("b", "api-refactor (2)", "/src/api/", time.Hour)
+	other := dupInst("c", "api refactor", "/src/web", 0) // different repo
+	wt1 := dupInst("w1", "login", "/wt/one", 2*time.Hour)
+	wt1.WorktreeRepoRoot, wt1.WorktreeBranch = "/src/app", "feat/login"
+	wt2 := dupInst("w2", "auth flow", "/wt/two", 3*time.Hour)
+	wt2.WorktreeRepoRoot, wt2.WorktreeBranch = "/src/app", "feat/login"
+
+	groups := FindDuplicateSessions([]*Instance{other, b, wt2, a, wt1})
+	if len(groups) != 2 {
+		t.Fatalf("got %d groups, want 2: %+v", len(groups), groups)
+	}
+	if got := instIDs(groups[0].Sessions); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Fatalf("first group = %v, want [a b] oldest first", got)
+	}
+	if !reflect.DeepEqual(groups[0].Reasons, []string{"similar titles"}) {
+		t.Fatalf("reasons = %v", groups[0].Reasons)
+	}
+	if got := instIDs(groups[1].Sessions); !reflect.DeepEqual(got, []string{"w1", "w2"}) {
+		t.Fatalf("second group = %v, want [w1 w2]", got)
+	}
+	if groups[1].Reasons[0] != "same branch feat/login" {
+		t.Fatalf("branch reason = %v", groups[1].Reasons)
+	}
+	if groups[0].Key() != "a,b" {
+		t.Fatalf("Key() = %q", groups[0].Key())
+	}
+}
+
+func TestFindDuplicateSessions_Prompts(t *testing.T) {
+	a := dupInst("a", "alpha", "/src/api", 0)
+	a.LatestPrompt = "Fix the flaky login test in the auth package"
+	b := dupInst("b", "beta", "/src/api", time.Minute)
+	b.LatestPrompt = "fix flaky login test in auth package please"
+	c := dupInst("c", "gamma", "/src/api", 2*time.Minute)
+	c.LatestPrompt = "continue"
+	d := dupInst("d", "delta", "/src/api", 3*time.Minute)
+	d.LatestPrompt = "continue"
+
+	groups := FindDuplicateSessions([]*Instance{a, b, c, d})
+	if len(groups) != 1 || !reflect.DeepEqual(instIDs(groups[0].Sessions), []string{"a", "b"}) {
+		t.Fatalf("groups = %+v, want only [a b] (short prompts never match)", groups)
+	}
+}
+
+func TestFindDuplicateSessions_SkipsFanOutArchivedAndConductors(t *testing.T) {
+	parent := dupInst("p", "planner", "/src/api", 0)
+	w1 := dupInst("w1", "worker 1", "/src/api", time.Minute)
+	w2 := dupInst("w2", "worker 2", "/src/api", 2*time.Minute)
+	w1.ParentSessionID, w2.ParentSessionID = "p", "p"
+	archived := dupInst("x", "planner", "/src/api", 3*time.Minute)
+	archived.ArchivedAt = time.Now()
+	c1 := dupInst("c1", "conductor-ops", "/src/api", 0)
+	c2 := dupInst("c2", "conductor-ops-2", "/src/api", 0)
+
+	if groups := FindDuplicateSessions([]*Instance{parent, w1, w2, archived, c1, c2}); len(groups) != 0 {
+		t.Fatalf("expected no suggestions, got %+v", groups)
+	}
+}
+
+func TestFindDuplicateSessions_TransitiveCluster(t *testing.T) {
+	a := dupInst("a", "search index", "/src/api", 0)
+	b := dupInst("b", "search index-2", "/src/api", time.Minute)
+	b.WorktreeRepoRoot, b.WorktreeBranch = "/src/api", "feat/search"
+	c := dupInst("c", "reindex", "/src/api/wt", 2*time.Minute)
+	c.WorktreeRepoRoot, c.WorktreeBranch = "/src/api", "feat/search"
+
+	groups := FindDuplicateSessions([]*Instance{a, b, c})
+	if len(groups) != 1 || len(groups[0].Sessions) != 3 {
+		t.Fatalf("groups = %+v, want one cluster of three", groups)
+	}
+	if !reflect.DeepEqual(groups[0].Reasons, []string{"similar titles", "same branch feat/search"}) {
+		t.Fatalf("reasons = %v", groups[0].Reasons)
+	}
+}
@@ -53,6 +53,9 @@ func (i *Instance) buildEnvSourceCommand() string {
 		sources = append(sources, paneWarning("config.toml error — overrides inactive: "+cfgErr.Error()))
 	}
 	if config == nil {
+		if portEnv := buildEnvExports(i.portEnv()); portEnv != "" {
+			sources = append(sources, portEnv)
+		}
 		if restartEnv := buildEnvExports(i.restartEnv); restartEnv != "" {
 			sources = append(sources, restartEnv)
 		}
@@ -139,13 +142,20 @@ func (i *Instance) buildEnvSourceCommand() string {
 		sources = append(sources, conductorEnv)
 	}
 
-	// 8. Explicit restart overrides are applied after every configured source,
+	// 8. Ports claimed in the port registry (PORT, PORT_2, ...). After the
+	//    configured sources so a claimed port wins over a PORT from an
+	//    env_file, which would collide across worktree copies.
+	if portEnv := buildEnvExports(i.portEnv()); portEnv != "" {
+		sources = append(sources, portEnv)
+	}
+
+	// 9. Explicit restart overrides are applied after every configured source,
 	//    so the command-line value wins for this replacement process.
 	if restartEnv := buildEnvExports(i.restartEnv); restartEnv != "" {
 		sources = append(sources, restartEnv)
 	}
 
-	// 9. S8 (v1.7.40) — strip TELEGRAM_STATE_DIR on every non-channel-owning
+	// 10. S8 (v1.7.40) — strip TELEGRAM_STATE_DIR on every non-channel-owning
 	// claude spawn. Fires AFTER all sources and inline env so it wins
 	// over any env_file / inline export that set the variable, and
 	// runs even when no env_file is in play (covers `agent-deck
@@ -0,0 +1,266 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/logging"
+)
+
+var eventHookLog = logging.ForComponent(logging.CompSession)
+
+const (
+	// defaultEventHookTimeout bounds a hook command when timeout_secs is unset.
+	defaultEventHookTimeout = 30 * time.Second
+	// maxConcurrentEventHooks caps in-flight hook commands. A flickering
+	// session or a mass restart must not fork an unbounded number of shells;
+	// transitions arriving while every slot is busy are dropped and logged.
+	maxConcurrentEventHooks = 8
+)
+
+// eventHookSlots is the semaphore behind maxConcurrentEventHooks.
+var eventHookSlots = make(chan struct{}, maxConcurrentEventHooks)
+
+// invalidEventHookWarned records hooks already reported as invalid so a bad
+// pattern is logged once instead of on every transition.
+var invalidEventHookWarned sync.Map
+
+// runEventHookCommand executes one rendered hook command. Swapped in tests.
+var runEventHookCommand = func(ctx context.Context, command, dir string, env []string) error {
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	cmd.WaitDelay = 2 * time.Second
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// EventsSettings maps session status transitions to shell commands:
+//
+//	[[events.hook]]
+//	on = "running->waiting"
+//	command = "notify-send 'agent-deck' {{.Title}}"
+type EventsSettings struct {
+	// Hooks are checked in order; every matching hook runs.
+	Hooks []EventHook `toml:"hook,omitempty"`
+
+	// TimeoutSecs bounds each hook command. Default: 30.
+	TimeoutSecs int `toml:"timeout_secs,omitzero"`
+}
+
+// EventHook is one transition-to-command mapping.
+type EventHook struct {
+	// On selects transitions as "from->to". Either side may be "*" (any
+	// status), and a bare status such as "waiting" means "*->waiting".
+	On string `toml:"on"`
+
+	// Command is a Go text/template run with /bin/sh -c. The fields .ID,
+	// .Title, .Status, .PrevStatus, .Path, .Group, .Tool and .Profile are
+	// shell-quoted on expansion, so they must not be wrapped in quotes again.
+	Command string `toml:"command"`
+
+	// Group limits the hook to sessions in this group and its subgroups.
+	Group string `toml:"group,omitempty"`
+}
+
+// EventHookData is the template data for EventHook.Command. Every field is
+// already shell-quoted; the raw values are exported to the command as
+// AGENTDECK_EVENT_* environment variables.
+type EventHookData struct {
+	ID         string
+	Title      string
+	Status     string
+	PrevStatus string
+	Path       string
+	Group      string
+	Tool       string
+	Profile    string
+}
+
+// Timeout returns the per-command timeout.
+func (s EventsSettings) Timeout() time.Duration {
+	if s.TimeoutSecs > 0 {
+		return time.Duration(s.TimeoutSecs) * time.Second
+	}
+	return defaultEventHookTimeout
+}
+
+// GetEventsSettings returns the [events] settings from config.toml.
+func GetEventsSettings() EventsSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return EventsSettings{}
+	}
+	return config.Events
+}
+
+// eventHookStatuses are the statuses an "on" pattern may name.
+var eventHookStatuses = map[string]bool{
+	string(StatusRunning):  true,
+	string(StatusWaiting):  true,
+	string(StatusIdle):     true,
+	string(StatusError):    true,
+	string(StatusStarting): true,
+	string(StatusStopped):  true,
+	string(StatusExited):   true,
+	string(StatusCrashed):  true,
+}
+
+// parseEventTransition splits an "on" pattern into its from and to sides,
+// with "*" standing for any status.
+func parseEventTransition(on string) (from, to string, err error) {
+	spec := strings.ToLower(strings.TrimSpace(strings.ReplaceAll(on, "→", "->")))
+	from, to = "*", spec
+	if left, right, ok := strings.Cut(spec, "->"); ok {
+		from, to = strings.TrimSpace(left), strings.TrimSpace(right)
+	}
+	for _, side := range []string{from, to} {
+		if side != "*" && !eventHookStatuses[side] {
+			return "", "", fmt.Errorf("invalid transition %q: unknown status %q", on, side)
+		}
+	}
+	return from, to, nil
+}
+
+// Matches reports whether the hook applies to a from→to transition of a
+// session in groupPath. An invalid "on" pattern matches nothing.
+func (h EventHook) Matches(from, to, groupPath string) bool {
+	wantFrom, wantTo, err := parseEventTransition(h.On)
+	if err != nil {
+		return false
+	}
+	if h.Group != "" && groupPath != h.Group && !strings.HasPrefix(groupPath, h.Group+"/") {
+		return false
+	}
+	return (wantFrom == "*" || wantFrom == from) && (wantTo == "*" || wantTo == to)
+}
+
+// Validate checks the transition pattern and command template.
+func (h EventHook) Validate() error {
+	if _, _, err := parseEventTransition(h.On); err != nil {
+		return err
+	}
+	if strings.TrimSpace(h.Command) == "" {
+		return fmt.Errorf("event hook %q: command is empty", h.On)
+	}
+	_, err := h.render(EventHookData{})
+	return err
+}
+
+// render expands the command template. missingkey=error turns a typo like
+// {{.Titel}} into an error instead of an empty argument.
+func (h EventHook) render(data EventHookData) (string, error) {
+	t, err := template.New("event").Option("missingkey=error").Parse(h.Command)
+	if err != nil {
+		return "", fmt.Errorf("event hook %q: %w", h.On, err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("event hook %q: %w", h.On, err)
+	}
+	return buf.String(), nil
+}
+
+// RunEventHooks starts the [events] hooks matching a from→to transition of
+// inst in the background and returns how many were started. Commands run in
+// the session's project directory when it exists; failures are logged, never
+// returned, so a broken hook cannot stall the status loop that reports the
+// transition.
+func RunEventHooks(inst *Instance, profile, from, to string) int {
+	if inst == nil || from == to {
+		return 0
+	}
+	settings := GetEventsSettings()
+	if len(settings.Hooks) == 0 {
+		return 0
+	}
+
+	raw := EventHookData{
+		ID:         inst.ID,
+		Title:      inst.Title,
+		Status:     to,
+		PrevStatus: from,
+		Path:       inst.ProjectPath,
+		Group:      inst.GroupPath,
+		Tool:       inst.Tool,
+		Profile:    profile,
+	}
+	quoted := EventHookData{
+		ID:         shellQuote(raw.ID),
+		Title:      shellQuote(raw.Title),
+		Status:     shellQuote(raw.Status),
+		PrevStatus: shellQuote(raw.PrevStatus),
+		Path:       shellQuote(raw.Path),
+		Group:      shellQuote(raw.Group),
+		Tool:       shellQuote(raw.Tool),
+		Profile:    shellQuote(raw.Profile),
+	}
+	env := []string{
+		"AGENTDECK_EVENT_ID=" + raw.ID,
+		"AGENTDECK_EVENT_TITLE=" + raw.Title,
+		"AGENTDECK_EVENT_STATUS=" + raw.Status,
+		"AGENTDECK_EVENT_PREV_STATUS=" + raw.PrevStatus,
+		"AGENTDECK_EVENT_PATH=" + raw.Path,
+		"AGENTDECK_EVENT_GROUP=" + raw.Group,
+		"AGENTDECK_EVENT_TOOL=" + raw.Tool,
+		"AGENTDECK_EVENT_PROFILE=" + raw.Profile,
+	}
+	dir := ""
+	if st, err := os.Stat(raw.Path); err == nil && st.IsDir() {
+		dir = raw.Path
+	}
+
+	started := 0
+	for _, hook := range settings.Hooks {
+		if err := hook.Validate(); err != nil {
+			if _, warned := invalidEventHookWarned.LoadOrStore(hook.On+"\x00"+hook.Command, true); !warned {
+				eventHookLog.Warn("event_hook_invalid", slog.String("on", hook.On), slog.String("error", err.Error()))
+			}
+			continue
+		}
+		if !hook.Matches(from, to, raw.Group) {
+			continue
+		}
+		command, err := hook.render(quoted)
+		if err != nil {
+			eventHookLog.Warn("event_hook_render_failed", slog.String("on", hook.On), slog.String("error", err.Error()))
+			continue
+		}
+		select {
+		case eventHookSlots <- struct{}{}:
+		default:
+			eventHookLog.Warn("event_hook_dropped",
+				slog.String("on", hook.On), slog.String("session", raw.ID),
+				slog.Int("in_flight", maxConcurrentEventHooks))
+			continue
+		}
+		started++
+		go func(on, command string) {
+			defer func() { <-eventHookSlots }()
+			ctx, cancel := context.WithTimeout(context.Background(), settings.Timeout())
+			defer cancel()
+			if err := runEventHookCommand(ctx, command, dir, env); err != nil {
+				eventHookLog.Warn("event_hook_failed",
+					slog.String("on", on), slog.String("session", raw.ID), slog.String("error", err.Error()))
+				return
+			}
+			eventHookLog.Debug("event_hook_ran", slog.String("on", on), slog.String("session", raw.ID))
+		}(hook.On, command)
+	}
+	return started
+}
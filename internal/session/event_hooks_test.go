@@ -0,0 +1,129 @@
+package session
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEventHookMatches(t *testing.T) {
+	cases := []struct {
+		hook     EventHook
+		from, to string
+		group    string
+		want     bool
+	}{
+		{EventHook{On: "running->waiting"}, "running", "waiting", "", true},
+		{EventHook{On: "running → waiting"}, "running", "waiting", "", true},
+		{EventHook{On: "running->waiting"}, "idle", "waiting", "", false},
+		{EventHook{On: "waiting"}, "idle", "waiting", "", true},
+		{EventHook{On: "*->error"}, "running", "error", "", true},
+		{EventHook{On: "running->*"}, "running", "idle", "", true},
+		{EventHook{On: "waiting", Group: "work"}, "running", "waiting", "work/api", true},
+		{EventHook{On: "waiting", Group: "work"}, "running", "waiting", "workshop", false},
+		{EventHook{On: "running->paused"}, "running", "waiting", "", false},
+	}
+	for _, tc := range cases {
+		if got := tc.hook.Matches(tc.from, tc.to, tc.group); got != tc.want {
+			t.Errorf("%+v.Matches(%s, %s, %q) = %v, want %v", tc.hook, tc.from, tc.to, tc.group, got, tc.want)
+		}
+	}
+}
+
+func TestEventHookValidate(t *testing.T) {
+	for _, on := range []string{"running->waiting", "crashed", "running->exited"} {
+		if err := (EventHook{On: on, Command: "echo {{.Title}}"}).Validate(); err != nil {
+			t.Errorf("valid hook %q rejected: %v", on, err)
+		}
+	}
+	for _, h := range []EventHook{
+		{On: "running->paused", Command: "true"},
+		{On: "waiting", Command: ""},
+		{On: "waiting", Command: "echo {{.Titel}}"},
+		{On: "waiting", Command: "echo {{.Title"},
+	} {
+		if err := h.Validate(); err == nil {
+			t.Errorf("invalid hook %+v accepted", h)
+		}
+	}
+}
+
+func TestRunEventHooks_QuotesTemplateValues(t *testing.T) {
+	channelsTestEnv(t)
+	configPath, err := GetUserConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(configPath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	config := `
+[[events.hook]]
+on = "running->waiting"
+command = "notify {{.Title}} {{.PrevStatus}} {{.Status}}"
+
+[[events.hook]]
+on = "*->error"
+command = "page {{.ID}}"
+`
+	if err := os.WriteFile(configPath, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	ClearUserConfigCache()
+
+	var mu sync.Mutex
+	var commands []string
+	var envs [][]string
+	done := make(chan struct{}, 4)
+	orig := runEventHookCommand
+	runEventHookCommand = func(_ context.Context, command, _ string, env []string) error {
+		mu.Lock()
+		commands = append(commands, command)
+		envs = append(envs, env)
+		mu.Unlock()
+		done <- struct{}{}
+		return nil
+	}
+	t.Cleanup(func() { runEventHookCommand = orig })
+
+	inst := &Instance{ID: "abc", Title: "it's $(rm -rf ~)", ProjectPath: t.TempDir()}
+	if n := RunEventHooks(inst, "default", "running", "waiting"); n != 1 {
+		t.Fatalf("started %d hooks, want 1", n)
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("hook did not run")
+	}
+	if n := RunEventHooks(inst, "default", "waiting", "idle"); n != 0 {
+		t.Errorf("non-matching transition started %d hooks", n)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := `notify 'it'\''s $(rm -rf ~)' 'running' 'waiting'`
+	if commands[0] != want {
+		t.Errorf("command = %s, want %s", commands[0], want)
+	}
+	if !strings.Contains(strings.Join(envs[0], "\n"), "AGENTDECK_EVENT_TITLE=it's $(rm -rf ~)") {
+		t.Errorf("raw title missing from env: %v", envs[0])
+	}
+}
+
+func TestRunEventHookCommand_Executes(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out")
+	cmd := `printf '%s' "$AGENTDECK_EVENT_STATUS" > ` + shellQuote(out)
+	if err := runEventHookCommand(context.Background(), cmd, "", []string{"AGENTDECK_EVENT_STATUS=waiting"}); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(out); string(data) != "waiting" {
+		t.Errorf("hook wrote %q", data)
+	}
+	if err := runEventHookCommand(context.Background(), "echo boom >&2; exit 3", "", nil); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("failing hook error = %v, want stderr in it", err)
+	}
+}
@@ -0,0 +1,180 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/safeio"
+)
+
+// Where an ExitRecord's code came from.
+const (
+	// ExitSourcePaneDead: tmux reported #{pane_dead_status} for a dead pane
+	// kept by remain-on-exit (sandbox sessions).
+	ExitSourcePaneDead = "pane_dead"
+	// ExitSourceShellReturn: the exit-to-shell wrapper recorded the agent's
+	// exit code before handing the pane back to an interactive shell.
+	ExitSourceShellReturn = "shell_return"
+)
+
+// ExitRecord is the agent process's recorded exit for the current spawn.
+//
+// Without it a session whose agent ended is indistinguishable from one that
+// is idle at a prompt (exit-to-shell) or from a generic error (dead pane).
+// The record is a one-line sidecar, "<code>[ <source>]", so the exit-to-shell
+// wrapper can write it from plain sh with printf; a bare code means
+// ExitSourceShellReturn. Every spawn clears it.
+type ExitRecord struct {
+	Code   int
+	Source string
+	At     time.Time
+}
+
+// Status classifies the exit: a zero code is a clean finish, anything else
+// (including 128+N for a fatal signal) a crash.
+func (r *ExitRecord) Status() Status {
+	if r.Code == 0 {
+		return StatusExited
+	}
+	return StatusCrashed
+}
+
+// Describe renders the exit code for humans, naming the signal when the shell
+// encoded one as 128+N.
+func (r *ExitRecord) Describe() string {
+	if r.Code > 128 && r.Code <= 128+64 {
+		return fmt.Sprintf("exit %d (signal %d)", r.Code, r.Code-128)
+	}
+	return fmt.Sprintf("exit %d", r.Code)
+}
+
+// exitStatusDir returns <data>/runtime/exit-status, falling back to a temp
+// path when the data dir cannot be resolved (mirrors spawnFailureDir).
+func exitStatusDir() string {
+	path, err := runtimeDataPath("exit-status")
+	if err != nil {
+		return tempAgentDeckPath("runtime", "exit-status")
+	}
+	return path
+}
+
+// exitStatusPath returns the sidecar path for one instance.
+func exitStatusPath(instanceID string) string {
+	return filepath.Join(exitStatusDir(), instanceID)
+}
+
+// writeExitRecord persists rec for an instance. Best-effort like the
+// spawn-failure sidecar: callers ignore the error.
+func writeExitRecord(instanceID string, rec ExitRecord) error {
+	path := exitStatusPath(instanceID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create exit-status dir: %w", err)
+	}
+	line := strconv.Itoa(rec.Code)
+	if rec.Source != "" && rec.Source != ExitSourceShellReturn {
+		line += " " + rec.Source
+	}
+	return safeio.SafeOverwrite(path, []byte(line+"\n"), safeio.Options{Perm: 0o644, SkipBackup: true})
+}
+
+// readExitRecord loads the sidecar for an instance, or nil when there is none
+// or it cannot be parsed (e.g. caught mid-write).
+func readExitRecord(instanceID string) *ExitRecord {
+	path := exitStatusPath(instanceID)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return parseExitRecord(string(data), fileModTime(path))
+}
+
+// parseExitRecord parses the "<code>[ <source>]" sidecar line.
+func parseExitRecord(data string, at time.Time) *ExitRecord {
+	fields := strings.Fields(data)
+	if len(fields) == 0 {
+		return nil
+	}
+	code, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return nil
+	}
+	rec := &ExitRecord{Code: code, Source: ExitSourceShellReturn, At: at}
+	if len(fields) > 1 {
+		rec.Source = fields[1]
+	}
+	return rec
+}
+
+func fileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// prepareExitRecord removes any record left by a previous spawn and makes
+// sure the directory exists, so the exit-to-shell wrapper's redirect can
+// create the file. Called at the top of every start, restart and stop.
+func prepareExitRecord(instanceID string) {
+	path := exitStatusPath(instanceID)
+	_ = safeio.SafeRemove(path, safeio.RemoveOptions{})
+	_ = os.MkdirAll(filepath.Dir(path), 0755)
+}
+
+// ExitRecord returns the agent's recorded exit for the current spawn, or nil
+// when the agent has not exited (or its exit could not be captured).
+// Exported for CLI surfaces (`session show`, `list --json`).
+func (i *Instance) ExitRecord() *ExitRecord {
+	return readExitRecord(i.ID)
+}
+
+// exitCodeRecorder returns the shell fragment the exit-to-shell wrapper runs
+// between the agent and the fallback shell to record the agent's exit code.
+// Empty for SSH sessions (the path would name the remote host's disk) and for
+// a fish launch shell, which rejects $? at parse time.
+func (i *Instance) exitCodeRecorder() string {
+	if i.SSHHost != "" {
+		return ""
+	}
+	if i.launchShellEnabled() && filepath.Base(os.Getenv("SHELL")) == "fish" {
+		return ""
+	}
+	return `; printf '%s\n' "$?" > ` + shellQuote(exitStatusPath(i.ID))
+}
+
+// detectAgentExit reports StatusExited/StatusCrashed when the agent process of
+// a live tmux session has ended. Two shapes qualify: a dead primary pane
+// (remain-on-exit), whose status tmux keeps and we persist on first sight,
+// and an exit-to-shell pane back at a bare shell with a recorded code. A code
+// recorded while the pane runs something else again (the user relaunched the
+// agent by hand) does not count. Called with i.mu held.
+func (i *Instance) detectAgentExit() (Status, bool) {
+	if i.tmuxSession.IsPaneDead() {
+		rec := readExitRecord(i.ID)
+		if rec == nil {
+			code, ok := i.tmuxSession.PaneDeadStatus()
+			if !ok {
+				return "", false
+			}
+			rec = &ExitRecord{Code: code, Source: ExitSourcePaneDead, At: time.Now()}
+			_ = writeExitRecord(i.ID, *rec)
+		}
+		return rec.Status(), true
+	}
+	if !isBuiltinAgentTool(i.Tool) {
+		return "", false
+	}
+	rec := readExitRecord(i.ID)
+	if rec == nil || rec.Source != ExitSourceShellReturn {
+		return "", false
+	}
+	if !isShellBinary(i.tmuxSession.PaneCurrentCommand()) {
+		return "", false
+	}
+	return rec.Status(), true
+}
@@ -0,0 +1,116 @@
+package session
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseExitRecord(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	tests := []struct {
+		in         string
+		wantNil    bool
+		wantCode   int
+		wantSource string
+	}{
+		{"0\n", false, 0, ExitSourceShellReturn},
+		{"137 pane_dead\n", false, 137, ExitSourcePaneDead},
+		{"", true, 0, ""},
+		{"oops", true, 0, ""},
+	}
+	for _, tc := range tests {
+		rec := parseExitRecord(tc.in, at)
+		if tc.wantNil {
+			if rec != nil {
+				t.Errorf("parseExitRecord(%q) = %+v, want nil", tc.in, rec)
+			}
+			continue
+		}
+		if rec == nil || rec.Code != tc.wantCode || rec.Source != tc.wantSource || !rec.At.Equal(at) {
+			t.Errorf("parseExitRecord(%q) = %+v, want code %d source %q", tc.in, rec, tc.wantCode, tc.wantSource)
+		}
+	}
+}
+
+func TestExitRecord_StatusAndDescribe(t *testing.T) {
+	clean := &ExitRecord{Code: 0}
+	if clean.Status() != StatusExited || clean.Describe() != "exit 0" {
+		t.Errorf("clean exit = (%q, %q)", clean.Status(), clean.Describe())
+	}
+	failed := &ExitRecord{Code: 1}
+	if failed.Status() != StatusCrashed || failed.Describe() != "exit 1" {
+		t.Errorf("failed exit = (%q, %q)", failed.Status(), failed.Describe())
+	}
+	killed := &ExitRecord{Code: 137}
+	if killed.Status() != StatusCrashed || killed.Describe() != "exit 137 (signal 9)" {
+		t.Errorf("killed exit = (%q, %q)", killed.Status(), killed.Describe())
+	}
+}
+
+func TestExitRecord_WriteReadRoundTrip(t *testing.T) {
+	exitToShellTestEnv(t)
+	inst := NewInstanceWithTool("exit-rt", t.TempDir(), "claude")
+
+	if inst.ExitRecord() != nil {
+		t.Fatal("fresh instance should have no exit record")
+	}
+	if err := writeExitRecord(inst.ID, ExitRecord{Code: 2, Source: ExitSourcePaneDead}); err != nil {
+		t.Fatalf("writeExitRecord: %v", err)
+	}
+	rec := inst.ExitRecord()
+	if rec == nil || rec.Code != 2 || rec.Source != ExitSourcePaneDead {
+		t.Fatalf("ExitRecord() = %+v, want code 2 from pane_dead", rec)
+	}
+	prepareExitRecord(inst.ID)
+	if inst.ExitRecord() != nil {
+		t.Fatal("prepareExitRecord must clear the previous spawn's record")
+	}
+}
+
+// The exit-to-shell wrap records the agent's code between the agent and the
+// fallback shell, and the suffix the fast-death watcher keys off stays last.
+func TestExitToShell_RecordsExitCodeBeforeShell(t *testing.T) {
+	exitToShellTestEnv(t)
+	inst := NewInstanceWithTool("exit-wrap", t.TempDir(), "claude")
+	inst.ExitToShell = boolPtr(true)
+
+	wrapped := inst.wrapExitToShell("claude")
+	if !strings.HasSuffix(wrapped, exitToShellTail) {
+		t.Fatalf("wrapped command must still end with %q, got %s", exitToShellTail, wrapped)
+	}
+	if !strings.Contains(wrapped, inst.exitCodeRecorder()) || inst.exitCodeRecorder() == "" {
+		t.Fatalf("wrapped command must contain the exit-code recorder, got %s", wrapped)
+	}
+
+	inst.SSHHost = "box"
+	if got := inst.exitCodeRecorder(); got != "" {
+		t.Errorf("SSH sessions must not record a local exit code, got %q", got)
+	}
+}
+
+// Run the recorder through a real sh to pin the quoting: the agent's code
+// lands in the sidecar and reads back as a crash.
+func TestExitCodeRecorder_WritesAgentExitCode(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not on PATH")
+	}
+	exitToShellTestEnv(t)
+	inst := NewInstanceWithTool("exit-sh", t.TempDir(), "claude")
+	prepareExitRecord(inst.ID)
+
+	script := "sh -c 'exit 3'" + inst.exitCodeRecorder()
+	if out, err := exec.Command("sh", "-c", script).CombinedOutput(); err != nil {
+		t.Fatalf("run recorder: %v\n%s", err, out)
+	}
+	rec := inst.ExitRecord()
+	if rec == nil {
+		data, _ := os.ReadFile(exitStatusPath(inst.ID))
+		t.Fatalf("no exit record written (file: %q)", data)
+	}
+	if rec.Code != 3 || rec.Source != ExitSourceShellReturn || rec.Status() != StatusCrashed {
+		t.Fatalf("ExitRecord() = %+v, want code 3 from shell_return (crashed)", rec)
+	}
+}
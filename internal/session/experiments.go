@@ -0,0 +1,121 @@
+package session
+
+import (
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/experiments"
+)
+
+// ForGroup returns the settings for 'try' sessions in group, with the
+// [experiments.groups.<group>] overrides applied. An empty group means
+// the configured default group.
+func (e ExperimentsSettings) ForGroup(group string) ExperimentsSettings {
+	if group == "" {
+		group = e.Group
+	}
+	if group == "" {
+		group = "experiments"
+	}
+	out := e
+	out.Group = group
+
+	override, ok := e.Groups[group]
+	if !ok {
+		return out
+	}
+	if override.Directory != "" {
+		out.Directory = ExpandPath(override.Directory)
+		// A group directory takes the group off the global template.
+		out.Template = ""
+	}
+	if override.Template != "" {
+		out.Template = ExpandPath(override.Template)
+	}
+	if override.DatePrefix != nil {
+		out.DatePrefix = override.DatePrefix
+	}
+	if override.DefaultTool != "" {
+		out.DefaultTool = override.DefaultTool
+	}
+	return out
+}
+
+// Root returns the directory experiment folders are created under: the
+// static part of Template, or Directory.
+func (e ExperimentsSettings) Root() string {
+	if e.Template != "" {
+		if tpl, err := experiments.ParseTemplate(e.Template); err == nil {
+			return tpl.Root()
+		}
+	}
+	return e.Directory
+}
+
+// ListExperiments lists the experiment folders these settings lay out.
+func (e ExperimentsSettings) ListExperiments() ([]experiments.Experiment, error) {
+	if e.Template != "" {
+		tpl, err := experiments.ParseTemplate(e.Template)
+		if err != nil {
+			return nil, err
+		}
+		return tpl.List()
+	}
+	return experiments.ListExperiments(e.Directory)
+}
+
+// FindOrCreateExperiment finds the experiment query names or creates its
+// folder. Returns (experiment, created, error)
+func (e ExperimentsSettings) FindOrCreateExperiment(query string) (*experiments.Experiment, bool, error) {
+	if e.Template != "" {
+		tpl, err := experiments.ParseTemplate(e.Template)
+		if err != nil {
+			return nil, false, err
+		}
+		return experiments.FindOrCreateTemplate(tpl, query)
+	}
+	return experiments.FindOrCreate(e.Directory, query, e.GetDatePrefix())
+}
+
+// cleanupEmptyExperiments deletes experiment folders that are still empty
+// [experiments] cleanup_empty_days after their last change, for the default
+// layout and every per-group one.
+func cleanupEmptyExperiments() int {
+	settings := GetExperimentsSettings()
+	if settings.CleanupEmptyDays <= 0 {
+		return 0
+	}
+	maxAge := time.Duration(settings.CleanupEmptyDays) * 24 * time.Hour
+
+	layouts := []ExperimentsSettings{settings.ForGroup("")}
+	groups := make([]string, 0, len(settings.Groups))
+	for group := range settings.Groups {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	for _, group := range groups {
+		layouts = append(layouts, settings.ForGroup(group))
+	}
+
+	removed := 0
+	seen := make(map[string]bool)
+	for _, layout := range layouts {
+		key := layout.Template
+		if key == "" {
+			key = layout.Directory
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		exps, err := layout.ListExperiments()
+		if err != nil {
+			maintLog.Warn("cleanup_experiments_list_failed", slog.String("path", key), slog.String("error", err.Error()))
+			continue
+		}
+		removed += experiments.RemoveEmpty(exps, layout.Root(), maxAge)
+	}
+	return removed
+}
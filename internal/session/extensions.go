@@ -0,0 +1,221 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ExtensionPrefix is the executable name prefix that makes a program on PATH
+// an agent-deck extension: agent-deck-<name> runs as `agent-deck <name>`,
+// like git and kubectl plugins.
+const ExtensionPrefix = "agent-deck-"
+
+// Where an Extension was found.
+const (
+	ExtensionSourcePath   = "path"
+	ExtensionSourceConfig = "config"
+)
+
+// ExtensionDef declares an extension in [extensions.<name>] in config.toml,
+// for programs that aren't named agent-deck-<name> or aren't on PATH.
+type ExtensionDef struct {
+	// Command is the executable: a path (~ and $VARS expanded) or a name
+	// looked up on PATH. It is run directly, not through a shell.
+	Command string `toml:"command"`
+
+	// Args are passed before the arguments given on the command line.
+	Args []string `toml:"args,omitempty"`
+
+	// Description is shown by `extension list` and in the TUI picker.
+	Description string `toml:"description,omitempty"`
+}
+
+// Extension is a resolved extension command.
+type Extension struct {
+	Name        string
+	Path        string
+	Args        []string
+	Description string
+	Source      string
+}
+
+// ValidateExtensionName rejects names that can't be typed as a subcommand.
+// Same rule as prompt names.
+func ValidateExtensionName(name string) error {
+	if !promptNameRe.MatchString(name) {
+		return fmt.Errorf("invalid extension name %q: use letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// GetExtensions returns the extensions on $PATH and in config.toml.
+func GetExtensions() []Extension {
+	var defs map[string]ExtensionDef
+	if config, err := LoadUserConfig(); err == nil && config != nil {
+		defs = config.Extensions
+	}
+	return DiscoverExtensions(os.Getenv("PATH"), defs)
+}
+
+// FindExtension returns the named extension.
+func FindExtension(name string) (Extension, bool) {
+	for _, ext := range GetExtensions() {
+		if ext.Name == name {
+			return ext, true
+		}
+	}
+	return Extension{}, false
+}
+
+// DiscoverExtensions scans pathList for agent-deck-<name> executables (the
+// first directory wins, as with command lookup) and adds defs, which take
+// precedence over a PATH executable of the same name. Sorted by name.
+func DiscoverExtensions(pathList string, defs map[string]ExtensionDef) []Extension {
+	found := make(map[string]Extension)
+	for _, dir := range filepath.SplitList(pathList) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name, ok := strings.CutPrefix(e.Name(), ExtensionPrefix)
+			if !ok || ValidateExtensionName(name) != nil {
+				continue
+			}
+			if _, seen := found[name]; seen {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			info, err := os.Stat(path)
+			if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0o111 == 0 {
+				continue
+			}
+			found[name] = Extension{Name: name, Path: path, Source: ExtensionSourcePath}
+		}
+	}
+	for name, def := range defs {
+		if ValidateExtensionName(name) != nil || strings.TrimSpace(def.Command) == "" {
+			continue
+		}
+		found[name] = Extension{
+			Name:        name,
+			Path:        resolveExtensionCommand(def.Command),
+			Args:        def.Args,
+			Description: def.Description,
+			Source:      ExtensionSourceConfig,
+		}
+	}
+
+	exts := make([]Extension, 0, len(found))
+	for _, ext := range found {
+		exts = append(exts, ext)
+	}
+	sort.Slice(exts, func(a, b int) bool { return exts[a].Name < exts[b].Name })
+	return exts
+}
+
+// resolveExtensionCommand expands a path, or looks a bare name up on PATH.
+// An unresolvable name is returned as is so running it reports the error.
+func resolveExtensionCommand(command string) string {
+	command = strings.TrimSpace(command)
+	if strings.ContainsRune(command, '/') || strings.HasPrefix(command, "~") {
+		return ExpandPath(command)
+	}
+	if path, err := exec.LookPath(command); err == nil {
+		return path
+	}
+	return command
+}
+
+// ExtensionSession describes the session an extension was run for.
+type ExtensionSession struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Path        string `json:"path"`
+	Group       string `json:"group"`
+	Tool        string `json:"tool"`
+	Status      string `json:"status"`
+	TmuxSession string `json:"tmux_session,omitempty"`
+}
+
+// ExtensionContext is what an extension is told about where it was run from:
+// always as AGENTDECK_* environment variables, and as JSON on stdin when run
+// from the TUI.
+type ExtensionContext struct {
+	Profile string            `json:"profile"`
+	Session *ExtensionSession `json:"session,omitempty"`
+}
+
+// NewExtensionContext builds the context for profile and, when non-nil, inst.
+func NewExtensionContext(profile string, inst *Instance) ExtensionContext {
+	ctx := ExtensionContext{Profile: profile}
+	if inst == nil {
+		return ctx
+	}
+	ctx.Session = &ExtensionSession{
+		ID:     inst.ID,
+		Title:  inst.Title,
+		Path:   inst.ProjectPath,
+		Group:  inst.GroupPath,
+		Tool:   inst.Tool,
+		Status: string(inst.GetStatusThreadSafe()),
+	}
+	if ts := inst.GetTmuxSession(); ts != nil {
+		ctx.Session.TmuxSession = ts.Name
+	}
+	return ctx
+}
+
+// Env returns the context as environment variables. AGENTDECK_BIN lets the
+// extension call back into this agent-deck binary.
+func (c ExtensionContext) Env() []string {
+	env := []string{"AGENTDECK_PROFILE=" + c.Profile}
+	if bin, err := os.Executable(); err == nil {
+		env = append(env, "AGENTDECK_BIN="+bin)
+	}
+	if s := c.Session; s != nil {
+		env = append(env,
+			"AGENTDECK_SESSION_ID="+s.ID,
+			"AGENTDECK_SESSION_TITLE="+s.Title,
+			"AGENTDECK_SESSION_PATH="+s.Path,
+			"AGENTDECK_SESSION_GROUP="+s.Group,
+			"AGENTDECK_SESSION_TOOL="+s.Tool,
+			"AGENTDECK_SESSION_STATUS="+s.Status,
+			"AGENTDECK_TMUX_SESSION="+s.TmuxSession,
+		)
+	}
+	return env
+}
+
+// JSON returns the context as the one-line JSON document written to an
+// extension's stdin.
+func (c ExtensionContext) JSON() []byte {
+	data, _ := json.Marshal(c)
+	return append(data, '\n')
+}
+
+// Command returns the exec.Cmd that runs ext with args and ectx in its
+// environment, in the session's project directory when there is one. Stdio is
+// left to the caller; ctx bounds the run.
+func (ext Extension) Command(ctx context.Context, args []string, ectx ExtensionContext) *exec.Cmd {
+	argv := append(append([]string{}, ext.Args...), args...)
+	// #nosec G204 -- the extension is the user's own executable from PATH or
+	// config.toml, run on their request.
+	cmd := exec.CommandContext(ctx, ext.Path, argv...)
+	cmd.Env = append(os.Environ(), ectx.Env()...)
+	if ectx.Session != nil && ectx.Session.Path != "" {
+		if info, err := os.Stat(ectx.Session.Path); err == nil && info.IsDir() {
+			cmd.Dir = ectx.Session.Path
+		}
+	}
+	return cmd
+}
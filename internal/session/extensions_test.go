@@ -0,0 +1,75 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writeExecutable(t *testing.T, path string, mode os.FileMode) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiscoverExtensions(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	writeExecutable(t, filepath.Join(first, "agent-deck-review"), 0o755)
+	writeExecutable(t, filepath.Join(second, "agent-deck-review"), 0o755) // shadowed
+	writeExecutable(t, filepath.Join(second, "agent-deck-notes"), 0o644)  // not executable
+	writeExecutable(t, filepath.Join(second, "agent-deck-bad.name"), 0o755)
+	writeExecutable(t, filepath.Join(second, "agent-deck-deploy"), 0o755)
+	writeExecutable(t, filepath.Join(second, "other-tool"), 0o755)
+
+	defs := map[string]ExtensionDef{
+		"deploy": {Command: "/opt/bin/deploy.sh", Args: []string{"--dry-run"}, Description: "Deploy"},
+		"empty":  {},
+	}
+	exts := DiscoverExtensions(first+string(os.PathListSeparator)+second, defs)
+
+	var names []string
+	for _, ext := range exts {
+		names = append(names, ext.Name)
+	}
+	if want := []string{"deploy", "review"}; !slices.Equal(names, want) {
+		t.Fatalf("names = %v, want %v", names, want)
+	}
+	if exts[0].Source != ExtensionSourceConfig || exts[0].Path != "/opt/bin/deploy.sh" || !slices.Equal(exts[0].Args, []string{"--dry-run"}) {
+		t.Errorf("config entry should win over PATH: %+v", exts[0])
+	}
+	if exts[1].Source != ExtensionSourcePath || exts[1].Path != filepath.Join(first, "agent-deck-review") {
+		t.Errorf("first PATH directory should win: %+v", exts[1])
+	}
+}
+
+func TestExtensionCommand_PassesContext(t *testing.T) {
+	dir := t.TempDir()
+	inst := &Instance{ID: "abc123", Title: "api", ProjectPath: dir, GroupPath: "work", Tool: "claude", Status: StatusIdle}
+	ctx := NewExtensionContext("dev", inst)
+
+	ext := Extension{Name: "echo", Path: "/bin/sh", Args: []string{"-c", `printf '%s|%s|%s|' "$AGENTDECK_PROFILE" "$AGENTDECK_SESSION_ID" "$1"; pwd; cat`, "sh"}}
+	cmd := ext.Command(context.Background(), []string{"arg"}, ctx)
+	cmd.Stdin = strings.NewReader(string(ctx.JSON()))
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	lines := strings.SplitN(string(out), "\n", 2)
+	realDir, _ := filepath.EvalSymlinks(dir)
+	if want := "dev|abc123|arg|" + realDir; lines[0] != want {
+		t.Errorf("env/args/dir = %q, want %q", lines[0], want)
+	}
+
+	var got ExtensionContext
+	if err := json.Unmarshal([]byte(lines[1]), &got); err != nil {
+		t.Fatalf("stdin JSON: %v (%q)", err, lines[1])
+	}
+	if got.Profile != "dev" || got.Session == nil || got.Session.Title != "api" || got.Session.Status != "idle" {
+		t.Errorf("stdin context = %+v", got)
+	}
+}
@@ -0,0 +1,153 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FanoutBranchPlaceholder is replaced by the attempt number (1..N) in a
+// fan-out branch template.
+const FanoutBranchPlaceholder = "{n}"
+
+// FanoutAttempt is one of the worktree sessions a fan-out created.
+type FanoutAttempt struct {
+	N         int    `json:"n"`
+	Branch    string `json:"branch"`
+	SessionID string `json:"session_id,omitempty"`
+	Title     string `json:"title,omitempty"`
+	Error     string `json:"error,omitempty"` // why the attempt failed to launch
+}
+
+// FanoutRecord is one `agent-deck fanout` run: the same prompt sent to N
+// worktree sessions of one repository, kept so their attempts can be
+// compared afterwards.
+type FanoutRecord struct {
+	ID        string          `json:"id"`
+	Prompt    string          `json:"prompt"`
+	Tool      string          `json:"tool,omitempty"`
+	Path      string          `json:"path"`
+	CreatedAt time.Time       `json:"created_at"`
+	Attempts  []FanoutAttempt `json:"attempts"`
+}
+
+// fanoutsMu serializes read-modify-write cycles on the fan-out file.
+var fanoutsMu sync.Mutex
+
+// FanoutsPath returns the file holding fan-out records.
+func FanoutsPath() (string, error) {
+	return dataPath("fanouts.json", "fanouts.json")
+}
+
+// ReadFanouts returns every recorded fan-out, oldest first. A missing file
+// yields an empty slice and no error.
+func ReadFanouts() ([]FanoutRecord, error) {
+	path, err := FanoutsPath()
+	if err != nil {
+		return nil, err
+	}
+	fanoutsMu.Lock()
+	defer fanoutsMu.Unlock()
+	return readFanoutsFile(path)
+}
+
+func readFanoutsFile(path string) ([]FanoutRecord, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var records []FanoutRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return records, nil
+}
+
+// RecordFanout stores rec, replacing an earlier record with the same ID.
+func RecordFanout(rec FanoutRecord) error {
+	if rec.ID == "" {
+		return fmt.Errorf("fan-out record has no id")
+	}
+	if rec.CreatedAt.IsZero() {
+		rec.CreatedAt = time.Now()
+	}
+	path, err := FanoutsPath()
+	if err != nil {
+		return err
+	}
+	fanoutsMu.Lock()
+	defer fanoutsMu.Unlock()
+	records, err := readFanoutsFile(path)
+	if err != nil {
+		return err
+	}
+	kept := records[:0]
+	for _, r := range records {
+		if r.ID != rec.ID {
+			kept = append(kept, r)
+		}
+	}
+	kept = append(kept, rec)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(kept, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeJSONFileAtomic(path, data, 0o600)
+}
+
+// FindFanout returns the fan-out whose ID starts with ref, or the newest one
+// when ref is empty.
+func FindFanout(ref string) (*FanoutRecord, error) {
+	records, err := ReadFanouts()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no fan-outs recorded")
+	}
+	if ref == "" {
+		return &records[len(records)-1], nil
+	}
+	var match *FanoutRecord
+	for i := range records {
+		if strings.HasPrefix(records[i].ID, ref) {
+			if match != nil {
+				return nil, fmt.Errorf("fan-out id '%s' is ambiguous", ref)
+			}
+			match = &records[i]
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("fan-out '%s' not found", ref)
+	}
+	return match, nil
+}
+
+// FanoutBranches expands a branch template into n branch names, replacing
+// {n} with 1..n. With more than one attempt the template must contain {n},
+// or every attempt would get the same branch.
+func FanoutBranches(template string, n int) ([]string, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("need at least one attempt, got %d", n)
+	}
+	if n > 1 && !strings.Contains(template, FanoutBranchPlaceholder) {
+		return nil, fmt.Errorf("branch template %q must contain %s to give each attempt its own branch", template, FanoutBranchPlaceholder)
+	}
+	branches := make([]string, n)
+	for i := range branches {
+		branches[i] = strings.ReplaceAll(template, FanoutBranchPlaceholder, strconv.Itoa(i+1))
+	}
+	return branches, nil
+}
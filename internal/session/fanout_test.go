@@ -0,0 +1,66 @@
+package session
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestFanoutBranches(t *testing.T) {
+	got, err := FanoutBranches("feature/try-{n}", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"feature/try-1", "feature/try-2", "feature/try-3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FanoutBranches = %v, want %v", got, want)
+	}
+	if got, err := FanoutBranches("feature/solo", 1); err != nil || len(got) != 1 || got[0] != "feature/solo" {
+		t.Errorf("FanoutBranches(single) = %v, %v", got, err)
+	}
+	if _, err := FanoutBranches("feature/same", 2); err == nil {
+		t.Error("template without {n} for 2 attempts: want error")
+	}
+	if _, err := FanoutBranches("feature/try-{n}", 0); err == nil {
+		t.Error("0 attempts: want error")
+	}
+}
+
+func TestRecordFanout_FindByPrefixAndNewest(t *testing.T) {
+	path, err := FanoutsPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = os.Remove(path)
+	t.Cleanup(func() { _ = os.Remove(path) })
+
+	if _, err := FindFanout(""); err == nil {
+		t.Error("FindFanout with no records: want error")
+	}
+	for _, rec := range []FanoutRecord{
+		{ID: "ab12cd34", Prompt: "implement X", Attempts: []FanoutAttempt{{N: 1, Branch: "try-1"}}},
+		{ID: "ab99ef00", Prompt: "implement Y"},
+		{ID: "ab12cd34", Prompt: "implement X", Attempts: []FanoutAttempt{{N: 1, Branch: "try-1", SessionID: "s1"}}},
+	} {
+		if err := RecordFanout(rec); err != nil {
+			t.Fatalf("RecordFanout(%+v): %v", rec, err)
+		}
+	}
+
+	records, err := ReadFanouts()
+	if err != nil || len(records) != 2 {
+		t.Fatalf("ReadFanouts = %+v, %v; want 2 records", records, err)
+	}
+	got, err := FindFanout("ab12")
+	if err != nil || got.Attempts[0].SessionID != "s1" || got.CreatedAt.IsZero() {
+		t.Fatalf("FindFanout(ab12) = %+v, %v", got, err)
+	}
+	if got, err := FindFanout(""); err != nil || got.ID != "ab12cd34" {
+		t.Errorf("FindFanout(newest) = %+v, %v", got, err)
+	}
+	if _, err := FindFanout("ab"); err == nil {
+		t.Error("ambiguous prefix: want error")
+	}
+	if _, err := FindFanout("zz"); err == nil {
+		t.Error("unknown id: want error")
+	}
+}
@@ -0,0 +1,95 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// DefaultForkContextLines is how many lines of the parent's terminal a
+// context fork carries over when no count is given.
+const DefaultForkContextLines = 200
+
+// SupportsContextFork reports whether a tool can be forked by seeding a fresh
+// session with the parent's recent output. Codex and OpenCode qualify: their
+// native fork needs a flushed, recently detected session id, which a context
+// fork does without.
+func SupportsContextFork(tool string) bool {
+	return tool == "opencode" || IsCodexCompatible(tool)
+}
+
+// ForkContext returns up to n of the parent's most recent terminal lines,
+// ANSI-stripped, with trailing blank lines dropped. A running session is read
+// from its pane scrollback; otherwise the recorded transcript is used.
+func (i *Instance) ForkContext(n int) ([]string, error) {
+	if n <= 0 {
+		n = DefaultForkContextLines
+	}
+	var lines []string
+	if i.tmuxSession != nil && i.tmuxSession.Exists() {
+		raw, err := i.tmuxSession.CaptureHistoryLines(n)
+		if err != nil {
+			return nil, err
+		}
+		lines = strings.Split(tmux.StripANSI(raw), "\n")
+	} else if path, err := TranscriptPath(i.ID); err == nil {
+		// Read the whole tail window: trailing blank lines are dropped
+		// below, and must not eat into the n kept.
+		lines = readTranscriptTail(path, math.MaxInt)
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	if len(lines) == 0 {
+		return nil, errors.New("no terminal output to carry over (session not running and no recorded transcript)")
+	}
+	return lines, nil
+}
+
+// BuildForkContextPrompt is the first prompt of a context fork: the parent's
+// recent terminal lines plus an instruction to take them in before acting.
+func BuildForkContextPrompt(parent *Instance, lines []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "This session is a fork of the %s session %q in %s. ", parent.Tool, parent.Title, parent.ProjectPath)
+	fmt.Fprintf(&b, "Below are the last %d lines of its terminal, for context only.\n\n", len(lines))
+	b.WriteString("```\n")
+	b.WriteString(strings.Join(lines, "\n"))
+	b.WriteString("\n```\n\n")
+	b.WriteString("Read them, summarize in a few lines where the work stands, then wait for my next instruction.")
+	return b.String()
+}
+
+// CreateContextForkedInstance creates a fresh session of the parent's tool
+// and command, in the parent's project (or opts' worktree) and group. Unlike
+// CreateForkedInstanceForTool it does not resume the parent's conversation;
+// the caller seeds it with BuildForkContextPrompt via StartWithMessage.
+func (i *Instance) CreateContextForkedInstance(newTitle, newGroupPath string, opts *ClaudeOptions) *Instance {
+	projectPath := i.ProjectPath
+	if opts != nil && opts.WorkDir != "" {
+		projectPath = opts.WorkDir
+	}
+	forked := NewInstance(newTitle, projectPath)
+	if newGroupPath != "" {
+		forked.GroupPath = newGroupPath
+	} else {
+		forked.GroupPath = i.GroupPath
+	}
+	forked.Tool = i.Tool
+	forked.Command = i.Command
+	forked.Wrapper = i.Wrapper
+	if len(i.ExtraArgs) > 0 {
+		forked.ExtraArgs = append([]string(nil), i.ExtraArgs...)
+	}
+	if opts != nil && opts.WorktreePath != "" {
+		forked.WorktreePath = opts.WorktreePath
+		forked.WorktreeRepoRoot = opts.WorktreeRepoRoot
+		forked.WorktreeBranch = opts.WorktreeBranch
+	}
+	return forked
+}
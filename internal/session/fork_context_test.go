@@ -0,0 +1,76 @@
+package session
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestForkContext_FallsBackToTranscript(t *testing.T) {
+	parent := &Instance{ID: "fork-context-test", Title: "worker", Tool: "codex", ProjectPath: "/src/app"}
+	path, err := TranscriptPath(parent.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Remove(path) })
+
+	e := TranscriptEntry{Timestamp: time.Now(), Lines: []string{"one", "two", "three", ""}}
+	if err := appendTranscriptEntry(path, e, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	lines, err := parent.ForkContext(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(lines, "|") != "two|three" {
+		t.Fatalf("ForkContext(2) = %q, want the last two non-blank lines", lines)
+	}
+
+	prompt := BuildForkContextPrompt(parent, lines)
+	for _, want := range []string{`codex session "worker"`, "/src/app", "last 2 lines", "two\nthree\n"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+}
+
+func TestForkContext_NothingRecorded(t *testing.T) {
+	parent := &Instance{ID: "fork-context-empty", Tool: "opencode"}
+	if _, err := parent.ForkContext(0); err == nil {
+		t.Fatal("ForkContext with no pane and no transcript must fail")
+	}
+}
+
+func TestCreateContextForkedInstance(t *testing.T) {
+	parent := &Instance{
+		ID: "p", Title: "worker", Tool: "opencode", Command: "opencode --model x",
+		ProjectPath: "/src/app", GroupPath: "team", ExtraArgs: []string{"--verbose"},
+		OpenCodeSessionID: "ses_parent",
+	}
+	forked := parent.CreateContextForkedInstance("worker-fork", "", &ClaudeOptions{
+		WorkDir: "/wt/fork", WorktreePath: "/wt/fork", WorktreeRepoRoot: "/src/app", WorktreeBranch: "fork",
+	})
+	if forked.Tool != "opencode" || forked.Command != parent.Command || forked.GroupPath != "team" {
+		t.Errorf("fork = tool %q command %q group %q", forked.Tool, forked.Command, forked.GroupPath)
+	}
+	if forked.ProjectPath != "/wt/fork" || forked.WorktreeBranch != "fork" {
+		t.Errorf("fork path %q branch %q, want the worktree", forked.ProjectPath, forked.WorktreeBranch)
+	}
+	if forked.OpenCodeSessionID != "" || forked.IsForkAwaitingStart {
+		t.Error("context fork must start a fresh conversation, not resume the parent")
+	}
+	parent.ExtraArgs[0] = "changed"
+	if forked.ExtraArgs[0] != "--verbose" {
+		t.Error("ExtraArgs must be copied, not aliased")
+	}
+}
+
+func TestSupportsContextFork(t *testing.T) {
+	for tool, want := range map[string]bool{"codex": true, "opencode": true, "claude": false, "pi": false} {
+		if got := SupportsContextFork(tool); got != want {
+			t.Errorf("SupportsContextFork(%q) = %v, want %v", tool, got, want)
+		}
+	}
+}
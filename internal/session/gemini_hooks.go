@@ -36,10 +36,13 @@ var geminiHookEventConfigs = []struct {
 }{
 	// SessionStart/SessionEnd bracket lifecycle.
 	// BeforeAgent/AfterAgent provide stable running/waiting transitions.
+	// Notification fires when a tool call needs the user's confirmation
+	// (notification_type "ToolPermission"), the mid-turn wait AfterAgent misses.
 	// We intentionally keep this set narrow to avoid mapping noisy/auxiliary events.
 	{Event: "SessionStart"},
 	{Event: "BeforeAgent"},
 	{Event: "AfterAgent"},
+	{Event: "Notification"},
 	{Event: "SessionEnd"},
 }
 
@@ -171,30 +174,59 @@ func RemoveGeminiHooks(configDir string) (bool, error) {
 	return true, nil
 }
 
+// GeminiHookEvents returns the Gemini CLI hook events agent-deck installs.
+func GeminiHookEvents() []string {
+	events := make([]string, len(geminiHookEventConfigs))
+	for i, cfg := range geminiHookEventConfigs {
+		events[i] = cfg.Event
+	}
+	return events
+}
+
 // CheckGeminiHooksInstalled checks whether required agent-deck Gemini hooks are installed.
 func CheckGeminiHooksInstalled(configDir string) bool {
-	settingsPath := filepath.Join(configDir, "settings.json")
-	data, err := os.ReadFile(settingsPath)
+	existingHooks := readGeminiHooks(configDir)
+	return existingHooks != nil && geminiHooksAlreadyInstalled(existingHooks)
+}
+
+// MissingGeminiHookEvents returns the hook events that lack the agent-deck
+// hook in configDir's settings.json. All of them are missing when none is
+// installed; a partial list means an install from an older agent-deck that
+// `gemini-hooks install` upgrades.
+func MissingGeminiHookEvents(configDir string) []string {
+	existingHooks := readGeminiHooks(configDir)
+	var missing []string
+	for _, cfg := range geminiHookEventConfigs {
+		if raw, ok := existingHooks[cfg.Event]; !ok || !geminiEventHasAgentDeckHook(raw) {
+			missing = append(missing, cfg.Event)
+		}
+	}
+	return missing
+}
+
+// readGeminiHooks returns the "hooks" object of configDir's settings.json,
+// or nil when the file, or its hooks, is missing or unreadable.
+func readGeminiHooks(configDir string) map[string]json.RawMessage {
+	data, err := os.ReadFile(filepath.Join(configDir, "settings.json"))
 	if err != nil {
-		return false
+		return nil
 	}
 
 	var rawSettings map[string]json.RawMessage
 	if err := json.Unmarshal(data, &rawSettings); err != nil {
-		return false
+		return nil
 	}
 
 	hooksRaw, ok := rawSettings["hooks"]
 	if !ok {
-		return false
+		return nil
 	}
 
 	var existingHooks map[string]json.RawMessage
 	if err := json.Unmarshal(hooksRaw, &existingHooks); err != nil {
-		return false
+		return nil
 	}
-
-	return geminiHooksAlreadyInstalled(existingHooks)
+	return existingHooks
 }
 
 func geminiHooksAlreadyInstalled(hooks map[string]json.RawMessage) bool {
@@ -174,3 +174,40 @@ func TestCheckGeminiHooksInstalled(t *testing.T) {
 		t.Fatal("expected installed after inject")
 	}
 }
+
+// An install from before the Notification hook is reported as missing just
+// that event, and re-running install adds it without duplicating the rest.
+func TestMissingGeminiHookEvents_UpgradeAddsNotification(t *testing.T) {
+	tmpDir := t.TempDir()
+	if got := MissingGeminiHookEvents(tmpDir); len(got) != len(geminiHookEventConfigs) {
+		t.Fatalf("missing before install = %v, want all events", got)
+	}
+
+	old := `{"hooks": {
+  "SessionStart": [{"hooks": [{"type": "command", "command": "agent-deck hook-handler"}]}],
+  "BeforeAgent": [{"hooks": [{"type": "command", "command": "agent-deck hook-handler"}]}],
+  "AfterAgent": [{"hooks": [{"type": "command", "command": "agent-deck hook-handler"}]}],
+  "SessionEnd": [{"hooks": [{"type": "command", "command": "agent-deck hook-handler"}]}]
+}}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "settings.json"), []byte(old), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := MissingGeminiHookEvents(tmpDir); len(got) != 1 || got[0] != "Notification" {
+		t.Fatalf("missing = %v, want [Notification]", got)
+	}
+	if CheckGeminiHooksInstalled(tmpDir) {
+		t.Fatal("a partial install must not report installed")
+	}
+
+	installed, err := InjectGeminiHooks(tmpDir)
+	if err != nil || !installed {
+		t.Fatalf("upgrade install = %v, %v", installed, err)
+	}
+	if got := MissingGeminiHookEvents(tmpDir); len(got) != 0 {
+		t.Fatalf("missing after upgrade = %v", got)
+	}
+	data, _ := os.ReadFile(filepath.Join(tmpDir, "settings.json"))
+	if n := strings.Count(string(data), "agent-deck hook-handler"); n != len(geminiHookEventConfigs) {
+		t.Errorf("agent-deck hook entries = %d, want %d", n, len(geminiHookEventConfigs))
+	}
+}
@@ -0,0 +1,41 @@
+package session
+
+import (
+	"strings"
+)
+
+// Goose adapter.
+//
+// Block's goose CLI (github.com/block/goose) runs its interactive chat as
+// `goose session`; `goose session --resume` reopens the most recent session
+// for the working directory. A bare `goose` command is launched as
+// `goose session`. Status comes from the pane patterns in internal/tmux (the
+// "( O)>" prompt and the thinking spinner).
+
+// buildGooseCommand builds the launch command for goose. continuePrev adds
+// --resume to a `goose session` command that has none, so restart picks the
+// conversation back up.
+func (i *Instance) buildGooseCommand(baseCommand string, continuePrev bool) string {
+	if i.Tool != "goose" {
+		return baseCommand
+	}
+
+	cmd := strings.TrimSpace(baseCommand)
+	if cmd == "" || cmd == "goose" {
+		cmd = "goose session"
+	}
+	if continuePrev && strings.Contains(cmd, " session") && !hasGooseResumeFlag(cmd) {
+		cmd += " --resume"
+	}
+	return i.buildEnvSourceCommand() + cmd
+}
+
+// hasGooseResumeFlag reports whether cmd already asks goose to resume.
+func hasGooseResumeFlag(cmd string) bool {
+	for _, f := range strings.Fields(cmd) {
+		if f == "--resume" || f == "-r" {
+			return true
+		}
+	}
+	return false
+}
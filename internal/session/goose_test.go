@@ -0,0 +1,42 @@
+package session
+
+import "testing"
+
+func TestBuildGooseCommand(t *testing.T) {
+	oldCache := userConfigCache
+	defer func() { userConfigCache = oldCache }()
+	userConfigCache = &UserConfig{}
+
+	inst := &Instance{Tool: "goose"}
+	tests := []struct {
+		name         string
+		base         string
+		continuePrev bool
+		want         string
+	}{
+		{"bare name starts a session", "goose", false, "goose session"},
+		{"empty command", "", false, "goose session"},
+		{"restart resumes", "goose", true, "goose session --resume"},
+		{"restart keeps explicit resume", "goose session -r --name work", true, "goose session -r --name work"},
+		{"restart of a non-session command", "goose run -t hello", true, "goose run -t hello"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inst.buildGooseCommand(tt.base, tt.continuePrev); !endsWith(got, tt.want) {
+				t.Errorf("buildGooseCommand(%q, %v) = %q, want suffix %q", tt.base, tt.continuePrev, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoose_IsBuiltin(t *testing.T) {
+	if got := GetToolIcon("goose"); got != "🪿" {
+		t.Errorf("GetToolIcon(goose) = %q, want 🪿", got)
+	}
+	if !isBuiltinToolName("goose") {
+		t.Error("goose should be a built-in tool name")
+	}
+	if !NewInstanceWithTool("g", "/tmp", "goose").CanRestart() {
+		t.Error("goose sessions should be restartable")
+	}
+}
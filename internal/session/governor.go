@@ -0,0 +1,255 @@
+package session
+
+// Send governor ([governor]).
+//
+// Many Claude sessions on one account share its rate limits: fanning the
+// same task out to ten sessions burns the quota in minutes and then every
+// session stalls on the usage-limit notice. The governor caps how many
+// Claude sessions may be working at once. A `session send` to an idle
+// Claude session over the cap, or while any Claude pane shows the usage
+// limit, is queued in statedb's send_queue instead of typed in. The TUI
+// status loop (or the notify daemon when no TUI runs) releases queued
+// messages oldest first as sessions go idle.
+//
+// Example:
+//
+//	[governor]
+//	max_active = 3
+//	queue_on_usage_limit = true
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// sendQueueCheckInterval rate-limits release passes per profile.
+const sendQueueCheckInterval = 5 * time.Second
+
+// sendQueueMaxAttempts is how often a released send may fail before it is
+// dropped from the queue.
+const sendQueueMaxAttempts = 3
+
+// GovernorSettings is the [governor] section of config.toml. Zero values
+// disable it, so an absent section keeps sends immediate.
+type GovernorSettings struct {
+	// MaxActive caps Claude sessions working (running or starting) at
+	// once. 0 means no cap.
+	MaxActive int `toml:"max_active,omitzero"`
+
+	// QueueOnUsageLimit holds sends to Claude sessions while any Claude
+	// pane shows the account's usage-limit notice.
+	QueueOnUsageLimit bool `toml:"queue_on_usage_limit,omitempty"`
+}
+
+// Enabled reports whether the governor may hold a send.
+func (g GovernorSettings) Enabled() bool {
+	return g.MaxActive > 0 || g.QueueOnUsageLimit
+}
+
+// GetGovernorSettings returns the [governor] settings from config.toml.
+func GetGovernorSettings() GovernorSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return GovernorSettings{}
+	}
+	return config.Governor
+}
+
+// GovernorDecision records whether a send may go out now, and why not. It
+// is returned by CheckGovernor and surfaced by `session send` and
+// `agent-deck governor`.
+type GovernorDecision struct {
+	Allowed      bool     `json:"allowed"`
+	Reason       string   `json:"reason,omitempty"`
+	Active       int      `json:"active"`
+	MaxActive    int      `json:"max_active,omitempty"`
+	UsageLimited []string `json:"usage_limited,omitempty"` // titles of sessions showing the notice
+}
+
+// governorIsActive reports whether a Claude session takes a slot. Like the
+// dispatch limits, starting counts so a burst cannot slip past the cap.
+func governorIsActive(inst *Instance) bool {
+	status := inst.GetStatusThreadSafe()
+	return status == StatusRunning || status == StatusStarting
+}
+
+// CheckGovernor decides whether a send to target may go out now. Sends to
+// non-Claude sessions are never held, and a session that is already
+// working does not take another slot.
+func CheckGovernor(settings GovernorSettings, instances []*Instance, target *Instance) GovernorDecision {
+	return checkGovernor(settings, instances, target, nil)
+}
+
+// checkGovernor is CheckGovernor with extra sessions (by ID) counted as
+// active: the ones a release pass has just sent to.
+func checkGovernor(settings GovernorSettings, instances []*Instance, target *Instance, extra map[string]bool) GovernorDecision {
+	d := GovernorDecision{Allowed: true, MaxActive: settings.MaxActive}
+	if target == nil || !settings.Enabled() || !IsClaudeCompatible(target.Tool) {
+		return d
+	}
+	targetActive := extra[target.ID]
+	for _, inst := range instances {
+		if inst == nil || !IsClaudeCompatible(inst.Tool) {
+			continue
+		}
+		if settings.QueueOnUsageLimit && inst.CachedSubstate() == SubstateUsageLimit {
+			d.UsageLimited = append(d.UsageLimited, inst.Title)
+		}
+		if governorIsActive(inst) || extra[inst.ID] {
+			d.Active++
+			if inst.ID == target.ID {
+				targetActive = true
+			}
+		}
+	}
+	switch {
+	case len(d.UsageLimited) > 0:
+		d.Allowed = false
+		d.Reason = "usage limit reached (" + strings.Join(d.UsageLimited, ", ") + ")"
+	case !targetActive && IsAtCap(d.Active, settings.MaxActive):
+		d.Allowed = false
+		d.Reason = fmt.Sprintf("claude at capacity (%d/%d active)", d.Active, settings.MaxActive)
+	}
+	return d
+}
+
+// QueueSend stores message for inst in the send queue, held for reason.
+func QueueSend(db *statedb.StateDB, inst *Instance, message, reason string, now time.Time) (*statedb.QueuedSend, error) {
+	if strings.TrimSpace(message) == "" {
+		return nil, fmt.Errorf("message is required")
+	}
+	q := &statedb.QueuedSend{
+		ID:         "send-" + randomString(8),
+		InstanceID: inst.ID,
+		Message:    message,
+		Reason:     reason,
+		QueuedAt:   now,
+	}
+	if err := db.SaveQueuedSend(q); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// takeReleasableSends picks the queued sends that may go out now, oldest
+// first, and takes them off the queue. A send waits while its session is
+// working (it is released when the session goes idle) or while the
+// governor holds it; the held ones get their reason refreshed. Sends to
+// sessions that no longer exist are dropped.
+func takeReleasableSends(db *statedb.StateDB, settings GovernorSettings, instances []*Instance) []*statedb.QueuedSend {
+	queued, err := db.LoadQueuedSends("")
+	if err != nil || len(queued) == 0 {
+		return nil
+	}
+	byID := make(map[string]*Instance, len(instances))
+	for _, inst := range instances {
+		if inst != nil {
+			byID[inst.ID] = inst
+		}
+	}
+	released := map[string]bool{}
+	var out []*statedb.QueuedSend
+	for _, q := range queued {
+		inst := byID[q.InstanceID]
+		if inst == nil {
+			_, _ = db.TakeQueuedSend(q.ID)
+			maintLog.Warn("send_queue_dropped", slog.String("id", q.ID), slog.String("instance", q.InstanceID),
+				slog.String("reason", "session removed"))
+			continue
+		}
+		if released[inst.ID] || governorIsActive(inst) {
+			continue // one message per turn: wait for the session to go idle
+		}
+		d := checkGovernor(settings, instances, inst, released)
+		if !d.Allowed {
+			if d.Reason != q.Reason {
+				_ = db.UpdateQueuedSendReason(q.ID, d.Reason)
+			}
+			continue
+		}
+		if ok, err := db.TakeQueuedSend(q.ID); err != nil || !ok {
+			continue // another drainer got it
+		}
+		released[inst.ID] = true
+		out = append(out, q)
+	}
+	return out
+}
+
+// deliverQueuedSends sends each taken message. A failed send goes back on
+// the queue until it has failed sendQueueMaxAttempts times.
+func deliverQueuedSends(db *statedb.StateDB, sends []*statedb.QueuedSend, send func(instanceID, message string) error) {
+	for _, q := range sends {
+		err := send(q.InstanceID, q.Message)
+		if err == nil {
+			continue
+		}
+		q.Attempts++
+		q.LastError = err.Error()
+		if q.Attempts >= sendQueueMaxAttempts {
+			maintLog.Warn("send_queue_dropped", slog.String("id", q.ID), slog.String("instance", q.InstanceID),
+				slog.String("reason", "delivery failed"), slog.String("error", q.LastError))
+			continue
+		}
+		maintLog.Warn("send_queue_retry", slog.String("id", q.ID), slog.String("instance", q.InstanceID),
+			slog.Int("attempts", q.Attempts), slog.String("error", q.LastError))
+		if err := db.SaveQueuedSend(q); err != nil {
+			maintLog.Warn("send_queue_requeue_failed", slog.String("id", q.ID), slog.String("error", err.Error()))
+		}
+	}
+}
+
+var (
+	sendQueueMu        sync.Mutex
+	sendQueueLastCheck = map[string]time.Time{}
+	sendQueueBusy      = map[string]bool{}
+)
+
+// ReleaseQueuedSends releases the profile's queued sends that may go out
+// now, given instances with fresh statuses. Call it once per status sweep;
+// passes are rate-limited to sendQueueCheckInterval and delivery runs in
+// the background. It returns how many sends were released.
+func ReleaseQueuedSends(db *statedb.StateDB, profile string, instances []*Instance, now time.Time) int {
+	if db == nil {
+		return 0
+	}
+	key := profileOrDefault(profile)
+	sendQueueMu.Lock()
+	if sendQueueBusy[key] || now.Sub(sendQueueLastCheck[key]) < sendQueueCheckInterval {
+		sendQueueMu.Unlock()
+		return 0
+	}
+	sendQueueLastCheck[key] = now
+	sendQueueBusy[key] = true
+	sendQueueMu.Unlock()
+
+	sends := takeReleasableSends(db, GetGovernorSettings(), instances)
+	if len(sends) == 0 {
+		sendQueueMu.Lock()
+		delete(sendQueueBusy, key)
+		sendQueueMu.Unlock()
+		return 0
+	}
+	go func() {
+		defer func() {
+			sendQueueMu.Lock()
+			delete(sendQueueBusy, key)
+			sendQueueMu.Unlock()
+		}()
+		deliverQueuedSends(db, sends, queuedSender(profile))
+	}()
+	return len(sends)
+}
+
+// queuedSender delivers a released message the way `session send` does,
+// with --no-queue so the governor does not hold it a second time.
+func queuedSender(profile string) func(instanceID, message string) error {
+	return func(instanceID, message string) error {
+		return sendSessionMessage(profile, instanceID, message, "--no-queue")
+	}
+}
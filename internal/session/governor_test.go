@@ -0,0 +1,111 @@
+package session
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+func governorInstance(id string, status Status) *Instance {
+	return &Instance{ID: id, Title: id, Tool: "claude", Status: status}
+}
+
+func TestCheckGovernor(t *testing.T) {
+	settings := GovernorSettings{MaxActive: 2, QueueOnUsageLimit: true}
+	busy1 := governorInstance("busy1", StatusRunning)
+	busy2 := governorInstance("busy2", StatusStarting)
+	idle := governorInstance("idle", StatusWaiting)
+	shell := &Instance{ID: "sh", Title: "sh", Tool: "shell", Status: StatusIdle}
+	instances := []*Instance{busy1, busy2, idle, shell}
+
+	if d := CheckGovernor(settings, instances, idle); d.Allowed || d.Active != 2 || !strings.Contains(d.Reason, "2/2 active") {
+		t.Fatalf("idle claude over the cap = %+v, want held", d)
+	}
+	if d := CheckGovernor(settings, instances, busy1); !d.Allowed {
+		t.Fatalf("a session already working takes no new slot: %+v", d)
+	}
+	if d := CheckGovernor(settings, instances, shell); !d.Allowed {
+		t.Fatalf("non-claude sends are never held: %+v", d)
+	}
+	if d := CheckGovernor(GovernorSettings{}, instances, idle); !d.Allowed {
+		t.Fatalf("a disabled governor holds nothing: %+v", d)
+	}
+
+	// The usage limit is account-wide: it holds even sends under the cap.
+	limited := governorInstance("limited", StatusWaiting)
+	limited.tmuxSession = tmux.NewSession("limited", t.TempDir())
+	tmux.SeedSubstateForTest(t, limited.tmuxSession, tmux.SubstateUsageLimit)
+	d := CheckGovernor(GovernorSettings{MaxActive: 5, QueueOnUsageLimit: true}, []*Instance{idle, limited}, idle)
+	if d.Allowed || !strings.Contains(d.Reason, "usage limit reached (limited)") {
+		t.Fatalf("usage-limited account = %+v, want held", d)
+	}
+	if d := CheckGovernor(GovernorSettings{MaxActive: 5}, []*Instance{idle, limited}, idle); !d.Allowed {
+		t.Fatalf("queue_on_usage_limit off must ignore the notice: %+v", d)
+	}
+}
+
+func TestReleaseQueuedSends_OldestFirstWithinCap(t *testing.T) {
+	db, err := statedb.Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+	settings := GovernorSettings{MaxActive: 2}
+	busy := governorInstance("busy", StatusRunning)
+	a := governorInstance("a", StatusWaiting)
+	b := governorInstance("b", StatusIdle)
+	instances := []*Instance{busy, a, b}
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.Local)
+	queue := func(inst *Instance, msg string, at time.Time) {
+		t.Helper()
+		if _, err := QueueSend(db, inst, msg, "claude at capacity (2/2 active)", at); err != nil {
+			t.Fatal(err)
+		}
+	}
+	queue(a, "a1", now)
+	queue(b, "b1", now.Add(time.Second))
+	queue(a, "a2", now.Add(2*time.Second))
+	queue(&Instance{ID: "gone"}, "lost", now)
+
+	// One slot free: only the oldest send goes, one message per session.
+	got := takeReleasableSends(db, settings, instances)
+	if len(got) != 1 || got[0].Message != "a1" {
+		t.Fatalf("released %+v, want only a1", got)
+	}
+	left, _ := db.LoadQueuedSends("")
+	if len(left) != 2 || left[0].Message != "b1" || left[1].Message != "a2" {
+		t.Fatalf("queue = %+v, want b1 and a2 (the removed session's send dropped)", left)
+	}
+
+	// a is now working; busy went idle. b may go, a2 waits for a.
+	a.Status, busy.Status = StatusRunning, StatusWaiting
+	got = takeReleasableSends(db, settings, instances)
+	if len(got) != 1 || got[0].Message != "b1" {
+		t.Fatalf("second pass released %+v, want b1", got)
+	}
+
+	// A failed delivery is requeued until it runs out of attempts.
+	fail := func(string, string) error { return errors.New("timeout waiting for agent") }
+	for n := 1; n <= sendQueueMaxAttempts; n++ {
+		deliverQueuedSends(db, got, fail)
+		left, _ = db.LoadQueuedSends("b")
+		if n < sendQueueMaxAttempts && (len(left) != 1 || left[0].Attempts != n) {
+			t.Fatalf("after %d failures queue(b) = %+v", n, left)
+		}
+		if n == sendQueueMaxAttempts && len(left) != 0 {
+			t.Fatalf("send still queued after %d failures", n)
+		}
+		for _, q := range left {
+			_, _ = db.TakeQueuedSend(q.ID) // as the next release pass would
+		}
+		got = left
+	}
+}
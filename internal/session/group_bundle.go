@@ -0,0 +1,318 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Group bundles share a deck between machines: `group export` writes a
+// group's sessions and subgroups as JSON, `group import` recreates them.
+// Only definitions travel — titles, tools, worktree branches, MCP and skill
+// loadout — never conversations or runtime state.
+//
+// Paths are machine-specific, so a bundle stores them as templates under a
+// single {root}: the deepest directory holding every exported path. The
+// bundle carries the exporter's root (with $HOME as ~) as a suggestion; the
+// importer may point {root} anywhere with `--root`. Import goes through the
+// project-manifest machinery, so it is idempotent and never escapes {root}.
+
+// GroupBundleVersion is the bundle format version written by NewGroupBundle.
+const GroupBundleVersion = 1
+
+// BundleRootVar is the placeholder every bundle path starts with.
+const BundleRootVar = "{root}"
+
+// GroupBundle is the JSON schema of a group bundle.
+type GroupBundle struct {
+	Version    int       `json:"version"`
+	Name       string    `json:"name"`  // the exported group's name, the default import group
+	Group      string    `json:"group"` // the exported group's path, for reference
+	ExportedAt time.Time `json:"exported_at"`
+	// Root is the exporter's {root}, with their home directory as "~".
+	Root string `json:"root"`
+	// Groups holds settings of the exported group ("") and its subgroups,
+	// keyed by path relative to it.
+	Groups   map[string]BundleGroup `json:"groups,omitempty"`
+	Sessions []BundleSession        `json:"sessions"`
+	// Skipped lists sessions that could not be exported, with why.
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// BundleGroup is one group's shareable settings.
+type BundleGroup struct {
+	DefaultPath   string   `json:"default_path,omitempty"` // template
+	DefaultMCPs   []string `json:"default_mcps,omitempty"`
+	MaxConcurrent int      `json:"max_concurrent,omitempty"`
+}
+
+// BundleSession is one session definition.
+type BundleSession struct {
+	Title    string   `json:"title"`
+	Group    string   `json:"group,omitempty"` // relative to the exported group
+	Path     string   `json:"path"`            // template; the repository for worktree sessions
+	Cmd      string   `json:"cmd,omitempty"`
+	Worktree string   `json:"worktree,omitempty"` // branch
+	MCPs     []string `json:"mcps,omitempty"`
+	Skills   []string `json:"skills,omitempty"`
+}
+
+// NewGroupBundle captures groupPath and its subgroups from tree. Remote
+// (SSH) sessions are skipped: their paths live on another machine.
+func NewGroupBundle(tree *GroupTree, groupPath string) (*GroupBundle, error) {
+	root, ok := tree.Groups[groupPath]
+	if !ok {
+		return nil, fmt.Errorf("group '%s' not found", groupPath)
+	}
+	b := &GroupBundle{
+		Version:    GroupBundleVersion,
+		Name:       root.Name,
+		Group:      groupPath,
+		ExportedAt: time.Now().UTC(),
+		Groups:     map[string]BundleGroup{},
+	}
+
+	var groups []*Group
+	for path, g := range tree.Groups {
+		if path == groupPath || strings.HasPrefix(path, groupPath+"/") {
+			groups = append(groups, g)
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Path < groups[j].Path })
+
+	// Collect the sessions and every absolute path first: {root} depends
+	// on all of them.
+	type pending struct {
+		spec BundleSession
+		path string
+	}
+	var sessions []pending
+	var paths []string
+	for _, g := range groups {
+		for _, inst := range g.Sessions {
+			if inst.SSHHost != "" {
+				b.Skipped = append(b.Skipped, fmt.Sprintf("%s: remote session on %s", inst.Title, inst.SSHHost))
+				continue
+			}
+			spec, path := bundleSessionFor(inst)
+			spec.Group = strings.TrimPrefix(strings.TrimPrefix(g.Path, groupPath), "/")
+			sessions = append(sessions, pending{spec, path})
+			paths = append(paths, path)
+		}
+		if g.DefaultPath != "" {
+			paths = append(paths, g.DefaultPath)
+		}
+	}
+	rootDir := commonDir(paths)
+	b.Root = homeToTilde(rootDir)
+
+	for _, g := range groups {
+		bg := BundleGroup{DefaultMCPs: g.DefaultMCPs, MaxConcurrent: g.MaxConcurrent}
+		if g.DefaultPath != "" {
+			bg.DefaultPath = bundlePathTemplate(rootDir, g.DefaultPath)
+		}
+		b.Groups[strings.TrimPrefix(strings.TrimPrefix(g.Path, groupPath), "/")] = bg
+	}
+	for _, p := range sessions {
+		p.spec.Path = bundlePathTemplate(rootDir, p.path)
+		b.Sessions = append(b.Sessions, p.spec)
+	}
+	return b, nil
+}
+
+// bundleSessionFor describes inst; path is its project directory, or the
+// repository for a worktree session, which import recreates from the branch.
+func bundleSessionFor(inst *Instance) (BundleSession, string) {
+	spec := BundleSession{Title: inst.Title, Cmd: inst.Command}
+	if spec.Cmd == "" && inst.Tool != "" && inst.Tool != "shell" {
+		spec.Cmd = inst.Tool
+	}
+	path := inst.ProjectPath
+	if inst.IsWorktree() && inst.WorktreeRepoRoot != "" && inst.WorktreeBranch != "" {
+		path = inst.WorktreeRepoRoot
+		spec.Worktree = inst.WorktreeBranch
+	}
+	if info := inst.MCPInfoForLocalAttach(); info != nil {
+		spec.MCPs = info.Local()
+	}
+	if skills, err := GetAttachedProjectSkills(inst.ProjectPath); err == nil {
+		for _, s := range skills {
+			ref := s.Name
+			if s.Source != "" {
+				ref = s.Source + "/" + s.Name
+			}
+			spec.Skills = append(spec.Skills, ref)
+		}
+	}
+	return spec, filepath.Clean(path)
+}
+
+// commonDir returns the deepest directory containing every path.
+func commonDir(paths []string) string {
+	if len(paths) == 0 {
+		return string(filepath.Separator)
+	}
+	common := filepath.Clean(paths[0])
+	for _, p := range paths[1:] {
+		p = filepath.Clean(p)
+		for common != p && !strings.HasPrefix(p, strings.TrimSuffix(common, string(filepath.Separator))+string(filepath.Separator)) {
+			parent := filepath.Dir(common)
+			if parent == common {
+				break
+			}
+			common = parent
+		}
+	}
+	return common
+}
+
+// bundlePathTemplate rewrites path, which lies under root, as a template.
+func bundlePathTemplate(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return BundleRootVar
+	}
+	return BundleRootVar + "/" + filepath.ToSlash(rel)
+}
+
+// bundleTemplateRel returns a template's path relative to {root}.
+func bundleTemplateRel(tmpl string) (string, error) {
+	if tmpl == BundleRootVar {
+		return ".", nil
+	}
+	rel, ok := strings.CutPrefix(tmpl, BundleRootVar+"/")
+	if !ok {
+		return "", fmt.Errorf("path %q must start with %s", tmpl, BundleRootVar)
+	}
+	return filepath.FromSlash(rel), nil
+}
+
+func homeToTilde(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	if path == home {
+		return "~"
+	}
+	if rest, ok := strings.CutPrefix(path, home+string(filepath.Separator)); ok {
+		return "~/" + filepath.ToSlash(rest)
+	}
+	return path
+}
+
+// Write writes the bundle as indented JSON.
+func (b *GroupBundle) Write(w io.Writer) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// ReadGroupBundle decodes and checks a bundle.
+func ReadGroupBundle(r io.Reader) (*GroupBundle, error) {
+	var b GroupBundle
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&b); err != nil {
+		return nil, fmt.Errorf("not a group bundle: %w", err)
+	}
+	if b.Version == 0 || b.Version > GroupBundleVersion {
+		return nil, fmt.Errorf("unsupported group bundle version %d (this agent-deck reads up to %d)", b.Version, GroupBundleVersion)
+	}
+	if len(b.Sessions) == 0 && len(b.Groups) == 0 {
+		return nil, errors.New("group bundle is empty")
+	}
+	return &b, nil
+}
+
+// Manifest turns the bundle into a project manifest rooted at root (the
+// local {root}) that creates everything under group (default: the bundle's
+// name). Validation is the manifest's: paths must stay inside root.
+func (b *GroupBundle) Manifest(root, group string) (*ProjectManifest, error) {
+	root, err := filepath.Abs(ExpandPath(root))
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("root %s is not a directory (choose one with --root)", root)
+	}
+	if group == "" {
+		group = b.Name
+	}
+	m := &ProjectManifest{Group: group, Groups: map[string]ManifestGroup{}, Path: root, Dir: root}
+	for key, g := range b.Groups {
+		if key == "" {
+			// Manifest groups are subgroups; the root group's default path
+			// is applied by ApplyGroupSettings.
+			continue
+		}
+		mg := ManifestGroup{}
+		if g.DefaultPath != "" {
+			if mg.DefaultPath, err = bundleTemplateRel(g.DefaultPath); err != nil {
+				return nil, fmt.Errorf("groups.%q: %w", key, err)
+			}
+		}
+		m.Groups[key] = mg
+	}
+	for i, s := range b.Sessions {
+		rel, err := bundleTemplateRel(s.Path)
+		if err != nil {
+			return nil, fmt.Errorf("sessions[%d] (%s): %w", i, s.Title, err)
+		}
+		m.Sessions = append(m.Sessions, ManifestSession{
+			Title:    s.Title,
+			Group:    s.Group,
+			Path:     rel,
+			Cmd:      s.Cmd,
+			Worktree: s.Worktree,
+			MCPs:     s.MCPs,
+			Skills:   s.Skills,
+		})
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ApplyGroupSettings sets the root group's default path and every group's
+// default MCPs and concurrency cap in tree, once m.ApplyGroups created them.
+// Returns true if the tree was modified.
+func (b *GroupBundle) ApplyGroupSettings(tree *GroupTree, m *ProjectManifest) bool {
+	changed := false
+	for key, bg := range b.Groups {
+		path := m.GroupPath(key)
+		g, ok := tree.Groups[path]
+		if !ok {
+			if g = tree.CreateGroupPath(path); g == nil {
+				continue
+			}
+			changed = true
+		}
+		if key == "" && bg.DefaultPath != "" {
+			if rel, err := bundleTemplateRel(bg.DefaultPath); err == nil {
+				if dir, err := m.resolvePath(rel); err == nil && g.DefaultPath != dir {
+					tree.SetDefaultPathForGroup(path, dir)
+					changed = true
+				}
+			}
+		}
+		if len(bg.DefaultMCPs) > 0 && tree.SetDefaultMCPsForGroup(path, bg.DefaultMCPs) {
+			changed = true
+		}
+		if bg.MaxConcurrent != 0 && g.MaxConcurrent != bg.MaxConcurrent {
+			g.MaxConcurrent = bg.MaxConcurrent
+			changed = true
+		}
+	}
+	return changed
+}
@@ -0,0 +1,112 @@
+package session
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGroupBundle_ExportImportRoundTrip(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "payments")
+	for _, dir := range []string{"services/api", "web"} {
+		if err := os.MkdirAll(filepath.Join(src, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	api := NewInstanceWithGroup("api", filepath.Join(src, "services", "api"), "work/payments/backend")
+	api.Tool = "claude"
+	web := NewInstanceWithGroup("web", filepath.Join(src, "web"), "work/payments")
+	remote := NewInstanceWithGroup("prod", "/srv/app", "work/payments")
+	remote.SSHHost = "prod-box"
+	other := NewInstanceWithGroup("elsewhere", t.TempDir(), "personal")
+
+	tree := NewGroupTreeWithGroups([]*Instance{api, web, remote, other}, nil)
+	tree.SetDefaultPathForGroup("work/payments", src)
+	tree.SetDefaultMCPsForGroup("work/payments/backend", []string{"postgres"})
+	tree.Groups["work/payments"].MaxConcurrent = 2
+
+	b, err := NewGroupBundle(tree, "work/payments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Name != "payments" || len(b.Sessions) != 2 || len(b.Skipped) != 1 {
+		t.Fatalf("bundle = %+v", b)
+	}
+	var buf bytes.Buffer
+	if err := b.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	// The exporter's root is only a suggestion; no other path may leak.
+	if n := strings.Count(buf.String(), src); n != 1 {
+		t.Errorf("bundle mentions the exporter's root %d times, want once:\n%s", n, buf.String())
+	}
+	if !strings.Contains(buf.String(), `"path": "{root}/services/api"`) {
+		t.Errorf("bundle missing templated session path:\n%s", buf.String())
+	}
+
+	read, err := ReadGroupBundle(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst := t.TempDir()
+	for _, dir := range []string{"services/api", "web"} {
+		if err := os.MkdirAll(filepath.Join(dst, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m, err := read.Manifest(dst, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := m.Materialize(nil, nil)
+	if len(res.Created) != 2 || len(res.Failed) != 0 {
+		t.Fatalf("created=%d failed=%v", len(res.Created), res.Failed)
+	}
+	byTitle := map[string]*Instance{}
+	for _, inst := range res.Created {
+		byTitle[inst.Title] = inst
+	}
+	if got := byTitle["api"]; got == nil || got.GroupPath != "payments/backend" || got.Tool != "claude" ||
+		got.ProjectPath != filepath.Join(dst, "services", "api") {
+		t.Errorf("imported api = %+v", got)
+	}
+	if got := byTitle["web"]; got == nil || got.GroupPath != "payments" || got.ProjectPath != filepath.Join(dst, "web") {
+		t.Errorf("imported web = %+v", got)
+	}
+
+	imported := NewGroupTreeWithGroups(res.Created, nil)
+	m.ApplyGroups(imported)
+	read.ApplyGroupSettings(imported, m)
+	if g := imported.Groups["payments"]; g.DefaultPath != dst || g.MaxConcurrent != 2 {
+		t.Errorf("payments group = %+v", g)
+	}
+	if g := imported.Groups["payments/backend"]; len(g.DefaultMCPs) != 1 || g.DefaultMCPs[0] != "postgres" {
+		t.Errorf("payments/backend group = %+v", g)
+	}
+
+	if _, err := read.Manifest(filepath.Join(dst, "missing"), ""); err == nil || !strings.Contains(err.Error(), "--root") {
+		t.Errorf("missing root: err = %v", err)
+	}
+}
+
+func TestReadGroupBundle_Rejects(t *testing.T) {
+	for name, body := range map[string]string{
+		"future version": `{"version": 99, "sessions": [{"title": "x", "path": "{root}"}]}`,
+		"unknown field":  `{"version": 1, "sessions": [], "extra": true}`,
+		"empty":          `{"version": 1}`,
+	} {
+		if _, err := ReadGroupBundle(strings.NewReader(body)); err == nil {
+			t.Errorf("%s: want error", name)
+		}
+	}
+	b := &GroupBundle{Version: 1, Name: "x", Sessions: []BundleSession{{Title: "x", Path: "/etc"}}}
+	if _, err := b.Manifest(t.TempDir(), ""); err == nil {
+		t.Error("untemplated path: want error")
+	}
+	b.Sessions[0].Path = "{root}/../../etc"
+	if _, err := b.Manifest(t.TempDir(), ""); err == nil {
+		t.Error("path escaping root: want error")
+	}
+}
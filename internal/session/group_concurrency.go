@@ -48,13 +48,15 @@ func ShouldQueue(instances []*Instance, groupPath string, maxConcurrent int) boo
 
 // FindNextQueued returns the oldest queued instance in the given group, or
 // nil if none are queued. "Oldest" is by CreatedAt (FIFO drain order).
+// Deferred conductor dispatches are skipped: they carry a pending message
+// and drain through the dispatch queue (QueuedDispatches) instead.
 func FindNextQueued(instances []*Instance, groupPath string) *Instance {
 	var oldest *Instance
 	for _, inst := range instances {
 		if inst == nil {
 			continue
 		}
-		if inst.GroupPath != groupPath || inst.Status != StatusQueued {
+		if inst.GroupPath != groupPath || inst.Status != StatusQueued || inst.Dispatch != nil {
 			continue
 		}
 		if oldest == nil || inst.CreatedAt.Before(oldest.CreatedAt) {
@@ -0,0 +1,120 @@
+package session
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// GroupDefaultMCPs returns the MCPs attached to new sessions in groupPath:
+// the group's own DefaultMCPs, else those of its nearest ancestor that sets
+// any. Subgroups therefore inherit a parent's set until they define their own.
+func GroupDefaultMCPs(tree *GroupTree, groupPath string) []string {
+	if origin := GroupMCPOrigin(tree, groupPath); origin != "" {
+		return tree.Groups[origin].DefaultMCPs
+	}
+	return nil
+}
+
+// GroupMCPOrigin returns the path of the group GroupDefaultMCPs takes
+// groupPath's set from (groupPath itself or an ancestor), or "" when no
+// group in the chain sets one.
+func GroupMCPOrigin(tree *GroupTree, groupPath string) string {
+	if tree == nil {
+		return ""
+	}
+	for path := groupPath; path != ""; path = getParentPath(path) {
+		if g, ok := tree.Groups[path]; ok && len(g.DefaultMCPs) > 0 {
+			return path
+		}
+	}
+	return ""
+}
+
+// ValidateMCPNames returns an error naming the first MCP that is not defined
+// in config.toml.
+func ValidateMCPNames(names []string) error {
+	available := GetAvailableMCPs()
+	for _, name := range names {
+		if _, ok := available[name]; !ok {
+			return fmt.Errorf("MCP '%s' not found in config.toml", name)
+		}
+	}
+	return nil
+}
+
+// ApplyGroupDefaultMCPs attaches the default MCPs of inst's group to a newly
+// created session, on top of whatever its MCP store already holds (for
+// example from `add --mcp`). Like `mcp attach`, MCPs go to the project's
+// local config, or the global config for Codex. It returns the names it
+// added; MCPs since removed from config.toml are skipped, and tools without
+// MCP support are left alone.
+func ApplyGroupDefaultMCPs(inst *Instance, tree *GroupTree) ([]string, error) {
+	return AttachDefaultMCPs(inst, GroupDefaultMCPs(tree, inst.GroupPath))
+}
+
+// AttachDefaultMCPs is ApplyGroupDefaultMCPs for a set resolved up front,
+// for callers that create the session off the goroutine owning the tree.
+func AttachDefaultMCPs(inst *Instance, defaults []string) ([]string, error) {
+	if len(defaults) == 0 || !ToolSupportsMCPManager(inst.Tool) {
+		return nil, nil
+	}
+
+	info := inst.MCPInfoForLocalAttach()
+	if info == nil {
+		info = &MCPInfo{}
+	}
+	current := info.Local()
+	if IsCodexCompatible(inst.Tool) {
+		current = info.Global
+	}
+	have := make(map[string]bool, len(current))
+	for _, name := range current {
+		have[name] = true
+	}
+
+	available := GetAvailableMCPs()
+	names := append([]string(nil), current...)
+	var added []string
+	for _, name := range defaults {
+		if have[name] {
+			continue
+		}
+		if _, ok := available[name]; !ok {
+			sessionLog.Warn("group_default_mcp_missing",
+				slog.String("group", inst.GroupPath),
+				slog.String("mcp", name))
+			continue
+		}
+		have[name] = true
+		names = append(names, name)
+		added = append(added, name)
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+	if err := inst.WriteLocalMCPConfig(names); err != nil {
+		return nil, err
+	}
+	inst.InvalidateProjectMCPIntegrationsCache()
+	return added, nil
+}
+
+// SetDefaultMCPsForGroup sets (or, with no names, clears) a group's default
+// MCPs. Duplicates are dropped; order is kept.
+func (t *GroupTree) SetDefaultMCPsForGroup(groupPath string, names []string) bool {
+	group, exists := t.Groups[groupPath]
+	if !exists {
+		return false
+	}
+	seen := make(map[string]bool, len(names))
+	var mcps []string
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" && !seen[name] {
+			seen[name] = true
+			mcps = append(mcps, name)
+		}
+	}
+	group.DefaultMCPs = mcps
+	return true
+}
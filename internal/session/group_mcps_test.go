@@ -0,0 +1,52 @@
+package session
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGroupDefaultMCPs_Inheritance(t *testing.T) {
+	tree := NewGroupTree(nil)
+	tree.CreateGroupPath("work/api/v2")
+	tree.CreateGroup("other")
+
+	if !tree.SetDefaultMCPsForGroup("work", []string{"memory", " exa ", "memory", ""}) {
+		t.Fatal("SetDefaultMCPsForGroup(work) = false")
+	}
+	if got := strings.Join(tree.Groups["work"].DefaultMCPs, ","); got != "memory,exa" {
+		t.Fatalf("work defaults = %q, want deduped and trimmed", got)
+	}
+
+	// Subgroups inherit from the nearest ancestor that sets any.
+	if got := strings.Join(GroupDefaultMCPs(tree, "work/api/v2"), ","); got != "memory,exa" {
+		t.Errorf("work/api/v2 inherits %q", got)
+	}
+	if origin := GroupMCPOrigin(tree, "work/api/v2"); origin != "work" {
+		t.Errorf("origin = %q, want work", origin)
+	}
+	if got := GroupDefaultMCPs(tree, "other"); got != nil {
+		t.Errorf("unrelated group has defaults %v", got)
+	}
+
+	// A subgroup's own set overrides; clearing it falls back to the parent.
+	tree.SetDefaultMCPsForGroup("work/api", []string{"github"})
+	if got := strings.Join(GroupDefaultMCPs(tree, "work/api/v2"), ","); got != "github" {
+		t.Errorf("after override, work/api/v2 = %q", got)
+	}
+	tree.SetDefaultMCPsForGroup("work/api", nil)
+	if origin := GroupMCPOrigin(tree, "work/api"); origin != "work" {
+		t.Errorf("after clear, origin = %q, want work", origin)
+	}
+
+	if tree.SetDefaultMCPsForGroup("missing", []string{"memory"}) {
+		t.Error("SetDefaultMCPsForGroup on a missing group must return false")
+	}
+}
+
+func TestAttachDefaultMCPs_SkipsUnsupportedTools(t *testing.T) {
+	inst := &Instance{Tool: "shell", ProjectPath: t.TempDir()}
+	added, err := AttachDefaultMCPs(inst, []string{"memory"})
+	if err != nil || added != nil {
+		t.Fatalf("AttachDefaultMCPs(shell) = %v, %v; want nothing attached", added, err)
+	}
+}
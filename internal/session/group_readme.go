@@ -0,0 +1,113 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/atomicfile"
+)
+
+// Group READMEs are plain markdown files kept beside the profile's state, at
+// <profile-dir>/groups/<group-path>/README.md. They hold the group's goal so
+// every agent launched into the group can be handed the same objective, and
+// stay editable with any editor without touching state.db.
+
+// GroupReadmePath returns the README location for groupPath in profile.
+func GroupReadmePath(profile, groupPath string) (string, error) {
+	groupPath = strings.Trim(groupPath, "/")
+	if groupPath == "" {
+		return "", errors.New("group path is required")
+	}
+	for _, seg := range strings.Split(groupPath, "/") {
+		if seg == "" || seg == "." || seg == ".." {
+			return "", fmt.Errorf("invalid group path: %s", groupPath)
+		}
+	}
+	profileDir, err := GetProfileDir(profile)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(profileDir, "groups", filepath.FromSlash(groupPath), "README.md"), nil
+}
+
+// ReadGroupReadme returns the group's README, or "" when it has none.
+func ReadGroupReadme(profile, groupPath string) (string, error) {
+	path, err := GroupReadmePath(profile, groupPath)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// WriteGroupReadme stores content as the group's README. Blank content
+// removes it.
+func WriteGroupReadme(profile, groupPath, content string) error {
+	path, err := GroupReadmePath(profile, groupPath)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(content) == "" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return atomicfile.WriteFile(path, []byte(content), 0o600)
+}
+
+// WithGroupContext prepends the group README to an initial message so a new
+// session starts aligned with the group's goal. Returns message unchanged when
+// readme is blank.
+func WithGroupContext(groupPath, readme, message string) string {
+	readme = strings.TrimSpace(readme)
+	if readme == "" {
+		return message
+	}
+	ctx := fmt.Sprintf("Context for group %q (shared goal for every session in this group):\n\n%s", groupPath, readme)
+	if strings.TrimSpace(message) == "" {
+		return ctx
+	}
+	return ctx + "\n\n---\n\n" + message
+}
+
+// MoveGroupReadmes re-homes the README subtree of oldPath (the group and its
+// subgroups) to newPath. An empty newPath deletes the subtree. A group with no
+// README is a no-op.
+func MoveGroupReadmes(profile, oldPath, newPath string) error {
+	oldFile, err := GroupReadmePath(profile, oldPath)
+	if err != nil {
+		return err
+	}
+	oldDir := filepath.Dir(oldFile)
+	if _, err := os.Stat(oldDir); os.IsNotExist(err) {
+		return nil
+	}
+	if newPath == "" {
+		return os.RemoveAll(oldDir)
+	}
+	newFile, err := GroupReadmePath(profile, newPath)
+	if err != nil {
+		return err
+	}
+	newDir := filepath.Dir(newFile)
+	if err := os.MkdirAll(filepath.Dir(newDir), 0o700); err != nil {
+		return err
+	}
+	return os.Rename(oldDir, newDir)
+}
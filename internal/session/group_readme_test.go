@@ -0,0 +1,71 @@
+package session
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGroupReadme_WriteReadMoveDelete(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", "")
+
+	if got, err := ReadGroupReadme("", "work"); err != nil || got != "" {
+		t.Fatalf("missing README: got %q, %v", got, err)
+	}
+
+	if err := WriteGroupReadme("", "work/api", "Ship v2"); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if got, _ := ReadGroupReadme("", "work/api"); got != "Ship v2\n" {
+		t.Fatalf("read = %q", got)
+	}
+
+	// Reparenting work -> team/work carries subgroup READMEs along.
+	if err := MoveGroupReadmes("", "work", "team/work"); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if got, _ := ReadGroupReadme("", "team/work/api"); got != "Ship v2\n" {
+		t.Fatalf("after move: %q", got)
+	}
+	if got, _ := ReadGroupReadme("", "work/api"); got != "" {
+		t.Fatalf("old path still has README: %q", got)
+	}
+
+	// Blank content removes the README.
+	if err := WriteGroupReadme("", "team/work/api", "  "); err != nil {
+		t.Fatalf("clear: %v", err)
+	}
+	path, _ := GroupReadmePath("", "team/work/api")
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("README not removed: %v", err)
+	}
+
+	if err := MoveGroupReadmes("", "team", ""); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := MoveGroupReadmes("", "never-existed", ""); err != nil {
+		t.Fatalf("delete of missing group should be a no-op: %v", err)
+	}
+}
+
+func TestGroupReadmePath_RejectsTraversal(t *testing.T) {
+	for _, p := range []string{"", "..", "work/../../etc", "a//b"} {
+		if _, err := GroupReadmePath("", p); err == nil {
+			t.Errorf("GroupReadmePath(%q) should fail", p)
+		}
+	}
+}
+
+func TestWithGroupContext(t *testing.T) {
+	if got := WithGroupContext("work", "", "do it"); got != "do it" {
+		t.Errorf("blank README should leave message alone, got %q", got)
+	}
+	got := WithGroupContext("work", "Goal: ship\n", "do it")
+	if !strings.HasPrefix(got, `Context for group "work"`) || !strings.Contains(got, "Goal: ship") || !strings.HasSuffix(got, "do it") {
+		t.Errorf("unexpected context message: %q", got)
+	}
+	if only := WithGroupContext("work", "Goal", ""); strings.Contains(only, "---") {
+		t.Errorf("README-only message should have no separator: %q", only)
+	}
+}
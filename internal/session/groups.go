@@ -36,7 +36,8 @@ const (
 	ItemTypeRemoteGroup
 	ItemTypeRemoteSession
 	ItemTypeWindow
-	ItemTypeDivider // Non-selectable separator between view-mode sections (running-on-top, etc.)
+	ItemTypeDivider  // Non-selectable separator between view-mode sections (running-on-top, etc.)
+	ItemTypeSubagent // Non-selectable, ephemeral child row for a running Claude sub-agent
 )
 
 // Item represents a single item in the flattened group tree view
@@ -63,6 +64,15 @@ type Item struct {
 	CreatingTitle       string             // Display title for creating placeholder
 	CreatingTool        string             // Tool for creating placeholder
 	DividerLabel        string             // Label shown on an ItemTypeDivider row (e.g. "idle / done")
+	Subagent            *Subagent          // Sub-agent shown on an ItemTypeSubagent row
+	SubagentSessionID   string             // Parent session ID (for ItemTypeSubagent)
+	IsLastSubagent      bool               // True if last sub-agent row of its parent session
+}
+
+// Selectable reports whether the cursor may rest on this row. Dividers and
+// sub-agent rows are display-only.
+func (it Item) Selectable() bool {
+	return it.Type != ItemTypeDivider && it.Type != ItemTypeSubagent
 }
 
 // IsCreatingPlaceholder reports whether this row is a still-creating session
@@ -87,6 +97,9 @@ type Group struct {
 	// (default for newly-created groups); N>=2 = bounded parallelism. Negative
 	// values are treated as unlimited (explicit opt-out).
 	MaxConcurrent int
+	// DefaultMCPs are attached to every session created in this group, and
+	// inherited by subgroups that set none of their own. See group_mcps.go.
+	DefaultMCPs []string
 }
 
 // GroupTree manages hierarchical session organization
@@ -109,20 +122,20 @@ type GroupTree struct {
 //
 // Buckets:
 //
-//	0  error                       (something broke, look at me)
+//	0  error, crashed              (something broke, look at me)
 //	1  waiting                     (model done, awaiting user input)
 //	2  running, starting           (model actively working)
 //	3  idle, queued, "" (unset)    (nothing to do; "" matches legacy
 //	                                instances persisted before this field
 //	                                was widely populated — TestSessionOrder*
 //	                                rely on this neutral default)
-//	4  stopped                     (user-parked, bottom of pile)
+//	4  stopped, exited             (user-parked or finished, bottom of pile)
 //
 // Any future Status value not enumerated above defaults to 5 so it sorts
 // after every known bucket rather than silently slotting into "idle".
 func actionablePriority(s Status) int {
 	switch s {
-	case StatusError:
+	case StatusError, StatusCrashed:
 		return 0
 	case StatusWaiting:
 		return 1
@@ -130,7 +143,7 @@ func actionablePriority(s Status) int {
 		return 2
 	case StatusIdle, StatusQueued, "":
 		return 3
-	case StatusStopped:
+	case StatusStopped, StatusExited:
 		return 4
 	}
 	return 5
@@ -337,6 +350,7 @@ func NewGroupTreeWithGroups(instances []*Instance, storedGroups []*GroupData) *G
 			Order:         gd.Order,
 			DefaultPath:   gd.DefaultPath,
 			MaxConcurrent: gd.MaxConcurrent,
+			DefaultMCPs:   gd.DefaultMCPs,
 		}
 		tree.Groups[gd.Path] = group
 		tree.Expanded[gd.Path] = gd.Expanded
@@ -1572,6 +1586,7 @@ func (t *GroupTree) ShallowCopyForSave() *GroupTree {
 			Order:         g.Order,
 			DefaultPath:   g.DefaultPath,
 			MaxConcurrent: g.MaxConcurrent,
+			DefaultMCPs:   append([]string(nil), g.DefaultMCPs...),
 			// Don't copy Sessions - not needed for save, only metadata is saved
 		}
 	}
@@ -0,0 +1,137 @@
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// The hook timeline keeps the last hook events of each session, one JSON
+// object per line in <data-dir>/hook-events/<session-id>.jsonl, so `session
+// show` can tell what an agent has been doing and not only its latest hook
+// status. Every hook event runs a fresh hook-handler process, so the file is
+// appended to and trimmed to the newest hookTimelineKeep events once it
+// grows past hookTimelineMaxBytes; nothing is held in memory between events.
+const (
+	hookTimelineKeep     = 100
+	hookTimelineMaxBytes = 64 << 10
+)
+
+// HookEvent is one recorded hook event.
+type HookEvent struct {
+	Timestamp time.Time `json:"ts"`
+	Event     string    `json:"event"`
+	Status    string    `json:"status"`
+
+	// DoneStatus is the worker's completion sentinel, when the event
+	// carried one.
+	DoneStatus string `json:"done_status,omitempty"`
+}
+
+// HookTimelinePath returns the hook timeline file of a session.
+func HookTimelinePath(sessionID string) (string, error) {
+	if sessionID == "" || sessionID == "." || sessionID == ".." || strings.ContainsAny(sessionID, `/\`) {
+		return "", fmt.Errorf("invalid session id %q", sessionID)
+	}
+	dir, err := dataPath("hook-events", "hook-events")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, sessionID+".jsonl"), nil
+}
+
+// AppendHookEvent records ev in a session's hook timeline. Best-effort: two
+// hook processes trimming at once may drop an event, which only shortens the
+// timeline.
+func AppendHookEvent(sessionID string, ev HookEvent) error {
+	path, err := HookTimelinePath(sessionID)
+	if err != nil {
+		return err
+	}
+	if ev.Timestamp.IsZero() {
+		ev.Timestamp = time.Now().UTC()
+	}
+	line, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	_, werr := f.Write(append(line, '\n'))
+	var size int64
+	if info, err := f.Stat(); err == nil {
+		size = info.Size()
+	}
+	if cerr := f.Close(); werr == nil {
+		werr = cerr
+	}
+	if werr != nil || size <= hookTimelineMaxBytes {
+		return werr
+	}
+
+	events, err := readHookEventsFile(path)
+	if err != nil {
+		return err
+	}
+	if len(events) > hookTimelineKeep {
+		events = events[len(events)-hookTimelineKeep:]
+	}
+	var buf bytes.Buffer
+	for _, e := range events {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+		buf.WriteByte('\n')
+	}
+	return writeJSONFileAtomic(path, buf.Bytes(), 0o600)
+}
+
+// ReadHookTimeline returns up to the last n recorded hook events of a
+// session, oldest first; n <= 0 returns them all. A session with no recorded
+// events yields an empty slice.
+func ReadHookTimeline(sessionID string, n int) ([]HookEvent, error) {
+	path, err := HookTimelinePath(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := readHookEventsFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if n > 0 && len(events) > n {
+		events = events[len(events)-n:]
+	}
+	return events, nil
+}
+
+func readHookEventsFile(path string) ([]HookEvent, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var events []HookEvent
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var e HookEvent
+		if json.Unmarshal(sc.Bytes(), &e) == nil {
+			events = append(events, e)
+		}
+	}
+	return events, sc.Err()
+}
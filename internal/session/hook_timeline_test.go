@@ -0,0 +1,54 @@
+package session
+
+import (
+	"os"
+	"testing"
+)
+
+func TestHookTimeline_AppendReadAndTrim(t *testing.T) {
+	id := "hook-timeline-test"
+	path, err := HookTimelinePath(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = os.Remove(path)
+	t.Cleanup(func() { _ = os.Remove(path) })
+
+	if events, err := ReadHookTimeline(id, 10); err != nil || len(events) != 0 {
+		t.Fatalf("empty timeline = %+v, %v", events, err)
+	}
+
+	for _, ev := range []HookEvent{
+		{Event: "UserPromptSubmit", Status: "running"},
+		{Event: "Stop", Status: "waiting", DoneStatus: "done"},
+	} {
+		if err := AppendHookEvent(id, ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+	events, err := ReadHookTimeline(id, 1)
+	if err != nil || len(events) != 1 {
+		t.Fatalf("last event = %+v, %v; want 1", events, err)
+	}
+	if events[0].Event != "Stop" || events[0].DoneStatus != "done" || events[0].Timestamp.IsZero() {
+		t.Errorf("last event = %+v", events[0])
+	}
+
+	// Past the size cap the file is cut back to the newest events.
+	for i := 0; i < 1000; i++ {
+		if err := AppendHookEvent(id, HookEvent{Event: "UserPromptSubmit", Status: "running"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() > hookTimelineMaxBytes {
+		t.Errorf("timeline not trimmed: %v, %v", info, err)
+	}
+	events, err = ReadHookTimeline(id, 0)
+	if err != nil || len(events) < hookTimelineKeep || len(events) > 1002 {
+		t.Errorf("after trim: %d events, %v", len(events), err)
+	}
+
+	if _, err := HookTimelinePath("../escape"); err == nil {
+		t.Error("expected an invalid session id error")
+	}
+}
@@ -0,0 +1,98 @@
+// Idle-stop policy: [idle_stop] after = "4h" stops every session whose pane
+// has shown no new output for that long, so dormant agents stop burning CPU
+// and battery. A stopped session keeps its metadata and conversation ID, so
+// restarting it resumes where it left off. [groups."<path>"] idle_timeout
+// overrides the default for a group and its subgroups ("0" exempts the
+// group), a session's own idle-timeout wins over both, and a session
+// labeled with exempt_label is never stopped. IdleTimeoutWatcher applies
+// the resolved timeout.
+package session
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultIdleStopExemptLabel is the label that exempts a session from idle
+// auto-stop when [idle_stop].exempt_label is unset.
+const DefaultIdleStopExemptLabel = "keep-alive"
+
+// IdleStopSettings is the [idle_stop] section.
+type IdleStopSettings struct {
+	// After is the default idle timeout for every session, as a duration
+	// like "4h". Empty or "0" leaves sessions running (the default).
+	After string `toml:"after,omitempty"`
+	// ExemptLabel names the session label that opts out of idle auto-stop.
+	// Default: "keep-alive".
+	ExemptLabel string `toml:"exempt_label,omitempty"`
+}
+
+// GetExemptLabel returns the exempt label, defaulting to "keep-alive".
+func (s IdleStopSettings) GetExemptLabel() string {
+	if v := strings.TrimSpace(s.ExemptLabel); v != "" {
+		return v
+	}
+	return DefaultIdleStopExemptLabel
+}
+
+// GetGroupIdleTimeout returns the idle_timeout spec for a group, walking
+// ancestor groups when the exact path sets none, and the group it came from.
+func (c *UserConfig) GetGroupIdleTimeout(groupPath string) (spec, matchedGroup string) {
+	if c == nil || c.Groups == nil {
+		return "", ""
+	}
+	for p := groupPath; p != ""; p = getParentPath(p) {
+		if groupCfg, ok := c.Groups[p]; ok && strings.TrimSpace(groupCfg.IdleTimeout) != "" {
+			return strings.TrimSpace(groupCfg.IdleTimeout), p
+		}
+	}
+	return "", ""
+}
+
+// idleStopWarned dedupes the warning for an unparseable timeout so a typo
+// logs once rather than on every tick.
+var idleStopWarned sync.Map
+
+// ResolveIdleTimeout returns the idle timeout in effect for the session and
+// where it came from: "session", "label:<label>", "group:<path>", "config",
+// or "" when the session is never auto-stopped.
+func (i *Instance) ResolveIdleTimeout() (time.Duration, string) {
+	config, _ := LoadUserConfig()
+	return i.resolveIdleTimeout(config)
+}
+
+func (i *Instance) resolveIdleTimeout(config *UserConfig) (time.Duration, string) {
+	if i.IdleTimeoutSecs > 0 {
+		return time.Duration(i.IdleTimeoutSecs) * time.Second, "session"
+	}
+	if config == nil {
+		return 0, ""
+	}
+	if label := config.IdleStop.GetExemptLabel(); i.HasLabel(label) {
+		return 0, "label:" + label
+	}
+	spec, source := config.GetGroupIdleTimeout(i.GroupPath)
+	if spec != "" {
+		source = "group:" + source
+	} else {
+		spec, source = strings.TrimSpace(config.IdleStop.After), "config"
+	}
+	if spec == "" {
+		return 0, ""
+	}
+	secs, err := ParseIdleTimeoutFlag(spec)
+	if err != nil {
+		if _, seen := idleStopWarned.LoadOrStore(source+"|"+spec, true); !seen {
+			sessionLog.Warn("idle_stop_invalid",
+				slog.String("source", source),
+				slog.String("error", err.Error()))
+		}
+		return 0, ""
+	}
+	if secs == 0 {
+		return 0, source
+	}
+	return time.Duration(secs) * time.Second, source
+}
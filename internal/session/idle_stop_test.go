@@ -0,0 +1,138 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolveIdleTimeout_Precedence(t *testing.T) {
+	cfg := &UserConfig{
+		IdleStop: IdleStopSettings{After: "4h"},
+		Groups: map[string]GroupSettings{
+			"work":      {IdleTimeout: "8h"},
+			"work/live": {IdleTimeout: "0"},
+			"broken":    {IdleTimeout: "soon"},
+		},
+	}
+	cases := []struct {
+		name       string
+		inst       *Instance
+		wantD      time.Duration
+		wantSource string
+	}{
+		{"global default", &Instance{GroupPath: "personal"}, 4 * time.Hour, "config"},
+		{"group override", &Instance{GroupPath: "work/api"}, 8 * time.Hour, "group:work"},
+		{"group exempt", &Instance{GroupPath: "work/live/db"}, 0, "group:work/live"},
+		{"session wins", &Instance{GroupPath: "work", IdleTimeoutSecs: 600}, 10 * time.Minute, "session"},
+		{"exempt label", &Instance{GroupPath: "work", Labels: []string{"Keep-Alive"}}, 0, "label:keep-alive"},
+		{"invalid group spec", &Instance{GroupPath: "broken"}, 0, ""},
+	}
+	for _, c := range cases {
+		d, source := c.inst.resolveIdleTimeout(cfg)
+		if d != c.wantD || source != c.wantSource {
+			t.Errorf("%s: got (%s, %q), want (%s, %q)", c.name, d, source, c.wantD, c.wantSource)
+		}
+	}
+
+	if d, source := (&Instance{}).resolveIdleTimeout(&UserConfig{}); d != 0 || source != "" {
+		t.Errorf("no policy: got (%s, %q), want never", d, source)
+	}
+	custom := &UserConfig{IdleStop: IdleStopSettings{After: "1h", ExemptLabel: "pinned-work"}}
+	if d, _ := (&Instance{Labels: []string{"keep-alive"}}).resolveIdleTimeout(custom); d != time.Hour {
+		t.Errorf("default label should not exempt when exempt_label is customized, got %s", d)
+	}
+	if d, _ := (&Instance{Labels: []string{"pinned-work"}}).resolveIdleTimeout(custom); d != 0 {
+		t.Errorf("custom exempt label ignored, got %s", d)
+	}
+}
+
+func TestIdleTimeoutWatcher_AppliesPolicy(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	clock := newFakeClock(time.Unix(1_700_000_000, 0))
+	capture := newFakeCapture()
+	stopper := &recordingStopper{}
+	cfg := &UserConfig{IdleStop: IdleStopSettings{After: "1h"}}
+	w := NewIdleTimeoutWatcher(IdleTimeoutWatcherConfig{
+		Now:     clock.Now,
+		Capture: capture.Capture,
+		Stop:    stopper.Stop,
+		Config:  func() *UserConfig { return cfg },
+	})
+
+	idle := newRunningInstance("idle", "idle", 0)
+	exempt := newRunningInstance("exempt", "exempt", 0)
+	exempt.Labels = []string{DefaultIdleStopExemptLabel}
+	for _, inst := range []*Instance{idle, exempt} {
+		capture.Set(inst.ID, "same")
+	}
+	all := []*Instance{idle, exempt}
+
+	w.Tick(all)
+	clock.Advance(59 * time.Minute)
+	w.Tick(all)
+	if got := stopper.StoppedIDs(); len(got) != 0 {
+		t.Fatalf("stopped before the policy timeout: %v", got)
+	}
+	clock.Advance(2 * time.Minute)
+	w.Tick(all)
+	if got := stopper.StoppedIDs(); len(got) != 1 || got[0] != "idle" {
+		t.Fatalf("stopped = %v, want only the unlabeled session", got)
+	}
+}
+
+func TestParseLabels(t *testing.T) {
+	got, err := ParseLabels(" keep-alive, frontend,,keep-alive ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != "keep-alive" || got[1] != "frontend" {
+		t.Errorf("ParseLabels = %v", got)
+	}
+	if _, err := ParseLabels("two words"); err == nil {
+		t.Error("label with a space accepted")
+	}
+	if got, _ := ParseLabels(""); got != nil {
+		t.Errorf("empty = %v, want nil", got)
+	}
+}
+
+func TestSetField_Labels(t *testing.T) {
+	inst := &Instance{ID: "x"}
+	if _, _, err := SetField(inst, FieldLabels, "keep-alive,api", nil); err != nil {
+		t.Fatalf("SetField: %v", err)
+	}
+	if !inst.HasLabel("keep-alive") || !inst.HasLabel("API") {
+		t.Errorf("Labels = %v", inst.Labels)
+	}
+	if _, _, err := SetField(inst, FieldLabels, "", nil); err != nil || len(inst.Labels) != 0 || !inst.labelsCleared {
+		t.Errorf("clear: %v cleared=%v err=%v", inst.Labels, inst.labelsCleared, err)
+	}
+}
+
+func TestLabels_SQLiteRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	storage := newTestStorage(t)
+
+	inst := NewInstance("labels-roundtrip", "/tmp")
+	inst.Tool = "shell"
+	inst.Labels = []string{"keep-alive", "api"}
+	save := func() []*Instance {
+		t.Helper()
+		if err := storage.SaveWithGroups([]*Instance{inst}, NewGroupTreeWithGroups([]*Instance{inst}, nil)); err != nil {
+			t.Fatalf("SaveWithGroups: %v", err)
+		}
+		loaded, _, err := storage.LoadWithGroups()
+		if err != nil || len(loaded) != 1 {
+			t.Fatalf("LoadWithGroups: %v (%d)", err, len(loaded))
+		}
+		return loaded
+	}
+
+	if got := save()[0].Labels; len(got) != 2 || got[0] != "keep-alive" || got[1] != "api" {
+		t.Fatalf("labels after round trip = %v", got)
+	}
+	inst.SetLabels(nil)
+	if got := save()[0].Labels; len(got) != 0 {
+		t.Fatalf("cleared labels came back: %v", got)
+	}
+}
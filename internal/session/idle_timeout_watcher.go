@@ -1,5 +1,6 @@
 // Issue #1143: central poll watcher that auto-stops sessions whose tmux pane
-// content hasn't changed for `IdleTimeoutSecs` seconds.
+// content hasn't changed for their idle timeout: the session's own
+// `IdleTimeoutSecs`, else the [idle_stop] policy (see idle_stop.go).
 //
 // Design (per RFC in PR body):
 //
@@ -35,7 +36,7 @@ var idleLog = logging.ForComponent(logging.CompSession)
 // SessionLifecycleEvent is a single row in session-lifecycle.jsonl.
 type SessionLifecycleEvent struct {
 	InstanceID string `json:"instance_id"`
-	Action     string `json:"action"` // currently only "idle-timeout-expired"
+	Action     string `json:"action"` // "idle-timeout-expired", "working-hours-paused", "working-hours-resumed"
 	Reason     string `json:"reason,omitempty"`
 	Timestamp  int64  `json:"ts"`
 }
@@ -101,6 +102,8 @@ type IdleTimeoutWatcherConfig struct {
 	// LogEvent persists a "session lifecycle" row. Defaults to
 	// WriteSessionLifecycleEvent.
 	LogEvent func(SessionLifecycleEvent) error
+	// Config supplies the [idle_stop] policy. Defaults to LoadUserConfig.
+	Config func() *UserConfig
 }
 
 // IdleTimeoutWatcher polls running sessions, tracks per-instance pane-content
@@ -137,29 +140,37 @@ func NewIdleTimeoutWatcher(cfg IdleTimeoutWatcherConfig) *IdleTimeoutWatcher {
 	if cfg.LogEvent == nil {
 		cfg.LogEvent = WriteSessionLifecycleEvent
 	}
+	if cfg.Config == nil {
+		cfg.Config = func() *UserConfig {
+			config, _ := LoadUserConfig()
+			return config
+		}
+	}
 	return &IdleTimeoutWatcher{
 		cfg:      cfg,
 		lastSeen: map[string]idleSeenEntry{},
 	}
 }
 
-// Tick scans the given instances. Sessions with IdleTimeoutSecs <= 0 are
+// Tick scans the given instances. Sessions without an idle timeout are
 // skipped (and their tracking state is cleared so toggling off via SetField
-// works on the next tick). Non-running sessions are skipped too.
+// or config works on the next tick). Non-running sessions are skipped too.
 func (w *IdleTimeoutWatcher) Tick(instances []*Instance) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
+	config := w.cfg.Config()
 	now := w.cfg.Now()
 	for _, inst := range instances {
 		if inst == nil {
 			continue
 		}
-		// IdleTimeoutSecs is checked FIRST because it is the gate that actually
+		// The timeout is resolved FIRST because it is the gate that actually
 		// bounds cost: Capture (a tmux subprocess) is the only expensive step in
 		// this loop, and it is unreachable for a session with no idle timeout
-		// armed. Idle timeout is strictly opt-in per session, so an archive
-		// backlog of hundreds of unarmed sessions costs one int64 read each.
+		// armed. Without an [idle_stop] policy idle timeout is opt-in per
+		// session, so an archive backlog of hundreds of unarmed sessions costs
+		// a config lookup each.
 		//
 		// Deliberately NOT short-circuiting on IsArchived(): an archived session
 		// whose tmux Kill silently failed keeps a live pane and a frozen
@@ -168,7 +179,8 @@ func (w *IdleTimeoutWatcher) Tick(instances []*Instance) {
 		// measurable time (they are unarmed, hence already free above) while
 		// removing the safety net for exactly the orphan class that motivated
 		// it.
-		if inst.IdleTimeoutSecs <= 0 {
+		threshold, source := inst.resolveIdleTimeout(config)
+		if threshold <= 0 {
 			delete(w.lastSeen, inst.ID)
 			continue
 		}
@@ -200,7 +212,6 @@ func (w *IdleTimeoutWatcher) Tick(instances []*Instance) {
 			continue
 		}
 		elapsed := now.Sub(prev.lastChange)
-		threshold := time.Duration(inst.IdleTimeoutSecs) * time.Second
 		if elapsed < threshold {
 			continue
 		}
@@ -216,8 +227,8 @@ func (w *IdleTimeoutWatcher) Tick(instances []*Instance) {
 			InstanceID: inst.ID,
 			Action:     ReasonIdleTimeoutExpired,
 			Reason: fmt.Sprintf(
-				"no tmux pane output change for %ds (threshold=%ds)",
-				int64(elapsed/time.Second), inst.IdleTimeoutSecs,
+				"no tmux pane output change for %ds (threshold=%ds, %s)",
+				int64(elapsed/time.Second), int64(threshold/time.Second), source,
 			),
 		}); logErr != nil {
 			idleLog.Warn("idle_timeout_log_failed",
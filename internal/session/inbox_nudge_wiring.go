@@ -2,8 +2,10 @@ package session
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"os/exec"
+	"strings"
 	"time"
 )
 
@@ -44,6 +46,14 @@ type wakeNudgeWiring struct {
 	now    func() time.Time
 	isIdle func(parent *Instance) bool
 	send   func(parent *Instance, profile string) error
+
+	// digestWindow, when set and non-zero, holds wake-ups for
+	// [notifications.digest]: committed events are collected in digest and
+	// FlushDigests sends one sendDigest per parent once the window passes.
+	// Only the notify-daemon sets it, since it is what calls FlushDigests.
+	digestWindow func() time.Duration
+	digest       *notifyDigest
+	sendDigest   func(parent *Instance, profile, msg string) error
 }
 
 // defaultWakeNudgeWiring is the production wiring: a debounced nudger, the wall
@@ -81,6 +91,13 @@ func (n *TransitionNotifier) fireWakeNudge(parent *Instance, event TransitionNot
 		now = w.now()
 	}
 	profile := event.Profile
+	if w.digestWindow != nil && w.digest != nil && w.digestWindow() > 0 {
+		w.digest.add(profile+"\x00"+parent.ID, profile, parent, now, digestItem{
+			id:   event.ChildSessionID,
+			line: digestEventLine(event),
+		})
+		return
+	}
 	isIdle := func() bool { return w.isIdle != nil && w.isIdle(parent) }
 	send := func() error {
 		if w.send == nil {
@@ -96,6 +113,91 @@ func (n *TransitionNotifier) fireWakeNudge(parent *Instance, event TransitionNot
 	}
 }
 
+// FlushDigests wakes each conductor of profile whose [notifications.digest]
+// window has passed with one message listing the children that reported to
+// its inbox meanwhile. byID supplies the parents' current status; a parent
+// that is busy is not woken, as it drains the same records at the end of its
+// turn. The notify-daemon calls it every poll. Returns the wake-ups sent.
+func (n *TransitionNotifier) FlushDigests(profile string, byID map[string]*Instance, now time.Time) int {
+	w := n.wake
+	if w == nil || w.digest == nil || w.digestWindow == nil {
+		return 0
+	}
+	// With digests since turned off the window is 0: whatever is still
+	// pending goes out now.
+	window := max(w.digestWindow(), 0)
+	sent := 0
+	for _, b := range w.digest.takeDue(profile+"\x00", window, now) {
+		parent := b.target
+		if fresh := byID[parent.ID]; fresh != nil {
+			parent = fresh
+		}
+		if w.isIdle == nil || !w.isIdle(parent) {
+			commsLog.Debug("wake_digest_skipped_busy",
+				slog.String("parent", parent.ID), slog.Int("events", len(b.items)))
+			continue
+		}
+		if w.sendDigest == nil {
+			continue
+		}
+		if err := w.sendDigest(parent, b.profile, wakeDigestMessage(b.items, window)); err != nil {
+			commsLog.Warn("wake_digest_send_failed",
+				slog.String("parent", parent.ID), slog.String("error", err.Error()))
+			continue
+		}
+		sent++
+	}
+	return sent
+}
+
+// digestEventLine describes one committed event in a conductor digest, e.g.
+// "api waiting" or "docs finished (success)".
+func digestEventLine(event TransitionNotificationEvent) string {
+	title := event.ChildTitle
+	if title == "" {
+		title = event.ChildSessionID
+	}
+	if event.Kind == transitionKindFinished {
+		if event.DoneStatus != "" {
+			return fmt.Sprintf("%s finished (%s)", title, event.DoneStatus)
+		}
+		return title + " finished"
+	}
+	return title + " " + event.ToStatus
+}
+
+// wakeDigestMessage is the single wake-up a digest sends in place of one
+// wakeNudgeMessage per committed event.
+func wakeDigestMessage(items []digestItem, window time.Duration) string {
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		lines = append(lines, item.line)
+	}
+	period := ""
+	if window > 0 {
+		period = fmt.Sprintf(" in the last %d min", int(window.Minutes()))
+	}
+	return fmt.Sprintf("[DIGEST] %d child update(s)%s: %s — drain your inbox and act on each item now.",
+		len(items), period, strings.Join(lines, "; "))
+}
+
+// conductorDigestWindow reads the [notifications.digest] conductor window.
+func conductorDigestWindow() time.Duration {
+	return GetNotificationsSettings().Digest.ConductorWindow()
+}
+
+// EnableWakeDigests makes n collect conductor wake-ups for
+// [notifications.digest] instead of sending each at once. The caller must
+// call FlushDigests regularly.
+func (n *TransitionNotifier) EnableWakeDigests() {
+	if n.wake == nil {
+		return
+	}
+	n.wake.digestWindow = conductorDigestWindow
+	n.wake.digest = newNotifyDigest()
+	n.wake.sendDigest = sendWakeDigest
+}
+
 // parentIsNudgeableIdle reports whether parent is safe to wake with a send-keys
 // nudge: it must be a conductor (only conductors drain an inbox on Stop, so a
 // nudge to a non-conductor leaf would be pure noise) AND currently idle/waiting,
@@ -137,6 +239,20 @@ func sendWakeNudge(parent *Instance, profile string) error {
 	return nil
 }
 
+// sendWakeDigest is sendWakeNudge with a digest message.
+func sendWakeDigest(parent *Instance, profile, msg string) error {
+	if parent == nil {
+		return nil
+	}
+	go func(profile, ref string) {
+		if err := sendWakeMessageNoWait(profile, ref, msg); err != nil {
+			commsLog.Warn("wake_digest_dispatch_failed",
+				slog.String("parent", ref), slog.String("error", err.Error()))
+		}
+	}(profile, parent.ID)
+	return nil
+}
+
 // wakeNudgeSendTimeout bounds the detached wake-nudge subprocess. --no-wait
 // already returns fast, so 5s is generous; the bound exists purely so a wedged
 // agent-deck binary (e.g. stuck on SQLite/tmux) is reaped instead of leaking the
@@ -157,6 +273,11 @@ var wakeNudgeExec = func(ctx context.Context, bin string, args ...string) error
 // pane is wedged. The context deadline is a belt-and-suspenders backstop for the
 // case where even the subprocess itself hangs.
 func sendWakeNudgeNoWait(profile, ref string) error {
+	return sendWakeMessageNoWait(profile, ref, wakeNudgeMessage)
+}
+
+// sendWakeMessageNoWait is sendWakeNudgeNoWait with any message.
+func sendWakeMessageNoWait(profile, ref, msg string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), wakeNudgeSendTimeout)
 	defer cancel()
 	bin := agentDeckBinaryPath()
@@ -164,6 +285,6 @@ func sendWakeNudgeNoWait(profile, ref string) error {
 	if profile != "" {
 		args = append(args, "-p", profile)
 	}
-	args = append(args, "session", "send", ref, wakeNudgeMessage, "--no-wait", "-q")
+	args = append(args, "session", "send", ref, msg, "--no-wait", "-q")
 	return wakeNudgeExec(ctx, bin, args...)
 }
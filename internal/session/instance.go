@@ -57,6 +57,12 @@ const (
 	// group max_concurrent caps; a launch into a group at cap stores the
 	// instance with this status and starts it once a running session ends.
 	StatusQueued Status = "queued"
+	// StatusExited and StatusCrashed report that the agent process itself
+	// ended while its pane lives on (a dead pane under remain-on-exit, or the
+	// exit-to-shell prompt). The recorded exit code decides which: 0 is a
+	// clean finish, anything else a crash. See exit_status.go.
+	StatusExited  Status = "exited"
+	StatusCrashed Status = "crashed"
 )
 
 // Substate is the additive Honest-Status-v2 refinement of a session's coarse
@@ -72,6 +78,7 @@ const (
 	SubstateIdleAtEmptyPrompt = tmux.SubstateIdleAtEmptyPrompt
 	SubstateModelUnavailable  = tmux.SubstateModelUnavailable
 	SubstateAuth401           = tmux.SubstateAuth401
+	SubstateUsageLimit        = tmux.SubstateUsageLimit
 )
 
 const wrapperPlaceholder = "{command}"
@@ -287,6 +294,11 @@ type Instance struct {
 	TrackedMCPPIDs []int `json:"tracked_mcp_pids,omitempty"`
 	mcpPIDsMu      sync.Mutex
 
+	// subagents tracks the Claude sub-agents (Task tool calls) this session
+	// has running, parsed from its transcript. See subagents.go.
+	subagentsOnce sync.Once
+	subagents     *subagentTracker
+
 	// Channels are Claude Code plugin-channel ids (e.g. "plugin:telegram@user/repo").
 	// When non-empty on a claude session, buildClaudeExtraFlags emits
 	// `--channels <csv>` so the session subscribes to inbound plugin messages.
@@ -347,6 +359,49 @@ type Instance struct {
 	// so existing sessions are unaffected on upgrade.
 	IdleTimeoutSecs int64 `json:"idle_timeout_secs,omitempty"`
 
+	// WorkingHours overrides the group's working_hours window for this
+	// session: a window spec like "09:00-17:00", or "always" to exempt it.
+	// Empty inherits the group. workingHoursCleared records that an
+	// override was removed so the next save overrides the persisted value.
+	WorkingHours        string `json:"working_hours,omitempty"`
+	workingHoursCleared bool
+
+	// Checkpoint is the automatic checkpoint mode ("waiting", an interval,
+	// or both; see checkpoint.go). Empty means off. checkpointCleared records
+	// that it was turned off so the next save overrides the persisted value.
+	Checkpoint        string `json:"checkpoint,omitempty"`
+	checkpointCleared bool
+
+	// Labels are free-form tags set with `session set <id> labels a,b`.
+	// A session labeled [idle_stop].exempt_label is never auto-stopped.
+	// labelsCleared records that the labels were removed so the next save
+	// overrides the persisted value.
+	Labels        []string `json:"labels,omitempty"`
+	labelsCleared bool
+
+	// Preset is the start preset (resource tier) the session was last
+	// switched to; see ApplyStartPreset. Empty if none was chosen.
+	Preset string `json:"preset,omitempty"`
+
+	// Layout names the [layouts] entry the session was created with, and
+	// Panes are its extra tmux panes, resolved at creation (see layouts.go).
+	// Empty for a single-pane session.
+	Layout string          `json:"layout,omitempty"`
+	Panes  []tmux.PaneSpec `json:"panes,omitempty"`
+
+	// DependsOn lists the IDs of sessions that must be running before this
+	// one starts (see DependencyGraph). dependsOnCleared records that the
+	// list was emptied so the next save overrides the persisted value.
+	DependsOn        []string `json:"depends_on,omitempty"`
+	dependsOnCleared bool
+
+	// Dispatch is the deferred conductor dispatch while this conductor
+	// child is queued for capacity (see CheckDispatchCapacity).
+	// dispatchCleared records that it was drained so the next save
+	// overrides the persisted value.
+	Dispatch        *DispatchRequest `json:"dispatch,omitempty"`
+	dispatchCleared bool
+
 	// IsForkAwaitingStart signals that this instance was produced by a
 	// fork builder and must run a pre-built fork command verbatim on the
 	// first Start() (#745). Claude fork targets usually store that command
@@ -403,6 +458,15 @@ type Instance struct {
 
 	tmuxSession *tmux.Session // Internal tmux session
 
+	// projectPathMissing is set at load when ProjectPath no longer exists
+	// (the project was moved or renamed); see relocate.go.
+	projectPathMissing bool
+
+	// tmuxOptionBatch, when set by a StartBatch, collects the cosmetic tmux
+	// options of Start/StartWithMessage/Restart so a bulk start flushes them in
+	// one call.
+	tmuxOptionBatch *tmux.OptionBatch
+
 	// Hook-based status detection (set by StatusFileWatcher from Claude Code hooks)
 	hookStatus     string    // running, idle, waiting, dead (empty = no hook data)
 	hookEvent      string    // Hook event name that caused the last status (e.g. "PermissionRequest")
@@ -424,6 +488,9 @@ type Instance struct {
 	// superseded it — race-free, without reading the mutex-guarded status fields
 	// from its own goroutine.
 	spawnGen atomic.Uint64
+	// spawnFailedGen holds the spawnGen the fast-death watcher classified as a
+	// start failure (0 = none). See StartFailed.
+	spawnFailedGen atomic.Uint64
 
 	// lastErrorCheck tracks when we last confirmed the session doesn't exist
 	// Used to skip expensive Exists() checks for ghost sessions (sessions in JSON but not in tmux)
@@ -758,7 +825,8 @@ func logSessionCreated(inst *Instance) {
 }
 
 // applyLaunchSettingsFromConfig copies LaunchInUserScope and LaunchAs from
-// the live TmuxSettings onto the tmux session, just before each Start().
+// the live TmuxSettings, and the session's layout panes and claimed ports,
+// onto the tmux session, just before each Start().
 //
 // Regression pin for #958 (SSH-logout session loss): three Start() call
 // sites in this file each need this wire-up. Consolidating into one helper
@@ -769,6 +837,8 @@ func (i *Instance) applyLaunchSettingsFromConfig() {
 	settings := GetTmuxSettings()
 	i.tmuxSession.LaunchInUserScope = settings.GetLaunchInUserScope()
 	i.tmuxSession.LaunchAs = settings.GetLaunchAs()
+	i.tmuxSession.Panes = i.Panes
+	i.tmuxSession.Environment = i.portEnv()
 	i.applyVimModeFromConfig()
 }
 
@@ -1115,6 +1185,40 @@ func (i *Instance) ensureProfileEnv() {
 	}
 }
 
+// RetargetLiveProfile points a running session at another profile without
+// restarting it: AGENTDECK_PROFILE is rewritten on the tmux session, and the
+// tmux display name is refreshed from the (possibly renamed) title. The agent
+// itself keeps the old value its launch command exported, but hook-handler
+// and in-session CLI calls resolve the profile from the tmux session at call
+// time (GetEffectiveProfile), so they land in the new profile's state.db.
+// Helpers the agent started before the move (MCP servers) keep the old
+// profile until the session restarts. The process, its scrollback and the
+// Claude session ID are untouched. No-op when the tmux session is not running.
+func (i *Instance) RetargetLiveProfile(profile string) error {
+	if i.tmuxSession == nil || !i.tmuxSession.Exists() {
+		return nil
+	}
+	if err := i.tmuxSession.SetEnvironment("AGENTDECK_PROFILE", profile); err != nil {
+		return err
+	}
+	i.SyncTmuxDisplayName()
+	return nil
+}
+
+// RenameTmuxSession gives the live tmux session a fresh name derived from the
+// instance's current title and returns it. An instance without a live tmux
+// session is left alone and yields "".
+func (i *Instance) RenameTmuxSession() (string, error) {
+	if i.tmuxSession == nil || !i.tmuxSession.Exists() {
+		return "", nil
+	}
+	if err := i.tmuxSession.Rename(tmux.GenerateSessionName(i.Title)); err != nil {
+		return "", err
+	}
+	i.SyncTmuxDisplayName()
+	return i.tmuxSession.Name, nil
+}
+
 // logClaudeConfigResolution emits the CFG-07 observability line documenting
 // which priority level resolved CLAUDE_CONFIG_DIR for this session.
 // Owns the single CFG-07 slog message literal for this package.
@@ -3435,6 +3539,10 @@ func (i *Instance) Start() error {
 		command = i.buildCursorCommand(i.Command, false)
 	case i.Tool == "hermes":
 		command = i.buildHermesCommand(i.Command)
+	case i.Tool == "aider":
+		command = i.buildAiderCommand(i.Command, false)
+	case i.Tool == "goose":
+		command = i.buildGooseCommand(i.Command, false)
 	default:
 		// Check if this is a custom tool with session resume config
 		if toolDef := GetToolDef(i.Tool); toolDef != nil {
@@ -3461,6 +3569,7 @@ func (i *Instance) Start() error {
 	// Sandbox sessions also get remain-on-exit for dead-pane detection.
 	i.tmuxSession.OptionOverrides = i.buildTmuxOptionOverrides()
 	i.tmuxSession.RunCommandAsInitialProcess = i.IsSandboxed() || i.Tool != "shell"
+	i.tmuxSession.DeferCosmeticOptions(i.tmuxOptionBatch)
 	i.applyLaunchSettingsFromConfig()
 
 	// Re-assert the declarative per-group/per-conductor skill+mcp loadout
@@ -3704,6 +3813,10 @@ func (i *Instance) StartWithMessage(message string) error {
 		command = i.buildCursorCommand(i.Command, false)
 	case i.Tool == "hermes":
 		command = i.buildHermesCommand(i.Command)
+	case i.Tool == "aider":
+		command = i.buildAiderCommand(i.Command, false)
+	case i.Tool == "goose":
+		command = i.buildGooseCommand(i.Command, false)
 	default:
 		// Check if this is a custom tool with session resume config
 		if toolDef := GetToolDef(i.Tool); toolDef != nil {
@@ -3730,6 +3843,7 @@ func (i *Instance) StartWithMessage(message string) error {
 	// Sandbox sessions also get remain-on-exit for dead-pane detection.
 	i.tmuxSession.OptionOverrides = i.buildTmuxOptionOverrides()
 	i.tmuxSession.RunCommandAsInitialProcess = i.IsSandboxed() || i.Tool != "shell"
+	i.tmuxSession.DeferCosmeticOptions(i.tmuxOptionBatch)
 	i.applyLaunchSettingsFromConfig()
 
 	// Re-assert the declarative skill+mcp loadout before spawn — sister
@@ -4054,6 +4168,7 @@ func (i *Instance) terminatedPaneStatus() Status {
 func (i *Instance) UpdateStatus() error {
 	i.mu.Lock()
 	defer i.mu.Unlock()
+	defer i.recordStatusSample(i.Status)
 
 	// Short grace period for tmux initialization (not Claude startup)
 	// Use lastStartTime for accuracy on restarts, fallback to CreatedAt
@@ -4074,6 +4189,14 @@ func (i *Instance) UpdateStatus() error {
 		// Session exists - allow normal status detection below
 	}
 
+	// A spawn the fast-death watcher classified as a start failure stays an
+	// error until the next start, restart or stop, even while the pane lives
+	// on (dead pane under remain-on-exit, or the exit-to-shell prompt).
+	if i.StartFailed() {
+		i.Status = StatusError
+		return nil
+	}
+
 	if i.tmuxSession == nil {
 		if i.neverStarted() {
 			// A session that was added but never started has no tmux yet; it is
@@ -4114,6 +4237,14 @@ func (i *Instance) UpdateStatus() error {
 	// Session exists - clear error check timestamp
 	i.lastErrorCheck = time.Time{}
 
+	// The agent process itself ended while its pane lives on: report the
+	// recorded exit code as exited/crashed instead of letting the dead pane
+	// read as a generic error or the fallback shell prompt as waiting.
+	if status, ok := i.detectAgentExit(); ok {
+		i.Status = status
+		return nil
+	}
+
 	// Tiered polling: skip expensive checks for idle sessions with no new activity
 	if i.Status == StatusIdle {
 		currentTS := i.tmuxSession.GetCachedWindowActivity()
@@ -6272,6 +6403,10 @@ func (i *Instance) killInternal(sync bool) error {
 	// SIGKILL anything still alive.
 	i.reapTrackedMCPChildren()
 
+	// Keep the scrollback: append what the transcript recorder has not seen
+	// yet before the pane, and its history, are gone.
+	i.recordFinalTranscript()
+
 	// Issue #953: kill the tmux session AND publish StatusStopped
 	// atomically under i.mu so concurrent UpdateStatus() callers (most
 	// notably the TUI's backgroundStatusUpdate poller) cannot observe
@@ -6304,6 +6439,7 @@ func (i *Instance) killInternal(sync bool) error {
 	// #1580: supersede any in-flight fast-death watcher so a deliberate stop is
 	// never mistaken for a spawn failure.
 	i.spawnGen.Add(1)
+	prepareExitRecord(i.ID)
 
 	// Clean up sandbox container (only if name matches our prefix convention).
 	// Runs regardless of tmux kill result to avoid orphaned containers.
@@ -6405,6 +6541,13 @@ func (i *Instance) restart(env map[string]string) error {
 	}
 	defer recordInstanceSpawn(i.ID)
 
+	// A restart supersedes any in-flight fast-death watcher and any start
+	// failure it recorded; the respawn-pane paths below do not go through
+	// Start's recordSpawnAttempt.
+	i.spawnGen.Add(1)
+	clearSpawnFailureRecord(i.ID)
+	prepareExitRecord(i.ID)
+
 	if len(env) > 0 {
 		i.restartEnv = make(map[string]string, len(env))
 		for key, value := range env {
@@ -6696,6 +6839,33 @@ func (i *Instance) restart(env map[string]string) error {
 		return nil
 	}
 
+	// If Aider or goose session AND tmux session exists, use respawn-pane.
+	// Both reopen their last conversation (see buildAiderCommand and
+	// buildGooseCommand).
+	if (i.Tool == "aider" || i.Tool == "goose") && i.tmuxSession != nil && i.tmuxSession.Exists() {
+		raw := i.buildAiderCommand(i.Command, true)
+		if i.Tool == "goose" {
+			raw = i.buildGooseCommand(i.Command, true)
+		}
+		resumeCmd, containerName, err := i.prepareCommand(raw)
+		if err != nil {
+			return err
+		}
+		if containerName != "" {
+			i.SandboxContainer = containerName
+		}
+		sessionLog.Info("restart_respawn", slog.String("tool", i.Tool), slog.String("command", resumeCmd))
+
+		if err := i.tmuxSession.RespawnPane(resumeCmd); err != nil {
+			return fmt.Errorf("failed to restart %s session: %w", i.Tool, err)
+		}
+
+		i.ensureProfileEnv()
+		i.sweepDuplicateToolSessions()
+		i.Status = StatusWaiting
+		return nil
+	}
+
 	// If custom tool with session resume support AND tmux session exists, use respawn-pane.
 	if i.CanRestartGeneric() && i.tmuxSession != nil && i.tmuxSession.Exists() {
 		toolDef := GetToolDef(i.Tool)
@@ -6793,6 +6963,10 @@ func (i *Instance) restart(env map[string]string) error {
 			command = i.buildCursorCommand(i.Command, true)
 		case i.Tool == "hermes":
 			command = i.buildHermesCommand(i.Command)
+		case i.Tool == "aider":
+			command = i.buildAiderCommand(i.Command, true)
+		case i.Tool == "goose":
+			command = i.buildGooseCommand(i.Command, true)
 		default:
 			// Check if this is a custom tool with session resume config
 			if toolDef := GetToolDef(i.Tool); toolDef != nil {
@@ -6835,6 +7009,7 @@ func (i *Instance) restart(env map[string]string) error {
 
 	mcpLog.Debug("restart_starting_new_session", slog.String("command", command))
 
+	i.tmuxSession.DeferCosmeticOptions(i.tmuxOptionBatch)
 	if err := i.tmuxSession.Start(command); err != nil {
 		mcpLog.Debug("restart_start_failed", slog.String("error", err.Error()))
 		i.Status = StatusError
@@ -7194,6 +7369,11 @@ func (i *Instance) CanRestart() bool {
 		return true
 	}
 
+	// Aider and goose reopen their last conversation on restart.
+	if i.Tool == "aider" || i.Tool == "goose" {
+		return true
+	}
+
 	// Custom tools: check if they have session resume support
 	if i.CanRestartGeneric() {
 		return true
@@ -8563,6 +8743,8 @@ var builtinAgentTools = map[string]bool{
 	"cursor":   true,
 	"hermes":   true,
 	"crush":    true,
+	"aider":    true,
+	"goose":    true,
 }
 
 // isBuiltinAgentTool reports whether tool is a first-party agent (or a custom
@@ -8611,9 +8793,13 @@ func (i *Instance) wrapExitToShell(command string) string {
 		return command
 	}
 	rewritten := strings.Replace(command, "exec ", "", 1)
-	return rewritten + `; exec "$SHELL" -i`
+	return rewritten + i.exitCodeRecorder() + exitToShellTail
 }
 
+// exitToShellTail is appended by wrapExitToShell. The fast-death watcher
+// keys its shell-return check off it.
+const exitToShellTail = `; exec "$SHELL" -i`
+
 // launchShellEnabled returns whether the session should wrap agent commands
 // with a shell invocation that loads startup files before launching the agent.
 // Checks per-session override first, then falls back to global [shell].launch_shell config.
@@ -158,6 +158,16 @@ func (i *Instance) WriteLocalMCPConfig(names []string) error {
 	return WriteLocalMCPConfigForTool(i.Tool, i.ProjectPath, names)
 }
 
+// PreviewLocalMCPConfig returns the .mcp.json rewrite WriteLocalMCPConfig would
+// perform. ok is false for tools whose local MCP file is not .mcp.json.
+func (i *Instance) PreviewLocalMCPConfig(names []string) (change MCPJsonChange, ok bool, err error) {
+	if !IsClaudeCompatible(i.Tool) && i.Tool != "gemini" {
+		return MCPJsonChange{}, false, nil
+	}
+	change, err = PreviewMCPJsonFromConfig(i.ProjectPath, names)
+	return change, err == nil, err
+}
+
 // WriteGlobalMCPConfig writes catalog MCPs to this instance's global MCP store.
 func (i *Instance) WriteGlobalMCPConfig(names []string) error {
 	if IsCodexCompatible(i.Tool) {
@@ -181,3 +191,11 @@ func (i *Instance) InvalidateProjectMCPIntegrationsCache() {
 func (i *Instance) SupportsMCPAgentRestart() bool {
 	return ToolSupportsMCPManager(i.Tool)
 }
+
+// MCPHotSwapActive reports whether this Claude session loads its local MCPs
+// through the hot-swap hub, so local attach/detach applies without a restart.
+// Call it before rewriting .mcp.json: a session only has the hub if it was
+// already there.
+func (i *Instance) MCPHotSwapActive() bool {
+	return IsClaudeCompatible(i.Tool) && MCPHubActive(i.ProjectPath)
+}
@@ -0,0 +1,84 @@
+// Session labels: free-form tags on a session, set with
+// `session set <id> labels a,b`. Like depends_on they live in the tool_data
+// extras zone, and clearing them writes an explicit empty array once so
+// MergeToolDataExtras does not carry the old labels forward.
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const toolDataLabelsKey = "labels"
+
+// ParseLabels splits a comma-separated label list, trimming blanks and
+// dropping duplicates. Labels may not contain whitespace.
+func ParseLabels(value string) ([]string, error) {
+	var labels []string
+	for _, l := range strings.Split(value, ",") {
+		l = strings.TrimSpace(l)
+		if l == "" {
+			continue
+		}
+		if strings.ContainsFunc(l, func(r rune) bool { return r == ' ' || r == '\t' || r == '\n' }) {
+			return nil, fmt.Errorf("invalid label %q: labels may not contain spaces", l)
+		}
+		if !slices.Contains(labels, l) {
+			labels = append(labels, l)
+		}
+	}
+	return labels, nil
+}
+
+// HasLabel reports whether the session carries label (case-insensitive).
+func (i *Instance) HasLabel(label string) bool {
+	for _, l := range i.Labels {
+		if strings.EqualFold(l, label) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetLabels replaces the session's labels. An empty list clears them.
+func (i *Instance) SetLabels(labels []string) {
+	i.Labels = labels
+	i.labelsCleared = len(labels) == 0
+}
+
+// WriteLabelsToToolData merges labels into the tool_data blob. See
+// WriteDependsOnToToolData for the cleared semantics.
+func WriteLabelsToToolData(td json.RawMessage, labels []string, cleared bool) json.RawMessage {
+	if len(labels) == 0 && !cleared {
+		return td
+	}
+	m := map[string]json.RawMessage{}
+	if len(td) > 0 {
+		_ = json.Unmarshal(td, &m)
+	}
+	if labels == nil {
+		labels = []string{}
+	}
+	raw, _ := json.Marshal(labels)
+	m[toolDataLabelsKey] = raw
+	out, _ := json.Marshal(m)
+	return out
+}
+
+// ReadLabelsFromToolData extracts labels from the blob. Returns nil for
+// missing/malformed/legacy rows.
+func ReadLabelsFromToolData(td json.RawMessage) []string {
+	if len(td) == 0 {
+		return nil
+	}
+	var blob struct {
+		Labels []string `json:"labels"`
+	}
+	_ = json.Unmarshal(td, &blob)
+	if len(blob.Labels) == 0 {
+		return nil
+	}
+	return blob.Labels
+}
@@ -0,0 +1,113 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// SessionLayout is a named multi-pane layout: the panes opened next to the
+// agent when a session starts (a dev server, a log tail). [layouts.<name>]
+// in config.toml defines one; `add --layout <name>` picks it.
+type SessionLayout struct {
+	// Description is shown in the `add --layout` error listing layouts.
+	Description string `toml:"description,omitempty"`
+
+	// Panes are opened in order after the agent pane. Status detection and
+	// input stay bound to the agent pane.
+	Panes []tmux.PaneSpec `toml:"panes"`
+}
+
+// Validate reports a layout with no panes or a pane tmux could not open.
+func (l SessionLayout) Validate() error {
+	if len(l.Panes) == 0 {
+		return fmt.Errorf("layout has no panes")
+	}
+	for _, p := range l.Panes {
+		if err := p.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetLayouts returns [layouts.<name>] from config.toml.
+func GetLayouts() map[string]SessionLayout {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil || config.Layouts == nil {
+		return map[string]SessionLayout{}
+	}
+	return config.Layouts
+}
+
+// LookupLayout returns the named layout, or an error listing the available
+// ones.
+func LookupLayout(name string) (SessionLayout, error) {
+	layouts := GetLayouts()
+	l, ok := layouts[name]
+	if !ok {
+		available := "none defined; add [layouts." + name + "] to config.toml"
+		if len(layouts) > 0 {
+			available = strings.Join(LayoutNames(layouts), ", ")
+		}
+		return SessionLayout{}, fmt.Errorf("unknown layout %q (available: %s)", name, available)
+	}
+	if err := l.Validate(); err != nil {
+		return SessionLayout{}, fmt.Errorf("layout %s: %w", name, err)
+	}
+	return l, nil
+}
+
+// LayoutNames returns the layout names in sorted order.
+func LayoutNames(layouts map[string]SessionLayout) []string {
+	names := make([]string, 0, len(layouts))
+	for name := range layouts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+const (
+	toolDataLayoutKey = "layout"
+	toolDataPanesKey  = "panes"
+)
+
+// WriteLayoutToToolData stores the session's layout name and panes in the
+// tool_data extras zone. The panes are stored resolved, so editing
+// [layouts] later does not reshape an existing session.
+func WriteLayoutToToolData(td json.RawMessage, layout string, panes []tmux.PaneSpec) json.RawMessage {
+	if layout == "" && len(panes) == 0 {
+		return td
+	}
+	m := map[string]json.RawMessage{}
+	if len(td) > 0 {
+		_ = json.Unmarshal(td, &m)
+	}
+	if layout != "" {
+		raw, _ := json.Marshal(layout)
+		m[toolDataLayoutKey] = raw
+	}
+	if len(panes) > 0 {
+		raw, _ := json.Marshal(panes)
+		m[toolDataPanesKey] = raw
+	}
+	out, _ := json.Marshal(m)
+	return out
+}
+
+// ReadLayoutFromToolData extracts the layout name and panes from the blob.
+func ReadLayoutFromToolData(td json.RawMessage) (string, []tmux.PaneSpec) {
+	if len(td) == 0 {
+		return "", nil
+	}
+	var blob struct {
+		Layout string          `json:"layout"`
+		Panes  []tmux.PaneSpec `json:"panes"`
+	}
+	_ = json.Unmarshal(td, &blob)
+	return blob.Layout, blob.Panes
+}
@@ -0,0 +1,55 @@
+package session
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+func TestLookupLayout_FromConfig(t *testing.T) {
+	writePresetTestConfig(t, `
+[layouts.fullstack]
+description = "agent + server + logs"
+[[layouts.fullstack.panes]]
+command = "npm run dev"
+size = "40%"
+[[layouts.fullstack.panes]]
+command = "tail -f log/dev.log"
+split = "below"
+
+[layouts.broken]
+[[layouts.broken.panes]]
+command = "x"
+split = "left"
+`)
+
+	l, err := LookupLayout("fullstack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l.Panes) != 2 || l.Panes[0].Size != "40%" || l.Panes[1].Split != tmux.PaneSplitBelow {
+		t.Fatalf("fullstack panes = %+v", l.Panes)
+	}
+	if _, err := LookupLayout("broken"); err == nil || !strings.Contains(err.Error(), "invalid split") {
+		t.Fatalf("broken layout error = %v", err)
+	}
+	if _, err := LookupLayout("nope"); err == nil || !strings.Contains(err.Error(), "broken, fullstack") {
+		t.Fatalf("unknown layout error = %v", err)
+	}
+}
+
+func TestLayoutToolDataRoundTrip(t *testing.T) {
+	panes := []tmux.PaneSpec{{Command: "npm run dev"}, {Command: "htop", Split: "window", Name: "top"}}
+	td := WriteLayoutToToolData([]byte(`{"preset":"light"}`), "fullstack", panes)
+	layout, got := ReadLayoutFromToolData(td)
+	if layout != "fullstack" || len(got) != 2 || got[1] != panes[1] {
+		t.Fatalf("ReadLayoutFromToolData = %q %+v", layout, got)
+	}
+	if ReadPresetFromToolData(td) != "light" {
+		t.Fatal("other tool_data keys must survive")
+	}
+	if out := WriteLayoutToToolData(nil, "", nil); out != nil {
+		t.Fatalf("single-pane session must not write layout keys, got %s", out)
+	}
+}
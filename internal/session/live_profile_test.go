@@ -0,0 +1,60 @@
+package session
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// A running agent keeps the AGENTDECK_PROFILE its launch command exported;
+// after `session move --live` only the tmux session environment is current,
+// so in-session CLI calls and hooks must resolve the profile from there.
+func TestGetEffectiveProfile_FollowsLiveTmuxSessionEnv(t *testing.T) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not installed")
+	}
+	socket := filepath.Join(t.TempDir(), "tmux.sock")
+	tmuxS := func(args ...string) string {
+		t.Helper()
+		out, err := exec.Command("tmux", append([]string{"-S", socket}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("tmux %v: %v: %s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	tmuxS("new-session", "-d", "-s", "moved", "-e", "AGENTDECK_PROFILE=old")
+	t.Cleanup(func() { _ = exec.Command("tmux", "-S", socket, "kill-server").Run() })
+	pane := tmuxS("list-panes", "-t", "moved", "-F", "#{pane_id}")
+	tmuxS("set-environment", "-t", "moved", "AGENTDECK_PROFILE", "work")
+
+	reset := func() {
+		liveProfileOnce = sync.Once{}
+		liveProfileValue = ""
+	}
+	reset()
+	t.Cleanup(reset)
+
+	t.Setenv("AGENTDECK_PROFILE", "old")
+	t.Setenv("TMUX", socket+",1,0")
+	t.Setenv("TMUX_PANE", pane)
+	t.Setenv("AGENTDECK_INSTANCE_ID", "")
+	if got := GetEffectiveProfile(""); got != "old" {
+		t.Errorf("outside an agent-deck session: got %q, want the exported %q", got, "old")
+	}
+
+	t.Setenv("AGENTDECK_INSTANCE_ID", "inst-1")
+	if got := GetEffectiveProfile(""); got != "work" {
+		t.Errorf("inside a moved session: got %q, want %q", got, "work")
+	}
+	if got := GetEffectiveProfile("explicit"); got != "explicit" {
+		t.Errorf("explicit profile: got %q", got)
+	}
+
+	reset()
+	tmuxS("set-environment", "-t", "moved", "-r", "AGENTDECK_PROFILE")
+	if got := GetEffectiveProfile(""); got != "old" {
+		t.Errorf("variable removed from the session: got %q, want the exported %q", got, "old")
+	}
+}
@@ -0,0 +1,277 @@
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Per-session mailbox: free-form messages one session sends another with
+// `agent-deck session send-to <from> <to> "..."` (or the send_to_session MCP
+// tool), so a child can tell its parent it is done — or a parent can hand a
+// child new instructions — without a human copying text between panes.
+//
+// Unlike the transition inbox (inbox.go), which carries machine-generated
+// status events to conductors, the mailbox holds text written by agents. It is
+// a JSONL file at <agent-deck-dir>/mailboxes/<session-id>.jsonl. Messages stay
+// after they are read (ReadAt set) so `session mailbox --all` can show the
+// conversation; only the newest mailboxKeepRead read messages are kept.
+//
+// Every rewrite holds mailboxMu plus an advisory flock on a sibling .lock
+// file, so a sender in one process cannot lose a message to a reader marking
+// the mailbox read in another.
+
+// mailboxKeepRead caps how many already-read messages a mailbox keeps.
+const mailboxKeepRead = 100
+
+// maxMailBytes caps one message's text.
+const maxMailBytes = 64 * 1024
+
+var mailboxMu sync.Mutex
+
+// MailMessage is one message in a session's mailbox.
+type MailMessage struct {
+	ID        string     `json:"id"`
+	From      string     `json:"from"`
+	FromTitle string     `json:"from_title"`
+	To        string     `json:"to"`
+	ToTitle   string     `json:"to_title"`
+	Text      string     `json:"text"`
+	SentAt    time.Time  `json:"sent_at"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+}
+
+// Unread reports whether the recipient has not read m yet.
+func (m MailMessage) Unread() bool { return m.ReadAt == nil }
+
+// MailboxDir returns the directory that holds per-session mailbox files.
+func MailboxDir() string {
+	dir, err := dataPath("mailboxes", "mailboxes")
+	if err != nil {
+		return tempAgentDeckPath("mailboxes")
+	}
+	return dir
+}
+
+// MailboxPathFor returns the mailbox path of a session.
+func MailboxPathFor(sessionID string) string {
+	return filepath.Join(MailboxDir(), sanitizeInboxName(sessionID)+".jsonl")
+}
+
+// lockMailbox takes the in-process and cross-process locks for path. The
+// returned func releases both.
+func lockMailbox(path string) (func(), error) {
+	mailboxMu.Lock()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		mailboxMu.Unlock()
+		return nil, err
+	}
+	f, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		mailboxMu.Unlock()
+		return nil, fmt.Errorf("open mailbox lock: %w", err)
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		_ = f.Close()
+		mailboxMu.Unlock()
+		return nil, fmt.Errorf("lock mailbox: %w", err)
+	}
+	return func() {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		_ = f.Close()
+		mailboxMu.Unlock()
+	}, nil
+}
+
+// loadMailboxLocked reads every message in path. A missing file is an empty
+// mailbox; unparseable lines are skipped.
+func loadMailboxLocked(path string) ([]MailMessage, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	var msgs []MailMessage
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxInboxLineBytes)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var m MailMessage
+		if json.Unmarshal([]byte(line), &m) == nil {
+			msgs = append(msgs, m)
+		}
+	}
+	return msgs, scanner.Err()
+}
+
+// saveMailboxLocked rewrites path with msgs, dropping the oldest read
+// messages beyond mailboxKeepRead.
+func saveMailboxLocked(path string, msgs []MailMessage) error {
+	read := 0
+	for _, m := range msgs {
+		if !m.Unread() {
+			read++
+		}
+	}
+	var b strings.Builder
+	for _, m := range msgs {
+		if !m.Unread() && read > mailboxKeepRead {
+			read--
+			continue
+		}
+		line, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	return writeFileDurable(path, []byte(b.String()), 0o644)
+}
+
+// PostMail appends a message from one session to another's mailbox.
+func PostMail(from, to *Instance, text string, now time.Time) (*MailMessage, error) {
+	text = strings.TrimSpace(text)
+	switch {
+	case text == "":
+		return nil, errors.New("message is required")
+	case len(text) > maxMailBytes:
+		return nil, fmt.Errorf("message is %d bytes; the limit is %d", len(text), maxMailBytes)
+	case from.ID == to.ID:
+		return nil, errors.New("a session cannot send mail to itself")
+	}
+	m := MailMessage{
+		ID:        "msg-" + randomString(8),
+		From:      from.ID,
+		FromTitle: from.Title,
+		To:        to.ID,
+		ToTitle:   to.Title,
+		Text:      text,
+		SentAt:    now,
+	}
+	line, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	path := MailboxPathFor(to.ID)
+	unlock, err := lockMailbox(path)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return nil, err
+	}
+	if err := fsyncFile(f); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// ReadMailbox returns a session's unread messages, oldest first, or every
+// kept message with all. With markRead the returned unread messages are
+// marked read at now.
+func ReadMailbox(sessionID string, all, markRead bool, now time.Time) ([]MailMessage, error) {
+	path := MailboxPathFor(sessionID)
+	unlock, err := lockMailbox(path)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	msgs, err := loadMailboxLocked(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []MailMessage
+	changed := false
+	for i := range msgs {
+		if !all && !msgs[i].Unread() {
+			continue
+		}
+		out = append(out, msgs[i])
+		if markRead && msgs[i].Unread() {
+			at := now
+			msgs[i].ReadAt = &at
+			changed = true
+		}
+	}
+	if changed {
+		if err := saveMailboxLocked(path, msgs); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// MarkMailRead marks the given messages in a session's mailbox read.
+func MarkMailRead(sessionID string, ids []string, now time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	want := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		want[id] = true
+	}
+	path := MailboxPathFor(sessionID)
+	unlock, err := lockMailbox(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	msgs, err := loadMailboxLocked(path)
+	if err != nil {
+		return err
+	}
+	for i := range msgs {
+		if want[msgs[i].ID] && msgs[i].Unread() {
+			at := now
+			msgs[i].ReadAt = &at
+		}
+	}
+	return saveMailboxLocked(path, msgs)
+}
+
+// UnreadMailCount returns how many unread messages a session has. Errors
+// count as none.
+func UnreadMailCount(sessionID string) int {
+	msgs, err := ReadMailbox(sessionID, false, false, time.Time{})
+	if err != nil {
+		return 0
+	}
+	return len(msgs)
+}
+
+// RemoveMailbox deletes a session's mailbox. Missing files are not an error.
+func RemoveMailbox(sessionID string) {
+	path := MailboxPathFor(sessionID)
+	_ = os.Remove(path)
+	_ = os.Remove(path + ".lock")
+}
+
+// FormatMailNotice renders a message as it is typed into the recipient's
+// pane: who it is from, the text, and how to reply.
+func FormatMailNotice(profile string, m MailMessage) string {
+	return fmt.Sprintf("[MAIL from %s] %s\n\n(Reply with: agent-deck -p %s session send-to %s %s \"<message>\")",
+		m.FromTitle, m.Text, profileOrDefault(profile), m.To, m.From)
+}
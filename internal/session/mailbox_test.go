@@ -0,0 +1,79 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMailbox_PostReadAndPrune(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("AGENT_DECK_HOME", "")
+	t.Setenv("AGENT_DECK_PROFILE", "")
+	t.Setenv("XDG_DATA_HOME", home+"/xdg-data")
+
+	parent := &Instance{ID: "parent-1", Title: "api"}
+	child := &Instance{ID: "child-1", Title: "api-tests"}
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	if _, err := PostMail(child, child, "hi", now); err == nil {
+		t.Error("mail to self: want error")
+	}
+	if _, err := PostMail(child, parent, "  ", now); err == nil {
+		t.Error("empty message: want error")
+	}
+	first, err := PostMail(child, parent, "tests pass", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := PostMail(child, parent, "PR is #412", now.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := UnreadMailCount(parent.ID); n != 2 {
+		t.Fatalf("unread = %d, want 2", n)
+	}
+
+	if err := MarkMailRead(parent.ID, []string{first.ID}, now); err != nil {
+		t.Fatal(err)
+	}
+	msgs, err := ReadMailbox(parent.ID, false, true, now)
+	if err != nil || len(msgs) != 1 || msgs[0].ID != second.ID || msgs[0].FromTitle != "api-tests" {
+		t.Fatalf("ReadMailbox = %+v, %v; want only %s", msgs, err, second.ID)
+	}
+	if n := UnreadMailCount(parent.ID); n != 0 {
+		t.Errorf("unread after read = %d", n)
+	}
+	all, _ := ReadMailbox(parent.ID, true, false, now)
+	if len(all) != 2 || all[0].Unread() || all[0].Text != "tests pass" {
+		t.Errorf("ReadMailbox(all) = %+v", all)
+	}
+
+	notice := FormatMailNotice("work", *second)
+	if !strings.HasPrefix(notice, "[MAIL from api-tests] PR is #412") ||
+		!strings.Contains(notice, "agent-deck -p work session send-to parent-1 child-1") {
+		t.Errorf("notice = %q", notice)
+	}
+
+	// Only the newest mailboxKeepRead read messages survive a rewrite.
+	for i := 0; i < mailboxKeepRead+5; i++ {
+		if _, err := PostMail(child, parent, fmt.Sprintf("m%d", i), now); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := ReadMailbox(parent.ID, false, true, now); err != nil {
+		t.Fatal(err)
+	}
+	all, _ = ReadMailbox(parent.ID, true, false, now)
+	if len(all) != mailboxKeepRead || all[len(all)-1].Text != fmt.Sprintf("m%d", mailboxKeepRead+4) {
+		t.Errorf("kept %d messages, last %q", len(all), all[len(all)-1].Text)
+	}
+
+	RemoveMailbox(parent.ID)
+	if _, err := os.Stat(MailboxPathFor(parent.ID)); !os.IsNotExist(err) {
+		t.Errorf("mailbox not removed: %v", err)
+	}
+}
@@ -22,6 +22,8 @@ type MaintenanceResult struct {
 	PrunedBackups    int
 	ArchivedSessions int
 	OrphanContainers int
+	EmptyExperiments int
+	BackupsWritten   int
 	Duration         time.Duration
 }
 
@@ -40,28 +42,27 @@ func RunMaintenance(ctx context.Context) MaintenanceResult {
 	prunedBackups := cleanupDeckBackups(filepath.Join(profileRoot, "profiles"))
 	archivedSessions := archiveBloatedSessions(profileRoot)
 	orphanContainers := cleanupOrphanContainers(ctx)
+	emptyExperiments := cleanupEmptyExperiments()
 
 	return MaintenanceResult{
 		PrunedLogs:       prunedLogs,
 		PrunedBackups:    prunedBackups,
 		ArchivedSessions: archivedSessions,
 		OrphanContainers: orphanContainers,
+		EmptyExperiments: emptyExperiments,
 		Duration:         time.Since(start),
 	}
 }
 
 // StartMaintenanceWorker launches a background goroutine that runs maintenance
 // on a 15-minute ticker with an immediate first run. It checks
-// GetMaintenanceSettings().Enabled before each run.
+// GetMaintenanceSettings().Enabled before each run. Scheduled backups
+// ([backup] interval) run on the same ticker whether or not the rest of
+// maintenance is enabled.
 func StartMaintenanceWorker(ctx context.Context, onComplete func(MaintenanceResult)) {
 	go func() {
 		// Immediate first run.
-		if GetMaintenanceSettings().Enabled {
-			result := RunMaintenance(ctx)
-			if onComplete != nil {
-				onComplete(result)
-			}
-		}
+		runMaintenanceTick(ctx, onComplete)
 
 		ticker := time.NewTicker(15 * time.Minute)
 		defer ticker.Stop()
@@ -71,17 +72,24 @@ func StartMaintenanceWorker(ctx context.Context, onComplete func(MaintenanceResu
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				if GetMaintenanceSettings().Enabled {
-					result := RunMaintenance(ctx)
-					if onComplete != nil {
-						onComplete(result)
-					}
-				}
+				runMaintenanceTick(ctx, onComplete)
 			}
 		}
 	}()
 }
 
+func runMaintenanceTick(ctx context.Context, onComplete func(MaintenanceResult)) {
+	enabled := GetMaintenanceSettings().Enabled
+	var result MaintenanceResult
+	if enabled {
+		result = RunMaintenance(ctx)
+	}
+	result.BackupsWritten = RunScheduledBackups(ctx)
+	if (enabled || result.BackupsWritten > 0) && onComplete != nil {
+		onComplete(result)
+	}
+}
+
 // pruneGeminiLogs deletes .txt files found directly inside ~/.gemini/tmp/*/
 // directories, but NOT inside chats/ subdirectories.
 func pruneGeminiLogs(baseDir string) int {
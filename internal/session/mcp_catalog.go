@@ -13,6 +13,7 @@ import (
 
 	"github.com/asheshgoplani/agent-deck/internal/atomicfile"
 	"github.com/asheshgoplani/agent-deck/internal/logging"
+	"github.com/asheshgoplani/agent-deck/internal/mcphub"
 	"github.com/asheshgoplani/agent-deck/internal/mcppool"
 )
 
@@ -164,22 +165,59 @@ func writeJSONFileAtomic(path string, data []byte, perm os.FileMode) error {
 // config.toml. When pluginPinClaudeProfile is non-empty (Claude project .mcp.json),
 // refreshes stale plugin version pins before merging (#960).
 func WriteMergedMcpJSONFile(mcpFile string, enabledNames []string, pluginPinClaudeProfile string) error {
-	availableMCPs := GetAvailableMCPs()
-	pool := GetGlobalPool()
+	return writeMergedMcpJSONFile(mcpFile, enabledNames, pluginPinClaudeProfile, false)
+}
 
+// writeMergedMcpJSONFile is WriteMergedMcpJSONFile; with hub set, stdio MCPs
+// are written to the hot-swap hub manifest instead (see mcp_hotswap.go).
+func writeMergedMcpJSONFile(mcpFile string, enabledNames []string, pluginPinClaudeProfile string, hub bool) error {
 	if pluginPinClaudeProfile != "" {
 		if _, err := RefreshStalePluginPins(mcpFile, []string{pluginPinClaudeProfile}); err != nil {
 			mcpCatLog.Warn("plugin_pin_refresh_failed", "path", mcpFile, "error", err)
 		}
 	}
 
+	data, manifest, err := buildMergedMcpJSON(mcpFile, enabledNames, true, hub)
+	if err != nil {
+		return err
+	}
+	// The manifest goes first: a running hub picks it up on its own, and
+	// the .mcp.json written next must never point at a hub without one.
+	if err := syncMCPHubManifest(filepath.Dir(mcpFile), manifest); err != nil {
+		return err
+	}
+
+	if err := backupMCPJSONFile(mcpFile, data); err != nil {
+		mcpCatLog.Warn("mcp_json_backup_failed", slog.String("path", mcpFile), slog.Any("error", err))
+	}
+
+	if err := writeJSONFileAtomic(mcpFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to save mcp json: %w", err)
+	}
+
+	return nil
+}
+
+// buildMergedMcpJSON renders the merged {"mcpServers":{...}} document for
+// mcpFile without writing it. Entries not defined in config.toml are carried
+// over verbatim. startServers controls whether auto-start HTTP MCPs are
+// launched as a side effect; previews pass false. With hub set, stdio and
+// pooled MCPs are returned in the hub manifest and .mcp.json gets the hub
+// entry in their place; manifest is nil otherwise.
+func buildMergedMcpJSON(mcpFile string, enabledNames []string, startServers, hub bool) (data []byte, manifest *mcphub.Manifest, err error) {
+	availableMCPs := GetAvailableMCPs()
+	pool := GetGlobalPool()
+	if hub {
+		manifest = &mcphub.Manifest{Servers: map[string]mcphub.Server{}}
+	}
+
 	existingServers := readExistingLocalMCPServers(mcpFile)
 	agentDeckServers := make(map[string]MCPServerConfig)
 
 	for _, name := range enabledNames {
 		if def, ok := availableMCPs[name]; ok {
 			if def.URL != "" {
-				if def.HasAutoStartServer() {
+				if startServers && def.HasAutoStartServer() {
 					if err := StartHTTPServer(name, &def); err != nil {
 						mcpCatLog.Warn("http_server_start_failed", slog.String("mcp", name), slog.String("scope", "local"), slog.Any("error", err))
 					}
@@ -199,6 +237,10 @@ func WriteMergedMcpJSONFile(mcpFile string, enabledNames []string, pluginPinClau
 			}
 
 			if socketCfg, used := tryPoolSocket(pool, name, "local"); used {
+				if manifest != nil {
+					manifest.Servers[name] = mcphub.Server{Command: socketCfg.Command, Args: socketCfg.Args}
+					continue
+				}
 				agentDeckServers[name] = socketCfg
 				continue
 			}
@@ -211,6 +253,11 @@ func WriteMergedMcpJSONFile(mcpFile string, enabledNames []string, pluginPinClau
 			if env == nil {
 				env = map[string]string{}
 			}
+			if manifest != nil {
+				manifest.Servers[name] = mcphub.Server{Command: def.Command, Args: args, Env: def.Env}
+				mcpCatLog.Info("transport_hub", slog.String("mcp", name), slog.String("scope", "local"))
+				continue
+			}
 			agentDeckServers[name] = MCPServerConfig{
 				Type:    "stdio",
 				Command: def.Command,
@@ -222,8 +269,11 @@ func WriteMergedMcpJSONFile(mcpFile string, enabledNames []string, pluginPinClau
 	}
 
 	mergedServers := make(map[string]json.RawMessage)
+	if manifest != nil {
+		agentDeckServers[MCPHubServerName] = mcpHubServerConfig(MCPHubManifestPath(filepath.Dir(mcpFile)))
+	}
 	for name, raw := range existingServers {
-		if _, managed := availableMCPs[name]; !managed {
+		if _, managed := availableMCPs[name]; !managed && name != MCPHubServerName {
 			mergedServers[name] = raw
 			mcpCatLog.Debug("preserved_existing_mcp", slog.String("mcp", name), slog.String("scope", "local"))
 		}
@@ -243,16 +293,11 @@ func WriteMergedMcpJSONFile(mcpFile string, enabledNames []string, pluginPinClau
 		MCPServers: mergedServers,
 	}
 
-	data, err := json.MarshalIndent(finalConfig, "", "  ")
+	data, err = json.MarshalIndent(finalConfig, "", "  ")
 	if err != nil {
-		return fmt.Errorf("failed to marshal mcp json: %w", err)
+		return nil, nil, fmt.Errorf("failed to marshal mcp json: %w", err)
 	}
-
-	if err := writeJSONFileAtomic(mcpFile, data, 0644); err != nil {
-		return fmt.Errorf("failed to save mcp json: %w", err)
-	}
-
-	return nil
+	return append(data, '\n'), manifest, nil
 }
 
 // WriteMCPJsonFromConfig writes enabled MCPs from config.toml to project's .mcp.json
@@ -264,7 +309,7 @@ func WriteMCPJsonFromConfig(projectPath string, enabledNames []string) error {
 	}
 
 	mcpFile := filepath.Join(projectPath, ".mcp.json")
-	return WriteMergedMcpJSONFile(mcpFile, enabledNames, GetClaudeConfigDir())
+	return writeMergedMcpJSONFile(mcpFile, enabledNames, GetClaudeConfigDir(), GetMCPHotSwap())
 }
 
 // WriteGlobalMCP adds or removes MCPs from Claude's global config
@@ -0,0 +1,77 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/asheshgoplani/agent-deck/internal/mcphub"
+)
+
+// MCPHubServerName is the .mcp.json entry that runs the hot-swap hub
+// (`agent-deck mcp-hub`) when mcp_hot_swap is enabled.
+const MCPHubServerName = "agent-deck-hub"
+
+// MCPHubManifestPath returns the hub manifest for the .mcp.json in projectDir.
+func MCPHubManifestPath(projectDir string) string {
+	return filepath.Join(projectDir, ".agent-deck", "mcp-hub.json")
+}
+
+// mcpHubServerConfig is the .mcp.json entry launching the hub on manifestPath.
+func mcpHubServerConfig(manifestPath string) MCPServerConfig {
+	return MCPServerConfig{
+		Type:    "stdio",
+		Command: "agent-deck",
+		Args:    []string{"mcp-hub", manifestPath},
+		Env:     map[string]string{},
+	}
+}
+
+// syncMCPHubManifest writes manifest for the .mcp.json in projectDir, or
+// removes a leftover one when manifest is nil (hot swap turned off).
+func syncMCPHubManifest(projectDir string, manifest *mcphub.Manifest) error {
+	path := MCPHubManifestPath(projectDir)
+	if manifest == nil {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove hub manifest: %w", err)
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create hub manifest dir: %w", err)
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal hub manifest: %w", err)
+	}
+	// 0600: entries carry the same env values (API keys) as .mcp.json would.
+	return writeJSONFileAtomic(path, data, 0o600)
+}
+
+// mcpHubServerNames returns the MCPs served by the hub for the .mcp.json in
+// projectDir, so the hub entry can be reported as the MCPs behind it.
+func mcpHubServerNames(projectDir string) []string {
+	m, err := mcphub.ReadManifest(MCPHubManifestPath(projectDir))
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(m.Servers))
+	for name := range m.Servers {
+		names = append(names, name)
+	}
+	return names
+}
+
+// MCPHubActive reports whether the .mcp.json in projectPath already routes
+// MCPs through the hub. A Claude session started on it picks up local MCP
+// changes without a restart.
+func MCPHubActive(projectPath string) bool {
+	if !GetMCPHotSwap() {
+		return false
+	}
+	servers := readExistingLocalMCPServers(filepath.Join(projectPath, ".mcp.json"))
+	_, ok := servers[MCPHubServerName]
+	return ok
+}
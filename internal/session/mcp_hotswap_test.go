@@ -0,0 +1,95 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/asheshgoplani/agent-deck/internal/mcphub"
+)
+
+const hotSwapTestConfig = `
+[mcps.hubtest-stdio]
+command = "echo"
+args = ["hi"]
+
+[mcps.hubtest-web]
+url = "https://example.com/mcp"
+`
+
+func readMCPJSONServers(t *testing.T, project string) map[string]json.RawMessage {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(project, ".mcp.json"))
+	if err != nil {
+		t.Fatalf("read .mcp.json: %v", err)
+	}
+	var cfg struct {
+		MCPServers map[string]json.RawMessage `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("parse .mcp.json: %v", err)
+	}
+	return cfg.MCPServers
+}
+
+func TestWriteMCPJsonFromConfig_HotSwapHub(t *testing.T) {
+	home := withIsolatedHomeAndConfig(t, "mcp_hot_swap = true\n"+hotSwapTestConfig)
+	project := t.TempDir()
+	if err := os.WriteFile(filepath.Join(project, ".mcp.json"), []byte(`{"mcpServers":{"manual":{"command":"manual"}}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if MCPHubActive(project) {
+		t.Fatal("hub reported active before it was written")
+	}
+
+	if err := WriteMCPJsonFromConfig(project, []string{"hubtest-stdio", "hubtest-web"}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	servers := readMCPJSONServers(t, project)
+	for _, name := range []string{MCPHubServerName, "hubtest-web", "manual"} {
+		if _, ok := servers[name]; !ok {
+			t.Errorf(".mcp.json missing %q: %v", name, servers)
+		}
+	}
+	if _, ok := servers["hubtest-stdio"]; ok {
+		t.Error("stdio MCP must go through the hub, not .mcp.json")
+	}
+
+	manifest, err := mcphub.ReadManifest(MCPHubManifestPath(project))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := manifest.Servers["hubtest-stdio"]; got.Command != "echo" || !slices.Equal(got.Args, []string{"hi"}) || len(manifest.Servers) != 1 {
+		t.Fatalf("manifest = %+v", manifest)
+	}
+	if !MCPHubActive(project) {
+		t.Fatal("hub should be active once .mcp.json carries it")
+	}
+
+	local := getMCPInfoUncached(project).Local()
+	slices.Sort(local)
+	if want := []string{"hubtest-stdio", "hubtest-web", "manual"}; !slices.Equal(local, want) {
+		t.Fatalf("local MCPs = %v, want %v", local, want)
+	}
+
+	// Turning hot swap off restores direct entries and drops the manifest.
+	if err := os.WriteFile(filepath.Join(home, ".agent-deck", "config.toml"), []byte(hotSwapTestConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ClearUserConfigCache()
+	if err := WriteMCPJsonFromConfig(project, local); err != nil {
+		t.Fatalf("write without hub: %v", err)
+	}
+	servers = readMCPJSONServers(t, project)
+	if _, ok := servers[MCPHubServerName]; ok {
+		t.Error("hub entry left behind after disabling hot swap")
+	}
+	if _, ok := servers["hubtest-stdio"]; !ok {
+		t.Errorf("stdio MCP not restored: %v", servers)
+	}
+	if _, err := os.Stat(MCPHubManifestPath(project)); !os.IsNotExist(err) {
+		t.Errorf("manifest not removed: %v", err)
+	}
+}
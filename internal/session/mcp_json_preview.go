@@ -0,0 +1,117 @@
+package session
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/safeio"
+)
+
+// MCPJsonChange describes a pending rewrite of a project's .mcp.json. Before is
+// nil when the file does not exist yet.
+type MCPJsonChange struct {
+	Path   string
+	Before []byte
+	After  []byte
+}
+
+// Changed reports whether applying the change would modify the file.
+func (c MCPJsonChange) Changed() bool {
+	return !bytes.Equal(c.Before, c.After)
+}
+
+// Diff renders a unified-style line diff ("-"/"+"/" " prefixes) of the change.
+// Returns "" when nothing changes.
+func (c MCPJsonChange) Diff() string {
+	if !c.Changed() {
+		return ""
+	}
+	return lineDiff(splitDiffLines(c.Before), splitDiffLines(c.After))
+}
+
+// PreviewMCPJsonFromConfig computes what WriteMCPJsonFromConfig would write to
+// projectPath/.mcp.json without touching disk or starting any HTTP MCP
+// servers. When .mcp.json management is disabled the returned change is a
+// no-op.
+func PreviewMCPJsonFromConfig(projectPath string, enabledNames []string) (MCPJsonChange, error) {
+	mcpFile := filepath.Join(projectPath, ".mcp.json")
+	before, err := os.ReadFile(mcpFile)
+	if err != nil && !os.IsNotExist(err) {
+		return MCPJsonChange{}, err
+	}
+	change := MCPJsonChange{Path: mcpFile, Before: before, After: before}
+	if !GetManageMCPJson() {
+		return change, nil
+	}
+	after, _, err := buildMergedMcpJSON(mcpFile, enabledNames, false, GetMCPHotSwap())
+	if err != nil {
+		return MCPJsonChange{}, err
+	}
+	change.After = after
+	return change, nil
+}
+
+// backupMCPJSONFile snapshots mcpFile to "<mcpFile>.bak" before it is replaced
+// with next, so a hand-maintained .mcp.json can always be recovered. No-op when
+// the file is missing or already matches next.
+func backupMCPJSONFile(mcpFile string, next []byte) error {
+	existing, err := os.ReadFile(mcpFile)
+	if err != nil || bytes.Equal(existing, next) {
+		return nil
+	}
+	_, err = safeio.Backup(mcpFile)
+	return err
+}
+
+func splitDiffLines(data []byte) []string {
+	s := strings.TrimSuffix(string(data), "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// lineDiff is a small LCS diff; .mcp.json files are tiny so the O(n*m) table
+// is fine.
+func lineDiff(a, b []string) string {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var sb strings.Builder
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			sb.WriteString("  " + a[i] + "\n")
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			sb.WriteString("- " + a[i] + "\n")
+			i++
+		default:
+			sb.WriteString("+ " + b[j] + "\n")
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		sb.WriteString("- " + a[i] + "\n")
+	}
+	for ; j < m; j++ {
+		sb.WriteString("+ " + b[j] + "\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,90 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// seedHandEditedMCPJSON writes a compact, hand-maintained .mcp.json holding an
+// entry agent-deck does not manage.
+func seedHandEditedMCPJSON(t *testing.T, dir string) (string, string) {
+	t.Helper()
+	mcpFile := filepath.Join(dir, ".mcp.json")
+	seed := `{"mcpServers":{"external":{"command":"foo"}}}` + "\n"
+	if err := os.WriteFile(mcpFile, []byte(seed), 0644); err != nil {
+		t.Fatalf("seed write: %v", err)
+	}
+	return mcpFile, seed
+}
+
+func TestPreviewMCPJsonFromConfig_DoesNotWrite(t *testing.T) {
+	if !GetManageMCPJson() {
+		t.Skip(".mcp.json management disabled in this environment")
+	}
+	dir := t.TempDir()
+	mcpFile, seed := seedHandEditedMCPJSON(t, dir)
+
+	change, err := PreviewMCPJsonFromConfig(dir, nil)
+	if err != nil {
+		t.Fatalf("preview: %v", err)
+	}
+	if change.Path != mcpFile {
+		t.Fatalf("path = %q, want %q", change.Path, mcpFile)
+	}
+	if !change.Changed() {
+		t.Fatal("expected reformatting to count as a change")
+	}
+	if !strings.Contains(string(change.After), `"external"`) {
+		t.Fatalf("unmanaged server dropped from preview: %s", change.After)
+	}
+	diff := change.Diff()
+	if !strings.Contains(diff, "- "+strings.TrimSuffix(seed, "\n")) || !strings.Contains(diff, "+ ") {
+		t.Fatalf("unexpected diff:\n%s", diff)
+	}
+
+	got, _ := os.ReadFile(mcpFile)
+	if string(got) != seed {
+		t.Fatalf("preview modified the file: %q", got)
+	}
+	if _, err := os.Stat(mcpFile + ".bak"); !os.IsNotExist(err) {
+		t.Fatal("preview must not create a backup")
+	}
+}
+
+func TestWriteMergedMcpJSONFile_KeepsBackup(t *testing.T) {
+	dir := t.TempDir()
+	mcpFile, seed := seedHandEditedMCPJSON(t, dir)
+
+	if err := WriteMergedMcpJSONFile(mcpFile, nil, ""); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	bak, err := os.ReadFile(mcpFile + ".bak")
+	if err != nil {
+		t.Fatalf("expected backup: %v", err)
+	}
+	if string(bak) != seed {
+		t.Fatalf("backup = %q, want %q", bak, seed)
+	}
+
+	// A no-op rewrite must not replace the backup with the current file.
+	if err := WriteMergedMcpJSONFile(mcpFile, nil, ""); err != nil {
+		t.Fatalf("second write: %v", err)
+	}
+	bak, _ = os.ReadFile(mcpFile + ".bak")
+	if string(bak) != seed {
+		t.Fatalf("unchanged write overwrote backup: %q", bak)
+	}
+}
+
+func TestMCPJsonChange_Diff(t *testing.T) {
+	c := MCPJsonChange{Before: []byte("a\nb\nc\n"), After: []byte("a\nc\nd\n")}
+	want := "  a\n- b\n  c\n+ d\n"
+	if got := c.Diff(); got != want {
+		t.Fatalf("diff = %q, want %q", got, want)
+	}
+	if (MCPJsonChange{Before: []byte("x\n"), After: []byte("x\n")}).Diff() != "" {
+		t.Fatal("unchanged file should produce an empty diff")
+	}
+}
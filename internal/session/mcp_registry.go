@@ -0,0 +1,170 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultMCPRegistryURL is the curated MCP server index `agent-deck mcp browse`
+// reads when no --registry is given. It is the mcp-registry.json at the root of
+// the agent-deck repository.
+const DefaultMCPRegistryURL = "https://raw.githubusercontent.com/asheshgoplani/agent-deck/main/mcp-registry.json"
+
+// maxMCPRegistrySize bounds how much of a registry response is read.
+const maxMCPRegistrySize = 1 << 20
+
+// MCPRegistry is a curated index of MCP servers that can be installed into
+// [mcps] in config.toml.
+type MCPRegistry struct {
+	Version int                `json:"version"`
+	Servers []MCPRegistryEntry `json:"servers"`
+}
+
+// MCPRegistryEntry describes one installable MCP server. Args and URL may
+// contain {{name}} placeholders that are filled from Inputs at install time.
+type MCPRegistryEntry struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Homepage    string              `json:"homepage,omitempty"`
+	Command     string              `json:"command,omitempty"`
+	Args        []string            `json:"args,omitempty"`
+	URL         string              `json:"url,omitempty"`
+	Transport   string              `json:"transport,omitempty"`
+	Inputs      []MCPRegistryInput  `json:"inputs,omitempty"`
+	Env         []MCPRegistryEnvVar `json:"env,omitempty"`
+}
+
+// MCPRegistryInput is a value prompted for and substituted into Args/URL.
+type MCPRegistryInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Default     string `json:"default,omitempty"`
+}
+
+// MCPRegistryEnvVar is an environment variable the server reads, typically an
+// API key.
+type MCPRegistryEnvVar struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Default     string `json:"default,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Secret      bool   `json:"secret,omitempty"`
+}
+
+// FetchMCPRegistry loads a registry from an https:// URL or, for anything
+// else, a local file path. Plain http is refused so a network attacker can't
+// swap in the commands that end up in config.toml.
+func FetchMCPRegistry(ctx context.Context, client *http.Client, source string) (*MCPRegistry, error) {
+	if !strings.Contains(source, "://") {
+		data, err := os.ReadFile(ExpandPath(source))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read registry: %w", err)
+		}
+		return ParseMCPRegistry(data)
+	}
+
+	u, err := url.Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry URL: %w", err)
+	}
+	if u.Scheme != "https" {
+		return nil, fmt.Errorf("registry URL must use https, got %q", u.Scheme)
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch registry: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch registry: status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxMCPRegistrySize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry: %w", err)
+	}
+	return ParseMCPRegistry(data)
+}
+
+// ParseMCPRegistry decodes and validates a registry, returning its servers
+// sorted by name.
+func ParseMCPRegistry(data []byte) (*MCPRegistry, error) {
+	var reg MCPRegistry
+	if err := json.Unmarshal(data, &reg); err != nil {
+		return nil, fmt.Errorf("invalid registry JSON: %w", err)
+	}
+	if reg.Version != 1 {
+		return nil, fmt.Errorf("unsupported registry version %d", reg.Version)
+	}
+	seen := make(map[string]bool, len(reg.Servers))
+	for _, e := range reg.Servers {
+		if e.Name == "" {
+			return nil, fmt.Errorf("registry entry without a name")
+		}
+		if seen[e.Name] {
+			return nil, fmt.Errorf("duplicate registry entry %q", e.Name)
+		}
+		seen[e.Name] = true
+		if (e.Command == "") == (e.URL == "") {
+			return nil, fmt.Errorf("registry entry %q must set exactly one of command or url", e.Name)
+		}
+	}
+	sort.Slice(reg.Servers, func(i, j int) bool { return reg.Servers[i].Name < reg.Servers[j].Name })
+	return &reg, nil
+}
+
+// Matches reports whether query appears in the entry's name or description,
+// ignoring case. An empty query matches everything.
+func (e MCPRegistryEntry) Matches(query string) bool {
+	q := strings.ToLower(strings.TrimSpace(query))
+	return q == "" || strings.Contains(strings.ToLower(e.Name), q) || strings.Contains(strings.ToLower(e.Description), q)
+}
+
+// ToMCPDef builds the [mcps] entry for e. inputs fills {{name}} placeholders
+// (unset ones fall back to the input's default); env holds the values for the
+// entry's environment variables, and empty values are left out.
+func (e MCPRegistryEntry) ToMCPDef(inputs, env map[string]string) MCPDef {
+	fill := func(s string) string {
+		for _, in := range e.Inputs {
+			v, ok := inputs[in.Name]
+			if !ok {
+				v = in.Default
+			}
+			s = strings.ReplaceAll(s, "{{"+in.Name+"}}", v)
+		}
+		return s
+	}
+
+	def := MCPDef{
+		Command:     e.Command,
+		Description: e.Description,
+		URL:         fill(e.URL),
+		Transport:   e.Transport,
+	}
+	for _, a := range e.Args {
+		def.Args = append(def.Args, fill(a))
+	}
+	for _, v := range e.Env {
+		if val := env[v.Name]; val != "" {
+			if def.Env == nil {
+				def.Env = make(map[string]string)
+			}
+			def.Env[v.Name] = val
+		}
+	}
+	return def
+}
@@ -0,0 +1,117 @@
+package session
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestParseMCPRegistry_ShippedIndex(t *testing.T) {
+	_, file, _, _ := runtime.Caller(0)
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(file), "..", "..", "mcp-registry.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg, err := ParseMCPRegistry(data)
+	if err != nil {
+		t.Fatalf("shipped mcp-registry.json is invalid: %v", err)
+	}
+	if len(reg.Servers) == 0 {
+		t.Fatal("shipped registry is empty")
+	}
+}
+
+func TestParseMCPRegistry_Validation(t *testing.T) {
+	tests := []struct {
+		name, json, wantErr string
+	}{
+		{"bad version", `{"version":2,"servers":[]}`, "unsupported registry version"},
+		{"no name", `{"version":1,"servers":[{"command":"x"}]}`, "without a name"},
+		{"duplicate", `{"version":1,"servers":[{"name":"a","command":"x"},{"name":"a","command":"y"}]}`, "duplicate"},
+		{"neither", `{"version":1,"servers":[{"name":"a"}]}`, "exactly one of command or url"},
+		{"both", `{"version":1,"servers":[{"name":"a","command":"x","url":"https://h"}]}`, "exactly one of command or url"},
+	}
+	for _, tt := range tests {
+		_, err := ParseMCPRegistry([]byte(tt.json))
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: got %v, want error containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+
+	reg, err := ParseMCPRegistry([]byte(`{"version":1,"servers":[{"name":"b","command":"x"},{"name":"a","url":"https://h"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reg.Servers[0].Name != "a" || reg.Servers[1].Name != "b" {
+		t.Fatalf("servers not sorted by name: %+v", reg.Servers)
+	}
+}
+
+func TestFetchMCPRegistry(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/index.json" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"version":1,"servers":[{"name":"memory","command":"npx"}]}`))
+	}))
+	defer srv.Close()
+
+	reg, err := FetchMCPRegistry(context.Background(), srv.Client(), srv.URL+"/index.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reg.Servers) != 1 || reg.Servers[0].Name != "memory" {
+		t.Fatalf("unexpected registry: %+v", reg)
+	}
+
+	if _, err := FetchMCPRegistry(context.Background(), srv.Client(), srv.URL+"/missing"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("expected status error, got %v", err)
+	}
+	if _, err := FetchMCPRegistry(context.Background(), nil, "http://example.com/index.json"); err == nil || !strings.Contains(err.Error(), "https") {
+		t.Fatalf("plain http must be refused, got %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "index.json")
+	if err := os.WriteFile(path, []byte(`{"version":1,"servers":[{"name":"local","command":"x"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	reg, err = FetchMCPRegistry(context.Background(), nil, path)
+	if err != nil || reg.Servers[0].Name != "local" {
+		t.Fatalf("local file: %+v, %v", reg, err)
+	}
+}
+
+func TestMCPRegistryEntry_ToMCPDef(t *testing.T) {
+	e := MCPRegistryEntry{
+		Name:        "fs",
+		Description: "files",
+		Command:     "npx",
+		Args:        []string{"-y", "server-fs", "{{dir}}", "{{mode}}"},
+		Inputs: []MCPRegistryInput{
+			{Name: "dir"},
+			{Name: "mode", Default: "ro"},
+		},
+		Env: []MCPRegistryEnvVar{{Name: "TOKEN"}, {Name: "UNSET"}},
+	}
+	def := e.ToMCPDef(map[string]string{"dir": "/srv"}, map[string]string{"TOKEN": "t"})
+	want := MCPDef{
+		Command:     "npx",
+		Args:        []string{"-y", "server-fs", "/srv", "ro"},
+		Env:         map[string]string{"TOKEN": "t"},
+		Description: "files",
+	}
+	if !reflect.DeepEqual(def, want) {
+		t.Fatalf("got %+v, want %+v", def, want)
+	}
+
+	if !e.Matches("FIL") || !e.Matches("") || e.Matches("github") {
+		t.Fatal("Matches should search name and description case-insensitively")
+	}
+}
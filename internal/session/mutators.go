@@ -35,6 +35,9 @@ const (
 	FieldAccount            = "account"      // #924 per-session named account slot
 	FieldIdleTimeout        = "idle-timeout" // #1143 auto-stop dormant sessions
 	FieldPin                = "pin"          // pin-sessions: anchor top/bottom of group
+	FieldWorkingHours       = "working-hours"
+	FieldLabels             = "labels"
+	FieldCheckpoint         = "checkpoint"
 	// FieldModel persists the operator's selected per-session model (#1436,
 	// follow-up to #1431). Tool-agnostic: routes to each tool's existing model
 	// store (ClaudeOptions.Model, GeminiModel, OpenCodeOptions.Model,
@@ -43,6 +46,9 @@ const (
 	// baked/default model. Restart-required (the running process keeps the
 	// model it launched with).
 	FieldModel = "model"
+	// FieldPreset switches the session to a start preset (ApplyStartPreset):
+	// model, MCP set and tool flags together. Restart-required like model.
+	FieldPreset = "preset"
 )
 
 var ValidMutableFields = []string{
@@ -67,7 +73,11 @@ var ValidMutableFields = []string{
 	FieldAccount,
 	FieldIdleTimeout,
 	FieldPin,
+	FieldWorkingHours,
+	FieldLabels,
+	FieldCheckpoint,
 	FieldModel,
+	FieldPreset,
 }
 
 type FieldRestartPolicy int
@@ -80,7 +90,7 @@ const (
 func RestartPolicyFor(field string) FieldRestartPolicy {
 	switch field {
 	case FieldCommand, FieldWrapper, FieldTool, FieldChannels, FieldPlugins, FieldExtraArgs, FieldPath,
-		FieldSkipPermissions, FieldAutoMode, FieldAccount, FieldModel:
+		FieldSkipPermissions, FieldAutoMode, FieldAccount, FieldModel, FieldPreset:
 		return FieldRestartRequired
 	default:
 		return FieldLive
@@ -367,6 +377,33 @@ func SetField(inst *Instance, field, value string, extraArgsTokens []string) (ol
 		}
 		inst.IdleTimeoutSecs = secs
 
+	case FieldWorkingHours:
+		// Overrides the group's working_hours window ("always" exempts the
+		// session, empty inherits the group). Live: the next watcher tick
+		// reads the new value.
+		oldValue = inst.WorkingHours
+		if err := inst.SetWorkingHours(value); err != nil {
+			return oldValue, nil, &MutationError{Field: field, Msg: err.Error()}
+		}
+
+	case FieldLabels:
+		// Comma-separated; empty clears. Live: labels only steer agent-deck
+		// itself (e.g. the idle-stop exempt label).
+		oldValue = strings.Join(inst.Labels, ",")
+		labels, perr := ParseLabels(value)
+		if perr != nil {
+			return oldValue, nil, &MutationError{Field: field, Msg: perr.Error()}
+		}
+		inst.SetLabels(labels)
+
+	case FieldCheckpoint:
+		// "waiting", an interval, both, or "off". Live: the next watcher
+		// tick reads the new mode.
+		oldValue = inst.Checkpoint
+		if err := inst.SetCheckpoint(value); err != nil {
+			return oldValue, nil, &MutationError{Field: field, Msg: err.Error()}
+		}
+
 	case FieldModel:
 		// #1436: persist the operator's selected model into the tool-specific
 		// store each builder already reads on start/restart. The restart-side
@@ -390,6 +427,12 @@ func SetField(inst *Instance, field, value string, extraArgsTokens []string) (ol
 			return oldValue, nil, &MutationError{Field: field, Msg: aerr.Error()}
 		}
 
+	case FieldPreset:
+		oldValue = inst.Preset
+		if perr := inst.ApplyStartPreset(strings.TrimSpace(value)); perr != nil {
+			return oldValue, nil, &MutationError{Field: field, Msg: perr.Error()}
+		}
+
 	case FieldPin:
 		// pin-sessions: anchor the session to the top/bottom of its group,
 		// exempt from the status/recency sort. "" clears the pin. Live: the
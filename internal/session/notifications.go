@@ -189,6 +189,28 @@ func (nm *NotificationManager) FormatBar() string {
 	return "⚡ " + strings.Join(parts, " ")
 }
 
+// WaitingCount returns the number of waiting sessions seen in the last sync
+// (excluding the current session). Published as @agentdeck_waiting.
+func (nm *NotificationManager) WaitingCount() int {
+	nm.mu.RLock()
+	defer nm.mu.RUnlock()
+	return nm.statusCounts[StatusWaiting]
+}
+
+// RenderNotificationTemplate expands a [notifications].template string.
+// {bar} is replaced by the formatted bar and {waiting} by the waiting count.
+// An empty template returns bar unchanged; an empty bar always renders
+// empty so clearing the bar still restores the user's status-left.
+func RenderNotificationTemplate(template, bar string, waiting int) string {
+	if template == "" || bar == "" {
+		return bar
+	}
+	return strings.NewReplacer(
+		"{bar}", bar,
+		"{waiting}", fmt.Sprintf("%d", waiting),
+	).Replace(template)
+}
+
 // statusColor returns the tmux fg color escape for a given status, matching the TUI palette.
 func statusColor(status Status) string {
 	switch status {
@@ -200,8 +222,10 @@ func statusColor(status Status) string {
 		return "#787fa0" // dim/muted
 	case StatusError:
 		return "#f7768e" // red
-	case StatusStopped:
+	case StatusStopped, StatusExited:
 		return "#787fa0" // dim/muted (same as idle)
+	case StatusCrashed:
+		return "#f7768e" // red (same as error)
 	default:
 		return "#787fa0"
 	}
@@ -218,7 +242,7 @@ func (nm *NotificationManager) formatBarMinimal() string {
 		parts = append(parts, colored)
 	}
 	// Render remaining statuses in a consistent order.
-	for _, s := range []Status{StatusWaiting, StatusIdle, StatusStopped, StatusError} {
+	for _, s := range []Status{StatusWaiting, StatusIdle, StatusStopped, StatusExited, StatusCrashed, StatusError} {
 		if n := nm.statusCounts[s]; n > 0 {
 			colored := fmt.Sprintf("#[fg=%s]%s %d#[default]", statusColor(s), statusIcon(s), n)
 			parts = append(parts, colored)
@@ -243,6 +267,10 @@ func statusIcon(status Status) string {
 		return "✕"
 	case StatusStopped:
 		return "■"
+	case StatusExited:
+		return "✓"
+	case StatusCrashed:
+		return "✗"
 	default:
 		return "○"
 	}
@@ -850,3 +850,32 @@ func TestMinimalMode_StartingCountsAsRunning(t *testing.T) {
 	assert.Contains(t, bar, "#9ece6a") // running/active color
 	assert.NotEqual(t, "", bar)
 }
+
+// TestNotificationManager_WaitingCount verifies the count published as
+// @agentdeck_waiting excludes the current session.
+func TestNotificationManager_WaitingCount(t *testing.T) {
+	nm := NewNotificationManager(6, false, false)
+
+	now := time.Now()
+	instances := []*Instance{
+		{ID: "w1", Title: "waiting-a", Status: StatusWaiting, CreatedAt: now},
+		{ID: "w2", Title: "waiting-b", Status: StatusWaiting, CreatedAt: now.Add(-1 * time.Second)},
+		{ID: "r1", Title: "running-a", Status: StatusRunning, CreatedAt: now.Add(-2 * time.Second)},
+	}
+
+	nm.SyncFromInstances(instances, "w2")
+	assert.Equal(t, 1, nm.WaitingCount())
+}
+
+func TestRenderNotificationTemplate(t *testing.T) {
+	assert.Equal(t, "⚡ [1] api", RenderNotificationTemplate("", "⚡ [1] api", 1))
+	assert.Equal(t, "#S | ⚡ [1] api (1) ", RenderNotificationTemplate("#S | {bar} ({waiting}) ", "⚡ [1] api", 1))
+	// An empty bar must render empty so clearing restores the user's status line.
+	assert.Equal(t, "", RenderNotificationTemplate("#S | {bar}", "", 0))
+}
+
+func TestNotificationsConfig_GetScope(t *testing.T) {
+	assert.Equal(t, NotificationScopeSession, NotificationsConfig{}.GetScope())
+	assert.Equal(t, NotificationScopeSession, NotificationsConfig{Scope: "bogus"}.GetScope())
+	assert.Equal(t, NotificationScopeGlobal, NotificationsConfig{Scope: "Global"}.GetScope())
+}
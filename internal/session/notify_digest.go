@@ -0,0 +1,131 @@
+package session
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Notification digests batch attention notifications instead of sending one
+// per session:
+//
+//	[notifications.digest]
+//	minutes = 10
+//	push = true       # [notifications.push] messages
+//	conductor = true  # conductor wake-ups
+//
+// With a window set, sessions whose push falls due, and transitions committed
+// to a conductor's inbox, are collected. Once the window has passed since the
+// first of them, one message lists every session that still needs attention.
+
+// NotificationDigestSettings configures [notifications.digest].
+type NotificationDigestSettings struct {
+	// Minutes is the digest window. 0 sends every notification on its own
+	// (default).
+	Minutes int `toml:"minutes,omitzero"`
+
+	// Push batches [notifications.push] messages (default: true, nil = true).
+	Push *bool `toml:"push,omitempty"`
+
+	// Conductor batches the wake-ups a conductor gets when its children
+	// report to its inbox (default: true, nil = true). Run by
+	// `agent-deck notify-daemon`.
+	Conductor *bool `toml:"conductor,omitempty"`
+}
+
+func (d NotificationDigestSettings) window(enabled *bool) time.Duration {
+	if d.Minutes <= 0 || (enabled != nil && !*enabled) {
+		return 0
+	}
+	return time.Duration(d.Minutes) * time.Minute
+}
+
+// PushWindow returns the push digest window, or 0 when each push is sent on
+// its own.
+func (d NotificationDigestSettings) PushWindow() time.Duration {
+	return d.window(d.Push)
+}
+
+// ConductorWindow returns the conductor digest window, or 0 when each
+// committed event wakes the conductor on its own.
+func (d NotificationDigestSettings) ConductorWindow() time.Duration {
+	return d.window(d.Conductor)
+}
+
+// Validate reports a negative window.
+func (d NotificationDigestSettings) Validate() error {
+	if d.Minutes < 0 {
+		return fmt.Errorf("minutes must be 0 (off) or positive, got %d", d.Minutes)
+	}
+	return nil
+}
+
+// digestItem is one session in a pending digest. line is what the digest
+// says about it; push digests leave it empty and describe the session when
+// the digest is sent.
+type digestItem struct {
+	id   string
+	line string
+}
+
+// digestBatch is the digest collected for one key since its first item.
+type digestBatch struct {
+	since   time.Time
+	profile string
+	target  *Instance // conductor digests: the parent to wake
+	items   []digestItem
+}
+
+// notifyDigest collects digest items by key until their window passes.
+type notifyDigest struct {
+	mu      sync.Mutex
+	batches map[string]*digestBatch
+}
+
+func newNotifyDigest() *notifyDigest {
+	return &notifyDigest{batches: map[string]*digestBatch{}}
+}
+
+// add queues item under key. A later item for the same session replaces the
+// earlier one, so a session is listed once, with its latest state.
+func (d *notifyDigest) add(key, profile string, target *Instance, now time.Time, item digestItem) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	b := d.batches[key]
+	if b == nil {
+		b = &digestBatch{since: now, profile: profile}
+		d.batches[key] = b
+	}
+	if target != nil {
+		b.target = target
+	}
+	for i := range b.items {
+		if b.items[i].id == item.id {
+			b.items[i] = item
+			return
+		}
+	}
+	b.items = append(b.items, item)
+}
+
+// takeDue removes and returns the batches under keys starting with prefix
+// whose window has passed, by key.
+func (d *notifyDigest) takeDue(prefix string, window time.Duration, now time.Time) []*digestBatch {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var keys []string
+	for key, b := range d.batches {
+		if strings.HasPrefix(key, prefix) && now.Sub(b.since) >= window {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	due := make([]*digestBatch, 0, len(keys))
+	for _, key := range keys {
+		due = append(due, d.batches[key])
+		delete(d.batches, key)
+	}
+	return due
+}
@@ -0,0 +1,106 @@
+package session
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNotificationDigestSettings_Windows(t *testing.T) {
+	off := false
+	cases := []struct {
+		settings        NotificationDigestSettings
+		push, conductor time.Duration
+	}{
+		{NotificationDigestSettings{}, 0, 0},
+		{NotificationDigestSettings{Minutes: 10}, 10 * time.Minute, 10 * time.Minute},
+		{NotificationDigestSettings{Minutes: 10, Conductor: &off}, 10 * time.Minute, 0},
+		{NotificationDigestSettings{Minutes: 10, Push: &off}, 0, 10 * time.Minute},
+	}
+	for _, c := range cases {
+		if got := c.settings.PushWindow(); got != c.push {
+			t.Errorf("%+v PushWindow = %v, want %v", c.settings, got, c.push)
+		}
+		if got := c.settings.ConductorWindow(); got != c.conductor {
+			t.Errorf("%+v ConductorWindow = %v, want %v", c.settings, got, c.conductor)
+		}
+	}
+	if err := (NotificationDigestSettings{Minutes: -1}).Validate(); err == nil {
+		t.Error("negative minutes accepted")
+	}
+}
+
+func TestWaitingPushDigest_BatchesStillWaitingSessions(t *testing.T) {
+	api := &Instance{ID: "digest-api", Title: "api", Status: StatusWaiting}
+	web := &Instance{ID: "digest-web", Title: "web", Status: StatusWaiting}
+	docs := &Instance{ID: "digest-docs", Title: "docs", Status: StatusWaiting}
+	instances := []*Instance{api, web, docs}
+	profile := "push-digest-test"
+	start := time.Unix(1_700_000_000, 0)
+	after, window := 5*time.Minute, 10*time.Minute
+	t.Cleanup(func() {
+		dueWaitingPushes(profile, nil, after, start)
+		pushDigest.takeDue(profile+"\x00", 0, start)
+	})
+
+	dueWaitingPushes(profile, instances, after, start)
+	due := dueWaitingPushes(profile, instances, after, start.Add(after))
+	if len(due) != 3 {
+		t.Fatalf("due = %d, want 3", len(due))
+	}
+	if _, ok := waitingPushDigest(profile, instances, due, window, start.Add(after)); ok {
+		t.Fatal("digest sent before its window passed")
+	}
+
+	// docs is answered before the window closes: the digest lists the rest.
+	docs.Status = StatusRunning
+	msg, ok := waitingPushDigest(profile, instances, nil, window, start.Add(after+window))
+	if !ok {
+		t.Fatal("no digest after the window")
+	}
+	if msg.Title != "2 sessions need attention" {
+		t.Errorf("title = %q", msg.Title)
+	}
+	if !strings.Contains(msg.Body, "api: Waiting for input for 15 min") || !strings.Contains(msg.Body, "web:") || strings.Contains(msg.Body, "docs") {
+		t.Errorf("body = %q", msg.Body)
+	}
+	if _, ok := waitingPushDigest(profile, instances, nil, window, start.Add(time.Hour)); ok {
+		t.Error("digest sent twice")
+	}
+}
+
+func TestFlushDigests_OneWakePerConductorWindow(t *testing.T) {
+	n, parentID, event := newWakeNudgeFixture(t)
+	now := time.Unix(5000, 0)
+	var nudges, digests []string
+	n.wake = &wakeNudgeWiring{
+		nudger:       NewWakeNudger(0),
+		now:          func() time.Time { return now },
+		isIdle:       func(p *Instance) bool { return true },
+		send:         func(p *Instance, profile string) error { nudges = append(nudges, p.ID); return nil },
+		digestWindow: func() time.Duration { return 10 * time.Minute },
+		digest:       newNotifyDigest(),
+		sendDigest: func(p *Instance, profile, msg string) error {
+			digests = append(digests, p.ID+"|"+msg)
+			return nil
+		},
+	}
+
+	n.NotifyFinished(event)
+	n.NotifyFinished(event)
+	if len(nudges) != 0 {
+		t.Fatalf("digest mode still nudged per event: %v", nudges)
+	}
+	if sent := n.FlushDigests(event.Profile, nil, now.Add(time.Minute)); sent != 0 {
+		t.Fatalf("flushed %d digests before the window passed", sent)
+	}
+	if sent := n.FlushDigests(event.Profile, nil, now.Add(10*time.Minute)); sent != 1 || len(digests) != 1 {
+		t.Fatalf("flushed %d digests (%v), want 1", sent, digests)
+	}
+	if !strings.HasPrefix(digests[0], parentID+"|[DIGEST] 1 child update(s) in the last 10 min: worker finished (success)") {
+		t.Errorf("digest = %q", digests[0])
+	}
+	if sent := n.FlushDigests(event.Profile, nil, now.Add(time.Hour)); sent != 0 {
+		t.Errorf("digest flushed twice")
+	}
+}
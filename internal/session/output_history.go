@@ -0,0 +1,147 @@
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OutputHistoryLimit is how many distinct responses are kept per session.
+const OutputHistoryLimit = 20
+
+// OutputEntry is one remembered agent response.
+type OutputEntry struct {
+	Timestamp time.Time `json:"ts"`
+	Tool      string    `json:"tool,omitempty"`
+	Hash      string    `json:"hash"`
+	Content   string    `json:"content"`
+}
+
+// OutputHistoryPath returns the file holding a session's recent responses.
+// Like transcripts it is keyed by session ID rather than profile, so the
+// history stays readable after the session is removed.
+func OutputHistoryPath(sessionID string) (string, error) {
+	if sessionID == "" || sessionID == "." || sessionID == ".." || strings.ContainsAny(sessionID, `/\`) {
+		return "", fmt.Errorf("invalid session id %q", sessionID)
+	}
+	dir, err := dataPath("outputs", "outputs")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, sessionID+".json"), nil
+}
+
+// outputHistoryMu serializes read-modify-write cycles on history files within
+// this process (the TUI records from its status worker while a CLI call in
+// the same process may be listing).
+var outputHistoryMu sync.Mutex
+
+// ReadOutputHistory returns a session's remembered responses, oldest first.
+// A session with no history yields an empty slice and no error.
+func ReadOutputHistory(sessionID string) ([]OutputEntry, error) {
+	path, err := OutputHistoryPath(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	outputHistoryMu.Lock()
+	defer outputHistoryMu.Unlock()
+	return readOutputHistoryFile(path)
+}
+
+func readOutputHistoryFile(path string) ([]OutputEntry, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []OutputEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// AppendOutputHistory adds content as the newest entry of a session's
+// history. Responses are kept distinct: repeating the newest entry is a
+// no-op, and repeating an older one moves it to the front instead of
+// duplicating it. Only the last OutputHistoryLimit entries are kept. The
+// returned bool reports whether the history changed.
+func AppendOutputHistory(sessionID string, entry OutputEntry) (bool, error) {
+	entry.Content = strings.TrimSpace(entry.Content)
+	if entry.Content == "" {
+		return false, nil
+	}
+	entry.Hash = outputHash(entry.Content)
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+
+	path, err := OutputHistoryPath(sessionID)
+	if err != nil {
+		return false, err
+	}
+	outputHistoryMu.Lock()
+	defer outputHistoryMu.Unlock()
+
+	entries, err := readOutputHistoryFile(path)
+	if err != nil {
+		// A corrupt file only costs the old entries; start over.
+		sessionLog.Warn("output_history_reset", slog.String("path", path), slog.String("error", err.Error()))
+		entries = nil
+	}
+	if n := len(entries); n > 0 && entries[n-1].Hash == entry.Hash {
+		return false, nil
+	}
+	kept := entries[:0]
+	for _, e := range entries {
+		if e.Hash != entry.Hash {
+			kept = append(kept, e)
+		}
+	}
+	kept = append(kept, entry)
+	if len(kept) > OutputHistoryLimit {
+		kept = kept[len(kept)-OutputHistoryLimit:]
+	}
+
+	data, err := json.MarshalIndent(kept, "", "  ")
+	if err != nil {
+		return false, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return false, err
+	}
+	if err := writeJSONFileAtomic(path, data, 0o600); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func outputHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:8])
+}
+
+// RecordOutputHistory parses the session's latest agent response and adds it
+// to the output history. It is meant to run when a turn ends (running →
+// waiting/idle); calling it again before the next turn is a no-op.
+func (i *Instance) RecordOutputHistory() (bool, error) {
+	resp, err := i.GetLastResponse()
+	if err != nil {
+		return false, err
+	}
+	if resp == nil || resp.Content == "" {
+		return false, nil
+	}
+	ts, _ := time.Parse(time.RFC3339Nano, resp.Timestamp)
+	return AppendOutputHistory(i.ID, OutputEntry{Timestamp: ts, Tool: resp.Tool, Content: resp.Content})
+}
@@ -0,0 +1,57 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestAppendOutputHistory_DistinctRing(t *testing.T) {
+	id := "output-history-test"
+	path, err := OutputHistoryPath(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Remove(path) })
+	_ = os.Remove(path)
+
+	if entries, err := ReadOutputHistory(id); err != nil || len(entries) != 0 {
+		t.Fatalf("empty history = %v, %v", entries, err)
+	}
+
+	add := func(content string) bool {
+		t.Helper()
+		changed, err := AppendOutputHistory(id, OutputEntry{Tool: "claude", Content: content})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return changed
+	}
+	add("first answer")
+	add("second answer")
+	if add("second answer\n") {
+		t.Fatal("repeating the newest response must not change the history")
+	}
+	if !add("first answer") {
+		t.Fatal("repeating an older response must move it to the front")
+	}
+	entries, _ := ReadOutputHistory(id)
+	if len(entries) != 2 || entries[0].Content != "second answer" || entries[1].Content != "first answer" {
+		t.Fatalf("entries = %+v", entries)
+	}
+
+	for n := 0; n < OutputHistoryLimit+5; n++ {
+		add(fmt.Sprintf("answer %d", n))
+	}
+	entries, _ = ReadOutputHistory(id)
+	if len(entries) != OutputHistoryLimit || entries[len(entries)-1].Content != fmt.Sprintf("answer %d", OutputHistoryLimit+4) {
+		t.Fatalf("history holds %d entries ending %q, want the newest %d", len(entries), entries[len(entries)-1].Content, OutputHistoryLimit)
+	}
+
+	if add("   ") {
+		t.Fatal("blank responses must be ignored")
+	}
+	if _, err := OutputHistoryPath("../escape"); err == nil {
+		t.Fatal("ids with path separators must be rejected")
+	}
+}
@@ -0,0 +1,181 @@
+package session
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// Port registry: sessions claim TCP ports (`agent-deck port claim <id> 2`)
+// from a configured range. Claims live in statedb's port_claims table, so
+// two worktree copies of the same app get different ports instead of
+// fighting over :3000. A session's ports are exported into its environment
+// as PORT, PORT_2, ... (plus AGENTDECK_PORTS, the comma-separated list) on
+// the next start, and set on its tmux session so new panes see them too.
+
+// Default claimable range, used when [ports] leaves it unset.
+const (
+	DefaultPortRangeStart = 4000
+	DefaultPortRangeEnd   = 4999
+)
+
+// PortSettings is the [ports] section of config.toml.
+type PortSettings struct {
+	// RangeStart and RangeEnd bound the ports handed out (inclusive).
+	RangeStart int `toml:"range_start,omitempty"`
+	RangeEnd   int `toml:"range_end,omitempty"`
+}
+
+// GetPortSettings returns the [ports] settings from config.toml.
+func GetPortSettings() PortSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return PortSettings{}
+	}
+	return config.Ports
+}
+
+// Range returns the claimable port range, falling back to the default for
+// an unset or invalid one.
+func (s PortSettings) Range() (lo, hi int) {
+	lo, hi = s.RangeStart, s.RangeEnd
+	if lo <= 0 || hi > 65535 || hi < lo {
+		return DefaultPortRangeStart, DefaultPortRangeEnd
+	}
+	return lo, hi
+}
+
+// portAvailable reports whether nothing on this host listens on port.
+// Ports in use by processes outside agent-deck are skipped when claiming.
+var portAvailable = func(port int) bool {
+	l, err := net.Listen("tcp", ":"+strconv.Itoa(port))
+	if err != nil {
+		return false
+	}
+	_ = l.Close()
+	return true
+}
+
+// PortEnvName is the environment variable of a session's k-th port
+// (1-based): PORT, then PORT_2, PORT_3, ...
+func PortEnvName(k int) string {
+	if k <= 1 {
+		return "PORT"
+	}
+	return fmt.Sprintf("PORT_%d", k)
+}
+
+// ClaimPorts claims n free ports in the configured range for a session and
+// returns the new claims. Ports held by another session or in use on the
+// host are skipped. Either all n are claimed or none are.
+func ClaimPorts(db *statedb.StateDB, instanceID string, n int, now time.Time) ([]*statedb.PortClaim, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("port count must be at least 1")
+	}
+	all, err := db.LoadPortClaims("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read port claims: %w", err)
+	}
+	held := make(map[int]bool, len(all))
+	names := map[string]bool{}
+	for _, c := range all {
+		held[c.Port] = true
+		if c.InstanceID == instanceID {
+			names[c.Name] = true
+		}
+	}
+
+	lo, hi := GetPortSettings().Range()
+	var claimed []*statedb.PortClaim
+	k := 1
+	for port := lo; port <= hi && len(claimed) < n; port++ {
+		if held[port] || !portAvailable(port) {
+			continue
+		}
+		for names[PortEnvName(k)] {
+			k++
+		}
+		c := &statedb.PortClaim{Port: port, InstanceID: instanceID, Name: PortEnvName(k), ClaimedAt: now}
+		ok, err := db.ClaimPort(c)
+		if err != nil {
+			releaseClaims(db, instanceID, claimed)
+			return nil, fmt.Errorf("failed to claim port %d: %w", port, err)
+		}
+		if !ok {
+			continue // claimed by another process since we read the table
+		}
+		names[c.Name] = true
+		claimed = append(claimed, c)
+	}
+	if len(claimed) < n {
+		releaseClaims(db, instanceID, claimed)
+		return nil, fmt.Errorf("only %d of %d ports free in %d-%d (widen [ports] range_start/range_end)", len(claimed), n, lo, hi)
+	}
+	return claimed, nil
+}
+
+func releaseClaims(db *statedb.StateDB, instanceID string, claims []*statedb.PortClaim) {
+	if len(claims) == 0 {
+		return
+	}
+	ports := make([]int, len(claims))
+	for i, c := range claims {
+		ports[i] = c.Port
+	}
+	_, _ = db.ReleasePorts(instanceID, ports...)
+}
+
+// PortEnv maps a session's claims to the environment it is started with.
+func PortEnv(claims []*statedb.PortClaim) map[string]string {
+	if len(claims) == 0 {
+		return nil
+	}
+	env := make(map[string]string, len(claims)+1)
+	ports := make([]string, len(claims))
+	for i, c := range claims {
+		env[c.Name] = strconv.Itoa(c.Port)
+		ports[i] = strconv.Itoa(c.Port)
+	}
+	env["AGENTDECK_PORTS"] = strings.Join(ports, ",")
+	return env
+}
+
+// portEnv returns the port environment of the session, read from the
+// profile's state database. nil when it holds no ports.
+func (i *Instance) portEnv() map[string]string {
+	db := statedb.GetGlobal()
+	if db == nil || i.ID == "" {
+		return nil
+	}
+	claims, err := db.LoadPortClaims(i.ID)
+	if err != nil {
+		return nil
+	}
+	return PortEnv(claims)
+}
+
+// SyncPortEnv brings the running tmux session's environment in line with
+// the session's current claims after a claim or release of dropped, so
+// panes opened from now on see the right ports. The agent process itself
+// picks them up on its next start.
+func (i *Instance) SyncPortEnv(claims, dropped []*statedb.PortClaim) {
+	if i.tmuxSession == nil || !i.tmuxSession.Exists() {
+		return
+	}
+	env := PortEnv(claims)
+	for _, c := range dropped {
+		if _, ok := env[c.Name]; !ok {
+			_ = i.tmuxSession.UnsetEnvironment(c.Name)
+		}
+	}
+	if len(env) == 0 {
+		_ = i.tmuxSession.UnsetEnvironment("AGENTDECK_PORTS")
+	}
+	for key, value := range env {
+		_ = i.tmuxSession.SetEnvironment(key, value)
+	}
+}
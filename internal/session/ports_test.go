@@ -0,0 +1,90 @@
+package session
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+func TestClaimPorts_SkipsHeldAndBusyPorts(t *testing.T) {
+	writePresetTestConfig(t, `
+[ports]
+range_start = 5000
+range_end = 5005
+`)
+	db, err := statedb.Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+	prev := portAvailable
+	portAvailable = func(port int) bool { return port != 5001 } // held outside agent-deck
+	t.Cleanup(func() { portAvailable = prev })
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.Local)
+
+	one, err := ClaimPorts(db, "try-1", 2, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := formatClaims(one); got != "5000 PORT,5002 PORT_2" {
+		t.Fatalf("try-1 claims = %s", got)
+	}
+	two, err := ClaimPorts(db, "try-2", 1, now)
+	if err != nil || formatClaims(two) != "5003 PORT" {
+		t.Fatalf("try-2 claims = %s, %v; a copy of the app must not get try-1's ports", formatClaims(two), err)
+	}
+
+	// A released port and name are reused before new ones.
+	if _, err := db.ReleasePorts("try-1", 5000); err != nil {
+		t.Fatal(err)
+	}
+	again, err := ClaimPorts(db, "try-1", 1, now)
+	if err != nil || formatClaims(again) != "5000 PORT" {
+		t.Fatalf("reclaim = %s, %v", formatClaims(again), err)
+	}
+
+	// Not enough room: nothing is claimed.
+	if _, err := ClaimPorts(db, "try-3", 3, now); err == nil || !strings.Contains(err.Error(), "only 2 of 3") {
+		t.Fatalf("overfull claim error = %v", err)
+	}
+	if claims, _ := db.LoadPortClaims("try-3"); len(claims) != 0 {
+		t.Fatalf("failed claim left %d ports behind", len(claims))
+	}
+
+	claims, _ := db.LoadPortClaims("try-1")
+	env := PortEnv(claims)
+	if env["PORT"] != "5000" || env["PORT_2"] != "5002" || env["AGENTDECK_PORTS"] != "5000,5002" {
+		t.Fatalf("PortEnv = %v", env)
+	}
+}
+
+func TestPortSettings_Range(t *testing.T) {
+	for _, tc := range []struct {
+		s      PortSettings
+		lo, hi int
+	}{
+		{PortSettings{}, DefaultPortRangeStart, DefaultPortRangeEnd},
+		{PortSettings{RangeStart: 3000, RangeEnd: 3099}, 3000, 3099},
+		{PortSettings{RangeStart: 3099, RangeEnd: 3000}, DefaultPortRangeStart, DefaultPortRangeEnd},
+		{PortSettings{RangeStart: 60000, RangeEnd: 70000}, DefaultPortRangeStart, DefaultPortRangeEnd},
+	} {
+		if lo, hi := tc.s.Range(); lo != tc.lo || hi != tc.hi {
+			t.Errorf("%+v.Range() = %d-%d, want %d-%d", tc.s, lo, hi, tc.lo, tc.hi)
+		}
+	}
+}
+
+func formatClaims(claims []*statedb.PortClaim) string {
+	parts := make([]string, len(claims))
+	for i, c := range claims {
+		parts[i] = fmt.Sprintf("%d %s", c.Port, c.Name)
+	}
+	return strings.Join(parts, ",")
+}
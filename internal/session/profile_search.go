@@ -0,0 +1,150 @@
+package session
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sahilm/fuzzy"
+)
+
+// ProfileSession is a stored session together with the profile it lives in.
+type ProfileSession struct {
+	Profile string
+	*InstanceData
+}
+
+// LoadProfileSessions reads the sessions of every profile except those in
+// skip, without touching tmux. Profiles that fail to open are left out, as
+// in `list --all`.
+func LoadProfileSessions(skip ...string) ([]ProfileSession, error) {
+	profiles, err := ListProfiles()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(profiles)
+	var out []ProfileSession
+	for _, p := range profiles {
+		if slices.Contains(skip, p) {
+			continue
+		}
+		storage, err := NewStorageWithProfile(p)
+		if err != nil {
+			continue
+		}
+		instances, _, err := storage.LoadLite()
+		storage.Close()
+		if err != nil {
+			continue
+		}
+		for _, inst := range instances {
+			out = append(out, ProfileSession{Profile: p, InstanceData: inst})
+		}
+	}
+	return out, nil
+}
+
+// Fields a SessionSearchHit can match in, best first.
+const (
+	SearchFieldTitle      = "title"
+	SearchFieldGroup      = "group"
+	SearchFieldPath       = "path"
+	SearchFieldTranscript = "transcript"
+)
+
+// SessionSearchHit is a session matched by SearchProfileSessions.
+type SessionSearchHit struct {
+	ProfileSession
+	Field   string // one of the SearchField constants
+	Snippet string // the matching transcript line, for SearchFieldTranscript
+	Score   int
+}
+
+// searchFieldWeight ranks a match by where it was found: a title beats a
+// group beats a path beats transcript content.
+var searchFieldWeight = map[string]int{
+	SearchFieldTitle:      3,
+	SearchFieldGroup:      2,
+	SearchFieldPath:       1,
+	SearchFieldTranscript: 0,
+}
+
+// SearchProfileSessions matches query against sessions' titles and groups
+// (fuzzy), project paths (substring) and, when transcripts is set, their
+// recorded transcripts (substring, newest line wins). A substring match
+// outranks a fuzzy one, and each session is reported once, for its best
+// field. Results are best first.
+func SearchProfileSessions(query string, sessions []ProfileSession, transcripts bool) []SessionSearchHit {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+	lower := strings.ToLower(query)
+	var hits []SessionSearchHit
+	for _, ps := range sessions {
+		if ps.InstanceData == nil {
+			continue
+		}
+		hit := SessionSearchHit{ProfileSession: ps, Score: -1}
+		for _, f := range []struct{ field, value string }{
+			{SearchFieldTitle, ps.Title},
+			{SearchFieldGroup, ps.GroupPath},
+			{SearchFieldPath, ps.ProjectPath},
+		} {
+			score := -1
+			switch {
+			case f.value == "":
+			case strings.Contains(strings.ToLower(f.value), lower):
+				score = 100 * searchFieldWeight[f.field]
+			case f.field != SearchFieldPath && len(fuzzy.Find(query, []string{f.value})) > 0:
+				// Paths are long enough that a fuzzy subsequence matches
+				// nearly any short query, so they only match as substrings.
+				score = 10 * searchFieldWeight[f.field]
+			}
+			if score > hit.Score {
+				hit.Field, hit.Score = f.field, score
+			}
+		}
+		if hit.Score < 0 && transcripts {
+			if line, ok := searchTranscript(ps.ID, lower); ok {
+				hit.Field, hit.Snippet, hit.Score = SearchFieldTranscript, line, 1
+			}
+		}
+		if hit.Score >= 0 {
+			hits = append(hits, hit)
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].LastAccessedAt.After(hits[j].LastAccessedAt)
+	})
+	return hits
+}
+
+// searchTranscriptSnippetLen caps the transcript line returned as a snippet.
+const searchTranscriptSnippetLen = 160
+
+// searchTranscript returns the newest recorded transcript line of a session
+// that contains lowerQuery (already lower-cased).
+func searchTranscript(sessionID, lowerQuery string) (string, bool) {
+	entries, err := ReadTranscript(sessionID, time.Time{})
+	if err != nil {
+		return "", false
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		lines := entries[i].Lines
+		for j := len(lines) - 1; j >= 0; j-- {
+			if strings.Contains(strings.ToLower(lines[j]), lowerQuery) {
+				line := strings.TrimSpace(lines[j])
+				if r := []rune(line); len(r) > searchTranscriptSnippetLen {
+					line = string(r[:searchTranscriptSnippetLen-1]) + "…"
+				}
+				return line, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,65 @@
+package session
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestSearchProfileSessions_RanksByField(t *testing.T) {
+	sessions := []ProfileSession{
+		{Profile: "work", InstanceData: &InstanceData{ID: "p1", Title: "web", GroupPath: "billing", ProjectPath: "/src/web"}},
+		{Profile: "default", InstanceData: &InstanceData{ID: "t1", Title: "billing-api", GroupPath: "misc", ProjectPath: "/src/api"}},
+		{Profile: "work", InstanceData: &InstanceData{ID: "x1", Title: "docs", GroupPath: "misc", ProjectPath: "/src/billing-docs"}},
+		{Profile: "default", InstanceData: &InstanceData{ID: "f1", Title: "big-lint", GroupPath: "misc", ProjectPath: "/src/lint"}},
+		{Profile: "default", InstanceData: &InstanceData{ID: "n1", Title: "other", GroupPath: "misc", ProjectPath: "/src/other"}},
+	}
+
+	hits := SearchProfileSessions("billing", sessions, false)
+	var got []string
+	for _, h := range hits {
+		got = append(got, h.ID+":"+h.Field)
+	}
+	want := []string{"t1:title", "p1:group", "x1:path"}
+	if len(got) != len(want) {
+		t.Fatalf("hits = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("hits = %v, want %v", got, want)
+		}
+	}
+
+	// Titles match fuzzily; paths do not.
+	hits = SearchProfileSessions("blint", sessions, false)
+	if len(hits) != 1 || hits[0].ID != "f1" || hits[0].Field != SearchFieldTitle {
+		t.Fatalf("fuzzy hits = %+v, want big-lint by title", hits)
+	}
+	if hits := SearchProfileSessions("  ", sessions, false); hits != nil {
+		t.Errorf("blank query matched %+v", hits)
+	}
+}
+
+func TestSearchProfileSessions_Transcript(t *testing.T) {
+	id := "profile-search-transcript-test"
+	path, err := TranscriptPath(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Remove(path) })
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	for i, lines := range [][]string{{"Running migration 0042"}, {"ERROR: Migration failed: duplicate column", "retrying"}} {
+		if err := appendTranscriptEntry(path, TranscriptEntry{Timestamp: base.Add(time.Duration(i) * time.Minute), Lines: lines}, 1<<20); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sessions := []ProfileSession{{Profile: "work", InstanceData: &InstanceData{ID: id, Title: "api", ProjectPath: "/src/api"}}}
+	if hits := SearchProfileSessions("migration", sessions, false); len(hits) != 0 {
+		t.Fatalf("transcripts searched when disabled: %+v", hits)
+	}
+	hits := SearchProfileSessions("migration", sessions, true)
+	if len(hits) != 1 || hits[0].Field != SearchFieldTranscript || hits[0].Snippet != "ERROR: Migration failed: duplicate column" {
+		t.Fatalf("hits = %+v, want the newest matching transcript line", hits)
+	}
+}
@@ -0,0 +1,420 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/asheshgoplani/agent-deck/internal/git"
+	"github.com/asheshgoplani/agent-deck/internal/progress"
+	"github.com/asheshgoplani/agent-deck/internal/vcs"
+	"github.com/asheshgoplani/agent-deck/internal/vcsbackend"
+)
+
+// ProjectManifestFile is the repo-level manifest declaring the sessions,
+// groups and loadout a project wants, so a teammate can materialize the same
+// deck with one `agent-deck add --from-manifest`.
+const ProjectManifestFile = ".agentdeck.toml"
+
+// ProjectManifest is the schema of .agentdeck.toml:
+//
+//	group  = "acme"             # root group (default: the directory name)
+//	mcps   = ["github"]         # attached to every session
+//	skills = ["team/review"]
+//
+//	[worktree]
+//	location = "sibling"        # for worktree sessions (default: [worktree].default_location)
+//
+//	[groups.backend]            # acme/backend
+//	default_path = "services/api"
+//
+//	[[sessions]]
+//	title  = "api"
+//	group  = "backend"          # relative to the root group
+//	path   = "services/api"     # relative to the manifest
+//	cmd    = "claude"
+//	mcps   = ["postgres"]
+//
+//	[[sessions]]
+//	title    = "login"
+//	cmd      = "claude"
+//	worktree = "feature/login"  # branch; the session runs in its worktree
+//
+// Paths must stay inside the manifest's directory. Materializing is
+// idempotent: a session whose title already exists in its group is kept and
+// only has its loadout re-asserted.
+type ProjectManifest struct {
+	Group    string                   `toml:"group"`
+	MCPs     []string                 `toml:"mcps"`
+	Skills   []string                 `toml:"skills"`
+	Worktree ManifestWorktreeSettings `toml:"worktree"`
+	Groups   map[string]ManifestGroup `toml:"groups"`
+	Sessions []ManifestSession        `toml:"sessions"`
+
+	// Path is the manifest file and Dir its directory, the base for every
+	// relative path in it.
+	Path string `toml:"-"`
+	Dir  string `toml:"-"`
+}
+
+// ManifestWorktreeSettings is the [worktree] table of a project manifest.
+type ManifestWorktreeSettings struct {
+	Location string `toml:"location"`
+}
+
+// ManifestGroup is one [groups."<path>"] table of a project manifest.
+type ManifestGroup struct {
+	DefaultPath string `toml:"default_path"`
+}
+
+// ManifestSession is one [[sessions]] entry of a project manifest.
+type ManifestSession struct {
+	Title    string   `toml:"title"`
+	Group    string   `toml:"group"`
+	Path     string   `toml:"path"`
+	Cmd      string   `toml:"cmd"`
+	Worktree string   `toml:"worktree"`
+	MCPs     []string `toml:"mcps"`
+	Skills   []string `toml:"skills"`
+}
+
+// FindProjectManifest returns the nearest .agentdeck.toml at or above dir.
+func FindProjectManifest(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		path := filepath.Join(dir, ProjectManifestFile)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// LoadProjectManifest reads and validates a manifest. path may be the file
+// itself or a directory containing .agentdeck.toml.
+func LoadProjectManifest(path string) (*ProjectManifest, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, ProjectManifestFile)
+	}
+	var m ProjectManifest
+	md, err := toml.DecodeFile(path, &m)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		return nil, fmt.Errorf("%s: unknown key %q", path, undecoded[0].String())
+	}
+	m.Path, m.Dir = path, filepath.Dir(path)
+	if err := m.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &m, nil
+}
+
+func (m *ProjectManifest) validate() error {
+	if m.RootGroup() == "" {
+		return errors.New("group resolves to an empty group path")
+	}
+	for key, g := range m.Groups {
+		if canonicalGroupPath(key) == "" {
+			return fmt.Errorf("groups.%q: empty group path", key)
+		}
+		if g.DefaultPath != "" {
+			if _, err := m.resolvePath(g.DefaultPath); err != nil {
+				return fmt.Errorf("groups.%q.default_path: %w", key, err)
+			}
+		}
+	}
+	seen := make(map[string]bool, len(m.Sessions))
+	for i, s := range m.Sessions {
+		title := strings.TrimSpace(s.Title)
+		if title == "" {
+			return fmt.Errorf("sessions[%d]: title is required", i)
+		}
+		key := m.GroupPath(s.Group) + "\x00" + title
+		if seen[key] {
+			return fmt.Errorf("sessions[%d]: duplicate title %q in group %q", i, title, m.GroupPath(s.Group))
+		}
+		seen[key] = true
+		if _, err := m.resolvePath(s.Path); err != nil {
+			return fmt.Errorf("sessions[%d] (%s).path: %w", i, title, err)
+		}
+		if s.Worktree != "" {
+			if err := git.ValidateBranchName(s.Worktree); err != nil {
+				return fmt.Errorf("sessions[%d] (%s).worktree: %w", i, title, err)
+			}
+		}
+	}
+	return nil
+}
+
+// RootGroup returns the canonical group every manifest group and session
+// nests under.
+func (m *ProjectManifest) RootGroup() string {
+	if m.Group != "" {
+		return canonicalGroupPath(m.Group)
+	}
+	return canonicalGroupPath(filepath.Base(m.Dir))
+}
+
+// GroupPath returns the canonical path of a group named relative to the
+// root group ("" is the root group itself).
+func (m *ProjectManifest) GroupPath(rel string) string {
+	if canonicalGroupPath(rel) == "" {
+		return m.RootGroup()
+	}
+	return canonicalGroupPath(m.RootGroup() + "/" + rel)
+}
+
+// resolvePath resolves a manifest-relative path, refusing absolute paths and
+// anything that escapes the manifest's directory: a checked-in manifest must
+// not point sessions at the rest of the filesystem.
+func (m *ProjectManifest) resolvePath(rel string) (string, error) {
+	if filepath.IsAbs(rel) || strings.HasPrefix(rel, "~") {
+		return "", fmt.Errorf("%q must be relative to the manifest", rel)
+	}
+	path := filepath.Join(m.Dir, rel)
+	if path != m.Dir && !strings.HasPrefix(path, m.Dir+string(filepath.Separator)) {
+		return "", fmt.Errorf("%q escapes the manifest directory", rel)
+	}
+	return path, nil
+}
+
+// ApplyGroups creates the root group and every declared group in tree and
+// applies their default paths. Returns true if the tree was modified.
+func (m *ProjectManifest) ApplyGroups(tree *GroupTree) bool {
+	changed := false
+	ensure := func(path string) bool {
+		if _, exists := tree.Groups[path]; exists {
+			return true
+		}
+		if tree.CreateGroupPath(path) == nil {
+			return false
+		}
+		changed = true
+		return true
+	}
+	ensure(m.RootGroup())
+	for key, g := range m.Groups {
+		path := m.GroupPath(key)
+		if !ensure(path) || g.DefaultPath == "" {
+			continue
+		}
+		dir, _ := m.resolvePath(g.DefaultPath)
+		if tree.Groups[path].DefaultPath != dir {
+			tree.SetDefaultPathForGroup(path, dir)
+			changed = true
+		}
+	}
+	for _, s := range m.Sessions {
+		ensure(m.GroupPath(s.Group))
+	}
+	return changed
+}
+
+// ManifestResult reports what Materialize did.
+type ManifestResult struct {
+	// Created holds the new sessions (not started). The caller appends them
+	// to its instance list and saves.
+	Created []*Instance
+	// Existing holds sessions the manifest matched in the deck.
+	Existing []*Instance
+	// Failed maps manifest session titles to why they were skipped.
+	Failed map[string]error
+	// Warnings are non-fatal loadout and setup-script problems.
+	Warnings []string
+}
+
+// Materialize creates the manifest's sessions that instances does not hold
+// yet (matched by title within their group), creating worktrees as needed,
+// and attaches the manifest's skills and MCPs to new and existing sessions
+// alike. Loadout is attach-only, like the config-driven loadout: removing an
+// entry from the manifest never detaches it. One failing session never stops
+// the rest. Call ApplyGroups on the caller's tree as well.
+func (m *ProjectManifest) Materialize(instances []*Instance, report progress.Func) *ManifestResult {
+	res := &ManifestResult{Failed: make(map[string]error)}
+	byKey := make(map[string]*Instance, len(instances))
+	for _, inst := range instances {
+		byKey[inst.GroupPath+"\x00"+inst.Title] = inst
+	}
+
+	for i, spec := range m.Sessions {
+		title := strings.TrimSpace(spec.Title)
+		group := m.GroupPath(spec.Group)
+		inst := byKey[group+"\x00"+title]
+		if inst != nil {
+			report.Step(i+1, len(m.Sessions), "Checking %s", title)
+			res.Existing = append(res.Existing, inst)
+		} else {
+			report.Step(i+1, len(m.Sessions), "Creating %s", title)
+			var warning string
+			var err error
+			inst, warning, err = m.newInstance(spec, title, group, report)
+			if err != nil {
+				res.Failed[title] = err
+				continue
+			}
+			if warning != "" {
+				res.Warnings = append(res.Warnings, fmt.Sprintf("%s: %s", title, warning))
+			}
+			res.Created = append(res.Created, inst)
+			byKey[group+"\x00"+title] = inst
+		}
+		for _, w := range m.applyLoadout(inst, spec) {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("%s: %s", title, w))
+		}
+	}
+	return res
+}
+
+func (m *ProjectManifest) newInstance(spec ManifestSession, title, group string, report progress.Func) (*Instance, string, error) {
+	path, err := m.resolvePath(spec.Path)
+	if err != nil {
+		return nil, "", err
+	}
+	if info, err := os.Stat(path); err != nil || !info.IsDir() {
+		return nil, "", fmt.Errorf("path is not a directory: %s", path)
+	}
+
+	var wt manifestWorktree
+	var warning string
+	if spec.Worktree != "" {
+		wt, warning, err = m.createWorktree(path, spec.Worktree, report)
+		if err != nil {
+			return nil, "", err
+		}
+		path = wt.path
+	}
+
+	inst := NewInstanceWithGroup(title, path, group)
+	// An explicit title is locked against Claude's session-name sync, as
+	// with `add -t` (#1615).
+	inst.TitleLocked = true
+	setInstanceCommand(inst, spec.Cmd)
+	if wt.path != "" {
+		inst.WorktreePath = wt.path
+		inst.WorktreeRepoRoot = wt.repoRoot
+		inst.WorktreeBranch = wt.branch
+		inst.WorktreeType = wt.vcsType
+	}
+	return inst, warning, nil
+}
+
+type manifestWorktree struct {
+	path, repoRoot, branch, vcsType string
+}
+
+// createWorktree creates (or reuses) the worktree for branch in the repo at
+// dir, the same way `add --worktree` does.
+func (m *ProjectManifest) createWorktree(dir, branch string, report progress.Func) (manifestWorktree, string, error) {
+	backend, err := vcsbackend.Detect(dir)
+	if err != nil {
+		return manifestWorktree{}, "", err
+	}
+	settings := GetWorktreeSettings()
+	branch = settings.ApplyBranchPrefix(branch)
+	wt := manifestWorktree{repoRoot: backend.RepoDir(), branch: branch, vcsType: string(backend.Type())}
+
+	if existing, err := backend.GetWorktreeForBranch(branch); err == nil && existing != "" {
+		report.Logf("Reusing worktree for %s at %s", branch, existing)
+		wt.path = existing
+		return wt, "", nil
+	}
+	location := settings.DefaultLocation
+	if m.Worktree.Location != "" {
+		location = m.Worktree.Location
+	}
+	wt.path = backend.WorktreePath(vcs.WorktreePathOptions{
+		Branch:    branch,
+		Location:  location,
+		SessionID: git.GeneratePathID(),
+		Template:  settings.Template(),
+	})
+	if err := os.MkdirAll(filepath.Dir(wt.path), 0o755); err != nil {
+		return manifestWorktree{}, "", fmt.Errorf("create worktree parent: %w", err)
+	}
+	report.Logf("Creating worktree for %s at %s", branch, wt.path)
+	lines := progress.LineWriter(report)
+	setupErr, err := vcsbackend.CreateWorktreeWithSetup(backend, wt.path, branch, lines, lines, settings.SetupTimeout())
+	lines.Flush()
+	if err != nil {
+		return manifestWorktree{}, "", fmt.Errorf("create worktree: %w", err)
+	}
+	if setupErr != nil {
+		return wt, fmt.Sprintf("worktree setup script failed: %v", setupErr), nil
+	}
+	return wt, "", nil
+}
+
+// applyLoadout attaches the manifest-wide and per-session skills and MCPs,
+// plus the config-driven loadout for the session's group.
+func (m *ProjectManifest) applyLoadout(inst *Instance, spec ManifestSession) []string {
+	warnings := ApplyConfiguredLoadout(inst)
+	if inst.SSHHost != "" {
+		return warnings
+	}
+
+	for _, entry := range unionLoadoutEntries(m.Skills, spec.Skills) {
+		_, err := AttachSkillToProject(inst.ProjectPath, inst.Tool, entry, "")
+		switch {
+		case err == nil, errors.Is(err, ErrSkillAlreadyAttached):
+		case errors.Is(err, ErrSkillNotFound) || errors.Is(err, ErrSkillSourceNotFound):
+			warnings = append(warnings, fmt.Sprintf("skill %q: not found in the skill-source registry", entry))
+		default:
+			warnings = append(warnings, fmt.Sprintf("skill %q: %v", entry, err))
+		}
+	}
+
+	mcps := unionLoadoutEntries(m.MCPs, spec.MCPs)
+	if len(mcps) == 0 {
+		return warnings
+	}
+	available := GetAvailableMCPs()
+	var current []string
+	if info := inst.MCPInfoForLocalAttach(); info != nil {
+		current = info.Local()
+	}
+	attached := make(map[string]bool, len(current))
+	for _, name := range current {
+		attached[name] = true
+	}
+	merged := append([]string{}, current...)
+	for _, name := range mcps {
+		if attached[name] {
+			continue
+		}
+		if _, ok := available[name]; !ok {
+			warnings = append(warnings, fmt.Sprintf("mcp %q: not defined in config.toml [mcps.%s]", name, name))
+			continue
+		}
+		merged = append(merged, name)
+		attached[name] = true
+	}
+	if len(merged) > len(current) {
+		if err := inst.WriteLocalMCPConfig(merged); err != nil {
+			warnings = append(warnings, fmt.Sprintf("mcp loadout: %v", err))
+		} else {
+			inst.InvalidateProjectMCPIntegrationsCache()
+			sessionLog.Info("manifest_mcps_attached",
+				slog.String("session", sanitizeLoadoutWarning(inst.Title)),
+				slog.String("mcps", sanitizeLoadoutWarning(strings.Join(merged[len(current):], ","))))
+		}
+	}
+	return warnings
+}
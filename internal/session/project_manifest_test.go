@@ -0,0 +1,110 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeManifest(t *testing.T, dir, body string) string {
+	t.Helper()
+	path := filepath.Join(dir, ProjectManifestFile)
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadProjectManifest_Validation(t *testing.T) {
+	cases := []struct {
+		name, body, wantErr string
+	}{
+		{"escaping path", "[[sessions]]\ntitle = \"x\"\npath = \"../other\"\n", "escapes the manifest directory"},
+		{"absolute path", "[[sessions]]\ntitle = \"x\"\npath = \"/etc\"\n", "must be relative"},
+		{"missing title", "[[sessions]]\npath = \"a\"\n", "title is required"},
+		{"duplicate", "[[sessions]]\ntitle = \"x\"\n[[sessions]]\ntitle = \"x\"\n", "duplicate title"},
+		{"typo", "[[sessions]]\ntitle = \"x\"\ncommand = \"claude\"\n", `unknown key "sessions.command"`},
+		{"bad branch", "[[sessions]]\ntitle = \"x\"\nworktree = \"a..b\"\n", "worktree"},
+	}
+	for _, c := range cases {
+		dir := t.TempDir()
+		writeManifest(t, dir, c.body)
+		if _, err := LoadProjectManifest(dir); err == nil || !strings.Contains(err.Error(), c.wantErr) {
+			t.Errorf("%s: err = %v, want %q", c.name, err, c.wantErr)
+		}
+	}
+}
+
+func TestFindProjectManifest_WalksUp(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "My Repo")
+	sub := filepath.Join(root, "services", "api")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	want := writeManifest(t, root, "[[sessions]]\ntitle = \"api\"\n")
+	got, ok := FindProjectManifest(sub)
+	if !ok || got != want {
+		t.Fatalf("FindProjectManifest = %q, %v; want %q", got, ok, want)
+	}
+	m, err := LoadProjectManifest(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.RootGroup() != "My-Repo" || m.GroupPath("backend/db") != "My-Repo/backend/db" {
+		t.Fatalf("root=%q nested=%q", m.RootGroup(), m.GroupPath("backend/db"))
+	}
+}
+
+func TestProjectManifest_MaterializeIsIdempotent(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "services", "api"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeManifest(t, dir, `group = "acme"
+
+[groups.backend]
+default_path = "services/api"
+
+[[sessions]]
+title = "api"
+group = "backend"
+path = "services/api"
+cmd = "claude"
+
+[[sessions]]
+title = "notes"
+
+[[sessions]]
+title = "ghost"
+path = "missing"
+`)
+	m, err := LoadProjectManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := m.Materialize(nil, nil)
+	if len(res.Created) != 2 || len(res.Failed) != 1 || res.Failed["ghost"] == nil {
+		t.Fatalf("created=%d failed=%v", len(res.Created), res.Failed)
+	}
+	api := res.Created[0]
+	if api.Title != "api" || api.GroupPath != "acme/backend" || api.Tool != "claude" ||
+		api.ProjectPath != filepath.Join(dir, "services", "api") || !api.TitleLocked {
+		t.Fatalf("api session = %+v", api)
+	}
+	if notes := res.Created[1]; notes.GroupPath != "acme" || notes.ProjectPath != dir {
+		t.Fatalf("notes session group=%q path=%q", notes.GroupPath, notes.ProjectPath)
+	}
+
+	tree := NewGroupTreeWithGroups(res.Created, nil)
+	m.ApplyGroups(tree)
+	if g := tree.Groups["acme/backend"]; g == nil || g.DefaultPath == "" {
+		t.Fatalf("acme/backend group = %+v, want it created with a default path", g)
+	}
+
+	again := m.Materialize(res.Created, nil)
+	if len(again.Created) != 0 || len(again.Existing) != 2 {
+		t.Fatalf("second run created=%d existing=%d, want 0/2", len(again.Created), len(again.Existing))
+	}
+}
@@ -0,0 +1,125 @@
+package session
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/git"
+)
+
+// SavedPrompt is a reusable prompt snippet from [prompts.<name>] in
+// config.toml, sent with `agent-deck prompt send` or the TUI prompt picker.
+type SavedPrompt struct {
+	// Text is the prompt. {name} placeholders are filled in when it is sent;
+	// see RenderPrompt.
+	Text string `toml:"text"`
+
+	// Description is shown by `prompt list` and in the picker.
+	Description string `toml:"description,omitempty"`
+}
+
+var (
+	promptNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+	promptVarRe  = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+)
+
+// ValidatePromptName rejects names that can't be typed as a CLI argument
+// without quoting.
+func ValidatePromptName(name string) error {
+	if !promptNameRe.MatchString(name) {
+		return fmt.Errorf("invalid prompt name %q: use letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// GetSavedPrompts returns the prompt library from config.toml.
+func GetSavedPrompts() map[string]SavedPrompt {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return nil
+	}
+	return config.Prompts
+}
+
+// SavedPromptNames returns the names in prompts, sorted.
+func SavedPromptNames(prompts map[string]SavedPrompt) []string {
+	names := make([]string, 0, len(prompts))
+	for name := range prompts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupSavedPrompt returns the named prompt.
+func LookupSavedPrompt(name string) (SavedPrompt, error) {
+	prompts := GetSavedPrompts()
+	p, ok := prompts[name]
+	if !ok {
+		if len(prompts) == 0 {
+			return SavedPrompt{}, fmt.Errorf("prompt %q not found (no prompts saved yet; add one with 'agent-deck prompt save')", name)
+		}
+		return SavedPrompt{}, fmt.Errorf("prompt %q not found (have: %s)", name, strings.Join(SavedPromptNames(prompts), ", "))
+	}
+	return p, nil
+}
+
+// PromptVariables returns the distinct {name} placeholders in text, in order
+// of first use.
+func PromptVariables(text string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, m := range promptVarRe.FindAllStringSubmatch(text, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
+
+// PromptVarsFor returns the built-in variables for sending text to inst:
+// {path}, {title}, {group}, {tool}, {id} and, when text uses it and the
+// project is a git checkout, {branch}.
+func PromptVarsFor(inst *Instance, text string) map[string]string {
+	vars := map[string]string{
+		"path":  inst.ProjectPath,
+		"title": inst.Title,
+		"group": inst.GroupPath,
+		"tool":  inst.Tool,
+		"id":    inst.ID,
+	}
+	if strings.Contains(text, "{branch}") {
+		if branch, err := git.GetCurrentBranch(inst.ProjectPath); err == nil && branch != "" {
+			vars["branch"] = branch
+		}
+	}
+	return vars
+}
+
+// RenderPrompt fills the {name} placeholders in text from vars. Braces that
+// don't enclose a plain identifier (JSON, code) are left alone. Every
+// placeholder must have a value; when some don't, the error names them and
+// the returned text has them left in place so the caller can offer it for
+// editing.
+func RenderPrompt(text string, vars map[string]string) (string, error) {
+	var missing []string
+	out := promptVarRe.ReplaceAllStringFunc(text, func(m string) string {
+		name := m[1 : len(m)-1]
+		v, ok := vars[name]
+		if !ok {
+			if !slices.Contains(missing, m) {
+				missing = append(missing, m)
+			}
+			return m
+		}
+		return v
+	})
+	if len(missing) > 0 {
+		return out, fmt.Errorf("no value for %s", strings.Join(missing, ", "))
+	}
+	return out, nil
+}
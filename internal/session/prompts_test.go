@@ -0,0 +1,42 @@
+package session
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPromptVariables(t *testing.T) {
+	got := PromptVariables(`cd {path} && test {branch}; echo {"json": 1} {path} {9x}`)
+	if want := []string{"path", "branch"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("PromptVariables = %v, want %v", got, want)
+	}
+}
+
+func TestRenderPrompt(t *testing.T) {
+	vars := map[string]string{"path": "/src/api", "branch": "main"}
+	got, err := RenderPrompt("Run tests in {path} on {branch}; keep {\"k\": 1}", vars)
+	if err != nil || got != `Run tests in /src/api on main; keep {"k": 1}` {
+		t.Fatalf("RenderPrompt = %q, %v", got, err)
+	}
+
+	got, err = RenderPrompt("Fix {issue} in {path}, see {issue}", vars)
+	if err == nil || err.Error() != "no value for {issue}" {
+		t.Fatalf("missing variable error = %v", err)
+	}
+	if got != "Fix {issue} in /src/api, see {issue}" {
+		t.Fatalf("partial render = %q", got)
+	}
+}
+
+func TestValidatePromptName(t *testing.T) {
+	for _, name := range []string{"fix-tests", "review_2", "a"} {
+		if err := ValidatePromptName(name); err != nil {
+			t.Errorf("ValidatePromptName(%q) = %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "-x", "has space", "dot.name"} {
+		if err := ValidatePromptName(name); err == nil {
+			t.Errorf("ValidatePromptName(%q) accepted", name)
+		}
+	}
+}
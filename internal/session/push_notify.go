@@ -0,0 +1,368 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Push notifications for sessions left waiting, sent straight to a phone
+// through ntfy, Pushover or Gotify — no conductor or bridge needed:
+//
+//	[notifications.push]
+//	provider = "ntfy"
+//	after_minutes = 5
+//
+//	[notifications.push.ntfy]
+//	topic = "my-agent-deck"
+//
+// A session that stays waiting for after_minutes gets one push; it is armed
+// again once the session leaves waiting. Like [events] hooks, the TUI sends
+// them from its status loop and the notify-daemon only while no TUI runs.
+
+const (
+	// defaultPushAfter is how long a session waits before a push.
+	defaultPushAfter = 5 * time.Minute
+	// pushTimeout bounds one provider request.
+	pushTimeout = 15 * time.Second
+	// defaultNtfyServer is used when [notifications.push.ntfy] server is empty.
+	defaultNtfyServer = "https://ntfy.sh"
+	// pushoverAPI is the Pushover message endpoint.
+	pushoverAPI = "https://api.pushover.net/1/messages.json"
+)
+
+// Push providers for PushSettings.Provider.
+const (
+	PushProviderNtfy     = "ntfy"
+	PushProviderPushover = "pushover"
+	PushProviderGotify   = "gotify"
+)
+
+// pushHTTPClient sends provider requests. Swapped in tests.
+var pushHTTPClient = &http.Client{}
+
+// PushSettings configures [notifications.push].
+type PushSettings struct {
+	// Provider is "ntfy", "pushover" or "gotify". Empty disables pushes.
+	Provider string `toml:"provider,omitempty"`
+
+	// AfterMinutes is how long a session has to be waiting before a push
+	// is sent (default: 5).
+	AfterMinutes int `toml:"after_minutes,omitzero"`
+
+	Ntfy     NtfySettings     `toml:"ntfy,omitempty"`
+	Pushover PushoverSettings `toml:"pushover,omitempty"`
+	Gotify   GotifySettings   `toml:"gotify,omitempty"`
+}
+
+// NtfySettings configures the ntfy provider.
+type NtfySettings struct {
+	// Server is the ntfy server (default: https://ntfy.sh).
+	Server string `toml:"server,omitempty"`
+	// Topic is the topic to publish to. Required.
+	Topic string `toml:"topic,omitempty"`
+	// Token is an optional access token for protected topics.
+	Token string `toml:"token,omitempty"`
+}
+
+// PushoverSettings configures the Pushover provider.
+type PushoverSettings struct {
+	// Token is the application API token. Required.
+	Token string `toml:"token,omitempty"`
+	// User is the user or group key. Required.
+	User string `toml:"user,omitempty"`
+}
+
+// GotifySettings configures the Gotify provider.
+type GotifySettings struct {
+	// Server is the Gotify server URL. Required.
+	Server string `toml:"server,omitempty"`
+	// Token is the application token. Required.
+	Token string `toml:"token,omitempty"`
+	// Priority is the message priority (default: 5).
+	Priority int `toml:"priority,omitzero"`
+}
+
+// GetAfter returns how long a session waits before a push.
+func (p PushSettings) GetAfter() time.Duration {
+	if p.AfterMinutes <= 0 {
+		return defaultPushAfter
+	}
+	return time.Duration(p.AfterMinutes) * time.Minute
+}
+
+// GetPushSettings returns [notifications.push] with env var references in
+// servers, topics, tokens and keys expanded.
+func GetPushSettings() PushSettings {
+	p := GetNotificationsSettings().Push
+	p.Provider = strings.ToLower(strings.TrimSpace(p.Provider))
+	for _, s := range []*string{
+		&p.Ntfy.Server, &p.Ntfy.Topic, &p.Ntfy.Token,
+		&p.Pushover.Token, &p.Pushover.User,
+		&p.Gotify.Server, &p.Gotify.Token,
+	} {
+		*s = os.ExpandEnv(*s)
+	}
+	return p
+}
+
+// PushMessage is one notification.
+type PushMessage struct {
+	Title string
+	Body  string
+}
+
+// Validate reports a missing or misconfigured provider.
+func (p PushSettings) Validate() error {
+	switch p.Provider {
+	case "":
+		return fmt.Errorf("no push provider configured")
+	case PushProviderNtfy:
+		if p.Ntfy.Topic == "" {
+			return fmt.Errorf("[notifications.push.ntfy] topic is required")
+		}
+	case PushProviderPushover:
+		if p.Pushover.Token == "" || p.Pushover.User == "" {
+			return fmt.Errorf("[notifications.push.pushover] token and user are required")
+		}
+	case PushProviderGotify:
+		if p.Gotify.Server == "" || p.Gotify.Token == "" {
+			return fmt.Errorf("[notifications.push.gotify] server and token are required")
+		}
+	default:
+		return fmt.Errorf("unknown push provider %q (want ntfy, pushover or gotify)", p.Provider)
+	}
+	return nil
+}
+
+// SendPush delivers msg through the configured provider.
+func SendPush(ctx context.Context, p PushSettings, msg PushMessage) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	var req *http.Request
+	var err error
+	switch p.Provider {
+	case PushProviderNtfy:
+		server := strings.TrimRight(p.Ntfy.Server, "/")
+		if server == "" {
+			server = defaultNtfyServer
+		}
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, server+"/"+url.PathEscape(p.Ntfy.Topic), strings.NewReader(msg.Body))
+		if err == nil {
+			req.Header.Set("Title", msg.Title)
+			req.Header.Set("Tags", "hourglass")
+			if p.Ntfy.Token != "" {
+				req.Header.Set("Authorization", "Bearer "+p.Ntfy.Token)
+			}
+		}
+	case PushProviderPushover:
+		form := url.Values{"token": {p.Pushover.Token}, "user": {p.Pushover.User}, "title": {msg.Title}, "message": {msg.Body}}
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, pushoverAPI, strings.NewReader(form.Encode()))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	case PushProviderGotify:
+		priority := p.Gotify.Priority
+		if priority == 0 {
+			priority = 5
+		}
+		form := url.Values{"title": {msg.Title}, "message": {msg.Body}, "priority": {strconv.Itoa(priority)}}
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(p.Gotify.Server, "/")+"/message", strings.NewReader(form.Encode()))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.Header.Set("X-Gotify-Key", p.Gotify.Token)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	resp, err := pushHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned %s", p.Provider, resp.Status)
+	}
+	return nil
+}
+
+// waitingPush tracks one session's current waiting stretch.
+type waitingPush struct {
+	since time.Time
+	sent  bool
+}
+
+// waitingPushes holds waiting stretches by profile and session ID.
+var (
+	waitingPushesMu sync.Mutex
+	waitingPushes   = map[string]*waitingPush{}
+)
+
+// dueWaitingPush is a session whose push is due, with how long it waited.
+type dueWaitingPush struct {
+	inst   *Instance
+	waited time.Duration
+}
+
+// dueWaitingPushes records which instances are waiting at now and returns
+// those waiting for at least after that were not pushed yet, marking them
+// sent. Sessions that left waiting are forgotten, which re-arms them.
+func dueWaitingPushes(profile string, instances []*Instance, after time.Duration, now time.Time) []dueWaitingPush {
+	waitingPushesMu.Lock()
+	defer waitingPushesMu.Unlock()
+	prefix := profile + "\x00"
+	seen := make(map[string]bool, len(instances))
+	var due []dueWaitingPush
+	for _, inst := range instances {
+		if inst == nil || inst.GetStatusThreadSafe() != StatusWaiting {
+			continue
+		}
+		key := prefix + inst.ID
+		seen[key] = true
+		w := waitingPushes[key]
+		if w == nil {
+			w = &waitingPush{since: now}
+			waitingPushes[key] = w
+		}
+		if !w.sent && now.Sub(w.since) >= after {
+			w.sent = true
+			due = append(due, dueWaitingPush{inst, now.Sub(w.since)})
+		}
+	}
+	for key := range waitingPushes {
+		if strings.HasPrefix(key, prefix) && !seen[key] {
+			delete(waitingPushes, key)
+		}
+	}
+	return due
+}
+
+// CheckWaitingPushes sends a push for every session in instances that has
+// been waiting for [notifications.push] after_minutes. With a
+// [notifications.digest] window, due sessions are collected instead and one
+// push lists those still waiting once the window has passed. Call it once per
+// status sweep; pushes are sent in the background and failures are logged.
+func CheckWaitingPushes(profile string, instances []*Instance, now time.Time) int {
+	settings := GetPushSettings()
+	if settings.Provider == "" {
+		return 0
+	}
+	due := dueWaitingPushes(profile, instances, settings.GetAfter(), now)
+
+	type pendingPush struct {
+		session string
+		msg     PushMessage
+	}
+	var pushes []pendingPush
+	if window := GetNotificationsSettings().Digest.PushWindow(); window > 0 {
+		if msg, ok := waitingPushDigest(profile, instances, due, window, now); ok {
+			pushes = append(pushes, pendingPush{"digest", msg})
+		}
+	} else {
+		for _, d := range due {
+			pushes = append(pushes, pendingPush{d.inst.ID, PushMessage{
+				Title: fmt.Sprintf("%s is waiting", d.inst.Title),
+				Body:  waitingPushBody(d.inst, d.waited),
+			}})
+		}
+	}
+	if len(pushes) == 0 {
+		return 0
+	}
+	if err := settings.Validate(); err != nil {
+		if _, warned := invalidEventHookWarned.LoadOrStore("push\x00"+err.Error(), true); !warned {
+			eventHookLog.Warn("push_invalid", slog.String("error", err.Error()))
+		}
+		return 0
+	}
+	for _, p := range pushes {
+		go func(id string, msg PushMessage) {
+			ctx, cancel := context.WithTimeout(context.Background(), pushTimeout)
+			defer cancel()
+			if err := SendPush(ctx, settings, msg); err != nil {
+				eventHookLog.Warn("push_failed", slog.String("session", id), slog.String("provider", settings.Provider), slog.String("error", err.Error()))
+				return
+			}
+			eventHookLog.Debug("push_sent", slog.String("session", id), slog.String("provider", settings.Provider))
+		}(p.session, p.msg)
+	}
+	return len(pushes)
+}
+
+// pushDigest collects due waiting pushes by profile for
+// [notifications.digest].
+var pushDigest = newNotifyDigest()
+
+// waitingPushDigest adds due to the profile's digest and, once its window
+// has passed, returns one push listing the sessions in it that are still
+// waiting. A digest whose sessions all moved on is dropped.
+func waitingPushDigest(profile string, instances []*Instance, due []dueWaitingPush, window time.Duration, now time.Time) (PushMessage, bool) {
+	key := profile + "\x00"
+	for _, d := range due {
+		pushDigest.add(key, profile, nil, now, digestItem{id: d.inst.ID})
+	}
+	batches := pushDigest.takeDue(key, window, now)
+	if len(batches) == 0 {
+		return PushMessage{}, false
+	}
+	byID := make(map[string]*Instance, len(instances))
+	for _, inst := range instances {
+		if inst != nil {
+			byID[inst.ID] = inst
+		}
+	}
+
+	waitingPushesMu.Lock()
+	var waiting []dueWaitingPush
+	for _, item := range batches[0].items {
+		inst := byID[item.id]
+		w := waitingPushes[key+item.id]
+		if inst == nil || w == nil || inst.GetStatusThreadSafe() != StatusWaiting {
+			continue
+		}
+		waiting = append(waiting, dueWaitingPush{inst, now.Sub(w.since)})
+	}
+	waitingPushesMu.Unlock()
+
+	switch len(waiting) {
+	case 0:
+		return PushMessage{}, false
+	case 1:
+		return PushMessage{
+			Title: fmt.Sprintf("%s is waiting", waiting[0].inst.Title),
+			Body:  waitingPushBody(waiting[0].inst, waiting[0].waited),
+		}, true
+	}
+	lines := make([]string, 0, len(waiting))
+	for _, d := range waiting {
+		lines = append(lines, d.inst.Title+": "+waitingPushBody(d.inst, d.waited))
+	}
+	return PushMessage{
+		Title: fmt.Sprintf("%d sessions need attention", len(waiting)),
+		Body:  strings.Join(lines, "\n"),
+	}, true
+}
+
+func waitingPushBody(inst *Instance, waited time.Duration) string {
+	body := fmt.Sprintf("Waiting for input for %d min", int(waited.Round(time.Minute).Minutes()))
+	var where []string
+	if inst.GroupPath != "" {
+		where = append(where, inst.GroupPath)
+	}
+	if inst.Tool != "" {
+		where = append(where, inst.Tool)
+	}
+	if len(where) > 0 {
+		body += " (" + strings.Join(where, ", ") + ")"
+	}
+	return body
+}
@@ -0,0 +1,99 @@
+package session
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type pushRoundTripper func(*http.Request) (*http.Response, error)
+
+func (f pushRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+// capturePush swaps pushHTTPClient for one that records requests.
+func capturePush(t *testing.T) *[]*http.Request {
+	t.Helper()
+	var reqs []*http.Request
+	orig := pushHTTPClient
+	pushHTTPClient = &http.Client{Transport: pushRoundTripper(func(r *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(strings.NewReader(string(body)))
+		reqs = append(reqs, r)
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})}
+	t.Cleanup(func() { pushHTTPClient = orig })
+	return &reqs
+}
+
+func TestSendPush_Providers(t *testing.T) {
+	reqs := capturePush(t)
+	msg := PushMessage{Title: "api is waiting", Body: "Waiting for input for 5 min"}
+	settings := []PushSettings{
+		{Provider: PushProviderNtfy, Ntfy: NtfySettings{Topic: "deck", Token: "tk"}},
+		{Provider: PushProviderPushover, Pushover: PushoverSettings{Token: "app", User: "me"}},
+		{Provider: PushProviderGotify, Gotify: GotifySettings{Server: "https://gotify.example/", Token: "gk"}},
+	}
+	for _, s := range settings {
+		if err := SendPush(context.Background(), s, msg); err != nil {
+			t.Fatalf("%s: %v", s.Provider, err)
+		}
+	}
+	if len(*reqs) != 3 {
+		t.Fatalf("sent %d requests, want 3", len(*reqs))
+	}
+	ntfy, pushover, gotify := (*reqs)[0], (*reqs)[1], (*reqs)[2]
+	if ntfy.URL.String() != "https://ntfy.sh/deck" || ntfy.Header.Get("Title") != msg.Title || ntfy.Header.Get("Authorization") != "Bearer tk" {
+		t.Errorf("ntfy request: %s %v", ntfy.URL, ntfy.Header)
+	}
+	if body, _ := io.ReadAll(pushover.Body); pushover.URL.Host != "api.pushover.net" || !strings.Contains(string(body), "user=me") {
+		t.Errorf("pushover request: %s %s", pushover.URL, body)
+	}
+	if gotify.URL.String() != "https://gotify.example/message" || gotify.Header.Get("X-Gotify-Key") != "gk" {
+		t.Errorf("gotify request: %s %v", gotify.URL, gotify.Header)
+	}
+}
+
+func TestPushSettings_Validate(t *testing.T) {
+	for _, s := range []PushSettings{
+		{},
+		{Provider: "pager"},
+		{Provider: PushProviderNtfy},
+		{Provider: PushProviderPushover, Pushover: PushoverSettings{Token: "x"}},
+		{Provider: PushProviderGotify, Gotify: GotifySettings{Token: "x"}},
+	} {
+		if err := s.Validate(); err == nil {
+			t.Errorf("%+v accepted", s)
+		}
+	}
+}
+
+func TestDueWaitingPushes_OncePerWaitingStretch(t *testing.T) {
+	inst := &Instance{ID: "push-1", Status: StatusWaiting}
+	instances := []*Instance{inst}
+	start := time.Unix(1_700_000_000, 0)
+	after := 5 * time.Minute
+	t.Cleanup(func() { dueWaitingPushes("push-test", nil, after, start) })
+
+	if due := dueWaitingPushes("push-test", instances, after, start); len(due) != 0 {
+		t.Fatalf("pushed at start of waiting: %v", due)
+	}
+	due := dueWaitingPushes("push-test", instances, after, start.Add(after))
+	if len(due) != 1 || due[0].waited != after {
+		t.Fatalf("due after threshold = %+v", due)
+	}
+	if due := dueWaitingPushes("push-test", instances, after, start.Add(time.Hour)); len(due) != 0 {
+		t.Fatal("pushed twice for one waiting stretch")
+	}
+
+	// Leaving waiting re-arms the session.
+	inst.Status = StatusRunning
+	dueWaitingPushes("push-test", instances, after, start.Add(61*time.Minute))
+	inst.Status = StatusWaiting
+	dueWaitingPushes("push-test", instances, after, start.Add(62*time.Minute))
+	if due := dueWaitingPushes("push-test", instances, after, start.Add(67*time.Minute)); len(due) != 1 {
+		t.Errorf("re-armed session not pushed: %v", due)
+	}
+}
@@ -0,0 +1,333 @@
+package session
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/git"
+)
+
+// Relocation search bounds: candidate folders are looked for this many
+// levels below each search root, stopping after relocationMaxDirs visited
+// directories so a search from $HOME stays interactive.
+const (
+	relocationMaxDepth      = 4
+	relocationMaxDirs       = 20000
+	relocationMaxCandidates = 10
+	relocationMaxRemoteDirs = 200
+)
+
+// relocationSkipDirs are never descended into while searching.
+var relocationSkipDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"Library":      true,
+}
+
+// markProjectPathMissing records whether a local session's project directory
+// is gone, so list/show/TUI can offer a relocation instead of failing on
+// start. SSH and multi-repo sessions are skipped: their paths are remote or
+// temporary.
+func (i *Instance) markProjectPathMissing() {
+	i.projectPathMissing = false
+	if i.ProjectPath == "" || i.SSHHost != "" || i.MultiRepoEnabled {
+		return
+	}
+	if _, err := os.Stat(i.ProjectPath); os.IsNotExist(err) {
+		i.projectPathMissing = true
+		sessionLog.Debug("project_path_missing",
+			slog.String("id", i.ID),
+			slog.String("title", i.Title),
+			slog.String("path", i.ProjectPath))
+	}
+}
+
+// ProjectPathMissing reports whether the session's project directory was
+// missing when it was loaded (or last relocated).
+func (i *Instance) ProjectPathMissing() bool {
+	return i.projectPathMissing
+}
+
+// RelocationCandidate is a folder a missing project may have moved to.
+type RelocationCandidate struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"` // "same name", "same git remote" or both
+}
+
+// FindRelocationCandidates looks for where a session's missing project went:
+// folders with the same name, or repositories with the same git remote when
+// the remote is still known from an existing worktree/repo root. roots
+// defaults to the nearest existing ancestor of the old path, its parent and
+// the home directory. Remote matches sort first; otherwise shallower wins.
+func FindRelocationCandidates(inst *Instance, roots []string) []RelocationCandidate {
+	if inst == nil || inst.ProjectPath == "" {
+		return nil
+	}
+	oldPath := filepath.Clean(inst.ProjectPath)
+	name := filepath.Base(oldPath)
+	if len(roots) == 0 {
+		roots = defaultRelocationRoots(oldPath)
+	}
+	remote := relocationRemote(inst)
+
+	type found struct {
+		RelocationCandidate
+		depth int
+		score int
+	}
+	var hits []found
+	seen := make(map[string]bool)
+	visited, remoteChecks := 0, 0
+
+	for _, root := range roots {
+		root = filepath.Clean(ExpandPath(root))
+		type entry struct {
+			path  string
+			depth int
+		}
+		queue := []entry{{root, 0}}
+		for len(queue) > 0 && visited < relocationMaxDirs {
+			cur := queue[0]
+			queue = queue[1:]
+			if seen[cur.path] {
+				continue
+			}
+			seen[cur.path] = true
+			visited++
+
+			if cur.path != oldPath && cur.depth > 0 {
+				var reasons []string
+				score := 0
+				if remote != "" && remoteChecks < relocationMaxRemoteDirs && isDir(filepath.Join(cur.path, ".git")) {
+					remoteChecks++
+					if url, err := git.GetRemoteURL(cur.path); err == nil && sameRemote(url, remote) {
+						reasons = append(reasons, "same git remote")
+						score += 2
+					}
+				}
+				if filepath.Base(cur.path) == name {
+					reasons = append([]string{"same name"}, reasons...)
+					score++
+				}
+				if score > 0 {
+					hits = append(hits, found{RelocationCandidate{cur.path, strings.Join(reasons, ", ")}, cur.depth, score})
+				}
+			}
+
+			if cur.depth >= relocationMaxDepth {
+				continue
+			}
+			entries, err := os.ReadDir(cur.path)
+			if err != nil {
+				continue
+			}
+			for _, e := range entries {
+				n := e.Name()
+				if !e.IsDir() || strings.HasPrefix(n, ".") || relocationSkipDirs[n] {
+					continue
+				}
+				queue = append(queue, entry{filepath.Join(cur.path, n), cur.depth + 1})
+			}
+		}
+	}
+
+	sort.SliceStable(hits, func(a, b int) bool {
+		if hits[a].score != hits[b].score {
+			return hits[a].score > hits[b].score
+		}
+		return hits[a].depth < hits[b].depth
+	})
+	var out []RelocationCandidate
+	for _, h := range hits {
+		if len(out) == relocationMaxCandidates {
+			break
+		}
+		out = append(out, h.RelocationCandidate)
+	}
+	return out
+}
+
+// defaultRelocationRoots returns the nearest existing ancestor of oldPath,
+// its parent and the home directory, deduplicated.
+func defaultRelocationRoots(oldPath string) []string {
+	var roots []string
+	add := func(p string) {
+		for _, r := range roots {
+			if r == p {
+				return
+			}
+		}
+		roots = append(roots, p)
+	}
+	dir := filepath.Dir(oldPath)
+	for !isDir(dir) && filepath.Dir(dir) != dir {
+		dir = filepath.Dir(dir)
+	}
+	add(dir)
+	if parent := filepath.Dir(dir); parent != dir {
+		add(parent)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		add(home)
+	}
+	return roots
+}
+
+// relocationRemote returns the git remote of the missing project when a
+// related checkout (the worktree's repo root, or the worktree itself) still
+// exists.
+func relocationRemote(inst *Instance) string {
+	for _, dir := range []string{inst.WorktreeRepoRoot, inst.WorktreePath} {
+		if dir == "" || !isDir(dir) {
+			continue
+		}
+		if url, err := git.GetRemoteURL(dir); err == nil && url != "" {
+			return url
+		}
+	}
+	return ""
+}
+
+// sameRemote compares remote URLs ignoring a trailing ".git" or slash.
+func sameRemote(a, b string) bool {
+	norm := func(s string) string {
+		return strings.TrimSuffix(strings.TrimSuffix(strings.TrimSpace(s), "/"), ".git")
+	}
+	return norm(a) == norm(b)
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// Relocation is the outcome of RelocateSessions.
+type Relocation struct {
+	OldPath  string      // moved directory (may be an ancestor of the project path)
+	NewPath  string      // where it lives now
+	Sessions []*Instance // sessions whose paths were rewritten
+	Warnings []string
+}
+
+// RelocateSessions points sessions at a project that moved: inst's missing
+// ProjectPath becomes newPath, and every session path under the moved
+// directory (project, worktree, worktree repo root, additional and parent
+// paths) is rewritten too. When the folder name is unchanged and the old
+// parent is gone as well, the move is widened to that parent, so sibling
+// projects follow along. Claude history is migrated and git worktrees are
+// repaired; failures there are warnings, the paths are updated regardless.
+// Nothing is saved; callers persist instances (and group default paths via
+// GroupTree.RelocateDefaultPaths).
+func RelocateSessions(instances []*Instance, inst *Instance, newPath string) (*Relocation, error) {
+	if inst == nil || inst.ProjectPath == "" {
+		return nil, fmt.Errorf("session has no project path")
+	}
+	newPath = ExpandPath(newPath)
+	if abs, err := filepath.Abs(newPath); err == nil {
+		newPath = abs
+	}
+	if !isDir(newPath) {
+		return nil, fmt.Errorf("path is not a directory: %s", newPath)
+	}
+	oldPath := filepath.Clean(inst.ProjectPath)
+	if oldPath == newPath {
+		return nil, fmt.Errorf("session is already at %s", newPath)
+	}
+	oldRoot, newRoot := movedRoots(oldPath, newPath)
+	rel := &Relocation{OldPath: oldRoot, NewPath: newRoot}
+
+	home, _ := os.UserHomeDir()
+	repairs := make(map[string][]string) // repo root -> worktrees
+	for _, s := range instances {
+		prevProject := s.ProjectPath
+		changed := false
+		rewrite := func(p *string) {
+			if np, ok := RewritePathPrefix(*p, oldRoot, newRoot); ok {
+				*p = np
+				changed = true
+			}
+		}
+		rewrite(&s.ProjectPath)
+		rewrite(&s.WorktreePath)
+		rewrite(&s.WorktreeRepoRoot)
+		rewrite(&s.ParentProjectPath)
+		for k := range s.AdditionalPaths {
+			rewrite(&s.AdditionalPaths[k])
+		}
+		if !changed {
+			continue
+		}
+		rel.Sessions = append(rel.Sessions, s)
+		s.markProjectPathMissing()
+
+		if home != "" && IsClaudeCompatible(s.Tool) && prevProject != s.ProjectPath {
+			if err := MigrateClaudeProjectDir(home, prevProject, s.ProjectPath, false); err != nil {
+				rel.Warnings = append(rel.Warnings, fmt.Sprintf("%s: %v", s.Title, err))
+			}
+		}
+		if s.WorktreePath != "" && s.WorktreeRepoRoot != "" {
+			repairs[s.WorktreeRepoRoot] = append(repairs[s.WorktreeRepoRoot], s.WorktreePath)
+		}
+	}
+
+	for repo, worktrees := range repairs {
+		if err := git.RepairWorktrees(repo, worktrees...); err != nil {
+			rel.Warnings = append(rel.Warnings, fmt.Sprintf("worktrees of %s: %v", repo, err))
+		}
+	}
+	sessionLog.Info("sessions_relocated",
+		slog.String("old_path", oldRoot),
+		slog.String("new_path", newRoot),
+		slog.Int("sessions", len(rel.Sessions)))
+	return rel, nil
+}
+
+// movedRoots widens oldPath→newPath to the directory that actually moved:
+// while the last path elements agree and the old parent no longer exists,
+// the parent moved too.
+func movedRoots(oldPath, newPath string) (string, string) {
+	for filepath.Base(oldPath) == filepath.Base(newPath) {
+		oldParent, newParent := filepath.Dir(oldPath), filepath.Dir(newPath)
+		if oldParent == oldPath || newParent == newPath || oldParent == newParent {
+			break
+		}
+		if _, err := os.Stat(oldParent); !os.IsNotExist(err) {
+			break
+		}
+		oldPath, newPath = oldParent, newParent
+	}
+	return oldPath, newPath
+}
+
+// RewritePathPrefix replaces the oldRoot prefix of path with newRoot. ok is
+// false when path is not oldRoot or below it.
+func RewritePathPrefix(path, oldRoot, newRoot string) (string, bool) {
+	if path == "" || oldRoot == "" {
+		return path, false
+	}
+	clean := filepath.Clean(path)
+	if clean == oldRoot {
+		return newRoot, true
+	}
+	if rest, ok := strings.CutPrefix(clean, oldRoot+string(filepath.Separator)); ok {
+		return filepath.Join(newRoot, rest), true
+	}
+	return path, false
+}
+
+// RelocateDefaultPaths rewrites group default paths under a moved directory
+// and returns how many changed.
+func (t *GroupTree) RelocateDefaultPaths(oldRoot, newRoot string) int {
+	n := 0
+	for _, g := range t.Groups {
+		if np, ok := RewritePathPrefix(g.DefaultPath, oldRoot, newRoot); ok {
+			g.DefaultPath = np
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,118 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRewritePathPrefix(t *testing.T) {
+	tests := []struct {
+		path, want string
+		ok         bool
+	}{
+		{"/old/app", "/new/app", true},
+		{"/old/app/sub/dir", "/new/app/sub/dir", true},
+		{"/old/application", "/old/application", false},
+		{"/elsewhere", "/elsewhere", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := RewritePathPrefix(tt.path, "/old/app", "/new/app")
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("RewritePathPrefix(%q) = %q, %v; want %q, %v", tt.path, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestMarkProjectPathMissing(t *testing.T) {
+	dir := t.TempDir()
+	present := &Instance{ProjectPath: dir}
+	gone := &Instance{ProjectPath: filepath.Join(dir, "moved-away")}
+	remote := &Instance{ProjectPath: "/does/not/exist", SSHHost: "box"}
+	for _, inst := range []*Instance{present, gone, remote} {
+		inst.markProjectPathMissing()
+	}
+	if present.ProjectPathMissing() || remote.ProjectPathMissing() {
+		t.Error("existing and SSH sessions must not be flagged")
+	}
+	if !gone.ProjectPathMissing() {
+		t.Error("missing project path not flagged")
+	}
+}
+
+func TestRelocateSessions_WidensToMovedParent(t *testing.T) {
+	channelsTestEnv(t)
+	base := t.TempDir()
+	oldRoot := filepath.Join(base, "code", "work")
+	newRoot := filepath.Join(base, "projects", "work")
+	for _, d := range []string{filepath.Join(newRoot, "api"), filepath.Join(newRoot, "web")} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(base, "code"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	api := &Instance{Title: "api", Tool: "shell", ProjectPath: filepath.Join(oldRoot, "api")}
+	web := &Instance{Title: "web", Tool: "shell", ProjectPath: filepath.Join(oldRoot, "web"),
+		AdditionalPaths: []string{filepath.Join(oldRoot, "api")}}
+	other := &Instance{Title: "other", Tool: "shell", ProjectPath: base}
+	instances := []*Instance{api, web, other}
+	for _, inst := range instances {
+		inst.markProjectPathMissing()
+	}
+
+	rel, err := RelocateSessions(instances, api, filepath.Join(newRoot, "api"))
+	if err != nil {
+		t.Fatalf("RelocateSessions: %v", err)
+	}
+	if rel.OldPath != oldRoot || rel.NewPath != newRoot {
+		t.Errorf("moved roots = %s -> %s, want %s -> %s", rel.OldPath, rel.NewPath, oldRoot, newRoot)
+	}
+	if len(rel.Sessions) != 2 {
+		t.Fatalf("relocated %d sessions, want 2 (api, web)", len(rel.Sessions))
+	}
+	if web.ProjectPath != filepath.Join(newRoot, "web") || web.AdditionalPaths[0] != filepath.Join(newRoot, "api") {
+		t.Errorf("sibling not rewritten: %s %v", web.ProjectPath, web.AdditionalPaths)
+	}
+	if api.ProjectPathMissing() || web.ProjectPathMissing() {
+		t.Error("relocated sessions still flagged missing")
+	}
+	if other.ProjectPath != base {
+		t.Errorf("unrelated session changed: %s", other.ProjectPath)
+	}
+
+	tree := NewGroupTree(nil)
+	tree.CreateGroup("work").DefaultPath = filepath.Join(oldRoot, "api")
+	if n := tree.RelocateDefaultPaths(rel.OldPath, rel.NewPath); n != 1 || tree.Groups["work"].DefaultPath != filepath.Join(newRoot, "api") {
+		t.Errorf("group default path = %q (%d changed)", tree.Groups["work"].DefaultPath, n)
+	}
+}
+
+func TestRelocateSessions_RejectsMissingTarget(t *testing.T) {
+	inst := &Instance{ProjectPath: "/old/app"}
+	if _, err := RelocateSessions([]*Instance{inst}, inst, filepath.Join(t.TempDir(), "nope")); err == nil {
+		t.Fatal("expected error for a target that does not exist")
+	}
+	if inst.ProjectPath != "/old/app" {
+		t.Errorf("path changed on error: %s", inst.ProjectPath)
+	}
+}
+
+func TestFindRelocationCandidates_ByName(t *testing.T) {
+	base := t.TempDir()
+	want := filepath.Join(base, "archive", "2026", "app")
+	for _, d := range []string{want, filepath.Join(base, "other"), filepath.Join(base, ".hidden", "app")} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	inst := &Instance{ProjectPath: filepath.Join(base, "app")}
+
+	got := FindRelocationCandidates(inst, []string{base})
+	if len(got) != 1 || got[0].Path != want || got[0].Reason != "same name" {
+		t.Fatalf("candidates = %+v, want only %s", got, want)
+	}
+}
@@ -61,6 +61,7 @@ func SweepInboxesForChildSession(childSessionID string) (int, error) {
 	ForgetConsumedTurnsForChild(childSessionID)      // consumed-turn ledgers (this id as a CHILD)
 	ResetStopBlockBudget(childSessionID)             // Stop-hook block budget (if it was a parent)
 	sweepParentSideArtifacts(childSessionID)         // audit B5: this id's OWN parent-side files
+	RemoveMailbox(childSessionID)                    // session send-to mailbox
 
 	return totalDropped, nil
 }
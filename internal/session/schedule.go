@@ -0,0 +1,287 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// scheduleCheckInterval is how often the TUI and the notify daemon look for
+// due schedules. Cron has minute resolution, so a run lands within this much
+// of its minute.
+const scheduleCheckInterval = 20 * time.Second
+
+// CronSpec is a parsed five-field cron expression: minute, hour, day of
+// month, month, day of week. Fields take numbers, names (jan, mon), "*",
+// ranges, steps and lists, as in crontab(5). As in cron, when both day
+// fields are restricted a day matching either one matches.
+type CronSpec struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+}
+
+var cronMacros = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    []string // names[i] is value min+i
+}
+
+var cronFields = [5]cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+// ParseCronSpec parses a five-field cron expression or one of @hourly,
+// @daily, @weekly, @monthly and @yearly.
+func ParseCronSpec(spec string) (*CronSpec, error) {
+	expr := strings.TrimSpace(spec)
+	if m, ok := cronMacros[strings.ToLower(expr)]; ok {
+		expr = m
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron spec %q: want 5 fields (minute hour day-of-month month day-of-week)", spec)
+	}
+	var bits [5]uint64
+	for i, f := range fields {
+		b, err := parseCronField(f, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron spec %q: %s: %w", spec, cronFields[i].name, err)
+		}
+		bits[i] = b
+	}
+	c := &CronSpec{
+		minute: bits[0],
+		hour:   bits[1],
+		dom:    bits[2],
+		month:  bits[3],
+		dow:    bits[4],
+		domAny: strings.HasPrefix(fields[2], "*"),
+		dowAny: strings.HasPrefix(fields[4], "*"),
+	}
+	// 7 is Sunday too.
+	if c.dow&(1<<7) != 0 {
+		c.dow = c.dow&^(1<<7) | 1
+	}
+	return c, nil
+}
+
+// parseCronField turns one field into a bitmask of the values it matches.
+func parseCronField(field string, f cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepStr)
+			}
+			step = n
+		}
+		lo, hi := f.min, f.max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			a, b, _ := strings.Cut(rng, "-")
+			var err error
+			if lo, err = cronValue(a, f); err != nil {
+				return 0, err
+			}
+			if hi, err = cronValue(b, f); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range %q", rng)
+			}
+		default:
+			v, err := cronValue(rng, f)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			if !hasStep {
+				hi = v
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// cronValue parses a number or name within f's bounds.
+func cronValue(s string, f cronField) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return f.min + i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("%d out of range %d-%d", v, f.min, f.max)
+	}
+	return v, nil
+}
+
+// Next returns the first matching minute after t, in t's location, or the
+// zero time when nothing matches within five years (e.g. "0 0 30 2 *").
+func (c *CronSpec) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case c.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (c *CronSpec) dayMatches(t time.Time) bool {
+	domOK := c.dom&(1<<uint(t.Day())) != 0
+	dowOK := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domAny || c.dowAny {
+		return domOK && dowOK
+	}
+	return domOK || dowOK
+}
+
+// AddSchedule validates spec and stores a schedule sending message to inst,
+// first due at the next matching minute after now.
+func AddSchedule(db *statedb.StateDB, spec string, inst *Instance, message string, now time.Time) (*statedb.ScheduleRow, error) {
+	c, err := ParseCronSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	next := c.Next(now)
+	if next.IsZero() {
+		return nil, fmt.Errorf("cron spec %q never matches", spec)
+	}
+	if strings.TrimSpace(message) == "" {
+		return nil, fmt.Errorf("message is required")
+	}
+	r := &statedb.ScheduleRow{
+		ID:         "sch-" + randomString(8),
+		Spec:       strings.TrimSpace(spec),
+		InstanceID: inst.ID,
+		Message:    message,
+		CreatedAt:  now,
+		NextRun:    next,
+	}
+	if err := db.SaveSchedule(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// RunDueSchedules sends every schedule in db that is due at now and records
+// the outcome. A schedule that was missed while nothing was running fires
+// once, then resumes at its next match after now. It returns how many runs
+// this call performed.
+func RunDueSchedules(db *statedb.StateDB, now time.Time, send func(instanceID, message string) error) int {
+	rows, err := db.LoadSchedules()
+	if err != nil {
+		maintLog.Warn("schedule_load_failed", slog.String("error", err.Error()))
+		return 0
+	}
+	ran := 0
+	for _, r := range rows {
+		if r.NextRun.After(now) {
+			continue
+		}
+		c, err := ParseCronSpec(r.Spec)
+		if err != nil {
+			continue
+		}
+		next := c.Next(now)
+		if next.IsZero() {
+			continue
+		}
+		claimed, err := db.ClaimScheduleRun(r.ID, r.NextRun, next)
+		if err != nil || !claimed {
+			continue
+		}
+		ran++
+		status, errMsg := "ok", ""
+		if err := send(r.InstanceID, r.Message); err != nil {
+			status, errMsg = "error", err.Error()
+			maintLog.Warn("schedule_run_failed",
+				slog.String("schedule", r.ID),
+				slog.String("instance", r.InstanceID),
+				slog.String("error", errMsg))
+		}
+		if err := db.RecordScheduleRun(r.ID, now, status, errMsg); err != nil {
+			maintLog.Warn("schedule_record_failed", slog.String("schedule", r.ID), slog.String("error", err.Error()))
+		}
+	}
+	return ran
+}
+
+// scheduleSender delivers a scheduled message the way `session send` does.
+func scheduleSender(profile string) func(instanceID, message string) error {
+	return func(instanceID, message string) error {
+		return SendSessionMessageReliable(profile, instanceID, message)
+	}
+}
+
+// StartScheduleWorker runs the profile's due schedules every
+// scheduleCheckInterval until ctx ends. The TUI starts it; the notify daemon
+// runs schedules from its own poll loop. Both may run at once: each run is
+// claimed in statedb first, so it is sent only once.
+func StartScheduleWorker(ctx context.Context, profile string) {
+	go func() {
+		storage, err := NewStorageWithProfile(profile)
+		if err != nil {
+			maintLog.Warn("schedule_worker_storage_failed", slog.String("error", err.Error()))
+			return
+		}
+		defer storage.Close()
+		db := storage.GetDB()
+		if db == nil {
+			return
+		}
+
+		ticker := time.NewTicker(scheduleCheckInterval)
+		defer ticker.Stop()
+		for {
+			RunDueSchedules(db, time.Now(), scheduleSender(profile))
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
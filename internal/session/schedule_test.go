@@ -0,0 +1,113 @@
+package session
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+func TestCronSpec_Next(t *testing.T) {
+	// Friday 2026-01-02 10:30 UTC.
+	from := time.Date(2026, 1, 2, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"0 9 * * 1-5", time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)},    // next weekday 9:00 is Monday
+		{"*/15 * * * *", time.Date(2026, 1, 2, 10, 45, 0, 0, time.UTC)}, // step
+		{"30 10 * * *", time.Date(2026, 1, 3, 10, 30, 0, 0, time.UTC)},  // "now" itself is not next
+		{"0 0 1 feb *", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},    // month name
+		{"0 12 15 * sun", time.Date(2026, 1, 4, 12, 0, 0, 0, time.UTC)}, // both days restricted: either matches
+		{"0 12 * * 7", time.Date(2026, 1, 4, 12, 0, 0, 0, time.UTC)},    // 7 is Sunday
+		{"5,10 8-9 * * *", time.Date(2026, 1, 3, 8, 5, 0, 0, time.UTC)}, // list and range
+		{"@weekly", time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC)},        // macro
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},    // leap day
+		{"0 0 30 2 *", time.Time{}},                                     // never
+	}
+	for _, tt := range tests {
+		c, err := ParseCronSpec(tt.spec)
+		if err != nil {
+			t.Errorf("ParseCronSpec(%q): %v", tt.spec, err)
+			continue
+		}
+		if got := c.Next(from); !got.Equal(tt.want) {
+			t.Errorf("Next(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestParseCronSpec_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "* * * foo *"} {
+		if _, err := ParseCronSpec(spec); err == nil {
+			t.Errorf("ParseCronSpec(%q) accepted", spec)
+		}
+	}
+}
+
+func TestRunDueSchedules_RunsOnceAndRecords(t *testing.T) {
+	db, err := statedb.Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2026, 1, 2, 8, 59, 30, 0, time.Local)
+	inst := &Instance{ID: "inst-1", Title: "api"}
+	ok, err := AddSchedule(db, "0 9 * * *", inst, "summarize CI", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	failing, err := AddSchedule(db, "0 9 * * *", &Instance{ID: "gone"}, "ping", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AddSchedule(db, "0 0 30 2 *", inst, "x", now); err == nil || !strings.Contains(err.Error(), "never matches") {
+		t.Fatalf("impossible spec: %v", err)
+	}
+
+	var sent []string
+	send := func(id, msg string) error {
+		if id == "gone" {
+			return errors.New("session not found")
+		}
+		sent = append(sent, id+":"+msg)
+		return nil
+	}
+	if n := RunDueSchedules(db, now, send); n != 0 {
+		t.Fatalf("ran %d schedules before they were due", n)
+	}
+
+	// Two hours late (nothing was running at 9:00): runs once, then moves to
+	// the next day.
+	late := now.Add(2 * time.Hour)
+	if n := RunDueSchedules(db, late, send); n != 2 {
+		t.Fatalf("ran %d, want 2", n)
+	}
+	if n := RunDueSchedules(db, late, send); n != 0 {
+		t.Fatalf("second pass ran %d, want 0", n)
+	}
+	if len(sent) != 1 || sent[0] != "inst-1:summarize CI" {
+		t.Fatalf("sent = %v", sent)
+	}
+
+	r, err := db.LoadSchedule(ok.ID)
+	if err != nil || r == nil {
+		t.Fatalf("LoadSchedule: %v %v", r, err)
+	}
+	if r.LastStatus != "ok" || !r.LastRun.Equal(late.Truncate(time.Second)) {
+		t.Errorf("last run = %v %q", r.LastRun, r.LastStatus)
+	}
+	if want := time.Date(2026, 1, 3, 9, 0, 0, 0, time.Local); !r.NextRun.Equal(want) {
+		t.Errorf("next run = %v, want %v", r.NextRun, want)
+	}
+	if r, _ := db.LoadSchedule(failing.ID); r.LastStatus != "error" || r.LastError != "session not found" {
+		t.Errorf("failing schedule = %q %q", r.LastStatus, r.LastError)
+	}
+}
@@ -13,6 +13,11 @@ import (
 // as `agent-deck session send` (default mode, no --no-wait).
 // It invokes the CLI command to keep behavior identical across callers.
 func SendSessionMessageReliable(profile, sessionRef, message string) error {
+	return sendSessionMessage(profile, sessionRef, message)
+}
+
+// sendSessionMessage runs `agent-deck session send` with extra flags.
+func sendSessionMessage(profile, sessionRef, message string, flags ...string) error {
 	sessionRef = strings.TrimSpace(sessionRef)
 	message = strings.TrimSpace(message)
 	if sessionRef == "" {
@@ -28,6 +33,7 @@ func SendSessionMessageReliable(profile, sessionRef, message string) error {
 		args = append(args, "-p", profile)
 	}
 	args = append(args, "session", "send", sessionRef, message, "-q")
+	args = append(args, flags...)
 
 	cmd := exec.Command(bin, args...)
 	var stderr bytes.Buffer
@@ -0,0 +1,31 @@
+package session
+
+// SendLintSettings configures the pre-send prompt checks run by
+// `agent-deck session send`. See internal/send/lint.go.
+type SendLintSettings struct {
+	// Enabled runs the checks on every send. Default: false.
+	Enabled bool `toml:"enabled,omitempty"`
+
+	// MinChars flags prompts shorter than this (after trimming). 0 uses the
+	// default of 3; -1 disables the check.
+	MinChars int `toml:"min_chars,omitzero"`
+
+	// MaxBytes flags prompts longer than this. 0 uses the tmux chunk size
+	// (4096), above which the prompt is pasted in several chunks; -1
+	// disables the check.
+	MaxBytes int `toml:"max_bytes,omitzero"`
+
+	// Placeholders are markers that suggest an unfinished prompt, matched
+	// as whole words, case-insensitively. Unset uses TODO, FIXME, TBD, XXX
+	// and "lorem ipsum"; an empty list disables the check.
+	Placeholders []string `toml:"placeholders,omitempty"`
+}
+
+// GetSendLintSettings returns the [send_lint] settings from config.toml.
+func GetSendLintSettings() SendLintSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return SendLintSettings{}
+	}
+	return config.SendLint
+}
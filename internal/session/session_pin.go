@@ -0,0 +1,314 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/platform"
+)
+
+// Pinned sessions are started at login by a per-session launchd agent
+// (macOS) or systemd user service (Linux), built like the conductor daemons.
+// The service runs `agent-deck -p <profile> session start <id> --if-stopped`
+// once; the unit file on disk is the only record that a session is pinned.
+
+// PinnedPlistLabel returns the launchd label for a pinned session.
+func PinnedPlistLabel(instanceID string) string {
+	return "com.agentdeck.session." + instanceID
+}
+
+// PinnedSystemdServiceName returns the systemd unit name for a pinned session.
+func PinnedSystemdServiceName(instanceID string) string {
+	return fmt.Sprintf("agent-deck-session-%s.service", instanceID)
+}
+
+// PinnedPlistPath returns where a pinned session's launchd plist is installed.
+func PinnedPlistPath(instanceID string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, "Library", "LaunchAgents", PinnedPlistLabel(instanceID)+".plist"), nil
+}
+
+// PinnedSystemdServicePath returns where a pinned session's systemd unit is
+// installed.
+func PinnedSystemdServicePath(instanceID string) (string, error) {
+	dir, err := SystemdUserDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, PinnedSystemdServiceName(instanceID)), nil
+}
+
+// pinnedStartArgs is the command line the pinned-session service runs.
+func pinnedStartArgs(instanceID, profile string) []string {
+	agentDeck := FindAgentDeck()
+	if agentDeck == "" {
+		agentDeck = "agent-deck"
+	}
+	return []string{agentDeck, "-p", profile, "session", "start", instanceID, "--if-stopped", "-q"}
+}
+
+// GeneratePinnedPlist returns the launchd plist that starts a session at
+// login. No KeepAlive: the start command exits once the session is up, and
+// the session outlives it in the tmux server.
+func GeneratePinnedPlist(instanceID, profile string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	logPath, err := logDataPath("pinned-" + instanceID + ".log")
+	if err != nil {
+		return "", fmt.Errorf("pinned session log path: %w", err)
+	}
+	dataBase, configBase, err := bridgeXDGBaseDirs()
+	if err != nil {
+		return "", err
+	}
+
+	var programArgs strings.Builder
+	for i, arg := range pinnedStartArgs(instanceID, profile) {
+		if i > 0 {
+			programArgs.WriteString("\n")
+		}
+		programArgs.WriteString("        <string>" + arg + "</string>")
+	}
+
+	plist := strings.ReplaceAll(pinnedSessionPlistTemplate, "__LABEL__", PinnedPlistLabel(instanceID))
+	plist = strings.ReplaceAll(plist, "__PROGRAM_ARGUMENTS__", programArgs.String())
+	plist = strings.ReplaceAll(plist, "__LOG_PATH__", logPath)
+	plist = strings.ReplaceAll(plist, "__HOME__", homeDir)
+	plist = strings.ReplaceAll(plist, "__XDG_DATA_HOME__", dataBase)
+	plist = strings.ReplaceAll(plist, "__XDG_CONFIG_HOME__", configBase)
+	plist = strings.ReplaceAll(plist, "__PATH__", buildDaemonPath(FindAgentDeck()))
+	return plist, nil
+}
+
+// GenerateSystemdPinnedService returns the systemd user unit that starts a
+// session at login. KillMode=process matters: the tmux server the start
+// command spawns stays in the unit's cgroup, and stopping the unit (unpin,
+// logout) must not take every session on that server down with it.
+func GenerateSystemdPinnedService(instanceID, profile, title string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	logPath, err := logDataPath("pinned-" + instanceID + ".log")
+	if err != nil {
+		return "", fmt.Errorf("pinned session log path: %w", err)
+	}
+	dataBase, configBase, err := bridgeXDGBaseDirs()
+	if err != nil {
+		return "", err
+	}
+
+	args := pinnedStartArgs(instanceID, profile)
+	execStart := make([]string, len(args))
+	for i, arg := range args {
+		// systemd splits ExecStart on unquoted whitespace.
+		execStart[i] = `"` + arg + `"`
+	}
+	xdgEnv := `Environment="XDG_DATA_HOME=` + dataBase + `"` +
+		"\n" + `Environment="XDG_CONFIG_HOME=` + configBase + `"`
+
+	// A newline in the title would end the Description= line.
+	title = strings.Join(strings.Fields(title), " ")
+	unit := strings.ReplaceAll(systemdPinnedSessionServiceTemplate, "__TITLE__", title)
+	unit = strings.ReplaceAll(unit, "__EXEC_START__", strings.Join(execStart, " "))
+	unit = strings.ReplaceAll(unit, "__LOG_PATH__", logPath)
+	unit = strings.ReplaceAll(unit, "__LOG_DIR__", filepath.Dir(logPath))
+	unit = strings.ReplaceAll(unit, "__HOME__", homeDir)
+	unit = strings.ReplaceAll(unit, "__XDG_ENV__", xdgEnv)
+	unit = strings.ReplaceAll(unit, "__PATH__", buildDaemonPath(FindAgentDeck()))
+	return unit, nil
+}
+
+// PinnedServicePath returns the installed unit/plist of a pinned session, or
+// "" when the session is not pinned.
+func PinnedServicePath(instanceID string) string {
+	var path string
+	var err error
+	switch platform.Detect() {
+	case platform.PlatformMacOS:
+		path, err = PinnedPlistPath(instanceID)
+	case platform.PlatformLinux, platform.PlatformWSL2:
+		path, err = PinnedSystemdServicePath(instanceID)
+	default:
+		return ""
+	}
+	if err != nil {
+		return ""
+	}
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// PinSession installs and enables the login service for inst in profile.
+// Returns the unit/plist path. Enabling does not start the session now.
+func PinSession(inst *Instance, profile string) (string, error) {
+	if inst.SSHHost != "" {
+		return "", fmt.Errorf("session %q runs on %s; only local sessions can be pinned", inst.Title, inst.SSHHost)
+	}
+	plat := platform.Detect()
+	switch plat {
+	case platform.PlatformMacOS:
+		return pinSessionLaunchd(inst.ID, profile)
+	case platform.PlatformLinux, platform.PlatformWSL2:
+		return pinSessionSystemd(inst.ID, profile, inst.Title)
+	default:
+		return "", fmt.Errorf("unsupported platform %s for pinned sessions", plat)
+	}
+}
+
+func pinSessionLaunchd(instanceID, profile string) (string, error) {
+	plistContent, err := GeneratePinnedPlist(instanceID, profile)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate plist: %w", err)
+	}
+	plistPath, err := PinnedPlistPath(instanceID)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(plistPath), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create LaunchAgents dir: %w", err)
+	}
+	_ = exec.Command("launchctl", "unload", plistPath).Run()
+	if err := os.WriteFile(plistPath, []byte(plistContent), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write plist: %w", err)
+	}
+	// Loading a RunAtLoad agent runs it once now; --if-stopped makes that a
+	// no-op for a running session.
+	if err := exec.Command("launchctl", "load", plistPath).Run(); err != nil {
+		return plistPath, fmt.Errorf("plist written but failed to load: %w", err)
+	}
+	return plistPath, nil
+}
+
+func pinSessionSystemd(instanceID, profile, title string) (string, error) {
+	unitContent, err := GenerateSystemdPinnedService(instanceID, profile, title)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate systemd unit: %w", err)
+	}
+	unitPath, err := PinnedSystemdServicePath(instanceID)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(unitPath), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create systemd user dir: %w", err)
+	}
+	if err := os.WriteFile(unitPath, []byte(unitContent), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write systemd unit: %w", err)
+	}
+	if !systemdUserAvailable() {
+		return unitPath, fmt.Errorf("unit written but systemd user session not available (common in containers/VMs without lingering)")
+	}
+	if err := exec.Command("systemctl", "--user", "daemon-reload").Run(); err != nil {
+		return unitPath, fmt.Errorf("unit written but daemon-reload failed: %w", err)
+	}
+	if err := exec.Command("systemctl", "--user", "enable", PinnedSystemdServiceName(instanceID)).Run(); err != nil {
+		return unitPath, fmt.Errorf("unit written but enable failed: %w", err)
+	}
+	return unitPath, nil
+}
+
+// UnpinSession removes a session's login service. Not being pinned is not an
+// error. Stopping the service never stops the session itself.
+func UnpinSession(instanceID string) error {
+	switch platform.Detect() {
+	case platform.PlatformMacOS:
+		plistPath, err := PinnedPlistPath(instanceID)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(plistPath); os.IsNotExist(err) {
+			return nil
+		}
+		_ = exec.Command("launchctl", "unload", plistPath).Run()
+		return os.Remove(plistPath)
+	case platform.PlatformLinux, platform.PlatformWSL2:
+		unitPath, err := PinnedSystemdServicePath(instanceID)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(unitPath); os.IsNotExist(err) {
+			return nil
+		}
+		_ = exec.Command("systemctl", "--user", "disable", "--now", PinnedSystemdServiceName(instanceID)).Run()
+		if err := os.Remove(unitPath); err != nil {
+			return err
+		}
+		_ = exec.Command("systemctl", "--user", "daemon-reload").Run()
+		return nil
+	default:
+		return nil
+	}
+}
+
+// pinnedSessionPlistTemplate is the launchd agent for a pinned session.
+const pinnedSessionPlistTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+    <key>Label</key>
+    <string>__LABEL__</string>
+
+    <key>ProgramArguments</key>
+    <array>
+__PROGRAM_ARGUMENTS__
+    </array>
+
+    <key>RunAtLoad</key>
+    <true/>
+
+    <key>StandardOutPath</key>
+    <string>__LOG_PATH__</string>
+
+    <key>StandardErrorPath</key>
+    <string>__LOG_PATH__</string>
+
+    <key>WorkingDirectory</key>
+    <string>__HOME__</string>
+
+    <key>EnvironmentVariables</key>
+    <dict>
+        <key>PATH</key>
+        <string>__PATH__</string>
+        <key>HOME</key>
+        <string>__HOME__</string>
+        <key>XDG_DATA_HOME</key>
+        <string>__XDG_DATA_HOME__</string>
+        <key>XDG_CONFIG_HOME</key>
+        <string>__XDG_CONFIG_HOME__</string>
+    </dict>
+</dict>
+</plist>
+`
+
+// systemdPinnedSessionServiceTemplate is the systemd user unit for a pinned
+// session.
+const systemdPinnedSessionServiceTemplate = `[Unit]
+Description=Agent Deck pinned session (__TITLE__)
+After=network.target
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+KillMode=process
+ExecStartPre=-/bin/mkdir -p "__LOG_DIR__"
+ExecStart=__EXEC_START__
+WorkingDirectory=__HOME__
+StandardOutput=append:__LOG_PATH__
+StandardError=append:__LOG_PATH__
+Environment="PATH=__PATH__"
+Environment="HOME=__HOME__"
+__XDG_ENV__
+
+[Install]
+WantedBy=default.target
+`
@@ -0,0 +1,74 @@
+package session
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateSystemdPinnedService(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, "data"))
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "cfg"))
+
+	unit, err := GenerateSystemdPinnedService("abc123", "work", "api\nserver")
+	if err != nil {
+		t.Fatalf("GenerateSystemdPinnedService: %v", err)
+	}
+	if strings.Contains(unit, "__") {
+		t.Errorf("unit still has placeholders:\n%s", unit)
+	}
+	for _, want := range []string{
+		"Description=Agent Deck pinned session (api server)\n",
+		"Type=oneshot\n",
+		"RemainAfterExit=yes\n",
+		// Stopping the unit must not kill the tmux server it spawned.
+		"KillMode=process\n",
+		`"-p" "work" "session" "start" "abc123" "--if-stopped" "-q"`,
+		`Environment="XDG_DATA_HOME=` + filepath.Join(home, "data") + `"`,
+		`Environment="XDG_CONFIG_HOME=` + filepath.Join(home, "cfg") + `"`,
+		"WantedBy=default.target",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("unit missing %q:\n%s", want, unit)
+		}
+	}
+}
+
+func TestGeneratePinnedPlist(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, "data"))
+
+	plist, err := GeneratePinnedPlist("abc123", "work")
+	if err != nil {
+		t.Fatalf("GeneratePinnedPlist: %v", err)
+	}
+	if strings.Contains(plist, "__") {
+		t.Errorf("plist still has placeholders:\n%s", plist)
+	}
+	for _, want := range []string{
+		"<string>com.agentdeck.session.abc123</string>",
+		"<string>abc123</string>\n        <string>--if-stopped</string>",
+		"<key>RunAtLoad</key>\n    <true/>",
+		"pinned-abc123.log",
+	} {
+		if !strings.Contains(plist, want) {
+			t.Errorf("plist missing %q:\n%s", want, plist)
+		}
+	}
+	if strings.Contains(plist, "KeepAlive") {
+		t.Error("a pinned session's start command exits once the session is up; KeepAlive would rerun it forever")
+	}
+}
+
+func TestUnpinSession_NotPinnedIsNoop(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := UnpinSession("abc123"); err != nil {
+		t.Fatalf("UnpinSession on an unpinned session: %v", err)
+	}
+	if path := PinnedServicePath("abc123"); path != "" {
+		t.Fatalf("PinnedServicePath = %q, want empty", path)
+	}
+}
@@ -137,7 +137,7 @@ func SupportsProjectSkills(tool string) bool {
 // ShouldRestartProjectSkills reports whether agent-deck should auto-restart the session
 // after project skill changes for this runtime.
 func ShouldRestartProjectSkills(tool string) bool {
-	return IsClaudeCompatible(tool) || tool == "gemini" || tool == "codex" || tool == "hermes"
+	return IsClaudeCompatible(tool) || tool == "gemini" || tool == "codex" || tool == "opencode" || tool == "hermes"
 }
 
 // GetProjectSkillsDir returns the runtime-managed project skill directory.
@@ -145,7 +145,7 @@ func GetProjectSkillsDir(tool string) (string, bool) {
 	switch {
 	case IsClaudeCompatible(tool):
 		return projectClaudeSkillsDir, true
-	case tool == "gemini" || tool == "codex" || tool == "pi":
+	case tool == "gemini" || tool == "codex" || tool == "opencode" || tool == "pi":
 		return projectAgentsSkillsDir, true
 	case tool == "hermes":
 		return projectHermesSkillsDir, true
@@ -1040,6 +1040,18 @@ func resolveMaterializationSource(sourcePath, fallbackPath string) (string, erro
 }
 
 func attachSkillCandidate(projectPath, tool string, candidate SkillCandidate) (*ProjectSkillAttachment, error) {
+	attachment, err := materializeSkillCandidate(projectPath, tool, candidate)
+	if err != nil {
+		return nil, err
+	}
+	if err := syncProjectSkillsIndex(projectPath, projectSkillsUseInstructionsIndex(tool)); err != nil {
+		return attachment, err
+	}
+	return attachment, nil
+}
+
+// materializeSkillCandidate is attachSkillCandidate without the AGENTS.md index.
+func materializeSkillCandidate(projectPath, tool string, candidate SkillCandidate) (*ProjectSkillAttachment, error) {
 	if !SupportsProjectSkills(tool) {
 		return nil, fmt.Errorf("project skills are not supported for %s sessions", tool)
 	}
@@ -1246,6 +1258,9 @@ func DetachSkillFromProject(projectPath, skillRef, sourceName string) (*ProjectS
 	if err := SaveProjectSkillsManifest(projectPath, manifest); err != nil {
 		return nil, err
 	}
+	if err := syncProjectSkillsIndex(projectPath, false); err != nil {
+		return &removed, err
+	}
 
 	return &removed, nil
 }
@@ -1425,5 +1440,8 @@ func ApplyProjectSkills(projectPath, tool string, desired []SkillCandidate) erro
 	}
 
 	manifest.Skills = newManifest
-	return SaveProjectSkillsManifest(projectPath, manifest)
+	if err := SaveProjectSkillsManifest(projectPath, manifest); err != nil {
+		return err
+	}
+	return syncProjectSkillsIndex(projectPath, projectSkillsUseInstructionsIndex(tool))
 }
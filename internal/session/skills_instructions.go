@@ -0,0 +1,108 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Some runtimes don't discover skill directories on their own; they only read
+// the project's AGENTS.md. For those, agent-deck keeps a managed block in
+// AGENTS.md listing every attached skill and where its SKILL.md lives, so one
+// skill library serves all tools.
+const (
+	projectInstructionsFile = "AGENTS.md"
+	skillsIndexBegin        = "<!-- agent-deck:skills:begin -->"
+	skillsIndexEnd          = "<!-- agent-deck:skills:end -->"
+)
+
+// projectSkillsUseInstructionsIndex reports whether tool learns about project
+// skills from the AGENTS.md index rather than its skills directory.
+func projectSkillsUseInstructionsIndex(tool string) bool {
+	return tool == "opencode"
+}
+
+// syncProjectSkillsIndex rewrites the managed skills block in the project's
+// AGENTS.md to match the manifest. An existing block is always kept in sync;
+// a new one is only added when create is set. The block is removed once no
+// skills are attached, and AGENTS.md with it if nothing else is left.
+func syncProjectSkillsIndex(projectPath string, create bool) error {
+	path := filepath.Join(projectPath, projectInstructionsFile)
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	text := string(content)
+
+	start := strings.Index(text, skillsIndexBegin)
+	end := strings.Index(text, skillsIndexEnd)
+	hasBlock := start >= 0 && end > start
+	if !hasBlock && !create {
+		return nil
+	}
+
+	manifest, err := LoadProjectSkillsManifest(projectPath)
+	if err != nil {
+		return err
+	}
+	block := buildProjectSkillsIndex(projectPath, manifest.Skills)
+
+	var updated string
+	switch {
+	case hasBlock:
+		before := strings.TrimRight(text[:start], "\n")
+		after := strings.TrimLeft(text[end+len(skillsIndexEnd):], "\n")
+		parts := make([]string, 0, 3)
+		for _, p := range []string{before, block, after} {
+			if p != "" {
+				parts = append(parts, strings.TrimRight(p, "\n"))
+			}
+		}
+		updated = strings.Join(parts, "\n\n")
+	case block == "":
+		return nil
+	case strings.TrimSpace(text) == "":
+		updated = block
+	default:
+		updated = strings.TrimRight(text, "\n") + "\n\n" + block
+	}
+
+	if strings.TrimSpace(updated) == "" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	updated = strings.TrimRight(updated, "\n") + "\n"
+	if updated == text {
+		return nil
+	}
+	if err := os.WriteFile(path, []byte(updated), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", projectInstructionsFile, err)
+	}
+	return nil
+}
+
+// buildProjectSkillsIndex renders the managed AGENTS.md block, or "" when no
+// skills are attached.
+func buildProjectSkillsIndex(projectPath string, skills []ProjectSkillAttachment) string {
+	if len(skills) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(skillsIndexBegin + "\n")
+	b.WriteString("## Skills\n\n")
+	b.WriteString("These skills are attached to this project by agent-deck. Before starting a task that matches a skill's description, read its SKILL.md and follow it.\n\n")
+	for _, s := range skills {
+		rel := filepath.ToSlash(filepath.Join(filepath.FromSlash(s.TargetPath), "SKILL.md"))
+		name, description := parseSkillMetadata(resolveTargetPath(projectPath, rel), s.Name)
+		line := fmt.Sprintf("- **%s** (`%s`)", name, rel)
+		if description != "" {
+			line += ": " + description
+		}
+		b.WriteString(line + "\n")
+	}
+	b.WriteString(skillsIndexEnd + "\n")
+	return b.String()
+}
@@ -20,6 +20,7 @@ func TestProjectSkillsDirMapping(t *testing.T) {
 		{tool: "claude", wantDir: ".claude/skills", wantOK: true, wantRestart: true},
 		{tool: "gemini", wantDir: ".agents/skills", wantOK: true, wantRestart: true},
 		{tool: "codex", wantDir: ".agents/skills", wantOK: true, wantRestart: true},
+		{tool: "opencode", wantDir: ".agents/skills", wantOK: true, wantRestart: true},
 		{tool: "pi", wantDir: ".agents/skills", wantOK: true, wantRestart: false},
 		{tool: "shell", wantDir: "", wantOK: false, wantRestart: false},
 	}
@@ -386,3 +387,51 @@ func TestSkillRuntime_ResolveSkillContent(t *testing.T) {
 	assert.Contains(t, frontmatter, "name: code-review", "frontmatter should contain skill name")
 	assert.Contains(t, frontmatter, "description: Automated code review rules", "frontmatter should contain description")
 }
+
+func TestSkillRuntime_OpenCodeKeepsAgentsMDIndex(t *testing.T) {
+	_, cleanup := setupSkillTestEnv(t)
+	defer cleanup()
+
+	sourcePath := t.TempDir()
+	writeSkillDir(t, sourcePath, "alpha", "alpha", "Alpha skill")
+	writeSkillDir(t, sourcePath, "beta", "beta", "Beta skill")
+
+	require.NoError(t, SaveSkillSources(map[string]SkillSourceDef{
+		"local": {Path: sourcePath, Enabled: boolPtr(true)},
+	}))
+
+	projectPath := t.TempDir()
+	agentsMD := filepath.Join(projectPath, "AGENTS.md")
+	require.NoError(t, os.WriteFile(agentsMD, []byte("# Project rules\n\nUse tabs.\n"), 0o644))
+
+	attachment, err := AttachSkillToProject(projectPath, "opencode", "alpha", "local")
+	require.NoError(t, err)
+	require.Equal(t, ".agents/skills/alpha", attachment.TargetPath)
+	_, err = AttachSkillToProject(projectPath, "opencode", "beta", "local")
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(agentsMD)
+	require.NoError(t, err)
+	text := string(content)
+	assert.True(t, strings.HasPrefix(text, "# Project rules\n\nUse tabs.\n\n"+skillsIndexBegin), "existing instructions must be kept:\n%s", text)
+	assert.Contains(t, text, "- **alpha** (`.agents/skills/alpha/SKILL.md`): Alpha skill")
+	assert.Contains(t, text, "- **beta** (`.agents/skills/beta/SKILL.md`): Beta skill")
+	assert.Equal(t, 1, strings.Count(text, skillsIndexBegin))
+
+	_, err = DetachSkillFromProject(projectPath, "alpha", "local")
+	require.NoError(t, err)
+	content, _ = os.ReadFile(agentsMD)
+	assert.NotContains(t, string(content), "alpha")
+	assert.Contains(t, string(content), "beta")
+
+	_, err = DetachSkillFromProject(projectPath, "beta", "local")
+	require.NoError(t, err)
+	content, _ = os.ReadFile(agentsMD)
+	assert.Equal(t, "# Project rules\n\nUse tabs.\n", string(content))
+
+	// Claude reads .claude/skills directly and never gets an index.
+	_, err = AttachSkillToProject(projectPath, "claude", "alpha", "local")
+	require.NoError(t, err)
+	content, _ = os.ReadFile(agentsMD)
+	assert.NotContains(t, string(content), skillsIndexBegin)
+}
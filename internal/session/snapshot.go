@@ -0,0 +1,357 @@
+package session
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/git"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// Session snapshots move one session between machines. A snapshot is a
+// gzipped tarball holding:
+//
+//	manifest.json     session row (metadata, tool options, MCP names), its
+//	                  group, the git ref of the project or worktree
+//	transcript.jsonl  the Claude conversation, when there is one
+//	mcp.json          the project's .mcp.json, when there is one
+//
+// RestoreSnapshot recreates the session from it, rewriting paths under the
+// source $HOME to the local one so ~/code/app on a laptop lands on
+// ~/code/app on a desktop even when the home directories differ.
+
+// SnapshotVersion is the snapshot format version written by WriteSnapshot.
+const SnapshotVersion = 1
+
+// Snapshot archive entries.
+const (
+	snapshotManifestEntry   = "manifest.json"
+	snapshotTranscriptEntry = "transcript.jsonl"
+	snapshotMCPEntry        = "mcp.json"
+)
+
+// maxSnapshotEntry bounds a single archive entry read by ReadSnapshot.
+const maxSnapshotEntry = 512 << 20
+
+// SnapshotManifest describes a snapshot.
+type SnapshotManifest struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Host      string    `json:"host,omitempty"`
+	// Home is the source machine's $HOME, used to map paths on restore.
+	Home     string               `json:"home"`
+	Instance *statedb.InstanceRow `json:"instance"`
+	Group    *statedb.GroupRow    `json:"group,omitempty"`
+	Git      *SnapshotGitRef      `json:"git,omitempty"`
+}
+
+// SnapshotGitRef is the git state of the session's project at snapshot time.
+type SnapshotGitRef struct {
+	Branch string `json:"branch,omitempty"`
+	Commit string `json:"commit,omitempty"`
+	Remote string `json:"remote,omitempty"`
+}
+
+// Snapshot is a decoded snapshot archive.
+type Snapshot struct {
+	Manifest   SnapshotManifest
+	Transcript []byte
+	MCPConfig  []byte
+}
+
+// NewSnapshot captures inst, with its group row if it has one.
+func NewSnapshot(inst *Instance, group *statedb.GroupRow) (*Snapshot, error) {
+	row, err := instanceToRow(inst)
+	if err != nil {
+		return nil, err
+	}
+	home, _ := os.UserHomeDir()
+	host, _ := os.Hostname()
+	snap := &Snapshot{Manifest: SnapshotManifest{
+		Version:   SnapshotVersion,
+		CreatedAt: time.Now().UTC(),
+		Host:      host,
+		Home:      home,
+		Instance:  row,
+		Group:     group,
+	}}
+
+	if git.IsGitRepo(inst.ProjectPath) {
+		ref := &SnapshotGitRef{}
+		ref.Branch, _ = git.GetCurrentBranch(inst.ProjectPath)
+		ref.Commit, _ = git.HeadCommit(inst.ProjectPath)
+		ref.Remote, _ = git.GetRemoteURL(inst.ProjectPath)
+		snap.Manifest.Git = ref
+	}
+	if path := inst.GetJSONLPath(); path != "" {
+		if snap.Transcript, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("read transcript: %w", err)
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(inst.ProjectPath, ".mcp.json")); err == nil {
+		snap.MCPConfig = data
+	}
+	return snap, nil
+}
+
+// Write writes the snapshot as a gzipped tarball.
+func (s *Snapshot) Write(w io.Writer) error {
+	manifest, err := json.MarshalIndent(s.Manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	entries := []struct {
+		name string
+		data []byte
+	}{
+		{snapshotManifestEntry, manifest},
+		{snapshotTranscriptEntry, s.Transcript},
+		{snapshotMCPEntry, s.MCPConfig},
+	}
+	for _, e := range entries {
+		if e.data == nil {
+			continue
+		}
+		hdr := &tar.Header{Name: e.name, Mode: 0o600, Size: int64(len(e.data)), ModTime: s.Manifest.CreatedAt}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(e.data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// ReadSnapshot decodes a snapshot written by Snapshot.Write.
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a snapshot: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	snap := &Snapshot{}
+	var manifest []byte
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read snapshot: %w", err)
+		}
+		if hdr.Size > maxSnapshotEntry {
+			return nil, fmt.Errorf("snapshot entry %s too large", hdr.Name)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("read snapshot: %w", err)
+		}
+		switch hdr.Name {
+		case snapshotManifestEntry:
+			manifest = data
+		case snapshotTranscriptEntry:
+			snap.Transcript = data
+		case snapshotMCPEntry:
+			snap.MCPConfig = data
+		}
+	}
+	if manifest == nil {
+		return nil, fmt.Errorf("snapshot has no %s", snapshotManifestEntry)
+	}
+	if err := json.Unmarshal(manifest, &snap.Manifest); err != nil {
+		return nil, fmt.Errorf("parse manifest: %w", err)
+	}
+	if snap.Manifest.Version > SnapshotVersion {
+		return nil, fmt.Errorf("snapshot version %d is newer than this agent-deck supports (%d)", snap.Manifest.Version, SnapshotVersion)
+	}
+	if snap.Manifest.Instance == nil || snap.Manifest.Instance.ID == "" {
+		return nil, fmt.Errorf("snapshot manifest has no session")
+	}
+	return snap, nil
+}
+
+// SnapshotRestoreOptions adjusts RestoreSnapshot.
+type SnapshotRestoreOptions struct {
+	// ProjectPath overrides where the project lives on this machine.
+	ProjectPath string
+	// Group overrides the group the session is restored into.
+	Group string
+	// Title overrides the session title.
+	Title string
+}
+
+// SnapshotRestore reports what RestoreSnapshot did.
+type SnapshotRestore struct {
+	Row               *statedb.InstanceRow
+	NewID             bool // the snapshot's ID was taken; a fresh one was used
+	WorktreeCreated   bool
+	TranscriptWritten string
+	MCPConfigWritten  string
+	Warnings          []string
+}
+
+// RestoreSnapshot recreates the snapshot's session in db, stopped. The
+// project directory must exist locally; a missing worktree is recreated
+// from its branch when the repository is present. Existing transcripts and
+// .mcp.json files are never overwritten.
+func RestoreSnapshot(db *statedb.StateDB, snap *Snapshot, opts SnapshotRestoreOptions) (*SnapshotRestore, error) {
+	m := snap.Manifest
+	row := *m.Instance
+	res := &SnapshotRestore{Row: &row}
+
+	home, _ := os.UserHomeDir()
+	mapPath := func(p string) string {
+		if p == "" || m.Home == "" || home == "" {
+			return p
+		}
+		if mapped, ok := RewritePathPrefix(p, m.Home, home); ok {
+			return mapped
+		}
+		return p
+	}
+	row.ProjectPath = mapPath(row.ProjectPath)
+	row.WorktreePath = mapPath(row.WorktreePath)
+	row.WorktreeRepo = mapPath(row.WorktreeRepo)
+	if opts.ProjectPath != "" {
+		if row.WorktreePath == row.ProjectPath {
+			row.WorktreePath = opts.ProjectPath
+		}
+		row.ProjectPath = opts.ProjectPath
+	}
+
+	if !isDir(row.ProjectPath) {
+		if row.WorktreePath == "" || row.WorktreeBranch == "" || !git.IsGitRepo(row.WorktreeRepo) {
+			return nil, fmt.Errorf("project path %s does not exist on this machine (pass the new location with --path)", row.ProjectPath)
+		}
+		if err := git.CreateWorktree(row.WorktreeRepo, row.WorktreePath, row.WorktreeBranch); err != nil {
+			return nil, fmt.Errorf("recreate worktree: %w", err)
+		}
+		res.WorktreeCreated = true
+		if !isDir(row.ProjectPath) {
+			return nil, fmt.Errorf("project path %s does not exist after recreating the worktree", row.ProjectPath)
+		}
+	}
+	if m.Git != nil && m.Git.Commit != "" {
+		if head, err := git.HeadCommit(row.ProjectPath); err == nil && head != m.Git.Commit {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("project is at %s, snapshot was taken at %s (%s)", shortCommit(head), shortCommit(m.Git.Commit), m.Git.Branch))
+		}
+	}
+
+	if existing, err := db.LoadInstanceByID(row.ID); err != nil {
+		return nil, err
+	} else if existing != nil {
+		row.ID = GenerateID()
+		res.NewID = true
+	}
+	if row.ParentSessionID != "" {
+		if parent, err := db.LoadInstanceByID(row.ParentSessionID); err != nil || parent == nil {
+			res.Warnings = append(res.Warnings, "parent session is not in this profile; restored as a top-level session")
+			row.ParentSessionID = ""
+		}
+	}
+	if opts.Title != "" {
+		row.Title = opts.Title
+	}
+	if opts.Group != "" {
+		row.GroupPath = opts.Group
+	}
+	if row.GroupPath == "" {
+		row.GroupPath = DefaultGroupPath
+	}
+	row.Status = string(StatusStopped)
+	row.TmuxSession = tmux.GenerateSessionName(row.Title)
+	row.TmuxSocketName = GetTmuxSettings().GetSocketName()
+	row.LastAccessed = time.Now()
+	row.ArchivedAt = time.Time{}
+
+	if err := restoreSnapshotGroup(db, m.Group, row.GroupPath, mapPath); err != nil {
+		return nil, fmt.Errorf("restore group: %w", err)
+	}
+
+	if len(snap.Transcript) > 0 {
+		if id := claudeSessionIDFromToolData(row.ToolData); id != "" {
+			dir := filepath.Join(GetClaudeConfigDir(), "projects", ConvertToClaudeDirName(row.ProjectPath))
+			path := filepath.Join(dir, id+".jsonl")
+			if _, err := os.Stat(path); err == nil {
+				res.Warnings = append(res.Warnings, fmt.Sprintf("transcript %s already exists; kept the local copy", path))
+			} else if err := os.MkdirAll(dir, 0o755); err != nil {
+				return nil, fmt.Errorf("restore transcript: %w", err)
+			} else if err := os.WriteFile(path, snap.Transcript, 0o600); err != nil {
+				return nil, fmt.Errorf("restore transcript: %w", err)
+			} else {
+				res.TranscriptWritten = path
+			}
+		}
+	}
+	if len(snap.MCPConfig) > 0 {
+		path := filepath.Join(row.ProjectPath, ".mcp.json")
+		if local, err := os.ReadFile(path); err == nil {
+			if !bytes.Equal(local, snap.MCPConfig) {
+				res.Warnings = append(res.Warnings, fmt.Sprintf("%s differs from the snapshot; kept the local copy", path))
+			}
+		} else if err := os.WriteFile(path, snap.MCPConfig, 0o644); err != nil {
+			return nil, fmt.Errorf("restore .mcp.json: %w", err)
+		} else {
+			res.MCPConfigWritten = path
+		}
+	}
+
+	if err := db.InsertInstanceRow(&row); err != nil {
+		return nil, fmt.Errorf("save session: %w", err)
+	}
+	_ = db.Touch()
+	return res, nil
+}
+
+// restoreSnapshotGroup creates the session's group when it does not exist,
+// from the snapshot's group row when it matches.
+func restoreSnapshotGroup(db *statedb.StateDB, snapGroup *statedb.GroupRow, groupPath string, mapPath func(string) string) error {
+	if groupPath == DefaultGroupPath {
+		return nil
+	}
+	existing, err := db.LoadGroup(groupPath)
+	if err != nil || existing != nil {
+		return err
+	}
+	g := &statedb.GroupRow{Path: groupPath, Name: filepath.Base(groupPath), Expanded: true}
+	if snapGroup != nil && snapGroup.Path == groupPath {
+		g = snapGroup
+		g.DefaultPath = mapPath(g.DefaultPath)
+	}
+	return db.SaveGroup(g)
+}
+
+// claudeSessionIDFromToolData returns the claude_session_id in a row's
+// tool data.
+func claudeSessionIDFromToolData(toolData json.RawMessage) string {
+	var td struct {
+		ClaudeSessionID string `json:"claude_session_id"`
+	}
+	if len(toolData) == 0 || json.Unmarshal(toolData, &td) != nil {
+		return ""
+	}
+	return td.ClaudeSessionID
+}
+
+func shortCommit(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
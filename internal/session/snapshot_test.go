@@ -0,0 +1,138 @@
+package session
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+func TestSnapshot_RoundTripMapsHomeAndRestoresFiles(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("AGENTDECK_PROFILE", "")
+	claudeDir := filepath.Join(home, ".claude")
+	t.Setenv("CLAUDE_CONFIG_DIR", claudeDir)
+
+	project := filepath.Join(home, "code", "app")
+	if err := os.MkdirAll(project, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	mcp := []byte(`{"mcpServers":{"exa":{"command":"exa"}}}`)
+	if err := os.WriteFile(filepath.Join(project, ".mcp.json"), mcp, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	transcriptDir := filepath.Join(claudeDir, "projects", ConvertToClaudeDirName(project))
+	if err := os.MkdirAll(transcriptDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	transcript := []byte(`{"type":"user","message":{"content":"hi"}}` + "\n")
+	if err := os.WriteFile(filepath.Join(transcriptDir, "conv-1.jsonl"), transcript, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	inst := NewInstanceWithTool("app", project, "claude")
+	inst.GroupPath = "work"
+	inst.ClaudeSessionID = "conv-1"
+	snap, err := NewSnapshot(inst, &statedb.GroupRow{Path: "work", Name: "Work", DefaultPath: project})
+	if err != nil {
+		t.Fatalf("NewSnapshot: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := snap.Write(&buf); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	// Restore on "another machine": a different $HOME holding the project
+	// at the same home-relative path, with no transcript or .mcp.json.
+	home2 := t.TempDir()
+	t.Setenv("HOME", home2)
+	claudeDir2 := filepath.Join(home2, ".claude")
+	t.Setenv("CLAUDE_CONFIG_DIR", claudeDir2)
+	project2 := filepath.Join(home2, "code", "app")
+	if err := os.MkdirAll(project2, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	storage, err := NewStorageWithProfile("_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer storage.Close()
+
+	got, err := ReadSnapshot(&buf)
+	if err != nil {
+		t.Fatalf("ReadSnapshot: %v", err)
+	}
+	res, err := RestoreSnapshot(storage.GetDB(), got, SnapshotRestoreOptions{})
+	if err != nil {
+		t.Fatalf("RestoreSnapshot: %v", err)
+	}
+
+	if res.Row.ProjectPath != project2 {
+		t.Errorf("ProjectPath = %q, want %q", res.Row.ProjectPath, project2)
+	}
+	if res.Row.ID != inst.ID || res.NewID {
+		t.Errorf("ID = %q (new=%v), want original %q", res.Row.ID, res.NewID, inst.ID)
+	}
+	if res.Row.Status != string(StatusStopped) {
+		t.Errorf("Status = %q, want stopped", res.Row.Status)
+	}
+	data, err := os.ReadFile(filepath.Join(claudeDir2, "projects", ConvertToClaudeDirName(project2), "conv-1.jsonl"))
+	if err != nil || !bytes.Equal(data, transcript) {
+		t.Errorf("transcript not restored: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(project2, ".mcp.json")); err != nil || !bytes.Equal(data, mcp) {
+		t.Errorf(".mcp.json not restored: %v", err)
+	}
+
+	row, err := storage.GetDB().LoadInstanceByID(inst.ID)
+	if err != nil || row == nil {
+		t.Fatalf("restored row missing: %v", err)
+	}
+	if claudeSessionIDFromToolData(row.ToolData) != "conv-1" {
+		t.Errorf("claude session id not preserved: %s", row.ToolData)
+	}
+	g, err := storage.GetDB().LoadGroup("work")
+	if err != nil || g == nil || g.DefaultPath != project2 {
+		t.Errorf("group = %+v (%v), want default path %q", g, err, project2)
+	}
+
+	// A second restore keeps the first and takes a fresh ID.
+	res2, err := RestoreSnapshot(storage.GetDB(), got, SnapshotRestoreOptions{})
+	if err != nil {
+		t.Fatalf("second RestoreSnapshot: %v", err)
+	}
+	if !res2.NewID || res2.Row.ID == inst.ID {
+		t.Errorf("second restore reused ID %q", res2.Row.ID)
+	}
+}
+
+func TestRestoreSnapshot_MissingProjectPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("AGENTDECK_PROFILE", "")
+	storage, err := NewStorageWithProfile("_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer storage.Close()
+
+	snap := &Snapshot{Manifest: SnapshotManifest{
+		Version:  SnapshotVersion,
+		Home:     "/home/elsewhere",
+		Instance: &statedb.InstanceRow{ID: "abc", Title: "gone", ProjectPath: "/home/elsewhere/nope", Tool: "shell"},
+	}}
+	if _, err := RestoreSnapshot(storage.GetDB(), snap, SnapshotRestoreOptions{}); err == nil {
+		t.Fatal("restore into a missing project path should fail")
+	}
+	other := t.TempDir()
+	res, err := RestoreSnapshot(storage.GetDB(), snap, SnapshotRestoreOptions{ProjectPath: other})
+	if err != nil {
+		t.Fatalf("restore with --path: %v", err)
+	}
+	if res.Row.ProjectPath != other {
+		t.Errorf("ProjectPath = %q, want %q", res.Row.ProjectPath, other)
+	}
+}
@@ -115,6 +115,10 @@ func (r *SpawnFailureRecord) FormatForDisplay() string {
 		b.WriteString("The terminal session could not be created.\n")
 	case "spawn_died_fast":
 		fmt.Fprintf(&b, "The command exited almost immediately (after %dms).\n", r.ElapsedMs)
+	case "spawn_pane_dead":
+		fmt.Fprintf(&b, "The command exited almost immediately (after %dms); the pane is dead.\n", r.ElapsedMs)
+	case "spawn_returned_to_shell":
+		fmt.Fprintf(&b, "The command exited almost immediately (after %dms) and the pane fell back to a shell.\n", r.ElapsedMs)
 	default:
 		b.WriteString("The session ended unexpectedly during startup.\n")
 	}
@@ -136,9 +140,15 @@ func (r *SpawnFailureRecord) FormatForDisplay() string {
 // spawnFastDeathWindow / spawnFastDeathTick bound the fast-death watcher: a
 // session that outlives the window is assumed to have started successfully. The
 // tick period trades detection latency against subprocess overhead.
+//
+// spawnShellReturnConfirm is how long an exit-to-shell pane must sit at a bare
+// shell before the watcher calls it a start failure. The wrapping bash is
+// briefly the foreground process while it launches the agent (longer when
+// launch_shell sources rc files), so a single sample is not enough.
 const (
-	spawnFastDeathWindow = 15 * time.Second
-	spawnFastDeathTick   = 250 * time.Millisecond
+	spawnFastDeathWindow    = 15 * time.Second
+	spawnFastDeathTick      = 250 * time.Millisecond
+	spawnShellReturnConfirm = 3 * time.Second
 )
 
 // watchForFastDeath runs after a successful tmux Start() and captures the pane
@@ -157,6 +167,13 @@ const (
 // and tool, and gen — a snapshot of i.spawnGen taken at launch. A deliberate
 // stop or a restart/respawn bumps i.spawnGen, so a mismatch means this watcher
 // has been superseded and must exit quietly (#1580 data-race fix).
+//
+// Two deaths leave the tmux session alive and would otherwise sit "starting"
+// until the user looked: a dead pane under remain-on-exit (sandbox sessions)
+// and an exit-to-shell command whose agent exited straight back to the
+// fallback shell. Both are recorded like a vanished session, with the pane
+// content as the dying output, and flag the spawn as failed so UpdateStatus
+// reports StatusError instead of waiting on a prompt that will never come.
 func (i *Instance) watchForFastDeath(command string, gen uint64, sess *tmux.Session, id, tool string, logger *slog.Logger) {
 	if sess == nil {
 		return
@@ -164,6 +181,8 @@ func (i *Instance) watchForFastDeath(command string, gen uint64, sess *tmux.Sess
 	start := time.Now()
 	deadline := start.Add(spawnFastDeathWindow)
 	var lastSnapshot string
+	shellFallback := strings.HasSuffix(command, exitToShellTail)
+	var atShellSince time.Time
 
 	ticker := time.NewTicker(spawnFastDeathTick)
 	defer ticker.Stop()
@@ -186,6 +205,25 @@ func (i *Instance) watchForFastDeath(command string, gen uint64, sess *tmux.Sess
 					lastSnapshot = trimmed
 				}
 			}
+			reason := ""
+			if sess.IsPaneDead() {
+				reason = "spawn_pane_dead"
+			} else if shellFallback {
+				if isShellBinary(sess.PaneCurrentCommand()) {
+					if atShellSince.IsZero() {
+						atShellSince = time.Now()
+					}
+					if time.Since(atShellSince) >= spawnShellReturnConfirm {
+						reason = "spawn_returned_to_shell"
+					}
+				} else {
+					atShellSince = time.Time{}
+				}
+			}
+			if reason != "" {
+				i.recordFastDeath(reason, command, lastSnapshot, time.Since(start), gen, id, tool, logger)
+				return
+			}
 			if time.Now().After(deadline) {
 				// Survived the window: healthy start.
 				_ = WriteSessionIDLifecycleEvent(SessionIDLifecycleEvent{
@@ -200,37 +238,61 @@ func (i *Instance) watchForFastDeath(command string, gen uint64, sess *tmux.Sess
 		}
 
 		// Session is gone and it was not a deliberate stop → fast death.
-		elapsed := time.Since(start).Milliseconds()
-		rec := SpawnFailureRecord{
-			InstanceID:  id,
-			Tool:        tool,
-			Command:     command,
-			Reason:      "spawn_died_fast",
-			DyingOutput: lastSnapshot,
-			ElapsedMs:   elapsed,
-		}
-		if err := writeSpawnFailureRecord(rec); err != nil {
-			logger.Warn("spawn_failure_record_write_failed",
-				slog.String("instance_id", id),
-				slog.String("error", err.Error()))
-		}
-		logger.Error("spawn_died_fast",
-			slog.String("instance_id", id),
-			slog.String("tool", tool),
-			slog.String("command", command),
-			slog.Int64("elapsed_ms", elapsed),
-			slog.String("dying_output", lastSnapshot))
-		_ = WriteSessionIDLifecycleEvent(SessionIDLifecycleEvent{
-			InstanceID: id,
-			Tool:       tool,
-			Action:     "spawn_died_fast",
-			Source:     "spawn_watcher",
-			Reason:     fmt.Sprintf("exited after %dms", elapsed),
-		})
+		i.recordFastDeath("spawn_died_fast", command, lastSnapshot, time.Since(start), gen, id, tool, logger)
 		return
 	}
 }
 
+// recordFastDeath persists a SpawnFailureRecord for a start failure observed
+// by the watcher, logs it, and flags spawn gen as failed. Like the watcher it
+// only touches i through atomics.
+func (i *Instance) recordFastDeath(reason, command, dyingOutput string, elapsed time.Duration, gen uint64, id, tool string, logger *slog.Logger) {
+	ms := elapsed.Milliseconds()
+	rec := SpawnFailureRecord{
+		InstanceID:  id,
+		Tool:        tool,
+		Command:     command,
+		Reason:      reason,
+		DyingOutput: dyingOutput,
+		ElapsedMs:   ms,
+	}
+	if err := writeSpawnFailureRecord(rec); err != nil {
+		logger.Warn("spawn_failure_record_write_failed",
+			slog.String("instance_id", id),
+			slog.String("error", err.Error()))
+	}
+	i.spawnFailedGen.Store(gen)
+	logger.Error(reason,
+		slog.String("instance_id", id),
+		slog.String("tool", tool),
+		slog.String("command", command),
+		slog.Int64("elapsed_ms", ms),
+		slog.String("dying_output", dyingOutput))
+	_ = WriteSessionIDLifecycleEvent(SessionIDLifecycleEvent{
+		InstanceID: id,
+		Tool:       tool,
+		Action:     reason,
+		Source:     "spawn_watcher",
+		Reason:     fmt.Sprintf("exited after %dms", ms),
+	})
+}
+
+// StartFailed reports whether the watcher classified the current spawn as a
+// start failure. A new spawn, restart or stop clears it.
+func (i *Instance) StartFailed() bool {
+	gen := i.spawnFailedGen.Load()
+	return gen != 0 && gen == i.spawnGen.Load()
+}
+
+// RetryCommand returns the command to offer when retrying a failed start:
+// the session's configured command, or its tool name when none is set.
+func (i *Instance) RetryCommand() string {
+	if i.Command != "" {
+		return i.Command
+	}
+	return i.Tool
+}
+
 // recordTmuxStartFailure persists a record for the case where tmux itself
 // failed to create the session (i.tmuxSession.Start returned an error). Unlike
 // the fast-death path this has no pane to snapshot — the error string is the
@@ -257,11 +319,12 @@ func (i *Instance) recordTmuxStartFailure(command string, startErr error) {
 	})
 }
 
-// recordSpawnAttempt clears any stale record and logs a spawn_attempt lifecycle
+// recordSpawnAttempt clears any stale failure and exit records and logs a spawn_attempt lifecycle
 // event so every start leaves a durable trace even when the process dies before
 // any other code runs.
 func (i *Instance) recordSpawnAttempt() {
 	clearSpawnFailureRecord(i.ID)
+	prepareExitRecord(i.ID)
 	_ = WriteSessionIDLifecycleEvent(SessionIDLifecycleEvent{
 		InstanceID: i.ID,
 		Tool:       i.Tool,
@@ -0,0 +1,56 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A start failure recorded by the watcher belongs to one spawn: it marks that
+// spawn failed, persists the record, and is superseded by the next spawn.
+func TestStartFailed_TracksSpawnGeneration(t *testing.T) {
+	inst := NewInstance("test-start-failed-gen", "/tmp")
+	inst.Tool = "codex"
+	defer clearSpawnFailureRecord(inst.ID)
+
+	assert.False(t, inst.StartFailed(), "a fresh instance has no failed spawn")
+
+	gen := inst.spawnGen.Add(1)
+	inst.recordFastDeath("spawn_returned_to_shell", "codex; exec \"$SHELL\" -i",
+		"codex: command not found\n$", 3250*time.Millisecond, gen, inst.ID, inst.Tool, sessionLog)
+
+	require.True(t, inst.StartFailed())
+	rec := inst.SpawnFailure()
+	require.NotNil(t, rec)
+	assert.Equal(t, "spawn_returned_to_shell", rec.Reason)
+	assert.Equal(t, int64(3250), rec.ElapsedMs)
+	assert.Contains(t, rec.FormatForDisplay(), "fell back to a shell")
+	assert.Contains(t, rec.FormatForDisplay(), "codex: command not found")
+	assert.Contains(t, readLifecycleLog(t), "spawn_returned_to_shell")
+
+	inst.spawnGen.Add(1)
+	assert.False(t, inst.StartFailed(), "a newer spawn must clear the failed flag")
+}
+
+// A failed spawn reports StatusError even though the pane may still exist
+// (dead pane under remain-on-exit, or the exit-to-shell prompt), instead of
+// sitting in "starting" forever.
+func TestUpdateStatus_StartFailedReportsError(t *testing.T) {
+	inst := NewInstance("test-start-failed-status", "/tmp")
+	inst.CreatedAt = time.Now().Add(-time.Minute)
+	inst.Status = StatusStarting
+	inst.spawnFailedGen.Store(inst.spawnGen.Add(1))
+
+	require.NoError(t, inst.UpdateStatus())
+	assert.Equal(t, StatusError, inst.Status)
+}
+
+func TestRetryCommand_FallsBackToTool(t *testing.T) {
+	inst := NewInstance("test-retry-command", "/tmp")
+	inst.Tool = "claude"
+	assert.Equal(t, "claude", inst.RetryCommand())
+	inst.Command = "claude --model opus"
+	assert.Equal(t, "claude --model opus", inst.RetryCommand())
+}
@@ -0,0 +1,240 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// AllMCPsPreset in a preset's mcps list stands for every MCP in config.toml.
+const AllMCPsPreset = "*"
+
+// StartPreset is a resource tier a session is started with: the model, MCP
+// set and tool flags it runs with. [presets.<name>] in config.toml defines
+// one; a user preset replaces the built-in of the same name.
+type StartPreset struct {
+	// Description is shown by `session presets`.
+	Description string `toml:"description,omitempty"`
+
+	// Model is the model for any tool without its own entry in Tools.
+	// Empty leaves the session on the configured default model.
+	Model string `toml:"model,omitempty"`
+
+	// MCPs is the session's MCP set; "*" attaches every MCP in config.toml
+	// and an empty list detaches them all. Omitted leaves MCPs alone.
+	MCPs []string `toml:"mcps"`
+
+	// Tools holds tool-specific options, keyed by tool name.
+	Tools map[string]PresetToolOptions `toml:"tools,omitempty"`
+}
+
+// PresetToolOptions are a preset's options for one tool.
+type PresetToolOptions struct {
+	// Model overrides the preset's Model for this tool.
+	Model string `toml:"model,omitempty"`
+
+	// ExtraArgs are appended to the tool's command (claude only, like
+	// `session set extra-args`).
+	ExtraArgs []string `toml:"extra_args,omitempty"`
+}
+
+// claudeThinkingArgs turn on extended thinking for a Claude session.
+var claudeThinkingArgs = []string{"--settings", `{"alwaysThinkingEnabled":true}`}
+
+// builtinStartPresets are the tiers available without any config.
+var builtinStartPresets = map[string]StartPreset{
+	"light": {
+		Description: "Fast and cheap: smallest model, no MCPs",
+		MCPs:        []string{},
+		Tools:       map[string]PresetToolOptions{"claude": {Model: "haiku"}},
+	},
+	"standard": {
+		Description: "Configured default model; MCPs left as they are",
+	},
+	"heavy": {
+		Description: "Largest model, every MCP, extended thinking",
+		MCPs:        []string{AllMCPsPreset},
+		Tools:       map[string]PresetToolOptions{"claude": {Model: "opus", ExtraArgs: claudeThinkingArgs}},
+	},
+}
+
+// GetStartPresets returns the preset registry: the built-in tiers overlaid
+// with [presets.<name>] from config.toml.
+func GetStartPresets() map[string]StartPreset {
+	presets := make(map[string]StartPreset, len(builtinStartPresets))
+	for name, p := range builtinStartPresets {
+		presets[name] = p
+	}
+	if config, err := LoadUserConfig(); err == nil && config != nil {
+		for name, p := range config.Presets {
+			presets[name] = p
+		}
+	}
+	return presets
+}
+
+// LookupStartPreset returns the named preset, or an error listing the
+// available ones.
+func LookupStartPreset(name string) (StartPreset, error) {
+	presets := GetStartPresets()
+	if p, ok := presets[name]; ok {
+		return p, nil
+	}
+	return StartPreset{}, fmt.Errorf("unknown preset %q (available: %s)", name, strings.Join(StartPresetNames(presets), ", "))
+}
+
+// StartPresetNames returns the preset names in sorted order.
+func StartPresetNames(presets map[string]StartPreset) []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// optionsFor returns the preset's options for tool, falling back to the entry
+// of the tool it is compatible with.
+func (p StartPreset) optionsFor(tool string) PresetToolOptions {
+	if o, ok := p.Tools[tool]; ok {
+		return o
+	}
+	return p.Tools[continueBaseTool(tool)]
+}
+
+// ModelFor returns the model the preset runs tool with ("" = configured default).
+func (p StartPreset) ModelFor(tool string) string {
+	if m := strings.TrimSpace(p.optionsFor(tool).Model); m != "" {
+		return m
+	}
+	return strings.TrimSpace(p.Model)
+}
+
+// MCPNames resolves the preset's MCP set against config.toml. ok is false
+// when the preset leaves MCPs alone.
+func (p StartPreset) MCPNames() (names []string, ok bool) {
+	if p.MCPs == nil {
+		return nil, false
+	}
+	available := GetAvailableMCPs()
+	names = []string{}
+	for _, name := range p.MCPs {
+		if name == AllMCPsPreset {
+			return sortedMCPNames(available), true
+		}
+		if _, exists := available[name]; exists {
+			names = append(names, name)
+		} else {
+			sessionLog.Warn("preset_mcp_missing", slog.String("mcp", name))
+		}
+	}
+	return names, true
+}
+
+func sortedMCPNames(m map[string]MCPDef) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyStartPreset switches the session to the named preset: the model is
+// set (or reset to the default), the previous preset's extra args are
+// swapped for the new one's, and the MCP set is replaced when the preset
+// defines one. Like a model change it takes effect on the next start.
+func (i *Instance) ApplyStartPreset(name string) error {
+	p, err := LookupStartPreset(name)
+	if err != nil {
+		return err
+	}
+
+	if SupportsLaunchModel(i.Tool) {
+		if model := p.ModelFor(i.Tool); model != "" {
+			err = i.ApplyLaunchModel(model)
+		} else {
+			err = i.ClearLaunchModel()
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if i.Tool == "claude" {
+		args := append([]string(nil), i.ExtraArgs...)
+		if i.Preset != "" {
+			if prev, perr := LookupStartPreset(i.Preset); perr == nil {
+				args = removeArgSequence(args, prev.optionsFor(i.Tool).ExtraArgs)
+			}
+		}
+		args = append(args, p.optionsFor(i.Tool).ExtraArgs...)
+		if len(args) == 0 {
+			args = nil
+		}
+		i.ExtraArgs = args
+	}
+
+	if names, ok := p.MCPNames(); ok && ToolSupportsMCPManager(i.Tool) && i.ProjectPath != "" {
+		if err := i.WriteLocalMCPConfig(names); err != nil {
+			return fmt.Errorf("preset %s: failed to write MCPs: %w", name, err)
+		}
+		i.InvalidateProjectMCPIntegrationsCache()
+	}
+
+	i.Preset = name
+	return nil
+}
+
+// removeArgSequence drops the first occurrence of seq from args.
+func removeArgSequence(args, seq []string) []string {
+	if len(seq) == 0 {
+		return args
+	}
+	for start := 0; start+len(seq) <= len(args); start++ {
+		match := true
+		for k, tok := range seq {
+			if args[start+k] != tok {
+				match = false
+				break
+			}
+		}
+		if match {
+			out := append([]string(nil), args[:start]...)
+			return append(out, args[start+len(seq):]...)
+		}
+	}
+	return args
+}
+
+const toolDataPresetKey = "preset"
+
+// WritePresetToToolData stores the session's preset name in the tool_data
+// extras zone, next to working_hours.
+func WritePresetToToolData(td json.RawMessage, preset string) json.RawMessage {
+	if preset == "" {
+		return td
+	}
+	m := map[string]json.RawMessage{}
+	if len(td) > 0 {
+		_ = json.Unmarshal(td, &m)
+	}
+	raw, _ := json.Marshal(preset)
+	m[toolDataPresetKey] = raw
+	out, _ := json.Marshal(m)
+	return out
+}
+
+// ReadPresetFromToolData extracts the preset name from the blob.
+func ReadPresetFromToolData(td json.RawMessage) string {
+	if len(td) == 0 {
+		return ""
+	}
+	var blob struct {
+		Preset string `json:"preset"`
+	}
+	_ = json.Unmarshal(td, &blob)
+	return blob.Preset
+}
@@ -0,0 +1,93 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writePresetTestConfig(t *testing.T, config string) {
+	t.Helper()
+	channelsTestEnv(t)
+	configPath, err := GetUserConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(configPath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	ClearUserConfigCache()
+}
+
+func TestStartPresets_ConfigOverlaysBuiltins(t *testing.T) {
+	writePresetTestConfig(t, `
+[presets.heavy]
+description = "team heavy"
+model = "big"
+[presets.heavy.tools.codex]
+model = "gpt-5.5"
+
+[presets.review]
+mcps = []
+`)
+
+	presets := GetStartPresets()
+	if got := strings.Join(StartPresetNames(presets), ","); got != "heavy,light,review,standard" {
+		t.Fatalf("preset names = %q", got)
+	}
+	heavy := presets["heavy"]
+	if heavy.Description != "team heavy" || heavy.MCPs != nil {
+		t.Errorf("config heavy must replace the built-in wholesale: %+v", heavy)
+	}
+	if got := heavy.ModelFor("codex"); got != "gpt-5.5" {
+		t.Errorf("ModelFor(codex) = %q, want tool entry", got)
+	}
+	if got := heavy.ModelFor("gemini"); got != "big" {
+		t.Errorf("ModelFor(gemini) = %q, want preset model", got)
+	}
+	if names, ok := presets["review"].MCPNames(); !ok || len(names) != 0 {
+		t.Errorf("mcps = [] must mean no MCPs, got %v, %v", names, ok)
+	}
+	if _, err := LookupStartPreset("huge"); err == nil || !strings.Contains(err.Error(), "light") {
+		t.Errorf("unknown preset error = %v, want the available list", err)
+	}
+}
+
+func TestApplyStartPreset_SwitchesClaudeTier(t *testing.T) {
+	writePresetTestConfig(t, "")
+
+	inst := &Instance{Tool: "claude", ExtraArgs: []string{"--agent", "reviewer"}}
+	if err := inst.ApplyStartPreset("heavy"); err != nil {
+		t.Fatal(err)
+	}
+	if inst.Preset != "heavy" || inst.LaunchModelID() == "" {
+		t.Errorf("after heavy: preset %q model %q", inst.Preset, inst.LaunchModelID())
+	}
+	if got := strings.Join(inst.ExtraArgs, " "); !strings.Contains(got, "--agent reviewer --settings") {
+		t.Errorf("heavy must append its flags after the user's: %q", got)
+	}
+
+	if err := inst.ApplyStartPreset("standard"); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(inst.ExtraArgs, " "); got != "--agent reviewer" {
+		t.Errorf("switching away must drop heavy's flags only, got %q", got)
+	}
+	if inst.LaunchModelID() != "" {
+		t.Errorf("standard must reset the model to the default, got %q", inst.LaunchModelID())
+	}
+}
+
+func TestPresetToolDataRoundTrip(t *testing.T) {
+	td := WritePresetToToolData([]byte(`{"working_hours":"09:00-17:00"}`), "light")
+	if got := ReadPresetFromToolData(td); got != "light" {
+		t.Fatalf("ReadPresetFromToolData = %q", got)
+	}
+	if got := ReadWorkingHoursFromToolData(td); got != "09:00-17:00" {
+		t.Fatalf("other tool_data keys must survive, working_hours = %q", got)
+	}
+}
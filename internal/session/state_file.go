@@ -0,0 +1,199 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StateFilesDirName is the per-profile directory holding one JSON file per
+// session (<profile-dir>/state/<session-id>.json).
+const StateFilesDirName = "state"
+
+// StateFilesSettings configures the per-session JSON state files.
+//
+//	[state_files]
+//	enabled = false
+type StateFilesSettings struct {
+	// Enabled writes state files (default: true, nil = true).
+	Enabled *bool `toml:"enabled,omitempty"`
+}
+
+// GetEnabled returns whether state files are written (default: true).
+func (s StateFilesSettings) GetEnabled() bool {
+	if s.Enabled == nil {
+		return true
+	}
+	return *s.Enabled
+}
+
+// GetStateFilesSettings returns state file settings from config.
+func GetStateFilesSettings() StateFilesSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return StateFilesSettings{}
+	}
+	return config.StateFiles
+}
+
+// SessionState is the schema of a per-session state file. It is intentionally
+// small and flat so statusline scripts can read it with jq or a one-line
+// JSON decode instead of invoking the CLI.
+type SessionState struct {
+	ID           string    `json:"id"`
+	Title        string    `json:"title"`
+	Status       string    `json:"status"`
+	Tool         string    `json:"tool"`
+	Path         string    `json:"path"`
+	Group        string    `json:"group"`
+	TmuxSession  string    `json:"tmux_session"`
+	WaitingSince time.Time `json:"waiting_since,omitzero"`
+	LastPrompt   string    `json:"last_prompt,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// StateFilesDir returns the state file directory for a profile.
+func StateFilesDir(profile string) (string, error) {
+	dir, err := GetProfileDir(profile)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, StateFilesDirName), nil
+}
+
+// sessionStateOf builds the state snapshot for an instance. UpdatedAt is left
+// zero so snapshots can be compared for change detection.
+func sessionStateOf(inst *Instance) SessionState {
+	st := SessionState{
+		ID:         inst.ID,
+		Title:      inst.Title,
+		Status:     string(inst.GetStatusThreadSafe()),
+		Tool:       inst.GetToolThreadSafe(),
+		Path:       inst.ProjectPath,
+		Group:      inst.GroupPath,
+		LastPrompt: inst.LatestPrompt,
+	}
+	if ts := inst.GetTmuxSession(); ts != nil {
+		st.TmuxSession = ts.Name
+	}
+	if inst.GetStatusThreadSafe() == StatusWaiting {
+		st.WaitingSince = inst.GetWaitingSince().UTC().Truncate(time.Second)
+	}
+	return st
+}
+
+// StateFileWriter keeps the state files of one profile in sync with the live
+// instance list. Files are only rewritten when their content changes, so a
+// caller can invoke Sync on every status tick.
+type StateFileWriter struct {
+	dir  string
+	mu   sync.Mutex
+	last map[string]SessionState
+}
+
+// NewStateFileWriter returns a writer for the profile's state directory.
+func NewStateFileWriter(profile string) (*StateFileWriter, error) {
+	dir, err := StateFilesDir(profile)
+	if err != nil {
+		return nil, err
+	}
+	return newStateFileWriterAt(dir), nil
+}
+
+func newStateFileWriterAt(dir string) *StateFileWriter {
+	return &StateFileWriter{dir: dir, last: make(map[string]SessionState)}
+}
+
+// Sync writes changed state files and removes files of sessions that are no
+// longer present. Errors are collected and returned after all sessions have
+// been attempted; one unwritable file never blocks the rest.
+func (w *StateFileWriter) Sync(instances []*Instance) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := os.MkdirAll(w.dir, 0o755); err != nil {
+		return fmt.Errorf("create state dir: %w", err)
+	}
+
+	var errs []string
+	seen := make(map[string]struct{}, len(instances))
+	for _, inst := range instances {
+		seen[inst.ID] = struct{}{}
+		st := sessionStateOf(inst)
+		if prev, ok := w.last[inst.ID]; ok && prev == st {
+			continue
+		}
+		out := st
+		out.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if err := atomicWriteFile(w.path(inst.ID), append(data, '\n'), 0o644); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		w.last[inst.ID] = st
+	}
+
+	entries, err := os.ReadDir(w.dir)
+	if err == nil {
+		for _, e := range entries {
+			id, ok := strings.CutSuffix(e.Name(), ".json")
+			if !ok {
+				continue
+			}
+			if _, live := seen[id]; !live {
+				_ = os.Remove(filepath.Join(w.dir, e.Name()))
+				delete(w.last, id)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("write state files: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (w *StateFileWriter) path(id string) string {
+	return filepath.Join(w.dir, filepath.Base(id)+".json")
+}
+
+// ReadSessionStates loads every state file of a profile. Unreadable or
+// malformed files are skipped (a writer may be mid-rename).
+func ReadSessionStates(profile string) ([]SessionState, error) {
+	dir, err := StateFilesDir(profile)
+	if err != nil {
+		return nil, err
+	}
+	return readSessionStatesAt(dir)
+}
+
+func readSessionStatesAt(dir string) ([]SessionState, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	states := make([]SessionState, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		var st SessionState
+		if json.Unmarshal(data, &st) != nil {
+			continue
+		}
+		states = append(states, st)
+	}
+	return states, nil
+}
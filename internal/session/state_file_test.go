@@ -0,0 +1,68 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateFileWriter_WritesAndRemoves(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "state")
+	w := newStateFileWriterAt(dir)
+
+	a := &Instance{ID: "a1", Title: "api", ProjectPath: "/src/api", GroupPath: "work", Tool: "claude", Status: StatusWaiting, LatestPrompt: "fix tests", CreatedAt: time.Now()}
+	b := &Instance{ID: "b2", Title: "web", ProjectPath: "/src/web", Tool: "shell", Status: StatusIdle}
+	require.NoError(t, w.Sync([]*Instance{a, b}))
+
+	states, err := readSessionStatesAt(dir)
+	require.NoError(t, err)
+	require.Len(t, states, 2)
+
+	byID := map[string]SessionState{}
+	for _, st := range states {
+		byID[st.ID] = st
+	}
+	assert.Equal(t, "waiting", byID["a1"].Status)
+	assert.Equal(t, "fix tests", byID["a1"].LastPrompt)
+	assert.Equal(t, "work", byID["a1"].Group)
+	assert.False(t, byID["a1"].WaitingSince.IsZero(), "waiting sessions carry waiting_since")
+	assert.True(t, byID["b2"].WaitingSince.IsZero())
+
+	// Removing a session from the live list deletes its file.
+	require.NoError(t, w.Sync([]*Instance{a}))
+	_, err = os.Stat(filepath.Join(dir, "b2.json"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestStateFileWriter_SkipsUnchanged(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "state")
+	w := newStateFileWriterAt(dir)
+
+	a := &Instance{ID: "a1", Title: "api", Tool: "claude", Status: StatusRunning}
+	require.NoError(t, w.Sync([]*Instance{a}))
+
+	path := filepath.Join(dir, "a1.json")
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(path, old, old))
+
+	require.NoError(t, w.Sync([]*Instance{a}))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.WithinDuration(t, old, info.ModTime(), time.Second, "unchanged state must not be rewritten")
+
+	a.Title = "api-renamed"
+	require.NoError(t, w.Sync([]*Instance{a}))
+	info, err = os.Stat(path)
+	require.NoError(t, err)
+	assert.True(t, info.ModTime().After(old))
+}
+
+func TestStateFilesSettings_DefaultEnabled(t *testing.T) {
+	assert.True(t, StateFilesSettings{}.GetEnabled())
+	off := false
+	assert.False(t, StateFilesSettings{Enabled: &off}.GetEnabled())
+}
@@ -0,0 +1,78 @@
+package session
+
+import (
+	"os"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// StatusCountersMaxAge is how old published status counters may be before
+// readers ignore them. The TUI, or the notify daemon when no TUI is running,
+// republishes well inside this window, so stale counters mean neither is
+// maintaining them.
+const StatusCountersMaxAge = 30 * time.Second
+
+// statusCountersRefresh is how often unchanged counters are rewritten by the
+// notify daemon. Must stay well under StatusCountersMaxAge.
+const statusCountersRefresh = 10 * time.Second
+
+// CountStatuses tallies instances by status, using the same buckets as
+// `agent-deck status`.
+func CountStatuses(instances []*Instance) statedb.StatusCounters {
+	var c statedb.StatusCounters
+	for _, inst := range instances {
+		addStatusCount(&c, inst.GetStatusThreadSafe())
+	}
+	return c
+}
+
+// addStatusCount adds one session with status s to c.
+func addStatusCount(c *statedb.StatusCounters, s Status) {
+	switch s {
+	case StatusWaiting:
+		c.Waiting++
+	case StatusRunning:
+		c.Running++
+	case StatusIdle:
+		c.Idle++
+	case StatusError:
+		c.Error++
+	case StatusStopped:
+		c.Stopped++
+	case StatusExited:
+		c.Exited++
+	case StatusCrashed:
+		c.Crashed++
+	}
+	c.Total++
+}
+
+// ReadCachedStatusCounters returns the counters a running TUI or the notify
+// daemon published for profile. It opens only state.db, without migrating or loading sessions, and
+// reports false when the database or fresh counters are missing so callers
+// fall back to a full status refresh.
+func ReadCachedStatusCounters(profile string) (statedb.StatusCounters, bool) {
+	dbPath, err := GetDBPathForProfile(GetEffectiveProfile(profile))
+	if err != nil {
+		return statedb.StatusCounters{}, false
+	}
+	// statedb.Open creates a missing file; a profile without a database has
+	// nothing cached anyway.
+	if _, err := os.Stat(dbPath); err != nil {
+		return statedb.StatusCounters{}, false
+	}
+	db, err := statedb.Open(dbPath)
+	if err != nil {
+		return statedb.StatusCounters{}, false
+	}
+	// Skip Close's WAL checkpoint: this is a read on a hot path and the
+	// running TUI owns checkpointing.
+	defer db.DB().Close()
+
+	c, ok, err := db.ReadStatusCounters(StatusCountersMaxAge)
+	if err != nil {
+		return statedb.StatusCounters{}, false
+	}
+	return c, ok
+}
@@ -0,0 +1,89 @@
+package session
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+func TestCountStatuses(t *testing.T) {
+	got := CountStatuses([]*Instance{
+		{Status: StatusWaiting},
+		{Status: StatusWaiting},
+		{Status: StatusRunning},
+		{Status: StatusStopped},
+		{Status: StatusStarting},
+	})
+	want := statedb.StatusCounters{Waiting: 2, Running: 1, Stopped: 1, Total: 5}
+	if got != want {
+		t.Errorf("CountStatuses = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadCachedStatusCounters(t *testing.T) {
+	channelsTestEnv(t)
+	t.Setenv("AGENTDECK_PROFILE", "")
+
+	if _, ok := ReadCachedStatusCounters(""); ok {
+		t.Fatal("profile without state.db reported cached counters")
+	}
+
+	storage, err := NewStorageWithProfile("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer storage.Close()
+	if _, ok := ReadCachedStatusCounters(""); ok {
+		t.Fatal("no TUI has published counters yet")
+	}
+
+	if err := storage.GetDB().WriteStatusCounters(statedb.StatusCounters{Waiting: 3, Total: 4}); err != nil {
+		t.Fatal(err)
+	}
+	c, ok := ReadCachedStatusCounters("")
+	if !ok || c.Waiting != 3 || c.Total != 4 {
+		t.Errorf("ReadCachedStatusCounters = %+v, %v", c, ok)
+	}
+}
+
+func TestTransitionDaemon_PublishStatusCounters(t *testing.T) {
+	db, err := statedb.Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := db.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	d := NewTransitionDaemon()
+	instances := []*Instance{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	statuses := map[string]string{"a": "waiting", "b": "waiting", "c": "exited"}
+	now := time.Now()
+	d.publishStatusCounters("p", db, instances, statuses, now)
+
+	want := statedb.StatusCounters{Waiting: 2, Exited: 1, Total: 3}
+	got, ok, err := db.ReadStatusCounters(time.Minute)
+	if err != nil || !ok {
+		t.Fatalf("ReadStatusCounters: ok=%v err=%v", ok, err)
+	}
+	got.UpdatedAt = 0
+	if got != want {
+		t.Errorf("published %+v, want %+v", got, want)
+	}
+
+	// Unchanged counters are not rewritten inside the refresh window.
+	if err := db.WriteStatusCounters(statedb.StatusCounters{}); err != nil {
+		t.Fatal(err)
+	}
+	d.publishStatusCounters("p", db, instances, statuses, now.Add(time.Second))
+	if got, _, _ := db.ReadStatusCounters(time.Minute); got.Total != 0 {
+		t.Errorf("unchanged counters rewritten early: %+v", got)
+	}
+	d.publishStatusCounters("p", db, instances, statuses, now.Add(statusCountersRefresh))
+	if got, _, _ := db.ReadStatusCounters(time.Minute); got.Total != 3 {
+		t.Errorf("counters not refreshed after %s: %+v", statusCountersRefresh, got)
+	}
+}
@@ -0,0 +1,326 @@
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StatusHistorySettings configures the status history ring: for every status
+// update that changes something, the decision and the evidence behind it
+// (hook state, the tmux classifier's verdict, a redacted tail of the
+// normalized pane) are appended to <data-dir>/status-history/<session-id>.jsonl.
+// `agent-deck debug status-history <id>` dumps it, so a report of a wrong
+// status comes with what agent-deck actually saw.
+//
+//	[status_history]
+//	enabled = true
+//	samples = 200
+//	capture_lines = 40
+type StatusHistorySettings struct {
+	// Enabled turns recording on (default: false).
+	Enabled bool `toml:"enabled,omitempty"`
+
+	// Samples is how many decisions are kept per session (default: 200).
+	Samples int `toml:"samples,omitempty"`
+
+	// CaptureLines is how many trailing pane lines each sample keeps
+	// (default: 40).
+	CaptureLines int `toml:"capture_lines,omitempty"`
+}
+
+// GetSamples returns the ring size (default: 200).
+func (s StatusHistorySettings) GetSamples() int {
+	if s.Samples <= 0 {
+		return 200
+	}
+	return s.Samples
+}
+
+// GetCaptureLines returns the capture tail length (default: 40).
+func (s StatusHistorySettings) GetCaptureLines() int {
+	if s.CaptureLines <= 0 {
+		return 40
+	}
+	return s.CaptureLines
+}
+
+// GetStatusHistorySettings returns status history settings from config.
+func GetStatusHistorySettings() StatusHistorySettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return StatusHistorySettings{}
+	}
+	return config.StatusHistory
+}
+
+// statusHistoryConfigTTL bounds how long UpdateStatus, which runs for every
+// session on every tick, reuses the settings before reading config again.
+const statusHistoryConfigTTL = 5 * time.Second
+
+var statusHistoryConfig struct {
+	mu       sync.Mutex
+	at       time.Time
+	settings StatusHistorySettings
+}
+
+// cachedStatusHistorySettings returns GetStatusHistorySettings, re-read at
+// most every statusHistoryConfigTTL.
+func cachedStatusHistorySettings() StatusHistorySettings {
+	statusHistoryConfig.mu.Lock()
+	defer statusHistoryConfig.mu.Unlock()
+	if time.Since(statusHistoryConfig.at) > statusHistoryConfigTTL {
+		statusHistoryConfig.settings = GetStatusHistorySettings()
+		statusHistoryConfig.at = time.Now()
+	}
+	return statusHistoryConfig.settings
+}
+
+// StatusSample is one recorded status decision.
+type StatusSample struct {
+	Timestamp time.Time `json:"ts"`
+	Status    string    `json:"status"`
+	Previous  string    `json:"prev,omitempty"`
+
+	// Hook is the last hook status ("running", "waiting", ...) with its
+	// event, and HookAge how old it was at decision time.
+	Hook    string `json:"hook,omitempty"`
+	HookAge string `json:"hook_age,omitempty"`
+
+	// Tmux is the content classifier's verdict (see tmux.StatusEvidence).
+	Tmux         string `json:"tmux,omitempty"`
+	Substate     string `json:"substate,omitempty"`
+	Title        string `json:"title,omitempty"`
+	TitleState   string `json:"title_state,omitempty"`
+	Acknowledged bool   `json:"ack,omitempty"`
+	Prompt       bool   `json:"prompt,omitempty"`
+
+	// Hash identifies the normalized pane. Capture holds its redacted tail
+	// and is omitted when the pane is unchanged since the previous sample.
+	Hash    string   `json:"hash,omitempty"`
+	Capture []string `json:"capture,omitempty"`
+}
+
+// StatusHistoryPath returns the status history file of a session.
+func StatusHistoryPath(sessionID string) (string, error) {
+	if sessionID == "" || sessionID == "." || sessionID == ".." || strings.ContainsAny(sessionID, `/\`) {
+		return "", fmt.Errorf("invalid session id %q", sessionID)
+	}
+	dir, err := dataPath("status-history", "status-history")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, sessionID+".jsonl"), nil
+}
+
+// statusHistoryRecorder dedupes samples per session and keeps the files
+// bounded. Files are append-only until they hold twice the ring size, then
+// rewritten with the newest samples, so a write is usually one small append.
+type statusHistoryRecorder struct {
+	mu       sync.Mutex
+	sessions map[string]*statusHistoryState
+}
+
+type statusHistoryState struct {
+	key   string // decision fields of the last sample, for dedupe
+	hash  string // pane hash of the last sample that carried a capture
+	lines int    // samples in the file
+}
+
+var statusHistory = &statusHistoryRecorder{sessions: make(map[string]*statusHistoryState)}
+
+// recordStatusSample appends the decision UpdateStatus just made, when
+// status history is enabled and something changed since the last sample.
+// Called with i.mu held; best-effort.
+func (i *Instance) recordStatusSample(prev Status) {
+	settings := cachedStatusHistorySettings()
+	if !settings.Enabled {
+		return
+	}
+	sample := StatusSample{
+		Timestamp: time.Now().UTC(),
+		Status:    string(i.Status),
+		Previous:  string(prev),
+	}
+	if i.hookStatus != "" {
+		sample.Hook = i.hookStatus
+		if i.hookEvent != "" {
+			sample.Hook += " (" + i.hookEvent + ")"
+		}
+		if !i.hookLastUpdate.IsZero() {
+			sample.HookAge = time.Since(i.hookLastUpdate).Round(time.Second).String()
+		}
+	}
+	var capture string
+	if i.tmuxSession != nil {
+		ev := i.tmuxSession.StatusEvidence()
+		sample.Tmux = ev.Status
+		sample.Substate = string(ev.Substate)
+		sample.Title = ev.Title
+		sample.TitleState = ev.TitleState
+		sample.Acknowledged = ev.Acknowledged
+		sample.Prompt = ev.Prompt
+		if ev.Hash != "" {
+			sample.Hash = ev.Hash[:12]
+		}
+		capture = ev.Capture
+	}
+	if err := statusHistory.record(i.ID, sample, capture, settings); err != nil {
+		sessionLog.Debug("status_history_write_failed", slog.String("id", i.ID), slog.String("error", err.Error()))
+	}
+}
+
+// record appends sample unless it repeats the previous one. The hook age is
+// left out of the comparison so a steady state is recorded once.
+func (r *statusHistoryRecorder) record(id string, sample StatusSample, capture string, settings StatusHistorySettings) error {
+	path, err := StatusHistoryPath(id)
+	if err != nil {
+		return err
+	}
+	key := strings.Join([]string{sample.Status, sample.Previous, sample.Hook, sample.Tmux, sample.Substate,
+		sample.TitleState, fmt.Sprint(sample.Acknowledged, sample.Prompt), sample.Hash}, "|")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	st := r.sessions[id]
+	if st == nil {
+		st = &statusHistoryState{lines: countStatusSamples(path)}
+		r.sessions[id] = st
+	}
+	if st.key == key {
+		return nil
+	}
+	if sample.Hash != "" && sample.Hash != st.hash {
+		sample.Capture = redactCaptureTail(capture, settings.GetCaptureLines())
+		st.hash = sample.Hash
+	}
+	st.key = key
+
+	line, err := json.Marshal(sample)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	_, werr := f.Write(append(line, '\n'))
+	if cerr := f.Close(); werr == nil {
+		werr = cerr
+	}
+	if werr != nil {
+		return werr
+	}
+	st.lines++
+
+	keep := settings.GetSamples()
+	if st.lines > 2*keep {
+		samples, err := readStatusSamplesFile(path)
+		if err != nil {
+			return err
+		}
+		if len(samples) > keep {
+			samples = samples[len(samples)-keep:]
+		}
+		var buf bytes.Buffer
+		for _, s := range samples {
+			b, err := json.Marshal(s)
+			if err != nil {
+				return err
+			}
+			buf.Write(b)
+			buf.WriteByte('\n')
+		}
+		if err := writeJSONFileAtomic(path, buf.Bytes(), 0o600); err != nil {
+			return err
+		}
+		st.lines = len(samples)
+	}
+	return nil
+}
+
+// ReadStatusHistory returns a session's recorded samples, oldest first. A
+// session that was never recorded yields os.ErrNotExist.
+func ReadStatusHistory(sessionID string) ([]StatusSample, error) {
+	path, err := StatusHistoryPath(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	statusHistory.mu.Lock()
+	defer statusHistory.mu.Unlock()
+	return readStatusSamplesFile(path)
+}
+
+func readStatusSamplesFile(path string) ([]StatusSample, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var samples []StatusSample
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64<<10), 16<<20)
+	for sc.Scan() {
+		var s StatusSample
+		if json.Unmarshal(sc.Bytes(), &s) == nil {
+			samples = append(samples, s)
+		}
+	}
+	return samples, sc.Err()
+}
+
+func countStatusSamples(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	return bytes.Count(data, []byte{'\n'})
+}
+
+// captureSecretPatterns match credentials that commonly show up in a pane:
+// API keys and tokens by their well-known prefixes, bearer headers, JWTs,
+// and key=value / key: value pairs with a secret-looking key.
+var captureSecretPatterns = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`\b(sk-(?:ant-)?[A-Za-z0-9_-]{16,}|gh[pousr]_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,}|xox[abprs]-[A-Za-z0-9-]{10,}|AKIA[0-9A-Z]{16}|AIza[0-9A-Za-z_-]{30,})`), "[REDACTED]"},
+	{regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`), "[REDACTED]"},
+	{regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9._~+/=-]{12,}`), "${1}[REDACTED]"},
+	{regexp.MustCompile(`(?i)\b([A-Za-z0-9_]*(?:password|passwd|secret|token|api[_-]?key)[A-Za-z0-9_]*["']?\s*[:=]\s*)["']?[^\s"']{4,}["']?`), "${1}[REDACTED]"},
+}
+
+// redactCapture replaces credentials in one line of pane text.
+func redactCapture(line string) string {
+	for _, p := range captureSecretPatterns {
+		line = p.re.ReplaceAllString(line, p.repl)
+	}
+	return line
+}
+
+// redactCaptureTail returns the last n non-trailing-blank lines of a
+// normalized capture with credentials redacted.
+func redactCaptureTail(capture string, n int) []string {
+	lines := strings.Split(strings.TrimRight(capture, "\n \t"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	for i, l := range lines {
+		lines[i] = redactCapture(l)
+	}
+	if len(lines) == 1 && lines[0] == "" {
+		return nil
+	}
+	return lines
+}
@@ -0,0 +1,70 @@
+package session
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestStatusHistoryRecorder_DedupesAndTrims(t *testing.T) {
+	id := "status-history-test"
+	path, err := StatusHistoryPath(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = os.Remove(path)
+	t.Cleanup(func() { _ = os.Remove(path) })
+
+	r := &statusHistoryRecorder{sessions: make(map[string]*statusHistoryState)}
+	settings := StatusHistorySettings{Enabled: true, Samples: 3, CaptureLines: 2}
+	record := func(status, hash, capture string) {
+		t.Helper()
+		if err := r.record(id, StatusSample{Status: status, Tmux: status, Hash: hash}, capture, settings); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	record("running", "aaa", "one\ntwo\nthree\n")
+	record("running", "aaa", "one\ntwo\nthree\n") // repeat: dropped
+	record("waiting", "aaa", "one\ntwo\nthree\n") // same pane: no capture
+
+	samples, err := ReadStatusHistory(id)
+	if err != nil || len(samples) != 2 {
+		t.Fatalf("samples = %+v, %v; want 2", samples, err)
+	}
+	if got := strings.Join(samples[0].Capture, "|"); got != "two|three" {
+		t.Errorf("first capture = %q, want the last 2 lines", got)
+	}
+	if samples[1].Capture != nil {
+		t.Errorf("unchanged pane should not repeat its capture: %v", samples[1].Capture)
+	}
+
+	// Past twice the ring size the file is cut back to the newest samples.
+	for i, st := range []string{"running", "waiting", "running", "waiting", "idle"} {
+		record(st, string(rune('b'+i)), "x")
+	}
+	samples, err = ReadStatusHistory(id)
+	if err != nil || len(samples) != 3 {
+		t.Fatalf("after trim: %d samples, %v; want 3", len(samples), err)
+	}
+	if samples[2].Status != "idle" {
+		t.Errorf("newest sample = %+v, want idle", samples[2])
+	}
+}
+
+func TestRedactCapture(t *testing.T) {
+	cases := map[string]string{
+		"export ANTHROPIC_API_KEY=sk-ant-REDACTED": "export ANTHROPIC_API_KEY=[REDACTED]",
+		"curl -H 'Authorization: Bearer abc.def.ghijklmnop'":     "curl -H 'Authorization: Bearer [REDACTED]'",
+		"token: ghp_0123456789abcdefghijABCD":                    "token: [REDACTED]",
+		"pushed with ghp_0123456789abcdefghijABCD ok":            "pushed with [REDACTED] ok",
+		`{"password": "hunter22"}`:                               `{"password": [REDACTED]}`,
+		"⏺ Running tests (12 passed)":                            "⏺ Running tests (12 passed)",
+		"see the token budget in the docs":                       "see the token budget in the docs",
+	}
+	for in, want := range cases {
+		if got := redactCapture(in); got != want {
+			t.Errorf("redactCapture(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package session
+
+import (
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// StatusSpan is a stretch of time a session spent in one status. End is the
+// next change, or now for the current status.
+type StatusSpan struct {
+	Status Status
+	Start  time.Time
+	End    time.Time
+}
+
+// Duration is how long the span lasted.
+func (s StatusSpan) Duration() time.Duration { return s.End.Sub(s.Start) }
+
+// StatusTimeline is a session's status history as consecutive spans.
+type StatusTimeline struct {
+	Spans []StatusSpan
+}
+
+// BuildStatusTimeline turns recorded status changes (oldest first) into
+// spans ending at now. Spans before since are clipped to it, so totals cover
+// the window only; a zero since keeps the whole history. Consecutive rows
+// with the same status are merged.
+func BuildStatusTimeline(rows []statedb.StatusTransitionRow, since, now time.Time) StatusTimeline {
+	var tl StatusTimeline
+	for i, r := range rows {
+		end := now
+		if i+1 < len(rows) {
+			end = rows[i+1].At
+		}
+		start := r.At
+		if !since.IsZero() && start.Before(since) {
+			start = since
+		}
+		if !end.After(start) && i+1 < len(rows) {
+			continue
+		}
+		status := Status(r.Status)
+		if n := len(tl.Spans); n > 0 && tl.Spans[n-1].Status == status {
+			tl.Spans[n-1].End = end
+			continue
+		}
+		tl.Spans = append(tl.Spans, StatusSpan{Status: status, Start: start, End: end})
+	}
+	return tl
+}
+
+// Totals sums the time spent in each status.
+func (t StatusTimeline) Totals() map[Status]time.Duration {
+	totals := make(map[Status]time.Duration)
+	for _, s := range t.Spans {
+		totals[s.Status] += s.Duration()
+	}
+	return totals
+}
+
+// Span is the time from the first span's start to the last span's end.
+func (t StatusTimeline) Span() time.Duration {
+	if len(t.Spans) == 0 {
+		return 0
+	}
+	return t.Spans[len(t.Spans)-1].End.Sub(t.Spans[0].Start)
+}
+
+// LoadStatusTimeline reads a session's status history from db and builds
+// its timeline since the given time (zero for all of it).
+func LoadStatusTimeline(db *statedb.StateDB, id string, since time.Time) (StatusTimeline, error) {
+	rows, err := db.LoadStatusTransitions(id, since)
+	if err != nil {
+		return StatusTimeline{}, err
+	}
+	return BuildStatusTimeline(rows, since, time.Now()), nil
+}
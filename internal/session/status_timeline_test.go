@@ -0,0 +1,44 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+func TestBuildStatusTimeline_SpansAndTotals(t *testing.T) {
+	base := time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC)
+	rows := []statedb.StatusTransitionRow{
+		{Status: "running", At: base},
+		{Status: "waiting", At: base.Add(20 * time.Minute)},
+		{Status: "waiting", At: base.Add(25 * time.Minute)}, // merged
+		{Status: "running", At: base.Add(50 * time.Minute)},
+		{Status: "idle", At: base.Add(60 * time.Minute)},
+	}
+	now := base.Add(90 * time.Minute)
+
+	tl := BuildStatusTimeline(rows, time.Time{}, now)
+	if len(tl.Spans) != 4 {
+		t.Fatalf("spans = %+v, want 4 after merging the repeated waiting", tl.Spans)
+	}
+	if s := tl.Spans[1]; s.Status != StatusWaiting || s.Duration() != 30*time.Minute {
+		t.Errorf("waiting span = %+v, want 30m", s)
+	}
+	totals := tl.Totals()
+	if totals[StatusRunning] != 30*time.Minute || totals[StatusWaiting] != 30*time.Minute || totals[StatusIdle] != 30*time.Minute {
+		t.Errorf("totals = %v", totals)
+	}
+	if tl.Span() != 90*time.Minute {
+		t.Errorf("Span = %v, want 90m", tl.Span())
+	}
+
+	// A window starting mid-wait clips the first span to the window.
+	clipped := BuildStatusTimeline(rows[1:], base.Add(40*time.Minute), now)
+	if s := clipped.Spans[0]; s.Status != StatusWaiting || !s.Start.Equal(base.Add(40*time.Minute)) || s.Duration() != 10*time.Minute {
+		t.Errorf("clipped first span = %+v", s)
+	}
+	if clipped.Span() != 50*time.Minute {
+		t.Errorf("clipped Span = %v, want 50m", clipped.Span())
+	}
+}
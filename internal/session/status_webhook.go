@@ -0,0 +1,237 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultWebhookTimeout bounds one POST when timeout_secs is unset.
+	defaultWebhookTimeout = 10 * time.Second
+	// defaultWebhookRetries is how many times a failed POST is retried.
+	defaultWebhookRetries = 3
+	// maxConcurrentWebhooks caps in-flight deliveries, retries included;
+	// transitions arriving while every slot is busy are dropped and logged.
+	maxConcurrentWebhooks = 8
+	// WebhookSignatureHeader carries "sha256=<hex HMAC of the body>" when a
+	// secret is configured.
+	WebhookSignatureHeader = "X-AgentDeck-Signature-256"
+)
+
+// webhookSlots is the semaphore behind maxConcurrentWebhooks.
+var webhookSlots = make(chan struct{}, maxConcurrentWebhooks)
+
+// webhookHTTPClient sends webhook requests. Swapped in tests.
+var webhookHTTPClient = &http.Client{}
+
+// webhookBackoff is the wait before retry n (1-based): 1s, 2s, 4s, ...
+// capped at 30s. Swapped in tests.
+var webhookBackoff = func(n int) time.Duration {
+	d := time.Second << (n - 1)
+	if d <= 0 || d > 30*time.Second {
+		return 30 * time.Second
+	}
+	return d
+}
+
+// StatusWebhookSettings POSTs a JSON payload to a URL when sessions change status:
+//
+//	[notifications.webhook]
+//	url = "https://example.com/agent-deck"
+//	secret = "s3cret"          # optional HMAC-SHA256 signing key
+//	on = ["waiting", "error"]  # default
+type StatusWebhookSettings struct {
+	// URL receives the POST. Empty disables the webhook.
+	URL string `toml:"url,omitempty"`
+
+	// Secret signs the body with HMAC-SHA256 in the
+	// X-AgentDeck-Signature-256 header ("sha256=<hex>"). Supports env var
+	// references like "$AGENTDECK_HOOK_SECRET". Empty sends unsigned
+	// requests.
+	Secret string `toml:"secret,omitempty"`
+
+	// On lists the statuses whose transitions are sent (default: waiting,
+	// error).
+	On []string `toml:"on,omitempty"`
+
+	// MaxRetries is how many times a failed delivery (network error, 429 or
+	// 5xx) is retried with exponential backoff (default: 3; negative
+	// disables retries).
+	MaxRetries int `toml:"max_retries,omitzero"`
+
+	// TimeoutSecs bounds each request (default: 10).
+	TimeoutSecs int `toml:"timeout_secs,omitzero"`
+}
+
+// Enabled reports whether a webhook URL is configured.
+func (w StatusWebhookSettings) Enabled() bool {
+	return strings.TrimSpace(w.URL) != ""
+}
+
+// Statuses returns the statuses that trigger the webhook.
+func (w StatusWebhookSettings) Statuses() []string {
+	if len(w.On) == 0 {
+		return []string{string(StatusWaiting), string(StatusError)}
+	}
+	out := make([]string, 0, len(w.On))
+	for _, s := range w.On {
+		out = append(out, strings.ToLower(strings.TrimSpace(s)))
+	}
+	return out
+}
+
+// GetMaxRetries returns the retry limit (default: 3).
+func (w StatusWebhookSettings) GetMaxRetries() int {
+	switch {
+	case w.MaxRetries < 0:
+		return 0
+	case w.MaxRetries == 0:
+		return defaultWebhookRetries
+	}
+	return w.MaxRetries
+}
+
+// Timeout returns the per-request timeout.
+func (w StatusWebhookSettings) Timeout() time.Duration {
+	if w.TimeoutSecs > 0 {
+		return time.Duration(w.TimeoutSecs) * time.Second
+	}
+	return defaultWebhookTimeout
+}
+
+// GetStatusWebhookSettings returns the [notifications.webhook] table with
+// env var references in the URL and secret expanded.
+func GetStatusWebhookSettings() StatusWebhookSettings {
+	settings := GetNotificationsSettings().Webhook
+	settings.URL = os.ExpandEnv(settings.URL)
+	settings.Secret = os.ExpandEnv(settings.Secret)
+	return settings
+}
+
+// WebhookPayload is the JSON body of a status webhook.
+type WebhookPayload struct {
+	Event      string    `json:"event"` // always "session.status"
+	ID         string    `json:"id"`
+	Title      string    `json:"title"`
+	Status     string    `json:"status"`
+	PrevStatus string    `json:"prev_status"`
+	Path       string    `json:"path"`
+	Group      string    `json:"group"`
+	Tool       string    `json:"tool"`
+	Profile    string    `json:"profile"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// SignWebhookBody returns the signature header value for body.
+func SignWebhookBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SendStatusWebhook posts a from→to transition of inst to the
+// [notifications.webhook] URL in the background and reports whether a
+// delivery was started. Like RunEventHooks, failures are logged, never
+// returned, so an unreachable endpoint cannot stall the status loop.
+func SendStatusWebhook(inst *Instance, profile, from, to string) bool {
+	if inst == nil || from == to {
+		return false
+	}
+	settings := GetStatusWebhookSettings()
+	if !settings.Enabled() || !slices.Contains(settings.Statuses(), to) {
+		return false
+	}
+	body, err := json.Marshal(WebhookPayload{
+		Event:      "session.status",
+		ID:         inst.ID,
+		Title:      inst.Title,
+		Status:     to,
+		PrevStatus: from,
+		Path:       inst.ProjectPath,
+		Group:      inst.GroupPath,
+		Tool:       inst.Tool,
+		Profile:    profile,
+		Timestamp:  time.Now().UTC(),
+	})
+	if err != nil {
+		return false
+	}
+
+	select {
+	case webhookSlots <- struct{}{}:
+	default:
+		eventHookLog.Warn("status_webhook_dropped",
+			slog.String("session", inst.ID), slog.Int("in_flight", maxConcurrentWebhooks))
+		return false
+	}
+	go func(id string) {
+		defer func() { <-webhookSlots }()
+		if err := deliverWebhook(settings, body); err != nil {
+			eventHookLog.Warn("status_webhook_failed",
+				slog.String("session", id), slog.String("status", to), slog.String("error", err.Error()))
+			return
+		}
+		eventHookLog.Debug("status_webhook_sent", slog.String("session", id), slog.String("status", to))
+	}(inst.ID)
+	return true
+}
+
+// deliverWebhook POSTs body, retrying network errors, 429 and 5xx responses
+// with exponential backoff. Other 4xx responses are not retried: the
+// request itself is wrong.
+func deliverWebhook(settings StatusWebhookSettings, body []byte) error {
+	var lastErr error
+	for attempt := 0; attempt <= settings.GetMaxRetries(); attempt++ {
+		if attempt > 0 {
+			time.Sleep(webhookBackoff(attempt))
+		}
+		retry, err := postWebhook(settings, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+	return lastErr
+}
+
+// postWebhook sends one request and reports whether a failure is worth
+// retrying.
+func postWebhook(settings StatusWebhookSettings, body []byte) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), settings.Timeout())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSpace(settings.URL), bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "agent-deck")
+	if settings.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhookBody(settings.Secret, body))
+	}
+	resp, err := webhookHTTPClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("webhook returned %s", resp.Status)
+}
@@ -0,0 +1,113 @@
+package session
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStatusWebhookSettings_Defaults(t *testing.T) {
+	var s StatusWebhookSettings
+	if s.Enabled() || s.GetMaxRetries() != 3 || s.Timeout() != 10*time.Second {
+		t.Errorf("defaults: enabled=%v retries=%d timeout=%v", s.Enabled(), s.GetMaxRetries(), s.Timeout())
+	}
+	if got := s.Statuses(); len(got) != 2 || got[0] != "waiting" || got[1] != "error" {
+		t.Errorf("default statuses = %v", got)
+	}
+	s = StatusWebhookSettings{URL: " http://x ", On: []string{" Idle "}, MaxRetries: -1}
+	if !s.Enabled() || s.GetMaxRetries() != 0 || s.Statuses()[0] != "idle" {
+		t.Errorf("custom: %+v", s)
+	}
+}
+
+func TestDeliverWebhook_SignsAndRetries(t *testing.T) {
+	origBackoff := webhookBackoff
+	webhookBackoff = func(int) time.Duration { return time.Millisecond }
+	t.Cleanup(func() { webhookBackoff = origBackoff })
+
+	var calls atomic.Int32
+	var gotSig string
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		gotSig = r.Header.Get(WebhookSignatureHeader)
+		gotBody, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	body := []byte(`{"status":"waiting"}`)
+	if err := deliverWebhook(StatusWebhookSettings{URL: srv.URL, Secret: "k"}, body); err != nil {
+		t.Fatalf("deliverWebhook: %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("calls = %d, want 3 (two 503s, then success)", calls.Load())
+	}
+	if string(gotBody) != string(body) || gotSig != SignWebhookBody("k", body) {
+		t.Errorf("body %s sig %s", gotBody, gotSig)
+	}
+}
+
+func TestDeliverWebhook_ClientErrorNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	if err := deliverWebhook(StatusWebhookSettings{URL: srv.URL}, []byte(`{}`)); err == nil {
+		t.Fatal("expected error for 400")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, want 1", calls.Load())
+	}
+}
+
+func TestSendStatusWebhook_FiltersStatuses(t *testing.T) {
+	channelsTestEnv(t)
+	received := make(chan WebhookPayload, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		var p WebhookPayload
+		_ = json.NewDecoder(r.Body).Decode(&p)
+		received <- p
+	}))
+	defer srv.Close()
+
+	configPath, err := GetUserConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(configPath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	config := "[notifications.webhook]\nurl = \"" + srv.URL + "\"\n"
+	if err := os.WriteFile(configPath, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	ClearUserConfigCache()
+
+	inst := &Instance{ID: "abc", Title: "api", Tool: "claude", GroupPath: "work"}
+	if SendStatusWebhook(inst, "default", "waiting", "idle") {
+		t.Error("idle transition should not be sent by default")
+	}
+	if !SendStatusWebhook(inst, "default", "running", "waiting") {
+		t.Fatal("waiting transition not sent")
+	}
+	select {
+	case p := <-received:
+		if p.Event != "session.status" || p.ID != "abc" || p.Status != "waiting" || p.PrevStatus != "running" || p.Group != "work" {
+			t.Errorf("payload = %+v", p)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook not delivered")
+	}
+}
@@ -1,6 +1,7 @@
 package session
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -145,6 +146,28 @@ type InstanceData struct {
 
 	// IdleTimeoutSecs mirrors Instance.IdleTimeoutSecs (#1143). 0 = disabled.
 	IdleTimeoutSecs int64 `json:"idle_timeout_secs,omitempty"`
+
+	// WorkingHours mirrors Instance.WorkingHours (per-session override).
+	WorkingHours string `json:"working_hours,omitempty"`
+
+	// Checkpoint mirrors Instance.Checkpoint (automatic checkpoint mode).
+	Checkpoint string `json:"checkpoint,omitempty"`
+
+	// Labels mirrors Instance.Labels.
+	Labels []string `json:"labels,omitempty"`
+
+	// Preset mirrors Instance.Preset (start preset name).
+	Preset string `json:"preset,omitempty"`
+
+	// DependsOn mirrors Instance.DependsOn (session prerequisites).
+	DependsOn []string `json:"depends_on,omitempty"`
+
+	// Layout and Panes mirror Instance.Layout and Instance.Panes.
+	Layout string          `json:"layout,omitempty"`
+	Panes  []tmux.PaneSpec `json:"panes,omitempty"`
+
+	// Dispatch mirrors Instance.Dispatch (deferred conductor dispatch).
+	Dispatch *DispatchRequest `json:"dispatch,omitempty"`
 }
 
 // GroupData represents serializable group data
@@ -158,6 +181,8 @@ type GroupData struct {
 	// 0 = unlimited (legacy default for groups predating this field); 1 = serial
 	// (default for newly-created groups); N>=2 = bounded parallelism.
 	MaxConcurrent int `json:"max_concurrent,omitempty"`
+	// DefaultMCPs are attached to new sessions in the group.
+	DefaultMCPs []string `json:"default_mcps,omitempty"`
 }
 
 // Storage handles persistence of session data via SQLite.
@@ -311,6 +336,8 @@ func (s *Storage) Save(instances []*Instance) error {
 // DeleteInstance / RemoveSessionAndVerify at the moment the user deletes a
 // session, or statedb.ClearAllInstances for an intentional full wipe.
 func (s *Storage) SaveWithGroups(instances []*Instance, groupTree *GroupTree) error {
+	_, span := logging.StartSpan(context.Background(), "storage_save", slog.Int("sessions", len(instances)))
+	defer span.End()
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -347,6 +374,7 @@ func (s *Storage) SaveWithGroups(instances []*Instance, groupTree *GroupTree) er
 				Order:         g.Order,
 				DefaultPath:   g.DefaultPath,
 				MaxConcurrent: g.MaxConcurrent,
+				DefaultMCPs:   g.DefaultMCPs,
 			})
 		}
 		if err := s.db.SaveGroups(groupRows); err != nil {
@@ -845,6 +873,13 @@ func instanceToRow(inst *Instance) (*statedb.InstanceRow, error) {
 	// the positional MarshalToolData signature so legacy binaries that don't
 	// know the key preserve it via MergeToolDataExtras.
 	toolData = WriteIdleTimeoutSecsToToolData(toolData, inst.IdleTimeoutSecs)
+	toolData = WriteWorkingHoursToToolData(toolData, inst.WorkingHours, inst.workingHoursCleared)
+	toolData = WriteCheckpointToToolData(toolData, inst.Checkpoint, inst.checkpointCleared)
+	toolData = WriteLabelsToToolData(toolData, inst.Labels, inst.labelsCleared)
+	toolData = WritePresetToToolData(toolData, inst.Preset)
+	toolData = WriteLayoutToToolData(toolData, inst.Layout, inst.Panes)
+	toolData = WriteDependsOnToToolData(toolData, inst.DependsOn, inst.dependsOnCleared)
+	toolData = WriteDispatchToToolData(toolData, inst.Dispatch, inst.dispatchCleared)
 
 	return &statedb.InstanceRow{
 		ID:                  inst.ID,
@@ -900,6 +935,7 @@ func (s *Storage) SaveGroupsOnly(groupTree *GroupTree) error {
 			Order:         g.Order,
 			DefaultPath:   g.DefaultPath,
 			MaxConcurrent: g.MaxConcurrent,
+			DefaultMCPs:   g.DefaultMCPs,
 		})
 	}
 
@@ -1016,7 +1052,14 @@ func (s *Storage) LoadLite() ([]*InstanceData, []*GroupData, error) {
 			AutoLinkedChannels:        autoLinkedChannels2,
 			Color:                     color2,
 			IdleTimeoutSecs:           ReadIdleTimeoutSecsFromToolData(r.ToolData),
+			WorkingHours:              ReadWorkingHoursFromToolData(r.ToolData),
+			Checkpoint:                ReadCheckpointFromToolData(r.ToolData),
+			Labels:                    ReadLabelsFromToolData(r.ToolData),
+			Preset:                    ReadPresetFromToolData(r.ToolData),
+			DependsOn:                 ReadDependsOnFromToolData(r.ToolData),
+			Dispatch:                  ReadDispatchFromToolData(r.ToolData),
 		}
+		instances[i].Layout, instances[i].Panes = ReadLayoutFromToolData(r.ToolData)
 	}
 
 	// Convert groups
@@ -1029,6 +1072,7 @@ func (s *Storage) LoadLite() ([]*InstanceData, []*GroupData, error) {
 			Order:         g.Order,
 			DefaultPath:   g.DefaultPath,
 			MaxConcurrent: g.MaxConcurrent,
+			DefaultMCPs:   g.DefaultMCPs,
 		}
 	}
 
@@ -1135,7 +1179,14 @@ func (s *Storage) LoadWithGroups() ([]*Instance, []*GroupData, error) {
 			AutoLinkedChannels:        autoLinkedChannels,
 			Color:                     color,
 			IdleTimeoutSecs:           ReadIdleTimeoutSecsFromToolData(r.ToolData),
+			WorkingHours:              ReadWorkingHoursFromToolData(r.ToolData),
+			Checkpoint:                ReadCheckpointFromToolData(r.ToolData),
+			Labels:                    ReadLabelsFromToolData(r.ToolData),
+			Preset:                    ReadPresetFromToolData(r.ToolData),
+			DependsOn:                 ReadDependsOnFromToolData(r.ToolData),
+			Dispatch:                  ReadDispatchFromToolData(r.ToolData),
 		}
+		data.Instances[i].Layout, data.Instances[i].Panes = ReadLayoutFromToolData(r.ToolData)
 	}
 
 	// Convert groups
@@ -1148,6 +1199,7 @@ func (s *Storage) LoadWithGroups() ([]*Instance, []*GroupData, error) {
 			Order:         g.Order,
 			DefaultPath:   g.DefaultPath,
 			MaxConcurrent: g.MaxConcurrent,
+			DefaultMCPs:   g.DefaultMCPs,
 		}
 	}
 
@@ -1289,6 +1341,9 @@ func (s *Storage) convertToInstances(data *StorageData) ([]*Instance, []*GroupDa
 			// server for a session that lives on an isolated socket and
 			// report it as dead (issue #687, v1.7.50).
 			tmuxSess.SocketName = instData.TmuxSocketName
+			// Multi-pane sessions: bind status and input to the agent pane
+			// recorded when the layout was applied.
+			tmuxSess.Panes = instData.Panes
 			// Issue #663: for multi-repo sessions ProjectPath is a symlink
 			// inside MultiRepoTempDir (see home.go:7255-7364), so the
 			// restart pane must cwd into the parent dir — not the symlink
@@ -1381,6 +1436,14 @@ func (s *Storage) convertToInstances(data *StorageData) ([]*Instance, []*GroupDa
 			AutoLinkedChannels:        instData.AutoLinkedChannels,
 			Color:                     instData.Color,
 			IdleTimeoutSecs:           instData.IdleTimeoutSecs,
+			WorkingHours:              instData.WorkingHours,
+			Checkpoint:                instData.Checkpoint,
+			Labels:                    instData.Labels,
+			Preset:                    instData.Preset,
+			DependsOn:                 instData.DependsOn,
+			Layout:                    instData.Layout,
+			Panes:                     instData.Panes,
+			Dispatch:                  instData.Dispatch,
 			Sandbox:                   instData.Sandbox,
 			SandboxContainer:          instData.SandboxContainer,
 			SSHHost:                   instData.SSHHost,
@@ -1406,6 +1469,13 @@ func (s *Storage) convertToInstances(data *StorageData) ([]*Instance, []*GroupDa
 			tmuxSess.OptionOverrides = inst.buildTmuxOptionOverrides()
 		}
 
+		// Configured status patterns apply to reconnected sessions too.
+		// Without a [tools.<name>] entry the tmux layer uses the cached
+		// built-in defaults, so the compile is skipped.
+		if tmuxSess != nil && toolPatternConfig(inst.Tool) != nil {
+			inst.loadCustomPatternsFromConfig()
+		}
+
 		// PERFORMANCE: Skip UpdateStatus at load time - use cached status from SQLite
 		// The background worker will update status on first tick.
 		// This saves one subprocess call per session at startup.
@@ -1414,6 +1484,9 @@ func (s *Storage) convertToInstances(data *StorageData) ([]*Instance, []*GroupDa
 		// Session ID syncing (SetEnvironment calls) will happen on EnsureConfigured()
 		// or when the session is restarted. This saves 0-4 subprocess calls per session.
 
+		// A stat (no subprocess) flags projects that were moved or renamed.
+		inst.markProjectPathMissing()
+
 		instances[i] = inst
 	}
 
@@ -1433,6 +1506,8 @@ func statusToString(s Status) string {
 		return "waiting" // Treat errors as needing attention
 	case StatusStopped:
 		return "inactive" // Stopped sessions are intentionally inactive
+	case StatusExited, StatusCrashed:
+		return "inactive" // The agent process ended; only its pane remains
 	default:
 		return "waiting"
 	}
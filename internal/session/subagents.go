@@ -0,0 +1,338 @@
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sub-agent visibility: a Claude session running Task/Agent tool calls is
+// doing several things at once, but its row only says "active". The
+// transcript records each spawn (an assistant tool_use named Task or Agent),
+// the sub-agent's own steps (progress records carrying parentToolUseID) and
+// its end (the matching tool_result). subagentTracker tails the JSONL
+// incrementally and keeps that picture, so the TUI can show each running
+// sub-agent as an ephemeral child row with its latest step.
+
+// subagentRefreshInterval rate-limits RefreshSubagents per session.
+const subagentRefreshInterval = 2 * time.Second
+
+// subagentKeepDone is how many finished sub-agents are remembered after
+// their tool_result, so a long session does not grow the map without bound.
+const subagentKeepDone = 20
+
+// Subagent is one sub-agent spawned by a Claude session.
+type Subagent struct {
+	ToolUseID   string    // id of the spawning tool_use
+	Type        string    // subagent_type ("general-purpose", "Explore", ...)
+	Description string    // short task description given to the sub-agent
+	Progress    string    // latest step, e.g. "Grep: TODO" (empty until one is seen)
+	StartedAt   time.Time // spawn time from the transcript
+	UpdatedAt   time.Time // time of the latest progress record
+	Done        bool      // the tool_result came back
+}
+
+// Label is the sub-agent's display name: its description, else its type.
+func (s Subagent) Label() string {
+	if s.Description != "" {
+		return s.Description
+	}
+	if s.Type != "" {
+		return s.Type
+	}
+	return "sub-agent"
+}
+
+// subagentTracker incrementally parses one transcript. It resets when the
+// path changes (new Claude session) or the file shrinks (rewrite).
+type subagentTracker struct {
+	mu          sync.Mutex
+	path        string
+	offset      int64
+	partial     []byte
+	agents      map[string]*Subagent
+	order       []string
+	lastRefresh time.Time
+}
+
+func newSubagentTracker() *subagentTracker {
+	return &subagentTracker{agents: map[string]*Subagent{}}
+}
+
+func (t *subagentTracker) reset(path string) {
+	t.path = path
+	t.offset = 0
+	t.partial = nil
+	t.agents = map[string]*Subagent{}
+	t.order = nil
+}
+
+// consume reads the bytes appended to path since the last call. A trailing
+// line without a newline is kept until it is complete.
+func (t *subagentTracker) consume(path string) error {
+	if path != t.path {
+		t.reset(path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if fi.Size() < t.offset {
+		t.reset(path)
+	}
+	if fi.Size() == t.offset {
+		return nil
+	}
+	if _, err := f.Seek(t.offset, io.SeekStart); err != nil {
+		return err
+	}
+	r := bufio.NewReaderSize(f, 64*1024)
+	for {
+		line, err := r.ReadBytes('\n')
+		t.offset += int64(len(line))
+		if err != nil {
+			t.partial = append(t.partial, line...)
+			break
+		}
+		if len(t.partial) > 0 {
+			line = append(t.partial, line...)
+			t.partial = nil
+		}
+		t.parseLine(line)
+	}
+	t.prune()
+	return nil
+}
+
+// subagentRecord is the subset of a transcript line the tracker reads.
+type subagentRecord struct {
+	Type            string          `json:"type"`
+	Timestamp       string          `json:"timestamp"`
+	IsSidechain     bool            `json:"isSidechain"`
+	ParentToolUseID string          `json:"parentToolUseID"`
+	Message         json.RawMessage `json:"message"`
+	Data            struct {
+		Message struct {
+			Message json.RawMessage `json:"message"`
+		} `json:"message"`
+	} `json:"data"`
+}
+
+type subagentContentBlock struct {
+	Type      string          `json:"type"`
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Input     json.RawMessage `json:"input"`
+	ToolUseID string          `json:"tool_use_id"`
+	Text      string          `json:"text"`
+}
+
+func (t *subagentTracker) parseLine(line []byte) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return
+	}
+	var rec subagentRecord
+	if err := json.Unmarshal(line, &rec); err != nil {
+		return
+	}
+	ts, _ := time.Parse(time.RFC3339Nano, rec.Timestamp)
+
+	switch rec.Type {
+	case "assistant":
+		if rec.IsSidechain {
+			return // a sub-agent's own turn, not a spawn by the session
+		}
+		for _, b := range messageBlocks(rec.Message) {
+			if b.Type != "tool_use" || (b.Name != "Task" && b.Name != "Agent") || b.ID == "" {
+				continue
+			}
+			var in struct {
+				Description  string `json:"description"`
+				SubagentType string `json:"subagent_type"`
+			}
+			_ = json.Unmarshal(b.Input, &in)
+			if _, ok := t.agents[b.ID]; !ok {
+				t.order = append(t.order, b.ID)
+			}
+			t.agents[b.ID] = &Subagent{
+				ToolUseID:   b.ID,
+				Type:        in.SubagentType,
+				Description: in.Description,
+				StartedAt:   ts,
+				UpdatedAt:   ts,
+			}
+		}
+	case "user":
+		if rec.IsSidechain {
+			return
+		}
+		for _, b := range messageBlocks(rec.Message) {
+			if b.Type != "tool_result" {
+				continue
+			}
+			if a, ok := t.agents[b.ToolUseID]; ok {
+				a.Done = true
+				a.UpdatedAt = ts
+			}
+		}
+	case "progress":
+		a, ok := t.agents[rec.ParentToolUseID]
+		if !ok || a.Done {
+			return
+		}
+		if step := progressStep(messageBlocks(rec.Data.Message.Message)); step != "" {
+			a.Progress = step
+		}
+		if !ts.IsZero() {
+			a.UpdatedAt = ts
+		}
+	}
+}
+
+// prune forgets the oldest finished sub-agents beyond subagentKeepDone.
+func (t *subagentTracker) prune() {
+	done := 0
+	for _, id := range t.order {
+		if t.agents[id].Done {
+			done++
+		}
+	}
+	if done <= subagentKeepDone {
+		return
+	}
+	kept := t.order[:0]
+	for _, id := range t.order {
+		if done > subagentKeepDone && t.agents[id].Done {
+			delete(t.agents, id)
+			done--
+			continue
+		}
+		kept = append(kept, id)
+	}
+	t.order = kept
+}
+
+// active returns the sub-agents still running, in spawn order.
+func (t *subagentTracker) active() []Subagent {
+	var out []Subagent
+	for _, id := range t.order {
+		if a := t.agents[id]; !a.Done {
+			out = append(out, *a)
+		}
+	}
+	return out
+}
+
+// messageBlocks returns the content blocks of a message; a plain string
+// content yields none.
+func messageBlocks(raw json.RawMessage) []subagentContentBlock {
+	if len(raw) == 0 {
+		return nil
+	}
+	var msg struct {
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return nil
+	}
+	var blocks []subagentContentBlock
+	_ = json.Unmarshal(msg.Content, &blocks)
+	return blocks
+}
+
+// progressStep summarizes a sub-agent message as one short step: its last
+// tool call with the most telling input field, else its last text.
+func progressStep(blocks []subagentContentBlock) string {
+	for i := len(blocks) - 1; i >= 0; i-- {
+		b := blocks[i]
+		switch b.Type {
+		case "tool_use":
+			var in map[string]interface{}
+			_ = json.Unmarshal(b.Input, &in)
+			for _, key := range []string{"description", "command", "pattern", "file_path", "path", "query", "url", "prompt"} {
+				if v, ok := in[key].(string); ok && strings.TrimSpace(v) != "" {
+					return b.Name + ": " + firstLine(v)
+				}
+			}
+			return b.Name
+		case "text":
+			if s := firstLine(b.Text); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	return s
+}
+
+// RefreshSubagents re-reads the session's transcript for sub-agent
+// activity. Calls within subagentRefreshInterval of the last one are
+// no-ops; sessions that are not Claude or not running have none.
+func (i *Instance) RefreshSubagents() {
+	if !IsClaudeCompatible(i.Tool) {
+		return
+	}
+	t := i.subagentTracker()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if time.Since(t.lastRefresh) < subagentRefreshInterval {
+		return
+	}
+	t.lastRefresh = time.Now()
+	if i.GetStatusThreadSafe() != StatusRunning {
+		t.reset(t.path) // a turn ended: every sub-agent it spawned is over
+		t.offset = fileSize(t.path)
+		return
+	}
+	path := i.GetJSONLPath()
+	if path == "" {
+		return
+	}
+	_ = t.consume(path)
+}
+
+// ActiveSubagents returns the sub-agents the session has running, as of
+// the last RefreshSubagents.
+func (i *Instance) ActiveSubagents() []Subagent {
+	if !IsClaudeCompatible(i.Tool) {
+		return nil
+	}
+	t := i.subagentTracker()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.active()
+}
+
+func (i *Instance) subagentTracker() *subagentTracker {
+	i.subagentsOnce.Do(func() { i.subagents = newSubagentTracker() })
+	return i.subagents
+}
+
+func fileSize(path string) int64 {
+	if path == "" {
+		return 0
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return fi.Size()
+}
@@ -0,0 +1,79 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const subagentTranscript = `{"type":"user","timestamp":"2026-10-16T09:00:00Z","message":{"role":"user","content":"audit the repo"}}
+{"type":"assistant","timestamp":"2026-10-16T09:00:01Z","message":{"role":"assistant","content":[{"type":"text","text":"Spawning two agents."},{"type":"tool_use","id":"toolu_a","name":"Task","input":{"description":"Find TODOs","subagent_type":"Explore","prompt":"..."}},{"type":"tool_use","id":"toolu_b","name":"Agent","input":{"description":"Review tests","subagent_type":"general-purpose","prompt":"..."}},{"type":"tool_use","id":"toolu_c","name":"Bash","input":{"command":"ls"}}]}}
+{"type":"progress","timestamp":"2026-10-16T09:00:05Z","parentToolUseID":"toolu_a","data":{"type":"agent_progress","message":{"type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","id":"toolu_a1","name":"Grep","input":{"pattern":"TODO\nmore"}}]}}}}
+{"type":"progress","timestamp":"2026-10-16T09:00:06Z","parentToolUseID":"toolu_b","data":{"type":"agent_progress","message":{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Reading the test layout"}]}}}}
+{"type":"assistant","timestamp":"2026-10-16T09:00:07Z","isSidechain":true,"message":{"role":"assistant","content":[{"type":"tool_use","id":"toolu_side","name":"Task","input":{"description":"nested"}}]}}
+{"type":"user","timestamp":"2026-10-16T09:00:09Z","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"toolu_b","content":"done"}]}}
+`
+
+func TestSubagentTracker_SpawnProgressDone(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "s.jsonl")
+	// The last line is written without its newline: it is held back until
+	// Claude finishes writing it.
+	head, tail, _ := strings.Cut(subagentTranscript, `{"type":"user","timestamp":"2026-10-16T09:00:09Z"`)
+	tail = `{"type":"user","timestamp":"2026-10-16T09:00:09Z"` + tail
+	if err := os.WriteFile(path, []byte(head+tail[:40]), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tr := newSubagentTracker()
+	if err := tr.consume(path); err != nil {
+		t.Fatal(err)
+	}
+	got := tr.active()
+	if len(got) != 2 {
+		t.Fatalf("active = %+v, want Task and Agent spawns only (not Bash or sidechain)", got)
+	}
+	if got[0].Label() != "Find TODOs" || got[0].Type != "Explore" || got[0].Progress != "Grep: TODO" {
+		t.Fatalf("first sub-agent = %+v", got[0])
+	}
+	if got[1].Progress != "Reading the test layout" || got[1].UpdatedAt.Second() != 6 {
+		t.Fatalf("second sub-agent = %+v", got[1])
+	}
+
+	if err := os.WriteFile(path, []byte(head+tail), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.consume(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := tr.active(); len(got) != 1 || got[0].ToolUseID != "toolu_a" {
+		t.Fatalf("after tool_result active = %+v, want only toolu_a", got)
+	}
+
+	// A rewritten (shorter) transcript starts over.
+	if err := os.WriteFile(path, []byte("{}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.consume(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := tr.active(); len(got) != 0 {
+		t.Fatalf("after truncation active = %+v", got)
+	}
+}
+
+func TestSubagentTracker_PrunesFinished(t *testing.T) {
+	tr := newSubagentTracker()
+	for n := 0; n < subagentKeepDone+5; n++ {
+		id := "toolu_" + string(rune('a'+n))
+		tr.parseLine([]byte(`{"type":"assistant","message":{"content":[{"type":"tool_use","id":"` + id + `","name":"Task","input":{}}]}}`))
+		tr.parseLine([]byte(`{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"` + id + `"}]}}`))
+	}
+	tr.parseLine([]byte(`{"type":"assistant","message":{"content":[{"type":"tool_use","id":"live","name":"Task","input":{}}]}}`))
+	tr.prune()
+	if len(tr.agents) != subagentKeepDone+1 || len(tr.order) != subagentKeepDone+1 {
+		t.Fatalf("kept %d agents / %d ids, want %d", len(tr.agents), len(tr.order), subagentKeepDone+1)
+	}
+	if got := tr.active(); len(got) != 1 || got[0].Label() != "sub-agent" {
+		t.Fatalf("active = %+v", got)
+	}
+}
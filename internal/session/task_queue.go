@@ -0,0 +1,180 @@
+// Task queue: durable work items that users and conductors push with
+// `agent-deck task add` and conductors drain. A task may name its session
+// up front or leave routing to the conductor. Dispatching sends the text to
+// the session with an acknowledgment footer, so the agent itself reports
+// back with `task done` or `task fail`; until then the task stays
+// "dispatched" and shows up as outstanding. Every state change is a
+// compare-and-swap in statedb, so concurrent drainers never double-send.
+package session
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// Task statuses.
+const (
+	TaskQueued     = "queued"
+	TaskDispatched = "dispatched"
+	TaskDone       = "done"
+	TaskFailed     = "failed"
+)
+
+// TaskStatuses lists the statuses in lifecycle order.
+var TaskStatuses = []string{TaskQueued, TaskDispatched, TaskDone, TaskFailed}
+
+// OpenTaskStatuses are the statuses of tasks still needing attention.
+var OpenTaskStatuses = []string{TaskQueued, TaskDispatched}
+
+// ErrTaskNotFound is returned by FindTask when no task matches.
+var ErrTaskNotFound = errors.New("task not found")
+
+// AddTask queues text, optionally for inst (nil leaves routing to the
+// conductor). source records who queued it.
+func AddTask(db *statedb.StateDB, inst *Instance, text, source string, now time.Time) (*statedb.TaskRow, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, errors.New("task text is required")
+	}
+	t := &statedb.TaskRow{
+		ID:        "task-" + randomString(8),
+		Text:      text,
+		Status:    TaskQueued,
+		Source:    source,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if inst != nil {
+		t.SessionID = inst.ID
+	}
+	if err := db.SaveTask(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// FindTask resolves ref, a task ID or unique ID prefix (with or without
+// the "task-" prefix).
+func FindTask(db *statedb.StateDB, ref string) (*statedb.TaskRow, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, ErrTaskNotFound
+	}
+	if t, err := db.LoadTask(ref); err != nil || t != nil {
+		return t, err
+	}
+	all, err := db.LoadTasks()
+	if err != nil {
+		return nil, err
+	}
+	var match *statedb.TaskRow
+	for _, t := range all {
+		if strings.HasPrefix(t.ID, ref) || strings.HasPrefix(t.ID, "task-"+ref) {
+			if match != nil {
+				return nil, fmt.Errorf("task %q is ambiguous (%s, %s, ...)", ref, match.ID, t.ID)
+			}
+			match = t
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("%w: %s", ErrTaskNotFound, ref)
+	}
+	return match, nil
+}
+
+// NextTask returns the oldest queued task for sessionID: one addressed to
+// it, or one nobody was assigned. With sessionID "" it returns the oldest
+// queued task of all. nil when the queue is empty.
+func NextTask(db *statedb.StateDB, sessionID string) (*statedb.TaskRow, error) {
+	queued, err := db.LoadTasks(TaskQueued)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range queued {
+		if sessionID == "" || t.SessionID == "" || t.SessionID == sessionID {
+			return t, nil
+		}
+	}
+	return nil, nil
+}
+
+// TaskMessage is what a dispatched task sends: the text plus how to
+// acknowledge it.
+func TaskMessage(profile string, t *statedb.TaskRow) string {
+	p := profileOrDefault(profile)
+	return fmt.Sprintf("[TASK %s] %s\n\n"+
+		"When you have finished this task, acknowledge it:\n"+
+		"  agent-deck -p %s task done %s --result \"<one-line summary>\"\n"+
+		"If you cannot complete it:\n"+
+		"  agent-deck -p %s task fail %s --reason \"<why>\"",
+		t.ID, t.Text, p, t.ID, p, t.ID)
+}
+
+// DispatchTask claims queued task t for inst and sends it with send. If
+// the send fails the task goes back to the queue. t is updated in place.
+func DispatchTask(db *statedb.StateDB, profile string, t *statedb.TaskRow, inst *Instance, now time.Time, send func(instanceID, message string) error) error {
+	if t.Status != TaskQueued {
+		return fmt.Errorf("task %s is %s, not queued", t.ID, t.Status)
+	}
+	if t.SessionID != "" && t.SessionID != inst.ID {
+		return fmt.Errorf("task %s is for another session (%s)", t.ID, t.SessionID)
+	}
+	ok, err := db.TransitionTask(t.ID, []string{TaskQueued}, TaskDispatched, inst.ID, "", now)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("task %s was taken by someone else", t.ID)
+	}
+	if err := send(inst.ID, TaskMessage(profile, t)); err != nil {
+		_, _ = db.TransitionTask(t.ID, []string{TaskDispatched}, TaskQueued, "", "dispatch failed: "+err.Error(), now)
+		return fmt.Errorf("send task %s to %s: %w", t.ID, inst.Title, err)
+	}
+	t.Status, t.SessionID, t.DispatchedAt, t.UpdatedAt = TaskDispatched, inst.ID, now, now
+	t.Attempts++
+	return nil
+}
+
+// FinishTask marks an open task done or failed with result (a summary or
+// the failure reason).
+func FinishTask(db *statedb.StateDB, t *statedb.TaskRow, status, result string, now time.Time) error {
+	if status != TaskDone && status != TaskFailed {
+		return fmt.Errorf("invalid task status %q", status)
+	}
+	ok, err := db.TransitionTask(t.ID, OpenTaskStatuses, status, "", strings.TrimSpace(result), now)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("task %s is already %s", t.ID, t.Status)
+	}
+	t.Status, t.Result, t.UpdatedAt = status, strings.TrimSpace(result), now
+	return nil
+}
+
+// RequeueTask puts a dispatched or failed task back in the queue, keeping
+// its session assignment.
+func RequeueTask(db *statedb.StateDB, t *statedb.TaskRow, now time.Time) error {
+	ok, err := db.TransitionTask(t.ID, []string{TaskDispatched, TaskFailed}, TaskQueued, "", "", now)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("task %s is %s; only dispatched or failed tasks can be requeued", t.ID, t.Status)
+	}
+	t.Status, t.Result, t.UpdatedAt = TaskQueued, "", now
+	return nil
+}
+
+// TaskCounts returns how many tasks are in each status.
+func TaskCounts(tasks []*statedb.TaskRow) map[string]int {
+	counts := make(map[string]int, len(TaskStatuses))
+	for _, t := range tasks {
+		counts[t.Status]++
+	}
+	return counts
+}
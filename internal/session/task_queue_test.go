@@ -0,0 +1,93 @@
+package session
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+func TestTaskQueue_DispatchAndAcknowledge(t *testing.T) {
+	db, err := statedb.Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.Local)
+	api := &Instance{ID: "inst-api", Title: "api"}
+	web := &Instance{ID: "inst-web", Title: "web"}
+	forWeb, err := AddTask(db, web, "fix the navbar", "conductor", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	open, err := AddTask(db, nil, "bump deps", "user", now.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AddTask(db, nil, "  ", "user", now); err == nil {
+		t.Error("empty task text: want error")
+	}
+
+	// api skips web's task and gets the unassigned one.
+	next, err := NextTask(db, api.ID)
+	if err != nil || next == nil || next.ID != open.ID {
+		t.Fatalf("NextTask(api) = %+v, %v; want %s", next, err, open.ID)
+	}
+	if err := DispatchTask(db, "", forWeb, api, now, func(string, string) error { return nil }); err == nil {
+		t.Error("dispatching web's task to api: want error")
+	}
+
+	// A failed send puts the task back in the queue.
+	if err := DispatchTask(db, "", next, api, now, func(string, string) error { return errors.New("pane gone") }); err == nil {
+		t.Fatal("send failure: want error")
+	}
+	if got, _ := db.LoadTask(open.ID); got.Status != TaskQueued || !strings.Contains(got.Result, "pane gone") {
+		t.Fatalf("after failed send: %+v", got)
+	}
+
+	var sentTo, sent string
+	next, _ = NextTask(db, api.ID)
+	if err := DispatchTask(db, "work", next, api, now, func(id, msg string) error { sentTo, sent = id, msg; return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if sentTo != api.ID || !strings.HasPrefix(sent, "[TASK "+open.ID+"] bump deps") ||
+		!strings.Contains(sent, "agent-deck -p work task done "+open.ID) {
+		t.Errorf("sent %q to %q", sent, sentTo)
+	}
+	if err := DispatchTask(db, "work", next, api, now, func(string, string) error { return nil }); err == nil {
+		t.Error("second dispatch: want error")
+	}
+
+	found, err := FindTask(db, strings.TrimPrefix(open.ID, "task-")[:4])
+	if err != nil || found.ID != open.ID {
+		t.Fatalf("FindTask by prefix = %+v, %v", found, err)
+	}
+	if _, err := FindTask(db, "nope"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("FindTask(nope) err = %v", err)
+	}
+	if err := FinishTask(db, found, TaskDone, " bumped 3 deps ", now); err != nil {
+		t.Fatal(err)
+	}
+	if err := FinishTask(db, found, TaskFailed, "", now); err == nil {
+		t.Error("finishing a done task: want error")
+	}
+	if err := RequeueTask(db, forWeb, now); err == nil {
+		t.Error("requeueing a queued task: want error")
+	}
+
+	all, _ := db.LoadTasks()
+	counts := TaskCounts(all)
+	if counts[TaskDone] != 1 || counts[TaskQueued] != 1 {
+		t.Errorf("counts = %v", counts)
+	}
+	if got, _ := db.LoadTask(open.ID); got.Result != "bumped 3 deps" || got.Attempts != 2 || got.SessionID != api.ID {
+		t.Errorf("done task = %+v", got)
+	}
+}
@@ -0,0 +1,275 @@
+package session
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ThemeDef is a user-defined color scheme, [themes.<name>] in config.toml.
+// Every color is optional and falls back to the base palette, so a theme only
+// lists what it changes. A theme named "dark" or "light" tweaks that built-in
+// palette (and applies when theme = "system" resolves to it) instead of
+// adding a new one.
+//
+//	theme = "gruvbox"
+//
+//	[themes.gruvbox]
+//	base = "dark"
+//	bg = "#282828"
+//	accent = "#fabd2f"
+//
+//	[themes.gruvbox.status]
+//	running = "#b8bb26"
+//	waiting = "#fe8019"
+//
+// Colors are "#rgb", "#rrggbb" or an ANSI color number (0-255).
+type ThemeDef struct {
+	// Base is the built-in palette the theme starts from, "dark" (default)
+	// or "light". It also tells sessions whether the background is light.
+	Base string `toml:"base,omitempty"`
+
+	Bg      string `toml:"bg,omitempty"`
+	Surface string `toml:"surface,omitempty"`
+	Border  string `toml:"border,omitempty"`
+	Text    string `toml:"text,omitempty"`
+	TextDim string `toml:"text_dim,omitempty"`
+	Accent  string `toml:"accent,omitempty"`
+	Purple  string `toml:"purple,omitempty"`
+	Cyan    string `toml:"cyan,omitempty"`
+	Green   string `toml:"green,omitempty"`
+	Yellow  string `toml:"yellow,omitempty"`
+	Orange  string `toml:"orange,omitempty"`
+	Red     string `toml:"red,omitempty"`
+	Comment string `toml:"comment,omitempty"`
+
+	// Status overrides the color of a session status (running, waiting,
+	// idle, error, starting, queued, stopped, exited, crashed).
+	Status map[string]string `toml:"status,omitempty"`
+}
+
+// ThemeColorKeys lists the palette colors of a ThemeDef by TOML key.
+var ThemeColorKeys = []string{
+	"bg", "surface", "border", "text", "text_dim", "accent", "purple",
+	"cyan", "green", "yellow", "orange", "red", "comment",
+}
+
+// ThemeStatusKeys lists the statuses a theme can color.
+var ThemeStatusKeys = []string{
+	string(StatusRunning), string(StatusWaiting), string(StatusIdle),
+	string(StatusError), string(StatusStarting), string(StatusQueued),
+	string(StatusStopped), string(StatusExited), string(StatusCrashed),
+}
+
+// Colors returns the theme's palette colors that are set, by TOML key.
+func (d ThemeDef) Colors() map[string]string {
+	all := map[string]string{
+		"bg": d.Bg, "surface": d.Surface, "border": d.Border, "text": d.Text,
+		"text_dim": d.TextDim, "accent": d.Accent, "purple": d.Purple,
+		"cyan": d.Cyan, "green": d.Green, "yellow": d.Yellow,
+		"orange": d.Orange, "red": d.Red, "comment": d.Comment,
+	}
+	for k, v := range all {
+		if v == "" {
+			delete(all, k)
+		}
+	}
+	return all
+}
+
+// SetColor sets a palette color by TOML key. Unknown keys are ignored.
+func (d *ThemeDef) SetColor(key, value string) {
+	switch key {
+	case "bg":
+		d.Bg = value
+	case "surface":
+		d.Surface = value
+	case "border":
+		d.Border = value
+	case "text":
+		d.Text = value
+	case "text_dim":
+		d.TextDim = value
+	case "accent":
+		d.Accent = value
+	case "purple":
+		d.Purple = value
+	case "cyan":
+		d.Cyan = value
+	case "green":
+		d.Green = value
+	case "yellow":
+		d.Yellow = value
+	case "orange":
+		d.Orange = value
+	case "red":
+		d.Red = value
+	case "comment":
+		d.Comment = value
+	}
+}
+
+var themeColorRe = regexp.MustCompile(`^#([0-9A-Fa-f]{3}|[0-9A-Fa-f]{6})$`)
+
+// IsThemeColor reports whether c is a color a theme may use.
+func IsThemeColor(c string) bool {
+	if themeColorRe.MatchString(c) {
+		return true
+	}
+	n, err := strconv.Atoi(c)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// ValidateThemeName rejects names that can't be typed as a CLI argument
+// without quoting, and "system", which theme = already uses.
+func ValidateThemeName(name string) error {
+	if !promptNameRe.MatchString(name) {
+		return fmt.Errorf("invalid theme name %q: use letters, digits, '-' and '_'", name)
+	}
+	if name == "system" {
+		return fmt.Errorf("theme name %q is reserved", name)
+	}
+	return nil
+}
+
+// ValidateThemeDef checks a theme's base, colors and status keys.
+func ValidateThemeDef(name string, def ThemeDef) error {
+	if err := ValidateThemeName(name); err != nil {
+		return err
+	}
+	switch def.Base {
+	case "", "dark", "light":
+	default:
+		return fmt.Errorf("theme %q: base must be \"dark\" or \"light\", got %q", name, def.Base)
+	}
+	if (name == "dark" || name == "light") && def.Base != "" && def.Base != name {
+		return fmt.Errorf("theme %q overrides the built-in palette and can't use base %q", name, def.Base)
+	}
+	keys := make([]string, 0, len(ThemeColorKeys))
+	colors := def.Colors()
+	for k := range colors {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if !IsThemeColor(colors[k]) {
+			return fmt.Errorf("theme %q: %s = %q is not a color (use #rrggbb, #rgb or 0-255)", name, k, colors[k])
+		}
+	}
+	statuses := make([]string, 0, len(def.Status))
+	for k := range def.Status {
+		statuses = append(statuses, k)
+	}
+	sort.Strings(statuses)
+	for _, k := range statuses {
+		known := false
+		for _, s := range ThemeStatusKeys {
+			if k == s {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("theme %q: unknown status %q (known: %s)", name, k, strings.Join(ThemeStatusKeys, ", "))
+		}
+		if !IsThemeColor(def.Status[k]) {
+			return fmt.Errorf("theme %q: status.%s = %q is not a color (use #rrggbb, #rgb or 0-255)", name, k, def.Status[k])
+		}
+	}
+	return nil
+}
+
+// ThemeBase returns the built-in palette a theme starts from.
+func (d ThemeDef) ThemeBase(name string) string {
+	if name == "light" || d.Base == "light" {
+		return "light"
+	}
+	return "dark"
+}
+
+// GetThemes returns the [themes] table from config.toml.
+func GetThemes() map[string]ThemeDef {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return nil
+	}
+	return config.Themes
+}
+
+// CustomThemeNames returns the names of the user-defined themes, sorted,
+// leaving out the "dark" and "light" overrides.
+func CustomThemeNames() []string {
+	var names []string
+	for name := range GetThemes() {
+		if name != "dark" && name != "light" && ValidateThemeName(name) == nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ThemeOverrides returns the [themes.<name>] definition, or a zero ThemeDef.
+func ThemeOverrides(name string) ThemeDef {
+	return GetThemes()[name]
+}
+
+// ResolveThemeDef returns the palette to render with: the resolved base
+// ("dark" or "light") and the overrides of the configured theme. With
+// theme = "system" those are the [themes.dark] or [themes.light] overrides.
+func ResolveThemeDef() (base string, def ThemeDef) {
+	base = ResolveTheme()
+	name := GetTheme()
+	if name == "system" {
+		name = base
+	}
+	return base, ThemeOverrides(name)
+}
+
+// ThemeFile is the on-disk form of an exported theme: the definition plus
+// the name it is imported under by default.
+type ThemeFile struct {
+	Name string `toml:"name"`
+	ThemeDef
+}
+
+// EncodeThemeFile renders a theme file as TOML.
+func EncodeThemeFile(name string, def ThemeDef) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("# Agent Deck theme. Import with: agent-deck theme import <file>\n")
+	if err := toml.NewEncoder(&buf).Encode(ThemeFile{Name: name, ThemeDef: def}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ReadThemeFile parses and validates a theme file. An empty name in the file
+// falls back to fallbackName.
+func ReadThemeFile(path, fallbackName string) (string, ThemeDef, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", ThemeDef{}, err
+	}
+	var file ThemeFile
+	meta, err := toml.Decode(string(raw), &file)
+	if err != nil {
+		return "", ThemeDef{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		return "", ThemeDef{}, fmt.Errorf("%s: unknown key %q", path, undecoded[0].String())
+	}
+	name := file.Name
+	if name == "" {
+		name = fallbackName
+	}
+	if err := ValidateThemeDef(name, file.ThemeDef); err != nil {
+		return "", ThemeDef{}, err
+	}
+	return name, file.ThemeDef, nil
+}
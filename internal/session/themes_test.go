@@ -0,0 +1,112 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateThemeDef(t *testing.T) {
+	valid := ThemeDef{Base: "light", Bg: "#fbf1c7", Accent: "#af3", Red: "196", Status: map[string]string{"running": "#98971a"}}
+	if err := ValidateThemeDef("gruvbox-light", valid); err != nil {
+		t.Fatalf("ValidateThemeDef(valid) = %v", err)
+	}
+	if err := ValidateThemeDef("dark", ThemeDef{Accent: "#ff0000"}); err != nil {
+		t.Fatalf("dark override = %v", err)
+	}
+
+	for name, tc := range map[string]struct {
+		theme string
+		def   ThemeDef
+		want  string
+	}{
+		"reserved name":  {"system", ThemeDef{}, "reserved"},
+		"bad name":       {"my theme", ThemeDef{}, "invalid theme name"},
+		"bad base":       {"x", ThemeDef{Base: "sepia"}, "base must be"},
+		"rebased dark":   {"dark", ThemeDef{Base: "light"}, "can't use base"},
+		"bad color":      {"x", ThemeDef{Bg: "red"}, "bg = \"red\""},
+		"out of range":   {"x", ThemeDef{Text: "256"}, "text = \"256\""},
+		"unknown status": {"x", ThemeDef{Status: map[string]string{"busy": "#fff"}}, "unknown status \"busy\""},
+		"bad status":     {"x", ThemeDef{Status: map[string]string{"waiting": "#ffff"}}, "status.waiting"},
+	} {
+		err := ValidateThemeDef(tc.theme, tc.def)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: err = %v, want %q", name, err, tc.want)
+		}
+	}
+}
+
+func TestThemeFile_RoundTrip(t *testing.T) {
+	def := ThemeDef{Base: "dark", Bg: "#282828", TextDim: "#a89984", Status: map[string]string{"waiting": "#fe8019"}}
+	data, err := EncodeThemeFile("gruvbox", def)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "gruvbox.toml")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	name, got, err := ReadThemeFile(path, "fallback")
+	if err != nil || name != "gruvbox" || !reflect.DeepEqual(got, def) {
+		t.Fatalf("ReadThemeFile = %q, %+v, %v\nfile:\n%s", name, got, err, data)
+	}
+
+	if err := os.WriteFile(path, []byte("bg = \"#000\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if name, _, err := ReadThemeFile(path, "nameless"); err != nil || name != "nameless" {
+		t.Fatalf("fallback name = %q, %v", name, err)
+	}
+
+	if err := os.WriteFile(path, []byte("name = \"x\"\nbackground = \"#000\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ReadThemeFile(path, ""); err == nil || !strings.Contains(err.Error(), "background") {
+		t.Fatalf("unknown key err = %v", err)
+	}
+}
+
+func TestGetTheme_CustomThemes(t *testing.T) {
+	tempDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	os.Setenv("HOME", tempDir)
+	defer os.Setenv("HOME", originalHome)
+	isolateConfigHomeXDG(t)
+
+	cfg := &UserConfig{
+		Theme: "paper",
+		Themes: map[string]ThemeDef{
+			"paper":  {Base: "light", Accent: "#005f87"},
+			"broken": {Base: "neon"},
+			"light":  {Accent: "#0000ff"},
+		},
+	}
+	if err := SaveUserConfig(cfg); err != nil {
+		t.Fatalf("SaveUserConfig: %v", err)
+	}
+	ClearUserConfigCache()
+
+	if got := GetTheme(); got != "paper" {
+		t.Fatalf("GetTheme() = %q, want paper", got)
+	}
+	if got := ResolveTheme(); got != "light" {
+		t.Fatalf("ResolveTheme() = %q, want the custom theme's base", got)
+	}
+	if base, def := ResolveThemeDef(); base != "light" || def.Accent != "#005f87" {
+		t.Fatalf("ResolveThemeDef() = %q, %+v", base, def)
+	}
+	if got := CustomThemeNames(); !reflect.DeepEqual(got, []string{"broken", "paper"}) {
+		t.Fatalf("CustomThemeNames() = %v", got)
+	}
+
+	cfg.Theme = "deleted"
+	if err := SaveUserConfig(cfg); err != nil {
+		t.Fatalf("SaveUserConfig: %v", err)
+	}
+	ClearUserConfigCache()
+	if got := GetTheme(); got != "dark" {
+		t.Fatalf("GetTheme() with an undefined theme = %q, want dark", got)
+	}
+}
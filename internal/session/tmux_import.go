@@ -0,0 +1,315 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Tmux workflow-manager formats `agent-deck import` understands.
+const (
+	TmuxImportTmuxinator = "tmuxinator"
+	TmuxImportTmuxp      = "tmuxp"
+)
+
+// TmuxImport is a tmuxinator or tmuxp project reduced to what agent-deck can
+// represent: the project becomes a group and each window one session.
+// agent-deck sessions have a single pane, so a window runs its first pane's
+// command; the other panes are reported in Warnings.
+type TmuxImport struct {
+	Format   string
+	Path     string
+	Name     string
+	Root     string
+	Windows  []TmuxImportWindow
+	Warnings []string
+}
+
+// TmuxImportWindow is one window of an imported project.
+type TmuxImportWindow struct {
+	Name    string
+	Dir     string
+	Command string
+}
+
+// FindTmuxImportConfig resolves the config argument of `import <format>`: a
+// path, or a project name looked up in the format's config directories
+// (~/.config/tmuxinator, ~/.tmuxinator; ~/.config/tmuxp, ~/.tmuxp).
+func FindTmuxImportConfig(format, arg string) (string, error) {
+	if _, err := os.Stat(ExpandPath(arg)); err == nil || strings.ContainsRune(arg, filepath.Separator) {
+		return ExpandPath(arg), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	dirs := []string{filepath.Join(home, ".config", format), filepath.Join(home, "."+format)}
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		dirs = append([]string{filepath.Join(xdg, format)}, dirs...)
+	}
+	for _, dir := range dirs {
+		for _, ext := range []string{".yml", ".yaml"} {
+			path := filepath.Join(dir, arg+ext)
+			if _, err := os.Stat(path); err == nil {
+				return path, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no %s config %q (looked in %s)", format, arg, strings.Join(dirs, ", "))
+}
+
+// LoadTmuxImport reads a tmuxinator or tmuxp YAML config.
+func LoadTmuxImport(format, path string) (*TmuxImport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	imp := &TmuxImport{Format: format, Path: path}
+	baseDir := filepath.Dir(path)
+	switch format {
+	case TmuxImportTmuxinator:
+		err = imp.parseTmuxinator(raw, baseDir)
+	case TmuxImportTmuxp:
+		err = imp.parseTmuxp(raw, baseDir)
+	default:
+		err = fmt.Errorf("unknown format %q (want %s or %s)", format, TmuxImportTmuxinator, TmuxImportTmuxp)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if imp.Name == "" {
+		imp.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if len(imp.Windows) == 0 {
+		return nil, fmt.Errorf("%s: no windows", path)
+	}
+	return imp, nil
+}
+
+// parseTmuxinator handles:
+//
+//	name: app
+//	root: ~/code/app
+//	windows:
+//	  - editor: vim                      # command
+//	  - server:                          # or a table with panes
+//	      root: ./api
+//	      panes:
+//	        - bundle exec rails s
+//	        - tail -f log/development.log
+func (imp *TmuxImport) parseTmuxinator(raw map[string]interface{}, baseDir string) error {
+	imp.Name = yamlString(raw["name"])
+	if imp.Name == "" {
+		imp.Name = yamlString(raw["project_name"])
+	}
+	imp.Root = resolvePath(firstNonEmpty(yamlString(raw["root"]), yamlString(raw["project_root"]), "."), baseDir)
+	for _, key := range []string{"pre_window", "on_project_start", "pre"} {
+		if raw[key] != nil {
+			imp.warn("%s is not imported", key)
+		}
+	}
+
+	windows, ok := raw["windows"].([]interface{})
+	if !ok {
+		windows, ok = raw["tabs"].([]interface{})
+	}
+	if !ok {
+		return fmt.Errorf("windows must be a list")
+	}
+	for i, w := range windows {
+		entry, ok := w.(map[string]interface{})
+		if !ok || len(entry) != 1 {
+			return fmt.Errorf("windows[%d]: expected `name: command` or `name: {panes: ...}`", i)
+		}
+		for name, spec := range entry {
+			win := TmuxImportWindow{Name: name, Dir: imp.Root}
+			var panes []interface{}
+			switch v := spec.(type) {
+			case map[string]interface{}:
+				if root := yamlString(v["root"]); root != "" {
+					win.Dir = resolvePath(root, imp.Root)
+				}
+				panes, _ = v["panes"].([]interface{})
+			case []interface{}:
+				panes = v
+			default:
+				win.Command = yamlString(v)
+			}
+			if len(panes) > 0 {
+				win.Command = paneCommand(panes[0])
+				if len(panes) > 1 {
+					imp.warn("window %q: %d extra panes not imported", name, len(panes)-1)
+				}
+			}
+			imp.Windows = append(imp.Windows, win)
+		}
+	}
+	return nil
+}
+
+// parseTmuxp handles:
+//
+//	session_name: app
+//	start_directory: ~/code/app
+//	windows:
+//	  - window_name: server
+//	    start_directory: ./api
+//	    shell_command_before: [source .env]
+//	    panes:
+//	      - shell_command: [make run]
+//	      - tail -f log/dev.log
+func (imp *TmuxImport) parseTmuxp(raw map[string]interface{}, baseDir string) error {
+	imp.Name = yamlString(raw["session_name"])
+	imp.Root = resolvePath(firstNonEmpty(yamlString(raw["start_directory"]), "."), baseDir)
+	before := yamlCommands(raw["shell_command_before"])
+	for _, key := range []string{"before_script", "shell_command"} {
+		if raw[key] != nil {
+			imp.warn("%s is not imported", key)
+		}
+	}
+
+	windows, ok := raw["windows"].([]interface{})
+	if !ok {
+		return fmt.Errorf("windows must be a list")
+	}
+	for i, w := range windows {
+		entry, ok := w.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("windows[%d]: expected a table", i)
+		}
+		win := TmuxImportWindow{Name: yamlString(entry["window_name"]), Dir: imp.Root}
+		if win.Name == "" {
+			win.Name = fmt.Sprintf("window-%d", i+1)
+		}
+		if dir := yamlString(entry["start_directory"]); dir != "" {
+			win.Dir = resolvePath(dir, imp.Root)
+		}
+		cmds := append(append([]string(nil), before...), yamlCommands(entry["shell_command_before"])...)
+		if panes, _ := entry["panes"].([]interface{}); len(panes) > 0 {
+			if c := paneCommand(panes[0]); c != "" {
+				cmds = append(cmds, c)
+			}
+			if len(panes) > 1 {
+				imp.warn("window %q: %d extra panes not imported", win.Name, len(panes)-1)
+			}
+		}
+		win.Command = strings.Join(cmds, " && ")
+		imp.Windows = append(imp.Windows, win)
+	}
+	return nil
+}
+
+func (imp *TmuxImport) warn(format string, args ...interface{}) {
+	imp.Warnings = append(imp.Warnings, fmt.Sprintf(format, args...))
+}
+
+// paneCommand flattens one pane entry of either format: a command string, a
+// list of commands, {shell_command: ...} (tmuxp) or {pane_name: [...]}
+// (tmuxinator named panes).
+func paneCommand(pane interface{}) string {
+	if m, ok := pane.(map[string]interface{}); ok {
+		if sc, ok := m["shell_command"]; ok {
+			return strings.Join(yamlCommands(sc), " && ")
+		}
+		for _, v := range m {
+			return strings.Join(yamlCommands(v), " && ")
+		}
+		return ""
+	}
+	return strings.Join(yamlCommands(pane), " && ")
+}
+
+// yamlCommands returns a scalar or a list of scalars as commands, skipping
+// blanks.
+func yamlCommands(v interface{}) []string {
+	var cmds []string
+	switch t := v.(type) {
+	case []interface{}:
+		for _, item := range t {
+			cmds = append(cmds, yamlCommands(item)...)
+		}
+	case map[string]interface{}:
+		if sc, ok := t["cmd"]; ok {
+			cmds = yamlCommands(sc)
+		}
+	default:
+		if s := yamlString(t); s != "" {
+			cmds = []string{s}
+		}
+	}
+	return cmds
+}
+
+func yamlString(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return strings.TrimSpace(fmt.Sprint(v))
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// GroupPath returns the canonical group the import lands in: group if given,
+// else the project name.
+func (imp *TmuxImport) GroupPath(group string) string {
+	return canonicalGroupPath(firstNonEmpty(strings.TrimSpace(group), imp.Name))
+}
+
+// Materialize creates a session per window in GroupPath(group), skipping
+// windows whose title already exists there. Sessions are created, not
+// started, like `add`.
+func (imp *TmuxImport) Materialize(instances []*Instance, group string) *ManifestResult {
+	res := &ManifestResult{Failed: make(map[string]error)}
+	group = imp.GroupPath(group)
+	existing := make(map[string]*Instance)
+	for _, inst := range instances {
+		if inst.GroupPath == group {
+			existing[inst.Title] = inst
+		}
+	}
+	res.Warnings = append(res.Warnings, imp.Warnings...)
+	for _, win := range imp.Windows {
+		if inst := existing[win.Name]; inst != nil {
+			res.Existing = append(res.Existing, inst)
+			continue
+		}
+		if info, err := os.Stat(win.Dir); err != nil || !info.IsDir() {
+			res.Failed[win.Name] = fmt.Errorf("path is not a directory: %s", win.Dir)
+			continue
+		}
+		inst := NewInstanceWithGroup(win.Name, win.Dir, group)
+		inst.TitleLocked = true
+		setInstanceCommand(inst, win.Command)
+		res.Created = append(res.Created, inst)
+		existing[win.Name] = inst
+	}
+	return res
+}
+
+// setInstanceCommand sets a new session's tool and command from a command
+// line, the way `add -c` resolves it.
+func setInstanceCommand(inst *Instance, cmd string) {
+	cmd = strings.TrimSpace(cmd)
+	if cmd == "" {
+		return
+	}
+	inst.Tool = MatchTool(cmd)
+	inst.Command = cmd
+	if def := GetToolDef(inst.Tool); def != nil {
+		inst.Command = def.Command
+	}
+}
@@ -0,0 +1,111 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTmuxImportConfig(t *testing.T, body string) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "api"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "app.yml")
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir, path
+}
+
+func TestLoadTmuxImport_Tmuxinator(t *testing.T) {
+	dir, path := writeTmuxImportConfig(t, `
+name: shop
+root: .
+pre_window: nvm use
+windows:
+  - agent: claude
+  - server:
+      root: api
+      layout: main-vertical
+      panes:
+        - bundle exec rails s
+        - tail -f log/development.log
+  - shell:
+`)
+	imp, err := LoadTmuxImport(TmuxImportTmuxinator, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imp.Name != "shop" || len(imp.Windows) != 3 {
+		t.Fatalf("imp = %+v", imp)
+	}
+	want := []TmuxImportWindow{
+		{Name: "agent", Dir: dir, Command: "claude"},
+		{Name: "server", Dir: filepath.Join(dir, "api"), Command: "bundle exec rails s"},
+		{Name: "shell", Dir: dir},
+	}
+	for i, w := range want {
+		if imp.Windows[i] != w {
+			t.Errorf("window %d = %+v, want %+v", i, imp.Windows[i], w)
+		}
+	}
+	if len(imp.Warnings) != 2 {
+		t.Errorf("warnings = %q, want pre_window and the extra pane", imp.Warnings)
+	}
+}
+
+func TestLoadTmuxImport_Tmuxp(t *testing.T) {
+	dir, path := writeTmuxImportConfig(t, `
+session_name: shop
+start_directory: ./
+shell_command_before: source .env
+windows:
+  - window_name: api
+    start_directory: api
+    panes:
+      - shell_command:
+          - make deps
+          - make run
+  - panes:
+      - codex
+`)
+	imp, err := LoadTmuxImport(TmuxImportTmuxp, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []TmuxImportWindow{
+		{Name: "api", Dir: filepath.Join(dir, "api"), Command: "source .env && make deps && make run"},
+		{Name: "window-2", Dir: dir, Command: "source .env && codex"},
+	}
+	for i, w := range want {
+		if imp.Windows[i] != w {
+			t.Errorf("window %d = %+v, want %+v", i, imp.Windows[i], w)
+		}
+	}
+}
+
+func TestTmuxImport_Materialize(t *testing.T) {
+	dir := t.TempDir()
+	imp := &TmuxImport{Name: "shop", Windows: []TmuxImportWindow{
+		{Name: "agent", Dir: dir, Command: "claude"},
+		{Name: "existing", Dir: dir},
+		{Name: "gone", Dir: filepath.Join(dir, "missing")},
+	}}
+	prior := NewInstanceWithGroup("existing", dir, "shop")
+
+	res := imp.Materialize([]*Instance{prior}, "")
+	if len(res.Created) != 1 || res.Created[0].Tool != "claude" || res.Created[0].GroupPath != "shop" {
+		t.Fatalf("created = %+v", res.Created)
+	}
+	if len(res.Existing) != 1 || res.Existing[0] != prior {
+		t.Errorf("existing = %+v", res.Existing)
+	}
+	if res.Failed["gone"] == nil {
+		t.Error("a window whose directory is missing must fail")
+	}
+	if got := imp.GroupPath("work/shop"); got != "work/shop" {
+		t.Errorf("GroupPath override = %q", got)
+	}
+}
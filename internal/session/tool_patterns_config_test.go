@@ -0,0 +1,87 @@
+package session
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+func useConfigTOML(t *testing.T, doc string) {
+	t.Helper()
+	var cfg UserConfig
+	if _, err := toml.Decode(doc, &cfg); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	oldCache := userConfigCache
+	t.Cleanup(func() { userConfigCache = oldCache })
+	userConfigCache = &cfg
+}
+
+func TestMergeToolPatterns_BuiltinPatternsBlock(t *testing.T) {
+	useConfigTOML(t, `
+[tools.claude.patterns]
+busy_regexps = ['^\s*Crunching']
+thinking_words_extra = ["flummoxing"]
+`)
+	defaults := tmux.DefaultRawPatterns("claude")
+	raw := MergeToolPatterns("claude")
+
+	// busy_regexps replaces only the regexps; the busy strings stay.
+	if !slices.Contains(raw.BusyPatterns, `re:^\s*Crunching`) {
+		t.Errorf("configured busy regexp missing: %v", raw.BusyPatterns)
+	}
+	for _, p := range raw.BusyPatterns {
+		if strings.HasPrefix(p, "re:") && p != `re:^\s*Crunching` {
+			t.Errorf("default busy regexp %q not replaced", p)
+		}
+	}
+	if !slices.Contains(raw.BusyPatterns, "ctrl+c to interrupt") {
+		t.Errorf("default busy strings dropped: %v", raw.BusyPatterns)
+	}
+	if len(raw.WhimsicalWords) != len(defaults.WhimsicalWords)+1 || !slices.Contains(raw.WhimsicalWords, "flummoxing") {
+		t.Errorf("thinking_words_extra not appended: %d words", len(raw.WhimsicalWords))
+	}
+	if !slices.Equal(raw.SpinnerChars, defaults.SpinnerChars) {
+		t.Errorf("unset spinner_chars changed the defaults: %v", raw.SpinnerChars)
+	}
+
+	resolved, err := tmux.CompilePatterns(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved.ThinkingPattern == nil || !resolved.ThinkingPattern.MatchString("✳ Flummoxing… (3s · ↓ 12 tokens)") {
+		t.Error("thinking pattern does not match the added word")
+	}
+
+	// A patterns-only entry tunes the built-in; it is not a custom tool.
+	if GetToolDef("claude") != nil {
+		t.Error("[tools.claude.patterns] registered claude as a custom tool")
+	}
+}
+
+func TestMergeToolPatterns_ReplaceAll(t *testing.T) {
+	useConfigTOML(t, `
+[tools.my-ai]
+command = "my-ai"
+
+[tools.my-ai.patterns]
+busy_strings = ["working"]
+prompt_strings = ["ready>"]
+prompt_regexps = ['(?m)^\? ']
+spinner_chars = ["◐", "◓"]
+thinking_words = ["musing"]
+`)
+	raw := MergeToolPatterns("my-ai")
+	if !slices.Equal(raw.BusyPatterns, []string{"working"}) {
+		t.Errorf("BusyPatterns = %v", raw.BusyPatterns)
+	}
+	if !slices.Equal(raw.PromptPatterns, []string{"ready>", `re:(?m)^\? `}) {
+		t.Errorf("PromptPatterns = %v", raw.PromptPatterns)
+	}
+	if !slices.Equal(raw.SpinnerChars, []string{"◐", "◓"}) || !slices.Equal(raw.WhimsicalWords, []string{"musing"}) {
+		t.Errorf("SpinnerChars = %v, WhimsicalWords = %v", raw.SpinnerChars, raw.WhimsicalWords)
+	}
+}
@@ -115,6 +115,11 @@ func InitFiltered(custom map[string]ToolDef, showOnlyInstalled bool, hiddenTools
 	}
 	for name, def := range custom {
 		if _, isBuiltin := r.builtins[name]; isBuiltin {
+			// A bare [tools.<builtin>.patterns] block tunes the built-in's
+			// status detection (see MergeToolPatterns); it is not a shadow.
+			if def.Patterns != nil && def.Command == "" {
+				continue
+			}
 			registryLog.Warn("ignored custom tool: name shadows a built-in",
 				"name", name,
 				"hint", "rename your custom tool and set compatible_with = \""+name+"\" instead")
@@ -78,7 +78,7 @@ func TestInstalled_FilterDropsMissing(t *testing.T) {
 	withStubbedProbe(t, []string{"claude", "codex"}, func() {
 		r := InitFiltered(nil, true, nil)
 
-		// All() is the UNFILTERED data view — still the full 11.
+		// All() is the UNFILTERED data view — still the full 12.
 		if got := len(r.All()); got != len(canonicalBuiltins) {
 			t.Errorf("All() = %d, want %d (All must stay unfiltered)", got, len(canonicalBuiltins))
 		}
@@ -5,14 +5,14 @@ import (
 	"testing"
 )
 
-// canonicalBuiltins is the canonical 11, in the precedence order that
+// canonicalBuiltins is the canonical 12, in the precedence order that
 // Registry.Match() (and the legacy detectTool() switch) walk.
 var canonicalBuiltins = []string{
 	"claude", "opencode", "gemini", "codex", "pi",
-	"copilot", "crush", "cursor", "hermes", "aider", "shell",
+	"copilot", "crush", "cursor", "hermes", "aider", "goose", "shell",
 }
 
-func TestRegistry_AllReturnsCanonical11(t *testing.T) {
+func TestRegistry_AllReturnsCanonical12(t *testing.T) {
 	all := Init(nil).All()
 	if len(all) != len(canonicalBuiltins) {
 		t.Fatalf("All() returned %d entries, want %d", len(all), len(canonicalBuiltins))
@@ -60,8 +60,10 @@ func TestRegistry_MatchAllBranches(t *testing.T) {
 		{"cursor agent subcommand", "cursor agent", "cursor"},
 		// hermes
 		{"hermes bare", "hermes", "hermes"},
-		// aider has NO detect arm — commands containing "aider" map to shell
-		{"aider falls through to shell", "aider", "shell"},
+		// aider
+		{"aider with flags", "aider --model sonnet", "aider"},
+		// goose
+		{"goose session subcommand", "goose session", "goose"},
 		// shell fallback
 		{"unknown -> shell", "vim", "shell"},
 		{"empty -> shell", "", "shell"},
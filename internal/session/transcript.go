@@ -0,0 +1,421 @@
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// TranscriptSettings configures the per-session transcript recorder, which
+// appends new pane output to <data-dir>/transcripts/<session-id>.jsonl so
+// scrollback survives the session being killed.
+//
+//	[transcripts]
+//	enabled = false
+//	max_size_mb = 10
+type TranscriptSettings struct {
+	// Enabled records transcripts (default: true, nil = true).
+	Enabled *bool `toml:"enabled,omitempty"`
+
+	// MaxSizeMB is the size at which a transcript is rotated to
+	// <session-id>.jsonl.1, replacing the previous rotation (default: 10).
+	MaxSizeMB int `toml:"max_size_mb,omitempty"`
+}
+
+// GetEnabled returns whether transcripts are recorded (default: true).
+func (s TranscriptSettings) GetEnabled() bool {
+	if s.Enabled == nil {
+		return true
+	}
+	return *s.Enabled
+}
+
+// GetMaxSizeBytes returns the rotation threshold in bytes (default: 10 MB).
+func (s TranscriptSettings) GetMaxSizeBytes() int64 {
+	if s.MaxSizeMB <= 0 {
+		return 10 << 20
+	}
+	return int64(s.MaxSizeMB) << 20
+}
+
+// GetTranscriptSettings returns transcript settings from config.
+func GetTranscriptSettings() TranscriptSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return TranscriptSettings{}
+	}
+	return config.Transcripts
+}
+
+// TranscriptEventKill marks the entry written from the final capture taken
+// just before a session's tmux pane is killed.
+const TranscriptEventKill = "kill"
+
+// transcriptCaptureLines is how much scrollback each periodic capture reads.
+// It only has to cover the output produced between two ticks; the overlap
+// with the previous capture is what lets the recorder drop repeated lines.
+const transcriptCaptureLines = 300
+
+// TranscriptEntry is one line of a transcript file: the pane lines that were
+// new since the previous entry, ANSI-stripped.
+type TranscriptEntry struct {
+	Timestamp time.Time `json:"ts"`
+	Hash      string    `json:"hash,omitempty"`
+	Event     string    `json:"event,omitempty"`
+	Lines     []string  `json:"lines"`
+}
+
+// TranscriptDir returns the directory holding session transcripts. It is not
+// per-profile: session IDs are unique, and a transcript should stay readable
+// after its session (or profile) is gone.
+func TranscriptDir() (string, error) {
+	return dataPath("transcripts", "transcripts")
+}
+
+// TranscriptPath returns the transcript file of a session.
+func TranscriptPath(sessionID string) (string, error) {
+	if sessionID == "" || sessionID == "." || sessionID == ".." || strings.ContainsAny(sessionID, `/\`) {
+		return "", fmt.Errorf("invalid session id %q", sessionID)
+	}
+	dir, err := TranscriptDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, sessionID+".jsonl"), nil
+}
+
+// transcriptFileMu serializes appends and rotations within this process, so
+// the recorder's tick and a concurrent kill never interleave on one file.
+var transcriptFileMu sync.Mutex
+
+// TranscriptRecorder appends new pane output of running sessions to their
+// transcript files. Captures whose normalized hash is unchanged are skipped,
+// and lines already recorded by the previous capture are dropped, so a caller
+// can invoke Tick on a timer without the transcript repeating itself.
+type TranscriptRecorder struct {
+	dir      string
+	maxBytes int64
+	now      func() time.Time
+
+	mu       sync.Mutex
+	sessions map[string]*transcriptState
+}
+
+type transcriptState struct {
+	hash  string
+	lines []string
+}
+
+// NewTranscriptRecorder returns a recorder writing to TranscriptDir with the
+// configured rotation size.
+func NewTranscriptRecorder() (*TranscriptRecorder, error) {
+	dir, err := TranscriptDir()
+	if err != nil {
+		return nil, err
+	}
+	return newTranscriptRecorderAt(dir, GetTranscriptSettings().GetMaxSizeBytes()), nil
+}
+
+func newTranscriptRecorderAt(dir string, maxBytes int64) *TranscriptRecorder {
+	return &TranscriptRecorder{
+		dir:      dir,
+		maxBytes: maxBytes,
+		now:      time.Now,
+		sessions: make(map[string]*transcriptState),
+	}
+}
+
+// transcriptRecordsStatus reports whether a session in status still has an
+// agent pane worth recording: stopped sessions have none, and an errored,
+// exited or crashed agent no longer writes to its pane.
+func transcriptRecordsStatus(status Status) bool {
+	switch status {
+	case StatusStopped, StatusError, StatusExited, StatusCrashed:
+		return false
+	}
+	return true
+}
+
+// Tick records new output of every running session and forgets sessions that
+// are no longer present. The visible pane (cached by the status poller) is
+// hashed first; scrollback is only captured when that hash moved. Errors are
+// collected and returned after all sessions have been attempted.
+func (r *TranscriptRecorder) Tick(instances []*Instance) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var errs []error
+	seen := make(map[string]bool, len(instances))
+	for _, inst := range instances {
+		seen[inst.ID] = true
+		if !transcriptRecordsStatus(inst.GetStatusThreadSafe()) {
+			continue
+		}
+		ts := inst.GetTmuxSession()
+		if ts == nil {
+			continue
+		}
+		screen, err := ts.CapturePane()
+		if err != nil {
+			continue
+		}
+		hash := ts.ContentHash(screen)
+		if st := r.sessions[inst.ID]; st != nil && st.hash == hash {
+			continue
+		}
+		content, err := ts.CaptureHistoryLines(transcriptCaptureLines)
+		if err != nil {
+			continue
+		}
+		if err := r.recordLocked(inst.ID, hash, content, ""); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", inst.ID, err))
+		}
+	}
+	for id := range r.sessions {
+		if !seen[id] {
+			delete(r.sessions, id)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// recordLocked appends whatever part of content the session's previous
+// capture did not already cover. On the first capture of a session the
+// previous lines come from the tail of its transcript file, so restarting
+// the recorder does not duplicate what is already on disk.
+func (r *TranscriptRecorder) recordLocked(id, hash, content, event string) error {
+	path := filepath.Join(r.dir, id+".jsonl")
+	st := r.sessions[id]
+	if st == nil {
+		st = &transcriptState{lines: readTranscriptTail(path, transcriptCaptureLines)}
+		r.sessions[id] = st
+	}
+	lines := transcriptLines(content)
+	added := transcriptDelta(st.lines, lines)
+	st.hash, st.lines = hash, lines
+	if len(added) == 0 && event == "" {
+		return nil
+	}
+	return appendTranscriptEntry(path, TranscriptEntry{
+		Timestamp: r.now().UTC().Truncate(time.Second),
+		Hash:      hash,
+		Event:     event,
+		Lines:     added,
+	}, r.maxBytes)
+}
+
+// recordFinalTranscript captures the pane's scrollback right before it is
+// killed and appends the unrecorded part with event "kill". Best-effort: a
+// failed capture or write never blocks the kill.
+func (i *Instance) recordFinalTranscript() {
+	settings := GetTranscriptSettings()
+	if !settings.GetEnabled() {
+		return
+	}
+	ts := i.GetTmuxSession()
+	if ts == nil {
+		return
+	}
+	content, err := ts.CaptureFullHistory()
+	if err != nil {
+		return
+	}
+	dir, err := TranscriptDir()
+	if err != nil {
+		return
+	}
+	r := newTranscriptRecorderAt(dir, settings.GetMaxSizeBytes())
+	if err := r.recordLocked(i.ID, ts.ContentHash(content), content, TranscriptEventKill); err != nil {
+		sessionLog.Debug("transcript_final_capture_failed", slog.String("id", i.ID), slog.String("error", err.Error()))
+	}
+}
+
+// transcriptLines splits a capture into plain-text lines, dropping trailing
+// whitespace and the blank rows below the cursor.
+func transcriptLines(content string) []string {
+	lines := strings.Split(tmux.StripANSI(content), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// transcriptDelta returns the lines of next that prev did not already show.
+// Lines shared at the very bottom (an agent's prompt box or status bar that
+// stays put while output scrolls above it) are held back until they scroll
+// into the history. Above that, the longest run of lines common to both
+// captures anchors them and whatever follows the run in next is new; with no
+// anchor (cleared screen, a fresh session) all of it is.
+func transcriptDelta(prev, next []string) []string {
+	suffix := 0
+	for suffix < len(prev) && suffix < len(next) &&
+		prev[len(prev)-1-suffix] == next[len(next)-1-suffix] {
+		suffix++
+	}
+	prev, next = prev[:len(prev)-suffix], next[:len(next)-suffix]
+	start, n := longestCommonRun(prev, next)
+	return next[start+n:]
+}
+
+// longestCommonRun finds the longest sequence of consecutive lines present in
+// both a and b and returns its start index in b and its length. Ties resolve
+// to the latest position in b; runs of blank lines only are ignored.
+func longestCommonRun(a, b []string) (start, length int) {
+	if len(a) == 0 || len(b) == 0 {
+		return 0, 0
+	}
+	prevRow := make([]int, len(b)+1)
+	row := make([]int, len(b)+1)
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] != b[j-1] {
+				row[j] = 0
+				continue
+			}
+			row[j] = prevRow[j-1] + 1
+			if row[j] >= length && hasContent(b[j-row[j]:j]) {
+				start, length = j-row[j], row[j]
+			}
+		}
+		prevRow, row = row, prevRow
+	}
+	return start, length
+}
+
+func hasContent(lines []string) bool {
+	for _, l := range lines {
+		if strings.TrimSpace(l) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// appendTranscriptEntry appends e to path, first rotating the file to
+// path+".1" when it has grown past maxBytes.
+func appendTranscriptEntry(path string, e TranscriptEntry, maxBytes int64) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal transcript entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	transcriptFileMu.Lock()
+	defer transcriptFileMu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create transcript dir: %w", err)
+	}
+	if info, err := os.Stat(path); err == nil && maxBytes > 0 && info.Size()+int64(len(line)) > maxBytes {
+		if err := os.Rename(path, path+".1"); err != nil {
+			return fmt.Errorf("rotate transcript: %w", err)
+		}
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open transcript: %w", err)
+	}
+	if _, err := f.Write(line); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write transcript: %w", err)
+	}
+	return f.Close()
+}
+
+// transcriptTailBytes bounds how much of a transcript file is read to seed a
+// session's previous capture.
+const transcriptTailBytes = 256 << 10
+
+// readTranscriptTail returns up to n of the last recorded lines of a
+// transcript file, or nil when it does not exist.
+func readTranscriptTail(path string, n int) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil
+	}
+	offset := max(info.Size()-transcriptTailBytes, 0)
+	data := make([]byte, info.Size()-offset)
+	if _, err := f.ReadAt(data, offset); err != nil && err != io.EOF {
+		return nil
+	}
+	if offset > 0 {
+		// Drop the partial entry the window starts in.
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			data = data[i+1:]
+		}
+	}
+	var lines []string
+	for _, e := range decodeTranscriptEntries(bytes.NewReader(data)) {
+		lines = append(lines, e.Lines...)
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
+
+// ReadTranscript returns the recorded entries of a session at or after since
+// (zero = all), oldest first, including the rotated file. A session that was
+// never recorded yields os.ErrNotExist.
+func ReadTranscript(sessionID string, since time.Time) ([]TranscriptEntry, error) {
+	path, err := TranscriptPath(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	var entries []TranscriptEntry
+	found := false
+	for _, p := range []string{path + ".1", path} {
+		f, err := os.Open(p)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		found = true
+		for _, e := range decodeTranscriptEntries(f) {
+			if !e.Timestamp.Before(since) {
+				entries = append(entries, e)
+			}
+		}
+		_ = f.Close()
+	}
+	if !found {
+		return nil, os.ErrNotExist
+	}
+	return entries, nil
+}
+
+// decodeTranscriptEntries parses JSONL entries, skipping lines that do not
+// decode (e.g. a write torn by a crash).
+func decodeTranscriptEntries(r io.Reader) []TranscriptEntry {
+	var entries []TranscriptEntry
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), 16<<20)
+	for sc.Scan() {
+		var e TranscriptEntry
+		if json.Unmarshal(sc.Bytes(), &e) == nil {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
@@ -4,12 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/logging"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
 )
 
 const (
@@ -71,17 +76,43 @@ type TransitionDaemon struct {
 	// logs at most once per probeStallLogInterval instead of flooding the log
 	// every few seconds. Accessed only from the single-threaded Run loop.
 	lastProbeStall map[string]time.Time
+
+	// watchdog restarts errored conductors and a stuck bridge; run at most
+	// once per watchdogInterval (see conductor_watchdog.go). nil until the
+	// first pass.
+	watchdog     *conductorWatchdog
+	lastWatchdog time.Time
+
+	// lastScheduleCheck rate-limits schedule passes per profile to
+	// scheduleCheckInterval; schedulesInFlight holds profiles whose pass is
+	// still sending, so a slow send never overlaps the next pass.
+	lastScheduleCheck map[string]time.Time
+	schedulesInFlight sync.Map
+
+	// lastCounters / lastCountersAt hold the status counters last published
+	// per profile while no TUI is alive, so unchanged counters are rewritten
+	// only every statusCountersRefresh.
+	lastCounters   map[string]statedb.StatusCounters
+	lastCountersAt map[string]time.Time
 }
 
 func NewTransitionDaemon() *TransitionDaemon {
+	// The daemon polls, so it can hold conductor wake-ups for
+	// [notifications.digest] and flush them in syncProfile.
+	notifier := NewTransitionNotifier()
+	notifier.EnableWakeDigests()
 	return &TransitionDaemon{
-		notifier:       NewTransitionNotifier(),
+		notifier:       notifier,
 		storages:       map[string]*Storage{},
 		lastStatus:     map[string]map[string]string{},
 		initialized:    map[string]bool{},
 		lastDone:       map[string]map[string]DoneSignal{},
 		lastDoneScan:   map[string]map[string]time.Time{},
 		lastProbeStall: map[string]time.Time{},
+
+		lastScheduleCheck: map[string]time.Time{},
+		lastCounters:      map[string]statedb.StatusCounters{},
+		lastCountersAt:    map[string]time.Time{},
 	}
 }
 
@@ -126,9 +157,11 @@ func (d *TransitionDaemon) SyncOnce(_ context.Context) time.Duration {
 		// parent was down/busy when the worker exited. Restart-safe and
 		// exactly-once via the record's Acked flag.
 		d.ReplayUnackedCompletions(profile)
+		d.maybeRunSchedules(profile)
 	}
 
 	d.maybeSweepInboxTTL()
+	d.maybeRunConductorWatchdog()
 
 	return nextInterval
 }
@@ -188,6 +221,70 @@ func (d *TransitionDaemon) maybeSweepInboxTTL() {
 	_, _ = SweepInboxByTTL(InboxTTL())
 }
 
+// maybeRunConductorWatchdog runs a conductor watchdog pass when more than
+// watchdogInterval has elapsed since the last one.
+func (d *TransitionDaemon) maybeRunConductorWatchdog() {
+	now := time.Now()
+	if !d.lastWatchdog.IsZero() && now.Sub(d.lastWatchdog) < watchdogInterval {
+		return
+	}
+	d.lastWatchdog = now
+	if d.watchdog == nil {
+		d.watchdog = newConductorWatchdog(d.conductorSessionStatus)
+	}
+	d.watchdog.Run()
+}
+
+// maybeRunSchedules runs profile's due schedules in the background when more
+// than scheduleCheckInterval has elapsed since the last pass.
+func (d *TransitionDaemon) maybeRunSchedules(profile string) {
+	now := time.Now()
+	if last, ok := d.lastScheduleCheck[profile]; ok && now.Sub(last) < scheduleCheckInterval {
+		return
+	}
+	d.lastScheduleCheck[profile] = now
+	storage := d.getStorage(profile)
+	if storage == nil || storage.GetDB() == nil {
+		return
+	}
+	if _, busy := d.schedulesInFlight.LoadOrStore(profile, struct{}{}); busy {
+		return
+	}
+	db := storage.GetDB()
+	go func() {
+		defer d.schedulesInFlight.Delete(profile)
+		RunDueSchedules(db, now, scheduleSender(profile))
+	}()
+}
+
+// conductorSessionStatus returns the status of meta's conductor session as
+// last seen by this daemon, or "" when the session does not exist.
+func (d *TransitionDaemon) conductorSessionStatus(meta ConductorMeta) string {
+	profile := meta.Profile
+	if profile == "" {
+		profile = DefaultProfile
+	}
+	storage := d.getStorage(profile)
+	if storage == nil {
+		return ""
+	}
+	instances, _, err := storage.LoadWithGroups()
+	if err != nil {
+		return ""
+	}
+	title := ConductorSessionTitle(meta.Name)
+	for _, inst := range instances {
+		if inst.Title != title {
+			continue
+		}
+		if status, ok := d.lastStatus[profile][inst.ID]; ok && status != "" {
+			return status
+		}
+		return normalizeStatusString(string(inst.Status))
+	}
+	return ""
+}
+
 // statusProbeBudget bounds a single instance's status refresh in the
 // no-live-TUI sync path. The notify-daemon recurring-freeze bug: Run is a
 // single-threaded poll loop, so a status probe that never returns — a wedged
@@ -318,7 +415,30 @@ func profilesForTransitionDaemon() []string {
 	return profiles
 }
 
+// publishStatusCounters stores the profile's status counters for
+// `agent-deck status -q` while no TUI is publishing them. It counts the
+// statuses this pass already resolved, so a wedged probe never blocks it.
+func (d *TransitionDaemon) publishStatusCounters(profile string, db *statedb.StateDB, instances []*Instance, statuses map[string]string, now time.Time) {
+	if db == nil {
+		return
+	}
+	var c statedb.StatusCounters
+	for _, inst := range instances {
+		addStatusCount(&c, Status(statuses[inst.ID]))
+	}
+	if c == d.lastCounters[profile] && now.Sub(d.lastCountersAt[profile]) < statusCountersRefresh {
+		return
+	}
+	if err := db.WriteStatusCounters(c); err != nil {
+		return
+	}
+	d.lastCounters[profile] = c
+	d.lastCountersAt[profile] = now
+}
+
 func (d *TransitionDaemon) syncProfile(profile string) time.Duration {
+	_, span := logging.StartSpan(context.Background(), "notify_sync", slog.String("profile", profile))
+	defer span.End()
 	storage := d.getStorage(profile)
 	if storage == nil {
 		return notifyPollSlow
@@ -426,6 +546,16 @@ func (d *TransitionDaemon) syncProfile(profile string) time.Duration {
 	// extra capture, no new goroutine (F3). Disabled-by-config → cheap no-op.
 	d.runSelfHealObservePass(profile, instances, statuses, hookStatuses, db, time.Now().UTC())
 
+	// [notifications.push], [auto_respond] and the [governor] send queue:
+	// like [events] hooks, a live TUI runs them from its own status loop.
+	if !tuiAlive {
+		CheckWaitingPushes(profile, instances, time.Now())
+		CheckAutoResponses(profile, instances, time.Now())
+		ReleaseQueuedSends(db, profile, instances, time.Now())
+		d.publishStatusCounters(profile, db, instances, statuses, time.Now())
+	}
+	d.notifier.FlushDigests(profile, byID, time.Now())
+
 	if !d.initialized[profile] {
 		// Cover fast transitions that completed before we observed a running snapshot.
 		d.emitHookTransitionCandidates(profile, byID, nil, statuses, hookCandidates)
@@ -439,6 +569,13 @@ func (d *TransitionDaemon) syncProfile(profile string) time.Duration {
 	notifyEnabled := GetNotificationsSettings().GetTransitionEventsEnabled()
 	for id, to := range statuses {
 		from := normalizeStatusString(prev[id])
+		// [events] hooks and the status webhook: a live TUI runs them from
+		// its own status loop, so the daemon only covers the no-TUI case to
+		// avoid running them twice.
+		if !tuiAlive && from != "" && byID[id] != nil {
+			RunEventHooks(byID[id], profile, from, to)
+			SendStatusWebhook(byID[id], profile, from, to)
+		}
 		if !ShouldNotifyTransition(from, to) {
 			continue
 		}
@@ -833,7 +970,11 @@ func (d *TransitionDaemon) emitHookTransitionCandidates(
 
 func isNotifyTerminalStatus(status string) bool {
 	s := normalizeStatusString(status)
-	return s == string(StatusWaiting) || s == string(StatusError) || s == string(StatusIdle) || s == string(StatusStopped)
+	switch Status(s) {
+	case StatusWaiting, StatusError, StatusIdle, StatusStopped, StatusExited, StatusCrashed:
+		return true
+	}
+	return false
 }
 
 func terminalHookTransitionCandidate(tool string, hs *HookStatus) (hookTransitionCandidate, bool) {
@@ -64,9 +64,14 @@ type UserConfig struct {
 	// Set an action to "" to explicitly unbind it.
 	Hotkeys map[string]string `toml:"hotkeys,omitempty"`
 
-	// Theme sets the color scheme: "dark" (default), "light", or "system"
+	// Theme sets the color scheme: "dark" (default), "light", "system", or
+	// the name of a [themes.<name>] palette
 	Theme string `toml:"theme,omitempty"`
 
+	// Themes defines custom palettes and per-status colors
+	// ([themes.<name>]); see themes.go.
+	Themes map[string]ThemeDef `toml:"themes,omitempty"`
+
 	// Tools defines custom AI tool configurations
 	Tools map[string]ToolDef `toml:"tools,omitempty"`
 
@@ -80,6 +85,12 @@ type UserConfig struct {
 	// Default: true (nil = true)
 	ManageMCPJson *bool `toml:"manage_mcp_json,omitempty"`
 
+	// MCPHotSwap routes a Claude session's local stdio MCPs through a single
+	// "agent-deck-hub" server in .mcp.json, so attaching or detaching them
+	// takes effect without restarting the session. HTTP MCPs are unaffected.
+	// Default: false
+	MCPHotSwap bool `toml:"mcp_hot_swap,omitempty"`
+
 	// SyncTitle controls whether agent-deck overwrites a session's Title with the
 	// agent's own session-name (e.g. Claude's `--name` / `/rename`, issues #572/#697).
 	// Tool-agnostic, global switch. Set false to keep the title you gave the session.
@@ -243,6 +254,70 @@ type UserConfig struct {
 
 	// Performance holds opt-in resource tuning for multi-instance setups.
 	Performance PerformanceSettings `toml:"performance,omitempty"`
+
+	// StateFiles controls the per-session JSON state files written for
+	// external tools (editor statuslines, WM widgets). See state_file.go.
+	StateFiles StateFilesSettings `toml:"state_files,omitempty"`
+
+	// Transcripts controls the per-session transcript recorder that keeps
+	// pane output after a session is killed. See transcript.go.
+	Transcripts TranscriptSettings `toml:"transcripts,omitempty"`
+
+	// StatusHistory opts into the per-session ring of status decisions and
+	// pane captures behind `debug status-history`. See status_history.go.
+	StatusHistory StatusHistorySettings `toml:"status_history,omitempty"`
+
+	// Archive configures automatic archiving of long-idle sessions. See
+	// auto_archive.go.
+	Archive ArchiveSettings `toml:"archive,omitempty"`
+
+	// Events maps session status transitions to shell commands. See
+	// event_hooks.go.
+	Events EventsSettings `toml:"events,omitempty"`
+
+	// Control configures the JSON-RPC control socket. See control_socket.go.
+	Control ControlSettings `toml:"control,omitempty"`
+
+	// SendLint configures pre-send prompt checks. See send_lint.go.
+	SendLint SendLintSettings `toml:"send_lint,omitempty"`
+
+	// AutoRespond answers Claude permission dialogs by rule. See
+	// auto_respond.go.
+	AutoRespond AutoRespondSettings `toml:"auto_respond,omitempty"`
+
+	// Continue configures the `session continue` nudge. See continue_prompt.go.
+	Continue ContinueSettings `toml:"continue,omitempty"`
+
+	// Presets defines start presets (resource tiers) by name, overriding
+	// the built-in light/standard/heavy. See start_presets.go.
+	Presets map[string]StartPreset `toml:"presets,omitempty"`
+
+	// Ports sets the range the port registry hands out. See ports.go.
+	Ports PortSettings `toml:"ports,omitempty"`
+
+	// Governor caps how many Claude sessions work at once and queues
+	// `session send` messages over the cap. See governor.go.
+	Governor GovernorSettings `toml:"governor,omitempty"`
+
+	// Layouts defines multi-pane session layouts by name, picked with
+	// `add --layout`. See layouts.go.
+	Layouts map[string]SessionLayout `toml:"layouts,omitempty"`
+
+	// Prompts is the prompt library: reusable snippets sent with
+	// `agent-deck prompt send` or the TUI prompt picker. See prompts.go.
+	Prompts map[string]SavedPrompt `toml:"prompts,omitempty"`
+
+	// Extensions declares extension commands ([extensions.<name>]) in
+	// addition to agent-deck-<name> executables on PATH. See extensions.go.
+	Extensions map[string]ExtensionDef `toml:"extensions,omitempty"`
+
+	// IdleStop stops sessions whose pane has been idle too long, keeping
+	// them for a later restart. See idle_stop.go.
+	IdleStop IdleStopSettings `toml:"idle_stop,omitempty"`
+
+	// Backup configures `agent-deck backup` and scheduled state.db backups.
+	// See backup.go.
+	Backup BackupSettings `toml:"backup,omitempty"`
 }
 
 // SelfHealSettings controls the self-heal supervision policy (SELF-HEAL-DESIGN.md
@@ -333,6 +408,52 @@ type PerformanceSettings struct {
 	//	[performance]
 	//	claim_polling = true
 	ClaimPolling *bool `toml:"claim_polling,omitempty"`
+
+	// Polling sets how often the TUI polls each session's status, by
+	// status tier, with per-tool overrides:
+	//
+	//	[performance.polling]
+	//	active_ms = 500
+	//	waiting_ms = 2000
+	//	idle_ms = 10000
+	//
+	//	[performance.polling.tools.shell]
+	//	idle_ms = 30000
+	Polling PollingSettings `toml:"polling,omitempty"`
+}
+
+// PollIntervalSettings are status polling intervals in milliseconds. Zero
+// keeps the default (or, for a tool override, the [performance.polling]
+// value).
+type PollIntervalSettings struct {
+	// ActiveMs applies to running and starting sessions (default: 500).
+	ActiveMs int `toml:"active_ms,omitzero"`
+	// WaitingMs applies to waiting and errored sessions (default: 2000).
+	WaitingMs int `toml:"waiting_ms,omitzero"`
+	// IdleMs applies to idle and stopped sessions (default: 10000).
+	IdleMs int `toml:"idle_ms,omitzero"`
+}
+
+// PollingSettings configures [performance.polling].
+type PollingSettings struct {
+	PollIntervalSettings
+	// Tools overrides intervals per tool name (e.g. "claude", "shell").
+	Tools map[string]PollIntervalSettings `toml:"tools,omitempty"`
+}
+
+// Intervals converts the settings to tmux poll intervals.
+func (p PollIntervalSettings) Intervals() tmux.PollIntervals {
+	ms := func(n int) time.Duration { return time.Duration(max(n, 0)) * time.Millisecond }
+	return tmux.PollIntervals{Active: ms(p.ActiveMs), Waiting: ms(p.WaitingMs), Idle: ms(p.IdleMs)}
+}
+
+// NewPollScheduler returns the status poll scheduler for these settings.
+func (p PollingSettings) NewPollScheduler() *tmux.PollScheduler {
+	tools := make(map[string]tmux.PollIntervals, len(p.Tools))
+	for tool, iv := range p.Tools {
+		tools[tool] = iv.Intervals()
+	}
+	return tmux.NewPollScheduler(p.Intervals(), tools)
 }
 
 // ClaimPollingEnabled reports whether claim-based polling is enabled.
@@ -372,6 +493,16 @@ type UISettings struct {
 	//              TERM_PROGRAM=iTerm.app, otherwise tmux
 	// Default: "" (auto). Issue #1470.
 	ShellSplit string `toml:"shell_split,omitempty"`
+	// AttachMode controls what Enter does on a session row when agent-deck
+	// itself runs inside tmux. Valid values:
+	//   "inline" — take over the terminal and attach (historical behavior)
+	//   "split"  — attach in a new horizontal split of the current tmux
+	//              window, keeping the deck visible in the original pane
+	//   "window" — attach in a new window of the current tmux client
+	// Outside tmux every mode falls back to inline. The attach_pane hotkey
+	// (default Alt+Enter) always opens a pane, using "window" when that is
+	// configured and "split" otherwise. Default: "" (inline).
+	AttachMode string `toml:"attach_mode,omitempty"`
 	// RemoteLatencyRefreshSecs sets how often the TUI re-measures the
 	// round-trip latency to each configured remote (issue #1103). Valid
 	// range: 2-300. Default: matches [system_stats].refresh_seconds (5s)
@@ -434,6 +565,24 @@ type UISettings struct {
 	// `add`/`session start` are unaffected by this flag — they attach only
 	// with an explicit `--attach`.
 	AttachOnCreate bool `toml:"attach_on_create,omitempty"`
+
+	// Mouse selects how much of the mouse the TUI captures. Any capture
+	// takes drag-selection away from the terminal, so users who copy text
+	// out of the dashboard can trade mouse features for native selection:
+	//   "full" (default) — clicks, wheel scroll and drag (divider resize)
+	//   "click"          — clicks and wheel scroll, no drag tracking
+	//   "off"            — no capture; the terminal's own selection works
+	// Empty or unknown values fall back to "full". Cycle at runtime with
+	// the toggle_mouse hotkey (alt+m); the toggle is not persisted.
+	Mouse string `toml:"mouse,omitempty"`
+
+	// MouseScreens overrides Mouse on individual screens, keyed by screen
+	// name: "list" (the main dashboard), "scrollback" (the attach
+	// scrollback pager), "search", "settings", "help", and "dialog" (every
+	// other overlay). For example `mouse_screens = { scrollback = "off" }`
+	// keeps mouse scrolling on the dashboard but lets the terminal select
+	// text in the pager. Unknown screens and modes are ignored.
+	MouseScreens map[string]string `toml:"mouse_screens,omitempty"`
 }
 
 // normalizeUIHiddenTools lowercases, dedupes, and drops unknown entries from
@@ -500,6 +649,13 @@ const (
 	ShellSplitTmux  = "tmux"
 )
 
+// Attach modes for Enter on a session row when running inside tmux.
+const (
+	AttachModeInline = "inline"
+	AttachModeSplit  = "split"
+	AttachModeWindow = "window"
+)
+
 // Preview-pane orientation modes for wide terminals (>= 80 cols).
 // "right" is the historical side-by-side split; "below" stacks the
 // PREVIEW pane under the SESSIONS list (portrait-monitor friendly).
@@ -580,6 +736,18 @@ func (u UISettings) GetShellSplit() string {
 	return ""
 }
 
+// GetAttachMode returns the configured attach mode. Unknown or empty values
+// return AttachModeInline. Matching is case-insensitive.
+func (u UISettings) GetAttachMode() string {
+	switch strings.ToLower(strings.TrimSpace(u.AttachMode)) {
+	case AttachModeSplit:
+		return AttachModeSplit
+	case AttachModeWindow:
+		return AttachModeWindow
+	}
+	return AttachModeInline
+}
+
 // GetPreviewOrientation returns the configured preview-pane orientation
 // for wide terminals. Unknown or empty values fall through to the default
 // ("right"). Matching is case-insensitive so users can write "Below" or
@@ -640,6 +808,74 @@ func (u UISettings) GetAttachOnCreate() bool {
 	return u.AttachOnCreate
 }
 
+// Mouse capture modes. See UISettings.Mouse.
+const (
+	MouseModeFull    = "full"
+	MouseModeClick   = "click"
+	MouseModeOff     = "off"
+	DefaultMouseMode = MouseModeFull
+)
+
+// Screens that accept a per-screen mouse mode. See UISettings.MouseScreens.
+const (
+	MouseScreenList       = "list"
+	MouseScreenScrollback = "scrollback"
+	MouseScreenSearch     = "search"
+	MouseScreenSettings   = "settings"
+	MouseScreenHelp       = "help"
+	MouseScreenDialog     = "dialog"
+)
+
+var mouseScreens = map[string]bool{
+	MouseScreenList:       true,
+	MouseScreenScrollback: true,
+	MouseScreenSearch:     true,
+	MouseScreenSettings:   true,
+	MouseScreenHelp:       true,
+	MouseScreenDialog:     true,
+}
+
+// NormalizeMouseMode maps a configured mouse mode to one of the known
+// values, case-insensitively. ok is false for empty or unknown input.
+func NormalizeMouseMode(mode string) (normalized string, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case MouseModeFull:
+		return MouseModeFull, true
+	case MouseModeClick, "click-only", "click_only":
+		return MouseModeClick, true
+	case MouseModeOff, "none", "disabled":
+		return MouseModeOff, true
+	}
+	return "", false
+}
+
+// GetMouseMode returns the configured mouse mode, falling back to
+// DefaultMouseMode ("full") when unset or unknown.
+func (u UISettings) GetMouseMode() string {
+	if mode, ok := NormalizeMouseMode(u.Mouse); ok {
+		return mode
+	}
+	return DefaultMouseMode
+}
+
+// GetMouseScreenModes returns the valid per-screen mouse overrides with
+// screen names lowercased and modes normalized. Nil when none are set.
+func (u UISettings) GetMouseScreenModes() map[string]string {
+	var out map[string]string
+	for screen, raw := range u.MouseScreens {
+		screen = strings.ToLower(strings.TrimSpace(screen))
+		mode, ok := NormalizeMouseMode(raw)
+		if !ok || !mouseScreens[screen] {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]string)
+		}
+		out[screen] = mode
+	}
+	return out
+}
+
 // GetRemoteLatencyRefreshSecs returns the remote latency refresh interval
 // in seconds, clamped to [2, 300]. When the user has not set this value
 // it falls back to fallbackSecs (typically the system_stats refresh
@@ -664,8 +900,71 @@ type WebSettings struct {
 	// MutationsEnabled controls whether POST/PATCH/DELETE endpoints accept
 	// requests. nil (omitted) defaults to true. Forced off by --read-only.
 	MutationsEnabled *bool `toml:"mutations_enabled,omitempty"`
+
+	// Webhooks configures the inbound CI webhook endpoint
+	// (POST /api/webhooks/github).
+	Webhooks WebhookSettings `toml:"webhooks,omitempty"`
 }
 
+// WebhookSettings configures inbound GitHub webhooks on the web server.
+// A workflow_run or workflow_job event for a failed CI run is matched to a
+// route by repository, and the route's session is created (or started) and
+// sent the rendered prompt.
+//
+//	[web.webhooks]
+//	secret = "$AGENTDECK_WEBHOOK_SECRET"
+//
+//	[[web.webhooks.routes]]
+//	repo = "acme/api"
+//	group = "ci/api"
+//	path = "~/src/api"
+//	prompt = "CI job {{.Job}} failed on {{.Branch}}: {{.URL}}"
+type WebhookSettings struct {
+	// Secret is the HMAC-SHA256 key GitHub signs deliveries with
+	// (X-Hub-Signature-256). Supports env var references like
+	// "$AGENTDECK_WEBHOOK_SECRET". The endpoint is disabled while empty.
+	Secret string `toml:"secret,omitempty"`
+
+	// Routes map repositories to sessions. The first route whose Repo
+	// matches wins.
+	Routes []WebhookRoute `toml:"routes,omitempty"`
+}
+
+// WebhookRoute maps a repository to the session that handles its CI
+// failures. Title and Prompt are Go text/templates over the event fields
+// (.Repo, .Workflow, .Job, .Branch, .SHA, .Conclusion, .URL, .Actor,
+// .RunNumber, .Event).
+type WebhookRoute struct {
+	// Repo is the "owner/name" repository, matched case-insensitively.
+	// Glob patterns such as "acme/*" are allowed.
+	Repo string `toml:"repo"`
+
+	// Group is the group path the session is created in.
+	Group string `toml:"group,omitempty"`
+
+	// Path is the project directory for a newly created session.
+	Path string `toml:"path"`
+
+	// Tool is the tool for a newly created session (default: claude).
+	Tool string `toml:"tool,omitempty"`
+
+	// Title names the session. An existing session with this title in
+	// Group is reused. Default: "ci-{{.Workflow}}".
+	Title string `toml:"title,omitempty"`
+
+	// Prompt is the message sent to the session.
+	// Default: DefaultWebhookPrompt.
+	Prompt string `toml:"prompt,omitempty"`
+}
+
+// DefaultWebhookPrompt is sent when a route sets no prompt.
+const DefaultWebhookPrompt = `CI failed in {{.Repo}}: workflow "{{.Workflow}}"{{if .Job}}, job "{{.Job}}"{{end}} concluded {{.Conclusion}} on {{.Branch}} ({{.SHA}}).
+Run: {{.URL}}
+Investigate the failure and fix it.`
+
+// DefaultWebhookTitle names route sessions when a route sets no title.
+const DefaultWebhookTitle = "ci-{{.Workflow}}"
+
 // FeedbackSettings controls the in-product feedback prompts.
 // When Disabled is true, neither the auto-prompt (TUI) nor the post-launch
 // auto-trigger (CLI, if any) will fire. Explicit `agent-deck feedback`
@@ -755,6 +1054,13 @@ type GroupSettings struct {
 	Claude GroupClaudeSettings `toml:"claude,omitempty"`
 	// Hermes defines Hermes overrides for a specific group.
 	Hermes GroupHermesSettings `toml:"hermes,omitempty"`
+	// WorkingHours limits when sessions in this group (and its subgroups)
+	// run, e.g. "18:00-23:00" or "mon-fri 09:00-17:30". Outside the window
+	// they are stopped and their notifications muted; see working_hours.go.
+	WorkingHours string `toml:"working_hours,omitempty"`
+	// IdleTimeout overrides [idle_stop].after for sessions in this group (and
+	// its subgroups): a duration like "8h", or "0" to never auto-stop them.
+	IdleTimeout string `toml:"idle_timeout,omitempty"`
 }
 
 // GroupDefaultsSettings carries [group_defaults] — defaults stamped onto new
@@ -1003,6 +1309,34 @@ type LogSettings struct {
 	// AggregateIntervalS is the event aggregation flush interval in seconds
 	// Default: 30
 	AggregateIntervalS int `toml:"aggregate_interval_secs,omitzero"`
+
+	// TracingEnabled exports OpenTelemetry spans for the status loop,
+	// capture-pane and storage saves over OTLP/HTTP. AGENTDECK_TRACING=1
+	// turns it on for one run.
+	// Default: false
+	TracingEnabled bool `toml:"tracing_enabled,omitempty"`
+
+	// TracingEndpoint is the OTLP/HTTP collector URL
+	// Default: OTEL_EXPORTER_OTLP_ENDPOINT, else http://localhost:4318
+	TracingEndpoint string `toml:"tracing_endpoint,omitempty"`
+
+	// TracingSampleRatio is the fraction of traces kept, 0-1
+	// Default: 1
+	TracingSampleRatio float64 `toml:"tracing_sample_ratio,omitzero"`
+}
+
+// TracingConfig returns the OpenTelemetry settings for logging.InitTracing.
+func (l LogSettings) TracingConfig(version string) logging.TracingConfig {
+	enabled := l.TracingEnabled
+	if v := os.Getenv("AGENTDECK_TRACING"); v != "" {
+		enabled = v != "0" && v != "false"
+	}
+	return logging.TracingConfig{
+		Enabled:        enabled,
+		Endpoint:       l.TracingEndpoint,
+		SampleRatio:    l.TracingSampleRatio,
+		ServiceVersion: version,
+	}
 }
 
 // UpdateSettings defines auto-update configuration
@@ -1095,6 +1429,34 @@ type ExperimentsSettings struct {
 	// DefaultTool is the AI tool to use for experiment sessions
 	// Default: "claude"
 	DefaultTool string `toml:"default_tool,omitempty"`
+
+	// Template lays out new experiment folders as a path pattern, e.g.
+	// "~/experiments/{yyyy}/{mm}/{name}". Tokens: {name}, {yyyy}, {mm},
+	// {dd}, {date}. When set, it replaces Directory and DatePrefix.
+	Template string `toml:"template,omitempty"`
+
+	// Group is the session group 'try' sessions are created in
+	// Default: "experiments"
+	Group string `toml:"group,omitempty"`
+
+	// CleanupEmptyDays lets the maintenance worker delete experiment
+	// folders that are still empty this many days after they were last
+	// modified. Default: 0 (never)
+	CleanupEmptyDays int `toml:"cleanup_empty_days,omitzero"`
+
+	// Groups overrides the settings above per session group, keyed by
+	// group path ([experiments.groups."work/ml"]). Selected with
+	// 'try --group'.
+	Groups map[string]ExperimentsGroupSettings `toml:"groups,omitempty"`
+}
+
+// ExperimentsGroupSettings overrides experiment settings for one group.
+// Empty fields fall back to the [experiments] value.
+type ExperimentsGroupSettings struct {
+	Directory   string `toml:"directory,omitempty"`
+	Template    string `toml:"template,omitempty"`
+	DatePrefix  *bool  `toml:"date_prefix,omitempty"`
+	DefaultTool string `toml:"default_tool,omitempty"`
 }
 
 // NotificationsConfig configures the waiting session notification bar
@@ -1117,6 +1479,44 @@ type NotificationsConfig struct {
 	// Default: true (nil = true). Set to false to suppress dispatch globally.
 	// Per-session override: Instance.NoTransitionNotify
 	TransitionEvents *bool `toml:"transition_events,omitempty"`
+
+	// Scope controls where the notification bar is injected:
+	//   "session" (default) - per-session status-left on agentdeck sessions only,
+	//                         leaving non-agentdeck sessions' status line untouched
+	//   "global"            - set-option -g status-left (pre-scoping behavior)
+	Scope string `toml:"scope,omitempty"`
+
+	// Template wraps the bar text before it is written to status-left.
+	// Placeholders: {bar} (formatted bar), {waiting} (waiting count).
+	// Empty means the bar text is used as-is. The waiting count is also
+	// published as the global user option @agentdeck_waiting, so custom
+	// status lines can embed #{@agentdeck_waiting} directly.
+	Template string `toml:"template,omitempty"`
+
+	// Webhook POSTs status transitions to a URL ([notifications.webhook]).
+	Webhook StatusWebhookSettings `toml:"webhook,omitempty"`
+
+	// Push sends phone notifications for sessions left waiting
+	// ([notifications.push]).
+	Push PushSettings `toml:"push,omitempty"`
+
+	// Digest batches push messages and conductor wake-ups over a window
+	// ([notifications.digest]).
+	Digest NotificationDigestSettings `toml:"digest,omitempty"`
+}
+
+// Notification bar scopes for NotificationsConfig.Scope.
+const (
+	NotificationScopeSession = "session"
+	NotificationScopeGlobal  = "global"
+)
+
+// GetScope returns the normalized notification bar scope (default: "session").
+func (n NotificationsConfig) GetScope() string {
+	if strings.EqualFold(strings.TrimSpace(n.Scope), NotificationScopeGlobal) {
+		return NotificationScopeGlobal
+	}
+	return NotificationScopeSession
 }
 
 // GetTransitionEventsEnabled returns whether transition event dispatch is enabled.
@@ -1951,6 +2351,10 @@ type WorktreeSettings struct {
 	// Default: "feature/" when not set.
 	BranchPrefix *string `toml:"branch_prefix,omitempty"`
 
+	// AutoBranchPrefix is the prefix for branches named from the prompt by
+	// `launch -w auto`, e.g. "agent/". Defaults to BranchPrefix when not set.
+	AutoBranchPrefix *string `toml:"auto_branch_prefix,omitempty"`
+
 	// SetupTimeoutSeconds caps how long .agent-deck/worktree-setup.sh may run.
 	// Pointer (not plain int) so the loader can distinguish three cases:
 	//   nil         → field unset → 60s default (backward compat, GH #724)
@@ -1961,6 +2365,23 @@ type WorktreeSettings struct {
 	// systemd, docker). Reporter @Clindbergh flagged the v1.7.65 behaviour
 	// (`0 = default`) as counter-convention in the PR review for #727.
 	SetupTimeoutSeconds *int `toml:"setup_timeout_seconds,omitempty"`
+
+	// SyncStrategy is how `worktree sync` updates a branch from its base:
+	// "rebase" (default) or "merge".
+	SyncStrategy string `toml:"sync_strategy,omitempty"`
+
+	// AutoSyncMinutes makes the TUI sync idle worktree sessions onto their
+	// base branch this often. 0 (default) disables background syncing.
+	AutoSyncMinutes int `toml:"auto_sync_minutes,omitzero"`
+}
+
+// AutoSyncInterval returns how often worktrees are synced in the
+// background, or 0 when background syncing is off.
+func (w WorktreeSettings) AutoSyncInterval() time.Duration {
+	if w.AutoSyncMinutes <= 0 {
+		return 0
+	}
+	return time.Duration(w.AutoSyncMinutes) * time.Minute
 }
 
 // DefaultWorktreeSetupTimeout is the fallback used when no explicit value is
@@ -2010,6 +2431,15 @@ func (w *WorktreeSettings) Prefix() string {
 	return os.ExpandEnv(*w.BranchPrefix)
 }
 
+// AutoPrefix returns the prefix for prompt-named branches: AutoBranchPrefix
+// when set (environment variables expanded), otherwise Prefix().
+func (w *WorktreeSettings) AutoPrefix() string {
+	if w.AutoBranchPrefix == nil {
+		return w.Prefix()
+	}
+	return os.ExpandEnv(*w.AutoBranchPrefix)
+}
+
 // ApplyBranchPrefix prepends the configured prefix to a branch name.
 // If the branch name already starts with the expanded prefix, it is returned unchanged.
 func (w *WorktreeSettings) ApplyBranchPrefix(branch string) string {
@@ -2122,6 +2552,78 @@ type ToolDef struct {
 
 	// SpinnerCharsExtra appends additional spinner characters to the built-in defaults
 	SpinnerCharsExtra []string `toml:"spinner_chars_extra,omitempty"`
+
+	// Patterns is the [tools.<name>.patterns] block. Unlike the rest of
+	// ToolDef it also applies to built-in tools, so [tools.claude.patterns]
+	// tunes Claude's status detection.
+	Patterns *ToolPatterns `toml:"patterns,omitempty"`
+}
+
+// ToolPatterns overrides a tool's status-detection patterns piece by piece.
+// A field that is set replaces the matching part of the defaults and leaves
+// the rest alone: busy_regexps replaces the built-in busy regexps but keeps
+// the built-in busy strings. Regexps use Go syntax without the "re:" prefix.
+type ToolPatterns struct {
+	// BusyStrings are case-insensitive substrings meaning the tool is working.
+	BusyStrings []string `toml:"busy_strings,omitempty"`
+
+	// BusyRegexps are regexps meaning the tool is working.
+	BusyRegexps []string `toml:"busy_regexps,omitempty"`
+
+	// PromptStrings are case-insensitive substrings meaning the tool waits for input.
+	PromptStrings []string `toml:"prompt_strings,omitempty"`
+
+	// PromptRegexps are regexps meaning the tool waits for input.
+	PromptRegexps []string `toml:"prompt_regexps,omitempty"`
+
+	// SpinnerChars are the characters of the tool's busy spinner.
+	SpinnerChars []string `toml:"spinner_chars,omitempty"`
+
+	// ThinkingWords replaces the words shown next to the spinner while the
+	// tool thinks (Claude's "Pondering…", "Clauding…").
+	ThinkingWords []string `toml:"thinking_words,omitempty"`
+
+	// ThinkingWordsExtra adds thinking words, e.g. ones a new Claude Code
+	// release introduced, without restating the whole list.
+	ThinkingWordsExtra []string `toml:"thinking_words_extra,omitempty"`
+}
+
+// apply returns raw with p's overrides applied. raw is not modified.
+func (p *ToolPatterns) apply(raw *tmux.RawPatterns) *tmux.RawPatterns {
+	out := tmux.MergeRawPatterns(raw, nil, nil)
+	out.BusyPatterns = replacePatternKind(out.BusyPatterns, p.BusyStrings, p.BusyRegexps)
+	out.PromptPatterns = replacePatternKind(out.PromptPatterns, p.PromptStrings, p.PromptRegexps)
+	if p.SpinnerChars != nil {
+		out.SpinnerChars = append([]string(nil), p.SpinnerChars...)
+	}
+	if p.ThinkingWords != nil {
+		out.WhimsicalWords = append([]string(nil), p.ThinkingWords...)
+	}
+	out.WhimsicalWords = append(out.WhimsicalWords, p.ThinkingWordsExtra...)
+	return out
+}
+
+// replacePatternKind splits mixed RawPatterns entries into plain strings and
+// "re:" regexps and replaces each kind for which a list is set.
+func replacePatternKind(patterns, strs, regexps []string) []string {
+	var keepStrs, keepRes []string
+	for _, p := range patterns {
+		if strings.HasPrefix(p, "re:") {
+			keepRes = append(keepRes, p)
+		} else {
+			keepStrs = append(keepStrs, p)
+		}
+	}
+	if strs != nil {
+		keepStrs = append([]string(nil), strs...)
+	}
+	if regexps != nil {
+		keepRes = keepRes[:0]
+		for _, re := range regexps {
+			keepRes = append(keepRes, "re:"+re)
+		}
+	}
+	return append(keepStrs, keepRes...)
 }
 
 // HTTPServerConfig defines how to auto-start an HTTP MCP server
@@ -2368,6 +2870,14 @@ type TmuxSettings struct {
 	// Precedence at Instance creation: CLI flag `--tmux-socket <name>`
 	// wins, else this config value, else empty.
 	SocketName string `toml:"socket_name,omitempty"`
+
+	// ControlModeOnly sends send-keys, set-option, kill-session and
+	// respawn-pane through the TUI's control-mode pipes instead of spawning
+	// a tmux subprocess for each, reconnecting a dropped pipe on demand.
+	// Commands fall back to a subprocess when no pipe is available (CLI
+	// commands, sessions without a pipe) or an argument contains a newline.
+	// Default: false.
+	ControlModeOnly bool `toml:"control_mode_only,omitempty"`
 }
 
 // GetInjectStatusLine returns whether to inject status line, defaulting to true.
@@ -2692,7 +3202,8 @@ type StatusSettings struct {
 // MaintenanceSettings controls the automatic maintenance worker
 type MaintenanceSettings struct {
 	// Enabled enables the maintenance worker (default: false)
-	// Prunes Gemini logs, cleans old backups, archives bloated sessions
+	// Prunes Gemini logs, cleans old backups, archives bloated sessions,
+	// removes empty experiment folders ([experiments] cleanup_empty_days)
 	Enabled bool `toml:"enabled,omitempty"`
 }
 
@@ -2741,6 +3252,10 @@ type DisplaySettings struct {
 	// every session row, not just the selected one. Default: false — opt-in to
 	// avoid crowding narrow sidebars. See renderSessionItem for the source.
 	ShowPaneTitles bool `toml:"show_pane_titles,omitempty"`
+
+	// ShowSessionCost appends the session's estimated Claude spend ("$1.24")
+	// to its row, from transcript and Stop-hook usage. Default: false.
+	ShowSessionCost bool `toml:"show_session_cost,omitempty"`
 }
 
 // GetActiveFilterExcludes returns the resolved set of statuses the % filter
@@ -2757,6 +3272,7 @@ func (d DisplaySettings) GetActiveFilterExcludes() map[Status]bool {
 	valid := map[Status]bool{
 		StatusRunning: true, StatusWaiting: true, StatusIdle: true,
 		StatusError: true, StatusStarting: true, StatusStopped: true,
+		StatusExited: true, StatusCrashed: true,
 	}
 	out := make(map[Status]bool, len(d.ActiveFilterExcludes))
 	for _, s := range d.ActiveFilterExcludes {
@@ -2849,7 +3365,9 @@ var (
 	userConfigCache      *UserConfig
 	userConfigCacheMtime time.Time
 	userConfigCacheErr   error
-	userConfigCacheMu    sync.RWMutex
+	// userConfigCacheIssues are the validation issues of the cached file.
+	userConfigCacheIssues []ConfigIssue
+	userConfigCacheMu     sync.RWMutex
 )
 
 // GetUserConfigPath returns the path to the user config file
@@ -2897,6 +3415,7 @@ func LoadUserConfig() (*UserConfig, error) {
 		userConfigCacheMtime = time.Time{}
 		SetGroupSortMode(fresh.GetGroupSort())
 		userConfigCacheErr = nil
+		userConfigCacheIssues = nil
 		return userConfigCache, nil
 	}
 
@@ -2906,11 +3425,17 @@ func LoadUserConfig() (*UserConfig, error) {
 		userConfigCacheMtime = time.Time{}
 		SetGroupSortMode(fresh.GetGroupSort())
 		userConfigCacheErr = nil
+		userConfigCacheIssues = nil
 		return userConfigCache, nil
 	}
 
 	var config UserConfig
-	if _, err := toml.DecodeFile(configPath, &config); err != nil {
+	var md toml.MetaData
+	data, err := os.ReadFile(configPath)
+	if err == nil {
+		md, err = toml.Decode(string(data), &config)
+	}
+	if err != nil {
 		// Cache default to prevent hot-looping on a broken file, and cache
 		// the error too so every call (not just the first after the mtime
 		// change) can surface that the on-disk config is being ignored.
@@ -2919,9 +3444,17 @@ func LoadUserConfig() (*UserConfig, error) {
 		userConfigCacheMtime = currentMtime
 		SetGroupSortMode(fresh.GetGroupSort())
 		userConfigCacheErr = fmt.Errorf("config.toml parse error: %w", err)
+		userConfigCacheIssues = []ConfigIssue{configParseIssue(err)}
 		return userConfigCache, userConfigCacheErr
 	}
 
+	// Unknown keys and unusable definitions do not stop the load (see
+	// config_validate.go); they are logged and kept for UserConfigIssues.
+	userConfigCacheIssues = validateDecodedConfig(string(data), md, &config)
+	for _, issue := range userConfigCacheIssues {
+		slog.Warn("config_issue", slog.String("path", configPath), slog.String("issue", issue.String()))
+	}
+
 	if config.Tools == nil {
 		config.Tools = make(map[string]ToolDef)
 	}
@@ -2945,6 +3478,16 @@ func LoadUserConfig() (*UserConfig, error) {
 	return userConfigCache, nil
 }
 
+// UserConfigIssues returns the problems found in config.toml when it was
+// last loaded: a syntax error, unknown keys, unusable definitions. Empty
+// when the file is clean or absent.
+func UserConfigIssues() []ConfigIssue {
+	_, _ = LoadUserConfig() // refresh the cache if the file changed
+	userConfigCacheMu.RLock()
+	defer userConfigCacheMu.RUnlock()
+	return append([]ConfigIssue(nil), userConfigCacheIssues...)
+}
+
 // ReloadUserConfig forces a reload of the user config
 func ReloadUserConfig() (*UserConfig, error) {
 	userConfigCacheMu.Lock()
@@ -3172,6 +3715,7 @@ func ClearUserConfigCache() {
 	userConfigCache = nil
 	userConfigCacheMtime = time.Time{}
 	userConfigCacheErr = nil
+	userConfigCacheIssues = nil
 	userConfigCacheMu.Unlock()
 }
 
@@ -3329,7 +3873,7 @@ func GetToolDef(toolName string) *ToolDef {
 }
 
 // GetCustomToolNames returns sorted custom tool names from config.toml,
-// excluding names that shadow built-in tools (claude, gemini, opencode, codex, pi, shell, cursor, aider).
+// excluding names that shadow built-in tools (claude, gemini, opencode, codex, pi, shell, cursor, aider, goose).
 // Returns nil if no custom tools are configured.
 func GetCustomToolNames() []string {
 	return currentRegistry().CustomNames()
@@ -3400,6 +3944,8 @@ func GetToolIcon(toolName string) string {
 		return "📝"
 	case "hermes":
 		return "☤"
+	case "goose":
+		return "🪿"
 	case "pi":
 		return "π"
 	case "shell":
@@ -3428,7 +3974,7 @@ func GetToolBusyPatterns(toolName string) []string {
 // Returns nil only if there are no defaults AND no config entry.
 func MergeToolPatterns(toolName string) *tmux.RawPatterns {
 	defaults := tmux.DefaultRawPatterns(toolName)
-	toolDef := GetToolDef(toolName)
+	toolDef := toolPatternConfig(toolName)
 
 	// No defaults and no config entry: nothing to do
 	if defaults == nil && toolDef == nil {
@@ -3465,7 +4011,29 @@ func MergeToolPatterns(toolName string) *tmux.RawPatterns {
 		}
 	}
 
-	return tmux.MergeRawPatterns(defaults, overrides, extras)
+	merged := tmux.MergeRawPatterns(defaults, overrides, extras)
+	if toolDef != nil && toolDef.Patterns != nil {
+		merged = toolDef.Patterns.apply(merged)
+	}
+	return merged
+}
+
+// toolPatternConfig returns the [tools.<name>] entry that tunes toolName's
+// status patterns. Unlike GetToolDef it also returns entries named after a
+// built-in: the registry ignores those as tools, but their patterns still
+// apply to the built-in.
+func toolPatternConfig(toolName string) *ToolDef {
+	if def := GetToolDef(toolName); def != nil {
+		return def
+	}
+	config, _ := LoadUserConfig()
+	if config == nil || !isBuiltinToolName(toolName) {
+		return nil
+	}
+	if def, ok := config.Tools[toolName]; ok {
+		return &def
+	}
+	return nil
 }
 
 // GetDefaultTool returns the user's preferred default tool for new sessions
@@ -3489,6 +4057,18 @@ func GetWebMutationsEnabled() bool {
 	return *config.Web.MutationsEnabled
 }
 
+// GetWebhookSettings returns the `[web.webhooks]` table with the secret's
+// env var references expanded.
+func GetWebhookSettings() WebhookSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return WebhookSettings{}
+	}
+	settings := config.Web.Webhooks
+	settings.Secret = os.ExpandEnv(settings.Secret)
+	return settings
+}
+
 // GetHotkeyOverrides returns user-configured hotkey overrides from config.toml.
 //
 // Merge order (issue #434):
@@ -3527,7 +4107,8 @@ func GetHotkeyOverrides() map[string]string {
 // cycle (session <- ui). If the UI constant ever changes, update here too.
 const hotkeyDetachAction = "detach"
 
-// GetTheme returns the current theme, defaulting to "dark"
+// GetTheme returns the current theme, defaulting to "dark". A custom theme
+// name is returned only when [themes.<name>] defines it.
 func GetTheme() string {
 	config, err := LoadUserConfig()
 	if err != nil || config == nil {
@@ -3536,18 +4117,25 @@ func GetTheme() string {
 	switch config.Theme {
 	case "dark", "light", "system":
 		return config.Theme
-	default:
-		return "dark"
 	}
+	if _, ok := config.Themes[config.Theme]; ok && ValidateThemeName(config.Theme) == nil {
+		return config.Theme
+	}
+	return "dark"
 }
 
 // ResolveTheme resolves the configured theme to "dark" or "light".
 // If theme is "system", detects the OS dark mode setting.
+// A custom theme resolves to its base palette.
 // Falls back to "dark" on detection failure.
 func ResolveTheme() string {
 	theme := GetTheme()
-	if theme != "system" {
+	switch theme {
+	case "dark", "light":
 		return theme
+	case "system":
+	default:
+		return ThemeOverrides(theme).ThemeBase(theme)
 	}
 	// Check the terminal's own declaration before asking the OS.
 	// COLORFGBG is set by iTerm2 and other terminals; format is "fg;bg"
@@ -3685,6 +4273,7 @@ func GetExperimentsSettings() ExperimentsSettings {
 		return ExperimentsSettings{
 			Directory:   filepath.Join(homeDir, "src", "tries"),
 			DefaultTool: "claude",
+			Group:       "experiments",
 		}
 	}
 
@@ -3701,6 +4290,14 @@ func GetExperimentsSettings() ExperimentsSettings {
 		settings.DefaultTool = "claude"
 	}
 
+	if settings.Template != "" {
+		settings.Template = ExpandPath(settings.Template)
+	}
+
+	if settings.Group == "" {
+		settings.Group = "experiments"
+	}
+
 	return settings
 }
 
@@ -4065,6 +4662,17 @@ directory = "~/src/tries"
 date_prefix = true
 # Default AI tool for experiment sessions (default: "claude")
 default_tool = "claude"
+# Lay out folders by template instead of directory + date_prefix
+# Tokens: {name}, {yyyy}, {mm}, {dd}, {date}
+# template = "~/experiments/{yyyy}/{mm}/{name}"
+# Session group for try sessions (default: "experiments")
+# group = "experiments"
+# Maintenance deletes experiment folders still empty after N days (default: 0 = never)
+# cleanup_empty_days = 14
+# Per-group overrides, used by 'agent-deck try --group <path>'
+# [experiments.groups."work/ml"]
+# template = "~/work/ml/{date}-{name}"
+# default_tool = "codex"
 
 # Git worktree settings
 # Worktrees allow creating isolated working directories for branches
@@ -4350,6 +4958,15 @@ func GetManageMCPJson() bool {
 	return *config.ManageMCPJson
 }
 
+// GetMCPHotSwap returns whether local MCPs go through the hot-swap hub.
+func GetMCPHotSwap() bool {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return false
+	}
+	return config.MCPHotSwap
+}
+
 // GetMCPDef returns a specific MCP definition by name
 // Returns nil if not found
 func GetMCPDef(name string) *MCPDef {
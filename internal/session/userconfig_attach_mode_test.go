@@ -0,0 +1,25 @@
+package session
+
+import "testing"
+
+func TestUISettings_GetAttachMode(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"split", AttachModeSplit},
+		{"SPLIT", AttachModeSplit},
+		{" window ", AttachModeWindow},
+		{"inline", AttachModeInline},
+		{"", AttachModeInline},
+		{"pane", AttachModeInline},
+	}
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			u := UISettings{AttachMode: tc.input}
+			if got := u.GetAttachMode(); got != tc.want {
+				t.Errorf("GetAttachMode(%q) = %q, want %q", tc.input, got, tc.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,13 @@
 package session
 
-import "testing"
+import (
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
 
 func TestClaimPollingDefaultOff(t *testing.T) {
 	var c UserConfig
@@ -20,3 +27,30 @@ func TestClaimPollingEnabled(t *testing.T) {
 		t.Error("claim_polling=true not honored")
 	}
 }
+
+func TestPollingSettings_Decode(t *testing.T) {
+	doc := `
+[performance.polling]
+idle_ms = 30000
+
+[performance.polling.tools.shell]
+active_ms = 1000
+`
+	var cfg UserConfig
+	if _, err := toml.Decode(doc, &cfg); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	s := cfg.Performance.Polling.NewPollScheduler()
+	if got := s.Interval("claude", tmux.PollIdle); got != 30*time.Second {
+		t.Errorf("claude idle = %v, want 30s", got)
+	}
+	if got := s.Interval("claude", tmux.PollActive); got != 500*time.Millisecond {
+		t.Errorf("claude active = %v, want default 500ms", got)
+	}
+	if got := s.Interval("shell", tmux.PollActive); got != time.Second {
+		t.Errorf("shell active = %v, want 1s", got)
+	}
+	if got := s.Interval("shell", tmux.PollIdle); got != 30*time.Second {
+		t.Errorf("shell idle = %v, want the [performance.polling] 30s", got)
+	}
+}
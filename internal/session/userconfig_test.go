@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -2199,6 +2200,28 @@ footer = "full"
 	}
 }
 
+func TestUISettings_MouseModes(t *testing.T) {
+	const cfg = `
+[ui]
+mouse = "Click-Only"
+mouse_screens = { scrollback = "off", Search = "full", bogus = "off", help = "sometimes" }
+`
+	var c UserConfig
+	if _, err := toml.Decode(cfg, &c); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got := c.UI.GetMouseMode(); got != MouseModeClick {
+		t.Errorf("GetMouseMode() = %q, want %q", got, MouseModeClick)
+	}
+	want := map[string]string{MouseScreenScrollback: MouseModeOff, MouseScreenSearch: MouseModeFull}
+	if got := c.UI.GetMouseScreenModes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetMouseScreenModes() = %v, want %v (unknown screens/modes dropped)", got, want)
+	}
+	if got := (UISettings{Mouse: "sideways"}).GetMouseMode(); got != DefaultMouseMode {
+		t.Errorf("unknown mode = %q, want default %q", got, DefaultMouseMode)
+	}
+}
+
 // TestSaveUserConfig_OmitsZeroValueFields verifies that SaveUserConfig does not
 // bloat config.toml with zero-value fields the user never set (issue #1360).
 // TestUserConfig_GroupDefaults_Decode verifies that [group_defaults].max_concurrent
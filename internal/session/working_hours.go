@@ -0,0 +1,469 @@
+// Group working hours: [groups."<path>"] working_hours = "18:00-23:00"
+// confines the sessions of a group (and its subgroups) to a daily window.
+// Outside it a central watcher stops them and the notification bar and web
+// push stay quiet for them; when the window opens the sessions the watcher
+// stopped are restarted. A session can override its group's window with
+// `session set <id> working-hours <spec>`, or opt out with "always".
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WorkingHoursAlways is the per-session override that exempts a session
+// from its group's working hours.
+const WorkingHoursAlways = "always"
+
+// Lifecycle log actions written by the working hours watcher.
+const (
+	ReasonWorkingHoursPaused  = "working-hours-paused"
+	ReasonWorkingHoursResumed = "working-hours-resumed"
+)
+
+// WorkingHours is a parsed window: the weekdays it opens on and its start
+// and end as minutes after midnight. An end at or before the start wraps
+// past midnight ("22:00-02:00"); such a window belongs to the day it opens.
+type WorkingHours struct {
+	Days  [7]bool // indexed by time.Weekday
+	Start int
+	End   int
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParseWorkingHours parses a window spec: "HH:MM-HH:MM", optionally preceded
+// by days as a range or list ("mon-fri 09:00-17:30", "sat,sun 10:00-14:00").
+// Without days the window applies every day.
+func ParseWorkingHours(spec string) (WorkingHours, error) {
+	var wh WorkingHours
+	fields := strings.Fields(strings.ToLower(strings.TrimSpace(spec)))
+	var days, window string
+	switch len(fields) {
+	case 1:
+		window = fields[0]
+		for d := range wh.Days {
+			wh.Days[d] = true
+		}
+	case 2:
+		days, window = fields[0], fields[1]
+		if err := parseWorkingDays(days, &wh.Days); err != nil {
+			return WorkingHours{}, fmt.Errorf("invalid working hours %q: %w", spec, err)
+		}
+	default:
+		return WorkingHours{}, fmt.Errorf("invalid working hours %q: want \"HH:MM-HH:MM\" or \"mon-fri HH:MM-HH:MM\"", spec)
+	}
+
+	start, end, ok := strings.Cut(window, "-")
+	if !ok {
+		return WorkingHours{}, fmt.Errorf("invalid working hours %q: window must be HH:MM-HH:MM", spec)
+	}
+	var err error
+	if wh.Start, err = parseClockMinutes(start); err != nil {
+		return WorkingHours{}, fmt.Errorf("invalid working hours %q: %w", spec, err)
+	}
+	if wh.End, err = parseClockMinutes(end); err != nil {
+		return WorkingHours{}, fmt.Errorf("invalid working hours %q: %w", spec, err)
+	}
+	if wh.Start == wh.End {
+		return WorkingHours{}, fmt.Errorf("invalid working hours %q: window is empty", spec)
+	}
+	return wh, nil
+}
+
+func parseWorkingDays(s string, days *[7]bool) error {
+	for _, part := range strings.Split(s, ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		a, ok := weekdayNames[from]
+		if !ok {
+			return fmt.Errorf("unknown day %q", from)
+		}
+		if !isRange {
+			days[a] = true
+			continue
+		}
+		b, ok := weekdayNames[to]
+		if !ok {
+			return fmt.Errorf("unknown day %q", to)
+		}
+		for d := a; ; d = (d + 1) % 7 {
+			days[d] = true
+			if d == b {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func parseClockMinutes(s string) (int, error) {
+	hh, mm, ok := strings.Cut(s, ":")
+	h, herr := strconv.Atoi(hh)
+	m, merr := strconv.Atoi(mm)
+	if !ok || herr != nil || merr != nil || h < 0 || h > 24 || m < 0 || m > 59 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("invalid time %q (want HH:MM)", s)
+	}
+	return h*60 + m, nil
+}
+
+// Contains reports whether t falls inside the window, in t's location.
+func (w WorkingHours) Contains(t time.Time) bool {
+	mins := t.Hour()*60 + t.Minute()
+	day := t.Weekday()
+	if w.Start < w.End {
+		return w.Days[day] && mins >= w.Start && mins < w.End
+	}
+	// Overnight: the evening part belongs to today, the morning part to
+	// the window that opened yesterday.
+	if mins >= w.Start {
+		return w.Days[day]
+	}
+	return mins < w.End && w.Days[(day+6)%7]
+}
+
+// NextStart returns the next time at or after t the window opens. It returns
+// the zero time for a window with no days.
+func (w WorkingHours) NextStart(t time.Time) time.Time {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	for i := 0; i <= 7; i++ {
+		day := midnight.AddDate(0, 0, i)
+		if !w.Days[day.Weekday()] {
+			continue
+		}
+		if start := day.Add(time.Duration(w.Start) * time.Minute); !start.Before(t) {
+			return start
+		}
+	}
+	return time.Time{}
+}
+
+// GetGroupWorkingHours returns the working_hours spec for a group, walking
+// ancestor groups when the exact path sets none, and the group it came from.
+func (c *UserConfig) GetGroupWorkingHours(groupPath string) (spec, matchedGroup string) {
+	if c == nil || c.Groups == nil {
+		return "", ""
+	}
+	for p := groupPath; p != ""; p = getParentPath(p) {
+		if groupCfg, ok := c.Groups[p]; ok && strings.TrimSpace(groupCfg.WorkingHours) != "" {
+			return strings.TrimSpace(groupCfg.WorkingHours), p
+		}
+	}
+	return "", ""
+}
+
+// ResolveWorkingHours returns the window spec in effect for the session and
+// where it came from: "session", "group:<path>", or "" when unrestricted.
+// A session override of "always" is returned as-is.
+func (i *Instance) ResolveWorkingHours() (spec, source string) {
+	config, _ := LoadUserConfig()
+	return i.resolveWorkingHours(config)
+}
+
+func (i *Instance) resolveWorkingHours(config *UserConfig) (spec, source string) {
+	if v := strings.TrimSpace(i.WorkingHours); v != "" {
+		return v, "session"
+	}
+	if v, matched := config.GetGroupWorkingHours(i.GroupPath); v != "" {
+		return v, "group:" + matched
+	}
+	return "", ""
+}
+
+// workingHoursWarned dedupes the warning for an unparseable spec so a typo
+// logs once rather than on every tick.
+var workingHoursWarned sync.Map
+
+// OutsideWorkingHours reports whether now falls outside the session's
+// working hours. Unrestricted and exempt sessions, and unparseable specs,
+// are never outside.
+func (i *Instance) OutsideWorkingHours(now time.Time) bool {
+	config, _ := LoadUserConfig()
+	return i.outsideWorkingHours(config, now)
+}
+
+func (i *Instance) outsideWorkingHours(config *UserConfig, now time.Time) bool {
+	spec, source := i.resolveWorkingHours(config)
+	if spec == "" || strings.EqualFold(spec, WorkingHoursAlways) {
+		return false
+	}
+	wh, err := ParseWorkingHours(spec)
+	if err != nil {
+		if _, seen := workingHoursWarned.LoadOrStore(source+"|"+spec, true); !seen {
+			sessionLog.Warn("working_hours_invalid",
+				slog.String("source", source),
+				slog.String("error", err.Error()))
+		}
+		return false
+	}
+	return !wh.Contains(now)
+}
+
+// SetWorkingHours sets the per-session override. Empty clears it.
+func (i *Instance) SetWorkingHours(spec string) error {
+	spec = strings.TrimSpace(spec)
+	if spec != "" && !strings.EqualFold(spec, WorkingHoursAlways) {
+		if _, err := ParseWorkingHours(spec); err != nil {
+			return err
+		}
+	}
+	if strings.EqualFold(spec, WorkingHoursAlways) {
+		spec = WorkingHoursAlways
+	}
+	i.WorkingHours = spec
+	i.workingHoursCleared = spec == ""
+	return nil
+}
+
+const toolDataWorkingHoursKey = "working_hours"
+
+// WriteWorkingHoursToToolData merges working_hours into the tool_data blob.
+// Like depends_on, a removed override writes an explicit "" once so
+// MergeToolDataExtras does not carry the old value forward.
+func WriteWorkingHoursToToolData(td json.RawMessage, spec string, cleared bool) json.RawMessage {
+	if spec == "" && !cleared {
+		return td
+	}
+	m := map[string]json.RawMessage{}
+	if len(td) > 0 {
+		_ = json.Unmarshal(td, &m)
+	}
+	raw, _ := json.Marshal(spec)
+	m[toolDataWorkingHoursKey] = raw
+	out, _ := json.Marshal(m)
+	return out
+}
+
+// ReadWorkingHoursFromToolData extracts working_hours from the blob.
+func ReadWorkingHoursFromToolData(td json.RawMessage) string {
+	if len(td) == 0 {
+		return ""
+	}
+	var blob struct {
+		WorkingHours string `json:"working_hours"`
+	}
+	_ = json.Unmarshal(td, &blob)
+	return blob.WorkingHours
+}
+
+// WorkingHoursPausedPath returns the file listing the sessions the watcher
+// stopped, so they are resumed even across an agent-deck restart.
+func WorkingHoursPausedPath() (string, error) {
+	return dataPath("working-hours-paused.json", "working-hours-paused.json")
+}
+
+// ReadWorkingHoursPaused returns the IDs of the sessions currently paused
+// for being outside their working hours.
+func ReadWorkingHoursPaused() ([]string, error) {
+	path, err := WorkingHoursPausedPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return ids, nil
+}
+
+func writeWorkingHoursPaused(paused map[string]bool) error {
+	path, err := WorkingHoursPausedPath()
+	if err != nil {
+		return err
+	}
+	ids := make([]string, 0, len(paused))
+	for id := range paused {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(ids, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeJSONFileAtomic(path, data, 0o600)
+}
+
+// WorkingHoursWatcherConfig wires the watcher to its environment. Nil fields
+// default to production behavior; tests inject them.
+type WorkingHoursWatcherConfig struct {
+	// Now is the clock source. Defaults to time.Now.
+	Now func() time.Time
+	// Stop pauses a session. Defaults to inst.Kill().
+	Stop func(*Instance) error
+	// Resume restarts a paused session. Defaults to inst.Restart().
+	Resume func(*Instance) error
+	// LogEvent persists a session lifecycle row. Defaults to
+	// WriteSessionLifecycleEvent.
+	LogEvent func(SessionLifecycleEvent) error
+	// LoadPaused and SavePaused persist the paused set. Default to the
+	// working-hours-paused.json data file.
+	LoadPaused func() ([]string, error)
+	SavePaused func(map[string]bool) error
+}
+
+// WorkingHoursWatcher stops running sessions that are outside their working
+// hours and restarts the ones it stopped once their window opens again.
+// Sessions stopped by hand, pinned sessions and archived sessions are left
+// alone. Like IdleTimeoutWatcher, Tick is driven from the TUI's background
+// sweep at a coarse cadence.
+type WorkingHoursWatcher struct {
+	cfg WorkingHoursWatcherConfig
+
+	mu     sync.Mutex
+	loaded bool
+	paused map[string]bool
+}
+
+// NewWorkingHoursWatcher constructs a watcher with production defaults
+// filled in for any nil config callback.
+func NewWorkingHoursWatcher(cfg WorkingHoursWatcherConfig) *WorkingHoursWatcher {
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	if cfg.Stop == nil {
+		cfg.Stop = defaultStop
+	}
+	if cfg.Resume == nil {
+		cfg.Resume = func(inst *Instance) error { return inst.Restart() }
+	}
+	if cfg.LogEvent == nil {
+		cfg.LogEvent = WriteSessionLifecycleEvent
+	}
+	if cfg.LoadPaused == nil {
+		cfg.LoadPaused = ReadWorkingHoursPaused
+	}
+	if cfg.SavePaused == nil {
+		cfg.SavePaused = writeWorkingHoursPaused
+	}
+	return &WorkingHoursWatcher{cfg: cfg, paused: map[string]bool{}}
+}
+
+// Tick pauses and resumes sessions against their working hours. It must see
+// every instance: paused IDs that no longer exist are dropped.
+func (w *WorkingHoursWatcher) Tick(instances []*Instance) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.loaded {
+		ids, err := w.cfg.LoadPaused()
+		if err != nil {
+			sessionLog.Warn("working_hours_load_failed", slog.String("error", err.Error()))
+		}
+		for _, id := range ids {
+			w.paused[id] = true
+		}
+		w.loaded = true
+	}
+
+	config, _ := LoadUserConfig()
+	now := w.cfg.Now()
+	changed := false
+	seen := make(map[string]bool, len(instances))
+	for _, inst := range instances {
+		if inst == nil {
+			continue
+		}
+		seen[inst.ID] = true
+		outside := inst.outsideWorkingHours(config, now)
+
+		if w.paused[inst.ID] {
+			if outside {
+				continue
+			}
+			delete(w.paused, inst.ID)
+			changed = true
+			// Only resume what is still down: a session the user started
+			// by hand in the meantime is already where it should be.
+			if inst.IsArchived() || idleTimeoutWatchable(inst) {
+				continue
+			}
+			if err := w.cfg.Resume(inst); err != nil {
+				sessionLog.Warn("working_hours_resume_failed",
+					slog.String("instance_id", inst.ID),
+					slog.String("error", err.Error()))
+				continue
+			}
+			w.logEvent(inst, ReasonWorkingHoursResumed, "working hours started")
+			continue
+		}
+
+		if !outside || inst.Pin != PinNone || inst.IsArchived() || !idleTimeoutWatchable(inst) {
+			continue
+		}
+		if err := w.cfg.Stop(inst); err != nil {
+			sessionLog.Warn("working_hours_stop_failed",
+				slog.String("instance_id", inst.ID),
+				slog.String("error", err.Error()))
+			continue
+		}
+		w.paused[inst.ID] = true
+		changed = true
+		spec, source := inst.resolveWorkingHours(config)
+		w.logEvent(inst, ReasonWorkingHoursPaused, fmt.Sprintf("outside working hours %q (%s)", spec, source))
+	}
+
+	for id := range w.paused {
+		if !seen[id] {
+			delete(w.paused, id)
+			changed = true
+		}
+	}
+	if changed {
+		if err := w.cfg.SavePaused(w.paused); err != nil {
+			sessionLog.Warn("working_hours_save_failed", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// WithinWorkingHours returns the instances that are not outside their
+// working hours at now: the sessions whose notifications should surface.
+func WithinWorkingHours(instances []*Instance, now time.Time) []*Instance {
+	config, _ := LoadUserConfig()
+	kept := make([]*Instance, 0, len(instances))
+	for _, inst := range instances {
+		if inst != nil && !inst.outsideWorkingHours(config, now) {
+			kept = append(kept, inst)
+		}
+	}
+	return kept
+}
+
+// IsPaused reports whether the watcher stopped the session and will resume it.
+func (w *WorkingHoursWatcher) IsPaused(id string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.paused[id]
+}
+
+func (w *WorkingHoursWatcher) logEvent(inst *Instance, action, reason string) {
+	if err := w.cfg.LogEvent(SessionLifecycleEvent{
+		InstanceID: inst.ID,
+		Action:     action,
+		Reason:     reason,
+	}); err != nil {
+		sessionLog.Warn("working_hours_log_failed",
+			slog.String("instance_id", inst.ID),
+			slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,197 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWorkingHours(t *testing.T) {
+	for _, spec := range []string{"18:00-23:00", "mon-fri 09:00-17:30", "sat,sun 10:00-14:00", "fri-mon 22:00-02:00", "00:00-24:00"} {
+		if _, err := ParseWorkingHours(spec); err != nil {
+			t.Errorf("ParseWorkingHours(%q) = %v", spec, err)
+		}
+	}
+	for _, spec := range []string{"", "18:00", "9-17", "25:00-26:00", "mon-fri", "funday 09:00-10:00", "09:00-09:00", "mon 09:00-10:00 extra"} {
+		if _, err := ParseWorkingHours(spec); err == nil {
+			t.Errorf("ParseWorkingHours(%q) succeeded, want error", spec)
+		}
+	}
+}
+
+func TestWorkingHoursContains(t *testing.T) {
+	// 2026-10-12 is a Monday.
+	at := func(day int, hhmm string) time.Time {
+		m, _ := parseClockMinutes(hhmm)
+		return time.Date(2026, 10, 12+day, m/60, m%60, 0, 0, time.UTC)
+	}
+	cases := []struct {
+		spec string
+		t    time.Time
+		want bool
+	}{
+		{"18:00-23:00", at(0, "17:59"), false},
+		{"18:00-23:00", at(0, "18:00"), true},
+		{"18:00-23:00", at(0, "23:00"), false},
+		{"mon-fri 09:00-17:00", at(4, "12:00"), true},  // Friday
+		{"mon-fri 09:00-17:00", at(5, "12:00"), false}, // Saturday
+		{"sat,sun 10:00-14:00", at(6, "10:30"), true},  // Sunday
+		// Overnight windows belong to the day they open.
+		{"fri 22:00-02:00", at(4, "23:00"), true},
+		{"fri 22:00-02:00", at(5, "01:00"), true},
+		{"fri 22:00-02:00", at(0, "01:00"), false},
+	}
+	for _, tc := range cases {
+		wh, err := ParseWorkingHours(tc.spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := wh.Contains(tc.t); got != tc.want {
+			t.Errorf("%q.Contains(%s) = %v, want %v", tc.spec, tc.t.Format("Mon 15:04"), got, tc.want)
+		}
+	}
+
+	wh, _ := ParseWorkingHours("mon-fri 09:00-17:00")
+	if got, want := wh.NextStart(at(4, "18:00")), at(7, "09:00"); !got.Equal(want) {
+		t.Errorf("NextStart after Friday close = %s, want %s", got, want)
+	}
+}
+
+func TestGetGroupWorkingHours_WalksAncestors(t *testing.T) {
+	cfg := &UserConfig{Groups: map[string]GroupSettings{
+		"personal":     {WorkingHours: "18:00-23:00"},
+		"personal/fun": {DefaultPath: "/tmp"},
+	}}
+	spec, matched := cfg.GetGroupWorkingHours("personal/fun/games")
+	if spec != "18:00-23:00" || matched != "personal" {
+		t.Errorf("got (%q, %q), want the personal window", spec, matched)
+	}
+	if spec, _ := cfg.GetGroupWorkingHours("work"); spec != "" {
+		t.Errorf("unrestricted group got %q", spec)
+	}
+}
+
+func TestWorkingHoursToolDataRoundTrip(t *testing.T) {
+	td := WriteWorkingHoursToToolData([]byte(`{"claude_session_id":"x"}`), "mon-fri 09:00-17:00", false)
+	if got := ReadWorkingHoursFromToolData(td); got != "mon-fri 09:00-17:00" {
+		t.Errorf("round trip = %q", got)
+	}
+	if got := WriteWorkingHoursToToolData(td, "", false); string(got) != string(td) {
+		t.Errorf("unset override should leave the blob alone, got %s", got)
+	}
+	if got := ReadWorkingHoursFromToolData(WriteWorkingHoursToToolData(td, "", true)); got != "" {
+		t.Errorf("cleared override = %q, want explicit empty", got)
+	}
+}
+
+func TestWorkingHoursWatcher_PausesAndResumes(t *testing.T) {
+	channelsTestEnv(t)
+	clock := newFakeClock(time.Date(2026, 10, 12, 12, 0, 0, 0, time.Local))
+
+	var stopped, resumed []string
+	var saved map[string]bool
+	w := NewWorkingHoursWatcher(WorkingHoursWatcherConfig{
+		Now: clock.Now,
+		Stop: func(inst *Instance) error {
+			stopped = append(stopped, inst.ID)
+			inst.Status = StatusStopped
+			return nil
+		},
+		Resume: func(inst *Instance) error {
+			resumed = append(resumed, inst.ID)
+			inst.Status = StatusRunning
+			return nil
+		},
+		LogEvent:   func(SessionLifecycleEvent) error { return nil },
+		LoadPaused: func() ([]string, error) { return nil, nil },
+		SavePaused: func(m map[string]bool) error {
+			saved = make(map[string]bool, len(m))
+			for k, v := range m {
+				saved[k] = v
+			}
+			return nil
+		},
+	})
+
+	evening := &Instance{ID: "evening", Status: StatusRunning, WorkingHours: "18:00-23:00"}
+	exempt := &Instance{ID: "exempt", Status: StatusRunning, WorkingHours: WorkingHoursAlways}
+	pinned := &Instance{ID: "pinned", Status: StatusRunning, WorkingHours: "18:00-23:00", Pin: PinTop}
+	manual := &Instance{ID: "manual", Status: StatusStopped, WorkingHours: "18:00-23:00"}
+	all := []*Instance{evening, exempt, pinned, manual}
+
+	w.Tick(all)
+	if len(stopped) != 1 || stopped[0] != "evening" {
+		t.Fatalf("stopped = %v, want only the evening session", stopped)
+	}
+	if !w.IsPaused("evening") || !saved["evening"] {
+		t.Fatal("evening session should be recorded as paused")
+	}
+
+	clock.Advance(6*time.Hour + time.Minute) // 18:01
+	w.Tick(all)
+	if len(resumed) != 1 || resumed[0] != "evening" {
+		t.Fatalf("resumed = %v, want only the evening session", resumed)
+	}
+	if w.IsPaused("evening") || len(saved) != 0 {
+		t.Errorf("paused set should be empty after resume, saved=%v", saved)
+	}
+}
+
+func TestWorkingHoursWatcher_ManualStartWhilePausedIsKept(t *testing.T) {
+	channelsTestEnv(t)
+	clock := newFakeClock(time.Date(2026, 10, 12, 12, 0, 0, 0, time.Local))
+	stops := 0
+	resumes := 0
+	w := NewWorkingHoursWatcher(WorkingHoursWatcherConfig{
+		Now:        clock.Now,
+		Stop:       func(inst *Instance) error { stops++; inst.Status = StatusStopped; return nil },
+		Resume:     func(*Instance) error { resumes++; return nil },
+		LogEvent:   func(SessionLifecycleEvent) error { return nil },
+		LoadPaused: func() ([]string, error) { return nil, nil },
+		SavePaused: func(map[string]bool) error { return nil },
+	})
+	inst := &Instance{ID: "s", Status: StatusRunning, WorkingHours: "18:00-23:00"}
+
+	w.Tick([]*Instance{inst})
+	inst.Status = StatusRunning // user starts it by hand off-hours
+	clock.Advance(time.Hour)
+	w.Tick([]*Instance{inst})
+	if stops != 1 {
+		t.Errorf("stops = %d, want the manual start left alone", stops)
+	}
+	clock.Advance(6 * time.Hour) // 19:00, window open
+	w.Tick([]*Instance{inst})
+	if resumes != 0 || w.IsPaused("s") {
+		t.Errorf("running session should just be released, resumes=%d", resumes)
+	}
+}
+
+func TestWithinWorkingHours_MutesOffHoursSessions(t *testing.T) {
+	channelsTestEnv(t)
+	noon := time.Date(2026, 10, 12, 12, 0, 0, 0, time.Local)
+	on := &Instance{ID: "on", WorkingHours: "09:00-17:00"}
+	off := &Instance{ID: "off", WorkingHours: "18:00-23:00"}
+	free := &Instance{ID: "free"}
+	got := WithinWorkingHours([]*Instance{on, off, free}, noon)
+	if len(got) != 2 || got[0] != on || got[1] != free {
+		t.Errorf("WithinWorkingHours = %v, want on and free", got)
+	}
+}
+
+func TestSetField_WorkingHours(t *testing.T) {
+	inst := &Instance{ID: "x"}
+	if _, _, err := SetField(inst, FieldWorkingHours, "mon-fri 9:00-17:00", nil); err != nil {
+		t.Fatalf("valid spec rejected: %v", err)
+	}
+	if inst.WorkingHours != "mon-fri 9:00-17:00" {
+		t.Errorf("WorkingHours = %q", inst.WorkingHours)
+	}
+	if _, _, err := SetField(inst, FieldWorkingHours, "ALWAYS", nil); err != nil || inst.WorkingHours != WorkingHoursAlways {
+		t.Errorf("always: %q, %v", inst.WorkingHours, err)
+	}
+	if _, _, err := SetField(inst, FieldWorkingHours, "nightly", nil); err == nil {
+		t.Error("invalid spec accepted")
+	}
+	if _, _, err := SetField(inst, FieldWorkingHours, "", nil); err != nil || inst.WorkingHours != "" || !inst.workingHoursCleared {
+		t.Errorf("clear: %q cleared=%v err=%v", inst.WorkingHours, inst.workingHoursCleared, err)
+	}
+}
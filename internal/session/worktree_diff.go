@@ -0,0 +1,30 @@
+package session
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/asheshgoplani/agent-deck/internal/git"
+)
+
+// DiffInstanceWorktree returns what merging inst's worktree branch into
+// base (see WorktreeBase) would bring in. Only committed work is included;
+// callers warn about uncommitted changes separately.
+func DiffInstanceWorktree(inst *Instance, base string) (*git.BranchDiff, error) {
+	if !inst.IsWorktree() || inst.WorktreeBranch == "" {
+		return nil, fmt.Errorf("session '%s' is not in a worktree", inst.Title)
+	}
+	base, err := WorktreeBase(inst, base)
+	if err != nil {
+		return nil, err
+	}
+	if base == inst.WorktreeBranch {
+		return nil, fmt.Errorf("cannot diff branch '%s' against itself", base)
+	}
+	// The worktree may already be gone; its branch still lives in the repo.
+	dir := inst.WorktreePath
+	if _, err := os.Stat(dir); err != nil {
+		dir = inst.WorktreeRepoRoot
+	}
+	return git.DiffBranch(dir, base, inst.WorktreeBranch)
+}
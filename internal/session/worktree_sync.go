@@ -0,0 +1,319 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/git"
+)
+
+// Worktree sync states recorded per session.
+const (
+	WorktreeSyncOK       = "synced"   // up to date with its base
+	WorktreeSyncConflict = "conflict" // the last sync stopped on conflicts and was aborted
+	WorktreeSyncFailed   = "failed"   // the last sync failed (fetch error, missing base, ...)
+)
+
+// Lifecycle log actions written by the worktree sync watcher.
+const (
+	ReasonWorktreeSyncConflict = "worktree-sync-conflict"
+	ReasonWorktreeSynced       = "worktree-synced"
+)
+
+// WorktreeSyncState is the outcome of the last `worktree sync` of a session,
+// kept in worktree-sync.json so the TUI and CLI agree on it.
+type WorktreeSyncState struct {
+	SessionID     string    `json:"session_id"`
+	Branch        string    `json:"branch"`
+	Base          string    `json:"base"`
+	Strategy      string    `json:"strategy"`
+	Status        string    `json:"status"`
+	Upstream      string    `json:"upstream,omitempty"`
+	Behind        int       `json:"behind,omitempty"`
+	ConflictFiles []string  `json:"conflict_files,omitempty"`
+	Error         string    `json:"error,omitempty"`
+	At            time.Time `json:"at"`
+}
+
+// worktreeSyncMu serializes read-modify-write cycles on the state file.
+var worktreeSyncMu sync.Mutex
+
+// WorktreeSyncPath returns the file holding per-session worktree sync state.
+func WorktreeSyncPath() (string, error) {
+	return dataPath("worktree-sync.json", "worktree-sync.json")
+}
+
+// ReadWorktreeSyncStates returns the recorded sync states keyed by session
+// ID. A missing file yields an empty map and no error.
+func ReadWorktreeSyncStates() (map[string]WorktreeSyncState, error) {
+	path, err := WorktreeSyncPath()
+	if err != nil {
+		return nil, err
+	}
+	worktreeSyncMu.Lock()
+	defer worktreeSyncMu.Unlock()
+	return readWorktreeSyncFile(path)
+}
+
+func readWorktreeSyncFile(path string) (map[string]WorktreeSyncState, error) {
+	states := map[string]WorktreeSyncState{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return states, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return states, nil
+}
+
+// updateWorktreeSyncStates applies fn to the stored states and writes them
+// back.
+func updateWorktreeSyncStates(fn func(map[string]WorktreeSyncState)) error {
+	path, err := WorktreeSyncPath()
+	if err != nil {
+		return err
+	}
+	worktreeSyncMu.Lock()
+	defer worktreeSyncMu.Unlock()
+	states, err := readWorktreeSyncFile(path)
+	if err != nil {
+		return err
+	}
+	fn(states)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeJSONFileAtomic(path, data, 0o600)
+}
+
+// ClearWorktreeSyncState drops the recorded state of a session, e.g. once
+// its worktree is finished.
+func ClearWorktreeSyncState(id string) error {
+	return updateWorktreeSyncStates(func(states map[string]WorktreeSyncState) {
+		delete(states, id)
+	})
+}
+
+// WorktreeBase returns the branch inst's worktree branch is synced onto,
+// diffed against and merged into: base when set, else the base of its last
+// sync, else the repository's default branch.
+func WorktreeBase(inst *Instance, base string) (string, error) {
+	if base != "" {
+		return base, nil
+	}
+	if states, err := ReadWorktreeSyncStates(); err == nil && states[inst.ID].Base != "" {
+		return states[inst.ID].Base, nil
+	}
+	b, err := git.GetDefaultBranch(inst.WorktreeRepoRoot)
+	if err != nil {
+		return "", fmt.Errorf("could not determine base branch: %w", err)
+	}
+	return b, nil
+}
+
+// SyncInstanceWorktree rebases (or merges) inst's worktree branch onto base
+// and records the outcome. An empty base reuses the session's previous base,
+// else the repository's default branch; an empty strategy uses [worktree]
+// sync_strategy. A dirty worktree is refused with git.ErrWorktreeDirty and
+// leaves the recorded state alone.
+func SyncInstanceWorktree(inst *Instance, base string, strategy git.SyncStrategy) (WorktreeSyncState, error) {
+	if !inst.IsWorktree() || inst.WorktreeBranch == "" {
+		return WorktreeSyncState{}, fmt.Errorf("session '%s' is not in a worktree", inst.Title)
+	}
+	if strategy == "" {
+		s, err := git.ParseSyncStrategy(GetWorktreeSettings().SyncStrategy)
+		if err != nil {
+			return WorktreeSyncState{}, err
+		}
+		strategy = s
+	}
+	base, err := WorktreeBase(inst, base)
+	if err != nil {
+		return WorktreeSyncState{}, err
+	}
+	if base == inst.WorktreeBranch {
+		return WorktreeSyncState{}, fmt.Errorf("cannot sync branch '%s' onto itself", base)
+	}
+
+	state := WorktreeSyncState{
+		SessionID: inst.ID,
+		Branch:    inst.WorktreeBranch,
+		Base:      base,
+		Strategy:  string(strategy),
+		At:        time.Now(),
+	}
+	res, err := git.SyncWorktree(inst.WorktreePath, base, strategy)
+	switch {
+	case errors.Is(err, git.ErrWorktreeDirty):
+		return state, err
+	case err != nil:
+		state.Status = WorktreeSyncFailed
+		state.Error = err.Error()
+	case res.Conflict:
+		state.Status = WorktreeSyncConflict
+		state.Upstream = res.Upstream
+		state.Behind = res.Behind
+		state.ConflictFiles = res.ConflictFiles
+	default:
+		state.Status = WorktreeSyncOK
+		state.Upstream = res.Upstream
+		state.Behind = res.Behind
+	}
+	if werr := updateWorktreeSyncStates(func(states map[string]WorktreeSyncState) {
+		states[inst.ID] = state
+	}); werr != nil {
+		sessionLog.Warn("worktree_sync_save_failed",
+			slog.String("instance_id", inst.ID),
+			slog.String("error", werr.Error()))
+	}
+	return state, err
+}
+
+// WorktreeSyncWatcherConfig wires the watcher to its environment. Nil fields
+// default to production behavior; tests inject them.
+type WorktreeSyncWatcherConfig struct {
+	// Now is the clock source. Defaults to time.Now.
+	Now func() time.Time
+	// Interval returns how often each worktree is synced; 0 disables
+	// syncing. Defaults to [worktree] auto_sync_minutes.
+	Interval func() time.Duration
+	// Sync syncs one session. Defaults to SyncInstanceWorktree onto the
+	// session's recorded base with the configured strategy.
+	Sync func(*Instance) (WorktreeSyncState, error)
+	// LogEvent persists a session lifecycle row. Defaults to
+	// WriteSessionLifecycleEvent.
+	LogEvent func(SessionLifecycleEvent) error
+	// LoadStates reads the recorded states. Defaults to
+	// ReadWorktreeSyncStates.
+	LoadStates func() (map[string]WorktreeSyncState, error)
+}
+
+// WorktreeSyncWatcher keeps worktree sessions rebased onto their base branch
+// when [worktree] auto_sync_minutes is set, and caches every session's last
+// sync state (including syncs run from the CLI) for display. Like
+// WorkingHoursWatcher, Tick is driven from the TUI's background sweep.
+type WorktreeSyncWatcher struct {
+	cfg WorktreeSyncWatcherConfig
+
+	run sync.Mutex // held for a whole Tick; a slow fetch skips the next one
+
+	mu     sync.Mutex
+	states map[string]WorktreeSyncState
+}
+
+// NewWorktreeSyncWatcher constructs a watcher with production defaults
+// filled in for any nil config callback.
+func NewWorktreeSyncWatcher(cfg WorktreeSyncWatcherConfig) *WorktreeSyncWatcher {
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	if cfg.Interval == nil {
+		cfg.Interval = func() time.Duration { return GetWorktreeSettings().AutoSyncInterval() }
+	}
+	if cfg.Sync == nil {
+		cfg.Sync = func(inst *Instance) (WorktreeSyncState, error) {
+			return SyncInstanceWorktree(inst, "", "")
+		}
+	}
+	if cfg.LogEvent == nil {
+		cfg.LogEvent = WriteSessionLifecycleEvent
+	}
+	if cfg.LoadStates == nil {
+		cfg.LoadStates = ReadWorktreeSyncStates
+	}
+	return &WorktreeSyncWatcher{cfg: cfg, states: map[string]WorktreeSyncState{}}
+}
+
+// Tick reloads the recorded states and syncs every worktree session whose
+// last sync is older than the interval. Sessions that are mid-turn or
+// starting are skipped so an agent never has files rewritten under it.
+func (w *WorktreeSyncWatcher) Tick(instances []*Instance) {
+	if !w.run.TryLock() {
+		return
+	}
+	defer w.run.Unlock()
+
+	states, err := w.cfg.LoadStates()
+	if err != nil {
+		sessionLog.Warn("worktree_sync_load_failed", slog.String("error", err.Error()))
+		return
+	}
+	w.mu.Lock()
+	w.states = states
+	w.mu.Unlock()
+
+	interval := w.cfg.Interval()
+	if interval <= 0 {
+		return
+	}
+	now := w.cfg.Now()
+	for _, inst := range instances {
+		if inst == nil || !inst.IsWorktree() || inst.WorktreeBranch == "" || inst.IsArchived() {
+			continue
+		}
+		if st := inst.GetStatusThreadSafe(); st == StatusRunning || st == StatusStarting {
+			continue
+		}
+		prev, seen := states[inst.ID]
+		if seen && now.Sub(prev.At) < interval {
+			continue
+		}
+		if _, err := os.Stat(inst.WorktreePath); err != nil {
+			continue
+		}
+		state, err := w.cfg.Sync(inst)
+		if errors.Is(err, git.ErrWorktreeDirty) {
+			continue
+		}
+		w.mu.Lock()
+		w.states[inst.ID] = state
+		w.mu.Unlock()
+
+		switch {
+		case state.Status == WorktreeSyncConflict && prev.Status != WorktreeSyncConflict:
+			w.logEvent(inst, ReasonWorktreeSyncConflict,
+				fmt.Sprintf("%s conflicts with %s in %d file(s)", state.Strategy, state.Upstream, len(state.ConflictFiles)))
+		case state.Status == WorktreeSyncOK && state.Behind > 0:
+			w.logEvent(inst, ReasonWorktreeSynced,
+				fmt.Sprintf("%s onto %s (%d new commit(s))", state.Strategy, state.Upstream, state.Behind))
+		case err != nil:
+			sessionLog.Warn("worktree_sync_failed",
+				slog.String("instance_id", inst.ID),
+				slog.String("error", err.Error()))
+		}
+	}
+}
+
+// State returns the last recorded sync state of a session.
+func (w *WorktreeSyncWatcher) State(id string) (WorktreeSyncState, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	st, ok := w.states[id]
+	return st, ok
+}
+
+func (w *WorktreeSyncWatcher) logEvent(inst *Instance, action, reason string) {
+	if err := w.cfg.LogEvent(SessionLifecycleEvent{
+		InstanceID: inst.ID,
+		Action:     action,
+		Reason:     reason,
+	}); err != nil {
+		sessionLog.Warn("worktree_sync_log_failed",
+			slog.String("instance_id", inst.ID),
+			slog.String("error", err.Error()))
+	}
+}
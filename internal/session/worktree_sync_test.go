@@ -0,0 +1,86 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWorktreeSyncWatcher_SyncsIdleWorktrees(t *testing.T) {
+	channelsTestEnv(t)
+	clock := newFakeClock(time.Date(2026, 10, 12, 12, 0, 0, 0, time.Local))
+	dir := t.TempDir()
+
+	var synced []string
+	var events []SessionLifecycleEvent
+	stored := map[string]WorktreeSyncState{}
+	w := NewWorktreeSyncWatcher(WorktreeSyncWatcherConfig{
+		Now:      clock.Now,
+		Interval: func() time.Duration { return 30 * time.Minute },
+		Sync: func(inst *Instance) (WorktreeSyncState, error) {
+			synced = append(synced, inst.ID)
+			st := WorktreeSyncState{SessionID: inst.ID, Status: WorktreeSyncConflict, ConflictFiles: []string{"a.go"}, At: clock.Now()}
+			stored[inst.ID] = st
+			return st, nil
+		},
+		LogEvent: func(e SessionLifecycleEvent) error { events = append(events, e); return nil },
+		LoadStates: func() (map[string]WorktreeSyncState, error) {
+			out := make(map[string]WorktreeSyncState, len(stored))
+			for k, v := range stored {
+				out[k] = v
+			}
+			return out, nil
+		},
+	})
+
+	idle := &Instance{ID: "idle", Status: StatusIdle, WorktreePath: dir, WorktreeBranch: "feature/a"}
+	busy := &Instance{ID: "busy", Status: StatusRunning, WorktreePath: dir, WorktreeBranch: "feature/b"}
+	plain := &Instance{ID: "plain", Status: StatusIdle}
+	gone := &Instance{ID: "gone", Status: StatusIdle, WorktreePath: dir + "/missing", WorktreeBranch: "feature/c"}
+	all := []*Instance{idle, busy, plain, gone}
+
+	w.Tick(all)
+	if len(synced) != 1 || synced[0] != "idle" {
+		t.Fatalf("synced = %v, want only the idle worktree", synced)
+	}
+	if st, ok := w.State("idle"); !ok || st.Status != WorktreeSyncConflict {
+		t.Errorf("State(idle) = %+v, %v", st, ok)
+	}
+	if len(events) != 1 || events[0].Action != ReasonWorktreeSyncConflict {
+		t.Errorf("events = %+v, want one conflict event", events)
+	}
+
+	// Within the interval nothing is re-synced.
+	clock.Advance(10 * time.Minute)
+	w.Tick(all)
+	if len(synced) != 1 {
+		t.Fatalf("re-synced within interval: %v", synced)
+	}
+
+	// After it, the still-conflicting session is retried without a second event.
+	clock.Advance(30 * time.Minute)
+	w.Tick(all)
+	if len(synced) != 2 {
+		t.Fatalf("synced = %v, want a retry after the interval", synced)
+	}
+	if len(events) != 1 {
+		t.Errorf("events = %+v, want no repeat conflict event", events)
+	}
+}
+
+func TestWorktreeSyncWatcher_DisabledStillLoadsStates(t *testing.T) {
+	channelsTestEnv(t)
+	w := NewWorktreeSyncWatcher(WorktreeSyncWatcherConfig{
+		Interval: func() time.Duration { return 0 },
+		Sync: func(inst *Instance) (WorktreeSyncState, error) {
+			t.Fatalf("synced %s with auto-sync disabled", inst.ID)
+			return WorktreeSyncState{}, nil
+		},
+		LoadStates: func() (map[string]WorktreeSyncState, error) {
+			return map[string]WorktreeSyncState{"s": {Status: WorktreeSyncOK}}, nil
+		},
+	})
+	w.Tick([]*Instance{{ID: "s", Status: StatusIdle, WorktreePath: t.TempDir(), WorktreeBranch: "b"}})
+	if st, ok := w.State("s"); !ok || st.Status != WorktreeSyncOK {
+		t.Errorf("State(s) = %+v, %v; want the CLI-recorded state", st, ok)
+	}
+}
@@ -0,0 +1,99 @@
+package statedb
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// DelegationRow is one conductor-to-conductor delegation.
+type DelegationRow struct {
+	ID        string
+	ParentID  string
+	From      string
+	To        string
+	Chain     []string
+	Task      string
+	Status    string
+	Result    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+const delegationColumns = `id, parent_id, from_conductor, to_conductor, chain, task, status, result, created_at, updated_at`
+
+// SaveDelegation inserts or replaces a delegation row.
+func (s *StateDB) SaveDelegation(d *DelegationRow) error {
+	chain, err := json.Marshal(d.Chain)
+	if err != nil {
+		return err
+	}
+	return withBusyRetry(func() error {
+		_, err := s.db.Exec(`
+			INSERT OR REPLACE INTO conductor_delegations (`+delegationColumns+`)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, d.ID, d.ParentID, d.From, d.To, string(chain), d.Task, d.Status, d.Result,
+			d.CreatedAt.Unix(), d.UpdatedAt.Unix())
+		return err
+	})
+}
+
+// LoadDelegation returns the delegation with the given id, or nil when there
+// is none.
+func (s *StateDB) LoadDelegation(id string) (*DelegationRow, error) {
+	row := s.db.QueryRow(`SELECT `+delegationColumns+` FROM conductor_delegations WHERE id = ?`, id)
+	d, err := scanDelegation(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return d, err
+}
+
+// LoadDelegations returns delegations ordered oldest first. When statuses
+// are given, only rows in one of those statuses are returned.
+func (s *StateDB) LoadDelegations(statuses ...string) ([]*DelegationRow, error) {
+	query := `SELECT ` + delegationColumns + ` FROM conductor_delegations`
+	args := make([]any, 0, len(statuses))
+	if len(statuses) > 0 {
+		query += ` WHERE status IN (?` + repeatPlaceholder(len(statuses)-1) + `)`
+		for _, st := range statuses {
+			args = append(args, st)
+		}
+	}
+	query += ` ORDER BY created_at, id`
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*DelegationRow
+	for rows.Next() {
+		d, err := scanDelegation(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+func repeatPlaceholder(n int) string {
+	b := make([]byte, 0, n*3)
+	for i := 0; i < n; i++ {
+		b = append(b, ", ?"...)
+	}
+	return string(b)
+}
+
+func scanDelegation(row interface{ Scan(...any) error }) (*DelegationRow, error) {
+	var d DelegationRow
+	var chain string
+	var createdAt, updatedAt int64
+	if err := row.Scan(&d.ID, &d.ParentID, &d.From, &d.To, &chain, &d.Task, &d.Status, &d.Result, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	_ = json.Unmarshal([]byte(chain), &d.Chain)
+	d.CreatedAt = time.Unix(createdAt, 0)
+	d.UpdatedAt = time.Unix(updatedAt, 0)
+	return &d, nil
+}
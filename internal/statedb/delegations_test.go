@@ -0,0 +1,57 @@
+package statedb
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDelegations_RoundTripAndStatusFilter(t *testing.T) {
+	db := newTestDB(t)
+
+	if d, err := db.LoadDelegation("dlg-missing"); err != nil || d != nil {
+		t.Fatalf("LoadDelegation(missing) = %+v, %v; want nil, nil", d, err)
+	}
+
+	base := time.Unix(1_700_000_000, 0)
+	rows := []*DelegationRow{
+		{ID: "dlg-a", From: "ops", To: "review", Chain: []string{"ops", "review"}, Task: "review PR 12", Status: "delivered", CreatedAt: base, UpdatedAt: base},
+		{ID: "dlg-b", ParentID: "dlg-a", From: "review", To: "qa", Chain: []string{"ops", "review", "qa"}, Task: "run e2e", Status: "done", Result: "green", CreatedAt: base.Add(time.Minute), UpdatedAt: base.Add(2 * time.Minute)},
+	}
+	for _, r := range rows {
+		if err := db.SaveDelegation(r); err != nil {
+			t.Fatalf("SaveDelegation(%s): %v", r.ID, err)
+		}
+	}
+
+	got, err := db.LoadDelegation("dlg-b")
+	if err != nil || got == nil {
+		t.Fatalf("LoadDelegation(dlg-b) = %+v, %v", got, err)
+	}
+	if got.ParentID != "dlg-a" || got.Result != "green" || !slices.Equal(got.Chain, []string{"ops", "review", "qa"}) || !got.UpdatedAt.Equal(base.Add(2*time.Minute)) {
+		t.Errorf("round trip mismatch: %+v", got)
+	}
+
+	open, err := db.LoadDelegations("pending", "delivered")
+	if err != nil {
+		t.Fatalf("LoadDelegations(open): %v", err)
+	}
+	if len(open) != 1 || open[0].ID != "dlg-a" {
+		t.Errorf("open delegations = %+v, want only dlg-a", open)
+	}
+	all, err := db.LoadDelegations()
+	if err != nil {
+		t.Fatalf("LoadDelegations(): %v", err)
+	}
+	if len(all) != 2 || all[0].ID != "dlg-a" || all[1].ID != "dlg-b" {
+		t.Errorf("all delegations = %+v, want dlg-a, dlg-b in creation order", all)
+	}
+
+	rows[0].Status = "done"
+	if err := db.SaveDelegation(rows[0]); err != nil {
+		t.Fatalf("SaveDelegation(update): %v", err)
+	}
+	if open, _ := db.LoadDelegations("pending", "delivered"); len(open) != 0 {
+		t.Errorf("after closing dlg-a, open = %+v", open)
+	}
+}
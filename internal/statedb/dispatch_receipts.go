@@ -0,0 +1,110 @@
+package statedb
+
+import (
+	"time"
+)
+
+// ReceiptRow is the read receipt of one prompt delivered to a conductor
+// child: whether the child started working on it. sent_at and acked_at are
+// stored in milliseconds: a prompt is often sent within the same second as
+// the hook event of the turn before it.
+type ReceiptRow struct {
+	ID        int64
+	SessionID string
+	ParentID  string
+	Message   string
+	Status    string
+	Reason    string
+	Attempts  int
+	SentAt    time.Time
+	AckedAt   time.Time
+	UpdatedAt time.Time
+}
+
+const receiptColumns = `id, session_id, parent_id, message, status, reason, attempts, sent_at, acked_at, updated_at`
+
+// InsertReceipt records a new receipt and sets r.ID.
+func (s *StateDB) InsertReceipt(r *ReceiptRow) error {
+	return withBusyRetry(func() error {
+		res, err := s.db.Exec(`
+			INSERT INTO dispatch_receipts (session_id, parent_id, message, status, reason, attempts, sent_at, acked_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, r.SessionID, r.ParentID, r.Message, r.Status, r.Reason, r.Attempts,
+			r.SentAt.UnixMilli(), unixMilliOrZero(r.AckedAt), r.UpdatedAt.Unix())
+		if err != nil {
+			return err
+		}
+		r.ID, err = res.LastInsertId()
+		return err
+	})
+}
+
+// UpdateReceipt writes back a receipt's status, attempts and timestamps.
+func (s *StateDB) UpdateReceipt(r *ReceiptRow) error {
+	return withBusyRetry(func() error {
+		_, err := s.db.Exec(`
+			UPDATE dispatch_receipts
+			SET status = ?, reason = ?, attempts = ?, sent_at = ?, acked_at = ?, updated_at = ?
+			WHERE id = ?
+		`, r.Status, r.Reason, r.Attempts, r.SentAt.UnixMilli(), unixMilliOrZero(r.AckedAt), r.UpdatedAt.Unix(), r.ID)
+		return err
+	})
+}
+
+// LoadReceipts returns receipts ordered oldest first. When statuses are
+// given, only rows in one of those statuses are returned.
+func (s *StateDB) LoadReceipts(statuses ...string) ([]*ReceiptRow, error) {
+	query := `SELECT ` + receiptColumns + ` FROM dispatch_receipts`
+	args := make([]any, 0, len(statuses))
+	if len(statuses) > 0 {
+		query += ` WHERE status IN (?` + repeatPlaceholder(len(statuses)-1) + `)`
+		for _, st := range statuses {
+			args = append(args, st)
+		}
+	}
+	query += ` ORDER BY sent_at, id`
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*ReceiptRow
+	for rows.Next() {
+		var r ReceiptRow
+		var sentAt, ackedAt, updatedAt int64
+		if err := rows.Scan(&r.ID, &r.SessionID, &r.ParentID, &r.Message, &r.Status, &r.Reason, &r.Attempts, &sentAt, &ackedAt, &updatedAt); err != nil {
+			return nil, err
+		}
+		r.SentAt = time.UnixMilli(sentAt)
+		if ackedAt > 0 {
+			r.AckedAt = time.UnixMilli(ackedAt)
+		}
+		r.UpdatedAt = time.Unix(updatedAt, 0)
+		out = append(out, &r)
+	}
+	return out, rows.Err()
+}
+
+// DeleteReceiptsBefore drops settled receipts (any status but those given)
+// last updated before cutoff, so the table does not grow without bound.
+func (s *StateDB) DeleteReceiptsBefore(cutoff time.Time, keepStatuses ...string) error {
+	query := `DELETE FROM dispatch_receipts WHERE updated_at < ?`
+	args := []any{cutoff.Unix()}
+	if len(keepStatuses) > 0 {
+		query += ` AND status NOT IN (?` + repeatPlaceholder(len(keepStatuses)-1) + `)`
+		for _, st := range keepStatuses {
+			args = append(args, st)
+		}
+	}
+	return withBusyRetry(func() error {
+		_, err := s.db.Exec(query, args...)
+		return err
+	})
+}
+
+func unixMilliOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
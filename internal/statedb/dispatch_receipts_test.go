@@ -0,0 +1,53 @@
+package statedb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDispatchReceipts_RoundTripAndPrune(t *testing.T) {
+	db := newTestDB(t)
+
+	base := time.UnixMilli(1_700_000_000_250)
+	pending := &ReceiptRow{SessionID: "child-a", ParentID: "cond", Message: "run the tests", Status: "pending", Attempts: 1, SentAt: base, UpdatedAt: base}
+	acked := &ReceiptRow{SessionID: "child-b", ParentID: "cond", Message: "fix lint", Status: "acked", Attempts: 1, SentAt: base.Add(time.Second), AckedAt: base.Add(3 * time.Second), UpdatedAt: base.Add(3 * time.Second)}
+	for _, r := range []*ReceiptRow{pending, acked} {
+		if err := db.InsertReceipt(r); err != nil {
+			t.Fatalf("InsertReceipt(%s): %v", r.SessionID, err)
+		}
+		if r.ID == 0 {
+			t.Fatalf("InsertReceipt(%s) did not set ID", r.SessionID)
+		}
+	}
+
+	open, err := db.LoadReceipts("pending", "unacked")
+	if err != nil {
+		t.Fatalf("LoadReceipts(open): %v", err)
+	}
+	if len(open) != 1 || open[0].ID != pending.ID || !open[0].SentAt.Equal(base) || !open[0].AckedAt.IsZero() {
+		t.Fatalf("open receipts = %+v, want only child-a sent at %v", open, base)
+	}
+
+	open[0].Status, open[0].Reason, open[0].Attempts = "unacked", "pane is dead", 2
+	if err := db.UpdateReceipt(open[0]); err != nil {
+		t.Fatalf("UpdateReceipt: %v", err)
+	}
+	all, err := db.LoadReceipts()
+	if err != nil {
+		t.Fatalf("LoadReceipts(): %v", err)
+	}
+	if len(all) != 2 || all[0].Status != "unacked" || all[0].Reason != "pane is dead" || all[0].Attempts != 2 {
+		t.Fatalf("after update = %+v", all)
+	}
+	if !all[1].AckedAt.Equal(acked.AckedAt) {
+		t.Errorf("acked_at = %v, want %v", all[1].AckedAt, acked.AckedAt)
+	}
+
+	if err := db.DeleteReceiptsBefore(base.Add(time.Hour), "pending", "unacked"); err != nil {
+		t.Fatalf("DeleteReceiptsBefore: %v", err)
+	}
+	all, _ = db.LoadReceipts()
+	if len(all) != 1 || all[0].ID != pending.ID {
+		t.Errorf("after prune = %+v, want only the open receipt", all)
+	}
+}
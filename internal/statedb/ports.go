@@ -0,0 +1,84 @@
+package statedb
+
+import (
+	"time"
+)
+
+// PortClaim is one port held by a session in the port registry. Name is
+// the environment variable the port is exported as (PORT, PORT_2, ...).
+type PortClaim struct {
+	Port       int
+	InstanceID string
+	Name       string
+	ClaimedAt  time.Time
+}
+
+// ClaimPort records c if its port is not already held. It reports whether
+// the claim was recorded; the port column is the primary key, so two
+// processes racing for the same port cannot both win.
+func (s *StateDB) ClaimPort(c *PortClaim) (bool, error) {
+	var n int64
+	err := withBusyRetry(func() error {
+		res, err := s.db.Exec(`
+			INSERT OR IGNORE INTO port_claims (port, instance_id, name, claimed_at)
+			VALUES (?, ?, ?, ?)
+		`, c.Port, c.InstanceID, c.Name, c.ClaimedAt.Unix())
+		if err != nil {
+			return err
+		}
+		n, _ = res.RowsAffected()
+		return nil
+	})
+	return n > 0, err
+}
+
+// LoadPortClaims returns the claims of one session, or of every session
+// when instanceID is "", ordered by session and port.
+func (s *StateDB) LoadPortClaims(instanceID string) ([]*PortClaim, error) {
+	query := `SELECT port, instance_id, name, claimed_at FROM port_claims`
+	var args []any
+	if instanceID != "" {
+		query += ` WHERE instance_id = ?`
+		args = append(args, instanceID)
+	}
+	query += ` ORDER BY instance_id, port`
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*PortClaim
+	for rows.Next() {
+		var c PortClaim
+		var claimedAt int64
+		if err := rows.Scan(&c.Port, &c.InstanceID, &c.Name, &claimedAt); err != nil {
+			return nil, err
+		}
+		c.ClaimedAt = time.Unix(claimedAt, 0)
+		out = append(out, &c)
+	}
+	return out, rows.Err()
+}
+
+// ReleasePorts drops a session's claims on ports, or all of its claims when
+// none are given. It returns how many were released.
+func (s *StateDB) ReleasePorts(instanceID string, ports ...int) (int, error) {
+	query := `DELETE FROM port_claims WHERE instance_id = ?`
+	args := []any{instanceID}
+	if len(ports) > 0 {
+		query += ` AND port IN (?` + repeatPlaceholder(len(ports)-1) + `)`
+		for _, p := range ports {
+			args = append(args, p)
+		}
+	}
+	var n int64
+	err := withBusyRetry(func() error {
+		res, err := s.db.Exec(query, args...)
+		if err != nil {
+			return err
+		}
+		n, _ = res.RowsAffected()
+		return nil
+	})
+	return int(n), err
+}
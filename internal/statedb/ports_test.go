@@ -0,0 +1,43 @@
+package statedb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPortClaims(t *testing.T) {
+	db := newTestDB(t)
+	at := time.Unix(1_700_000_000, 0)
+	claim := func(port int, inst, name string) bool {
+		t.Helper()
+		ok, err := db.ClaimPort(&PortClaim{Port: port, InstanceID: inst, Name: name, ClaimedAt: at})
+		if err != nil {
+			t.Fatalf("ClaimPort(%d): %v", port, err)
+		}
+		return ok
+	}
+	if !claim(4001, "s1", "PORT") || !claim(4000, "s2", "PORT") || !claim(4002, "s1", "PORT_2") {
+		t.Fatal("free ports must be claimable")
+	}
+	if claim(4001, "s2", "PORT_2") {
+		t.Fatal("a held port must not be claimed twice")
+	}
+
+	s1, err := db.LoadPortClaims("s1")
+	if err != nil || len(s1) != 2 || s1[0].Port != 4001 || s1[1].Name != "PORT_2" || !s1[0].ClaimedAt.Equal(at) {
+		t.Fatalf("LoadPortClaims(s1) = %+v, %v", s1, err)
+	}
+	if all, _ := db.LoadPortClaims(""); len(all) != 3 {
+		t.Fatalf("all claims = %d, want 3", len(all))
+	}
+
+	if n, err := db.ReleasePorts("s1", 4002, 4000); err != nil || n != 1 {
+		t.Fatalf("ReleasePorts(s1, 4002, 4000) = %d, %v; another session's port must stay", n, err)
+	}
+	if err := db.DeleteInstance("s2"); err != nil {
+		t.Fatal(err)
+	}
+	if all, _ := db.LoadPortClaims(""); len(all) != 1 || all[0].Port != 4001 {
+		t.Fatalf("claims after release and delete = %+v", all)
+	}
+}
@@ -111,3 +111,29 @@ func TestDeleteGroupSubtreeRemovesGroupAndDescendants(t *testing.T) {
 		}
 	}
 }
+
+func TestSaveGroupsRoundTripsDefaultMCPs(t *testing.T) {
+	db := newTestDB(t)
+
+	if err := db.SaveGroups([]*GroupRow{
+		{Path: "work", Name: "work", DefaultMCPs: []string{"memory", "exa"}},
+		{Path: "work/scratch", Name: "scratch"},
+	}); err != nil {
+		t.Fatalf("SaveGroups: %v", err)
+	}
+
+	rows, err := db.LoadGroups()
+	if err != nil {
+		t.Fatalf("LoadGroups: %v", err)
+	}
+	got := make(map[string][]string, len(rows))
+	for _, g := range rows {
+		got[g.Path] = g.DefaultMCPs
+	}
+	if m := got["work"]; len(m) != 2 || m[0] != "memory" || m[1] != "exa" {
+		t.Fatalf("work default MCPs = %v, want [memory exa]", m)
+	}
+	if m := got["work/scratch"]; len(m) != 0 {
+		t.Fatalf("work/scratch default MCPs = %v, want none", m)
+	}
+}
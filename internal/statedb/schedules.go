@@ -0,0 +1,122 @@
+package statedb
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ScheduleRow is a recurring message to a session: at every time matching
+// the cron Spec, Message is sent to InstanceID. NextRun is when it fires
+// next; LastStatus is "" before the first run, then "ok" or "error" with
+// LastError holding the reason.
+type ScheduleRow struct {
+	ID         string
+	Spec       string
+	InstanceID string
+	Message    string
+	CreatedAt  time.Time
+	NextRun    time.Time
+	LastRun    time.Time
+	LastStatus string
+	LastError  string
+}
+
+const scheduleColumns = `id, spec, instance_id, message, created_at, next_run, last_run, last_status, last_error`
+
+// SaveSchedule inserts or replaces a schedule row.
+func (s *StateDB) SaveSchedule(r *ScheduleRow) error {
+	var lastRun int64
+	if !r.LastRun.IsZero() {
+		lastRun = r.LastRun.Unix()
+	}
+	return withBusyRetry(func() error {
+		_, err := s.db.Exec(`
+			INSERT OR REPLACE INTO schedules (`+scheduleColumns+`)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, r.ID, r.Spec, r.InstanceID, r.Message, r.CreatedAt.Unix(), r.NextRun.Unix(),
+			lastRun, r.LastStatus, r.LastError)
+		return err
+	})
+}
+
+// LoadSchedules returns every schedule, soonest first.
+func (s *StateDB) LoadSchedules() ([]*ScheduleRow, error) {
+	rows, err := s.db.Query(`SELECT ` + scheduleColumns + ` FROM schedules ORDER BY next_run, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*ScheduleRow
+	for rows.Next() {
+		r, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// LoadSchedule returns the schedule with the given id, or nil when there is
+// none.
+func (s *StateDB) LoadSchedule(id string) (*ScheduleRow, error) {
+	r, err := scanSchedule(s.db.QueryRow(`SELECT `+scheduleColumns+` FROM schedules WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return r, err
+}
+
+// DeleteSchedule removes a schedule. It reports whether one existed.
+func (s *StateDB) DeleteSchedule(id string) (bool, error) {
+	var n int64
+	err := withBusyRetry(func() error {
+		res, err := s.db.Exec(`DELETE FROM schedules WHERE id = ?`, id)
+		if err != nil {
+			return err
+		}
+		n, _ = res.RowsAffected()
+		return nil
+	})
+	return n > 0, err
+}
+
+// ClaimScheduleRun moves a due schedule from due to next. It succeeds only
+// while the stored next_run is still due, so when the TUI and the notify
+// daemon both see the schedule due, exactly one of them runs it.
+func (s *StateDB) ClaimScheduleRun(id string, due, next time.Time) (bool, error) {
+	var n int64
+	err := withBusyRetry(func() error {
+		res, err := s.db.Exec(`UPDATE schedules SET next_run = ? WHERE id = ? AND next_run = ?`,
+			next.Unix(), id, due.Unix())
+		if err != nil {
+			return err
+		}
+		n, _ = res.RowsAffected()
+		return nil
+	})
+	return n > 0, err
+}
+
+// RecordScheduleRun stores the outcome of a run.
+func (s *StateDB) RecordScheduleRun(id string, at time.Time, status, errMsg string) error {
+	return withBusyRetry(func() error {
+		_, err := s.db.Exec(`UPDATE schedules SET last_run = ?, last_status = ?, last_error = ? WHERE id = ?`,
+			at.Unix(), status, errMsg, id)
+		return err
+	})
+}
+
+func scanSchedule(row interface{ Scan(...any) error }) (*ScheduleRow, error) {
+	var r ScheduleRow
+	var createdAt, nextRun, lastRun int64
+	if err := row.Scan(&r.ID, &r.Spec, &r.InstanceID, &r.Message, &createdAt, &nextRun, &lastRun, &r.LastStatus, &r.LastError); err != nil {
+		return nil, err
+	}
+	r.CreatedAt = time.Unix(createdAt, 0)
+	r.NextRun = time.Unix(nextRun, 0)
+	if lastRun > 0 {
+		r.LastRun = time.Unix(lastRun, 0)
+	}
+	return &r, nil
+}
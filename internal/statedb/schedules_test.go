@@ -0,0 +1,47 @@
+package statedb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSchedules_ClaimIsExclusive(t *testing.T) {
+	db := newTestDB(t)
+	due := time.Unix(1_700_000_000, 0)
+	next := due.Add(24 * time.Hour)
+	if err := db.SaveSchedule(&ScheduleRow{
+		ID: "sch-1", Spec: "0 9 * * *", InstanceID: "s1", Message: "hi",
+		CreatedAt: due.Add(-time.Hour), NextRun: due,
+	}); err != nil {
+		t.Fatalf("SaveSchedule: %v", err)
+	}
+
+	if ok, err := db.ClaimScheduleRun("sch-1", due, next); err != nil || !ok {
+		t.Fatalf("first claim = %v, %v", ok, err)
+	}
+	if ok, err := db.ClaimScheduleRun("sch-1", due, next); err != nil || ok {
+		t.Fatalf("second claim of the same run = %v, %v; want refused", ok, err)
+	}
+	if err := db.RecordScheduleRun("sch-1", due, "error", "boom"); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.LoadSchedules()
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("LoadSchedules = %v, %v", rows, err)
+	}
+	r := rows[0]
+	if !r.NextRun.Equal(next) || !r.LastRun.Equal(due) || r.LastStatus != "error" || r.LastError != "boom" {
+		t.Fatalf("row = %+v", r)
+	}
+
+	if removed, err := db.DeleteSchedule("sch-1"); err != nil || !removed {
+		t.Fatalf("DeleteSchedule = %v, %v", removed, err)
+	}
+	if removed, _ := db.DeleteSchedule("sch-1"); removed {
+		t.Fatal("deleting a missing schedule reported success")
+	}
+	if r, err := db.LoadSchedule("sch-1"); err != nil || r != nil {
+		t.Fatalf("LoadSchedule after delete = %v, %v", r, err)
+	}
+}
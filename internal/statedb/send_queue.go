@@ -0,0 +1,80 @@
+package statedb
+
+import (
+	"time"
+)
+
+// QueuedSend is a `session send` message the governor held back, waiting
+// for Claude capacity.
+type QueuedSend struct {
+	ID         string
+	InstanceID string
+	Message    string
+	Reason     string // why it was held, refreshed while it waits
+	Attempts   int    // failed delivery attempts
+	LastError  string
+	QueuedAt   time.Time
+}
+
+// SaveQueuedSend inserts q, or replaces the row with its ID.
+func (s *StateDB) SaveQueuedSend(q *QueuedSend) error {
+	return withBusyRetry(func() error {
+		_, err := s.db.Exec(`
+			INSERT OR REPLACE INTO send_queue (id, instance_id, message, reason, attempts, last_error, queued_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, q.ID, q.InstanceID, q.Message, q.Reason, q.Attempts, q.LastError, q.QueuedAt.Unix())
+		return err
+	})
+}
+
+// LoadQueuedSends returns the queued sends of one session, or of every
+// session when instanceID is "", oldest first.
+func (s *StateDB) LoadQueuedSends(instanceID string) ([]*QueuedSend, error) {
+	query := `SELECT id, instance_id, message, reason, attempts, last_error, queued_at FROM send_queue`
+	var args []any
+	if instanceID != "" {
+		query += ` WHERE instance_id = ?`
+		args = append(args, instanceID)
+	}
+	query += ` ORDER BY queued_at, rowid`
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*QueuedSend
+	for rows.Next() {
+		var q QueuedSend
+		var queuedAt int64
+		if err := rows.Scan(&q.ID, &q.InstanceID, &q.Message, &q.Reason, &q.Attempts, &q.LastError, &queuedAt); err != nil {
+			return nil, err
+		}
+		q.QueuedAt = time.Unix(queuedAt, 0)
+		out = append(out, &q)
+	}
+	return out, rows.Err()
+}
+
+// UpdateQueuedSendReason records why a queued send is still held.
+func (s *StateDB) UpdateQueuedSendReason(id, reason string) error {
+	return withBusyRetry(func() error {
+		_, err := s.db.Exec(`UPDATE send_queue SET reason = ? WHERE id = ?`, reason, id)
+		return err
+	})
+}
+
+// TakeQueuedSend removes a queued send and reports whether this call removed
+// it. Releasing a send takes it first, so when the TUI and the notify daemon
+// drain the queue at once each message is delivered only once.
+func (s *StateDB) TakeQueuedSend(id string) (bool, error) {
+	var n int64
+	err := withBusyRetry(func() error {
+		res, err := s.db.Exec(`DELETE FROM send_queue WHERE id = ?`, id)
+		if err != nil {
+			return err
+		}
+		n, _ = res.RowsAffected()
+		return nil
+	})
+	return n > 0, err
+}
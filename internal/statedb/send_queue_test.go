@@ -0,0 +1,48 @@
+package statedb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSendQueue(t *testing.T) {
+	db := newTestDB(t)
+	at := time.Unix(1_700_000_000, 0)
+	for _, q := range []*QueuedSend{
+		{ID: "q2", InstanceID: "s1", Message: "second", QueuedAt: at.Add(time.Second)},
+		{ID: "q1", InstanceID: "s1", Message: "first", Reason: "claude at capacity (3/3 active)", QueuedAt: at},
+		{ID: "q3", InstanceID: "s2", Message: "other", QueuedAt: at},
+	} {
+		if err := db.SaveQueuedSend(q); err != nil {
+			t.Fatalf("SaveQueuedSend(%s): %v", q.ID, err)
+		}
+	}
+
+	s1, err := db.LoadQueuedSends("s1")
+	if err != nil || len(s1) != 2 || s1[0].ID != "q1" || s1[0].Reason == "" || !s1[0].QueuedAt.Equal(at) {
+		t.Fatalf("LoadQueuedSends(s1) = %+v, %v; want oldest first", s1, err)
+	}
+	if err := db.UpdateQueuedSendReason("q2", "usage limit"); err != nil {
+		t.Fatal(err)
+	}
+	s1[1].Attempts, s1[1].LastError = 1, "timeout"
+	if err := db.SaveQueuedSend(s1[1]); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := db.LoadQueuedSends("s1"); got[1].Attempts != 1 || got[1].LastError != "timeout" {
+		t.Fatalf("resaved q2 = %+v", got[1])
+	}
+
+	if ok, err := db.TakeQueuedSend("q1"); err != nil || !ok {
+		t.Fatalf("TakeQueuedSend(q1) = %v, %v", ok, err)
+	}
+	if ok, _ := db.TakeQueuedSend("q1"); ok {
+		t.Fatal("a send must only be taken once")
+	}
+	if err := db.DeleteInstance("s1"); err != nil {
+		t.Fatal(err)
+	}
+	if all, _ := db.LoadQueuedSends(""); len(all) != 1 || all[0].ID != "q3" {
+		t.Fatalf("queue after take and delete = %+v", all)
+	}
+}
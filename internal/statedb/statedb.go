@@ -60,6 +60,23 @@ func (s *StateDB) backupDBFile() error {
 	return nil
 }
 
+// SnapshotTo writes a consistent copy of the live database to dest with
+// VACUUM INTO, which reads inside a transaction and so is safe while other
+// processes keep writing. dest must not exist. The copy is compacted and
+// self-contained (no -wal sidecar).
+func (s *StateDB) SnapshotTo(dest string) error {
+	if _, err := os.Stat(dest); err == nil {
+		return fmt.Errorf("statedb: snapshot: %s already exists", dest)
+	}
+	return withBusyRetry(func() error {
+		if _, err := s.db.Exec("VACUUM INTO ?", dest); err != nil {
+			_ = os.Remove(dest) // a retry needs dest gone again
+			return fmt.Errorf("statedb: snapshot: %w", err)
+		}
+		return nil
+	})
+}
+
 // withBusyRetry runs op with linear backoff (10ms, 20ms, 30ms, 40ms, 50ms;
 // ~150ms total) when op fails with SQLITE_BUSY. Non-BUSY errors are returned
 // immediately; the final BUSY error is returned if every attempt fails.
@@ -97,7 +114,7 @@ func withBusyRetry(op func() error) error {
 
 // SchemaVersion tracks the current database schema version.
 // Bump this when adding migrations.
-const SchemaVersion = 13
+const SchemaVersion = 22
 
 // StateDB wraps a SQLite database for session/group persistence.
 // Thread-safe for concurrent use from multiple goroutines within one process.
@@ -277,6 +294,9 @@ type GroupRow struct {
 	// 0 = unlimited (legacy default for groups predating this field); 1 = serial
 	// (default for newly-created groups); N>=2 = bounded parallelism.
 	MaxConcurrent int
+	// DefaultMCPs are attached to new sessions in the group; stored as a
+	// JSON array in default_mcps.
+	DefaultMCPs []string
 }
 
 // StatusRow holds status + acknowledgment for a session.
@@ -429,7 +449,8 @@ func (s *StateDB) Migrate() error {
 			expanded       INTEGER NOT NULL DEFAULT 1,
 			sort_order     INTEGER NOT NULL DEFAULT 0,
 			default_path   TEXT NOT NULL DEFAULT '',
-			max_concurrent INTEGER NOT NULL DEFAULT 0
+			max_concurrent INTEGER NOT NULL DEFAULT 0,
+			default_mcps   TEXT NOT NULL DEFAULT '[]'
 		)
 	`); err != nil {
 		return fmt.Errorf("statedb: create groups: %w", err)
@@ -449,7 +470,8 @@ func (s *StateDB) Migrate() error {
 			pid        INTEGER PRIMARY KEY,
 			started    INTEGER NOT NULL,
 			heartbeat  INTEGER NOT NULL,
-			is_primary INTEGER NOT NULL DEFAULT 0
+			is_primary INTEGER NOT NULL DEFAULT 0,
+			control_socket TEXT NOT NULL DEFAULT ''
 		)
 	`); err != nil {
 		return fmt.Errorf("statedb: create heartbeats: %w", err)
@@ -562,6 +584,131 @@ func (s *StateDB) Migrate() error {
 		return fmt.Errorf("statedb: create watcher_events index: %w", err)
 	}
 
+	// conductor_delegations table (v14): tasks one conductor handed to
+	// another. chain is the JSON array of conductor names from the root
+	// delegator to to_conductor, used to refuse delegation loops.
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS conductor_delegations (
+			id             TEXT PRIMARY KEY,
+			parent_id      TEXT NOT NULL DEFAULT '',
+			from_conductor TEXT NOT NULL,
+			to_conductor   TEXT NOT NULL,
+			chain          TEXT NOT NULL DEFAULT '[]',
+			task           TEXT NOT NULL DEFAULT '',
+			status         TEXT NOT NULL,
+			result         TEXT NOT NULL DEFAULT '',
+			created_at     INTEGER NOT NULL,
+			updated_at     INTEGER NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("statedb: create conductor_delegations: %w", err)
+	}
+
+	// dispatch_receipts table (v17): prompts delivered to conductor
+	// children and whether the child acted on them (see
+	// session/conductor_receipts.go).
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS dispatch_receipts (
+			id          INTEGER PRIMARY KEY AUTOINCREMENT,
+			session_id  TEXT NOT NULL,
+			parent_id   TEXT NOT NULL DEFAULT '',
+			message     TEXT NOT NULL DEFAULT '',
+			status      TEXT NOT NULL,
+			reason      TEXT NOT NULL DEFAULT '',
+			attempts    INTEGER NOT NULL DEFAULT 1,
+			sent_at     INTEGER NOT NULL,
+			acked_at    INTEGER NOT NULL DEFAULT 0,
+			updated_at  INTEGER NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("statedb: create dispatch_receipts: %w", err)
+	}
+
+	// status_transitions table (v18): one row per status change of a session,
+	// written by WriteStatus (see status_transitions.go).
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS status_transitions (
+			instance_id TEXT NOT NULL,
+			status      TEXT NOT NULL,
+			at          INTEGER NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("statedb: create status_transitions: %w", err)
+	}
+	if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_status_transitions_instance ON status_transitions(instance_id, at)`); err != nil {
+		return fmt.Errorf("statedb: create status_transitions index: %w", err)
+	}
+
+	// schedules table (v19): cron-style messages to sessions, run by the
+	// TUI and the notify daemon (see session/schedule.go). Times are unix
+	// seconds; last_run is 0 until the first run.
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS schedules (
+			id          TEXT PRIMARY KEY,
+			spec        TEXT NOT NULL,
+			instance_id TEXT NOT NULL,
+			message     TEXT NOT NULL,
+			created_at  INTEGER NOT NULL,
+			next_run    INTEGER NOT NULL,
+			last_run    INTEGER NOT NULL DEFAULT 0,
+			last_status TEXT NOT NULL DEFAULT '',
+			last_error  TEXT NOT NULL DEFAULT ''
+		)
+	`); err != nil {
+		return fmt.Errorf("statedb: create schedules: %w", err)
+	}
+
+	// task_queue table (v20): work items pushed by users and conductors and
+	// drained by conductors (see session/task_queue.go). Times are unix
+	// seconds; dispatched_at is 0 until the first dispatch.
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS task_queue (
+			id            TEXT PRIMARY KEY,
+			session_id    TEXT NOT NULL DEFAULT '',
+			text          TEXT NOT NULL,
+			status        TEXT NOT NULL,
+			source        TEXT NOT NULL DEFAULT '',
+			result        TEXT NOT NULL DEFAULT '',
+			attempts      INTEGER NOT NULL DEFAULT 0,
+			created_at    INTEGER NOT NULL,
+			updated_at    INTEGER NOT NULL,
+			dispatched_at INTEGER NOT NULL DEFAULT 0
+		)
+	`); err != nil {
+		return fmt.Errorf("statedb: create task_queue: %w", err)
+	}
+
+	// port_claims table (v21): the port registry (see session/ports.go).
+	// One row per claimed port; the primary key keeps a port with a single
+	// session. claimed_at is unix seconds.
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS port_claims (
+			port        INTEGER PRIMARY KEY,
+			instance_id TEXT NOT NULL,
+			name        TEXT NOT NULL,
+			claimed_at  INTEGER NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("statedb: create port_claims: %w", err)
+	}
+
+	// send_queue table (v22): messages the [governor] held back from
+	// `session send` until Claude capacity frees up (see
+	// session/governor.go). queued_at is unix seconds.
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS send_queue (
+			id          TEXT PRIMARY KEY,
+			instance_id TEXT NOT NULL,
+			message     TEXT NOT NULL,
+			reason      TEXT NOT NULL DEFAULT '',
+			attempts    INTEGER NOT NULL DEFAULT 0,
+			last_error  TEXT NOT NULL DEFAULT '',
+			queued_at   INTEGER NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("statedb: create send_queue: %w", err)
+	}
+
 	// ALTER TABLE migrations for existing databases.
 	// CREATE TABLE IF NOT EXISTS won't add new columns to tables that already exist.
 	// Each migration is idempotent: errors from "duplicate column" are silently ignored.
@@ -603,6 +750,13 @@ func (s *StateDB) Migrate() error {
 		// deliberate-idle (never a self-heal candidate). Additive + targeted-write
 		// only (WriteLastSentAt); never part of a whole-row REPLACE/SaveInstances.
 		"ALTER TABLE instances ADD COLUMN last_sent_at INTEGER NOT NULL DEFAULT 0",
+		// v15 (control socket): the JSON-RPC socket a live process serves.
+		// Default '' means "no control socket" for heartbeats written by
+		// older binaries.
+		"ALTER TABLE instance_heartbeats ADD COLUMN control_socket TEXT NOT NULL DEFAULT ''",
+		// v16 (group MCP defaults): JSON array of MCPs attached to new
+		// sessions in the group. Default '[]' means "none" for existing groups.
+		"ALTER TABLE groups ADD COLUMN default_mcps TEXT NOT NULL DEFAULT '[]'",
 	}
 	for _, stmt := range alterMigrations {
 		if _, err := tx.Exec(stmt); err != nil {
@@ -699,6 +853,23 @@ func (s *StateDB) Migrate() error {
 				}
 			}
 		}
+		// v14: conductor_delegations is new (CREATE TABLE IF NOT EXISTS
+		// handles creation). No backfill needed.
+		// v15: instance_heartbeats.control_socket is added by the ALTER
+		// list above. No backfill needed.
+		// v17: dispatch_receipts is new (CREATE TABLE IF NOT EXISTS handles
+		// creation). No backfill needed.
+		// v18: status_transitions is new (CREATE TABLE IF NOT EXISTS handles
+		// creation). History starts at the first status change after the
+		// upgrade.
+		// v19: schedules is new (CREATE TABLE IF NOT EXISTS handles
+		// creation). No backfill needed.
+		// v20: task_queue is new (CREATE TABLE IF NOT EXISTS handles
+		// creation). No backfill needed.
+		// v21: port_claims is new (CREATE TABLE IF NOT EXISTS handles
+		// creation). No backfill needed.
+		// v22: send_queue is new (CREATE TABLE IF NOT EXISTS handles
+		// creation). No backfill needed.
 		if _, err := tx.Exec(`
 			UPDATE metadata SET value = ? WHERE key = 'schema_version'
 		`, schemaVersion); err != nil {
@@ -939,6 +1110,10 @@ func (s *StateDB) saveInstancesOnce(insts []*InstanceRow, sweep bool) error {
 		if _, err := tx.Exec(query, args...); err != nil {
 			return err
 		}
+		// The timelines of swept sessions go with them.
+		if _, err := tx.Exec("DELETE FROM status_transitions WHERE instance_id NOT IN ("+strings.Join(placeholders, ",")+")", args...); err != nil {
+			return err
+		}
 	}
 
 	stmt, err := tx.Prepare(`
@@ -1065,6 +1240,15 @@ func (s *StateDB) LoadInstances() ([]*InstanceRow, error) {
 // still reports success — the silent-loss half of issue #909.
 func (s *StateDB) DeleteInstance(id string) error {
 	return withBusyRetry(func() error {
+		if _, err := s.db.Exec("DELETE FROM status_transitions WHERE instance_id = ?", id); err != nil {
+			return err
+		}
+		if _, err := s.db.Exec("DELETE FROM port_claims WHERE instance_id = ?", id); err != nil {
+			return err
+		}
+		if _, err := s.db.Exec("DELETE FROM send_queue WHERE instance_id = ?", id); err != nil {
+			return err
+		}
 		_, err := s.db.Exec("DELETE FROM instances WHERE id = ?", id)
 		return err
 	})
@@ -1144,14 +1328,15 @@ func (s *StateDB) SaveGroups(groups []*GroupRow) error {
 	defer func() { _ = tx.Rollback() }()
 
 	stmt, err := tx.Prepare(`
-		INSERT INTO groups (path, name, expanded, sort_order, default_path, max_concurrent)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO groups (path, name, expanded, sort_order, default_path, max_concurrent, default_mcps)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(path) DO UPDATE SET
 			name = excluded.name,
 			expanded = excluded.expanded,
 			sort_order = excluded.sort_order,
 			default_path = excluded.default_path,
-			max_concurrent = excluded.max_concurrent
+			max_concurrent = excluded.max_concurrent,
+			default_mcps = excluded.default_mcps
 	`)
 	if err != nil {
 		return err
@@ -1163,7 +1348,15 @@ func (s *StateDB) SaveGroups(groups []*GroupRow) error {
 		if g.Expanded {
 			expanded = 1
 		}
-		if _, err := stmt.Exec(g.Path, g.Name, expanded, g.Order, g.DefaultPath, g.MaxConcurrent); err != nil {
+		mcps := g.DefaultMCPs
+		if mcps == nil {
+			mcps = []string{}
+		}
+		mcpsJSON, err := json.Marshal(mcps)
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.Exec(g.Path, g.Name, expanded, g.Order, g.DefaultPath, g.MaxConcurrent, string(mcpsJSON)); err != nil {
 			return err
 		}
 	}
@@ -1174,7 +1367,7 @@ func (s *StateDB) SaveGroups(groups []*GroupRow) error {
 // LoadGroups returns all groups ordered by sort_order.
 func (s *StateDB) LoadGroups() ([]*GroupRow, error) {
 	rows, err := s.db.Query(`
-		SELECT path, name, expanded, sort_order, default_path, max_concurrent
+		SELECT path, name, expanded, sort_order, default_path, max_concurrent, default_mcps
 		FROM groups ORDER BY sort_order
 	`)
 	if err != nil {
@@ -1186,10 +1379,12 @@ func (s *StateDB) LoadGroups() ([]*GroupRow, error) {
 	for rows.Next() {
 		g := &GroupRow{}
 		var expanded int
-		if err := rows.Scan(&g.Path, &g.Name, &expanded, &g.Order, &g.DefaultPath, &g.MaxConcurrent); err != nil {
+		var mcpsJSON string
+		if err := rows.Scan(&g.Path, &g.Name, &expanded, &g.Order, &g.DefaultPath, &g.MaxConcurrent, &mcpsJSON); err != nil {
 			return nil, err
 		}
 		g.Expanded = expanded != 0
+		_ = json.Unmarshal([]byte(mcpsJSON), &g.DefaultMCPs)
 		result = append(result, g)
 	}
 	return result, rows.Err()
@@ -1218,7 +1413,10 @@ func (s *StateDB) DeleteGroupSubtree(path string) error {
 
 // --- Status + Acknowledgment ---
 
-// WriteStatus updates the status and tool for an instance.
+// WriteStatus updates the status and tool for an instance. A change of
+// status is also appended to status_transitions, in the same transaction, so
+// every writer (TUI poller, transition daemon, attach return) feeds the
+// session's timeline.
 //
 // Wrapped in withBusyRetry: the transition daemon (#755 family) calls this
 // under contention with other writers (heartbeat, status poller, hook
@@ -1226,14 +1424,24 @@ func (s *StateDB) DeleteGroupSubtree(path string) error {
 // status update and the TUI shows stale state.
 func (s *StateDB) WriteStatus(id, status, tool string) error {
 	return withBusyRetry(func() error {
-		_, err := s.db.Exec(
+		tx, err := s.db.Begin()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = tx.Rollback() }()
+		if err := recordStatusChange(tx, id, status, time.Now()); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(
 			`UPDATE instances
 			 SET status = ?, tool = ?,
 			     acknowledged = CASE WHEN ? = 'running' THEN 0 ELSE acknowledged END
 			 WHERE id = ?`,
 			status, tool, status, id,
-		)
-		return err
+		); err != nil {
+			return err
+		}
+		return tx.Commit()
 	})
 }
 
@@ -1349,7 +1557,11 @@ func (s *StateDB) PersistInstanceStatusesTx(updates []InstanceStatusUpdate) erro
 		}
 		defer stmt.Close()
 
+		now := time.Now()
 		for _, u := range updates {
+			if err := recordStatusChange(tx, u.ID, u.Status, now); err != nil {
+				return err
+			}
 			if _, err := stmt.Exec(u.Status, u.Status, u.ID); err != nil {
 				return err
 			}
@@ -1549,6 +1761,33 @@ func (s *StateDB) CleanDeadInstances(timeout time.Duration) error {
 	return err
 }
 
+// SetControlSocket records the control socket this process serves on its
+// heartbeat row. Call after RegisterInstance, which replaces the row.
+func (s *StateDB) SetControlSocket(path string) error {
+	_, err := s.db.Exec(
+		"UPDATE instance_heartbeats SET control_socket = ? WHERE pid = ?",
+		path, s.pid,
+	)
+	return err
+}
+
+// LiveControlSocket returns the control socket of a live instance, preferring
+// the primary and then the most recent heartbeat. Returns "" when no live
+// instance serves one.
+func (s *StateDB) LiveControlSocket() (string, error) {
+	var path string
+	cutoff := time.Now().Add(-30 * time.Second).Unix()
+	err := s.db.QueryRow(`
+		SELECT control_socket FROM instance_heartbeats
+		WHERE heartbeat >= ? AND control_socket != ''
+		ORDER BY is_primary DESC, heartbeat DESC LIMIT 1
+	`, cutoff).Scan(&path)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return path, err
+}
+
 // AliveInstanceCount returns how many TUI instances have fresh heartbeats.
 func (s *StateDB) AliveInstanceCount() (int, error) {
 	var count int
@@ -1824,6 +2063,27 @@ func (s *StateDB) LoadClaims() (map[string]ClaimRow, error) {
 	return out, rows.Err()
 }
 
+// IntegrityCheck runs SQLite's quick_check and returns the problems it
+// reports, or nil for a healthy database. Used by `agent-deck doctor`.
+func (s *StateDB) IntegrityCheck() ([]string, error) {
+	rows, err := s.db.Query("PRAGMA quick_check")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var problems []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, err
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	return problems, rows.Err()
+}
+
 // --- Metadata ---
 
 // SetMeta sets a key-value pair in the metadata table.
@@ -1866,6 +2126,52 @@ func (s *StateDB) TakeMeta(key string) (string, error) {
 	return value, nil
 }
 
+// --- Status Counters ---
+
+// statusCountersKey is the metadata key holding the latest StatusCounters.
+const statusCountersKey = "status_counters"
+
+// StatusCounters is the per-status session tally a running TUI publishes so
+// `agent-deck status -q` can answer without loading the profile or polling
+// tmux.
+type StatusCounters struct {
+	Waiting   int   `json:"waiting"`
+	Running   int   `json:"running"`
+	Idle      int   `json:"idle"`
+	Error     int   `json:"error"`
+	Stopped   int   `json:"stopped"`
+	Exited    int   `json:"exited"`
+	Crashed   int   `json:"crashed"`
+	Total     int   `json:"total"`
+	UpdatedAt int64 `json:"updated_at"` // unix seconds
+}
+
+// WriteStatusCounters stores c, stamping UpdatedAt with the current time.
+func (s *StateDB) WriteStatusCounters(c StatusCounters) error {
+	c.UpdatedAt = time.Now().Unix()
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return s.SetMeta(statusCountersKey, string(data))
+}
+
+// ReadStatusCounters returns the last published counters. ok is false when
+// none were written or they are older than maxAge.
+func (s *StateDB) ReadStatusCounters(maxAge time.Duration) (c StatusCounters, ok bool, err error) {
+	value, err := s.GetMeta(statusCountersKey)
+	if err != nil || value == "" {
+		return c, false, err
+	}
+	if err := json.Unmarshal([]byte(value), &c); err != nil {
+		return c, false, err
+	}
+	if time.Since(time.Unix(c.UpdatedAt, 0)) > maxAge {
+		return c, false, nil
+	}
+	return c, true, nil
+}
+
 // --- Change Detection (replaces fsnotify) ---
 
 // Touch updates a metadata timestamp that other instances can poll to detect changes.
@@ -394,6 +394,44 @@ func TestHeartbeatCleanup(t *testing.T) {
 	}
 }
 
+func TestControlSocket(t *testing.T) {
+	db := newTestDB(t)
+
+	if err := db.RegisterInstance(false); err != nil {
+		t.Fatalf("RegisterInstance: %v", err)
+	}
+	path, err := db.LiveControlSocket()
+	if err != nil || path != "" {
+		t.Fatalf("LiveControlSocket before set = %q, %v; want empty", path, err)
+	}
+
+	// A stale instance that still advertises a socket must be ignored.
+	stale := time.Now().Add(-2 * time.Minute).Unix()
+	if _, err := db.DB().Exec(
+		"INSERT INTO instance_heartbeats (pid, started, heartbeat, is_primary, control_socket) VALUES (?, ?, ?, ?, ?)",
+		99999, stale, stale, 1, "/tmp/stale.sock",
+	); err != nil {
+		t.Fatalf("Insert stale: %v", err)
+	}
+	if err := db.SetControlSocket("/tmp/live.sock"); err != nil {
+		t.Fatalf("SetControlSocket: %v", err)
+	}
+	path, err = db.LiveControlSocket()
+	if err != nil {
+		t.Fatalf("LiveControlSocket: %v", err)
+	}
+	if path != "/tmp/live.sock" {
+		t.Errorf("LiveControlSocket = %q, want /tmp/live.sock", path)
+	}
+
+	if err := db.UnregisterInstance(); err != nil {
+		t.Fatalf("UnregisterInstance: %v", err)
+	}
+	if path, _ = db.LiveControlSocket(); path != "" {
+		t.Errorf("LiveControlSocket after unregister = %q, want empty", path)
+	}
+}
+
 func TestTouchAndLastModified(t *testing.T) {
 	db := newTestDB(t)
 
@@ -568,6 +606,46 @@ func TestMetadata(t *testing.T) {
 	}
 }
 
+func TestIntegrityCheck(t *testing.T) {
+	db := newTestDB(t)
+	problems, err := db.IntegrityCheck()
+	if err != nil {
+		t.Fatalf("IntegrityCheck: %v", err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("fresh database reported problems: %v", problems)
+	}
+}
+
+func TestStatusCounters(t *testing.T) {
+	db := newTestDB(t)
+
+	if _, ok, err := db.ReadStatusCounters(time.Minute); err != nil || ok {
+		t.Fatalf("empty db: ok=%v err=%v, want no counters", ok, err)
+	}
+
+	want := StatusCounters{Waiting: 2, Running: 1, Idle: 3, Total: 6}
+	if err := db.WriteStatusCounters(want); err != nil {
+		t.Fatalf("WriteStatusCounters: %v", err)
+	}
+	got, ok, err := db.ReadStatusCounters(time.Minute)
+	if err != nil || !ok {
+		t.Fatalf("ReadStatusCounters: ok=%v err=%v", ok, err)
+	}
+	if got.Waiting != 2 || got.Total != 6 || got.UpdatedAt == 0 {
+		t.Errorf("got %+v", got)
+	}
+
+	// A row older than maxAge means no TUI is maintaining it.
+	stale, _ := json.Marshal(StatusCounters{Waiting: 9, UpdatedAt: time.Now().Add(-time.Hour).Unix()})
+	if err := db.SetMeta(statusCountersKey, string(stale)); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := db.ReadStatusCounters(time.Minute); ok {
+		t.Error("stale counters reported as fresh")
+	}
+}
+
 func TestElectPrimary_FirstInstance(t *testing.T) {
 	db := newTestDB(t)
 
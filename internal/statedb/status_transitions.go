@@ -0,0 +1,75 @@
+package statedb
+
+import (
+	"database/sql"
+	"time"
+)
+
+// StatusTransitionRow is one status change of a session: from At on, the
+// session was in Status. at is stored in milliseconds so quick
+// running→waiting flips keep their order.
+type StatusTransitionRow struct {
+	Status string
+	At     time.Time
+}
+
+// statusTransitionsMaxRows caps the timeline kept per session. At a few dozen
+// changes a day that is weeks of history; older rows are pruned on write.
+const statusTransitionsMaxRows = 2000
+
+// recordStatusChange appends (id, status, at) to status_transitions when the
+// instance's stored status differs from status, and prunes the session's
+// oldest rows beyond statusTransitionsMaxRows. It runs inside the caller's
+// transaction, before the status UPDATE, so the comparison sees the old
+// value. Unknown ids record nothing.
+func recordStatusChange(tx *sql.Tx, id, status string, at time.Time) error {
+	res, err := tx.Exec(`
+		INSERT INTO status_transitions (instance_id, status, at)
+		SELECT id, ?, ? FROM instances WHERE id = ? AND status != ?
+	`, status, at.UnixMilli(), id, status)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil
+	}
+	_, err = tx.Exec(`
+		DELETE FROM status_transitions
+		WHERE instance_id = ? AND at < (
+			SELECT at FROM status_transitions WHERE instance_id = ?
+			ORDER BY at DESC LIMIT 1 OFFSET ?
+		)
+	`, id, id, statusTransitionsMaxRows-1)
+	return err
+}
+
+// LoadStatusTransitions returns the status changes of a session, oldest first.
+// When since is non-zero, the last change before since is included too, so
+// the caller knows the status the session was in at since.
+func (s *StateDB) LoadStatusTransitions(id string, since time.Time) ([]StatusTransitionRow, error) {
+	var sinceMs int64
+	if !since.IsZero() {
+		sinceMs = since.UnixMilli()
+	}
+	rows, err := s.db.Query(`
+		SELECT status, at FROM status_transitions
+		WHERE instance_id = ? AND at >= COALESCE(
+			(SELECT MAX(at) FROM status_transitions WHERE instance_id = ? AND at <= ?), 0)
+		ORDER BY at, rowid
+	`, id, id, sinceMs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []StatusTransitionRow
+	for rows.Next() {
+		var r StatusTransitionRow
+		var at int64
+		if err := rows.Scan(&r.Status, &at); err != nil {
+			return nil, err
+		}
+		r.At = time.UnixMilli(at)
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
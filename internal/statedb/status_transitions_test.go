@@ -0,0 +1,73 @@
+package statedb
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestStatusTransitions_RecordedOnChangeOnly(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.SaveInstance(&InstanceRow{
+		ID: "s1", Title: "S1", ProjectPath: "/tmp", GroupPath: "grp",
+		Tool: "claude", Status: "idle", CreatedAt: time.Now(), ToolData: json.RawMessage("{}"),
+	}); err != nil {
+		t.Fatalf("SaveInstance: %v", err)
+	}
+
+	for _, status := range []string{"running", "running", "waiting", "waiting", "running"} {
+		if err := db.WriteStatus("s1", status, "claude"); err != nil {
+			t.Fatalf("WriteStatus(%s): %v", status, err)
+		}
+	}
+	if err := db.WriteStatus("missing", "running", "claude"); err != nil {
+		t.Fatalf("WriteStatus(missing): %v", err)
+	}
+
+	rows, err := db.LoadStatusTransitions("s1", time.Time{})
+	if err != nil {
+		t.Fatalf("LoadStatusTransitions: %v", err)
+	}
+	var got []string
+	for _, r := range rows {
+		got = append(got, r.Status)
+	}
+	want := []string{"running", "waiting", "running"}
+	if len(got) != len(want) {
+		t.Fatalf("transitions = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("transitions = %v, want %v", got, want)
+		}
+	}
+	if rows, _ := db.LoadStatusTransitions("missing", time.Time{}); len(rows) != 0 {
+		t.Errorf("unknown session recorded %v", rows)
+	}
+
+	if err := db.DeleteInstance("s1"); err != nil {
+		t.Fatalf("DeleteInstance: %v", err)
+	}
+	if rows, _ := db.LoadStatusTransitions("s1", time.Time{}); len(rows) != 0 {
+		t.Errorf("history survived DeleteInstance: %v", rows)
+	}
+}
+
+func TestLoadStatusTransitions_SinceKeepsStatusInEffect(t *testing.T) {
+	db := newTestDB(t)
+	base := time.UnixMilli(1_700_000_000_000)
+	for i, status := range []string{"running", "waiting", "running", "idle"} {
+		if _, err := db.DB().Exec(`INSERT INTO status_transitions (instance_id, status, at) VALUES (?, ?, ?)`,
+			"s1", status, base.Add(time.Duration(i)*time.Hour).UnixMilli()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rows, err := db.LoadStatusTransitions("s1", base.Add(90*time.Minute))
+	if err != nil {
+		t.Fatalf("LoadStatusTransitions: %v", err)
+	}
+	if len(rows) != 3 || rows[0].Status != "waiting" || !rows[0].At.Equal(base.Add(time.Hour)) {
+		t.Fatalf("rows = %+v, want waiting (in effect at since), running, idle", rows)
+	}
+}
@@ -0,0 +1,138 @@
+package statedb
+
+import (
+	"database/sql"
+	"time"
+)
+
+// TaskRow is one work item in the task queue. SessionID is the session it
+// was dispatched (or is meant) to; "" leaves routing to the conductor.
+// Status moves queued -> dispatched -> done|failed; a failed or stuck task
+// can go back to queued. Result holds the done summary or failure reason.
+type TaskRow struct {
+	ID           string
+	SessionID    string
+	Text         string
+	Status       string
+	Source       string
+	Result       string
+	Attempts     int
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	DispatchedAt time.Time
+}
+
+const taskColumns = `id, session_id, text, status, source, result, attempts, created_at, updated_at, dispatched_at`
+
+// SaveTask inserts or replaces a task row.
+func (s *StateDB) SaveTask(t *TaskRow) error {
+	var dispatchedAt int64
+	if !t.DispatchedAt.IsZero() {
+		dispatchedAt = t.DispatchedAt.Unix()
+	}
+	return withBusyRetry(func() error {
+		_, err := s.db.Exec(`
+			INSERT OR REPLACE INTO task_queue (`+taskColumns+`)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, t.ID, t.SessionID, t.Text, t.Status, t.Source, t.Result, t.Attempts,
+			t.CreatedAt.Unix(), t.UpdatedAt.Unix(), dispatchedAt)
+		return err
+	})
+}
+
+// LoadTask returns the task with the given id, or nil when there is none.
+func (s *StateDB) LoadTask(id string) (*TaskRow, error) {
+	t, err := scanTask(s.db.QueryRow(`SELECT `+taskColumns+` FROM task_queue WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return t, err
+}
+
+// LoadTasks returns tasks in queue order (oldest first). When statuses are
+// given, only tasks in one of them are returned.
+func (s *StateDB) LoadTasks(statuses ...string) ([]*TaskRow, error) {
+	query := `SELECT ` + taskColumns + ` FROM task_queue`
+	args := make([]any, 0, len(statuses))
+	if len(statuses) > 0 {
+		query += ` WHERE status IN (?` + repeatPlaceholder(len(statuses)-1) + `)`
+		for _, st := range statuses {
+			args = append(args, st)
+		}
+	}
+	query += ` ORDER BY created_at, id`
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []*TaskRow
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+// TransitionTask moves a task to status `to` if it is currently in one of
+// `from`, so two drainers racing for the same task cannot both win. A
+// non-empty sessionID replaces the task's session; result replaces its
+// result. Moving to "dispatched" stamps dispatched_at and counts an attempt.
+func (s *StateDB) TransitionTask(id string, from []string, to, sessionID, result string, at time.Time) (bool, error) {
+	set := `status = ?, result = ?, updated_at = ?`
+	args := []any{to, result, at.Unix()}
+	if sessionID != "" {
+		set += `, session_id = ?`
+		args = append(args, sessionID)
+	}
+	if to == "dispatched" {
+		set += `, dispatched_at = ?, attempts = attempts + 1`
+		args = append(args, at.Unix())
+	}
+	args = append(args, id)
+	for _, st := range from {
+		args = append(args, st)
+	}
+	var n int64
+	err := withBusyRetry(func() error {
+		res, err := s.db.Exec(`UPDATE task_queue SET `+set+` WHERE id = ? AND status IN (?`+repeatPlaceholder(len(from)-1)+`)`, args...)
+		if err != nil {
+			return err
+		}
+		n, _ = res.RowsAffected()
+		return nil
+	})
+	return n > 0, err
+}
+
+// DeleteTask removes a task. It reports whether one existed.
+func (s *StateDB) DeleteTask(id string) (bool, error) {
+	var n int64
+	err := withBusyRetry(func() error {
+		res, err := s.db.Exec(`DELETE FROM task_queue WHERE id = ?`, id)
+		if err != nil {
+			return err
+		}
+		n, _ = res.RowsAffected()
+		return nil
+	})
+	return n > 0, err
+}
+
+func scanTask(row interface{ Scan(...any) error }) (*TaskRow, error) {
+	var t TaskRow
+	var createdAt, updatedAt, dispatchedAt int64
+	if err := row.Scan(&t.ID, &t.SessionID, &t.Text, &t.Status, &t.Source, &t.Result, &t.Attempts,
+		&createdAt, &updatedAt, &dispatchedAt); err != nil {
+		return nil, err
+	}
+	t.CreatedAt = time.Unix(createdAt, 0)
+	t.UpdatedAt = time.Unix(updatedAt, 0)
+	if dispatchedAt > 0 {
+		t.DispatchedAt = time.Unix(dispatchedAt, 0)
+	}
+	return &t, nil
+}
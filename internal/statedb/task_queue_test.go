@@ -0,0 +1,50 @@
+package statedb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTaskQueue_Transitions(t *testing.T) {
+	db := newTestDB(t)
+	at := time.Unix(1_700_000_000, 0)
+	for i, id := range []string{"task-b", "task-a"} {
+		if err := db.SaveTask(&TaskRow{
+			ID: id, Text: "do " + id, Status: "queued", Source: "user",
+			CreatedAt: at.Add(time.Duration(i) * time.Minute), UpdatedAt: at,
+		}); err != nil {
+			t.Fatalf("SaveTask: %v", err)
+		}
+	}
+
+	queued, err := db.LoadTasks("queued")
+	if err != nil || len(queued) != 2 || queued[0].ID != "task-b" {
+		t.Fatalf("LoadTasks(queued) = %v, %v; want task-b first", queued, err)
+	}
+
+	later := at.Add(time.Hour)
+	if ok, err := db.TransitionTask("task-b", []string{"queued"}, "dispatched", "s1", "", later); err != nil || !ok {
+		t.Fatalf("dispatch = %v, %v", ok, err)
+	}
+	if ok, _ := db.TransitionTask("task-b", []string{"queued"}, "dispatched", "s2", "", later); ok {
+		t.Fatal("second dispatch of the same task succeeded")
+	}
+	if ok, err := db.TransitionTask("task-b", []string{"queued", "dispatched"}, "done", "", "shipped", later); err != nil || !ok {
+		t.Fatalf("done = %v, %v", ok, err)
+	}
+
+	got, err := db.LoadTask("task-b")
+	if err != nil || got == nil {
+		t.Fatalf("LoadTask = %v, %v", got, err)
+	}
+	if got.Status != "done" || got.SessionID != "s1" || got.Result != "shipped" || got.Attempts != 1 || !got.DispatchedAt.Equal(later) {
+		t.Fatalf("task = %+v", got)
+	}
+
+	if removed, err := db.DeleteTask("task-a"); err != nil || !removed {
+		t.Fatalf("DeleteTask = %v, %v", removed, err)
+	}
+	if all, _ := db.LoadTasks(); len(all) != 1 {
+		t.Fatalf("tasks after delete = %d, want 1", len(all))
+	}
+}
@@ -51,6 +51,12 @@ type AttachRequest struct {
 	// Issue #1100.
 	OpenAs string
 
+	// Title is an optional human-readable label for launchers that can name
+	// what they open (currently: the tmux window created by
+	// OpenSessionInTmuxPane in window mode). Empty leaves naming to the
+	// terminal.
+	Title string
+
 	// Remote, when non-nil, switches BuildAttachCommand from a local
 	// `tmux attach` invocation to an `ssh` invocation that runs
 	// `agent-deck session attach <Name>` on the remote host. Issue #1100,
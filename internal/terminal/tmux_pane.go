@@ -0,0 +1,81 @@
+package terminal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// Pane modes for OpenSessionInTmuxPane. The values match the [ui]
+// attach_mode config strings so callers can pass the resolved setting
+// straight through.
+const (
+	TmuxPaneSplit  = "split"
+	TmuxPaneWindow = "window"
+)
+
+// ErrNotInTmux is returned by OpenSessionInTmuxPane when agent-deck is not
+// running inside a tmux client, so there is no "current" window to split.
+var ErrNotInTmux = errors.New("terminal: attaching in a pane requires agent-deck to run inside tmux")
+
+// InsideTmux reports whether the current process runs inside a tmux client.
+func InsideTmux() bool {
+	return strings.TrimSpace(os.Getenv("TMUX")) != ""
+}
+
+// BuildTmuxPaneArgs returns the tmux argv (without the leading "tmux") that
+// opens a pane in the *current* tmux client and attaches it to the requested
+// session. mode TmuxPaneWindow creates a new window; anything else splits
+// targetPane horizontally so the new pane sits to its right. An empty
+// targetPane lets tmux pick the active pane.
+//
+// The attach runs with TMUX unset: the pane inherits the outer client's TMUX
+// variable, and tmux refuses a nested attach while it is set. No -L flag is
+// added for the outer server — the tmux binary finds it through TMUX in our
+// own environment. Returns nil when the request has no attach command.
+func BuildTmuxPaneArgs(req AttachRequest, mode, targetPane string) []string {
+	attach := BuildAttachCommand(req)
+	if attach == "" {
+		return nil
+	}
+	shellCmd := "env -u TMUX " + attach
+
+	var args []string
+	if mode == TmuxPaneWindow {
+		args = []string{"new-window"}
+		if title := strings.TrimSpace(req.Title); title != "" {
+			args = append(args, "-n", title)
+		}
+	} else {
+		args = []string{"split-window", "-h"}
+	}
+	if pane := strings.TrimSpace(targetPane); pane != "" {
+		args = append(args, "-t", pane)
+	}
+	return append(args, shellCmd)
+}
+
+// OpenSessionInTmuxPane attaches the requested session in a new split or
+// window of the tmux client agent-deck is running in, leaving agent-deck's
+// own pane untouched. Returns ErrNotInTmux outside tmux.
+func OpenSessionInTmuxPane(req AttachRequest, mode string) error {
+	if !InsideTmux() {
+		return ErrNotInTmux
+	}
+	args := BuildTmuxPaneArgs(req, mode, os.Getenv("TMUX_PANE"))
+	if args == nil {
+		return fmt.Errorf("terminal: empty attach command (missing session name or remote host)")
+	}
+	// Empty socket name: no -L, so tmux routes to the outer server via TMUX.
+	out, err := tmux.Exec("", args...).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("tmux %s: %s", args[0], msg)
+		}
+		return fmt.Errorf("tmux %s: %w", args[0], err)
+	}
+	return nil
+}
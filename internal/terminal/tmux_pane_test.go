@@ -0,0 +1,44 @@
+package terminal
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBuildTmuxPaneArgs_Split(t *testing.T) {
+	got := BuildTmuxPaneArgs(AttachRequest{Name: "proj", SocketName: "agentdeck"}, TmuxPaneSplit, "%3")
+	want := []string{"split-window", "-h", "-t", "%3", "env -u TMUX tmux -L 'agentdeck' attach -t 'proj'"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("split args:\n got=%q\nwant=%q", got, want)
+	}
+}
+
+func TestBuildTmuxPaneArgs_WindowNamedAfterTitle(t *testing.T) {
+	got := BuildTmuxPaneArgs(AttachRequest{Name: "proj", Title: "my agent"}, TmuxPaneWindow, "")
+	want := []string{"new-window", "-n", "my agent", "env -u TMUX tmux attach -t 'proj'"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("window args:\n got=%q\nwant=%q", got, want)
+	}
+}
+
+func TestBuildTmuxPaneArgs_UnknownModeSplits(t *testing.T) {
+	got := BuildTmuxPaneArgs(AttachRequest{Name: "proj"}, "", "")
+	if len(got) < 2 || got[0] != "split-window" || got[1] != "-h" {
+		t.Fatalf("expected horizontal split for unknown mode, got %q", got)
+	}
+}
+
+func TestBuildTmuxPaneArgs_EmptyName(t *testing.T) {
+	if got := BuildTmuxPaneArgs(AttachRequest{}, TmuxPaneSplit, "%1"); got != nil {
+		t.Fatalf("expected nil args for empty request, got %q", got)
+	}
+}
+
+func TestOpenSessionInTmuxPane_OutsideTmux(t *testing.T) {
+	t.Setenv("TMUX", "")
+	err := OpenSessionInTmuxPane(AttachRequest{Name: "proj"}, TmuxPaneSplit)
+	if !errors.Is(err, ErrNotInTmux) {
+		t.Fatalf("expected ErrNotInTmux outside tmux, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package tmux
+
+import "testing"
+
+// Aider: tmux-layer detection and status pattern tests.
+
+func TestDetectToolFromCommand_Aider(t *testing.T) {
+	for _, cmd := range []string{"aider", "aider --model sonnet", "/home/user/.local/bin/aider", "AIDER"} {
+		if got := detectToolFromCommand(cmd); got != "aider" {
+			t.Errorf("detectToolFromCommand(%q) = %q, want aider", cmd, got)
+		}
+	}
+}
+
+func TestDetectToolFromContent_Aider(t *testing.T) {
+	content := "Aider v0.86.1\nMain model: anthropic/claude-sonnet-4 with diff edit format\nUse /help <question> for help, run \"aider --help\" to see cmd line args\n> "
+	if got := detectToolFromContent(content); got != "aider" {
+		t.Errorf("detectToolFromContent = %q, want aider", got)
+	}
+}
+
+func TestAiderPrompt(t *testing.T) {
+	d := NewPromptDetector("aider")
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"empty prompt", "Tokens: 2.4k sent, 120 received.\n\n> ", true},
+		{"architect prompt", "architect> ", true},
+		{"partly typed", "> fix the failing test", true},
+		{"add file confirmation", "Add src/app.go to the chat? (Y)es/(N)o/(D)on't ask again [Yes]: ", true},
+		{"answered confirmation", "Add src/app.go to the chat? (Y)es/(N)o/(D)on't ask again [Yes]: y\nApplied edit to src/app.go", false},
+		{"waiting for model", "> fix it\n\n░█        Waiting for anthropic/claude-sonnet-4", false},
+		{"streaming output", "I'll update the handler so it returns early.\n\nsrc/app.go", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.HasPrompt(tt.content); got != tt.want {
+				t.Errorf("HasPrompt(%q) = %v, want %v", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultRawPatterns_Aider(t *testing.T) {
+	resolved, err := CompilePatterns(DefaultRawPatterns("aider"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	busy := "> fix it\n\n░█        Waiting for anthropic/claude-sonnet-4"
+	matched := false
+	for _, re := range resolved.BusyRegexps {
+		matched = matched || re.MatchString(busy)
+	}
+	if !matched {
+		t.Error("aider busy patterns do not match the Waiting for spinner line")
+	}
+	for _, re := range resolved.BusyRegexps {
+		if re.MatchString("I am waiting for your reply on the design.\n> ") {
+			t.Errorf("busy pattern %q matched prose", re)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package tmux
+
+import (
+	"log/slog"
+	"strings"
+	"sync/atomic"
+)
+
+// Control-mode-only operation ([tmux] control_mode_only): mutating tmux
+// commands — send-keys, set-option, kill-session, respawn-pane — are written
+// to a live PipeManager control pipe on the session's socket instead of
+// spawning a `tmux` subprocess per call. On a loaded host the fork+exec of
+// those short-lived processes dominates agent-deck's CPU.
+//
+// A command falls back to a subprocess when no pipe can be had (CLI
+// processes run without a PipeManager; background sessions are not
+// connected), when an argument cannot be expressed on a control-mode command
+// line (embedded newline), or when the pipe breaks before the command is
+// written. Errors tmux itself reports (%error) are returned as-is, never
+// retried, so a command is never run twice.
+
+var controlModeOnly atomic.Bool
+
+// SetControlModeOnly enables or disables control-mode-only operation for the
+// process. Called once at startup from [tmux] control_mode_only.
+func SetControlModeOnly(on bool) {
+	controlModeOnly.Store(on)
+}
+
+// ControlModeOnly reports whether control-mode-only operation is enabled.
+func ControlModeOnly() bool {
+	return controlModeOnly.Load()
+}
+
+// quoteControlCommand renders argv as a tmux control-mode command line. Each
+// argument is single-quoted (an embedded quote closes, escapes and reopens
+// the quoting) so tmux performs no expansion on it; a bare ";" stays unquoted
+// and separates chained commands, as it does in subprocess argv. ok is false when an argument contains a
+// newline, which would end the command line early.
+func quoteControlCommand(args []string) (string, bool) {
+	var b strings.Builder
+	for i, a := range args {
+		if strings.ContainsAny(a, "\n\r") {
+			return "", false
+		}
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		if a == ";" {
+			b.WriteString(a)
+			continue
+		}
+		b.WriteByte('\'')
+		b.WriteString(strings.ReplaceAll(a, "'", `'\''`))
+		b.WriteByte('\'')
+	}
+	return b.String(), true
+}
+
+// commandTarget returns the session a tmux command line targets: the first
+// -t value with any ":window.pane" suffix stripped, or "" when there is none.
+func commandTarget(args []string) string {
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] == "-t" {
+			name, _, _ := strings.Cut(args[i+1], ":")
+			return name
+		}
+	}
+	return ""
+}
+
+// controlPipeFor picks a live pipe on socket to carry a command for target.
+// The target's own pipe is preferred; with avoidTarget (kill-session, which
+// ends the pipe's own client mid-command) any other pipe on the socket is
+// used instead.
+func (pm *PipeManager) controlPipeFor(socket, target string, avoidTarget bool) *ControlPipe {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	if !avoidTarget {
+		if p := pm.pipes[target]; p != nil && p.IsAlive() && p.socketName == socket {
+			return p
+		}
+	}
+	for name, p := range pm.pipes {
+		if name == target && avoidTarget {
+			continue
+		}
+		if p != nil && p.IsAlive() && p.socketName == socket {
+			return p
+		}
+	}
+	return nil
+}
+
+// runViaControlPipe runs args through a control pipe when control-mode-only
+// operation is on. handled is false when the caller must spawn the command
+// as a subprocess instead. It only uses pipes that are already attached and
+// never connects one itself: each pipe is a persistent `tmux -C` client, and
+// opening one for every background session a command touches would leave
+// those clients running. The PipeManager's own watcher replaces pipes that
+// die.
+func runViaControlPipe(socket string, args []string) (handled bool, err error) {
+	if !ControlModeOnly() || len(args) == 0 {
+		return false, nil
+	}
+	pm := GetPipeManager()
+	if pm == nil {
+		return false, nil
+	}
+	line, ok := quoteControlCommand(args)
+	if !ok {
+		pipeLog.Debug("control_only_fallback", slog.String("command", args[0]), slog.String("reason", "newline in argument"))
+		return false, nil
+	}
+	target := commandTarget(args)
+	avoidTarget := args[0] == "kill-session"
+
+	pipe := pm.controlPipeFor(socket, target, avoidTarget)
+	if pipe == nil {
+		pipeLog.Debug("control_only_fallback", slog.String("command", args[0]), slog.String("reason", "no pipe"))
+		return false, nil
+	}
+
+	if _, err := pipe.SendCommand(line); err != nil {
+		if commandNotWritten(err) {
+			pipeLog.Debug("control_only_fallback", slog.String("command", args[0]), slog.String("reason", err.Error()))
+			return false, nil
+		}
+		return true, err
+	}
+	return true, nil
+}
+
+// commandNotWritten reports whether a SendCommand error happened before the
+// command reached tmux, so running it as a subprocess cannot double it. A
+// timeout or a pipe that closed mid-command may already have taken effect.
+func commandNotWritten(err error) bool {
+	msg := err.Error()
+	return strings.HasPrefix(msg, "pipe not alive") || strings.HasPrefix(msg, "write to pipe")
+}
+
+// runTmux runs a tmux command that produces no output, through a control
+// pipe in control-mode-only operation and as a subprocess otherwise.
+func runTmux(socket string, args ...string) error {
+	if handled, err := runViaControlPipe(socket, args); handled {
+		return err
+	}
+	return tmuxExec(socket, args...).Run()
+}
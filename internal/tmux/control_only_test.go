@@ -0,0 +1,90 @@
+package tmux
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuoteControlCommand(t *testing.T) {
+	line, ok := quoteControlCommand([]string{"set-option", "-t", "s1", "status-left", "it's #{x} $HOME", ";", "set-option", "-gq", "@n", "3"})
+	require.True(t, ok)
+	assert.Equal(t, `'set-option' '-t' 's1' 'status-left' 'it'\''s #{x} $HOME' ; 'set-option' '-gq' '@n' '3'`, line)
+
+	_, ok = quoteControlCommand([]string{"send-keys", "-l", "-t", "s1", "--", "line one\nline two"})
+	assert.False(t, ok, "a newline cannot be carried on a control-mode command line")
+}
+
+func TestCommandTarget(t *testing.T) {
+	assert.Equal(t, "s1", commandTarget([]string{"respawn-pane", "-k", "-t", "s1:", "bash"}))
+	assert.Equal(t, "s1", commandTarget([]string{"send-keys", "-t", "s1:2.0", "Enter"}))
+	assert.Equal(t, "", commandTarget([]string{"set-option", "-g", "status-left-length", "120"}))
+}
+
+func TestRunViaControlPipe_DisabledOrNoManager(t *testing.T) {
+	SetControlModeOnly(false)
+	handled, err := runViaControlPipe("", []string{"set-option", "-g", "status-left-length", "120"})
+	assert.False(t, handled)
+	assert.NoError(t, err)
+
+	old := GetPipeManager()
+	SetPipeManager(nil)
+	SetControlModeOnly(true)
+	t.Cleanup(func() {
+		SetControlModeOnly(false)
+		SetPipeManager(old)
+	})
+	handled, _ = runViaControlPipe("", []string{"set-option", "-g", "status-left-length", "120"})
+	assert.False(t, handled, "without a PipeManager commands must fall back to a subprocess")
+}
+
+func TestRunViaControlPipe_RoutesThroughPipe(t *testing.T) {
+	skipIfNoTmuxBinary(t)
+	name := createTestSessionStrict(t, "ctl-only")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pm := NewPipeManager(ctx, nil)
+	defer pm.Close()
+
+	old := GetPipeManager()
+	SetPipeManager(pm)
+	SetControlModeOnly(true)
+	t.Cleanup(func() {
+		SetControlModeOnly(false)
+		SetPipeManager(old)
+	})
+
+	// No pipe attached: fall back to a subprocess without opening a
+	// persistent control client for the target.
+	handled, err := runViaControlPipe("", []string{"set-option", "-t", name, "@ctl_only", "x"})
+	assert.False(t, handled)
+	assert.NoError(t, err)
+	assert.False(t, pm.IsConnected(name), "a command must not connect a pipe")
+	assert.Zero(t, pm.ConnectedCount())
+
+	require.NoError(t, pm.Connect(name, ""))
+	handled, err = runViaControlPipe("", []string{"set-option", "-t", name, "@ctl_only", "it's on", ";", "set-option", "-t", name, "@ctl_two", "2"})
+	require.True(t, handled)
+	require.NoError(t, err)
+
+	out, err := exec.Command("tmux", "show-options", "-v", "-t", name, "@ctl_only").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "it's on", strings.TrimSpace(string(out)))
+	out, err = exec.Command("tmux", "show-options", "-v", "-t", name, "@ctl_two").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "2", strings.TrimSpace(string(out)))
+
+	// tmux errors are returned, not retried as a subprocess.
+	handled, err = runViaControlPipe("", []string{"set-option", "-t", name, "no-such-option", "x"})
+	assert.True(t, handled)
+	assert.Error(t, err)
+
+	// kill-session never rides the target's own pipe.
+	handled, _ = runViaControlPipe("", []string{"kill-session", "-t", name})
+	assert.False(t, handled)
+}
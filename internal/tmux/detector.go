@@ -84,6 +84,12 @@ func (d *PromptDetector) HasPrompt(content string) bool {
 	case "cursor":
 		return d.hasCursorPrompt(content)
 
+	case "aider":
+		return d.hasAiderPrompt(content)
+
+	case "goose":
+		return d.hasGoosePrompt(content)
+
 	default:
 		// Generic shell - check for common prompts
 		return d.hasShellPrompt(content)
@@ -502,6 +508,50 @@ func (d *PromptDetector) hasCursorPrompt(content string) bool {
 	return d.hasCodexPromptMarker(content)
 }
 
+// hasAiderPrompt detects Aider waiting for input: a (Y)es/(N)o confirmation
+// (add file, run command, create file) or the chat prompt on the last line.
+// The "Waiting for <model>" spinner line means aider is still working.
+func (d *PromptDetector) hasAiderPrompt(content string) bool {
+	recent := strings.Join(lastNLines(content, 5), "\n")
+	if strings.Contains(recent, "Waiting for ") || strings.Contains(recent, "Updating repo map") {
+		return false
+	}
+	last := lastNonEmptyLine(content)
+	if strings.Contains(last, "(Y)es/(N)o") {
+		return true
+	}
+	// The prompt may already hold a partly typed message.
+	for _, mode := range []string{"", "architect", "ask", "code", "context", "help", "multi"} {
+		if last == mode+">" || strings.HasPrefix(last, mode+"> ") {
+			return true
+		}
+	}
+	return false
+}
+
+// hasGoosePrompt detects goose waiting for input: its "( O)>" prompt on the
+// last line or a tool-call approval question. An earlier prompt line with the
+// submitted message stays on screen while goose works, so only the last line
+// counts.
+func (d *PromptDetector) hasGoosePrompt(content string) bool {
+	if strings.HasPrefix(lastNonEmptyLine(content), "( O)>") {
+		return true
+	}
+	return strings.Contains(strings.Join(lastNLines(content, 10), "\n"), "Goose would like to call")
+}
+
+// lastNonEmptyLine returns the last non-blank line of content, trimmed and
+// without ANSI codes.
+func lastNonEmptyLine(content string) string {
+	lines := strings.Split(content, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if t := strings.TrimSpace(StripANSI(lines[i])); t != "" {
+			return t
+		}
+	}
+	return ""
+}
+
 // hasGeminiPrompt detects if Gemini CLI is waiting for input.
 // Checks last 10 non-blank lines for known Gemini prompt patterns.
 func (d *PromptDetector) hasGeminiPrompt(content string) bool {
@@ -168,8 +168,12 @@ func (s *Session) KillAndWait() error {
 
 	_, oldPIDs := s.getPaneProcessTree()
 
-	cmd := execCommand("tmux", "kill-session", "-t", s.Name)
-	killErr := cmd.Run()
+	var killErr error
+	if handled, err := runViaControlPipe(s.SocketName, []string{"kill-session", "-t", s.Name}); handled {
+		killErr = err
+	} else {
+		killErr = execCommand("tmux", "kill-session", "-t", s.Name).Run()
+	}
 
 	if len(oldPIDs) > 0 {
 		EnsurePIDsDead(oldPIDs, 3*time.Second)
@@ -0,0 +1,552 @@
+package faketmux
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// invocation is one shim run: a locked server, a fixed clock reading and
+// the process's stdio.
+type invocation struct {
+	srv    *server
+	now    time.Time
+	stdin  io.Reader
+	stdout io.Writer
+}
+
+// cmdArgs is a parsed tmux command line: getopt-style flags (repeatable,
+// with values for flags that take one) and the remaining arguments.
+type cmdArgs struct {
+	flags map[byte][]string
+	args  []string
+}
+
+func (c cmdArgs) has(f byte) bool { _, ok := c.flags[f]; return ok }
+
+func (c cmdArgs) value(f byte) string {
+	if v := c.flags[f]; len(v) > 0 {
+		return v[len(v)-1]
+	}
+	return ""
+}
+
+// parseArgs parses argv with the flags in withValue taking an argument.
+func parseArgs(argv []string, withValue string) cmdArgs {
+	c := cmdArgs{flags: map[byte][]string{}}
+	for i := 0; i < len(argv); i++ {
+		a := argv[i]
+		if a == "--" {
+			c.args = append(c.args, argv[i+1:]...)
+			break
+		}
+		if len(a) < 2 || a[0] != '-' {
+			c.args = append(c.args, argv[i:]...)
+			break
+		}
+		for j := 1; j < len(a); j++ {
+			f := a[j]
+			if !strings.ContainsRune(withValue, rune(f)) {
+				c.flags[f] = append(c.flags[f], "")
+				continue
+			}
+			v := a[j+1:]
+			if v == "" && i+1 < len(argv) {
+				i++
+				v = argv[i]
+			}
+			c.flags[f] = append(c.flags[f], v)
+			break
+		}
+	}
+	return c
+}
+
+// commandAliases maps tmux's short command names to canonical ones.
+var commandAliases = map[string]string{
+	"new": "new-session", "has": "has-session", "ls": "list-sessions",
+	"lsw": "list-windows", "lsp": "list-panes", "lsc": "list-clients",
+	"send": "send-keys", "capturep": "capture-pane", "display": "display-message",
+	"set": "set-option", "setw": "set-option", "set-window-option": "set-option",
+	"show": "show-options", "show-option": "show-options", "showw": "show-options",
+	"show-window-options": "show-options", "setenv": "set-environment",
+	"showenv": "show-environment", "respawnp": "respawn-pane",
+	"respawn-window": "respawn-pane", "respawnw": "respawn-pane",
+	"rename": "rename-session", "attach": "attach-session", "a": "attach-session",
+	"switchc": "switch-client", "kill-ses": "kill-session",
+}
+
+// noopCommands change nothing the simulation models (key bindings, hooks,
+// layout, buffers, clients) and always succeed.
+var noopCommands = map[string]bool{
+	"bind-key": true, "bind": true, "unbind-key": true, "unbind": true,
+	"set-hook": true, "refresh-client": true, "resize-window": true,
+	"resize-pane": true, "select-window": true, "select-pane": true,
+	"source-file": true, "source": true, "run-shell": true, "run": true,
+	"pipe-pane": true, "start-server": true, "start": true,
+	"rename-window": true, "renamew": true, "set-buffer": true,
+	"load-buffer": true, "paste-buffer": true, "delete-buffer": true,
+	"wait-for": true, "detach-client": true, "detach": true,
+	"display-popup": true, "split-window": true, "new-window": true,
+	"kill-pane": true, "kill-window": true,
+}
+
+func (inv *invocation) run(argv []string) error {
+	name := argv[0]
+	if canonical, ok := commandAliases[name]; ok {
+		name = canonical
+	}
+	args := argv[1:]
+	for _, sess := range inv.srv.state.Sessions {
+		sess.advance(inv.now)
+	}
+	switch name {
+	case "new-session":
+		return inv.newSession(parseArgs(args, "cefnstxyFX"))
+	case "has-session":
+		_, err := inv.target(parseArgs(args, "t"))
+		return err
+	case "kill-session":
+		c := parseArgs(args, "t")
+		sess, err := inv.target(c)
+		if err != nil {
+			return err
+		}
+		inv.srv.remove(sess.Name)
+		return nil
+	case "kill-server":
+		inv.srv.state.Sessions = nil
+		return nil
+	case "list-sessions":
+		return inv.listSessions(parseArgs(args, "Ff"))
+	case "list-windows", "list-panes":
+		return inv.listPanes(name, parseArgs(args, "Fft"))
+	case "list-clients":
+		return nil
+	case "display-message":
+		return inv.displayMessage(parseArgs(args, "cdFt"))
+	case "capture-pane":
+		return inv.capturePane(parseArgs(args, "bESt"))
+	case "send-keys":
+		return inv.sendKeys(parseArgs(args, "Nt"))
+	case "set-option":
+		return inv.setOption(parseArgs(args, "t"))
+	case "show-options":
+		return inv.showOptions(parseArgs(args, "t"))
+	case "set-environment":
+		return inv.setEnvironment(parseArgs(args, "t"))
+	case "show-environment":
+		return inv.showEnvironment(parseArgs(args, "t"))
+	case "respawn-pane":
+		return inv.respawnPane(parseArgs(args, "cet"))
+	case "rename-session":
+		c := parseArgs(args, "t")
+		sess, err := inv.target(c)
+		if err != nil {
+			return err
+		}
+		if len(c.args) == 0 || inv.srv.find(c.args[0]) != nil {
+			return fmt.Errorf("duplicate session: %s", strings.Join(c.args, " "))
+		}
+		sess.Name = c.args[0]
+		return nil
+	case "clear-history":
+		sess, err := inv.target(parseArgs(args, "t"))
+		if err != nil {
+			return err
+		}
+		if n := len(sess.Lines) - paneHeight; n > 0 {
+			sess.Lines = sess.Lines[n:]
+		}
+		return nil
+	case "attach-session", "switch-client":
+		return fmt.Errorf("fake tmux: %s is not supported (no terminal clients are simulated)", name)
+	case "fake-output":
+		return inv.fakeOutput(parseArgs(args, "t"))
+	case "fake-busy":
+		return inv.fakeBusy(parseArgs(args, "t"))
+	case "fake-exit":
+		return inv.fakeExit(parseArgs(args, "t"))
+	}
+	if noopCommands[name] {
+		return nil
+	}
+	return fmt.Errorf("unknown command: %s", name)
+}
+
+// target resolves -t (or the most recent session when omitted). Session,
+// window and pane suffixes (":0", ".0") and the exact-match "=" prefix are
+// accepted.
+func (inv *invocation) target(c cmdArgs) (*fakeSession, error) {
+	sessions := inv.srv.state.Sessions
+	if len(sessions) == 0 {
+		return nil, inv.noServer()
+	}
+	t := strings.TrimPrefix(c.value('t'), "=")
+	if i := strings.IndexAny(t, ":."); i >= 0 {
+		t = t[:i]
+	}
+	if t == "" {
+		return sessions[len(sessions)-1], nil
+	}
+	for _, sess := range sessions {
+		if sess.Name == t || "$"+strconv.Itoa(sess.ID) == t {
+			return sess, nil
+		}
+	}
+	return nil, fmt.Errorf("can't find session: %s", t)
+}
+
+func (inv *invocation) noServer() error {
+	return fmt.Errorf("no server running on %s", inv.srv.path)
+}
+
+func (inv *invocation) newSession(c cmdArgs) error {
+	name := c.value('s')
+	if c.has('A') && name != "" && inv.srv.find(name) != nil {
+		return nil
+	}
+	if name == "" {
+		name = strconv.Itoa(inv.srv.state.NextID)
+	}
+	if inv.srv.find(name) != nil {
+		return fmt.Errorf("duplicate session: %s", name)
+	}
+	dir := c.value('c')
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+	sess := &fakeSession{
+		Name:    name,
+		ID:      inv.srv.state.NextID,
+		WorkDir: dir,
+		Command: strings.Join(c.args, " "),
+		Window:  c.value('n'),
+		Created: inv.now,
+	}
+	inv.srv.state.NextID++
+	sess.Tool = detectTool(sess.Command)
+	for _, kv := range c.flags['e'] {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			setMap(&sess.Env, k, v)
+		}
+	}
+	sess.reset(inv.now)
+	inv.srv.state.Sessions = append(inv.srv.state.Sessions, sess)
+	if c.has('P') {
+		format := c.value('F')
+		if format == "" {
+			format = "#{session_name}:"
+		}
+		fmt.Fprintln(inv.stdout, expand(format, formatVars(sess, inv.now)))
+	}
+	return nil
+}
+
+func (inv *invocation) listSessions(c cmdArgs) error {
+	if len(inv.srv.state.Sessions) == 0 {
+		return inv.noServer()
+	}
+	for _, sess := range inv.srv.state.Sessions {
+		if format := c.value('F'); format != "" {
+			fmt.Fprintln(inv.stdout, expand(format, formatVars(sess, inv.now)))
+			continue
+		}
+		fmt.Fprintf(inv.stdout, "%s: 1 windows (created %s)\n", sess.Name, sess.Created.Format(time.ANSIC))
+	}
+	return nil
+}
+
+// listPanes serves list-windows and list-panes: each session has exactly
+// one window with one pane.
+func (inv *invocation) listPanes(cmd string, c cmdArgs) error {
+	var sessions []*fakeSession
+	if c.has('a') {
+		if len(inv.srv.state.Sessions) == 0 {
+			return inv.noServer()
+		}
+		sessions = inv.srv.state.Sessions
+	} else {
+		sess, err := inv.target(c)
+		if err != nil {
+			return err
+		}
+		sessions = []*fakeSession{sess}
+	}
+	for _, sess := range sessions {
+		vars := formatVars(sess, inv.now)
+		format := c.value('F')
+		switch {
+		case format != "":
+		case cmd == "list-windows":
+			format = "#{window_index}: #{window_name}* (1 panes) [#{window_width}x#{window_height}]"
+		default:
+			format = "#{pane_index}: [#{pane_width}x#{pane_height}] #{pane_id} (active)"
+		}
+		fmt.Fprintln(inv.stdout, expand(format, vars))
+	}
+	return nil
+}
+
+func (inv *invocation) displayMessage(c cmdArgs) error {
+	if !c.has('p') {
+		return nil
+	}
+	format := c.value('F')
+	if format == "" {
+		format = strings.Join(c.args, " ")
+	}
+	sess, err := inv.target(c)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(inv.stdout, expand(format, formatVars(sess, inv.now)))
+	return nil
+}
+
+func (inv *invocation) capturePane(c cmdArgs) error {
+	sess, err := inv.target(c)
+	if err != nil {
+		return err
+	}
+	if !c.has('p') {
+		return nil
+	}
+	screen := sess.screen(inv.now)
+	start := max(len(screen)-paneHeight, 0)
+	if v := c.value('S'); v == "-" {
+		start = 0
+	} else if n, err := strconv.Atoi(v); err == nil && n < 0 {
+		start = max(start+n, 0)
+	}
+	for _, line := range screen[start:] {
+		fmt.Fprintln(inv.stdout, line)
+	}
+	return nil
+}
+
+func (inv *invocation) sendKeys(c cmdArgs) error {
+	sess, err := inv.target(c)
+	if err != nil {
+		return err
+	}
+	if c.has('X') || c.has('R') {
+		return nil
+	}
+	for _, key := range c.args {
+		if c.has('l') {
+			sess.typeText(inv.now, key)
+			continue
+		}
+		sess.sendKey(inv.now, key)
+	}
+	return nil
+}
+
+// optionScope returns the option map set-option/show-options act on.
+func (inv *invocation) optionScope(c cmdArgs) (*map[string]string, error) {
+	if c.has('g') {
+		return &inv.srv.state.Options, nil
+	}
+	sess, err := inv.target(c)
+	if err != nil {
+		return nil, err
+	}
+	return &sess.Options, nil
+}
+
+func (inv *invocation) setOption(c cmdArgs) error {
+	if len(c.args) == 0 {
+		return fmt.Errorf("set-option: missing option name")
+	}
+	scope, err := inv.optionScope(c)
+	if err != nil {
+		if c.has('q') {
+			return nil
+		}
+		return err
+	}
+	key := c.args[0]
+	value := "on"
+	if len(c.args) > 1 {
+		value = strings.Join(c.args[1:], " ")
+	}
+	switch {
+	case c.has('u'):
+		delete(*scope, key)
+	case c.has('o') && (*scope)[key] != "":
+	case c.has('a'):
+		setMap(scope, key, (*scope)[key]+value)
+	default:
+		setMap(scope, key, value)
+	}
+	return nil
+}
+
+func (inv *invocation) showOptions(c cmdArgs) error {
+	scope, err := inv.optionScope(c)
+	if err != nil {
+		return err
+	}
+	if len(c.args) == 0 {
+		for _, k := range sortedKeys(*scope) {
+			fmt.Fprintf(inv.stdout, "%s %s\n", k, (*scope)[k])
+		}
+		return nil
+	}
+	key := c.args[0]
+	v, ok := (*scope)[key]
+	switch {
+	case !ok && c.has('q'):
+	case !ok:
+		return fmt.Errorf("invalid option: %s", key)
+	case c.has('v'):
+		fmt.Fprintln(inv.stdout, v)
+	default:
+		fmt.Fprintf(inv.stdout, "%s %s\n", key, v)
+	}
+	return nil
+}
+
+// envScope returns the environment set-environment/show-environment act on.
+func (inv *invocation) envScope(c cmdArgs) (*map[string]string, error) {
+	if c.has('g') {
+		return &inv.srv.state.Env, nil
+	}
+	sess, err := inv.target(c)
+	if err != nil {
+		return nil, err
+	}
+	return &sess.Env, nil
+}
+
+func (inv *invocation) setEnvironment(c cmdArgs) error {
+	if len(c.args) == 0 {
+		return fmt.Errorf("set-environment: missing variable name")
+	}
+	scope, err := inv.envScope(c)
+	if err != nil {
+		return err
+	}
+	if c.has('u') || c.has('r') {
+		delete(*scope, c.args[0])
+		return nil
+	}
+	value := ""
+	if len(c.args) > 1 {
+		value = c.args[1]
+	}
+	setMap(scope, c.args[0], value)
+	return nil
+}
+
+func (inv *invocation) showEnvironment(c cmdArgs) error {
+	scope, err := inv.envScope(c)
+	if err != nil {
+		return err
+	}
+	if len(c.args) == 0 {
+		for _, k := range sortedKeys(*scope) {
+			fmt.Fprintf(inv.stdout, "%s=%s\n", k, (*scope)[k])
+		}
+		return nil
+	}
+	v, ok := (*scope)[c.args[0]]
+	if !ok {
+		return fmt.Errorf("unknown variable: %s", c.args[0])
+	}
+	fmt.Fprintf(inv.stdout, "%s=%s\n", c.args[0], v)
+	return nil
+}
+
+func (inv *invocation) respawnPane(c cmdArgs) error {
+	sess, err := inv.target(c)
+	if err != nil {
+		return err
+	}
+	if !sess.Dead && !c.has('k') {
+		return fmt.Errorf("respawn pane failed: pane %%%d still active", sess.ID)
+	}
+	if len(c.args) > 0 {
+		sess.Command = strings.Join(c.args, " ")
+		sess.Tool = detectTool(sess.Command)
+	}
+	if dir := c.value('c'); dir != "" {
+		sess.WorkDir = dir
+	}
+	sess.reset(inv.now)
+	return nil
+}
+
+// fakeOutput appends stdin to the pane, as if its program printed it.
+func (inv *invocation) fakeOutput(c cmdArgs) error {
+	sess, err := inv.target(c)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(inv.stdin)
+	if err != nil {
+		return err
+	}
+	text := strings.TrimSuffix(string(data), "\n")
+	sess.Lines = append(sess.Lines, strings.Split(text, "\n")...)
+	sess.Activity = inv.now
+	return nil
+}
+
+// fakeBusy shows the busy indicator for the given duration.
+func (inv *invocation) fakeBusy(c cmdArgs) error {
+	sess, err := inv.target(c)
+	if err != nil {
+		return err
+	}
+	if len(c.args) == 0 {
+		return fmt.Errorf("fake-busy: missing duration")
+	}
+	d, err := time.ParseDuration(c.args[0])
+	if err != nil || d <= 0 {
+		return fmt.Errorf("fake-busy: invalid duration %q", c.args[0])
+	}
+	sess.startBusy(inv.now, d)
+	return nil
+}
+
+// fakeExit marks the pane's process as exited, like a crashed agent under
+// remain-on-exit.
+func (inv *invocation) fakeExit(c cmdArgs) error {
+	sess, err := inv.target(c)
+	if err != nil {
+		return err
+	}
+	code := 0
+	if len(c.args) > 0 {
+		if code, err = strconv.Atoi(c.args[0]); err != nil {
+			return fmt.Errorf("fake-exit: invalid status %q", c.args[0])
+		}
+	}
+	sess.Dead = true
+	sess.BusySince, sess.BusyUntil, sess.Reply, sess.Input = time.Time{}, time.Time{}, nil, ""
+	sess.Lines = append(sess.Lines, "", fmt.Sprintf("Pane is dead (status %d, %s)", code, inv.now.Format(time.ANSIC)))
+	sess.Activity = inv.now
+	return nil
+}
+
+func setMap(m *map[string]string, k, v string) {
+	if *m == nil {
+		*m = map[string]string{}
+	}
+	(*m)[k] = v
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
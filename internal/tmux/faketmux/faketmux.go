@@ -0,0 +1,225 @@
+// Package faketmux is a simulated tmux server for integration tests and CI.
+//
+// When AGENTDECK_FAKE_TMUX names a state directory, agent-deck links a `tmux`
+// shim to its own binary under <dir>/bin and prepends that directory to PATH
+// (Install). Every tmux subprocess agent-deck spawns — and every one spawned
+// by scripts running under it — then re-enters agent-deck as the shim, which
+// answers from JSON state in the directory instead of talking to a real tmux
+// server (Main).
+//
+// The simulation covers what agent-deck's CLI paths rely on: sessions and
+// their options and environment, format strings, capture-pane and send-keys.
+// Panes render a Claude-style prompt; a message submitted with Enter puts the
+// pane in a "busy" state (spinner plus "ctrl+c to interrupt") for
+// AGENTDECK_FAKE_TMUX_BUSY (default 2s) and then prints a canned reply, so
+// status detection walks running → waiting exactly as with a live agent. All
+// timing is derived from stored timestamps, so no background process is
+// needed and every invocation sees the same pane.
+//
+// Scripts can drive a pane directly through shim-only commands:
+//
+//	tmux fake-output -t <session>        # append stdin to the pane
+//	tmux fake-busy -t <session> 30s      # show the busy indicator for 30s
+//	tmux fake-exit -t <session> [code]   # mark the pane's process as exited
+//
+// Control mode (-C) and attach are not simulated; callers that use them fall
+// back exactly as they do when tmux is unavailable.
+package faketmux
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// EnvDir enables the fake backend and names its state directory.
+	EnvDir = "AGENTDECK_FAKE_TMUX"
+	// EnvBusy overrides how long a simulated agent turn stays busy
+	// (a time.ParseDuration string; "0" completes turns instantly).
+	EnvBusy = "AGENTDECK_FAKE_TMUX_BUSY"
+
+	defaultBusy = 2 * time.Second
+	shimName    = "tmux"
+)
+
+// Dir returns the fake backend's state directory, or "" when it is disabled.
+func Dir() string {
+	dir := strings.TrimSpace(os.Getenv(EnvDir))
+	if dir == "" {
+		return ""
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		return abs
+	}
+	return dir
+}
+
+// Active reports whether the fake backend is enabled for this process.
+func Active() bool {
+	return Dir() != ""
+}
+
+// IsShim reports whether argv0 is an invocation of the tmux shim, i.e. this
+// binary should act as the fake tmux server rather than as agent-deck.
+func IsShim(argv0 string) bool {
+	return Active() && filepath.Base(argv0) == shimName
+}
+
+// Install links <dir>/bin/tmux to the running executable and puts that
+// directory first on PATH, so bare `tmux` invocations reach the shim. It is a
+// no-op when the fake backend is disabled and idempotent otherwise.
+func Install() error {
+	dir := Dir()
+	if dir == "" {
+		return nil
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locate executable: %w", err)
+	}
+	return installShim(dir, exe)
+}
+
+func installShim(dir, exe string) error {
+	bin := filepath.Join(dir, "bin")
+	if err := os.MkdirAll(bin, 0o755); err != nil {
+		return fmt.Errorf("create %s: %w", bin, err)
+	}
+	link := filepath.Join(bin, shimName)
+	if cur, err := os.Readlink(link); err != nil || cur != exe {
+		_ = os.Remove(link)
+		// A concurrent agent-deck may have won the race; its link points at
+		// the same kind of binary, so EEXIST is fine.
+		if err := os.Symlink(exe, link); err != nil && !errors.Is(err, fs.ErrExist) {
+			return fmt.Errorf("link tmux shim: %w", err)
+		}
+	}
+	path := os.Getenv("PATH")
+	if first, _, _ := strings.Cut(path, string(os.PathListSeparator)); first != bin {
+		_ = os.Setenv("PATH", bin+string(os.PathListSeparator)+path)
+	}
+	return nil
+}
+
+// busyDuration is the length of one simulated agent turn.
+func busyDuration() time.Duration {
+	v := strings.TrimSpace(os.Getenv(EnvBusy))
+	if v == "" {
+		return defaultBusy
+	}
+	if v == "0" {
+		return 0
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return defaultBusy
+	}
+	return d
+}
+
+// Main runs one shim invocation with tmux's argv (without the program name)
+// and returns the process exit code.
+func Main(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	dir := Dir()
+	if dir == "" {
+		fmt.Fprintf(stderr, "fake tmux: %s is not set\n", EnvDir)
+		return 1
+	}
+	socket := "default"
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		flag := args[0]
+		args = args[1:]
+		switch flag {
+		case "-V":
+			fmt.Fprintln(stdout, "tmux 3.4")
+			return 0
+		case "-L", "-S":
+			if len(args) == 0 {
+				fmt.Fprintf(stderr, "fake tmux: %s requires an argument\n", flag)
+				return 1
+			}
+			socket = socketKey(args[0])
+			args = args[1:]
+		case "-f":
+			if len(args) > 0 {
+				args = args[1:]
+			}
+		case "-C", "-CC":
+			fmt.Fprintln(stderr, "fake tmux: control mode is not supported")
+			return 1
+		}
+	}
+	if len(args) == 0 {
+		args = []string{"new-session"}
+	}
+
+	srv, err := openServer(dir, socket)
+	if err != nil {
+		fmt.Fprintf(stderr, "fake tmux: %v\n", err)
+		return 1
+	}
+	defer srv.close()
+
+	ctx := &invocation{srv: srv, now: now(), stdin: stdin, stdout: stdout}
+	code := 0
+	for _, cmd := range splitCommands(args) {
+		if err := ctx.run(cmd); err != nil {
+			fmt.Fprintln(stderr, err)
+			code = 1
+			break
+		}
+	}
+	if err := srv.save(); err != nil {
+		fmt.Fprintf(stderr, "fake tmux: %v\n", err)
+		return 1
+	}
+	return code
+}
+
+// now is the shim's clock; tests replace it.
+var now = time.Now
+
+// socketKey maps a -L name or -S path to the file name of its state.
+func socketKey(v string) string {
+	v = filepath.Base(strings.TrimSpace(v))
+	var b strings.Builder
+	for _, r := range v {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	if b.Len() == 0 || v == "." {
+		return "default"
+	}
+	return b.String()
+}
+
+// splitCommands splits argv on standalone ";" tokens, tmux's command
+// separator.
+func splitCommands(args []string) [][]string {
+	var cmds [][]string
+	var cur []string
+	for _, a := range args {
+		if a == ";" || a == `\;` {
+			if len(cur) > 0 {
+				cmds = append(cmds, cur)
+			}
+			cur = nil
+			continue
+		}
+		cur = append(cur, a)
+	}
+	if len(cur) > 0 {
+		cmds = append(cmds, cur)
+	}
+	return cmds
+}
@@ -0,0 +1,194 @@
+package faketmux
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// shim runs one fake tmux invocation against a fresh state dir per test.
+type shim struct {
+	t     *testing.T
+	clock time.Time
+}
+
+func newShim(t *testing.T) *shim {
+	t.Helper()
+	t.Setenv(EnvDir, t.TempDir())
+	t.Setenv(EnvBusy, "5s")
+	s := &shim{t: t, clock: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	prev := now
+	now = func() time.Time { return s.clock }
+	t.Cleanup(func() { now = prev })
+	return s
+}
+
+func (s *shim) run(stdin string, args ...string) (string, string, int) {
+	var out, errOut bytes.Buffer
+	code := Main(args, strings.NewReader(stdin), &out, &errOut)
+	return out.String(), errOut.String(), code
+}
+
+func (s *shim) ok(args ...string) string {
+	s.t.Helper()
+	out, errOut, code := s.run("", args...)
+	if code != 0 {
+		s.t.Fatalf("tmux %v: exit %d: %s", args, code, errOut)
+	}
+	return out
+}
+
+func TestSessionLifecycleAndFormats(t *testing.T) {
+	s := newShim(t)
+	if _, _, code := s.run("", "has-session", "-t", "a"); code == 0 {
+		t.Fatal("has-session on an empty server must fail")
+	}
+	s.ok("new-session", "-d", "-s", "a", "-c", "/work", "-e", "K=V", "bash", "-c", "claude --resume")
+	s.ok("-L", "other", "new-session", "-d", "-s", "b")
+
+	if _, errOut, code := s.run("", "new-session", "-d", "-s", "a"); code == 0 || !strings.Contains(errOut, "duplicate session") {
+		t.Fatalf("duplicate new-session = %d %q", code, errOut)
+	}
+	s.ok("has-session", "-t", "=a")
+	if got := s.ok("list-sessions", "-F", "#{session_name}"); got != "a\n" {
+		t.Fatalf("sockets must be isolated, default server lists %q", got)
+	}
+	got := s.ok("list-windows", "-a", "-F", "#{session_name}|#{window_activity}|#{window_index}|#{window_name}")
+	if want := "a|" + itoa(s.clock.Unix()) + "|0|claude\n"; got != want {
+		t.Fatalf("list-windows = %q, want %q", got, want)
+	}
+	if got := s.ok("display-message", "-t", "a:0.0", "-p", "#{pane_current_path} #{pane_dead} #S #{?pane_dead,dead,alive}"); got != "/work 0 a alive\n" {
+		t.Fatalf("display-message = %q", got)
+	}
+	if got := s.ok("list-panes", "-t", "a:", "-F", "#{pane_pid}"); got != "\n" {
+		t.Fatalf("pane_pid must stay empty so callers never signal a real process, got %q", got)
+	}
+	if got := s.ok("show-environment", "-t", "a", "K"); got != "K=V\n" {
+		t.Fatalf("show-environment = %q", got)
+	}
+
+	// Chained commands and user options.
+	s.ok("set-option", "-t", "a", "@agentdeck_display_name", "Demo", ";", "set-option", "-t", "a", "-q", "mouse", "on")
+	if got := s.ok("display-message", "-t", "a", "-p", "#{@agentdeck_display_name}"); got != "Demo\n" {
+		t.Fatalf("user option = %q", got)
+	}
+
+	s.ok("kill-session", "-t", "a")
+	if _, _, code := s.run("", "list-sessions"); code == 0 {
+		t.Fatal("list-sessions after the last kill must report no server")
+	}
+}
+
+func TestAgentTurnTiming(t *testing.T) {
+	s := newShim(t)
+	s.ok("new-session", "-d", "-s", "a", "claude")
+	start := s.clock
+
+	s.ok("send-keys", "-l", "-t", "a", "--", "fix the bug")
+	if pane := s.ok("capture-pane", "-t", "a", "-p"); !strings.Contains(pane, "❯ fix the bug") {
+		t.Fatalf("typed text must sit in the composer:\n%s", pane)
+	}
+	s.ok("send-keys", "-t", "a", "Enter")
+
+	s.clock = start.Add(2 * time.Second)
+	pane := s.ok("capture-pane", "-t", "a", "-p", "-e")
+	if !strings.Contains(pane, "ctrl+c to interrupt") || strings.Contains(pane, "Fake reply") {
+		t.Fatalf("pane must be busy mid-turn:\n%s", pane)
+	}
+	if got := s.ok("display-message", "-t", "a", "-p", "#{window_activity}"); got != itoa(s.clock.Unix())+"\n" {
+		t.Fatalf("busy pane activity = %q, want now", got)
+	}
+
+	s.clock = start.Add(10 * time.Second)
+	pane = s.ok("capture-pane", "-t", "a", "-p")
+	if strings.Contains(pane, "interrupt") || !strings.Contains(pane, "● Fake reply to: fix the bug") {
+		t.Fatalf("turn must complete after the busy period:\n%s", pane)
+	}
+	if !strings.HasSuffix(strings.TrimSpace(pane), strings.Repeat("─", ruleWidth)) || !strings.Contains(pane, "❯ \n") {
+		t.Fatalf("idle pane must end on an empty prompt:\n%s", pane)
+	}
+	if got := s.ok("display-message", "-t", "a", "-p", "#{window_activity}"); got != itoa(start.Add(5*time.Second).Unix())+"\n" {
+		t.Fatalf("activity must stop when the turn ends, got %q", got)
+	}
+}
+
+func TestInterruptAndScriptedPanes(t *testing.T) {
+	s := newShim(t)
+	s.ok("new-session", "-d", "-s", "a", "claude")
+	s.ok("send-keys", "-t", "a", "go", "Enter")
+	s.ok("send-keys", "-t", "a", "C-c")
+	if pane := s.ok("capture-pane", "-t", "a", "-p"); !strings.Contains(pane, "Interrupted") || strings.Contains(pane, "interrupt)") {
+		t.Fatalf("C-c must cancel the turn:\n%s", pane)
+	}
+
+	if _, errOut, code := s.run("Do you want to proceed?\n❯ 1. Yes\n", "fake-output", "-t", "a"); code != 0 {
+		t.Fatalf("fake-output: %s", errOut)
+	}
+	if pane := s.ok("capture-pane", "-t", "a", "-p"); !strings.Contains(pane, "Do you want to proceed?") {
+		t.Fatalf("fake-output text missing:\n%s", pane)
+	}
+
+	s.ok("fake-busy", "-t", "a", "1m")
+	if pane := s.ok("capture-pane", "-t", "a", "-p"); !strings.Contains(pane, "ctrl+c to interrupt") {
+		t.Fatalf("fake-busy must show the busy indicator:\n%s", pane)
+	}
+
+	s.ok("fake-exit", "-t", "a", "2")
+	if got := s.ok("list-panes", "-t", "a", "-F", "#{pane_dead}|#{pane_current_command}"); got != "1|\n" {
+		t.Fatalf("dead pane = %q", got)
+	}
+	if _, _, code := s.run("", "respawn-pane", "-t", "a"); code != 0 {
+		t.Fatal("respawn-pane must revive a dead pane without -k")
+	}
+	if got := s.ok("list-panes", "-t", "a", "-F", "#{pane_dead}"); got != "0\n" {
+		t.Fatalf("respawned pane dead = %q", got)
+	}
+}
+
+func TestUnsupportedModes(t *testing.T) {
+	s := newShim(t)
+	if got := s.ok("-V"); got != "tmux 3.4\n" {
+		t.Fatalf("-V = %q", got)
+	}
+	if _, _, code := s.run("", "-C", "attach-session", "-t", "a"); code == 0 {
+		t.Fatal("control mode must fail so callers fall back")
+	}
+	s.ok("new-session", "-d", "-s", "a")
+	if _, _, code := s.run("", "attach-session", "-t", "a"); code == 0 {
+		t.Fatal("attach must fail without a terminal client")
+	}
+	if _, _, code := s.run("", "bogus-command"); code == 0 {
+		t.Fatal("unknown commands must fail")
+	}
+}
+
+func TestInstallShim(t *testing.T) {
+	dir := t.TempDir()
+	exe := filepath.Join(dir, "agent-deck")
+	if err := os.WriteFile(exe, nil, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(EnvDir, dir)
+	t.Setenv("PATH", "/usr/bin")
+	for range 2 {
+		if err := installShim(dir, exe); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bin := filepath.Join(dir, "bin")
+	if target, err := os.Readlink(filepath.Join(bin, "tmux")); err != nil || target != exe {
+		t.Fatalf("shim link = %q, %v", target, err)
+	}
+	if got := os.Getenv("PATH"); got != bin+string(os.PathListSeparator)+"/usr/bin" {
+		t.Fatalf("PATH = %q (the shim dir must be prepended exactly once)", got)
+	}
+	if !IsShim(filepath.Join(bin, "tmux")) || IsShim(exe) {
+		t.Fatal("IsShim must match only the tmux shim name")
+	}
+}
+
+func itoa(n int64) string { return strconv.FormatInt(n, 10) }
@@ -0,0 +1,155 @@
+package faketmux
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// formatVars returns the tmux format variables of sess's single
+// window/pane at time t. Variables tmux would report for real processes
+// (pane_pid, client_pid) are left empty on purpose: callers parse them as
+// PIDs and must never signal an unrelated process.
+func formatVars(sess *fakeSession, t time.Time) map[string]string {
+	dead := "0"
+	if sess.Dead {
+		dead = "1"
+	}
+	vars := map[string]string{
+		"session_name":         sess.Name,
+		"session_id":           "$" + strconv.Itoa(sess.ID),
+		"session_created":      strconv.FormatInt(sess.Created.Unix(), 10),
+		"session_activity":     strconv.FormatInt(sess.activityAt(t).Unix(), 10),
+		"session_attached":     "0",
+		"session_windows":      "1",
+		"window_index":         "0",
+		"window_id":            "@" + strconv.Itoa(sess.ID),
+		"window_name":          sess.windowName(),
+		"window_activity":      strconv.FormatInt(sess.activityAt(t).Unix(), 10),
+		"window_width":         strconv.Itoa(paneWidth),
+		"window_height":        strconv.Itoa(paneHeight),
+		"window_panes":         "1",
+		"pane_index":           "0",
+		"pane_id":              "%" + strconv.Itoa(sess.ID),
+		"pane_active":          "1",
+		"pane_dead":            dead,
+		"pane_width":           strconv.Itoa(paneWidth),
+		"pane_height":          strconv.Itoa(paneHeight),
+		"pane_current_path":    sess.WorkDir,
+		"pane_current_command": sess.currentCommand(),
+		"pane_start_command":   sess.Command,
+		"pane_title":           sess.Name,
+		"pane_pid":             "",
+		"alternate_on":         "0",
+		"history_size":         strconv.Itoa(max(len(sess.Lines)-paneHeight, 0)),
+	}
+	for k, v := range sess.Options {
+		if strings.HasPrefix(k, "@") {
+			vars[k] = v
+		}
+	}
+	return vars
+}
+
+func (p *fakeSession) windowName() string {
+	if p.Window != "" {
+		return p.Window
+	}
+	return p.currentCommand()
+}
+
+// shortFormats are tmux's single-letter format aliases.
+var shortFormats = map[byte]string{
+	'S': "session_name",
+	'I': "window_index",
+	'W': "window_name",
+	'P': "pane_index",
+	'D': "pane_id",
+	'T': "pane_title",
+}
+
+// expand substitutes #{name}, #{?cond,then,else}, the #X short aliases and ##
+// in format. Unknown variables expand to "".
+func expand(format string, vars map[string]string) string {
+	var b strings.Builder
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		if c != '#' || i+1 >= len(format) {
+			b.WriteByte(c)
+			continue
+		}
+		next := format[i+1]
+		switch {
+		case next == '#':
+			b.WriteByte('#')
+			i++
+		case next == '{':
+			end := closingBrace(format, i+2)
+			if end < 0 {
+				b.WriteString(format[i:])
+				return b.String()
+			}
+			b.WriteString(expandBlock(format[i+2:end], vars))
+			i = end
+		case shortFormats[next] != "":
+			b.WriteString(vars[shortFormats[next]])
+			i++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// closingBrace returns the index of the } matching an opening brace just
+// before start, honouring nested #{...} blocks.
+func closingBrace(s string, start int) int {
+	depth := 1
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func expandBlock(body string, vars map[string]string) string {
+	if !strings.HasPrefix(body, "?") {
+		return vars[body]
+	}
+	parts := splitTopLevel(body[1:])
+	if len(parts) != 3 {
+		return ""
+	}
+	cond := expand("#{"+parts[0]+"}", vars)
+	if cond != "" && cond != "0" {
+		return expand(parts[1], vars)
+	}
+	return expand(parts[2], vars)
+}
+
+// splitTopLevel splits s on commas outside nested #{...} blocks.
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
@@ -0,0 +1,198 @@
+package faketmux
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	paneWidth  = 200
+	paneHeight = 50
+	ruleWidth  = 80
+
+	toolShell = "shell"
+)
+
+// agentTools are the command names rendered with the agent composer UI;
+// anything else is a plain shell.
+var agentTools = []string{"claude", "codex", "gemini", "opencode", "copilot", "crush", "hermes"}
+
+// detectTool guesses which program a pane runs from its start command.
+func detectTool(command string) string {
+	lower := strings.ToLower(command)
+	for _, tool := range agentTools {
+		if strings.Contains(lower, tool) {
+			return tool
+		}
+	}
+	return toolShell
+}
+
+func (p *fakeSession) isAgent() bool { return p.Tool != toolShell }
+
+// reset puts the pane back to a freshly started process.
+func (p *fakeSession) reset(t time.Time) {
+	p.Lines = nil
+	p.Input = ""
+	p.BusySince = time.Time{}
+	p.BusyUntil = time.Time{}
+	p.Reply = nil
+	p.Dead = false
+	p.Activity = t
+	if p.isAgent() {
+		p.Lines = []string{
+			fmt.Sprintf("✻ Welcome to %s (agent-deck fake tmux)", p.Tool),
+			"",
+			"  cwd: " + p.WorkDir,
+			"",
+		}
+	}
+}
+
+// advance completes a simulated turn whose busy period has passed.
+func (p *fakeSession) advance(t time.Time) {
+	if p.BusyUntil.IsZero() || t.Before(p.BusyUntil) {
+		return
+	}
+	p.Lines = append(p.Lines, p.Reply...)
+	p.Reply = nil
+	p.Activity = p.BusyUntil
+	p.BusySince = time.Time{}
+	p.BusyUntil = time.Time{}
+}
+
+func (p *fakeSession) busy() bool { return !p.BusyUntil.IsZero() }
+
+// activityAt is #{window_activity}: a busy pane redraws its spinner
+// continuously, so it is always "just active".
+func (p *fakeSession) activityAt(t time.Time) time.Time {
+	if p.busy() {
+		return t
+	}
+	return p.Activity
+}
+
+// screen returns the pane's full buffer — history plus the live composer.
+func (p *fakeSession) screen(t time.Time) []string {
+	out := append([]string(nil), p.Lines...)
+	switch {
+	case p.Dead:
+	case p.isAgent():
+		if p.busy() {
+			elapsed := int(t.Sub(p.BusySince).Seconds())
+			out = append(out, fmt.Sprintf("✢ Working… (%ds · ctrl+c to interrupt)", max(elapsed, 0)), "")
+		}
+		rule := strings.Repeat("─", ruleWidth)
+		out = append(out, rule, "❯ "+p.Input, rule)
+	default:
+		out = append(out, "$ "+p.Input)
+	}
+	return out
+}
+
+// currentCommand is #{pane_current_command}.
+func (p *fakeSession) currentCommand() string {
+	if p.Dead {
+		return ""
+	}
+	if p.isAgent() {
+		return p.Tool
+	}
+	return "bash"
+}
+
+func (p *fakeSession) typeText(t time.Time, text string) {
+	if p.Dead || text == "" {
+		return
+	}
+	p.Input += text
+	p.Activity = t
+}
+
+// submit sends the composer contents: an agent pane turns busy and replies
+// after the configured turn length; a shell just echoes the command.
+func (p *fakeSession) submit(t time.Time, turn time.Duration) {
+	if p.Dead {
+		return
+	}
+	text := p.Input
+	p.Input = ""
+	p.Activity = t
+	if !p.isAgent() {
+		p.Lines = append(p.Lines, "$ "+text)
+		return
+	}
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+	p.Lines = append(p.Lines, "❯ "+text, "")
+	p.Reply = append(p.Reply, "● Fake reply to: "+text, "")
+	switch {
+	case turn <= 0:
+		p.BusyUntil = t
+		p.advance(t)
+	case p.busy():
+		// Queued behind the running turn, like a message typed mid-turn.
+		p.BusyUntil = p.BusyUntil.Add(turn)
+	default:
+		p.startBusy(t, turn)
+	}
+}
+
+// startBusy begins a turn of length d at t.
+func (p *fakeSession) startBusy(t time.Time, d time.Duration) {
+	p.BusySince = t
+	p.BusyUntil = t.Add(d)
+	p.Activity = t
+}
+
+// interrupt is C-c: it cancels a running turn, or clears the composer.
+func (p *fakeSession) interrupt(t time.Time) {
+	if p.busy() {
+		p.BusySince = time.Time{}
+		p.BusyUntil = time.Time{}
+		p.Reply = nil
+		p.Lines = append(p.Lines, "  ⎿  Interrupted by user", "")
+		p.Activity = t
+		return
+	}
+	p.Input = ""
+}
+
+// ignoredKeys are key names that move the cursor or toggle UI state; they
+// never change the simulated pane.
+var ignoredKeys = map[string]bool{
+	"Escape": true, "Up": true, "Down": true, "Left": true, "Right": true,
+	"Tab": true, "BTab": true, "Home": true, "End": true, "PageUp": true,
+	"PageDown": true, "PPage": true, "NPage": true, "DC": true, "IC": true,
+}
+
+// sendKey applies one non-literal send-keys argument. Unknown strings are
+// typed literally, as tmux does.
+func (p *fakeSession) sendKey(t time.Time, key string) {
+	switch {
+	case key == "Enter" || key == "C-m" || key == "KPEnter":
+		p.submit(t, busyDuration())
+	case key == "C-c":
+		p.interrupt(t)
+	case key == "C-u":
+		p.Input = ""
+	case key == "BSpace" || key == "C-h":
+		if p.Input != "" {
+			_, size := utf8.DecodeLastRuneInString(p.Input)
+			p.Input = p.Input[:len(p.Input)-size]
+		}
+	case key == "Space":
+		p.typeText(t, " ")
+	case ignoredKeys[key], isModifiedKey(key):
+	default:
+		p.typeText(t, key)
+	}
+}
+
+// isModifiedKey matches tmux modifier key names such as C-a, M-x or S-Up.
+func isModifiedKey(key string) bool {
+	return len(key) > 2 && strings.Contains("CMS", key[:1]) && key[1] == '-'
+}
@@ -0,0 +1,118 @@
+package faketmux
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/atomicfile"
+)
+
+// serverState is one simulated tmux server (one -L/-S socket), persisted as
+// <dir>/servers/<socket>.json.
+type serverState struct {
+	NextID   int               `json:"next_id"`
+	Sessions []*fakeSession    `json:"sessions"`
+	Env      map[string]string `json:"env,omitempty"`
+	Options  map[string]string `json:"options,omitempty"`
+}
+
+// fakeSession is a session with its single window and pane.
+type fakeSession struct {
+	Name     string            `json:"name"`
+	ID       int               `json:"id"`
+	WorkDir  string            `json:"work_dir"`
+	Command  string            `json:"command,omitempty"`
+	Window   string            `json:"window,omitempty"`
+	Tool     string            `json:"tool"`
+	Created  time.Time         `json:"created"`
+	Activity time.Time         `json:"activity"`
+	Env      map[string]string `json:"env,omitempty"`
+	Options  map[string]string `json:"options,omitempty"`
+
+	// Lines is the pane's output above the composer; Input is the text
+	// typed into the composer but not yet submitted.
+	Lines []string `json:"lines"`
+	Input string   `json:"input,omitempty"`
+	// BusySince..BusyUntil, when set, is the current simulated turn;
+	// Reply is appended to Lines once it ends.
+	BusySince time.Time `json:"busy_since,omitzero"`
+	BusyUntil time.Time `json:"busy_until,omitzero"`
+	Reply     []string  `json:"reply,omitempty"`
+	Dead      bool      `json:"dead,omitempty"`
+}
+
+// server is a locked, loaded serverState. Every shim invocation holds the
+// lock for its whole run, so concurrent tmux calls serialize like they do
+// against a real server.
+type server struct {
+	path  string
+	lock  *os.File
+	state serverState
+}
+
+func openServer(dir, socket string) (*server, error) {
+	base := filepath.Join(dir, "servers")
+	if err := os.MkdirAll(base, 0o755); err != nil {
+		return nil, fmt.Errorf("create state dir: %w", err)
+	}
+	lock, err := os.OpenFile(filepath.Join(base, socket+".lock"), os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open lock: %w", err)
+	}
+	if err := syscall.Flock(int(lock.Fd()), syscall.LOCK_EX); err != nil {
+		_ = lock.Close()
+		return nil, fmt.Errorf("lock state: %w", err)
+	}
+	s := &server{path: filepath.Join(base, socket+".json"), lock: lock}
+	data, err := os.ReadFile(s.path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		s.close()
+		return nil, fmt.Errorf("read state: %w", err)
+	default:
+		if err := json.Unmarshal(data, &s.state); err != nil {
+			s.close()
+			return nil, fmt.Errorf("parse state %s: %w", s.path, err)
+		}
+	}
+	return s, nil
+}
+
+func (s *server) save() error {
+	data, err := json.MarshalIndent(&s.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(s.path, data, 0o644)
+}
+
+func (s *server) close() {
+	// Closing the fd also drops the advisory lock.
+	_ = s.lock.Close()
+}
+
+func (s *server) find(name string) *fakeSession {
+	for _, sess := range s.state.Sessions {
+		if sess.Name == name {
+			return sess
+		}
+	}
+	return nil
+}
+
+func (s *server) remove(name string) bool {
+	for i, sess := range s.state.Sessions {
+		if sess.Name == name {
+			s.state.Sessions = append(s.state.Sessions[:i], s.state.Sessions[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package tmux
+
+import "testing"
+
+// goose: tmux-layer detection and status pattern tests.
+
+func TestDetectToolFromCommand_Goose(t *testing.T) {
+	for _, cmd := range []string{"goose", "goose session", "goose session --resume", "/opt/homebrew/bin/goose"} {
+		if got := detectToolFromCommand(cmd); got != "goose" {
+			t.Errorf("detectToolFromCommand(%q) = %q, want goose", cmd, got)
+		}
+	}
+}
+
+func TestDetectToolFromContent_Goose(t *testing.T) {
+	content := "starting session | provider: anthropic model: claude-sonnet-4\n\nGoose is running! Enter your instructions, or try asking what goose can do.\n\n( O)> "
+	if got := detectToolFromContent(content); got != "goose" {
+		t.Errorf("detectToolFromContent = %q, want goose", got)
+	}
+}
+
+func TestGoosePrompt(t *testing.T) {
+	d := NewPromptDetector("goose")
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"input prompt", "Done, the tests pass now.\n\n( O)> ", true},
+		{"tool approval", "─── shell | developer ──────\ncommand: go test ./...\n\n◆  Goose would like to call the above tool, do you allow?\n│  ● Allow", true},
+		{"thinking", "( O)> fix the tests\n◐  Consulting the oracle...", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.HasPrompt(tt.content); got != tt.want {
+				t.Errorf("HasPrompt(%q) = %v, want %v", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultRawPatterns_Goose_PromptOnLastLineOnly(t *testing.T) {
+	resolved, err := CompilePatterns(DefaultRawPatterns("goose"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	matches := func(content string) bool {
+		for _, re := range resolved.PromptRegexps {
+			if re.MatchString(content) {
+				return true
+			}
+		}
+		return false
+	}
+	if !matches("Done.\n\n( O)> ") {
+		t.Error("prompt regex does not match an idle prompt")
+	}
+	if matches("( O)> fix the tests\nrunning go test ./...\nok  \tpkg\t0.2s") {
+		t.Error("prompt regex matched the submitted prompt while goose is working")
+	}
+}
@@ -0,0 +1,154 @@
+package tmux
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// Multi-pane sessions: a session may carry extra panes or windows next to
+// the agent (a dev server, a log tail). They are opened once, right after
+// the session is created, and the agent's pane is recorded in the session
+// option @agentdeck_agent_pane. Status capture and every send target that
+// pane by id, so focusing another pane while attached never redirects status
+// detection or input away from the agent.
+
+// Pane placements for PaneSpec.Split.
+const (
+	PaneSplitRight  = "right"
+	PaneSplitBelow  = "below"
+	PaneSplitWindow = "window"
+)
+
+// agentPaneOption is the session option holding the agent's pane id.
+const agentPaneOption = "@agentdeck_agent_pane"
+
+var paneSizeRe = regexp.MustCompile(`^[0-9]+%?$`)
+
+// PaneSpec is one extra pane of a multi-pane session.
+type PaneSpec struct {
+	// Command runs in the pane, in the session's working directory. The
+	// pane drops to a shell when it exits.
+	Command string `toml:"command" json:"command"`
+	// Split places the pane: "right" of the agent pane (default), "below"
+	// the previous pane, or "window" for a tmux window of its own.
+	Split string `toml:"split,omitempty" json:"split,omitempty"`
+	// Size of a split pane, in cells ("30") or percent ("40%").
+	Size string `toml:"size,omitempty" json:"size,omitempty"`
+	// Name names the window of a "window" pane.
+	Name string `toml:"name,omitempty" json:"name,omitempty"`
+}
+
+// Validate reports a pane spec that tmux could not open.
+func (p PaneSpec) Validate() error {
+	if strings.TrimSpace(p.Command) == "" {
+		return fmt.Errorf("pane has no command")
+	}
+	switch p.Split {
+	case "", PaneSplitRight, PaneSplitBelow, PaneSplitWindow:
+	default:
+		return fmt.Errorf("pane %q: invalid split %q (use right, below or window)", p.Command, p.Split)
+	}
+	if p.Size != "" && !paneSizeRe.MatchString(p.Size) {
+		return fmt.Errorf("pane %q: invalid size %q (use cells like 30 or a percentage like 40%%)", p.Command, p.Size)
+	}
+	return nil
+}
+
+// layoutArgs returns the tmux command opening p. agent is the agent's pane
+// id and prev the pane opened before p (the agent pane for the first).
+func (p PaneSpec) layoutArgs(session, agent, prev, workDir string) []string {
+	var args []string
+	switch p.Split {
+	case PaneSplitWindow:
+		args = []string{"new-window", "-d", "-P", "-F", "#{pane_id}", "-t", session + ":", "-c", workDir}
+		if p.Name != "" {
+			args = append(args, "-n", p.Name)
+		}
+	case PaneSplitBelow:
+		args = []string{"split-window", "-d", "-v", "-P", "-F", "#{pane_id}", "-t", prev, "-c", workDir}
+	default:
+		args = []string{"split-window", "-d", "-h", "-P", "-F", "#{pane_id}", "-t", agent, "-c", workDir}
+	}
+	if p.Size != "" && p.Split != PaneSplitWindow {
+		args = append(args, "-l", p.Size)
+	}
+	return append(args, bashCWrap(p.Command+`; exec "${SHELL:-/bin/sh}"`))
+}
+
+// applyLayout opens s.Panes around the freshly created agent pane and
+// records the agent pane's id. New panes and windows are opened detached so
+// the agent keeps focus. A pane that fails to open is logged and skipped.
+func (s *Session) applyLayout(workDir string) error {
+	out, err := s.runBoundedOutput("display-message", "-p", "-t", s.Name+":", "#{pane_id}")
+	if err != nil {
+		return fmt.Errorf("find agent pane: %w", err)
+	}
+	agent := strings.TrimSpace(string(out))
+	if agent == "" {
+		return fmt.Errorf("find agent pane: empty pane id")
+	}
+	prev := agent
+	for _, p := range s.Panes {
+		if err := p.Validate(); err != nil {
+			statusLog.Warn("layout_pane_invalid", slog.String("session", s.Name), slog.String("error", err.Error()))
+			continue
+		}
+		out, err := s.tmuxCmd(p.layoutArgs(s.Name, agent, prev, workDir)...).CombinedOutput()
+		if err != nil {
+			statusLog.Warn("layout_pane_failed", slog.String("session", s.Name),
+				slog.String("command", p.Command), slog.String("error", err.Error()),
+				slog.String("output", strings.TrimSpace(string(out))))
+			continue
+		}
+		if id := strings.TrimSpace(string(out)); id != "" && p.Split != PaneSplitWindow {
+			prev = id
+		}
+	}
+	if err := s.runBoundedRun("set-option", "-t", s.Name, agentPaneOption, agent); err != nil {
+		return fmt.Errorf("record agent pane: %w", err)
+	}
+	s.layoutMu.Lock()
+	s.agentPane = agent
+	s.agentPaneLoaded = true
+	s.layoutMu.Unlock()
+	return nil
+}
+
+// agentPaneID returns the pane id of the agent in a multi-pane session, or
+// "" for a single-pane session. A session reconnected after a restart looks
+// the id up once from @agentdeck_agent_pane; when it is missing (the layout
+// was never applied) the session behaves as single-pane.
+func (s *Session) agentPaneID() string {
+	if len(s.Panes) == 0 {
+		return ""
+	}
+	s.layoutMu.Lock()
+	defer s.layoutMu.Unlock()
+	if !s.agentPaneLoaded {
+		s.agentPaneLoaded = true
+		if out, err := s.runBoundedOutput("show-options", "-qv", "-t", s.Name, agentPaneOption); err == nil {
+			s.agentPane = strings.TrimSpace(string(out))
+		}
+	}
+	return s.agentPane
+}
+
+// paneTarget is the tmux target for commands aimed at the agent: its pane
+// in a multi-pane session, else the session (its active pane).
+func (s *Session) paneTarget() string {
+	if id := s.agentPaneID(); id != "" {
+		return id
+	}
+	return s.Name
+}
+
+// resetAgentPane forgets the recorded agent pane, before the session is
+// (re)created.
+func (s *Session) resetAgentPane() {
+	s.layoutMu.Lock()
+	s.agentPane = ""
+	s.agentPaneLoaded = false
+	s.layoutMu.Unlock()
+}
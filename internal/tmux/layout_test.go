@@ -0,0 +1,85 @@
+package tmux
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPaneSpec_Validate(t *testing.T) {
+	for _, p := range []PaneSpec{
+		{Command: "npm run dev"},
+		{Command: "tail -f log", Split: "below", Size: "30%"},
+		{Command: "htop", Split: "window", Name: "top"},
+	} {
+		if err := p.Validate(); err != nil {
+			t.Errorf("%+v: %v", p, err)
+		}
+	}
+	for _, p := range []PaneSpec{
+		{Command: " "},
+		{Command: "x", Split: "left"},
+		{Command: "x", Size: "big"},
+	} {
+		if err := p.Validate(); err == nil {
+			t.Errorf("%+v: want error", p)
+		}
+	}
+}
+
+// TestStart_LayoutBindsAgentPane starts a three-pane session, moves focus to
+// a layout pane, and checks that capture and sends still reach the agent.
+func TestStart_LayoutBindsAgentPane(t *testing.T) {
+	skipIfNoTmuxBinary(t)
+	s := NewSession("agent-deck-layout", t.TempDir())
+	s.Panes = []PaneSpec{
+		{Command: "echo server-pane", Size: "40%"},
+		{Command: "echo logs-pane", Split: PaneSplitBelow},
+		{Command: "echo window-pane", Split: PaneSplitWindow, Name: "extra"},
+	}
+	if err := s.Start(""); err != nil {
+		t.Skipf("could not start tmux session in this environment: %v", err)
+	}
+	defer func() { _ = s.Kill() }()
+
+	out, err := tmuxExec(s.SocketName, "list-panes", "-s", "-t", s.Name, "-F", "#{window_name}|#{pane_id}").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	panes := strings.Fields(strings.TrimSpace(string(out)))
+	if len(panes) != 4 {
+		t.Fatalf("panes = %v, want agent + 3", panes)
+	}
+	agent := s.agentPaneID()
+	if agent == "" || !strings.HasSuffix(panes[0], "|"+agent) {
+		t.Fatalf("agent pane %q, want the first pane of %v", agent, panes)
+	}
+
+	// A reconnected session finds the agent pane from the session option.
+	re := ReconnectSessionLazy(s.Name, s.DisplayName, s.WorkDir, "", "waiting")
+	re.SocketName = s.SocketName
+	re.Panes = s.Panes
+	if got := re.agentPaneID(); got != agent {
+		t.Fatalf("reconnected agent pane = %q, want %q", got, agent)
+	}
+
+	// Focus another pane; input and capture must stay on the agent.
+	last := panes[2][strings.Index(panes[2], "|")+1:]
+	if err := tmuxExec(s.SocketName, "select-pane", "-t", last).Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := re.SendKeysAndEnter("echo agent-marker"); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		content, err := re.CapturePaneFresh()
+		if err == nil && strings.Contains(content, "agent-marker") && !strings.Contains(content, "logs-pane") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("agent pane capture = %q, err %v", content, err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -0,0 +1,128 @@
+package tmux
+
+import (
+	"errors"
+	"sync"
+)
+
+// maxOptionBatchArgs caps the argv length of one batched tmux invocation.
+// Each session contributes ~40 args, so a 30-session bulk start still fits in
+// one call while staying far below ARG_MAX.
+const maxOptionBatchArgs = 2000
+
+// OptionBatch collects ";"-chained tmux commands for many sessions and applies
+// them with one tmux invocation per socket on Flush. Session.Start routes its
+// cosmetic options (status bar, terminal title, detach binding) here when a
+// batch is attached via DeferCosmeticOptions, so starting N sessions costs N
+// essential calls plus one shared call instead of 3N extra subprocesses.
+// Safe for concurrent use.
+type OptionBatch struct {
+	mu      sync.Mutex
+	sockets []string
+	cmds    map[string][][]string // socket -> command groups, in Add order
+}
+
+// NewOptionBatch returns an empty batch.
+func NewOptionBatch() *OptionBatch {
+	return &OptionBatch{cmds: make(map[string][][]string)}
+}
+
+// Add queues a ";"-chained command group for the tmux server on socket.
+func (b *OptionBatch) Add(socket string, args []string) {
+	if len(args) == 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.cmds[socket]; !ok {
+		b.sockets = append(b.sockets, socket)
+	}
+	b.cmds[socket] = append(b.cmds[socket], args)
+}
+
+// Len returns the number of queued command groups.
+func (b *OptionBatch) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := 0
+	for _, groups := range b.cmds {
+		n += len(groups)
+	}
+	return n
+}
+
+// Flush applies every queued group and empties the batch. tmux aborts a
+// ";"-chain at the first failing command, so one session that died after it
+// was queued would leave every later session's options unapplied: a chunk
+// that fails is re-run one group at a time, and only the groups that still
+// fail are reported. Failures are joined.
+func (b *OptionBatch) Flush() error {
+	b.mu.Lock()
+	sockets, cmds := b.sockets, b.cmds
+	b.sockets, b.cmds = nil, make(map[string][][]string)
+	b.mu.Unlock()
+
+	var errs []error
+	for _, socket := range sockets {
+		for _, chunk := range optionBatchChunks(cmds[socket], maxOptionBatchArgs) {
+			if err := runBoundedRun(socket, joinOptionGroups(chunk)...); err == nil || len(chunk) == 1 {
+				if err != nil {
+					errs = append(errs, err)
+				}
+				continue
+			}
+			for _, g := range chunk {
+				if err := runBoundedRun(socket, g...); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// optionBatchInvocations joins command groups with ";" into as few argv lists
+// as possible without exceeding maxArgs. A single group longer than maxArgs is
+// emitted on its own.
+func optionBatchInvocations(groups [][]string, maxArgs int) [][]string {
+	var out [][]string
+	for _, chunk := range optionBatchChunks(groups, maxArgs) {
+		out = append(out, joinOptionGroups(chunk))
+	}
+	return out
+}
+
+// optionBatchChunks splits groups into runs whose ";"-joined argv stays
+// within maxArgs.
+func optionBatchChunks(groups [][]string, maxArgs int) [][][]string {
+	var out [][][]string
+	var cur [][]string
+	n := 0
+	for _, g := range groups {
+		if len(cur) > 0 && n+1+len(g) > maxArgs {
+			out = append(out, cur)
+			cur, n = nil, 0
+		}
+		if len(cur) > 0 {
+			n++
+		}
+		cur = append(cur, g)
+		n += len(g)
+	}
+	if len(cur) > 0 {
+		out = append(out, cur)
+	}
+	return out
+}
+
+// joinOptionGroups chains groups into one tmux argv.
+func joinOptionGroups(groups [][]string) []string {
+	var argv []string
+	for i, g := range groups {
+		if i > 0 {
+			argv = append(argv, ";")
+		}
+		argv = append(argv, g...)
+	}
+	return argv
+}
@@ -0,0 +1,141 @@
+package tmux
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOptionBatchInvocations_ChainsAndChunks(t *testing.T) {
+	groups := [][]string{
+		{"set-option", "-t", "a", "status", "on"},
+		{"set-option", "-t", "b", "status", "on"},
+		{"set-option", "-t", "c", "status", "on"},
+	}
+
+	got := optionBatchInvocations(groups, 100)
+	want := [][]string{{
+		"set-option", "-t", "a", "status", "on", ";",
+		"set-option", "-t", "b", "status", "on", ";",
+		"set-option", "-t", "c", "status", "on",
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("single invocation mismatch:\n got %q\nwant %q", got, want)
+	}
+
+	// 5 + 1 + 5 = 11 args fit; the third group starts a new invocation.
+	got = optionBatchInvocations(groups, 11)
+	if len(got) != 2 || len(got[0]) != 11 || len(got[1]) != 5 {
+		t.Fatalf("expected chunks of 11 and 5 args, got %q", got)
+	}
+
+	if got := optionBatchInvocations(nil, 100); got != nil {
+		t.Fatalf("empty batch should produce no invocations, got %q", got)
+	}
+}
+
+func TestOptionBatch_AddLenAndSockets(t *testing.T) {
+	b := NewOptionBatch()
+	b.Add("", []string{"set-option", "-t", "a", "status", "on"})
+	b.Add("isolated", []string{"set-option", "-t", "b", "status", "on"})
+	b.Add("", nil)
+	if b.Len() != 2 {
+		t.Fatalf("Len = %d, want 2", b.Len())
+	}
+	if !reflect.DeepEqual(b.sockets, []string{"", "isolated"}) {
+		t.Fatalf("sockets = %q", b.sockets)
+	}
+}
+
+func TestSessionCosmeticOptionArgs(t *testing.T) {
+	s := NewSession("cosmetic", "/tmp")
+	s.OptionOverrides = map[string]string{"status-style": "bg=red"}
+	args := strings.Join(s.cosmeticOptionArgs(), " ")
+
+	for _, want := range []string{"bind-key -n -T root C-q", "status-right-length", "set-titles-string"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("cosmetic args missing %q: %s", want, args)
+		}
+	}
+	if strings.Contains(args, "status-style") {
+		t.Errorf("user-overridden status-style must be skipped: %s", args)
+	}
+}
+
+func TestSessionOptionOverrideArgs_Sorted(t *testing.T) {
+	s := NewSession("overrides", "/tmp")
+	s.OptionOverrides = map[string]string{"history-limit": "50000", "allow-passthrough": "all"}
+	got := s.optionOverrideArgs()
+	want := []string{
+		";", "set-option", "-t", s.Name, "-q", "allow-passthrough", "all",
+		";", "set-option", "-t", s.Name, "-q", "history-limit", "50000",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("override args mismatch:\n got %q\nwant %q", got, want)
+	}
+}
+
+// TestStart_DeferredCosmeticOptionsApplyOnFlush verifies that a batched Start
+// leaves the status bar untouched until the batch is flushed.
+func TestStart_DeferredCosmeticOptionsApplyOnFlush(t *testing.T) {
+	skipIfNoTmuxBinary(t)
+	s := NewSession("agent-deck-option-batch", t.TempDir())
+	batch := NewOptionBatch()
+	s.DeferCosmeticOptions(batch)
+	if err := s.Start(""); err != nil {
+		t.Skipf("could not start tmux session in this environment: %v", err)
+	}
+	defer func() { _ = s.Kill() }()
+
+	show := func() string {
+		out, _ := tmuxExec(s.SocketName, "show-options", "-t", s.Name, "-v", "status-right-length").Output()
+		return strings.TrimSpace(string(out))
+	}
+	if got := show(); got == "100" {
+		t.Fatal("status bar applied before Flush")
+	}
+	if batch.Len() != 1 {
+		t.Fatalf("batch.Len = %d, want 1", batch.Len())
+	}
+	if err := batch.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if got := show(); got != "100" {
+		t.Fatalf("status-right-length after Flush = %q, want 100", got)
+	}
+}
+
+// TestOptionBatch_FlushSurvivesDeadSessionMidChain verifies that a session
+// killed after its options were queued does not abort the ";"-chain for the
+// sessions queued after it.
+func TestOptionBatch_FlushSurvivesDeadSessionMidChain(t *testing.T) {
+	skipIfNoTmuxBinary(t)
+	var sessions []*Session
+	for _, name := range []string{"agent-deck-batch-a", "agent-deck-batch-dead", "agent-deck-batch-c"} {
+		s := NewSession(name, t.TempDir())
+		if err := s.Start(""); err != nil {
+			t.Skipf("could not start tmux session in this environment: %v", err)
+		}
+		t.Cleanup(func() { _ = s.Kill() })
+		sessions = append(sessions, s)
+	}
+
+	batch := NewOptionBatch()
+	for _, s := range sessions {
+		batch.Add(s.SocketName, []string{"set-option", "-t", s.Name, "status-left-length", "77"})
+	}
+	if err := sessions[1].Kill(); err != nil {
+		t.Fatalf("kill middle session: %v", err)
+	}
+
+	err := batch.Flush()
+	if err == nil {
+		t.Error("Flush: want an error for the dead session")
+	}
+	for _, s := range []*Session{sessions[0], sessions[2]} {
+		out, _ := tmuxExec(s.SocketName, "show-options", "-t", s.Name, "-v", "status-left-length").Output()
+		if got := strings.TrimSpace(string(out)); got != "77" {
+			t.Errorf("%s status-left-length = %q, want 77 (flush error: %v)", s.Name, got, err)
+		}
+	}
+}
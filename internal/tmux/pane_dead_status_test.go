@@ -0,0 +1,24 @@
+package tmux
+
+import "testing"
+
+func TestParsePaneDeadStatus(t *testing.T) {
+	tests := []struct {
+		in     string
+		code   int
+		wantOK bool
+	}{
+		{"1|0\n", 0, true},
+		{"1|137", 137, true},
+		{"0|", 0, false},
+		{"1|", 0, false},
+		{"", 0, false},
+		{"garbage", 0, false},
+	}
+	for _, tc := range tests {
+		code, ok := parsePaneDeadStatus(tc.in)
+		if ok != tc.wantOK || code != tc.code {
+			t.Errorf("parsePaneDeadStatus(%q) = (%d, %v), want (%d, %v)", tc.in, code, ok, tc.code, tc.wantOK)
+		}
+	}
+}
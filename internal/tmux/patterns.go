@@ -150,6 +150,36 @@ func DefaultRawPatterns(toolName string) *RawPatterns {
 				"Switch modes",
 			},
 		}
+	case "aider":
+		// Aider (aider.chat). While the model works, aider shows a spinner
+		// line "Waiting for <model>" (or "Updating repo map") that it erases
+		// once output starts streaming. The input prompt is "> ", prefixed by
+		// the chat mode outside code mode ("architect> ", "ask> "). Prompt
+		// patterns are anchored to the last line: answered confirmations and
+		// submitted prompts stay on screen while aider works.
+		return &RawPatterns{
+			BusyPatterns: []string{
+				`re:(?m)Waiting for [\w./:@-]+\s*$`,
+				"Updating repo map",
+			},
+			PromptPatterns: []string{
+				`re:\(Y\)es/\(N\)o[^\n]*$`,
+				`re:(?:^|\n)(?:architect|ask|code|context|help|multi)?>\s*$`,
+			},
+			SpinnerChars: []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"},
+		}
+	case "goose":
+		// Block's goose CLI (`goose session`). Thinking shows a cliclack
+		// spinner (◐◓◑◒) with a rotating message; the input prompt is
+		// "( O)>" (matched on the last line only) and tool calls ask "Goose
+		// would like to call the above tool" in approve mode.
+		return &RawPatterns{
+			PromptPatterns: []string{
+				`re:(?:^|\n)\( O\)>[^\n]*$`,
+				"Goose would like to call",
+			},
+			SpinnerChars: []string{"◐", "◓", "◑", "◒"},
+		}
 	case "shell":
 		return &RawPatterns{
 			PromptPatterns: []string{"$ ", "# ", "% "},
@@ -167,6 +167,23 @@ func (pm *PipeManager) CapturePane(sessionName string) (string, error) {
 	return pipe.CapturePaneVia()
 }
 
+// CapturePaneTarget is CapturePane for one pane of the session (a pane id
+// such as "%3"), carried on the session's pipe.
+func (pm *PipeManager) CapturePaneTarget(sessionName, target string) (string, error) {
+	if target == "" || target == sessionName {
+		return pm.CapturePane(sessionName)
+	}
+	pm.mu.RLock()
+	pipe := pm.pipes[sessionName]
+	pm.mu.RUnlock()
+
+	if pipe == nil || !pipe.IsAlive() {
+		return "", fmt.Errorf("no pipe for session %s", sessionName)
+	}
+
+	return pipe.SendCommand(fmt.Sprintf("capture-pane -t %s -p -e", target))
+}
+
 // GetWindowActivity sends a display-message command through the pipe to get
 // the window_activity timestamp. Falls back to error if pipe unavailable.
 func (pm *PipeManager) GetWindowActivity(sessionName string) (int64, error) {
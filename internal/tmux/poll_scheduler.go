@@ -0,0 +1,150 @@
+package tmux
+
+import (
+	"sync"
+	"time"
+)
+
+// PollTier groups sessions by how often their status needs polling: a
+// working session changes state within seconds, an idle one rarely does.
+type PollTier int
+
+const (
+	PollActive  PollTier = iota // running or starting
+	PollWaiting                 // waiting for input (or errored)
+	PollIdle                    // idle or stopped
+)
+
+// PollIntervals are the per-tier polling intervals. A zero field falls back
+// to the scheduler's base (or DefaultPollIntervals).
+type PollIntervals struct {
+	Active  time.Duration
+	Waiting time.Duration
+	Idle    time.Duration
+}
+
+// DefaultPollIntervals: active sessions every 500ms, waiting every 2s, idle
+// every 10s.
+var DefaultPollIntervals = PollIntervals{
+	Active:  500 * time.Millisecond,
+	Waiting: 2 * time.Second,
+	Idle:    10 * time.Second,
+}
+
+// get returns the interval for tier, or zero when unset.
+func (p PollIntervals) get(tier PollTier) time.Duration {
+	switch tier {
+	case PollActive:
+		return p.Active
+	case PollWaiting:
+		return p.Waiting
+	default:
+		return p.Idle
+	}
+}
+
+// withDefaults fills zero fields from def.
+func (p PollIntervals) withDefaults(def PollIntervals) PollIntervals {
+	if p.Active <= 0 {
+		p.Active = def.Active
+	}
+	if p.Waiting <= 0 {
+		p.Waiting = def.Waiting
+	}
+	if p.Idle <= 0 {
+		p.Idle = def.Idle
+	}
+	return p
+}
+
+// PollScheduler decides which sessions a status sweep polls. Each session is
+// due again one tier interval after its last poll; Wake makes it due at once
+// (new pane output, a hook event, a user action). The sweep itself runs at
+// Tick, the smallest configured interval, and skips sessions not yet due, so
+// a large idle fleet no longer pays for the few busy sessions' cadence.
+// Safe for concurrent use.
+type PollScheduler struct {
+	base  PollIntervals
+	tools map[string]PollIntervals
+
+	mu   sync.Mutex
+	last map[string]time.Time
+	next map[string]time.Time
+}
+
+// NewPollScheduler returns a scheduler with base intervals (zero fields use
+// DefaultPollIntervals) and per-tool overrides (zero fields use base).
+func NewPollScheduler(base PollIntervals, tools map[string]PollIntervals) *PollScheduler {
+	s := &PollScheduler{
+		base:  base.withDefaults(DefaultPollIntervals),
+		tools: make(map[string]PollIntervals, len(tools)),
+		last:  make(map[string]time.Time),
+		next:  make(map[string]time.Time),
+	}
+	for tool, iv := range tools {
+		s.tools[tool] = iv.withDefaults(s.base)
+	}
+	return s
+}
+
+// Interval returns the polling interval for a session of tool in tier.
+func (s *PollScheduler) Interval(tool string, tier PollTier) time.Duration {
+	if iv, ok := s.tools[tool]; ok {
+		return iv.get(tier)
+	}
+	return s.base.get(tier)
+}
+
+// Tick returns the sweep cadence: the smallest interval of any tier or tool.
+func (s *PollScheduler) Tick() time.Duration {
+	tick := min(s.base.Active, s.base.Waiting, s.base.Idle)
+	for _, iv := range s.tools {
+		tick = min(tick, iv.Active, iv.Waiting, iv.Idle)
+	}
+	return tick
+}
+
+// Due reports whether key should be polled at now.
+func (s *PollScheduler) Due(key string, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	next, ok := s.next[key]
+	return !ok || !now.Before(next)
+}
+
+// Polled records a poll of key at now; the session is due again after the
+// interval of its (new) tier.
+func (s *PollScheduler) Polled(key, tool string, tier PollTier, now time.Time) {
+	interval := s.Interval(tool, tier)
+	s.mu.Lock()
+	s.last[key] = now
+	s.next[key] = now.Add(interval)
+	s.mu.Unlock()
+}
+
+// LastPolled returns when key was last polled (zero: never).
+func (s *PollScheduler) LastPolled(key string) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last[key]
+}
+
+// Wake makes key due on the next sweep.
+func (s *PollScheduler) Wake(key string) {
+	s.mu.Lock()
+	delete(s.next, key)
+	s.mu.Unlock()
+}
+
+// Retain forgets every key not in keep, so removed sessions do not
+// accumulate.
+func (s *PollScheduler) Retain(keep map[string]bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.last {
+		if !keep[key] {
+			delete(s.last, key)
+			delete(s.next, key)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package tmux
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPollScheduler_TiersAndToolOverrides(t *testing.T) {
+	s := NewPollScheduler(PollIntervals{Idle: 20 * time.Second}, map[string]PollIntervals{
+		"shell": {Active: 250 * time.Millisecond},
+	})
+	cases := []struct {
+		tool string
+		tier PollTier
+		want time.Duration
+	}{
+		{"claude", PollActive, 500 * time.Millisecond},
+		{"claude", PollWaiting, 2 * time.Second},
+		{"claude", PollIdle, 20 * time.Second},
+		{"shell", PollActive, 250 * time.Millisecond},
+		{"shell", PollIdle, 20 * time.Second},
+	}
+	for _, tc := range cases {
+		if got := s.Interval(tc.tool, tc.tier); got != tc.want {
+			t.Errorf("Interval(%s, %d) = %v, want %v", tc.tool, tc.tier, got, tc.want)
+		}
+	}
+	if s.Tick() != 250*time.Millisecond {
+		t.Errorf("Tick = %v, want the smallest interval (250ms)", s.Tick())
+	}
+}
+
+func TestPollScheduler_DueWakeRetain(t *testing.T) {
+	s := NewPollScheduler(PollIntervals{}, nil)
+	now := time.Unix(1_700_000_000, 0)
+
+	if !s.Due("a", now) {
+		t.Fatal("never-polled session must be due")
+	}
+	s.Polled("a", "claude", PollIdle, now)
+	if s.Due("a", now.Add(9*time.Second)) || !s.Due("a", now.Add(10*time.Second)) {
+		t.Error("idle session not due exactly one idle interval after its poll")
+	}
+	if !s.LastPolled("a").Equal(now) {
+		t.Errorf("LastPolled = %v", s.LastPolled("a"))
+	}
+
+	s.Wake("a")
+	if !s.Due("a", now.Add(time.Second)) {
+		t.Error("woken session not due")
+	}
+
+	s.Polled("b", "claude", PollActive, now)
+	s.Retain(map[string]bool{"b": true})
+	if !s.LastPolled("a").IsZero() || s.LastPolled("b").IsZero() {
+		t.Error("Retain kept the wrong sessions")
+	}
+}
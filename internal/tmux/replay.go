@@ -0,0 +1,48 @@
+package tmux
+
+// SnapshotVerdict is the status GetStatus assigns to one recorded pane
+// capture (see ReplaySnapshot).
+type SnapshotVerdict struct {
+	// Tool is the tool the capture was classified as: the one passed in, or
+	// the one detected from the content.
+	Tool string `json:"tool"`
+	// Status is the GetStatus answer: "active", "waiting", "idle", "error"
+	// or "starting".
+	Status string `json:"status"`
+	// Substate names the finer-grained state (e.g. "auth-401"), if any.
+	Substate Substate `json:"substate,omitempty"`
+	// Matched is false when the capture carries no busy, prompt or error
+	// signal; Status is then the first-poll default, "waiting".
+	Matched bool `json:"matched"`
+}
+
+// ReplaySnapshot runs a recorded pane capture through GetStatus's content
+// classification, as the first poll of a fresh, unacknowledged session
+// would see it. No tmux server is involved, so the verdict is deterministic:
+// fixtures under testdata/status replay through it, and `agent-deck debug
+// classify` uses it to show how a capture that misfired is read. tool ""
+// detects the tool from the content.
+func ReplaySnapshot(tool, content string) SnapshotVerdict {
+	clean := StripANSI(content)
+	if tool == "" {
+		tool = detectToolFromContent(clean)
+	}
+	s := &Session{Name: "replay", DisplayName: "replay", detectedTool: tool}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status, ok := s.statusFromContentLocked(clean, 0, s.DisplayName)
+	if !ok {
+		status = "waiting"
+	}
+	return SnapshotVerdict{Tool: tool, Status: status, Substate: s.lastSubstate, Matched: ok}
+}
+
+// IsSnapshotStatus reports whether status is one ReplaySnapshot can return,
+// i.e. a valid <status> in a testdata/status fixture name.
+func IsSnapshotStatus(status string) bool {
+	switch status {
+	case "active", "waiting", "idle", "error", "starting":
+		return true
+	}
+	return false
+}
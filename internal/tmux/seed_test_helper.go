@@ -44,3 +44,13 @@ func ExpirePaneInfoCacheForTest(t testing.TB) {
 		paneCacheMu.Unlock()
 	})
 }
+
+// SeedSubstateForTest sets the substate s reports from CachedSubstate, as
+// if its last status poll had classified it, so packages outside
+// internal/tmux can test substate-driven behavior without a tmux server.
+func SeedSubstateForTest(t testing.TB, s *Session, sub Substate) {
+	t.Helper()
+	s.mu.Lock()
+	s.lastSubstate = sub
+	s.mu.Unlock()
+}
@@ -299,11 +299,23 @@ func runBoundedOutput(socketName string, args ...string) ([]byte, error) {
 // under tmuxPollTimeout, discarding stdout. Timeout-guarded replacement for the
 // bare tmuxExec(socket, args...).Run() / s.tmuxCmd(args...).Run() status sites.
 func runBoundedRun(socketName string, args ...string) error {
+	if handled, err := runViaControlPipe(socketName, args); handled {
+		return err
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), tmuxPollTimeout)
 	defer cancel()
 	return tmuxExecContext(ctx, socketName, args...).Run()
 }
 
+// sendKeysCmd runs a send-keys command line for s through a control pipe in
+// control-mode-only operation, else as a bounded keySenderExec subprocess.
+func (s *Session) sendKeysCmd(args ...string) error {
+	if handled, err := runViaControlPipe(s.SocketName, args); handled {
+		return err
+	}
+	return runSendKeysBounded(keySenderExec(s.SocketName, args...))
+}
+
 // runBoundedOutput is the per-Session convenience wrapper, targeting the
 // session's own socket (see tmuxCmd for why the socket must not drift).
 func (s *Session) runBoundedOutput(args ...string) ([]byte, error) {
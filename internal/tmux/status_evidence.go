@@ -0,0 +1,61 @@
+package tmux
+
+// StatusEvidence is what the content-based classifier had to go on for a
+// session's current status, gathered for post-mortems of a misclassified
+// status (see the session package's status history).
+type StatusEvidence struct {
+	// Status is the classifier's last stable status ("active", "waiting",
+	// "idle", "starting", "error" or "inactive").
+	Status   string
+	Substate Substate
+
+	// Title and TitleState come from the cached pane title, which short-
+	// circuits detection for Claude ("working" or "done"; "" otherwise).
+	Title      string
+	TitleState string
+
+	Acknowledged bool
+
+	// Prompt reports whether the prompt patterns match Capture.
+	Prompt bool
+
+	// Capture is the visible pane, normalized the way status hashing sees
+	// it (ANSI, spinners and counters stripped); Hash is its hash. Both are
+	// empty when the pane could not be captured.
+	Capture string
+	Hash    string
+}
+
+// StatusEvidence returns the classifier state behind the last GetStatus call
+// together with a capture of the visible pane. The capture reuses the short-
+// lived pane cache, so calling it right after GetStatus costs no extra tmux
+// round trip. The busy indicator is not re-evaluated: its spinner tracking
+// has side effects on the classifier.
+func (s *Session) StatusEvidence() StatusEvidence {
+	var ev StatusEvidence
+	if info, ok := GetCachedPaneInfo(s.Name); ok {
+		ev.Title = info.Title
+		switch AnalyzePaneTitle(info.Title, info.CurrentCommand) {
+		case TitleStateWorking:
+			ev.TitleState = "working"
+		case TitleStateDone:
+			ev.TitleState = "done"
+		}
+	}
+
+	content, err := s.CapturePane()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ev.Status = s.lastStableStatus
+	ev.Substate = s.lastSubstate
+	if s.stateTracker != nil {
+		ev.Acknowledged = s.stateTracker.acknowledged
+	}
+	if err == nil {
+		ev.Capture = s.normalizeContent(content)
+		ev.Hash = s.hashContent(ev.Capture)
+		ev.Prompt = s.hasPromptIndicator(StripANSI(content))
+	}
+	return ev
+}
@@ -0,0 +1,64 @@
+package tmux
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestStatusFixtures replays every recorded pane capture under
+// testdata/status/<tool>/<version>/<name>.<status>.txt through
+// ReplaySnapshot and checks the status in its name. When detection misfires
+// on a new tool release, `agent-deck debug classify --expect` confirms the
+// capture reproduces it; drop the file in here under the right name.
+func TestStatusFixtures(t *testing.T) {
+	root := filepath.Join("testdata", "status")
+	n := 0
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".txt" {
+			return err
+		}
+		rel, _ := filepath.Rel(root, path)
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		if len(parts) != 3 {
+			t.Errorf("%s: want <tool>/<version>/<name>.<status>.txt", rel)
+			return nil
+		}
+		want := strings.TrimPrefix(filepath.Ext(strings.TrimSuffix(parts[2], ".txt")), ".")
+		if !IsSnapshotStatus(want) {
+			t.Errorf("%s: unknown status %q in file name", rel, want)
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		n++
+		t.Run(rel, func(t *testing.T) {
+			got := ReplaySnapshot(parts[0], string(content))
+			if got.Status != want {
+				t.Errorf("status = %q (substate %q, matched %v), want %q", got.Status, got.Substate, got.Matched, want)
+			}
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n == 0 {
+		t.Fatal("no status fixtures found")
+	}
+}
+
+func TestReplaySnapshot_DetectsToolAndReportsUnmatched(t *testing.T) {
+	got := ReplaySnapshot("", "Using: 1 GEMINI.md file\n│ >   Type your message or @path/to/file │\n")
+	if got.Tool != "gemini" || got.Status != "waiting" || !got.Matched {
+		t.Errorf("gemini prompt: %+v", got)
+	}
+	got = ReplaySnapshot("claude", "plain output with no prompt\n")
+	if got.Matched || got.Status != "waiting" {
+		t.Errorf("no signal: %+v, want unmatched first-poll waiting", got)
+	}
+}
@@ -0,0 +1,34 @@
+package tmux
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStatusLeftSessionsArgs_SetsPerSessionOnly(t *testing.T) {
+	got := statusLeftSessionsArgs([]string{"agentdeck_a", "agentdeck_b"}, "⚡ [1] a", 2)
+	want := []string{
+		"set-option", "-gq", WaitingCountOption, "2",
+		";", "set-option", "-q", "-t", "agentdeck_a", "status-left", "⚡ [1] a",
+		";", "set-option", "-q", "-t", "agentdeck_b", "status-left", "⚡ [1] a",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("args mismatch:\n got %q\nwant %q", got, want)
+	}
+	for _, a := range got {
+		if a == "-g" {
+			t.Fatalf("status-left must never be set globally in session scope: %q", got)
+		}
+	}
+}
+
+func TestStatusLeftSessionsArgs_EmptyTextUnsets(t *testing.T) {
+	got := statusLeftSessionsArgs([]string{"agentdeck_a"}, "", 0)
+	want := []string{
+		"set-option", "-gq", WaitingCountOption, "0",
+		";", "set-option", "-q", "-t", "agentdeck_a", "-u", "status-left",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("args mismatch:\n got %q\nwant %q", got, want)
+	}
+}
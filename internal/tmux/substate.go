@@ -37,8 +37,29 @@ const (
 	// /login", "API Error: 401", "socket connection closed"). Pairs with
 	// status "error". Built on the #1400 error-banner detection.
 	SubstateAuth401 Substate = "auth-401"
+
+	// SubstateUsageLimit marks a Claude session stopped by the account's
+	// usage limit ("Claude usage limit reached", "5-hour limit reached ∙
+	// resets 3pm"). The limit is account-wide: every Claude session is held
+	// until it resets, so the [governor] queues sends while any pane shows
+	// it. Never a self-heal target — a restart cannot help. Pairs with any
+	// status; it does not change it.
+	SubstateUsageLimit Substate = "usage-limit"
 )
 
+// claudeInputLinePrefixes mark the prompt/input lines of a Claude pane.
+var claudeInputLinePrefixes = []string{"❯", ">"}
+
+// usageLimitSubstrings are fragments (lowercased) of the usage-limit notices
+// Claude Code renders when the account runs out of quota.
+var usageLimitSubstrings = []string{
+	"usage limit reached",
+	"limit reached ∙ resets",
+	"limit reached · resets",
+	"you've hit your limit",
+	"you've reached your usage limit",
+}
+
 // modelUnavailableSubstrings are fragments of the Fable/model-down no-op the
 // tool renders in the pane. Anchored on the rendered phrasing rather than a
 // bare token so ordinary conversation does not match.
@@ -71,9 +92,10 @@ const crunchedNoopMarker = "Crunched for 0s"
 //     no-op: if the session is crunching NOW, an older "Crunched for 0s" /
 //     "unavailable" line is stale. Deliberately does NOT treat a bare "✶" as a
 //     cue, so the no-op completion line's decorative asterisk does not match.
-//  3. model-unavailable — the Fable-down no-op loop with no live busy cue.
-//  4. idle-at-empty-prompt — sitting at the prompt with nothing happening.
-//  5. none      — no distinct refinement.
+//  3. usage-limit — the account's usage-limit notice with no live busy cue.
+//  4. model-unavailable — the Fable-down no-op loop with no live busy cue.
+//  5. idle-at-empty-prompt — sitting at the prompt with nothing happening.
+//  6. none      — no distinct refinement.
 func (d *PromptDetector) ClassifySubstate(content string) Substate {
 	if d.tool != "claude" {
 		return SubstateNone
@@ -93,7 +115,13 @@ func (d *PromptDetector) ClassifySubstate(content string) Substate {
 		return SubstateRunning
 	}
 
-	// 3. Model-unavailable no-op loop (Fable down) with no live busy cue: the
+	// 3. Usage limit reached with no live busy cue: the session is idle until
+	//    the quota resets, and so is every other session on the account.
+	if hasUsageLimitNotice(content) {
+		return SubstateUsageLimit
+	}
+
+	// 4. Model-unavailable no-op loop (Fable down) with no live busy cue: the
 	//    "Crunched for 0s" / "is currently unavailable" line is the actionable
 	//    signal. Scan the recent tail so a stale line scrolled far up does not
 	//    match.
@@ -101,7 +129,7 @@ func (d *PromptDetector) ClassifySubstate(content string) Substate {
 		return SubstateModelUnavailable
 	}
 
-	// 4. Sitting at the input prompt with no busy/error signal = genuinely idle.
+	// 5. Sitting at the input prompt with no busy/error signal = genuinely idle.
 	if d.hasClaudePrompt(content) {
 		return SubstateIdleAtEmptyPrompt
 	}
@@ -173,6 +201,32 @@ func hasModelUnavailableNoop(content string) bool {
 	return false
 }
 
+// hasUsageLimitNotice scans the last 15 non-empty lines for a usage-limit
+// notice. Input lines are skipped, so a user typing about usage limits does
+// not match; "⎿" lines are not, because Claude renders the notice as an
+// error result under the failed turn.
+func hasUsageLimitNotice(content string) bool {
+	lines := strings.Split(content, "\n")
+	checked := 0
+	for i := len(lines) - 1; i >= 0 && checked < 15; i-- {
+		line := strings.TrimSpace(StripANSI(lines[i]))
+		if line == "" {
+			continue
+		}
+		checked++
+		if hasAnyPrefix(line, claudeInputLinePrefixes) {
+			continue
+		}
+		lower := strings.ToLower(line)
+		for _, pat := range usageLimitSubstrings {
+			if strings.Contains(lower, pat) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // recentTailLower returns a lowercased join of the last n non-empty lines.
 func recentTailLower(content string, n int) string {
 	lines := strings.Split(content, "\n")
@@ -210,6 +210,30 @@ func TestClassifySubstate_LiveBusyWinsOverStaleNoop(t *testing.T) {
 	}
 }
 
+// The account's usage-limit notice is its own substate, so the [governor] can
+// hold sends to every Claude session until the quota resets. Typing about
+// the limit at the prompt must not match.
+func TestClassifySubstate_UsageLimit(t *testing.T) {
+	d := NewPromptDetector("claude")
+	for _, content := range []string{
+		"⏺ Working on it\n  ⎿  Claude usage limit reached. Your limit will reset at 5pm (Europe/Berlin).\n\n❯ \n  ? for shortcuts",
+		"  ⎿  5-hour limit reached ∙ resets 3pm\n     /upgrade to increase your usage limit.\n\n❯ ",
+		"You've hit your limit · resets 11pm\n❯ ",
+	} {
+		if got := d.ClassifySubstate(content); got != SubstateUsageLimit {
+			t.Errorf("content %q: got %q, want %q", content, got, SubstateUsageLimit)
+		}
+	}
+	if got := d.ClassifySubstate("❯ what does usage limit reached mean?"); got == SubstateUsageLimit {
+		t.Errorf("prompt input about the limit must not classify as %q", got)
+	}
+	live := "  ⎿  Claude usage limit reached. Your limit will reset at 5pm\n" +
+		"✻ Reticulating… (4s · ↑ 12 tokens · ctrl+c to interrupt)"
+	if got := d.ClassifySubstate(live); got != SubstateRunning {
+		t.Errorf("a live busy cue must win over a stale usage-limit notice, got %q", got)
+	}
+}
+
 // Empty / unrecognized content yields SubstateNone.
 func TestClassifySubstate_None(t *testing.T) {
 	d := NewPromptDetector("claude")
@@ -13,6 +13,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -27,6 +28,7 @@ import (
 	"github.com/asheshgoplani/agent-deck/internal/agentpaths"
 	"github.com/asheshgoplani/agent-deck/internal/logging"
 	"github.com/asheshgoplani/agent-deck/internal/platform"
+	"github.com/asheshgoplani/agent-deck/internal/tmux/faketmux"
 	dark "github.com/thiagokokada/dark-mode-go"
 )
 
@@ -741,7 +743,7 @@ func SupportsHyperlinks() bool {
 }
 
 // Tool detection patterns (used by DetectTool for initial tool identification)
-var toolDetectionOrder = []string{"claude", "gemini", "opencode", "codex", "copilot", "crush", "cursor", "hermes", "pi"}
+var toolDetectionOrder = []string{"claude", "gemini", "opencode", "codex", "copilot", "crush", "cursor", "hermes", "aider", "goose", "pi"}
 
 var toolDetectionPatterns = map[string][]*regexp.Regexp{
 	"claude": {
@@ -780,6 +782,16 @@ var toolDetectionPatterns = map[string][]*regexp.Regexp{
 		regexp.MustCompile(`(?i)\bhermes\s+agent\b`),
 		regexp.MustCompile(`(?i)\bnous\s*research\b`),
 	},
+	"aider": {
+		// Aider's startup banner ("Aider v0.86.1") and its help hint.
+		regexp.MustCompile(`\bAider v\d+\.\d+`),
+		regexp.MustCompile(`(?i)use /help <question> for help`),
+	},
+	"goose": {
+		// Block's goose CLI: the "( O)>" prompt and the session banner.
+		regexp.MustCompile(`\( O\)>`),
+		regexp.MustCompile(`(?i)\bgoose is running\b`),
+	},
 	"pi": {
 		regexp.MustCompile(`(?mi)^\s*pi>\s*`),
 		regexp.MustCompile(`(?i)\bpi\s+cli\b`),
@@ -818,6 +830,10 @@ func detectToolFromCommand(command string) string {
 			return "cursor"
 		case "hermes":
 			return "hermes"
+		case "aider":
+			return "aider"
+		case "goose":
+			return "goose"
 		case "pi":
 			return "pi"
 		}
@@ -840,6 +856,10 @@ func detectToolFromCommand(command string) string {
 		return "cursor"
 	case strings.Contains(cmdLower, "hermes"):
 		return "hermes"
+	case strings.Contains(cmdLower, "aider"):
+		return "aider"
+	case strings.Contains(cmdLower, "goose"):
+		return "goose"
 	case strings.Contains(cmdLower, " pi ") || strings.HasPrefix(cmdLower, "pi "):
 		return "pi"
 	default:
@@ -984,6 +1004,21 @@ type Session struct {
 	// phase 1 and Instance.TmuxSocketName. Never mutate after Start().
 	SocketName string
 
+	// Panes are the extra panes/windows opened next to the agent at Start
+	// (see layout.go). Empty for a single-pane session.
+	Panes []PaneSpec
+
+	// Environment is set on the tmux session right after it is created, so
+	// the layout's panes and any pane opened later inherit it. The agent's
+	// own command gets its environment from the command string.
+	Environment map[string]string
+
+	// agentPane is the agent's pane id in a multi-pane session, looked up
+	// once (agentPaneLoaded) and guarded by layoutMu.
+	layoutMu        sync.Mutex
+	agentPane       string
+	agentPaneLoaded bool
+
 	// mu protects all mutable fields below from concurrent access
 	mu sync.Mutex
 
@@ -1038,6 +1073,11 @@ type Session struct {
 	// Example: {"allow-passthrough": "all", "history-limit": "50000"}
 	OptionOverrides map[string]string
 
+	// cosmeticBatch, when set, receives Start's cosmetic options (status bar,
+	// terminal title, Ctrl+Q detach binding) instead of applying them inline.
+	// See DeferCosmeticOptions.
+	cosmeticBatch *OptionBatch
+
 	// RunCommandAsInitialProcess launches Start(command) as the pane's initial
 	// process instead of sending it via SendKeysAndEnter after session creation.
 	// Sandbox sessions enable this so pane-dead detection can restart exited tools.
@@ -1182,6 +1222,11 @@ const (
 // through to the legacy LaunchInUserScope path — callers populate
 // LaunchAs from TmuxSettings.GetLaunchAs which already canonicalises.
 func (s *Session) resolveLaunchMode() string {
+	// The simulated server (AGENTDECK_FAKE_TMUX) has no process to place in
+	// a systemd unit, and a transient unit would not inherit the shim PATH.
+	if faketmux.Active() {
+		return launchModeDirect
+	}
 	switch strings.ToLower(strings.TrimSpace(s.LaunchAs)) {
 	case "service":
 		return launchModeService
@@ -1562,13 +1607,16 @@ func LogDir() string {
 	return logDir
 }
 
+// GenerateSessionName returns a fresh tmux session name for a display name:
+// the agent-deck prefix, the sanitized name and a unique suffix.
+func GenerateSessionName(displayName string) string {
+	return SessionPrefix + sanitizeName(displayName) + "_" + generateShortID()
+}
+
 // NewSession creates a new Session instance with a unique name
 func NewSession(name, workDir string) *Session {
-	sanitized := sanitizeName(name)
-	// Add unique suffix to prevent name collisions
-	uniqueSuffix := generateShortID()
 	return &Session{
-		Name:                  SessionPrefix + sanitized + "_" + uniqueSuffix,
+		Name:                  GenerateSessionName(name),
 		DisplayName:           name,
 		WorkDir:               workDir,
 		Created:               time.Now(),
@@ -1802,7 +1850,7 @@ func KillSessionsWithEnvValue(envKey, envValue, excludeName string) {
 					slog.String("env_key", envKey),
 					slog.String("env_value", envValue),
 					slog.String("kept", excludeName))
-				_ = tmuxExec(socket, "kill-session", "-t", name).Run()
+				_ = runTmux(socket, "kill-session", "-t", name)
 			}
 		}
 	}
@@ -1820,9 +1868,18 @@ func generateShortID() string {
 
 // SetEnvironment sets an environment variable for this tmux session
 func (s *Session) SetEnvironment(key, value string) error {
+	return s.setEnvironment(key, "set-environment", "-t", s.Name, key, value)
+}
+
+// UnsetEnvironment removes an environment variable from this tmux session.
+func (s *Session) UnsetEnvironment(key string) error {
+	return s.setEnvironment(key, "set-environment", "-u", "-t", s.Name, key)
+}
+
+func (s *Session) setEnvironment(key string, args ...string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
-	cmd := s.tmuxCmdContext(ctx, "set-environment", "-t", s.Name, key, value)
+	cmd := s.tmuxCmdContext(ctx, args...)
 	// CombinedOutput (not Run) so tmux stderr is folded into the error. A bare
 	// "exit status 1" is useless for diagnosing a wedged server (#1579): the
 	// real cause ("no server running on ...", "can't find session") lives on
@@ -1843,6 +1900,22 @@ func (s *Session) SetEnvironment(key, value string) error {
 	return err
 }
 
+// Rename renames the live tmux session to newName and points s at it.
+func (s *Session) Rename(newName string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	out, err := s.tmuxCmdContext(ctx, "rename-session", "-t", s.Name, newName).CombinedOutput()
+	if err != nil {
+		if trimmed := strings.TrimSpace(string(out)); trimmed != "" {
+			return fmt.Errorf("%w: %s", err, trimmed)
+		}
+		return err
+	}
+	s.Name = newName
+	s.invalidateCache()
+	return nil
+}
+
 func (s *Session) ApplyThemeOptions() error {
 	themeStyle := currentTmuxThemeStyle()
 	var args []string
@@ -2061,6 +2134,7 @@ func (s *Session) Start(command string) error {
 
 	s.Command = command
 	s.invalidateCache()
+	s.resetAgentPane()
 	s.Created = time.Now()
 	s.startupAt = s.Created
 	s.mu.Lock()
@@ -2073,8 +2147,7 @@ func (s *Session) Start(command string) error {
 	// Check if session already exists (shouldn't happen with unique IDs, but handle gracefully)
 	if s.Exists() {
 		// Session with this exact name exists - regenerate with new unique suffix
-		sanitized := sanitizeName(s.DisplayName)
-		s.Name = SessionPrefix + sanitized + "_" + generateShortID()
+		s.Name = GenerateSessionName(s.DisplayName)
 	}
 
 	// Ensure working directory exists
@@ -2245,34 +2318,30 @@ func (s *Session) Start(command string) error {
 	if _, ok := s.OptionOverrides["aggressive-resize"]; !ok {
 		startArgs = append(startArgs, ";", "set-window-option", "-t", s.Name, "aggressive-resize", "on")
 	}
+	// Apply user-specified tmux option overrides from config (after defaults)
+	// in the same invocation, so they still take precedence.
+	startArgs = append(startArgs, s.optionOverrideArgs()...)
+	startArgs = append(startArgs, s.environmentArgs()...)
 	_ = s.tmuxCmd(startArgs...).Run()
 
-	// Bind Ctrl+Q to detach at the tmux level as fallback for terminals where
-	// XON/XOFF flow control intercepts the key before it reaches the PTY stdin
-	// reader (e.g. iTerm2 on macOS). Only binds on agentdeck-managed sessions.
-	_ = s.tmuxCmd("bind-key", "-n", "-T", "root", "C-q",
-		"if-shell", fmt.Sprintf("[ \"#{session_name}\" = \"%s\" ]", s.Name),
-		"detach-client", "").Run()
-
-	// Apply user-specified tmux option overrides from config (after defaults).
-	// These are batched into a single call when multiple overrides are present.
-	if len(s.OptionOverrides) > 0 {
-		args := make([]string, 0, len(s.OptionOverrides)*6)
-		first := true
-		for key, value := range s.OptionOverrides {
-			if !first {
-				args = append(args, ";")
-			}
-			args = append(args, "set-option", "-t", s.Name, "-q", key, value)
-			first = false
+	// Cosmetic options (detach binding, status bar, terminal title) go out in
+	// one more call — or into the caller's batch when many sessions are being
+	// started at once.
+	if cosmetic := s.cosmeticOptionArgs(); len(cosmetic) > 0 {
+		if s.cosmeticBatch != nil {
+			s.cosmeticBatch.Add(s.SocketName, cosmetic)
+		} else {
+			// Bounded — see tmuxPollTimeout.
+			_ = s.runBoundedRun(cosmetic...)
 		}
-		_ = s.tmuxCmd(args...).Run()
 	}
 
-	// Configure status bar with session info for easy identification
-	// Shows: session title on left, project folder on right
-	s.ConfigureStatusBar()
-	s.ConfigureTerminalTitle()
+	// Open the layout's extra panes before anything targets the agent pane.
+	if len(s.Panes) > 0 {
+		if err := s.applyLayout(workDir); err != nil {
+			statusLog.Warn("layout_apply_failed", slog.String("session", s.Name), slog.String("error", err.Error()))
+		}
+	}
 
 	// Wait for the pane shell to be ready before sending the command via send-keys.
 	// On WSL/Linux non-interactive contexts, pane initialisation can take 100-500ms and
@@ -2441,6 +2510,113 @@ func (s *Session) IsPaneDead() bool {
 	return strings.TrimSpace(string(out)) == "1"
 }
 
+// PaneDeadStatus returns the exit status tmux recorded for the session's
+// primary pane (#{pane_dead_status}). It is only meaningful for a dead pane
+// kept around by remain-on-exit; ok is false when the pane is alive, the
+// status is unavailable, or the bounded probe fails.
+func (s *Session) PaneDeadStatus() (code int, ok bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), hasSessionProbeTimeout)
+	defer cancel()
+	out, err := s.tmuxCmdContext(ctx, "list-panes", "-t", s.Name+":0.0", "-F", "#{pane_dead}"+tmuxFieldSep+"#{pane_dead_status}").Output()
+	if err != nil {
+		return 0, false
+	}
+	return parsePaneDeadStatus(string(out))
+}
+
+// parsePaneDeadStatus parses the "#{pane_dead}<sep>#{pane_dead_status}" probe.
+// A live pane, or a dead one whose status tmux did not record, reports false.
+func parsePaneDeadStatus(output string) (int, bool) {
+	dead, status, found := strings.Cut(strings.TrimSpace(output), tmuxFieldSep)
+	if !found || dead != "1" {
+		return 0, false
+	}
+	code, err := strconv.Atoi(strings.TrimSpace(status))
+	if err != nil {
+		return 0, false
+	}
+	return code, true
+}
+
+// PaneCurrentCommand returns the foreground command of the session's primary
+// pane (tmux #{pane_current_command}), or "" when it cannot be determined.
+// Like IsPaneDead it prefers the per-tick pane cache and falls back to a
+// bounded direct query.
+func (s *Session) PaneCurrentCommand() string {
+	if info, ok := GetCachedPaneInfo(s.Name); ok {
+		return info.CurrentCommand
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), hasSessionProbeTimeout)
+	defer cancel()
+	out, err := s.tmuxCmdContext(ctx, "list-panes", "-t", s.Name+":0.0", "-F", "#{pane_current_command}").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// DeferCosmeticOptions routes the cosmetic options applied by Start into
+// batch instead of a per-session tmux call; the caller must Flush the batch
+// once its sessions have started. Pass nil to apply them inline again.
+func (s *Session) DeferCosmeticOptions(batch *OptionBatch) {
+	s.cosmeticBatch = batch
+}
+
+// optionOverrideArgs returns ";"-prefixed set-option commands for the
+// user-specified OptionOverrides, in key order so the argv is deterministic.
+func (s *Session) optionOverrideArgs() []string {
+	if len(s.OptionOverrides) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(s.OptionOverrides))
+	for key := range s.OptionOverrides {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	args := make([]string, 0, len(keys)*7)
+	for _, key := range keys {
+		args = append(args, ";", "set-option", "-t", s.Name, "-q", key, s.OptionOverrides[key])
+	}
+	return args
+}
+
+// environmentArgs chains set-environment for s.Environment, in key order.
+func (s *Session) environmentArgs() []string {
+	if len(s.Environment) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(s.Environment))
+	for key := range s.Environment {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	args := make([]string, 0, len(keys)*6)
+	for _, key := range keys {
+		args = append(args, ";", "set-environment", "-t", s.Name, key, s.Environment[key])
+	}
+	return args
+}
+
+// cosmeticOptionArgs chains the options Start applies after the essential
+// ones: the Ctrl+Q detach binding, the status bar and the terminal title.
+// None affect the running tool, so they are safe to defer to an OptionBatch.
+func (s *Session) cosmeticOptionArgs() []string {
+	// Bind Ctrl+Q to detach at the tmux level as fallback for terminals where
+	// XON/XOFF flow control intercepts the key before it reaches the PTY stdin
+	// reader (e.g. iTerm2 on macOS). Only binds on agentdeck-managed sessions.
+	args := []string{"bind-key", "-n", "-T", "root", "C-q",
+		"if-shell", fmt.Sprintf("[ \"#{session_name}\" = \"%s\" ]", s.Name),
+		"detach-client", ""}
+	// Status bar shows session info for easy identification.
+	if status := s.buildStatusBarArgs(); len(status) > 0 {
+		args = append(append(args, ";"), status...)
+	}
+	if title := s.buildTerminalTitleArgs(); len(title) > 0 {
+		args = append(append(args, ";"), title...)
+	}
+	return args
+}
+
 // buildStatusBarArgs returns the tmux command args for configuring the status bar.
 // Returns nil if status bar injection is disabled.
 // Skips any option key that exists in s.OptionOverrides — user-defined options take precedence.
@@ -2575,8 +2751,7 @@ func (s *Session) EnableMouseMode() error {
 	if s.mouse {
 		// CRITICAL: Mouse mode must succeed - keep as separate call for error handling
 		// This is the only essential feature; all others are enhancements
-		mouseCmd := s.tmuxCmd("set-option", "-t", s.Name, "mouse", "on")
-		if err := mouseCmd.Run(); err != nil {
+		if err := runTmux(s.SocketName, "set-option", "-t", s.Name, "mouse", "on"); err != nil {
 			return err
 		}
 	}
@@ -2602,10 +2777,9 @@ func (s *Session) EnableMouseMode() error {
 	// #1625: gate the key-handling defaults through OptionOverrides so an explicit
 	// user tmux setting wins (mirrors Start; see gatedTmuxKeyOptionArgs).
 	enhanceArgs = append(enhanceArgs, gatedTmuxKeyOptionArgs(s.Name, s.OptionOverrides)...)
-	enhanceCmd := s.tmuxCmd(enhanceArgs...)
 	// Ignore errors - all these are non-fatal enhancements
 	// Older tmux versions may not support some options
-	_ = enhanceCmd.Run()
+	_ = runTmux(s.SocketName, enhanceArgs...)
 
 	return nil
 }
@@ -2631,8 +2805,7 @@ func (s *Session) Kill() error {
 	}
 
 	// Kill the tmux session
-	cmd := s.tmuxCmd("kill-session", "-t", s.Name)
-	err := cmd.Run()
+	err := runTmux(s.SocketName, "kill-session", "-t", s.Name)
 
 	// Verify old processes are dead; escalate to SIGKILL if needed
 	if len(oldPIDs) > 0 {
@@ -2657,6 +2830,9 @@ func (s *Session) Kill() error {
 // Used before respawn to track processes that must die.
 func (s *Session) getPaneProcessTree() (panePID int, allPIDs []int) {
 	target := s.Name + ":"
+	if id := s.agentPaneID(); id != "" {
+		target = id
+	}
 	out, err := s.tmuxCmd("list-panes", "-t", target, "-F", "#{pane_pid}").Output()
 	if err != nil {
 		return 0, nil
@@ -2825,6 +3001,9 @@ func (s *Session) RespawnPane(command string) error {
 	// -t: Target pane (session:window.pane format, use session: for active pane)
 	// command: New command to run
 	target := s.Name + ":" // Append colon to target the active pane
+	if id := s.agentPaneID(); id != "" {
+		target = id // Multi-pane session: respawn only the agent's pane
+	}
 	args := []string{"respawn-pane", "-k", "-t", target}
 	if command != "" {
 		wrapped, wrapErr := wrapRespawnCommand(command)
@@ -2839,13 +3018,19 @@ func (s *Session) RespawnPane(command string) error {
 	}
 
 	mcpLog.Debug("respawn_pane_executing", slog.Any("args", args))
-	cmd := s.tmuxCmd(args...)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		mcpLog.Debug("respawn_pane_error", slog.String("error", err.Error()), slog.String("output", string(output)))
-		return fmt.Errorf("failed to respawn pane: %w (output: %s)", err, string(output))
+	if handled, err := runViaControlPipe(s.SocketName, args); handled {
+		if err != nil {
+			mcpLog.Debug("respawn_pane_error", slog.String("error", err.Error()))
+			return fmt.Errorf("failed to respawn pane: %w", err)
+		}
+	} else {
+		output, err := s.tmuxCmd(args...).CombinedOutput()
+		if err != nil {
+			mcpLog.Debug("respawn_pane_error", slog.String("error", err.Error()), slog.String("output", string(output)))
+			return fmt.Errorf("failed to respawn pane: %w (output: %s)", err, string(output))
+		}
+		mcpLog.Debug("respawn_pane_output", slog.String("output", string(output)))
 	}
-	mcpLog.Debug("respawn_pane_output", slog.String("output", string(output)))
 
 	// Get the NEW pane PID so we don't accidentally kill the fresh process
 	newPanePID, _ := s.getPaneProcessTree()
@@ -2952,6 +3137,8 @@ func (s *Session) CapturePane() (string, error) {
 	s.cacheMu.RUnlock()
 
 	// Slow path: deduplicate concurrent calls via singleflight.
+	_, span := logging.StartSpan(context.Background(), "capture_pane", slog.String("session", s.Name))
+	defer span.End()
 	v, err, _ := s.captureSf.Do("capture", func() (interface{}, error) {
 		// Double-check cache inside singleflight
 		s.cacheMu.RLock()
@@ -2965,11 +3152,12 @@ func (s *Session) CapturePane() (string, error) {
 		// Try control mode pipe first (zero subprocess)
 		if pm := GetPipeManager(); pm != nil {
 			pipeStart := time.Now()
-			if content, pipeErr := pm.CapturePane(s.Name); pipeErr == nil {
+			if content, pipeErr := pm.CapturePaneTarget(s.Name, s.paneTarget()); pipeErr == nil {
 				s.cacheMu.Lock()
 				s.cacheContent = content
 				s.cacheTime = time.Now()
 				s.cacheMu.Unlock()
+				span.SetAttrs(slog.String("source", "pipe"))
 				logging.Aggregate(logging.CompPerf, "capture_pane_pipe",
 					slog.String("session", s.Name),
 					slog.Duration("elapsed", time.Since(pipeStart)))
@@ -2982,11 +3170,12 @@ func (s *Session) CapturePane() (string, error) {
 		}
 
 		// Subprocess fallback: 3s timeout
+		span.SetAttrs(slog.String("source", "subprocess"))
 		finish := logging.TraceOp(perfLog, "capture_pane_subprocess", 200*time.Millisecond,
 			slog.String("session", s.Name))
 		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 		defer cancel()
-		cmd := s.tmuxCmdContext(ctx, "capture-pane", "-t", s.Name, "-p", "-e")
+		cmd := s.tmuxCmdContext(ctx, "capture-pane", "-t", s.paneTarget(), "-p", "-e")
 		output, err := cmd.Output()
 		finish()
 		if err != nil {
@@ -3023,11 +3212,13 @@ func (s *Session) CapturePane() (string, error) {
 // cache to provide a fresh snapshot. Use this for send verification where
 // stale pane content can hide unsent composer input.
 func (s *Session) CapturePaneFresh() (string, error) {
+	_, span := logging.StartSpan(context.Background(), "capture_pane_fresh", slog.String("session", s.Name))
+	defer span.End()
 	s.invalidateCache()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
-	cmd := s.tmuxCmdContext(ctx, "capture-pane", "-t", s.Name, "-p", "-e")
+	cmd := s.tmuxCmdContext(ctx, "capture-pane", "-t", s.paneTarget(), "-p", "-e")
 	output, err := cmd.Output()
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
@@ -3049,7 +3240,7 @@ func (s *Session) CapturePaneFresh() (string, error) {
 func (s *Session) CaptureFullHistory() (string, error) {
 	// Limit to last 2000 lines to balance content availability with memory usage
 	// AI agent conversations can be long - 2000 lines captures ~40-80 screens of content
-	cmd := s.tmuxCmd("capture-pane", "-t", s.Name, "-p", "-e", "-S", "-2000")
+	cmd := s.tmuxCmd("capture-pane", "-t", s.paneTarget(), "-p", "-e", "-S", "-2000")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to capture history: %w", err)
@@ -3072,7 +3263,7 @@ func (s *Session) CaptureHistoryLines(n int) (string, error) {
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	cmd := s.tmuxCmdContext(ctx, "capture-pane", "-t", s.Name, "-p", "-e", "-S", fmt.Sprintf("-%d", n))
+	cmd := s.tmuxCmdContext(ctx, "capture-pane", "-t", s.paneTarget(), "-p", "-e", "-S", fmt.Sprintf("-%d", n))
 	output, err := cmd.Output()
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
@@ -3357,146 +3548,9 @@ func (s *Session) GetStatus() (string, error) {
 			// No previous state, fall through to default logic
 			statusLog.Debug("capture_timeout_no_previous", slog.String("session", shortName))
 		} else if err == nil {
-			s.ensureStateTrackerLocked()
-
-			// Honest Status v2: compute the additive substate from the content we
-			// already captured (pure string ops; no extra pane capture). This
-			// keeps lastSubstate fresh for the reporting layers.
-			s.lastSubstate = s.classifySubstate(content)
-
-			// Honest Status v2: a model-unavailable no-op loop ("X is currently
-			// unavailable" / "Crunched for 0s") is the Fable-down case that this
-			// feature exists to surface. It must short-circuit to "error" BEFORE
-			// the busy check: the "✶ Crunched for 0s" completion line carries a
-			// decorative asterisk that hasBusyIndicator would otherwise misread
-			// as an active spinner and report "running" — the exact false-alive
-			// this feature fixes. classifySubstate already excluded a real
-			// (non-zero) crunch, so only the genuine no-op reaches here.
-			if s.lastSubstate == SubstateModelUnavailable {
-				s.resetPromptNoBusyHoldLocked()
-				s.lastStableStatus = "error"
-				s.startupAt = time.Time{}
-				statusLog.Debug("model_unavailable_noop", slog.String("session", shortName))
-				return "error", nil
-			}
-
-			// A TERMINAL auth/connection-failure banner (#1400) routes to "error"
-			// BEFORE the busy check: a real 401 stops the spinner, so a stale busy
-			// glyph lingering in the same window must not mask the failure as
-			// "running". hasErrorBannerIndicator already EXCLUDES the in-flight
-			// retry case (rendered behind the "⎿" tool-result connector with a
-			// live spinner), so a session that is genuinely retrying is NOT
-			// matched here and still reaches the busy check below — preserving
-			// #1400's "a retry in progress is still working" intent. The substate
-			// (in s.lastSubstate) names WHICH failure for the TUI glyph.
-			if s.hasErrorBannerIndicator(content) {
-				s.resetPromptNoBusyHoldLocked()
-				s.lastStableStatus = "error"
-				s.startupAt = time.Time{}
-				statusLog.Debug("error_banner_detected", slog.String("session", shortName), slog.String("substate", string(s.lastSubstate)))
-				return "error", nil
-			}
-
-			// Check for explicit busy indicator (spinner, "ctrl+c to interrupt")
-			isExplicitlyBusy := s.hasBusyIndicator(content)
-			// Debug: show last line of content for this session
-			lines := strings.Split(content, "\n")
-			lastLine := ""
-			for i := len(lines) - 1; i >= 0; i-- {
-				if strings.TrimSpace(lines[i]) != "" {
-					lastLine = lines[i]
-					if len(lastLine) > 60 {
-						lastLine = lastLine[:60] + "..."
-					}
-					break
-				}
-			}
-			statusLog.Debug("needs_busy_check", slog.String("session", shortName), slog.Bool("busy", isExplicitlyBusy), slog.String("last_line", lastLine))
-
-			// Check for prompt indicators (AskUserQuestion, permission dialogs, etc.)
-			// BUSY indicator is AUTHORITATIVE: if spinner is active (or in grace period),
-			// return GREEN immediately. Prompt detection must NOT override this because
-			// the ❯ prompt from the user's previous input is always visible and causes
-			// false "waiting" detection during tool transitions.
-			if isExplicitlyBusy {
-				s.stateTracker.lastChangeTime = time.Now()
-				s.stateTracker.realActivityConfirmed = true
-				s.stateTracker.acknowledged = false
-				s.resetPromptNoBusyHoldLocked()
-				s.stateTracker.lastActivityTimestamp = currentTS
-				s.lastStableStatus = "active"
-				s.startupAt = time.Time{}
-				statusLog.Debug("busy_indicator_active", slog.String("session", shortName))
-				return "active", nil
+			if status, ok := s.statusFromContentLocked(content, currentTS, shortName); ok {
+				return status, nil
 			}
-
-			// Foreground turn ended but background work is still in flight: a
-			// run_in_background shell, or a background agent the turn is awaiting.
-			// Claude shows this at the prompt ("N shells still running" /
-			// "Waiting for N background agent to finish") with no spinner, so the
-			// busy check above misses it and the session would flip to waiting
-			// (yellow) and fire a premature "finished" notification. Keep it green
-			// until the work actually completes (then the next poll settles to
-			// waiting and notifies — "done" now means foreground AND background).
-			if s.markBackgroundWorkActiveLocked(content, currentTS, shortName) {
-				return "active", nil
-			}
-
-			// Update content hash for spike detection (deferred until after early return above).
-			// The 500ms CapturePane cache means the spike path gets the same content,
-			// so we store the normalized result once and reuse it via cachedNormContent.
-			cleanContent := s.normalizeContent(content)
-			currentHash := s.hashContent(cleanContent)
-			if currentHash != "" {
-				// Keep the content hash for diagnostics/fallback logic only.
-				// Do NOT clear acknowledgment on hash changes: dynamic footer text
-				// (timers, context counters, redraws) can mutate content without any
-				// real new work and causes idle -> waiting flapping.
-				s.stateTracker.lastHash = currentHash
-			}
-
-			// (Auth/connection-failure banners and the model-unavailable no-op
-			// already routed to "error" above, before the busy check, so by here
-			// the session is neither wedged nor busy.)
-
-			// Not busy. Check for prompt indicators to distinguish YELLOW vs fall-through.
-			hasPrompt := s.hasPromptIndicator(content)
-			if hasPrompt {
-				// Respect acknowledgment: if user already acknowledged (e.g. by attaching),
-				// keep idle status. The prompt is still visible but the user is looking at it.
-				if s.stateTracker.acknowledged {
-					s.resetPromptNoBusyHoldLocked()
-					s.lastStableStatus = "idle"
-					s.startupAt = time.Time{}
-					statusLog.Debug("prompt_detected_idle", slog.String("session", shortName))
-					return "idle", nil
-				}
-				if s.shouldHoldActiveOnPromptLocked() {
-					s.startupAt = time.Time{}
-					statusLog.Debug("prompt_no_busy_hold_active",
-						slog.String("session", shortName),
-						slog.Int("count", s.stateTracker.promptNoBusyCount))
-					return "active", nil
-				}
-				s.resetPromptNoBusyHoldLocked()
-				if s.lastStableStatus != "waiting" {
-					s.stateTracker.waitingSince = time.Now()
-				}
-				s.lastStableStatus = "waiting"
-				s.startupAt = time.Time{}
-				statusLog.Debug("prompt_detected_waiting", slog.String("session", shortName))
-				return "waiting", nil
-			}
-
-			// During startup there may be a long period with neither spinner nor prompt.
-			// Keep this as STARTING to avoid premature waiting/idle transitions.
-			if s.inStartupWindowLocked() {
-				s.resetPromptNoBusyHoldLocked()
-				s.lastStableStatus = "starting"
-				statusLog.Debug("startup_no_prompt_or_busy", slog.String("session", shortName))
-				return "starting", nil
-			}
-			s.resetPromptNoBusyHoldLocked()
 		}
 	}
 
@@ -3779,6 +3833,157 @@ func (s *Session) GetStatus() (string, error) {
 	return "waiting", nil
 }
 
+// statusFromContentLocked classifies a freshly captured, ANSI-stripped pane
+// for GetStatus: model-unavailable substate and error banners → "error",
+// busy indicator or background work → "active", prompt → "waiting" (or
+// "idle" once acknowledged), startup window → "starting". ok is false when
+// the content carries none of those signals and GetStatus falls back to
+// activity-timestamp tracking. ReplaySnapshot drives the same path for
+// recorded fixtures. Caller must hold s.mu.
+func (s *Session) statusFromContentLocked(content string, currentTS int64, shortName string) (string, bool) {
+	s.ensureStateTrackerLocked()
+
+	// Honest Status v2: compute the additive substate from the content we
+	// already captured (pure string ops; no extra pane capture). This
+	// keeps lastSubstate fresh for the reporting layers.
+	s.lastSubstate = s.classifySubstate(content)
+
+	// Honest Status v2: a model-unavailable no-op loop ("X is currently
+	// unavailable" / "Crunched for 0s") is the Fable-down case that this
+	// feature exists to surface. It must short-circuit to "error" BEFORE
+	// the busy check: the "✶ Crunched for 0s" completion line carries a
+	// decorative asterisk that hasBusyIndicator would otherwise misread
+	// as an active spinner and report "running" — the exact false-alive
+	// this feature fixes. classifySubstate already excluded a real
+	// (non-zero) crunch, so only the genuine no-op reaches here.
+	if s.lastSubstate == SubstateModelUnavailable {
+		s.resetPromptNoBusyHoldLocked()
+		s.lastStableStatus = "error"
+		s.startupAt = time.Time{}
+		statusLog.Debug("model_unavailable_noop", slog.String("session", shortName))
+		return "error", true
+	}
+
+	// A TERMINAL auth/connection-failure banner (#1400) routes to "error"
+	// BEFORE the busy check: a real 401 stops the spinner, so a stale busy
+	// glyph lingering in the same window must not mask the failure as
+	// "running". hasErrorBannerIndicator already EXCLUDES the in-flight
+	// retry case (rendered behind the "⎿" tool-result connector with a
+	// live spinner), so a session that is genuinely retrying is NOT
+	// matched here and still reaches the busy check below — preserving
+	// #1400's "a retry in progress is still working" intent. The substate
+	// (in s.lastSubstate) names WHICH failure for the TUI glyph.
+	if s.hasErrorBannerIndicator(content) {
+		s.resetPromptNoBusyHoldLocked()
+		s.lastStableStatus = "error"
+		s.startupAt = time.Time{}
+		statusLog.Debug("error_banner_detected", slog.String("session", shortName), slog.String("substate", string(s.lastSubstate)))
+		return "error", true
+	}
+
+	// Check for explicit busy indicator (spinner, "ctrl+c to interrupt")
+	isExplicitlyBusy := s.hasBusyIndicator(content)
+	// Debug: show last line of content for this session
+	lines := strings.Split(content, "\n")
+	lastLine := ""
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.TrimSpace(lines[i]) != "" {
+			lastLine = lines[i]
+			if len(lastLine) > 60 {
+				lastLine = lastLine[:60] + "..."
+			}
+			break
+		}
+	}
+	statusLog.Debug("needs_busy_check", slog.String("session", shortName), slog.Bool("busy", isExplicitlyBusy), slog.String("last_line", lastLine))
+
+	// Check for prompt indicators (AskUserQuestion, permission dialogs, etc.)
+	// BUSY indicator is AUTHORITATIVE: if spinner is active (or in grace period),
+	// return GREEN immediately. Prompt detection must NOT override this because
+	// the ❯ prompt from the user's previous input is always visible and causes
+	// false "waiting" detection during tool transitions.
+	if isExplicitlyBusy {
+		s.stateTracker.lastChangeTime = time.Now()
+		s.stateTracker.realActivityConfirmed = true
+		s.stateTracker.acknowledged = false
+		s.resetPromptNoBusyHoldLocked()
+		s.stateTracker.lastActivityTimestamp = currentTS
+		s.lastStableStatus = "active"
+		s.startupAt = time.Time{}
+		statusLog.Debug("busy_indicator_active", slog.String("session", shortName))
+		return "active", true
+	}
+
+	// Foreground turn ended but background work is still in flight: a
+	// run_in_background shell, or a background agent the turn is awaiting.
+	// Claude shows this at the prompt ("N shells still running" /
+	// "Waiting for N background agent to finish") with no spinner, so the
+	// busy check above misses it and the session would flip to waiting
+	// (yellow) and fire a premature "finished" notification. Keep it green
+	// until the work actually completes (then the next poll settles to
+	// waiting and notifies — "done" now means foreground AND background).
+	if s.markBackgroundWorkActiveLocked(content, currentTS, shortName) {
+		return "active", true
+	}
+
+	// Update content hash for spike detection (deferred until after early return above).
+	// The 500ms CapturePane cache means the spike path gets the same content,
+	// so we store the normalized result once and reuse it via cachedNormContent.
+	cleanContent := s.normalizeContent(content)
+	currentHash := s.hashContent(cleanContent)
+	if currentHash != "" {
+		// Keep the content hash for diagnostics/fallback logic only.
+		// Do NOT clear acknowledgment on hash changes: dynamic footer text
+		// (timers, context counters, redraws) can mutate content without any
+		// real new work and causes idle -> waiting flapping.
+		s.stateTracker.lastHash = currentHash
+	}
+
+	// (Auth/connection-failure banners and the model-unavailable no-op
+	// already routed to "error" above, before the busy check, so by here
+	// the session is neither wedged nor busy.)
+
+	// Not busy. Check for prompt indicators to distinguish YELLOW vs fall-through.
+	hasPrompt := s.hasPromptIndicator(content)
+	if hasPrompt {
+		// Respect acknowledgment: if user already acknowledged (e.g. by attaching),
+		// keep idle status. The prompt is still visible but the user is looking at it.
+		if s.stateTracker.acknowledged {
+			s.resetPromptNoBusyHoldLocked()
+			s.lastStableStatus = "idle"
+			s.startupAt = time.Time{}
+			statusLog.Debug("prompt_detected_idle", slog.String("session", shortName))
+			return "idle", true
+		}
+		if s.shouldHoldActiveOnPromptLocked() {
+			s.startupAt = time.Time{}
+			statusLog.Debug("prompt_no_busy_hold_active",
+				slog.String("session", shortName),
+				slog.Int("count", s.stateTracker.promptNoBusyCount))
+			return "active", true
+		}
+		s.resetPromptNoBusyHoldLocked()
+		if s.lastStableStatus != "waiting" {
+			s.stateTracker.waitingSince = time.Now()
+		}
+		s.lastStableStatus = "waiting"
+		s.startupAt = time.Time{}
+		statusLog.Debug("prompt_detected_waiting", slog.String("session", shortName))
+		return "waiting", true
+	}
+
+	// During startup there may be a long period with neither spinner nor prompt.
+	// Keep this as STARTING to avoid premature waiting/idle transitions.
+	if s.inStartupWindowLocked() {
+		s.resetPromptNoBusyHoldLocked()
+		s.lastStableStatus = "starting"
+		statusLog.Debug("startup_no_prompt_or_busy", slog.String("session", shortName))
+		return "starting", true
+	}
+	s.resetPromptNoBusyHoldLocked()
+	return "", false
+}
+
 // getStatusFallback uses content-hash based detection as fallback
 // when activity timestamp detection fails
 func (s *Session) getStatusFallback() (string, error) {
@@ -4653,6 +4858,13 @@ func (s *Session) hashContent(content string) string {
 	return hex.EncodeToString(h[:])
 }
 
+// ContentHash returns the hash HasUpdated uses for change detection: content
+// is normalized first, so spinners, counters and clock ticks do not count as
+// new output.
+func (s *Session) ContentHash(content string) string {
+	return s.hashContent(s.normalizeContent(content))
+}
+
 // keySenderExec is a swappable seam for the tmux subprocesses spawned by the
 // SendKeys / SendEnter / SendNamedKey key-delivery primitives. It defaults to
 // tmuxExec (the real `tmux` binary) in production; tests override it to record
@@ -4663,7 +4875,7 @@ var keySenderExec = tmuxExec
 // SendKeys sends keys to the tmux session
 // Uses -l flag to treat keys as literal text, preventing tmux special key interpretation
 func (s *Session) SendKeys(keys string) error {
-	return s.sendKeysToTarget(s.Name, keys)
+	return s.sendKeysToTarget(s.paneTarget(), keys)
 }
 
 // windowTarget returns the tmux target addressing a specific window index
@@ -4674,15 +4886,14 @@ func (s *Session) windowTarget(windowIndex int) string {
 }
 
 // sendKeysToTarget sends literal text to an explicit tmux target — either the
-// session name (active window) or a "<session>:<windowIndex>" window target.
-// SendKeys delegates here against the active window.
+// session's agent pane (see paneTarget) or a "<session>:<windowIndex>" window
+// target. SendKeys delegates here against the agent pane.
 func (s *Session) sendKeysToTarget(target, keys string) error {
 	s.invalidateCache()
 	// The -l flag makes tmux treat the string as literal text, not key names
 	// This prevents issues like "Enter" being interpreted as the Enter key
 	// and provides a layer of safety against tmux special sequences
-	cmd := keySenderExec(s.SocketName, "send-keys", "-l", "-t", target, "--", keys)
-	return runSendKeysBounded(cmd)
+	return s.sendKeysCmd("send-keys", "-l", "-t", target, "--", keys)
 }
 
 // ensureInsertMode prepends an Escape + `i` sequence so a vim-mode composer
@@ -4691,7 +4902,7 @@ func (s *Session) sendKeysToTarget(target, keys string) error {
 // is idempotent — Escape lands in normal mode, `i` enters insert — so it is
 // safe to call when the prompt is already in insert mode. See issue #1264.
 func (s *Session) ensureInsertMode() {
-	s.ensureInsertModeOnTarget(s.Name)
+	s.ensureInsertModeOnTarget(s.paneTarget())
 }
 
 // ensureInsertModeOnTarget is ensureInsertMode against an explicit tmux target.
@@ -4700,9 +4911,9 @@ func (s *Session) ensureInsertModeOnTarget(target string) {
 		return
 	}
 	// Escape: guarantee normal mode regardless of current state.
-	_ = runSendKeysBounded(keySenderExec(s.SocketName, "send-keys", "-t", target, "Escape"))
+	_ = s.sendKeysCmd("send-keys", "-t", target, "Escape")
 	// i: enter insert mode so the following paste/Enter are taken literally.
-	_ = runSendKeysBounded(keySenderExec(s.SocketName, "send-keys", "-t", target, "i"))
+	_ = s.sendKeysCmd("send-keys", "-t", target, "i")
 }
 
 // sendEnterRaw emits a single Enter keystroke without the vim-mode insert
@@ -4710,14 +4921,13 @@ func (s *Session) ensureInsertModeOnTarget(target string) {
 // insert mode before the paste — re-escaping before the trailing Enter would
 // drop the prompt back to normal mode and swallow the submit.
 func (s *Session) sendEnterRaw() error {
-	return s.sendEnterRawToTarget(s.Name)
+	return s.sendEnterRawToTarget(s.paneTarget())
 }
 
 // sendEnterRawToTarget is sendEnterRaw against an explicit tmux target.
 func (s *Session) sendEnterRawToTarget(target string) error {
 	s.invalidateCache()
-	cmd := keySenderExec(s.SocketName, "send-keys", "-t", target, "Enter")
-	return runSendKeysBounded(cmd)
+	return s.sendKeysCmd("send-keys", "-t", target, "Enter")
 }
 
 // SendEnter sends an Enter key to the tmux session. When VimMode is set it
@@ -4747,8 +4957,7 @@ func (s *Session) OpenKeySender() (KeySender, error) {
 // Backspace, arrow keys, Tab, and Ctrl-{C,D} from the TUI to the focused pane.
 func (s *Session) SendNamedKey(key string) error {
 	s.invalidateCache()
-	cmd := keySenderExec(s.SocketName, "send-keys", "-t", s.Name, key)
-	return runSendKeysBounded(cmd)
+	return s.sendKeysCmd("send-keys", "-t", s.paneTarget(), key)
 }
 
 // SendKeysAndEnter sends literal text followed by Enter as two separate tmux
@@ -4757,7 +4966,7 @@ func (s *Session) SendNamedKey(key string) error {
 // Without the delay, Enter arrives in the same PTY buffer as the paste-end
 // marker and gets swallowed by async TUI frameworks (Ink/Node.js, curses).
 func (s *Session) SendKeysAndEnter(keys string) error {
-	return s.sendKeysAndEnterToTarget(s.Name, keys)
+	return s.sendKeysAndEnterToTarget(s.paneTarget(), keys)
 }
 
 // SendKeysAndEnterToWindow is SendKeysAndEnter aimed at a specific tmux window
@@ -4790,23 +4999,26 @@ func (s *Session) sendKeysAndEnterToTarget(target, keys string) error {
 	return s.sendEnterRawToTarget(target)
 }
 
+// SendChunkSize is the largest payload SendKeysChunked sends in one tmux
+// call; longer content is split into several.
+const SendChunkSize = 4096
+
 // SendKeysChunked sends large content to the tmux session in chunks to avoid
 // tmux/OS buffer limits. Content ≤4KB is sent directly via SendKeys.
 // Larger content is split at newline boundaries with a short delay between chunks.
 func (s *Session) SendKeysChunked(content string) error {
-	return s.sendKeysChunkedToTarget(s.Name, content)
+	return s.sendKeysChunkedToTarget(s.paneTarget(), content)
 }
 
 // sendKeysChunkedToTarget is SendKeysChunked against an explicit tmux target.
 func (s *Session) sendKeysChunkedToTarget(target, content string) error {
-	const chunkSize = 4096
 	const chunkDelay = 50 * time.Millisecond
 
-	if len(content) <= chunkSize {
+	if len(content) <= SendChunkSize {
 		return s.sendKeysToTarget(target, content)
 	}
 
-	chunks := splitIntoChunks(content, chunkSize)
+	chunks := splitIntoChunks(content, SendChunkSize)
 	for i, chunk := range chunks {
 		if err := s.sendKeysToTarget(target, chunk); err != nil {
 			return fmt.Errorf("failed to send chunk %d/%d: %w", i+1, len(chunks), err)
@@ -4818,6 +5030,11 @@ func (s *Session) sendKeysChunkedToTarget(target, content string) error {
 	return nil
 }
 
+// SendChunkCount returns how many tmux calls SendKeysChunked needs for content.
+func SendChunkCount(content string) int {
+	return len(splitIntoChunks(content, SendChunkSize))
+}
+
 // splitIntoChunks splits content into chunks of at most maxSize bytes,
 // preferring to split at newline boundaries. If a single line exceeds maxSize,
 // it is split at the byte boundary as a fallback.
@@ -4857,14 +5074,20 @@ func splitIntoChunks(content string, maxSize int) []string {
 // SendCtrlC sends Ctrl+C (interrupt signal) to the tmux session
 func (s *Session) SendCtrlC() error {
 	s.invalidateCache()
-	cmd := s.tmuxCmd("send-keys", "-t", s.Name, "C-c")
+	if handled, err := runViaControlPipe(s.SocketName, []string{"send-keys", "-t", s.paneTarget(), "C-c"}); handled {
+		return err
+	}
+	cmd := s.tmuxCmd("send-keys", "-t", s.paneTarget(), "C-c")
 	return runSendKeysBounded(cmd)
 }
 
 // SendCtrlU sends Ctrl+U (clear line) to the tmux session
 func (s *Session) SendCtrlU() error {
 	s.invalidateCache()
-	cmd := s.tmuxCmd("send-keys", "-t", s.Name, "C-u")
+	if handled, err := runViaControlPipe(s.SocketName, []string{"send-keys", "-t", s.paneTarget(), "C-u"}); handled {
+		return err
+	}
+	cmd := s.tmuxCmd("send-keys", "-t", s.paneTarget(), "C-u")
 	return runSendKeysBounded(cmd)
 }
 
@@ -5053,7 +5276,7 @@ func (s *Session) GetWorkDir() string {
 
 	// Bounded: a wedged server / destroyed target must not hang this poll (see
 	// tmuxPollTimeout). Bare .Output() here was one of the orphan-spin sources.
-	output, err := s.runBoundedOutput("display-message", "-t", s.Name, "-p", "#{pane_current_path}")
+	output, err := s.runBoundedOutput("display-message", "-t", s.paneTarget(), "-p", "#{pane_current_path}")
 	if err != nil {
 		return ""
 	}
@@ -5357,16 +5580,14 @@ func ListAgentDeckSessions() ([]string, error) {
 func SetStatusLeft(sessionName, text string) error {
 	// Escape single quotes for tmux by replacing ' with '\''
 	escaped := strings.ReplaceAll(text, "'", "'\\''")
-	cmd := tmuxExec(DefaultSocketName(), "set-option", "-t", sessionName, "status-left", escaped)
-	return cmd.Run()
+	return runTmux(DefaultSocketName(), "set-option", "-t", sessionName, "status-left", escaped)
 }
 
 // ClearStatusLeft resets status-left to default for a session.
 // Called when notifications are cleared or acknowledged.
 func ClearStatusLeft(sessionName string) error {
 	// -u flag unsets the option, reverting to tmux default
-	cmd := tmuxExec(DefaultSocketName(), "set-option", "-t", sessionName, "-u", "status-left")
-	return cmd.Run()
+	return runTmux(DefaultSocketName(), "set-option", "-t", sessionName, "-u", "status-left")
 }
 
 // savedStatusLeft holds the original global status-left value before agent-deck overwrites it.
@@ -5395,8 +5616,7 @@ func captureOriginalStatusLeft() {
 func SetStatusLeftGlobal(text string) error {
 	savedStatusLeft.Do(captureOriginalStatusLeft)
 	escaped := strings.ReplaceAll(text, "'", "'\\''")
-	cmd := tmuxExec(DefaultSocketName(), "set-option", "-g", "status-left", escaped)
-	return cmd.Run()
+	return runTmux(DefaultSocketName(), "set-option", "-g", "status-left", escaped)
 }
 
 // ClearStatusLeftGlobal restores the original global status-left value.
@@ -5407,10 +5627,39 @@ func ClearStatusLeftGlobal() error {
 	socket := DefaultSocketName()
 	if savedStatusLeft.captured {
 		escaped := strings.ReplaceAll(savedStatusLeft.value, "'", "'\\''")
-		return tmuxExec(socket, "set-option", "-g", "status-left", escaped).Run()
+		return runTmux(socket, "set-option", "-g", "status-left", escaped)
 	}
 	// No saved value — fall back to unset (original behavior)
-	return tmuxExec(socket, "set-option", "-gu", "status-left").Run()
+	return runTmux(socket, "set-option", "-gu", "status-left")
+}
+
+// WaitingCountOption is the global tmux user option carrying the number of
+// waiting agent-deck sessions. User options never override anything, so custom
+// status lines can embed #{@agentdeck_waiting} in any session.
+const WaitingCountOption = "@agentdeck_waiting"
+
+// statusLeftSessionsArgs builds one chained tmux invocation that sets (or, when
+// text is empty, unsets) status-left on each named session and publishes the
+// waiting count as a global user option.
+func statusLeftSessionsArgs(sessionNames []string, text string, waiting int) []string {
+	args := []string{"set-option", "-gq", WaitingCountOption, strconv.Itoa(waiting)}
+	for _, name := range sessionNames {
+		if text == "" {
+			args = append(args, ";", "set-option", "-q", "-t", name, "-u", "status-left")
+			continue
+		}
+		args = append(args, ";", "set-option", "-q", "-t", name, "status-left", text)
+	}
+	return args
+}
+
+// SetStatusLeftSessions scopes the notification bar to the given agentdeck
+// sessions (per-session status-left) instead of overriding the global value,
+// so non-agentdeck sessions keep their own status line. All sessions are
+// updated in a single tmux invocation. An empty text unsets the per-session
+// value, reverting each session to the inherited global status-left.
+func SetStatusLeftSessions(sessionNames []string, text string, waiting int) error {
+	return runTmux(DefaultSocketName(), statusLeftSessionsArgs(sessionNames, text, waiting)...)
 }
 
 // InitializeStatusBarOptions sets optimal status bar options for agent-deck.
@@ -5419,7 +5668,7 @@ func ClearStatusLeftGlobal() error {
 func InitializeStatusBarOptions() error {
 	// Set adequate status-left-length globally (default is only 10 chars!)
 	// This ensures the notification bar content is not truncated
-	return tmuxExec(DefaultSocketName(), "set-option", "-g", "status-left-length", "120").Run()
+	return runTmux(DefaultSocketName(), "set-option", "-g", "status-left-length", "120")
 }
 
 // RefreshStatusBarImmediate forces an immediate status bar redraw for ALL connected clients.
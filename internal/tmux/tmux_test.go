@@ -3300,3 +3300,19 @@ func TestGatedTmuxKeyOptionArgs(t *testing.T) {
 		t.Errorf("expected no forced args when all keys overridden, got: %s", joined(all))
 	}
 }
+
+func TestSession_Rename(t *testing.T) {
+	skipIfNoTmuxBinary(t)
+	sess := NewSession("rename-test", t.TempDir())
+	err := sess.Start("")
+	assert.NoError(t, err)
+	defer func() { _ = sess.Kill() }()
+
+	oldName := sess.Name
+	newName := GenerateSessionName("renamed test")
+	assert.True(t, strings.HasPrefix(newName, SessionPrefix+"renamed-test_"), "unexpected generated name %q", newName)
+	assert.NoError(t, sess.Rename(newName))
+	assert.Equal(t, newName, sess.Name)
+	assert.True(t, sess.Exists(), "renamed session should exist under its new name")
+	assert.False(t, (&Session{Name: oldName, SocketName: sess.SocketName}).Exists(), "old name should be gone")
+}
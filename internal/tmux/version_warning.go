@@ -68,6 +68,39 @@ func defaultTmuxVersionProbe() (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
+// Version returns the host tmux version from `tmux -V`, e.g. "3.6a".
+func Version() (string, error) {
+	raw, err := defaultTmuxVersionProbe()
+	if err != nil {
+		return "", err
+	}
+	ver := parseTmuxVersion(raw)
+	if ver == "" {
+		return "", fmt.Errorf("unrecognized `tmux -V` output %q", raw)
+	}
+	return ver, nil
+}
+
+// VersionAtLeast reports whether ver (as returned by Version) is
+// major.minor or newer. master and next builds count as newest; an
+// unparseable version as older.
+func VersionAtLeast(ver string, major, minor int) bool {
+	if ver == "master" || strings.HasPrefix(ver, "next") {
+		return true
+	}
+	m, n, _, ok := splitTmuxVersion(ver)
+	if !ok {
+		return false
+	}
+	return m > major || (m == major && n >= minor)
+}
+
+// IsVulnerableVersion reports whether ver has the unfixed control-mode
+// NULL deref that WarnIfVulnerableTmux warns about on macOS.
+func IsVulnerableVersion(ver string) bool {
+	return isVulnerableTmuxVersion(ver)
+}
+
 var tmuxVersionRE = regexp.MustCompile(`^tmux\s+(\S+)`)
 
 func parseTmuxVersion(raw string) string {
@@ -53,6 +53,28 @@ func TestParseTmuxVersion(t *testing.T) {
 	}
 }
 
+func TestVersionAtLeast(t *testing.T) {
+	cases := []struct {
+		ver  string
+		want bool
+	}{
+		{"3.2", true},
+		{"3.2a", true},
+		{"3.6a", true},
+		{"4.0", true},
+		{"3.1c", false},
+		{"2.9", false},
+		{"master", true},
+		{"next-3.7", true},
+		{"garbage", false},
+	}
+	for _, c := range cases {
+		if got := VersionAtLeast(c.ver, 3, 2); got != c.want {
+			t.Errorf("VersionAtLeast(%q, 3, 2) = %v, want %v", c.ver, got, c.want)
+		}
+	}
+}
+
 func TestDoCheckAndWarnTmuxVersion_WarnsOnVulnerableDarwin(t *testing.T) {
 	var buf bytes.Buffer
 	probe := func() (string, error) { return "tmux 3.6a", nil }
@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/terminal"
+)
+
+func writeAttachModeConfig(t *testing.T, mode string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	session.ClearUserConfigCache()
+	t.Cleanup(session.ClearUserConfigCache)
+	dir := filepath.Join(home, ".agent-deck")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	body := "[ui]\nattach_mode = \"" + mode + "\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.toml"), []byte(body), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+}
+
+func TestResolveAttachMode_ReadsConfig(t *testing.T) {
+	writeAttachModeConfig(t, "window")
+	if got := resolveAttachMode(); got != session.AttachModeWindow {
+		t.Fatalf("resolveAttachMode() = %q, want %q", got, session.AttachModeWindow)
+	}
+}
+
+func TestPaneAttachMode(t *testing.T) {
+	cases := map[string]string{
+		session.AttachModeInline: terminal.TmuxPaneSplit,
+		session.AttachModeSplit:  terminal.TmuxPaneSplit,
+		session.AttachModeWindow: terminal.TmuxPaneWindow,
+	}
+	for in, want := range cases {
+		if got := paneAttachMode(in); got != want {
+			t.Errorf("paneAttachMode(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// The attach_pane hotkey (Alt+Enter) routes the focused session to the pane
+// launcher with the session's tmux name and title, never the inline attach.
+func TestAttachPaneHotkey_DispatchesSplit(t *testing.T) {
+	writeAttachModeConfig(t, "")
+	home, inst, _ := armHomeWithOneSession(t)
+
+	var gotReq terminal.AttachRequest
+	var gotMode string
+	calls := 0
+	home.openInTmuxPaneSink = func(req terminal.AttachRequest, mode string) error {
+		calls++
+		gotReq, gotMode = req, mode
+		return nil
+	}
+
+	_, cmd := home.handleMainKey(tea.KeyMsg{Type: tea.KeyEnter, Alt: true})
+	if calls != 1 {
+		t.Fatalf("pane launcher called %d times, want 1", calls)
+	}
+	if cmd != nil {
+		t.Error("attach_pane returned a command; the deck must keep the terminal")
+	}
+	if gotMode != terminal.TmuxPaneSplit {
+		t.Errorf("mode = %q, want %q", gotMode, terminal.TmuxPaneSplit)
+	}
+	if gotReq.Name == "" || gotReq.Name != inst.GetTmuxSession().Name {
+		t.Errorf("request name = %q, want the session's tmux name", gotReq.Name)
+	}
+	if gotReq.Title != inst.Title {
+		t.Errorf("request title = %q, want %q", gotReq.Title, inst.Title)
+	}
+}
+
+func TestAttachPaneHotkey_OutsideTmuxShowsError(t *testing.T) {
+	t.Setenv("TMUX", "")
+	home, _, _ := armHomeWithOneSession(t)
+
+	_, _ = home.handleMainKey(tea.KeyMsg{Type: tea.KeyEnter, Alt: true})
+	if home.err == nil {
+		t.Fatal("expected an error outside tmux")
+	}
+}
@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+const (
+	// autoArchiveInterval is how often the TUI applies [archive]
+	// auto_archive_after_days. The threshold is in days, so hourly is plenty.
+	autoArchiveInterval = time.Hour
+	// autoArchiveStartupDelay postpones the first sweep until the status
+	// worker has refreshed every session, so a session that is busy but
+	// stored as idle is not archived on boot.
+	autoArchiveStartupDelay = 2 * time.Minute
+)
+
+// sessionsAutoArchivedMsg reports the sessions the auto-archive sweep moved
+// to the archive.
+type sessionsAutoArchivedMsg struct {
+	sessionIDs []string
+}
+
+// autoArchiveIdleSessions archives the sessions idle past the configured
+// threshold, at most once per autoArchiveInterval. Candidates are picked on
+// the UI goroutine; the kills run in the returned command.
+func (h *Home) autoArchiveIdleSessions(now time.Time) tea.Cmd {
+	if now.Before(h.nextAutoArchive) {
+		return nil
+	}
+	h.nextAutoArchive = now.Add(autoArchiveInterval)
+
+	after := session.GetArchiveSettings().AutoArchiveAfter()
+	if after <= 0 {
+		return nil
+	}
+	h.instancesMu.RLock()
+	candidates := session.AutoArchiveCandidates(h.instances, now, after)
+	h.instancesMu.RUnlock()
+	if len(candidates) == 0 {
+		return nil
+	}
+	for _, inst := range candidates {
+		h.captureAutoNameBeforeStop(inst)
+	}
+
+	return func() tea.Msg {
+		var ids []string
+		for _, inst := range candidates {
+			// Kill only a live pane: killing a dead one errors and would
+			// leave an already-stopped session unarchived forever.
+			if inst.Exists() {
+				if err := inst.Kill(); err != nil {
+					uiLog.Warn("auto_archive_kill_failed",
+						slog.String("id", inst.ID), slog.String("error", err.Error()))
+					continue
+				}
+			}
+			inst.ArchivedAt = time.Now().UTC()
+			ids = append(ids, inst.ID)
+		}
+		return sessionsAutoArchivedMsg{sessionIDs: ids}
+	}
+}
+
+// handleSessionsAutoArchived persists the sweep's archive stamps with the
+// same targeted UPDATE a manual archive uses.
+func (h *Home) handleSessionsAutoArchived(msg sessionsAutoArchivedMsg) {
+	if len(msg.sessionIDs) == 0 {
+		return
+	}
+	h.cachedStatusCounts.valid.Store(false)
+	for _, id := range msg.sessionIDs {
+		h.invalidatePreviewCache(id)
+		if inst := h.getInstanceByID(id); inst != nil {
+			if err := h.persistArchived(inst); err != nil {
+				h.setError(fmt.Errorf("failed to persist archive: %w", err))
+				h.rebuildFlatItems()
+				return
+			}
+		}
+	}
+	h.rebuildFlatItems()
+	uiLog.Info("auto_archive", slog.Int("count", len(msg.sessionIDs)))
+	h.setError(fmt.Errorf("auto-archived %d idle session(s) (^ to view)", len(msg.sessionIDs)))
+}
@@ -0,0 +1,339 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Board view (hotkeyBoardView, default Alt+b): an alternate full-screen layout
+// that sorts sessions into status columns — waiting, running, idle, error —
+// so the deck reads like a triage board. ←/→ move between columns, ↑/↓
+// within one, Enter attaches, Esc returns to the tree.
+
+// Board columns, left to right.
+const (
+	boardColWaiting = iota
+	boardColRunning
+	boardColIdle
+	boardColError
+	boardColumnCount
+)
+
+var boardColumnTitles = [boardColumnCount]string{"Waiting", "Running", "Idle", "Error"}
+
+// boardColumnFor places a status on the board. Starting and queued sessions
+// are on their way to running; stopped ones sit with idle.
+func boardColumnFor(status session.Status) int {
+	switch status {
+	case session.StatusWaiting:
+		return boardColWaiting
+	case session.StatusRunning, session.StatusStarting, session.StatusQueued:
+		return boardColRunning
+	case session.StatusError:
+		return boardColError
+	default:
+		return boardColIdle
+	}
+}
+
+// boardCard is one session on the board.
+type boardCard struct {
+	id       string
+	title    string
+	detail   string // group · tool · time waiting
+	status   session.Status
+	substate session.Substate
+}
+
+// BoardView renders sessions as status columns. The selection follows the
+// session, not the row, so a card that changes column keeps the focus.
+type BoardView struct {
+	visible       bool
+	width, height int
+	col           int    // focused column
+	selectedID    string // selected card's session ID
+}
+
+// NewBoardView creates the board (hidden).
+func NewBoardView() *BoardView {
+	return &BoardView{}
+}
+
+// Show opens the board with selectedID focused, or on the first waiting
+// session when selectedID is not on the board.
+func (b *BoardView) Show(cols [boardColumnCount][]boardCard, selectedID string) {
+	b.visible = true
+	b.selectedID = selectedID
+	b.col = boardColWaiting
+	if c, _, ok := boardFind(cols, selectedID); ok {
+		b.col = c
+		return
+	}
+	for c := range cols {
+		if len(cols[c]) > 0 {
+			b.col = c
+			b.selectedID = cols[c][0].id
+			return
+		}
+	}
+	b.selectedID = ""
+}
+
+// Hide closes the board.
+func (b *BoardView) Hide() { b.visible = false }
+
+// IsVisible reports whether the board is open.
+func (b *BoardView) IsVisible() bool { return b != nil && b.visible }
+
+// SetSize updates the board dimensions.
+func (b *BoardView) SetSize(w, h int) {
+	if b == nil {
+		return
+	}
+	b.width = w
+	b.height = h
+}
+
+// boardFind locates the card for id.
+func boardFind(cols [boardColumnCount][]boardCard, id string) (col, row int, ok bool) {
+	if id == "" {
+		return 0, 0, false
+	}
+	for c := range cols {
+		for r, card := range cols[c] {
+			if card.id == id {
+				return c, r, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// sync re-points the focus after statuses changed: the selected card's
+// column when it is still on the board, otherwise the nearest card in the
+// focused column.
+func (b *BoardView) sync(cols [boardColumnCount][]boardCard) (row int) {
+	if c, r, ok := boardFind(cols, b.selectedID); ok {
+		b.col = c
+		return r
+	}
+	b.selectedID = ""
+	if len(cols[b.col]) > 0 {
+		b.selectedID = cols[b.col][0].id
+	}
+	return 0
+}
+
+// Update handles a key while the board is visible. attachID is the session
+// to attach when Enter was pressed on a card.
+func (b *BoardView) Update(msg tea.KeyMsg, cols [boardColumnCount][]boardCard) (attachID string) {
+	if !b.IsVisible() {
+		return ""
+	}
+	row := b.sync(cols)
+	switch msg.String() {
+	case "esc", "q":
+		b.Hide()
+	case "left", "h":
+		b.moveColumn(cols, row, -1)
+	case "right", "l", "tab":
+		b.moveColumn(cols, row, 1)
+	case "up", "k":
+		if row > 0 {
+			b.selectedID = cols[b.col][row-1].id
+		}
+	case "down", "j":
+		if row+1 < len(cols[b.col]) {
+			b.selectedID = cols[b.col][row+1].id
+		}
+	case "enter":
+		if b.selectedID != "" {
+			b.Hide()
+			return b.selectedID
+		}
+	}
+	return ""
+}
+
+// moveColumn focuses the next column in dir, keeping the row where it can.
+// Empty columns are focusable so the board never skips past a column.
+func (b *BoardView) moveColumn(cols [boardColumnCount][]boardCard, row, dir int) {
+	next := b.col + dir
+	if next < 0 || next >= boardColumnCount {
+		return
+	}
+	b.col = next
+	b.selectedID = ""
+	if n := len(cols[next]); n > 0 {
+		b.selectedID = cols[next][min(row, n-1)].id
+	}
+}
+
+// boardCardLines is the height of one card: title, detail and a gap.
+const boardCardLines = 3
+
+// View renders the board.
+func (b *BoardView) View(cols [boardColumnCount][]boardCard) string {
+	if !b.IsVisible() {
+		return ""
+	}
+	row := b.sync(cols)
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorAccent)
+	footerStyle := lipgloss.NewStyle().Foreground(ColorComment).Italic(true)
+
+	colWidth := max((b.width-(boardColumnCount-1))/boardColumnCount, 12)
+	// Header, blank, column title, rule, footer and the two scroll
+	// indicators take 7 lines.
+	visibleCards := max((b.height-7)/boardCardLines, 1)
+
+	rendered := make([]string, boardColumnCount)
+	for c := range cols {
+		selRow := -1
+		if c == b.col {
+			selRow = row
+		}
+		rendered[c] = b.renderColumn(c, cols[c], selRow, colWidth, visibleCards)
+	}
+	sep := lipgloss.NewStyle().Foreground(ColorBorder).Render(strings.TrimSuffix(strings.Repeat("│\n", max(b.height-3, 1)), "\n"))
+	body := lipgloss.JoinHorizontal(lipgloss.Top,
+		rendered[0], sep, rendered[1], sep, rendered[2], sep, rendered[3])
+
+	header := titleStyle.Render("Board") + lipgloss.NewStyle().Foreground(ColorTextDim).Render(
+		fmt.Sprintf("  %d waiting · %d running · %d idle · %d error",
+			len(cols[boardColWaiting]), len(cols[boardColRunning]), len(cols[boardColIdle]), len(cols[boardColError])))
+	footer := footerStyle.Render("←/→ column | ↑/↓ session | Enter attach | Esc back to list")
+
+	view := lipgloss.JoinVertical(lipgloss.Left, header, "", body)
+	lines := strings.Split(view, "\n")
+	if len(lines) > b.height-1 {
+		lines = lines[:max(b.height-1, 0)]
+	}
+	for len(lines) < b.height-1 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, footer)
+	return lipgloss.NewStyle().MaxWidth(b.width).Render(strings.Join(lines, "\n"))
+}
+
+// renderColumn renders one column, scrolled so the selected card (selRow,
+// -1 when the column is not focused) stays visible.
+func (b *BoardView) renderColumn(col int, cards []boardCard, selRow, width, visibleCards int) string {
+	headStyle := lipgloss.NewStyle().Bold(true)
+	if col == b.col {
+		headStyle = headStyle.Foreground(ColorAccent).Underline(true)
+	}
+	dim := lipgloss.NewStyle().Foreground(ColorTextDim)
+	selStyle := lipgloss.NewStyle().Foreground(ColorAccent).Bold(true)
+
+	lines := []string{
+		headStyle.Render(cellTruncate(fmt.Sprintf("%s (%d)", boardColumnTitles[col], len(cards)), width, "…")),
+		dim.Render(strings.Repeat("─", width)),
+	}
+	if len(cards) == 0 {
+		lines = append(lines, dim.Render("  (none)"))
+	}
+
+	start := 0
+	if selRow >= visibleCards {
+		start = selRow - visibleCards + 1
+	}
+	end := min(start+visibleCards, len(cards))
+	if start > 0 {
+		lines = append(lines, dim.Render(fmt.Sprintf("  ⋮ +%d above", start)))
+	}
+	for i := start; i < end; i++ {
+		card := cards[i]
+		icon, iconStyle := rowStatusGlyph(card.status, card.substate, false)
+		prefix := "  "
+		title := cellTruncate(card.title, width-4, "…")
+		if i == selRow {
+			prefix = selStyle.Render("▶ ")
+			title = selStyle.Render(title)
+		}
+		lines = append(lines,
+			prefix+iconStyle.Render(icon)+" "+title,
+			"    "+dim.Render(cellTruncate(card.detail, width-4, "…")),
+			"")
+	}
+	if end < len(cards) {
+		lines = append(lines, dim.Render(fmt.Sprintf("  ⋮ +%d more", len(cards)-end)))
+	}
+	return lipgloss.NewStyle().Width(width).Render(strings.Join(lines, "\n"))
+}
+
+// boardCards sorts the sessions in scope into board columns, in list order.
+// Archived sessions are left off; waiting cards say how long they have waited.
+func (h *Home) boardCards() [boardColumnCount][]boardCard {
+	var cols [boardColumnCount][]boardCard
+	snapshot := h.getSessionRenderSnapshot()
+	h.instancesMu.RLock()
+	defer h.instancesMu.RUnlock()
+	for _, inst := range h.instances {
+		if inst.IsArchived() || !h.isInGroupScope(inst.GroupPath) {
+			continue
+		}
+		state, ok := snapshot[inst.ID]
+		if !ok {
+			state = h.getSessionRenderState(inst)
+		}
+		detail := []string{inst.GroupPath, state.tool}
+		if state.status == session.StatusWaiting {
+			if since := inst.GetWaitingSince(); !since.IsZero() {
+				detail = append(detail, "waiting "+formatDuration(time.Since(since).Round(time.Second)))
+			}
+		}
+		c := boardColumnFor(state.status)
+		cols[c] = append(cols[c], boardCard{
+			id:       inst.ID,
+			title:    displaySessionTitle(inst, state.paneTitle),
+			detail:   strings.Join(detail, " · "),
+			status:   state.status,
+			substate: state.substate,
+		})
+	}
+	return cols
+}
+
+// openBoard shows the board focused on the session under the list cursor.
+func (h *Home) openBoard() {
+	if h.boardView == nil {
+		h.boardView = NewBoardView()
+	}
+	selected := ""
+	if h.cursor >= 0 && h.cursor < len(h.flatItems) {
+		if item := h.flatItems[h.cursor]; item.Type == session.ItemTypeSession && item.Session != nil {
+			selected = item.Session.ID
+		}
+	}
+	h.boardView.SetSize(h.width, h.height)
+	h.boardView.Show(h.boardCards(), selected)
+}
+
+// handleBoardKey routes a key to the board. Enter on a card moves the list
+// cursor to that session and attaches it the same way the list does.
+func (h *Home) handleBoardKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if h.normalizeMainKey(msg.String()) == "alt+b" {
+		h.boardView.Hide()
+		return h, nil
+	}
+	id := h.boardView.Update(msg, h.boardCards())
+	if id == "" {
+		return h, nil
+	}
+	inst := h.getInstanceByID(id)
+	if inst == nil {
+		return h, nil
+	}
+	h.jumpToSession(inst)
+	if h.cursor < len(h.flatItems) {
+		if item := h.flatItems[h.cursor]; item.Session != nil && item.Session.ID == id {
+			return h.handleMainKey(tea.KeyMsg{Type: tea.KeyEnter})
+		}
+	}
+	return h, h.fetchSelectedPreview()
+}
@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestBoardColumnFor(t *testing.T) {
+	cases := map[session.Status]int{
+		session.StatusWaiting:  boardColWaiting,
+		session.StatusRunning:  boardColRunning,
+		session.StatusStarting: boardColRunning,
+		session.StatusIdle:     boardColIdle,
+		session.StatusStopped:  boardColIdle,
+		session.StatusError:    boardColError,
+	}
+	for status, want := range cases {
+		if got := boardColumnFor(status); got != want {
+			t.Errorf("boardColumnFor(%s) = %d, want %d", status, got, want)
+		}
+	}
+}
+
+func testBoardCols() [boardColumnCount][]boardCard {
+	var cols [boardColumnCount][]boardCard
+	cols[boardColWaiting] = []boardCard{{id: "w1", title: "w1", status: session.StatusWaiting}, {id: "w2", title: "w2", status: session.StatusWaiting}}
+	cols[boardColRunning] = []boardCard{{id: "r1", title: "r1", status: session.StatusRunning}}
+	cols[boardColError] = []boardCard{{id: "e1", title: "e1", status: session.StatusError}}
+	return cols
+}
+
+func TestBoardView_Navigation(t *testing.T) {
+	cols := testBoardCols()
+	b := NewBoardView()
+	b.SetSize(120, 30)
+	b.Show(cols, "")
+	if b.col != boardColWaiting || b.selectedID != "w1" {
+		t.Fatalf("opened on col %d %q, want waiting w1", b.col, b.selectedID)
+	}
+
+	press := func(k tea.KeyType) string { return b.Update(tea.KeyMsg{Type: k}, cols) }
+	press(tea.KeyDown)
+	if b.selectedID != "w2" {
+		t.Errorf("down selected %q, want w2", b.selectedID)
+	}
+	press(tea.KeyRight)
+	if b.col != boardColRunning || b.selectedID != "r1" {
+		t.Errorf("right focused col %d %q, want running r1", b.col, b.selectedID)
+	}
+	press(tea.KeyRight) // empty idle column is still focusable
+	if b.col != boardColIdle || b.selectedID != "" {
+		t.Errorf("right focused col %d %q, want empty idle", b.col, b.selectedID)
+	}
+	press(tea.KeyRight)
+	if got := press(tea.KeyEnter); got != "e1" {
+		t.Errorf("enter attached %q, want e1", got)
+	}
+	if b.IsVisible() {
+		t.Error("enter should close the board")
+	}
+
+	// The selection follows a session into another column.
+	b.Show(cols, "w2")
+	cols[boardColWaiting] = cols[boardColWaiting][:1]
+	cols[boardColRunning] = append(cols[boardColRunning], boardCard{id: "w2", title: "w2", status: session.StatusRunning})
+	if view := b.View(cols); !strings.Contains(view, "Running (2)") {
+		t.Errorf("view missing updated running column:\n%s", view)
+	}
+	if b.col != boardColRunning || b.selectedID != "w2" {
+		t.Errorf("focus = col %d %q, want running w2", b.col, b.selectedID)
+	}
+}
+
+func TestBoardCards_GroupsByStatus(t *testing.T) {
+	h, insts := armHomeForSelect(t)
+	insts[0].Status = session.StatusWaiting
+	insts[1].Status = session.StatusError
+	insts[2].Status = session.StatusRunning
+	insts[3].Status = session.StatusIdle
+	h.sessionRenderSnapshot.Store(map[string]sessionRenderState{})
+
+	cols := h.boardCards()
+	for c, want := range map[int]string{boardColWaiting: "exp-1", boardColError: "exp-2", boardColRunning: "exp-3", boardColIdle: "keep"} {
+		if len(cols[c]) != 1 || cols[c][0].title != want {
+			t.Errorf("column %s = %+v, want %s", boardColumnTitles[c], cols[c], want)
+		}
+	}
+
+	h.handleMainKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}, Alt: true})
+	if !h.boardView.IsVisible() {
+		t.Fatal("alt+b should open the board")
+	}
+	h.handleBoardKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if h.boardView.IsVisible() {
+		t.Error("esc should close the board")
+	}
+}
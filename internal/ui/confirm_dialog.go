@@ -23,9 +23,11 @@ const (
 	ConfirmCloseRemoteSession
 	ConfirmRemoveSession     // status-gated registry-only remove (TUI 'X')
 	ConfirmBulkRemoveErrored // bulk remove of all errored sessions (TUI Ctrl+X)
+	ConfirmBulkDeleteMarked  // delete of the sessions marked in select mode
 	ConfirmArchiveSession
 	ConfirmUnarchiveSession
-	ConfirmNotice // acknowledge-only message (single OK button), e.g. protected-action blocks
+	ConfirmNotice   // acknowledge-only message (single OK button), e.g. protected-action blocks
+	ConfirmRelocate // "did this project move?" for a session whose path is missing
 )
 
 // ConfirmDialog handles confirmation for destructive actions
@@ -64,6 +66,11 @@ type ConfirmDialog struct {
 	pendingLaunchModelID     string          // Optional per-session model/version override.
 	pendingParentSessionID   string
 	pendingParentProjectPath string
+
+	// Relocation target (for ConfirmRelocate)
+	relocateFrom   string
+	relocateTo     string
+	relocateReason string
 }
 
 // NewConfirmDialog creates a new confirmation dialog
@@ -161,6 +168,18 @@ func (c *ConfirmDialog) ShowBulkRemoveErrored(count int) {
 	c.focusedButton = 1
 }
 
+// ShowBulkDeleteMarked shows confirmation for deleting the sessions marked
+// in select mode. count is the number of sessions that will be deleted.
+func (c *ConfirmDialog) ShowBulkDeleteMarked(count int) {
+	c.visible = true
+	c.confirmType = ConfirmBulkDeleteMarked
+	c.targetID = ""
+	c.targetName = ""
+	c.mcpCount = count // reuse mcpCount as a generic integer carrier
+	c.buttonCount = 2
+	c.focusedButton = 1
+}
+
 // ShowDeleteGroup shows confirmation for group deletion
 func (c *ConfirmDialog) ShowDeleteGroup(groupPath, groupName string) {
 	c.visible = true
@@ -229,6 +248,24 @@ func (c *ConfirmDialog) ShowCreateDirectory(
 	c.focusedButton = 1
 }
 
+// ShowRelocate asks whether a session's missing project moved to newPath.
+func (c *ConfirmDialog) ShowRelocate(sessionID, sessionName, oldPath, newPath, reason string) {
+	c.visible = true
+	c.confirmType = ConfirmRelocate
+	c.targetID = sessionID
+	c.targetName = sessionName
+	c.relocateFrom = oldPath
+	c.relocateTo = newPath
+	c.relocateReason = reason
+	c.buttonCount = 2
+	c.focusedButton = 0
+}
+
+// GetRelocateTarget returns the proposed new project path.
+func (c *ConfirmDialog) GetRelocateTarget() string {
+	return c.relocateTo
+}
+
 // ShowInstallHooks shows confirmation for installing Claude Code hooks
 func (c *ConfirmDialog) ShowInstallHooks() {
 	c.visible = true
@@ -435,6 +472,17 @@ func (c *ConfirmDialog) View() string {
 		buttons = lipgloss.JoinVertical(lipgloss.Left, buttonRow,
 			hintStyle.Render("y remove · n cancel · ←/→ navigate · Enter select · Esc"))
 
+	case ConfirmBulkDeleteMarked:
+		title = "⚠  Delete Marked Sessions?"
+		warning = fmt.Sprintf("This will permanently delete %d marked session(s).", c.mcpCount)
+		details = "• Their tmux sessions and processes will be killed\n• Their git worktrees will be removed\n• Pinned sessions are skipped\n• Undo is available from the session list"
+		borderColor = ColorRed
+		buttonRow := lipgloss.JoinHorizontal(lipgloss.Center,
+			renderButton("Delete All", ColorRed, c.focusedButton == 0), "  ",
+			renderButton("Cancel", ColorAccent, c.focusedButton == 1))
+		buttons = lipgloss.JoinVertical(lipgloss.Left, buttonRow,
+			hintStyle.Render("y delete · n cancel · ←/→ navigate · Enter select · Esc"))
+
 	case ConfirmDeleteGroup:
 		title = "⚠  Delete Group?"
 		warning = fmt.Sprintf("This will delete the group:\n\n  \"%s\"", c.targetName)
@@ -468,6 +516,17 @@ func (c *ConfirmDialog) View() string {
 		buttons = lipgloss.JoinVertical(lipgloss.Left, buttonRow,
 			hintStyle.Render("y create · n cancel · ←/→ navigate · Enter select · Esc"))
 
+	case ConfirmRelocate:
+		title = "📁  Project Moved?"
+		warning = fmt.Sprintf("%q points at a missing folder:\n\n  %s", c.targetName, c.relocateFrom)
+		details = fmt.Sprintf("Found %s (%s).\nRelocate sessions, worktrees and group paths there?", c.relocateTo, c.relocateReason)
+		borderColor = ColorAccent
+		buttonRow := lipgloss.JoinHorizontal(lipgloss.Center,
+			renderButton("Relocate", ColorGreen, c.focusedButton == 0), "  ",
+			renderButton("Cancel", ColorRed, c.focusedButton == 1))
+		buttons = lipgloss.JoinVertical(lipgloss.Left, buttonRow,
+			hintStyle.Render("y relocate · n cancel · ←/→ navigate · Enter select · Esc"))
+
 	case ConfirmNotice:
 		title = c.noticeTitle
 		warning = c.noticeBody
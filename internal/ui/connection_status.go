@@ -41,6 +41,14 @@ func rowStatusGlyph(status session.Status, substate session.Substate, archived b
 		icon, style = "✕", SessionStatusError
 	case session.StatusStopped:
 		icon, style = "■", SessionStatusStopped
+	case session.StatusExited:
+		icon, style = "✓", SessionStatusExited
+	case session.StatusCrashed:
+		icon, style = "✗", SessionStatusCrashed
+	case session.StatusStarting:
+		icon, style = "○", SessionStatusStarting
+	case session.StatusQueued:
+		icon, style = "○", SessionStatusQueued
 	default:
 		icon, style = "○", SessionStatusIdle
 	}
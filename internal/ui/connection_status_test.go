@@ -51,6 +51,8 @@ func TestRowStatusGlyph(t *testing.T) {
 		{"idle", session.StatusIdle, "", false, "○"},
 		{"error", session.StatusError, "", false, "✕"},
 		{"stopped", session.StatusStopped, "", false, "■"},
+		{"exited", session.StatusExited, "", false, "✓"},
+		{"crashed", session.StatusCrashed, "", false, "✗"},
 		{"unknown status falls back to idle glyph", session.Status("weird"), "", false, "○"},
 		{"error + model-unavailable substate", session.StatusError, session.SubstateModelUnavailable, false, "⚡"},
 		{"error + auth substate", session.StatusError, session.SubstateAuth401, false, "🔒"},
@@ -68,3 +70,19 @@ func TestRowStatusGlyph(t *testing.T) {
 		})
 	}
 }
+
+func TestStatusCountBucket_FoldsExitStatuses(t *testing.T) {
+	if got := statusCountBucket(session.StatusExited); got != session.StatusStopped {
+		t.Errorf("exited bucket = %q, want stopped", got)
+	}
+	if got := statusCountBucket(session.StatusCrashed); got != session.StatusError {
+		t.Errorf("crashed bucket = %q, want error", got)
+	}
+	if got := statusCountBucket(session.StatusRunning); got != session.StatusRunning {
+		t.Errorf("running bucket = %q, want running", got)
+	}
+	h := NewHome()
+	if !h.matchesStatusFilter(session.StatusError, session.StatusCrashed) {
+		t.Error("error filter should include crashed sessions")
+	}
+}
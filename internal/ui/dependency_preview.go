@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// renderDependencySection draws the "Depends On" block of the preview pane:
+// each prerequisite with its status, then the resolved start order (or the
+// cycle that prevents one).
+func (h *Home) renderDependencySection(b *strings.Builder, selected *session.Instance, width int) {
+	graph := session.NewDependencyGraph(h.instances)
+	deps := graph.Dependencies(selected.ID)
+	if len(deps) == 0 {
+		return
+	}
+
+	b.WriteString(renderSectionDivider("Depends On", width-4))
+	b.WriteString("\n")
+
+	labelStyle := lipgloss.NewStyle().Foreground(ColorText)
+	valueStyle := lipgloss.NewStyle().Foreground(ColorText)
+	for _, dep := range deps {
+		statusStyle := lipgloss.NewStyle().Foreground(ColorComment)
+		label := "stopped"
+		if dep.Exists() {
+			statusStyle = lipgloss.NewStyle().Foreground(ColorGreen)
+			label = string(dep.Status)
+		}
+		b.WriteString(labelStyle.Render("  • "))
+		b.WriteString(valueStyle.Render(truncatePath(dep.Title, width-4-16)))
+		b.WriteString(" ")
+		b.WriteString(statusStyle.Render(label))
+		b.WriteString("\n")
+	}
+
+	order, err := graph.StartOrder(selected.ID)
+	if err != nil {
+		b.WriteString(lipgloss.NewStyle().Foreground(ColorRed).Render(truncatePath(err.Error(), width-4)))
+		b.WriteString("\n")
+		return
+	}
+	titles := make([]string, len(order))
+	for i, inst := range order {
+		titles[i] = inst.Title
+	}
+	b.WriteString(labelStyle.Render("Order:   "))
+	b.WriteString(valueStyle.Render(truncatePath(strings.Join(titles, " → "), width-4-9)))
+	b.WriteString("\n")
+}
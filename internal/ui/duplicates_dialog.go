@@ -0,0 +1,299 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// duplicateScanInterval is how often the tick loop re-runs the duplicate
+// advisor. The scan is an in-memory pass over h.instances, so the interval
+// only limits how often a new suggestion can be announced.
+const duplicateScanInterval = 5 * time.Minute
+
+// duplicateMergeMsg asks Home to keep keepID and fold the other members of a
+// duplicate group into it (children reparented, notes carried over, then
+// archived).
+type duplicateMergeMsg struct {
+	keepID   string
+	mergeIDs []string
+}
+
+// duplicateRemoveMsg asks Home to open the standard delete confirmation for
+// one member of a duplicate group.
+type duplicateRemoveMsg struct {
+	sessionID string
+}
+
+// DuplicatesDialog walks the "possible duplicates" suggestions produced by
+// session.FindDuplicateSessions, one group at a time. Enter keeps the
+// highlighted session and merges the rest into it, x removes the
+// highlighted session (through the delete confirmation), i dismisses the
+// suggestion for this run.
+type DuplicatesDialog struct {
+	visible       bool
+	width, height int
+	groups        []session.DuplicateGroup
+	group         int
+	cursor        int
+	dismissed     []string // keys dismissed while open; Home records them
+}
+
+// NewDuplicatesDialog creates the dialog (hidden).
+func NewDuplicatesDialog() *DuplicatesDialog {
+	return &DuplicatesDialog{}
+}
+
+// Show opens the dialog on the given groups.
+func (d *DuplicatesDialog) Show(groups []session.DuplicateGroup) {
+	d.visible = true
+	d.groups = groups
+	d.group = 0
+	d.cursor = 0
+	d.dismissed = nil
+}
+
+// Hide closes the dialog.
+func (d *DuplicatesDialog) Hide() {
+	d.visible = false
+	d.groups = nil
+	d.group = 0
+	d.cursor = 0
+}
+
+// IsVisible reports whether the dialog is open.
+func (d *DuplicatesDialog) IsVisible() bool { return d != nil && d.visible }
+
+// SetSize updates the dialog dimensions for centering. Nil-safe like
+// RetryStartDialog.SetSize.
+func (d *DuplicatesDialog) SetSize(w, h int) {
+	if d == nil {
+		return
+	}
+	d.width = w
+	d.height = h
+}
+
+// TakeDismissed returns and clears the group keys dismissed since the last
+// call.
+func (d *DuplicatesDialog) TakeDismissed() []string {
+	keys := d.dismissed
+	d.dismissed = nil
+	return keys
+}
+
+// current returns the group on screen, or nil when none are left.
+func (d *DuplicatesDialog) current() *session.DuplicateGroup {
+	if d.group < 0 || d.group >= len(d.groups) {
+		return nil
+	}
+	return &d.groups[d.group]
+}
+
+// dropCurrent removes the group on screen and closes the dialog when none
+// remain.
+func (d *DuplicatesDialog) dropCurrent() {
+	d.groups = append(d.groups[:d.group:d.group], d.groups[d.group+1:]...)
+	d.cursor = 0
+	if d.group >= len(d.groups) {
+		d.group = 0
+	}
+	if len(d.groups) == 0 {
+		d.Hide()
+	}
+}
+
+// Update handles a key while the dialog is visible.
+func (d *DuplicatesDialog) Update(msg tea.KeyMsg) (*DuplicatesDialog, tea.Cmd) {
+	if !d.IsVisible() {
+		return d, nil
+	}
+	g := d.current()
+	if g == nil {
+		d.Hide()
+		return d, nil
+	}
+	switch msg.String() {
+	case "esc", "q":
+		d.Hide()
+	case "j", "down":
+		d.cursor = (d.cursor + 1) % len(g.Sessions)
+	case "k", "up":
+		d.cursor = (d.cursor - 1 + len(g.Sessions)) % len(g.Sessions)
+	case "tab", "l", "right":
+		d.group = (d.group + 1) % len(d.groups)
+		d.cursor = 0
+	case "shift+tab", "h", "left":
+		d.group = (d.group - 1 + len(d.groups)) % len(d.groups)
+		d.cursor = 0
+	case "i":
+		d.dismissed = append(d.dismissed, g.Key())
+		d.dropCurrent()
+	case "enter":
+		keep := g.Sessions[d.cursor]
+		var merge []string
+		for _, inst := range g.Sessions {
+			if inst.ID != keep.ID {
+				merge = append(merge, inst.ID)
+			}
+		}
+		d.dropCurrent()
+		return d, func() tea.Msg { return duplicateMergeMsg{keepID: keep.ID, mergeIDs: merge} }
+	case "x":
+		id := g.Sessions[d.cursor].ID
+		d.Hide()
+		return d, func() tea.Msg { return duplicateRemoveMsg{sessionID: id} }
+	}
+	return d, nil
+}
+
+// View renders the dialog.
+func (d *DuplicatesDialog) View() string {
+	if !d.IsVisible() {
+		return ""
+	}
+	g := d.current()
+	if g == nil {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorAccent)
+	reasonStyle := lipgloss.NewStyle().Foreground(ColorYellow)
+	selectedStyle := lipgloss.NewStyle().Foreground(ColorAccent).Bold(true)
+	normalStyle := lipgloss.NewStyle().Foreground(ColorText)
+	dimStyle := lipgloss.NewStyle().Foreground(ColorTextDim)
+	footerStyle := lipgloss.NewStyle().Foreground(ColorComment).Italic(true)
+
+	dialogWidth := fitDialogWidth(72, 40, d.width)
+
+	var lines []string
+	lines = append(lines, titleStyle.Render(fmt.Sprintf("Possible Duplicates (%d/%d)", d.group+1, len(d.groups))))
+	lines = append(lines, reasonStyle.Render(strings.Join(g.Reasons, " · ")))
+	lines = append(lines, "")
+	for i, inst := range g.Sessions {
+		label := fmt.Sprintf("%s %s", statusIndicator(inst.Status), inst.Title)
+		meta := []string{inst.Tool}
+		if inst.GroupPath != "" {
+			meta = append(meta, inst.GroupPath)
+		}
+		if inst.WorktreeBranch != "" {
+			meta = append(meta, inst.WorktreeBranch)
+		}
+		meta = append(meta, "created "+formatRelativeTime(inst.CreatedAt))
+		if i == d.cursor {
+			lines = append(lines, "> "+selectedStyle.Render(label))
+		} else {
+			lines = append(lines, "  "+normalStyle.Render(label))
+		}
+		lines = append(lines, "    "+dimStyle.Render(truncatePath(strings.Join(meta, " · "), dialogWidth-8)))
+	}
+	lines = append(lines, "")
+	lines = append(lines, footerStyle.Render("Enter keep this, merge others | x remove | i ignore | Tab next | Esc close"))
+
+	box := DialogBoxStyle.Width(dialogWidth).Render(strings.Join(lines, "\n"))
+	return centerInScreen(box, d.width, d.height)
+}
+
+// scanDuplicates re-runs the duplicate advisor over the loaded sessions and
+// announces new suggestions in the status bar. Dismissed groups stay hidden
+// for the rest of the run; a group whose membership changes gets a new key
+// and is suggested again.
+func (h *Home) scanDuplicates() {
+	h.lastDuplicateScan = time.Now()
+	h.instancesMu.RLock()
+	all := session.FindDuplicateSessions(h.instances)
+	h.instancesMu.RUnlock()
+
+	groups := all[:0]
+	for _, g := range all {
+		if !h.dismissedDuplicates[g.Key()] {
+			groups = append(groups, g)
+		}
+	}
+	h.duplicateGroups = groups
+
+	if len(groups) > h.announcedDuplicates {
+		hint := ""
+		if key := h.actionKey(hotkeyReviewDuplicates); key != "" {
+			hint = fmt.Sprintf(" (%s to review)", key)
+		}
+		h.setError(fmt.Errorf("%d possible duplicate session group(s)%s", len(groups), hint))
+	}
+	h.announcedDuplicates = len(groups)
+}
+
+// openDuplicatesDialog rescans and shows the suggestions.
+func (h *Home) openDuplicatesDialog() {
+	h.scanDuplicates()
+	if len(h.duplicateGroups) == 0 {
+		h.setError(fmt.Errorf("no possible duplicate sessions"))
+		return
+	}
+	if h.duplicatesDialog == nil {
+		h.duplicatesDialog = NewDuplicatesDialog()
+	}
+	h.duplicatesDialog.SetSize(h.width, h.height)
+	h.duplicatesDialog.Show(h.duplicateGroups)
+}
+
+// handleDuplicatesDialogKey routes a key to the dialog and records groups
+// the user dismissed.
+func (h *Home) handleDuplicatesDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	d, cmd := h.duplicatesDialog.Update(msg)
+	h.duplicatesDialog = d
+	for _, key := range d.TakeDismissed() {
+		if h.dismissedDuplicates == nil {
+			h.dismissedDuplicates = map[string]bool{}
+		}
+		h.dismissedDuplicates[key] = true
+	}
+	return h, cmd
+}
+
+// mergeDuplicates keeps msg.keepID and folds the other sessions into it:
+// their sub-sessions are reparented to the kept session, their notes are
+// appended to its notes, and they are archived (reversible with unarchive).
+func (h *Home) mergeDuplicates(msg duplicateMergeMsg) tea.Cmd {
+	keep := h.getInstanceByID(msg.keepID)
+	if keep == nil {
+		return nil
+	}
+	var merged []*session.Instance
+	for _, id := range msg.mergeIDs {
+		if inst := h.getInstanceByID(id); inst != nil && inst.ID != keep.ID {
+			merged = append(merged, inst)
+		}
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+
+	h.instancesMu.Lock()
+	for _, inst := range merged {
+		for _, child := range h.instances {
+			if child.ParentSessionID == inst.ID {
+				child.SetParentWithPath(keep.ID, keep.ProjectPath)
+			}
+		}
+		if notes := strings.TrimSpace(inst.Notes); notes != "" {
+			if keep.Notes != "" {
+				keep.Notes += "\n\n"
+			}
+			keep.Notes += fmt.Sprintf("[from %s]\n%s", inst.Title, notes)
+		}
+	}
+	h.instancesMu.Unlock()
+	h.saveInstances()
+
+	cmds := make([]tea.Cmd, 0, len(merged))
+	for _, inst := range merged {
+		cmds = append(cmds, h.archiveSession(inst))
+	}
+	h.announcedDuplicates = 0
+	return tea.Batch(cmds...)
+}
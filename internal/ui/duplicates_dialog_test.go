@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+func duplicatesTestHome(t *testing.T) (*Home, []*session.Instance) {
+	t.Helper()
+	h := NewHome()
+	base := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	insts := []*session.Instance{
+		{ID: "a", Title: "api refactor", ProjectPath: "/src/api", Tool: "claude", CreatedAt: base},
+		{ID: "b", Title: "api refactor 2", ProjectPath: "/src/api", Tool: "claude", CreatedAt: base.Add(time.Hour), Notes: "half done"},
+		{ID: "kid", Title: "tests", ProjectPath: "/src/api/tests", Tool: "claude", ParentSessionID: "b"},
+	}
+	h.instances = insts
+	for _, inst := range insts {
+		h.instanceByID[inst.ID] = inst
+	}
+	return h, insts
+}
+
+func TestHome_ScanDuplicatesAnnouncesAndDismisses(t *testing.T) {
+	h, _ := duplicatesTestHome(t)
+
+	h.scanDuplicates()
+	if len(h.duplicateGroups) != 1 {
+		t.Fatalf("duplicateGroups = %d, want 1", len(h.duplicateGroups))
+	}
+	if h.err == nil || !strings.Contains(h.err.Error(), "1 possible duplicate") {
+		t.Fatalf("expected an announcement, got %v", h.err)
+	}
+
+	h.clearError()
+	h.scanDuplicates()
+	if h.err != nil {
+		t.Fatalf("an unchanged suggestion must not be re-announced: %v", h.err)
+	}
+
+	h.openDuplicatesDialog()
+	if !h.duplicatesDialog.IsVisible() {
+		t.Fatal("dialog should open on the suggestions")
+	}
+	h.handleDuplicatesDialogKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	if h.duplicatesDialog.IsVisible() || !h.dismissedDuplicates["a,b"] {
+		t.Fatalf("ignore should close the last group and remember it, dismissed=%v", h.dismissedDuplicates)
+	}
+	h.scanDuplicates()
+	if len(h.duplicateGroups) != 0 {
+		t.Fatal("dismissed group must not be suggested again")
+	}
+}
+
+func TestDuplicatesDialog_MergeKeepsHighlighted(t *testing.T) {
+	h, _ := duplicatesTestHome(t)
+	h.openDuplicatesDialog()
+	h.handleDuplicatesDialogKey(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd := h.handleDuplicatesDialogKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("enter should emit a merge")
+	}
+	msg, ok := cmd().(duplicateMergeMsg)
+	if !ok || msg.keepID != "b" || fmt.Sprint(msg.mergeIDs) != "[a]" {
+		t.Fatalf("merge msg = %+v, want keep b, merge [a]", msg)
+	}
+}
+
+func TestHome_MergeDuplicatesReparentsAndCarriesNotes(t *testing.T) {
+	h, insts := duplicatesTestHome(t)
+	if cmd := h.mergeDuplicates(duplicateMergeMsg{keepID: "a", mergeIDs: []string{"b"}}); cmd == nil {
+		t.Fatal("merge should archive the merged session")
+	}
+	if kid := insts[2]; kid.ParentSessionID != "a" || kid.ParentProjectPath != "/src/api" {
+		t.Fatalf("child not reparented: parent=%q path=%q", kid.ParentSessionID, kid.ParentProjectPath)
+	}
+	if !strings.Contains(insts[0].Notes, "[from api refactor 2]\nhalf done") {
+		t.Fatalf("notes not carried over: %q", insts[0].Notes)
+	}
+}
+
+func TestDuplicatesDialog_RemoveOpensDeleteConfirm(t *testing.T) {
+	h, _ := duplicatesTestHome(t)
+	h.openDuplicatesDialog()
+	_, cmd := h.handleDuplicatesDialogKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if cmd == nil || h.duplicatesDialog.IsVisible() {
+		t.Fatal("x should close the dialog and request removal")
+	}
+	h.Update(cmd())
+	if !h.confirmDialog.IsVisible() || h.confirmDialog.GetTargetID() != "a" {
+		t.Fatalf("delete confirmation not shown for a (target %q)", h.confirmDialog.GetTargetID())
+	}
+}
@@ -0,0 +1,324 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// extensionRunTimeout bounds an extension run from the TUI. It runs in the
+// background with no terminal, so one that waits on input must not linger.
+const extensionRunTimeout = 2 * time.Minute
+
+// extensionResultMsg reports a finished TUI extension run.
+type extensionResultMsg struct {
+	name   string
+	output string
+	err    error
+}
+
+// ExtensionPickerDialog lists the extensions (agent-deck-<name> on PATH and
+// [extensions] in config.toml) for running one against the selected session.
+// Typing filters by name and description; with an empty filter 1-9 pick
+// directly. Enter/esc are handled by Home, like PromptPickerDialog.
+type ExtensionPickerDialog struct {
+	visible       bool
+	width, height int
+	entries       []session.Extension
+	filtered      []session.Extension
+	filter        string
+	cursor        int
+	instanceID    string
+	sessionTitle  string
+}
+
+// NewExtensionPickerDialog creates the dialog (hidden).
+func NewExtensionPickerDialog() *ExtensionPickerDialog {
+	return &ExtensionPickerDialog{}
+}
+
+// Show opens the picker for the given session. Returns false and stays
+// hidden when there are no extensions.
+func (d *ExtensionPickerDialog) Show(instanceID, sessionTitle string, exts []session.Extension) bool {
+	if len(exts) == 0 {
+		return false
+	}
+	d.entries = exts
+	d.visible = true
+	d.instanceID = instanceID
+	d.sessionTitle = sessionTitle
+	d.setFilter("")
+	return true
+}
+
+// Hide closes the dialog and clears its state.
+func (d *ExtensionPickerDialog) Hide() {
+	d.visible = false
+	d.entries = nil
+	d.filtered = nil
+	d.filter = ""
+	d.cursor = 0
+	d.instanceID = ""
+	d.sessionTitle = ""
+}
+
+// IsVisible reports whether the dialog is shown. Nil-safe.
+func (d *ExtensionPickerDialog) IsVisible() bool { return d != nil && d.visible }
+
+// SetSize updates the dialog dimensions for centering.
+func (d *ExtensionPickerDialog) SetSize(w, h int) {
+	if d == nil {
+		return
+	}
+	d.width = w
+	d.height = h
+}
+
+// InstanceID returns the session the picker was opened for.
+func (d *ExtensionPickerDialog) InstanceID() string { return d.instanceID }
+
+// GetSelected returns the extension at the cursor; ok is false when the
+// filter matches nothing.
+func (d *ExtensionPickerDialog) GetSelected() (session.Extension, bool) {
+	if d.cursor < 0 || d.cursor >= len(d.filtered) {
+		return session.Extension{}, false
+	}
+	return d.filtered[d.cursor], true
+}
+
+// setFilter narrows the list to entries whose name or description contains
+// filter (case-insensitive) and resets the cursor.
+func (d *ExtensionPickerDialog) setFilter(filter string) {
+	d.filter = filter
+	d.cursor = 0
+	d.filtered = d.filtered[:0]
+	q := strings.ToLower(filter)
+	for _, e := range d.entries {
+		if q == "" || strings.Contains(strings.ToLower(e.Name), q) ||
+			strings.Contains(strings.ToLower(e.Description), q) {
+			d.filtered = append(d.filtered, e)
+		}
+	}
+}
+
+// Update handles navigation and filter keys. It returns true when the key
+// picked an entry by number (1-9 with an empty filter).
+func (d *ExtensionPickerDialog) Update(msg tea.KeyMsg) bool {
+	if !d.IsVisible() {
+		return false
+	}
+	switch msg.String() {
+	case "down", "ctrl+n":
+		if len(d.filtered) > 0 {
+			d.cursor = (d.cursor + 1) % len(d.filtered)
+		}
+		return false
+	case "up", "ctrl+p":
+		if len(d.filtered) > 0 {
+			d.cursor = (d.cursor - 1 + len(d.filtered)) % len(d.filtered)
+		}
+		return false
+	case "backspace":
+		if d.filter != "" {
+			r := []rune(d.filter)
+			d.setFilter(string(r[:len(r)-1]))
+		}
+		return false
+	}
+	if msg.Type != tea.KeyRunes || msg.Alt {
+		return false
+	}
+	if d.filter == "" && len(msg.Runes) == 1 && msg.Runes[0] >= '1' && msg.Runes[0] <= '9' {
+		if n := int(msg.Runes[0] - '1'); n < len(d.filtered) {
+			d.cursor = n
+			return true
+		}
+		return false
+	}
+	for _, r := range msg.Runes {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	d.setFilter(d.filter + string(msg.Runes))
+	return false
+}
+
+// extensionPickerDialogChrome counts the rows around the entry list: border
+// and padding (4), title, session line, filter line, blank, two overflow
+// markers, blank, path line, blank, footer.
+const extensionPickerDialogChrome = 14
+
+// visibleRows returns how many entry rows fit on screen.
+func (d *ExtensionPickerDialog) visibleRows() int {
+	const def = 10
+	if d.height <= 0 {
+		return def
+	}
+	rows := d.height - extensionPickerDialogChrome
+	if rows < 1 {
+		return 1
+	}
+	if rows > def {
+		return def
+	}
+	return rows
+}
+
+// View renders the dialog.
+func (d *ExtensionPickerDialog) View() string {
+	if !d.IsVisible() {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorAccent)
+	sourceStyle := lipgloss.NewStyle().Foreground(ColorTextDim)
+	selectedStyle := lipgloss.NewStyle().Foreground(ColorAccent).Bold(true)
+	normalStyle := lipgloss.NewStyle().Foreground(ColorText)
+	dimStyle := lipgloss.NewStyle().Foreground(ColorComment)
+	footerStyle := lipgloss.NewStyle().Foreground(ColorComment).Italic(true)
+
+	dialogWidth := fitDialogWidth(76, 40, d.width)
+	innerWidth := dialogWidth - 4
+	if innerWidth < 1 {
+		innerWidth = 1
+	}
+	fit := func(s string) string { return cellTruncate(s, innerWidth, "…") }
+
+	var lines []string
+	lines = append(lines, fit(titleStyle.Render(fmt.Sprintf("Extensions (%d)", len(d.entries)))))
+	lines = append(lines, fit(sourceStyle.Render(fmt.Sprintf("Run for: %q", d.sessionTitle))))
+	if d.filter != "" {
+		lines = append(lines, fit(normalStyle.Render("Filter: "+d.filter)))
+	} else {
+		lines = append(lines, fit(dimStyle.Render("Type to filter, 1-9 to run")))
+	}
+	lines = append(lines, "")
+
+	if len(d.filtered) == 0 {
+		lines = append(lines, fit(dimStyle.Render("  No extensions match")))
+	}
+	start, end := windowBounds(d.cursor, len(d.filtered), d.visibleRows())
+	if start > 0 {
+		lines = append(lines, fit(dimStyle.Render(fmt.Sprintf("  ↑ %d more", start))))
+	}
+	for i := start; i < end; i++ {
+		e := d.filtered[i]
+		label := fmt.Sprintf("%d. %s", i+1, e.Name)
+		if e.Description != "" {
+			label += "  " + dimStyle.Render(e.Description)
+		}
+		if i == d.cursor {
+			lines = append(lines, fit("> "+selectedStyle.Render(label)))
+		} else {
+			lines = append(lines, fit("  "+normalStyle.Render(label)))
+		}
+	}
+	if end < len(d.filtered) {
+		lines = append(lines, fit(dimStyle.Render(fmt.Sprintf("  ↓ %d more", len(d.filtered)-end))))
+	}
+
+	if sel, ok := d.GetSelected(); ok {
+		lines = append(lines, "")
+		lines = append(lines, fit(sourceStyle.Render("│ "+strings.Join(append([]string{sel.Path}, sel.Args...), " "))))
+	}
+
+	lines = append(lines, "")
+	footer := "Enter run | Esc close | ↑/↓ navigate"
+	if cellWidth(footer) > innerWidth {
+		footer = "Enter run | Esc | ↑/↓"
+	}
+	lines = append(lines, fit(footerStyle.Render(footer)))
+
+	box := DialogBoxStyle.Width(dialogWidth).Render(strings.Join(lines, "\n"))
+	return centerInScreen(box, d.width, d.height)
+}
+
+// openExtensionPicker shows the extension picker for inst. Unlike the prompt
+// picker it doesn't need a running session: extensions get the session's
+// metadata, not its pane.
+func (h *Home) openExtensionPicker(inst *session.Instance) {
+	if inst == nil {
+		return
+	}
+	if h.extensionPickerDialog == nil {
+		h.extensionPickerDialog = NewExtensionPickerDialog()
+	}
+	h.extensionPickerDialog.SetSize(h.width, h.height)
+	if !h.extensionPickerDialog.Show(inst.ID, inst.Title, session.GetExtensions()) {
+		h.setError(fmt.Errorf("no extensions found; put an agent-deck-<name> executable on PATH or add [extensions.<name>] to config.toml"))
+	}
+}
+
+// handleExtensionPickerDialogKey runs the picked extension on enter (or a
+// number) and routes everything else to the dialog.
+func (h *Home) handleExtensionPickerDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		h.extensionPickerDialog.Hide()
+		return h, nil
+	case "enter":
+	default:
+		if !h.extensionPickerDialog.Update(msg) {
+			return h, nil
+		}
+	}
+
+	ext, ok := h.extensionPickerDialog.GetSelected()
+	instanceID := h.extensionPickerDialog.InstanceID()
+	h.extensionPickerDialog.Hide()
+	if !ok {
+		return h, nil
+	}
+	inst := h.getInstanceByID(instanceID)
+	if inst == nil {
+		h.setError(fmt.Errorf("extension target session no longer exists"))
+		return h, nil
+	}
+	return h, runExtensionCmd(ext, session.NewExtensionContext(session.GetEffectiveProfile(h.profile), inst))
+}
+
+// runExtensionCmd runs ext in the background with the context as JSON on
+// stdin, and reports its combined output.
+func runExtensionCmd(ext session.Extension, ectx session.ExtensionContext) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), extensionRunTimeout)
+		defer cancel()
+		cmd := ext.Command(ctx, nil, ectx)
+		cmd.Stdin = bytes.NewReader(ectx.JSON())
+		out, err := cmd.CombinedOutput()
+		if ctx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("timed out after %s", extensionRunTimeout)
+		}
+		return extensionResultMsg{name: ext.Name, output: string(out), err: err}
+	}
+}
+
+// extensionResultText is the footer line for a finished run: the last line
+// of output, which is where a script's summary or error usually is.
+func extensionResultText(msg extensionResultMsg) string {
+	var last string
+	for _, line := range strings.Split(strings.TrimSpace(msg.output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			last = line
+		}
+	}
+	if msg.err != nil {
+		if last != "" {
+			return fmt.Sprintf("extension %s failed (%v): %s", msg.name, msg.err, last)
+		}
+		return fmt.Sprintf("extension %s failed: %v", msg.name, msg.err)
+	}
+	if last != "" {
+		return fmt.Sprintf("%s: %s", msg.name, last)
+	}
+	return fmt.Sprintf("extension %s finished", msg.name)
+}
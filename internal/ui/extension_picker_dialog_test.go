@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+func TestExtensionPickerDialog_FilterAndNumberPick(t *testing.T) {
+	d := NewExtensionPickerDialog()
+	d.SetSize(100, 40)
+	if d.Show("id1", "api", nil) || d.IsVisible() {
+		t.Fatal("no extensions must not open the picker")
+	}
+
+	d.Show("id1", "api", []session.Extension{
+		{Name: "deploy", Path: "/opt/bin/deploy.sh", Args: []string{"--dry-run"}, Description: "ship it"},
+		{Name: "review", Path: "/usr/local/bin/agent-deck-review"},
+	})
+	if !d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")}) {
+		t.Fatal("2 with an empty filter should pick the second entry")
+	}
+	if ext, _ := d.GetSelected(); ext.Name != "review" {
+		t.Fatalf("2 selected %q", ext.Name)
+	}
+
+	for _, r := range "SHIP" {
+		d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if ext, ok := d.GetSelected(); !ok || ext.Name != "deploy" || len(d.filtered) != 1 {
+		t.Fatalf("filter on description: %q %v (%d shown)", ext.Name, ok, len(d.filtered))
+	}
+	view := d.View()
+	for _, want := range []string{"Extensions (2)", `Run for: "api"`, "Filter: SHIP", "1. deploy", "│ /opt/bin/deploy.sh --dry-run"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestExtensionResultText(t *testing.T) {
+	cases := []struct {
+		msg  extensionResultMsg
+		want string
+	}{
+		{extensionResultMsg{name: "review", output: "checking\nall good\n\n"}, "review: all good"},
+		{extensionResultMsg{name: "review"}, "extension review finished"},
+		{extensionResultMsg{name: "deploy", output: "no credentials\n", err: errors.New("exit status 2")}, "extension deploy failed (exit status 2): no credentials"},
+		{extensionResultMsg{name: "deploy", err: errors.New("timed out after 2m0s")}, "extension deploy failed: timed out after 2m0s"},
+	}
+	for _, tc := range cases {
+		if got := extensionResultText(tc.msg); got != tc.want {
+			t.Errorf("extensionResultText(%+v) = %q, want %q", tc.msg, got, tc.want)
+		}
+	}
+}
@@ -182,6 +182,7 @@ func (h *HelpOverlay) View() string {
 	restartFreshKey := h.key(hotkeyRestartFresh, "Shift+T")
 	renameKey := h.key(hotkeyRename, "r")
 	moveKey := h.key(hotkeyMoveToGroup, "M")
+	selectKey := h.key(hotkeySelectMode, "B")
 	mcpKey := h.key(hotkeyMCPManager, "m")
 	pluginKey := h.key(hotkeyPluginManager, "L")
 	skillsKey := h.key(hotkeySkillsManager, "s")
@@ -195,11 +196,13 @@ func (h *HelpOverlay) View() string {
 	unreadKey := h.key(hotkeyMarkUnread, "u")
 	quickApproveKey := h.key(hotkeyQuickApprove, "a")
 	promptSessionKey := h.key(hotkeyPromptSession, "o")
+	continueKey := h.key(hotkeyContinueSession, "Alt+c")
 	copyKey := h.key(hotkeyCopyOutput, "c")
 	copyPaneKey := h.key(hotkeyCopyPane, "V")
 	sendKey := h.key(hotkeySendOutput, "x")
 	execShellKey := h.key(hotkeyExecShell, "E")
 	openShellHereKey := h.key(hotkeyOpenShellHere, "h")
+	attachPaneKey := h.key(hotkeyAttachPane, "Alt+Enter")
 	notesKey := h.key(hotkeyEditNotes, "e")
 	if cfg, _ := session.LoadUserConfig(); cfg != nil && !cfg.GetShowNotes() {
 		notesKey = ""
@@ -208,7 +211,19 @@ func (h *HelpOverlay) View() string {
 	editSessionKey := h.key(hotkeyEditSession, "P")
 	worktreeSetupKey := h.key(hotkeyWorktreeSetup, "b")
 	worktreeKey := h.key(hotkeyWorktreeFinish, "W")
+	worktreeDiffKey := h.key(hotkeyWorktreeDiff, "Alt+w")
 	watcherPanelKey := h.key(hotkeyWatcherPanel, "w")
+	mouseKey := h.key(hotkeyToggleMouse, "Alt+m")
+	previewPaneKey := h.key(hotkeyPreviewPane, "Alt+v")
+	duplicatesKey := h.key(hotkeyReviewDuplicates, "Alt+d")
+	tasksKey := h.key(hotkeyTasksPanel, "Alt+t")
+	taskQueueKey := h.key(hotkeyTaskQueue, "Alt+q")
+	boardKey := h.key(hotkeyBoardView, "Alt+b")
+	manifestKey := h.key(hotkeyApplyManifest, "Alt+p")
+	outputHistoryKey := h.key(hotkeyOutputHistory, "Alt+o")
+	statusTimelineKey := h.key(hotkeyStatusTimeline, "Alt+h")
+	promptLibraryKey := h.key(hotkeyPromptLibrary, "Alt+l")
+	extensionsKey := h.key(hotkeyExtensions, "Alt+x")
 	groupKey := h.key(hotkeyCreateGroup, "g")
 	undoKey := h.key(hotkeyUndoDelete, "Ctrl+Z")
 	archiveKey := h.key(hotkeyArchiveSession, "A")
@@ -251,7 +266,7 @@ func (h *HelpOverlay) View() string {
 			items: [][2]string{
 				{newKeys, "New / quick create"},
 				{renameKey, "Rename session"},
-				{restartKey, "Restart session"},
+				{restartKey, "Restart session (on a group: restart all, as a background task)"},
 				{restartFreshKey, "Restart with new session ID"},
 				{deleteKey, "Delete session"},
 				{closeKey, "Close session process"},
@@ -260,16 +275,21 @@ func (h *HelpOverlay) View() string {
 				{unarchiveKey, "Unarchive session"},
 				{viewArchivedKey, "Toggle archived view"},
 				{moveKey, "Move to group"},
+				{selectKey, "Select mode: Space marks sessions (or a whole group); delete / move / restart / MCP then act on all marked"},
 				{mcpKey, "MCP Manager (Claude/Gemini/Cursor)"},
 				{pluginKey, "Plugin Manager (Claude — RFC PLUGIN_ATTACH.md)"},
 				{skillsKey, "Skills Manager"},
 				{"$", "Cost Dashboard"},
 				{previewKey, "Toggle preview mode (output/stats/both)"},
+				{previewPaneKey, "Show / hide preview pane"},
 				{"O", "Toggle preview orientation (right / below — portrait monitors)"},
 				{"< / >", "Shrink / grow preview pane by 5% (drag divider with mouse; vertical in below-orientation)"},
 				{unreadKey, "Mark unread"},
 				{quickApproveKey, "Quick approve (send '1' to Claude)"},
 				{promptSessionKey, "Prompt session (send a one-line prompt without attaching)"},
+				{promptLibraryKey, "Prompt library (send a saved prompt)"},
+				{extensionsKey, "Run an extension (agent-deck-<name> command) for the session"},
+				{continueKey, "Continue (nudge an idle session with its continuation prompt)"},
 				{reorderUpKeys, "Reorder up (auto-promote at edge)"},
 				{reorderDownKeys, "Reorder down (auto-promote at edge)"},
 				{indentKeys, "Indent / outdent (in group)"},
@@ -278,10 +298,13 @@ func (h *HelpOverlay) View() string {
 				{copyKey, "Copy output to clipboard"},
 				{"C", "Copy preview info (Repo / Path / Branch)"},
 				{"Y", "Copy a code block from output"},
+				{outputHistoryKey, "Output history (copy an earlier response)"},
+				{statusTimelineKey, "Status timeline (running vs waiting)"},
 				{copyPaneKey, "Copy visible terminal text, including links"},
 				{sendKey, "Send output to session"},
 				{execShellKey, "Exec shell in sandbox container"},
 				{openShellHereKey, "Open shell in session's worktree (split pane / tmux)"},
+				{attachPaneKey, "Attach in a tmux split beside the deck"},
 				{editPathsKey, "Edit multi-repo paths"},
 				{editSessionKey, "Edit session settings (title/color/...)"},
 				{notesKey, "Edit notes"},
@@ -291,6 +314,7 @@ func (h *HelpOverlay) View() string {
 			title: "WORKTREES",
 			items: [][2]string{
 				{worktreeSetupKey, "Re-run worktree setup script"},
+				{worktreeDiffKey, "Review worktree diff before merging"},
 				{worktreeKey, "Finish worktree (merge + cleanup)"},
 				{"n → w", "Create session in worktree"},
 				{"F → w", "Fork session into worktree"},
@@ -327,6 +351,12 @@ func (h *HelpOverlay) View() string {
 				{settingsKey, "Settings"},
 				{reloadKey, "Reload from disk"},
 				{importKey, "Import tmux sessions"},
+				{mouseKey, "Cycle mouse capture (full / click-only / off for text selection)"},
+				{duplicatesKey, "Review possible duplicate sessions (merge / remove)"},
+				{tasksKey, "Background tasks (progress, logs, cancel)"},
+				{taskQueueKey, "Task queue (dispatch, mark done / failed)"},
+				{boardKey, "Board view: sessions in waiting / running / idle / error columns"},
+				{manifestKey, "Apply the project's .agentdeck.toml (create declared sessions)"},
 				{"Ctrl+Q", "Detach from session"},
 				{switchKey, "Switch session (here or attached)"},
 				{scrollbackKey, "Scrollback pager (while attached)"},
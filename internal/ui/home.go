@@ -36,6 +36,7 @@ import (
 	"github.com/asheshgoplani/agent-deck/internal/git"
 	"github.com/asheshgoplani/agent-deck/internal/jujutsu"
 	"github.com/asheshgoplani/agent-deck/internal/logging"
+	"github.com/asheshgoplani/agent-deck/internal/progress"
 	"github.com/asheshgoplani/agent-deck/internal/safego"
 	"github.com/asheshgoplani/agent-deck/internal/send"
 	"github.com/asheshgoplani/agent-deck/internal/session"
@@ -118,6 +119,14 @@ const (
 	// Prevents repeated /clear if context fills up again quickly
 	clearOnCompactCooldown = 60 * time.Second
 
+	// statusCountersRefresh - how often unchanged status counters are rewritten
+	// Must stay well under session.StatusCountersMaxAge or `status -q` falls back
+	statusCountersRefresh = 10 * time.Second
+
+	// portClaimsRefresh - how often the preview's port registry snapshot is
+	// re-read; claims change from the CLI, outside this process
+	portClaimsRefresh = 5 * time.Second
+
 	// attach-return grace periods keep the main menu responsive right after tea.Exec returns.
 	attachReturnHotDuration  = 1200 * time.Millisecond
 	attachReturnRefreshDelay = 350 * time.Millisecond
@@ -239,37 +248,48 @@ type Home struct {
 	headless bool
 
 	// Components
-	search               *Search
-	globalSearch         *GlobalSearch              // Global session search across all Claude conversations
-	globalSearchIndex    *session.GlobalSearchIndex // Search index (nil if disabled)
-	newDialog            *NewDialog
-	pendingRemoteName    string                // #1353: remote target for the open new-session dialog ("" = local)
-	groupDialog          *GroupDialog          // For creating/renaming groups
-	forkDialog           *ForkDialog           // For forking sessions
-	confirmDialog        *ConfirmDialog        // For confirming destructive actions
-	helpOverlay          *HelpOverlay          // For showing keyboard shortcuts
-	mcpDialog            *MCPDialog            // For managing MCPs
-	pluginDialog         *PluginDialog         // For managing per-session Claude Code plugins (RFC PLUGIN_ATTACH.md)
-	editPathsDialog      *EditPathsDialog      // For editing multi-repo paths
-	editSessionDialog    *EditSessionDialog    // For editing session settings (title/color/notes/command/...)
-	skillDialog          *SkillDialog          // For managing project skills
-	setupWizard          *SetupWizard          // For first-run setup
-	settingsPanel        *SettingsPanel        // For editing settings
-	analyticsPanel       *AnalyticsPanel       // For displaying session analytics
-	geminiModelDialog    *GeminiModelDialog    // For selecting Gemini model
-	promptInputDialog    *PromptInputDialog    // For prompting the highlighted session from the list without attaching (#1410)
-	sessionPickerDialog  *SessionPickerDialog  // For sending output to another session
-	codeBlockDialog      *CodeBlockDialog      // For copying a fenced code block from session output (#1412)
-	sessionSwitcher      *SessionSwitcher      // In-attach session switcher (Ctrl+Tab / Ctrl+S)
-	scrollbackPager      *ScrollbackPager      // In-attach scrollback pager for the deck's control-mode view (#1491)
-	worktreeFinishDialog *WorktreeFinishDialog // For finishing worktree sessions (merge + cleanup)
-	feedbackDialog       *FeedbackDialog       // For in-app feedback popup (Phase 2)
-	zoxidePicker         *ZoxidePicker         // Quick-open picker backed by the zoxide DB
-	feedbackState        *feedback.State       // Loaded at first show, avoids repeated disk I/O
-	feedbackSender       *feedback.Sender      // Sender constructed once in NewHome (Phase 3, per D-05)
-	watcherPanel         *WatcherPanel         // For showing watcher status and events
-	toolVisibilityPanel  *ToolVisibilityPanel  // Edits [ui].hidden_tools
-	watcherEngine        *watcher.Engine       // nil until Init (D-07: lifecycle tied to TUI startup)
+	search                *Search
+	globalSearch          *GlobalSearch              // Global session search across all Claude conversations
+	globalSearchIndex     *session.GlobalSearchIndex // Search index (nil if disabled)
+	newDialog             *NewDialog
+	pendingRemoteName     string                 // #1353: remote target for the open new-session dialog ("" = local)
+	groupDialog           *GroupDialog           // For creating/renaming groups
+	forkDialog            *ForkDialog            // For forking sessions
+	confirmDialog         *ConfirmDialog         // For confirming destructive actions
+	helpOverlay           *HelpOverlay           // For showing keyboard shortcuts
+	mcpDialog             *MCPDialog             // For managing MCPs
+	pluginDialog          *PluginDialog          // For managing per-session Claude Code plugins (RFC PLUGIN_ATTACH.md)
+	editPathsDialog       *EditPathsDialog       // For editing multi-repo paths
+	editSessionDialog     *EditSessionDialog     // For editing session settings (title/color/notes/command/...)
+	skillDialog           *SkillDialog           // For managing project skills
+	setupWizard           *SetupWizard           // For first-run setup
+	settingsPanel         *SettingsPanel         // For editing settings
+	analyticsPanel        *AnalyticsPanel        // For displaying session analytics
+	geminiModelDialog     *GeminiModelDialog     // For selecting Gemini model
+	promptInputDialog     *PromptInputDialog     // For prompting the highlighted session from the list without attaching (#1410)
+	retryStartDialog      *RetryStartDialog      // For editing the command and retrying a failed start
+	duplicatesDialog      *DuplicatesDialog      // Possible-duplicate suggestions with merge/remove
+	tasksPanel            *TasksPanel            // Background tasks with progress, logs and cancel
+	boardView             *BoardView             // Sessions as status columns (board.go)
+	tasks                 *TaskManager           // Long-running operations (group restart, worktree creation)
+	sessionPickerDialog   *SessionPickerDialog   // For sending output to another session
+	codeBlockDialog       *CodeBlockDialog       // For copying a fenced code block from session output (#1412)
+	outputHistoryDialog   *OutputHistoryDialog   // Recent agent responses of a session, for copying
+	promptPickerDialog    *PromptPickerDialog    // Saved prompts ([prompts] in config.toml) to send to a session
+	extensionPickerDialog *ExtensionPickerDialog // Extensions (agent-deck-<name> commands) to run for a session
+	statusTimelineDialog  *StatusTimelineDialog  // When a session was running vs waiting
+	taskQueueDialog       *TaskQueueDialog       // Work items queued with `agent-deck task add`
+	sessionSwitcher       *SessionSwitcher       // In-attach session switcher (Ctrl+Tab / Ctrl+S)
+	scrollbackPager       *ScrollbackPager       // In-attach scrollback pager for the deck's control-mode view (#1491)
+	worktreeFinishDialog  *WorktreeFinishDialog  // For finishing worktree sessions (merge + cleanup)
+	worktreeDiffDialog    *WorktreeDiffDialog    // Review a worktree branch against its base before merging
+	feedbackDialog        *FeedbackDialog        // For in-app feedback popup (Phase 2)
+	zoxidePicker          *ZoxidePicker          // Quick-open picker backed by the zoxide DB
+	feedbackState         *feedback.State        // Loaded at first show, avoids repeated disk I/O
+	feedbackSender        *feedback.Sender       // Sender constructed once in NewHome (Phase 3, per D-05)
+	watcherPanel          *WatcherPanel          // For showing watcher status and events
+	toolVisibilityPanel   *ToolVisibilityPanel   // Edits [ui].hidden_tools
+	watcherEngine         *watcher.Engine        // nil until Init (D-07: lifecycle tied to TUI startup)
 
 	// Configurable hotkeys
 	hotkeys        map[string]string // action -> configured key
@@ -301,6 +321,7 @@ type Home struct {
 	initialSelect       string                // Session ID or title to preselect on first load (#709). Does NOT scope groups.
 	initialSelectDone   bool                  // Guard so preselection only fires once
 	previewMode         PreviewMode           // What to show in preview pane (both, output-only, analytics-only)
+	previewHidden       bool                  // Preview pane hidden by the user; the list takes the full width
 	groupViewMode       session.GroupViewMode // List partition: normal, active-on-top, populated-on-top (cycled by hotkey 't')
 	err                 error
 	errTime             time.Time  // When error occurred (for auto-dismiss)
@@ -332,12 +353,24 @@ type Home struct {
 	statusTrigger       chan statusUpdateRequest // Triggers background status update
 	statusWorkerDone    chan struct{}            // Signals worker has stopped
 	lastFullStatusSweep atomic.Int64             // UnixNano timestamp of last full background status sweep
-	lastPersistedStatus map[string]string        // instanceID -> last status written to SQLite
+	// pollScheduler tiers per-session status polling ([performance.polling]);
+	// lastTieredSweep is when the sweep last ran regardless of due sessions.
+	pollScheduler       *tmux.PollScheduler
+	lastTieredSweep     atomic.Int64
+	lastPersistedStatus map[string]string // instanceID -> last status written to SQLite
 	// lastPersistedAutoNameDesc tracks the last auto-name description written to
 	// SQLite per instance, so the background loop only issues a targeted write
 	// when the live Claude task description actually changes (mirrors
 	// lastPersistedStatus). Keyed by instance ID.
 	lastPersistedAutoNameDesc map[string]string
+	// stateFiles mirrors each session into <profile-dir>/state/<id>.json for
+	// external statusline/widget readers. Nil when [state_files] is disabled.
+	stateFiles *session.StateFileWriter
+	// transcripts appends new pane output of running sessions to their
+	// transcript files. Nil when [transcripts] is disabled. Ticked from the
+	// status worker, rate-limited by transcriptsLastTick.
+	transcripts         *session.TranscriptRecorder
+	transcriptsLastTick atomic.Int64 // UnixNano
 
 	// Issue #1143: auto-stop dormant child sessions via central poll.
 	// Coalesced into the existing 2-second statusWorker tick by way of
@@ -346,6 +379,26 @@ type Home struct {
 	idleTimeoutWatcher  *session.IdleTimeoutWatcher
 	idleTimeoutLastTick atomic.Int64 // UnixNano
 
+	// Group working hours: stops sessions outside their group's
+	// working_hours window and restarts them when it opens. Ticked from the
+	// same sweep as idleTimeoutWatcher, once a minute.
+	workingHoursWatcher  *session.WorkingHoursWatcher
+	workingHoursLastTick atomic.Int64 // UnixNano
+
+	// Worktree sync: background rebase onto the base branch when
+	// [worktree] auto_sync_minutes is set, plus the last sync state of
+	// every worktree session for the conflict badge.
+	worktreeSyncWatcher  *session.WorktreeSyncWatcher
+	worktreeSyncLastTick atomic.Int64 // UnixNano
+
+	// Automatic checkpoints for sessions with a checkpoint mode (see
+	// `session set <id> checkpoint`).
+	checkpointWatcher *session.CheckpointWatcher
+
+	// nextAutoArchive is when the [archive] auto_archive_after_days sweep
+	// next runs (see auto_archive.go). UI goroutine only.
+	nextAutoArchive time.Time
+
 	// PERFORMANCE: Worker pool for output-driven status updates (Priority 2)
 	// Caps the number of goroutines spawned for %output events from control pipes
 	logUpdateChan chan *session.Instance // Buffers status update requests from PipeManager
@@ -363,9 +416,21 @@ type Home struct {
 	worktreeDirtyCacheTs map[string]time.Time // sessionID -> cache timestamp
 	worktreeDirtyMu      sync.Mutex           // Protects dirty cache maps
 
+	// Worktree pull request cache (lazy, 2m TTL; nil entry = no PR or lookup failed)
+	worktreePRCache   map[string]*git.PullRequest // sessionID -> PR
+	worktreePRCacheTs map[string]time.Time        // sessionID -> cache timestamp
+	worktreePRMu      sync.Mutex                  // Protects PR cache maps
+
 	// Memory management: periodic cache pruning
 	lastCachePrune time.Time
 
+	// Duplicate-session advisor (see duplicates_dialog.go). Scanned every
+	// duplicateScanInterval; dismissed keys are remembered for this run.
+	duplicateGroups     []session.DuplicateGroup
+	dismissedDuplicates map[string]bool
+	lastDuplicateScan   time.Time
+	announcedDuplicates int
+
 	// Hook-based status detection (Claude Code lifecycle hooks)
 	hookWatcher        *session.StatusFileWatcher
 	pendingHooksPrompt bool // True if user should be prompted to install hooks
@@ -424,6 +489,22 @@ type Home struct {
 	// SQLite heartbeat: tracks when we last cleaned dead instances
 	lastDeadInstanceCleanup time.Time
 
+	// Status counters published for `agent-deck status -q`; rewritten on
+	// change and refreshed every statusCountersRefresh so readers can tell a
+	// live TUI from a stale row.
+	lastStatusCounters        statedb.StatusCounters
+	lastStatusCountersPublish time.Time
+
+	// Port registry snapshot shown in the preview (sessionID -> claims),
+	// re-read every portClaimsRefresh.
+	portClaims            map[string][]*statedb.PortClaim
+	portClaimsMu          sync.Mutex
+	lastPortClaimsRefresh time.Time
+
+	// lastSubagentSig is the subagentSignature the flat list was last built
+	// with. UI goroutine only.
+	lastSubagentSig string
+
 	// User activity tracking for adaptive status updates
 	// PERFORMANCE: Only update statuses when user is actively interacting
 	lastUserInputTime time.Time // When user last pressed a key
@@ -451,6 +532,11 @@ type Home struct {
 	jumpMode   bool   // True when jump mode is active
 	jumpBuffer string // Characters typed so far in jump mode
 
+	// Select mode (multi_select.go): sessions marked for batch actions
+	selectMode  bool
+	marked      map[string]bool // marked session IDs
+	batchMCPIDs []string        // other sessions a batch MCP attach applies to
+
 	// Cached status counts (invalidated on instance changes)
 	cachedStatusCounts struct {
 		running, waiting, idle, stopped, errored int
@@ -485,6 +571,9 @@ type Home struct {
 	// description) suffix on every session row instead of only the selected
 	// one. Cached here so all rows of a frame agree; reloaded after panel save.
 	showPaneTitles bool
+	// showSessionCost appends the session's estimated spend to its row; see
+	// refreshSessionCosts for where the numbers come from.
+	showSessionCost bool
 
 	// Sessions/Preview split (issue #1092): percentage of width allocated to
 	// preview pane. Loaded from config.toml [ui] preview_pct, adjustable
@@ -511,6 +600,15 @@ type Home struct {
 	// false: today's select-only behavior. See sessionCreatedMsg handling.
 	attachOnCreate bool
 
+	// mouseMode is the base mouse capture mode (config.toml [ui] mouse,
+	// cycled for this run by the toggle_mouse hotkey); mouseScreenModes
+	// holds [ui].mouse_screens overrides. appliedMouseMode is what the
+	// terminal was last switched to, starting at "full" to match
+	// tea.WithMouseCellMotion. See mouse_mode.go.
+	mouseMode        string
+	mouseScreenModes map[string]string
+	appliedMouseMode string
+
 	// Performance observability (debug mode only, zero cost when off)
 	debugMode          bool         // true when AGENTDECK_DEBUG=1, enables perf overlay
 	lastRenderDuration atomic.Int64 // microseconds, for debug status bar
@@ -526,6 +624,9 @@ type Home struct {
 	boundKeysMu             sync.Mutex        // Protects boundKeys for background worker access
 	lastBarText             string            // Cache to avoid updating all sessions every tick
 	lastBarTextMu           sync.Mutex        // Protects lastBarText for background worker access
+	notificationScope       string            // "session" (per-session status-left) or "global"
+	notificationTemplate    string            // [notifications].template wrapper for the bar text
+	lastBarSessions         []string          // tmux sessions carrying a per-session bar (scope="session")
 
 	// Maintenance banner (shown after background maintenance completes)
 	maintenanceMsg     string
@@ -615,7 +716,12 @@ type Home struct {
 	costLastMonth        atomic.Int64 // microdollars
 	costProjected        atomic.Int64 // microdollars
 	costRefreshTime      time.Time
-	costLineTemplate     string // resolved at construction; see session.ResolveCostLineTemplate
+	costSyncer           *costs.TranscriptSyncer
+	costSyncTime         time.Time
+	costSyncing          atomic.Bool
+	sessionCostsMu       sync.RWMutex
+	sessionCosts         map[string]int64 // microdollars by instance ID
+	costLineTemplate     string           // resolved at construction; see session.ResolveCostLineTemplate
 	costLineHideWhenZero bool
 	showCostDashboard    bool
 	costDashboard        costDashboard
@@ -681,6 +787,11 @@ type Home struct {
 	// When nil, the dispatch calls terminal.OpenSessionInSplitPane directly.
 	// See issue #1470.
 	openInSplitPaneSink func(req terminal.AttachRequest) error
+	// openInTmuxPaneSink is an optional override used by tests to capture
+	// attach-in-pane dispatches (attach_pane hotkey, [ui] attach_mode)
+	// without splitting a real tmux client. When nil, the dispatch calls
+	// terminal.OpenSessionInTmuxPane directly.
+	openInTmuxPaneSink func(req terminal.AttachRequest, mode string) error
 	// quickApproveSink is an optional override used by tests to capture the
 	// quick-approve (`a`) dispatch — the (instance, windowIndex) it would send
 	// "1"+Enter to — without driving real tmux. windowIndex < 0 means the
@@ -702,6 +813,7 @@ type uiState struct {
 	CursorSessionID string `json:"cursor_session_id,omitempty"`
 	CursorGroupPath string `json:"cursor_group_path,omitempty"`
 	PreviewMode     int    `json:"preview_mode"`
+	PreviewHidden   bool   `json:"preview_hidden,omitempty"`
 	StatusFilter    string `json:"status_filter,omitempty"`
 	GroupViewMode   int    `json:"group_view_mode,omitempty"`
 }
@@ -871,6 +983,38 @@ func (h *Home) openPromptInput(inst *session.Instance) {
 	h.promptInputDialog.Show(inst.ID, inst.Title)
 }
 
+// continueSession sends the continuation prompt ([continue] config, else the
+// tool's default) to inst without attaching. Only idle or waiting sessions are
+// nudged; a busy one is refused rather than interrupted mid-turn.
+func (h *Home) continueSession(inst *session.Instance) {
+	if inst == nil {
+		return
+	}
+	if inst.Tool == "shell" {
+		h.setError(fmt.Errorf("session %q is a shell; continue only applies to agent sessions", inst.Title))
+		return
+	}
+	ts := inst.GetTmuxSession()
+	if ts == nil || ts.Name == "" {
+		h.setError(fmt.Errorf("session %q is not running; start it before continuing", inst.Title))
+		return
+	}
+	if err := session.CheckContinuable(inst.Title, inst.GetStatusThreadSafe()); err != nil {
+		h.setError(err)
+		return
+	}
+	text := session.GetContinueSettings().PromptFor(inst.Tool)
+	tmuxName := ts.Name
+	go func() {
+		if err := deliverToConductorPane(ts, text); err != nil {
+			uiLog.Warn("continue_send_failed",
+				slog.String("tmux_session", tmuxName),
+				slog.String("error", err.Error()))
+		}
+	}()
+	h.setError(fmt.Errorf("Sent %q to %s", text, inst.Title))
+}
+
 // resolveITermOpenAs reads the [ui] iterm_open_as setting from the user
 // config, returning "tab" by default if the config can't be loaded or
 // the value is unset/unknown. Issue #1100.
@@ -947,6 +1091,68 @@ func (h *Home) openShellHere(inst *session.Instance) tea.Cmd {
 	return h.attachSession(inst)
 }
 
+// resolveAttachMode reads [ui] attach_mode from the user config, returning
+// session.AttachModeInline when the config can't be loaded or the value is
+// unset/unknown.
+func resolveAttachMode() string {
+	cfg, err := session.LoadUserConfig()
+	if err != nil || cfg == nil {
+		return session.AttachModeInline
+	}
+	return cfg.UI.GetAttachMode()
+}
+
+// paneAttachMode maps the configured attach mode to the pane mode used by the
+// attach_pane hotkey: a new window when "window" is configured, a split
+// otherwise (the hotkey never attaches inline).
+func paneAttachMode(configured string) string {
+	if configured == session.AttachModeWindow {
+		return terminal.TmuxPaneWindow
+	}
+	return terminal.TmuxPaneSplit
+}
+
+// canAttachInPane reports whether attach-in-pane can run: agent-deck must be
+// inside a tmux client so there is a current window to split. The test sink
+// bypasses the check.
+func (h *Home) canAttachInPane() bool {
+	return h.openInTmuxPaneSink != nil || terminal.InsideTmux()
+}
+
+// attachInPane attaches inst in a new split (or window, per mode) of the tmux
+// client agent-deck runs in, instead of handing the whole terminal to the
+// session. The deck keeps running in its own pane, so there is no detach
+// round-trip: status polling picks the session up as usual.
+func (h *Home) attachInPane(inst *session.Instance, mode string) {
+	tmuxSess := inst.GetTmuxSession()
+	if tmuxSess == nil {
+		return
+	}
+	req := terminal.AttachRequest{
+		Name:       tmuxSess.Name,
+		SocketName: tmuxSess.SocketName,
+		Title:      inst.Title,
+	}
+	if h.openInTmuxPaneSink != nil {
+		if err := h.openInTmuxPaneSink(req, mode); err != nil {
+			h.setError(fmt.Errorf("attach in pane: %w", err))
+		}
+		return
+	}
+	if !inst.Exists() {
+		h.setError(fmt.Errorf("session %q is not running; start it before attaching", inst.Title))
+		return
+	}
+	if !terminal.InsideTmux() {
+		h.setError(terminal.ErrNotInTmux)
+		return
+	}
+	h.prepareAttach(inst, tmuxSess)
+	if err := terminal.OpenSessionInTmuxPane(req, mode); err != nil {
+		h.setError(fmt.Errorf("attach in pane: %w", err))
+	}
+}
+
 // collapseOrNavUp implements the "h"/"left" collapse-or-parent navigation:
 // collapses an open group/session-windows, or moves the cursor to the parent
 // group of the focused item. Issue #1470.
@@ -1053,7 +1259,7 @@ type deletedSessionEntry struct {
 // preview_orientation = "below"; narrow terminals always stack.
 func (h *Home) getLayoutMode() string {
 	switch {
-	case h.width < layoutBreakpointSingle:
+	case h.width < layoutBreakpointSingle, h.previewHidden:
 		return LayoutModeSingle
 	case h.width < layoutBreakpointStacked:
 		return LayoutModeStacked
@@ -1236,6 +1442,12 @@ type worktreeDirtyCheckMsg struct {
 	err       error
 }
 
+// worktreePRCheckMsg is sent when an async worktree pull request lookup completes
+type worktreePRCheckMsg struct {
+	sessionID string
+	pr        *git.PullRequest
+}
+
 // worktreeSetupResultMsg is sent when re-running the worktree setup script completes
 type worktreeSetupResultMsg struct {
 	sessionID    string
@@ -1343,11 +1555,22 @@ func NewHomeWithProfileAndMode(profile string) *Home {
 		analyticsPanel:            NewAnalyticsPanel(),
 		geminiModelDialog:         NewGeminiModelDialog(),
 		promptInputDialog:         NewPromptInputDialog(),
+		retryStartDialog:          NewRetryStartDialog(),
+		duplicatesDialog:          NewDuplicatesDialog(),
+		tasksPanel:                NewTasksPanel(),
+		boardView:                 NewBoardView(),
+		tasks:                     NewTaskManager(),
 		sessionPickerDialog:       NewSessionPickerDialog(),
 		codeBlockDialog:           NewCodeBlockDialog(),
+		outputHistoryDialog:       NewOutputHistoryDialog(),
+		promptPickerDialog:        NewPromptPickerDialog(),
+		extensionPickerDialog:     NewExtensionPickerDialog(),
+		statusTimelineDialog:      NewStatusTimelineDialog(),
+		taskQueueDialog:           NewTaskQueueDialog(),
 		sessionSwitcher:           NewSessionSwitcher(),
 		scrollbackPager:           NewScrollbackPager(),
 		worktreeFinishDialog:      NewWorktreeFinishDialog(),
+		worktreeDiffDialog:        NewWorktreeDiffDialog(),
 		feedbackDialog:            NewFeedbackDialog(),
 		zoxidePicker:              NewZoxidePicker(),
 		feedbackSender:            feedback.NewSender(),
@@ -1379,9 +1602,15 @@ func NewHomeWithProfileAndMode(profile string) *Home {
 		windowsCollapsed:          make(map[string]bool),
 		worktreeDirtyCache:        make(map[string]bool),
 		worktreeDirtyCacheTs:      make(map[string]time.Time),
+		worktreePRCache:           make(map[string]*git.PullRequest),
+		worktreePRCacheTs:         make(map[string]time.Time),
 		statusTrigger:             make(chan statusUpdateRequest, 1), // Buffered to avoid blocking
 		statusWorkerDone:          statusWorkerDone,
 		idleTimeoutWatcher:        session.NewIdleTimeoutWatcher(session.IdleTimeoutWatcherConfig{}),
+		workingHoursWatcher:       session.NewWorkingHoursWatcher(session.WorkingHoursWatcherConfig{}),
+		worktreeSyncWatcher:       session.NewWorktreeSyncWatcher(session.WorktreeSyncWatcherConfig{}),
+		checkpointWatcher:         session.NewCheckpointWatcher(session.CheckpointWatcherConfig{}),
+		nextAutoArchive:           time.Now().Add(autoArchiveStartupDelay),
 		lastPersistedStatus:       make(map[string]string),
 		lastPersistedAutoNameDesc: make(map[string]string),
 		logUpdateChan:             make(chan *session.Instance, 100), // Buffered to absorb bursts
@@ -1406,6 +1635,7 @@ func NewHomeWithProfileAndMode(profile string) *Home {
 		// [performance] claim_polling: snapshot once at startup. Defaults to
 		// false (today's behavior); stays false when config is unreadable.
 		h.claimPolling = cfg.ClaimPollingEnabled()
+		h.pollScheduler = cfg.Performance.Polling.NewPollScheduler()
 		h.fullRepaint = cfg.Display.GetFullRepaint()
 		h.defaultFilter = cfg.Display.GetDefaultFilter()
 		h.activeFilterLabel = cfg.Display.ActiveFilterLabel
@@ -1413,6 +1643,7 @@ func NewHomeWithProfileAndMode(profile string) *Home {
 		tmux.SetHideCwdPrefixInTitle(!cfg.Display.GetIncludeCwdPrefix())
 		h.showSessionTimestamps = cfg.Display.ShowSessionTimestamps
 		h.showPaneTitles = cfg.Display.ShowPaneTitles
+		h.showSessionCost = cfg.Display.ShowSessionCost
 		h.sysStatsConfig = cfg.SystemStats
 		h.costLineTemplate, h.costLineHideWhenZero = session.ResolveCostLineTemplate(cfg, actualProfile)
 		h.previewPct = cfg.UI.GetPreviewPct()
@@ -1421,6 +1652,8 @@ func NewHomeWithProfileAndMode(profile string) *Home {
 		h.remoteSessionRefreshSec = cfg.UI.GetRemoteSessionRefreshSecs()
 		h.footerMode = cfg.UI.GetFooter()
 		h.attachOnCreate = cfg.UI.GetAttachOnCreate()
+		h.mouseMode = cfg.UI.GetMouseMode()
+		h.mouseScreenModes = cfg.UI.GetMouseScreenModes()
 	} else {
 		h.fullRepaint = (session.DisplaySettings{}).GetFullRepaint()
 		h.activeFilterExcludes = (session.DisplaySettings{}).GetActiveFilterExcludes()
@@ -1430,9 +1663,17 @@ func NewHomeWithProfileAndMode(profile string) *Home {
 		h.remoteLatencyRefreshSec = (session.UISettings{}).GetRemoteLatencyRefreshSecs(0)
 		h.remoteSessionRefreshSec = (session.UISettings{}).GetRemoteSessionRefreshSecs()
 		h.footerMode = (session.UISettings{}).GetFooter()
+		h.mouseMode = session.DefaultMouseMode
 	}
+	h.appliedMouseMode = session.MouseModeFull
 	h.remoteLatency = make(map[string]session.RemoteLatency)
 
+	// A typo in config.toml is otherwise silently ignored; point at the
+	// first problem and the command that lists them all.
+	if issues := session.UserConfigIssues(); len(issues) > 0 {
+		h.setError(fmt.Errorf("config.toml: %s (%d issue(s), see `agent-deck config validate`)", issues[0], len(issues)))
+	}
+
 	// Initialize system stats collector if enabled
 	if h.sysStatsConfig.GetEnabled() {
 		h.sysStatsCollector = sysinfo.NewCollector(h.sysStatsConfig.GetRefreshSeconds(), nil)
@@ -1451,6 +1692,17 @@ func NewHomeWithProfileAndMode(profile string) *Home {
 		h.statusFilter = session.Status(h.defaultFilter)
 	}
 
+	if session.GetStateFilesSettings().GetEnabled() {
+		if w, err := session.NewStateFileWriter(h.profile); err == nil {
+			h.stateFiles = w
+		}
+	}
+	if session.GetTranscriptSettings().GetEnabled() {
+		if r, err := session.NewTranscriptRecorder(); err == nil {
+			h.transcripts = r
+		}
+	}
+
 	tmuxSettings := session.GetTmuxSettings()
 	h.manageTmuxNotifications = tmuxSettings.GetInjectStatusLine()
 
@@ -1460,6 +1712,8 @@ func NewHomeWithProfileAndMode(profile string) *Home {
 	if notifSettings.GetEnabled() && h.manageTmuxNotifications {
 		h.notificationsEnabled = true
 		h.notificationManager = session.NewNotificationManager(notifSettings.MaxShown, notifSettings.ShowAll, notifSettings.Minimal)
+		h.notificationScope = notifSettings.GetScope()
+		h.notificationTemplate = notifSettings.Template
 
 		// Initialize tmux status bar options for proper notification display
 		// Fixes truncation (default status-left-length is only 10 chars)
@@ -1561,7 +1815,14 @@ func NewHomeWithProfileAndMode(profile string) *Home {
 		alreadyInstalled := session.CheckClaudeHooksInstalled(configDir)
 
 		if alreadyInstalled {
-			// Hooks already present: start watcher, no prompt needed
+			// Hooks already present: start watcher, no prompt needed. An
+			// install stamped by an older binary is re-synced silently;
+			// one from a newer binary is left alone (see `hooks sync`).
+			if status := session.InspectClaudeHooks(configDir); status.State == session.ClaudeHooksOutdated {
+				if _, err := session.InjectClaudeHooks(configDir); err != nil {
+					uiLog.Warn("hook_resync_failed", slog.String("error", err.Error()))
+				}
+			}
 			hookWatcher, err := session.NewStatusFileWatcher(nil)
 			if err != nil {
 				uiLog.Warn("hook_watcher_init_failed", slog.String("error", err.Error()))
@@ -1655,6 +1916,17 @@ func NewHomeWithProfileAndMode(profile string) *Home {
 		}
 	}
 
+	// Gemini CLI hooks are installed explicitly (`agent-deck gemini-hooks
+	// install` or `agent-deck setup`). When any are present, run the shared
+	// watcher even if Claude hooks are off so their status updates land.
+	if homeBackgroundWorkersEnabled && h.hookWatcher == nil &&
+		len(session.MissingGeminiHookEvents(session.GetGeminiConfigDir())) < len(session.GeminiHookEvents()) {
+		if hookWatcher, err := session.NewStatusFileWatcher(nil); err == nil {
+			h.hookWatcher = hookWatcher
+			go hookWatcher.Start()
+		}
+	}
+
 	// Start system theme watcher if configured
 	if session.GetTheme() == "system" {
 		h.themeWatcher = NewThemeWatcher(ctx)
@@ -1937,6 +2209,63 @@ func (h *Home) refreshCostTotals() {
 	h.costProjected.Store(projected)
 }
 
+// sessionCostSyncInterval is how often the session list's cost column
+// re-reads Claude transcripts.
+const sessionCostSyncInterval = 30 * time.Second
+
+// refreshSessionCosts feeds the per-row cost badge. While the badge is on it
+// imports new usage from the Claude transcripts of every session in the
+// background (unchanged transcripts are skipped), then reloads the per-session
+// totals. Runs at most every sessionCostSyncInterval, one pass at a time.
+func (h *Home) refreshSessionCosts() {
+	if !h.showSessionCost || h.costStore == nil || h.costPricer == nil {
+		return
+	}
+	if time.Since(h.costSyncTime) < sessionCostSyncInterval || !h.costSyncing.CompareAndSwap(false, true) {
+		return
+	}
+	h.costSyncTime = time.Now()
+	if h.costSyncer == nil {
+		h.costSyncer = costs.NewTranscriptSyncer(h.costStore, h.costPricer)
+	}
+
+	h.instancesMu.RLock()
+	var syncSessions []costs.SyncSession
+	for _, inst := range h.instances {
+		if inst.Tool != "claude" || inst.ClaudeSessionID == "" {
+			continue
+		}
+		syncSessions = append(syncSessions, costs.SyncSession{
+			InstanceID:      inst.ID,
+			ClaudeSessionID: inst.ClaudeSessionID,
+			ProjectPath:     inst.ProjectPath,
+			Tool:            inst.Tool,
+		})
+	}
+	h.instancesMu.RUnlock()
+
+	syncer, store := h.costSyncer, h.costStore
+	go func() {
+		defer h.costSyncing.Store(false)
+		syncer.Sync(syncSessions)
+		totals, err := store.CostBySession()
+		if err != nil {
+			return
+		}
+		h.sessionCostsMu.Lock()
+		h.sessionCosts = totals
+		h.sessionCostsMu.Unlock()
+	}()
+}
+
+// sessionCost returns the cached estimated spend of a session in
+// microdollars (0 when none is recorded).
+func (h *Home) sessionCost(id string) int64 {
+	h.sessionCostsMu.RLock()
+	defer h.sessionCostsMu.RUnlock()
+	return h.sessionCosts[id]
+}
+
 func (h *Home) publishWebMenuSnapshot() {
 	menuData := h.getWebMenuData()
 	if menuData == nil || h.groupTree == nil {
@@ -2105,11 +2434,11 @@ func (h *Home) moveCursorToSession(sessionID string) {
 	}
 }
 
-// skipDivider nudges the cursor off a non-selectable divider row in the given
-// direction (+1 = down, -1 = up). Dividers only ever sit between two non-empty
-// sections, so they are never at a list edge nor adjacent to another divider;
-// a single step in the travel direction always lands on a selectable row. The
-// extra scan is defensive only.
+// skipDivider nudges the cursor off a non-selectable row (a divider or a
+// sub-agent child) in the given direction (+1 = down, -1 = up). Dividers sit
+// alone between two non-empty sections, but a session may have several
+// sub-agent rows in a row, so the cursor scans in the travel direction and
+// only turns back when it reaches the list edge without a selectable row.
 func (h *Home) skipDivider(dir int) {
 	n := len(h.flatItems)
 	if n == 0 {
@@ -2121,22 +2450,23 @@ func (h *Home) skipDivider(dir int) {
 	if h.cursor >= n {
 		h.cursor = n - 1
 	}
-	if h.flatItems[h.cursor].Type != session.ItemTypeDivider {
+	if h.flatItems[h.cursor].Selectable() {
 		return
 	}
-	h.cursor += dir
-	if h.cursor < 0 {
-		h.cursor = 0
-	}
-	if h.cursor >= n {
-		h.cursor = n - 1
+	if dir == 0 {
+		dir = 1
 	}
-	// Defensive: if somehow still on a divider, scan toward a selectable row.
-	for h.cursor < n-1 && h.flatItems[h.cursor].Type == session.ItemTypeDivider {
-		h.cursor++
+	for i := h.cursor + dir; i >= 0 && i < n; i += dir {
+		if h.flatItems[i].Selectable() {
+			h.cursor = i
+			return
+		}
 	}
-	for h.cursor > 0 && h.flatItems[h.cursor].Type == session.ItemTypeDivider {
-		h.cursor--
+	for i := h.cursor - dir; i >= 0 && i < n; i -= dir {
+		if h.flatItems[i].Selectable() {
+			h.cursor = i
+			return
+		}
 	}
 }
 
@@ -2357,7 +2687,8 @@ func (h *Home) rebuildFlatItems() {
 		}
 	}
 
-	// Inject window items after sessions that have 2+ windows
+	// Inject window items after sessions that have 2+ windows, then one
+	// display-only row per running Claude sub-agent.
 	if len(h.flatItems) > 0 {
 		expanded := make([]session.Item, 0, len(h.flatItems)+8)
 		for _, item := range h.flatItems {
@@ -2366,16 +2697,16 @@ func (h *Home) rebuildFlatItems() {
 			if item.Type != session.ItemTypeSession || item.Session == nil {
 				continue
 			}
-			tmuxSess := item.Session.GetTmuxSession()
-			if tmuxSess == nil {
-				continue
-			}
-			wins := tmux.GetCachedWindows(tmuxSess.Name)
-			if len(wins) < 2 || h.windowsCollapsed[item.Session.ID] {
-				continue
+			agents := sessionSubagents(item.Session)
+			var wins []tmux.WindowInfo
+			if tmuxSess := item.Session.GetTmuxSession(); tmuxSess != nil && !h.windowsCollapsed[item.Session.ID] {
+				if cached := tmux.GetCachedWindows(tmuxSess.Name); len(cached) >= 2 {
+					wins = cached
+				}
 			}
 
 			for winIdx, win := range wins {
+				last := winIdx == len(wins)-1 && len(agents) == 0
 				expanded = append(expanded, session.Item{
 					Type:                session.ItemTypeWindow,
 					WindowIndex:         win.Index,
@@ -2385,8 +2716,21 @@ func (h *Home) rebuildFlatItems() {
 					Level:               item.Level + 1,
 					Path:                item.Path,
 					IsWindow:            true,
-					IsLastWindow:        winIdx == len(wins)-1,
-					IsLastInGroup:       item.IsLastInGroup && winIdx == len(wins)-1,
+					IsLastWindow:        last,
+					IsLastInGroup:       item.IsLastInGroup && last,
+					ParentIsLastInGroup: item.IsLastInGroup,
+				})
+			}
+			for k := range agents {
+				last := k == len(agents)-1
+				expanded = append(expanded, session.Item{
+					Type:                session.ItemTypeSubagent,
+					Subagent:            &agents[k],
+					SubagentSessionID:   item.Session.ID,
+					Level:               item.Level + 1,
+					Path:                item.Path,
+					IsLastSubagent:      last,
+					IsLastInGroup:       item.IsLastInGroup && last,
 					ParentIsLastInGroup: item.IsLastInGroup,
 				})
 			}
@@ -2518,6 +2862,10 @@ func (h *Home) syncViewport() {
 	if h.maintenanceMsg != "" {
 		maintenanceBannerHeight = 1
 	}
+	tasksLineHeight := 0
+	if h.tasks.Running() > 0 {
+		tasksLineHeight = 1
+	}
 	debugBarHeight := 0
 	if h.debugMode {
 		debugBarHeight = 1
@@ -2525,7 +2873,7 @@ func (h *Home) syncViewport() {
 
 	// contentHeight = total height for main content area
 	// MUST match View(): subtract debugBarHeight when the debug footer is rendered.
-	contentHeight := h.height - 1 - helpBarHeight - updateBannerHeight - maintenanceBannerHeight - filterBarHeight - debugBarHeight
+	contentHeight := h.height - 1 - helpBarHeight - updateBannerHeight - maintenanceBannerHeight - tasksLineHeight - filterBarHeight - debugBarHeight
 
 	// CRITICAL: Calculate panelContentHeight based on current layout mode
 	// This MUST match the calculations in renderStackedLayout/renderDualColumnLayout/renderSingleColumnLayout
@@ -2739,8 +3087,16 @@ func (h *Home) cleanupNotifications() {
 		return
 	}
 
-	// Clear global status bar (ONE call instead of per-session)
-	_ = tmux.ClearStatusLeftGlobal()
+	// Clear the bar: per-session values in session scope (ONE chained call),
+	// otherwise restore the global status-left.
+	if h.notificationScope == session.NotificationScopeSession {
+		h.lastBarTextMu.Lock()
+		scoped := h.lastBarSessions
+		h.lastBarTextMu.Unlock()
+		_ = tmux.SetStatusLeftSessions(scoped, "", 0)
+	} else {
+		_ = tmux.ClearStatusLeftGlobal()
+	}
 
 	// Unbind all keys (with mutex protection)
 	h.boundKeysMu.Lock()
@@ -2765,12 +3121,16 @@ func (h *Home) getVisibleHeight() int {
 	if h.maintenanceMsg != "" {
 		maintenanceBannerHeight = 1
 	}
+	tasksLineHeight := 0
+	if h.tasks.Running() > 0 {
+		tasksLineHeight = 1
+	}
 	debugBarHeight := 0
 	if h.debugMode {
 		debugBarHeight = 1
 	}
 
-	contentHeight := h.height - 1 - helpBarHeight - updateBannerHeight - maintenanceBannerHeight - filterBarHeight - debugBarHeight
+	contentHeight := h.height - 1 - helpBarHeight - updateBannerHeight - maintenanceBannerHeight - tasksLineHeight - filterBarHeight - debugBarHeight
 
 	var panelContentHeight int
 	layoutMode := h.getLayoutMode()
@@ -2849,6 +3209,9 @@ func (h *Home) Init() tea.Cmd {
 	// Start watcher engine (D-07: lifecycle tied to TUI startup)
 	cmds = append(cmds, h.startWatcherEngine())
 
+	// Redraw as background tasks report progress
+	cmds = append(cmds, listenForTaskChanges(h.tasks))
+
 	return tea.Batch(cmds...)
 }
 
@@ -4058,7 +4421,7 @@ func (h *Home) statusWorker() {
 	// the Bubble Tea tick messages stop firing, but this goroutine keeps running.
 	// A timer (reset after each sweep) rather than a fixed ticker lets the cadence
 	// adapt when a sweep overruns the interval (#1366).
-	timer := time.NewTimer(baseStatusInterval)
+	timer := time.NewTimer(h.statusTick())
 	defer timer.Stop()
 
 	for {
@@ -4070,7 +4433,7 @@ func (h *Home) statusWorker() {
 			// Self-triggered update - runs even when TUI is paused
 			sweepStart := time.Now()
 			h.backgroundStatusUpdate()
-			timer.Reset(nextStatusInterval(time.Since(sweepStart), baseStatusInterval, maxStatusInterval))
+			timer.Reset(nextStatusInterval(time.Since(sweepStart), h.statusTick(), maxStatusInterval))
 			// Coalesce a queued immediate request after full sweep.
 			select {
 			case <-h.statusTrigger:
@@ -4155,6 +4518,17 @@ func (h *Home) backgroundStatusUpdate() {
 	copy(instances, h.instances)
 	h.instancesMu.RUnlock()
 
+	// Tiered polling ([performance.polling]): the worker ticks at the
+	// fastest tier. Between full sweeps (every baseStatusInterval) a tick
+	// only runs when some session is due, and then polls just those.
+	pm := tmux.GetPipeManager()
+	if last := h.lastTieredSweep.Load(); totalStart.Sub(time.Unix(0, last)) >= baseStatusInterval {
+		h.lastTieredSweep.Store(totalStart.UnixNano())
+		h.retainPollState(instances)
+	} else if !h.anyStatusPollDue(instances, pm, totalStart) {
+		return
+	}
+
 	// Claim reconciliation: decide which sessions THIS instance polls. This
 	// runs BEFORE the tmux-alive and empty-instances early returns below:
 	// claims lifecycle (heartbeats, orphan sweep, primary election) is
@@ -4173,11 +4547,16 @@ func (h *Home) backgroundStatusUpdate() {
 		opID := sod.Start("background_status_update")
 		defer sod.Finish(opID)
 	}
+	tickCtx, tickSpan := logging.StartSpan(context.Background(), "status_tick",
+		slog.Int("sessions", len(instances)))
+	defer tickSpan.End()
 
 	// Refresh tmux session cache
 	refreshStart := time.Now()
+	_, refreshSpan := logging.StartSpan(tickCtx, "refresh_tmux_cache")
 	tmux.RefreshExistingSessions()
 	tmux.RefreshPaneInfoCache()
+	refreshSpan.End()
 	refreshDur := time.Since(refreshStart)
 	if refreshDur > 100*time.Millisecond {
 		perfLog.Warn("slow_refresh", slog.Duration("duration", refreshDur))
@@ -4207,14 +4586,48 @@ func (h *Home) backgroundStatusUpdate() {
 			if h.idleTimeoutLastTick.CompareAndSwap(lastNano, nowNano) {
 				// Full set, not ownedOnly: Tick's lastSeen cleanup has a
 				// documented invariant that it must see every instance to
-				// prune dead entries correctly. Sessions with
-				// IdleTimeoutSecs>0 are rare, so the cross-instance dedupe
-				// win here was negligible against that correctness leak.
+				// prune dead entries correctly. Unarmed sessions (no
+				// per-session timeout and no [idle_stop] policy) cost no
+				// capture, so the cross-instance dedupe win here was
+				// negligible against that correctness leak.
 				h.idleTimeoutWatcher.Tick(instances)
 			}
 		}
 	}
 
+	// Working hours: same once-a-minute cadence as the idle-timeout watcher.
+	// Pause/resume decisions are minute-granular, so a finer tick buys nothing.
+	if h.workingHoursWatcher != nil {
+		const workingHoursTickEvery = 60 * time.Second
+		nowNano := time.Now().UnixNano()
+		lastNano := h.workingHoursLastTick.Load()
+		if lastNano == 0 || time.Duration(nowNano-lastNano) >= workingHoursTickEvery {
+			if h.workingHoursLastTick.CompareAndSwap(lastNano, nowNano) {
+				h.workingHoursWatcher.Tick(instances)
+			}
+		}
+	}
+
+	// Worktree sync: once a minute, off the sweep goroutine since a sync
+	// fetches from the remote. The watcher skips a tick while one is running.
+	if h.worktreeSyncWatcher != nil {
+		const worktreeSyncTickEvery = 60 * time.Second
+		nowNano := time.Now().UnixNano()
+		lastNano := h.worktreeSyncLastTick.Load()
+		if lastNano == 0 || time.Duration(nowNano-lastNano) >= worktreeSyncTickEvery {
+			if h.worktreeSyncLastTick.CompareAndSwap(lastNano, nowNano) {
+				go h.worktreeSyncWatcher.Tick(activeInstances)
+			}
+		}
+	}
+
+	// Checkpoints: every sweep, so a running -> waiting transition is seen
+	// promptly. Snapshots shell out to git, so run off the sweep goroutine;
+	// the watcher skips a tick while the previous one is still running.
+	if h.checkpointWatcher != nil {
+		go h.checkpointWatcher.Tick(activeInstances)
+	}
+
 	// PERFORMANCE: Gradually configure unconfigured sessions in background
 	// Configure one session per tick to avoid blocking the status update
 	// This ensures all sessions get configured within ~1 minute even without user interaction
@@ -4296,11 +4709,11 @@ func (h *Home) backgroundStatusUpdate() {
 					_ = tmuxSess.SendKeysAndEnter("/clear")
 					// After /clear wipes context, immediately send heartbeat to restore orientation
 					time.Sleep(3 * time.Second)
-					_ = session.DefaultProfile
-					if meta, err := session.LoadConductorMeta(conductorName); err == nil {
-						_ = meta.Profile
+					meta, err := session.LoadConductorMeta(conductorName)
+					if err != nil {
+						meta = &session.ConductorMeta{Name: conductorName}
 					}
-					msg := fmt.Sprintf("Heartbeat: Check sessions in your group (%s). List any that are waiting, auto-respond where safe, and report what needs my attention.", conductorName)
+					msg := session.ConductorHeartbeatMessagePrefix + " " + session.ConductorHeartbeatPrompt(meta)
 					_ = tmuxSess.SendKeysAndEnter(msg)
 				})
 			}
@@ -4313,7 +4726,8 @@ func (h *Home) backgroundStatusUpdate() {
 	var statusChanged atomic.Bool
 	var slowMu sync.Mutex
 	var slowSessions []string
-	pm := tmux.GetPipeManager()
+	var turnsMu sync.Mutex
+	var turnsEnded []*session.Instance
 	var skipped int // sessions not polled this tick (archived + idle fast-path)
 
 	tracker := h.getTransitionTracker()
@@ -4335,6 +4749,10 @@ func (h *Home) backgroundStatusUpdate() {
 			skipped++
 			continue
 		}
+		if !h.statusPollDue(inst, pm, statusStart) {
+			skipped++
+			continue
+		}
 
 		// Skip idle sessions when PipeManager knows they haven't produced output.
 		// Only skip if pipe is alive (otherwise we need UpdateStatus for Error detection).
@@ -4351,7 +4769,12 @@ func (h *Home) backgroundStatusUpdate() {
 		g.Go(func() error {
 			oldStatus := inst.GetStatusThreadSafe()
 			instStart := time.Now()
+			_, span := logging.StartSpan(tickCtx, "update_status",
+				slog.String("session", inst.Title), slog.String("tool", inst.Tool))
 			_ = inst.UpdateStatus()
+			inst.RefreshSubagents()
+			span.SetAttrs(slog.String("status", string(inst.GetStatusThreadSafe())))
+			span.End()
 			instDur := time.Since(instStart)
 
 			if instDur > 50*time.Millisecond {
@@ -4360,6 +4783,7 @@ func (h *Home) backgroundStatusUpdate() {
 				slowMu.Unlock()
 			}
 			newStatus := inst.GetStatusThreadSafe()
+			h.markStatusPolled(inst, newStatus)
 			if newStatus != oldStatus {
 				statusChanged.Store(true)
 				notifLog.Debug(
@@ -4372,6 +4796,13 @@ func (h *Home) backgroundStatusUpdate() {
 				// has oscillated >3 times within 60s. One alert per burst.
 				session.GlobalFlickerDetector().Observe(inst.ID, string(newStatus))
 				tracker.record(inst.ID, inst.Title, inst.Tool, string(oldStatus), string(newStatus))
+				session.RunEventHooks(inst, h.profile, string(oldStatus), string(newStatus))
+				session.SendStatusWebhook(inst, h.profile, string(oldStatus), string(newStatus))
+				if oldStatus == session.StatusRunning && (newStatus == session.StatusWaiting || newStatus == session.StatusIdle) {
+					turnsMu.Lock()
+					turnsEnded = append(turnsEnded, inst)
+					turnsMu.Unlock()
+				}
 			}
 			return nil
 		})
@@ -4379,7 +4810,11 @@ func (h *Home) backgroundStatusUpdate() {
 	_ = g.Wait() // Errors are logged within each goroutine
 
 	statusDur := time.Since(statusStart)
+	tickSpan.SetAttrs(slog.Int("skipped", skipped), slog.Bool("changed", statusChanged.Load()))
 	tracker.tickEnd(statusStart, time.Now())
+	session.CheckWaitingPushes(h.profile, instances, time.Now())
+	session.CheckAutoResponses(h.profile, instances, time.Now())
+	session.ReleaseQueuedSends(statedb.GetGlobal(), h.profile, instances, time.Now())
 	if skipped > 0 {
 		perfLog.Debug(
 			"idle_sessions_skipped",
@@ -4442,6 +4877,43 @@ func (h *Home) backgroundStatusUpdate() {
 	}
 	h.refreshSessionRenderSnapshot(instances)
 
+	// Per-session JSON state files for external tools. The writer diffs
+	// against its last snapshot, so this is a no-op when nothing changed.
+	if h.stateFiles != nil {
+		if err := h.stateFiles.Sync(instances); err != nil {
+			uiLog.Debug("state_files_sync_failed", slog.String("error", err.Error()))
+		}
+	}
+
+	// Session transcripts. The recorder only captures scrollback for panes
+	// whose hash moved, but hashing every pane still costs a capture, so it
+	// runs every 10s rather than on every 2s sweep.
+	if h.transcripts != nil {
+		const transcriptTickEvery = 10 * time.Second
+		nowNano := time.Now().UnixNano()
+		lastNano := h.transcriptsLastTick.Load()
+		if lastNano == 0 || time.Duration(nowNano-lastNano) >= transcriptTickEvery {
+			if h.transcriptsLastTick.CompareAndSwap(lastNano, nowNano) {
+				if err := h.transcripts.Tick(activeInstances); err != nil {
+					uiLog.Debug("transcripts_tick_failed", slog.String("error", err.Error()))
+				}
+			}
+		}
+	}
+
+	// Output history: a running → waiting/idle transition means the agent
+	// finished a turn, so its final response is now in the transcript.
+	// Parsing can read a large JSONL, so it runs off the sweep.
+	if len(turnsEnded) > 0 {
+		go func(insts []*session.Instance) {
+			for _, inst := range insts {
+				if _, err := inst.RecordOutputHistory(); err != nil {
+					uiLog.Debug("output_history_record_failed", slog.String("session", inst.ID), slog.String("error", err.Error()))
+				}
+			}
+		}(turnsEnded)
+	}
+
 	// SQLite writes: heartbeat, status writes (enables multi-instance coordination)
 	if db := statedb.GetGlobal(); db != nil {
 		// Heartbeat: mark this process as alive
@@ -4456,6 +4928,29 @@ func (h *Home) backgroundStatusUpdate() {
 			h.lastDeadInstanceCleanup = time.Now()
 		}
 
+		// Port registry snapshot for the preview.
+		if time.Since(h.lastPortClaimsRefresh) > portClaimsRefresh {
+			if claims, err := db.LoadPortClaims(""); err == nil {
+				byInstance := make(map[string][]*statedb.PortClaim)
+				for _, c := range claims {
+					byInstance[c.InstanceID] = append(byInstance[c.InstanceID], c)
+				}
+				h.portClaimsMu.Lock()
+				h.portClaims = byInstance
+				h.portClaimsMu.Unlock()
+			}
+			h.lastPortClaimsRefresh = time.Now()
+		}
+
+		// Status counters for the `status -q` fast path.
+		counters := session.CountStatuses(instances)
+		if counters != h.lastStatusCounters || time.Since(h.lastStatusCountersPublish) > statusCountersRefresh {
+			if err := db.WriteStatusCounters(counters); err == nil {
+				h.lastStatusCounters = counters
+				h.lastStatusCountersPublish = time.Now()
+			}
+		}
+
 		// Write statuses only when changed to reduce SQLite write pressure.
 		// Sessions neither owned nor orphan-polled this sweep are not written:
 		// the owning instance (or, for orphans, this primary's orphan sweep)
@@ -4608,21 +5103,38 @@ func (h *Home) syncNotificationsBackground() {
 		slog.Int("instances", len(instances)),
 	)
 
-	// Sync notification manager with current states
-	h.notificationManager.SyncFromInstances(instances, currentSessionID)
+	// Sync notification manager with current states. Sessions outside their
+	// group's working hours are muted.
+	h.notificationManager.SyncFromInstances(session.WithinWorkingHours(instances, time.Now()), currentSessionID)
 
 	// Update tmux status bar directly
-	barText := h.notificationManager.FormatBar()
+	waiting := h.notificationManager.WaitingCount()
+	barText := session.RenderNotificationTemplate(h.notificationTemplate, h.notificationManager.FormatBar(), waiting)
+
+	// Session scope: the bar lives on each agentdeck session's own status-left,
+	// so the cache key includes the session set (new sessions need the bar too).
+	var scopedSessions []string
+	cacheKey := barText
+	if h.notificationScope == session.NotificationScopeSession {
+		scopedSessions = agentdeckTmuxNames(instances)
+		cacheKey = fmt.Sprintf("%s\x00%d\x00%s", barText, waiting, strings.Join(scopedSessions, ","))
+	}
 
 	// Only update if changed (avoid unnecessary tmux calls)
 	h.lastBarTextMu.Lock()
-	if barText != h.lastBarText {
-		h.lastBarText = barText
+	if cacheKey != h.lastBarText {
+		h.lastBarText = cacheKey
+		if scopedSessions != nil {
+			h.lastBarSessions = scopedSessions
+		}
 		h.lastBarTextMu.Unlock()
 
-		if barText == "" {
+		switch {
+		case h.notificationScope == session.NotificationScopeSession:
+			_ = tmux.SetStatusLeftSessions(scopedSessions, barText, waiting)
+		case barText == "":
 			_ = tmux.ClearStatusLeftGlobal()
-		} else {
+		default:
 			_ = tmux.SetStatusLeftGlobal(barText)
 		}
 
@@ -4640,6 +5152,19 @@ func (h *Home) syncNotificationsBackground() {
 	h.updateKeyBindings()
 }
 
+// agentdeckTmuxNames returns the sorted tmux session names of the given
+// instances, used to scope the notification bar to agentdeck sessions only.
+func agentdeckTmuxNames(instances []*session.Instance) []string {
+	names := make([]string, 0, len(instances))
+	for _, inst := range instances {
+		if ts := inst.GetTmuxSession(); ts != nil && ts.Name != "" {
+			names = append(names, ts.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 // updateKeyBindings updates tmux key bindings based on current notification entries.
 // Thread-safe via boundKeysMu. Can be called from both foreground and background.
 func (h *Home) updateKeyBindings() {
@@ -4908,6 +5433,9 @@ func (h *Home) processStatusUpdate(req statusUpdateRequest) {
 func (h *Home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	defer h.recordFocusedSession()
 	model, cmd := h.updateInner(msg)
+	if mouseCmd := h.syncMouseModeCmd(); mouseCmd != nil {
+		cmd = tea.Batch(cmd, mouseCmd)
+	}
 	if !h.fullRepaint {
 		return model, cmd
 	}
@@ -4956,6 +5484,16 @@ func (h *Home) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		h.geminiModelDialog.SetSize(msg.Width, msg.Height)
 		h.promptInputDialog.SetSize(msg.Width, msg.Height)
+		h.retryStartDialog.SetSize(msg.Width, msg.Height)
+		h.duplicatesDialog.SetSize(msg.Width, msg.Height)
+		h.tasksPanel.SetSize(msg.Width, msg.Height)
+		h.boardView.SetSize(msg.Width, msg.Height)
+		h.outputHistoryDialog.SetSize(msg.Width, msg.Height)
+		h.worktreeDiffDialog.SetSize(msg.Width, msg.Height)
+		h.promptPickerDialog.SetSize(msg.Width, msg.Height)
+		h.extensionPickerDialog.SetSize(msg.Width, msg.Height)
+		h.statusTimelineDialog.SetSize(msg.Width, msg.Height)
+		h.taskQueueDialog.SetSize(msg.Width, msg.Height)
 		h.scrollbackPager.SetSize(msg.Width, msg.Height)
 		// Issue #1366: a resize can reveal the preview pane (single -> stacked/dual).
 		// fetchSelectedPreview self-guards to nil in single-column, so this only
@@ -5645,6 +6183,19 @@ func (h *Home) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return h, nil
 
+	case duplicateMergeMsg:
+		return h, h.mergeDuplicates(msg)
+
+	case duplicateRemoveMsg:
+		if inst := h.getInstanceByID(msg.sessionID); inst != nil {
+			h.confirmDialog.ShowDeleteSession(inst.ID, inst.Title, inst.IsSandboxed(), inst.IsWorktree())
+		}
+		return h, nil
+
+	case sessionsAutoArchivedMsg:
+		h.handleSessionsAutoArchived(msg)
+		return h, nil
+
 	case sessionUnarchivedMsg:
 		h.rebuildFlatItems()
 		if inst := h.getInstanceByID(msg.sessionID); inst != nil {
@@ -5737,6 +6288,16 @@ func (h *Home) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 		h.forceSaveInstances()
 		return h, nil
 
+	case tasksChangedMsg:
+		return h, listenForTaskChanges(h.tasks)
+
+	case groupRestartDoneMsg:
+		return h, h.applyGroupRestart(msg)
+
+	case manifestAppliedMsg:
+		h.applyManifestResult(msg)
+		return h, nil
+
 	case sessionRestartedMsg:
 		if msg.err != nil {
 			// Restart failed - clear resuming animation immediately so user can retry.
@@ -5775,6 +6336,19 @@ func (h *Home) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 		delete(h.resumingSessions, msg.sessionID)
 		return h, nil
 
+	case relocationSearchMsg:
+		inst := h.getInstanceByID(msg.sessionID)
+		if inst == nil {
+			return h, nil
+		}
+		if len(msg.candidates) == 0 {
+			h.setError(fmt.Errorf("project path missing: %s (run: agent-deck session relocate %s <new-path>)", msg.oldPath, inst.ID))
+			return h, nil
+		}
+		best := msg.candidates[0]
+		h.confirmDialog.ShowRelocate(inst.ID, inst.Title, msg.oldPath, best.Path, best.Reason)
+		return h, nil
+
 	case mcpRestartedMsg:
 		if msg.err != nil {
 			h.setError(fmt.Errorf("failed to restart session for MCP changes: %w", msg.err))
@@ -5873,7 +6447,7 @@ func (h *Home) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 		h.beginAttachReturnGrace(time.Now())
 		return h, tea.Batch(
 			h.fetchRemoteSessions,
-			tea.EnableMouseCellMotion,
+			h.reapplyMouseModeCmd(),
 			RestoreLegacyKeyboardCmd(os.Stdout),
 			tea.WindowSize(),
 			tea.Tick(attachReturnRefreshDelay, func(time.Time) tea.Msg { return attachReturnRefreshMsg{} }),
@@ -5900,6 +6474,12 @@ func (h *Home) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if r.OrphanContainers > 0 {
 			parts = append(parts, fmt.Sprintf("%d orphan containers removed", r.OrphanContainers))
 		}
+		if r.EmptyExperiments > 0 {
+			parts = append(parts, fmt.Sprintf("%d empty experiments removed", r.EmptyExperiments))
+		}
+		if r.BackupsWritten > 0 {
+			parts = append(parts, fmt.Sprintf("%d profiles backed up", r.BackupsWritten))
+		}
 		if len(parts) > 0 {
 			h.maintenanceMsg = "Maintenance: " + strings.Join(parts, ", ") + fmt.Sprintf(" (%s)", r.Duration.Round(time.Millisecond))
 			h.maintenanceMsgTime = time.Now()
@@ -5960,6 +6540,10 @@ func (h *Home) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return h, nil
 
+	case extensionResultMsg:
+		h.setError(fmt.Errorf("%s", extensionResultText(msg)))
+		return h, nil
+
 	case promptSubmitMsg:
 		// #1410: deliver a one-line prompt to the highlighted session without
 		// attaching, reusing the prompt-state-aware send path (the #1409/#1432
@@ -5989,6 +6573,9 @@ func (h *Home) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}()
 		return h, nil
 
+	case retryStartMsg:
+		return h, h.retryFailedStart(msg.instanceID, msg.command)
+
 	case refreshMsg:
 		return h, h.loadSessions
 
@@ -6007,7 +6594,7 @@ func (h *Home) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else {
 			os.Setenv("COLORFGBG", "0;15")
 		}
-		InitTheme(theme)
+		InitThemeDef(theme, session.ThemeOverrides(theme))
 		h.propagateThemeToSessions()
 		// IMPORTANT: Re-issue listener to keep watching for theme changes.
 		// Without this, the watcher silently disconnects.
@@ -6096,7 +6683,7 @@ func (h *Home) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 		reloading := h.isReloading
 		h.reloadMu.Unlock()
 		if reloading {
-			return h, tea.EnableMouseCellMotion
+			return h, h.reapplyMouseModeCmd()
 		}
 
 		h.followAttachReturnCwd(msg)
@@ -6117,7 +6704,7 @@ func (h *Home) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// right edge), and schedule a delayed repaint for any pane-title/content
 		// cache changes that settle just after tmux restores the outer client.
 		return h, tea.Batch(
-			tea.EnableMouseCellMotion,
+			h.reapplyMouseModeCmd(),
 			RestoreLegacyKeyboardCmd(os.Stdout),
 			tea.WindowSize(),
 			tea.Tick(attachReturnRefreshDelay, func(time.Time) tea.Msg { return attachReturnRefreshMsg{} }),
@@ -6139,7 +6726,7 @@ func (h *Home) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 		})
 		h.openSessionSwitcher(msg.fromSessionID, true)
 		return h, tea.Batch(
-			tea.EnableMouseCellMotion,
+			h.reapplyMouseModeCmd(),
 			RestoreLegacyKeyboardCmd(os.Stdout),
 			tea.WindowSize(),
 			tea.Tick(attachReturnRefreshDelay, func(time.Time) tea.Msg { return attachReturnRefreshMsg{} }),
@@ -6161,7 +6748,7 @@ func (h *Home) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 		})
 		captureCmd := h.openScrollbackPager(msg.fromSessionID)
 		return h, tea.Batch(
-			tea.EnableMouseCellMotion,
+			h.reapplyMouseModeCmd(),
 			RestoreLegacyKeyboardCmd(os.Stdout),
 			tea.WindowSize(),
 			captureCmd,
@@ -6315,6 +6902,30 @@ func (h *Home) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
+			// Worktree pull request lookup (lazy, 2m TTL; shells out to gh/glab)
+			if inst.IsWorktree() && inst.WorktreeBranch != "" && inst.WorktreePath != "" {
+				h.worktreePRMu.Lock()
+				cacheTs, hasCached := h.worktreePRCacheTs[inst.ID]
+				needsCheck := !hasCached || time.Since(cacheTs) > 2*time.Minute
+				if needsCheck {
+					h.worktreePRCacheTs[inst.ID] = time.Now() // Prevent duplicate fetches
+				}
+				h.worktreePRMu.Unlock()
+				if needsCheck {
+					sid := inst.ID
+					wtPath := inst.WorktreePath
+					branch := inst.WorktreeBranch
+					cmds = append(cmds, func() tea.Msg {
+						forge, err := git.DetectPRProvider(wtPath)
+						if err != nil {
+							return worktreePRCheckMsg{sessionID: sid}
+						}
+						pr, _ := git.GetPullRequest(wtPath, forge, branch)
+						return worktreePRCheckMsg{sessionID: sid, pr: pr}
+					})
+				}
+			}
+
 			if len(cmds) > 0 {
 				return h, tea.Batch(cmds...)
 			}
@@ -6372,6 +6983,22 @@ func (h *Home) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return h, nil
 
+	case taskDispatchedMsg:
+		if msg.err != nil {
+			h.setError(msg.err)
+		} else {
+			h.maintenanceMsg = fmt.Sprintf("Dispatched %s to %s", msg.taskID, msg.title)
+			h.maintenanceMsgTime = time.Now()
+		}
+		h.reloadTaskQueue()
+		return h, nil
+
+	case worktreeDiffLoadedMsg:
+		if h.worktreeDiffDialog.IsVisible() && h.worktreeDiffDialog.sessionID == msg.sessionID {
+			h.worktreeDiffDialog.SetDiff(msg.diff, msg.dirty, msg.err)
+		}
+		return h, nil
+
 	case worktreeDirtyCheckMsg:
 		// Update worktree dirty status cache
 		if msg.err == nil {
@@ -6386,6 +7013,13 @@ func (h *Home) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return h, nil
 
+	case worktreePRCheckMsg:
+		h.worktreePRMu.Lock()
+		h.worktreePRCache[msg.sessionID] = msg.pr
+		h.worktreePRCacheTs[msg.sessionID] = time.Now()
+		h.worktreePRMu.Unlock()
+		return h, nil
+
 	case worktreeSetupResultMsg:
 		delete(h.setupRunningSessions, msg.sessionID)
 		if msg.err != nil {
@@ -6432,6 +7066,10 @@ func (h *Home) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 		delete(h.worktreeDirtyCache, msg.sessionID)
 		delete(h.worktreeDirtyCacheTs, msg.sessionID)
 		h.worktreeDirtyMu.Unlock()
+		h.worktreePRMu.Lock()
+		delete(h.worktreePRCache, msg.sessionID)
+		delete(h.worktreePRCacheTs, msg.sessionID)
+		h.worktreePRMu.Unlock()
 		h.logActivityMu.Lock()
 		delete(h.lastLogActivity, msg.sessionID)
 		h.logActivityMu.Unlock()
@@ -6468,6 +7106,10 @@ func (h *Home) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 		h.setError(fmt.Errorf("%s", successMsg))
 		return h, nil
 
+	case otherProfileSessionsMsg:
+		h.search.SetOtherProfileItems(msg.sessions)
+		return h, nil
+
 	case copyResultMsg:
 		if msg.err != nil {
 			h.setError(msg.err)
@@ -6551,7 +7193,11 @@ func (h *Home) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 		var remoteFetchCmd tea.Cmd
 		var remoteLatencyCmd tea.Cmd
 
-		if h.groupViewMode != session.GroupViewNormal {
+		// Sub-agent rows come and go with the transcript, so the list is also
+		// rebuilt whenever the set of running sub-agents or their steps change.
+		subagentSig := h.subagentSignature()
+		if h.groupViewMode != session.GroupViewNormal || subagentSig != h.lastSubagentSig {
+			h.lastSubagentSig = subagentSig
 			selectedBefore := h.captureSelectedItemIdentity()
 			h.rebuildFlatItemsPreservingSelection(selectedBefore)
 		}
@@ -6589,6 +7235,7 @@ func (h *Home) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Refresh cost totals for header display
 		h.refreshCostTotals()
+		h.refreshSessionCosts()
 
 		// Periodic UI state save (every 5 ticks = ~10 seconds)
 		h.uiStateSaveTicks++
@@ -6638,6 +7285,12 @@ func (h *Home) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}()
 		}
 
+		// Duplicate-session advisor: cheap in-memory pass, so it also runs
+		// on the first tick after load.
+		if !h.initialLoading && time.Since(h.lastDuplicateScan) >= duplicateScanInterval {
+			h.scanDuplicates()
+		}
+
 		// Prune stale caches and limiters every 20 seconds
 		if time.Since(h.lastCachePrune) >= 20*time.Second {
 			h.lastCachePrune = time.Now()
@@ -6764,7 +7417,7 @@ func (h *Home) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 				h.previewCacheMu.Unlock()
 			}
 		}
-		cmds := []tea.Cmd{h.tick(), previewCmd, remoteFetchCmd, remoteLatencyCmd}
+		cmds := []tea.Cmd{h.tick(), previewCmd, remoteFetchCmd, remoteLatencyCmd, h.autoArchiveIdleSessions(time.Now())}
 		if h.fullRepaint {
 			cmds = append(cmds, tea.ClearScreen)
 		}
@@ -6873,11 +7526,11 @@ func (h *Home) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 				h.reloadHotkeysFromConfig()
 				h.showSessionTimestamps = config.Display.ShowSessionTimestamps
 				h.showPaneTitles = config.Display.ShowPaneTitles
+				h.showSessionCost = config.Display.ShowSessionCost
 
 				// Apply theme changes live
 				h.stopThemeWatcher()
-				resolvedTheme := session.ResolveTheme()
-				InitTheme(resolvedTheme)
+				InitThemeDef(session.ResolveThemeDef())
 				h.propagateThemeToSessions()
 				var themeCmd tea.Cmd
 				if config.Theme == "system" {
@@ -6946,6 +7599,11 @@ func (h *Home) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 			h.promptInputDialog = d
 			return h, cmd
 		}
+		if h.retryStartDialog.IsVisible() {
+			d, cmd := h.retryStartDialog.Update(msg)
+			h.retryStartDialog = d
+			return h, cmd
+		}
 		if h.sessionSwitcher.IsVisible() {
 			return h.handleSessionSwitcherKey(msg)
 		}
@@ -6955,9 +7613,36 @@ func (h *Home) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if h.sessionPickerDialog.IsVisible() {
 			return h.handleSessionPickerDialogKey(msg)
 		}
+		if h.duplicatesDialog.IsVisible() {
+			return h.handleDuplicatesDialogKey(msg)
+		}
+		if h.tasksPanel.IsVisible() {
+			return h.handleTasksPanelKey(msg)
+		}
+		if h.boardView.IsVisible() {
+			return h.handleBoardKey(msg)
+		}
 		if h.codeBlockDialog.IsVisible() {
 			return h.handleCodeBlockDialogKey(msg)
 		}
+		if h.outputHistoryDialog.IsVisible() {
+			return h.handleOutputHistoryDialogKey(msg)
+		}
+		if h.promptPickerDialog.IsVisible() {
+			return h.handlePromptPickerDialogKey(msg)
+		}
+		if h.extensionPickerDialog.IsVisible() {
+			return h.handleExtensionPickerDialogKey(msg)
+		}
+		if h.statusTimelineDialog.IsVisible() {
+			return h.handleStatusTimelineDialogKey(msg)
+		}
+		if h.taskQueueDialog.IsVisible() {
+			return h.handleTaskQueueDialogKey(msg)
+		}
+		if h.worktreeDiffDialog.IsVisible() {
+			return h.handleWorktreeDiffDialogKey(msg)
+		}
 		if h.worktreeFinishDialog.IsVisible() {
 			return h.handleWorktreeFinishDialogKey(msg)
 		}
@@ -6994,6 +7679,11 @@ func (h *Home) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (h *Home) handleSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "enter":
+		if other := h.search.SelectedOther(); other != nil {
+			hit := *other
+			h.search.Hide()
+			return h, h.attachOtherProfileSession(&hit)
+		}
 		selected := h.search.Selected()
 		if selected != nil {
 			// Ensure the session's group AND all parent groups are expanded so it's visible
@@ -7052,7 +7742,7 @@ func (h *Home) handleGlobalSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Check if user wants to switch to local search
 	if h.globalSearch.WantsSwitchToLocal() {
 		h.search.SetItems(h.instances)
-		h.search.Show()
+		return h, tea.Batch(cmd, h.openLocalSearch())
 	}
 
 	return h, cmd
@@ -7283,11 +7973,14 @@ func (h *Home) handleNewDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		var worktreePath, worktreeRepoRoot string
 		if worktreeEnabled && branchName != "" {
 			// resolveWorktreeTarget validates the path is a supported VCS repo
-			// (git or jujutsu; including bare-repo project roots) and implements the #1185
+			// (git, jujutsu or sapling; including bare-repo project roots) and implements the #1185
 			// fallback: a worktree enabled by config default (not an explicit
 			// user toggle) on a non-repo dir falls back to a normal session
 			// instead of erroring, while an explicit worktree still fails loud.
-			wtPath, repoRoot, fallback, errMsg := resolveWorktreeTarget(path, branchName, h.newDialog.IsWorktreeExplicit())
+			// The location and {session-id} come from the dialog so the worktree
+			// lands where its preview said it would.
+			location, pathID := h.newDialog.GetWorktreeTarget()
+			wtPath, repoRoot, fallback, errMsg := resolveWorktreeTargetAt(path, branchName, location, pathID, h.newDialog.IsWorktreeExplicit())
 			if errMsg != "" {
 				h.newDialog.SetError(errMsg)
 				return h, nil
@@ -7603,7 +8296,7 @@ func jumpItemName(item session.Item) string {
 func selectableItemIndices(items []session.Item) []int {
 	indices := make([]int, 0, len(items))
 	for i, item := range items {
-		if item.Type != session.ItemTypeDivider {
+		if item.Selectable() {
 			indices = append(indices, i)
 		}
 	}
@@ -7664,9 +8357,11 @@ func (h *Home) hasModalVisible() bool {
 		h.newDialog.IsVisible() || h.groupDialog.IsVisible() || h.forkDialog.IsVisible() ||
 		h.confirmDialog.IsVisible() || h.mcpDialog.IsVisible() || h.pluginDialog.IsVisible() || h.skillDialog.IsVisible() ||
 		h.geminiModelDialog.IsVisible() || h.promptInputDialog.IsVisible() || h.sessionPickerDialog.IsVisible() ||
-		h.codeBlockDialog.IsVisible() ||
+		h.codeBlockDialog.IsVisible() || h.retryStartDialog.IsVisible() || h.duplicatesDialog.IsVisible() ||
+		h.tasksPanel.IsVisible() || h.boardView.IsVisible() || h.outputHistoryDialog.IsVisible() || h.statusTimelineDialog.IsVisible() ||
+		h.taskQueueDialog.IsVisible() || h.promptPickerDialog.IsVisible() || h.extensionPickerDialog.IsVisible() ||
 		h.sessionSwitcher.IsVisible() || h.scrollbackPager.IsVisible() ||
-		h.worktreeFinishDialog.IsVisible() || h.editPathsDialog.IsVisible() ||
+		h.worktreeFinishDialog.IsVisible() || h.worktreeDiffDialog.IsVisible() || h.editPathsDialog.IsVisible() ||
 		h.editSessionDialog.IsVisible() ||
 		h.zoxidePicker.IsVisible()
 }
@@ -7758,8 +8453,8 @@ func (h *Home) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 		if itemIndex < 0 || itemIndex >= len(h.flatItems) {
 			return h, nil
 		}
-		// Dividers are non-selectable: clicking one does nothing.
-		if h.flatItems[itemIndex].Type == session.ItemTypeDivider {
+		// Dividers and sub-agent rows are non-selectable: clicking one does nothing.
+		if !h.flatItems[itemIndex].Selectable() {
 			return h, nil
 		}
 
@@ -7822,6 +8517,9 @@ func (h *Home) getListContentStartY() int {
 	if h.maintenanceMsg != "" {
 		startY++ // Maintenance banner
 	}
+	if h.tasks.Running() > 0 {
+		startY++ // Background tasks line
+	}
 	// Panel title: 2 lines (title + underline)
 	startY += 2
 	return startY
@@ -7897,10 +8595,21 @@ func (h *Home) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	if h.selectMode {
+		if cmd, handled := h.handleSelectModeKey(key); handled {
+			return h, cmd
+		}
+	}
+
 	switch key {
 	case "q", "ctrl+c":
 		return h.tryQuit()
 
+	case "B", "shift+b":
+		// Select mode: mark sessions with Space for batch actions.
+		h.toggleSelectMode()
+		return h, nil
+
 	case "U":
 		// Dismiss the >5-releases-behind update nudge for this session.
 		// Only meaningful when the nudge is actually showing — otherwise
@@ -8042,7 +8751,7 @@ func (h *Home) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			h.globalSearch.SetSize(h.width, h.height)
 			h.globalSearch.Show()
 		} else {
-			h.search.Show()
+			return h, h.openLocalSearch()
 		}
 		return h, nil
 
@@ -8105,6 +8814,35 @@ func (h *Home) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		h.collapseOrNavUp()
 		return h, nil
 
+	case h.actionKey(hotkeyExtensions):
+		// Run an extension for the focused session; a window sub-row
+		// routes to its parent session.
+		if h.cursor < len(h.flatItems) {
+			item := h.flatItems[h.cursor]
+			switch item.Type {
+			case session.ItemTypeWindow:
+				h.openExtensionPicker(h.getInstanceByID(item.WindowSessionID))
+			case session.ItemTypeSession:
+				h.openExtensionPicker(item.Session)
+			}
+		}
+		return h, nil
+
+	case h.actionKey(hotkeyAttachPane):
+		// Attach the focused session in a new split (or window) of the
+		// current tmux client, keeping the deck visible alongside it.
+		if h.cursor < len(h.flatItems) {
+			item := h.flatItems[h.cursor]
+			if item.Type == session.ItemTypeSession && item.Session != nil {
+				if !h.canAttachInPane() {
+					h.setError(terminal.ErrNotInTmux)
+					return h, nil
+				}
+				h.attachInPane(item.Session, paneAttachMode(resolveAttachMode()))
+			}
+		}
+		return h, nil
+
 	case "shift+enter":
 		// Open the focused session in a new native terminal tab (or
 		// window, per [ui] iterm_open_as), leaving agent-deck running
@@ -8155,9 +8893,19 @@ func (h *Home) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 						}
 						return h, nil
 					}
+					// [ui] attach_mode = "split"/"window": attach next to the
+					// deck instead of taking over the terminal.
+					if mode := resolveAttachMode(); mode != session.AttachModeInline && h.canAttachInPane() {
+						h.attachInPane(item.Session, paneAttachMode(mode))
+						return h, nil
+					}
 					return h, h.attachSession(item.Session)
 				}
-				// Session exited (tmux session gone) — auto-restart it.
+				// Session exited (tmux session gone) — auto-restart it,
+				// unless its project folder moved: offer to relocate first.
+				if item.Session.ProjectPathMissing() {
+					return h, h.findRelocation(item.Session)
+				}
 				if !h.hasActiveAnimation(item.Session.ID) {
 					h.resumingSessions[item.Session.ID] = time.Now()
 					return h, h.restartSession(item.Session)
@@ -8521,20 +9269,7 @@ func (h *Home) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					h.setError(fmt.Errorf("session '%s' is not a worktree", inst.Title))
 					return h, nil
 				}
-				// Determine default target branch
-				defaultBranch := "main"
-				if detected, err := git.GetDefaultBranch(inst.WorktreeRepoRoot); err == nil {
-					defaultBranch = detected
-				}
-				h.worktreeFinishDialog.SetSize(h.width, h.height)
-				h.worktreeFinishDialog.Show(inst.ID, inst.Title, inst.WorktreeBranch, inst.WorktreeRepoRoot, inst.WorktreePath, defaultBranch)
-				// Trigger async dirty check
-				sid := inst.ID
-				wtPath := inst.WorktreePath
-				return h, func() tea.Msg {
-					dirty, err := git.HasUncommittedChanges(wtPath)
-					return worktreeDirtyCheckMsg{sessionID: sid, isDirty: dirty, err: err}
-				}
+				return h, h.openWorktreeFinish(inst, "")
 			}
 		}
 		return h, nil
@@ -8616,7 +9351,7 @@ func (h *Home) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			h.globalSearch.SetSize(h.width, h.height)
 			h.globalSearch.Show()
 		} else {
-			h.search.Show()
+			return h, h.openLocalSearch()
 		}
 		return h, nil
 
@@ -8879,7 +9614,8 @@ func (h *Home) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			item := h.flatItems[h.cursor]
 			if item.Type == session.ItemTypeSession && item.Session != nil {
 				status := item.Session.Status
-				if status == session.StatusStopped || status == session.StatusError {
+				if status == session.StatusStopped || status == session.StatusError ||
+					status == session.StatusExited || status == session.StatusCrashed {
 					h.confirmDialog.ShowRemoveSession(item.Session.ID, item.Session.Title)
 				} else {
 					h.setError(fmt.Errorf("session must be stopped or errored to remove; use 'd' to destructively delete a %s session", status))
@@ -8990,6 +9726,21 @@ func (h *Home) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return h, nil
 
+	case defaultHotkeyBindings[hotkeyContinueSession]:
+		// Nudge the highlighted idle/waiting session with its continuation
+		// prompt. A window sub-row routes to its parent session, like
+		// prompt_session.
+		if h.cursor < len(h.flatItems) {
+			item := h.flatItems[h.cursor]
+			switch item.Type {
+			case session.ItemTypeWindow:
+				h.continueSession(h.getInstanceByID(item.WindowSessionID))
+			case session.ItemTypeSession:
+				h.continueSession(item.Session)
+			}
+		}
+		return h, nil
+
 	case " ":
 		if len(h.flatItems) > 0 {
 			h.jumpMode = true
@@ -9097,6 +9848,15 @@ func (h *Home) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					h.setError(fmt.Errorf("session is starting, please wait..."))
 					return h, nil
 				}
+				// A failed start gets the command editor first so a typo or
+				// missing binary can be fixed in the same keystroke.
+				if startFailure(item.Session) != nil {
+					h.retryStartDialog.Show(item.Session.ID, item.Session.Title, item.Session.RetryCommand())
+					return h, nil
+				}
+				if item.Session.ProjectPathMissing() {
+					return h, h.findRelocation(item.Session)
+				}
 				if item.Session.CanRestart() {
 					// Track as resuming for animation (before async call starts)
 					h.resumingSessions[item.Session.ID] = time.Now()
@@ -9104,6 +9864,8 @@ func (h *Home) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				}
 			} else if item.Type == session.ItemTypeRemoteSession && item.RemoteSession != nil {
 				return h, h.restartRemoteSession(item.RemoteName, item.RemoteSession.ID, item.RemoteSession.Title)
+			} else if item.Type == session.ItemTypeGroup {
+				return h, h.restartGroup(item.Path)
 			}
 		}
 		return h, nil
@@ -9228,6 +9990,68 @@ func (h *Home) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+	case "alt+m":
+		return h, h.toggleMouseMode()
+
+	case "alt+v":
+		// Show / hide the preview pane. Hidden, the list takes the full width
+		// and no preview is captured; shown again, the selected session's
+		// output is fetched at once instead of on the next tick.
+		h.previewHidden = !h.previewHidden
+		h.saveUIState()
+		if h.previewHidden {
+			return h, nil
+		}
+		return h, h.fetchSelectedPreview()
+
+	case "alt+d":
+		h.openDuplicatesDialog()
+		return h, nil
+
+	case "alt+t":
+		h.openTasksPanel()
+		return h, nil
+
+	case "alt+b":
+		h.openBoard()
+		return h, nil
+
+	case "alt+p":
+		return h, h.applyProjectManifest()
+
+	case "alt+o":
+		h.openOutputHistory()
+		return h, nil
+
+	case "alt+w":
+		return h, h.openWorktreeDiff()
+
+	case "alt+h":
+		h.openStatusTimeline()
+		return h, nil
+
+	case "alt+q":
+		h.openTaskQueue()
+		return h, nil
+
+	case "alt+l":
+		// Same gating as the inline prompt input: Claude-compatible tools,
+		// and a window sub-row routes to its parent session.
+		if h.cursor < len(h.flatItems) {
+			item := h.flatItems[h.cursor]
+			switch item.Type {
+			case session.ItemTypeWindow:
+				if session.IsClaudeCompatible(item.WindowTool) {
+					h.openPromptLibrary(h.getInstanceByID(item.WindowSessionID))
+				}
+			case session.ItemTypeSession:
+				if item.Session != nil && session.IsClaudeCompatible(item.Session.Tool) {
+					h.openPromptLibrary(item.Session)
+				}
+			}
+		}
+		return h, nil
+
 	case "ctrl+r":
 		// Manual refresh (useful if watcher fails or for user preference)
 		state := h.preserveState()
@@ -9416,6 +10240,22 @@ func (h *Home) handleConfirmDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return h, nil
 
+	case ConfirmRelocate:
+		switch msg.String() {
+		case "y", "Y":
+			return h, h.confirmRelocate()
+		case "enter":
+			if h.confirmDialog.GetFocusedButton() == 0 {
+				return h, h.confirmRelocate()
+			}
+			h.confirmDialog.Hide()
+			return h, nil
+		case "n", "N", "esc":
+			h.confirmDialog.Hide()
+			return h, nil
+		}
+		return h, nil
+
 	case ConfirmNotice:
 		// Acknowledge-only: any of the usual dismiss keys closes it.
 		switch msg.String() {
@@ -9518,6 +10358,9 @@ func (h *Home) confirmAction() tea.Cmd {
 	case ConfirmBulkRemoveErrored:
 		h.confirmDialog.Hide()
 		return h.bulkRemoveErrored()
+	case ConfirmBulkDeleteMarked:
+		h.confirmDialog.Hide()
+		return h.deleteMarked()
 	}
 	h.confirmDialog.Hide()
 	return nil
@@ -10022,8 +10865,15 @@ func (h *Home) handleMCPDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				mcpUILog.Debug("dialog_apply_failed", slog.String("error", err.Error()))
 				h.setError(err)
 				h.mcpDialog.Hide() // Hide dialog even on error
+				h.batchMCPIDs = nil
 				return h, nil
 			}
+			// Batch MCP attach from select mode: copy the changes to the
+			// other marked sessions before Hide drops the dialog's lists.
+			var batchCmd tea.Cmd
+			if len(h.batchMCPIDs) > 0 {
+				batchCmd = h.applyBatchMCP()
+			}
 			mcpUILog.Debug("dialog_apply_succeeded")
 
 			// Find the session by ID (stored when dialog opened - same as Shift+S uses)
@@ -10040,6 +10890,15 @@ func (h *Home) handleMCPDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				)
 			}
 
+			if targetInst != nil && h.mcpDialog.HotSwapped() {
+				mcpUILog.Debug("dialog_hot_swapped", slog.String("session_id", targetInst.ID))
+				h.mcpDialog.Hide()
+				h.maintenanceMsg = "MCPs hot-swapped into " + targetInst.Title + " (no restart)"
+				h.maintenanceMsgTime = time.Now()
+				return h, tea.Batch(batchCmd, tea.Tick(5*time.Second, func(_ time.Time) tea.Msg {
+					return clearMaintenanceMsg{}
+				}))
+			}
 			if targetInst != nil {
 				mcpUILog.Debug("dialog_restarting_session", slog.String("session_id", targetInst.ID))
 				// Track as MCP loading for animation in preview pane
@@ -10048,17 +10907,21 @@ func (h *Home) handleMCPDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				targetInst.SkipMCPRegenerate = true
 				// Restart the session to apply MCP changes
 				h.mcpDialog.Hide()
-				return h, h.restartSession(targetInst)
+				return h, tea.Batch(h.restartSession(targetInst), batchCmd)
 			} else {
 				mcpUILog.Debug("dialog_session_not_found", slog.String("session_id", sessionID))
+				h.mcpDialog.Hide()
+				return h, batchCmd
 			}
 		}
 		mcpUILog.Debug("dialog_hiding_without_restart")
 		h.mcpDialog.Hide()
+		h.batchMCPIDs = nil
 		return h, nil
 
 	case "esc":
 		h.mcpDialog.Hide()
+		h.batchMCPIDs = nil
 		return h, nil
 
 	default:
@@ -10551,7 +11414,9 @@ func (h *Home) handleGroupDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		case GroupDialogMove:
 			targetGroupPath := h.groupDialog.GetSelectedGroup()
-			if targetGroupPath != "" && h.cursor < len(h.flatItems) {
+			if targetGroupPath != "" && len(h.markedInstances()) > 0 {
+				h.moveMarked(targetGroupPath)
+			} else if targetGroupPath != "" && h.cursor < len(h.flatItems) {
 				item := h.flatItems[h.cursor]
 				// A creating-session placeholder (Type == ItemTypeSession,
 				// Session == nil) must not reach MoveSessionToGroup or the
@@ -10938,6 +11803,7 @@ func (h *Home) saveUIState() {
 
 	state := uiState{
 		PreviewMode:   int(h.previewMode),
+		PreviewHidden: h.previewHidden,
 		StatusFilter:  string(h.statusFilter),
 		GroupViewMode: int(h.groupViewMode),
 	}
@@ -10992,6 +11858,7 @@ func (h *Home) loadUIState() {
 
 	// Apply preview mode, status filter, and group view mode immediately
 	h.previewMode = PreviewMode(state.PreviewMode)
+	h.previewHidden = state.PreviewHidden
 	h.statusFilter = session.Status(state.StatusFilter)
 	h.groupViewMode = session.GroupViewMode(state.GroupViewMode)
 	if h.groupViewMode < session.GroupViewNormal || h.groupViewMode >= session.GroupViewModeCount {
@@ -11017,6 +11884,9 @@ func (h *Home) createSessionInGroupWithWorktreeAndOptions(
 	tempID string,
 	autoName bool,
 ) tea.Cmd {
+	// Resolve the group's default MCPs here: the tree belongs to the UI goroutine.
+	groupMCPs := append([]string(nil), session.GroupDefaultMCPs(h.groupTree, groupPath)...)
+
 	return func() tea.Msg {
 		var setupWarning string // non-fatal worktree setup-script failure, if any
 
@@ -11050,7 +11920,9 @@ func (h *Home) createSessionInGroupWithWorktreeAndOptions(
 				if err := os.MkdirAll(filepath.Dir(worktreePath), 0o755); err != nil {
 					return sessionCreatedMsg{err: fmt.Errorf("failed to create parent directory: %w", err), tempID: tempID}
 				}
-				setupErr, err := createWorktreeWithSetupAndLog(backend, worktreePath, worktreeBranch)
+				task := h.tasks.Start("Create worktree "+worktreeBranch, nil)
+				setupErr, err := createWorktreeWithSetupAndLog(backend, worktreePath, worktreeBranch, task.Report)
+				finishWorktreeTask(task, setupErr, err)
 				if err != nil {
 					return sessionCreatedMsg{err: fmt.Errorf("failed to create worktree: %w", err), tempID: tempID}
 				}
@@ -11216,6 +12088,12 @@ func (h *Home) createSessionInGroupWithWorktreeAndOptions(
 			inst.SetParentWithPath(parentSessionID, parentProjectPath)
 		}
 
+		if added, err := session.AttachDefaultMCPs(inst, groupMCPs); err != nil {
+			uiLog.Warn("group_default_mcps_failed", slog.String("error", err.Error()))
+		} else if len(added) > 0 {
+			uiLog.Info("group_default_mcps_attached", slog.String("mcps", strings.Join(added, ",")))
+		}
+
 		uiLog.Info("session_create_starting",
 			slog.String("tool", inst.Tool),
 			slog.String("path", inst.ProjectPath),
@@ -11239,9 +12117,13 @@ func (h *Home) createSessionInGroupWithWorktreeAndOptions(
 // is created regardless, but the caller surfaces setupErr to the user. err is
 // the fatal worktree-creation error. The full setup output is logged here; only
 // the concise setupErr is returned for display (see formatSetupWarning).
-func createWorktreeWithSetupAndLog(backend vcs.Backend, wtPath, branch string) (setupErr error, err error) {
+func createWorktreeWithSetupAndLog(backend vcs.Backend, wtPath, branch string, report progress.Func) (setupErr error, err error) {
 	var buf bytes.Buffer
-	setupErr, err = vcsbackend.CreateWorktreeWithSetup(backend, wtPath, branch, &buf, &buf, session.GetWorktreeSettings().SetupTimeout())
+	lines := progress.LineWriter(report)
+	out := io.MultiWriter(&buf, lines)
+	report.Logf("Creating worktree for %s at %s", branch, wtPath)
+	setupErr, err = vcsbackend.CreateWorktreeWithSetup(backend, wtPath, branch, out, out, session.GetWorktreeSettings().SetupTimeout())
+	lines.Flush()
 	if err != nil {
 		return nil, err
 	}
@@ -11251,6 +12133,20 @@ func createWorktreeWithSetupAndLog(backend vcs.Backend, wtPath, branch string) (
 	return setupErr, nil
 }
 
+// finishWorktreeTask ends a worktree-creation task. A failed setup script
+// does not fail the session (see formatSetupWarning) but does mark the task
+// failed so its output stays one keypress away in the tasks panel.
+func finishWorktreeTask(task *Task, setupErr, err error) {
+	switch {
+	case err != nil:
+		task.Finish(err)
+	case setupErr != nil:
+		task.Finish(fmt.Errorf("setup script: %w", setupErr))
+	default:
+		task.Finish(nil)
+	}
+}
+
 // setupWarningMaxLen bounds the setup-script failure text shown in the footer,
 // which is height-constrained and auto-dismisses. The full output is in uiLog.
 const setupWarningMaxLen = 300
@@ -11296,6 +12192,8 @@ func createSessionTool(command string) (string, string) {
 		command = "cursor agent"
 	case "hermes":
 		tool = "hermes"
+	case "goose":
+		tool = "goose"
 	default:
 		if toolDef := session.GetToolDef(command); toolDef != nil {
 			tool = command
@@ -11991,7 +12889,7 @@ func (h *Home) buildForkCmd(
 			opts.WorktreeBranch = branchName
 			worktreeApplied = true
 		} else {
-			notice = "forked without worktree: not a git or jujutsu repo"
+			notice = "forked without worktree: not a git, jujutsu or sapling repo"
 		}
 	}
 	forkState := git.WorktreeStateOptions{WithState: toggles.WithState, WithIgnored: toggles.WithIgnored}
@@ -12133,7 +13031,9 @@ func (h *Home) forkSessionCmdWithOptions(
 				if err := os.MkdirAll(filepath.Dir(opts.WorktreePath), 0o755); err != nil {
 					return sessionForkedMsg{err: fmt.Errorf("failed to create directory: %w", err), sourceID: sourceID}
 				}
-				setupErr, err := createWorktreeWithSetupAndLog(backend, opts.WorktreePath, opts.WorktreeBranch)
+				task := h.tasks.Start("Create worktree "+opts.WorktreeBranch, nil)
+				setupErr, err := createWorktreeWithSetupAndLog(backend, opts.WorktreePath, opts.WorktreeBranch, task.Report)
+				finishWorktreeTask(task, setupErr, err)
 				if err != nil {
 					return sessionForkedMsg{err: fmt.Errorf("worktree creation failed: %w", err), sourceID: sourceID}
 				}
@@ -12607,6 +13507,12 @@ func restartWithArchiveTransition(
 // restartSession restarts a session, unarchiving it first when invoked from
 // the archived view.
 func (h *Home) restartSession(inst *session.Instance) tea.Cmd {
+	return h.restartSessionBatched(inst, nil)
+}
+
+// restartSessionBatched is restartSession with the cosmetic tmux options of
+// every session it starts deferred into batch (nil applies them inline).
+func (h *Home) restartSessionBatched(inst *session.Instance, batch *session.StartBatch) tea.Cmd {
 	id := inst.ID
 	mcpUILog.Debug(
 		"restart_session_called",
@@ -12628,7 +13534,19 @@ func (h *Home) restartSession(inst *session.Instance) tea.Cmd {
 			return sessionRestartedMsg{sessionID: id, err: err}
 		}
 
-		unarchived, err := restartWithArchiveTransition(current, h.persistArchived, current.Restart)
+		// Bring up stopped prerequisites (session depends) before the
+		// session itself.
+		h.instancesMu.RLock()
+		graph := session.NewDependencyGraph(h.instances)
+		h.instancesMu.RUnlock()
+		startPrereq := func(i *session.Instance) error { return batch.Do(i, i.Start) }
+		if _, err := graph.StartPrerequisites(id, startPrereq); err != nil {
+			mcpUILog.Debug("restart_session_result", slog.String("id", id), slog.Any("error", err))
+			return sessionRestartedMsg{sessionID: id, err: err}
+		}
+
+		restart := func() error { return batch.Do(current, current.Restart) }
+		unarchived, err := restartWithArchiveTransition(current, h.persistArchived, restart)
 		mcpUILog.Debug("restart_session_result", slog.String("id", id), slog.Any("error", err))
 		return sessionRestartedMsg{
 			sessionID:  id,
@@ -12639,6 +13557,67 @@ func (h *Home) restartSession(inst *session.Instance) tea.Cmd {
 	}
 }
 
+// relocationSearchMsg carries the folders a missing project may have moved to.
+type relocationSearchMsg struct {
+	sessionID  string
+	oldPath    string
+	candidates []session.RelocationCandidate
+}
+
+// findRelocation searches for where a session's missing project folder went;
+// the result opens the "did this move?" confirmation.
+func (h *Home) findRelocation(inst *session.Instance) tea.Cmd {
+	// Search on a detached copy of the paths: the instance may be replaced
+	// by a storage reload while the walk runs.
+	probe := &session.Instance{
+		ProjectPath:      inst.ProjectPath,
+		WorktreePath:     inst.WorktreePath,
+		WorktreeRepoRoot: inst.WorktreeRepoRoot,
+	}
+	id := inst.ID
+	return func() tea.Msg {
+		return relocationSearchMsg{
+			sessionID:  id,
+			oldPath:    probe.ProjectPath,
+			candidates: session.FindRelocationCandidates(probe, nil),
+		}
+	}
+}
+
+// confirmRelocate handles the "yes" action for ConfirmRelocate: every session
+// under the moved folder is repointed, then the selected one is restarted.
+func (h *Home) confirmRelocate() tea.Cmd {
+	id := h.confirmDialog.GetTargetID()
+	newPath := h.confirmDialog.GetRelocateTarget()
+	h.confirmDialog.Hide()
+	inst := h.getInstanceByID(id)
+	if inst == nil {
+		return nil
+	}
+
+	h.instancesMu.Lock()
+	rel, err := session.RelocateSessions(h.instances, inst, newPath)
+	h.instancesMu.Unlock()
+	if err != nil {
+		h.setError(fmt.Errorf("failed to relocate: %w", err))
+		return nil
+	}
+	h.groupTree.RelocateDefaultPaths(rel.OldPath, rel.NewPath)
+	for _, s := range rel.Sessions {
+		h.invalidatePreviewCache(s.ID)
+	}
+	h.saveInstances()
+	if len(rel.Warnings) > 0 {
+		h.setError(fmt.Errorf("relocated %d session(s) to %s: %s", len(rel.Sessions), rel.NewPath, strings.Join(rel.Warnings, "; ")))
+	}
+
+	if h.hasActiveAnimation(id) {
+		return nil
+	}
+	h.resumingSessions[id] = time.Now()
+	return h.restartSession(inst)
+}
+
 // restartSessionFresh restarts a session without resuming the previous tool session.
 func (h *Home) restartSessionFresh(inst *session.Instance) tea.Cmd {
 	return h.restartSessionFreshWith(inst, h.persistArchived, (*session.Instance).RestartFresh)
@@ -12794,13 +13773,10 @@ func (h *Home) restartRemoteSession(remoteName, sessionID, title string) tea.Cmd
 	}
 }
 
-// attachSession attaches to a session using custom PTY with Ctrl+Q detection
-func (h *Home) attachSession(inst *session.Instance) tea.Cmd {
-	tmuxSess := inst.GetTmuxSession()
-	if tmuxSess == nil {
-		return nil
-	}
-
+// prepareAttach runs the bookkeeping every attach path shares before handing
+// the terminal to tmux: deferred tmux configuration, session ID sync, access
+// time, title reconcile and the waiting-session acknowledgement.
+func (h *Home) prepareAttach(inst *session.Instance, tmuxSess *tmux.Session) {
 	// PERFORMANCE: Ensure tmux session is configured on first attach
 	// This runs deferred ConfigureStatusBar, EnableMouseMode
 	// which were skipped during lazy loading for TUI startup performance
@@ -12852,6 +13828,15 @@ func (h *Home) attachSession(inst *session.Instance) tea.Cmd {
 		}
 		statusLog.Debug("acknowledged_on_attach", slog.String("title", inst.Title))
 	}
+}
+
+// attachSession attaches to a session using custom PTY with Ctrl+Q detection
+func (h *Home) attachSession(inst *session.Instance) tea.Cmd {
+	tmuxSess := inst.GetTmuxSession()
+	if tmuxSess == nil {
+		return nil
+	}
+	h.prepareAttach(inst, tmuxSess)
 
 	// Use tea.Exec with a custom command that runs our Attach method
 	// On return, immediately update all session statuses (don't reload from storage
@@ -13182,7 +14167,7 @@ func (h *Home) countSessionStatuses() (running, waiting, idle, stopped, errored
 		snapshot = h.getSessionRenderSnapshot()
 	}
 	for _, state := range snapshot {
-		switch state.status {
+		switch statusCountBucket(state.status) {
 		case session.StatusRunning:
 			running++
 		case session.StatusWaiting:
@@ -13216,9 +14201,9 @@ func (h *Home) countSessionStatuses() (running, waiting, idle, stopped, errored
 				waiting++
 			case "idle":
 				idle++
-			case "stopped":
+			case "stopped", "exited":
 				stopped++
-			case "error":
+			case "error", "crashed":
 				errored++
 			}
 		}
@@ -13514,9 +14499,36 @@ func (h *Home) View() string {
 	if h.sessionPickerDialog.IsVisible() {
 		return h.sessionPickerDialog.View()
 	}
+	if h.duplicatesDialog.IsVisible() {
+		return h.duplicatesDialog.View()
+	}
+	if h.tasksPanel.IsVisible() {
+		return h.tasksPanel.View(h.tasks, h.animationFrame)
+	}
+	if h.boardView.IsVisible() {
+		return h.boardView.View(h.boardCards())
+	}
 	if h.codeBlockDialog.IsVisible() {
 		return h.codeBlockDialog.View()
 	}
+	if h.outputHistoryDialog.IsVisible() {
+		return h.outputHistoryDialog.View()
+	}
+	if h.promptPickerDialog.IsVisible() {
+		return h.promptPickerDialog.View()
+	}
+	if h.extensionPickerDialog.IsVisible() {
+		return h.extensionPickerDialog.View()
+	}
+	if h.statusTimelineDialog.IsVisible() {
+		return h.statusTimelineDialog.View()
+	}
+	if h.taskQueueDialog.IsVisible() {
+		return h.taskQueueDialog.View()
+	}
+	if h.worktreeDiffDialog.IsVisible() {
+		return h.worktreeDiffDialog.View()
+	}
 	if h.worktreeFinishDialog.IsVisible() {
 		return h.worktreeFinishDialog.View()
 	}
@@ -13706,6 +14718,16 @@ func (h *Home) View() string {
 		b.WriteString("\n")
 	}
 
+	// ═══════════════════════════════════════════════════════════════════
+	// TASKS LINE (while background tasks run)
+	// ═══════════════════════════════════════════════════════════════════
+	tasksLineHeight := 0
+	if indicator := h.renderTasksIndicator(); indicator != "" {
+		tasksLineHeight = 1
+		b.WriteString(indicator)
+		b.WriteString("\n")
+	}
+
 	// ═══════════════════════════════════════════════════════════════════
 	// MAIN CONTENT AREA - Responsive layout based on terminal width
 	// ═══════════════════════════════════════════════════════════════════
@@ -13714,8 +14736,8 @@ func (h *Home) View() string {
 	if h.debugMode {
 		debugBarHeight = 1
 	}
-	// Height breakdown: -1 header, -filterBarHeight filter, -updateBannerHeight banner, -maintenanceBannerHeight maintenance, -helpBarHeight help, -debugBarHeight debug
-	contentHeight := h.height - 1 - helpBarHeight - updateBannerHeight - maintenanceBannerHeight - filterBarHeight - debugBarHeight
+	// Height breakdown: -1 header, -filterBarHeight filter, -updateBannerHeight banner, -maintenanceBannerHeight maintenance, -tasksLineHeight tasks, -helpBarHeight help, -debugBarHeight debug
+	contentHeight := h.height - 1 - helpBarHeight - updateBannerHeight - maintenanceBannerHeight - tasksLineHeight - filterBarHeight - debugBarHeight
 
 	// Route to appropriate layout based on terminal width
 	layoutMode := h.getLayoutMode()
@@ -13802,6 +14824,9 @@ func (h *Home) View() string {
 	if h.promptInputDialog.IsVisible() {
 		rendered = h.promptInputDialog.View(rendered)
 	}
+	if h.retryStartDialog.IsVisible() {
+		rendered = h.retryStartDialog.View(rendered)
+	}
 	return rendered
 }
 
@@ -14627,6 +15652,8 @@ func (h *Home) renderHelpBarTiny() string {
 	var hint string
 	if h.jumpMode {
 		hint = lipgloss.NewStyle().Foreground(ColorYellow).Bold(true).Render("Jump: a-z/esc")
+	} else if h.selectMode {
+		hint = lipgloss.NewStyle().Foreground(ColorYellow).Bold(true).Render(fmt.Sprintf("Select (%d): space/esc", len(h.marked)))
 	} else {
 		hintText := "Help key unbound"
 		if helpKey != "" {
@@ -14689,6 +15716,12 @@ func (h *Home) renderHelpBarMinimal() string {
 			bufStyle := lipgloss.NewStyle().Foreground(ColorYellow).Bold(true)
 			contextKeys = bufStyle.Render(h.jumpBuffer+"…") + " " + contextKeys
 		}
+	} else if h.selectMode {
+		var keys []string
+		for _, hint := range h.selectModeHints() {
+			keys = append(keys, hint.key)
+		}
+		contextKeys = h.selectModeStatus() + " " + renderKeys(keys...)
 	} else if len(h.flatItems) == 0 {
 		contextKeys = renderKeys(newKey, quickKey, importKey, groupKey)
 	} else if h.cursor >= 0 && h.cursor < len(h.flatItems) {
@@ -14854,6 +15887,11 @@ func (h *Home) renderHelpBarCompact() string {
 			bufStyle := lipgloss.NewStyle().Foreground(ColorYellow).Bold(true)
 			contextHints = append([]string{bufStyle.Render(h.jumpBuffer + "…")}, contextHints...)
 		}
+	} else if h.selectMode {
+		contextHints = []string{h.selectModeStatus()}
+		for _, hint := range h.selectModeHints() {
+			contextHints = append(contextHints, h.helpKeyShort(hint.key, capitalizeFirst(hint.label)))
+		}
 	}
 
 	// Global hints (abbreviated)
@@ -15065,6 +16103,13 @@ func (h *Home) renderHelpBarFull() string {
 			primaryHints = append([]string{bufStyle.Render(h.jumpBuffer + "…")}, primaryHints...)
 		}
 		secondaryHints = nil
+	} else if h.selectMode {
+		contextTitle = "Select"
+		primaryHints = []string{h.selectModeStatus()}
+		for _, hint := range h.selectModeHints() {
+			primaryHints = append(primaryHints, h.helpKey(hint.key, capitalizeFirst(hint.label)))
+		}
+		secondaryHints = nil
 	}
 
 	// Top border
@@ -15293,8 +16338,11 @@ func (h *Home) curatedContextHints(item session.Item) []footerHint {
 // the status via the thread-safe getter since the render goroutine runs
 // concurrently with backgroundStatusUpdate (PR #1289 review nit 2).
 func sessionIsDead(s *session.Instance) bool {
-	status := s.GetStatusThreadSafe()
-	return status == session.StatusStopped || status == session.StatusError
+	switch s.GetStatusThreadSafe() {
+	case session.StatusStopped, session.StatusError, session.StatusExited, session.StatusCrashed:
+		return true
+	}
+	return false
 }
 
 // sessionIsQueued reports whether a session is waiting for a group slot and has
@@ -15321,6 +16369,9 @@ func (h *Home) renderHelpBarCurated() string {
 	switch {
 	case h.jumpMode:
 		contextHints = append(contextHints, footerHint{key: "a-z", label: "jump"}, footerHint{key: "esc", label: "cancel"})
+	case h.selectMode:
+		contextHints = append(contextHints, footerHint{key: fmt.Sprintf("%d", len(h.marked)), label: "marked"})
+		contextHints = append(contextHints, h.selectModeHints()...)
 	case len(h.flatItems) == 0:
 		// Empty list: the useful actions all create something — new session,
 		// import, or a group. Cap at the same budget as context hints.
@@ -15479,7 +16530,7 @@ func (h *Home) renderSessionList(width, height int) string {
 
 	for i := h.viewOffset; i < len(h.flatItems) && visibleCount < maxVisible; i++ {
 		item := h.flatItems[i]
-		if h.jumpMode && item.Type != session.ItemTypeDivider {
+		if h.jumpMode && item.Selectable() {
 			hint, ok := jumpHintByItemIndex[i]
 			if !ok {
 				h.renderItem(&b, item, i == h.cursor, i, groupStats, snapshot, width)
@@ -15604,6 +16655,8 @@ func (h *Home) renderItem(
 		h.renderRemoteSessionItem(b, item, selected)
 	case session.ItemTypeDivider:
 		h.renderDivider(b, item)
+	case session.ItemTypeSubagent:
+		h.renderSubagentItem(b, item, listWidth)
 	}
 }
 
@@ -15923,6 +16976,19 @@ func (h *Home) renderSessionItem(
 			selectionPrefix = " "
 		}
 	}
+	// Select mode: a marked row shows ✓ in place of the cursor arrow.
+	if h.isMarked(inst.ID) {
+		markStyle := lipgloss.NewStyle().Foreground(ColorYellow).Bold(true)
+		if selected {
+			markStyle = SessionSelectionPrefix
+		}
+		if selected && item.IsSubSession {
+			groupIndent := strings.Repeat(treeEmpty, max(0, item.Level-2))
+			baseIndent = groupIndent + markStyle.Render(" ✓")
+		} else {
+			selectionPrefix = markStyle.Render("✓")
+		}
+	}
 
 	tool := toolStyle.Render(" " + instTool)
 
@@ -15976,6 +17042,15 @@ func (h *Home) renderSessionItem(
 			wtStyle = SessionStatusSelStyle
 		}
 		worktreeBadge = wtStyle.Render(" [" + branch + "]")
+		if h.worktreeSyncWatcher != nil {
+			if st, ok := h.worktreeSyncWatcher.State(inst.ID); ok && st.Status == session.WorktreeSyncConflict {
+				conflictStyle := lipgloss.NewStyle().Foreground(ColorRed).Bold(true)
+				if selected {
+					conflictStyle = SessionStatusSelStyle
+				}
+				worktreeBadge += conflictStyle.Render(" [conflict]")
+			}
+		}
 	}
 
 	// Sandbox badge for containerized sessions.
@@ -16031,6 +17106,18 @@ func (h *Home) renderSessionItem(
 		timestampBadge = tsStyle.Render(" " + formatRelativeTime(ts))
 	}
 
+	// Estimated spend badge (display.show_session_cost).
+	costBadge := ""
+	if h.showSessionCost {
+		if cost := h.sessionCost(inst.ID); cost > 0 {
+			costStyle := DimStyle
+			if selected {
+				costStyle = SessionStatusSelStyle
+			}
+			costBadge = costStyle.Render(" " + costs.FormatUSD(cost))
+		}
+	}
+
 	// Window expand/collapse chevron for sessions with 2+ windows
 	windowChevron := " " // space placeholder to keep status icons aligned
 	if h.sessionHasWindows(item) {
@@ -16072,7 +17159,7 @@ func (h *Home) renderSessionItem(
 			cellWidth(status) + 1 /* space before title */ + cellWidth(tool) +
 			cellWidth(maestroBadge) + cellWidth(yoloBadge) + cellWidth(worktreeBadge) +
 			cellWidth(sandboxBadge) + cellWidth(multiRepoBadge) + cellWidth(sshBadge) +
-			cellWidth(timestampBadge)
+			cellWidth(timestampBadge) + cellWidth(costBadge)
 		budget := listWidth - reserved - 1 // -1 trailing margin
 		if budget > 0 && cellWidth(displayTitle) > budget {
 			displayTitle = cellTruncate(displayTitle, budget, "…")
@@ -16084,7 +17171,7 @@ func (h *Home) renderSessionItem(
 	// The leading gutter (leftGutterWidth) keeps sessions aligned with group
 	// rows, which reserve the same gutter for root hotkey numbers.
 	row := fmt.Sprintf(
-		"%s%s%s%s%s%s %s%s%s%s%s%s%s%s%s",
+		"%s%s%s%s%s%s %s%s%s%s%s%s%s%s%s%s",
 		strings.Repeat(" ", leftGutterWidth),
 		baseIndent,
 		selectionPrefix,
@@ -16100,6 +17187,7 @@ func (h *Home) renderSessionItem(
 		multiRepoBadge,
 		sshBadge,
 		timestampBadge,
+		costBadge,
 	)
 
 	// Append pane title filling remaining row space (only for the selected item).
@@ -16237,18 +17325,15 @@ func (h *Home) renderRemotePreview(item session.Item, width, height int) string
 	b.WriteString(nameStyle.Render(rs.Title))
 	b.WriteString("  ")
 
-	statusColor := ColorTextDim
+	statusColor := StatusColor(session.Status(rs.Status))
 	statusIcon := "○"
 	switch rs.Status {
 	case "running":
 		statusIcon = "●"
-		statusColor = ColorGreen
 	case "waiting":
 		statusIcon = "◐"
-		statusColor = ColorYellow
 	case "error":
 		statusIcon = "✗"
-		statusColor = ColorRed
 	}
 	b.WriteString(lipgloss.NewStyle().Foreground(statusColor).Render(statusIcon + " " + rs.Status))
 	b.WriteString("\n\n")
@@ -16350,15 +17435,32 @@ func (h *Home) renderRemoteGroupItem(b *strings.Builder, item session.Item, sele
 	}
 	h.remoteSessionsMu.RUnlock()
 
-	b.WriteString(fmt.Sprintf("%s%s %s%s%s\n",
+	hostStr := ""
+	if host := remoteHost(item.RemoteName); host != "" {
+		hostStr = countStyle.Render(" " + host)
+	}
+
+	b.WriteString(fmt.Sprintf("%s%s %s%s%s%s\n",
 		remoteRowGutter(selected), // align with group hotkey gutter (flush with local root groups)
 		expandIcon,
 		nameStyle.Render("remotes/"+item.RemoteName),
 		countStyle.Render(fmt.Sprintf(" (%d)", count)),
+		hostStr,
 		h.renderRemoteLatencyMarker(item.RemoteName, selected),
 	))
 }
 
+// remoteHost returns the SSH destination configured for remoteName, or "" when
+// the remote is not (or no longer) in [remotes]. The header shows it so two
+// remotes on different machines can be told apart at a glance.
+func remoteHost(remoteName string) string {
+	cfg, err := session.LoadUserConfig()
+	if err != nil || cfg == nil {
+		return ""
+	}
+	return cfg.Remotes[remoteName].Host
+}
+
 // renderRemoteLatencyMarker returns the colored ` — Xms` (or ` — offline`)
 // suffix for a remote group header. Empty string when no measurement has
 // been taken yet so the header doesn't jitter on first paint. See #1103.
@@ -16512,6 +17614,13 @@ func (h *Home) renderLaunchingState(inst *session.Instance, width int, startTime
 		} else {
 			toolDesc = "Starting Aider..."
 		}
+	case "goose":
+		toolName = "goose"
+		if isResuming {
+			toolDesc = "Resuming goose session..."
+		} else {
+			toolDesc = "Starting goose..."
+		}
 	case "codex":
 		toolName = "Codex"
 		if isResuming {
@@ -16775,18 +17884,7 @@ func (h *Home) renderSessionInfoCard(inst *session.Instance, width, height int)
 	b.WriteString(fmt.Sprintf("%s %s\n", labelStyle.Render("Path:"), valueStyle.Render(inst.ProjectPath)))
 
 	// Status with color
-	var statusColor lipgloss.Color
-	switch cardStatus {
-	case session.StatusRunning:
-		statusColor = ColorGreen
-	case session.StatusWaiting:
-		statusColor = ColorYellow
-	case session.StatusError:
-		statusColor = ColorRed
-	default:
-		statusColor = ColorTextDim
-	}
-	statusStyle := lipgloss.NewStyle().Foreground(statusColor)
+	statusStyle := lipgloss.NewStyle().Foreground(StatusColor(cardStatus))
 	b.WriteString(fmt.Sprintf("%s %s\n", labelStyle.Render("Status:"), statusStyle.Render(string(cardStatus))))
 
 	// Tool
@@ -16932,24 +18030,30 @@ func (h *Home) renderPreviewPane(width, height int) string {
 	// Cache status once to avoid races with background status updates
 	selectedStatus := selected.GetStatusThreadSafe()
 	statusIcon := "○"
-	statusColor := ColorTextDim
+	statusColor := StatusColor(selectedStatus)
 	switch selectedStatus {
 	case session.StatusRunning:
 		statusIcon = "●"
-		statusColor = ColorGreen
 	case session.StatusWaiting:
 		statusIcon = "◐"
-		statusColor = ColorYellow
 	case session.StatusError:
 		statusIcon = "✕"
-		statusColor = ColorRed
 	case session.StatusStopped:
 		statusIcon = "■"
-		statusColor = ColorTextDim
+	case session.StatusExited:
+		statusIcon = "✓"
+	case session.StatusCrashed:
+		statusIcon = "✗"
 	}
 
 	// Header with session name and status
-	statusBadge := lipgloss.NewStyle().Foreground(statusColor).Render(statusIcon + " " + string(selectedStatus))
+	statusText := string(selectedStatus)
+	if selectedStatus == session.StatusExited || selectedStatus == session.StatusCrashed {
+		if rec := selected.ExitRecord(); rec != nil {
+			statusText += " (" + rec.Describe() + ")"
+		}
+	}
+	statusBadge := lipgloss.NewStyle().Foreground(statusColor).Render(statusIcon + " " + statusText)
 	nameStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorAccent)
 	b.WriteString(nameStyle.Render(selected.Title))
 	b.WriteString("  ")
@@ -16961,6 +18065,11 @@ func (h *Home) renderPreviewPane(width, height int) string {
 	pathStr := truncatePath(selected.ProjectPath, width-4)
 	b.WriteString(infoStyle.Render("📁 " + pathStr))
 	b.WriteString("\n")
+	if selected.ProjectPathMissing() {
+		missingStyle := lipgloss.NewStyle().Foreground(ColorYellow)
+		b.WriteString(missingStyle.Render("   folder missing (moved?) · Enter/R to relocate"))
+		b.WriteString("\n")
+	}
 
 	// Activity time - shows when session was last active. Uses the display-
 	// oriented accessor so sessions with no confirmed activity (error/idle/
@@ -16989,6 +18098,19 @@ func (h *Home) renderPreviewPane(width, height int) string {
 	b.WriteString(groupBadge)
 	b.WriteString("\n")
 
+	// Ports claimed in the port registry (`agent-deck port claim`).
+	h.portClaimsMu.Lock()
+	ports := h.portClaims[selected.ID]
+	h.portClaimsMu.Unlock()
+	if len(ports) > 0 {
+		parts := make([]string, len(ports))
+		for i, c := range ports {
+			parts[i] = fmt.Sprintf(":%d %s", c.Port, c.Name)
+		}
+		b.WriteString(infoStyle.Render("🔌 " + strings.Join(parts, " · ")))
+		b.WriteString("\n")
+	}
+
 	// Worktree info section (for sessions running in git worktrees)
 	if selected.IsWorktree() {
 		wtHeader := renderSectionDivider("Worktree", width-4)
@@ -17044,6 +18166,48 @@ func (h *Home) renderPreviewPane(width, height int) string {
 		b.WriteString(dirtyStyle.Render(dirtyLabel))
 		b.WriteString("\n")
 
+		// Pull request (lazy-cached alongside the dirty check, 2m TTL).
+		// Nothing is shown until a PR is found, so repos without gh/glab
+		// don't get a permanent "checking..." line.
+		h.worktreePRMu.Lock()
+		pr := h.worktreePRCache[selected.ID]
+		h.worktreePRMu.Unlock()
+		if pr != nil {
+			prStyle := wtValueStyle
+			switch {
+			case pr.Checks == git.PRChecksFailing:
+				prStyle = lipgloss.NewStyle().Foreground(ColorRed)
+			case pr.State == git.PRStateMerged:
+				prStyle = lipgloss.NewStyle().Foreground(ColorPurple)
+			case pr.Checks == git.PRChecksPassing:
+				prStyle = lipgloss.NewStyle().Foreground(ColorGreen)
+			case pr.Checks == git.PRChecksPending:
+				prStyle = lipgloss.NewStyle().Foreground(ColorYellow)
+			}
+			b.WriteString(wtLabelStyle.Render("PR:      "))
+			b.WriteString(prStyle.Render(pr.Summary()))
+			b.WriteString("\n")
+		}
+
+		// Last sync onto the base branch (`worktree sync` or auto-sync)
+		if h.worktreeSyncWatcher != nil {
+			if st, ok := h.worktreeSyncWatcher.State(selected.ID); ok {
+				syncLabel := "up to date with " + st.Upstream
+				syncStyle := lipgloss.NewStyle().Foreground(ColorGreen)
+				switch st.Status {
+				case session.WorktreeSyncConflict:
+					syncLabel = fmt.Sprintf("conflict with %s (%d files)", st.Upstream, len(st.ConflictFiles))
+					syncStyle = lipgloss.NewStyle().Foreground(ColorRed).Bold(true)
+				case session.WorktreeSyncFailed:
+					syncLabel = "failed: " + st.Error
+					syncStyle = lipgloss.NewStyle().Foreground(ColorYellow)
+				}
+				b.WriteString(wtLabelStyle.Render("Sync:    "))
+				b.WriteString(syncStyle.Render(truncateStr(syncLabel, width-4-9)))
+				b.WriteString("\n")
+			}
+		}
+
 		// Setup hint
 		if setupKey := h.actionKey(hotkeyWorktreeSetup); setupKey != "" {
 			b.WriteString(wtHintStyle.Render("Setup:   "))
@@ -17061,6 +18225,11 @@ func (h *Home) renderPreviewPane(width, height int) string {
 		}
 	}
 
+	// Dependencies section: prerequisites and resolved start order
+	if len(selected.DependsOn) > 0 {
+		h.renderDependencySection(&b, selected, width)
+	}
+
 	// Multi-repo info section
 	if selected.IsMultiRepo() {
 		mrHeader := renderSectionDivider("Multi-Repo", width-4)
@@ -17564,22 +18733,31 @@ func (h *Home) renderPreviewPane(width, height int) string {
 		dimStyle := lipgloss.NewStyle().Foreground(ColorText)
 		keyStyle := lipgloss.NewStyle().Foreground(ColorAccent).Bold(true)
 
-		b.WriteString(warnStyle.Render("✕ No tmux session running"))
-		b.WriteString("\n\n")
-		b.WriteString(dimStyle.Render("This can happen if:"))
-		b.WriteString("\n")
-		b.WriteString(dimStyle.Render("  - Session was added but not yet started"))
-		b.WriteString("\n")
-		b.WriteString(dimStyle.Render("  - tmux server was restarted"))
-		b.WriteString("\n")
-		b.WriteString(dimStyle.Render("  - Terminal was closed or system rebooted"))
-		b.WriteString("\n\n")
+		failure := startFailure(selected)
+		if failure != nil {
+			renderStartFailure(&b, failure, width)
+		} else {
+			b.WriteString(warnStyle.Render("✕ No tmux session running"))
+			b.WriteString("\n\n")
+			b.WriteString(dimStyle.Render("This can happen if:"))
+			b.WriteString("\n")
+			b.WriteString(dimStyle.Render("  - Session was added but not yet started"))
+			b.WriteString("\n")
+			b.WriteString(dimStyle.Render("  - tmux server was restarted"))
+			b.WriteString("\n")
+			b.WriteString(dimStyle.Render("  - Terminal was closed or system rebooted"))
+			b.WriteString("\n\n")
+		}
 		b.WriteString(dimStyle.Render("Actions:"))
 		b.WriteString("\n")
 		if restartKey := h.actionKey(hotkeyRestart); restartKey != "" {
 			b.WriteString("  ")
 			b.WriteString(keyStyle.Render(restartKey))
-			b.WriteString(dimStyle.Render(" Start   - create and start tmux session"))
+			if failure != nil {
+				b.WriteString(dimStyle.Render(" Retry   - edit the command and start again"))
+			} else {
+				b.WriteString(dimStyle.Render(" Start   - create and start tmux session"))
+			}
 			b.WriteString("\n")
 		}
 		if selected.CanRestartFresh() {
@@ -18277,7 +19455,7 @@ func (h *Home) renderGroupPreview(group *session.Group, width, height int) strin
 	// Status breakdown with inline badges
 	running, waiting, idle, stopped, errored := 0, 0, 0, 0, 0
 	for _, sess := range group.Sessions {
-		switch sess.Status {
+		switch statusCountBucket(sess.Status) {
 		case session.StatusRunning:
 			running++
 		case session.StatusWaiting:
@@ -18356,6 +19534,18 @@ func (h *Home) renderGroupPreview(group *session.Group, width, height int) strin
 		b.WriteString("\n")
 	}
 
+	// Default MCPs attached to new sessions in this group (own or inherited)
+	if mcps := session.GroupDefaultMCPs(h.groupTree, group.Path); len(mcps) > 0 {
+		b.WriteString(renderSectionDivider("Default MCPs", width-4))
+		b.WriteString("\n")
+		line := strings.Join(mcps, ", ")
+		if origin := session.GroupMCPOrigin(h.groupTree, group.Path); origin != group.Path {
+			line += " (from " + origin + ")"
+		}
+		b.WriteString(lipgloss.NewStyle().Foreground(ColorText).Render(line))
+		b.WriteString("\n\n")
+	}
+
 	// Sessions divider
 	b.WriteString(renderSectionDivider("Sessions", width-4))
 	b.WriteString("\n")
@@ -18379,16 +19569,20 @@ func (h *Home) renderGroupPreview(group *session.Group, width, height int) strin
 
 			// Status icon
 			statusIcon := "○"
-			statusColor := ColorTextDim
+			statusColor := StatusColor(sess.Status)
 			switch sess.Status {
 			case session.StatusRunning:
-				statusIcon, statusColor = "●", ColorGreen
+				statusIcon = "●"
 			case session.StatusWaiting:
-				statusIcon, statusColor = "◐", ColorYellow
+				statusIcon = "◐"
 			case session.StatusError:
-				statusIcon, statusColor = "✕", ColorRed
+				statusIcon = "✕"
 			case session.StatusStopped:
-				statusIcon, statusColor = "■", ColorTextDim
+				statusIcon = "■"
+			case session.StatusExited:
+				statusIcon = "✓"
+			case session.StatusCrashed:
+				statusIcon = "✗"
 			}
 			status := lipgloss.NewStyle().Foreground(statusColor).Render(statusIcon)
 			name := lipgloss.NewStyle().Foreground(ColorText).Render(sess.Title)
@@ -18789,6 +19983,27 @@ func (h *Home) handleSessionSwitcherKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 }
 
+// openWorktreeFinish shows the finish dialog for a worktree session and
+// starts its dirty check. target pre-fills the merge target; empty uses the
+// repository's default branch.
+func (h *Home) openWorktreeFinish(inst *session.Instance, target string) tea.Cmd {
+	if target == "" {
+		target = "main"
+		if detected, err := git.GetDefaultBranch(inst.WorktreeRepoRoot); err == nil {
+			target = detected
+		}
+	}
+	h.worktreeFinishDialog.SetSize(h.width, h.height)
+	h.worktreeFinishDialog.Show(inst.ID, inst.Title, inst.WorktreeBranch, inst.WorktreeRepoRoot, inst.WorktreePath, target)
+	// Trigger async dirty check
+	sid := inst.ID
+	wtPath := inst.WorktreePath
+	return func() tea.Msg {
+		dirty, err := git.HasUncommittedChanges(wtPath)
+		return worktreeDirtyCheckMsg{sessionID: sid, isDirty: dirty, err: err}
+	}
+}
+
 // handleWorktreeFinishDialogKey processes key events for the worktree finish dialog
 func (h *Home) handleWorktreeFinishDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	action := h.worktreeFinishDialog.HandleKey(msg.String())
@@ -18799,7 +20014,7 @@ func (h *Home) handleWorktreeFinishDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd
 
 	case "confirm":
 		// Execute the finish operation
-		mergeEnabled, targetBranch, keepBranch := h.worktreeFinishDialog.GetOptions()
+		mergeEnabled, targetBranch, squash, keepBranch := h.worktreeFinishDialog.GetOptions()
 		h.worktreeFinishDialog.SetExecuting(true)
 
 		sid := h.worktreeFinishDialog.sessionID
@@ -18819,7 +20034,7 @@ func (h *Home) handleWorktreeFinishDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd
 		)
 		h.instancesMu.RUnlock()
 
-		return h, h.finishWorktree(inst, sid, sTitle, branch, repoRoot, wtPath, mergeEnabled, targetBranch, keepBranch, shared)
+		return h, h.finishWorktree(inst, sid, sTitle, branch, repoRoot, wtPath, mergeEnabled, targetBranch, squash, keepBranch, shared)
 
 	case "input":
 		// Pass through to text input
@@ -18857,7 +20072,7 @@ func (h *Home) runWorktreeSetup(inst *session.Instance) tea.Cmd {
 
 // finishWorktree performs the worktree finish operation asynchronously:
 // merge branch, remove worktree, delete branch, kill session, remove from storage
-func (h *Home) finishWorktree(inst *session.Instance, sessionID, sessionTitle, branchName, repoRoot, worktreePath string, mergeEnabled bool, targetBranch string, keepBranch bool, sharedWorktree bool) tea.Cmd {
+func (h *Home) finishWorktree(inst *session.Instance, sessionID, sessionTitle, branchName, repoRoot, worktreePath string, mergeEnabled bool, targetBranch string, squash, keepBranch bool, sharedWorktree bool) tea.Cmd {
 	return func() tea.Msg {
 		merged := false
 
@@ -18865,7 +20080,13 @@ func (h *Home) finishWorktree(inst *session.Instance, sessionID, sessionTitle, b
 		// and bare-repo layouts; in bare layouts the project root has no
 		// working tree, so checkout/merge cannot run there (#891).
 		if mergeEnabled {
-			if err := git.MergeBack(repoRoot, branchName, targetBranch); err != nil {
+			merge := func() error { return git.MergeBack(repoRoot, branchName, targetBranch) }
+			if squash {
+				merge = func() error {
+					return git.SquashMergeBack(repoRoot, branchName, targetBranch, fmt.Sprintf("%s (squashed %s)", sessionTitle, branchName))
+				}
+			}
+			if err := merge(); err != nil {
 				return worktreeFinishResultMsg{
 					sessionID: sessionID, sessionTitle: sessionTitle,
 					err: fmt.Errorf("merge failed: %v", err),
@@ -18920,8 +20141,8 @@ func (h *Home) getOtherActiveSessions(excludeID string) []*session.Instance {
 		if inst.ID == excludeID {
 			continue
 		}
-		s := inst.GetStatusThreadSafe()
-		if s == session.StatusError || s == session.StatusStopped {
+		switch inst.GetStatusThreadSafe() {
+		case session.StatusError, session.StatusStopped, session.StatusExited, session.StatusCrashed:
 			continue
 		}
 		result = append(result, inst)
@@ -19130,12 +20351,26 @@ func markGroupPathAndAncestors(groupsWithMatches map[string]bool, groupPath stri
 // FilterModeActive consults [display].active_filter_excludes; concrete
 // filters require exact match.
 func (h *Home) matchesStatusFilter(filter, status session.Status) bool {
+	status = statusCountBucket(status)
 	if filter == FilterModeActive {
 		return !h.activeFilterExcludes[status]
 	}
 	return status == filter
 }
 
+// statusCountBucket folds the agent-exit statuses into the buckets the header
+// pills and status filters know: a clean exit counts as stopped, a crash as
+// error. Row glyphs and the preview still show the precise status.
+func statusCountBucket(status session.Status) session.Status {
+	switch status {
+	case session.StatusExited:
+		return session.StatusStopped
+	case session.StatusCrashed:
+		return session.StatusError
+	}
+	return status
+}
+
 // renderFilterBarHint renders the filter-bar keyboard-shortcut hint with the
 // shortcut character of the currently-engaged filter highlighted (subtle shade
 // brighter than the surrounding faint hint text).
@@ -21,14 +21,17 @@ const (
 	hotkeyViewArchived     = "view_archived"
 	hotkeyUndoDelete       = "undo_delete"
 	hotkeyMoveToGroup      = "move_to_group"
+	hotkeySelectMode       = "select_mode" // mark sessions for batch delete/move/restart/MCP
 	hotkeyMCPManager       = "mcp_manager"
 	hotkeyPluginManager    = "plugin_manager"
 	hotkeySkillsManager    = "skills_manager"
 	hotkeyTogglePreview    = "toggle_preview"
+	hotkeyPreviewPane      = "preview_pane" // show / hide the preview pane
 	hotkeyCycleGroupView   = "cycle_group_view"
 	hotkeyMarkUnread       = "mark_unread"
 	hotkeyQuickApprove     = "quick_approve"
 	hotkeyPromptSession    = "prompt_session" // #1410: prompt the highlighted session without attaching
+	hotkeyContinueSession  = "continue_session"
 	hotkeyToggleYolo       = "toggle_yolo"
 	hotkeyQuickFork        = "quick_fork"
 	hotkeyForkWithOptions  = "fork_with_options"
@@ -37,11 +40,13 @@ const (
 	hotkeySendOutput       = "send_output"
 	hotkeyExecShell        = "exec_shell"
 	hotkeyOpenShellHere    = "open_shell_here"
+	hotkeyAttachPane       = "attach_pane" // attach in a split of the current tmux client
 	hotkeyEditNotes        = "edit_notes"
 	hotkeyEditPaths        = "edit_paths"
 	hotkeyEditSession      = "edit_session"
 	hotkeyWorktreeSetup    = "worktree_setup"
 	hotkeyWorktreeFinish   = "worktree_finish"
+	hotkeyWorktreeDiff     = "worktree_diff"
 	hotkeyCreateGroup      = "create_group"
 	hotkeySearch           = "search"
 	hotkeyHelp             = "help"
@@ -50,6 +55,16 @@ const (
 	hotkeyReload           = "reload"
 	hotkeyDetach           = "detach"
 	hotkeyWatcherPanel     = "watcher_panel"
+	hotkeyToggleMouse      = "toggle_mouse" // cycle mouse capture: full → click → off
+	hotkeyReviewDuplicates = "review_duplicates"
+	hotkeyTasksPanel       = "tasks_panel"
+	hotkeyBoardView        = "board_view" // sessions as waiting/running/idle/error columns
+	hotkeyApplyManifest    = "apply_manifest"
+	hotkeyOutputHistory    = "output_history"
+	hotkeyStatusTimeline   = "status_timeline"
+	hotkeyTaskQueue        = "task_queue"
+	hotkeyPromptLibrary    = "prompt_library"
+	hotkeyExtensions       = "extensions"
 	// Session switcher. While attached it is intercepted in the tmux attach
 	// loop (see internal/tmux/pty.go AttachOptions); on the home screen it is
 	// dispatched like any other hotkey. Must resolve to a "ctrl+<letter>" chord.
@@ -87,14 +102,17 @@ var hotkeyActionOrder = []string{
 	hotkeyViewArchived,
 	hotkeyUndoDelete,
 	hotkeyMoveToGroup,
+	hotkeySelectMode,
 	hotkeyMCPManager,
 	hotkeyPluginManager,
 	hotkeySkillsManager,
 	hotkeyTogglePreview,
+	hotkeyPreviewPane,
 	hotkeyCycleGroupView,
 	hotkeyMarkUnread,
 	hotkeyQuickApprove,
 	hotkeyPromptSession,
+	hotkeyContinueSession,
 	hotkeyToggleYolo,
 	hotkeyQuickFork,
 	hotkeyForkWithOptions,
@@ -103,11 +121,13 @@ var hotkeyActionOrder = []string{
 	hotkeySendOutput,
 	hotkeyExecShell,
 	hotkeyOpenShellHere,
+	hotkeyAttachPane,
 	hotkeyEditNotes,
 	hotkeyEditPaths,
 	hotkeyEditSession,
 	hotkeyWorktreeSetup,
 	hotkeyWorktreeFinish,
+	hotkeyWorktreeDiff,
 	hotkeyCreateGroup,
 	hotkeySearch,
 	hotkeyHelp,
@@ -116,6 +136,16 @@ var hotkeyActionOrder = []string{
 	hotkeyReload,
 	hotkeyDetach,
 	hotkeyWatcherPanel,
+	hotkeyToggleMouse,
+	hotkeyReviewDuplicates,
+	hotkeyTasksPanel,
+	hotkeyBoardView,
+	hotkeyApplyManifest,
+	hotkeyOutputHistory,
+	hotkeyStatusTimeline,
+	hotkeyTaskQueue,
+	hotkeyPromptLibrary,
+	hotkeyExtensions,
 	hotkeySwitchSession,
 }
 
@@ -133,14 +163,17 @@ var defaultHotkeyBindings = map[string]string{
 	hotkeyViewArchived:     "^",
 	hotkeyUndoDelete:       "ctrl+z",
 	hotkeyMoveToGroup:      "M",
+	hotkeySelectMode:       "B",
 	hotkeyMCPManager:       "m",
 	hotkeyPluginManager:    "L",
 	hotkeySkillsManager:    "s",
 	hotkeyTogglePreview:    "v",
+	hotkeyPreviewPane:      "alt+v",
 	hotkeyCycleGroupView:   "t",
 	hotkeyMarkUnread:       "u",
 	hotkeyQuickApprove:     "a",
 	hotkeyPromptSession:    "o",
+	hotkeyContinueSession:  "alt+c",
 	hotkeyToggleYolo:       "y",
 	hotkeyQuickFork:        "f",
 	hotkeyForkWithOptions:  "F",
@@ -149,11 +182,13 @@ var defaultHotkeyBindings = map[string]string{
 	hotkeySendOutput:       "x",
 	hotkeyExecShell:        "E",
 	hotkeyOpenShellHere:    "H",
+	hotkeyAttachPane:       "alt+enter",
 	hotkeyEditNotes:        "e",
 	hotkeyEditPaths:        "p",
 	hotkeyEditSession:      "P",
 	hotkeyWorktreeSetup:    "b",
 	hotkeyWorktreeFinish:   "W",
+	hotkeyWorktreeDiff:     "alt+w",
 	hotkeyCreateGroup:      "g",
 	hotkeySearch:           "/",
 	hotkeyHelp:             "?",
@@ -162,6 +197,16 @@ var defaultHotkeyBindings = map[string]string{
 	hotkeyReload:           "ctrl+r",
 	hotkeyDetach:           "ctrl+q",
 	hotkeyWatcherPanel:     "w",
+	hotkeyToggleMouse:      "alt+m",
+	hotkeyReviewDuplicates: "alt+d",
+	hotkeyTasksPanel:       "alt+t",
+	hotkeyBoardView:        "alt+b",
+	hotkeyApplyManifest:    "alt+p",
+	hotkeyOutputHistory:    "alt+o",
+	hotkeyStatusTimeline:   "alt+h",
+	hotkeyTaskQueue:        "alt+q",
+	hotkeyPromptLibrary:    "alt+l",
+	hotkeyExtensions:       "alt+x",
 	hotkeySwitchSession:    "ctrl+s",
 }
 
@@ -169,6 +214,7 @@ var hotkeyActionDefaultTriggers = map[string][]string{
 	hotkeyQuit:            {"q", "ctrl+c"},
 	hotkeyForkWithOptions: {"F", "shift+f"},
 	hotkeyMoveToGroup:     {"M", "shift+m"},
+	hotkeySelectMode:      {"B", "shift+b"},
 	hotkeyWorktreeFinish:  {"W", "shift+w"},
 	hotkeyEditSession:     {"P", "shift+p"},
 }
@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/asheshgoplani/agent-deck/internal/progress"
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// manifestAppliedMsg carries the outcome of a project manifest task back to
+// the update loop, which owns the instance list and group tree.
+type manifestAppliedMsg struct {
+	manifest *session.ProjectManifest
+	result   *session.ManifestResult
+}
+
+// manifestSearchDir is where the apply-manifest action looks for
+// .agentdeck.toml: the highlighted session's project, else the highlighted
+// group's default path, else the working directory.
+func (h *Home) manifestSearchDir() string {
+	if h.cursor >= 0 && h.cursor < len(h.flatItems) {
+		item := h.flatItems[h.cursor]
+		switch {
+		case item.Type == session.ItemTypeSession && item.Session != nil:
+			return item.Session.ProjectPath
+		case item.Type == session.ItemTypeGroup && h.groupTree != nil:
+			if dir := h.groupTree.DefaultPathForGroup(item.Path); dir != "" {
+				return dir
+			}
+		}
+	}
+	dir, _ := os.Getwd()
+	return dir
+}
+
+// applyProjectManifest materializes the nearest .agentdeck.toml as a
+// background task: missing sessions (and their worktrees) are created
+// without starting them, and the manifest's loadout is attached.
+func (h *Home) applyProjectManifest() tea.Cmd {
+	dir := h.manifestSearchDir()
+	path, ok := session.FindProjectManifest(dir)
+	if !ok {
+		h.setError(fmt.Errorf("no %s found in %s or its parents", session.ProjectManifestFile, dir))
+		return nil
+	}
+	manifest, err := session.LoadProjectManifest(path)
+	if err != nil {
+		h.setError(err)
+		return nil
+	}
+
+	h.instancesMu.RLock()
+	instances := append([]*session.Instance(nil), h.instances...)
+	h.instancesMu.RUnlock()
+
+	task := h.tasks.Start(fmt.Sprintf("Apply %s (%s)", session.ProjectManifestFile, filepath.Base(manifest.Dir)), nil)
+	return func() tea.Msg {
+		report := progress.Func(task.Report)
+		res := manifest.Materialize(instances, report)
+		for _, w := range res.Warnings {
+			report.Logf("warning: %s", w)
+		}
+		for title, err := range res.Failed {
+			report.Logf("%s: %v", title, err)
+		}
+		if len(res.Failed) > 0 {
+			task.Finish(fmt.Errorf("%d of %d sessions failed", len(res.Failed), len(manifest.Sessions)))
+		} else {
+			task.Finish(nil)
+		}
+		return manifestAppliedMsg{manifest: manifest, result: res}
+	}
+}
+
+// applyManifestResult adds the sessions a manifest task created, applies its
+// groups and saves.
+func (h *Home) applyManifestResult(msg manifestAppliedMsg) {
+	res := msg.result
+	h.instancesMu.Lock()
+	for _, inst := range res.Created {
+		h.instances = append(h.instances, inst)
+		h.instanceByID[inst.ID] = inst
+	}
+	h.instancesMu.Unlock()
+
+	msg.manifest.ApplyGroups(h.groupTree)
+	for _, inst := range res.Created {
+		h.groupTree.AddSession(inst)
+	}
+	h.groupTree.ExpandGroupWithParents(msg.manifest.RootGroup())
+	h.cachedStatusCounts.valid.Store(false)
+	h.rebuildFlatItems()
+	h.search.SetItems(h.instances)
+	// Existing sessions may have picked up loadout too, so save either way.
+	h.forceSaveInstances()
+
+	summary := fmt.Sprintf("%s: %d created, %d already present", session.ProjectManifestFile, len(res.Created), len(res.Existing))
+	if len(res.Failed) > 0 || len(res.Warnings) > 0 {
+		summary += fmt.Sprintf(", %d failed, %d warning(s)", len(res.Failed), len(res.Warnings))
+		if key := h.actionKey(hotkeyTasksPanel); key != "" {
+			summary += fmt.Sprintf(" (%s for details)", key)
+		}
+	}
+	h.setError(noticeError(h.err, summary))
+}
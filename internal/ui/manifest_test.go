@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+func TestHome_ApplyProjectManifest(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "acme")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+
+	h := NewHome()
+	h.width, h.height = 100, 40
+	if cmd := h.applyProjectManifest(); cmd != nil || h.err == nil || !strings.Contains(h.err.Error(), "no .agentdeck.toml") {
+		t.Fatalf("without a manifest: cmd=%v err=%v", cmd != nil, h.err)
+	}
+
+	body := "[[sessions]]\ntitle = \"api\"\ncmd = \"claude\"\n"
+	if err := os.WriteFile(filepath.Join(dir, session.ProjectManifestFile), []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := h.applyProjectManifest()
+	if cmd == nil || h.tasks.Running() != 1 {
+		t.Fatal("applying a manifest must run as a background task")
+	}
+	msg, ok := cmd().(manifestAppliedMsg)
+	if !ok || len(msg.result.Created) != 1 {
+		t.Fatalf("task result = %+v", msg)
+	}
+	h.applyManifestResult(msg)
+
+	if h.tasks.Running() != 0 {
+		t.Fatal("task must finish with the materialization")
+	}
+	if inst := h.getInstanceByID(msg.result.Created[0].ID); inst == nil || inst.GroupPath != "acme" {
+		t.Fatalf("created session not added to the deck: %+v", inst)
+	}
+	if h.groupTree.Groups["acme"] == nil {
+		t.Fatal("root group must exist")
+	}
+	if !strings.Contains(h.err.Error(), "1 created, 0 already present") {
+		t.Fatalf("summary = %v", h.err)
+	}
+}
@@ -76,11 +76,18 @@ type MCPDialog struct {
 	userAttachedIdx    int // USER scope index
 	userAvailableIdx   int // USER scope index
 
+	// Local attachments when the dialog opened, for ApplyLocalTo's delta
+	localInitial []string
+
 	// Track changes
 	localChanged  bool
 	globalChanged bool
 	userChanged   bool // USER scope changed
 
+	// hotSwapped: the last Apply only changed local MCPs of a session running
+	// the hot-swap hub, which picks them up without a restart
+	hotSwapped bool
+
 	err           error
 	configError   string // Error message from config parsing
 	typeJumpBuf   string
@@ -362,6 +369,7 @@ func (m *MCPDialog) Show(projectPath string, sessionID string, tool string) erro
 			m.scope = MCPScopeLocal
 		}
 	}
+	m.localInitial = mcpItemNames(m.localAttached)
 	m.column = MCPColumnAttached
 	m.localAttachedIdx = 0
 	m.localAvailableIdx = 0
@@ -419,6 +427,12 @@ func (m *MCPDialog) GetProjectPath() string {
 	return m.projectPath
 }
 
+// HotSwapped reports whether the last Apply was picked up by the session's
+// hot-swap hub, so no restart is needed.
+func (m *MCPDialog) HotSwapped() bool {
+	return m.hotSwapped
+}
+
 // GetSessionID returns the session ID being managed
 func (m *MCPDialog) GetSessionID() string {
 	return m.sessionID
@@ -582,6 +596,7 @@ func (m *MCPDialog) typeJump(r rune) {
 
 // Apply saves the changes to the active tool's local, global, or user MCP store.
 func (m *MCPDialog) Apply() error {
+	m.hotSwapped = false
 	mcpDialogLog.Debug("mcp_apply_start",
 		slog.String("tool", m.tool),
 		slog.Bool("local_changed", m.localChanged),
@@ -642,6 +657,10 @@ func (m *MCPDialog) Apply() error {
 			enabledNames[i] = item.Name
 		}
 
+		// Checked before the write: only a session started with the hub has it.
+		m.hotSwapped = !m.globalChanged && !m.userChanged &&
+			session.IsClaudeCompatible(m.tool) && session.MCPHubActive(m.projectPath)
+
 		// Write to .mcp.json
 		if err := session.WriteMCPJsonFromConfig(m.projectPath, enabledNames); err != nil {
 			m.err = err
@@ -698,6 +717,70 @@ func (m *MCPDialog) Apply() error {
 	return nil
 }
 
+// ApplyLocalTo applies the LOCAL changes made in the dialog — MCPs attached
+// and detached since Show — to another project of the same tool, keeping that
+// project's other attachments. Used for batch MCP attach from the home
+// screen's select mode; GLOBAL and USER scope are shared and written by Apply.
+func (m *MCPDialog) ApplyLocalTo(projectPath string) error {
+	if !m.localChanged || projectPath == m.projectPath {
+		return nil
+	}
+	var current []string
+	switch {
+	case m.tool == "gemini" || session.IsCodexCompatible(m.tool):
+		return nil // global scope only
+	case m.tool == "cursor":
+		current = session.GetCursorMCPInfo(projectPath).Local()
+	default:
+		current = session.GetMCPInfo(projectPath).Local()
+	}
+
+	before := make(map[string]bool, len(m.localInitial))
+	for _, name := range m.localInitial {
+		before[name] = true
+	}
+	after := make(map[string]bool, len(m.localAttached))
+	for _, item := range m.localAttached {
+		after[item.Name] = true
+	}
+	var names []string
+	have := make(map[string]bool, len(current))
+	for _, name := range current {
+		if before[name] && !after[name] {
+			continue // detached in the dialog
+		}
+		names = append(names, name)
+		have[name] = true
+	}
+	for _, item := range m.localAttached {
+		if !before[item.Name] && !have[item.Name] {
+			names = append(names, item.Name)
+		}
+	}
+
+	if m.tool == "cursor" {
+		if err := session.WriteLocalMCPConfigForTool(m.tool, projectPath, names); err != nil {
+			return err
+		}
+		session.InvalidateProjectMCPIntegrationsCache(projectPath)
+		return nil
+	}
+	if err := session.WriteMCPJsonFromConfig(projectPath, names); err != nil {
+		return err
+	}
+	session.ClearMCPCache(projectPath)
+	return nil
+}
+
+// mcpItemNames returns the names of items, in order.
+func mcpItemNames(items []MCPItem) []string {
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = item.Name
+	}
+	return names
+}
+
 // Update handles input
 func (m *MCPDialog) Update(msg tea.KeyMsg) (*MCPDialog, tea.Cmd) {
 	list, idx := m.getCurrentList()
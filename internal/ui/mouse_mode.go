@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// Mouse capture modes ([ui] mouse / mouse_screens, toggle_mouse hotkey).
+//
+// Bubble Tea only offers cell-motion and all-motion tracking, both of which
+// report drags and so take text selection away from the terminal. Click-only
+// needs xterm's normal tracking mode (1000), which Bubble Tea cannot enable,
+// so every mode is applied by writing the DEC mode sequences directly —
+// the same way RestoreLegacyKeyboardCmd restores keyboard state after
+// attach. Bubble Tea still parses the resulting SGR/X10 reports as
+// tea.MouseMsg.
+
+// mouseModeSequence returns the escape sequence that switches the terminal
+// to mode. The previous tracking mode is always reset first: xterm keeps a
+// single tracking mode, so resetting one after setting another would turn
+// tracking off entirely.
+func mouseModeSequence(mode string) string {
+	switch mode {
+	case session.MouseModeOff:
+		return ansi.ResetNormalMouseMode + ansi.ResetButtonEventMouseMode +
+			ansi.ResetAnyEventMouseMode + ansi.ResetSgrExtMouseMode
+	case session.MouseModeClick:
+		return ansi.ResetButtonEventMouseMode + ansi.ResetAnyEventMouseMode +
+			ansi.SetNormalMouseMode + ansi.SetSgrExtMouseMode
+	default:
+		return ansi.ResetNormalMouseMode + ansi.ResetAnyEventMouseMode +
+			ansi.SetButtonEventMouseMode + ansi.SetSgrExtMouseMode
+	}
+}
+
+// SetMouseModeCmd returns a tea.Cmd that switches the terminal's mouse
+// reporting to mode. Takes a writer so tests can substitute a buffer.
+func SetMouseModeCmd(w io.Writer, mode string) tea.Cmd {
+	return func() tea.Msg {
+		_, _ = io.WriteString(w, mouseModeSequence(mode))
+		return nil
+	}
+}
+
+// ResetMouseReporting turns off click-only tracking. Bubble Tea's shutdown
+// disables the modes it knows about but not mode 1000, so the CLI calls
+// this after the program exits to avoid leaving the shell reporting clicks.
+func ResetMouseReporting(w io.Writer) {
+	_, _ = io.WriteString(w, ansi.ResetNormalMouseMode)
+}
+
+// mouseModeLabel is the human name shown when the mode is toggled.
+func mouseModeLabel(mode string) string {
+	switch mode {
+	case session.MouseModeOff:
+		return "off (terminal text selection)"
+	case session.MouseModeClick:
+		return "click-only (clicks and scroll wheel)"
+	default:
+		return "full (clicks, scroll wheel and drag)"
+	}
+}
+
+// nextMouseMode cycles full → click → off → full.
+func nextMouseMode(mode string) string {
+	switch mode {
+	case session.MouseModeFull:
+		return session.MouseModeClick
+	case session.MouseModeClick:
+		return session.MouseModeOff
+	default:
+		return session.MouseModeFull
+	}
+}
+
+// mouseScreen names the screen currently in front, for per-screen mouse
+// overrides.
+func (h *Home) mouseScreen() string {
+	switch {
+	case h.scrollbackPager.IsVisible():
+		return session.MouseScreenScrollback
+	case h.globalSearch.IsVisible() || h.search.IsVisible():
+		return session.MouseScreenSearch
+	case h.settingsPanel.IsVisible():
+		return session.MouseScreenSettings
+	case h.helpOverlay.IsVisible():
+		return session.MouseScreenHelp
+	case h.hasModalVisible() && !h.initialLoading && !h.jumpMode:
+		return session.MouseScreenDialog
+	}
+	return session.MouseScreenList
+}
+
+// effectiveMouseMode is the mode for the current screen: its override when
+// configured, otherwise the base mode.
+func (h *Home) effectiveMouseMode() string {
+	if mode, ok := h.mouseScreenModes[h.mouseScreen()]; ok {
+		return mode
+	}
+	if h.mouseMode == "" {
+		return session.DefaultMouseMode
+	}
+	return h.mouseMode
+}
+
+// syncMouseModeCmd returns a command switching the terminal to the
+// effective mode when it differs from the applied one, or nil. Called after
+// every update so opening or closing a screen with an override takes effect
+// immediately.
+func (h *Home) syncMouseModeCmd() tea.Cmd {
+	mode := h.effectiveMouseMode()
+	applied := h.appliedMouseMode
+	if applied == "" {
+		applied = session.MouseModeFull
+	}
+	if mode == applied {
+		return nil
+	}
+	h.appliedMouseMode = mode
+	return SetMouseModeCmd(os.Stdout, mode)
+}
+
+// reapplyMouseModeCmd re-sends the effective mode unconditionally. Used on
+// return from an attach, where tmux has reset the terminal's mouse state.
+func (h *Home) reapplyMouseModeCmd() tea.Cmd {
+	h.appliedMouseMode = h.effectiveMouseMode()
+	return SetMouseModeCmd(os.Stdout, h.appliedMouseMode)
+}
+
+// toggleMouseMode cycles the base mode for this run (toggle_mouse hotkey).
+// Per-screen overrides still apply on their screens.
+func (h *Home) toggleMouseMode() tea.Cmd {
+	if h.mouseMode == "" {
+		h.mouseMode = session.DefaultMouseMode
+	}
+	h.mouseMode = nextMouseMode(h.mouseMode)
+	if h.draggingDivider && h.mouseMode != session.MouseModeFull {
+		// Drag tracking is going away mid-drag; keep the ratio reached so far.
+		h.draggingDivider = false
+		persistPreviewPct(h.getPreviewPct())
+	}
+	h.setError(fmt.Errorf("Mouse: %s", mouseModeLabel(h.mouseMode)))
+	return h.syncMouseModeCmd()
+}
@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+func TestMouseModeSequence(t *testing.T) {
+	cases := []struct {
+		mode       string
+		want       []string
+		wantAbsent []string
+	}{
+		{session.MouseModeFull, []string{"\x1b[?1000l", "\x1b[?1002h", "\x1b[?1006h"}, []string{"\x1b[?1000h"}},
+		{session.MouseModeClick, []string{"\x1b[?1002l", "\x1b[?1000h", "\x1b[?1006h"}, []string{"\x1b[?1002h"}},
+		{session.MouseModeOff, []string{"\x1b[?1000l", "\x1b[?1002l", "\x1b[?1006l"}, []string{"h"}},
+	}
+	for _, tc := range cases {
+		var buf bytes.Buffer
+		SetMouseModeCmd(&buf, tc.mode)()
+		got := buf.String()
+		for _, seq := range tc.want {
+			if !strings.Contains(got, seq) {
+				t.Errorf("%s: sequence %q missing %q", tc.mode, got, seq)
+			}
+		}
+		for _, seq := range tc.wantAbsent {
+			if strings.Contains(got, seq) {
+				t.Errorf("%s: sequence %q must not contain %q", tc.mode, got, seq)
+			}
+		}
+	}
+	// The old tracking mode must be reset before the new one is set.
+	click := mouseModeSequence(session.MouseModeClick)
+	if strings.Index(click, "\x1b[?1002l") > strings.Index(click, "\x1b[?1000h") {
+		t.Errorf("click-only must reset cell motion before enabling normal tracking: %q", click)
+	}
+}
+
+func TestHome_MouseModeToggleAndScreenOverride(t *testing.T) {
+	h := NewHome()
+	h.mouseMode = session.MouseModeFull
+	h.mouseScreenModes = map[string]string{session.MouseScreenScrollback: session.MouseModeOff}
+	h.appliedMouseMode = session.MouseModeFull
+
+	if cmd := h.syncMouseModeCmd(); cmd != nil {
+		t.Fatal("no command expected while the effective mode is already applied")
+	}
+
+	if cmd := h.toggleMouseMode(); cmd == nil || h.appliedMouseMode != session.MouseModeClick {
+		t.Fatalf("toggle should switch to click-only, applied=%q", h.appliedMouseMode)
+	}
+	h.toggleMouseMode()
+	if h.mouseMode != session.MouseModeOff {
+		t.Fatalf("second toggle = %q, want off", h.mouseMode)
+	}
+	h.toggleMouseMode()
+	if h.mouseMode != session.MouseModeFull {
+		t.Fatalf("third toggle = %q, want full", h.mouseMode)
+	}
+
+	h.scrollbackPager.Show("title", "sess-1", 80, 24)
+	if got := h.mouseScreen(); got != session.MouseScreenScrollback {
+		t.Fatalf("mouseScreen() = %q, want scrollback", got)
+	}
+	if cmd := h.syncMouseModeCmd(); cmd == nil || h.appliedMouseMode != session.MouseModeOff {
+		t.Fatalf("scrollback override should switch to off, applied=%q", h.appliedMouseMode)
+	}
+	h.scrollbackPager.Hide()
+	if cmd := h.syncMouseModeCmd(); cmd == nil || h.appliedMouseMode != session.MouseModeFull {
+		t.Fatalf("closing the pager should restore the base mode, applied=%q", h.appliedMouseMode)
+	}
+}
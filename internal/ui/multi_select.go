@@ -0,0 +1,308 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Select mode (hotkeySelectMode, default B): Space marks the session under
+// the cursor (or every session in a group) and moves down; the delete, move,
+// restart and MCP keys then act on all marked sessions at once. With nothing
+// marked those keys keep their single-session meaning. Esc clears the marks
+// and leaves the mode.
+
+// toggleSelectMode enters select mode, or leaves it and drops the marks.
+func (h *Home) toggleSelectMode() {
+	if h.selectMode {
+		h.exitSelectMode()
+		return
+	}
+	h.selectMode = true
+	h.marked = make(map[string]bool)
+}
+
+// exitSelectMode leaves select mode and clears every mark.
+func (h *Home) exitSelectMode() {
+	h.selectMode = false
+	h.marked = nil
+	h.batchMCPIDs = nil
+}
+
+// isMarked reports whether the session id is marked in select mode.
+func (h *Home) isMarked(id string) bool {
+	return h.selectMode && h.marked[id]
+}
+
+// markedInstances returns the marked sessions that still exist. Marks of
+// sessions deleted meanwhile are dropped.
+func (h *Home) markedInstances() []*session.Instance {
+	if len(h.marked) == 0 {
+		return nil
+	}
+	h.instancesMu.RLock()
+	defer h.instancesMu.RUnlock()
+	var out []*session.Instance
+	seen := make(map[string]bool, len(h.marked))
+	for _, inst := range h.instances {
+		if h.marked[inst.ID] {
+			out = append(out, inst)
+			seen[inst.ID] = true
+		}
+	}
+	for id := range h.marked {
+		if !seen[id] {
+			delete(h.marked, id)
+		}
+	}
+	return out
+}
+
+// toggleMarkAtCursor marks or unmarks the session under the cursor and moves
+// down. On a group row it marks every session in the group and its
+// subgroups, or unmarks them all when they already are.
+func (h *Home) toggleMarkAtCursor() tea.Cmd {
+	if h.cursor < 0 || h.cursor >= len(h.flatItems) {
+		return nil
+	}
+	item := h.flatItems[h.cursor]
+	switch {
+	case item.Type == session.ItemTypeSession && item.Session != nil:
+		if h.marked[item.Session.ID] {
+			delete(h.marked, item.Session.ID)
+		} else {
+			h.marked[item.Session.ID] = true
+		}
+	case item.Type == session.ItemTypeGroup:
+		var ids []string
+		allMarked := true
+		h.instancesMu.RLock()
+		for _, inst := range h.instances {
+			if inst.GroupPath == item.Path || strings.HasPrefix(inst.GroupPath, item.Path+"/") {
+				ids = append(ids, inst.ID)
+				allMarked = allMarked && h.marked[inst.ID]
+			}
+		}
+		h.instancesMu.RUnlock()
+		for _, id := range ids {
+			if allMarked {
+				delete(h.marked, id)
+			} else {
+				h.marked[id] = true
+			}
+		}
+	default:
+		return nil
+	}
+
+	if h.cursor < len(h.flatItems)-1 {
+		h.cursor++
+		h.skipDivider(1)
+		h.syncViewport()
+		return h.fetchSelectedPreview()
+	}
+	return nil
+}
+
+// handleSelectModeKey handles key (already normalized to its default
+// binding) in select mode. handled is false when the key should fall through
+// to normal handling.
+func (h *Home) handleSelectModeKey(key string) (cmd tea.Cmd, handled bool) {
+	switch key {
+	case " ":
+		return h.toggleMarkAtCursor(), true
+	case "esc":
+		h.exitSelectMode()
+		return nil, true
+	}
+
+	marked := h.markedInstances()
+	if len(marked) == 0 {
+		return nil, false
+	}
+	switch key {
+	case "d":
+		count := 0
+		for _, inst := range marked {
+			if inst.Pin == session.PinNone {
+				count++
+			}
+		}
+		if count == 0 {
+			h.setError(fmt.Errorf("all marked sessions are pinned"))
+			return nil, true
+		}
+		h.confirmDialog.ShowBulkDeleteMarked(count)
+		return nil, true
+	case "M", "shift+m":
+		h.groupDialog.ShowMove(h.scopedGroupPaths())
+		return nil, true
+	case "R":
+		return h.restartMarked(marked), true
+	case "m":
+		return h.openBatchMCP(marked), true
+	}
+	return nil, false
+}
+
+// deleteMarked deletes every marked, unpinned session and leaves select mode.
+func (h *Home) deleteMarked() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, inst := range h.markedInstances() {
+		// pin-protects-from-stop, as in bulkRemoveErrored.
+		if inst.Pin != session.PinNone {
+			continue
+		}
+		cmds = append(cmds, h.deleteSession(inst))
+	}
+	h.exitSelectMode()
+	return tea.Batch(cmds...)
+}
+
+// moveMarked moves every marked session to targetGroupPath and leaves
+// select mode.
+func (h *Home) moveMarked(targetGroupPath string) {
+	for _, inst := range h.markedInstances() {
+		h.groupTree.MoveSessionToGroup(inst, targetGroupPath)
+		h.pendingGroupOps = append(h.pendingGroupOps, pendingGroupOp{
+			kind: groupOpMove, sessionID: inst.ID, targetPath: targetGroupPath,
+		})
+	}
+	h.exitSelectMode()
+	h.instancesMu.Lock()
+	h.instances = h.groupTree.GetAllInstances()
+	h.instancesMu.Unlock()
+	h.rebuildFlatItems()
+	h.saveInstances()
+}
+
+// restartMarked restarts the marked sessions one at a time as a background
+// task, like a group restart.
+func (h *Home) restartMarked(marked []*session.Instance) tea.Cmd {
+	var targets []*session.Instance
+	for _, inst := range marked {
+		if inst.IsArchived() || !inst.CanRestart() || h.hasActiveAnimation(inst.ID) {
+			continue
+		}
+		targets = append(targets, inst)
+	}
+	if len(targets) == 0 {
+		h.setError(fmt.Errorf("none of the marked sessions can be restarted"))
+		return nil
+	}
+	h.exitSelectMode()
+	return h.restartSessionsTask(fmt.Sprintf("Restart %d marked sessions", len(targets)), "from the selection", targets)
+}
+
+// openBatchMCP opens the MCP manager for the first marked session that
+// supports it. On apply, the changes are copied to the other marked sessions
+// of the same tool and all of them restart (see applyBatchMCP).
+func (h *Home) openBatchMCP(marked []*session.Instance) tea.Cmd {
+	var anchor *session.Instance
+	h.batchMCPIDs = nil
+	for _, inst := range marked {
+		if !session.ToolSupportsMCPManager(inst.Tool) {
+			continue
+		}
+		if anchor == nil {
+			anchor = inst
+			continue
+		}
+		if sameMCPTool(anchor.Tool, inst.Tool) {
+			h.batchMCPIDs = append(h.batchMCPIDs, inst.ID)
+		}
+	}
+	if anchor == nil {
+		h.setError(fmt.Errorf("none of the marked sessions supports MCPs"))
+		return nil
+	}
+	h.mcpDialog.SetSize(h.width, h.height)
+	if err := h.mcpDialog.Show(anchor.ProjectPath, anchor.ID, anchor.Tool); err != nil {
+		h.batchMCPIDs = nil
+		h.setError(err)
+	}
+	return nil
+}
+
+// sameMCPTool reports whether two tools read MCPs from the same config
+// files, so a change made for one applies to the other.
+func sameMCPTool(a, b string) bool {
+	switch {
+	case a == b:
+		return true
+	case session.IsCodexCompatible(a) || session.IsCodexCompatible(b):
+		return session.IsCodexCompatible(a) && session.IsCodexCompatible(b)
+	case a == "gemini" || b == "gemini" || a == "cursor" || b == "cursor":
+		return false
+	}
+	return true // Claude-compatible and opencode share .mcp.json
+}
+
+// applyBatchMCP copies the MCP dialog's local changes, already applied to
+// the dialog's own session, to the other sessions of a batch MCP attach and
+// restarts them. It leaves select mode.
+func (h *Home) applyBatchMCP() tea.Cmd {
+	ids := h.batchMCPIDs
+	h.exitSelectMode()
+	var cmds []tea.Cmd
+	var failed []string
+	done := make(map[string]bool) // one write per project path
+	for _, id := range ids {
+		inst := h.getInstanceByID(id)
+		if inst == nil {
+			continue
+		}
+		if !done[inst.ProjectPath] {
+			done[inst.ProjectPath] = true
+			if err := h.mcpDialog.ApplyLocalTo(inst.ProjectPath); err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", inst.Title, err))
+				continue
+			}
+		}
+		if !inst.CanRestart() || h.hasActiveAnimation(inst.ID) {
+			continue
+		}
+		h.mcpLoadingSessions[inst.ID] = time.Now()
+		inst.SkipMCPRegenerate = true
+		cmds = append(cmds, h.restartSession(inst))
+	}
+	if len(failed) > 0 {
+		h.setError(fmt.Errorf("MCP changes not applied to %s", strings.Join(failed, "; ")))
+	}
+	return tea.Batch(cmds...)
+}
+
+// selectModeHints lists select mode's keys for the help bar: mark, the batch
+// actions under their current bindings, then leaving the mode.
+func (h *Home) selectModeHints() []footerHint {
+	hints := []footerHint{{key: "space", label: "mark"}}
+	for _, a := range []struct{ action, label string }{
+		{hotkeyDelete, "delete"},
+		{hotkeyMoveToGroup, "move"},
+		{hotkeyRestart, "restart"},
+		{hotkeyMCPManager, "MCP"},
+	} {
+		if key := h.actionKey(a.action); key != "" {
+			hints = append(hints, footerHint{key: key, label: a.label})
+		}
+	}
+	return append(hints, footerHint{key: "esc", label: "done"})
+}
+
+// selectModeStatus renders the "N marked" badge shown ahead of the hints.
+func (h *Home) selectModeStatus() string {
+	return lipgloss.NewStyle().Foreground(ColorYellow).Bold(true).Render(fmt.Sprintf("%d marked", len(h.marked)))
+}
+
+// capitalizeFirst upper-cases the first letter of a footer label for the
+// bars that title-case their hints.
+func capitalizeFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// armHomeForSelect builds a Home with three sessions in group "exp" and one
+// in "work". No tmux is started.
+func armHomeForSelect(t *testing.T) (*Home, []*session.Instance) {
+	t.Helper()
+	h := NewHome()
+	h.width, h.height = 120, 40
+	h.initialLoading = false
+
+	var insts []*session.Instance
+	for _, spec := range []struct{ title, group string }{
+		{"exp-1", "exp"}, {"exp-2", "exp"}, {"exp-3", "exp"}, {"keep", "work"},
+	} {
+		inst := session.NewInstanceWithTool(spec.title, "/tmp/sel/"+spec.title, "shell")
+		inst.GroupPath = spec.group
+		insts = append(insts, inst)
+	}
+	h.instancesMu.Lock()
+	h.instances = insts
+	h.instanceByID = make(map[string]*session.Instance, len(insts))
+	for _, inst := range insts {
+		h.instanceByID[inst.ID] = inst
+	}
+	h.instancesMu.Unlock()
+	h.groupTree = session.NewGroupTree(h.instances)
+	h.rebuildFlatItems()
+	return h, insts
+}
+
+func cursorTo(t *testing.T, h *Home, match func(session.Item) bool) {
+	t.Helper()
+	for i, item := range h.flatItems {
+		if match(item) {
+			h.cursor = i
+			return
+		}
+	}
+	t.Fatal("item not in list")
+}
+
+func TestSelectMode_MarkAndBatchDelete(t *testing.T) {
+	h, insts := armHomeForSelect(t)
+	key := func(r rune) { h.handleMainKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}) }
+
+	key('B')
+	if !h.selectMode {
+		t.Fatal("B should enter select mode")
+	}
+
+	// Space on a session marks it and moves down.
+	cursorTo(t, h, func(it session.Item) bool { return it.Session != nil && it.Session.ID == insts[3].ID })
+	start := h.cursor
+	h.handleMainKey(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	if !h.marked[insts[3].ID] {
+		t.Fatal("space should mark the session under the cursor")
+	}
+	if h.cursor == start && start < len(h.flatItems)-1 {
+		t.Error("space should move the cursor down")
+	}
+
+	// Space on a group marks every session in it.
+	cursorTo(t, h, func(it session.Item) bool { return it.Type == session.ItemTypeGroup && it.Path == "exp" })
+	h.handleMainKey(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	if len(h.marked) != 4 {
+		t.Fatalf("marked = %d, want 4", len(h.marked))
+	}
+
+	// Pinned sessions are left out of a batch delete.
+	insts[0].Pin = session.PinTop
+	key('d')
+	if !h.confirmDialog.IsVisible() || h.confirmDialog.GetConfirmType() != ConfirmBulkDeleteMarked {
+		t.Fatalf("d should open the batch delete confirm, got %v", h.confirmDialog.GetConfirmType())
+	}
+	if h.confirmDialog.mcpCount != 3 {
+		t.Errorf("batch delete count = %d, want 3", h.confirmDialog.mcpCount)
+	}
+	h.confirmDialog.Hide()
+
+	h.handleMainKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if h.selectMode || len(h.marked) != 0 {
+		t.Error("esc should leave select mode and clear the marks")
+	}
+}
+
+func TestSelectMode_MoveMarked(t *testing.T) {
+	h, insts := armHomeForSelect(t)
+	h.toggleSelectMode()
+	h.marked[insts[0].ID] = true
+	h.marked[insts[2].ID] = true
+
+	h.moveMarked("work")
+
+	for _, inst := range []*session.Instance{insts[0], insts[2]} {
+		if inst.GroupPath != "work" {
+			t.Errorf("%s group = %q, want work", inst.Title, inst.GroupPath)
+		}
+	}
+	if insts[1].GroupPath != "exp" {
+		t.Errorf("unmarked session moved to %q", insts[1].GroupPath)
+	}
+	if h.selectMode {
+		t.Error("a batch move should leave select mode")
+	}
+}
+
+func TestSelectMode_NoMarksFallsThrough(t *testing.T) {
+	h, insts := armHomeForSelect(t)
+	h.toggleSelectMode()
+	cursorTo(t, h, func(it session.Item) bool { return it.Session != nil && it.Session.ID == insts[1].ID })
+
+	h.handleMainKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if h.confirmDialog.GetConfirmType() != ConfirmDeleteSession || h.confirmDialog.GetTargetID() != insts[1].ID {
+		t.Error("with nothing marked, d should delete the session under the cursor")
+	}
+}
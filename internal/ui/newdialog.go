@@ -123,17 +123,18 @@ func sliceVisibleFrom(s string, n int) string {
 type focusTarget int
 
 const (
-	focusName      focusTarget = iota
-	focusPath                  // project path input (hidden when multi-repo enabled).
-	focusCommand               // tool/command picker.
-	focusModel                 // optional per-session model/version override.
-	focusWorktree              // worktree checkbox.
-	focusSandbox               // sandbox checkbox.
-	focusConductor             // conducting parent dropdown (conditional — only when conductors exist).
-	focusMultiRepo             // multi-repo toggle (transforms path into list when enabled).
-	focusInherited             // inherited Docker settings toggle (conditional).
-	focusBranch                // branch input (conditional — only when worktree enabled).
-	focusOptions               // tool-specific options panel (conditional).
+	focusName             focusTarget = iota
+	focusPath                         // project path input (hidden when multi-repo enabled).
+	focusCommand                      // tool/command picker.
+	focusModel                        // optional per-session model/version override.
+	focusWorktree                     // worktree checkbox.
+	focusSandbox                      // sandbox checkbox.
+	focusConductor                    // conducting parent dropdown (conditional — only when conductors exist).
+	focusMultiRepo                    // multi-repo toggle (transforms path into list when enabled).
+	focusInherited                    // inherited Docker settings toggle (conditional).
+	focusBranch                       // branch input (conditional — only when worktree enabled).
+	focusWorktreeLocation             // worktree location strategy (conditional — single-repo worktree only).
+	focusOptions                      // tool-specific options panel (conditional).
 )
 
 // New session dialog: outer box and textinput widths stay in sync so long
@@ -193,6 +194,11 @@ type NewDialog struct {
 	branchAutoSet   bool   // true if branch was auto-derived from session name.
 	branchPrefix    string // configured prefix for auto-generated branch names.
 	branchPicker    *BranchPickerDialog
+	// Worktree wizard: location choice and path preview (see newdialog_worktree.go).
+	worktreeLocationCursor int    // index into worktreeLocationChoices.
+	worktreePathID         string // {session-id} shared by preview and submit.
+	worktreePreviewKey     string
+	worktreePreview        worktreePreview
 	// Docker sandbox support.
 	sandboxEnabled    bool
 	inheritedExpanded bool             // whether the inherited settings section is expanded.
@@ -439,6 +445,7 @@ func (d *NewDialog) ShowInGroup(groupPath, groupName, defaultPath string, conduc
 	d.branchInput.SetValue("")
 	d.branchAutoSet = false
 	d.branchPrefix = "feature/" // default; overridden below if config provides one.
+	d.resetWorktreeWizard()
 	// Reset multi-repo fields (ephemeral, never pre-filled).
 	d.multiRepoEnabled = false
 	d.multiRepoPaths = nil
@@ -858,6 +865,7 @@ func (d *NewDialog) previewRecentSession(rs *statedb.RecentSessionRow) {
 	d.worktreeToggled = false
 	d.branchInput.SetValue("")
 	d.branchAutoSet = false
+	d.resetWorktreeWizard()
 
 	// Reset multi-repo (ephemeral, never pre-filled)
 	d.multiRepoEnabled = false
@@ -1327,6 +1335,9 @@ func (d *NewDialog) Validate() string {
 		if err := git.ValidateBranchName(branch); err != nil {
 			return err.Error()
 		}
+		if preview := d.refreshWorktreePreview(); preview.blocking {
+			return preview.err
+		}
 	}
 
 	return "" // Valid
@@ -1386,6 +1397,9 @@ func (d *NewDialog) rebuildFocusTargets() {
 	if d.worktreeEnabled {
 		targets = append(targets, focusBranch)
 	}
+	if d.worktreeWizardActive() {
+		targets = append(targets, focusWorktreeLocation)
+	}
 	// Multi-repo toggle below the fold (its path list renders here when enabled).
 	targets = append(targets, focusMultiRepo)
 	if d.toolOptions != nil {
@@ -1459,7 +1473,7 @@ func (d *NewDialog) updateFocus() {
 		}
 	case focusModel:
 		d.modelInput.Focus()
-	case focusWorktree, focusSandbox, focusConductor, focusInherited:
+	case focusWorktree, focusSandbox, focusConductor, focusInherited, focusWorktreeLocation:
 		// Checkbox/toggle rows and conductor dropdown — no text input to focus.
 	case focusBranch:
 		d.branchInput.Focus()
@@ -2053,6 +2067,10 @@ func (d *NewDialog) Update(msg tea.Msg) (*NewDialog, tea.Cmd) {
 				d.updateFocus()
 				return d, nil
 			}
+			if cur == focusWorktreeLocation {
+				d.cycleWorktreeLocation(-1)
+				return d, nil
+			}
 			if cur == focusOptions && d.toolOptions != nil {
 				return d, d.toolOptions.Update(msg)
 			}
@@ -2065,6 +2083,10 @@ func (d *NewDialog) Update(msg tea.Msg) (*NewDialog, tea.Cmd) {
 				d.updateFocus()
 				return d, nil
 			}
+			if cur == focusWorktreeLocation {
+				d.cycleWorktreeLocation(1)
+				return d, nil
+			}
 			if cur == focusOptions && d.toolOptions != nil {
 				return d, d.toolOptions.Update(msg)
 			}
@@ -2183,6 +2205,10 @@ func (d *NewDialog) Update(msg tea.Msg) (*NewDialog, tea.Cmd) {
 				d.inheritedExpanded = !d.inheritedExpanded
 				return d, nil
 			}
+			if cur == focusWorktreeLocation {
+				d.cycleWorktreeLocation(1)
+				return d, nil
+			}
 			if cur == focusOptions && d.toolOptions != nil {
 				return d, d.toolOptions.Update(msg)
 			}
@@ -2234,7 +2260,7 @@ func (d *NewDialog) Update(msg tea.Msg) (*NewDialog, tea.Cmd) {
 				d.filterPathSuggestions()
 			}
 		}
-	case focusWorktree, focusSandbox, focusConductor, focusInherited:
+	case focusWorktree, focusSandbox, focusConductor, focusInherited, focusWorktreeLocation:
 		// Checkbox/toggle rows and conductor dropdown — no text input to update.
 	case focusBranch:
 		oldBranch := d.branchInput.Value()
@@ -2707,6 +2733,11 @@ func (d *NewDialog) View() string {
 		}
 	}
 
+	// Worktree location + resulting path preview (single-repo worktrees).
+	if d.worktreeWizardActive() {
+		d.renderWorktreeWizard(&content, cur)
+	}
+
 	// Multi-repo toggle (below the fold, UX top-3 #3). Its path list renders
 	// here when enabled; in the common single-repo case it's just a checkbox.
 	content.WriteString("\n")
@@ -2777,6 +2808,12 @@ func (d *NewDialog) View() string {
 		}
 	} else if cur == focusConductor {
 		helpText = "↑↓ select parent │ Tab next │ Enter/^S create │ Esc cancel"
+	} else if cur == focusWorktreeLocation {
+		if d.worktreeTemplateActive() {
+			helpText = "Location set by [worktree] path_template │ Tab next │ Enter/^S create │ Esc cancel"
+		} else {
+			helpText = "←→/Space location │ Tab next │ Enter/^S create │ Esc cancel"
+		}
 	} else if cur == focusWorktree || cur == focusSandbox {
 		helpText = "Space toggle │ ↑↓ navigate │ Enter/^S create │ Esc cancel"
 	} else if cur == focusInherited {
@@ -0,0 +1,236 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/asheshgoplani/agent-deck/internal/git"
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/vcs"
+	"github.com/asheshgoplani/agent-deck/internal/vcsbackend"
+)
+
+// The new-session dialog doubles as the interactive form of `add -w`: with the
+// worktree box checked it lets the user pick a branch (typed or via ^F), pick
+// a location strategy, and shows where the worktree will land before anything
+// touches disk. The preview runs the same resolution as the submit path
+// (resolveWorktreeTargetAt) with the same {session-id}, so what you see is
+// what gets created.
+
+// worktreeLocationChoices returns the location strategies the dialog cycles
+// through. The configured default_location comes first (so an untouched row
+// keeps today's behavior), followed by the built-in strategies not already
+// listed. An empty default means "sibling", matching git.GenerateWorktreePath.
+func worktreeLocationChoices(configured string) []string {
+	configured = strings.TrimSpace(configured)
+	if configured == "" {
+		configured = "sibling"
+	}
+	choices := []string{configured}
+	for _, builtin := range []string{"subdirectory", "sibling"} {
+		if builtin != configured {
+			choices = append(choices, builtin)
+		}
+	}
+	return choices
+}
+
+// worktreeLocationLabel describes a location strategy for the dialog row.
+func worktreeLocationLabel(location string) string {
+	switch location {
+	case "subdirectory":
+		return "subdirectory (<repo>/.worktrees/<branch>)"
+	case "sibling":
+		return "sibling (<repo>-<branch>)"
+	default:
+		return "custom (" + location + "/<repo>/<branch>)"
+	}
+}
+
+// worktreePreview is the dialog's read-only summary of what submit will do.
+type worktreePreview struct {
+	path     string // worktree path that will be created or reused
+	note     string // what happens to the branch (new, existing, reused worktree)
+	err      string // why the current inputs can't produce a worktree
+	blocking bool   // err must be fixed before submit (vs. handled at submit)
+}
+
+// previewWorktreeTarget mirrors the `add -w` decisions for a single-repo
+// session: validate the branch, detect the VCS, reuse a worktree already
+// checked out on the branch, otherwise compute the new path and report
+// whether the branch will be created or checked out.
+func previewWorktreeTarget(path, branch, location, pathID string, explicit bool) worktreePreview {
+	if branch == "" {
+		return worktreePreview{err: "Branch name required for worktree"}
+	}
+	if err := git.ValidateBranchName(branch); err != nil {
+		return worktreePreview{err: err.Error()}
+	}
+	backend, err := vcsbackend.Detect(path)
+	if err != nil {
+		if explicit {
+			return worktreePreview{err: "Path is not a git, jujutsu or sapling repository"}
+		}
+		return worktreePreview{note: "not a repository; a normal session will be created"}
+	}
+	if existing, err := backend.GetWorktreeForBranch(branch); err == nil && existing != "" {
+		return worktreePreview{path: existing, note: "reuses the worktree already on this branch"}
+	}
+
+	wtPath, _, _, errMsg := resolveWorktreeTargetAt(path, branch, location, pathID, explicit)
+	if errMsg != "" {
+		return worktreePreview{err: errMsg}
+	}
+	if _, err := os.Stat(wtPath); err == nil {
+		return worktreePreview{path: wtPath, err: "Worktree path already exists: " + wtPath, blocking: true}
+	}
+	p := worktreePreview{path: wtPath, note: "creates new branch from " + currentBranchOr(backend, "HEAD")}
+	if backend.BranchExists(branch) {
+		p.note = "checks out existing branch"
+	}
+	return p
+}
+
+func currentBranchOr(backend vcs.Backend, fallback string) string {
+	if b, err := backend.GetCurrentBranch(); err == nil && b != "" {
+		return b
+	}
+	return fallback
+}
+
+// worktreeWizardActive reports whether the location row and path preview
+// apply. Multi-repo sessions lay out their worktrees under a shared root
+// instead, so the single-path preview would be misleading there.
+func (d *NewDialog) worktreeWizardActive() bool {
+	return d.worktreeEnabled && !d.multiRepoEnabled
+}
+
+// worktreeTemplateActive reports whether [worktree] path_template is set; it
+// overrides the location strategy, so the location row becomes read-only.
+func (d *NewDialog) worktreeTemplateActive() bool {
+	settings := session.GetWorktreeSettings()
+	return settings.Template() != ""
+}
+
+// currentWorktreeLocation returns the location strategy selected in the row.
+func (d *NewDialog) currentWorktreeLocation() string {
+	choices := worktreeLocationChoices(session.GetWorktreeSettings().DefaultLocation)
+	if d.worktreeLocationCursor < 0 || d.worktreeLocationCursor >= len(choices) {
+		d.worktreeLocationCursor = 0
+	}
+	return choices[d.worktreeLocationCursor]
+}
+
+// cycleWorktreeLocation moves the location row by delta, wrapping around.
+func (d *NewDialog) cycleWorktreeLocation(delta int) {
+	if d.worktreeTemplateActive() {
+		return
+	}
+	n := len(worktreeLocationChoices(session.GetWorktreeSettings().DefaultLocation))
+	d.worktreeLocationCursor = ((d.worktreeLocationCursor+delta)%n + n) % n
+}
+
+// worktreePathIDValue returns the {session-id} used for path templates,
+// generating it on first use so preview and submit agree.
+func (d *NewDialog) worktreePathIDValue() string {
+	if d.worktreePathID == "" {
+		d.worktreePathID = git.GeneratePathID()
+	}
+	return d.worktreePathID
+}
+
+// GetWorktreeTarget returns the location strategy and {session-id} the
+// preview was computed with; pass them to resolveWorktreeTargetAt on submit.
+func (d *NewDialog) GetWorktreeTarget() (location, pathID string) {
+	return d.currentWorktreeLocation(), d.worktreePathIDValue()
+}
+
+// refreshWorktreePreview recomputes the preview when its inputs changed. The
+// git lookups only run when path, branch, location or explicit-ness differ
+// from the last render.
+func (d *NewDialog) refreshWorktreePreview() worktreePreview {
+	if !d.worktreeWizardActive() {
+		return worktreePreview{}
+	}
+	path := d.sanitizePath(d.pathInput.Value())
+	branch := strings.TrimSpace(d.branchInput.Value())
+	location := d.currentWorktreeLocation()
+	key := strings.Join([]string{path, branch, location, d.worktreePathIDValue(), boolKey(d.worktreeToggled)}, "\x00")
+	if key != d.worktreePreviewKey {
+		d.worktreePreviewKey = key
+		d.worktreePreview = previewWorktreeTarget(path, branch, location, d.worktreePathIDValue(), d.worktreeToggled)
+	}
+	return d.worktreePreview
+}
+
+func boolKey(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// resetWorktreeWizard clears the per-opening location choice and path ID.
+func (d *NewDialog) resetWorktreeWizard() {
+	d.worktreeLocationCursor = 0
+	d.worktreePathID = ""
+	d.worktreePreviewKey = ""
+	d.worktreePreview = worktreePreview{}
+}
+
+// displayWorktreePath shortens $HOME to ~ for the preview line.
+func displayWorktreePath(path string) string {
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		if rel, err := filepath.Rel(home, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.Join("~", rel)
+		}
+	}
+	return path
+}
+
+// renderWorktreeWizard draws the location row and the path preview beneath
+// the branch input.
+func (d *NewDialog) renderWorktreeWizard(content *strings.Builder, cur focusTarget) {
+	labelStyle := lipgloss.NewStyle().Foreground(ColorText)
+	activeLabelStyle := lipgloss.NewStyle().Foreground(ColorCyan).Bold(true)
+	dimStyle := lipgloss.NewStyle().Foreground(ColorComment)
+	errStyle := lipgloss.NewStyle().Foreground(ColorRed)
+
+	if cur == focusWorktreeLocation {
+		content.WriteString(activeLabelStyle.Render("▶ Location:"))
+	} else {
+		content.WriteString(labelStyle.Render("  Location:"))
+	}
+	content.WriteString(" ")
+	if d.worktreeTemplateActive() {
+		content.WriteString(dimStyle.Render("path_template (config)"))
+	} else {
+		label := worktreeLocationLabel(d.currentWorktreeLocation())
+		if d.worktreeLocationCursor == 0 {
+			label += " · default"
+		}
+		if cur == focusWorktreeLocation {
+			content.WriteString(activeLabelStyle.Render("◀ " + label + " ▶"))
+		} else {
+			content.WriteString(labelStyle.Render(label))
+		}
+	}
+	content.WriteString("\n")
+
+	preview := d.refreshWorktreePreview()
+	if preview.path != "" {
+		content.WriteString(dimStyle.Render("  → " + displayWorktreePath(preview.path)))
+		content.WriteString("\n")
+	}
+	switch {
+	case preview.err != "":
+		content.WriteString(errStyle.Render("    " + preview.err))
+		content.WriteString("\n")
+	case preview.note != "":
+		content.WriteString(dimStyle.Render("    " + preview.note))
+		content.WriteString("\n")
+	}
+}
@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestWorktreeLocationChoices(t *testing.T) {
+	cases := map[string][]string{
+		"subdirectory": {"subdirectory", "sibling"},
+		"sibling":      {"sibling", "subdirectory"},
+		"":             {"sibling", "subdirectory"},
+		"~/worktrees":  {"~/worktrees", "subdirectory", "sibling"},
+	}
+	for configured, want := range cases {
+		if got := worktreeLocationChoices(configured); !reflect.DeepEqual(got, want) {
+			t.Errorf("worktreeLocationChoices(%q) = %v, want %v", configured, got, want)
+		}
+	}
+}
+
+func TestPreviewWorktreeTarget_NewExistingAndReused(t *testing.T) {
+	repo := t.TempDir()
+	makeGitRepo(t, repo)
+
+	p := previewWorktreeTarget(repo, "feature/new", "subdirectory", "abcd1234", true)
+	if p.err != "" || p.path != filepath.Join(repo, ".worktrees", "feature-new") {
+		t.Fatalf("new branch preview = %+v", p)
+	}
+	if !strings.HasPrefix(p.note, "creates new branch") {
+		t.Errorf("note = %q, want new-branch note", p.note)
+	}
+
+	if out, err := exec.Command("git", "-C", repo, "branch", "existing").CombinedOutput(); err != nil {
+		t.Fatalf("git branch: %v: %s", err, out)
+	}
+	p = previewWorktreeTarget(repo, "existing", "sibling", "abcd1234", true)
+	if p.path != repo+"-existing" || p.note != "checks out existing branch" {
+		t.Fatalf("existing branch preview = %+v", p)
+	}
+
+	wt := filepath.Join(t.TempDir(), "wt")
+	if out, err := exec.Command("git", "-C", repo, "worktree", "add", wt, "existing").CombinedOutput(); err != nil {
+		t.Fatalf("git worktree add: %v: %s", err, out)
+	}
+	p = previewWorktreeTarget(repo, "existing", "subdirectory", "abcd1234", true)
+	if !strings.HasPrefix(p.note, "reuses") || filepath.Base(p.path) != "wt" {
+		t.Fatalf("reused worktree preview = %+v", p)
+	}
+}
+
+func TestPreviewWorktreeTarget_Errors(t *testing.T) {
+	repo := t.TempDir()
+	makeGitRepo(t, repo)
+
+	if p := previewWorktreeTarget(repo, "bad..name", "subdirectory", "", true); p.err == "" {
+		t.Error("invalid branch name should surface an error")
+	}
+	if p := previewWorktreeTarget(t.TempDir(), "feature/x", "subdirectory", "", true); p.err == "" {
+		t.Error("explicit worktree on a non-repo should surface an error")
+	}
+	if p := previewWorktreeTarget(t.TempDir(), "feature/x", "subdirectory", "", false); p.err != "" || p.note == "" {
+		t.Errorf("default-enabled worktree on a non-repo should note the fallback, got %+v", p)
+	}
+
+	taken := filepath.Join(repo, ".worktrees", "feature-taken")
+	if err := os.MkdirAll(taken, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if p := previewWorktreeTarget(repo, "feature/taken", "subdirectory", "", true); !p.blocking {
+		t.Errorf("occupied worktree path should block submit, got %+v", p)
+	}
+}
+
+func TestNewDialog_WorktreeLocationRowCyclesAndFeedsSubmit(t *testing.T) {
+	repo := t.TempDir()
+	makeGitRepo(t, repo)
+
+	d := NewNewDialog()
+	d.Show()
+	d.pathInput.SetValue(repo)
+	d.nameInput.SetValue("wizard")
+	d.ToggleWorktree()
+
+	idx := d.indexOf(focusWorktreeLocation)
+	if idx < 0 {
+		t.Fatal("location row should be focusable when worktree is enabled")
+	}
+	d.focusIndex = idx
+	d.updateFocus()
+
+	first, _ := d.GetWorktreeTarget()
+	d, _ = d.Update(tea.KeyMsg{Type: tea.KeyRight})
+	second, pathID := d.GetWorktreeTarget()
+	if first == second {
+		t.Fatalf("right arrow should change location, stayed %q", first)
+	}
+	if _, again := d.GetWorktreeTarget(); again != pathID || pathID == "" {
+		t.Fatalf("path ID must be stable across calls: %q vs %q", pathID, again)
+	}
+
+	view := d.View()
+	if !strings.Contains(view, "Location:") || !strings.Contains(view, "→") {
+		t.Errorf("view should show the location row and path preview:\n%s", view)
+	}
+
+	// Submit resolves to exactly the previewed path.
+	want := d.refreshWorktreePreview().path
+	got, _, _, errMsg := resolveWorktreeTargetAt(repo, "feature/wizard", second, pathID, true)
+	if errMsg != "" || got != want {
+		t.Fatalf("submit path = %q (%s), preview = %q", got, errMsg, want)
+	}
+
+	d.ToggleWorktree()
+	if d.indexOf(focusWorktreeLocation) >= 0 {
+		t.Error("location row should disappear when worktree is disabled")
+	}
+}
@@ -0,0 +1,240 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/asheshgoplani/agent-deck/internal/clipboard"
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// outputHistoryPreviewLines is how many lines of the highlighted response the
+// dialog previews under the list.
+const outputHistoryPreviewLines = 6
+
+// OutputHistoryDialog lists a session's recent distinct agent responses,
+// newest first, so one from a few turns back can be copied without
+// scrolling the pane. Shaped like CodeBlockDialog: navigation is handled
+// here, enter/esc by Home.
+type OutputHistoryDialog struct {
+	visible       bool
+	width, height int
+	entries       []session.OutputEntry // newest first
+	cursor        int
+	sessionTitle  string
+}
+
+// NewOutputHistoryDialog creates the dialog (hidden).
+func NewOutputHistoryDialog() *OutputHistoryDialog {
+	return &OutputHistoryDialog{}
+}
+
+// Show opens the dialog on entries as stored (oldest first). Returns false
+// and stays hidden when there is nothing to list.
+func (d *OutputHistoryDialog) Show(sessionTitle string, entries []session.OutputEntry) bool {
+	if len(entries) == 0 {
+		return false
+	}
+	d.entries = make([]session.OutputEntry, len(entries))
+	for i, e := range entries {
+		d.entries[len(entries)-1-i] = e
+	}
+	d.visible = true
+	d.sessionTitle = sessionTitle
+	d.cursor = 0
+	return true
+}
+
+// Hide closes the dialog and clears its state.
+func (d *OutputHistoryDialog) Hide() {
+	d.visible = false
+	d.entries = nil
+	d.cursor = 0
+	d.sessionTitle = ""
+}
+
+// IsVisible reports whether the dialog is shown. Nil-safe like
+// CodeBlockDialog.IsVisible.
+func (d *OutputHistoryDialog) IsVisible() bool { return d != nil && d.visible }
+
+// SetSize updates the dialog dimensions for centering.
+func (d *OutputHistoryDialog) SetSize(w, h int) {
+	if d == nil {
+		return
+	}
+	d.width = w
+	d.height = h
+}
+
+// GetSelected returns the entry at the cursor, or nil when none.
+func (d *OutputHistoryDialog) GetSelected() *session.OutputEntry {
+	if d.cursor < 0 || d.cursor >= len(d.entries) {
+		return nil
+	}
+	return &d.entries[d.cursor]
+}
+
+// Update handles navigation keys.
+func (d *OutputHistoryDialog) Update(msg tea.KeyMsg) (*OutputHistoryDialog, tea.Cmd) {
+	if !d.IsVisible() || len(d.entries) == 0 {
+		return d, nil
+	}
+	switch msg.String() {
+	case "j", "down":
+		d.cursor = (d.cursor + 1) % len(d.entries)
+	case "k", "up":
+		d.cursor = (d.cursor - 1 + len(d.entries)) % len(d.entries)
+	case "g", "home":
+		d.cursor = 0
+	case "G", "end":
+		d.cursor = len(d.entries) - 1
+	}
+	return d, nil
+}
+
+// outputHistoryDialogChrome counts the rows around the entry list: border
+// and padding (4), title, session line, blank, two overflow markers, blank,
+// preview rows, blank, footer.
+const outputHistoryDialogChrome = 10 + outputHistoryPreviewLines
+
+// visibleRows returns how many entry rows fit on screen (see
+// CodeBlockDialog.visibleRows).
+func (d *OutputHistoryDialog) visibleRows() int {
+	const def = 10
+	if d.height <= 0 {
+		return def
+	}
+	rows := d.height - outputHistoryDialogChrome
+	if rows < 1 {
+		return 1
+	}
+	if rows > def {
+		return def
+	}
+	return rows
+}
+
+// View renders the dialog.
+func (d *OutputHistoryDialog) View() string {
+	if !d.IsVisible() {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorAccent)
+	sourceStyle := lipgloss.NewStyle().Foreground(ColorTextDim)
+	selectedStyle := lipgloss.NewStyle().Foreground(ColorAccent).Bold(true)
+	normalStyle := lipgloss.NewStyle().Foreground(ColorText)
+	dimStyle := lipgloss.NewStyle().Foreground(ColorComment)
+	footerStyle := lipgloss.NewStyle().Foreground(ColorComment).Italic(true)
+
+	dialogWidth := fitDialogWidth(76, 40, d.width)
+	innerWidth := dialogWidth - 4
+	if innerWidth < 1 {
+		innerWidth = 1
+	}
+	fit := func(s string) string { return cellTruncate(s, innerWidth, "…") }
+
+	var lines []string
+	lines = append(lines, fit(titleStyle.Render(fmt.Sprintf("Output History (%d)", len(d.entries)))))
+	lines = append(lines, fit(sourceStyle.Render(fmt.Sprintf("Session: %q", d.sessionTitle))))
+	lines = append(lines, "")
+
+	start, end := windowBounds(d.cursor, len(d.entries), d.visibleRows())
+	if start > 0 {
+		lines = append(lines, fit(dimStyle.Render(fmt.Sprintf("  ↑ %d more", start))))
+	}
+	for i := start; i < end; i++ {
+		e := d.entries[i]
+		first, _, _ := strings.Cut(e.Content, "\n")
+		meta := dimStyle.Render(fmt.Sprintf("%s · %d line(s)", formatRelativeTime(e.Timestamp), strings.Count(e.Content, "\n")+1))
+		label := fmt.Sprintf("%d. %s  %s", i+1, strings.TrimSpace(first), meta)
+		if i == d.cursor {
+			lines = append(lines, fit("> "+selectedStyle.Render(label)))
+		} else {
+			lines = append(lines, fit("  "+normalStyle.Render(label)))
+		}
+	}
+	if end < len(d.entries) {
+		lines = append(lines, fit(dimStyle.Render(fmt.Sprintf("  ↓ %d more", len(d.entries)-end))))
+	}
+
+	lines = append(lines, "")
+	if sel := d.GetSelected(); sel != nil {
+		preview := strings.Split(sel.Content, "\n")
+		if len(preview) > outputHistoryPreviewLines {
+			preview = preview[:outputHistoryPreviewLines]
+		}
+		for _, l := range preview {
+			lines = append(lines, fit(sourceStyle.Render("│ "+l)))
+		}
+	}
+
+	lines = append(lines, "")
+	footer := "Enter copy | Esc close | j/k navigate"
+	if cellWidth(footer) > innerWidth {
+		footer = "Enter copy | Esc | j/k"
+	}
+	lines = append(lines, fit(footerStyle.Render(footer)))
+
+	box := DialogBoxStyle.Width(dialogWidth).Render(strings.Join(lines, "\n"))
+	return centerInScreen(box, d.width, d.height)
+}
+
+// openOutputHistory shows the selected session's output history. The list
+// is fed by the status worker whenever a turn ends; a session that has not
+// finished a turn since the TUI started has nothing to show yet.
+func (h *Home) openOutputHistory() {
+	if h.cursor >= len(h.flatItems) {
+		return
+	}
+	item := h.flatItems[h.cursor]
+	if item.Type != session.ItemTypeSession || item.Session == nil {
+		return
+	}
+	inst := item.Session
+	entries, err := session.ReadOutputHistory(inst.ID)
+	if err != nil {
+		h.setError(fmt.Errorf("output history: %w", err))
+		return
+	}
+	if h.outputHistoryDialog == nil {
+		h.outputHistoryDialog = NewOutputHistoryDialog()
+	}
+	h.outputHistoryDialog.SetSize(h.width, h.height)
+	if !h.outputHistoryDialog.Show(inst.Title, entries) {
+		h.setError(fmt.Errorf("no recorded outputs for %s yet", inst.Title))
+	}
+}
+
+// handleOutputHistoryDialogKey copies the highlighted entry on enter and
+// routes everything else to the dialog.
+func (h *Home) handleOutputHistoryDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		selected := h.outputHistoryDialog.GetSelected()
+		title := h.outputHistoryDialog.sessionTitle
+		h.outputHistoryDialog.Hide()
+		if selected == nil {
+			return h, nil
+		}
+		content := selected.Content
+		return h, func() tea.Msg {
+			termInfo := tmux.GetTerminalInfo()
+			result, err := clipboard.Copy(content, termInfo.SupportsOSC52)
+			if err != nil {
+				return copyResultMsg{err: fmt.Errorf("clipboard: %w", err)}
+			}
+			return copyResultMsg{sessionTitle: title, lineCount: result.LineCount}
+		}
+	case "esc", "q":
+		h.outputHistoryDialog.Hide()
+		return h, nil
+	default:
+		h.outputHistoryDialog.Update(msg)
+		return h, nil
+	}
+}
@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+func TestOutputHistoryDialog_NewestFirst(t *testing.T) {
+	d := NewOutputHistoryDialog()
+	d.SetSize(100, 40)
+	if d.Show("api", nil) || d.IsVisible() {
+		t.Fatal("an empty history must not open the dialog")
+	}
+
+	now := time.Now()
+	d.Show("api", []session.OutputEntry{
+		{Timestamp: now.Add(-2 * time.Minute), Content: "older answer\nwith detail"},
+		{Timestamp: now, Content: "latest answer"},
+	})
+	if sel := d.GetSelected(); sel == nil || sel.Content != "latest answer" {
+		t.Fatalf("cursor starts on %+v, want the newest entry", sel)
+	}
+	d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	if sel := d.GetSelected(); sel == nil || sel.Content != "older answer\nwith detail" {
+		t.Fatalf("after j selected %+v", sel)
+	}
+
+	view := d.View()
+	for _, want := range []string{"Output History (2)", "1. latest answer", "2. older answer", "│ with detail"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Alt+v hides the preview pane: the list takes the full width and no preview
+// capture is scheduled. Pressing it again restores the pane and fetches the
+// selected session's output immediately.
+func TestPreviewPaneToggle(t *testing.T) {
+	h := armHomeOneSessionForPreview(t)
+	h.width = 120
+	alt := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}, Alt: true}
+
+	_, cmd := h.handleMainKey(alt)
+	if !h.previewHidden {
+		t.Fatal("alt+v should hide the preview pane")
+	}
+	if cmd != nil {
+		t.Error("hiding the preview pane should not schedule a fetch")
+	}
+	if got := h.getLayoutMode(); got != LayoutModeSingle {
+		t.Errorf("layout with hidden preview = %q, want %q", got, LayoutModeSingle)
+	}
+	if cmd := h.fetchSelectedPreview(); cmd != nil {
+		t.Error("no preview fetch while the pane is hidden")
+	}
+
+	_, cmd = h.handleMainKey(alt)
+	if h.previewHidden {
+		t.Fatal("second alt+v should show the preview pane")
+	}
+	if got := h.getLayoutMode(); got != LayoutModeDual {
+		t.Errorf("layout with preview shown = %q, want %q", got, LayoutModeDual)
+	}
+	if cmd == nil {
+		t.Error("showing the preview pane should fetch the selected session's output")
+	}
+}
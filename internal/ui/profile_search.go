@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// otherProfileSessionsMsg carries the sessions of the other profiles, loaded
+// in the background when the local search opens.
+type otherProfileSessionsMsg struct {
+	sessions []session.ProfileSession
+}
+
+// openLocalSearch shows the local search and loads the other profiles'
+// sessions so they can be matched too.
+func (h *Home) openLocalSearch() tea.Cmd {
+	h.search.Show()
+	current := h.profile
+	if current == "" {
+		current = session.DefaultProfile
+	}
+	return func() tea.Msg {
+		sessions, err := session.LoadProfileSessions(current)
+		if err != nil {
+			uiLog.Debug("other_profile_sessions_load_failed", slog.String("error", err.Error()))
+		}
+		return otherProfileSessionsMsg{sessions: sessions}
+	}
+}
+
+// attachOtherProfileSession attaches to a session of another profile found
+// by the search. Only its tmux session is used: the session stays managed by
+// its own profile, so one that is not running is reported with the command
+// that starts it.
+func (h *Home) attachOtherProfileSession(hit *session.SessionSearchHit) tea.Cmd {
+	if hit.TmuxSession == "" || !tmux.HasSessionOnSocket(hit.TmuxSocketName, hit.TmuxSession) {
+		h.setError(fmt.Errorf("%q (profile %s) is not running; start it with: agent-deck -p %s session start %s",
+			hit.Title, hit.Profile, hit.Profile, hit.ID))
+		return nil
+	}
+	tmuxSess := tmux.ReconnectSessionLazy(hit.TmuxSession, hit.Title, hit.ProjectPath, hit.Command, string(hit.Status))
+	tmuxSess.SocketName = hit.TmuxSocketName
+	h.isAttaching.Store(true)
+	return tea.Exec(attachCmd{session: tmuxSess, opts: h.attachOptions(tmuxSess)}, func(err error) tea.Msg {
+		h.isAttaching.Store(false)
+		return statusUpdateMsg{}
+	})
+}
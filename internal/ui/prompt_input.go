@@ -50,6 +50,14 @@ func (d *PromptInputDialog) Show(instanceID, title string) {
 	d.input.Focus()
 }
 
+// ShowWithText opens the input prefilled with text, for editing before it is
+// sent (the prompt picker uses it when a saved prompt has unfilled variables).
+func (d *PromptInputDialog) ShowWithText(instanceID, title, text string) {
+	d.Show(instanceID, title)
+	d.input.SetValue(text)
+	d.input.CursorEnd()
+}
+
 // Hide closes the input and blurs it.
 func (d *PromptInputDialog) Hide() {
 	d.visible = false
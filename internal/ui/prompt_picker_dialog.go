@@ -0,0 +1,305 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// promptPickerEntry is one saved prompt in the picker.
+type promptPickerEntry struct {
+	name   string
+	prompt session.SavedPrompt
+}
+
+// PromptPickerDialog lists the [prompts] library for sending one to the
+// selected session. Typing filters by name and description; with an empty
+// filter 1-9 pick directly, so a prompt is two keystrokes away. Enter/esc
+// are handled by Home, like OutputHistoryDialog.
+type PromptPickerDialog struct {
+	visible       bool
+	width, height int
+	entries       []promptPickerEntry // all prompts, sorted by name
+	filtered      []promptPickerEntry
+	filter        string
+	cursor        int
+	instanceID    string
+	sessionTitle  string
+}
+
+// NewPromptPickerDialog creates the dialog (hidden).
+func NewPromptPickerDialog() *PromptPickerDialog {
+	return &PromptPickerDialog{}
+}
+
+// Show opens the picker for the given session. Returns false and stays
+// hidden when the library is empty.
+func (d *PromptPickerDialog) Show(instanceID, sessionTitle string, prompts map[string]session.SavedPrompt) bool {
+	if len(prompts) == 0 {
+		return false
+	}
+	d.entries = d.entries[:0]
+	for _, name := range session.SavedPromptNames(prompts) {
+		d.entries = append(d.entries, promptPickerEntry{name: name, prompt: prompts[name]})
+	}
+	d.visible = true
+	d.instanceID = instanceID
+	d.sessionTitle = sessionTitle
+	d.setFilter("")
+	return true
+}
+
+// Hide closes the dialog and clears its state.
+func (d *PromptPickerDialog) Hide() {
+	d.visible = false
+	d.entries = nil
+	d.filtered = nil
+	d.filter = ""
+	d.cursor = 0
+	d.instanceID = ""
+	d.sessionTitle = ""
+}
+
+// IsVisible reports whether the dialog is shown. Nil-safe like
+// OutputHistoryDialog.IsVisible.
+func (d *PromptPickerDialog) IsVisible() bool { return d != nil && d.visible }
+
+// SetSize updates the dialog dimensions for centering.
+func (d *PromptPickerDialog) SetSize(w, h int) {
+	if d == nil {
+		return
+	}
+	d.width = w
+	d.height = h
+}
+
+// InstanceID returns the session the picker was opened for.
+func (d *PromptPickerDialog) InstanceID() string { return d.instanceID }
+
+// GetSelected returns the name and prompt at the cursor; ok is false when
+// the filter matches nothing.
+func (d *PromptPickerDialog) GetSelected() (name string, prompt session.SavedPrompt, ok bool) {
+	if d.cursor < 0 || d.cursor >= len(d.filtered) {
+		return "", session.SavedPrompt{}, false
+	}
+	e := d.filtered[d.cursor]
+	return e.name, e.prompt, true
+}
+
+// setFilter narrows the list to entries whose name or description contains
+// filter (case-insensitive) and resets the cursor.
+func (d *PromptPickerDialog) setFilter(filter string) {
+	d.filter = filter
+	d.cursor = 0
+	d.filtered = d.filtered[:0]
+	q := strings.ToLower(filter)
+	for _, e := range d.entries {
+		if q == "" || strings.Contains(strings.ToLower(e.name), q) ||
+			strings.Contains(strings.ToLower(e.prompt.Description), q) {
+			d.filtered = append(d.filtered, e)
+		}
+	}
+}
+
+// Update handles navigation and filter keys. It returns true when the key
+// picked an entry by number (1-9 with an empty filter); the cursor is then on
+// that entry.
+func (d *PromptPickerDialog) Update(msg tea.KeyMsg) bool {
+	if !d.IsVisible() {
+		return false
+	}
+	switch msg.String() {
+	case "down", "ctrl+n":
+		if len(d.filtered) > 0 {
+			d.cursor = (d.cursor + 1) % len(d.filtered)
+		}
+		return false
+	case "up", "ctrl+p":
+		if len(d.filtered) > 0 {
+			d.cursor = (d.cursor - 1 + len(d.filtered)) % len(d.filtered)
+		}
+		return false
+	case "backspace":
+		if d.filter != "" {
+			r := []rune(d.filter)
+			d.setFilter(string(r[:len(r)-1]))
+		}
+		return false
+	}
+	if msg.Type != tea.KeyRunes || msg.Alt {
+		return false
+	}
+	if d.filter == "" && len(msg.Runes) == 1 && msg.Runes[0] >= '1' && msg.Runes[0] <= '9' {
+		if n := int(msg.Runes[0] - '1'); n < len(d.filtered) {
+			d.cursor = n
+			return true
+		}
+		return false
+	}
+	for _, r := range msg.Runes {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	d.setFilter(d.filter + string(msg.Runes))
+	return false
+}
+
+// promptPickerDialogChrome counts the rows around the entry list: border and
+// padding (4), title, session line, filter line, blank, two overflow markers,
+// blank, preview (up to 3), blank, footer.
+const promptPickerDialogChrome = 16
+
+// visibleRows returns how many entry rows fit on screen (see
+// OutputHistoryDialog.visibleRows).
+func (d *PromptPickerDialog) visibleRows() int {
+	const def = 10
+	if d.height <= 0 {
+		return def
+	}
+	rows := d.height - promptPickerDialogChrome
+	if rows < 1 {
+		return 1
+	}
+	if rows > def {
+		return def
+	}
+	return rows
+}
+
+// View renders the dialog.
+func (d *PromptPickerDialog) View() string {
+	if !d.IsVisible() {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorAccent)
+	sourceStyle := lipgloss.NewStyle().Foreground(ColorTextDim)
+	selectedStyle := lipgloss.NewStyle().Foreground(ColorAccent).Bold(true)
+	normalStyle := lipgloss.NewStyle().Foreground(ColorText)
+	dimStyle := lipgloss.NewStyle().Foreground(ColorComment)
+	footerStyle := lipgloss.NewStyle().Foreground(ColorComment).Italic(true)
+
+	dialogWidth := fitDialogWidth(76, 40, d.width)
+	innerWidth := dialogWidth - 4
+	if innerWidth < 1 {
+		innerWidth = 1
+	}
+	fit := func(s string) string { return cellTruncate(s, innerWidth, "…") }
+
+	var lines []string
+	lines = append(lines, fit(titleStyle.Render(fmt.Sprintf("Prompt Library (%d)", len(d.entries)))))
+	lines = append(lines, fit(sourceStyle.Render(fmt.Sprintf("Send to: %q", d.sessionTitle))))
+	if d.filter != "" {
+		lines = append(lines, fit(normalStyle.Render("Filter: "+d.filter)))
+	} else {
+		lines = append(lines, fit(dimStyle.Render("Type to filter, 1-9 to send")))
+	}
+	lines = append(lines, "")
+
+	if len(d.filtered) == 0 {
+		lines = append(lines, fit(dimStyle.Render("  No prompts match")))
+	}
+	start, end := windowBounds(d.cursor, len(d.filtered), d.visibleRows())
+	if start > 0 {
+		lines = append(lines, fit(dimStyle.Render(fmt.Sprintf("  ↑ %d more", start))))
+	}
+	for i := start; i < end; i++ {
+		e := d.filtered[i]
+		label := fmt.Sprintf("%d. %s", i+1, e.name)
+		if e.prompt.Description != "" {
+			label += "  " + dimStyle.Render(e.prompt.Description)
+		}
+		if i == d.cursor {
+			lines = append(lines, fit("> "+selectedStyle.Render(label)))
+		} else {
+			lines = append(lines, fit("  "+normalStyle.Render(label)))
+		}
+	}
+	if end < len(d.filtered) {
+		lines = append(lines, fit(dimStyle.Render(fmt.Sprintf("  ↓ %d more", len(d.filtered)-end))))
+	}
+
+	if _, sel, ok := d.GetSelected(); ok {
+		lines = append(lines, "")
+		preview := strings.Split(strings.TrimSpace(sel.Text), "\n")
+		if len(preview) > 3 {
+			preview = preview[:3]
+		}
+		for _, l := range preview {
+			lines = append(lines, fit(sourceStyle.Render("│ "+l)))
+		}
+	}
+
+	lines = append(lines, "")
+	footer := "Enter send | Esc close | ↑/↓ navigate"
+	if cellWidth(footer) > innerWidth {
+		footer = "Enter send | Esc | ↑/↓"
+	}
+	lines = append(lines, fit(footerStyle.Render(footer)))
+
+	box := DialogBoxStyle.Width(dialogWidth).Render(strings.Join(lines, "\n"))
+	return centerInScreen(box, d.width, d.height)
+}
+
+// openPromptLibrary shows the prompt picker for inst. Gated like
+// openPromptInput: the prompt goes into the live pane, so the session must be
+// running.
+func (h *Home) openPromptLibrary(inst *session.Instance) {
+	if inst == nil {
+		return
+	}
+	if ts := inst.GetTmuxSession(); ts == nil || ts.Name == "" {
+		h.setError(fmt.Errorf("session %q is not running; start it before prompting", inst.Title))
+		return
+	}
+	if h.promptPickerDialog == nil {
+		h.promptPickerDialog = NewPromptPickerDialog()
+	}
+	h.promptPickerDialog.SetSize(h.width, h.height)
+	if !h.promptPickerDialog.Show(inst.ID, inst.Title, session.GetSavedPrompts()) {
+		h.setError(fmt.Errorf("no saved prompts yet; add one with 'agent-deck prompt save <name> <text>'"))
+	}
+}
+
+// handlePromptPickerDialogKey sends the picked prompt on enter (or a number)
+// and routes everything else to the dialog. A prompt whose variables can't
+// all be filled for this session opens in the prompt input for editing
+// instead of being sent with holes in it.
+func (h *Home) handlePromptPickerDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		h.promptPickerDialog.Hide()
+		return h, nil
+	case "enter":
+	default:
+		if !h.promptPickerDialog.Update(msg) {
+			return h, nil
+		}
+	}
+
+	_, picked, ok := h.promptPickerDialog.GetSelected()
+	instanceID := h.promptPickerDialog.InstanceID()
+	h.promptPickerDialog.Hide()
+	if !ok {
+		return h, nil
+	}
+	inst := h.getInstanceByID(instanceID)
+	if inst == nil {
+		h.setError(fmt.Errorf("prompt target session no longer exists"))
+		return h, nil
+	}
+	text, err := session.RenderPrompt(picked.Text, session.PromptVarsFor(inst, picked.Text))
+	if err != nil {
+		h.promptInputDialog.ShowWithText(inst.ID, inst.Title, text)
+		return h, nil
+	}
+	return h, func() tea.Msg {
+		return promptSubmitMsg{instanceID: instanceID, text: text}
+	}
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+func TestPromptPickerDialog_FilterAndNumberPick(t *testing.T) {
+	d := NewPromptPickerDialog()
+	d.SetSize(100, 40)
+	if d.Show("id1", "api", nil) || d.IsVisible() {
+		t.Fatal("an empty library must not open the picker")
+	}
+
+	d.Show("id1", "api", map[string]session.SavedPrompt{
+		"tests":  {Text: "Run the tests and fix failures", Description: "test loop"},
+		"review": {Text: "Review the diff on {branch}"},
+	})
+	if name, _, _ := d.GetSelected(); name != "review" {
+		t.Fatalf("cursor starts on %q, want the first name in order", name)
+	}
+	if !d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")}) {
+		t.Fatal("2 with an empty filter should pick the second entry")
+	}
+	if name, _, _ := d.GetSelected(); name != "tests" {
+		t.Fatalf("2 selected %q", name)
+	}
+
+	for _, r := range "LOOP" {
+		d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if name, _, ok := d.GetSelected(); !ok || name != "tests" || len(d.filtered) != 1 {
+		t.Fatalf("filter on description: %q %v (%d shown)", name, ok, len(d.filtered))
+	}
+	view := d.View()
+	for _, want := range []string{"Prompt Library (2)", `Send to: "api"`, "Filter: LOOP", "1. tests", "│ Run the tests"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("z")})
+	if _, _, ok := d.GetSelected(); ok {
+		t.Fatal("no entry should match LOOPz")
+	}
+	for range 5 {
+		d.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	if d.filter != "" || len(d.filtered) != 2 {
+		t.Fatalf("backspace should clear the filter, got %q (%d shown)", d.filter, len(d.filtered))
+	}
+}
@@ -10,6 +10,8 @@
 package ui
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -73,3 +75,37 @@ func TestRemoteRows_AlignWithRootGroups_NoExtraIndent(t *testing.T) {
 		t.Errorf("selected remote host header indent = %d cols, want %d (selection must not shift the row)\n  line: %q", w, leftGutterWidth, headerSel)
 	}
 }
+
+func TestRemoteHostHeader_ShowsConfiguredHost(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	session.ClearUserConfigCache()
+	t.Cleanup(session.ClearUserConfigCache)
+
+	cfgPath, err := session.GetUserConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(cfgPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cfgPath, []byte("[remotes.dev]\nhost = \"me@dev-box\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewHome()
+	h.width, h.height = 120, 40
+	render := func(name string) string {
+		var b strings.Builder
+		h.renderRemoteGroupItem(&b, session.Item{Type: session.ItemTypeRemoteGroup, RemoteName: name, Path: "remotes/" + name}, false)
+		return stripANSILatency(b.String())
+	}
+
+	if got := render("dev"); !strings.Contains(got, "remotes/dev (0) me@dev-box") {
+		t.Fatalf("header should show the SSH host after the count; got %q", got)
+	}
+	if got := render("gone"); strings.Contains(got, "@") {
+		t.Fatalf("unconfigured remote should render without a host; got %q", got)
+	}
+}
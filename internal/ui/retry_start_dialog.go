@@ -0,0 +1,233 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// retryStartMsg is emitted when the operator confirms a retry of a session
+// whose start failed. command is the (possibly edited) session command; Home
+// persists it when it changed and restarts the session.
+type retryStartMsg struct {
+	instanceID string
+	command    string
+}
+
+// RetryStartDialog is a one-line command editor shown when R is pressed on a
+// session whose start failed (dead pane, instant exit, fall-back to shell).
+// It is prefilled with the session's command so a typo or wrong binary can be
+// fixed and retried without going through the edit dialog. Same shape as
+// PromptInputDialog: anchored at the bottom of the list, Enter submits, Esc
+// cancels.
+type RetryStartDialog struct {
+	input      textinput.Model
+	visible    bool
+	width      int
+	height     int
+	instanceID string
+	title      string
+}
+
+// NewRetryStartDialog creates the retry input (hidden).
+func NewRetryStartDialog() *RetryStartDialog {
+	ti := textinput.New()
+	ti.Placeholder = "command to start the session with"
+	ti.CharLimit = 2000
+	ti.Width = 60
+	return &RetryStartDialog{input: ti}
+}
+
+// Show opens the input for the given session, prefilled with command.
+func (d *RetryStartDialog) Show(instanceID, title, command string) {
+	d.visible = true
+	d.instanceID = instanceID
+	d.title = title
+	d.input.SetValue(command)
+	d.input.CursorEnd()
+	d.input.Focus()
+}
+
+// Hide closes the input and blurs it.
+func (d *RetryStartDialog) Hide() {
+	d.visible = false
+	d.input.Blur()
+	d.instanceID = ""
+	d.title = ""
+}
+
+// IsVisible reports whether the input is open. Nil-safe like
+// PromptInputDialog.IsVisible.
+func (d *RetryStartDialog) IsVisible() bool { return d != nil && d.visible }
+
+// SetSize updates the layout dimensions and the input width.
+func (d *RetryStartDialog) SetSize(width, height int) {
+	if d == nil {
+		return
+	}
+	d.width = width
+	d.height = height
+	w := width - 20
+	if w < 20 {
+		w = 20
+	}
+	if w > 120 {
+		w = 120
+	}
+	d.input.Width = w
+}
+
+// Update handles a key while the input is visible. Enter with a non-empty
+// command returns a retryStartMsg; Esc cancels.
+func (d *RetryStartDialog) Update(msg tea.KeyMsg) (*RetryStartDialog, tea.Cmd) {
+	if d == nil || !d.visible {
+		return d, nil
+	}
+	switch msg.String() {
+	case "esc":
+		d.Hide()
+		return d, nil
+	case "enter":
+		command := strings.TrimSpace(d.input.Value())
+		if command == "" {
+			return d, nil
+		}
+		instanceID := d.instanceID
+		d.Hide()
+		return d, func() tea.Msg {
+			return retryStartMsg{instanceID: instanceID, command: command}
+		}
+	default:
+		var cmd tea.Cmd
+		d.input, cmd = d.input.Update(msg)
+		return d, cmd
+	}
+}
+
+// View overlays the retry bar at the bottom of the rendered list body.
+func (d *RetryStartDialog) View(listBody string) string {
+	if d == nil || !d.visible {
+		return listBody
+	}
+
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorYellow)
+	dimStyle := lipgloss.NewStyle().Foreground(ColorComment)
+
+	barWidth := d.width - 2
+	if barWidth < 1 {
+		barWidth = d.width
+	}
+	bar := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ColorYellow).
+		Padding(0, 1).
+		Width(barWidth).
+		Render(labelStyle.Render("Retry start → "+d.title) + "\n" + d.input.View() + "\n" +
+			dimStyle.Render("Enter Retry   Esc Cancel   (edits are saved as the session command)"))
+
+	barHeight := lipgloss.Height(bar)
+	bodyLines := strings.Split(listBody, "\n")
+	maxBody := d.height - barHeight
+	if maxBody < 0 {
+		maxBody = 0
+	}
+	if len(bodyLines) > maxBody {
+		bodyLines = bodyLines[:maxBody]
+	}
+	return strings.Join(bodyLines, "\n") + "\n" + bar
+}
+
+// startFailureOutputLines caps the dying output shown in the preview.
+const startFailureOutputLines = 8
+
+// startFailure returns inst's recorded start failure while the session is in
+// the error state, or nil. Healthy sessions skip the sidecar read.
+func startFailure(inst *session.Instance) *session.SpawnFailureRecord {
+	if inst == nil || inst.GetStatusThreadSafe() != session.StatusError {
+		return nil
+	}
+	return inst.SpawnFailure()
+}
+
+// renderStartFailure writes the start-failure summary for the preview's error
+// section: what happened and the tail of the captured output.
+func renderStartFailure(b *strings.Builder, rec *session.SpawnFailureRecord, width int) {
+	warnStyle := lipgloss.NewStyle().Foreground(ColorYellow)
+	dimStyle := lipgloss.NewStyle().Foreground(ColorText)
+	outStyle := lipgloss.NewStyle().Foreground(ColorComment)
+	lineWidth := width - 6
+	if lineWidth < 10 {
+		lineWidth = 10
+	}
+
+	b.WriteString(warnStyle.Render("✕ Session failed to start"))
+	b.WriteString("\n")
+	switch rec.Reason {
+	case "tmux_start_failed":
+		b.WriteString(dimStyle.Render("The terminal session could not be created."))
+	case "spawn_pane_dead":
+		b.WriteString(dimStyle.Render(fmt.Sprintf("The command exited after %dms.", rec.ElapsedMs)))
+	case "spawn_returned_to_shell":
+		b.WriteString(dimStyle.Render(fmt.Sprintf("The command exited after %dms and fell back to a shell.", rec.ElapsedMs)))
+	default:
+		b.WriteString(dimStyle.Render(fmt.Sprintf("The command exited almost immediately (after %dms).", rec.ElapsedMs)))
+	}
+	b.WriteString("\n\n")
+
+	output := strings.TrimRight(rec.DyingOutput, "\n")
+	if strings.TrimSpace(output) == "" {
+		b.WriteString(dimStyle.Render("(no output was captured before the process exited)"))
+		b.WriteString("\n\n")
+		return
+	}
+	b.WriteString(dimStyle.Render("Last output:"))
+	b.WriteString("\n")
+	lines := strings.Split(output, "\n")
+	if len(lines) > startFailureOutputLines {
+		lines = lines[len(lines)-startFailureOutputLines:]
+	}
+	for _, line := range lines {
+		b.WriteString("  ")
+		b.WriteString(outStyle.Render(truncateVisible(tmux.StripANSI(line), lineWidth)))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+}
+
+// retryFailedStart saves an edited command (when it changed) and restarts
+// the session, mirroring the R path.
+func (h *Home) retryFailedStart(instanceID, command string) tea.Cmd {
+	h.instancesMu.RLock()
+	inst := h.instanceByID[instanceID]
+	h.instancesMu.RUnlock()
+	if inst == nil {
+		h.setError(fmt.Errorf("retry target session no longer exists"))
+		return nil
+	}
+	if h.hasActiveAnimation(inst.ID) {
+		h.setError(fmt.Errorf("session is starting, please wait..."))
+		return nil
+	}
+	if command != inst.RetryCommand() {
+		h.instancesMu.Lock()
+		_, postCommit, err := session.SetField(inst, session.FieldCommand, command, nil)
+		h.instancesMu.Unlock()
+		if err != nil {
+			h.setError(err)
+			return nil
+		}
+		if postCommit != nil {
+			postCommit()
+		}
+		h.forceSaveInstances()
+	}
+	h.resumingSessions[inst.ID] = time.Now()
+	return h.restartSession(inst)
+}
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// The retry bar opens prefilled with the session's command; editing it and
+// pressing Enter emits a retryStartMsg carrying the edited command.
+func TestRetryStartDialog_SubmitsEditedCommand(t *testing.T) {
+	d := NewRetryStartDialog()
+	d.SetSize(120, 40)
+	d.Show("sess-1", "api", "clade")
+
+	d, _ = d.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	d, _ = d.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	d, _ = d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+
+	d, cmd := d.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Enter must emit a retry command")
+	}
+	if d.IsVisible() {
+		t.Error("dialog should hide after submit")
+	}
+	msg, ok := cmd().(retryStartMsg)
+	if !ok {
+		t.Fatalf("emitted %T, want retryStartMsg", cmd())
+	}
+	if msg.instanceID != "sess-1" || msg.command != "claude" {
+		t.Errorf("msg = %+v, want sess-1/claude", msg)
+	}
+}
+
+func TestRetryStartDialog_EscAndEmpty(t *testing.T) {
+	d := NewRetryStartDialog()
+	d.Show("sess-1", "api", "")
+	if _, cmd := d.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || !d.IsVisible() {
+		t.Fatal("Enter on an empty command must do nothing")
+	}
+	if _, cmd := d.Update(tea.KeyMsg{Type: tea.KeyEsc}); cmd != nil || d.IsVisible() {
+		t.Fatal("Esc must close without retrying")
+	}
+}
+
+func TestRenderStartFailure_ShowsOutputTail(t *testing.T) {
+	var lines []string
+	for i := 0; i < 12; i++ {
+		lines = append(lines, "line-"+string(rune('a'+i)))
+	}
+	lines = append(lines, "\x1b[31mclaude: command not found\x1b[0m")
+	var b strings.Builder
+	renderStartFailure(&b, &session.SpawnFailureRecord{
+		Reason:      "spawn_returned_to_shell",
+		ElapsedMs:   3100,
+		DyingOutput: strings.Join(lines, "\n"),
+	}, 80)
+	out := tmux.StripANSI(b.String())
+
+	for _, want := range []string{"failed to start", "fell back to a shell", "claude: command not found", "line-f"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "line-e") {
+		t.Errorf("output should keep only the last %d lines:\n%s", startFailureOutputLines, out)
+	}
+}
@@ -41,8 +41,17 @@ type Search struct {
 	allItems       []*session.Instance
 	switchToGlobal bool   // Flag to signal switch to global search
 	scopedGroup    string // Non-empty => filter items to this exact GroupPath (v1.7.60)
+
+	// Sessions of the other profiles, matched below the local results so a
+	// search can jump anywhere. Not searched while scoped to a group.
+	otherItems   []session.ProfileSession
+	otherResults []session.SessionSearchHit
 }
 
+// maxOtherProfileResults caps the other-profile hits listed under the local
+// results.
+const maxOtherProfileResults = 5
+
 // NewSearch creates a new search overlay
 func NewSearch() *Search {
 	ti := textinput.New()
@@ -78,6 +87,12 @@ func (s *Search) SetItems(items []*session.Instance) {
 	s.updateResults()
 }
 
+// SetOtherProfileItems sets the sessions of the other profiles to search.
+func (s *Search) SetOtherProfileItems(items []session.ProfileSession) {
+	s.otherItems = items
+	s.updateResults()
+}
+
 // SetScopedGroup restricts SetItems to a single group path. Pass "" to clear.
 func (s *Search) SetScopedGroup(groupPath string) {
 	s.scopedGroup = groupPath
@@ -117,17 +132,30 @@ func (s *Search) IsVisible() bool {
 	return s.visible
 }
 
-// Selected returns the currently selected item
+// Selected returns the currently selected item, or nil when the cursor is on
+// another profile's session (see SelectedOther).
 func (s *Search) Selected() *session.Instance {
 	if len(s.results) == 0 {
 		return nil
 	}
 	if s.cursor >= len(s.results) {
+		if len(s.otherResults) > 0 {
+			return nil // on another profile's session
+		}
 		s.cursor = len(s.results) - 1
 	}
 	return s.results[s.cursor]
 }
 
+// SelectedOther returns the other-profile session under the cursor, or nil.
+func (s *Search) SelectedOther() *session.SessionSearchHit {
+	i := s.cursor - len(s.results)
+	if i < 0 || i >= len(s.otherResults) {
+		return nil
+	}
+	return &s.otherResults[i]
+}
+
 // Update handles messages for the search overlay
 // Returns the updated Search and any command to execute
 func (s *Search) Update(msg tea.Msg) (*Search, tea.Cmd) {
@@ -143,7 +171,7 @@ func (s *Search) Update(msg tea.Msg) (*Search, tea.Cmd) {
 			return s, nil
 
 		case "enter":
-			if len(s.results) > 0 {
+			if len(s.results)+len(s.otherResults) > 0 {
 				s.Hide()
 				// Parent should handle the selection
 			}
@@ -156,7 +184,7 @@ func (s *Search) Update(msg tea.Msg) (*Search, tea.Cmd) {
 			return s, nil
 
 		case "down", "ctrl+j":
-			if s.cursor < len(s.results)-1 {
+			if s.cursor < len(s.results)+len(s.otherResults)-1 {
 				s.cursor++
 			}
 			return s, nil
@@ -183,6 +211,13 @@ func (s *Search) Update(msg tea.Msg) (*Search, tea.Cmd) {
 func (s *Search) updateResults() {
 	query := s.input.Value()
 	s.results = session.FilterByQuery(s.allItems, query)
+	s.otherResults = nil
+	if s.scopedGroup == "" {
+		s.otherResults = session.SearchProfileSessions(query, s.otherItems, false)
+		if len(s.otherResults) > maxOtherProfileResults {
+			s.otherResults = s.otherResults[:maxOtherProfileResults]
+		}
+	}
 	s.cursor = 0
 }
 
@@ -207,6 +242,9 @@ func (s *Search) View() string {
 	if len(s.results) > maxResults {
 		s.results = s.results[:maxResults]
 	}
+	if s.cursor >= len(s.results)+len(s.otherResults) {
+		s.cursor = max(len(s.results)+len(s.otherResults)-1, 0)
+	}
 
 	for i, item := range s.results {
 		var line string
@@ -220,11 +258,26 @@ func (s *Search) View() string {
 			resultsStr.WriteString("\n")
 		}
 	}
+	if len(s.otherResults) > 0 {
+		if len(s.results) > 0 {
+			resultsStr.WriteString("\n")
+		}
+		resultsStr.WriteString(lipgloss.NewStyle().Foreground(ColorComment).Render("  Other profiles"))
+		for i, hit := range s.otherResults {
+			label := "[" + hit.Profile + "] " + hit.Title + " (" + hit.Tool + ")"
+			resultsStr.WriteString("\n")
+			if len(s.results)+i == s.cursor {
+				resultsStr.WriteString(selectedResultStyle.Render("› " + label))
+			} else {
+				resultsStr.WriteString(resultItemStyle.Render("  " + label))
+			}
+		}
+	}
 
 	// Show count
 	countStr := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("241")).
-		Render("  " + formatCount(len(s.results)))
+		Render("  " + formatCount(len(s.results)+len(s.otherResults)))
 
 	// Show filter hint when search is empty
 	hintStr := ""
@@ -1,8 +1,11 @@
 package ui
 
 import (
+	"strings"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
+
 	"github.com/asheshgoplani/agent-deck/internal/session"
 )
 
@@ -95,3 +98,35 @@ func TestSearchView(t *testing.T) {
 		t.Error("View should not be empty when visible")
 	}
 }
+
+func TestSearch_OtherProfileResults(t *testing.T) {
+	s := NewSearch()
+	s.SetItems([]*session.Instance{{ID: "l1", Title: "api-local", Tool: "claude"}})
+	s.SetOtherProfileItems([]session.ProfileSession{
+		{Profile: "work", InstanceData: &session.InstanceData{ID: "w1", Title: "api-work", Tool: "codex"}},
+	})
+	s.Show()
+	s.input.SetValue("api")
+	s.updateResults()
+
+	if sel := s.Selected(); sel == nil || sel.ID != "l1" {
+		t.Fatalf("Selected = %+v, want the local session first", sel)
+	}
+	s.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if s.Selected() != nil {
+		t.Error("Selected must be nil on another profile's session")
+	}
+	if other := s.SelectedOther(); other == nil || other.Profile != "work" || other.ID != "w1" {
+		t.Fatalf("SelectedOther = %+v, want work/w1", other)
+	}
+	if view := s.View(); !strings.Contains(view, "[work] api-work (codex)") {
+		t.Errorf("view missing the other-profile hit:\n%s", view)
+	}
+
+	// A group-scoped search stays within the current profile.
+	s.SetScopedGroup("grp")
+	s.updateResults()
+	if len(s.otherResults) != 0 {
+		t.Errorf("scoped search listed other profiles: %+v", s.otherResults)
+	}
+}
@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// Session row cost badge. With [display] show_session_cost = true a row shows
+// the session's cached spend; without the flag, or with nothing recorded, the
+// row carries no "$".
+
+func renderRowWithCost(t *testing.T, enabled bool, costs map[string]int64) string {
+	t.Helper()
+	forceTrueColorProfile()
+
+	h := &Home{width: 140, showSessionCost: enabled, sessionCosts: costs}
+	inst := &session.Instance{ID: "sess-cost", Title: "billing"}
+	item := session.Item{
+		Type:          session.ItemTypeSession,
+		Session:       inst,
+		Level:         1,
+		Path:          "test",
+		IsLastInGroup: true,
+	}
+	snapshot := map[string]sessionRenderState{
+		inst.ID: {status: session.StatusRunning, tool: "claude"},
+	}
+
+	var b strings.Builder
+	h.renderSessionItem(&b, item, false, snapshot, h.width)
+	return b.String()
+}
+
+func TestSessionCostBadge(t *testing.T) {
+	costs := map[string]int64{"sess-cost": 1_240_000}
+	if row := renderRowWithCost(t, true, costs); !strings.Contains(row, "$1.24") {
+		t.Errorf("show_session_cost=true should show the spend, got %q", row)
+	}
+	if row := renderRowWithCost(t, false, costs); strings.Contains(row, "$") {
+		t.Errorf("show_session_cost=false must not show a cost badge, got %q", row)
+	}
+	if row := renderRowWithCost(t, true, nil); strings.Contains(row, "$") {
+		t.Errorf("a session with no recorded usage must not show a cost badge, got %q", row)
+	}
+}
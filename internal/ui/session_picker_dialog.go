@@ -38,7 +38,8 @@ func (d *SessionPickerDialog) Show(source *session.Instance, allInstances []*ses
 		if inst.ID == source.ID {
 			continue
 		}
-		if inst.Status == session.StatusError || inst.Status == session.StatusStopped {
+		switch inst.Status {
+		case session.StatusError, session.StatusStopped, session.StatusExited, session.StatusCrashed:
 			continue
 		}
 		d.sessions = append(d.sessions, inst)
@@ -174,13 +175,14 @@ func (d *SessionPickerDialog) View() string {
 
 // statusIndicator returns the status symbol for a session.
 func statusIndicator(status session.Status) string {
+	style := lipgloss.NewStyle().Foreground(StatusColor(status))
 	switch status {
 	case session.StatusRunning:
-		return lipgloss.NewStyle().Foreground(ColorGreen).Render("●")
+		return style.Render("●")
 	case session.StatusWaiting:
-		return lipgloss.NewStyle().Foreground(ColorYellow).Render("◐")
+		return style.Render("◐")
 	case session.StatusIdle:
-		return lipgloss.NewStyle().Foreground(ColorTextDim).Render("○")
+		return style.Render("○")
 	default:
 		return lipgloss.NewStyle().Foreground(ColorRed).Render("✕")
 	}
@@ -98,7 +98,7 @@ func (s *SessionSwitcher) Show(fromID string, allInstances []*session.Instance,
 		}
 		// Mirror the send-output picker: only switchable (live) sessions.
 		switch inst.GetStatusThreadSafe() {
-		case session.StatusError, session.StatusStopped:
+		case session.StatusError, session.StatusStopped, session.StatusExited, session.StatusCrashed:
 			continue
 		}
 		list = append(list, inst)
@@ -70,6 +70,8 @@ func TestSessionSwitcher_FiltersStoppedAndError(t *testing.T) {
 		{ID: "b", Status: session.StatusError, LastAccessedAt: now},
 		{ID: "c", Status: session.StatusStopped, LastAccessedAt: now},
 		{ID: "d", Status: session.StatusWaiting, LastAccessedAt: now.Add(-time.Minute)},
+		{ID: "e", Status: session.StatusExited, LastAccessedAt: now},
+		{ID: "f", Status: session.StatusCrashed, LastAccessedAt: now},
 	}
 	sw := NewSessionSwitcher()
 	if !sw.Show("a", list, nil) {
@@ -79,7 +81,7 @@ func TestSessionSwitcher_FiltersStoppedAndError(t *testing.T) {
 		t.Fatalf("live sessions = %v, want only a and d", got)
 	}
 	for _, inst := range sw.sessions {
-		if inst.ID == "b" || inst.ID == "c" {
+		if inst.ID != "a" && inst.ID != "d" {
 			t.Fatalf("dead session %s leaked into switcher", inst.ID)
 		}
 	}
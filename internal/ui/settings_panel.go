@@ -48,12 +48,13 @@ const (
 	SettingSyncTitle
 	SettingShowSessionTimestamps
 	SettingShowPaneTitles
+	SettingShowSessionCost
 	SettingShowOnlyInstalledTools
 	SettingVisibleTools
 )
 
 // Total number of navigable settings.
-const settingsCount = 34
+const settingsCount = 35
 
 // SettingsPanel displays and edits user configuration
 type SettingsPanel struct {
@@ -68,8 +69,12 @@ type SettingsPanel struct {
 	toolNames  []string
 	toolValues []string
 
+	// Dynamic theme lists (built-in + [themes.<name>] from config)
+	themeNames  []string
+	themeValues []string
+
 	// Setting values
-	selectedTheme       int // 0=dark, 1=light, 2=system
+	selectedTheme       int // index into themeNames/themeValues (0=dark, 1=light, 2=system)
 	selectedTool        int // index into toolNames/toolValues
 	dangerousMode       bool
 	claudeConfigDir     string
@@ -103,6 +108,7 @@ type SettingsPanel struct {
 
 	showSessionTimestamps  bool
 	showPaneTitles         bool
+	showSessionCost        bool
 	showOnlyInstalledTools bool
 	pendingToolVisibility  bool
 
@@ -130,10 +136,11 @@ var (
 	tierValues = []string{"auto", "instant", "balanced"}
 )
 
-// Theme names for radio selection
+// builtinThemeNames and builtinThemeValues are the built-in themes. Custom
+// [themes.<name>] palettes are appended dynamically in LoadConfig.
 var (
-	themeNames  = []string{"Dark", "Light", "System"}
-	themeValues = []string{"dark", "light", "system"}
+	builtinThemeNames  = []string{"Dark", "Light", "System"}
+	builtinThemeValues = []string{"dark", "light", "system"}
 )
 
 // Stats format names for radio selection
@@ -147,6 +154,8 @@ func NewSettingsPanel() *SettingsPanel {
 	return &SettingsPanel{
 		toolNames:           append(append([]string{}, builtinToolNames...), "None"),
 		toolValues:          append(append([]string{}, builtinToolValues...), ""),
+		themeNames:          append([]string{}, builtinThemeNames...),
+		themeValues:         append([]string{}, builtinThemeValues...),
 		logMaxSizeMB:        10,
 		logMaxLines:         10000,
 		removeOrphans:       true,
@@ -226,13 +235,13 @@ func (s *SettingsPanel) SetProfile(profile string) {
 // LoadConfig populates panel values from a UserConfig
 func (s *SettingsPanel) LoadConfig(config *session.UserConfig) {
 	// Load theme
-	switch config.Theme {
-	case "light":
-		s.selectedTheme = 1
-	case "system":
-		s.selectedTheme = 2
-	default:
-		s.selectedTheme = 0
+	s.buildThemeLists(config)
+	s.selectedTheme = 0
+	for i, val := range s.themeValues {
+		if val == config.Theme {
+			s.selectedTheme = i
+			break
+		}
 	}
 
 	// Rebuild tool lists: built-ins + custom tools + "None".
@@ -338,11 +347,34 @@ func (s *SettingsPanel) LoadConfig(config *session.UserConfig) {
 	// Display settings
 	s.showSessionTimestamps = config.Display.ShowSessionTimestamps
 	s.showPaneTitles = config.Display.ShowPaneTitles
+	s.showSessionCost = config.Display.ShowSessionCost
 
 	// UI tool picker settings
 	s.showOnlyInstalledTools = config.UI.ShowOnlyInstalledTools
 }
 
+// buildThemeLists rebuilds the theme choices: built-ins, then the custom
+// [themes.<name>] palettes sorted by name.
+func (s *SettingsPanel) buildThemeLists(config *session.UserConfig) {
+	names := append([]string{}, builtinThemeNames...)
+	values := append([]string{}, builtinThemeValues...)
+
+	var custom []string
+	for name := range config.Themes {
+		if name != "dark" && name != "light" && session.ValidateThemeName(name) == nil {
+			custom = append(custom, name)
+		}
+	}
+	sort.Strings(custom)
+	for _, name := range custom {
+		names = append(names, strings.ToUpper(name[:1])+name[1:])
+		values = append(values, name)
+	}
+
+	s.themeNames = names
+	s.themeValues = values
+}
+
 func (s *SettingsPanel) buildToolLists(config *session.UserConfig) {
 	names := append([]string{}, builtinToolNames...)
 	values := append([]string{}, builtinToolValues...)
@@ -383,8 +415,8 @@ func (s *SettingsPanel) GetConfig() *session.UserConfig {
 	}
 
 	// Theme
-	if s.selectedTheme < len(themeValues) {
-		config.Theme = themeValues[s.selectedTheme]
+	if s.selectedTheme < len(s.themeValues) {
+		config.Theme = s.themeValues[s.selectedTheme]
 	}
 
 	// Default tool
@@ -474,6 +506,7 @@ func (s *SettingsPanel) GetConfig() *session.UserConfig {
 	// Display settings
 	config.Display.ShowSessionTimestamps = s.showSessionTimestamps
 	config.Display.ShowPaneTitles = s.showPaneTitles
+	config.Display.ShowSessionCost = s.showSessionCost
 
 	// UI tool picker settings
 	config.UI.ShowOnlyInstalledTools = s.showOnlyInstalledTools
@@ -582,7 +615,7 @@ func (s *SettingsPanel) adjustValue(delta int) bool {
 	switch setting {
 	case SettingTheme:
 		newVal := s.selectedTheme + delta
-		if newVal >= 0 && newVal < len(themeNames) {
+		if newVal >= 0 && newVal < len(s.themeNames) {
 			s.selectedTheme = newVal
 			changed = true
 		}
@@ -747,6 +780,10 @@ func (s *SettingsPanel) toggleValue() bool {
 		s.showPaneTitles = !s.showPaneTitles
 		return true
 
+	case SettingShowSessionCost:
+		s.showSessionCost = !s.showSessionCost
+		return true
+
 	case SettingShowOnlyInstalledTools:
 		s.showOnlyInstalledTools = !s.showOnlyInstalledTools
 		return true
@@ -853,7 +890,11 @@ func (s *SettingsPanel) View() string {
 		content.WriteString(warningStyle.Render(" (restart required)"))
 	}
 	content.WriteString("\n")
-	themeRow := s.renderRadioGroup(themeNames, s.selectedTheme, s.cursor == int(SettingTheme))
+	themeRow := s.renderRadioGroup(s.themeNames, s.selectedTheme, s.cursor == int(SettingTheme))
+	if lipgloss.Width(themeRow) > dialogWidth-6 {
+		// Too many custom themes for one row: show the selection alone.
+		themeRow = s.renderSpinner(s.themeNames, s.selectedTheme)
+	}
 	if s.cursor == int(SettingTheme) {
 		themeRow = highlightStyle.Render(themeRow)
 	}
@@ -1127,6 +1168,12 @@ func (s *SettingsPanel) View() string {
 	if s.cursor == int(SettingShowPaneTitles) {
 		line = highlightStyle.Render(line)
 	}
+	content.WriteString("  " + labelStyle.Render(line) + "\n")
+
+	line = s.renderCheckbox("Show session cost", s.showSessionCost) + " - Claude spend per row"
+	if s.cursor == int(SettingShowSessionCost) {
+		line = highlightStyle.Render(line)
+	}
 	content.WriteString("  " + labelStyle.Render(line) + "\n\n")
 
 	// UI / TOOL PICKER
@@ -1213,8 +1260,9 @@ func (s *SettingsPanel) View() string {
 			54, // SettingSyncTitle (SESSIONS section, after stats)
 			57, // SettingShowSessionTimestamps (DISPLAY section, after SESSIONS)
 			58, // SettingShowPaneTitles (DISPLAY section, after timestamps)
-			61, // SettingShowOnlyInstalledTools (TOOL PICKER section)
-			62, // SettingVisibleTools
+			59, // SettingShowSessionCost (DISPLAY section, after pane titles)
+			62, // SettingShowOnlyInstalledTools (TOOL PICKER section)
+			63, // SettingVisibleTools
 		}
 		cursorLine := cursorToLine[s.cursor]
 
@@ -1318,6 +1366,16 @@ func (s *SettingsPanel) renderRadioGroup(options []string, selected int, focused
 	return strings.Join(parts, "  ")
 }
 
+// renderSpinner renders a single-choice selector as "◀ Name (i/n) ▶", for
+// option lists too long for a radio row.
+func (s *SettingsPanel) renderSpinner(options []string, selected int) string {
+	if selected < 0 || selected >= len(options) {
+		return ""
+	}
+	style := lipgloss.NewStyle().Bold(true).Foreground(ColorAccent)
+	return fmt.Sprintf("◀ %s (%d/%d) ▶", style.Render(options[selected]), selected+1, len(options))
+}
+
 // renderNumber renders a number input with label and suffix
 func (s *SettingsPanel) renderNumber(label string, value int, suffix string) string {
 	numStyle := lipgloss.NewStyle().Foreground(ColorAccent).Bold(true)
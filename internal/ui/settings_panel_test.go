@@ -2,6 +2,7 @@ package ui
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/asheshgoplani/agent-deck/internal/session"
@@ -762,6 +763,38 @@ func TestSettingsPanel_GetConfig_Theme(t *testing.T) {
 	}
 }
 
+func TestSettingsPanel_CustomThemes(t *testing.T) {
+	panel := NewSettingsPanel()
+	panel.LoadConfig(&session.UserConfig{
+		Theme: "nord",
+		Themes: map[string]session.ThemeDef{
+			"nord":    {Accent: "#88c0d0"},
+			"gruvbox": {Bg: "#282828"},
+			"dark":    {Accent: "#ff0000"},
+		},
+	})
+
+	if want := []string{"dark", "light", "system", "gruvbox", "nord"}; !reflect.DeepEqual(panel.themeValues, want) {
+		t.Fatalf("themeValues = %v, want %v", panel.themeValues, want)
+	}
+	if panel.themeNames[4] != "Nord" || panel.selectedTheme != 4 {
+		t.Fatalf("selected %d (%v), want Nord", panel.selectedTheme, panel.themeNames)
+	}
+	if got := panel.GetConfig().Theme; got != "nord" {
+		t.Fatalf("GetConfig().Theme = %q, want nord", got)
+	}
+
+	panel.visible = true
+	panel.cursor = int(SettingTheme)
+	panel, _, shouldSave := panel.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	if panel.GetConfig().Theme != "gruvbox" || !shouldSave {
+		t.Fatalf("after left: theme %q, save %v", panel.GetConfig().Theme, shouldSave)
+	}
+	if view := panel.View(); !strings.Contains(view, "Gruvbox") {
+		t.Errorf("settings view should show the selected custom theme:\n%s", view)
+	}
+}
+
 func TestSettingsPanelPreviewSettings(t *testing.T) {
 	sp := NewSettingsPanel()
 
@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// pollTierFor maps a session status to its polling tier.
+func pollTierFor(status session.Status) tmux.PollTier {
+	switch status {
+	case session.StatusRunning, session.StatusStarting:
+		return tmux.PollActive
+	case session.StatusWaiting, session.StatusError:
+		return tmux.PollWaiting
+	default:
+		return tmux.PollIdle
+	}
+}
+
+// statusTick is the status worker's cadence: the scheduler's smallest
+// interval, never slower than baseStatusInterval.
+func (h *Home) statusTick() time.Duration {
+	if h.pollScheduler == nil {
+		return baseStatusInterval
+	}
+	if tick := h.pollScheduler.Tick(); tick < baseStatusInterval {
+		return tick
+	}
+	return baseStatusInterval
+}
+
+// statusPollDue reports whether inst is due for a status poll at now. New
+// control-pipe output or a lifecycle hook event since its last poll wakes it
+// early. Without a scheduler every session is always due.
+func (h *Home) statusPollDue(inst *session.Instance, pm *tmux.PipeManager, now time.Time) bool {
+	if h.pollScheduler == nil {
+		return true
+	}
+	last := h.pollScheduler.LastPolled(inst.ID)
+	if h.hookWatcher != nil {
+		// Hook timestamps have whole-second precision.
+		if hs := h.hookWatcher.GetHookStatus(inst.ID); hs != nil && !hs.UpdatedAt.Before(last.Truncate(time.Second)) {
+			h.pollScheduler.Wake(inst.ID)
+		}
+	}
+	if pm != nil {
+		if ts := inst.GetTmuxSession(); ts != nil && pm.IsConnected(ts.Name) {
+			if out := pm.LastOutputTime(ts.Name); !out.IsZero() && out.After(last) {
+				h.pollScheduler.Wake(inst.ID)
+			}
+		}
+	}
+	return h.pollScheduler.Due(inst.ID, now)
+}
+
+// anyStatusPollDue reports whether any swept session is due at now.
+func (h *Home) anyStatusPollDue(instances []*session.Instance, pm *tmux.PipeManager, now time.Time) bool {
+	for _, inst := range instances {
+		if h.shouldSweepInstance(inst) && h.statusPollDue(inst, pm, now) {
+			return true
+		}
+	}
+	return false
+}
+
+// markStatusPolled records a poll of inst; its next poll follows the tier
+// of the status it now has.
+func (h *Home) markStatusPolled(inst *session.Instance, status session.Status) {
+	if h.pollScheduler != nil {
+		h.pollScheduler.Polled(inst.ID, inst.Tool, pollTierFor(status), time.Now())
+	}
+}
+
+// retainPollState drops scheduler state for sessions that no longer exist.
+func (h *Home) retainPollState(instances []*session.Instance) {
+	if h.pollScheduler == nil {
+		return
+	}
+	keep := make(map[string]bool, len(instances))
+	for _, inst := range instances {
+		keep[inst.ID] = true
+	}
+	h.pollScheduler.Retain(keep)
+}
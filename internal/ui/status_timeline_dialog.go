@@ -0,0 +1,273 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// statusTimelineRanges are the windows the timeline dialog cycles through
+// with tab; 0 is the whole recorded history.
+var statusTimelineRanges = []struct {
+	label  string
+	window time.Duration
+}{
+	{"last 24h", 24 * time.Hour},
+	{"last 7 days", 7 * 24 * time.Hour},
+	{"last hour", time.Hour},
+	{"all", 0},
+}
+
+// StatusTimelineDialog shows when a session was running, waiting or idle:
+// a bar across the selected window, the totals per status and the spans,
+// newest first. Fed from the status_transitions table, so it covers time the
+// TUI was closed while the daemon kept polling.
+type StatusTimelineDialog struct {
+	visible       bool
+	width, height int
+	sessionTitle  string
+	rows          []statedb.StatusTransitionRow
+	rangeIdx      int
+	now           time.Time
+	timeline      session.StatusTimeline
+	cursor        int // index into timeline.Spans, counted from the newest
+}
+
+// NewStatusTimelineDialog creates the dialog (hidden).
+func NewStatusTimelineDialog() *StatusTimelineDialog {
+	return &StatusTimelineDialog{}
+}
+
+// Show opens the dialog on a session's recorded status changes (oldest
+// first). Returns false and stays hidden when nothing was recorded.
+func (d *StatusTimelineDialog) Show(sessionTitle string, rows []statedb.StatusTransitionRow, now time.Time) bool {
+	if len(rows) == 0 {
+		return false
+	}
+	d.visible = true
+	d.sessionTitle = sessionTitle
+	d.rows = rows
+	d.now = now
+	d.rangeIdx = 0
+	d.rebuild()
+	return true
+}
+
+// rebuild recomputes the timeline for the selected range.
+func (d *StatusTimelineDialog) rebuild() {
+	var since time.Time
+	if w := statusTimelineRanges[d.rangeIdx].window; w > 0 {
+		since = d.now.Add(-w)
+	}
+	d.timeline = session.BuildStatusTimeline(d.rows, since, d.now)
+	d.cursor = 0
+}
+
+// Hide closes the dialog and clears its state.
+func (d *StatusTimelineDialog) Hide() {
+	d.visible = false
+	d.rows = nil
+	d.timeline = session.StatusTimeline{}
+	d.cursor = 0
+	d.sessionTitle = ""
+}
+
+// IsVisible reports whether the dialog is shown (nil-safe).
+func (d *StatusTimelineDialog) IsVisible() bool { return d != nil && d.visible }
+
+// SetSize updates the dialog dimensions for centering.
+func (d *StatusTimelineDialog) SetSize(w, h int) {
+	if d == nil {
+		return
+	}
+	d.width = w
+	d.height = h
+}
+
+// Update handles navigation and range keys.
+func (d *StatusTimelineDialog) Update(msg tea.KeyMsg) (*StatusTimelineDialog, tea.Cmd) {
+	if !d.IsVisible() {
+		return d, nil
+	}
+	n := len(d.timeline.Spans)
+	switch msg.String() {
+	case "tab":
+		d.rangeIdx = (d.rangeIdx + 1) % len(statusTimelineRanges)
+		d.rebuild()
+	case "shift+tab":
+		d.rangeIdx = (d.rangeIdx - 1 + len(statusTimelineRanges)) % len(statusTimelineRanges)
+		d.rebuild()
+	case "j", "down":
+		if n > 0 {
+			d.cursor = (d.cursor + 1) % n
+		}
+	case "k", "up":
+		if n > 0 {
+			d.cursor = (d.cursor - 1 + n) % n
+		}
+	case "g", "home":
+		d.cursor = 0
+	case "G", "end":
+		d.cursor = max(n-1, 0)
+	}
+	return d, nil
+}
+
+// statusTimelineDialogChrome counts the rows around the span list: border
+// and padding (4), title, session line, blank, bar, axis, totals, blank, two
+// overflow markers, blank, footer.
+const statusTimelineDialogChrome = 14
+
+// visibleRows returns how many span rows fit on screen.
+func (d *StatusTimelineDialog) visibleRows() int {
+	const def = 12
+	if d.height <= 0 {
+		return def
+	}
+	return min(max(d.height-statusTimelineDialogChrome, 1), def)
+}
+
+// statusTimelineBar renders the spans as a width-cell bar, each cell colored
+// by the status in effect at its midpoint.
+func statusTimelineBar(tl session.StatusTimeline, width int) string {
+	if len(tl.Spans) == 0 || width < 1 {
+		return ""
+	}
+	start := tl.Spans[0].Start
+	total := tl.Span()
+	var b strings.Builder
+	j := 0
+	for i := 0; i < width; i++ {
+		t := start.Add(time.Duration((float64(i) + 0.5) / float64(width) * float64(total)))
+		for j+1 < len(tl.Spans) && !t.Before(tl.Spans[j].End) {
+			j++
+		}
+		_, style := rowStatusGlyph(tl.Spans[j].Status, session.SubstateNone, false)
+		b.WriteString(style.Render("█"))
+	}
+	return b.String()
+}
+
+// View renders the dialog.
+func (d *StatusTimelineDialog) View() string {
+	if !d.IsVisible() {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorAccent)
+	sourceStyle := lipgloss.NewStyle().Foreground(ColorTextDim)
+	selectedStyle := lipgloss.NewStyle().Foreground(ColorAccent).Bold(true)
+	normalStyle := lipgloss.NewStyle().Foreground(ColorText)
+	dimStyle := lipgloss.NewStyle().Foreground(ColorComment)
+	footerStyle := lipgloss.NewStyle().Foreground(ColorComment).Italic(true)
+
+	dialogWidth := fitDialogWidth(76, 40, d.width)
+	innerWidth := max(dialogWidth-4, 1)
+	fit := func(s string) string { return cellTruncate(s, innerWidth, "…") }
+
+	tl := d.timeline
+	spans := tl.Spans
+	var lines []string
+	lines = append(lines, fit(titleStyle.Render("Status Timeline")+dimStyle.Render(" · "+statusTimelineRanges[d.rangeIdx].label)))
+	lines = append(lines, fit(sourceStyle.Render(fmt.Sprintf("Session: %q", d.sessionTitle))))
+	lines = append(lines, "")
+
+	if len(spans) == 0 {
+		lines = append(lines, dimStyle.Render("  No status changes in this range"))
+	} else {
+		lines = append(lines, statusTimelineBar(tl, innerWidth))
+		from := spans[0].Start.Local().Format("Jan 02 15:04")
+		to := "now"
+		axis := from + strings.Repeat(" ", max(innerWidth-cellWidth(from)-cellWidth(to), 1)) + to
+		lines = append(lines, fit(dimStyle.Render(axis)))
+
+		totals := tl.Totals()
+		statuses := make([]session.Status, 0, len(totals))
+		for status := range totals {
+			statuses = append(statuses, status)
+		}
+		sort.Slice(statuses, func(i, j int) bool { return totals[statuses[i]] > totals[statuses[j]] })
+		parts := make([]string, 0, len(statuses))
+		for _, status := range statuses {
+			icon, style := rowStatusGlyph(status, session.SubstateNone, false)
+			parts = append(parts, style.Render(icon)+" "+normalStyle.Render(fmt.Sprintf("%s %s", status, formatDuration(totals[status].Round(time.Second)))))
+		}
+		lines = append(lines, fit(strings.Join(parts, dimStyle.Render(" · "))))
+		lines = append(lines, "")
+
+		start, end := windowBounds(d.cursor, len(spans), d.visibleRows())
+		if start > 0 {
+			lines = append(lines, fit(dimStyle.Render(fmt.Sprintf("  ↑ %d more", start))))
+		}
+		for i := start; i < end; i++ {
+			s := spans[len(spans)-1-i]
+			icon, style := rowStatusGlyph(s.Status, session.SubstateNone, false)
+			label := fmt.Sprintf("%s  %-8s %s", s.Start.Local().Format("Jan 02 15:04:05"), s.Status, formatDuration(s.Duration().Round(time.Second)))
+			if i == d.cursor {
+				lines = append(lines, fit("> "+style.Render(icon)+" "+selectedStyle.Render(label)))
+			} else {
+				lines = append(lines, fit("  "+style.Render(icon)+" "+normalStyle.Render(label)))
+			}
+		}
+		if end < len(spans) {
+			lines = append(lines, fit(dimStyle.Render(fmt.Sprintf("  ↓ %d more", len(spans)-end))))
+		}
+	}
+
+	lines = append(lines, "")
+	footer := "Tab range | j/k navigate | Esc close"
+	if cellWidth(footer) > innerWidth {
+		footer = "Tab | j/k | Esc"
+	}
+	lines = append(lines, fit(footerStyle.Render(footer)))
+
+	box := DialogBoxStyle.Width(dialogWidth).Render(strings.Join(lines, "\n"))
+	return centerInScreen(box, d.width, d.height)
+}
+
+// openStatusTimeline shows the selected session's status timeline.
+func (h *Home) openStatusTimeline() {
+	if h.cursor >= len(h.flatItems) {
+		return
+	}
+	item := h.flatItems[h.cursor]
+	if item.Type != session.ItemTypeSession || item.Session == nil {
+		return
+	}
+	inst := item.Session
+	db := statedb.GetGlobal()
+	if db == nil {
+		return
+	}
+	rows, err := db.LoadStatusTransitions(inst.ID, time.Time{})
+	if err != nil {
+		h.setError(fmt.Errorf("status timeline: %w", err))
+		return
+	}
+	if h.statusTimelineDialog == nil {
+		h.statusTimelineDialog = NewStatusTimelineDialog()
+	}
+	h.statusTimelineDialog.SetSize(h.width, h.height)
+	if !h.statusTimelineDialog.Show(inst.Title, rows, time.Now()) {
+		h.setError(fmt.Errorf("no status changes recorded for %s yet", inst.Title))
+	}
+}
+
+// handleStatusTimelineDialogKey closes the dialog on esc/q and routes
+// everything else to it.
+func (h *Home) handleStatusTimelineDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q", "enter":
+		h.statusTimelineDialog.Hide()
+	default:
+		h.statusTimelineDialog.Update(msg)
+	}
+	return h, nil
+}
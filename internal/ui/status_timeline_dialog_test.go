@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+func TestStatusTimelineDialog_ShowsSpansNewestFirst(t *testing.T) {
+	d := NewStatusTimelineDialog()
+	d.SetSize(100, 40)
+	now := time.Now()
+	if d.Show("api", nil, now) || d.IsVisible() {
+		t.Fatal("an empty history must not open the dialog")
+	}
+
+	d.Show("api", []statedb.StatusTransitionRow{
+		{Status: "running", At: now.Add(-50 * time.Minute)},
+		{Status: "waiting", At: now.Add(-20 * time.Minute)},
+	}, now)
+	view := d.View()
+	for _, want := range []string{"Status Timeline", "last 24h", `Session: "api"`, "running 30m 0s", "waiting 20m 0s"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+	if strings.Index(view, "waiting  ") > strings.Index(view, "running  ") {
+		t.Errorf("spans should list newest first:\n%s", view)
+	}
+
+	// Tab cycles the range; both spans fall inside the last hour.
+	d.Update(tea.KeyMsg{Type: tea.KeyTab})
+	d.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if view := d.View(); !strings.Contains(view, "last hour") || !strings.Contains(view, "running 30m 0s") {
+		t.Errorf("last-hour view:\n%s", view)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"sync"
 
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
 )
 
 // Theme represents the current color scheme
@@ -18,12 +20,15 @@ const (
 // currentTheme holds the active theme (set at init)
 var currentTheme Theme = ThemeDark
 
-// Dark Theme - Tokyo Night
-var darkColors = struct {
+// themePalette is a full set of theme colors.
+type themePalette struct {
 	Bg, Surface, Border, Text, TextDim  lipgloss.Color
 	Accent, Purple, Cyan, Green, Yellow lipgloss.Color
 	Orange, Red, Comment                lipgloss.Color
-}{
+}
+
+// Dark Theme - Tokyo Night
+var darkColors = themePalette{
 	Bg:      lipgloss.Color("#1a1b26"),
 	Surface: lipgloss.Color("#24283b"),
 	Border:  lipgloss.Color("#414868"),
@@ -40,11 +45,7 @@ var darkColors = struct {
 }
 
 // Light Theme - Tokyo Night Light variant
-var lightColors = struct {
-	Bg, Surface, Border, Text, TextDim  lipgloss.Color
-	Accent, Purple, Cyan, Green, Yellow lipgloss.Color
-	Orange, Red, Comment                lipgloss.Color
-}{
+var lightColors = themePalette{
 	Bg:      lipgloss.Color("#d5d6db"),
 	Surface: lipgloss.Color("#e9e9ec"),
 	Border:  lipgloss.Color("#9699a3"),
@@ -60,6 +61,67 @@ var lightColors = struct {
 	Comment: lipgloss.Color("#6a6d7c"),
 }
 
+// colorRefs maps ThemeDef color keys to the palette fields they override.
+func (p *themePalette) colorRefs() map[string]*lipgloss.Color {
+	return map[string]*lipgloss.Color{
+		"bg": &p.Bg, "surface": &p.Surface, "border": &p.Border, "text": &p.Text,
+		"text_dim": &p.TextDim, "accent": &p.Accent, "purple": &p.Purple,
+		"cyan": &p.Cyan, "green": &p.Green, "yellow": &p.Yellow,
+		"orange": &p.Orange, "red": &p.Red, "comment": &p.Comment,
+	}
+}
+
+// statusColorsFor returns the default status colors of a palette.
+func statusColorsFor(p themePalette) map[session.Status]lipgloss.Color {
+	return map[session.Status]lipgloss.Color{
+		session.StatusRunning:  p.Green,
+		session.StatusWaiting:  p.Yellow,
+		session.StatusIdle:     p.TextDim,
+		session.StatusError:    p.Red,
+		session.StatusStarting: p.TextDim,
+		session.StatusQueued:   p.TextDim,
+		session.StatusStopped:  p.TextDim,
+		session.StatusExited:   p.TextDim,
+		session.StatusCrashed:  p.Red,
+	}
+}
+
+// resolvePalette applies def's valid colors on top of the base palette.
+// Invalid colors are skipped here; `agent-deck theme list` reports them.
+func resolvePalette(base string, def session.ThemeDef) (themePalette, map[session.Status]lipgloss.Color) {
+	p := darkColors
+	if base == "light" {
+		p = lightColors
+	}
+	refs := p.colorRefs()
+	for key, value := range def.Colors() {
+		if ref, ok := refs[key]; ok && session.IsThemeColor(value) {
+			*ref = lipgloss.Color(value)
+		}
+	}
+	statuses := statusColorsFor(p)
+	for key, value := range def.Status {
+		if _, ok := statuses[session.Status(key)]; ok && session.IsThemeColor(value) {
+			statuses[session.Status(key)] = lipgloss.Color(value)
+		}
+	}
+	return p, statuses
+}
+
+// ResolvedThemeDef returns base plus def with every color filled in, the
+// form `agent-deck theme export` writes so a theme file stands on its own.
+func ResolvedThemeDef(base string, def session.ThemeDef) session.ThemeDef {
+	p, statuses := resolvePalette(base, def)
+	out := session.ThemeDef{Base: base, Status: make(map[string]string, len(statuses))}
+	for key, ref := range p.colorRefs() {
+		out.SetColor(key, string(*ref))
+	}
+	for status, c := range statuses {
+		out.Status[string(status)] = string(c)
+	}
+	return out
+}
+
 // Active color variables (set by InitTheme)
 var (
 	ColorBg      lipgloss.Color
@@ -77,50 +139,59 @@ var (
 	ColorComment lipgloss.Color
 )
 
+// statusColors holds the active per-status colors (set by InitTheme).
+var statusColors map[session.Status]lipgloss.Color
+
 // themeMu protects global color/style variables during live theme switches.
-// Write lock held by InitTheme; read lock held by GetToolStyle (map access).
+// Write lock held by InitTheme; read lock held by GetToolStyle and
+// StatusColor (map access).
 var themeMu sync.RWMutex
 
 // InitTheme sets the active color palette based on theme name
 // Must be called before any UI rendering
 func InitTheme(theme string) {
+	InitThemeDef(theme, session.ThemeDef{})
+}
+
+// InitThemeDef sets the active palette to the built-in base ("dark" or
+// "light") with a [themes.<name>] definition's colors applied on top.
+func InitThemeDef(base string, def session.ThemeDef) {
 	themeMu.Lock()
 	defer themeMu.Unlock()
-	if theme == "light" {
+	if base == "light" {
 		currentTheme = ThemeLight
-		ColorBg = lightColors.Bg
-		ColorSurface = lightColors.Surface
-		ColorBorder = lightColors.Border
-		ColorText = lightColors.Text
-		ColorTextDim = lightColors.TextDim
-		ColorAccent = lightColors.Accent
-		ColorPurple = lightColors.Purple
-		ColorCyan = lightColors.Cyan
-		ColorGreen = lightColors.Green
-		ColorYellow = lightColors.Yellow
-		ColorOrange = lightColors.Orange
-		ColorRed = lightColors.Red
-		ColorComment = lightColors.Comment
 	} else {
 		currentTheme = ThemeDark
-		ColorBg = darkColors.Bg
-		ColorSurface = darkColors.Surface
-		ColorBorder = darkColors.Border
-		ColorText = darkColors.Text
-		ColorTextDim = darkColors.TextDim
-		ColorAccent = darkColors.Accent
-		ColorPurple = darkColors.Purple
-		ColorCyan = darkColors.Cyan
-		ColorGreen = darkColors.Green
-		ColorYellow = darkColors.Yellow
-		ColorOrange = darkColors.Orange
-		ColorRed = darkColors.Red
-		ColorComment = darkColors.Comment
 	}
+	p, statuses := resolvePalette(string(currentTheme), def)
+	ColorBg = p.Bg
+	ColorSurface = p.Surface
+	ColorBorder = p.Border
+	ColorText = p.Text
+	ColorTextDim = p.TextDim
+	ColorAccent = p.Accent
+	ColorPurple = p.Purple
+	ColorCyan = p.Cyan
+	ColorGreen = p.Green
+	ColorYellow = p.Yellow
+	ColorOrange = p.Orange
+	ColorRed = p.Red
+	ColorComment = p.Comment
+	statusColors = statuses
 	// Reinitialize styles with new colors
 	initStyles()
 }
 
+// StatusColor returns the active theme's color for a session status.
+func StatusColor(status session.Status) lipgloss.Color {
+	themeMu.RLock()
+	defer themeMu.RUnlock()
+	if c, ok := statusColors[status]; ok {
+		return c
+	}
+	return ColorTextDim
+}
+
 // GetCurrentTheme returns the active theme
 func GetCurrentTheme() Theme {
 	return currentTheme
@@ -240,6 +311,10 @@ var (
 	SessionStatusIdle     lipgloss.Style
 	SessionStatusError    lipgloss.Style
 	SessionStatusStopped  lipgloss.Style
+	SessionStatusStarting lipgloss.Style
+	SessionStatusQueued   lipgloss.Style
+	SessionStatusExited   lipgloss.Style
+	SessionStatusCrashed  lipgloss.Style
 	SessionStatusSelStyle lipgloss.Style
 
 	// Session title styles by state
@@ -487,11 +562,17 @@ func initStyles() {
 	TreeConnectorSelStyle = lipgloss.NewStyle().Foreground(ColorBg).Background(ColorAccent)
 
 	// Session status indicator styles
-	SessionStatusRunning = lipgloss.NewStyle().Foreground(ColorGreen)
-	SessionStatusWaiting = lipgloss.NewStyle().Foreground(ColorYellow)
-	SessionStatusIdle = lipgloss.NewStyle().Foreground(ColorTextDim)
-	SessionStatusError = lipgloss.NewStyle().Foreground(ColorRed)
-	SessionStatusStopped = lipgloss.NewStyle().Foreground(ColorTextDim)
+	// (themes can recolor each status; initStyles runs under themeMu, so
+	// statusColors is read directly)
+	SessionStatusRunning = lipgloss.NewStyle().Foreground(statusColors[session.StatusRunning])
+	SessionStatusWaiting = lipgloss.NewStyle().Foreground(statusColors[session.StatusWaiting])
+	SessionStatusIdle = lipgloss.NewStyle().Foreground(statusColors[session.StatusIdle])
+	SessionStatusError = lipgloss.NewStyle().Foreground(statusColors[session.StatusError])
+	SessionStatusStopped = lipgloss.NewStyle().Foreground(statusColors[session.StatusStopped])
+	SessionStatusStarting = lipgloss.NewStyle().Foreground(statusColors[session.StatusStarting])
+	SessionStatusQueued = lipgloss.NewStyle().Foreground(statusColors[session.StatusQueued])
+	SessionStatusExited = lipgloss.NewStyle().Foreground(statusColors[session.StatusExited])
+	SessionStatusCrashed = lipgloss.NewStyle().Foreground(statusColors[session.StatusCrashed])
 	SessionStatusSelStyle = lipgloss.NewStyle().Foreground(ColorBg).Background(ColorAccent)
 
 	// Session title styles by state
@@ -508,8 +589,8 @@ func initStyles() {
 	GroupNameStyle = lipgloss.NewStyle().Bold(true).Foreground(ColorCyan)
 	GroupCountStyle = lipgloss.NewStyle().Foreground(ColorText)
 	GroupHotkeyStyle = lipgloss.NewStyle().Foreground(ColorComment)
-	GroupStatusRunning = lipgloss.NewStyle().Foreground(ColorGreen)
-	GroupStatusWaiting = lipgloss.NewStyle().Foreground(ColorYellow)
+	GroupStatusRunning = lipgloss.NewStyle().Foreground(statusColors[session.StatusRunning])
+	GroupStatusWaiting = lipgloss.NewStyle().Foreground(statusColors[session.StatusWaiting])
 
 	// Group selected styles
 	GroupNameSelStyle = lipgloss.NewStyle().Bold(true).Foreground(ColorBg).Background(ColorAccent)
@@ -529,6 +610,7 @@ func initStyles() {
 		"shell":    lipgloss.NewStyle().Foreground(ColorText),
 		"opencode": lipgloss.NewStyle().Foreground(ColorText),
 		"crush":    lipgloss.NewStyle().Foreground(ColorPurple),
+		"goose":    lipgloss.NewStyle().Foreground(ColorGreen),
 	}
 
 	// DefaultToolStyle
@@ -610,6 +692,8 @@ func ToolIcon(tool string) string {
 		return "📝"
 	case "hermes":
 		return "☤"
+	case "goose":
+		return "🪿"
 	case "pi":
 		return IconPi
 	case "shell":
@@ -641,6 +725,8 @@ func ToolColor(tool string) lipgloss.Color {
 		return ColorAccent
 	case "aider":
 		return ColorRed // Red for Aider
+	case "goose":
+		return ColorGreen // Green for goose
 	default:
 		return ColorTextDim // Default gray
 	}
@@ -2,6 +2,8 @@ package ui
 
 import (
 	"testing"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
 )
 
 func TestColorsDefined(t *testing.T) {
@@ -137,3 +139,46 @@ func TestToolStyleCache_ReinitializedOnThemeChange(t *testing.T) {
 	// Reset to dark for other tests
 	InitTheme("dark")
 }
+
+func TestInitThemeDef_OverridesPaletteAndStatuses(t *testing.T) {
+	defer InitTheme("dark")
+
+	InitThemeDef("light", session.ThemeDef{
+		Accent: "#fabd2f",
+		Bg:     "not-a-color",
+		Status: map[string]string{"waiting": "208"},
+	})
+	if GetCurrentTheme() != ThemeLight {
+		t.Fatalf("current theme = %q, want light", GetCurrentTheme())
+	}
+	if ColorAccent != "#fabd2f" {
+		t.Errorf("ColorAccent = %q, want the override", ColorAccent)
+	}
+	if ColorBg != lightColors.Bg {
+		t.Errorf("invalid bg should fall back to the base, got %q", ColorBg)
+	}
+	if got := StatusColor(session.StatusWaiting); got != "208" {
+		t.Errorf("StatusColor(waiting) = %q, want 208", got)
+	}
+	if got := StatusColor(session.StatusRunning); got != lightColors.Green {
+		t.Errorf("StatusColor(running) = %q, want the base green", got)
+	}
+
+	InitTheme("dark")
+	if got := StatusColor(session.StatusWaiting); got != darkColors.Yellow {
+		t.Errorf("StatusColor(waiting) after reset = %q, want dark yellow", got)
+	}
+}
+
+func TestResolvedThemeDef_FillsEveryColor(t *testing.T) {
+	def := ResolvedThemeDef("dark", session.ThemeDef{Red: "#fb4934", Status: map[string]string{"error": "#cc241d"}})
+	if err := session.ValidateThemeDef("export", def); err != nil {
+		t.Fatalf("resolved def is not a valid theme: %v", err)
+	}
+	if got := len(def.Colors()); got != len(session.ThemeColorKeys) {
+		t.Errorf("resolved def has %d colors, want %d", got, len(session.ThemeColorKeys))
+	}
+	if def.Red != "#fb4934" || def.Cyan != string(darkColors.Cyan) || def.Status["error"] != "#cc241d" {
+		t.Errorf("resolved def = %+v", def)
+	}
+}
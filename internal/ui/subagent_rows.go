@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// sessionSubagents returns the sub-agent rows to show under inst: its
+// running Claude sub-agents while the session itself is running. The status
+// check keeps rows from lingering between the turn ending and the next
+// transcript refresh.
+func sessionSubagents(inst *session.Instance) []session.Subagent {
+	if inst == nil || inst.GetStatusThreadSafe() != session.StatusRunning {
+		return nil
+	}
+	return inst.ActiveSubagents()
+}
+
+// subagentSignature fingerprints every session's visible sub-agents, so the
+// tick handler rebuilds the list only when a row appears, ends or moves on
+// to a new step.
+func (h *Home) subagentSignature() string {
+	h.instancesMu.RLock()
+	defer h.instancesMu.RUnlock()
+	var b strings.Builder
+	for _, inst := range h.instances {
+		for _, a := range sessionSubagents(inst) {
+			b.WriteString(inst.ID)
+			b.WriteByte('/')
+			b.WriteString(a.ToolUseID)
+			b.WriteByte('/')
+			b.WriteString(a.Progress)
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// renderSubagentItem renders a display-only sub-agent row under its session:
+// the sub-agent's type and task, its latest step and how long it has run.
+func (h *Home) renderSubagentItem(b *strings.Builder, item session.Item, listWidth int) {
+	a := item.Subagent
+	if a == nil {
+		return
+	}
+	treeStyle := TreeConnectorStyle
+
+	// Same indent as window rows, so sub-agents line up under the parent
+	// session's status bullet.
+	baseIndent := ""
+	if item.Level > 1 {
+		groupIndent := strings.Repeat(treeEmpty, item.Level-2)
+		if item.ParentIsLastInGroup {
+			baseIndent = groupIndent + "   "
+		} else {
+			baseIndent = groupIndent + " " + treeStyle.Render("│") + " "
+		}
+	}
+	treeConnector := subBranch
+	if item.IsLastSubagent {
+		treeConnector = subLast
+	}
+
+	label := DimStyle.Render(a.Label())
+	if a.Type != "" && a.Description != "" {
+		label = lipgloss.NewStyle().Foreground(ColorCyan).Render(a.Type) + DimStyle.Render(" "+a.Description)
+	}
+	row := fmt.Sprintf("%s%s %s %s",
+		strings.Repeat(" ", leftGutterWidth),
+		baseIndent,
+		treeStyle.Render(treeConnector),
+		label,
+	)
+	if !a.StartedAt.IsZero() {
+		row += DimStyle.Render(" " + formatDuration(time.Since(a.StartedAt).Truncate(time.Second)))
+	}
+	if a.Progress != "" {
+		row += DimStyle.Render(" · " + a.Progress)
+	}
+	// Progress text is arbitrary agent output: never let it wrap the row.
+	if listWidth > 2 {
+		row = cellTruncate(row, listWidth-2, "…")
+	}
+	b.WriteString(row)
+	b.WriteString("\n")
+}
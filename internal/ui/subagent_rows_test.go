@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+func TestSkipDividerGlidesPastSubagentRows(t *testing.T) {
+	home := NewHome()
+	a := &session.Instance{ID: "a", Title: "a"}
+	b := &session.Instance{ID: "b", Title: "b"}
+	agent := &session.Subagent{ToolUseID: "toolu_1", Description: "Find TODOs"}
+	home.flatItems = []session.Item{
+		{Type: session.ItemTypeSession, Session: a, Level: 1},
+		{Type: session.ItemTypeSubagent, Subagent: agent, SubagentSessionID: "a", Level: 2},
+		{Type: session.ItemTypeSubagent, Subagent: agent, SubagentSessionID: "a", Level: 2, IsLastSubagent: true},
+		{Type: session.ItemTypeSession, Session: b, Level: 1},
+		{Type: session.ItemTypeSubagent, Subagent: agent, SubagentSessionID: "b", Level: 2, IsLastSubagent: true},
+	}
+
+	home.cursor = 0
+	home.handleMainKey(tea.KeyMsg{Type: tea.KeyDown})
+	if home.cursor != 3 {
+		t.Fatalf("down past two sub-agent rows: cursor=%d, want 3", home.cursor)
+	}
+	home.handleMainKey(tea.KeyMsg{Type: tea.KeyUp})
+	if home.cursor != 0 {
+		t.Fatalf("up past two sub-agent rows: cursor=%d, want 0", home.cursor)
+	}
+	// A sub-agent row at the very end turns the cursor back.
+	home.handleMainKey(tea.KeyMsg{Type: tea.KeyEnd})
+	if home.cursor != 3 {
+		t.Fatalf("end with a trailing sub-agent row: cursor=%d, want 3", home.cursor)
+	}
+	if got := selectableItemIndices(home.flatItems); len(got) != 2 {
+		t.Fatalf("selectable rows = %v, want only the two sessions", got)
+	}
+}
+
+func TestRenderSubagentItem_FitsWidth(t *testing.T) {
+	home := NewHome()
+	item := session.Item{
+		Type: session.ItemTypeSubagent,
+		Subagent: &session.Subagent{
+			Type:        "Explore",
+			Description: "Find TODOs",
+			Progress:    "Grep: " + strings.Repeat("x", 200),
+			StartedAt:   time.Now().Add(-65 * time.Second),
+		},
+		Level:          2,
+		IsLastSubagent: true,
+	}
+	var b strings.Builder
+	home.renderSubagentItem(&b, item, 60)
+	row := strings.TrimSuffix(b.String(), "\n")
+	if w := cellWidth(row); w > 58 {
+		t.Fatalf("row is %d cells wide, want <= 58: %q", w, row)
+	}
+	plain := tmux.StripANSI(row)
+	for _, want := range []string{"└─", "Explore", "Find TODOs", "1m 5s", "Grep: x"} {
+		if !strings.Contains(plain, want) {
+			t.Fatalf("row %q missing %q", plain, want)
+		}
+	}
+}
@@ -0,0 +1,373 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+// taskDispatchedMsg reports the end of a dispatch started from the task
+// queue dialog.
+type taskDispatchedMsg struct {
+	taskID string
+	title  string
+	err    error
+}
+
+// TaskQueueDialog lists the profile's task queue (`agent-deck task`): open
+// tasks by default, every task after tab. Navigation is handled here; the
+// actions (dispatch, done, fail, requeue, remove) by Home.
+type TaskQueueDialog struct {
+	visible       bool
+	width, height int
+	tasks         []*statedb.TaskRow // every task, oldest first
+	titles        map[string]string  // session ID -> title
+	showAll       bool
+	cursor        int // index into the filtered list
+	now           time.Time
+}
+
+// NewTaskQueueDialog creates the dialog (hidden).
+func NewTaskQueueDialog() *TaskQueueDialog {
+	return &TaskQueueDialog{}
+}
+
+// Show opens the dialog on tasks, with titles naming their sessions.
+func (d *TaskQueueDialog) Show(tasks []*statedb.TaskRow, titles map[string]string, now time.Time) {
+	d.visible = true
+	d.showAll = false
+	d.cursor = 0
+	d.SetTasks(tasks, titles, now)
+}
+
+// SetTasks replaces the listed tasks, keeping the selection on the same
+// task when it is still shown.
+func (d *TaskQueueDialog) SetTasks(tasks []*statedb.TaskRow, titles map[string]string, now time.Time) {
+	selected := ""
+	if t := d.Selected(); t != nil {
+		selected = t.ID
+	}
+	d.tasks, d.titles, d.now = tasks, titles, now
+	d.cursor = 0
+	for i, t := range d.filtered() {
+		if t.ID == selected {
+			d.cursor = i
+		}
+	}
+}
+
+// Hide closes the dialog and clears its state.
+func (d *TaskQueueDialog) Hide() {
+	d.visible = false
+	d.tasks = nil
+	d.titles = nil
+	d.cursor = 0
+}
+
+// IsVisible reports whether the dialog is shown (nil-safe).
+func (d *TaskQueueDialog) IsVisible() bool { return d != nil && d.visible }
+
+// SetSize updates the dialog dimensions for centering.
+func (d *TaskQueueDialog) SetSize(w, h int) {
+	if d == nil {
+		return
+	}
+	d.width = w
+	d.height = h
+}
+
+// filtered returns the tasks shown: open ones, or all with showAll.
+func (d *TaskQueueDialog) filtered() []*statedb.TaskRow {
+	if d.showAll {
+		return d.tasks
+	}
+	var open []*statedb.TaskRow
+	for _, t := range d.tasks {
+		if t.Status == session.TaskQueued || t.Status == session.TaskDispatched {
+			open = append(open, t)
+		}
+	}
+	return open
+}
+
+// Selected returns the task under the cursor, or nil.
+func (d *TaskQueueDialog) Selected() *statedb.TaskRow {
+	if !d.IsVisible() {
+		return nil
+	}
+	rows := d.filtered()
+	if d.cursor < 0 || d.cursor >= len(rows) {
+		return nil
+	}
+	return rows[d.cursor]
+}
+
+// Update handles navigation and the open/all filter.
+func (d *TaskQueueDialog) Update(msg tea.KeyMsg) (*TaskQueueDialog, tea.Cmd) {
+	if !d.IsVisible() {
+		return d, nil
+	}
+	n := len(d.filtered())
+	switch msg.String() {
+	case "tab", "shift+tab":
+		selected := d.Selected()
+		d.showAll = !d.showAll
+		d.cursor = 0
+		for i, t := range d.filtered() {
+			if t == selected {
+				d.cursor = i
+			}
+		}
+	case "j", "down":
+		if n > 0 {
+			d.cursor = (d.cursor + 1) % n
+		}
+	case "k", "up":
+		if n > 0 {
+			d.cursor = (d.cursor - 1 + n) % n
+		}
+	case "g", "home":
+		d.cursor = 0
+	case "G", "end":
+		d.cursor = max(n-1, 0)
+	}
+	return d, nil
+}
+
+// taskQueueDialogChrome counts the rows around the task list: border and
+// padding (4), title, counts, blank, two overflow markers, blank, the
+// selected task's text and result (2), blank, footer.
+const taskQueueDialogChrome = 14
+
+// visibleRows returns how many task rows fit on screen.
+func (d *TaskQueueDialog) visibleRows() int {
+	const def = 12
+	if d.height <= 0 {
+		return def
+	}
+	return min(max(d.height-taskQueueDialogChrome, 1), def)
+}
+
+// taskStatusStyle colors a task status.
+func taskStatusStyle(status string) lipgloss.Style {
+	switch status {
+	case session.TaskQueued:
+		return lipgloss.NewStyle().Foreground(ColorYellow)
+	case session.TaskDispatched:
+		return lipgloss.NewStyle().Foreground(ColorCyan)
+	case session.TaskDone:
+		return lipgloss.NewStyle().Foreground(ColorGreen)
+	case session.TaskFailed:
+		return lipgloss.NewStyle().Foreground(ColorRed)
+	}
+	return lipgloss.NewStyle().Foreground(ColorComment)
+}
+
+// View renders the dialog.
+func (d *TaskQueueDialog) View() string {
+	if !d.IsVisible() {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorAccent)
+	selectedStyle := lipgloss.NewStyle().Foreground(ColorAccent).Bold(true)
+	normalStyle := lipgloss.NewStyle().Foreground(ColorText)
+	dimStyle := lipgloss.NewStyle().Foreground(ColorComment)
+	footerStyle := lipgloss.NewStyle().Foreground(ColorComment).Italic(true)
+
+	dialogWidth := fitDialogWidth(84, 40, d.width)
+	innerWidth := max(dialogWidth-4, 1)
+	fit := func(s string) string { return cellTruncate(s, innerWidth, "…") }
+
+	filter := "open"
+	if d.showAll {
+		filter = "all"
+	}
+	rows := d.filtered()
+	var lines []string
+	lines = append(lines, fit(titleStyle.Render("Task Queue")+dimStyle.Render(" · "+filter)))
+	counts := session.TaskCounts(d.tasks)
+	parts := make([]string, 0, len(session.TaskStatuses))
+	for _, st := range session.TaskStatuses {
+		parts = append(parts, taskStatusStyle(st).Render(fmt.Sprintf("%d %s", counts[st], st)))
+	}
+	lines = append(lines, fit(strings.Join(parts, dimStyle.Render(" · "))))
+	lines = append(lines, "")
+
+	if len(rows) == 0 {
+		lines = append(lines, dimStyle.Render("  No tasks. Queue one with: agent-deck task add \"...\""))
+	} else {
+		start, end := windowBounds(d.cursor, len(rows), d.visibleRows())
+		if start > 0 {
+			lines = append(lines, fit(dimStyle.Render(fmt.Sprintf("  ↑ %d more", start))))
+		}
+		for i := start; i < end; i++ {
+			t := rows[i]
+			target := "(any)"
+			if t.SessionID != "" {
+				if target = d.titles[t.SessionID]; target == "" {
+					target = "(removed)"
+				}
+			}
+			age := formatDuration(d.now.Sub(t.UpdatedAt).Round(time.Minute))
+			status := taskStatusStyle(t.Status).Render(fmt.Sprintf("%-10s", t.Status))
+			label := fmt.Sprintf("%-14s %-5s %s", cellTruncate(target, 14, "…"), age, firstTaskLine(t.Text))
+			if i == d.cursor {
+				lines = append(lines, fit("> "+status+" "+selectedStyle.Render(label)))
+			} else {
+				lines = append(lines, fit("  "+status+" "+normalStyle.Render(label)))
+			}
+		}
+		if end < len(rows) {
+			lines = append(lines, fit(dimStyle.Render(fmt.Sprintf("  ↓ %d more", len(rows)-end))))
+		}
+		if t := d.Selected(); t != nil {
+			lines = append(lines, "")
+			lines = append(lines, fit(dimStyle.Render(fmt.Sprintf("%s · from %s · %d attempt(s)", t.ID, t.Source, t.Attempts))))
+			if t.Result != "" {
+				lines = append(lines, fit(dimStyle.Render("result: "+firstTaskLine(t.Result))))
+			}
+		}
+	}
+
+	lines = append(lines, "")
+	footer := "Enter dispatch | c done | f fail | r requeue | x remove | Tab open/all | Esc close"
+	if cellWidth(footer) > innerWidth {
+		footer = "Enter | c | f | r | x | Tab | Esc"
+	}
+	lines = append(lines, fit(footerStyle.Render(footer)))
+
+	box := DialogBoxStyle.Width(dialogWidth).Render(strings.Join(lines, "\n"))
+	return centerInScreen(box, d.width, d.height)
+}
+
+func firstTaskLine(s string) string {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	return s
+}
+
+// taskQueueSnapshot loads every task and the titles of the current sessions.
+func (h *Home) taskQueueSnapshot() ([]*statedb.TaskRow, map[string]string, error) {
+	db := statedb.GetGlobal()
+	if db == nil {
+		return nil, nil, fmt.Errorf("task queue: no state database")
+	}
+	tasks, err := db.LoadTasks()
+	if err != nil {
+		return nil, nil, fmt.Errorf("task queue: %w", err)
+	}
+	h.instancesMu.RLock()
+	titles := make(map[string]string, len(h.instances))
+	for _, inst := range h.instances {
+		titles[inst.ID] = inst.Title
+	}
+	h.instancesMu.RUnlock()
+	return tasks, titles, nil
+}
+
+// openTaskQueue shows the task queue.
+func (h *Home) openTaskQueue() {
+	tasks, titles, err := h.taskQueueSnapshot()
+	if err != nil {
+		h.setError(err)
+		return
+	}
+	if h.taskQueueDialog == nil {
+		h.taskQueueDialog = NewTaskQueueDialog()
+	}
+	h.taskQueueDialog.SetSize(h.width, h.height)
+	h.taskQueueDialog.Show(tasks, titles, time.Now())
+}
+
+// reloadTaskQueue refreshes an open task queue dialog.
+func (h *Home) reloadTaskQueue() {
+	if !h.taskQueueDialog.IsVisible() {
+		return
+	}
+	tasks, titles, err := h.taskQueueSnapshot()
+	if err != nil {
+		h.setError(err)
+		return
+	}
+	h.taskQueueDialog.SetTasks(tasks, titles, time.Now())
+}
+
+// dispatchQueuedTask sends t to its session or, when it has none, to the
+// session selected in the list. The send runs in the background.
+func (h *Home) dispatchQueuedTask(t *statedb.TaskRow) tea.Cmd {
+	if t.Status != session.TaskQueued {
+		h.setError(fmt.Errorf("task %s is %s, not queued", t.ID, t.Status))
+		return nil
+	}
+	var inst *session.Instance
+	if t.SessionID != "" {
+		inst = h.getInstanceByID(t.SessionID)
+	} else if h.cursor < len(h.flatItems) && h.flatItems[h.cursor].Type == session.ItemTypeSession {
+		inst = h.flatItems[h.cursor].Session
+	}
+	if inst == nil {
+		h.setError(fmt.Errorf("task %s has no session: select one in the list first", t.ID))
+		return nil
+	}
+	db := statedb.GetGlobal()
+	if db == nil {
+		return nil
+	}
+	profile := h.profile
+	task := *t
+	return func() tea.Msg {
+		err := session.DispatchTask(db, profile, &task, inst, time.Now(), func(instanceID, message string) error {
+			return session.SendSessionMessageReliable(profile, instanceID, message)
+		})
+		return taskDispatchedMsg{taskID: task.ID, title: inst.Title, err: err}
+	}
+}
+
+// handleTaskQueueDialogKey closes the dialog on esc/q, runs the task
+// actions and routes navigation to it.
+func (h *Home) handleTaskQueueDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	d := h.taskQueueDialog
+	t := d.Selected()
+	db := statedb.GetGlobal()
+	var err error
+	switch msg.String() {
+	case "esc", "q":
+		d.Hide()
+		return h, nil
+	case "enter":
+		if t == nil {
+			return h, nil
+		}
+		return h, h.dispatchQueuedTask(t)
+	case "c":
+		if t != nil && db != nil {
+			err = session.FinishTask(db, t, session.TaskDone, "marked done in the TUI", time.Now())
+		}
+	case "f":
+		if t != nil && db != nil {
+			err = session.FinishTask(db, t, session.TaskFailed, "marked failed in the TUI", time.Now())
+		}
+	case "r":
+		if t != nil && db != nil {
+			err = session.RequeueTask(db, t, time.Now())
+		}
+	case "x":
+		if t != nil && db != nil {
+			_, err = db.DeleteTask(t.ID)
+		}
+	default:
+		d.Update(msg)
+		return h, nil
+	}
+	if err != nil {
+		h.setError(err)
+	}
+	h.reloadTaskQueue()
+	return h, nil
+}
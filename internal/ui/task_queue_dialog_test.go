@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/asheshgoplani/agent-deck/internal/statedb"
+)
+
+func TestTaskQueueDialog_FiltersOpenTasks(t *testing.T) {
+	d := NewTaskQueueDialog()
+	d.SetSize(120, 40)
+	now := time.Now()
+	tasks := []*statedb.TaskRow{
+		{ID: "task-aaaa", Text: "fix navbar", Status: "dispatched", SessionID: "s1", Source: "user", UpdatedAt: now},
+		{ID: "task-bbbb", Text: "bump deps", Status: "done", Result: "bumped", Source: "user", UpdatedAt: now},
+		{ID: "task-cccc", Text: "triage e2e\nlong details", Status: "queued", Source: "conductor", UpdatedAt: now},
+	}
+	d.Show(tasks, map[string]string{"s1": "web"}, now)
+
+	view := d.View()
+	for _, want := range []string{"Task Queue", "· open", "1 queued", "1 done", "web", "fix navbar", "(any)", "triage e2e"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "bump deps") || strings.Contains(view, "long details") {
+		t.Errorf("open view shows a done task or a second text line:\n%s", view)
+	}
+
+	d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	if got := d.Selected(); got == nil || got.ID != "task-cccc" {
+		t.Fatalf("selected = %+v, want task-cccc", got)
+	}
+	// Tab shows every task and keeps the selection.
+	d.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if got := d.Selected(); got == nil || got.ID != "task-cccc" {
+		t.Fatalf("selected after tab = %+v, want task-cccc", got)
+	}
+	if view := d.View(); !strings.Contains(view, "bump deps") || !strings.Contains(view, "· all") {
+		t.Errorf("all view:\n%s", view)
+	}
+}
@@ -0,0 +1,581 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/asheshgoplani/agent-deck/internal/progress"
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+const (
+	// taskLogLimit caps the log lines kept per task; older lines are dropped.
+	taskLogLimit = 200
+	// taskHistoryLimit caps how many finished tasks the panel remembers.
+	taskHistoryLimit = 20
+)
+
+// taskStatus is the lifecycle state of a background task.
+type taskStatus int
+
+const (
+	taskRunning taskStatus = iota
+	taskDone
+	taskFailed
+	taskCancelled
+)
+
+// Task is one long-running operation (group restart, worktree creation)
+// shown in the background tasks panel. The goroutine doing the work reports
+// through Report and ends the task with Finish; everything else reads
+// snapshots through TaskManager.
+type Task struct {
+	m      *TaskManager
+	id     int
+	title  string
+	cancel context.CancelFunc // nil when the operation cannot be cancelled
+
+	// Guarded by m.mu.
+	status    taskStatus
+	cancelled bool // the user asked to cancel
+	last      progress.Update
+	log       []string
+	err       error
+	started   time.Time
+	finished  time.Time
+}
+
+// taskSnapshot is a copy of a Task's state for rendering.
+type taskSnapshot struct {
+	id       int
+	title    string
+	status   taskStatus
+	last     progress.Update
+	log      []string
+	err      error
+	started  time.Time
+	finished time.Time
+}
+
+// TaskManager tracks background tasks and wakes the UI when any of them
+// changes. All methods are safe for concurrent use; read methods are
+// nil-safe so tests can build a Home without one.
+type TaskManager struct {
+	mu      sync.Mutex
+	tasks   []*Task
+	nextID  int
+	changed chan struct{}
+}
+
+// NewTaskManager creates an empty task manager.
+func NewTaskManager() *TaskManager {
+	return &TaskManager{changed: make(chan struct{}, 1)}
+}
+
+// Start registers a running task. cancel, when non-nil, is called if the
+// user cancels the task from the panel; the operation must watch the
+// matching context and return promptly. On a nil manager the task is
+// tracked by a throwaway manager, so callers never need a nil check.
+func (m *TaskManager) Start(title string, cancel context.CancelFunc) *Task {
+	if m == nil {
+		m = NewTaskManager()
+	}
+	m.mu.Lock()
+	m.nextID++
+	t := &Task{m: m, id: m.nextID, title: title, cancel: cancel, started: time.Now()}
+	m.tasks = append(m.tasks, t)
+	m.pruneLocked()
+	m.mu.Unlock()
+	m.notify()
+	return t
+}
+
+// Changed returns the channel signalled (coalesced) after any task update.
+func (m *TaskManager) Changed() <-chan struct{} { return m.changed }
+
+func (m *TaskManager) notify() {
+	select {
+	case m.changed <- struct{}{}:
+	default:
+	}
+}
+
+// pruneLocked drops the oldest finished tasks beyond taskHistoryLimit.
+func (m *TaskManager) pruneLocked() {
+	finished := 0
+	for _, t := range m.tasks {
+		if t.status != taskRunning {
+			finished++
+		}
+	}
+	kept := m.tasks[:0]
+	for _, t := range m.tasks {
+		if t.status != taskRunning && finished > taskHistoryLimit {
+			finished--
+			continue
+		}
+		kept = append(kept, t)
+	}
+	m.tasks = kept
+}
+
+// Running returns the number of tasks still in progress.
+func (m *TaskManager) Running() int {
+	if m == nil {
+		return 0
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for _, t := range m.tasks {
+		if t.status == taskRunning {
+			n++
+		}
+	}
+	return n
+}
+
+// Len returns the number of tasks, running or finished.
+func (m *TaskManager) Len() int {
+	if m == nil {
+		return 0
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.tasks)
+}
+
+// snapshot returns copies of all tasks, newest first.
+func (m *TaskManager) snapshot() []taskSnapshot {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]taskSnapshot, 0, len(m.tasks))
+	for i := len(m.tasks) - 1; i >= 0; i-- {
+		t := m.tasks[i]
+		out = append(out, taskSnapshot{
+			id:       t.id,
+			title:    t.title,
+			status:   t.status,
+			last:     t.last,
+			log:      append([]string(nil), t.log...),
+			err:      t.err,
+			started:  t.started,
+			finished: t.finished,
+		})
+	}
+	return out
+}
+
+// Cancel asks the running task id to stop. It reports whether the task was
+// running and cancellable; the task itself records the outcome in Finish.
+func (m *TaskManager) Cancel(id int) bool {
+	m.mu.Lock()
+	var cancel context.CancelFunc
+	for _, t := range m.tasks {
+		if t.id == id && t.status == taskRunning && t.cancel != nil {
+			cancel = t.cancel
+			t.cancelled = true
+			t.log = appendTaskLog(t.log, "cancel requested")
+		}
+	}
+	m.mu.Unlock()
+	if cancel == nil {
+		return false
+	}
+	cancel()
+	m.notify()
+	return true
+}
+
+// ClearFinished forgets every task that is no longer running.
+func (m *TaskManager) ClearFinished() {
+	m.mu.Lock()
+	kept := m.tasks[:0]
+	for _, t := range m.tasks {
+		if t.status == taskRunning {
+			kept = append(kept, t)
+		}
+	}
+	m.tasks = kept
+	m.mu.Unlock()
+	m.notify()
+}
+
+// Report records a progress update; its message is appended to the task
+// log. Task.Report satisfies progress.Func.
+func (t *Task) Report(u progress.Update) {
+	t.m.mu.Lock()
+	if t.status == taskRunning {
+		// Byte transfers report on every read; log only the first one.
+		if u.Message != "" && !(u.Bytes && u.Message == t.last.Message) {
+			t.log = appendTaskLog(t.log, u.Message)
+		}
+		t.last = u
+	}
+	t.m.mu.Unlock()
+	t.m.notify()
+}
+
+// Finish ends the task. A context.Canceled error (or any error after the
+// user cancelled) marks it cancelled rather than failed.
+func (t *Task) Finish(err error) {
+	t.m.mu.Lock()
+	if t.status == taskRunning {
+		t.finished = time.Now()
+		t.err = err
+		switch {
+		case errors.Is(err, context.Canceled), err != nil && t.cancelled:
+			t.status = taskCancelled
+		case err != nil:
+			t.status = taskFailed
+			t.log = appendTaskLog(t.log, "error: "+err.Error())
+		default:
+			t.status = taskDone
+		}
+		t.m.pruneLocked()
+	}
+	t.m.mu.Unlock()
+	t.m.notify()
+}
+
+func appendTaskLog(log []string, line string) []string {
+	log = append(log, line)
+	if len(log) > taskLogLimit {
+		log = log[len(log)-taskLogLimit:]
+	}
+	return log
+}
+
+// tasksChangedMsg is produced by listenForTaskChanges after any task update.
+type tasksChangedMsg struct{}
+
+// listenForTaskChanges waits for the next task update. Must be re-issued
+// after each tasksChangedMsg to keep listening.
+func listenForTaskChanges(m *TaskManager) tea.Cmd {
+	if m == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		<-m.Changed()
+		return tasksChangedMsg{}
+	}
+}
+
+// TasksPanel lists background tasks with their progress. j/k select, Enter
+// toggles the selected task's log, x cancels it, c clears finished tasks.
+type TasksPanel struct {
+	visible       bool
+	width, height int
+	cursor        int
+	showLog       bool
+}
+
+// NewTasksPanel creates the panel (hidden).
+func NewTasksPanel() *TasksPanel {
+	return &TasksPanel{}
+}
+
+// Show opens the panel on the newest task.
+func (p *TasksPanel) Show() {
+	p.visible = true
+	p.cursor = 0
+	p.showLog = false
+}
+
+// Hide closes the panel.
+func (p *TasksPanel) Hide() { p.visible = false }
+
+// IsVisible reports whether the panel is open.
+func (p *TasksPanel) IsVisible() bool { return p != nil && p.visible }
+
+// SetSize updates the panel dimensions for centering. Nil-safe like
+// DuplicatesDialog.SetSize.
+func (p *TasksPanel) SetSize(w, h int) {
+	if p == nil {
+		return
+	}
+	p.width = w
+	p.height = h
+}
+
+// Update handles a key while the panel is visible.
+func (p *TasksPanel) Update(msg tea.KeyMsg, m *TaskManager) *TasksPanel {
+	if !p.IsVisible() {
+		return p
+	}
+	tasks := m.snapshot()
+	p.cursor = min(p.cursor, max(len(tasks)-1, 0))
+	switch msg.String() {
+	case "esc", "q":
+		p.Hide()
+	case "j", "down":
+		if len(tasks) > 0 {
+			p.cursor = (p.cursor + 1) % len(tasks)
+		}
+	case "k", "up":
+		if len(tasks) > 0 {
+			p.cursor = (p.cursor - 1 + len(tasks)) % len(tasks)
+		}
+	case "enter", "l":
+		p.showLog = !p.showLog
+	case "x":
+		if p.cursor < len(tasks) {
+			m.Cancel(tasks[p.cursor].id)
+		}
+	case "c":
+		m.ClearFinished()
+		p.cursor = 0
+	}
+	return p
+}
+
+// taskStatusIcon renders a task's state; running tasks get the spinner.
+func taskStatusIcon(t taskSnapshot, frame int) string {
+	switch t.status {
+	case taskRunning:
+		return lipgloss.NewStyle().Foreground(ColorAccent).Render(spinnerFrame(frame))
+	case taskDone:
+		return lipgloss.NewStyle().Foreground(ColorGreen).Render("✓")
+	case taskCancelled:
+		return lipgloss.NewStyle().Foreground(ColorYellow).Render("⊘")
+	default:
+		return lipgloss.NewStyle().Foreground(ColorRed).Render("✗")
+	}
+}
+
+// taskProgressText summarizes a task's latest update and elapsed time.
+func taskProgressText(t taskSnapshot) string {
+	end := t.finished
+	if t.status == taskRunning {
+		end = time.Now()
+	}
+	elapsed := end.Sub(t.started).Round(time.Second)
+	var parts []string
+	switch t.status {
+	case taskRunning:
+		if t.last.Message != "" || t.last.Total > 0 {
+			parts = append(parts, t.last.String())
+		} else {
+			parts = append(parts, "starting…")
+		}
+	case taskDone:
+		parts = append(parts, "done")
+	case taskCancelled:
+		parts = append(parts, "cancelled")
+	default:
+		parts = append(parts, "failed: "+t.err.Error())
+	}
+	parts = append(parts, elapsed.String())
+	return strings.Join(parts, " · ")
+}
+
+// spinnerFrame returns the braille spinner glyph for an animation frame.
+func spinnerFrame(frame int) string {
+	frames := []string{"⣾", "⣽", "⣻", "⢿", "⡿", "⣟", "⣯", "⣷"}
+	return frames[frame%len(frames)]
+}
+
+// taskPanelLogLines is how many trailing log lines the panel shows.
+const taskPanelLogLines = 12
+
+// View renders the panel. frame drives the running-task spinner.
+func (p *TasksPanel) View(m *TaskManager, frame int) string {
+	if !p.IsVisible() {
+		return ""
+	}
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorAccent)
+	selectedStyle := lipgloss.NewStyle().Foreground(ColorAccent).Bold(true)
+	normalStyle := lipgloss.NewStyle().Foreground(ColorText)
+	dimStyle := lipgloss.NewStyle().Foreground(ColorTextDim)
+	footerStyle := lipgloss.NewStyle().Foreground(ColorComment).Italic(true)
+
+	dialogWidth := fitDialogWidth(72, 40, p.width)
+	tasks := m.snapshot()
+	cursor := min(p.cursor, max(len(tasks)-1, 0))
+
+	var lines []string
+	lines = append(lines, titleStyle.Render(fmt.Sprintf("Background Tasks (%d running)", m.Running())))
+	lines = append(lines, "")
+	if len(tasks) == 0 {
+		lines = append(lines, dimStyle.Render("No background tasks."))
+	}
+	for i, t := range tasks {
+		label := taskStatusIcon(t, frame) + " "
+		if i == cursor {
+			lines = append(lines, "> "+label+selectedStyle.Render(t.title))
+		} else {
+			lines = append(lines, "  "+label+normalStyle.Render(t.title))
+		}
+		lines = append(lines, "    "+dimStyle.Render(truncatePath(taskProgressText(t), dialogWidth-8)))
+		if i == cursor && p.showLog {
+			log := t.log
+			if len(log) > taskPanelLogLines {
+				log = log[len(log)-taskPanelLogLines:]
+			}
+			if len(log) == 0 {
+				lines = append(lines, "      "+dimStyle.Render("(no output)"))
+			}
+			for _, l := range log {
+				lines = append(lines, "      "+dimStyle.Render(truncatePath(l, dialogWidth-10)))
+			}
+		}
+	}
+	lines = append(lines, "")
+	lines = append(lines, footerStyle.Render("Enter log | x cancel | c clear finished | j/k select | Esc close"))
+
+	box := DialogBoxStyle.Width(dialogWidth).Render(strings.Join(lines, "\n"))
+	return centerInScreen(box, p.width, p.height)
+}
+
+// openTasksPanel shows the background tasks panel.
+func (h *Home) openTasksPanel() {
+	if h.tasks.Len() == 0 {
+		h.setError(fmt.Errorf("no background tasks"))
+		return
+	}
+	if h.tasksPanel == nil {
+		h.tasksPanel = NewTasksPanel()
+	}
+	h.tasksPanel.SetSize(h.width, h.height)
+	h.tasksPanel.Show()
+}
+
+// handleTasksPanelKey routes a key to the tasks panel.
+func (h *Home) handleTasksPanelKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	h.tasksPanel = h.tasksPanel.Update(msg, h.tasks)
+	return h, nil
+}
+
+// renderTasksIndicator is the status line shown under the help bar while
+// background tasks run: the newest task's progress plus the panel hotkey.
+func (h *Home) renderTasksIndicator() string {
+	running := h.tasks.Running()
+	if running == 0 {
+		return ""
+	}
+	var current taskSnapshot
+	for _, t := range h.tasks.snapshot() {
+		if t.status == taskRunning {
+			current = t
+			break
+		}
+	}
+	text := fmt.Sprintf("%s %s: %s", spinnerFrame(h.animationFrame), current.title, taskProgressText(current))
+	if running > 1 {
+		text += fmt.Sprintf(" (+%d more)", running-1)
+	}
+	if key := h.actionKey(hotkeyTasksPanel); key != "" {
+		text += fmt.Sprintf(" [%s]", key)
+	}
+	return lipgloss.NewStyle().Foreground(ColorAccent).Render(truncatePath(text, max(h.width-1, 10)))
+}
+
+// groupRestartDoneMsg carries the per-session results of a group (or
+// marked-sessions) restart task; cancelled sessions are absent from results.
+type groupRestartDoneMsg struct {
+	scope     string // where the sessions came from, for the summary: "in 'work'"
+	results   []sessionRestartedMsg
+	cancelled bool
+}
+
+// restartGroup restarts every restartable session in groupPath (and its
+// subgroups) one at a time as a cancellable background task.
+func (h *Home) restartGroup(groupPath string) tea.Cmd {
+	h.instancesMu.RLock()
+	var targets []*session.Instance
+	for _, inst := range h.instances {
+		if inst.IsArchived() || !inst.CanRestart() || h.hasActiveAnimation(inst.ID) {
+			continue
+		}
+		if inst.GroupPath == groupPath || strings.HasPrefix(inst.GroupPath, groupPath+"/") {
+			targets = append(targets, inst)
+		}
+	}
+	h.instancesMu.RUnlock()
+	if len(targets) == 0 {
+		h.setError(fmt.Errorf("no sessions to restart in group '%s'", groupPath))
+		return nil
+	}
+	return h.restartSessionsTask(fmt.Sprintf("Restart group %s", groupPath), fmt.Sprintf("in '%s'", groupPath), targets)
+}
+
+// restartSessionsTask restarts targets one at a time as a cancellable
+// background task titled title; scope describes them in the summary.
+func (h *Home) restartSessionsTask(title, scope string, targets []*session.Instance) tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	task := h.tasks.Start(title, cancel)
+	cmds := make([]tea.Cmd, len(targets))
+	titles := make([]string, len(targets))
+	// Stopped sessions are started fresh; their cosmetic tmux options are
+	// applied together once the run ends.
+	batch := session.NewStartBatch()
+	for i, inst := range targets {
+		cmds[i] = h.restartSessionBatched(inst, batch)
+		titles[i] = inst.Title
+	}
+	return func() tea.Msg {
+		defer cancel()
+		defer batch.Flush()
+		done := groupRestartDoneMsg{scope: scope}
+		failed := 0
+		for i, restart := range cmds {
+			if ctx.Err() != nil {
+				done.cancelled = true
+				break
+			}
+			task.Report(progress.Update{Done: int64(i), Total: int64(len(cmds)), Message: "Restarting " + titles[i]})
+			res, _ := restart().(sessionRestartedMsg)
+			if res.err != nil {
+				failed++
+				task.Report(progress.Update{Done: int64(i + 1), Total: int64(len(cmds)), Message: fmt.Sprintf("%s: %v", titles[i], res.err)})
+			}
+			done.results = append(done.results, res)
+		}
+		switch {
+		case done.cancelled:
+			task.Finish(context.Canceled)
+		case failed > 0:
+			task.Finish(fmt.Errorf("%d of %d sessions failed to restart", failed, len(cmds)))
+		default:
+			task.Report(progress.Update{Done: int64(len(cmds)), Total: int64(len(cmds)), Message: "Restarted all sessions"})
+			task.Finish(nil)
+		}
+		return done
+	}
+}
+
+// applyGroupRestart feeds each session result through the single-restart
+// handler and summarizes the run in the status bar.
+func (h *Home) applyGroupRestart(msg groupRestartDoneMsg) tea.Cmd {
+	var cmds []tea.Cmd
+	restarted := 0
+	for _, res := range msg.results {
+		if res.err == nil {
+			restarted++
+		}
+		_, cmd := h.Update(res)
+		cmds = append(cmds, cmd)
+	}
+	summary := fmt.Sprintf("restarted %d session(s) %s", restarted, msg.scope)
+	if failed := len(msg.results) - restarted; failed > 0 {
+		summary += fmt.Sprintf(", %d failed", failed)
+		if key := h.actionKey(hotkeyTasksPanel); key != "" {
+			summary += fmt.Sprintf(" (%s for details)", key)
+		}
+	}
+	if msg.cancelled {
+		summary += " before cancel"
+	}
+	h.setError(fmt.Errorf("%s", summary))
+	return tea.Batch(cmds...)
+}
@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/asheshgoplani/agent-deck/internal/progress"
+)
+
+func keyRunes(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+func TestTaskManager_LifecycleAndCancel(t *testing.T) {
+	m := NewTaskManager()
+	ctx, cancel := context.WithCancel(context.Background())
+	restart := m.Start("Restart group work", cancel)
+	worktree := m.Start("Create worktree feat", nil)
+	<-m.Changed()
+
+	restart.Report(progress.Update{Done: 1, Total: 3, Message: "Restarting api"})
+	worktree.Report(progress.Update{Message: "npm install"})
+	if got := m.Running(); got != 2 {
+		t.Fatalf("Running = %d, want 2", got)
+	}
+	tasks := m.snapshot()
+	if tasks[0].title != "Create worktree feat" || tasks[1].last.String() != "[1/3] Restarting api" {
+		t.Fatalf("snapshot must list newest first with the latest update: %+v", tasks)
+	}
+
+	if m.Cancel(worktree.id) {
+		t.Fatal("a task without a cancel func must not report cancellation")
+	}
+	if !m.Cancel(restart.id) || ctx.Err() == nil {
+		t.Fatal("Cancel must call the task's cancel func")
+	}
+	// The operation notices and ends with whatever error it hit.
+	restart.Finish(errors.New("interrupted mid-restart"))
+	worktree.Finish(fmt.Errorf("setup script: %w", errors.New("exit 1")))
+
+	tasks = m.snapshot()
+	if tasks[1].status != taskCancelled || tasks[0].status != taskFailed {
+		t.Fatalf("statuses = %v/%v, want cancelled/failed", tasks[1].status, tasks[0].status)
+	}
+	if log := strings.Join(tasks[0].log, "\n"); !strings.Contains(log, "npm install") || !strings.Contains(log, "error: setup script: exit 1") {
+		t.Fatalf("worktree log = %q", log)
+	}
+	restart.Report(progress.Update{Message: "late"})
+	if m.snapshot()[1].last.Message == "late" {
+		t.Fatal("reports after Finish must be ignored")
+	}
+
+	m.ClearFinished()
+	if m.Len() != 0 || m.Running() != 0 {
+		t.Fatalf("ClearFinished left %d tasks", m.Len())
+	}
+}
+
+func TestTaskManager_BoundsLogAndHistory(t *testing.T) {
+	m := NewTaskManager()
+	task := m.Start("download", nil)
+	for i := range taskLogLimit + 5 {
+		task.Report(progress.Update{Message: fmt.Sprintf("line %d", i)})
+	}
+	// Byte transfers log their message once, not once per read.
+	for i := range 10 {
+		task.Report(progress.Update{Done: int64(i), Total: 10, Bytes: true, Message: "Downloading x"})
+	}
+	log := m.snapshot()[0].log
+	if len(log) != taskLogLimit || log[len(log)-1] != "Downloading x" || log[len(log)-2] != fmt.Sprintf("line %d", taskLogLimit+4) {
+		t.Fatalf("log len=%d tail=%q", len(log), log[len(log)-2:])
+	}
+	task.Finish(nil)
+
+	for i := range taskHistoryLimit + 3 {
+		m.Start(fmt.Sprintf("t%d", i), nil).Finish(nil)
+	}
+	running := m.Start("still running", nil)
+	if got := m.Len(); got != taskHistoryLimit+1 {
+		t.Fatalf("Len = %d, want %d finished + 1 running", got, taskHistoryLimit)
+	}
+	running.Finish(nil)
+
+	var nilManager *TaskManager
+	if nilManager.Running() != 0 || nilManager.Len() != 0 || nilManager.snapshot() != nil {
+		t.Fatal("read methods must be nil-safe")
+	}
+	nilManager.Start("detached", nil).Finish(nil)
+}
+
+func TestHome_TasksPanelAndIndicator(t *testing.T) {
+	h := NewHome()
+	h.width, h.height = 100, 40
+
+	h.openTasksPanel()
+	if h.tasksPanel.IsVisible() || h.err == nil {
+		t.Fatal("panel must not open without tasks")
+	}
+	if h.renderTasksIndicator() != "" {
+		t.Fatal("no indicator while nothing runs")
+	}
+
+	_, cancel := context.WithCancel(context.Background())
+	task := h.tasks.Start("Restart group work", cancel)
+	task.Report(progress.Update{Done: 2, Total: 5, Message: "Restarting api"})
+	if got := h.renderTasksIndicator(); !strings.Contains(got, "Restart group work: [2/5] Restarting api") || !strings.Contains(got, "[alt+t]") {
+		t.Fatalf("indicator = %q", got)
+	}
+
+	h.openTasksPanel()
+	if !h.hasModalVisible() {
+		t.Fatal("the tasks panel must count as a modal")
+	}
+	view := h.tasksPanel.View(h.tasks, 0)
+	if !strings.Contains(view, "Background Tasks (1 running)") || strings.Count(view, "Restarting api") != 1 {
+		t.Fatalf("collapsed view = %s", view)
+	}
+	h.handleTasksPanelKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if view := h.tasksPanel.View(h.tasks, 0); strings.Count(view, "Restarting api") != 2 {
+		t.Fatalf("enter must expand the log under the progress line: %s", view)
+	}
+	h.handleTasksPanelKey(keyRunes("x"))
+	task.Finish(context.Canceled)
+	if got := h.tasks.snapshot()[0].status; got != taskCancelled {
+		t.Fatalf("status after x = %v, want cancelled", got)
+	}
+	if h.renderTasksIndicator() != "" {
+		t.Fatal("indicator must disappear once nothing runs")
+	}
+	h.handleTasksPanelKey(keyRunes("c"))
+	h.handleTasksPanelKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if h.tasksPanel.IsVisible() || h.tasks.Len() != 0 {
+		t.Fatal("c must clear finished tasks and esc close the panel")
+	}
+}
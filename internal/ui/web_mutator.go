@@ -8,7 +8,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/asheshgoplani/agent-deck/internal/git"
 	"github.com/asheshgoplani/agent-deck/internal/session"
 	"github.com/asheshgoplani/agent-deck/internal/vcs"
 	"github.com/asheshgoplani/agent-deck/internal/vcsbackend"
@@ -184,6 +183,28 @@ func (m *WebMutator) RestartSession(id string) error {
 	return inst.Restart()
 }
 
+// SendMessage types message into a running session's pane and presses Enter.
+// Delivery is composer-guarded and verified like conductor sends, so it can
+// block for several seconds; the headless transaction is released first.
+func (m *WebMutator) SendMessage(id, message string) error {
+	unlock, err := m.beginHeadlessTx()
+	if err != nil {
+		return err
+	}
+	m.h.instancesMu.RLock()
+	inst := m.h.instanceByID[id]
+	m.h.instancesMu.RUnlock()
+	unlock()
+	if inst == nil {
+		return fmt.Errorf("session not found: %s", id)
+	}
+	tmuxSess := inst.GetTmuxSession()
+	if tmuxSess == nil || !tmuxSess.Exists() {
+		return fmt.Errorf("session is not running: %s", id)
+	}
+	return deliverToConductorPane(tmuxSess, message)
+}
+
 // DeleteSession kills a session and removes it from persistent storage.
 // Before removal, the instance is pushed onto the web undo stack so a
 // subsequent UndoDelete (POST /api/sessions/undelete) can restore it.
@@ -590,7 +611,7 @@ func (m *WebMutator) FinishWorktree(id string, opts web.WorktreeFinishOptions) (
 	}
 
 	if !opts.Force {
-		dirty, dErr := git.HasUncommittedChanges(worktreePath)
+		dirty, dErr := backend.HasUncommittedChanges(worktreePath)
 		if dErr != nil {
 			if _, statErr := os.Stat(worktreePath); os.IsNotExist(statErr) {
 				dirty = false
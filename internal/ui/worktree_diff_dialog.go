@@ -0,0 +1,412 @@
+package ui
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/asheshgoplani/agent-deck/internal/git"
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// worktreeDiffLoadedMsg delivers the diff computed for WorktreeDiffDialog.
+type worktreeDiffLoadedMsg struct {
+	sessionID string
+	diff      *git.BranchDiff
+	dirty     bool
+	err       error
+}
+
+// diffTreeRow is one line of the file tree: a directory heading or a file.
+type diffTreeRow struct {
+	depth int
+	label string
+	file  int // index into files, -1 for a directory
+}
+
+// WorktreeDiffDialog reviews a worktree session's branch against its base
+// before merging: a file tree on the left, the selected file's patch on the
+// right. Navigation is handled here; m (merge) and esc by Home.
+type WorktreeDiffDialog struct {
+	visible       bool
+	width, height int
+
+	sessionID    string
+	sessionTitle string
+	loading      bool
+	errMsg       string
+	dirty        bool
+	diff         *git.BranchDiff
+
+	files  []git.DiffFile // sorted by path
+	rows   []diffTreeRow
+	cursor int // index into files
+	scroll int // first patch line shown
+	// patchFocus routes j/k to the patch instead of the file tree.
+	patchFocus bool
+}
+
+// NewWorktreeDiffDialog creates the dialog (hidden).
+func NewWorktreeDiffDialog() *WorktreeDiffDialog {
+	return &WorktreeDiffDialog{}
+}
+
+// Show opens the dialog in its loading state; SetDiff fills it in.
+func (d *WorktreeDiffDialog) Show(sessionID, sessionTitle string) {
+	*d = WorktreeDiffDialog{width: d.width, height: d.height}
+	d.visible = true
+	d.sessionID = sessionID
+	d.sessionTitle = sessionTitle
+	d.loading = true
+}
+
+// SetDiff installs the loaded diff, or the error that prevented it.
+func (d *WorktreeDiffDialog) SetDiff(diff *git.BranchDiff, dirty bool, err error) {
+	d.loading = false
+	if err != nil {
+		d.errMsg = err.Error()
+		return
+	}
+	d.diff = diff
+	d.dirty = dirty
+	d.files = append([]git.DiffFile(nil), diff.Files...)
+	sort.Slice(d.files, func(i, j int) bool { return d.files[i].Path < d.files[j].Path })
+	d.rows = buildDiffTree(d.files)
+	d.cursor = 0
+	d.scroll = 0
+}
+
+// buildDiffTree lays sorted files out as a directory tree: each directory
+// gets a heading row the first time a file under it appears.
+func buildDiffTree(files []git.DiffFile) []diffTreeRow {
+	var rows []diffTreeRow
+	var prev []string
+	for i, f := range files {
+		dir := path.Dir(f.Path)
+		var parts []string
+		if dir != "." {
+			parts = strings.Split(dir, "/")
+		}
+		common := 0
+		for common < len(parts) && common < len(prev) && parts[common] == prev[common] {
+			common++
+		}
+		for depth := common; depth < len(parts); depth++ {
+			rows = append(rows, diffTreeRow{depth: depth, label: parts[depth] + "/", file: -1})
+		}
+		rows = append(rows, diffTreeRow{depth: len(parts), label: path.Base(f.Path), file: i})
+		prev = parts
+	}
+	return rows
+}
+
+// Hide closes the dialog and clears its state.
+func (d *WorktreeDiffDialog) Hide() {
+	*d = WorktreeDiffDialog{width: d.width, height: d.height}
+}
+
+// IsVisible reports whether the dialog is shown.
+func (d *WorktreeDiffDialog) IsVisible() bool { return d != nil && d.visible }
+
+// SetSize updates the dialog dimensions.
+func (d *WorktreeDiffDialog) SetSize(w, h int) {
+	if d == nil {
+		return
+	}
+	d.width = w
+	d.height = h
+}
+
+// Ready reports whether a diff is loaded with something to merge.
+func (d *WorktreeDiffDialog) Ready() bool {
+	return d.diff != nil && d.diff.Commits > 0
+}
+
+// Base returns the branch the diff is against, or "" before it loads.
+func (d *WorktreeDiffDialog) Base() string {
+	if d.diff == nil {
+		return ""
+	}
+	return d.diff.Base
+}
+
+// selectedPatch returns the selected file's patch lines.
+func (d *WorktreeDiffDialog) selectedPatch() []string {
+	if d.cursor < 0 || d.cursor >= len(d.files) {
+		return nil
+	}
+	return strings.Split(d.files[d.cursor].Patch, "\n")
+}
+
+// bodyRows is how many tree/patch rows fit: the screen minus border and
+// padding (4), title, summary, blank, blank, footer and a margin.
+func (d *WorktreeDiffDialog) bodyRows() int {
+	if d.height <= 0 {
+		return 20
+	}
+	if rows := d.height - 11; rows > 3 {
+		return rows
+	}
+	return 3
+}
+
+func (d *WorktreeDiffDialog) selectFile(i int) {
+	if len(d.files) == 0 {
+		return
+	}
+	d.cursor = (i + len(d.files)) % len(d.files)
+	d.scroll = 0
+}
+
+func (d *WorktreeDiffDialog) scrollBy(n int) {
+	maxScroll := len(d.selectedPatch()) - d.bodyRows()
+	d.scroll += n
+	if d.scroll > maxScroll {
+		d.scroll = maxScroll
+	}
+	if d.scroll < 0 {
+		d.scroll = 0
+	}
+}
+
+// Update handles navigation keys.
+func (d *WorktreeDiffDialog) Update(msg tea.KeyMsg) {
+	if !d.IsVisible() || len(d.files) == 0 {
+		return
+	}
+	half := d.bodyRows() / 2
+	switch msg.String() {
+	case "tab", "l", "right", "h", "left":
+		d.patchFocus = !d.patchFocus
+	case "j", "down":
+		if d.patchFocus {
+			d.scrollBy(1)
+		} else {
+			d.selectFile(d.cursor + 1)
+		}
+	case "k", "up":
+		if d.patchFocus {
+			d.scrollBy(-1)
+		} else {
+			d.selectFile(d.cursor - 1)
+		}
+	case "n", "]":
+		d.selectFile(d.cursor + 1)
+	case "p", "[":
+		d.selectFile(d.cursor - 1)
+	case "ctrl+d", "pgdown", " ":
+		d.scrollBy(half)
+	case "ctrl+u", "pgup":
+		d.scrollBy(-half)
+	case "g", "home":
+		d.scroll = 0
+	case "G", "end":
+		d.scrollBy(len(d.selectedPatch()))
+	}
+}
+
+// diffLineStyle colors one patch line by its kind.
+func diffLineStyle(line string) lipgloss.Style {
+	switch {
+	case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"),
+		strings.HasPrefix(line, "diff --git"), strings.HasPrefix(line, "index "),
+		strings.HasPrefix(line, "new file"), strings.HasPrefix(line, "deleted file"),
+		strings.HasPrefix(line, "rename "), strings.HasPrefix(line, "similarity "):
+		return lipgloss.NewStyle().Foreground(ColorTextDim).Bold(true)
+	case strings.HasPrefix(line, "@@"):
+		return lipgloss.NewStyle().Foreground(ColorCyan)
+	case strings.HasPrefix(line, "+"):
+		return lipgloss.NewStyle().Foreground(ColorGreen)
+	case strings.HasPrefix(line, "-"):
+		return lipgloss.NewStyle().Foreground(ColorRed)
+	}
+	return lipgloss.NewStyle().Foreground(ColorText)
+}
+
+// diffStatusStyle colors a file's change kind in the tree.
+func diffStatusStyle(status string) lipgloss.Style {
+	switch status {
+	case git.DiffAdded:
+		return lipgloss.NewStyle().Foreground(ColorGreen)
+	case git.DiffDeleted:
+		return lipgloss.NewStyle().Foreground(ColorRed)
+	case git.DiffRenamed:
+		return lipgloss.NewStyle().Foreground(ColorCyan)
+	}
+	return lipgloss.NewStyle().Foreground(ColorYellow)
+}
+
+// View renders the dialog.
+func (d *WorktreeDiffDialog) View() string {
+	if !d.IsVisible() {
+		return ""
+	}
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorAccent)
+	dimStyle := lipgloss.NewStyle().Foreground(ColorComment)
+	selectedStyle := lipgloss.NewStyle().Foreground(ColorAccent).Bold(true)
+	normalStyle := lipgloss.NewStyle().Foreground(ColorText)
+	warnStyle := lipgloss.NewStyle().Foreground(ColorYellow)
+	errStyle := lipgloss.NewStyle().Foreground(ColorRed).Bold(true)
+	footerStyle := lipgloss.NewStyle().Foreground(ColorComment).Italic(true)
+
+	dialogWidth := fitDialogWidth(160, 50, d.width)
+	innerWidth := dialogWidth - 4
+	if innerWidth < 1 {
+		innerWidth = 1
+	}
+	fit := func(s string) string { return cellTruncate(s, innerWidth, "…") }
+
+	var lines []string
+	lines = append(lines, fit(titleStyle.Render("Review: "+d.sessionTitle)))
+	switch {
+	case d.loading:
+		lines = append(lines, dimStyle.Render("Computing diff..."))
+	case d.errMsg != "":
+		lines = append(lines, fit(errStyle.Render(d.errMsg)))
+	default:
+		added, deleted := d.diff.Totals()
+		summary := fmt.Sprintf("%s → %s · %d commit(s) · %d file(s) · +%d -%d",
+			d.diff.Branch, d.diff.Base, d.diff.Commits, len(d.files), added, deleted)
+		lines = append(lines, fit(dimStyle.Render(summary)))
+		if d.dirty {
+			lines = append(lines, fit(warnStyle.Render("⚠ uncommitted changes in the worktree are not shown and will not be merged")))
+		}
+	}
+	lines = append(lines, "")
+
+	if !d.loading && d.errMsg == "" {
+		if len(d.files) == 0 {
+			lines = append(lines, dimStyle.Render("  No changes against the base branch."))
+		} else {
+			lines = append(lines, d.viewBody(innerWidth, selectedStyle, normalStyle, dimStyle)...)
+		}
+	}
+
+	lines = append(lines, "")
+	footer := "j/k file · Tab focus patch · PgUp/PgDn scroll · m merge… · Esc close"
+	if d.patchFocus {
+		footer = "j/k scroll · Tab focus files · n/p file · m merge… · Esc close"
+	}
+	if !d.Ready() {
+		footer = "Esc close"
+	}
+	lines = append(lines, fit(footerStyle.Render(footer)))
+
+	box := DialogBoxStyle.Width(dialogWidth).Render(strings.Join(lines, "\n"))
+	return centerInScreen(box, d.width, d.height)
+}
+
+// viewBody renders the tree and patch panes side by side.
+func (d *WorktreeDiffDialog) viewBody(innerWidth int, selectedStyle, normalStyle, dimStyle lipgloss.Style) []string {
+	rows := d.bodyRows()
+	treeWidth := innerWidth / 3
+	if treeWidth > 40 {
+		treeWidth = 40
+	}
+	patchWidth := innerWidth - treeWidth - 3
+	if patchWidth < 1 {
+		patchWidth = 1
+	}
+
+	// Keep the selected file's row in view.
+	selRow := 0
+	for i, r := range d.rows {
+		if r.file == d.cursor {
+			selRow = i
+		}
+	}
+	start, end := windowBounds(selRow, len(d.rows), rows)
+	var tree []string
+	for i := start; i < end; i++ {
+		r := d.rows[i]
+		indent := strings.Repeat("  ", r.depth)
+		if r.file < 0 {
+			tree = append(tree, dimStyle.Render(indent+r.label))
+			continue
+		}
+		f := d.files[r.file]
+		label := indent + diffStatusStyle(f.Status).Render(f.Status) + " "
+		if r.file == d.cursor {
+			marker := selectedStyle
+			if d.patchFocus {
+				marker = normalStyle.Bold(true)
+			}
+			label += marker.Render(r.label)
+		} else {
+			label += normalStyle.Render(r.label)
+		}
+		tree = append(tree, label)
+	}
+
+	patch := d.selectedPatch()
+	sep := dimStyle.Render(" │ ")
+	out := make([]string, 0, rows)
+	for i := 0; i < rows; i++ {
+		left := ""
+		if i < len(tree) {
+			left = tree[i]
+		}
+		right := ""
+		if n := d.scroll + i; n < len(patch) {
+			line := strings.ReplaceAll(patch[n], "\t", "    ")
+			right = diffLineStyle(line).Render(cellTruncate(line, patchWidth, "…"))
+		}
+		out = append(out, fitCellWidth(left, treeWidth)+sep+right)
+	}
+	return out
+}
+
+// openWorktreeDiff opens the review panel for the selected worktree session
+// and computes the diff in the background.
+func (h *Home) openWorktreeDiff() tea.Cmd {
+	if h.cursor >= len(h.flatItems) {
+		return nil
+	}
+	item := h.flatItems[h.cursor]
+	if item.Type != session.ItemTypeSession || item.Session == nil {
+		return nil
+	}
+	inst := item.Session
+	if !inst.IsWorktree() {
+		h.setError(fmt.Errorf("session '%s' is not a worktree", inst.Title))
+		return nil
+	}
+	h.worktreeDiffDialog.SetSize(h.width, h.height)
+	h.worktreeDiffDialog.Show(inst.ID, inst.Title)
+	return func() tea.Msg {
+		d, err := session.DiffInstanceWorktree(inst, "")
+		dirty, _ := git.HasUncommittedChanges(inst.WorktreePath)
+		return worktreeDiffLoadedMsg{sessionID: inst.ID, diff: d, dirty: dirty, err: err}
+	}
+}
+
+// handleWorktreeDiffDialogKey closes the panel on esc/q and hands off to the
+// finish dialog, targeting the reviewed base, on m.
+func (h *Home) handleWorktreeDiffDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		h.worktreeDiffDialog.Hide()
+		return h, nil
+	case "m":
+		if !h.worktreeDiffDialog.Ready() {
+			return h, nil
+		}
+		sid := h.worktreeDiffDialog.sessionID
+		base := h.worktreeDiffDialog.Base()
+		h.worktreeDiffDialog.Hide()
+		h.instancesMu.RLock()
+		inst := h.instanceByID[sid]
+		h.instancesMu.RUnlock()
+		if inst == nil {
+			return h, nil
+		}
+		return h, h.openWorktreeFinish(inst, base)
+	default:
+		h.worktreeDiffDialog.Update(msg)
+		return h, nil
+	}
+}
@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/asheshgoplani/agent-deck/internal/git"
+)
+
+func TestBuildDiffTree(t *testing.T) {
+	files := []git.DiffFile{
+		{Path: "README.md"},
+		{Path: "internal/ui/a.go"},
+		{Path: "internal/ui/b.go"},
+		{Path: "internal/web/c.go"},
+	}
+	var got []string
+	for _, r := range buildDiffTree(files) {
+		got = append(got, strings.Repeat(" ", r.depth)+r.label)
+	}
+	want := []string{"README.md", "internal/", " ui/", "  a.go", "  b.go", " web/", "  c.go"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("tree = %q\nwant   %q", got, want)
+	}
+}
+
+func TestWorktreeDiffDialog_NavigateAndScroll(t *testing.T) {
+	d := NewWorktreeDiffDialog()
+	d.SetSize(120, 20)
+	d.Show("s1", "api")
+	if d.Ready() || !strings.Contains(d.View(), "Computing diff") {
+		t.Fatal("dialog should show a loading state before the diff arrives")
+	}
+
+	var long []string
+	for i := 0; i < 50; i++ {
+		long = append(long, "+line")
+	}
+	d.SetDiff(&git.BranchDiff{
+		Base: "main", Branch: "feature", Commits: 2,
+		Files: []git.DiffFile{
+			{Path: "z.go", Status: git.DiffModified, Added: 50, Patch: "@@ -1 +1,50 @@\n" + strings.Join(long, "\n")},
+			{Path: "a.go", Status: git.DiffAdded, Added: 1, Patch: "@@ -0,0 +1 @@\n+package a"},
+		},
+	}, true, nil)
+
+	if !d.Ready() || d.Base() != "main" {
+		t.Fatalf("Ready=%v Base=%q", d.Ready(), d.Base())
+	}
+	view := d.View()
+	for _, want := range []string{"feature → main", "2 commit(s)", "+51 -0", "uncommitted changes", "a.go", "z.go", "+package a"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	// Files are sorted, so j moves from a.go to z.go.
+	d.Update(keyRunes("j"))
+	if d.files[d.cursor].Path != "z.go" {
+		t.Fatalf("cursor on %s, want z.go", d.files[d.cursor].Path)
+	}
+	// With the patch focused, j scrolls instead, clamped to the end.
+	d.Update(tea.KeyMsg{Type: tea.KeyTab})
+	d.Update(keyRunes("j"))
+	if d.scroll != 1 {
+		t.Errorf("scroll = %d after j in the patch, want 1", d.scroll)
+	}
+	d.Update(keyRunes("G"))
+	if max := len(d.selectedPatch()) - d.bodyRows(); d.scroll != max {
+		t.Errorf("scroll = %d after G, want %d", d.scroll, max)
+	}
+	// Changing file resets the scroll.
+	d.Update(keyRunes("n"))
+	if d.files[d.cursor].Path != "a.go" || d.scroll != 0 {
+		t.Errorf("after n: file %s scroll %d", d.files[d.cursor].Path, d.scroll)
+	}
+}
+
+func TestWorktreeDiffDialog_Error(t *testing.T) {
+	d := NewWorktreeDiffDialog()
+	d.SetSize(100, 30)
+	d.Show("s1", "api")
+	d.SetDiff(nil, false, errors.New("no common history"))
+	if d.Ready() {
+		t.Error("a failed diff must not offer merging")
+	}
+	if view := d.View(); !strings.Contains(view, "no common history") {
+		t.Errorf("view missing the error:\n%s", view)
+	}
+}
+
+func TestWorktreeFinishDialog_Squash(t *testing.T) {
+	d := NewWorktreeFinishDialog()
+	d.SetSize(100, 40)
+	d.Show("s1", "api", "feature", "/repo", "/wt", "main")
+
+	// Tab order: merge → target → squash.
+	d.HandleKey("tab")
+	d.HandleKey("tab")
+	if d.focusIndex != 3 {
+		t.Fatalf("focus = %d after two tabs, want the squash checkbox", d.focusIndex)
+	}
+	d.HandleKey(" ")
+	if _, _, squash, _ := d.GetOptions(); !squash {
+		t.Fatal("space did not enable squash")
+	}
+	if !strings.Contains(d.View(), "[x] Squash into one commit") {
+		t.Errorf("view missing the checked squash box:\n%s", d.View())
+	}
+	d.HandleKey("enter")
+	if !strings.Contains(d.View(), "Squash-merge feature → main") {
+		t.Errorf("confirm view missing the squash merge:\n%s", d.View())
+	}
+
+	// Squash only applies while merging.
+	d.HandleKey("n")
+	d.focusIndex = 0
+	d.HandleKey(" ")
+	if merge, _, squash, _ := d.GetOptions(); merge || squash {
+		t.Errorf("merge=%v squash=%v with merge disabled", merge, squash)
+	}
+	if d.HandleKey("tab"); d.focusIndex != 2 {
+		t.Errorf("focus = %d, want keep-branch when merge is off", d.focusIndex)
+	}
+}
@@ -29,12 +29,13 @@ type WorktreeFinishDialog struct {
 
 	// Options (step 0)
 	mergeEnabled bool
+	squash       bool
 	keepBranch   bool
 	targetInput  textinput.Model
 
 	// Dialog state
 	step       int // 0=options, 1=confirm
-	focusIndex int // 0=merge checkbox, 1=target input, 2=keep-branch checkbox
+	focusIndex int // 0=merge checkbox, 1=target input, 2=keep-branch checkbox, 3=squash checkbox
 }
 
 // NewWorktreeFinishDialog creates a new worktree finish dialog
@@ -63,6 +64,7 @@ func (d *WorktreeFinishDialog) Show(sessionID, sessionTitle, branchName, repoRoo
 	d.isExecuting = false
 	d.errorMsg = ""
 	d.mergeEnabled = true
+	d.squash = false
 	d.keepBranch = false
 	d.step = 0
 	d.focusIndex = 0
@@ -112,12 +114,34 @@ func (d *WorktreeFinishDialog) GetSessionID() string {
 }
 
 // GetOptions returns the current dialog options
-func (d *WorktreeFinishDialog) GetOptions() (mergeEnabled bool, targetBranch string, keepBranch bool) {
+func (d *WorktreeFinishDialog) GetOptions() (mergeEnabled bool, targetBranch string, squash, keepBranch bool) {
 	target := strings.TrimSpace(d.targetInput.Value())
 	if target == "" {
 		target = d.targetInput.Placeholder
 	}
-	return d.mergeEnabled, target, d.keepBranch
+	return d.mergeEnabled, target, d.mergeEnabled && d.squash, d.keepBranch
+}
+
+// focusOrder lists the focusable fields top to bottom; the target input and
+// squash checkbox only apply when merging.
+func (d *WorktreeFinishDialog) focusOrder() []int {
+	if d.mergeEnabled {
+		return []int{0, 1, 3, 2} // merge, target, squash, keep-branch
+	}
+	return []int{0, 2}
+}
+
+// moveFocus moves focus delta fields along focusOrder, wrapping around.
+func (d *WorktreeFinishDialog) moveFocus(delta int) {
+	order := d.focusOrder()
+	pos := 0
+	for i, f := range order {
+		if f == d.focusIndex {
+			pos = i
+		}
+	}
+	d.focusIndex = order[(pos+delta+len(order))%len(order)]
+	d.updateFocus()
 }
 
 // HandleKey processes a key event and returns the action to take.
@@ -152,30 +176,11 @@ func (d *WorktreeFinishDialog) HandleKey(key string) (action string) {
 		return "close"
 
 	case "tab", "down":
-		if d.mergeEnabled {
-			d.focusIndex = (d.focusIndex + 1) % 3 // merge, target, keep-branch
-		} else {
-			// Skip target input when merge disabled
-			if d.focusIndex == 0 {
-				d.focusIndex = 2
-			} else {
-				d.focusIndex = 0
-			}
-		}
-		d.updateFocus()
+		d.moveFocus(1)
 		return ""
 
 	case "shift+tab", "up":
-		if d.mergeEnabled {
-			d.focusIndex = (d.focusIndex + 2) % 3
-		} else {
-			if d.focusIndex == 2 {
-				d.focusIndex = 0
-			} else {
-				d.focusIndex = 2
-			}
-		}
-		d.updateFocus()
+		d.moveFocus(-1)
 		return ""
 
 	case " ":
@@ -186,6 +191,8 @@ func (d *WorktreeFinishDialog) HandleKey(key string) (action string) {
 			// Tab handler already skips target input when merge is disabled
 		case 2:
 			d.keepBranch = !d.keepBranch
+		case 3:
+			d.squash = !d.squash
 		}
 		return ""
 
@@ -321,6 +328,17 @@ func (d *WorktreeFinishDialog) viewOptions(titleStyle, labelStyle, valueStyle, c
 		}
 		b.WriteString(d.targetInput.View())
 		b.WriteString("\n")
+
+		squashCheck := "[ ]"
+		if d.squash {
+			squashCheck = "[x]"
+		}
+		if d.focusIndex == 3 {
+			b.WriteString(checkboxActiveStyle.Render(fmt.Sprintf("▶ %s Squash into one commit", squashCheck)))
+		} else {
+			b.WriteString(checkboxStyle.Render(fmt.Sprintf("  %s Squash into one commit", squashCheck)))
+		}
+		b.WriteString("\n")
 	}
 
 	// Keep branch checkbox
@@ -383,7 +401,11 @@ func (d *WorktreeFinishDialog) viewConfirm(titleStyle, labelStyle, errStyle, foo
 
 	actionStyle := lipgloss.NewStyle().Foreground(ColorText)
 	if d.mergeEnabled {
-		b.WriteString(actionStyle.Render(fmt.Sprintf("  • Merge %s → %s", d.branchName, target)))
+		verb := "Merge"
+		if d.squash {
+			verb = "Squash-merge"
+		}
+		b.WriteString(actionStyle.Render(fmt.Sprintf("  • %s %s → %s", verb, d.branchName, target)))
 		b.WriteString("\n")
 	}
 	b.WriteString(actionStyle.Render("  • Remove worktree directory"))
@@ -18,13 +18,21 @@ import (
 // (explicit == true) on a non-repo path, it returns a non-empty errMsg so the
 // caller fails loudly, preserving explicit intent.
 //
-// On a supported repo (git or jujutsu) it computes and returns the backend's
+// On a supported repo (git, jujutsu or sapling) it computes and returns the backend's
 // worktree/workspace path plus repo root.
 func resolveWorktreeTarget(path, branch string, explicit bool) (worktreePath, repoRoot string, fallback bool, errMsg string) {
+	return resolveWorktreeTargetAt(path, branch, "", "", explicit)
+}
+
+// resolveWorktreeTargetAt is resolveWorktreeTarget with the location strategy
+// and {session-id} chosen by the caller, so the new-session dialog creates the
+// worktree exactly where its preview said it would. An empty location uses
+// [worktree] default_location; an empty pathID generates a fresh one.
+func resolveWorktreeTargetAt(path, branch, location, pathID string, explicit bool) (worktreePath, repoRoot string, fallback bool, errMsg string) {
 	backend, err := vcsbackend.Detect(path)
 	if err != nil {
 		if explicit {
-			return "", "", false, "Path is not a git or jujutsu repository"
+			return "", "", false, "Path is not a git, jujutsu or sapling repository"
 		}
 		// #1185: worktree was on by config default, not explicit user intent —
 		// fall back to a normal session on non-repo dirs instead of erroring.
@@ -33,10 +41,16 @@ func resolveWorktreeTarget(path, branch string, explicit bool) (worktreePath, re
 	root := backend.RepoDir()
 
 	wtSettings := session.GetWorktreeSettings()
+	if location == "" {
+		location = wtSettings.DefaultLocation
+	}
+	if pathID == "" {
+		pathID = git.GeneratePathID()
+	}
 	worktreePath = backend.WorktreePath(vcs.WorktreePathOptions{
 		Branch:    branch,
-		Location:  wtSettings.DefaultLocation,
-		SessionID: git.GeneratePathID(),
+		Location:  location,
+		SessionID: pathID,
 		Template:  wtSettings.Template(),
 	})
 	return worktreePath, root, false, ""
@@ -13,6 +13,7 @@ package update
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
@@ -20,6 +21,8 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/progress"
 )
 
 // ChecksumsAssetName is the release asset goreleaser publishes containing the
@@ -88,10 +91,15 @@ func assetArchiveName(release *Release, goos, goarch string) string {
 	return fmt.Sprintf("agent-deck_%s_%s_%s.tar.gz", version, goos, goarch)
 }
 
-// httpGetBytes downloads url fully into memory under a bounded timeout.
-func httpGetBytes(url string, timeout time.Duration) ([]byte, error) {
+// httpGetBytes downloads url fully into memory under a bounded timeout,
+// reporting bytes received under message.
+func httpGetBytes(ctx context.Context, url string, timeout time.Duration, message string, report progress.Func) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
 	client := &http.Client{Timeout: timeout}
-	resp, err := client.Get(url)
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -99,7 +107,7 @@ func httpGetBytes(url string, timeout time.Duration) ([]byte, error) {
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("download failed with status %d", resp.StatusCode)
 	}
-	return io.ReadAll(resp.Body)
+	return io.ReadAll(progress.Reader(resp.Body, resp.ContentLength, message, report))
 }
 
 // DownloadVerifiedBinary downloads the release archive for goos/goarch, verifies
@@ -109,6 +117,12 @@ func httpGetBytes(url string, timeout time.Duration) ([]byte, error) {
 // asset, a missing checksums.txt asset, an asset absent from checksums.txt, or a
 // hash mismatch all abort BEFORE any binary is returned.
 func DownloadVerifiedBinary(release *Release, goos, goarch string) ([]byte, error) {
+	return DownloadVerifiedBinaryProgress(context.Background(), release, goos, goarch, nil)
+}
+
+// DownloadVerifiedBinaryProgress is DownloadVerifiedBinary with cancellation
+// and download progress reported to report (nil to discard).
+func DownloadVerifiedBinaryProgress(ctx context.Context, release *Release, goos, goarch string, report progress.Func) ([]byte, error) {
 	if release == nil {
 		return nil, fmt.Errorf("nil release")
 	}
@@ -121,16 +135,17 @@ func DownloadVerifiedBinary(release *Release, goos, goarch string) ([]byte, erro
 		return nil, fmt.Errorf("release %s publishes no %s — refusing to deploy an unverified artifact", release.TagName, ChecksumsAssetName)
 	}
 
-	archive, err := httpGetBytes(assetURL, 120*time.Second)
+	assetName := assetArchiveName(release, goos, goarch)
+	archive, err := httpGetBytes(ctx, assetURL, 120*time.Second, "Downloading "+assetName, report)
 	if err != nil {
 		return nil, fmt.Errorf("failed to download release archive: %w", err)
 	}
-	checksumsData, err := httpGetBytes(checksumsURL, 30*time.Second)
+	checksumsData, err := httpGetBytes(ctx, checksumsURL, 30*time.Second, "Downloading "+ChecksumsAssetName, report)
 	if err != nil {
 		return nil, fmt.Errorf("failed to download %s: %w", ChecksumsAssetName, err)
 	}
 
-	assetName := assetArchiveName(release, goos, goarch)
+	report.Logf("Verifying %s checksum", assetName)
 	if err := VerifyAssetChecksum(assetName, archive, ParseChecksums(checksumsData)); err != nil {
 		return nil, err
 	}
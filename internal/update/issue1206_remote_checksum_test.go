@@ -9,13 +9,17 @@ import (
 	"archive/tar"
 	"bytes"
 	"compress/gzip"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"github.com/asheshgoplani/agent-deck/internal/progress"
 )
 
 // makeTarGz builds a goreleaser-style .tar.gz containing an `agent-deck` binary
@@ -208,3 +212,35 @@ func TestAssetArchiveName_MatchesAssetURL(t *testing.T) {
 		t.Fatalf("asset URL lookup failed for computed name %q", name)
 	}
 }
+
+func TestDownloadVerifiedBinaryProgress_ReportsAndCancels(t *testing.T) {
+	binary := []byte("ELF-agent-deck-binary")
+	archive := makeTarGz(t, binary)
+	checksums := []byte(sha256hex(archive) + "  agent-deck_1.2.3_linux_amd64.tar.gz\n")
+	rel, cleanup := releaseServer(t, archive, checksums, true)
+	defer cleanup()
+
+	var messages []string
+	var archiveDone int64
+	report := func(u progress.Update) {
+		messages = append(messages, u.Message)
+		if u.Message == "Downloading agent-deck_1.2.3_linux_amd64.tar.gz" {
+			archiveDone = u.Done
+		}
+	}
+	if _, err := DownloadVerifiedBinaryProgress(context.Background(), rel, "linux", "amd64", report); err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	if archiveDone != int64(len(archive)) {
+		t.Fatalf("archive progress ended at %d bytes, want %d", archiveDone, len(archive))
+	}
+	if last := messages[len(messages)-1]; last != "Verifying agent-deck_1.2.3_linux_amd64.tar.gz checksum" {
+		t.Fatalf("last report = %q, want the verify step", last)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := DownloadVerifiedBinaryProgress(ctx, rel, "linux", "amd64", nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled download err = %v, want context.Canceled", err)
+	}
+}
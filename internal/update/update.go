@@ -5,6 +5,7 @@ import (
 	"archive/tar"
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -17,6 +18,7 @@ import (
 	"time"
 
 	"github.com/asheshgoplani/agent-deck/internal/agentpaths"
+	"github.com/asheshgoplani/agent-deck/internal/progress"
 )
 
 const (
@@ -581,6 +583,13 @@ func PerformUpdate(downloadURL string) error {
 // missing-entry, or hash mismatch errors occur before the installed binary is
 // touched.
 func PerformVerifiedUpdate(release *Release, goos, goarch string) error {
+	return PerformVerifiedUpdateProgress(context.Background(), release, goos, goarch, nil)
+}
+
+// PerformVerifiedUpdateProgress is PerformVerifiedUpdate with cancellation
+// and download progress reported to report (nil to discard). Cancelling ctx
+// aborts the download; the installed binary is never touched in that case.
+func PerformVerifiedUpdateProgress(ctx context.Context, release *Release, goos, goarch string, report progress.Func) error {
 	execPath, upgradeCmd, managed, err := detectHomebrewManagedInstall()
 	if err != nil {
 		return fmt.Errorf("failed to detect install type: %w", err)
@@ -590,7 +599,7 @@ func PerformVerifiedUpdate(release *Release, goos, goarch string) error {
 	}
 
 	fmt.Printf("Downloading and verifying %s/%s release binary...\n", goos, goarch)
-	binaryData, err := DownloadVerifiedBinary(release, goos, goarch)
+	binaryData, err := DownloadVerifiedBinaryProgress(ctx, release, goos, goarch, report)
 	if err != nil {
 		return fmt.Errorf("download/verify failed: %w", err)
 	}
@@ -23,6 +23,7 @@ type Type string
 const (
 	TypeGit     Type = "git"
 	TypeJujutsu Type = "jujutsu"
+	TypeSapling Type = "sapling"
 )
 
 // Backend abstracts version control operations scoped to a repository.
@@ -46,4 +47,7 @@ type Backend interface {
 	RemoveWorktree(worktreePath string, force bool) error
 	GetWorktreeForBranch(branchName string) (string, error)
 	PruneWorktrees() error
+
+	// Status
+	HasUncommittedChanges(worktreePath string) (bool, error)
 }
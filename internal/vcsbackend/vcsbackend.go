@@ -1,5 +1,5 @@
 // Package vcsbackend exposes a single Detect entry point that returns the
-// correct vcs.Backend (git or jujutsu) for a directory. It lives in its
+// correct vcs.Backend (git, jujutsu or sapling) for a directory. It lives in its
 // own package so cmd/agent-deck and internal/ui can share backend
 // selection without re-declaring the gitBackend adapter (issue #1126).
 package vcsbackend
@@ -11,6 +11,7 @@ import (
 
 	"github.com/asheshgoplani/agent-deck/internal/git"
 	"github.com/asheshgoplani/agent-deck/internal/jujutsu"
+	"github.com/asheshgoplani/agent-deck/internal/sapling"
 	"github.com/asheshgoplani/agent-deck/internal/vcs"
 )
 
@@ -95,23 +96,31 @@ func (g *gitBackend) PruneWorktrees() error {
 	return git.PruneWorktrees(g.repoDir)
 }
 
+func (g *gitBackend) HasUncommittedChanges(worktreePath string) (bool, error) {
+	return git.HasUncommittedChanges(worktreePath)
+}
+
 // Detect returns the Backend for dir, preferring jujutsu when both jj and
 // git are present (matching the cmd/agent-deck ordering established in PR
-// #754).
+// #754). Sapling is tried before git for the same reason: a colocated repo
+// should get the VCS the team actually drives it with.
 func Detect(dir string) (vcs.Backend, error) {
 	if b, err := jujutsu.NewJJBackend(dir); err == nil {
 		return b, nil
 	}
+	if b, err := sapling.NewSLBackend(dir); err == nil {
+		return b, nil
+	}
 	if b, err := newGitBackend(dir); err == nil {
 		return b, nil
 	}
-	return nil, fmt.Errorf("not a git or jujutsu repository: %s", dir)
+	return nil, fmt.Errorf("not a git, jujutsu or sapling repository: %s", dir)
 }
 
 // CreateWorktreeWithSetup creates a worktree via the backend. For git
 // backends it also runs the per-repo worktree-setup script. For non-git
-// backends (jujutsu) the worktree is created without running a setup
-// script. Same semantics as cmd/agent-deck's createWorktreeWithSetup
+// backends (jujutsu, sapling) the worktree is created without running a
+// setup script. Same semantics as cmd/agent-deck's createWorktreeWithSetup
 // helper.
 func CreateWorktreeWithSetup(backend vcs.Backend, worktreePath, branchName string, stdout, stderr io.Writer, setupTimeout time.Duration) (setupErr error, err error) {
 	if backend.Type() == vcs.TypeGit {
@@ -14,7 +14,7 @@ func TestDetect_NonRepoReturnsError(t *testing.T) {
 	if err == nil {
 		t.Fatalf("expected error for non-repo directory, got nil")
 	}
-	if !strings.Contains(err.Error(), "not a git or jujutsu repository") {
+	if !strings.Contains(err.Error(), "not a git, jujutsu or sapling repository") {
 		t.Errorf("expected diagnostic in error, got %q", err.Error())
 	}
 }
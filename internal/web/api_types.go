@@ -1,6 +1,10 @@
 package web
 
-import "github.com/asheshgoplani/agent-deck/internal/session"
+import (
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
 
 // Error code constants for API error responses.
 const (
@@ -14,6 +18,7 @@ const (
 	ErrCodeInternalError    = "INTERNAL_ERROR"
 	ErrCodeNotImplemented   = "NOT_IMPLEMENTED"
 	ErrCodeReadOnly         = "READ_ONLY"
+	ErrCodeShareExpired     = "SHARE_EXPIRED"
 )
 
 // CreateSessionRequest is the body for POST /api/sessions.
@@ -36,6 +41,33 @@ type RenameGroupRequest struct {
 	Name string `json:"name"`
 }
 
+// SendMessageRequest is the body for POST /api/sessions/{id}/send.
+type SendMessageRequest struct {
+	Message string `json:"message"`
+}
+
+// ShareLinkRequest is the optional body for POST /api/sessions/{id}/share.
+type ShareLinkRequest struct {
+	// TTL is a Go duration ("30m", "4h"); empty means DefaultShareTTL.
+	TTL string `json:"ttl,omitempty"`
+}
+
+// ShareLinkResponse is returned by POST /api/sessions/{id}/share.
+type ShareLinkResponse struct {
+	SessionID string    `json:"sessionId"`
+	Path      string    `json:"path"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// ShareTranscriptResponse is returned by GET /share/{token}/transcript.
+type ShareTranscriptResponse struct {
+	SessionID string                    `json:"sessionId"`
+	Title     string                    `json:"title"`
+	ExpiresAt time.Time                 `json:"expiresAt"`
+	Entries   []session.TranscriptEntry `json:"entries"`
+}
+
 // UpdateSessionRequest is the body for PATCH /api/sessions/{id}. Every field
 // is optional; only the fields present in the request body are updated.
 // Pointer types let the handler distinguish "not supplied" from "set to zero
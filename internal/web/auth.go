@@ -35,9 +35,21 @@ func (s *Server) authorizeStreamRequest(r *http.Request) bool {
 }
 
 func (s *Server) authorize(r *http.Request, allowQueryToken bool) bool {
-	if s.cfg.Token == "" {
+	if !s.cfg.authConfigured() {
+		return true
+	}
+
+	// A verified mTLS client certificate or a trusted proxy's user header
+	// authenticates on its own; see tls_auth.go.
+	if s.cfg.TLSClientCAFile != "" && clientCertUser(r) != "" {
 		return true
 	}
+	if s.proxyUser(r) != "" {
+		return true
+	}
+	if s.cfg.Token == "" {
+		return false
+	}
 
 	if allowQueryToken {
 		queryToken := strings.TrimSpace(r.URL.Query().Get("token"))
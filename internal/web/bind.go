@@ -8,14 +8,14 @@ import (
 
 // CheckBindSecurity refuses an unauthenticated bind to a non-loopback address.
 //
-// Authentication is opt-in (an empty token authorizes every request), so a
-// non-loopback bind with no token turns the box into an unauthenticated
+// Authentication is opt-in (with no token, client CA or trusted proxy every
+// request is authorized), so a non-loopback bind with none of them turns the box into an unauthenticated
 // remote-code-execution surface (terminal-bridge keystroke injection +
 // POST /api/sessions). When that combination is detected and the operator has
 // not explicitly acknowledged it via insecureBind, this returns an actionable
 // error so the server refuses to start. See /tmp/sec-web-REPORT.md finding #1.
-func CheckBindSecurity(listenAddr, token string, insecureBind bool) error {
-	if token != "" || insecureBind {
+func CheckBindSecurity(listenAddr string, authenticated, insecureBind bool) error {
+	if authenticated || insecureBind {
 		return nil
 	}
 	loopback, err := bindIsLoopback(listenAddr)
@@ -26,12 +26,14 @@ func CheckBindSecurity(listenAddr, token string, insecureBind bool) error {
 		return nil
 	}
 	return fmt.Errorf(
-		"refusing to bind %q without an auth token: this exposes an unauthenticated "+
+		"refusing to bind %q without authentication: this exposes an unauthenticated "+
 			"remote-code-execution surface (terminal bridge + session-create API) to the network.\n"+
 			"  Fix one of:\n"+
-			"    - bind loopback only:  --listen 127.0.0.1:8420  (default)\n"+
-			"    - set an auth token:   --token <secret>\n"+
-			"    - override (unsafe):   --insecure-bind",
+			"    - bind loopback only:      --listen 127.0.0.1:8420  (default)\n"+
+			"    - set an auth token:       --token <secret>\n"+
+			"    - require client certs:    --tls-cert <crt> --tls-key <key> --tls-client-ca <ca>\n"+
+			"    - trust a reverse proxy:   --trusted-proxy <ip|cidr>\n"+
+			"    - override (unsafe):       --insecure-bind",
 		listenAddr,
 	)
 }
@@ -63,5 +65,5 @@ func bindIsLoopback(listenAddr string) (bool, error) {
 // checkBindSecurity is the server-bound wrapper around CheckBindSecurity used
 // as a defense-in-depth gate at Start() time.
 func (s *Server) checkBindSecurity() error {
-	return CheckBindSecurity(s.cfg.ListenAddr, s.cfg.Token, s.cfg.InsecureBind)
+	return CheckBindSecurity(s.cfg.ListenAddr, s.cfg.authConfigured(), s.cfg.InsecureBind)
 }
@@ -14,19 +14,25 @@ import (
 // submissions to the local agent-deck API (e.g. creating sessions that execute
 // arbitrary commands via tmux).
 //
-// Report #4: when an auth token is configured (the exposed mode that report #1
+// Report #4: when authentication is configured (the exposed mode that report #1
 // forces for any non-loopback bind), CSRF additionally fails closed — a
 // mutation carrying NEITHER Origin NOR Referer is rejected, so a non-browser
 // caller (or an SSRF pivot) can't slip a mutation past the Origin check. In the
 // default loopback no-token dev mode this fail-closed step is skipped, leaving
 // behavior unchanged for normal local/CLI users.
 func (s *Server) csrfProtect(next http.Handler) http.Handler {
-	failClosed := s.cfg.Token != ""
+	failClosed := s.cfg.authConfigured()
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !isMutationMethod(r.Method) {
 			next.ServeHTTP(w, r)
 			return
 		}
+		// Inbound webhooks carry no browser credentials and are
+		// HMAC-verified by their handler, so Origin checks don't apply.
+		if strings.HasPrefix(r.URL.Path, webhookPathPrefix) {
+			next.ServeHTTP(w, r)
+			return
+		}
 
 		if !validateOrigin(r, failClosed) {
 			writeAPIError(w, http.StatusForbidden, ErrCodeCSRF, "cross-origin request blocked")
@@ -0,0 +1,348 @@
+package web
+
+// Read-only live pane streaming (/ws/pane/<id>).
+//
+// /ws/session/<id> attaches a real tmux client through a PTY, which is what
+// the interactive terminal needs but is heavy for "just let me watch": every
+// viewer is another tmux client and can resize the window. The pane stream
+// instead reuses the control-mode PipeManager the TUI runs on: output events
+// trigger a capture-pane, the capture is diffed line-by-line against the
+// previous one, and only changed lines go over the wire. Any number of
+// viewers share one control pipe per tmux session, and viewers can never
+// send input.
+//
+// Wire format (JSON text frames):
+//
+//	{"type":"pane_snapshot","sessionId":"…","lines":["…"],"total":N}
+//	{"type":"pane_diff","sessionId":"…","changes":[{"line":3,"text":"…"}],"total":N}
+//
+// Clients apply a diff by replacing each listed line and truncating to total.
+// Lines keep tmux's SGR escapes (capture-pane -e), so an xterm can render
+// them as-is.
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/logging"
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// paneStreamDebounce coalesces bursts of %output events into one
+	// capture. Agents stream tokens far faster than a viewer can read.
+	paneStreamDebounce = 100 * time.Millisecond
+	// paneStreamPollInterval re-captures even without output events, so a
+	// pipe that reconnected or a pane redrawn by resize is picked up.
+	paneStreamPollInterval = 2 * time.Second
+)
+
+// paneStreamSource is the subset of *tmux.PipeManager the pane stream needs.
+// Injectable so tests run without tmux.
+type paneStreamSource interface {
+	Connect(sessionName, socketName string) error
+	Disconnect(sessionName string)
+	CapturePane(sessionName string) (string, error)
+}
+
+// paneLineChange is one replaced line in a pane_diff message.
+type paneLineChange struct {
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+type paneStreamMessage struct {
+	Type      string           `json:"type"` // pane_snapshot, pane_diff
+	SessionID string           `json:"sessionId"`
+	Lines     []string         `json:"lines,omitempty"`
+	Changes   []paneLineChange `json:"changes,omitempty"`
+	Total     int              `json:"total"`
+	Time      time.Time        `json:"time"`
+}
+
+// paneStreamHub shares one control pipe per tmux session between all pane
+// stream viewers. The pipe is connected for the first viewer and dropped
+// with the last.
+type paneStreamHub struct {
+	ctx       context.Context
+	newSource func(ctx context.Context, onOutput func(sessionName string)) paneStreamSource
+
+	mu     sync.Mutex
+	source paneStreamSource
+	subs   map[string]map[chan struct{}]struct{}
+}
+
+func newPaneStreamHub(ctx context.Context) *paneStreamHub {
+	return &paneStreamHub{
+		ctx: ctx,
+		newSource: func(ctx context.Context, onOutput func(string)) paneStreamSource {
+			return tmux.NewPipeManager(ctx, onOutput)
+		},
+		subs: make(map[string]map[chan struct{}]struct{}),
+	}
+}
+
+// subscribe registers a viewer of tmuxName. The returned channel receives a
+// (coalesced) signal whenever the pane produced output; cancel must be
+// called when the viewer leaves.
+func (h *paneStreamHub) subscribe(tmuxName, socketName string) (<-chan struct{}, func(), error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.source == nil {
+		h.source = h.newSource(h.ctx, h.notify)
+	}
+	if len(h.subs[tmuxName]) == 0 {
+		if err := h.source.Connect(tmuxName, socketName); err != nil {
+			return nil, nil, err
+		}
+		h.subs[tmuxName] = make(map[chan struct{}]struct{})
+	}
+	ch := make(chan struct{}, 1)
+	h.subs[tmuxName][ch] = struct{}{}
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			delete(h.subs[tmuxName], ch)
+			if len(h.subs[tmuxName]) == 0 {
+				delete(h.subs, tmuxName)
+				h.source.Disconnect(tmuxName)
+			}
+		})
+	}
+	return ch, cancel, nil
+}
+
+// notify is the PipeManager output callback.
+func (h *paneStreamHub) notify(tmuxName string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs[tmuxName] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (h *paneStreamHub) capture(tmuxName string) (string, error) {
+	h.mu.Lock()
+	source := h.source
+	h.mu.Unlock()
+	return source.CapturePane(tmuxName)
+}
+
+// splitPaneLines splits a capture into lines, dropping the trailing newline
+// capture-pane always emits.
+func splitPaneLines(content string) []string {
+	content = strings.TrimSuffix(content, "\n")
+	if content == "" {
+		return []string{}
+	}
+	return strings.Split(content, "\n")
+}
+
+// diffPaneLines compares two captures. It returns the changed lines of next,
+// or snapshot=true when prev is unknown or most of the pane changed (a
+// full redraw is cheaper to send whole).
+func diffPaneLines(prev, next []string) (changes []paneLineChange, snapshot bool) {
+	if prev == nil {
+		return nil, true
+	}
+	for i, line := range next {
+		if i >= len(prev) || prev[i] != line {
+			changes = append(changes, paneLineChange{Line: i, Text: line})
+		}
+	}
+	if len(next) > 0 && len(changes)*2 > len(next) {
+		return nil, true
+	}
+	return changes, false
+}
+
+// handlePaneStreamWS streams a read-only live view of a session's pane.
+func (s *Server) handlePaneStreamWS(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeAPIError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed")
+		return
+	}
+	if !s.authorizeWSRequest(r) {
+		writeAPIError(w, http.StatusUnauthorized, "UNAUTHORIZED", "unauthorized")
+		return
+	}
+
+	const prefix = "/ws/pane/"
+	sessionID := strings.TrimPrefix(r.URL.Path, prefix)
+	if sessionID == "" || strings.Contains(sessionID, "/") {
+		writeAPIError(w, http.StatusBadRequest, "INVALID_REQUEST", "session id is required")
+		return
+	}
+	s.servePaneStream(w, r, sessionID, time.Time{})
+}
+
+// servePaneStream upgrades an authorized request and streams sessionID's
+// pane. A non-zero expires closes the stream at that time; share links use
+// it so a viewer connected just before expiry doesn't keep watching.
+func (s *Server) servePaneStream(w http.ResponseWriter, r *http.Request, sessionID string, expires time.Time) {
+	snapshot, err := s.menuData.LoadMenuSnapshot()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to load session data")
+		return
+	}
+	menuSession, found := snapshotSessionByID(snapshot, sessionID)
+	if !found {
+		writeAPIError(w, http.StatusNotFound, "NOT_FOUND", "session not found")
+		return
+	}
+
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	writer := newWSConnWriter(conn)
+	_ = writer.WriteJSON(wsServerMessage{
+		Type:      "status",
+		Event:     "connected",
+		SessionID: sessionID,
+		Profile:   snapshot.Profile,
+		ReadOnly:  true,
+		Time:      time.Now().UTC(),
+	})
+
+	if menuSession.TmuxSession == "" {
+		_ = writer.WriteJSON(wsServerMessage{
+			Type:      "error",
+			Code:      "TMUX_SESSION_NOT_FOUND",
+			Message:   "tmux session is not available",
+			SessionID: sessionID,
+			Time:      time.Now().UTC(),
+		})
+		return
+	}
+
+	updates, unsubscribe, err := s.paneStreams.subscribe(menuSession.TmuxSession, menuSession.TmuxSocketName)
+	if err != nil {
+		logging.ForComponent(logging.CompWeb).Error("pane_stream_connect_failed",
+			slog.String("session_id", sessionID),
+			slog.String("tmux_session", menuSession.TmuxSession),
+			slog.String("error", err.Error()))
+		_ = writer.WriteJSON(wsServerMessage{
+			Type:      "error",
+			Code:      "PANE_STREAM_FAILED",
+			Message:   "failed to connect to tmux session",
+			SessionID: sessionID,
+			Time:      time.Now().UTC(),
+		})
+		return
+	}
+	defer unsubscribe()
+
+	// The stream is read-only: the reader only answers pings and reports
+	// when the client goes away.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			_, payload, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var msg wsClientMessage
+			if err := json.Unmarshal(payload, &msg); err == nil && msg.Type == "ping" {
+				_ = writer.WriteJSON(wsServerMessage{
+					Type:      "status",
+					Event:     "pong",
+					SessionID: sessionID,
+					Time:      time.Now().UTC(),
+				})
+				continue
+			}
+			_ = writer.WriteJSON(wsServerMessage{
+				Type:      "error",
+				Code:      "READ_ONLY",
+				Message:   "the pane stream is read-only",
+				SessionID: sessionID,
+				Time:      time.Now().UTC(),
+			})
+		}
+	}()
+
+	var last []string
+	send := func() bool {
+		content, err := s.paneStreams.capture(menuSession.TmuxSession)
+		if err != nil {
+			// Transient (pipe reconnecting); the next event or poll retries.
+			return true
+		}
+		lines := splitPaneLines(content)
+		changes, full := diffPaneLines(last, lines)
+		msg := paneStreamMessage{SessionID: sessionID, Total: len(lines), Time: time.Now().UTC()}
+		switch {
+		case full:
+			msg.Type = "pane_snapshot"
+			msg.Lines = lines
+		case len(changes) > 0 || len(lines) != len(last):
+			msg.Type = "pane_diff"
+			msg.Changes = changes
+		default:
+			return true
+		}
+		last = lines
+		return writer.WriteJSON(msg) == nil
+	}
+
+	if !send() {
+		return
+	}
+	poll := time.NewTicker(paneStreamPollInterval)
+	defer poll.Stop()
+	var expired <-chan time.Time
+	if !expires.IsZero() {
+		expiry := time.NewTimer(time.Until(expires))
+		defer expiry.Stop()
+		expired = expiry.C
+	}
+	for {
+		select {
+		case <-done:
+			return
+		case <-expired:
+			_ = conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "share link expired"),
+				time.Now().Add(time.Second))
+			return
+		case <-s.baseCtx.Done():
+			_ = conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"),
+				time.Now().Add(time.Second))
+			return
+		case <-updates:
+			select {
+			case <-time.After(paneStreamDebounce):
+			case <-done:
+				return
+			}
+			// Events that arrived during the debounce are covered by
+			// this capture.
+			select {
+			case <-updates:
+			default:
+			}
+		case <-poll.C:
+		}
+		if !send() {
+			return
+		}
+	}
+}
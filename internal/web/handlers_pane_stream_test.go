@@ -0,0 +1,190 @@
+package web
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// fakePaneSource stands in for the PipeManager: content is whatever the test
+// last set, and onOutput fires the hub callback like a %output event.
+type fakePaneSource struct {
+	mu        sync.Mutex
+	content   string
+	connected map[string]int
+	onOutput  func(string)
+}
+
+func (f *fakePaneSource) Connect(name, _ string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.connected[name]++
+	return nil
+}
+
+func (f *fakePaneSource) Disconnect(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.connected[name]--
+}
+
+func (f *fakePaneSource) CapturePane(string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.content, nil
+}
+
+func (f *fakePaneSource) set(content string) {
+	f.mu.Lock()
+	f.content = content
+	f.mu.Unlock()
+}
+
+func (f *fakePaneSource) connections(name string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.connected[name]
+}
+
+func newFakePaneHub(src *fakePaneSource) *paneStreamHub {
+	h := newPaneStreamHub(context.Background())
+	h.newSource = func(_ context.Context, onOutput func(string)) paneStreamSource {
+		src.onOutput = onOutput
+		return src
+	}
+	return h
+}
+
+func TestDiffPaneLines(t *testing.T) {
+	if _, full := diffPaneLines(nil, []string{"a"}); !full {
+		t.Fatal("first capture must be a snapshot")
+	}
+
+	prev := []string{"$ make", "building", "ok", "$"}
+	changes, full := diffPaneLines(prev, []string{"$ make", "building", "FAIL", "$"})
+	if full || len(changes) != 1 || changes[0] != (paneLineChange{Line: 2, Text: "FAIL"}) {
+		t.Fatalf("single-line change = %+v full=%v", changes, full)
+	}
+
+	changes, full = diffPaneLines(prev, []string{"$ make", "building", "ok", "$", "next"})
+	if full || len(changes) != 1 || changes[0].Line != 4 {
+		t.Fatalf("appended line = %+v full=%v", changes, full)
+	}
+
+	if changes, full := diffPaneLines(prev, prev); full || len(changes) != 0 {
+		t.Fatalf("unchanged pane = %+v full=%v", changes, full)
+	}
+
+	if _, full := diffPaneLines(prev, []string{"x", "y", "z", "$"}); !full {
+		t.Fatal("a mostly redrawn pane should be sent as a snapshot")
+	}
+}
+
+func TestSplitPaneLines(t *testing.T) {
+	if got := splitPaneLines("a\nb\n"); len(got) != 2 || got[1] != "b" {
+		t.Fatalf("splitPaneLines = %q", got)
+	}
+	if got := splitPaneLines(""); got == nil || len(got) != 0 {
+		t.Fatalf("empty capture = %#v, want empty non-nil slice", got)
+	}
+}
+
+func TestPaneStreamHub_SharesOnePipePerSession(t *testing.T) {
+	src := &fakePaneSource{connected: map[string]int{}}
+	hub := newFakePaneHub(src)
+
+	a, cancelA, err := hub.subscribe("agentdeck_x", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, cancelB, err := hub.subscribe("agentdeck_x", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := src.connections("agentdeck_x"); n != 1 {
+		t.Fatalf("connections = %d, want one shared pipe", n)
+	}
+
+	src.onOutput("agentdeck_x")
+	src.onOutput("agentdeck_x") // coalesced, must not block
+	for name, ch := range map[string]<-chan struct{}{"a": a, "b": b} {
+		select {
+		case <-ch:
+		default:
+			t.Fatalf("viewer %s was not notified", name)
+		}
+	}
+
+	cancelA()
+	cancelA() // idempotent
+	if n := src.connections("agentdeck_x"); n != 1 {
+		t.Fatalf("pipe dropped while a viewer remains (connections = %d)", n)
+	}
+	cancelB()
+	if n := src.connections("agentdeck_x"); n != 0 {
+		t.Fatalf("pipe kept after last viewer left (connections = %d)", n)
+	}
+}
+
+func TestPaneStreamWS_SnapshotThenDiff(t *testing.T) {
+	srv := NewServer(Config{ListenAddr: "127.0.0.1:0", Profile: "work"})
+	srv.menuData = &fakeMenuDataLoader{
+		snapshot: &MenuSnapshot{
+			Profile: "work",
+			Items: []MenuItem{{
+				Type:    MenuItemTypeSession,
+				Session: &MenuSession{ID: "sess-1", TmuxSession: "agentdeck_sess-1"},
+			}},
+		},
+	}
+	src := &fakePaneSource{connected: map[string]int{}, content: "$ make\nbuilding\nok\n$\n"}
+	srv.paneStreams = newFakePaneHub(src)
+
+	testServer := httptest.NewServer(srv.Handler())
+	defer testServer.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL(testServer.URL, "/ws/pane/sess-1"), nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+
+	var status wsServerMessage
+	if err := conn.ReadJSON(&status); err != nil || status.Event != "connected" || !status.ReadOnly {
+		t.Fatalf("connected status = %+v, err %v", status, err)
+	}
+
+	var snap paneStreamMessage
+	if err := conn.ReadJSON(&snap); err != nil {
+		t.Fatal(err)
+	}
+	if snap.Type != "pane_snapshot" || snap.Total != 4 || strings.Join(snap.Lines, "|") != "$ make|building|ok|$" {
+		t.Fatalf("snapshot = %+v", snap)
+	}
+
+	src.set("$ make\nbuilding\nFAIL\n$\n$ \n")
+	src.onOutput("agentdeck_sess-1")
+	var diff paneStreamMessage
+	if err := conn.ReadJSON(&diff); err != nil {
+		t.Fatal(err)
+	}
+	if diff.Type != "pane_diff" || diff.Total != 5 || len(diff.Changes) != 2 ||
+		diff.Changes[0] != (paneLineChange{Line: 2, Text: "FAIL"}) {
+		t.Fatalf("diff = %+v", diff)
+	}
+
+	// Input is rejected: the stream is a view, not a terminal.
+	if err := conn.WriteJSON(wsClientMessage{Type: "input", Data: "rm -rf /\r"}); err != nil {
+		t.Fatal(err)
+	}
+	var rejected wsServerMessage
+	if err := conn.ReadJSON(&rejected); err != nil || rejected.Code != "READ_ONLY" {
+		t.Fatalf("input reply = %+v, err %v", rejected, err)
+	}
+}
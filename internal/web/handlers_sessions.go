@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
@@ -11,10 +12,17 @@ import (
 	"github.com/asheshgoplani/agent-deck/internal/session"
 )
 
+// sessionsListResponse is GET /api/sessions. Sessions is one page of the
+// sessions matching the query (see sessionListQuery); Total counts all
+// matches, so a client pages with offset until offset+len(sessions) reaches
+// it. Limit is 0 when the whole list was returned.
 type sessionsListResponse struct {
 	Sessions []*MenuSession `json:"sessions"`
 	Groups   []*MenuGroup   `json:"groups"`
 	Profile  string         `json:"profile"`
+	Total    int            `json:"total"`
+	Offset   int            `json:"offset"`
+	Limit    int            `json:"limit"`
 }
 
 func (s *Server) handleSessionsCollection(w http.ResponseWriter, r *http.Request) {
@@ -25,6 +33,11 @@ func (s *Server) handleSessionsCollection(w http.ResponseWriter, r *http.Request
 
 	switch r.Method {
 	case http.MethodGet:
+		query, err := parseSessionListQuery(r.URL.Query())
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, ErrCodeBadRequest, err.Error())
+			return
+		}
 		snapshot, err := s.menuData.LoadMenuSnapshot()
 		if err != nil {
 			writeAPIError(w, http.StatusInternalServerError, ErrCodeInternalError, "failed to load session data")
@@ -35,17 +48,20 @@ func (s *Server) handleSessionsCollection(w http.ResponseWriter, r *http.Request
 		// See snapshot_hook_refresh.go for the rationale.
 		refreshSnapshotHookStatuses(snapshot, s.hookStatusLoader)
 		resp := sessionsListResponse{
-			Sessions: make([]*MenuSession, 0),
-			Groups:   make([]*MenuGroup, 0),
-			Profile:  snapshot.Profile,
+			Groups:  make([]*MenuGroup, 0),
+			Profile: snapshot.Profile,
+			Offset:  query.offset,
+			Limit:   query.limit,
 		}
+		var sessions []*MenuSession
 		for _, item := range snapshot.Items {
 			if item.Type == MenuItemTypeSession && item.Session != nil {
-				resp.Sessions = append(resp.Sessions, item.Session)
-			} else if item.Type == MenuItemTypeGroup && item.Group != nil {
+				sessions = append(sessions, item.Session)
+			} else if item.Type == MenuItemTypeGroup && item.Group != nil && query.inGroup(item.Group.Path) {
 				resp.Groups = append(resp.Groups, item.Group)
 			}
 		}
+		resp.Sessions, resp.Total = query.apply(sessions)
 		writeJSON(w, http.StatusOK, resp)
 
 	case http.MethodPost:
@@ -130,6 +146,13 @@ func (s *Server) handleSessionByAction(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Share link: POST /api/sessions/{id}/share. Grants read access only,
+	// so it skips the mutation gate.
+	if action == "share" {
+		s.handleSessionShare(w, r, sessionID)
+		return
+	}
+
 	// PATCH /api/sessions/{id} — partial field edit (matches TUI EditSessionDialog).
 	if r.Method == http.MethodPatch && action == "" {
 		s.handleSessionPatch(w, r, sessionID)
@@ -201,6 +224,8 @@ func (s *Server) handleSessionByAction(w http.ResponseWriter, r *http.Request) {
 			}
 			s.notifyMenuChanged()
 			writeJSON(w, http.StatusOK, SessionActionResponse{SessionID: sessionID})
+		case "send":
+			s.handleSessionSend(w, r, sessionID)
 		case "fork":
 			newID, err := s.mutator.ForkSession(sessionID)
 			if err != nil {
@@ -244,6 +269,46 @@ func (s *Server) handleSessionByAction(w http.ResponseWriter, r *http.Request) {
 	writeAPIError(w, http.StatusNotFound, ErrCodeNotFound, "route not found")
 }
 
+// maxSendMessageBytes caps the body of POST /api/sessions/{id}/send.
+const maxSendMessageBytes = 64 << 10
+
+// handleSessionSend is POST /api/sessions/{id}/send — types a message into
+// the session's pane, e.g. to answer a waiting agent from a phone. Auth,
+// read-only and rate limiting are enforced by the caller.
+//
+//   - 400 if the body is malformed or the message is blank
+//   - 404 if the session does not exist
+//   - 409 if the session is not running
+func (s *Server) handleSessionSend(w http.ResponseWriter, r *http.Request, sessionID string) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSendMessageBytes))
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, ErrCodeBadRequest, "request body too large or unreadable")
+		return
+	}
+	var req SendMessageRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeAPIError(w, http.StatusBadRequest, ErrCodeBadRequest, "invalid JSON body")
+		return
+	}
+	if strings.TrimSpace(req.Message) == "" {
+		writeAPIError(w, http.StatusBadRequest, ErrCodeBadRequest, "message is required")
+		return
+	}
+	if err := s.mutator.SendMessage(sessionID, req.Message); err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			writeAPIError(w, http.StatusNotFound, ErrCodeNotFound, err.Error())
+		case strings.Contains(err.Error(), "not running"):
+			writeAPIError(w, http.StatusConflict, ErrCodeBadRequest, err.Error())
+		default:
+			writeAPIError(w, http.StatusInternalServerError, ErrCodeInternalError, err.Error())
+		}
+		return
+	}
+	s.notifyMenuChanged()
+	writeJSON(w, http.StatusOK, SessionActionResponse{SessionID: sessionID})
+}
+
 // undeleteResponse is the JSON body returned from POST /api/sessions/undelete.
 type undeleteResponse struct {
 	SessionID string `json:"sessionId"`
@@ -19,6 +19,7 @@ type fakeMutator struct {
 	startSessionFn     func(id string) error
 	stopSessionFn      func(id string) error
 	restartSessionFn   func(id string) error
+	sendMessageFn      func(id, message string) error
 	deleteSessionFn    func(id string) error
 	closeSessionFn     func(id string) error
 	archiveSessionFn   func(id string) error
@@ -60,6 +61,13 @@ func (f *fakeMutator) RestartSession(id string) error {
 	return f.restartSessionFn(id)
 }
 
+func (f *fakeMutator) SendMessage(id, message string) error {
+	if f.sendMessageFn == nil {
+		return fmt.Errorf("sendMessage not configured")
+	}
+	return f.sendMessageFn(id, message)
+}
+
 func (f *fakeMutator) DeleteSession(id string) error {
 	if f.deleteSessionFn == nil {
 		return fmt.Errorf("deleteSession not configured")
@@ -184,6 +192,87 @@ func TestSessionsCollectionGET(t *testing.T) {
 	}
 }
 
+func TestSessionsCollectionGETFiltersSortsAndPages(t *testing.T) {
+	srv := NewServer(Config{
+		ListenAddr: "127.0.0.1:0",
+		Profile:    "test",
+	})
+	now := time.Now()
+	sess := func(id, group, tool string, status session.Status, waiting time.Duration) MenuItem {
+		s := &MenuSession{ID: id, Title: id, GroupPath: group, Tool: tool, Status: status}
+		if waiting > 0 {
+			s.WaitingSince = now.Add(-waiting)
+		}
+		return MenuItem{Type: MenuItemTypeSession, Session: s}
+	}
+	srv.menuData = &fakeMenuDataLoader{
+		snapshot: &MenuSnapshot{
+			Profile: "test",
+			Items: []MenuItem{
+				{Type: MenuItemTypeGroup, Group: &MenuGroup{Name: "work", Path: "work"}},
+				sess("w-short", "work", "claude", session.StatusWaiting, time.Minute),
+				sess("w-run", "work", "claude", session.StatusRunning, 0),
+				{Type: MenuItemTypeGroup, Group: &MenuGroup{Name: "api", Path: "work/api"}},
+				sess("w-long", "work/api", "claude", session.StatusWaiting, time.Hour),
+				sess("w-codex", "work/api", "codex", session.StatusWaiting, 2*time.Hour),
+				{Type: MenuItemTypeGroup, Group: &MenuGroup{Name: "home", Path: "home"}},
+				sess("h-wait", "home", "claude", session.StatusWaiting, 3*time.Hour),
+			},
+		},
+	}
+
+	get := func(query string) (int, sessionsListResponse) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/sessions"+query, nil)
+		rr := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rr, req)
+		var resp sessionsListResponse
+		if rr.Code == http.StatusOK {
+			if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode %s: %v", query, err)
+			}
+		}
+		return rr.Code, resp
+	}
+	ids := func(resp sessionsListResponse) string {
+		var out []string
+		for _, s := range resp.Sessions {
+			out = append(out, s.ID)
+		}
+		return strings.Join(out, ",")
+	}
+
+	code, resp := get("?status=waiting&group=work&tool=claude&sort=waiting_since")
+	if code != http.StatusOK {
+		t.Fatalf("status %d", code)
+	}
+	if got := ids(resp); got != "w-long,w-short" || resp.Total != 2 {
+		t.Errorf("filtered = %s (total %d), want w-long,w-short (total 2)", got, resp.Total)
+	}
+	if len(resp.Groups) != 2 {
+		t.Errorf("groups = %d, want work and work/api only", len(resp.Groups))
+	}
+
+	_, resp = get("?status=waiting&sort=waiting_since&limit=2&offset=1")
+	if got := ids(resp); got != "w-codex,w-long" || resp.Total != 4 || resp.Limit != 2 || resp.Offset != 1 {
+		t.Errorf("page = %s (total %d, limit %d, offset %d), want w-codex,w-long of 4", got, resp.Total, resp.Limit, resp.Offset)
+	}
+	_, resp = get("?offset=10")
+	if resp.Sessions == nil || len(resp.Sessions) != 0 || resp.Total != 5 {
+		t.Errorf("past the end = %+v, want an empty page of 5", resp)
+	}
+	_, resp = get("?sort=-waiting_since&tool=claude,codex")
+	if got := ids(resp); got != "w-short,w-long,w-codex,h-wait,w-run" {
+		t.Errorf("descending = %s, want unknown waiting time last", got)
+	}
+
+	for _, bad := range []string{"?status=bogus", "?sort=size", "?limit=-1", "?limit=5000", "?offset=x"} {
+		if code, _ := get(bad); code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, want 400", bad, code)
+		}
+	}
+}
+
 func TestSessionsCollectionPOSTCreatesSession(t *testing.T) {
 	srv := NewServer(Config{
 		ListenAddr:   "127.0.0.1:0",
@@ -402,6 +491,131 @@ func TestSessionRestartOK(t *testing.T) {
 	}
 }
 
+func TestSessionSendOK(t *testing.T) {
+	srv := NewServer(Config{
+		ListenAddr:   "127.0.0.1:0",
+		WebMutations: true,
+	})
+	srv.menuData = &fakeMenuDataLoader{snapshot: &MenuSnapshot{}}
+	var gotID, gotMsg string
+	srv.mutator = &fakeMutator{
+		sendMessageFn: func(id, message string) error {
+			gotID, gotMsg = id, message
+			return nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/sessions/test-id/send", strings.NewReader(`{"message":"yes, continue"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if gotID != "test-id" || gotMsg != "yes, continue" {
+		t.Fatalf("mutator got (%q, %q), want (test-id, \"yes, continue\")", gotID, gotMsg)
+	}
+}
+
+func TestSessionSendRejectsBadRequests(t *testing.T) {
+	srv := NewServer(Config{
+		ListenAddr:   "127.0.0.1:0",
+		WebMutations: true,
+	})
+	srv.menuData = &fakeMenuDataLoader{snapshot: &MenuSnapshot{}}
+	srv.mutator = &fakeMutator{
+		sendMessageFn: func(id, message string) error {
+			switch id {
+			case "missing":
+				return fmt.Errorf("session not found: %s", id)
+			case "stopped":
+				return fmt.Errorf("session is not running: %s", id)
+			}
+			return nil
+		},
+	}
+
+	cases := []struct {
+		name string
+		id   string
+		body string
+		want int
+	}{
+		{"blank message", "test-id", `{"message":"   "}`, http.StatusBadRequest},
+		{"malformed JSON", "test-id", `{"message":`, http.StatusBadRequest},
+		{"unknown session", "missing", `{"message":"hi"}`, http.StatusNotFound},
+		{"stopped session", "stopped", `{"message":"hi"}`, http.StatusConflict},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/sessions/"+tc.id+"/send", strings.NewReader(tc.body))
+			rr := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(rr, req)
+			if rr.Code != tc.want {
+				t.Fatalf("expected status %d, got %d: %s", tc.want, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestSessionSendGatedByTokenAndReadOnly(t *testing.T) {
+	called := false
+	mut := &fakeMutator{
+		sendMessageFn: func(id, message string) error {
+			called = true
+			return nil
+		},
+	}
+
+	tokenSrv := NewServer(Config{
+		ListenAddr:   "127.0.0.1:0",
+		Token:        "secret-token",
+		WebMutations: true,
+	})
+	tokenSrv.menuData = &fakeMenuDataLoader{snapshot: &MenuSnapshot{}}
+	tokenSrv.mutator = mut
+	req := httptest.NewRequest(http.MethodPost, "/api/sessions/test-id/send", strings.NewReader(`{"message":"hi"}`))
+	// Same-origin so the request clears CSRF and reaches the auth check.
+	req.Header.Set("Origin", "http://example.com")
+	rr := httptest.NewRecorder()
+	tokenSrv.Handler().ServeHTTP(rr, req)
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("send without token: expected 401, got %d", rr.Code)
+	}
+	if called {
+		t.Fatal("mutator must not be reached without a valid token")
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/sessions/test-id/send", strings.NewReader(`{"message":"hi"}`))
+	req.Header.Set("Origin", "http://example.com")
+	req.Header.Set("Authorization", "Bearer secret-token")
+	rr = httptest.NewRecorder()
+	tokenSrv.Handler().ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK || !called {
+		t.Fatalf("send with token: expected 200 and delivery, got %d (called=%v)", rr.Code, called)
+	}
+	called = false
+
+	readOnlySrv := NewServer(Config{
+		ListenAddr:   "127.0.0.1:0",
+		ReadOnly:     true,
+		WebMutations: false,
+	})
+	readOnlySrv.menuData = &fakeMenuDataLoader{snapshot: &MenuSnapshot{}}
+	readOnlySrv.mutator = mut
+	req = httptest.NewRequest(http.MethodPost, "/api/sessions/test-id/send", strings.NewReader(`{"message":"hi"}`))
+	rr = httptest.NewRecorder()
+	readOnlySrv.Handler().ServeHTTP(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("send in read-only mode: expected 403, got %d", rr.Code)
+	}
+
+	if called {
+		t.Fatal("mutator must not be reached in read-only mode")
+	}
+}
+
 func TestSessionForkOK(t *testing.T) {
 	srv := NewServer(Config{
 		ListenAddr:   "127.0.0.1:0",
@@ -0,0 +1,84 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+type savedViewsResponse struct {
+	Views []savedView `json:"views"`
+}
+
+type savedViewPutRequest struct {
+	Spec    json.RawMessage `json:"spec"`
+	Default bool            `json:"default"`
+}
+
+// handleSavedViews serves GET /api/views: the caller's saved sessions-table
+// views.
+func (s *Server) handleSavedViews(w http.ResponseWriter, r *http.Request) {
+	if !s.authorizeRequest(r) {
+		writeAPIError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "unauthorized")
+		return
+	}
+	if s.views == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, ErrCodeNotImplemented, "saved views are not available")
+		return
+	}
+	views, err := s.views.List(savedViewOwner(s.cfg.Token))
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, ErrCodeInternalError, "failed to load saved views")
+		return
+	}
+	writeJSON(w, http.StatusOK, savedViewsResponse{Views: views})
+}
+
+// handleSavedView serves PUT and DELETE /api/views/{name}. Saved views are a
+// display preference, like push subscriptions, so they are not gated by
+// web mutations.
+func (s *Server) handleSavedView(w http.ResponseWriter, r *http.Request) {
+	if !s.authorizeRequest(r) {
+		writeAPIError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "unauthorized")
+		return
+	}
+	if s.views == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, ErrCodeNotImplemented, "saved views are not available")
+		return
+	}
+	owner := savedViewOwner(s.cfg.Token)
+	name := strings.TrimSpace(r.PathValue("name"))
+
+	switch r.Method {
+	case http.MethodPut:
+		var req savedViewPutRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 2*maxSavedViewSpecBytes)).Decode(&req); err != nil {
+			writeAPIError(w, http.StatusBadRequest, ErrCodeBadRequest, "invalid view payload")
+			return
+		}
+		view, err := s.views.Put(owner, savedView{Name: name, Spec: req.Spec, Default: req.Default})
+		if err != nil {
+			if errors.Is(err, errSavedViewLimit) {
+				writeAPIError(w, http.StatusConflict, ErrCodeBadRequest, err.Error())
+				return
+			}
+			writeAPIError(w, http.StatusBadRequest, ErrCodeBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, view)
+	case http.MethodDelete:
+		removed, err := s.views.Delete(owner, name)
+		if err != nil {
+			writeAPIError(w, http.StatusInternalServerError, ErrCodeInternalError, "failed to delete saved view")
+			return
+		}
+		if !removed {
+			writeAPIError(w, http.StatusNotFound, ErrCodeNotFound, "saved view not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+	default:
+		writeAPIError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed, "method not allowed")
+	}
+}
@@ -0,0 +1,406 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/logging"
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// Inbound CI webhooks. A GitHub repository (or org) webhook subscribed to
+// "Workflow runs" / "Workflow jobs" — or a `curl` step at the end of a
+// GitHub Actions workflow — POSTs to /api/webhooks/github when CI finishes.
+// Failed runs are routed by repository to a session configured under
+// [web.webhooks] in config.toml: the session is created (or started, when it
+// already exists) and sent a prompt rendered from the run's details.
+//
+// Deliveries authenticate with the X-Hub-Signature-256 HMAC instead of the
+// web token, so GitHub can call the endpoint without holding a bearer token.
+// Like the Command Center's /ask, the work goes through the supported CLI
+// primitives (`launch`, `session start`, `session send`) rather than typing
+// into tmux directly.
+
+// webhookPathPrefix is the route prefix for inbound webhooks. csrfProtect
+// exempts it: deliveries come from GitHub, not a browser, and are
+// authenticated by their signature.
+const webhookPathPrefix = "/api/webhooks/"
+
+// webhookMaxBody caps a delivery. GitHub's own limit is 25MB, but workflow
+// payloads are a few KB.
+const webhookMaxBody = 1 << 20
+
+// webhookRunTimeout bounds one delivery's CLI work. `session send` waits for
+// the agent to become ready, which can take a while after a cold start.
+const webhookRunTimeout = 5 * time.Minute
+
+// webhookFailedConclusions are the CI conclusions that dispatch a session.
+var webhookFailedConclusions = map[string]bool{
+	"failure":   true,
+	"timed_out": true,
+}
+
+// WebhookEvent is the normalized view of a CI delivery. Its fields are the
+// data available to a route's title and prompt templates.
+type WebhookEvent struct {
+	Event      string // "workflow_run" or "workflow_job"
+	Repo       string // owner/name
+	Workflow   string
+	Job        string // empty for workflow_run
+	Branch     string
+	SHA        string // abbreviated to 12 characters
+	Conclusion string
+	URL        string
+	Actor      string
+	RunNumber  int
+}
+
+type ghWorkflowRunPayload struct {
+	Action      string `json:"action"`
+	WorkflowRun struct {
+		Name       string `json:"name"`
+		HeadBranch string `json:"head_branch"`
+		HeadSHA    string `json:"head_sha"`
+		Conclusion string `json:"conclusion"`
+		HTMLURL    string `json:"html_url"`
+		RunNumber  int    `json:"run_number"`
+		Actor      struct {
+			Login string `json:"login"`
+		} `json:"actor"`
+	} `json:"workflow_run"`
+	WorkflowJob struct {
+		Name         string `json:"name"`
+		WorkflowName string `json:"workflow_name"`
+		HeadBranch   string `json:"head_branch"`
+		HeadSHA      string `json:"head_sha"`
+		Conclusion   string `json:"conclusion"`
+		HTMLURL      string `json:"html_url"`
+	} `json:"workflow_job"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Sender struct {
+		Login string `json:"login"`
+	} `json:"sender"`
+}
+
+// parseWebhookEvent normalizes a workflow_run or workflow_job delivery.
+// It returns a non-empty reason instead of an event for deliveries that
+// never dispatch: other event types, runs that are not completed, and
+// successful runs.
+func parseWebhookEvent(eventType string, body []byte) (evt WebhookEvent, reason string, err error) {
+	if eventType != "workflow_run" && eventType != "workflow_job" {
+		return WebhookEvent{}, fmt.Sprintf("event %q is not handled", eventType), nil
+	}
+	var p ghWorkflowRunPayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return WebhookEvent{}, "", fmt.Errorf("github webhook %s: unmarshal failed: %w", eventType, err)
+	}
+	if p.Action != "completed" {
+		return WebhookEvent{}, fmt.Sprintf("action %q is not completed", p.Action), nil
+	}
+
+	evt = WebhookEvent{
+		Event: eventType,
+		Repo:  p.Repository.FullName,
+		Actor: p.Sender.Login,
+	}
+	if eventType == "workflow_run" {
+		run := p.WorkflowRun
+		evt.Workflow = run.Name
+		evt.Branch = run.HeadBranch
+		evt.SHA = run.HeadSHA
+		evt.Conclusion = run.Conclusion
+		evt.URL = run.HTMLURL
+		evt.RunNumber = run.RunNumber
+		if run.Actor.Login != "" {
+			evt.Actor = run.Actor.Login
+		}
+	} else {
+		job := p.WorkflowJob
+		evt.Workflow = job.WorkflowName
+		evt.Job = job.Name
+		evt.Branch = job.HeadBranch
+		evt.SHA = job.HeadSHA
+		evt.Conclusion = job.Conclusion
+		evt.URL = job.HTMLURL
+	}
+	if len(evt.SHA) > 12 {
+		evt.SHA = evt.SHA[:12]
+	}
+	if evt.Repo == "" {
+		return WebhookEvent{}, "", fmt.Errorf("github webhook %s: repository.full_name is missing", eventType)
+	}
+	if !webhookFailedConclusions[evt.Conclusion] {
+		return WebhookEvent{}, fmt.Sprintf("conclusion %q is not a failure", evt.Conclusion), nil
+	}
+	return evt, "", nil
+}
+
+// matchWebhookRoute returns the first route whose repo pattern matches repo,
+// or nil.
+func matchWebhookRoute(routes []session.WebhookRoute, repo string) *session.WebhookRoute {
+	repo = strings.ToLower(repo)
+	for i := range routes {
+		pattern := strings.ToLower(strings.TrimSpace(routes[i].Repo))
+		if pattern == "" {
+			continue
+		}
+		if ok, err := path.Match(pattern, repo); err == nil && ok {
+			return &routes[i]
+		}
+	}
+	return nil
+}
+
+// renderWebhookTemplate executes tmpl (or fallback when tmpl is empty) over
+// evt. missingkey=error turns a typo like {{.Brnach}} into an error instead
+// of "<no value>" in the prompt.
+func renderWebhookTemplate(name, tmpl, fallback string, evt WebhookEvent) (string, error) {
+	if strings.TrimSpace(tmpl) == "" {
+		tmpl = fallback
+	}
+	t, err := template.New(name).Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("webhook %s template: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, evt); err != nil {
+		return "", fmt.Errorf("webhook %s template: %w", name, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// webhookDispatch is the work planned for one delivery: the CLI invocations
+// to run, in order, and the session they target.
+type webhookDispatch struct {
+	Title     string
+	Group     string
+	SessionID string // empty when a new session is launched
+	Commands  [][]string
+}
+
+// planWebhookDispatch resolves the route's session against the current
+// sessions and returns the CLI commands that deliver prompt to it: an
+// existing session (same title in the route's group) is started when
+// stopped and sent the prompt; otherwise a new one is launched with the
+// prompt as its initial message.
+func planWebhookDispatch(route *session.WebhookRoute, title, prompt string, snapshot *MenuSnapshot) webhookDispatch {
+	group := strings.Trim(strings.TrimSpace(route.Group), "/")
+	d := webhookDispatch{Title: title, Group: group}
+	if snapshot != nil {
+		for _, item := range snapshot.Items {
+			if item.Type != MenuItemTypeSession || item.Session == nil {
+				continue
+			}
+			ms := item.Session
+			if ms.Title != title || ms.GroupPath != group {
+				continue
+			}
+			d.SessionID = ms.ID
+			switch ms.Status {
+			case session.StatusStopped, session.StatusError, session.StatusExited, session.StatusCrashed:
+				d.Commands = append(d.Commands, []string{"session", "start", ms.ID})
+			}
+			d.Commands = append(d.Commands, []string{"session", "send", ms.ID, prompt})
+			return d
+		}
+	}
+
+	tool := strings.TrimSpace(route.Tool)
+	if tool == "" {
+		tool = "claude"
+	}
+	launch := []string{"launch", route.Path, "--title", title, "--cmd", tool, "--message", prompt, "--no-parent"}
+	if group != "" {
+		launch = append(launch, "--group", group)
+	}
+	d.Commands = append(d.Commands, launch)
+	return d
+}
+
+// verifyWebhookSignature checks a GitHub "sha256=<hex>" signature over body
+// in constant time.
+func verifyWebhookSignature(secret, signature string, body []byte) bool {
+	hexSig, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	expected, err := hex.DecodeString(hexSig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// webhookResponse is the JSON body for an accepted or ignored delivery.
+type webhookResponse struct {
+	Status    string `json:"status"` // "dispatched" or "ignored"
+	Reason    string `json:"reason,omitempty"`
+	Session   string `json:"session,omitempty"`
+	Group     string `json:"group,omitempty"`
+	SessionID string `json:"sessionId,omitempty"`
+	Delivery  string `json:"delivery,omitempty"`
+}
+
+// handleGitHubWebhook serves POST /api/webhooks/github.
+func (s *Server) handleGitHubWebhook(w http.ResponseWriter, r *http.Request) {
+	settings := s.webhookSettings()
+	if settings.Secret == "" {
+		// Unconfigured endpoints look absent rather than unauthenticated.
+		writeAPIError(w, http.StatusNotFound, ErrCodeNotFound, "webhooks are not configured")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, webhookMaxBody))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeAPIError(w, http.StatusRequestEntityTooLarge, ErrCodeBadRequest, "request body too large")
+			return
+		}
+		writeAPIError(w, http.StatusBadRequest, ErrCodeBadRequest, "invalid request body")
+		return
+	}
+	signature := r.Header.Get("X-Hub-Signature-256")
+	if signature == "" || !verifyWebhookSignature(settings.Secret, signature, body) {
+		writeAPIError(w, http.StatusUnauthorized, ErrCodeUnauthorized, "invalid signature")
+		return
+	}
+	if !s.checkMutationsAllowed(w) {
+		return
+	}
+	if !s.checkMutationRateLimit(w) {
+		return
+	}
+
+	webLog := logging.ForComponent(logging.CompWeb)
+	delivery := r.Header.Get("X-GitHub-Delivery")
+	eventType := r.Header.Get("X-GitHub-Event")
+	if eventType == "ping" {
+		writeJSON(w, http.StatusOK, webhookResponse{Status: "ignored", Reason: "pong", Delivery: delivery})
+		return
+	}
+
+	evt, reason, err := parseWebhookEvent(eventType, body)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, ErrCodeBadRequest, err.Error())
+		return
+	}
+	if reason != "" {
+		writeJSON(w, http.StatusAccepted, webhookResponse{Status: "ignored", Reason: reason, Delivery: delivery})
+		return
+	}
+	route := matchWebhookRoute(settings.Routes, evt.Repo)
+	if route == nil {
+		// Org-wide hooks deliver for every repository; unrouted ones are
+		// expected, so this is not an error.
+		writeJSON(w, http.StatusAccepted, webhookResponse{Status: "ignored", Reason: "no route for " + evt.Repo, Delivery: delivery})
+		return
+	}
+
+	title, err := renderWebhookTemplate("title", route.Title, session.DefaultWebhookTitle, evt)
+	if err == nil && title == "" {
+		err = fmt.Errorf("webhook title template rendered empty")
+	}
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, ErrCodeInternalError, err.Error())
+		return
+	}
+	prompt, err := renderWebhookTemplate("prompt", route.Prompt, session.DefaultWebhookPrompt, evt)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, ErrCodeInternalError, err.Error())
+		return
+	}
+
+	snapshot, err := s.menuData.LoadMenuSnapshot()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, ErrCodeInternalError, "failed to load session data")
+		return
+	}
+	dispatch := planWebhookDispatch(route, title, prompt, snapshot)
+	if dispatch.SessionID == "" && strings.TrimSpace(route.Path) == "" {
+		writeAPIError(w, http.StatusInternalServerError, ErrCodeInternalError,
+			fmt.Sprintf("webhook route for %s has no path to create a session in", route.Repo))
+		return
+	}
+
+	webLog.Info("webhook_dispatch",
+		slog.String("delivery", delivery),
+		slog.String("event", evt.Event),
+		slog.String("repo", evt.Repo),
+		slog.String("workflow", evt.Workflow),
+		slog.String("session", dispatch.Title),
+		slog.String("group", dispatch.Group),
+	)
+	// GitHub gives up on a delivery after 10s; `session send` can wait far
+	// longer for a cold agent, so the work runs after the 202.
+	go s.runWebhookDispatch(delivery, dispatch)
+
+	writeJSON(w, http.StatusAccepted, webhookResponse{
+		Status:    "dispatched",
+		Session:   dispatch.Title,
+		Group:     dispatch.Group,
+		SessionID: dispatch.SessionID,
+		Delivery:  delivery,
+	})
+}
+
+// runWebhookDispatch runs a dispatch's CLI commands in order, stopping at
+// the first failure.
+func (s *Server) runWebhookDispatch(delivery string, d webhookDispatch) {
+	ctx, cancel := context.WithTimeout(s.baseCtx, webhookRunTimeout)
+	defer cancel()
+	webLog := logging.ForComponent(logging.CompWeb)
+	for _, args := range d.Commands {
+		if out, err := s.webhookRunner(ctx, append([]string{"-p", s.cfg.Profile}, args...)); err != nil {
+			webLog.Warn("webhook_dispatch_failed",
+				slog.String("delivery", delivery),
+				slog.String("session", d.Title),
+				slog.String("step", strings.Join(args[:min(2, len(args))], " ")),
+				slog.String("error", err.Error()),
+				slog.String("output", strings.TrimSpace(string(out))),
+			)
+			return
+		}
+	}
+	s.notifyMenuChanged()
+}
+
+// webhookSettings returns the configured webhook settings: Config.Webhooks
+// when set (tests, embedding), otherwise config.toml's [web.webhooks].
+func (s *Server) webhookSettings() session.WebhookSettings {
+	if s.cfg.Webhooks != nil {
+		return *s.cfg.Webhooks
+	}
+	return session.GetWebhookSettings()
+}
+
+// runAgentDeckCLI runs the agent-deck binary with args, the way
+// handleCommandCenterAsk does. Text is passed as argv, never through a shell.
+func runAgentDeckCLI(ctx context.Context, args []string) ([]byte, error) {
+	exe, err := os.Executable()
+	if err != nil || exe == "" {
+		exe = "agent-deck"
+	}
+	cmd := exec.CommandContext(ctx, exe, args...)
+	cmd.Env = os.Environ()
+	return cmd.CombinedOutput()
+}
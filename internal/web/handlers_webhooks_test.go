@@ -0,0 +1,237 @@
+package web
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+const testWebhookSecret = "ci-secret"
+
+const failedRunPayload = `{
+  "action": "completed",
+  "workflow_run": {
+    "name": "CI",
+    "head_branch": "main",
+    "head_sha": "0123456789abcdef0123",
+    "conclusion": "failure",
+    "html_url": "https://github.com/acme/api/actions/runs/42",
+    "run_number": 42,
+    "actor": {"login": "octo"}
+  },
+  "repository": {"full_name": "acme/api"},
+  "sender": {"login": "octo"}
+}`
+
+func signWebhook(body string) string {
+	mac := hmac.New(sha256.New, []byte(testWebhookSecret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookTestServer returns a server with one acme/api route and a runner
+// that records each CLI invocation on calls.
+func webhookTestServer(t *testing.T, snapshot *MenuSnapshot, token string) (*Server, chan []string) {
+	t.Helper()
+	srv := NewServer(Config{
+		ListenAddr:   "127.0.0.1:0",
+		Profile:      "work",
+		WebMutations: true,
+		Token:        token,
+		Webhooks: &session.WebhookSettings{
+			Secret: testWebhookSecret,
+			Routes: []session.WebhookRoute{{
+				Repo:   "acme/*",
+				Group:  "ci/api",
+				Path:   "/src/api",
+				Prompt: "{{.Workflow}} failed on {{.Branch}} at {{.SHA}}: {{.URL}}",
+			}},
+		},
+	})
+	if snapshot == nil {
+		snapshot = &MenuSnapshot{}
+	}
+	srv.menuData = &fakeMenuDataLoader{snapshot: snapshot}
+	calls := make(chan []string, 4)
+	srv.webhookRunner = func(_ context.Context, args []string) ([]byte, error) {
+		calls <- args
+		return nil, nil
+	}
+	return srv, calls
+}
+
+func postWebhook(srv *Server, event, body, signature string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/webhooks/github", strings.NewReader(body))
+	req.Header.Set("X-GitHub-Event", event)
+	req.Header.Set("X-GitHub-Delivery", "d-1")
+	if signature != "" {
+		req.Header.Set("X-Hub-Signature-256", signature)
+	}
+	rr := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rr, req)
+	return rr
+}
+
+func nextCall(t *testing.T, calls chan []string) []string {
+	t.Helper()
+	select {
+	case args := <-calls:
+		return args
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a CLI invocation")
+		return nil
+	}
+}
+
+func TestGitHubWebhook_RejectsBadSignature(t *testing.T) {
+	// A token is configured to prove the endpoint bypasses bearer auth and
+	// fail-closed CSRF, relying on the signature alone.
+	srv, calls := webhookTestServer(t, nil, "web-token")
+
+	if rr := postWebhook(srv, "workflow_run", failedRunPayload, ""); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("unsigned delivery: status %d, want 401", rr.Code)
+	}
+	if rr := postWebhook(srv, "workflow_run", failedRunPayload, "sha256=deadbeef"); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("bad signature: status %d, want 401", rr.Code)
+	}
+	if rr := postWebhook(srv, "workflow_run", failedRunPayload, signWebhook(failedRunPayload)); rr.Code != http.StatusAccepted {
+		t.Fatalf("signed delivery: status %d, want 202: %s", rr.Code, rr.Body.String())
+	}
+	nextCall(t, calls)
+}
+
+func TestGitHubWebhook_DisabledWithoutSecret(t *testing.T) {
+	srv, _ := webhookTestServer(t, nil, "")
+	srv.cfg.Webhooks = &session.WebhookSettings{}
+	if rr := postWebhook(srv, "workflow_run", failedRunPayload, signWebhook(failedRunPayload)); rr.Code != http.StatusNotFound {
+		t.Fatalf("status %d, want 404 when no secret is configured", rr.Code)
+	}
+}
+
+func TestGitHubWebhook_LaunchesRoutedSession(t *testing.T) {
+	srv, calls := webhookTestServer(t, nil, "")
+	rr := postWebhook(srv, "workflow_run", failedRunPayload, signWebhook(failedRunPayload))
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("status %d: %s", rr.Code, rr.Body.String())
+	}
+	var resp webhookResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Status != "dispatched" || resp.Session != "ci-CI" || resp.Group != "ci/api" {
+		t.Fatalf("response = %+v", resp)
+	}
+
+	want := []string{"-p", "work", "launch", "/src/api", "--title", "ci-CI", "--cmd", "claude",
+		"--message", "CI failed on main at 0123456789ab: https://github.com/acme/api/actions/runs/42",
+		"--no-parent", "--group", "ci/api"}
+	if got := nextCall(t, calls); !reflect.DeepEqual(got, want) {
+		t.Fatalf("launch args =\n  %q\nwant\n  %q", got, want)
+	}
+}
+
+func TestPlanWebhookDispatch_RestartsEndedSessions(t *testing.T) {
+	route := &session.WebhookRoute{Group: "ci"}
+	for _, status := range []session.Status{session.StatusStopped, session.StatusError, session.StatusExited, session.StatusCrashed} {
+		snapshot := &MenuSnapshot{Items: []MenuItem{
+			{Type: MenuItemTypeSession, Session: &MenuSession{ID: "sess-1", Title: "fix", GroupPath: "ci", Status: status}},
+		}}
+		d := planWebhookDispatch(route, "fix", "go", snapshot)
+		if len(d.Commands) != 2 || !reflect.DeepEqual(d.Commands[0], []string{"session", "start", "sess-1"}) {
+			t.Errorf("%s session: commands = %q, want start then send", status, d.Commands)
+		}
+	}
+}
+
+func TestGitHubWebhook_ReusesExistingSession(t *testing.T) {
+	snapshot := &MenuSnapshot{Items: []MenuItem{
+		{Type: MenuItemTypeSession, Session: &MenuSession{
+			ID: "sess-1", Title: "ci-CI", GroupPath: "ci/api", Status: session.StatusStopped,
+		}},
+	}}
+	srv, calls := webhookTestServer(t, snapshot, "")
+	if rr := postWebhook(srv, "workflow_run", failedRunPayload, signWebhook(failedRunPayload)); rr.Code != http.StatusAccepted {
+		t.Fatalf("status %d: %s", rr.Code, rr.Body.String())
+	}
+	if got := nextCall(t, calls); !reflect.DeepEqual(got, []string{"-p", "work", "session", "start", "sess-1"}) {
+		t.Fatalf("first call = %q, want session start", got)
+	}
+	if got := nextCall(t, calls); len(got) != 6 || got[2] != "session" || got[3] != "send" || got[4] != "sess-1" {
+		t.Fatalf("second call = %q, want session send", got)
+	}
+}
+
+func TestGitHubWebhook_IgnoresNonFailures(t *testing.T) {
+	srv, calls := webhookTestServer(t, nil, "")
+	cases := map[string]struct{ event, body string }{
+		"success":     {"workflow_run", strings.Replace(failedRunPayload, `"failure"`, `"success"`, 1)},
+		"in progress": {"workflow_run", strings.Replace(failedRunPayload, `"completed"`, `"in_progress"`, 1)},
+		"other event": {"push", `{"ref":"refs/heads/main"}`},
+		"other repo":  {"workflow_run", strings.Replace(failedRunPayload, `"acme/api"`, `"other/api"`, 1)},
+		"ping":        {"ping", `{"zen":"hi"}`},
+	}
+	for name, tc := range cases {
+		rr := postWebhook(srv, tc.event, tc.body, signWebhook(tc.body))
+		if rr.Code != http.StatusAccepted && rr.Code != http.StatusOK {
+			t.Errorf("%s: status %d", name, rr.Code)
+			continue
+		}
+		var resp webhookResponse
+		_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+		if resp.Status != "ignored" {
+			t.Errorf("%s: status %q, want ignored", name, resp.Status)
+		}
+	}
+	select {
+	case args := <-calls:
+		t.Fatalf("ignored deliveries must not run the CLI, got %q", args)
+	default:
+	}
+}
+
+func TestGitHubWebhook_ForbiddenWhenMutationsDisabled(t *testing.T) {
+	srv, _ := webhookTestServer(t, nil, "")
+	srv.cfg.WebMutations = false
+	if rr := postWebhook(srv, "workflow_run", failedRunPayload, signWebhook(failedRunPayload)); rr.Code != http.StatusForbidden {
+		t.Fatalf("status %d, want 403", rr.Code)
+	}
+}
+
+func TestParseWebhookEvent_WorkflowJob(t *testing.T) {
+	body := `{"action":"completed","workflow_job":{"name":"test (linux)","workflow_name":"CI",
+		"head_branch":"feat","head_sha":"abc","conclusion":"timed_out","html_url":"https://x/job/1"},
+		"repository":{"full_name":"acme/api"},"sender":{"login":"octo"}}`
+	evt, reason, err := parseWebhookEvent("workflow_job", []byte(body))
+	if err != nil || reason != "" {
+		t.Fatalf("parse: reason=%q err=%v", reason, err)
+	}
+	want := WebhookEvent{Event: "workflow_job", Repo: "acme/api", Workflow: "CI", Job: "test (linux)",
+		Branch: "feat", SHA: "abc", Conclusion: "timed_out", URL: "https://x/job/1", Actor: "octo"}
+	if evt != want {
+		t.Fatalf("event = %+v\nwant    %+v", evt, want)
+	}
+}
+
+func TestRenderWebhookTemplate_DefaultsAndMissingKeys(t *testing.T) {
+	evt := WebhookEvent{Repo: "acme/api", Workflow: "CI", Branch: "main", SHA: "abc", Conclusion: "failure", URL: "u"}
+	prompt, err := renderWebhookTemplate("prompt", "", session.DefaultWebhookPrompt, evt)
+	if err != nil {
+		t.Fatalf("default prompt: %v", err)
+	}
+	if !strings.Contains(prompt, `workflow "CI" concluded failure on main`) || strings.Contains(prompt, "job") {
+		t.Fatalf("default prompt = %q", prompt)
+	}
+	if _, err := renderWebhookTemplate("prompt", "{{.Brnach}}", session.DefaultWebhookPrompt, evt); err == nil {
+		t.Fatal("a misspelled field should fail to render")
+	}
+}
@@ -506,6 +506,15 @@ func (s *parityStore) StopSession(id string) error    { return s.transition(id,
 func (s *parityStore) RestartSession(id string) error { return s.transition(id, session.StatusRunning) }
 func (s *parityStore) CloseSession(id string) error   { return s.transition(id, session.StatusStopped) }
 
+func (s *parityStore) SendMessage(id, message string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.sessions[id]; !ok {
+		return errNotFound(id)
+	}
+	return nil
+}
+
 func (s *parityStore) ArchiveSession(id string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -529,7 +529,7 @@ func (p *pushService) syncOnce(ctx context.Context) {
 		}
 
 		sessionMeta := sessions[sessionID]
-		if sessionMeta == nil {
+		if sessionMeta == nil || sessionMeta.OffHours {
 			continue
 		}
 		transitions = append(transitions, pushTransition{
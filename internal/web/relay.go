@@ -0,0 +1,35 @@
+package web
+
+import (
+	"log/slog"
+
+	"github.com/asheshgoplani/agent-deck/internal/logging"
+	"github.com/asheshgoplani/agent-deck/internal/relay"
+)
+
+// startRelay registers this deck on cfg.RelayURL and forwards paired
+// devices' streams to the local listener. It runs until Shutdown cancels the
+// base context; relay outages are retried in the background and never take
+// the web server down.
+func (s *Server) startRelay() error {
+	dir, err := relay.DefaultDir()
+	if err != nil {
+		return err
+	}
+	id, store, err := relay.OpenDeck(dir)
+	if err != nil {
+		return err
+	}
+	host := &relay.Host{
+		RelayURL: s.cfg.RelayURL,
+		Identity: id,
+		Store:    store,
+		Target:   s.cfg.ListenAddr,
+	}
+	logging.ForComponent(logging.CompWeb).Info("relay_enabled",
+		slog.String("relay", s.cfg.RelayURL),
+		slog.String("deck_id", id.ID()),
+		slog.Int("paired_devices", len(store.Devices())))
+	go func() { _ = host.Run(s.baseCtx) }()
+	return nil
+}
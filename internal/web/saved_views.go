@@ -0,0 +1,213 @@
+package web
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+const (
+	savedViewsFileName = "web_views.json"
+
+	// maxSavedViewNameLen, maxSavedViewSpecBytes and maxSavedViewsPerOwner
+	// bound what one client can store in the profile directory.
+	maxSavedViewNameLen   = 64
+	maxSavedViewSpecBytes = 4 << 10
+	maxSavedViewsPerOwner = 50
+)
+
+// savedView is a named sessions-table configuration. Spec is owned by the
+// web client (sort, filters, search) and stored verbatim; the server only
+// checks that it is a bounded JSON object.
+type savedView struct {
+	Name      string          `json:"name"`
+	Spec      json.RawMessage `json:"spec"`
+	Default   bool            `json:"default,omitempty"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+func (v savedView) validate() error {
+	if v.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if utf8.RuneCountInString(v.Name) > maxSavedViewNameLen {
+		return fmt.Errorf("name is longer than %d characters", maxSavedViewNameLen)
+	}
+	if len(v.Spec) > maxSavedViewSpecBytes {
+		return fmt.Errorf("spec is larger than %d bytes", maxSavedViewSpecBytes)
+	}
+	var obj map[string]json.RawMessage
+	if len(v.Spec) == 0 || json.Unmarshal(v.Spec, &obj) != nil || obj == nil {
+		return fmt.Errorf("spec must be a JSON object")
+	}
+	return nil
+}
+
+// savedViewsFile is the on-disk shape: views grouped by owner (see
+// savedViewOwner).
+type savedViewsFile struct {
+	UpdatedAt time.Time              `json:"updatedAt"`
+	Owners    map[string][]savedView `json:"owners"`
+}
+
+// errSavedViewLimit is returned when an owner already has
+// maxSavedViewsPerOwner views.
+var errSavedViewLimit = fmt.Errorf("at most %d saved views", maxSavedViewsPerOwner)
+
+// savedViewStore persists saved views in the profile directory, next to the
+// push subscriptions. The web server is bound to one profile, so a view is
+// implicitly scoped to it.
+type savedViewStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+func newSavedViewStore(profile string) (*savedViewStore, error) {
+	profileDir, err := session.GetProfileDir(session.GetEffectiveProfile(profile))
+	if err != nil {
+		return nil, fmt.Errorf("resolve profile dir: %w", err)
+	}
+	return &savedViewStore{path: filepath.Join(profileDir, savedViewsFileName)}, nil
+}
+
+// savedViewOwner keys views by the credential that authorized the request:
+// a hash of the bearer token, or "local" when the server runs without one.
+// The token itself never reaches disk.
+func savedViewOwner(token string) string {
+	if token == "" {
+		return "local"
+	}
+	sum := sha256.Sum256([]byte(token))
+	return "token:" + hex.EncodeToString(sum[:8])
+}
+
+// List returns the owner's views sorted by name.
+func (s *savedViewStore) List(owner string) ([]savedView, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := s.readLocked()
+	if err != nil {
+		return nil, err
+	}
+	out := append([]savedView{}, data.Owners[owner]...)
+	sort.Slice(out, func(i, j int) bool { return strings.ToLower(out[i].Name) < strings.ToLower(out[j].Name) })
+	return out, nil
+}
+
+// Put creates or replaces the owner's view with v.Name. Marking it default
+// clears the flag on the owner's other views.
+func (s *savedViewStore) Put(owner string, v savedView) (savedView, error) {
+	v.Name = strings.TrimSpace(v.Name)
+	if err := v.validate(); err != nil {
+		return savedView{}, err
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, v.Spec); err == nil {
+		v.Spec = compact.Bytes()
+	}
+	v.UpdatedAt = time.Now().UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := s.readLocked()
+	if err != nil {
+		return savedView{}, err
+	}
+	views := data.Owners[owner]
+	replaced := false
+	for i := range views {
+		if views[i].Name == v.Name {
+			views[i] = v
+			replaced = true
+		} else if v.Default {
+			views[i].Default = false
+		}
+	}
+	if !replaced {
+		if len(views) >= maxSavedViewsPerOwner {
+			return savedView{}, errSavedViewLimit
+		}
+		views = append(views, v)
+	}
+	data.Owners[owner] = views
+	return v, s.writeLocked(data)
+}
+
+// Delete removes the owner's view named name and reports whether it existed.
+func (s *savedViewStore) Delete(owner, name string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := s.readLocked()
+	if err != nil {
+		return false, err
+	}
+	views := data.Owners[owner]
+	kept := views[:0]
+	for _, v := range views {
+		if v.Name != name {
+			kept = append(kept, v)
+		}
+	}
+	if len(kept) == len(views) {
+		return false, nil
+	}
+	if len(kept) == 0 {
+		delete(data.Owners, owner)
+	} else {
+		data.Owners[owner] = kept
+	}
+	return true, s.writeLocked(data)
+}
+
+func (s *savedViewStore) readLocked() (*savedViewsFile, error) {
+	raw, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &savedViewsFile{Owners: map[string][]savedView{}}, nil
+		}
+		return nil, fmt.Errorf("read saved views: %w", err)
+	}
+	var data savedViewsFile
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("parse saved views: %w", err)
+	}
+	if data.Owners == nil {
+		data.Owners = map[string][]savedView{}
+	}
+	return &data, nil
+}
+
+func (s *savedViewStore) writeLocked(data *savedViewsFile) error {
+	data.UpdatedAt = time.Now().UTC()
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("mkdir saved views dir: %w", err)
+	}
+	raw, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal saved views: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return fmt.Errorf("write temp saved views: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("rename saved views: %w", err)
+	}
+	return nil
+}
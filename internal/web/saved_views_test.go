@@ -0,0 +1,157 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestSavedViewStore(t *testing.T) *savedViewStore {
+	t.Helper()
+	return &savedViewStore{path: filepath.Join(t.TempDir(), savedViewsFileName)}
+}
+
+func TestSavedViewStore_PutListDelete(t *testing.T) {
+	store := newTestSavedViewStore(t)
+
+	if _, err := store.Put("local", savedView{Name: " waiting ", Spec: json.RawMessage(`{ "statuses": ["waiting"] }`)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Put("local", savedView{Name: "all", Spec: json.RawMessage(`{}`), Default: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Put("token:other", savedView{Name: "theirs", Spec: json.RawMessage(`{}`)}); err != nil {
+		t.Fatal(err)
+	}
+
+	views, err := store.List("local")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(views) != 2 || views[0].Name != "all" || views[1].Name != "waiting" {
+		t.Fatalf("views = %+v, want [all waiting]", views)
+	}
+	var spec struct{ Statuses []string }
+	if err := json.Unmarshal(views[1].Spec, &spec); err != nil || len(spec.Statuses) != 1 {
+		t.Errorf("spec = %s, want statuses round-tripped", views[1].Spec)
+	}
+
+	info, err := os.Stat(store.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	removed, err := store.Delete("local", "waiting")
+	if err != nil || !removed {
+		t.Fatalf("Delete = %v, %v", removed, err)
+	}
+	if removed, _ := store.Delete("local", "waiting"); removed {
+		t.Error("second Delete reported a removal")
+	}
+	if theirs, _ := store.List("token:other"); len(theirs) != 1 {
+		t.Errorf("other owner's views = %+v, want untouched", theirs)
+	}
+}
+
+func TestSavedViewStore_DefaultIsExclusive(t *testing.T) {
+	store := newTestSavedViewStore(t)
+	for _, name := range []string{"a", "b"} {
+		if _, err := store.Put("local", savedView{Name: name, Spec: json.RawMessage(`{}`), Default: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	views, _ := store.List("local")
+	if views[0].Default || !views[1].Default {
+		t.Errorf("views = %+v, want only b default", views)
+	}
+}
+
+func TestSavedViewStore_Validation(t *testing.T) {
+	store := newTestSavedViewStore(t)
+	for _, v := range []savedView{
+		{Name: "", Spec: json.RawMessage(`{}`)},
+		{Name: strings.Repeat("x", maxSavedViewNameLen+1), Spec: json.RawMessage(`{}`)},
+		{Name: "array", Spec: json.RawMessage(`[]`)},
+		{Name: "null", Spec: json.RawMessage(`null`)},
+		{Name: "big", Spec: json.RawMessage(`{"q":"` + strings.Repeat("x", maxSavedViewSpecBytes) + `"}`)},
+	} {
+		if _, err := store.Put("local", v); err == nil {
+			t.Errorf("Put(%.20q) accepted an invalid view", v.Name)
+		}
+	}
+
+	for i := 0; i < maxSavedViewsPerOwner; i++ {
+		if _, err := store.Put("local", savedView{Name: fmt.Sprintf("v%d", i), Spec: json.RawMessage(`{}`)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := store.Put("local", savedView{Name: "one-too-many", Spec: json.RawMessage(`{}`)}); err != errSavedViewLimit {
+		t.Errorf("Put over limit = %v, want errSavedViewLimit", err)
+	}
+	// Replacing an existing view is still allowed at the limit.
+	if _, err := store.Put("local", savedView{Name: "v0", Spec: json.RawMessage(`{"q":"x"}`)}); err != nil {
+		t.Errorf("replace at limit: %v", err)
+	}
+}
+
+func TestSavedViewOwner_HashesToken(t *testing.T) {
+	if got := savedViewOwner(""); got != "local" {
+		t.Errorf("owner without token = %q", got)
+	}
+	got := savedViewOwner("secret")
+	if !strings.HasPrefix(got, "token:") || strings.Contains(got, "secret") || got == savedViewOwner("other") {
+		t.Errorf("owner(secret) = %q", got)
+	}
+}
+
+func TestSavedViewsHandlers(t *testing.T) {
+	srv := NewServer(Config{ListenAddr: "127.0.0.1:0", Token: "secret"})
+	srv.views = newTestSavedViewStore(t)
+
+	do := func(method, path, body string, auth bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "http://127.0.0.1:8420"+path, strings.NewReader(body))
+		req.Header.Set("Origin", "http://127.0.0.1:8420")
+		if auth {
+			req.Header.Set("Authorization", "Bearer secret")
+		}
+		rr := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rr, req)
+		return rr
+	}
+
+	if rr := do(http.MethodGet, "/api/views", "", false); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("GET without token = %d, want 401", rr.Code)
+	}
+
+	rr := do(http.MethodPut, "/api/views/prod%20waiting", `{"spec":{"statuses":["waiting"],"sort":{"key":"waiting","dir":"asc"}},"default":true}`, true)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("PUT = %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = do(http.MethodGet, "/api/views", "", true)
+	var resp savedViewsResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v (%s)", err, rr.Body.String())
+	}
+	if len(resp.Views) != 1 || resp.Views[0].Name != "prod waiting" || !resp.Views[0].Default {
+		t.Fatalf("views = %+v", resp.Views)
+	}
+
+	if rr := do(http.MethodPut, "/api/views/bad", `{"spec":"nope"}`, true); rr.Code != http.StatusBadRequest {
+		t.Errorf("PUT with non-object spec = %d, want 400", rr.Code)
+	}
+	if rr := do(http.MethodDelete, "/api/views/prod%20waiting", "", true); rr.Code != http.StatusOK {
+		t.Errorf("DELETE = %d, want 200", rr.Code)
+	}
+	if rr := do(http.MethodDelete, "/api/views/prod%20waiting", "", true); rr.Code != http.StatusNotFound {
+		t.Errorf("second DELETE = %d, want 404", rr.Code)
+	}
+}
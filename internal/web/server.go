@@ -33,6 +33,26 @@ type Config struct {
 	PushVAPIDPrivateKey string
 	PushVAPIDSubject    string
 	PushTestInterval    time.Duration
+	// Webhooks overrides config.toml's [web.webhooks] for the inbound CI
+	// webhook endpoint. nil reads config.toml on each delivery.
+	Webhooks *session.WebhookSettings
+	// RelayURL registers the deck on a self-hosted relay so paired devices
+	// reach this server end-to-end encrypted without an exposed port.
+	RelayURL string
+	// TLSCertFile and TLSKeyFile serve HTTPS instead of plain HTTP.
+	TLSCertFile string
+	TLSKeyFile  string
+	// TLSClientCAFile turns on mutual TLS: clients must present a
+	// certificate signed by this CA, which then counts as authentication.
+	TLSClientCAFile string
+	// TrustedProxies lists reverse-proxy addresses (IPs or CIDRs) whose
+	// TrustedProxyHeader (default X-Forwarded-User) is taken as proof the
+	// proxy already authenticated the user.
+	TrustedProxies     []string
+	TrustedProxyHeader string
+	// ShareSecret signs read-only share links. Empty reads the profile's
+	// web_share_secret (see share_links.go); set by tests.
+	ShareSecret []byte
 }
 
 // DefaultUndoWindow is the default Chrome-style undo grace period for
@@ -103,6 +123,10 @@ type SessionMutator interface {
 	StartSession(sessionID string) error
 	StopSession(sessionID string) error
 	RestartSession(sessionID string) error
+	// SendMessage types message into the session's pane and presses Enter,
+	// the same guarded delivery the conductor uses. Returns an error
+	// containing "not running" when the session has no live tmux pane.
+	SendMessage(sessionID, message string) error
 	DeleteSession(sessionID string) error
 	// CloseSession stops the session process while keeping its metadata
 	// in storage (TUI Shift+D — non-destructive close).
@@ -142,6 +166,7 @@ type Server struct {
 	httpServer  *http.Server
 	menuData    MenuDataLoader
 	push        pushServiceAPI
+	views       *savedViewStore
 	baseCtx     context.Context
 	cancelBase  context.CancelFunc
 	hookWatcher *session.StatusFileWatcher
@@ -159,6 +184,18 @@ type Server struct {
 	// whose hook file is present on disk. Defaults to defaultLoadHookStatuses
 	// (which reads ~/.agent-deck/hooks/) but is injectable for tests.
 	hookStatusLoader func() map[string]*session.HookStatus
+
+	// webhookRunner runs the agent-deck CLI for inbound webhook dispatches.
+	// Defaults to runAgentDeckCLI; injectable for tests.
+	webhookRunner func(ctx context.Context, args []string) ([]byte, error)
+
+	// paneStreams shares tmux control pipes between /ws/pane viewers.
+	paneStreams *paneStreamHub
+
+	// trustedProxies is cfg.TrustedProxies parsed; trustedProxiesErr
+	// makes Start refuse a config with an unparseable entry.
+	trustedProxies    []*net.IPNet
+	trustedProxiesErr error
 }
 
 // NewServer creates a new web server with base routes and middleware.
@@ -184,14 +221,22 @@ func NewServer(cfg Config) *Server {
 		menuSubscribers:  make(map[chan struct{}]struct{}),
 		mutationLimiter:  mutationLimiter,
 		hookStatusLoader: defaultLoadHookStatuses,
+		webhookRunner:    runAgentDeckCLI,
 	}
+	s.trustedProxies, s.trustedProxiesErr = ParseTrustedProxies(cfg.TrustedProxies)
 	s.baseCtx, s.cancelBase = context.WithCancel(context.Background())
+	s.paneStreams = newPaneStreamHub(s.baseCtx)
 	webLog := logging.ForComponent(logging.CompWeb)
 	if pushSvc, err := newPushService(cfg, menuData); err != nil {
 		webLog.Warn("push_disabled", slog.String("error", err.Error()))
 	} else {
 		s.push = pushSvc
 	}
+	if views, err := newSavedViewStore(cfg.Profile); err != nil {
+		webLog.Warn("saved_views_disabled", slog.String("error", err.Error()))
+	} else {
+		s.views = views
+	}
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", s.handleIndex)
@@ -235,12 +280,20 @@ func NewServer(cfg Config) *Server {
 	mux.HandleFunc("/api/groups/", s.handleGroupByPath)
 	mux.HandleFunc("/api/settings", s.handleSettings)
 	mux.HandleFunc("/api/profiles", s.handleProfiles)
+	mux.HandleFunc("GET /api/views", s.handleSavedViews)
+	mux.HandleFunc("/api/views/{name}", s.handleSavedView)
 	mux.HandleFunc("/api/push/config", s.handlePushConfig)
 	mux.HandleFunc("/api/push/subscribe", s.handlePushSubscribe)
 	mux.HandleFunc("/api/push/unsubscribe", s.handlePushUnsubscribe)
 	mux.HandleFunc("/api/push/presence", s.handlePushPresence)
 	mux.HandleFunc("/events/menu", s.handleMenuEvents)
 	mux.HandleFunc("/ws/session/", s.handleSessionWS)
+	mux.HandleFunc("/ws/pane/", s.handlePaneStreamWS)
+	// Share links authenticate with their signed token rather than the web
+	// token and only reach the one session they name; see share_links.go.
+	mux.HandleFunc("GET /share/{token}", s.handleSharePage)
+	mux.HandleFunc("GET /share/{token}/ws", s.handleShareStream)
+	mux.HandleFunc("GET /share/{token}/transcript", s.handleShareTranscript)
 
 	// Command Center (the embedded live fleet god-view — see
 	// conductor/agent-deck/COMMAND-CENTER-DESIGN.md). Two read endpoints and
@@ -270,6 +323,9 @@ func NewServer(cfg Config) *Server {
 	mux.HandleFunc("POST /api/sessions/{id}/mcps/{name}", s.handleSessionMCPsRouter)
 	mux.HandleFunc("DELETE /api/sessions/{id}/mcps/{name}", s.handleSessionMCPsRouter)
 	mux.HandleFunc("PATCH /api/sessions/{id}/mcps/{name}", s.handleSessionMCPsRouter)
+	// Inbound CI webhooks authenticate with an HMAC signature rather than the
+	// web token; see handlers_webhooks.go.
+	mux.HandleFunc("POST "+webhookPathPrefix+"github", s.handleGitHubWebhook)
 
 	handler := withRecover(s.csrfProtect(mux))
 
@@ -302,6 +358,14 @@ func (s *Server) Start() error {
 	if err := s.checkBindSecurity(); err != nil {
 		return err
 	}
+	if s.trustedProxiesErr != nil {
+		return s.trustedProxiesErr
+	}
+	tlsCfg, err := buildTLSConfig(s.cfg)
+	if err != nil {
+		return err
+	}
+	s.httpServer.TLSConfig = tlsCfg
 
 	webLog := logging.ForComponent(logging.CompWeb)
 	if watcher, err := session.NewStatusFileWatcher(func() {
@@ -319,7 +383,17 @@ func (s *Server) Start() error {
 	if s.push != nil {
 		s.push.Start(s.baseCtx)
 	}
-	err := s.httpServer.ListenAndServe()
+	if s.cfg.RelayURL != "" {
+		if err := s.startRelay(); err != nil {
+			return fmt.Errorf("relay: %w", err)
+		}
+	}
+	if tlsCfg != nil {
+		// Certificates are already loaded into TLSConfig.
+		err = s.httpServer.ListenAndServeTLS("", "")
+	} else {
+		err = s.httpServer.ListenAndServe()
+	}
 	if s.hookWatcher != nil {
 		s.hookWatcher.Stop()
 		s.hookWatcher = nil
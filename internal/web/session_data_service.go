@@ -82,6 +82,9 @@ type MenuSession struct {
 	CreatedAt      time.Time `json:"createdAt"`
 	LastAccessedAt time.Time `json:"lastAccessedAt,omitempty"`
 	ArchivedAt     time.Time `json:"archivedAt,omitempty"`
+	// WaitingSince is when a waiting session entered waiting, for sorting
+	// by how long it has needed input. Zero for other statuses.
+	WaitingSince time.Time `json:"waitingSince,omitempty"`
 
 	// Fields below mirror *session.Instance state visible in the TUI
 	// EditSessionDialog. Promoted from MISSING in tests/web/PARITY_MATRIX.md
@@ -125,6 +128,12 @@ type MenuSession struct {
 	TitleLocked        bool `json:"titleLocked,omitempty"`
 	NoTransitionNotify bool `json:"noTransitionNotify,omitempty"`
 
+	// WorkingHours is the per-session working hours override; OffHours
+	// reports that the session is outside its effective window, so its
+	// notifications are muted.
+	WorkingHours string `json:"workingHours,omitempty"`
+	OffHours     bool   `json:"offHours,omitempty"`
+
 	LoadedMCPNames []string `json:"loadedMcpNames,omitempty"`
 
 	// claude_analytics has no underlying struct on *Instance so the matrix
@@ -250,6 +259,7 @@ func toMenuSession(inst *session.Instance) *MenuSession {
 		CreatedAt:          inst.CreatedAt,
 		LastAccessedAt:     inst.LastAccessedAt,
 		ArchivedAt:         inst.ArchivedAt,
+		WaitingSince:       menuWaitingSince(inst),
 		IsConductor:        inst.IsConductor,
 		ClaudeSessionID:    inst.ClaudeSessionID,
 		GeminiSessionID:    inst.GeminiSessionID,
@@ -278,11 +288,26 @@ func toMenuSession(inst *session.Instance) *MenuSession {
 		WorktreeBranch:     inst.WorktreeBranch,
 		TitleLocked:        inst.TitleLocked,
 		NoTransitionNotify: inst.NoTransitionNotify,
+		WorkingHours:       inst.WorkingHours,
+		OffHours:           inst.OutsideWorkingHours(time.Now()),
 		LoadedMCPNames:     inst.LoadedMCPNames,
 		GeminiAnalytics:    inst.GeminiAnalytics,
 	}
 }
 
+// menuWaitingSince returns when inst entered waiting, or zero when it is not
+// waiting or no transition was observed. Unlike Instance.GetWaitingSince it
+// does not fall back to CreatedAt, which would misreport the wait.
+func menuWaitingSince(inst *session.Instance) time.Time {
+	if inst.GetStatusThreadSafe() != session.StatusWaiting {
+		return time.Time{}
+	}
+	if tmuxSess := inst.GetTmuxSession(); tmuxSess != nil {
+		return tmuxSess.GetWaitingSince()
+	}
+	return time.Time{}
+}
+
 type rawHookStatus struct {
 	Status    string `json:"status"`
 	SessionID string `json:"session_id"`
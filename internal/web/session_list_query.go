@@ -0,0 +1,229 @@
+package web
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// maxSessionListLimit bounds one page of GET /api/sessions.
+const maxSessionListLimit = 1000
+
+// sessionListStatusRank is the attention order used by sort=status, the same
+// as the web sessions table: the statuses that need a human come first.
+var sessionListStatusRank = map[session.Status]int{
+	session.StatusWaiting:  0,
+	session.StatusError:    1,
+	session.StatusCrashed:  1,
+	session.StatusRunning:  2,
+	session.StatusStarting: 3,
+	session.StatusQueued:   4,
+	session.StatusIdle:     5,
+	session.StatusStopped:  6,
+	session.StatusExited:   6,
+}
+
+// sessionListSortKeys are the accepted sort= values. "order" is the menu
+// order and the default.
+var sessionListSortKeys = map[string]bool{
+	"order": true, "title": true, "status": true, "group": true, "tool": true,
+	"waiting_since": true, "last_accessed": true, "created": true,
+}
+
+// sessionListQuery is the parsed query string of GET /api/sessions:
+//
+//	?status=waiting,error&group=work&tool=claude&q=api&sort=-created&limit=50&offset=0
+//
+// status and tool take comma-separated lists (or repeat the parameter);
+// group matches the group and its subgroups; q is a case-insensitive
+// substring of title, group, tool or path. sort takes a key from
+// sessionListSortKeys, prefixed with "-" for descending. Time keys sort
+// oldest first ascending, so sort=waiting_since puts the longest-waiting
+// session first; sessions without the time sort last either way. limit 0
+// (the default) returns every match.
+type sessionListQuery struct {
+	statuses map[session.Status]bool
+	tools    map[string]bool
+	group    string
+	q        string
+	sortKey  string
+	desc     bool
+	limit    int
+	offset   int
+}
+
+// listValues splits repeated and comma-separated values of a parameter.
+func listValues(v url.Values, key string) []string {
+	var out []string
+	for _, raw := range v[key] {
+		for _, s := range strings.Split(raw, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+// parseSessionListQuery validates the query string. Errors are meant for a
+// 400 response.
+func parseSessionListQuery(v url.Values) (sessionListQuery, error) {
+	q := sessionListQuery{
+		group:   strings.Trim(strings.TrimSpace(v.Get("group")), "/"),
+		q:       strings.ToLower(strings.TrimSpace(v.Get("q"))),
+		sortKey: "order",
+	}
+	for _, s := range listValues(v, "status") {
+		status := session.Status(strings.ToLower(s))
+		if _, ok := sessionListStatusRank[status]; !ok {
+			return q, fmt.Errorf("unknown status %q", s)
+		}
+		if q.statuses == nil {
+			q.statuses = make(map[session.Status]bool)
+		}
+		q.statuses[status] = true
+	}
+	for _, t := range listValues(v, "tool") {
+		if q.tools == nil {
+			q.tools = make(map[string]bool)
+		}
+		q.tools[strings.ToLower(t)] = true
+	}
+	if s := strings.TrimSpace(v.Get("sort")); s != "" {
+		q.desc = strings.HasPrefix(s, "-")
+		q.sortKey = strings.TrimPrefix(s, "-")
+		if !sessionListSortKeys[q.sortKey] {
+			return q, fmt.Errorf("unknown sort key %q", q.sortKey)
+		}
+	}
+	var err error
+	if q.limit, err = intParam(v, "limit", maxSessionListLimit); err != nil {
+		return q, err
+	}
+	if q.offset, err = intParam(v, "offset", -1); err != nil {
+		return q, err
+	}
+	return q, nil
+}
+
+// intParam parses a non-negative integer parameter, at most limit (limit < 0
+// for no bound). A missing parameter is 0.
+func intParam(v url.Values, key string, limit int) (int, error) {
+	raw := strings.TrimSpace(v.Get(key))
+	if raw == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(raw)
+	switch {
+	case limit >= 0 && (err != nil || n < 0 || n > limit):
+		return 0, fmt.Errorf("%s must be an integer between 0 and %d", key, limit)
+	case err != nil || n < 0:
+		return 0, fmt.Errorf("%s must be a non-negative integer", key)
+	}
+	return n, nil
+}
+
+// inGroup reports whether groupPath is q's group or one of its subgroups.
+func (q sessionListQuery) inGroup(groupPath string) bool {
+	return q.group == "" || groupPath == q.group || strings.HasPrefix(groupPath, q.group+"/")
+}
+
+func (q sessionListQuery) matches(s *MenuSession) bool {
+	if q.statuses != nil && !q.statuses[s.Status] {
+		return false
+	}
+	if q.tools != nil && !q.tools[strings.ToLower(s.Tool)] {
+		return false
+	}
+	if !q.inGroup(s.GroupPath) {
+		return false
+	}
+	if q.q != "" {
+		hay := strings.ToLower(s.Title + " " + s.GroupPath + " " + s.Tool + " " + s.ProjectPath)
+		if !strings.Contains(hay, q.q) {
+			return false
+		}
+	}
+	return true
+}
+
+// apply filters, sorts and pages sessions (in menu order). total is the
+// number of matches before paging.
+func (q sessionListQuery) apply(sessions []*MenuSession) (page []*MenuSession, total int) {
+	matched := make([]*MenuSession, 0, len(sessions))
+	for _, s := range sessions {
+		if q.matches(s) {
+			matched = append(matched, s)
+		}
+	}
+	if q.sortKey != "order" {
+		sort.SliceStable(matched, func(i, j int) bool { return q.less(matched[i], matched[j]) })
+	} else if q.desc {
+		for i, j := 0, len(matched)-1; i < j; i, j = i+1, j-1 {
+			matched[i], matched[j] = matched[j], matched[i]
+		}
+	}
+	total = len(matched)
+	if q.offset >= total {
+		return []*MenuSession{}, total
+	}
+	matched = matched[q.offset:]
+	if q.limit > 0 && len(matched) > q.limit {
+		matched = matched[:q.limit]
+	}
+	return matched, total
+}
+
+// less orders a before b under q's sort key, breaking ties by title and then
+// ID so pages are stable between requests.
+func (q sessionListQuery) less(a, b *MenuSession) bool {
+	var c int
+	switch q.sortKey {
+	case "title":
+	case "status":
+		c = rankOf(a.Status) - rankOf(b.Status)
+	case "group":
+		c = strings.Compare(strings.ToLower(a.GroupPath), strings.ToLower(b.GroupPath))
+	case "tool":
+		c = strings.Compare(strings.ToLower(a.Tool), strings.ToLower(b.Tool))
+	case "waiting_since", "last_accessed", "created":
+		ta, tb := sessionListTime(a, q.sortKey), sessionListTime(b, q.sortKey)
+		if ta.IsZero() != tb.IsZero() {
+			return tb.IsZero() // unknown times last in either direction
+		}
+		c = ta.Compare(tb)
+	}
+	if c == 0 {
+		c = strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+	}
+	if c == 0 {
+		c = strings.Compare(a.ID, b.ID)
+	}
+	if q.desc {
+		return c > 0
+	}
+	return c < 0
+}
+
+func rankOf(status session.Status) int {
+	if r, ok := sessionListStatusRank[status]; ok {
+		return r
+	}
+	return len(sessionListStatusRank)
+}
+
+func sessionListTime(s *MenuSession, key string) time.Time {
+	switch key {
+	case "waiting_since":
+		return s.WaitingSince
+	case "last_accessed":
+		return s.LastAccessedAt
+	default:
+		return s.CreatedAt
+	}
+}
@@ -0,0 +1,429 @@
+package web
+
+// Read-only observer share links (/share/<token>).
+//
+// A share link lets whoever holds the URL watch one session's live pane and
+// read its transcript until the link expires, without the web token and
+// without seeing any other session. The token is
+//
+//	<expires-unix>.<sig>.<session-id>
+//
+// where sig is base64url(HMAC-SHA256("share:<session-id>:<expires-unix>"))
+// keyed by a per-profile secret kept in web_share_secret next to the VAPID
+// keys. Nothing is stored per link, so the CLI can mint links while the
+// server runs elsewhere, and rotating the secret (agent-deck web share
+// --revoke-all) invalidates every outstanding link at once.
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+const shareSecretFileName = "web_share_secret"
+
+const (
+	// DefaultShareTTL is how long a share link lives when no TTL is given.
+	DefaultShareTTL = time.Hour
+	// MaxShareTTL caps a share link's lifetime. Links can't be revoked one
+	// by one, so they must not outlive the work they were shared for.
+	MaxShareTTL = 7 * 24 * time.Hour
+)
+
+var (
+	// ErrShareLinkInvalid is returned for a malformed or forged token.
+	ErrShareLinkInvalid = errors.New("invalid share link")
+	// ErrShareLinkExpired is returned for a correctly signed, expired token.
+	ErrShareLinkExpired = errors.New("share link expired")
+)
+
+func shareSecretPath(profile string) (string, error) {
+	profileDir, err := session.GetProfileDir(session.GetEffectiveProfile(profile))
+	if err != nil {
+		return "", fmt.Errorf("resolve profile dir: %w", err)
+	}
+	return filepath.Join(profileDir, shareSecretFileName), nil
+}
+
+// EnsureShareSecret returns the profile's share-link signing secret,
+// generating and persisting one on first use.
+func EnsureShareSecret(profile string) ([]byte, error) {
+	path, err := shareSecretPath(profile)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := loadShareSecret(path)
+	if err == nil {
+		return secret, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return writeShareSecret(path)
+}
+
+// RotateShareSecret replaces the profile's signing secret, invalidating
+// every share link issued so far.
+func RotateShareSecret(profile string) error {
+	path, err := shareSecretPath(profile)
+	if err != nil {
+		return err
+	}
+	_, err = writeShareSecret(path)
+	return err
+}
+
+func loadShareSecret(path string) ([]byte, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, os.ErrNotExist
+		}
+		return nil, fmt.Errorf("read share secret: %w", err)
+	}
+	secret, err := hex.DecodeString(strings.TrimSpace(string(raw)))
+	if err != nil || len(secret) < 32 {
+		return nil, fmt.Errorf("share secret %s is corrupt; remove it or run 'agent-deck web share --revoke-all'", path)
+	}
+	return secret, nil
+}
+
+func writeShareSecret(path string) ([]byte, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("generate share secret: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("create share secret dir: %w", err)
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(hex.EncodeToString(secret)+"\n"), 0o600); err != nil {
+		return nil, fmt.Errorf("write share secret: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return nil, fmt.Errorf("save share secret: %w", err)
+	}
+	return secret, nil
+}
+
+func shareSignature(secret []byte, sessionID string, expiresUnix int64) string {
+	mac := hmac.New(sha256.New, secret)
+	fmt.Fprintf(mac, "share:%s:%d", sessionID, expiresUnix)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// NewShareToken signs a read-only share token for sessionID that expires at
+// expires (truncated to the second).
+func NewShareToken(secret []byte, sessionID string, expires time.Time) string {
+	exp := expires.Unix()
+	return strconv.FormatInt(exp, 10) + "." + shareSignature(secret, sessionID, exp) + "." + sessionID
+}
+
+// VerifyShareToken checks token's signature and expiry and returns the
+// session it grants. The signature is checked first, so a forged token never
+// learns whether its expiry would have passed.
+func VerifyShareToken(secret []byte, token string, now time.Time) (sessionID string, expires time.Time, err error) {
+	parts := strings.SplitN(token, ".", 3)
+	if len(parts) != 3 || parts[2] == "" || len(secret) == 0 {
+		return "", time.Time{}, ErrShareLinkInvalid
+	}
+	exp, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return "", time.Time{}, ErrShareLinkInvalid
+	}
+	sessionID = parts[2]
+	if !hmac.Equal([]byte(parts[1]), []byte(shareSignature(secret, sessionID, exp))) {
+		return "", time.Time{}, ErrShareLinkInvalid
+	}
+	expires = time.Unix(exp, 0)
+	if !now.Before(expires) {
+		return "", time.Time{}, ErrShareLinkExpired
+	}
+	return sessionID, expires, nil
+}
+
+// ShareLinkPath returns the server path of a share token's observer page.
+func ShareLinkPath(token string) string {
+	return "/share/" + token
+}
+
+// ValidateShareTTL rejects lifetimes outside (0, MaxShareTTL].
+func ValidateShareTTL(ttl time.Duration) error {
+	if ttl <= 0 {
+		return fmt.Errorf("share link TTL must be positive")
+	}
+	if ttl > MaxShareTTL {
+		return fmt.Errorf("share link TTL %s exceeds the maximum of %s", ttl, MaxShareTTL)
+	}
+	return nil
+}
+
+// shareSecret returns the signing secret used to verify share links. It is
+// re-read per request so links minted (or revoked) by the CLI take effect
+// without restarting the server. A profile that never shared anything has no
+// secret, and every token is invalid.
+func (s *Server) shareSecret() ([]byte, error) {
+	if len(s.cfg.ShareSecret) > 0 {
+		return s.cfg.ShareSecret, nil
+	}
+	path, err := shareSecretPath(s.cfg.Profile)
+	if err != nil {
+		return nil, err
+	}
+	return loadShareSecret(path)
+}
+
+// resolveShare verifies the {token} path value and returns the session it
+// grants and when the grant ends. On failure it has already written the
+// error response: 404 for a bad token or missing session (so tokens can't be
+// probed), 410 for an expired one.
+func (s *Server) resolveShare(w http.ResponseWriter, r *http.Request) (*MenuSession, time.Time, bool) {
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Robots-Tag", "noindex")
+
+	secret, err := s.shareSecret()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		writeAPIError(w, http.StatusInternalServerError, ErrCodeInternalError, "failed to load share secret")
+		return nil, time.Time{}, false
+	}
+	sessionID, expires, err := VerifyShareToken(secret, r.PathValue("token"), time.Now())
+	if errors.Is(err, ErrShareLinkExpired) {
+		writeAPIError(w, http.StatusGone, ErrCodeShareExpired, "share link expired")
+		return nil, time.Time{}, false
+	}
+	if err != nil {
+		writeAPIError(w, http.StatusNotFound, ErrCodeNotFound, "share link not found")
+		return nil, time.Time{}, false
+	}
+
+	snapshot, err := s.menuData.LoadMenuSnapshot()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, ErrCodeInternalError, "failed to load session data")
+		return nil, time.Time{}, false
+	}
+	menuSession, found := snapshotSessionByID(snapshot, sessionID)
+	if !found {
+		writeAPIError(w, http.StatusNotFound, ErrCodeNotFound, "share link not found")
+		return nil, time.Time{}, false
+	}
+	return menuSession, expires, true
+}
+
+// handleSharePage serves the observer page of a share link.
+func (s *Server) handleSharePage(w http.ResponseWriter, r *http.Request) {
+	menuSession, expires, ok := s.resolveShare(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.WriteHeader(http.StatusOK)
+	_ = sharePageTemplate.Execute(w, map[string]any{
+		"Title":     menuSession.Title,
+		"Tool":      menuSession.Tool,
+		"Status":    string(menuSession.Status),
+		"Base":      ShareLinkPath(r.PathValue("token")),
+		"ExpiresAt": expires.UTC().Format(time.RFC3339),
+	})
+}
+
+// handleShareStream is the share link's read-only pane stream: the same
+// wire format as /ws/pane/<id>, closed when the link expires.
+func (s *Server) handleShareStream(w http.ResponseWriter, r *http.Request) {
+	menuSession, expires, ok := s.resolveShare(w, r)
+	if !ok {
+		return
+	}
+	s.servePaneStream(w, r, menuSession.ID, expires)
+}
+
+// handleShareTranscript returns the shared session's recorded transcript.
+func (s *Server) handleShareTranscript(w http.ResponseWriter, r *http.Request) {
+	menuSession, expires, ok := s.resolveShare(w, r)
+	if !ok {
+		return
+	}
+	entries, err := session.ReadTranscript(menuSession.ID, time.Time{})
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		writeAPIError(w, http.StatusInternalServerError, ErrCodeInternalError, "failed to read transcript")
+		return
+	}
+	if entries == nil {
+		entries = []session.TranscriptEntry{}
+	}
+	writeJSON(w, http.StatusOK, ShareTranscriptResponse{
+		SessionID: menuSession.ID,
+		Title:     menuSession.Title,
+		ExpiresAt: expires.UTC(),
+		Entries:   entries,
+	})
+}
+
+// handleSessionShare is POST /api/sessions/{id}/share — mints a share link.
+// It hands out read access only, so it is allowed in read-only mode; the
+// caller has already been authorized.
+func (s *Server) handleSessionShare(w http.ResponseWriter, r *http.Request, sessionID string) {
+	if r.Method != http.MethodPost {
+		writeAPIError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed, "method not allowed")
+		return
+	}
+	var req ShareLinkRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+			writeAPIError(w, http.StatusBadRequest, ErrCodeBadRequest, "invalid JSON body")
+			return
+		}
+	}
+	ttl := DefaultShareTTL
+	if req.TTL != "" {
+		d, err := time.ParseDuration(req.TTL)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, ErrCodeBadRequest, "ttl must be a duration like 30m or 4h")
+			return
+		}
+		ttl = d
+	}
+	if err := ValidateShareTTL(ttl); err != nil {
+		writeAPIError(w, http.StatusBadRequest, ErrCodeBadRequest, err.Error())
+		return
+	}
+
+	snapshot, err := s.menuData.LoadMenuSnapshot()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, ErrCodeInternalError, "failed to load session data")
+		return
+	}
+	if _, found := snapshotSessionByID(snapshot, sessionID); !found {
+		writeAPIError(w, http.StatusNotFound, ErrCodeNotFound, "session not found")
+		return
+	}
+
+	secret := s.cfg.ShareSecret
+	if len(secret) == 0 {
+		if secret, err = EnsureShareSecret(s.cfg.Profile); err != nil {
+			writeAPIError(w, http.StatusInternalServerError, ErrCodeInternalError, "failed to prepare share secret")
+			return
+		}
+	}
+	expires := time.Now().Add(ttl).Truncate(time.Second)
+	path := ShareLinkPath(NewShareToken(secret, sessionID, expires))
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	writeJSON(w, http.StatusOK, ShareLinkResponse{
+		SessionID: sessionID,
+		Path:      path,
+		URL:       scheme + "://" + r.Host + path,
+		ExpiresAt: expires.UTC(),
+	})
+}
+
+// sharePageTemplate is the observer page. It is self-contained rather than
+// part of the SPA bundle: a share link must not load the deck UI, which
+// assumes access to every session.
+var sharePageTemplate = template.Must(template.New("share").Parse(`<!doctype html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{.Title}} · Agent Deck (read-only)</title>
+<style>
+  body { margin: 0; font-family: system-ui, sans-serif; background: #1a1b26; color: #c0caf5; }
+  header { padding: 10px 16px; border-bottom: 1px solid #2f334d; display: flex; gap: 16px; align-items: baseline; flex-wrap: wrap; }
+  h1 { font-size: 16px; margin: 0; }
+  .meta { color: #737aa2; font-size: 13px; }
+  nav button { background: none; border: 1px solid #2f334d; color: #c0caf5; padding: 4px 10px; cursor: pointer; }
+  nav button.active { border-color: #7aa2f7; color: #7aa2f7; }
+  pre { margin: 0; padding: 12px 16px; font: 13px/1.35 ui-monospace, Menlo, monospace; white-space: pre-wrap; word-break: break-all; }
+  #notice { padding: 8px 16px; color: #e0af68; }
+</style>
+</head>
+<body>
+<header>
+  <h1>{{.Title}}</h1>
+  <span class="meta">{{.Tool}} · <span id="status">{{.Status}}</span> · read-only · expires <span id="expires" data-at="{{.ExpiresAt}}">{{.ExpiresAt}}</span></span>
+  <nav><button id="tab-live" class="active">Live</button> <button id="tab-transcript">Transcript</button></nav>
+</header>
+<div id="notice" hidden></div>
+<pre id="live"></pre>
+<pre id="transcript" hidden></pre>
+<script>
+(function () {
+  var base = {{.Base}};
+  var ansi = /\x1b\[[0-9;?]*[ -\/]*[@-~]|\x1b\][^\x07]*\x07/g;
+  var live = document.getElementById("live");
+  var transcript = document.getElementById("transcript");
+  var notice = document.getElementById("notice");
+  var lines = [];
+
+  function show(msg) { notice.textContent = msg; notice.hidden = false; }
+  function render() { live.textContent = lines.join("\n").replace(ansi, ""); }
+
+  var expires = document.getElementById("expires");
+  expires.textContent = new Date(expires.dataset.at).toLocaleString();
+
+  var ws = new WebSocket((location.protocol === "https:" ? "wss://" : "ws://") + location.host + base + "/ws");
+  ws.onmessage = function (ev) {
+    var msg = JSON.parse(ev.data);
+    if (msg.type === "pane_snapshot") {
+      lines = msg.lines || [];
+    } else if (msg.type === "pane_diff") {
+      (msg.changes || []).forEach(function (c) { lines[c.line] = c.text; });
+      lines.length = msg.total;
+      for (var i = 0; i < lines.length; i++) { if (lines[i] === undefined) lines[i] = ""; }
+    } else if (msg.type === "error") {
+      show(msg.message);
+      return;
+    } else {
+      return;
+    }
+    render();
+  };
+  ws.onclose = function (ev) {
+    show(ev.reason === "share link expired" ? "This share link has expired." : "Live view disconnected. Reload to reconnect.");
+  };
+
+  function loadTranscript() {
+    fetch(base + "/transcript").then(function (res) {
+      if (!res.ok) throw new Error(res.status === 410 ? "This share link has expired." : "Transcript unavailable.");
+      return res.json();
+    }).then(function (data) {
+      var out = [];
+      data.entries.forEach(function (e) { out.push.apply(out, e.lines || []); });
+      transcript.textContent = out.length ? out.join("\n") : "No transcript recorded yet.";
+    }).catch(function (err) { show(err.message); });
+  }
+
+  function select(tab) {
+    var isLive = tab === "live";
+    live.hidden = !isLive;
+    transcript.hidden = isLive;
+    document.getElementById("tab-live").classList.toggle("active", isLive);
+    document.getElementById("tab-transcript").classList.toggle("active", !isLive);
+    if (!isLive) loadTranscript();
+  }
+  document.getElementById("tab-live").onclick = function () { select("live"); };
+  document.getElementById("tab-transcript").onclick = function () { select("transcript"); };
+})();
+</script>
+</body>
+</html>
+`))
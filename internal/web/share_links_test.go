@@ -0,0 +1,225 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+var testShareSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func TestShareToken_RoundTripAndTamper(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	token := NewShareToken(testShareSecret, "a1b2c3d4-1700000000", now.Add(time.Hour))
+
+	id, expires, err := VerifyShareToken(testShareSecret, token, now)
+	if err != nil || id != "a1b2c3d4-1700000000" || !expires.Equal(now.Add(time.Hour)) {
+		t.Fatalf("VerifyShareToken = %q, %v, %v", id, expires, err)
+	}
+
+	if _, _, err := VerifyShareToken(testShareSecret, token, now.Add(time.Hour)); !errors.Is(err, ErrShareLinkExpired) {
+		t.Fatalf("at expiry: err = %v, want ErrShareLinkExpired", err)
+	}
+
+	parts := strings.SplitN(token, ".", 3)
+	for name, forged := range map[string]string{
+		"other session":  parts[0] + "." + parts[1] + ".other-1700000000",
+		"later expiry":   "1800000000." + parts[1] + "." + parts[2],
+		"other secret":   NewShareToken([]byte("another-secret-another-secret-xx"), parts[2], now.Add(time.Hour)),
+		"missing parts":  parts[0] + "." + parts[1],
+		"garbage expiry": "soon." + parts[1] + "." + parts[2],
+	} {
+		if _, _, err := VerifyShareToken(testShareSecret, forged, now); !errors.Is(err, ErrShareLinkInvalid) {
+			t.Errorf("%s: err = %v, want ErrShareLinkInvalid", name, err)
+		}
+	}
+	if _, _, err := VerifyShareToken(nil, token, now); !errors.Is(err, ErrShareLinkInvalid) {
+		t.Errorf("no secret: err = %v, want ErrShareLinkInvalid", err)
+	}
+}
+
+func TestEnsureShareSecret_PersistsAndRotates(t *testing.T) {
+	first, err := EnsureShareSecret("sharetest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := EnsureShareSecret("sharetest")
+	if err != nil || string(again) != string(first) {
+		t.Fatalf("second EnsureShareSecret returned a different secret (err %v)", err)
+	}
+	if err := RotateShareSecret("sharetest"); err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := EnsureShareSecret("sharetest")
+	if err != nil || string(rotated) == string(first) {
+		t.Fatalf("RotateShareSecret kept the old secret (err %v)", err)
+	}
+}
+
+func newShareTestServer(t *testing.T, cfg Config) (*Server, *httptest.Server) {
+	t.Helper()
+	cfg.ListenAddr = "127.0.0.1:0"
+	cfg.Profile = "work"
+	cfg.ShareSecret = testShareSecret
+	srv := NewServer(cfg)
+	srv.menuData = &fakeMenuDataLoader{
+		snapshot: &MenuSnapshot{
+			Profile: "work",
+			Items: []MenuItem{
+				{Type: MenuItemTypeSession, Session: &MenuSession{ID: "sess-1", Title: "api <server>", Tool: "claude", TmuxSession: "agentdeck_sess-1"}},
+				{Type: MenuItemTypeSession, Session: &MenuSession{ID: "sess-2", Title: "secret work", TmuxSession: "agentdeck_sess-2"}},
+			},
+		},
+	}
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+	return srv, ts
+}
+
+func TestSharePage_TokenGrantsOnlyItsSession(t *testing.T) {
+	_, ts := newShareTestServer(t, Config{Token: "deck-token"})
+	path := ShareLinkPath(NewShareToken(testShareSecret, "sess-1", time.Now().Add(time.Hour)))
+
+	resp, err := http.Get(ts.URL + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("share page status = %d: %s", resp.StatusCode, body)
+	}
+	if got := resp.Header.Get("Referrer-Policy"); got != "no-referrer" {
+		t.Errorf("Referrer-Policy = %q", got)
+	}
+	if !strings.Contains(string(body), "api &lt;server&gt;") || strings.Contains(string(body), "secret work") {
+		t.Errorf("page should show (escaped) sess-1 only:\n%s", body)
+	}
+
+	resp, err = http.Get(ts.URL + path + "/transcript")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var transcript ShareTranscriptResponse
+	_ = json.NewDecoder(resp.Body).Decode(&transcript)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || transcript.SessionID != "sess-1" || transcript.Entries == nil {
+		t.Fatalf("transcript = %d %+v", resp.StatusCode, transcript)
+	}
+
+	// The share token is not a deck token.
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/api/menu", nil)
+	req.Header.Set("Authorization", "Bearer "+strings.TrimPrefix(path, "/share/"))
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("/api/menu with a share token = %d, want 401", resp.StatusCode)
+	}
+
+	expired := ShareLinkPath(NewShareToken(testShareSecret, "sess-1", time.Now().Add(-time.Minute)))
+	forged := ShareLinkPath(NewShareToken([]byte("not-the-secret-not-the-secret-xx"), "sess-2", time.Now().Add(time.Hour)))
+	gone := ShareLinkPath(NewShareToken(testShareSecret, "sess-deleted", time.Now().Add(time.Hour)))
+	for p, want := range map[string]int{
+		expired:                  http.StatusGone,
+		forged:                   http.StatusNotFound,
+		gone:                     http.StatusNotFound,
+		forged + "/transcript":   http.StatusNotFound,
+		expired + "/transcript":  http.StatusGone,
+		"/share/not-a-token/ws":  http.StatusNotFound,
+		"/share/not-a-token/api": http.StatusNotFound,
+	} {
+		resp, err := http.Get(ts.URL + p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("GET %s = %d, want %d", p, resp.StatusCode, want)
+		}
+	}
+}
+
+func TestShareStream_ReadOnlyPaneOfSharedSession(t *testing.T) {
+	srv, ts := newShareTestServer(t, Config{Token: "deck-token"})
+	src := &fakePaneSource{connected: map[string]int{}, content: "$ make\nok\n"}
+	srv.paneStreams = newFakePaneHub(src)
+
+	path := ShareLinkPath(NewShareToken(testShareSecret, "sess-1", time.Now().Add(time.Hour)))
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL(ts.URL, path+"/ws"), nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+
+	var status wsServerMessage
+	if err := conn.ReadJSON(&status); err != nil || !status.ReadOnly || status.SessionID != "sess-1" {
+		t.Fatalf("connected status = %+v, err %v", status, err)
+	}
+	var snap paneStreamMessage
+	if err := conn.ReadJSON(&snap); err != nil || snap.Type != "pane_snapshot" || strings.Join(snap.Lines, "|") != "$ make|ok" {
+		t.Fatalf("snapshot = %+v, err %v", snap, err)
+	}
+	if n := src.connections("agentdeck_sess-1"); n != 1 {
+		t.Fatalf("connections to the shared session = %d", n)
+	}
+}
+
+func TestSessionShareAPI_MintsLinkForAuthorizedCaller(t *testing.T) {
+	_, ts := newShareTestServer(t, Config{Token: "deck-token", ReadOnly: true})
+
+	post := func(id, body, token string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodPost, ts.URL+"/api/sessions/"+id+"/share", strings.NewReader(body))
+		req.Header.Set("Origin", ts.URL)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	if resp := post("sess-1", "", ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("unauthenticated mint = %d, want 401", resp.StatusCode)
+	}
+	if resp := post("sess-1", `{"ttl":"720h"}`, "deck-token"); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("ttl over the maximum = %d, want 400", resp.StatusCode)
+	}
+	if resp := post("missing", "", "deck-token"); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unknown session = %d, want 404", resp.StatusCode)
+	}
+
+	// Read-only mode still allows sharing: a link only grants reading.
+	resp := post("sess-1", `{"ttl":"30m"}`, "deck-token")
+	var link ShareLinkResponse
+	_ = json.NewDecoder(resp.Body).Decode(&link)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(link.URL, ts.URL+"/share/") {
+		t.Fatalf("mint = %d %+v", resp.StatusCode, link)
+	}
+	if left := time.Until(link.ExpiresAt); left < 29*time.Minute || left > 30*time.Minute {
+		t.Fatalf("expiresAt is %s away, want ~30m", left)
+	}
+
+	page, err := http.Get(link.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	page.Body.Close()
+	if page.StatusCode != http.StatusOK {
+		t.Fatalf("minted link = %d", page.StatusCode)
+	}
+}
@@ -20,6 +20,7 @@ import { CostsPane } from './panes/CostsPane.js'
 import { FleetPane } from './panes/FleetPane.js'
 import { CommandCenterPane } from './panes/CommandCenterPane.js'
 import { ArchivedPane } from './panes/ArchivedPane.js'
+import { SessionsPane } from './panes/SessionsPane.js'
 import { StubPane } from './panes/StubPane.js'
 import { SearchPane } from './panes/SearchPane.js'
 import { McpPane } from './panes/McpPane.js'
@@ -105,6 +106,7 @@ function Panes({ tab }) {
     </div>
     ${tab === 'command-center' && html`<${CommandCenterPane}/>`}
     ${tab === 'fleet'     && html`<${FleetPane}/>`}
+    ${tab === 'sessions'  && html`<${SessionsPane}/>`}
     ${tab === 'costs'     && html`<${CostsPane}/>`}
     ${tab === 'search'    && html`<${SearchPane}/>`}
     ${tab === 'archived'  && html`<${ArchivedPane}/>`}
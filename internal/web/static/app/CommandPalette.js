@@ -28,6 +28,7 @@ export function CommandPalette() {
   const cmds = useMemo(() => {
     const list = [
       { id: 'cmd-fleet',     sec: 'COMMANDS', label: 'Open Fleet',     tool: '▦', run: () => { activeTabSignal.value = 'fleet'; close() } },
+      { id: 'cmd-sessions',  sec: 'COMMANDS', label: 'Sessions table', tool: '☰', run: () => { activeTabSignal.value = 'sessions'; close() } },
       { id: 'cmd-terminal',  sec: 'COMMANDS', label: 'Open Terminal',  tool: '›_', run: () => { activeTabSignal.value = 'terminal'; close() } },
       { id: 'cmd-costs',     sec: 'COMMANDS', label: 'Costs dashboard', tool: '$', run: () => { activeTabSignal.value = 'costs'; close() } },
       { id: 'cmd-search',    sec: 'COMMANDS', label: 'Session search', tool: '/', run: () => { activeTabSignal.value = 'search'; close() } },
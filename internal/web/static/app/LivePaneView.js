@@ -0,0 +1,111 @@
+// LivePaneView.js -- read-only live view of a session's pane.
+// Subscribes to /ws/pane/<id>, which streams capture-pane snapshots and
+// line diffs from the server's PipeManager. Unlike TerminalPanel this never
+// attaches a tmux client, so watching a session cannot resize it or send
+// keystrokes to it. When mutations are enabled a one-line send box posts a
+// whole message to /api/sessions/<id>/send instead (e.g. answering a
+// waiting agent from a phone).
+import { html } from 'htm/preact'
+import { useEffect, useState } from 'preact/hooks'
+import { authTokenSignal, mutationsEnabledSignal } from './state.js'
+import { apiFetch } from './api.js'
+import { addToast } from './Toast.js'
+
+// capture-pane -e keeps SGR escapes; the view is plain text.
+const ANSI_RE = /\x1b\[[0-9;:?]*[A-Za-z]/g
+
+function paneStreamURL(sessionId, token) {
+  const wsProto = window.location.protocol === 'https:' ? 'wss' : 'ws'
+  const url = new URL(
+    wsProto + '://' + window.location.host + '/ws/pane/' + encodeURIComponent(sessionId)
+  )
+  if (token) url.searchParams.set('token', token)
+  return url.toString()
+}
+
+// applyPaneMessage returns the pane lines after one stream message.
+export function applyPaneMessage(lines, msg) {
+  if (msg.type === 'pane_snapshot') return (msg.lines || []).slice()
+  if (msg.type !== 'pane_diff') return lines
+  const next = lines.slice(0, msg.total)
+  while (next.length < msg.total) next.push('')
+  for (const c of msg.changes || []) next[c.line] = c.text
+  return next
+}
+
+function SendBox({ sessionId }) {
+  const [text, setText] = useState('')
+  const [sending, setSending] = useState(false)
+
+  async function onSubmit(e) {
+    e.preventDefault()
+    const message = text.trim()
+    if (!message || sending) return
+    setSending(true)
+    try {
+      await apiFetch('POST', '/api/sessions/' + encodeURIComponent(sessionId) + '/send', { message })
+      setText('')
+      addToast('Message sent', 'success')
+    } catch (_e) {
+      // apiFetch already surfaced the error as a toast.
+    } finally {
+      setSending(false)
+    }
+  }
+
+  return html`
+    <form onSubmit=${onSubmit} data-testid="live-pane-send" style="display: flex; gap: 8px; padding: 0 16px 12px;">
+      <input
+        type="text"
+        value=${text}
+        onInput=${(e) => setText(e.target.value)}
+        placeholder="Send a message…"
+        aria-label="Message to send to the session"
+        disabled=${sending}
+        style="flex: 1; font-family: var(--mono); font-size: 12px; padding: 6px 8px;"
+      />
+      <button type="submit" disabled=${sending || !text.trim()}>Send</button>
+    </form>
+  `
+}
+
+export function LivePaneView({ sessionId, title }) {
+  const [lines, setLines] = useState([])
+  const [error, setError] = useState('')
+
+  useEffect(() => {
+    if (!sessionId) return undefined
+    setLines([])
+    setError('')
+    const controller = new AbortController()
+    const ws = new WebSocket(paneStreamURL(sessionId, authTokenSignal.value))
+    function onMessage(event) {
+      let msg
+      try { msg = JSON.parse(event.data) } catch (_e) { return }
+      if (msg.type === 'error') {
+        setError(msg.message || msg.code || 'stream error')
+        return
+      }
+      setLines((prev) => applyPaneMessage(prev, msg))
+    }
+    ws.addEventListener('message', onMessage, { signal: controller.signal })
+    return () => {
+      controller.abort()
+      ws.close()
+    }
+  }, [sessionId])
+
+  if (!sessionId) return null
+  return html`
+    <div class="fleet-section" data-testid="live-pane-view">
+      <div class="fleet-section-head">
+        <span class="kicker">LIVE</span>
+        <span class="sub-kicker">${title || sessionId} · read-only</span>
+      </div>
+      ${error
+        ? html`<div style="font-family: var(--mono); font-size: 11px; color: var(--tn-red); padding: 16px;">${error}</div>`
+        : html`<pre style="font-family: var(--mono); font-size: 11px; color: var(--text); margin: 0; padding: 12px 16px; max-height: 360px; overflow: auto; white-space: pre;">${lines.map(l => l.replace(ANSI_RE, '')).join('\n')}</pre>`}
+      ${mutationsEnabledSignal.value && html`<${SendBox} sessionId=${sessionId}/>`}
+    </div>
+  `
+}
@@ -50,6 +50,7 @@ function projectSession(item) {
     worktreeBranch: s.worktreeBranch || '',
     lastAccessedAt: s.lastAccessedAt || '',
     createdAt: s.createdAt || '',
+    waitingSince: s.waitingSince || '',
     sandbox: false,     // not exposed by API
     parent: null,
     pendingNeeds: 0,
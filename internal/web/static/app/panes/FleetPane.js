@@ -7,6 +7,7 @@ import { useMemo } from 'preact/hooks'
 import { menuModelSignal } from '../dataModel.js'
 import { selectedIdSignal } from '../state.js'
 import { activeTabSignal } from '../uiState.js'
+import { LivePaneView } from '../LivePaneView.js'
 
 function GroupCard({ name, items, onSelect }) {
   const running = items.filter(s => s.status === 'running').length
@@ -48,6 +49,7 @@ export function FleetPane() {
     idle:    sessions.filter(s => s.status === 'idle').length,
   }), [sessions])
   const totalCost = sessions.reduce((n, s) => n + (s.cost || 0), 0)
+  const watched = sessions.find(s => s.id === selectedIdSignal.value)
 
   const onSelect = (id) => {
     selectedIdSignal.value = id
@@ -65,6 +67,8 @@ export function FleetPane() {
         <div class="stat" data-testid="fleet-stat-sessions"><div class="lbl">SESSIONS</div><div class="num">${sessions.length}</div></div>
       </div>
 
+      ${watched && html`<${LivePaneView} key=${watched.id} sessionId=${watched.id} title=${watched.title}/>`}
+
       <div class="fleet-section">
         <div class="fleet-section-head">
           <span class="kicker">GROUPS</span>
@@ -0,0 +1,235 @@
+// panes/SessionsPane.js -- Sortable, filterable table of the live sessions.
+//
+// The table state (sort, status/group/tool filters, search) is a plain JSON
+// "spec". Named specs are saved per token via /api/views; the view marked
+// default is applied when the pane first mounts, and ?view=<name> in the URL
+// picks a view explicitly (handy for wall dashboards).
+import { html } from 'htm/preact'
+import { useMemo, useEffect, useState } from 'preact/hooks'
+import { signal } from '@preact/signals'
+import { Dot } from '../icons.js'
+import { menuModelSignal } from '../dataModel.js'
+import { selectedIdSignal } from '../state.js'
+import { activeTabSignal } from '../uiState.js'
+import { apiFetch } from '../api.js'
+import { addToast } from '../Toast.js'
+import { formatRelativeTime, humanizeSince } from '../timeFmt.js'
+
+const STATUSES = ['waiting', 'running', 'error', 'idle', 'starting', 'stopped']
+
+// Attention order: the statuses that need a human sort first ascending.
+const STATUS_RANK = { waiting: 0, error: 1, running: 2, starting: 3, idle: 4, stopped: 5 }
+
+const COLUMNS = [
+  { key: 'title',      label: 'Session' },
+  { key: 'status',     label: 'Status' },
+  { key: 'group',      label: 'Group' },
+  { key: 'tool',       label: 'Tool' },
+  { key: 'waiting',    label: 'Waiting' },
+  { key: 'lastActive', label: 'Last active' },
+  { key: 'created',    label: 'Created' },
+]
+
+const DEFAULT_SPEC = { sort: { key: 'status', dir: 'asc' }, statuses: [], groups: [], tools: [], q: '' }
+
+// Table state survives tab switches; saved views persist it across reloads.
+const specSignal = signal(DEFAULT_SPEC)
+const activeViewSignal = signal('')
+const viewsSignal = signal([])
+let initialViewApplied = false
+
+function normalizeSpec(raw) {
+  const s = raw || {}
+  const sort = s.sort && COLUMNS.some(c => c.key === s.sort.key)
+    ? { key: s.sort.key, dir: s.sort.dir === 'desc' ? 'desc' : 'asc' }
+    : DEFAULT_SPEC.sort
+  const list = v => Array.isArray(v) ? v.filter(x => typeof x === 'string') : []
+  return { sort, statuses: list(s.statuses), groups: list(s.groups), tools: list(s.tools), q: typeof s.q === 'string' ? s.q : '' }
+}
+
+function timeMs(iso) {
+  if (!iso) return NaN
+  const t = new Date(iso).getTime()
+  return t > 0 ? t : NaN
+}
+
+// sortValue returns a comparable value; NaN (unknown time) always sorts last.
+function sortValue(s, key) {
+  switch (key) {
+    case 'status':     return STATUS_RANK[s.status] ?? 9
+    case 'waiting':    return timeMs(s.waitingSince)
+    case 'lastActive': return timeMs(s.lastAccessedAt)
+    case 'created':    return timeMs(s.createdAt)
+    default:           return String(s[key] || '').toLowerCase()
+  }
+}
+
+function compareSessions(a, b, { key, dir }) {
+  const va = sortValue(a, key)
+  const vb = sortValue(b, key)
+  const na = typeof va === 'number' && Number.isNaN(va)
+  const nb = typeof vb === 'number' && Number.isNaN(vb)
+  if (na || nb) return na === nb ? 0 : na ? 1 : -1
+  // Time columns read as ages: ascending = longest waiting / oldest first.
+  let c = va < vb ? -1 : va > vb ? 1 : 0
+  if (c === 0 && key !== 'title') c = a.title.localeCompare(b.title)
+  return dir === 'desc' ? -c : c
+}
+
+export function filterAndSortSessions(sessions, spec) {
+  const q = spec.q.trim().toLowerCase()
+  const out = sessions.filter(s =>
+    (spec.statuses.length === 0 || spec.statuses.includes(s.status)) &&
+    (spec.groups.length === 0 || spec.groups.includes(s.group)) &&
+    (spec.tools.length === 0 || spec.tools.includes(s.tool)) &&
+    (!q || `${s.title} ${s.group} ${s.tool} ${s.path} ${s.branch}`.toLowerCase().includes(q))
+  )
+  return out.sort((a, b) => compareSessions(a, b, spec.sort))
+}
+
+function loadViews() {
+  return apiFetch('GET', '/api/views')
+    .then(data => { viewsSignal.value = data.views || []; return viewsSignal.value })
+    .catch(() => [])
+}
+
+function applyView(view) {
+  activeViewSignal.value = view ? view.name : ''
+  specSignal.value = view ? normalizeSpec(view.spec) : DEFAULT_SPEC
+}
+
+function updateSpec(patch) {
+  specSignal.value = { ...specSignal.value, ...patch }
+}
+
+function toggle(list, v) {
+  return list.includes(v) ? list.filter(x => x !== v) : [...list, v]
+}
+
+function ChipRow({ label, options, selected, field }) {
+  if (options.length === 0) return null
+  return html`
+    <div class="st-chips" data-testid=${`sessions-filter-${field}`}>
+      <span class="st-chips-label">${label}</span>
+      ${options.map(o => html`
+        <button key=${o} class=${`st-chip ${selected.includes(o) ? 'on' : ''}`}
+                onClick=${() => updateSpec({ [field]: toggle(selected, o) })}>${o || '(none)'}</button>
+      `)}
+    </div>
+  `
+}
+
+function ViewBar({ spec }) {
+  const views = viewsSignal.value
+  const active = activeViewSignal.value
+  const [name, setName] = useState('')
+  const current = views.find(v => v.name === active)
+
+  const save = (viewName, makeDefault) => {
+    const n = viewName.trim()
+    if (!n) return
+    apiFetch('PUT', `/api/views/${encodeURIComponent(n)}`, { spec, default: makeDefault })
+      .then(() => loadViews())
+      .then(() => { activeViewSignal.value = n; setName(''); addToast(`Saved view "${n}"`, 'success') })
+      .catch(() => {})
+  }
+
+  const remove = () => {
+    if (!current) return
+    apiFetch('DELETE', `/api/views/${encodeURIComponent(current.name)}`)
+      .then(() => loadViews())
+      .then(() => { activeViewSignal.value = '' })
+      .catch(() => {})
+  }
+
+  return html`
+    <div class="st-views" data-testid="sessions-views">
+      <select value=${active} data-testid="sessions-view-select"
+              onChange=${e => applyView(views.find(v => v.name === e.target.value))}>
+        <option value="">All sessions</option>
+        ${views.map(v => html`<option key=${v.name} value=${v.name}>${v.name}${v.default ? ' ★' : ''}</option>`)}
+      </select>
+      ${current && html`
+        <button class="mini" title="Update this view with the current table" onClick=${() => save(current.name, current.default)}>Update</button>
+        <button class="mini" title="Open this view by default" onClick=${() => save(current.name, !current.default)}>
+          ${current.default ? 'Unset default' : 'Set default'}
+        </button>
+        <button class="mini danger" onClick=${remove}>Delete</button>
+      `}
+      <input placeholder="New view name" maxlength="64" value=${name}
+             onInput=${e => setName(e.target.value)}
+             onKeyDown=${e => { if (e.key === 'Enter') save(name, false) }}/>
+      <button class="mini good" disabled=${!name.trim()} onClick=${() => save(name, false)}>Save view</button>
+    </div>
+  `
+}
+
+export function SessionsPane() {
+  const { sessions } = menuModelSignal.value
+  const spec = specSignal.value
+  const now = Date.now()
+
+  useEffect(() => {
+    loadViews().then(views => {
+      if (initialViewApplied) return
+      initialViewApplied = true
+      const wanted = new URLSearchParams(window.location.search).get('view')
+      const view = wanted ? views.find(v => v.name === wanted) : views.find(v => v.default)
+      if (view) applyView(view)
+    })
+  }, [])
+
+  const groups = useMemo(() => [...new Set(sessions.map(s => s.group))].sort(), [sessions])
+  const tools = useMemo(() => [...new Set(sessions.map(s => s.tool).filter(Boolean))].sort(), [sessions])
+  const rows = useMemo(() => filterAndSortSessions(sessions, spec), [sessions, spec])
+
+  const onSort = (key) => {
+    const dir = spec.sort.key === key && spec.sort.dir === 'asc' ? 'desc' : 'asc'
+    updateSpec({ sort: { key, dir } })
+  }
+
+  const onSelect = (id) => {
+    selectedIdSignal.value = id
+    activeTabSignal.value = 'terminal'
+  }
+
+  return html`
+    <div class="search-wrap sessions-table-wrap" data-testid="sessions-pane">
+      <${ViewBar} spec=${spec}/>
+      <div class="field">
+        <input placeholder="Search title, group, tool, path, branch…" data-testid="sessions-search"
+               value=${spec.q} onInput=${e => updateSpec({ q: e.target.value })}/>
+      </div>
+      <${ChipRow} label="Status" field="statuses" options=${STATUSES} selected=${spec.statuses}/>
+      <${ChipRow} label="Group" field="groups" options=${groups} selected=${spec.groups}/>
+      <${ChipRow} label="Tool" field="tools" options=${tools} selected=${spec.tools}/>
+      <div class="st-count">${rows.length} OF ${sessions.length} SESSIONS</div>
+      <table class="list-table sessions-table">
+        <thead>
+          <tr>
+            ${COLUMNS.map(c => html`
+              <th key=${c.key} class="sortable" onClick=${() => onSort(c.key)}
+                  aria-sort=${spec.sort.key === c.key ? (spec.sort.dir === 'asc' ? 'ascending' : 'descending') : 'none'}>
+                ${c.label}${spec.sort.key === c.key ? (spec.sort.dir === 'asc' ? ' ▲' : ' ▼') : ''}
+              </th>
+            `)}
+          </tr>
+        </thead>
+        <tbody>
+          ${rows.map(s => html`
+            <tr key=${s.id} data-testid="sessions-row" data-session-id=${s.id} onClick=${() => onSelect(s.id)}>
+              <td><${Dot} status=${s.status}/> ${s.title}</td>
+              <td>${s.status}</td>
+              <td>${s.group || '—'}</td>
+              <td>${s.tool || '—'}</td>
+              <td class="num">${s.waitingSince ? humanizeSince(now - timeMs(s.waitingSince)).replace(' ago', '') : '—'}</td>
+              <td class="num">${formatRelativeTime(s.lastAccessedAt, now)}</td>
+              <td class="num">${formatRelativeTime(s.createdAt, now)}</td>
+            </tr>
+          `)}
+        </tbody>
+      </table>
+      ${rows.length === 0 && html`<div class="st-empty">No sessions match these filters.</div>`}
+    </div>
+  `
+}
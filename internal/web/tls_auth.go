@@ -0,0 +1,126 @@
+package web
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// DefaultTrustedProxyHeader is the header a trusted reverse proxy sets to the
+// authenticated user (Caddy forward_auth, Traefik ForwardAuth, oauth2-proxy).
+const DefaultTrustedProxyHeader = "X-Forwarded-User"
+
+// authConfigured reports whether any authentication mode is enabled: a
+// bearer token, mutual TLS, or trusted-proxy header auth. With none of them
+// every request is authorized (the default loopback dev mode).
+func (c Config) authConfigured() bool {
+	return c.Token != "" || c.TLSClientCAFile != "" || len(c.TrustedProxies) > 0
+}
+
+// buildTLSConfig returns the server TLS config for cfg, or nil when TLS is
+// off. With a client CA the handshake requires a certificate signed by it,
+// so an unauthenticated peer never reaches a handler.
+func buildTLSConfig(cfg Config) (*tls.Config, error) {
+	if cfg.TLSCertFile == "" && cfg.TLSKeyFile == "" {
+		if cfg.TLSClientCAFile != "" {
+			return nil, fmt.Errorf("a TLS client CA requires a server certificate and key")
+		}
+		return nil, nil
+	}
+	if cfg.TLSCertFile == "" || cfg.TLSKeyFile == "" {
+		return nil, fmt.Errorf("TLS needs both a certificate and a key")
+	}
+	cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load TLS certificate: %w", err)
+	}
+	tlsCfg := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+	if cfg.TLSClientCAFile != "" {
+		pem, err := os.ReadFile(cfg.TLSClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read TLS client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in TLS client CA %s", cfg.TLSClientCAFile)
+		}
+		tlsCfg.ClientCAs = pool
+		tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsCfg, nil
+}
+
+// ParseTrustedProxies parses proxy addresses given as IPs or CIDRs. A bare
+// IP becomes a single-host network.
+func ParseTrustedProxies(entries []string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if _, n, err := net.ParseCIDR(entry); err == nil {
+			nets = append(nets, n)
+			continue
+		}
+		ip := net.ParseIP(entry)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: want an IP or CIDR", entry)
+		}
+		bits := 8 * net.IPv6len
+		if ip4 := ip.To4(); ip4 != nil {
+			ip, bits = ip4, 8*net.IPv4len
+		}
+		nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+	}
+	return nets, nil
+}
+
+// clientCertUser returns the subject common name of a verified mTLS client
+// certificate, or "" when the request carries none.
+func clientCertUser(r *http.Request) string {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	if cn := r.TLS.VerifiedChains[0][0].Subject.CommonName; cn != "" {
+		return cn
+	}
+	return "client-certificate"
+}
+
+// proxyUser returns the user a trusted reverse proxy vouches for. The header
+// is only believed when the connection itself comes from a trusted proxy
+// address; anyone else could set it.
+func (s *Server) proxyUser(r *http.Request) string {
+	if len(s.trustedProxies) == 0 {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return ""
+	}
+	for _, n := range s.trustedProxies {
+		if n.Contains(ip) {
+			return strings.TrimSpace(r.Header.Get(s.trustedProxyHeader()))
+		}
+	}
+	return ""
+}
+
+func (s *Server) trustedProxyHeader() string {
+	if s.cfg.TrustedProxyHeader != "" {
+		return s.cfg.TrustedProxyHeader
+	}
+	return DefaultTrustedProxyHeader
+}
@@ -0,0 +1,223 @@
+package web
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// healthzAuthorized reports whether /healthz treated req as authorized: it
+// only discloses the profile to authorized callers.
+func healthzAuthorized(t *testing.T, h http.Handler, req *http.Request) bool {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	var body map[string]any
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode healthz: %v", err)
+	}
+	_, ok := body["profile"]
+	return ok
+}
+
+func TestTrustedProxyHeaderAuth(t *testing.T) {
+	srv := NewServer(Config{ListenAddr: "0.0.0.0:8420", Profile: "test", TrustedProxies: []string{"10.0.0.0/24"}})
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	req.RemoteAddr = "10.0.0.2:41000"
+	req.Header.Set("X-Forwarded-User", "alice")
+	if !healthzAuthorized(t, srv.Handler(), req) {
+		t.Error("trusted proxy with user header should be authorized")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	req.RemoteAddr = "10.0.0.2:41000"
+	if healthzAuthorized(t, srv.Handler(), req) {
+		t.Error("trusted proxy without user header should not be authorized")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	req.RemoteAddr = "192.168.1.9:41000"
+	req.Header.Set("X-Forwarded-User", "mallory")
+	if healthzAuthorized(t, srv.Handler(), req) {
+		t.Error("user header from an untrusted address should not be authorized")
+	}
+}
+
+func TestTrustedProxyCustomHeaderAndToken(t *testing.T) {
+	srv := NewServer(Config{
+		ListenAddr:         "0.0.0.0:8420",
+		Profile:            "test",
+		Token:              "secret",
+		TrustedProxies:     []string{"127.0.0.1"},
+		TrustedProxyHeader: "Remote-User",
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	req.RemoteAddr = "127.0.0.1:5000"
+	req.Header.Set("X-Forwarded-User", "alice")
+	if healthzAuthorized(t, srv.Handler(), req) {
+		t.Error("default header should be ignored when a custom one is configured")
+	}
+	req.Header.Set("Remote-User", "alice")
+	if !healthzAuthorized(t, srv.Handler(), req) {
+		t.Error("custom proxy header should be authorized")
+	}
+
+	// The token keeps working alongside proxy auth.
+	req = httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	req.RemoteAddr = "192.168.1.9:41000"
+	req.Header.Set("Authorization", "Bearer secret")
+	if !healthzAuthorized(t, srv.Handler(), req) {
+		t.Error("bearer token should still be authorized")
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	nets, err := ParseTrustedProxies([]string{"10.0.0.1", " 172.16.0.0/12 ", "", "::1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nets) != 3 {
+		t.Fatalf("got %d networks, want 3", len(nets))
+	}
+	if !nets[0].Contains(net.ParseIP("10.0.0.1")) || nets[0].Contains(net.ParseIP("10.0.0.2")) {
+		t.Errorf("bare IP should be a single host, got %v", nets[0])
+	}
+	if _, err := ParseTrustedProxies([]string{"proxy.local"}); err == nil {
+		t.Error("hostname should be rejected")
+	}
+}
+
+func TestCheckBindSecurity_NonLoopbackWithProxyOrClientCA_Allowed(t *testing.T) {
+	for _, cfg := range []Config{
+		{ListenAddr: "0.0.0.0:8420", TrustedProxies: []string{"10.0.0.2"}},
+		{ListenAddr: "0.0.0.0:8420", TLSCertFile: "c", TLSKeyFile: "k", TLSClientCAFile: "ca"},
+	} {
+		if err := NewServer(cfg).checkBindSecurity(); err != nil {
+			t.Errorf("%+v: expected allowed, got %v", cfg, err)
+		}
+	}
+}
+
+func TestBuildTLSConfig_Validation(t *testing.T) {
+	if cfg, err := buildTLSConfig(Config{}); cfg != nil || err != nil {
+		t.Errorf("no TLS flags: got %v, %v", cfg, err)
+	}
+	if _, err := buildTLSConfig(Config{TLSCertFile: "c"}); err == nil {
+		t.Error("cert without key should fail")
+	}
+	if _, err := buildTLSConfig(Config{TLSClientCAFile: "ca"}); err == nil {
+		t.Error("client CA without a server cert should fail")
+	}
+}
+
+func TestMutualTLSAuthorizesVerifiedClient(t *testing.T) {
+	dir := t.TempDir()
+	caCert, caKey := newTestCert(t, "test-ca", nil, nil)
+	srvCert, srvKey := newTestCert(t, "127.0.0.1", caCert, caKey)
+	cliCert, cliKey := newTestCert(t, "alice", caCert, caKey)
+	writePEM(t, filepath.Join(dir, "ca.crt"), "CERTIFICATE", caCert.Raw)
+	writePEM(t, filepath.Join(dir, "srv.crt"), "CERTIFICATE", srvCert.Raw)
+	writeKey(t, filepath.Join(dir, "srv.key"), srvKey)
+
+	cfg := Config{
+		ListenAddr:      "127.0.0.1:0",
+		Profile:         "test",
+		TLSCertFile:     filepath.Join(dir, "srv.crt"),
+		TLSKeyFile:      filepath.Join(dir, "srv.key"),
+		TLSClientCAFile: filepath.Join(dir, "ca.crt"),
+	}
+	tlsCfg, err := buildTLSConfig(cfg)
+	if err != nil {
+		t.Fatalf("buildTLSConfig: %v", err)
+	}
+	ts := httptest.NewUnstartedServer(NewServer(cfg).Handler())
+	ts.TLS = tlsCfg
+	ts.StartTLS()
+	defer ts.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+	client := func(certs ...tls.Certificate) *http.Client {
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: certs}}}
+	}
+
+	if _, err := client().Get(ts.URL + "/healthz"); err == nil {
+		t.Error("request without a client certificate should fail the handshake")
+	}
+
+	resp, err := client(tls.Certificate{Certificate: [][]byte{cliCert.Raw}, PrivateKey: cliKey}).Get(ts.URL + "/healthz")
+	if err != nil {
+		t.Fatalf("request with client certificate: %v", err)
+	}
+	defer resp.Body.Close()
+	var body map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body["profile"] != "test" {
+		t.Errorf("verified client should be authorized, got %v", body)
+	}
+}
+
+func newTestCert(t *testing.T, cn string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		parent, parentKey = tmpl, key
+	} else if ip := net.ParseIP(cn); ip != nil {
+		tmpl.IPAddresses = []net.IP{ip}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func writePEM(t *testing.T, path, typ string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeKey(t *testing.T, path string, key *ecdsa.PrivateKey) {
+	t.Helper()
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writePEM(t, path, "EC PRIVATE KEY", der)
+}
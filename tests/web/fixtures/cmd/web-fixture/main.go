@@ -338,6 +338,15 @@ func (s *fixtureStore) RestartSession(id string) error {
 	return s.transition(id, session.StatusRunning)
 }
 
+func (s *fixtureStore) SendMessage(id, message string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.sessions[id]; !ok {
+		return fmt.Errorf("session %q not found", id)
+	}
+	return nil
+}
+
 func (s *fixtureStore) DeleteSession(id string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()